@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/marcus/td/internal/admin"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/output"
+	"github.com/marcus/td/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var adminCmd = &cobra.Command{
+	Use:   "admin",
+	Short: "Repair stuck issues on behalf of other sessions",
+	Long: `Fix workflow state left behind by sessions that are gone: hand an issue
+to a new implementer or clear a reviewer who never finished.
+
+Each repair is saved as an update by the session acted for, noted on the
+issue with the admin session and reason, and recorded as an impersonation
+that td admin log lists. Over HTTP the same repairs live under /v1/admin
+and need a token with the admin scope.`,
+	Example: `  td admin reassign td-a1b2 ses_c3d4e5 --reason "agent crashed mid-task"
+  td admin clear-reviewer td-a1b2 --reason "reviewer session ended"
+  td admin log --issue td-a1b2`,
+	GroupID: "system",
+}
+
+var adminReassignCmd = &cobra.Command{
+	Use:   "reassign <issue-id> <session-id>",
+	Short: "Make another session the implementer of an issue",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runAdminRepair(cmd, func(database *db.DB, actor, reason string) (*admin.Result, error) {
+			return admin.Reassign(database, args[0], args[1], actor, reason)
+		})
+	},
+}
+
+var adminClearReviewerCmd = &cobra.Command{
+	Use:   "clear-reviewer <issue-id>",
+	Short: "Remove the reviewer of an issue so another session can review it",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runAdminRepair(cmd, func(database *db.DB, actor, reason string) (*admin.Result, error) {
+			return admin.ClearReviewer(database, args[0], actor, reason)
+		})
+	},
+}
+
+// runAdminRepair applies repair as the current session with the --reason
+// flag and reports the impersonation it recorded
+func runAdminRepair(cmd *cobra.Command, repair func(database *db.DB, actor, reason string) (*admin.Result, error)) error {
+	cmd.SilenceUsage = true
+	database, err := db.Open(getBaseDir())
+	if err != nil {
+		output.Error("%v", err)
+		return err
+	}
+	defer database.Close()
+
+	sess, err := session.GetOrCreate(database)
+	if err != nil {
+		output.Error("%v", err)
+		return err
+	}
+
+	reason, _ := cmd.Flags().GetString("reason")
+	res, err := repair(database, sess.ID, reason)
+	if err != nil {
+		output.Error("%v", err)
+		return err
+	}
+
+	im := res.Impersonation
+	switch im.Action {
+	case models.ImpersonateReassign:
+		from := im.Before
+		if from == "" {
+			from = "(none)"
+		}
+		output.Success("%s implementer %s -> %s", im.IssueID, from, im.After)
+	case models.ImpersonateClearReviewer:
+		output.Success("%s reviewer %s cleared", im.IssueID, im.Before)
+	}
+	output.Info("Recorded impersonation %s", im.ID)
+	return nil
+}
+
+var adminLogCmd = &cobra.Command{
+	Use:   "log",
+	Short: "List repairs made on behalf of other sessions",
+	Long: `List admin repairs, newest first, with the admin session, the session
+acted for and the reason given. --session matches either.
+
+--since takes a date (2026-03-01) or an offset back from now (7d, 2w).`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		var filter db.ImpersonationFilter
+		filter.IssueID, _ = cmd.Flags().GetString("issue")
+		filter.Session, _ = cmd.Flags().GetString("session")
+		if since, _ := cmd.Flags().GetString("since"); since != "" {
+			t, err := parseSince(since)
+			if err != nil {
+				output.Error("invalid --since: %v", err)
+				return err
+			}
+			filter.Since = t
+		}
+
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		records, err := database.ListImpersonations(filter)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			if records == nil {
+				records = []models.Impersonation{}
+			}
+			data, _ := json.MarshalIndent(records, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if len(records) == 0 {
+			output.Info("No impersonations recorded")
+			return nil
+		}
+		for _, im := range records {
+			change := im.Before + " -> " + im.After
+			if im.Action == models.ImpersonateClearReviewer {
+				change = im.Before + " cleared"
+			}
+			fmt.Printf("%s  %s  %-20s  %s as %s  %s  %s\n", im.CreatedAt.Local().Format("2006-01-02 15:04"),
+				im.IssueID, im.Action, im.ActorSession, orNone(im.AsSession), change, im.Reason)
+		}
+		return nil
+	},
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
+
+func init() {
+	adminReassignCmd.Flags().String("reason", "", "Why the repair is needed (required)")
+	adminClearReviewerCmd.Flags().String("reason", "", "Why the repair is needed (required)")
+	adminLogCmd.Flags().String("issue", "", "Only repairs to this issue")
+	adminLogCmd.Flags().String("session", "", "Only repairs by or on behalf of this session")
+	adminLogCmd.Flags().String("since", "", "Only repairs since a date or offset")
+	adminLogCmd.Flags().Bool("json", false, "Output as JSON")
+	adminCmd.AddCommand(adminReassignCmd, adminClearReviewerCmd, adminLogCmd)
+	rootCmd.AddCommand(adminCmd)
+}
@@ -7,11 +7,15 @@ import (
 	"sort"
 	"text/tabwriter"
 
+	"github.com/marcus/td/internal/features"
 	"github.com/marcus/td/internal/workdir"
 	"github.com/spf13/cobra"
 )
 
 func init() {
+	if !features.IsEnabledForProcess(features.SyncCLI.Name) {
+		return
+	}
 	configCmd.AddCommand(associateCmd)
 	configCmd.AddCommand(associationsCmd)
 	configCmd.AddCommand(dissociateCmd)
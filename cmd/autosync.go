@@ -124,8 +124,12 @@ func autoSyncOnce() {
 	client := syncclient.New(serverURL, apiKey, deviceID)
 	client.HTTP.Timeout = autoSyncHTTPTimeout
 
-	if err := autoSyncPush(database, client, syncState, deviceID); err != nil {
-		slog.Debug("autosync: push", "err", err)
+	pushErr := autoSyncPush(database, client, syncState, deviceID)
+	if pushErr != nil {
+		slog.Debug("autosync: push", "err", pushErr)
+	}
+	if err := database.RecordPushAttempt(pushErr); err != nil {
+		slog.Debug("autosync: record push attempt", "err", err)
 	}
 
 	if syncconfig.GetAutoSyncPull() {
@@ -151,11 +155,11 @@ func autoSyncAfterMutation() {
 
 // startupSyncSkipCommands lists commands that should not trigger startup auto-sync.
 var startupSyncSkipCommands = map[string]bool{
-	"sync": true, "auth": true, "login": true, "version": true, "help": true,
+	"sync": true, "auth": true, "login": true, "version": true, "help": true, "prompt": true,
 }
 
 // autoSyncOnStartup runs a one-time push+pull at process start if configured.
-// Does NOT set debounce timestamp — the post-mutation sync in PersistentPostRun
+// Does NOT set debounce timestamp — the post-mutation sync in PersistentPostRunE
 // must still fire for commands that create data after the startup sync.
 func autoSyncOnStartup(cmdName string) {
 	if !syncconfig.GetAutoSyncOnStart() {
@@ -277,6 +281,7 @@ func autoSyncPush(database *db.DB, client *syncclient.Client, state *db.SyncStat
 	var allAcks []tdsync.Ack
 	var maxActionID int64
 	var allHistoryEntries []db.SyncHistoryEntry
+	rejected := make(map[int64]string) // refused for reasons other than being a duplicate
 
 	// Push in batches to stay within server limits
 	for i := 0; i < len(events); i += pushBatchSize {
@@ -316,7 +321,11 @@ func autoSyncPush(database *db.DB, client *syncclient.Client, state *db.SyncStat
 			}
 		}
 		for _, r := range pushResp.Rejected {
-			if r.Reason == "duplicate" && r.ServerSeq > 0 {
+			if r.Reason != "duplicate" {
+				rejected[r.ClientActionID] = r.Reason
+				continue
+			}
+			if r.ServerSeq > 0 {
 				allAcks = append(allAcks, tdsync.Ack{ClientActionID: r.ClientActionID, ServerSeq: r.ServerSeq})
 				if r.ClientActionID > maxActionID {
 					maxActionID = r.ClientActionID
@@ -344,6 +353,14 @@ func autoSyncPush(database *db.DB, client *syncclient.Client, state *db.SyncStat
 		}
 	}
 
+	acked := make([]int64, len(allAcks))
+	for i, a := range allAcks {
+		acked[i] = a.ClientActionID
+	}
+	if err := db.RecordPushRejectionsTx(tx, rejected, acked); err != nil {
+		return fmt.Errorf("record rejections: %w", err)
+	}
+
 	if err := tdsync.MarkEventsSynced(tx, allAcks); err != nil {
 		return fmt.Errorf("mark synced: %w", err)
 	}
@@ -307,3 +307,63 @@ func TestAutoSyncPush_ServerRejectsUnbatched(t *testing.T) {
 		t.Fatalf("autoSyncPush should succeed with batching, got: %v", err)
 	}
 }
+
+func TestAutoSyncPush_RecordsRejections(t *testing.T) {
+	database := setupAutoSyncTestDB(t, 3)
+
+	var mu sync.Mutex
+	refuse := true
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/projects/", func(w http.ResponseWriter, r *http.Request) {
+		var req syncclient.PushRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "bad json", http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		var resp syncclient.PushResponse
+		for i, ev := range req.Events {
+			if refuse && i == 0 {
+				resp.Rejected = append(resp.Rejected, syncclient.RejectResponse{ClientActionID: ev.ClientActionID, Reason: "invalid payload"})
+				continue
+			}
+			resp.Accepted++
+			resp.Acks = append(resp.Acks, syncclient.AckResponse{ClientActionID: ev.ClientActionID, ServerSeq: int64(100 + i)})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	client := syncclient.New(srv.URL, "test-key", "dev-test")
+	state, _ := database.GetSyncState()
+
+	for round := 1; round <= 2; round++ {
+		if err := autoSyncPush(database, client, state, "dev-test"); err != nil {
+			t.Fatalf("push %d: %v", round, err)
+		}
+		entries, err := database.ListOutbox(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 || entries[0].Rejection != "invalid payload" || entries[0].Attempts != round {
+			t.Fatalf("outbox after push %d = %+v", round, entries)
+		}
+	}
+
+	mu.Lock()
+	refuse = false
+	mu.Unlock()
+	if err := autoSyncPush(database, client, state, "dev-test"); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := database.ListOutbox(0); len(entries) != 0 {
+		t.Errorf("outbox after accepted push = %+v", entries)
+	}
+	var n int
+	database.Conn().QueryRow(`SELECT COUNT(*) FROM sync_push_rejections`).Scan(&n)
+	if n != 0 {
+		t.Errorf("%d rejections left after the event was accepted", n)
+	}
+}
@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/marcus/td/internal/bench"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Benchmark td against a large synthetic project",
+	Long: `Seeds a synthetic project of --issues issues from --seed, then times TDQ
+parsing and execution, monitor refreshes, issue list serialization and SSE
+broadcasts. The same size and seed always give the same data, so a
+performance problem can be reproduced anywhere.
+
+The project is seeded in a temporary directory and removed afterwards; with
+--dir it is seeded there and kept, to explore with td monitor or td query.
+
+--save writes the results as a baseline. --baseline compares against one
+and exits non-zero when a case is more than --max-regression percent
+slower, for use as a CI gate.`,
+	Example: `  td bench
+  td bench --issues 20000 --seed 7 --run tdq
+  td bench --save bench.json
+  td bench --baseline bench.json --max-regression 25`,
+	GroupID: "system",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		issues, _ := cmd.Flags().GetInt("issues")
+		seed, _ := cmd.Flags().GetInt64("seed")
+		pattern, _ := cmd.Flags().GetString("run")
+		dir, _ := cmd.Flags().GetString("dir")
+		savePath, _ := cmd.Flags().GetString("save")
+		baselinePath, _ := cmd.Flags().GetString("baseline")
+		maxRegression, _ := cmd.Flags().GetFloat64("max-regression")
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		var filter *regexp.Regexp
+		if pattern != "" {
+			var err error
+			if filter, err = regexp.Compile(pattern); err != nil {
+				output.Error("invalid --run pattern: %v", err)
+				return err
+			}
+		}
+		var baseline *bench.Report
+		if baselinePath != "" {
+			var err error
+			if baseline, err = bench.LoadReport(baselinePath); err != nil {
+				output.Error("load baseline: %v", err)
+				return err
+			}
+		}
+
+		if dir == "" {
+			tmp, err := os.MkdirTemp("", "td-bench-")
+			if err != nil {
+				output.Error("%v", err)
+				return err
+			}
+			defer os.RemoveAll(tmp)
+			dir = tmp
+		} else if _, err := os.Stat(filepath.Join(dir, ".todos")); err == nil {
+			err := fmt.Errorf("--dir needs a fresh directory: %s/.todos already exists", dir)
+			output.Error("%v", err)
+			return err
+		}
+
+		database, err := db.Initialize(dir)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		opts := bench.Options{Issues: issues, Seed: seed}
+		start := time.Now()
+		res, err := bench.Seed(database, opts)
+		if err != nil {
+			output.Error("seed: %v", err)
+			return err
+		}
+		if !asJSON {
+			fmt.Printf("Seeded %d issues, %d dependencies, %d comments, %d logs in %s\n",
+				res.Issues, res.Dependencies, res.Comments, res.Logs, time.Since(start).Round(time.Millisecond))
+		}
+
+		report := bench.Run(bench.Cases(database), filter, opts)
+		var regressions []bench.Regression
+		if baseline != nil {
+			regressions = bench.Compare(baseline, report, maxRegression)
+		}
+
+		if savePath != "" {
+			if err := bench.SaveReport(savePath, report); err != nil {
+				output.Error("save report: %v", err)
+				return err
+			}
+		}
+
+		if asJSON {
+			data, _ := json.MarshalIndent(map[string]interface{}{
+				"report":      report,
+				"regressions": regressions,
+			}, "", "  ")
+			fmt.Println(string(data))
+		} else {
+			renderBench(report, baseline, regressions, maxRegression)
+			if savePath != "" {
+				output.Success("Saved baseline to %s", savePath)
+			}
+		}
+
+		if len(regressions) > 0 {
+			return fmt.Errorf("%d benchmark(s) regressed more than %.0f%%", len(regressions), maxRegression)
+		}
+		return nil
+	},
+}
+
+// renderBench prints a report, with the change against the baseline when
+// there is one
+func renderBench(report, baseline *bench.Report, regressions []bench.Regression, maxRegression float64) {
+	fmt.Println(output.SectionHeader(fmt.Sprintf("BENCHMARKS (%d issues, seed %d)", report.Issues, report.Seed)))
+
+	base := map[string]float64{}
+	if baseline != nil {
+		if baseline.Issues != report.Issues || baseline.Seed != report.Seed {
+			output.Warning("baseline used %d issues, seed %d; results may not be comparable", baseline.Issues, baseline.Seed)
+		}
+		for _, m := range baseline.Results {
+			base[m.Name] = m.NsPerOp
+		}
+	}
+	for _, m := range report.Results {
+		line := fmt.Sprintf("  %-16s %14s/op %10d B/op %8d allocs/op",
+			m.Name, benchDuration(m.NsPerOp), m.BytesPerOp, m.AllocsPerOp)
+		if was, ok := base[m.Name]; ok && was > 0 {
+			line += fmt.Sprintf("  %+6.1f%%", (m.NsPerOp-was)/was*100)
+		}
+		fmt.Println(line)
+	}
+
+	if baseline == nil {
+		return
+	}
+	fmt.Println()
+	if len(regressions) == 0 {
+		output.Success("No case regressed more than %.0f%%", maxRegression)
+		return
+	}
+	for _, r := range regressions {
+		output.Error("%s: %s/op, was %s/op (%+.1f%%)", r.Name,
+			benchDuration(r.Current), benchDuration(r.Baseline), r.Percent)
+	}
+}
+
+// benchDuration rounds a per-op time for display
+func benchDuration(ns float64) time.Duration {
+	d := time.Duration(ns)
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond)
+	case d >= time.Millisecond:
+		return d.Round(time.Microsecond)
+	}
+	return d
+}
+
+func init() {
+	rootCmd.AddCommand(benchCmd)
+
+	benchCmd.Flags().Int("issues", bench.DefaultIssues, "Issues in the synthetic project")
+	benchCmd.Flags().Int64("seed", bench.DefaultSeed, "Random seed for the synthetic project")
+	benchCmd.Flags().String("run", "", "Only run cases whose name matches this regexp")
+	benchCmd.Flags().String("dir", "", "Seed the project in this directory and keep it")
+	benchCmd.Flags().String("save", "", "Write the results to this file as a baseline")
+	benchCmd.Flags().String("baseline", "", "Compare against a saved baseline")
+	benchCmd.Flags().Float64("max-regression", 20, "Percent slowdown against --baseline that fails the run")
+	benchCmd.Flags().Bool("json", false, "Output as JSON")
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/marcus/td/internal/db"
 	"github.com/marcus/td/internal/models"
@@ -12,8 +13,21 @@ import (
 )
 
 var blockCmd = &cobra.Command{
-	Use:     "block [issue-id...]",
-	Short:   "Mark issue(s) as blocked",
+	Use:   "block [issue-id...]",
+	Short: "Mark issue(s) as blocked",
+	Long: `Marks issue(s) as blocked, recording why.
+
+--because takes the kind of blocker:
+  dependency   waiting on other issues (inferred when the issue has open dependencies)
+  external     waiting on something outside td; add --ref with a link or ticket
+  decision     needs a decision before work can continue
+
+Issues blocked on an external wait or a decision stay blocked when their
+dependencies close.
+
+Examples:
+  td block td-abc1 --because external --ref https://github.com/acme/api/issues/42
+  td block td-abc1 --because decision --reason "pick a queue backend"`,
 	GroupID: "workflow",
 	Args:    cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -33,6 +47,20 @@ var blockCmd = &cobra.Command{
 		}
 
 		reason, _ := cmd.Flags().GetString("reason")
+		because, _ := cmd.Flags().GetString("because")
+		ref, _ := cmd.Flags().GetString("ref")
+
+		var kind models.BlockedReason
+		if because != "" {
+			kind = models.NormalizeBlockedReason(because)
+			if !models.IsValidBlockedReason(kind) {
+				err := fmt.Errorf("invalid --because %q (use dependency, external or decision)", because)
+				output.Error("%v", err)
+				return err
+			}
+		} else if ref != "" {
+			kind = models.BlockedReasonExternal
+		}
 
 		for _, issueID := range args {
 			issue, err := database.GetIssue(issueID)
@@ -41,6 +69,16 @@ var blockCmd = &cobra.Command{
 				continue
 			}
 
+			issueKind := kind
+			if issueKind == "" {
+				if open, _ := database.HasOpenDependencies(issue.ID); open {
+					issueKind = models.BlockedReasonDependency
+				} else {
+					output.Warning("cannot block %s: say why with --because dependency|external|decision", issueID)
+					continue
+				}
+			}
+
 			// Validate transition with state machine
 			sm := workflow.DefaultMachine()
 			if !sm.IsValidTransition(issue.Status, models.StatusBlocked) {
@@ -49,6 +87,8 @@ var blockCmd = &cobra.Command{
 			}
 
 			issue.Status = models.StatusBlocked
+			issue.BlockedReason = issueKind
+			issue.BlockedRef = ref
 
 			if err := database.UpdateIssueLogged(issue, sess.ID, models.ActionBlock); err != nil {
 				output.Error("failed to block %s: %v", issueID, err)
@@ -68,7 +108,11 @@ var blockCmd = &cobra.Command{
 				Type:      models.LogTypeBlocker,
 			})
 
-			fmt.Printf("BLOCKED %s\n", issueID)
+			if issue.BlockedRef != "" {
+				fmt.Printf("BLOCKED %s (%s: %s)\n", issueID, issue.BlockedReason, issue.BlockedRef)
+			} else {
+				fmt.Printf("BLOCKED %s (%s)\n", issueID, issue.BlockedReason)
+			}
 		}
 
 		return nil
@@ -80,9 +124,18 @@ var reopenCmd = &cobra.Command{
 	Short: "Reopen closed issues",
 	Long: `Reopens closed issue(s) back to open status.
 
+Each reopen is recorded as a rework cycle for td stats and td report rework.
+--category says why the work came back:
+  bug           it did not do what it should
+  incomplete    acceptance criteria were not met
+  requirements  the requirements changed after closing
+  regression    it worked, then broke again
+  other         anything else (default)
+
 Examples:
   td reopen td-abc1                    # Reopen single issue
-  td reopen td-abc1 td-abc2 td-abc3    # Reopen multiple issues`,
+  td reopen td-abc1 td-abc2 td-abc3    # Reopen multiple issues
+  td reopen td-abc1 --category incomplete --reason "no tests for expiry"`,
 	GroupID: "workflow",
 	Args:    cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -102,6 +155,13 @@ Examples:
 		}
 
 		reason, _ := cmd.Flags().GetString("reason")
+		categoryFlag, _ := cmd.Flags().GetString("category")
+		category := models.ReworkCategory(strings.ToLower(categoryFlag))
+		if !models.IsValidReworkCategory(category) {
+			err := fmt.Errorf("invalid --category %q (use bug, incomplete, requirements, regression or other)", categoryFlag)
+			output.Error("%v", err)
+			return err
+		}
 		reopened := 0
 		skipped := 0
 
@@ -127,6 +187,13 @@ Examples:
 				continue
 			}
 
+			rework := &models.Rework{
+				IssueID:   issue.ID,
+				Category:  category,
+				Reason:    reason,
+				Approved:  issue.ReviewerSession != "",
+				SessionID: sess.ID,
+			}
 			issue.Status = models.StatusOpen
 			issue.ReviewerSession = ""
 			issue.ClosedAt = nil
@@ -136,6 +203,9 @@ Examples:
 				skipped++
 				continue
 			}
+			if err := database.RecordRework(rework); err != nil {
+				output.Warning("failed to record rework for %s: %v", issueID, err)
+			}
 
 			// Log
 			logMsg := "Reopened"
@@ -251,6 +321,9 @@ func init() {
 	rootCmd.AddCommand(reopenCmd)
 
 	blockCmd.Flags().String("reason", "", "Reason for blocking")
+	blockCmd.Flags().String("because", "", "Kind of blocker: dependency, external or decision")
+	blockCmd.Flags().String("ref", "", "External reference, e.g. a ticket URL (implies --because external)")
 	unblockCmd.Flags().String("reason", "", "Reason for unblocking")
 	reopenCmd.Flags().String("reason", "", "Reason for reopening")
+	reopenCmd.Flags().String("category", string(models.ReworkOther), "Why the work came back: bug, incomplete, requirements, regression or other")
 }
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/marcus/td/internal/clone"
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/git"
+	"github.com/marcus/td/internal/output"
+	"github.com/marcus/td/internal/query"
+	"github.com/marcus/td/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var cloneCmd = &cobra.Command{
+	Use:   "clone [issue-id...]",
+	Short: "Copy issues, with their checklist, labels, links and subtree",
+	Long: `Creates an open copy of each issue: title, description, type, priority,
+points and sprint. --checklist, --labels, --links (dependencies and linked
+files) and --children (the whole subtree) copy more; --all copies
+everything.
+
+--from-sprint and --query pick issues to clone in bulk, e.g. last sprint's
+recurring rituals. When an issue and its parent are both cloned, the copy
+goes under the parent's copy, and dependencies between cloned issues point
+at the copies.`,
+	Example: `  td clone td-a1b2
+  td clone td-a1b2 --all --sprint 25
+  td clone --from-sprint 24 --query 'labels ~ ritual' --sprint 25 --all`,
+	GroupID: "core",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		baseDir := getBaseDir()
+		fromSprint, _ := cmd.Flags().GetString("from-sprint")
+		queryStr, _ := cmd.Flags().GetString("query")
+		if len(args) == 0 && fromSprint == "" && queryStr == "" {
+			err := fmt.Errorf("give issue IDs, --from-sprint or --query")
+			output.Error("%v", err)
+			return err
+		}
+
+		database, err := db.Open(baseDir)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		sess, err := session.GetOrCreate(database)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		ids := append([]string(nil), args...)
+		if fromSprint != "" || queryStr != "" {
+			tdq := clone.SelectQuery(queryStr, fromSprint)
+			issues, err := query.Execute(database, tdq, sess.ID, query.ExecuteOptions{Project: config.GetProjectName(baseDir)})
+			if err != nil {
+				output.Error("%v", err)
+				return err
+			}
+			for _, issue := range issues {
+				ids = append(ids, issue.ID)
+			}
+			if len(ids) == 0 {
+				output.Info("No issues match; nothing cloned")
+				return nil
+			}
+		}
+
+		opts := cloneOptions(cmd)
+		if gitState, _ := git.GetState(); gitState != nil {
+			opts.CreatedBranch = gitState.Branch
+			opts.CreatedRepo = gitState.Repo
+		}
+
+		result, err := clone.Clone(database, ids, opts, sess.ID)
+		if err != nil {
+			// Clones made before the failure stay; report them
+			if result != nil && len(result.Clones) > 0 {
+				printCloneResult(result)
+			}
+			output.Error("%v", err)
+			return err
+		}
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			data, _ := json.MarshalIndent(result, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+		printCloneResult(result)
+		return nil
+	},
+}
+
+// cloneOptions reads the clone flags
+func cloneOptions(cmd *cobra.Command) clone.Options {
+	all, _ := cmd.Flags().GetBool("all")
+	var opts clone.Options
+	opts.Checklist, _ = cmd.Flags().GetBool("checklist")
+	opts.Labels, _ = cmd.Flags().GetBool("labels")
+	opts.Links, _ = cmd.Flags().GetBool("links")
+	opts.Children, _ = cmd.Flags().GetBool("children")
+	if all {
+		opts.Checklist, opts.Labels, opts.Links, opts.Children = true, true, true, true
+	}
+	opts.Sprint, _ = cmd.Flags().GetString("sprint")
+	opts.Parent, _ = cmd.Flags().GetString("parent")
+	return opts
+}
+
+// printCloneResult lists each source and its clone
+func printCloneResult(r *clone.Result) {
+	for _, p := range r.Clones {
+		fmt.Printf("CLONED %s -> %s  %s\n", p.Source, p.Clone, p.Title)
+	}
+	if r.Dependencies > 0 {
+		fmt.Printf("  dependencies copied: %d\n", r.Dependencies)
+	}
+	if r.Files > 0 {
+		fmt.Printf("  linked files copied: %d\n", r.Files)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(cloneCmd)
+
+	cloneCmd.Flags().Bool("checklist", false, "Copy acceptance criteria")
+	cloneCmd.Flags().Bool("labels", false, "Copy labels")
+	cloneCmd.Flags().Bool("links", false, "Copy dependencies and linked files")
+	cloneCmd.Flags().Bool("children", false, "Copy each issue's whole subtree")
+	cloneCmd.Flags().BoolP("all", "a", false, "Copy checklist, labels, links and children")
+	cloneCmd.Flags().String("sprint", "", "Put the clones in this sprint")
+	cloneCmd.Flags().String("parent", "", "Parent for the top-level clones")
+	cloneCmd.Flags().String("from-sprint", "", "Clone the issues in this sprint")
+	cloneCmd.Flags().StringP("query", "q", "", "Clone the issues matching this TDQ query")
+	cloneCmd.Flags().Bool("json", false, "Output as JSON")
+}
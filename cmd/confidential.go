@@ -0,0 +1,400 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/marcus/td/internal/confidential"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/input"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/output"
+	"github.com/marcus/td/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var confidentialCmd = &cobra.Command{
+	Use:   "confidential",
+	Short: "Encrypt issues and choose who can read them",
+	Long: `A confidential issue keeps its title, status and labels in the open, but its
+description, acceptance criteria and comments are encrypted with the
+project key in .todos/confidential.key. The database, sync, exports and
+webhooks only carry the encrypted text.
+
+Sessions granted access see the text in td show, td serve and the monitor;
+everyone else sees [confidential]. The session that made an issue
+confidential is granted access. Grant a session ID, a session name (which
+survives session changes), or role:read, role:write or role:admin for API
+tokens with that scope.
+
+The key never leaves this machine on its own. Share it with teammates with
+td confidential key export and td confidential key import, or set
+TD_CONFIDENTIAL_KEY. Anyone with the key and the database can decrypt the
+text, so grants decide what td shows, not who could read the data.
+
+Marking an existing issue confidential encrypts its current text; earlier
+versions kept in the undo log and edit history are not rewritten. Create
+sensitive issues with td create --confidential.`,
+	GroupID: "workflow",
+}
+
+var confidentialOnCmd = &cobra.Command{
+	Use:   "on <issue-id>...",
+	Short: "Make issues confidential, encrypting their text and comments",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setConfidential(cmd, args, true)
+	},
+}
+
+var confidentialOffCmd = &cobra.Command{
+	Use:   "off <issue-id>...",
+	Short: "Make issues public again, decrypting their text and comments",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setConfidential(cmd, args, false)
+	},
+}
+
+func setConfidential(cmd *cobra.Command, args []string, on bool) error {
+	cmd.SilenceUsage = true
+	baseDir := getBaseDir()
+	database, err := db.Open(baseDir)
+	if err != nil {
+		output.Error("%v", err)
+		return err
+	}
+	defer database.Close()
+
+	sess, err := session.GetOrCreate(database)
+	if err != nil {
+		output.Error("%v", err)
+		return err
+	}
+	if on {
+		if err := ensureConfidentialKey(baseDir); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+	}
+
+	var failed error
+	for _, id := range args {
+		issue, err := database.GetIssue(id)
+		if err == nil && !on {
+			err = requireConfidentialAccess(database, issue, sess.ID)
+		}
+		if err == nil {
+			err = database.SetConfidential(issue.ID, on, sess.ID)
+		}
+		if err != nil {
+			output.Error("%s: %v", id, err)
+			failed = err
+			continue
+		}
+		if on {
+			output.Success("%s is confidential", issue.ID)
+		} else {
+			output.Success("%s is no longer confidential", issue.ID)
+		}
+	}
+	return failed
+}
+
+var confidentialGrantCmd = &cobra.Command{
+	Use:   "grant <issue-id> <grantee>...",
+	Short: "Let sessions, session names or token roles read a confidential issue",
+	Example: `  td confidential grant td-a1b2 ses_9f8e7d
+  td confidential grant td-a1b2 security-oncall role:admin`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		database, issue, sess, err := openConfidentialIssue(args[0])
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		for _, grantee := range args[1:] {
+			if err := confidential.ValidGrantee(grantee); err != nil {
+				output.Error("%v", err)
+				return err
+			}
+		}
+		for _, grantee := range args[1:] {
+			if err := database.GrantConfidential(issue.ID, grantee, sess.ID); err != nil {
+				output.Error("%v", err)
+				return err
+			}
+			output.Success("%s can read %s", grantee, issue.ID)
+		}
+		return nil
+	},
+}
+
+var confidentialRevokeCmd = &cobra.Command{
+	Use:   "revoke <issue-id> <grantee>...",
+	Short: "Remove grants from a confidential issue",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		database, issue, _, err := openConfidentialIssue(args[0])
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		for _, grantee := range args[1:] {
+			removed, err := database.RevokeConfidential(issue.ID, grantee)
+			if err != nil {
+				output.Error("%v", err)
+				return err
+			}
+			if removed {
+				output.Success("%s can no longer read %s", grantee, issue.ID)
+			} else {
+				output.Warning("%s had no grant on %s", grantee, issue.ID)
+			}
+		}
+		return nil
+	},
+}
+
+var confidentialGrantsCmd = &cobra.Command{
+	Use:   "grants <issue-id>",
+	Short: "List who can read a confidential issue",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		database, issue, _, err := openConfidentialIssue(args[0])
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		grants, err := database.ListConfidentialGrants(issue.ID)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			data, _ := json.MarshalIndent(map[string]interface{}{"creator": issue.CreatorSession, "grants": grants}, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+		if issue.CreatorSession != "" {
+			fmt.Printf("%-24s (creator)\n", issue.CreatorSession)
+		}
+		for _, g := range grants {
+			fmt.Printf("%-24s granted by %s %s\n", g.Grantee, g.GrantedBy, output.FormatTimeAgo(g.CreatedAt))
+		}
+		return nil
+	},
+}
+
+var confidentialListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List confidential issues, marking those you can read",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		issues, err := database.ListIssues(db.ListIssuesOptions{})
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		viewer := cliViewer(database)
+		type entry struct {
+			ID       string `json:"id"`
+			Title    string `json:"title"`
+			Status   string `json:"status"`
+			Readable bool   `json:"readable"`
+		}
+		entries := []entry{}
+		for i := range issues {
+			if !issues[i].Confidential {
+				continue
+			}
+			ok, _ := database.CanReadConfidential(&issues[i], viewer)
+			entries = append(entries, entry{ID: issues[i].ID, Title: issues[i].Title, Status: string(issues[i].Status), Readable: ok})
+		}
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			data, _ := json.MarshalIndent(entries, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+		if len(entries) == 0 {
+			output.Info("No confidential issues")
+			return nil
+		}
+		for _, e := range entries {
+			access := "no access"
+			if e.Readable {
+				access = "readable"
+			}
+			fmt.Printf("%s  %-11s  %-9s  %s\n", e.ID, e.Status, access, e.Title)
+		}
+		return nil
+	},
+}
+
+var confidentialKeyCmd = &cobra.Command{
+	Use:   "key",
+	Short: "Create, export and import the project key",
+}
+
+var confidentialKeyInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create the project key if there is none",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		baseDir := getBaseDir()
+		_, created, err := confidential.EnsureKey(baseDir)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if created {
+			output.Success("Created %s", confidential.KeyPath(baseDir))
+		} else {
+			output.Info("The project already has a key")
+		}
+		return nil
+	},
+}
+
+var confidentialKeyExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Print the project key to hand to a teammate",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		key, err := confidential.LoadKey(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		fmt.Println(confidential.EncodeKey(key))
+		return nil
+	},
+}
+
+var confidentialKeyImportCmd = &cobra.Command{
+	Use:   "import [key|-]",
+	Short: "Save a teammate's project key (from the argument or stdin)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		encoded := ""
+		if len(args) == 1 && args[0] != "-" {
+			encoded = args[0]
+		} else {
+			data, err := input.ReadText(os.Stdin)
+			if err != nil {
+				output.Error("failed to read stdin: %v", err)
+				return err
+			}
+			encoded = data
+		}
+		key, err := confidential.DecodeKey(encoded)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		force, _ := cmd.Flags().GetBool("force")
+		baseDir := getBaseDir()
+		if err := confidential.SaveKey(baseDir, key, force); err != nil {
+			output.Error("%v (--force replaces it; text sealed with the old key becomes unreadable)", err)
+			return err
+		}
+		output.Success("Saved %s", confidential.KeyPath(baseDir))
+		return nil
+	},
+}
+
+// ensureConfidentialKey creates the project key on first use, saying so
+func ensureConfidentialKey(baseDir string) error {
+	_, created, err := confidential.EnsureKey(baseDir)
+	if created {
+		output.Info("Created the project key %s; share it with td confidential key export", confidential.KeyPath(baseDir))
+	}
+	return err
+}
+
+// openConfidentialIssue opens the database and loads a confidential issue
+// the current session can read
+func openConfidentialIssue(id string) (*db.DB, *models.Issue, *session.Session, error) {
+	database, err := db.Open(getBaseDir())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	sess, err := session.GetOrCreate(database)
+	if err == nil {
+		var issue *models.Issue
+		issue, err = database.GetIssue(id)
+		if err == nil && !issue.Confidential {
+			err = fmt.Errorf("%s is not confidential", issue.ID)
+		}
+		if err == nil {
+			err = requireConfidentialAccess(database, issue, sess.ID)
+		}
+		if err == nil {
+			return database, issue, sess, nil
+		}
+	}
+	database.Close()
+	return nil, nil, nil, err
+}
+
+// cliViewer describes the current session as a reader of confidential
+// issues. Without a session it can read nothing confidential.
+func cliViewer(database *db.DB) confidential.Viewer {
+	sess, err := session.Get(database)
+	if err != nil {
+		return confidential.Viewer{}
+	}
+	return database.ConfidentialViewer(sess.ID)
+}
+
+// requireConfidentialAccess fails unless sessionID may read issue
+func requireConfidentialAccess(database *db.DB, issue *models.Issue, sessionID string) error {
+	ok, err := database.CanReadConfidential(issue, database.ConfidentialViewer(sessionID))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%s is confidential and not shared with this session", issue.ID)
+	}
+	return nil
+}
+
+// revealIssue decrypts a confidential issue and its comments for the
+// current session, or redacts them
+func revealIssue(database *db.DB, issue *models.Issue, comments []models.Comment) {
+	if issue == nil || !issue.Confidential {
+		return
+	}
+	_, _ = database.RevealIssue(issue, comments, cliViewer(database))
+}
+
+func init() {
+	confidentialGrantsCmd.Flags().Bool("json", false, "Output as JSON")
+	confidentialListCmd.Flags().Bool("json", false, "Output as JSON")
+	confidentialKeyImportCmd.Flags().Bool("force", false, "Replace a different existing key")
+	confidentialKeyCmd.AddCommand(confidentialKeyInitCmd, confidentialKeyExportCmd, confidentialKeyImportCmd)
+	confidentialCmd.AddCommand(confidentialOnCmd, confidentialOffCmd, confidentialGrantCmd, confidentialRevokeCmd,
+		confidentialGrantsCmd, confidentialListCmd, confidentialKeyCmd)
+	rootCmd.AddCommand(confidentialCmd)
+}
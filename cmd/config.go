@@ -3,11 +3,14 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
 
 	"github.com/marcus/td/internal/features"
+
 	"github.com/marcus/td/internal/output"
+	"github.com/marcus/td/internal/settings"
 	"github.com/marcus/td/internal/syncconfig"
 	"github.com/spf13/cobra"
 )
@@ -213,9 +216,105 @@ var configListCmd = &cobra.Command{
 	},
 }
 
+var configDoctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Show effective settings and where each comes from",
+	Long: `Show the value td uses for each setting and the layer it came from.
+Layers, later ones winning:
+
+  default  built into td
+  user     ~/.config/td/config.json
+  profile  the user config's "profiles" entry named by TD_PROFILE
+  project  .todos/config.json
+  env      TD_* environment variables
+  flag     command flags, e.g. td serve --port
+
+When more than one layer sets a value, the ones it overrides are listed
+under it. Values td would reject are ignored, as td ignores them, and
+reported at the end along with config files that could not be read.`,
+	Example: `  td config doctor
+  td config doctor --area serve
+  TD_PROFILE=ci td config doctor --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		area, _ := cmd.Flags().GetString("area")
+		switch area {
+		case "", settings.AreaServe, settings.AreaSync, settings.AreaWebhook, settings.AreaWorkflow, settings.AreaTUI:
+		default:
+			err := fmt.Errorf("invalid area %q: use serve, sync, webhook, workflow or tui", area)
+			output.Error("%v", err)
+			return err
+		}
+
+		values, problems := settings.Resolve(getBaseDir(), os.Getenv)
+		var shown []settings.Value
+		for _, v := range values {
+			if area == "" || v.Area == area {
+				shown = append(shown, v)
+			}
+		}
+
+		if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+			if problems == nil {
+				problems = []string{}
+			}
+			return output.JSON(map[string]interface{}{
+				"profile":  syncconfig.ActiveProfile(),
+				"settings": shown,
+				"problems": problems,
+			})
+		}
+
+		if profile := syncconfig.ActiveProfile(); profile != "" {
+			fmt.Printf("Profile: %s (TD_PROFILE)\n", profile)
+		}
+		lastArea := ""
+		for _, v := range shown {
+			if v.Area != lastArea {
+				fmt.Print(output.SectionHeader(strings.ToUpper(v.Area[:1]) + v.Area[1:]))
+				lastArea = v.Area
+			}
+			value := v.Value
+			if value == "" {
+				value = "(unset)"
+			}
+			fmt.Printf("  %-38s %-24s %s\n", v.Key, value, settingSourceLabel(v.Setting, v.Source))
+			for i := len(v.Overridden) - 1; i >= 0; i-- {
+				o := v.Overridden[i]
+				fmt.Printf("  %-38s   overrides %s from %s\n", "", o.Value, settingSourceLabel(v.Setting, o.Source))
+			}
+		}
+		for _, p := range problems {
+			output.Warning("%s", p)
+		}
+		return nil
+	},
+}
+
+// settingSourceLabel names where a layer keeps a setting
+func settingSourceLabel(s settings.Setting, source settings.Source) string {
+	switch source {
+	case settings.SourceUser:
+		return "user (" + s.UserPath + ")"
+	case settings.SourceProfile:
+		return "profile " + syncconfig.ActiveProfile() + " (" + s.UserPath + ")"
+	case settings.SourceProject:
+		return "project (" + s.ProjectPath + ")"
+	case settings.SourceEnv:
+		return "env (" + s.Env + ")"
+	}
+	return string(source)
+}
+
 func init() {
-	configCmd.AddCommand(configSetCmd)
-	configCmd.AddCommand(configGetCmd)
-	configCmd.AddCommand(configListCmd)
-	AddFeatureGatedCommand(features.SyncCLI.Name, configCmd)
+	configDoctorCmd.Flags().String("area", "", "Only show one area: serve, sync, webhook, workflow or tui")
+	configDoctorCmd.Flags().Bool("json", false, "Output as JSON")
+	configCmd.AddCommand(configDoctorCmd)
+	// Setting sync keys by name is part of the sync CLI
+	if features.IsEnabledForProcess(features.SyncCLI.Name) {
+		configCmd.AddCommand(configSetCmd)
+		configCmd.AddCommand(configGetCmd)
+		configCmd.AddCommand(configListCmd)
+	}
+	rootCmd.AddCommand(configCmd)
 }
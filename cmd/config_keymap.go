@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/marcus/td/internal/output"
+	"github.com/marcus/td/pkg/monitor/keymap"
+	"github.com/spf13/cobra"
+)
+
+var configKeymapCmd = &cobra.Command{
+	Use:   "keymap",
+	Short: "Show or remap the monitor's keys",
+	Long: `Show the keys remapped in .todos/keymap.json, for vim-style habits or
+keyboard layouts where the default keys are awkward to reach. Each binding
+is "context:key" and a command:
+
+  main     the issue panels          board   board and backlog view
+  modal    issue details             form    create and edit form
+  global   every context, unless the context binds the key itself
+
+A remapped key wins over the default one in its context; the default key
+keeps working unless it is remapped too or bound to "none". td config
+keymap --defaults lists every context, key and command.
+
+The monitor's ? help shows the remapped keys. Bindings with an unknown
+context or command, or a key given twice, are skipped: td config keymap
+check reports them, along with keys that start a key sequence and commands
+left without a key.`,
+	Example: `  td config keymap
+  td config keymap set main:ö cursor-down
+  td config keymap set main:x none
+  td config keymap unset main:x
+  td config keymap check`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		if defaults, _ := cmd.Flags().GetBool("defaults"); defaults {
+			bindings := keymap.DefaultBindings()
+			if asJSON {
+				data, _ := json.MarshalIndent(bindings, "", "  ")
+				fmt.Println(string(data))
+				return nil
+			}
+			for _, b := range bindings {
+				fmt.Printf("%-20s %-12s %-24s %s\n", b.Context, b.Key, b.Command, b.Description)
+			}
+			return nil
+		}
+
+		cfg, err := keymap.LoadConfig(keymap.ConfigPath(getBaseDir()))
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if asJSON {
+			data, _ := json.MarshalIndent(cfg, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+		if len(cfg.Bindings) == 0 {
+			output.Info("No remapped keys; the monitor uses its defaults (td config keymap --defaults)")
+			return nil
+		}
+		names := make([]string, 0, len(cfg.Bindings))
+		for name := range cfg.Bindings {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("%-24s %s\n", name, cfg.Bindings[name])
+		}
+		printKeymapProblems(keymap.Check(cfg))
+		return nil
+	},
+}
+
+var configKeymapSetCmd = &cobra.Command{
+	Use:   "set <context:key> <command>",
+	Short: "Bind a key to a command",
+	Long:  `Bind a key to a command in a context. The command "none" unbinds the key.`,
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		path := keymap.ConfigPath(getBaseDir())
+		cfg, err := keymap.LoadConfig(path)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		name := args[0]
+		if !strings.Contains(name, ":") {
+			name = string(keymap.ContextGlobal) + ":" + name
+		}
+		cfg.Bindings[name] = args[1]
+
+		var problems []keymap.Problem
+		for _, p := range keymap.Check(cfg) {
+			if p.Binding == name {
+				problems = append(problems, p)
+			}
+		}
+		if keymap.HasErrors(problems) {
+			err := fmt.Errorf("%s: %s", name, problems[0].Message)
+			output.Error("%v", err)
+			return err
+		}
+		if err := keymap.SaveConfig(path, cfg); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		output.Success("Bound %s to %s", name, args[1])
+		printKeymapProblems(problems)
+		return nil
+	},
+}
+
+var configKeymapUnsetCmd = &cobra.Command{
+	Use:   "unset <context:key>",
+	Short: "Restore a key's default binding",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		path := keymap.ConfigPath(getBaseDir())
+		cfg, err := keymap.LoadConfig(path)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		name := args[0]
+		if _, ok := cfg.Bindings[name]; !ok {
+			name = string(keymap.ContextGlobal) + ":" + name
+		}
+		if _, ok := cfg.Bindings[name]; !ok {
+			err := fmt.Errorf("%s is not remapped", args[0])
+			output.Error("%v", err)
+			return err
+		}
+		delete(cfg.Bindings, name)
+		if err := keymap.SaveConfig(path, cfg); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		output.Success("Restored the default binding for %s", name)
+		return nil
+	},
+}
+
+var configKeymapCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Report invalid and conflicting key bindings",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		cfg, err := keymap.LoadConfig(keymap.ConfigPath(getBaseDir()))
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		problems := keymap.Check(cfg)
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			if problems == nil {
+				problems = []keymap.Problem{}
+			}
+			data, _ := json.MarshalIndent(problems, "", "  ")
+			fmt.Println(string(data))
+		} else if len(problems) == 0 {
+			output.Success("%d key bindings, no problems", len(cfg.Bindings))
+		} else {
+			printKeymapProblems(problems)
+		}
+		if keymap.HasErrors(problems) {
+			return fmt.Errorf("keymap.json has bindings the monitor skips")
+		}
+		return nil
+	},
+}
+
+// printKeymapProblems prints keymap problems as errors and warnings
+func printKeymapProblems(problems []keymap.Problem) {
+	for _, p := range problems {
+		if p.Severity == keymap.SeverityError {
+			output.Error("%s: %s (skipped)", p.Binding, p.Message)
+		} else {
+			output.Warning("%s: %s", p.Binding, p.Message)
+		}
+	}
+}
+
+func init() {
+	configKeymapCmd.Flags().Bool("defaults", false, "List the default bindings of every context")
+	configKeymapCmd.Flags().Bool("json", false, "Output as JSON")
+	configKeymapCheckCmd.Flags().Bool("json", false, "Output as JSON")
+	configKeymapCmd.AddCommand(configKeymapSetCmd, configKeymapUnsetCmd, configKeymapCheckCmd)
+	configCmd.AddCommand(configKeymapCmd)
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/marcus/td/internal/i18n"
+	"github.com/marcus/td/internal/output"
+	"github.com/marcus/td/internal/syncconfig"
+	"github.com/spf13/cobra"
+)
+
+var configLocaleCmd = &cobra.Command{
+	Use:   "locale [language]",
+	Short: "Show or set the language of td's messages",
+	Long: `Show the language td uses for its messages, or set it in your user config
+(~/.config/td/config.json). Translated so far: the monitor's footer, help
+and alerts, and validation errors.
+
+Without a setting td follows LC_ALL, LC_MESSAGES and LANG, falling back to
+English. TD_LANG overrides the setting for one process.
+
+Languages: ` + strings.Join(i18n.Locales(), ", "),
+	Example: `  td config locale
+  td config locale es
+  td config locale --reset`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		reset, _ := cmd.Flags().GetBool("reset")
+
+		if len(args) == 0 && !reset {
+			fmt.Println(i18n.Current())
+			return nil
+		}
+
+		locale := ""
+		if !reset {
+			locale = i18n.Normalize(args[0])
+			if locale == "" {
+				err := fmt.Errorf("unsupported language %q: use one of %s", args[0], strings.Join(i18n.Locales(), ", "))
+				output.Error("%v", err)
+				return err
+			}
+		}
+
+		cfg, err := syncconfig.LoadConfig()
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		cfg.Locale = locale
+		if err := syncconfig.SaveConfig(cfg); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if reset {
+			output.Success("Language reset to the system's (%s)", i18n.Detect("", os.Getenv))
+			return nil
+		}
+		output.Success("Language set: %s", locale)
+		return nil
+	},
+}
+
+// useLocale picks the language td's messages are shown in for this
+// process: TD_LANG, then the user config, then the system's
+func useLocale() {
+	i18n.Use(i18n.Detect(syncconfig.GetLocale(), os.Getenv))
+}
+
+func init() {
+	configLocaleCmd.Flags().Bool("reset", false, "Follow the system's language again")
+	configCmd.AddCommand(configLocaleCmd)
+}
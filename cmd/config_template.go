@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/issuetmpl"
+	"github.com/marcus/td/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var configTemplateCmd = &cobra.Command{
+	Use:   "template [name] [text]",
+	Short: "List, show or set output templates for td list and td show",
+	Long: `Manage the Go templates td list and td show render with --template @name.
+With no arguments, lists every template; with a name, prints it; with a
+name and text, saves it to the project config.
+
+A template runs once per issue and sees the issue's fields: .ID, .Title,
+.Status, .Type, .Priority, .Points, .Labels, .Sprint, .ParentID,
+.Description, .Acceptance, .CreatedAt, .UpdatedAt, .DueDate and the rest.
+td show also fills in .Logs and .Handoff. Besides the text/template
+builtins, templates can call join, upper, lower, pad, trunc, date and ago.
+
+Built-in templates: @compact, @ids, @markdown, @tsv. A project template
+with the same name replaces the built-in.`,
+	Example: `  td config template
+  td config template review '{{.ID}} {{upper .Priority}} {{trunc 50 .Title}} ({{join "," .Labels}})'
+  td list --template @review
+  td config template review --rm`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		baseDir := getBaseDir()
+
+		custom, err := config.GetTemplates(baseDir)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		if rm, _ := cmd.Flags().GetBool("rm"); rm {
+			if len(args) != 1 {
+				err := fmt.Errorf("--rm takes a template name")
+				output.Error("%v", err)
+				return err
+			}
+			removed, err := config.RemoveTemplate(baseDir, args[0])
+			if err != nil {
+				output.Error("%v", err)
+				return err
+			}
+			if !removed {
+				output.Warning("no project template named %s", args[0])
+				return nil
+			}
+			output.Success("Removed template @%s", args[0])
+			return nil
+		}
+
+		switch len(args) {
+		case 0:
+			for _, name := range issuetmpl.Names(custom) {
+				text, _ := issuetmpl.Lookup(name, custom)
+				source := "built-in"
+				if _, ok := custom[name]; ok {
+					source = "project"
+				}
+				fmt.Printf("@%-12s %-8s  %s\n", name, source, strings.ReplaceAll(text, "\n", `\n`))
+			}
+		case 1:
+			text, ok := issuetmpl.Lookup(strings.TrimPrefix(args[0], "@"), custom)
+			if !ok {
+				err := fmt.Errorf("unknown template @%s", strings.TrimPrefix(args[0], "@"))
+				output.Error("%v", err)
+				return err
+			}
+			fmt.Println(text)
+		default:
+			name := strings.TrimPrefix(args[0], "@")
+			if _, err := issuetmpl.Parse(args[1], nil); err != nil {
+				output.Error("%v", err)
+				return err
+			}
+			if err := config.SetTemplate(baseDir, name, args[1]); err != nil {
+				output.Error("%v", err)
+				return err
+			}
+			output.Success("Template saved: use --template @%s", name)
+		}
+		return nil
+	},
+}
+
+// issueTemplate compiles the command's --template value, or returns nil
+// when it was not given.
+func issueTemplate(cmd *cobra.Command, baseDir string) (*template.Template, error) {
+	spec, _ := cmd.Flags().GetString("template")
+	if spec == "" {
+		return nil, nil
+	}
+	custom, err := config.GetTemplates(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	return issuetmpl.Parse(spec, custom)
+}
+
+// renderIssueTemplate prints issues through tmpl
+func renderIssueTemplate(tmpl *template.Template, items []issuetmpl.Data) error {
+	if err := issuetmpl.Render(os.Stdout, tmpl, items); err != nil {
+		output.Error("%v", err)
+		return err
+	}
+	return nil
+}
+
+func init() {
+	configTemplateCmd.Flags().Bool("rm", false, "Remove the named project template")
+	configCmd.AddCommand(configTemplateCmd)
+}
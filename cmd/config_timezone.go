@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/dateparse"
+	"github.com/marcus/td/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var configTimezoneCmd = &cobra.Command{
+	Use:   "timezone [name]",
+	Short: "Show or set the project's timezone",
+	Long: `Show the timezone that decides which day it is for the project, or set
+it to an IANA name such as Europe/Berlin. It is used for due dates
+(overdue, due soon), TDQ date keywords like today and this_week, relative
+dates like +3d, and the offsets on td serve timestamps.
+
+Without one, td uses each machine's own timezone, so teammates in different
+timezones can disagree about whether an issue is overdue. TD_TIMEZONE
+overrides the project setting for one process.`,
+	Example: `  td config timezone
+  td config timezone America/New_York
+  td config timezone --reset`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		baseDir := getBaseDir()
+
+		if reset, _ := cmd.Flags().GetBool("reset"); reset {
+			if err := config.SetTimezone(baseDir, ""); err != nil {
+				output.Error("%v", err)
+				return err
+			}
+			output.Success("Timezone reset to the machine's (%s)", time.Local)
+			return nil
+		}
+
+		if len(args) == 0 {
+			loc, err := config.GetTimezone(baseDir)
+			if err != nil {
+				output.Warning("%v, using the machine's timezone", err)
+			}
+			if env := strings.TrimSpace(os.Getenv("TD_TIMEZONE")); env != "" {
+				fmt.Printf("%s (TD_TIMEZONE)\n", env)
+				return nil
+			}
+			fmt.Println(loc)
+			return nil
+		}
+
+		if err := config.SetTimezone(baseDir, args[0]); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		output.Success("Timezone set: %s", args[0])
+		return nil
+	},
+}
+
+// useTimezone makes the project's timezone, or TD_TIMEZONE, the one dates
+// are read in for this process. An unknown name falls back to the
+// machine's timezone with a warning rather than failing the command.
+func useTimezone(baseDir string) {
+	if name := strings.TrimSpace(os.Getenv("TD_TIMEZONE")); name != "" {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			slog.Warn("timezone", "env", "TD_TIMEZONE", "err", err)
+			loc = nil
+		}
+		dateparse.Use(loc)
+		return
+	}
+	if baseDir == "" {
+		dateparse.Use(nil)
+		return
+	}
+	loc, err := config.GetTimezone(baseDir)
+	if err != nil {
+		slog.Warn("timezone", "err", err)
+	}
+	dateparse.Use(loc)
+}
+
+func init() {
+	configTimezoneCmd.Flags().Bool("reset", false, "Go back to the machine's timezone")
+	configCmd.AddCommand(configTimezoneCmd)
+}
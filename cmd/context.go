@@ -65,9 +65,13 @@ var usageCmd = &cobra.Command{
 		// Get focused issue
 		focusedID, _ := config.GetFocus(baseDir)
 		var focusedIssue *models.Issue
+		var focusedDecisions []models.Decision
 		if focusedID != "" {
 			focusedIssue, _ = database.GetIssue(focusedID)
 		}
+		if focusedIssue != nil {
+			focusedDecisions, _ = database.ListDecisions(db.DecisionFilter{IssueID: focusedIssue.ID})
+		}
 
 		// Get active work session
 		wsID, _ := config.GetActiveWorkSession(baseDir)
@@ -100,6 +104,7 @@ var usageCmd = &cobra.Command{
 			result := map[string]interface{}{
 				"session":      sess.ID,
 				"focused":      focusedIssue,
+				"decisions":    focusedDecisions,
 				"work_session": activeWS,
 				"ws_issues":    wsIssues,
 				"in_progress":  inProgress,
@@ -147,6 +152,13 @@ var usageCmd = &cobra.Command{
 				}
 			}
 
+			if len(focusedDecisions) > 0 {
+				fmt.Println("  Decisions:")
+				for _, d := range focusedDecisions {
+					fmt.Printf("    %s: %s\n", d.Title, d.Decision)
+				}
+			}
+
 			files, _ := database.GetLinkedFiles(focusedID)
 			if len(files) > 0 {
 				fmt.Printf("  Files: ")
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/marcus/td/internal/crash"
+	"github.com/marcus/td/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var crashCmd = &cobra.Command{
+	Use:   "crash",
+	Short: "List and share crash reports",
+	Long: `When td serve, td monitor or a command panics, td recovers: serve answers
+the request with a 500 and keeps running, the monitor keeps its screen and
+shows the error in the status bar, and a command exits with status 2. Each
+panic is saved with its stack trace under .todos/crashes (the newest 50).
+
+Crash dumps stay on this machine. td crash report prints one with project
+paths, user names, request paths and IDs taken out, for attaching to a bug
+report; nothing is sent anywhere.
+
+Set crash_dumps to false in ~/.config/td/config.json, or TD_CRASH_DUMPS=0,
+to stop saving dumps; panics are still recovered and logged.`,
+	GroupID: "system",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return crashListCmd.RunE(crashListCmd, args)
+	},
+}
+
+var crashListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved crash reports, newest first",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		reports, err := crash.List(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			for i := range reports {
+				reports[i].Stack = ""
+			}
+			data, _ := json.MarshalIndent(reports, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+		if len(reports) == 0 {
+			output.Info("No crash reports")
+			return nil
+		}
+		for _, r := range reports {
+			fmt.Printf("%s  %s  %-7s  %s\n", r.ID, r.Time.Local().Format("2006-01-02 15:04"), r.Component, firstLine(r.Panic))
+		}
+		return nil
+	},
+}
+
+var crashShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Show a crash report with its stack trace",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		r, err := crash.Load(getBaseDir(), args[0])
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			data, _ := json.MarshalIndent(r, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+		fmt.Printf("ID:        %s\n", r.ID)
+		fmt.Printf("Time:      %s\n", r.Time.Local().Format("2006-01-02 15:04:05"))
+		fmt.Printf("Component: %s\n", r.Component)
+		fmt.Printf("Version:   td %s, %s %s/%s\n", r.Version, r.GoVersion, r.OS, r.Arch)
+		keys := make([]string, 0, len(r.Context))
+		for k := range r.Context {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Printf("%-10s %s\n", k+":", r.Context[k])
+		}
+		fmt.Printf("Panic:     %s\n\n%s", r.Panic, r.Stack)
+		return nil
+	},
+}
+
+var crashReportCmd = &cobra.Command{
+	Use:   "report <id>",
+	Short: "Print an anonymized crash report to share",
+	Long: `Print a crash report as JSON with the project directory, home directory and
+user name replaced, and request paths, IDs and the exact time left out.
+Read it before sharing it: a panic message can still quote data, such as
+an issue title.`,
+	Example: `  td crash report cr-20260101-120000-a1b2c3
+  td crash report cr-20260101 -o crash.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		baseDir := getBaseDir()
+		r, err := crash.Load(baseDir, args[0])
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		data, _ := json.MarshalIndent(r.Anonymize(baseDir), "", "  ")
+
+		path, _ := cmd.Flags().GetString("output")
+		if path == "" {
+			fmt.Println(string(data))
+			return nil
+		}
+		if err := os.WriteFile(path, append(data, '\n'), 0600); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		output.Success("Wrote anonymized report %s to %s", r.ID, path)
+		return nil
+	},
+}
+
+var crashClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Delete all saved crash reports",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		n, err := crash.Clear(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		output.Success("Deleted %d crash reports", n)
+		return nil
+	},
+}
+
+// firstLine returns s up to its first newline
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+func init() {
+	crashListCmd.Flags().Bool("json", false, "Output as JSON, without stack traces")
+	crashShowCmd.Flags().Bool("json", false, "Output as JSON")
+	crashReportCmd.Flags().StringP("output", "o", "", "Write the report to a file instead of stdout")
+	crashCmd.AddCommand(crashListCmd, crashShowCmd, crashReportCmd, crashClearCmd)
+	rootCmd.AddCommand(crashCmd)
+}
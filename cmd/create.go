@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"unicode/utf8"
 
@@ -9,9 +11,13 @@ import (
 	"github.com/marcus/td/internal/dateparse"
 	"github.com/marcus/td/internal/db"
 	"github.com/marcus/td/internal/git"
+	"github.com/marcus/td/internal/i18n"
 	"github.com/marcus/td/internal/models"
 	"github.com/marcus/td/internal/output"
+	"github.com/marcus/td/internal/quickadd"
 	"github.com/marcus/td/internal/session"
+	"github.com/marcus/td/internal/triage"
+	"github.com/marcus/td/internal/xref"
 	"github.com/spf13/cobra"
 )
 
@@ -19,7 +25,22 @@ var createCmd = &cobra.Command{
 	Use:     "create [title]",
 	Aliases: []string{"add", "new"},
 	Short:   "Create a new issue",
-	Long:    `Create a new issue with optional flags for type, priority, labels, and more.`,
+	Long: `Create a new issue with optional flags for type, priority, labels, and more.
+
+Called as td add, the title may carry inline tokens instead of flags:
+
+  #bug #feature #task #epic #chore   type
+  !p0 .. !p4 (or !high, !low)        priority
+  @sprint-7                          sprint
+  +auth +backend                     labels
+  due:friday (any --due format)      due date
+  3pts                               story points
+
+Flags override tokens. Look-alikes such as "#123" stay in the title.
+
+Examples:
+  td create "Fix login timeout" --type bug --priority P1
+  td add "Fix login timeout #bug !p1 @sprint-7 +auth +backend due:friday 3pts"`,
 	GroupID: "core",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Route "td new task Title" → td create --type task "Title"
@@ -51,6 +72,22 @@ var createCmd = &cobra.Command{
 			title = args[0]
 		}
 
+		// td add reads inline tokens from the whole line
+		var quick quickadd.Fields
+		if cmd.CalledAs() == "add" {
+			if len(args) > 0 {
+				title = strings.Join(args, " ")
+			}
+			if title != "" {
+				quick, err = quickadd.Parse(title)
+				if err != nil {
+					output.Error("%v", err)
+					return err
+				}
+				title = quick.Title
+			}
+		}
+
 		if title == "" {
 			output.Error("title is required")
 			return fmt.Errorf("title is required")
@@ -72,7 +109,15 @@ var createCmd = &cobra.Command{
 
 		// Build issue
 		issue := &models.Issue{
-			Title: title,
+			Title:    title,
+			Type:     quick.Type,
+			Priority: quick.Priority,
+			Points:   quick.Points,
+			Labels:   quick.Labels,
+			Sprint:   quick.Sprint,
+		}
+		if quick.DueDate != "" {
+			issue.DueDate = &quick.DueDate
 		}
 
 		// Apply extracted type if no explicit --type
@@ -125,9 +170,10 @@ var createCmd = &cobra.Command{
 			}
 		}
 		if labelsStr != "" {
-			issue.Labels = strings.Split(labelsStr, ",")
-			for i := range issue.Labels {
-				issue.Labels[i] = strings.TrimSpace(issue.Labels[i])
+			for _, label := range strings.Split(labelsStr, ",") {
+				if label = strings.TrimSpace(label); !slices.Contains(issue.Labels, label) {
+					issue.Labels = append(issue.Labels, label)
+				}
 			}
 		}
 
@@ -159,10 +205,27 @@ var createCmd = &cobra.Command{
 				issue.ParentID = epic
 			}
 		}
+		if xref.IsQualified(issue.ParentID) {
+			output.Error("parent %s is in another project; use td dep add for cross-project links", issue.ParentID)
+			return fmt.Errorf("cross-project parent: %s", issue.ParentID)
+		}
 
 		// Minor (allows self-review)
 		issue.Minor, _ = cmd.Flags().GetBool("minor")
 
+		// Triage inbox, explicitly or by project policy
+		issue.Inbox, _ = cmd.Flags().GetBool("inbox")
+		issue.Inbox = issue.Inbox || triage.Routes(baseDir, triage.SourceCLI)
+
+		// Confidential: description, acceptance and comments are encrypted
+		issue.Confidential, _ = cmd.Flags().GetBool("confidential")
+		if issue.Confidential {
+			if err := ensureConfidentialKey(baseDir); err != nil {
+				output.Error("%v", err)
+				return err
+			}
+		}
+
 		// Defer date
 		if deferStr, _ := cmd.Flags().GetString("defer"); deferStr != "" {
 			parsed, err := dateparse.ParseDate(deferStr)
@@ -195,6 +258,7 @@ var createCmd = &cobra.Command{
 		gitState, _ := git.GetState()
 		if gitState != nil {
 			issue.CreatedBranch = gitState.Branch
+			issue.CreatedRepo = gitState.Repo
 		}
 
 		// Create the issue (atomic create + action log)
@@ -227,6 +291,10 @@ var createCmd = &cobra.Command{
 			}
 		}
 
+		if issue.Inbox {
+			fmt.Printf("CREATED %s (inbox)\n", issue.ID)
+			return nil
+		}
 		fmt.Printf("CREATED %s\n", issue.ID)
 		return nil
 	},
@@ -253,6 +321,8 @@ func init() {
 	createCmd.Flags().String("depends-on", "", "Issues this depends on")
 	createCmd.Flags().String("blocks", "", "Issues this blocks")
 	createCmd.Flags().Bool("minor", false, "Mark as minor task (allows self-review)")
+	createCmd.Flags().Bool("inbox", false, "Put in the triage inbox (see td inbox)")
+	createCmd.Flags().Bool("confidential", false, "Encrypt the description, acceptance and comments (see td confidential)")
 	createCmd.Flags().String("defer", "", "Defer until date (e.g., +7d, monday, 2026-03-01)")
 	createCmd.Flags().String("due", "", "Due date (e.g., friday, +2w, 2026-03-15)")
 }
@@ -291,7 +361,7 @@ func validateTitle(title string, minLength, maxLength int) error {
 	// Check for exact match with generic titles
 	for _, generic := range genericTitles {
 		if lower == generic {
-			return fmt.Errorf("title '%s' is too generic - describe what it does or fixes", title)
+			return errors.New(i18n.T("title '%s' is too generic - describe what it does or fixes", title))
 		}
 	}
 
@@ -299,10 +369,10 @@ func validateTitle(title string, minLength, maxLength int) error {
 	// Use trimmed length to prevent whitespace padding exploit
 	runeCount := utf8.RuneCountInString(trimmed)
 	if runeCount < minLength {
-		return fmt.Errorf("title too short (%d chars, need %d) - e.g. 'Fix login timeout' not 'Fix bug'", runeCount, minLength)
+		return errors.New(i18n.T("title too short (%d chars, need %d) - e.g. 'Fix login timeout' not 'Fix bug'", runeCount, minLength))
 	}
 	if runeCount > maxLength {
-		return fmt.Errorf("title too long (%d chars, max %d) - move details to description", runeCount, maxLength)
+		return errors.New(i18n.T("title too long (%d chars, max %d) - move details to description", runeCount, maxLength))
 	}
 
 	return nil
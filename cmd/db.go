@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/marcus/td/internal/dateparse"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/output"
+	"github.com/marcus/td/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var dbCmd = &cobra.Command{
+	Use:     "db",
+	Short:   "Database maintenance",
+	GroupID: "system",
+}
+
+var dbRebuildProjectionsCmd = &cobra.Command{
+	Use:   "rebuild-projections",
+	Short: "Rebuild issue rows from the action log",
+	Long: `The action log is the source of truth for issue state; the issues table
+is a projection of it. rebuild-projections replays the log and rewrites
+any issue row that has drifted from it. Issues with no logged history
+are left untouched.
+
+With --as-of the project is restored to that point in time instead.
+The restore is recorded as new events, so it syncs and can be undone.
+A bare date means the end of that day.
+
+Projects that have pulled from a sync server hold changes that are not
+in the local log; rebuilding them requires --force.
+
+Issue cards, the per-issue summaries list views read, are recomputed from
+the tables afterwards.`,
+	Example: `  td db rebuild-projections --dry-run
+  td db rebuild-projections
+  td db rebuild-projections --as-of 2026-03-01`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		force, _ := cmd.Flags().GetBool("force")
+		asOfStr, _ := cmd.Flags().GetString("as-of")
+
+		var asOf time.Time
+		if asOfStr != "" {
+			var err error
+			if asOf, err = parseAsOf(asOfStr); err != nil {
+				output.Error("%v", err)
+				return err
+			}
+		}
+
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		opts := db.RebuildOptions{AsOf: asOf, DryRun: dryRun, Force: force}
+		if !asOf.IsZero() && !dryRun {
+			sess, err := session.GetOrCreate(database)
+			if err != nil {
+				output.Error("%v", err)
+				return err
+			}
+			opts.SessionID = sess.ID
+		}
+
+		res, err := database.RebuildIssueProjection(opts)
+		if errors.Is(err, db.ErrRemoteHistory) {
+			output.Error("%v; rerun with --force to rebuild anyway", err)
+			return err
+		}
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		cards := 0
+		if !dryRun {
+			if cards, err = database.RebuildIssueCards(); err != nil {
+				output.Error("rebuild issue cards: %v", err)
+				return err
+			}
+		}
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			data, _ := json.MarshalIndent(struct {
+				*db.RebuildResult
+				Cards int `json:"cards"`
+			}{res, cards}, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		fmt.Printf("Replayed %d events for %d issues\n", res.Events, res.Projected)
+		for _, d := range res.Drift {
+			switch {
+			case d.Missing:
+				fmt.Printf("  %s  missing from issues table\n", d.IssueID)
+			case d.Removed:
+				fmt.Printf("  %s  created after %s\n", d.IssueID, asOf.Local().Format("2006-01-02 15:04"))
+			default:
+				fmt.Printf("  %s  %v\n", d.IssueID, d.Fields)
+			}
+		}
+		if len(res.Unlogged) > 0 {
+			output.Warning("%d issues have no logged history and were left as-is", len(res.Unlogged))
+		}
+		switch {
+		case len(res.Drift) == 0:
+			output.Success("Issues table matches the action log")
+		case dryRun:
+			output.Info("Dry run: %d issues would be rewritten", len(res.Drift))
+		default:
+			output.Success("Rewrote %d issues", res.Written)
+		}
+		if !dryRun {
+			output.Success("Rebuilt %d issue cards", cards)
+		}
+		return nil
+	},
+}
+
+// parseAsOf accepts an RFC3339 timestamp or any date td understands,
+// which is taken as the end of that day in local time.
+func parseAsOf(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	date, err := dateparse.ParseDate(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --as-of %q: %w", s, err)
+	}
+	day, err := time.ParseInLocation("2006-01-02", date, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --as-of %q: %w", s, err)
+	}
+	return day.Add(24*time.Hour - time.Nanosecond), nil
+}
+
+func init() {
+	dbRebuildProjectionsCmd.Flags().Bool("dry-run", false, "Report drift without writing")
+	dbRebuildProjectionsCmd.Flags().String("as-of", "", "Restore issues to their state at this time (RFC3339 or date)")
+	dbRebuildProjectionsCmd.Flags().Bool("force", false, "Rebuild even if the project has pulled remote changes")
+	dbRebuildProjectionsCmd.Flags().Bool("json", false, "Output as JSON")
+
+	dbCmd.AddCommand(dbRebuildProjectionsCmd)
+	rootCmd.AddCommand(dbCmd)
+}
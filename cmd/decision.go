@@ -0,0 +1,301 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/output"
+	"github.com/marcus/td/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var decisionCmd = &cobra.Command{
+	Use:     "decision",
+	Aliases: []string{"decisions"},
+	Short:   "Record and browse project decisions",
+	Long: `Keep a log of decisions: the context that called for one, the options
+considered and what was decided, linked to the issues it affects. Linked
+decisions appear in td show / td context for those issues.`,
+	GroupID: "core",
+}
+
+var decisionAddCmd = &cobra.Command{
+	Use:   "add <title>",
+	Short: "Record a decision",
+	Long: `Record a decision.
+
+Examples:
+  td decision add "Queue backend" --decision "postgres, we already run it" \
+    --context "workers need durable jobs" --option redis --option postgres --issue td-a1b2
+  td decision add "Drop Node 18" -d "support ends in April" --issue td-a1b2 --issue td-c3d4`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		decisionText, _ := cmd.Flags().GetString("decision")
+		if strings.TrimSpace(decisionText) == "" {
+			err := fmt.Errorf("--decision is required")
+			output.Error("%v", err)
+			return err
+		}
+
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		issueIDs, err := resolveDecisionIssues(cmd, database)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		sess, err := session.GetOrCreate(database)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		contextText, _ := cmd.Flags().GetString("context")
+		options, _ := cmd.Flags().GetStringArray("option")
+		decision := &models.Decision{
+			Title:     strings.TrimSpace(args[0]),
+			Context:   strings.TrimSpace(contextText),
+			Options:   options,
+			Decision:  strings.TrimSpace(decisionText),
+			IssueIDs:  issueIDs,
+			SessionID: sess.ID,
+		}
+		if err := database.CreateDecision(decision); err != nil {
+			output.Error("failed to record decision: %v", err)
+			return err
+		}
+
+		fmt.Printf("RECORDED %s %s\n", decision.ID, decision.Title)
+		return nil
+	},
+}
+
+var decisionListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List decisions, newest first",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		filter := db.DecisionFilter{}
+		filter.Search, _ = cmd.Flags().GetString("search")
+		filter.Limit, _ = cmd.Flags().GetInt("limit")
+		if issueID, _ := cmd.Flags().GetString("issue"); issueID != "" {
+			filter.IssueID = db.NormalizeIssueID(issueID)
+		}
+		decisions, err := database.ListDecisions(filter)
+		if err != nil {
+			output.Error("failed to list decisions: %v", err)
+			return err
+		}
+
+		if jsonOut, _ := cmd.Flags().GetBool("json"); jsonOut {
+			if decisions == nil {
+				decisions = []models.Decision{}
+			}
+			return output.JSON(decisions)
+		}
+
+		if len(decisions) == 0 {
+			output.Info("No decisions")
+			return nil
+		}
+		for _, d := range decisions {
+			title := d.Title
+			if len(title) > 50 {
+				title = title[:47] + "..."
+			}
+			fmt.Printf("%s  %-50s  %-12s  %s\n", d.ID, title, output.FormatTimeAgo(d.CreatedAt), strings.Join(d.IssueIDs, ", "))
+		}
+		return nil
+	},
+}
+
+var decisionShowCmd = &cobra.Command{
+	Use:   "show <decision-id>",
+	Short: "Display a decision",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		decision, err := database.GetDecision(args[0])
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		if jsonOut, _ := cmd.Flags().GetBool("json"); jsonOut {
+			return output.JSON(decision)
+		}
+
+		fmt.Printf("%s: %s\n", decision.ID, decision.Title)
+		fmt.Printf("Recorded: %s by %s\n", output.FormatTimeAgo(decision.CreatedAt), decision.SessionID)
+		if decision.Context != "" {
+			fmt.Print(output.SectionHeader("Context"))
+			fmt.Printf("  %s\n", decision.Context)
+		}
+		if len(decision.Options) > 0 {
+			fmt.Print(output.SectionHeader("Options Considered"))
+			for _, opt := range decision.Options {
+				fmt.Printf("  - %s\n", opt)
+			}
+		}
+		fmt.Print(output.SectionHeader("Decision"))
+		fmt.Printf("  %s\n", decision.Decision)
+		if len(decision.IssueIDs) > 0 {
+			fmt.Print(output.SectionHeader("Issues"))
+			for _, id := range decision.IssueIDs {
+				if issue, err := database.GetIssue(id); err == nil {
+					fmt.Printf("  %s\n", output.IssueOneLiner(issue))
+				} else {
+					fmt.Printf("  %s\n", id)
+				}
+			}
+		}
+		return nil
+	},
+}
+
+var decisionUpdateCmd = &cobra.Command{
+	Use:   "update <decision-id>",
+	Short: "Update a decision",
+	Long: `Update the fields of a decision given as flags. --option and --issue
+replace the existing options and issue links.
+
+Examples:
+  td decision update dc-1a2b3c4d --decision "redis streams after all"
+  td decision update dc-1a2b3c4d --issue td-a1b2 --issue td-e5f6`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		decision, err := database.GetDecision(args[0])
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		if cmd.Flags().Changed("title") {
+			title, _ := cmd.Flags().GetString("title")
+			decision.Title = strings.TrimSpace(title)
+		}
+		if cmd.Flags().Changed("decision") {
+			text, _ := cmd.Flags().GetString("decision")
+			decision.Decision = strings.TrimSpace(text)
+		}
+		if cmd.Flags().Changed("context") {
+			text, _ := cmd.Flags().GetString("context")
+			decision.Context = strings.TrimSpace(text)
+		}
+		if cmd.Flags().Changed("option") {
+			decision.Options, _ = cmd.Flags().GetStringArray("option")
+		}
+		if cmd.Flags().Changed("issue") {
+			if decision.IssueIDs, err = resolveDecisionIssues(cmd, database); err != nil {
+				output.Error("%v", err)
+				return err
+			}
+		}
+		if decision.Title == "" || decision.Decision == "" {
+			err := fmt.Errorf("title and decision cannot be empty")
+			output.Error("%v", err)
+			return err
+		}
+
+		if err := database.UpdateDecision(decision); err != nil {
+			output.Error("failed to update decision: %v", err)
+			return err
+		}
+		fmt.Printf("UPDATED %s\n", decision.ID)
+		return nil
+	},
+}
+
+var decisionDeleteCmd = &cobra.Command{
+	Use:   "delete <decision-id>",
+	Short: "Delete a decision",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		if err := database.DeleteDecision(args[0]); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		fmt.Printf("DELETED %s\n", args[0])
+		return nil
+	},
+}
+
+// resolveDecisionIssues normalizes the --issue flags and checks each issue exists
+func resolveDecisionIssues(cmd *cobra.Command, database *db.DB) ([]string, error) {
+	raw, _ := cmd.Flags().GetStringArray("issue")
+	var ids []string
+	for _, r := range raw {
+		for _, id := range strings.Split(r, ",") {
+			if id = strings.TrimSpace(id); id == "" {
+				continue
+			}
+			issue, err := database.GetIssue(id)
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, issue.ID)
+		}
+	}
+	return ids, nil
+}
+
+func init() {
+	for _, c := range []*cobra.Command{decisionAddCmd, decisionUpdateCmd} {
+		c.Flags().StringP("decision", "d", "", "What was decided")
+		c.Flags().String("context", "", "Why a decision was needed")
+		c.Flags().StringArray("option", nil, "Option considered (repeatable)")
+		c.Flags().StringArray("issue", nil, "Linked issue (repeatable or comma-separated)")
+	}
+	decisionUpdateCmd.Flags().String("title", "", "New title")
+
+	decisionListCmd.Flags().String("issue", "", "Only decisions linked to this issue")
+	decisionListCmd.Flags().String("search", "", "Search title, context and decision")
+	decisionListCmd.Flags().Int("limit", 0, "Maximum decisions to show")
+	decisionListCmd.Flags().Bool("json", false, "Output as JSON")
+	decisionShowCmd.Flags().Bool("json", false, "Output as JSON")
+
+	decisionCmd.AddCommand(decisionAddCmd, decisionListCmd, decisionShowCmd, decisionUpdateCmd, decisionDeleteCmd)
+	rootCmd.AddCommand(decisionCmd)
+}
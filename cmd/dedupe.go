@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/dedupe"
+	"github.com/marcus/td/internal/output"
+	"github.com/marcus/td/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var dedupeCmd = &cobra.Command{
+	Use:   "dedupe",
+	Short: "Find and merge duplicate issues",
+	Long: `Scans open issues for likely duplicates by the words their titles and
+descriptions share, and merges a duplicate cluster into one issue. td serve
+rebuilds the same report periodically at /v1/reports/duplicates.`,
+	GroupID: "query",
+}
+
+var dedupeReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "List clusters of likely-duplicate open issues",
+	Long: `Lists clusters of open issues whose similarity (0 to 1) reaches
+--threshold, most similar first. The oldest issue in a cluster is suggested
+as the one to keep.`,
+	Example: `  td dedupe report
+  td dedupe report --threshold 0.8 --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		threshold, _ := cmd.Flags().GetFloat64("threshold")
+		if threshold <= 0 || threshold > 1 {
+			err := fmt.Errorf("--threshold must be above 0 and at most 1")
+			output.Error("%v", err)
+			return err
+		}
+
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		report, err := dedupe.Compute(database, threshold, time.Now())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			data, _ := json.MarshalIndent(report, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+		renderDuplicates(report)
+		return nil
+	},
+}
+
+var dedupeMergeCmd = &cobra.Command{
+	Use:   "merge [keep-id dup-id...]",
+	Short: "Merge duplicates into one issue",
+	Long: `Moves the duplicates' labels, children and dependencies to the kept
+issue, closes each duplicate with a "duplicate of" log, and comments on the
+kept issue. With --cluster N, merges cluster N of the current report into
+its suggested issue.`,
+	Example: `  td dedupe merge td-a1b2 td-c3d4 td-e5f6
+  td dedupe merge --cluster 1`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		cluster, _ := cmd.Flags().GetInt("cluster")
+		if (cluster == 0 && len(args) < 2) || (cluster != 0 && len(args) > 0) {
+			err := fmt.Errorf("give a keep ID and at least one duplicate, or --cluster N")
+			output.Error("%v", err)
+			return err
+		}
+
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		sess, err := session.GetOrCreate(database)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		var keepID string
+		var dupIDs []string
+		if cluster == 0 {
+			keepID, dupIDs = args[0], args[1:]
+		} else {
+			threshold, _ := cmd.Flags().GetFloat64("threshold")
+			report, err := dedupe.Compute(database, threshold, time.Now())
+			if err != nil {
+				output.Error("%v", err)
+				return err
+			}
+			if cluster < 1 || cluster > len(report.Clusters) {
+				err := fmt.Errorf("no cluster %d: the report has %d", cluster, len(report.Clusters))
+				output.Error("%v", err)
+				return err
+			}
+			c := report.Clusters[cluster-1]
+			keepID = c.Keep
+			for _, m := range c.Issues[1:] {
+				dupIDs = append(dupIDs, m.ID)
+			}
+		}
+
+		result, err := dedupe.Merge(database, keepID, dupIDs, sess.ID)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		fmt.Printf("MERGED %s into %s\n", strings.Join(result.Closed, ", "), result.Keep)
+		if len(result.Labels) > 0 {
+			fmt.Printf("  labels added: %s\n", strings.Join(result.Labels, ", "))
+		}
+		if len(result.Children) > 0 {
+			fmt.Printf("  children moved: %s\n", strings.Join(result.Children, ", "))
+		}
+		if result.Dependencies > 0 {
+			fmt.Printf("  dependencies moved: %d\n", result.Dependencies)
+		}
+		return nil
+	},
+}
+
+// renderDuplicates prints a duplicate report
+func renderDuplicates(r *dedupe.Report) {
+	if len(r.Clusters) == 0 {
+		fmt.Printf("No duplicates among %d open issues (threshold %.2f)\n", r.Scanned, r.Threshold)
+		return
+	}
+	noun := "clusters"
+	if len(r.Clusters) == 1 {
+		noun = "cluster"
+	}
+	fmt.Printf("%d %s among %d open issues (threshold %.2f)\n", len(r.Clusters), noun, r.Scanned, r.Threshold)
+	for i, c := range r.Clusters {
+		fmt.Print(output.SectionHeader(fmt.Sprintf("Cluster %d (%.2f)", i+1, c.Score)))
+		for _, m := range c.Issues {
+			mark := " "
+			if m.ID == c.Keep {
+				mark = "*"
+			}
+			fmt.Printf("  %s %s  [%s]  %s\n", mark, m.ID, m.Status, m.Title)
+		}
+	}
+	fmt.Println()
+	fmt.Println("* suggested to keep; merge with: td dedupe merge --cluster N")
+}
+
+func init() {
+	dedupeReportCmd.Flags().Float64("threshold", dedupe.DefaultThreshold, "Minimum similarity (0 to 1)")
+	dedupeReportCmd.Flags().Bool("json", false, "Output as JSON")
+	dedupeMergeCmd.Flags().Int("cluster", 0, "Merge this cluster of the report (1-based)")
+	dedupeMergeCmd.Flags().Float64("threshold", dedupe.DefaultThreshold, "Threshold for the report --cluster refers to")
+	dedupeCmd.AddCommand(dedupeReportCmd, dedupeMergeCmd)
+	rootCmd.AddCommand(dedupeCmd)
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/marcus/td/internal/db"
@@ -26,13 +27,29 @@ var deleteCmd = &cobra.Command{
 
 		sess, _ := session.GetOrCreate(database)
 
+		// --force and --yes are what agents reach for to push a delete through
+		cascade, _ := cmd.Flags().GetBool("cascade")
+		force, _ := cmd.Flags().GetBool("force")
+		yes, _ := cmd.Flags().GetBool("yes")
+		cascade = cascade || force || yes
+
 		for _, issueID := range args {
-			if err := database.DeleteIssueLogged(issueID, sess.ID); err != nil {
-				output.Error("failed to delete %s: %v", issueID, err)
+			refs, err := database.DeleteIssueRefsLogged(issueID, sess.ID, cascade)
+			if err != nil {
+				var refErr *db.ReferencesError
+				if errors.As(err, &refErr) {
+					output.Error("%v", err)
+					fmt.Println("  Use --cascade to detach children and remove its dependencies, board positions and focus")
+				} else {
+					output.Error("failed to delete %s: %v", issueID, err)
+				}
 				continue
 			}
 
 			fmt.Printf("DELETED %s\n", issueID)
+			if !refs.Empty() {
+				fmt.Printf("  cleaned up %s\n", refs)
+			}
 		}
 
 		return nil
@@ -73,7 +90,8 @@ func init() {
 	rootCmd.AddCommand(deleteCmd)
 	rootCmd.AddCommand(restoreCmd)
 
-	// Accept --force and --yes as no-ops for LLM compatibility
-	deleteCmd.Flags().BoolP("force", "f", false, "No-op (delete always succeeds)")
-	deleteCmd.Flags().BoolP("yes", "y", false, "No-op (delete always succeeds, alias for --force)")
+	deleteCmd.Flags().Bool("cascade", false, "Also detach children and remove dependencies, board positions and focus referencing the issue")
+	// Accept --force and --yes for LLM compatibility
+	deleteCmd.Flags().BoolP("force", "f", false, "Alias for --cascade")
+	deleteCmd.Flags().BoolP("yes", "y", false, "Alias for --cascade")
 }
@@ -32,7 +32,7 @@ func TestDeleteSingleIssue(t *testing.T) {
 	}
 
 	// Verify issue is marked as deleted
-	retrieved, err := database.GetIssue(issueID)
+	retrieved, err := database.GetIssueWithDeleted(issueID)
 	if err != nil {
 		t.Fatalf("GetIssue failed: %v", err)
 	}
@@ -71,7 +71,7 @@ func TestDeleteMultipleIssues(t *testing.T) {
 
 	// Verify all deleted
 	for _, id := range issueIDs {
-		retrieved, _ := database.GetIssue(id)
+		retrieved, _ := database.GetIssueWithDeleted(id)
 		if retrieved.DeletedAt == nil {
 			t.Errorf("Issue %s should be deleted", id)
 		}
@@ -175,7 +175,7 @@ func TestDeleteFromDifferentStatuses(t *testing.T) {
 				t.Errorf("Failed to delete from %s: %v", tc.initialStatus, err)
 			}
 
-			retrieved, _ := database.GetIssue(issue.ID)
+			retrieved, _ := database.GetIssueWithDeleted(issue.ID)
 			if retrieved.DeletedAt == nil {
 				t.Errorf("Issue from %s should be deleted", tc.initialStatus)
 			}
@@ -207,7 +207,7 @@ func TestDeleteAlreadyDeletedIssue(t *testing.T) {
 		t.Logf("Second delete returned: %v", err)
 	}
 
-	retrieved, _ := database.GetIssue(issue.ID)
+	retrieved, _ := database.GetIssueWithDeleted(issue.ID)
 	if retrieved.DeletedAt == nil {
 		t.Error("Expected issue to remain deleted")
 	}
@@ -238,7 +238,7 @@ func TestDeleteWithDependencies(t *testing.T) {
 	}
 
 	// Parent should be deleted
-	parentRetrieved, _ := database.GetIssue(parent.ID)
+	parentRetrieved, _ := database.GetIssueWithDeleted(parent.ID)
 	if parentRetrieved.DeletedAt == nil {
 		t.Error("Parent should be deleted")
 	}
@@ -278,7 +278,7 @@ func TestDeleteUpdatesTimestamp(t *testing.T) {
 	// Delete
 	database.DeleteIssue(issue.ID)
 
-	retrieved, _ := database.GetIssue(issue.ID)
+	retrieved, _ := database.GetIssueWithDeleted(issue.ID)
 	if retrieved.DeletedAt == nil {
 		t.Error("DeletedAt should be set after delete")
 	}
@@ -310,7 +310,7 @@ func TestDeletePreservesIssueData(t *testing.T) {
 	database.DeleteIssue(issueID)
 
 	// Retrieve and verify data is preserved
-	retrieved, _ := database.GetIssue(issueID)
+	retrieved, _ := database.GetIssueWithDeleted(issueID)
 	if retrieved.Title != issue.Title {
 		t.Errorf("Title changed: %s -> %s", issue.Title, retrieved.Title)
 	}
@@ -365,7 +365,7 @@ func TestDeleteMultipleWithMixedStatuses(t *testing.T) {
 
 	// Verify all deleted
 	for _, id := range issueIDs {
-		retrieved, _ := database.GetIssue(id)
+		retrieved, _ := database.GetIssueWithDeleted(id)
 		if retrieved.DeletedAt == nil {
 			t.Errorf("Issue %s should be deleted", id)
 		}
@@ -394,7 +394,7 @@ func TestDeletePartialFailure(t *testing.T) {
 	err2 := database.DeleteIssue("td-nonexistent")
 
 	// Verify first deletion succeeded
-	retrieved, _ := database.GetIssue(issue1.ID)
+	retrieved, _ := database.GetIssueWithDeleted(issue1.ID)
 	if retrieved.DeletedAt == nil {
 		t.Error("First deletion should have succeeded")
 	}
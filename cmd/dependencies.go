@@ -1,15 +1,18 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
 
 	"github.com/marcus/td/internal/db"
 	"github.com/marcus/td/internal/dependency"
+	"github.com/marcus/td/internal/external"
 	"github.com/marcus/td/internal/models"
 	"github.com/marcus/td/internal/output"
 	"github.com/marcus/td/internal/session"
+	"github.com/marcus/td/internal/xref"
 	"github.com/spf13/cobra"
 )
 
@@ -435,6 +438,13 @@ Usage:
   td dep rm <issue> <depends-on>    Remove a dependency
   td dep <issue>                    Show what issue depends on
   td dep <issue> --blocking         Show what depends on issue
+  td dep poll                       Check external dependencies now
+
+A dependency can also be a URL outside td: a GitHub pull request (met when
+merged), a GitHub Actions run (met when it succeeds) or any other URL (met
+when it answers 2xx). The issue is blocked until td serve's poller, or
+td dep poll, sees every external condition met and its issue dependencies
+closed. GITHUB_TOKEN or GH_TOKEN is used for private repositories.
 
 Backward compatible:
   td dep <issue> <depends-on>       Same as 'td dep add'
@@ -443,7 +453,8 @@ Examples:
   td dep add td-abc td-xyz    # td-abc now depends on td-xyz
   td dep rm td-abc td-xyz     # remove that dependency
   td dep td-abc               # show what td-abc depends on
-  td dep td-abc --blocking    # show what depends on td-abc`,
+  td dep td-abc --blocking    # show what depends on td-abc
+  td dep add td-abc https://github.com/acme/lib/pull/42`,
 	GroupID: "workflow",
 	Args:    cobra.RangeArgs(1, 2),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -547,7 +558,7 @@ var depAddCmd = &cobra.Command{
 }
 
 var depRmCmd = &cobra.Command{
-	Use:     "rm <issue> <depends-on>",
+	Use:     "rm <issue> <depends-on|url>",
 	Aliases: []string{"remove"},
 	Short:   "Remove a dependency",
 	Args:    cobra.ExactArgs(2),
@@ -576,6 +587,22 @@ var depRmCmd = &cobra.Command{
 			return err
 		}
 
+		if external.IsURL(dependsOnID) || strings.HasPrefix(dependsOnID, "ed-") {
+			removed, unblocked, err := database.RemoveExternalDependency(issue.ID, dependsOnID, sess.ID)
+			if err != nil {
+				output.Error("%v", err)
+				return err
+			}
+			fmt.Printf("REMOVED: %s no longer waits on %s\n", issue.ID, removed.URL)
+			if unblocked {
+				fmt.Printf("UNBLOCKED %s\n", issue.ID)
+			}
+			return nil
+		}
+		if xref.IsQualified(dependsOnID) {
+			return removeCrossProjectDependency(database, issue, dependsOnID, sess.ID)
+		}
+
 		depIssue, err := database.GetIssue(dependsOnID)
 		if err != nil {
 			output.Error("issue not found: %s", dependsOnID)
@@ -601,6 +628,13 @@ func addDependency(database *db.DB, issueID, dependsOnID, sessionID string) erro
 		return err
 	}
 
+	if external.IsURL(dependsOnID) {
+		return addExternalDependency(database, issue, dependsOnID, sessionID)
+	}
+	if xref.IsQualified(dependsOnID) {
+		return addCrossProjectDependency(database, issue, dependsOnID, sessionID)
+	}
+
 	depIssue, err := database.GetIssue(dependsOnID)
 	if err != nil {
 		output.Error("issue not found: %s", dependsOnID)
@@ -628,6 +662,107 @@ func addDependency(database *db.DB, issueID, dependsOnID, sessionID string) erro
 	return nil
 }
 
+// addCrossProjectDependency records a dependency on an issue in a linked
+// project. The issue has to exist there; cycle checks stop at the project
+// boundary.
+func addCrossProjectDependency(database *db.DB, issue *models.Issue, qualifiedID, sessionID string) error {
+	ref, ok := xref.Parse(qualifiedID)
+	if !ok {
+		err := fmt.Errorf("invalid issue reference: %s (want <project>/<issue-id>)", qualifiedID)
+		output.Error("%v", err)
+		return err
+	}
+
+	resolver, err := xref.NewResolver(getBaseDir())
+	if err != nil {
+		output.Error("%v", err)
+		return err
+	}
+	defer resolver.Close()
+
+	depIssue, err := resolver.Resolve(ref)
+	if err != nil {
+		output.Error("%v", err)
+		return err
+	}
+	ref.IssueID = depIssue.ID
+
+	deps, _ := database.GetDependencies(issue.ID)
+	for _, d := range deps {
+		if d == ref.String() {
+			output.Warning("%s already depends on %s", issue.ID, ref)
+			return nil
+		}
+	}
+
+	if err := database.AddDependencyLogged(issue.ID, ref.String(), "depends_on", sessionID); err != nil {
+		output.Error("failed to add dependency: %v", err)
+		return err
+	}
+
+	fmt.Printf("ADDED: %s depends on %s\n", issue.ID, ref)
+	fmt.Printf("  %s: %s\n", issue.ID, issue.Title)
+	fmt.Printf("  └── now depends on: %s: %s\n", ref, depIssue.Title)
+	return nil
+}
+
+// addExternalDependency makes an issue wait on a URL outside td, blocking
+// it unless it is already blocked or not yet started
+func addExternalDependency(database *db.DB, issue *models.Issue, rawURL, sessionID string) error {
+	target, err := external.Parse(rawURL)
+	if err != nil {
+		output.Error("%v", err)
+		return err
+	}
+	dep, blocked, err := database.AddExternalDependency(issue.ID, target.URL, target.Provider, sessionID)
+	if errors.Is(err, db.ErrExternalExists) {
+		output.Warning("%s already waits on %s", issue.ID, target.URL)
+		return nil
+	}
+	if err != nil {
+		output.Error("failed to add dependency: %v", err)
+		return err
+	}
+
+	fmt.Printf("ADDED: %s depends on %s (%s, %s)\n", issue.ID, dep.URL, dep.Provider, dep.ID)
+	if blocked {
+		fmt.Printf("BLOCKED %s until it resolves\n", issue.ID)
+	}
+	return nil
+}
+
+// removeCrossProjectDependency removes a dependency on a linked project's
+// issue. It works from the stored reference, so the other project doesn't
+// need to be reachable.
+func removeCrossProjectDependency(database *db.DB, issue *models.Issue, qualifiedID, sessionID string) error {
+	want, ok := xref.Parse(qualifiedID)
+	if !ok {
+		err := fmt.Errorf("invalid issue reference: %s (want <project>/<issue-id>)", qualifiedID)
+		output.Error("%v", err)
+		return err
+	}
+
+	deps, err := database.GetDependencies(issue.ID)
+	if err != nil {
+		output.Error("%v", err)
+		return err
+	}
+	for _, d := range deps {
+		if ref, ok := xref.Parse(d); ok && ref.Matches(want) {
+			if err := database.RemoveDependencyLogged(issue.ID, d, sessionID); err != nil {
+				output.Error("failed to remove dependency: %v", err)
+				return err
+			}
+			fmt.Printf("REMOVED: %s no longer depends on %s\n", issue.ID, d)
+			return nil
+		}
+	}
+
+	err = fmt.Errorf("%s does not depend on %s", issue.ID, qualifiedID)
+	output.Error("%v", err)
+	return err
+}
+
 // showDependencies shows what an issue depends on
 func showDependencies(database *db.DB, issue *models.Issue, jsonOutput bool) error {
 	deps, err := database.GetDependencies(issue.ID)
@@ -636,17 +771,27 @@ func showDependencies(database *db.DB, issue *models.Issue, jsonOutput bool) err
 		return err
 	}
 
+	externals, err := database.ListExternalDependencies(issue.ID)
+	if err != nil {
+		output.Error("failed to get external dependencies: %v", err)
+		return err
+	}
+
 	if jsonOutput {
+		if externals == nil {
+			externals = []models.ExternalDependency{}
+		}
 		result := map[string]interface{}{
 			"issue":        issue,
 			"dependencies": deps,
+			"external":     externals,
 		}
 		return output.JSON(result)
 	}
 
 	fmt.Println(output.IssueOneLiner(issue))
 
-	if len(deps) == 0 {
+	if len(deps) == 0 && len(externals) == 0 {
 		fmt.Println("No dependencies")
 		return nil
 	}
@@ -655,7 +800,32 @@ func showDependencies(database *db.DB, issue *models.Issue, jsonOutput bool) err
 	blocking := 0
 	resolved := 0
 
+	var resolver *xref.Resolver
 	for _, depID := range deps {
+		if ref, ok := xref.Parse(depID); ok {
+			if resolver == nil {
+				if resolver, err = xref.NewResolver(getBaseDir()); err != nil {
+					resolver = xref.NewResolverFor(nil)
+				}
+				defer resolver.Close()
+			}
+			dep, err := resolver.Resolve(ref)
+			if err != nil {
+				blocking++
+				fmt.Printf("    %s: (%v)\n", depID, err)
+				continue
+			}
+			if dep.Status == models.StatusClosed {
+				resolved++
+			} else {
+				blocking++
+			}
+			external := *dep
+			external.ID = depID
+			fmt.Println(output.DependencyLine(&external, true))
+			continue
+		}
+
 		dep, err := database.GetIssue(depID)
 		if err != nil {
 			continue
@@ -670,6 +840,24 @@ func showDependencies(database *db.DB, issue *models.Issue, jsonOutput bool) err
 		fmt.Println(output.DependencyLine(dep, true))
 	}
 
+	for _, ext := range externals {
+		if ext.State == models.ExternalSatisfied {
+			resolved++
+		} else {
+			blocking++
+		}
+		line := fmt.Sprintf("    %s %s [%s]", ext.ID, ext.URL, ext.State)
+		switch {
+		case ext.LastError != "":
+			line += " (check failed: " + ext.LastError + ")"
+		case ext.Detail != "":
+			line += " (" + ext.Detail + ")"
+		case ext.CheckedAt == nil:
+			line += " (not checked yet)"
+		}
+		fmt.Println(line)
+	}
+
 	fmt.Printf("\n%d blocking, %d resolved\n", blocking, resolved)
 	return nil
 }
@@ -710,6 +898,43 @@ func showBlocking(database *db.DB, issue *models.Issue, jsonOutput bool) error {
 	return nil
 }
 
+var depPollCmd = &cobra.Command{
+	Use:   "poll",
+	Short: "Check external dependencies now and unblock resolved issues",
+	Long: `Check every unsatisfied external dependency once, as td serve does on its
+--external-poll-interval, and unblock issues whose conditions are all met.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		sess, err := session.GetOrCreate(database)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		res, err := external.Poll(cmd.Context(), database, external.NewChecker(), sess.ID)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+			return output.JSON(res)
+		}
+		fmt.Printf("Checked %d external dependencies: %d satisfied, %d failed checks\n", res.Checked, res.Satisfied, res.Errors)
+		for _, id := range res.Unblocked {
+			fmt.Printf("UNBLOCKED %s\n", id)
+		}
+		return nil
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(blockedByCmd)
 	rootCmd.AddCommand(dependsOnCmd)
@@ -719,6 +944,7 @@ func init() {
 	// Add subcommands to dep
 	depCmd.AddCommand(depAddCmd)
 	depCmd.AddCommand(depRmCmd)
+	depCmd.AddCommand(depPollCmd)
 
 	// Flag-based syntax for dep add (for agent compatibility)
 	depAddCmd.Flags().String("depends-on", "", "Dependency ID(s) to add (comma-separated)")
@@ -730,6 +956,7 @@ func init() {
 
 	depCmd.Flags().Bool("blocking", false, "Show what depends on this issue (reverse)")
 	depCmd.Flags().Bool("json", false, "JSON output")
+	depPollCmd.Flags().Bool("json", false, "JSON output")
 
 	criticalPathCmd.Flags().Int("limit", 10, "Max issues to show")
 	criticalPathCmd.Flags().Bool("json", false, "JSON output")
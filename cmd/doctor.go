@@ -3,113 +3,395 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/marcus/td/internal/db"
 	"github.com/marcus/td/internal/features"
+	"github.com/marcus/td/internal/git"
+	"github.com/marcus/td/internal/output"
+	"github.com/marcus/td/internal/serve"
+	"github.com/marcus/td/internal/settings"
 	"github.com/marcus/td/internal/syncclient"
 	"github.com/marcus/td/internal/syncconfig"
 	"github.com/spf13/cobra"
 )
 
+// Check results
+const (
+	checkOK   = "OK"
+	checkWarn = "WARN"
+	checkFail = "FAIL"
+	checkSkip = "SKIP"
+)
+
+// orphanedWorkAge is how long in-progress work can sit untouched by a
+// vanished session before doctor flags it
+const orphanedWorkAge = 7 * 24 * time.Hour
+
+// clockSkewLimit is the largest skew against the sync server doctor accepts
+const clockSkewLimit = 30 * time.Second
+
+// doctorCheck is the result of one diagnostic check
+type doctorCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"` // OK, WARN, FAIL or SKIP
+	Detail string `json:"detail,omitempty"`
+	Fix    string `json:"fix,omitempty"` // suggested fix for WARN and FAIL
+}
+
 var doctorCmd = &cobra.Command{
-	Use:     "doctor",
-	Short:   "Run diagnostic checks for sync setup",
+	Use:   "doctor",
+	Short: "Check td's setup and database, with suggested fixes",
+	Long: `Run the checks support starts with: the project database (schema
+version, WAL mode, integrity), git, sessions that left work behind,
+references to missing issues, td serve's port and, when sync is set up,
+the sync server, login and clock skew against it.
+
+Each check reports OK, WARN, FAIL or SKIP, with a suggested fix for
+problems. Exits non-zero when a check fails.`,
+	Example: `  td doctor
+  td doctor --json`,
 	GroupID: "system",
+	Args:    cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		runDoctor()
+		checks := runDoctor(getBaseDir())
+
+		failed := 0
+		for _, c := range checks {
+			if c.Status == checkFail {
+				failed++
+			}
+		}
+
+		if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+			if err := output.JSON(map[string]interface{}{"checks": checks, "failed": failed}); err != nil {
+				return err
+			}
+		} else {
+			for _, c := range checks {
+				printDoctorCheck(c)
+			}
+		}
+
+		if failed > 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("%d check(s) failed", failed)
+		}
 		return nil
 	},
 }
 
-func runDoctor() {
-	// 1. Auth config
+func printDoctorCheck(c doctorCheck) {
+	line := c.Status
+	if c.Detail != "" {
+		line += " (" + c.Detail + ")"
+	}
+	dots := 22 - len(c.Name)
+	if dots < 3 {
+		dots = 3
+	}
+	fmt.Printf("%s %s %s\n", c.Name, strings.Repeat(".", dots), line)
+	if c.Fix != "" && (c.Status == checkWarn || c.Status == checkFail) {
+		fmt.Printf("  fix: %s\n", c.Fix)
+	}
+}
+
+// runDoctor runs every check for the project in baseDir
+func runDoctor(baseDir string) []doctorCheck {
+	var checks []doctorCheck
+
+	database, err := db.Open(baseDir)
+	if err != nil {
+		checks = append(checks, doctorCheck{Name: "Local database", Status: checkFail, Detail: err.Error(), Fix: "run td init in the project root, or td -w <dir> to point at it"})
+		for _, name := range []string{"Schema version", "WAL mode", "Integrity", "Orphaned sessions", "Dangling references"} {
+			checks = append(checks, doctorCheck{Name: name, Status: checkSkip})
+		}
+	} else {
+		defer database.Close()
+		checks = append(checks, doctorCheck{Name: "Local database", Status: checkOK})
+		checks = append(checks, checkSchemaVersion(database), checkWALMode(database), checkIntegrity(database),
+			checkOrphanedWork(database), checkDanglingReferences(database))
+	}
+
+	checks = append(checks, checkGit(baseDir), checkServePort(baseDir))
+	checks = append(checks, syncDoctorChecks(baseDir, database)...)
+	return checks
+}
+
+func checkSchemaVersion(database *db.DB) doctorCheck {
+	c := doctorCheck{Name: "Schema version"}
+	version, err := database.GetSchemaVersion()
+	switch {
+	case err != nil:
+		c.Status, c.Detail = checkFail, err.Error()
+	case version > db.SchemaVersion:
+		c.Status = checkFail
+		c.Detail = fmt.Sprintf("database is v%d, this td only knows v%d", version, db.SchemaVersion)
+		c.Fix = "upgrade td (td upgrade); a newer td has written to this database"
+	case version < db.SchemaVersion:
+		c.Status = checkFail
+		c.Detail = fmt.Sprintf("database is v%d, want v%d", version, db.SchemaVersion)
+		c.Fix = "migrations did not finish; back up .todos/issues.db and rerun any td command to retry them"
+	default:
+		c.Status, c.Detail = checkOK, fmt.Sprintf("v%d", version)
+	}
+	return c
+}
+
+func checkWALMode(database *db.DB) doctorCheck {
+	c := doctorCheck{Name: "WAL mode"}
+	mode, err := database.JournalMode()
+	switch {
+	case err != nil:
+		c.Status, c.Detail = checkFail, err.Error()
+	case mode != "wal":
+		c.Status = checkWarn
+		c.Detail = "journal_mode is " + mode
+		c.Fix = "the database may be on a filesystem without shared memory (network drive, some containers); move .todos to a local disk"
+	default:
+		c.Status = checkOK
+	}
+	return c
+}
+
+func checkIntegrity(database *db.DB) doctorCheck {
+	c := doctorCheck{Name: "Integrity"}
+	problems, err := database.QuickCheck()
+	switch {
+	case err != nil:
+		c.Status, c.Detail = checkFail, err.Error()
+	case len(problems) > 0:
+		c.Status = checkFail
+		c.Detail = fmt.Sprintf("%d problem(s), first: %s", len(problems), problems[0])
+		c.Fix = "restore .todos/issues.db from a backup or td export sqlite snapshot, or rebuild it with td db rebuild-projections"
+	default:
+		c.Status = checkOK
+	}
+	return c
+}
+
+func checkOrphanedWork(database *db.DB) doctorCheck {
+	c := doctorCheck{Name: "Orphaned sessions"}
+	orphans, err := database.FindOrphanedWork(time.Now().Add(-orphanedWorkAge))
+	if err != nil {
+		c.Status, c.Detail = checkFail, err.Error()
+		return c
+	}
+	if len(orphans.IssueIDs) == 0 && len(orphans.WorkSessionIDs) == 0 {
+		c.Status = checkOK
+		return c
+	}
+
+	c.Status = checkWarn
+	var parts, fixes []string
+	if n := len(orphans.IssueIDs); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d in-progress issue(s) from sessions idle over 7 days: %s", n, doctorSample(orphans.IssueIDs)))
+		fixes = append(fixes, "td unstart <id> to release each issue, or td handoff <id> to pass it on")
+	}
+	if n := len(orphans.WorkSessionIDs); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d work session(s) never ended: %s", n, doctorSample(orphans.WorkSessionIDs)))
+		fixes = append(fixes, "td ws end from the session that started them")
+	}
+	c.Detail = strings.Join(parts, "; ")
+	c.Fix = strings.Join(fixes, "; ")
+	return c
+}
+
+func checkDanglingReferences(database *db.DB) doctorCheck {
+	c := doctorCheck{Name: "Dangling references"}
+	refs, err := database.DanglingReferences()
+	if err != nil {
+		c.Status, c.Detail = checkFail, err.Error()
+		return c
+	}
+	if len(refs) == 0 {
+		c.Status = checkOK
+		return c
+	}
+
+	c.Status = checkWarn
+	var parts []string
+	for _, r := range refs {
+		parts = append(parts, fmt.Sprintf("%d %s.%s -> %s", r.Count, r.Table, r.Column, doctorSample(r.Missing)))
+	}
+	c.Detail = strings.Join(parts, "; ")
+	c.Fix = "td db rebuild-projections --dry-run shows whether the action log can restore the missing rows"
+	return c
+}
+
+func checkGit(baseDir string) doctorCheck {
+	c := doctorCheck{Name: "Git"}
+	if _, err := exec.LookPath("git"); err != nil {
+		c.Status = checkWarn
+		c.Detail = "git not found on PATH"
+		c.Fix = "install git; without it td can't record branches, commits or changed files"
+		return c
+	}
+	if !git.IsRepoIn(baseDir) {
+		c.Status = checkWarn
+		c.Detail = "project is not in a git repository"
+		c.Fix = "git init, or ignore this if the project doesn't use git"
+		return c
+	}
+	c.Status = checkOK
+	if out, err := exec.Command("git", "--version").Output(); err == nil {
+		c.Detail = strings.TrimPrefix(strings.TrimSpace(string(out)), "git version ")
+	}
+	return c
+}
+
+func checkServePort(baseDir string) doctorCheck {
+	c := doctorCheck{Name: "Serve port"}
+	running := 0
+	if info, err := serve.ReadPortFile(baseDir); err == nil {
+		if serve.IsPortFileStale(info) {
+			c.Status = checkWarn
+			c.Detail = fmt.Sprintf("stale .todos/serve-port for pid %d, port %d", info.PID, info.Port)
+			c.Fix = "rm .todos/serve-port; clients reading it will try a server that isn't there"
+			return c
+		}
+		running = info.Port
+	}
+
+	port, addr := 0, "localhost"
+	if v, ok := settings.Get(baseDir, "serve.port", os.Getenv); ok {
+		port, _ = strconv.Atoi(v.Value)
+	}
+	if v, ok := settings.Get(baseDir, "serve.addr", os.Getenv); ok {
+		addr = v.Value
+	}
+	if port != 0 && port != running {
+		ln, err := net.Listen("tcp", net.JoinHostPort(addr, strconv.Itoa(port)))
+		if err != nil {
+			c.Status = checkFail
+			c.Detail = fmt.Sprintf("configured port %d is in use by another process", port)
+			c.Fix = "stop the other process, or change serve.port (td config doctor --area serve shows where it is set)"
+			return c
+		}
+		ln.Close()
+	}
+
+	c.Status = checkOK
+	switch {
+	case running != 0:
+		c.Detail = fmt.Sprintf("td serve running on port %d", running)
+	case port != 0:
+		c.Detail = fmt.Sprintf("port %d free", port)
+	default:
+		c.Detail = "td serve picks a free port"
+	}
+	return c
+}
+
+// syncDoctorChecks checks the sync setup; without the sync CLI they are
+// skipped
+func syncDoctorChecks(baseDir string, database *db.DB) []doctorCheck {
+	names := []string{"Auth config", "Server reachable", "Auth valid", "Clock skew", "Sync linked", "Pending events"}
+	if !features.IsEnabled(baseDir, features.SyncCLI.Name) {
+		var checks []doctorCheck
+		for _, name := range names {
+			checks = append(checks, doctorCheck{Name: name, Status: checkSkip, Detail: "sync not enabled"})
+		}
+		return checks
+	}
+
+	var checks []doctorCheck
+
+	// Auth config
 	auth, err := syncconfig.LoadAuth()
 	authOK := err == nil && auth != nil && auth.APIKey != ""
-	if authOK {
-		fmt.Printf("Auth config ............ OK (%s)\n", auth.Email)
-	} else if err != nil {
-		fmt.Printf("Auth config ............ FAIL (%v)\n", err)
-	} else {
-		fmt.Printf("Auth config ............ FAIL (not logged in)\n")
+	switch {
+	case authOK:
+		checks = append(checks, doctorCheck{Name: "Auth config", Status: checkOK, Detail: auth.Email})
+	case err != nil:
+		checks = append(checks, doctorCheck{Name: "Auth config", Status: checkFail, Detail: err.Error(), Fix: "td auth login"})
+	default:
+		checks = append(checks, doctorCheck{Name: "Auth config", Status: checkFail, Detail: "not logged in", Fix: "td auth login"})
 	}
 
-	// 2. Server reachable
+	// Server reachable; healthz doesn't need auth
 	serverURL := syncconfig.GetServerURL()
-	var client *syncclient.Client
-	serverOK := false
-	if !authOK {
-		// Still try server check even without auth - healthz doesn't need auth
-		client = syncclient.New(serverURL, "", "")
-	} else {
+	client := syncclient.New(serverURL, "", "")
+	if authOK {
 		deviceID, _ := syncconfig.GetDeviceID()
 		client = syncclient.New(serverURL, auth.APIKey, deviceID)
 	}
 	_, err = client.HealthCheck()
-	if err == nil {
-		serverOK = true
-		fmt.Printf("Server reachable ....... OK (%s)\n", serverURL)
+	serverOK := err == nil
+	if serverOK {
+		checks = append(checks, doctorCheck{Name: "Server reachable", Status: checkOK, Detail: serverURL})
 	} else {
-		fmt.Printf("Server reachable ....... FAIL (%v)\n", err)
+		checks = append(checks, doctorCheck{Name: "Server reachable", Status: checkFail, Detail: err.Error(), Fix: "check sync.url (td config doctor --area sync) and that the server is up"})
 	}
 
-	// 3. Auth valid
-	authValid := false
-	if !authOK || !serverOK {
-		fmt.Printf("Auth valid ............. SKIP\n")
-	} else {
+	// Auth valid
+	switch {
+	case !authOK || !serverOK:
+		checks = append(checks, doctorCheck{Name: "Auth valid", Status: checkSkip})
+	default:
 		_, err = client.ListProjects()
-		if err == nil {
-			authValid = true
-			fmt.Printf("Auth valid ............. OK\n")
-		} else if errors.Is(err, syncclient.ErrUnauthorized) {
-			fmt.Printf("Auth valid ............. FAIL (invalid or expired API key)\n")
-		} else {
-			fmt.Printf("Auth valid ............. FAIL (%v)\n", err)
+		switch {
+		case err == nil:
+			checks = append(checks, doctorCheck{Name: "Auth valid", Status: checkOK})
+		case errors.Is(err, syncclient.ErrUnauthorized):
+			checks = append(checks, doctorCheck{Name: "Auth valid", Status: checkFail, Detail: "invalid or expired API key", Fix: "td auth login"})
+		default:
+			checks = append(checks, doctorCheck{Name: "Auth valid", Status: checkFail, Detail: err.Error()})
 		}
 	}
-	_ = authValid // used for documentation; no dependent checks currently gate on this
 
-	// 4. Local database
-	baseDir := getBaseDir()
-	database, err := db.Open(baseDir)
-	dbOK := err == nil
-	if dbOK {
-		defer database.Close()
-		fmt.Printf("Local database ......... OK\n")
+	// Clock skew: sync orders and reports changes by their timestamps
+	if !serverOK {
+		checks = append(checks, doctorCheck{Name: "Clock skew", Status: checkSkip})
+	} else if skew, err := client.ClockSkew(); err != nil {
+		checks = append(checks, doctorCheck{Name: "Clock skew", Status: checkWarn, Detail: err.Error()})
 	} else {
-		fmt.Printf("Local database ......... FAIL (%v)\n", err)
+		c := doctorCheck{Name: "Clock skew", Status: checkOK, Detail: fmt.Sprintf("%+.1fs", skew.Seconds())}
+		if skew > clockSkewLimit || skew < -clockSkewLimit {
+			c.Status = checkWarn
+			c.Detail = fmt.Sprintf("local clock is %s off the server's", skew.Abs().Round(time.Second))
+			c.Fix = "turn on network time sync (e.g. timedatectl set-ntp true, or Date & Time settings)"
+		}
+		checks = append(checks, c)
 	}
 
-	// 5. Sync linked
-	var syncState *db.SyncState
-	if !dbOK {
-		fmt.Printf("Sync linked ............ SKIP\n")
+	// Sync linked and pending events
+	if database == nil {
+		checks = append(checks, doctorCheck{Name: "Sync linked", Status: checkSkip}, doctorCheck{Name: "Pending events", Status: checkSkip})
+		return checks
+	}
+	syncState, err := database.GetSyncState()
+	switch {
+	case err != nil:
+		checks = append(checks, doctorCheck{Name: "Sync linked", Status: checkFail, Detail: err.Error()})
+	case syncState == nil:
+		checks = append(checks, doctorCheck{Name: "Sync linked", Status: checkWarn, Detail: "not linked to a project", Fix: "td sync init"})
+	default:
+		checks = append(checks, doctorCheck{Name: "Sync linked", Status: checkOK, Detail: "project " + syncState.ProjectID})
+	}
+	if count, err := database.CountPendingEvents(); err != nil {
+		checks = append(checks, doctorCheck{Name: "Pending events", Status: checkFail, Detail: err.Error()})
 	} else {
-		syncState, err = database.GetSyncState()
-		if err != nil {
-			fmt.Printf("Sync linked ............ FAIL (%v)\n", err)
-		} else if syncState == nil {
-			fmt.Printf("Sync linked ............ WARN (not linked to a project)\n")
-		} else {
-			fmt.Printf("Sync linked ............ OK (project %s)\n", syncState.ProjectID)
-		}
+		checks = append(checks, doctorCheck{Name: "Pending events", Status: checkOK, Detail: strconv.FormatInt(count, 10)})
 	}
+	return checks
+}
 
-	// 6. Pending events
-	if !dbOK {
-		fmt.Printf("Pending events ......... SKIP\n")
-	} else {
-		count, err := database.CountPendingEvents()
-		if err != nil {
-			fmt.Printf("Pending events ......... FAIL (%v)\n", err)
-		} else if count > 0 {
-			fmt.Printf("Pending events ......... %d\n", count)
-		} else {
-			fmt.Printf("Pending events ......... 0\n")
-		}
+// doctorSample lists the first few ids
+func doctorSample(ids []string) string {
+	const max = 5
+	if len(ids) <= max {
+		return strings.Join(ids, ", ")
 	}
+	return strings.Join(ids[:max], ", ") + fmt.Sprintf(" and %d more", len(ids)-max)
 }
 
 func init() {
-	AddFeatureGatedCommand(features.SyncCLI.Name, doctorCmd)
+	doctorCmd.Flags().Bool("json", false, "Output as JSON")
+	rootCmd.AddCommand(doctorCmd)
 }
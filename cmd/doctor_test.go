@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"net"
+	"testing"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+func TestRunDoctor(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	baseDir := t.TempDir()
+	database, err := db.Initialize(baseDir)
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	database.Close()
+
+	statuses := func() map[string]doctorCheck {
+		got := map[string]doctorCheck{}
+		for _, c := range runDoctor(baseDir) {
+			got[c.Name] = c
+		}
+		return got
+	}
+
+	got := statuses()
+	for _, name := range []string{"Local database", "Schema version", "WAL mode", "Integrity", "Orphaned sessions", "Dangling references", "Serve port"} {
+		if got[name].Status != checkOK {
+			t.Errorf("%s = %+v, want OK", name, got[name])
+		}
+	}
+	if got["Clock skew"].Status != checkSkip {
+		t.Errorf("Clock skew = %+v, want SKIP without sync", got["Clock skew"])
+	}
+
+	// A configured serve port something else holds is a failure with a fix
+	ln, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+	port := ln.Addr().(*net.TCPAddr).Port
+	if err := config.Save(baseDir, &models.Config{Serve: &models.ServeConfig{Port: port}}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	if c := statuses()["Serve port"]; c.Status != checkFail || c.Fix == "" {
+		t.Errorf("Serve port with port %d taken = %+v, want FAIL with a fix", port, c)
+	}
+
+	// Without a project everything past the database check is skipped
+	got = map[string]doctorCheck{}
+	for _, c := range runDoctor(t.TempDir()) {
+		got[c.Name] = c
+	}
+	if got["Local database"].Status != checkFail || got["Schema version"].Status != checkSkip {
+		t.Errorf("no project: database = %+v, schema = %+v", got["Local database"], got["Schema version"])
+	}
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var exportSQLiteCmd = &cobra.Command{
+	Use:   "sqlite",
+	Short: "Write a read-only SQLite snapshot of the database",
+	Long: `Write a consistent copy of the database to --path using SQLite's online
+backup API. The snapshot is a single read-only file that can be opened with
+any SQLite tool without touching the live database.
+
+Examples:
+  td export sqlite --path snapshot.db
+  sqlite3 -readonly snapshot.db 'SELECT status, COUNT(*) FROM issues GROUP BY status'`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		baseDir := getBaseDir()
+		path, _ := cmd.Flags().GetString("path")
+		if path == "" {
+			err := fmt.Errorf("--path is required")
+			output.Error("%v", err)
+			return err
+		}
+
+		cmd.SilenceUsage = true
+		if force, _ := cmd.Flags().GetBool("force"); force {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				output.Error("failed to replace %s: %v", path, err)
+				return err
+			}
+		} else if _, err := os.Stat(path); err == nil {
+			err := fmt.Errorf("%s already exists", path)
+			output.Error("%v (use --force to replace it)", err)
+			return err
+		}
+
+		database, err := db.Open(baseDir)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		if err := database.Snapshot(path); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		output.Success("Snapshot written to %s (%d KB)", path, (info.Size()+1023)/1024)
+		return nil
+	},
+}
+
+func init() {
+	exportSQLiteCmd.Flags().String("path", "", "Snapshot file to write (required)")
+	exportSQLiteCmd.Flags().Bool("force", false, "Replace the file if it exists")
+	exportCmd.AddCommand(exportSQLiteCmd)
+}
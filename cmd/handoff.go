@@ -148,6 +148,7 @@ Or use flags with values, stdin (-), or file (@path):
 				Event:      "handoff",
 				CommitSHA:  gitState.CommitSHA,
 				Branch:     gitState.Branch,
+				Repo:       gitState.Repo,
 				DirtyFiles: gitState.DirtyFiles,
 			}); err != nil {
 				output.Warning("failed to save git snapshot: %v", err)
@@ -165,7 +166,8 @@ Or use flags with values, stdin (-), or file (@path):
 		if gitErr == nil {
 			// Check for commits since start
 			startSnapshot, _ := database.GetStartSnapshot(issueID)
-			if startSnapshot != nil {
+			// Commits since start only make sense in the repository work started in
+			if startSnapshot != nil && (startSnapshot.Repo == "" || startSnapshot.Repo == gitState.Repo) {
 				commits, _ := git.GetCommitsSince(startSnapshot.CommitSHA)
 				fmt.Printf("Git: %s (%s) +%d commits since start\n",
 					output.ShortSHA(gitState.CommitSHA), gitState.Branch, commits)
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/hookscripts"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/output"
+	"github.com/marcus/td/internal/workflow"
+	"github.com/spf13/cobra"
+)
+
+// hookScriptsPreRunRowid is the last action_log row before the command ran,
+// or -1 when post- scripts are not run for it
+var hookScriptsPreRunRowid int64 = -1
+
+var registerPreCloseOnce sync.Once
+
+// hookScriptsSkipped reports whether a command runs no hook scripts. The
+// server has webhooks of its own, and the monitor is long-running: post-
+// scripts for everything it did would all fire when it quits.
+func hookScriptsSkipped(cmd *cobra.Command) (pre, post bool) {
+	switch resolveCommandName(cmd) {
+	case "serve":
+		return true, true
+	case "monitor":
+		return false, true
+	}
+	return false, false
+}
+
+// captureHookScriptState registers the pre-close guard and notes where the
+// action log stands, so post- scripts can run for what the command changes
+func captureHookScriptState(cmd *cobra.Command) {
+	hookScriptsPreRunRowid = -1
+	dir := getBaseDir()
+	skipPre, skipPost := hookScriptsSkipped(cmd)
+	if dir == "" || !hookscripts.Any(dir) {
+		return
+	}
+	if !skipPre && hookscripts.Find(dir, hookscripts.PreClose) != "" {
+		registerPreCloseOnce.Do(func() { workflow.RegisterHook(&preCloseScriptHook{}) })
+	}
+	if skipPost {
+		return
+	}
+
+	database, err := db.Open(dir)
+	if err != nil {
+		return
+	}
+	defer database.Close()
+	hookScriptsPreRunRowid, _ = database.MaxActionRowid()
+}
+
+// runPostHookScripts runs post-create and post-transition scripts for the
+// issues the command created or moved. A failing script is reported as
+// its failure policy says; with "fail" the command fails too.
+func runPostHookScripts(cmd *cobra.Command) error {
+	dir := getBaseDir()
+	if hookScriptsPreRunRowid < 0 || dir == "" {
+		return nil
+	}
+	database, err := db.Open(dir)
+	if err != nil {
+		slog.Debug("hook scripts: open db", "err", err)
+		return nil
+	}
+	actions, err := database.GetActionsAfterRowid(hookScriptsPreRunRowid)
+	database.Close()
+	if err != nil {
+		slog.Debug("hook scripts: query actions", "err", err)
+		return nil
+	}
+
+	var failed error
+	for _, ev := range postHookEvents(actions) {
+		err := hookscripts.Run(dir, ev)
+		if err == nil {
+			continue
+		}
+		switch _, policy := hookscripts.Settings(dir, ev.Name); policy {
+		case hookscripts.OnFailureIgnore:
+		case hookscripts.OnFailureFail:
+			output.Error("%v", err)
+			failed = err
+		default:
+			output.Warning("%v", err)
+		}
+	}
+	if failed != nil {
+		cmd.SilenceUsage = true
+	}
+	return failed
+}
+
+// postHookEvents turns issue creates and status changes in the action log
+// into script events, in the order they happened
+func postHookEvents(actions []models.ActionLog) []hookscripts.Event {
+	var events []hookscripts.Event
+	for _, a := range actions {
+		if a.EntityType != "issue" && a.EntityType != "issues" {
+			continue
+		}
+		var next models.Issue
+		if json.Unmarshal([]byte(a.NewData), &next) != nil || next.ID == "" {
+			continue
+		}
+		if a.ActionType == models.ActionCreate {
+			events = append(events, hookscripts.Event{Name: hookscripts.PostCreate, Issue: &next, To: next.Status, SessionID: a.SessionID})
+			continue
+		}
+		var prev models.Issue
+		if json.Unmarshal([]byte(a.PreviousData), &prev) != nil || prev.Status == "" || prev.Status == next.Status {
+			continue
+		}
+		events = append(events, hookscripts.Event{Name: hookscripts.PostTransition, Issue: &next, From: prev.Status, To: next.Status, SessionID: a.SessionID})
+	}
+	return events
+}
+
+// preCloseScriptHook runs the pre-close script as a policy hook, so every
+// way the CLI closes an issue goes through it
+type preCloseScriptHook struct{}
+
+func (h *preCloseScriptHook) Name() string {
+	return hookscripts.PreClose
+}
+
+func (h *preCloseScriptHook) Check(ctx *workflow.TransitionContext) workflow.GuardResult {
+	if ctx.ToStatus != models.StatusClosed {
+		return workflow.GuardResult{Passed: true}
+	}
+	dir := getBaseDir()
+	err := hookscripts.Run(dir, hookscripts.Event{
+		Name:      hookscripts.PreClose,
+		Issue:     ctx.Issue,
+		From:      ctx.FromStatus,
+		To:        ctx.ToStatus,
+		SessionID: ctx.SessionID,
+	})
+	var serr *hookscripts.Error
+	if !errors.As(err, &serr) {
+		return workflow.GuardResult{Passed: true}
+	}
+	switch _, policy := hookscripts.Settings(dir, hookscripts.PreClose); policy {
+	case hookscripts.OnFailureFail:
+		return workflow.GuardResult{Passed: false, Message: serr.Message}
+	case hookscripts.OnFailureWarn:
+		output.Warning("%v", err)
+	}
+	return workflow.GuardResult{Passed: true}
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/csvimport"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/output"
+	"github.com/marcus/td/internal/session"
+	"github.com/marcus/td/internal/triage"
+	"github.com/spf13/cobra"
+)
+
+var importCSVCmd = &cobra.Command{
+	Use:   "csv <file>",
+	Short: "Bulk-create issues from a CSV file",
+	Long: `Create one issue per CSV row. The first row must be a header.
+
+Columns are matched to fields by name (title, description, type, priority,
+points, labels, parent, acceptance, sprint, due, defer, minor, plus common
+aliases like "Summary" or "Tags"). Use --map to map them explicitly:
+
+  --map title=Title,priority=Prio        map fields to columns
+  --map 'description="Notes, extra"'     double quotes for names with commas
+  --map "labels='imported'"              single quotes set a value for every row
+
+Every row is validated before anything is created. If any row is invalid the
+import is aborted, unless --skip-invalid is given.
+
+Examples:
+  td import csv backlog.csv --dry-run
+  td import csv backlog.csv --map title=Title,priority=Prio,labels=Tags`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		baseDir := getBaseDir()
+
+		var mapping csvimport.Mapping
+		if spec, _ := cmd.Flags().GetString("map"); spec != "" {
+			m, err := csvimport.ParseMapping(spec)
+			if err != nil {
+				output.Error("%v", err)
+				return err
+			}
+			mapping = m
+		}
+
+		f, err := os.Open(args[0])
+		if err != nil {
+			output.Error("failed to read file: %v", err)
+			return err
+		}
+		defer f.Close()
+
+		database, err := db.Open(baseDir)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		sess, err := session.GetOrCreate(database)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		titleMin, titleMax, _ := config.GetTitleLengthLimits(baseDir)
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		skipInvalid, _ := cmd.Flags().GetBool("skip-invalid")
+
+		res, importErr := csvimport.Import(database, f, csvimport.Options{
+			Mapping:     mapping,
+			TitleMin:    titleMin,
+			TitleMax:    titleMax,
+			DryRun:      dryRun,
+			SkipInvalid: skipInvalid,
+			Inbox:       triage.Routes(baseDir, triage.SourceImport),
+		}, sess.ID)
+		if res == nil {
+			output.Error("%v", importErr)
+			return importErr
+		}
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			data, _ := json.MarshalIndent(res, "", "  ")
+			fmt.Println(string(data))
+		} else {
+			printCSVImportResult(res, importErr == nil)
+		}
+
+		if importErr != nil {
+			if errors.Is(importErr, csvimport.ErrInvalidRows) {
+				cmd.SilenceUsage = true
+				output.Error("%v - nothing imported (fix the rows or use --skip-invalid)", importErr)
+			} else {
+				output.Error("%v", importErr)
+			}
+			return importErr
+		}
+		return nil
+	},
+}
+
+func printCSVImportResult(res *csvimport.Result, summary bool) {
+	fmt.Printf("Mapping: %s\n\n", res.Mapping)
+	for _, row := range res.Rows {
+		switch {
+		case !row.Valid():
+			fmt.Printf("line %d: INVALID %s\n", row.Line, row.Title)
+			for _, e := range row.Errors {
+				fmt.Printf("    %s: %s\n", e.Field, e.Message)
+			}
+		case row.ID != "":
+			fmt.Printf("line %d: CREATED %s: %s\n", row.Line, row.ID, row.Title)
+		case res.DryRun:
+			fmt.Printf("line %d: [dry-run] %s %s %s\n", row.Line, row.Issue.Priority, row.Issue.Type, row.Title)
+		}
+	}
+
+	if !summary {
+		return
+	}
+	if res.DryRun {
+		valid := len(res.Rows) - res.Invalid
+		fmt.Printf("\n%d rows, %d valid, %d invalid (dry run, nothing created)\n", len(res.Rows), valid, res.Invalid)
+		return
+	}
+	fmt.Printf("\nImported %d issues", res.Created)
+	if res.Invalid > 0 {
+		fmt.Printf(" (%d invalid rows)", res.Invalid)
+	}
+	fmt.Println()
+}
+
+func init() {
+	importCSVCmd.Flags().String("map", "", "Column mapping, e.g. title=Title,priority=Prio (default: match header names)")
+	importCSVCmd.Flags().Bool("dry-run", false, "Validate and preview without creating issues")
+	importCSVCmd.Flags().Bool("skip-invalid", false, "Import valid rows even if some rows are invalid")
+	importCSVCmd.Flags().Bool("json", false, "Output the result as JSON")
+	importCmd.AddCommand(importCSVCmd)
+}
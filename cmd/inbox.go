@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/output"
+	"github.com/marcus/td/internal/session"
+	"github.com/marcus/td/internal/triage"
+	"github.com/spf13/cobra"
+)
+
+var inboxCmd = &cobra.Command{
+	Use:   "inbox",
+	Short: "List issues awaiting triage",
+	Long: `Lists the triage inbox, oldest first. Issues land in the inbox when
+created with --inbox, or from a source the project routes there (see td
+policy inbox), such as imports and bots using the API. They stay out of td
+ready, td next and td list until triaged:
+
+  accept  leave the inbox as ordinary work
+  reject  close with a reason
+  merge   close as duplicates of another issue
+  defer   hide until a date, then come back to the inbox
+
+Each action takes any number of issues.`,
+	Example: `  td inbox
+  td inbox accept td-a1b2 td-c3d4
+  td inbox reject td-e5f6 --reason "not reproducible"
+  td inbox merge td-a7b8 td-c9d0 --into td-a1b2
+  td inbox defer td-e1f2 --until +2w`,
+	GroupID: "workflow",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		issues, err := triage.List(database)
+		if err != nil {
+			output.Error("failed to list the inbox: %v", err)
+			return err
+		}
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			return output.JSON(issues)
+		}
+		for _, issue := range issues {
+			fmt.Println(output.FormatIssueShort(&issue))
+		}
+		if len(issues) == 0 {
+			fmt.Println("Inbox empty")
+		}
+		return nil
+	},
+}
+
+// newInboxActionCmd builds the td inbox subcommand for a triage action
+func newInboxActionCmd(action triage.Action, short, verb string) *cobra.Command {
+	return &cobra.Command{
+		Use:   string(action) + " <issue-id>...",
+		Short: short,
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			req := triage.Request{Action: action, IDs: args}
+			if cmd.Flags().Lookup("reason") != nil {
+				req.Reason, _ = cmd.Flags().GetString("reason")
+			}
+			if cmd.Flags().Lookup("into") != nil {
+				req.Into, _ = cmd.Flags().GetString("into")
+			}
+			if cmd.Flags().Lookup("until") != nil {
+				req.Until, _ = cmd.Flags().GetString("until")
+			}
+			return runInboxAction(cmd, req, verb)
+		},
+	}
+}
+
+// runInboxAction applies a triage decision and reports it
+func runInboxAction(cmd *cobra.Command, req triage.Request, verb string) error {
+	database, err := db.Open(getBaseDir())
+	if err != nil {
+		output.Error("%v", err)
+		return err
+	}
+	defer database.Close()
+
+	sess, err := session.GetOrCreate(database)
+	if err != nil {
+		output.Error("%v", err)
+		return err
+	}
+
+	result, err := triage.Apply(database, req, sess.ID)
+	if err != nil {
+		output.Error("%v", err)
+		return err
+	}
+
+	if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+		return output.JSON(result)
+	}
+	for _, id := range result.Done {
+		switch req.Action {
+		case triage.ActionMerge:
+			fmt.Printf("%s %s into %s\n", verb, id, result.Merge.Keep)
+		case triage.ActionDefer:
+			fmt.Printf("%s %s until %s\n", verb, id, result.Until)
+		default:
+			fmt.Printf("%s %s\n", verb, id)
+		}
+	}
+	for _, s := range result.Skipped {
+		output.Warning("skipped %s: %s", s.ID, s.Reason)
+	}
+	if len(req.IDs) > 1 {
+		fmt.Printf("\n%s %d, skipped %d\n", verb[:1]+strings.ToLower(verb[1:]), len(result.Done), len(result.Skipped))
+	}
+	return nil
+}
+
+func init() {
+	inboxAcceptCmd := newInboxActionCmd(triage.ActionAccept, "Accept issues as ordinary work", "ACCEPTED")
+	inboxRejectCmd := newInboxActionCmd(triage.ActionReject, "Close issues with a reason", "REJECTED")
+	inboxRejectCmd.Flags().StringP("reason", "r", "", "Why the issues are rejected (required)")
+	inboxMergeCmd := newInboxActionCmd(triage.ActionMerge, "Close issues as duplicates of another", "MERGED")
+	inboxMergeCmd.Flags().String("into", "", "Issue to merge into (required)")
+	inboxDeferCmd := newInboxActionCmd(triage.ActionDefer, "Hide issues until a date, then triage again", "DEFERRED")
+	inboxDeferCmd.Flags().String("until", "", "Date to triage again (e.g., +2w, monday, 2026-03-01; required)")
+
+	inboxCmd.Flags().Bool("json", false, "JSON output")
+	for _, c := range []*cobra.Command{inboxAcceptCmd, inboxRejectCmd, inboxMergeCmd, inboxDeferCmd} {
+		c.Flags().Bool("json", false, "JSON output")
+		inboxCmd.AddCommand(c)
+	}
+	rootCmd.AddCommand(inboxCmd)
+}
@@ -3,32 +3,89 @@ package cmd
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/marcus/td/internal/agent"
+	"github.com/marcus/td/internal/config"
 	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/demo"
+	"github.com/marcus/td/internal/features"
 	"github.com/marcus/td/internal/git"
+	"github.com/marcus/td/internal/models"
 	"github.com/marcus/td/internal/output"
 	"github.com/marcus/td/internal/session"
 	"github.com/spf13/cobra"
 )
 
 var initCmd = &cobra.Command{
-	Use:     "init",
-	Short:   "Initialize a new td project",
-	Long:    `Creates the local .todos directory and SQLite database.`,
+	Use:   "init",
+	Short: "Initialize a new td project",
+	Long: `Creates the local .todos directory and SQLite database.
+
+Run in a terminal, init asks for the project name, issue ID prefix,
+workflow preset and default boards; flags answer a question up front and
+--yes accepts the defaults for the rest.
+
+Presets:
+  solo    td defaults
+  team    desktop notifications for reviews and mentions
+  strict  reviews need a session other than the creator; titles of 20+ chars
+
+Board sets:
+  standard  Bugs, Urgent (P0/P1) and In Review boards
+  none      no boards beyond the built-in All Issues
+
+--demo seeds a fresh project with a sample web shop (epics, bugs,
+dependencies, reviews in flight, boards and sessions) for trying td out or
+reproducing a bug report from a known state.`,
+	Example: `  td init
+  td init --name "Web app" --prefix web --preset team -y
+  td init --demo`,
 	GroupID: "system",
 	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
 		baseDir := getBaseDir()
+		withDemo, _ := cmd.Flags().GetBool("demo")
 
 		// Check if already initialized
 		if _, err := os.Stat(filepath.Join(baseDir, ".todos")); err == nil {
+			if withDemo {
+				err := fmt.Errorf("--demo needs a fresh project: .todos/ already exists")
+				output.Error("%v", err)
+				return err
+			}
 			output.Warning(".todos/ already exists")
 			return nil
 		}
 
+		opts := initOptions{Name: filepath.Base(baseDir), Preset: "solo", Boards: "standard"}
+		if withDemo {
+			opts.Name, opts.Boards = "acme-shop", "none"
+		}
+		if cmd.Flags().Changed("name") {
+			opts.Name, _ = cmd.Flags().GetString("name")
+		}
+		opts.Prefix, _ = cmd.Flags().GetString("prefix")
+		if cmd.Flags().Changed("preset") {
+			opts.Preset, _ = cmd.Flags().GetString("preset")
+		}
+		if cmd.Flags().Changed("boards") {
+			opts.Boards, _ = cmd.Flags().GetString("boards")
+		}
+
+		yes, _ := cmd.Flags().GetBool("yes")
+		if !yes && !withDemo && stdinIsTerminal() {
+			opts = promptInitOptions(bufio.NewReader(os.Stdin), os.Stdout, opts)
+		}
+		if err := opts.validate(); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
 		// Initialize database
 		database, err := db.Initialize(baseDir)
 		if err != nil {
@@ -40,6 +97,17 @@ var initCmd = &cobra.Command{
 		todosPath := filepath.Join(baseDir, ".todos")
 		fmt.Printf("INITIALIZED %s\n", todosPath)
 
+		if opts.Prefix != "" {
+			if err := database.SetIssuePrefix(opts.Prefix); err != nil {
+				output.Error("%v", err)
+				return err
+			}
+		}
+		if err := applyInitConfig(baseDir, opts); err != nil {
+			output.Error("failed to save config: %v", err)
+			return err
+		}
+
 		// Add to .gitignore if in a git repo
 		if git.IsRepo() {
 			gitignorePath := filepath.Join(baseDir, ".gitignore")
@@ -55,6 +123,25 @@ var initCmd = &cobra.Command{
 
 		fmt.Printf("Session: %s\n", sess.ID)
 
+		if opts.Boards == "standard" {
+			for _, b := range standardBoards {
+				if _, err := database.CreateBoardLogged(b.name, b.query, sess.ID); err != nil {
+					output.Warning("failed to create board %s: %v", b.name, err)
+				}
+			}
+		}
+
+		if withDemo {
+			res, err := demo.Seed(database)
+			if err != nil {
+				output.Error("failed to seed demo data: %v", err)
+				return err
+			}
+			output.Success("Seeded demo project: %d issues, %d boards, %d sessions", res.Issues, res.Boards, res.Sessions)
+			fmt.Println("Try: td list, td board show Checkout, td monitor")
+			return nil
+		}
+
 		// Suggest adding td usage to agent file
 		suggestAgentFileAddition(baseDir)
 
@@ -62,6 +149,123 @@ var initCmd = &cobra.Command{
 	},
 }
 
+// initOptions holds the answers to td init's setup questions
+type initOptions struct {
+	Name   string
+	Prefix string // issue ID prefix without the dash; empty keeps "td"
+	Preset string // solo, team or strict
+	Boards string // standard or none
+}
+
+var (
+	initPresets   = []string{"solo", "team", "strict"}
+	initBoardSets = []string{"standard", "none"}
+)
+
+// standardBoards is the "standard" board set
+var standardBoards = []struct{ name, query string }{
+	{"Bugs", "type = bug"},
+	{"Urgent", "priority <= P1"},
+	{"In Review", "status = in_review"},
+}
+
+func (o *initOptions) validate() error {
+	if o.Prefix != "" {
+		prefix, err := db.ValidateIssuePrefix(o.Prefix)
+		if err != nil {
+			return err
+		}
+		o.Prefix = prefix
+	}
+	if !slices.Contains(initPresets, o.Preset) {
+		return fmt.Errorf("unknown preset %q (want %s)", o.Preset, strings.Join(initPresets, ", "))
+	}
+	if !slices.Contains(initBoardSets, o.Boards) {
+		return fmt.Errorf("unknown board set %q (want %s)", o.Boards, strings.Join(initBoardSets, ", "))
+	}
+	return nil
+}
+
+// promptInitOptions asks each setup question, offering the current value
+// as the default. An empty answer or EOF keeps the default; an invalid
+// choice is asked again.
+func promptInitOptions(in *bufio.Reader, out io.Writer, opts initOptions) initOptions {
+	ask := func(question, def string) (string, bool) {
+		fmt.Fprintf(out, "%s [%s]: ", question, def)
+		line, err := in.ReadString('\n')
+		line = strings.TrimSpace(line)
+		if line == "" {
+			if err != nil {
+				fmt.Fprintln(out)
+			}
+			return def, err == nil
+		}
+		return line, true
+	}
+	choose := func(question string, choices []string, def string) string {
+		for {
+			answer, more := ask(fmt.Sprintf("%s (%s)", question, strings.Join(choices, "/")), def)
+			answer = strings.ToLower(answer)
+			if slices.Contains(choices, answer) || !more {
+				return answer
+			}
+			fmt.Fprintf(out, "Please choose one of: %s\n", strings.Join(choices, ", "))
+		}
+	}
+
+	opts.Name, _ = ask("Project name", opts.Name)
+	prefix := opts.Prefix
+	if prefix == "" {
+		prefix = "td"
+	}
+	for {
+		answer, more := ask("Issue ID prefix", prefix)
+		p, err := db.ValidateIssuePrefix(answer)
+		if err == nil {
+			opts.Prefix = p
+			break
+		}
+		if !more {
+			break
+		}
+		fmt.Fprintln(out, err)
+	}
+	if opts.Prefix == "td" {
+		opts.Prefix = ""
+	}
+	opts.Preset = choose("Workflow preset", initPresets, opts.Preset)
+	opts.Boards = choose("Default boards", initBoardSets, opts.Boards)
+	return opts
+}
+
+// applyInitConfig writes the project name and preset settings to config
+func applyInitConfig(baseDir string, opts initOptions) error {
+	cfg, err := config.Load(baseDir)
+	if err != nil {
+		return err
+	}
+	if opts.Name != filepath.Base(baseDir) {
+		cfg.ProjectName = strings.TrimSpace(opts.Name)
+	}
+	switch opts.Preset {
+	case "team":
+		cfg.Notify = &models.NotifyConfig{Enabled: true, Events: []string{"review", "mention"}}
+	case "strict":
+		if cfg.FeatureFlags == nil {
+			cfg.FeatureFlags = map[string]bool{}
+		}
+		cfg.FeatureFlags[features.BalancedReviewPolicy.Name] = false
+		cfg.TitleMinLength = 20
+	}
+	return config.Save(baseDir, cfg)
+}
+
+// stdinIsTerminal reports whether stdin is interactive
+func stdinIsTerminal() bool {
+	stat, err := os.Stdin.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
+
 func addToGitignore(path string) {
 	// Read existing content
 	content, _ := os.ReadFile(path)
@@ -130,5 +334,11 @@ func suggestAgentFileAddition(baseDir string) {
 }
 
 func init() {
+	initCmd.Flags().String("name", "", "Project display name (default: directory name)")
+	initCmd.Flags().String("prefix", "", "Issue ID prefix, e.g. web for web-a1b2c3 (default: td)")
+	initCmd.Flags().String("preset", "solo", "Workflow preset: solo, team, strict")
+	initCmd.Flags().String("boards", "standard", "Default boards: standard, none")
+	initCmd.Flags().BoolP("yes", "y", false, "Skip questions and use flags and defaults")
+	initCmd.Flags().Bool("demo", false, "Seed sample issues, boards and sessions")
 	rootCmd.AddCommand(initCmd)
 }
@@ -1,11 +1,17 @@
 package cmd
 
 import (
+	"bufio"
+	"bytes"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/marcus/td/internal/config"
 	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/features"
 	"github.com/marcus/td/internal/models"
 )
 
@@ -130,3 +136,85 @@ func TestInitPermissions(t *testing.T) {
 		t.Error("Expected .todos directory to be readable/writable")
 	}
 }
+
+// TestPromptInitOptions tests the interactive setup questions
+func TestPromptInitOptions(t *testing.T) {
+	defaults := initOptions{Name: "shop", Preset: "solo", Boards: "standard"}
+
+	// Empty answers keep the defaults
+	var out bytes.Buffer
+	got := promptInitOptions(bufio.NewReader(strings.NewReader("\n\n\n\n")), &out, defaults)
+	if got != defaults {
+		t.Errorf("defaults: got %+v, want %+v", got, defaults)
+	}
+
+	// Invalid answers are asked again
+	out.Reset()
+	input := "Web App\n9bad\nWeb\nchaos\nstrict\nnone\n"
+	got = promptInitOptions(bufio.NewReader(strings.NewReader(input)), &out, defaults)
+	want := initOptions{Name: "Web App", Prefix: "web", Preset: "strict", Boards: "none"}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if !strings.Contains(out.String(), "invalid issue ID prefix") || !strings.Contains(out.String(), "Please choose one of") {
+		t.Errorf("expected re-prompts, got output:\n%s", out.String())
+	}
+
+	// EOF mid-way keeps the remaining defaults
+	got = promptInitOptions(bufio.NewReader(strings.NewReader("Web App\n")), io.Discard, defaults)
+	if got.Name != "Web App" || got.Preset != "solo" || got.Boards != "standard" {
+		t.Errorf("EOF: got %+v", got)
+	}
+}
+
+// TestInitOptionsValidate tests flag validation
+func TestInitOptionsValidate(t *testing.T) {
+	opts := initOptions{Prefix: "API-", Preset: "team", Boards: "none"}
+	if err := opts.validate(); err != nil || opts.Prefix != "api" {
+		t.Errorf("validate = %v, prefix %q", err, opts.Prefix)
+	}
+	for _, bad := range []initOptions{
+		{Prefix: "a b", Preset: "solo", Boards: "none"},
+		{Preset: "loose", Boards: "none"},
+		{Preset: "solo", Boards: "all"},
+	} {
+		if err := bad.validate(); err == nil {
+			t.Errorf("validate(%+v) should fail", bad)
+		}
+	}
+}
+
+// TestApplyInitConfig tests that presets and the project name reach config
+func TestApplyInitConfig(t *testing.T) {
+	dir := t.TempDir()
+	database, err := db.Initialize(dir)
+	if err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	database.Close()
+
+	if err := applyInitConfig(dir, initOptions{Name: "Web App", Preset: "strict"}); err != nil {
+		t.Fatalf("applyInitConfig: %v", err)
+	}
+	if name := config.GetProjectName(dir); name != "Web App" {
+		t.Errorf("project name = %q", name)
+	}
+	if min, _, _ := config.GetTitleLengthLimits(dir); min != 20 {
+		t.Errorf("title min length = %d, want 20", min)
+	}
+	if enabled, set, _ := config.GetFeatureFlag(dir, features.BalancedReviewPolicy.Name); !set || enabled {
+		t.Errorf("balanced_review_policy = %v (set %v), want disabled", enabled, set)
+	}
+
+	team := t.TempDir()
+	if err := applyInitConfig(team, initOptions{Name: filepath.Base(team), Preset: "team"}); err != nil {
+		t.Fatalf("applyInitConfig: %v", err)
+	}
+	cfg, _ := config.Load(team)
+	if cfg.ProjectName != "" {
+		t.Errorf("directory name should not be stored, got %q", cfg.ProjectName)
+	}
+	if cfg.Notify == nil || !cfg.Notify.Enabled || len(cfg.Notify.Events) != 2 {
+		t.Errorf("team notify = %+v", cfg.Notify)
+	}
+}
@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/integrations"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var integrationCmd = &cobra.Command{
+	Use:     "integration",
+	Aliases: []string{"integrations"},
+	Short:   "Manage Slack and Discord integrations",
+	Long: `Configure chat integrations served by td serve.
+
+Inbound slash commands are answered at POST /v1/integrations/<name>:
+  /td create [-p P1] [-t bug] [-l labels] <title>
+  /td show td-abc
+  /td list
+
+Outgoing posts send td activity to a channel webhook after each command.`,
+	GroupID: "system",
+}
+
+var integrationAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add or update an integration",
+	Example: `  td integration add team-slack --kind slack --signing-secret $SLACK_SIGNING_SECRET \
+      --webhook-url https://hooks.slack.com/services/... --events create,close
+  td integration add eng-discord --kind discord --public-key <hex> --webhook-url https://discord.com/api/webhooks/...`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		baseDir := getBaseDir()
+		name := args[0]
+
+		ic, err := config.GetIntegration(baseDir, name)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if ic == nil {
+			ic = &models.IntegrationConfig{Name: name, VerifySignature: true}
+		}
+
+		if cmd.Flags().Changed("kind") {
+			ic.Kind, _ = cmd.Flags().GetString("kind")
+		}
+		if ic.Kind != integrations.KindSlack && ic.Kind != integrations.KindDiscord {
+			err := fmt.Errorf("--kind must be slack or discord")
+			output.Error("%v", err)
+			return err
+		}
+		if cmd.Flags().Changed("signing-secret") {
+			ic.SigningSecret, _ = cmd.Flags().GetString("signing-secret")
+		}
+		if cmd.Flags().Changed("public-key") {
+			ic.PublicKey, _ = cmd.Flags().GetString("public-key")
+		}
+		if cmd.Flags().Changed("webhook-url") {
+			ic.WebhookURL, _ = cmd.Flags().GetString("webhook-url")
+		}
+		if cmd.Flags().Changed("events") {
+			ic.Events, _ = cmd.Flags().GetStringSlice("events")
+		}
+		if cmd.Flags().Changed("verify") {
+			ic.VerifySignature, _ = cmd.Flags().GetBool("verify")
+		}
+
+		if ic.VerifySignature {
+			if ic.Kind == integrations.KindSlack && ic.SigningSecret == "" {
+				output.Warning("signature verification is on but no --signing-secret is set; inbound requests will be rejected")
+			}
+			if ic.Kind == integrations.KindDiscord && ic.PublicKey == "" {
+				output.Warning("signature verification is on but no --public-key is set; inbound requests will be rejected")
+			}
+		} else {
+			output.Warning("signature verification is off; anyone who can reach td serve can run commands")
+		}
+
+		if err := config.SetIntegration(baseDir, *ic); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		output.Success("Integration %s (%s) saved", ic.Name, ic.Kind)
+		fmt.Printf("Inbound endpoint: POST /v1/integrations/%s\n", ic.Name)
+		return nil
+	},
+}
+
+var integrationListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List integrations",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, err := config.GetIntegrations(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			// Never print secrets
+			redacted := make([]models.IntegrationConfig, len(all))
+			for i, ic := range all {
+				redacted[i] = ic
+				if ic.SigningSecret != "" {
+					redacted[i].SigningSecret = "***"
+				}
+			}
+			data, _ := json.MarshalIndent(redacted, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if len(all) == 0 {
+			output.Info("No integrations configured")
+			return nil
+		}
+		for _, ic := range all {
+			verify := "verify"
+			if !ic.VerifySignature {
+				verify = "no-verify"
+			}
+			outgoing := "no outgoing"
+			if ic.WebhookURL != "" {
+				outgoing = "posts"
+				if len(ic.Events) > 0 {
+					outgoing += " " + strings.Join(ic.Events, ",")
+				}
+			}
+			fmt.Printf("%s: %s, %s, %s\n", ic.Name, ic.Kind, verify, outgoing)
+		}
+		return nil
+	},
+}
+
+var integrationRmCmd = &cobra.Command{
+	Use:     "rm <name>",
+	Aliases: []string{"remove", "delete"},
+	Short:   "Remove an integration",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		removed, err := config.RemoveIntegration(getBaseDir(), args[0])
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if !removed {
+			err := fmt.Errorf("integration not found: %s", args[0])
+			output.Error("%v", err)
+			return err
+		}
+		output.Success("Removed integration %s", args[0])
+		return nil
+	},
+}
+
+var integrationTestCmd = &cobra.Command{
+	Use:   "test <name>",
+	Short: "Post a test message to an integration's channel webhook",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ic, err := config.GetIntegration(getBaseDir(), args[0])
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if ic == nil || ic.WebhookURL == "" {
+			err := fmt.Errorf("integration %s has no --webhook-url", args[0])
+			output.Error("%v", err)
+			return err
+		}
+		if err := integrations.Post(ic.Kind, ic.WebhookURL, "Test message from td"); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		output.Success("Posted test message to %s", ic.Name)
+		return nil
+	},
+}
+
+func init() {
+	integrationAddCmd.Flags().String("kind", "", "Integration kind: slack or discord")
+	integrationAddCmd.Flags().String("signing-secret", "", "Slack app signing secret")
+	integrationAddCmd.Flags().String("public-key", "", "Discord application public key (hex)")
+	integrationAddCmd.Flags().String("webhook-url", "", "Channel webhook for outgoing activity posts")
+	integrationAddCmd.Flags().StringSlice("events", nil, "Action types to post, e.g. create,close,approve (default all)")
+	integrationAddCmd.Flags().Bool("verify", true, "Verify inbound request signatures")
+	integrationListCmd.Flags().Bool("json", false, "Output as JSON")
+	integrationCmd.AddCommand(integrationAddCmd, integrationListCmd, integrationRmCmd, integrationTestCmd)
+	rootCmd.AddCommand(integrationCmd)
+}
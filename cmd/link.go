@@ -14,6 +14,7 @@ import (
 	"github.com/marcus/td/internal/dependency"
 	"github.com/marcus/td/internal/models"
 	"github.com/marcus/td/internal/output"
+	"github.com/marcus/td/internal/owners"
 	"github.com/marcus/td/internal/session"
 	"github.com/spf13/cobra"
 )
@@ -87,6 +88,9 @@ var linkCmd = &cobra.Command{
 	Short: "Link files to an issue",
 	Long: `Link one or more files to an issue.
 
+If .todos/owners maps the files to labels (see td owners), the issue gets
+those labels too; --no-owner-labels skips that.
+
 Examples:
   td link td-abc1 src/main.go           # Link single file
   td link td-abc1 src/*.go              # Link via glob pattern
@@ -234,6 +238,7 @@ Examples:
 
 		// Link each file
 		count := 0
+		var linked []string
 		for _, file := range allFiles {
 			// Get absolute path for SHA computation
 			absPath, _ := filepath.Abs(file)
@@ -257,6 +262,7 @@ Examples:
 				continue
 			}
 
+			linked = append(linked, relPath)
 			count++
 		}
 
@@ -266,10 +272,51 @@ Examples:
 			fmt.Printf("LINKED %d files to %s\n", count, issueID)
 		}
 
+		if noLabels, _ := cmd.Flags().GetBool("no-owner-labels"); !noLabels {
+			applyOwnerLabels(database, baseDir, issueID, linked, sess.ID)
+		}
 		return nil
 	},
 }
 
+// applyOwnerLabels adds the labels .todos/owners gives the linked files
+// to the issue. Failures only warn: the files are linked either way.
+func applyOwnerLabels(database *db.DB, baseDir, issueID string, files []string, sessionID string) {
+	m, err := owners.Load(baseDir)
+	if err != nil {
+		output.Warning("owners file: %v", err)
+		return
+	}
+	labels := m.For(files).Labels
+	if len(labels) == 0 {
+		return
+	}
+	issue, err := database.GetIssue(issueID)
+	if err != nil {
+		output.Warning("%v", err)
+		return
+	}
+	has := make(map[string]bool, len(issue.Labels))
+	for _, l := range issue.Labels {
+		has[l] = true
+	}
+	var added []string
+	for _, l := range labels {
+		if !has[l] {
+			issue.Labels = append(issue.Labels, l)
+			added = append(added, l)
+		}
+	}
+	if len(added) == 0 {
+		return
+	}
+	if err := database.UpdateIssueLogged(issue, sessionID, models.ActionUpdate); err != nil {
+		output.Warning("failed to label %s: %v", issue.ID, err)
+		return
+	}
+	fmt.Printf("LABELED %s: %s (from .todos/owners)\n", issue.ID, strings.Join(added, ", "))
+}
+
 var unlinkCmd = &cobra.Command{
 	Use:     "unlink [issue-id] [file-pattern]",
 	Short:   "Remove file associations",
@@ -497,6 +544,7 @@ func init() {
 	linkCmd.Flags().String("role", "implementation", "File role: implementation, test, reference, config")
 	linkCmd.Flags().Bool("recursive", true, "Include subdirectories")
 	linkCmd.Flags().String("depends-on", "", "Add dependency instead of linking files (alternative to 'td dep')")
+	linkCmd.Flags().Bool("no-owner-labels", false, "Don't add the labels .todos/owners gives the linked files")
 
 	filesCmd.Flags().Bool("json", false, "JSON output")
 	filesCmd.Flags().Bool("changed", false, "Only show changed files")
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/output"
+	"github.com/marcus/td/internal/xref"
+	"github.com/spf13/cobra"
+)
+
+var projectCmd = &cobra.Command{
+	Use:   "project",
+	Short: "Link other td projects for cross-project references",
+	Long: `Linking another td project lets this one refer to its issues as
+<name>/<issue-id>, for example api/td-a1b2c3. Qualified IDs work as
+dependencies (td dep add td-x api/td-a1b2c3), are picked up from
+descriptions, and are resolved with their title and status in td deps
+and the HTTP API.
+
+Cross-project dependencies are read-only links: closing an issue never
+changes another project, and a dependency on another project counts as
+open for is_ready() and auto-unblocking. Remove it with td dep rm once
+it no longer applies.`,
+	GroupID: "system",
+}
+
+var projectLinkCmd = &cobra.Command{
+	Use:     "link <name> <path>",
+	Short:   "Link another td project under a reference name",
+	Example: "  td project link api ../api",
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		baseDir := getBaseDir()
+		name := args[0]
+		if !xref.ValidName(name) {
+			err := fmt.Errorf("invalid project name %q: use lowercase letters, digits, - and _", name)
+			output.Error("%v", err)
+			return err
+		}
+
+		path, err := filepath.Abs(args[1])
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		self, _ := filepath.Abs(db.ResolveBaseDir(baseDir))
+		if db.ResolveBaseDir(path) == self {
+			err := fmt.Errorf("%s is this project", path)
+			output.Error("%v", err)
+			return err
+		}
+		other, err := db.Open(path)
+		if err != nil {
+			output.Error("%s: %v", path, err)
+			return err
+		}
+		other.Close()
+
+		if err := config.SetLinkedProject(baseDir, name, path); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		output.Success("Linked %s → %s", name, path)
+		return nil
+	},
+}
+
+var projectUnlinkCmd = &cobra.Command{
+	Use:   "unlink <name>",
+	Short: "Forget a linked project",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		removed, err := config.RemoveLinkedProject(getBaseDir(), args[0])
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if !removed {
+			err := fmt.Errorf("no linked project %q", args[0])
+			output.Error("%v", err)
+			return err
+		}
+		output.Success("Unlinked %s", args[0])
+		return nil
+	},
+}
+
+var projectListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List linked projects",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		projects, err := config.GetLinkedProjects(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			if projects == nil {
+				projects = map[string]string{}
+			}
+			data, _ := json.MarshalIndent(projects, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if len(projects) == 0 {
+			output.Info("No linked projects (add one with td project link <name> <path>)")
+			return nil
+		}
+		names := make([]string, 0, len(projects))
+		for name := range projects {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("%-20s  %s\n", name, projects[name])
+		}
+		return nil
+	},
+}
+
+func init() {
+	projectListCmd.Flags().Bool("json", false, "Output as JSON")
+	projectCmd.AddCommand(projectLinkCmd, projectUnlinkCmd, projectListCmd)
+	rootCmd.AddCommand(projectCmd)
+}
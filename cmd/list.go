@@ -5,7 +5,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/dateparse"
 	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/issuetmpl"
 	"github.com/marcus/td/internal/models"
 	"github.com/marcus/td/internal/output"
 	"github.com/marcus/td/internal/query"
@@ -28,6 +31,12 @@ var listCmd = &cobra.Command{
 		}
 		defer database.Close()
 
+		tmpl, err := issueTemplate(cmd, baseDir)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
 		// Handle --filter flag (TDQ query expression)
 		filterQuery, _ := cmd.Flags().GetString("filter")
 		filterFlagProvided := cmd.Flags().Changed("filter")
@@ -80,6 +89,9 @@ var listCmd = &cobra.Command{
 			if format == "json" || jsonOutput {
 				return output.JSON(results)
 			}
+			if tmpl != nil {
+				return renderIssueTemplate(tmpl, issuetmpl.FromIssues(results))
+			}
 
 			long, _ := cmd.Flags().GetBool("long")
 			if format == "long" || long {
@@ -252,6 +264,13 @@ var listCmd = &cobra.Command{
 			opts.ExcludeDeferred = true
 		}
 
+		// Triage inbox: hidden unless asked for
+		if inbox, _ := cmd.Flags().GetBool("inbox"); inbox {
+			opts.InboxOnly = true
+		} else if !showAll {
+			opts.ExcludeInbox = true
+		}
+
 		issues, err := database.ListIssues(opts)
 		if err != nil {
 			output.Error("failed to list issues: %v", err)
@@ -264,6 +283,9 @@ var listCmd = &cobra.Command{
 		if format == "json" || jsonOutput {
 			return output.JSON(issues)
 		}
+		if tmpl != nil {
+			return renderIssueTemplate(tmpl, issuetmpl.FromIssues(issues))
+		}
 
 		long, _ := cmd.Flags().GetBool("long")
 		if format == "long" || long {
@@ -292,6 +314,10 @@ var listCmd = &cobra.Command{
 // listShortcutResult holds the result of a shortcut list operation
 type listShortcutResult struct {
 	issues []models.Issue
+	triage []models.Issue      // open issues short of the ready gate
+	gaps   map[string][]string // what each triage issue lacks
+
+	inherited map[string]models.InheritedPriority // raised priorities, when inheritance is on
 }
 
 // runListShortcut is the shared core for all list shortcut commands
@@ -314,6 +340,49 @@ func runListShortcut(opts db.ListIssuesOptions) (*listShortcutResult, error) {
 	return &listShortcutResult{issues: issues}, nil
 }
 
+// runReadyShortcut lists open issues with no open dependencies, ranked
+// issues first (see td rank) and the rest by priority, split by the
+// project's definition of ready (see td policy ready)
+func runReadyShortcut() (*listShortcutResult, error) {
+	database, err := db.Open(getBaseDir())
+	if err != nil {
+		output.Error("%v", err)
+		return nil, err
+	}
+	defer database.Close()
+
+	issues, err := database.ListIssues(db.ListIssuesOptions{
+		Status:             []models.Status{models.StatusOpen},
+		SortBy:             "rank",
+		ExcludeHasOpenDeps: true,
+	})
+	if err != nil {
+		output.Error("failed to list issues: %v", err)
+		return nil, err
+	}
+	inherited, err := database.InheritedPriorities()
+	if err != nil {
+		output.Error("failed to compute inherited priorities: %v", err)
+		return nil, err
+	}
+	db.SortByEffectivePriority(issues, inherited)
+
+	gaps, err := database.ReadinessGaps(issues)
+	if err != nil {
+		output.Error("failed to check the ready gate: %v", err)
+		return nil, err
+	}
+	result := &listShortcutResult{gaps: gaps, inherited: inherited}
+	for _, issue := range issues {
+		if _, ok := gaps[issue.ID]; ok {
+			result.triage = append(result.triage, issue)
+		} else {
+			result.issues = append(result.issues, issue)
+		}
+	}
+	return result, nil
+}
+
 var reviewableCmd = &cobra.Command{
 	Use:     "reviewable",
 	Short:   "Show issues awaiting review that you can review",
@@ -420,52 +489,95 @@ var inReviewCmd = &cobra.Command{
 }
 
 var readyCmd = &cobra.Command{
-	Use:     "ready",
-	Short:   "List open issues sorted by priority",
+	Use:   "ready",
+	Short: "List open issues sorted by rank, then priority",
+	Long: `List open, unblocked issues: ranked issues first (see td rank), in queue
+order, then the rest by priority. With priority inheritance on (see td
+policy inherit), an issue that a higher-priority issue waits on sorts at
+that priority and is marked with it. When the project has a definition of
+ready (see td policy ready), issues that fall short of it are left out and
+counted instead; td triage lists them.`,
 	GroupID: "shortcuts",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		result, err := runListShortcut(db.ListIssuesOptions{
-			Status:             []models.Status{models.StatusOpen},
-			SortBy:             "priority",
-			ExcludeHasOpenDeps: true,
-		})
+		result, err := runReadyShortcut()
 		if err != nil {
 			return err
 		}
 
 		for _, issue := range result.issues {
-			fmt.Println(output.FormatIssueShort(&issue))
+			line := output.FormatIssueShort(&issue)
+			if ip, ok := result.inherited[issue.ID]; ok {
+				line += "  " + output.FormatInheritedPriority(ip)
+			}
+			fmt.Println(line)
 		}
 
 		if len(result.issues) == 0 {
 			fmt.Println("No open issues")
 		}
+		if n := len(result.triage); n > 0 {
+			fmt.Printf("\n%d more need triage (td triage)\n", n)
+		}
+		return nil
+	},
+}
+
+var triageCmd = &cobra.Command{
+	Use:   "triage",
+	Short: "List open issues that need triage before they are ready",
+	Long: `List open, unblocked issues that fall short of the project's definition
+of ready (see td policy ready), with what each one lacks. These issues are
+kept out of td ready, td next and the monitor's ready section until they
+are filled in.`,
+	GroupID: "shortcuts",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		result, err := runReadyShortcut()
+		if err != nil {
+			return err
+		}
+
+		for _, issue := range result.triage {
+			fmt.Printf("%s  needs: %s\n", output.FormatIssueShort(&issue), strings.Join(result.gaps[issue.ID], ", "))
+		}
+
+		if len(result.triage) == 0 {
+			fmt.Println("No issues need triage")
+		}
 		return nil
 	},
 }
 
 var nextCmd = &cobra.Command{
-	Use:     "next",
-	Short:   "Show highest-priority open issue",
+	Use:   "next",
+	Short: "Show the highest-scoring open issue",
+	Long: `Show the open, unblocked issue with the highest score. Scores come from
+the project's scoring formula (see td score), so an old or nearly due P2
+can outrank a fresh P1. Issues that need triage are never suggested.`,
 	GroupID: "shortcuts",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		result, err := runListShortcut(db.ListIssuesOptions{
-			Status:             []models.Status{models.StatusOpen},
-			SortBy:             "priority",
-			Limit:              1,
-			ExcludeHasOpenDeps: true,
-		})
+		result, err := runReadyShortcut()
 		if err != nil {
 			return err
 		}
 
 		if len(result.issues) == 0 {
 			fmt.Println("No open issues")
+			if n := len(result.triage); n > 0 {
+				fmt.Printf("%d need triage first (td triage)\n", n)
+			}
 			return nil
 		}
 
+		formula, err := config.GetScoreFormula(getBaseDir())
+		if err != nil {
+			output.Warning("invalid score_formula in config, using default: %v", err)
+		}
+		now := dateparse.Now()
+		formula.Sort(result.issues, now, false)
+
 		issue := result.issues[0]
 		fmt.Println(output.FormatIssueShort(&issue))
+		fmt.Printf("Score: %.2f (%s)\n", formula.Eval(&issue, now), formula)
 		fmt.Println()
 		fmt.Printf("Run `td start %s` to begin working on this issue.\n", issue.ID)
 		return nil
@@ -591,6 +703,7 @@ func init() {
 	rootCmd.AddCommand(inReviewCmd)
 	rootCmd.AddCommand(readyCmd)
 	rootCmd.AddCommand(nextCmd)
+	rootCmd.AddCommand(triageCmd)
 	rootCmd.AddCommand(deletedCmd)
 
 	listCmd.Flags().StringArrayP("id", "i", nil, "Filter by issue IDs")
@@ -624,8 +737,10 @@ func init() {
 	listCmd.Flags().Bool("overdue", false, "Show tasks past their due date")
 	listCmd.Flags().Bool("surfacing", false, "Show tasks that just resurfaced (previously deferred)")
 	listCmd.Flags().Bool("due-soon", false, "Show tasks due within 3 days")
+	listCmd.Flags().Bool("inbox", false, "Show only issues awaiting triage")
 
 	listCmd.Flags().String("format", "", "Output format (short, long, json)")
+	listCmd.Flags().String("template", "", "Render each issue with a Go template, or @name (see td config template)")
 	listCmd.Flags().Bool("no-pager", false, "Disable paging (no-op, td list does not page)")
 	listCmd.Flags().StringP("filter", "f", "", "TDQ query expression (e.g., 'status=open AND type=bug')")
 }
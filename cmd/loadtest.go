@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/loadtest"
+	"github.com/marcus/td/internal/output"
+	"github.com/marcus/td/internal/serve"
+	"github.com/marcus/td/internal/serveclient"
+	"github.com/spf13/cobra"
+)
+
+var loadtestCmd = &cobra.Command{
+	Use:   "loadtest",
+	Short: "Load-test a running td serve",
+	Long: `Runs --clients concurrent clients against td serve for --duration, each
+picking requests from a weighted mix of reads (issue list, issue detail,
+monitor) and writes (create, update, comment), then reports requests per
+second, error rate and latency percentiles per operation.
+
+The server is found from this project's port file unless --url is given.
+Writes only touch issues the run creates; they are labeled "loadtest" and
+deleted at the end unless --keep is set. Run it against a scratch project
+(td bench --dir seeds a large one) rather than real work.
+
+--mix overrides the default weights, e.g. "list=50,get=50" for reads only.
+Operations: list, get, monitor, create, update, comment.`,
+	Example: `  td loadtest --clients 50 --duration 60s
+  td loadtest --url http://127.0.0.1:8080 --token secret --read-only
+  td loadtest --mix "list=40,monitor=40,create=20" --json`,
+	GroupID: "system",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		clients, _ := cmd.Flags().GetInt("clients")
+		duration, _ := cmd.Flags().GetDuration("duration")
+		baseURL, _ := cmd.Flags().GetString("url")
+		token, _ := cmd.Flags().GetString("token")
+		mixStr, _ := cmd.Flags().GetString("mix")
+		readOnly, _ := cmd.Flags().GetBool("read-only")
+		keep, _ := cmd.Flags().GetBool("keep")
+		seed, _ := cmd.Flags().GetInt64("seed")
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		cfg := loadtest.Config{Clients: clients, Duration: duration, ReadOnly: readOnly, Seed: seed}
+		if mixStr != "" {
+			mix, err := parseLoadMix(mixStr)
+			if err != nil {
+				output.Error("%v", err)
+				return err
+			}
+			cfg.Mix = mix
+		}
+
+		if baseURL == "" {
+			info, err := serve.ReadPortFile(getBaseDir())
+			if err != nil || serve.IsPortFileStale(info) {
+				err := fmt.Errorf("no running td serve found for this project; start one or pass --url")
+				output.Error("%v", err)
+				return err
+			}
+			baseURL = fmt.Sprintf("http://127.0.0.1:%d", info.Port)
+		}
+		client := serveclient.New(strings.TrimRight(baseURL, "/"), token)
+
+		// Ctrl-C ends the run early but still reports and cleans up
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		if !asJSON {
+			fmt.Printf("Load testing %s with %d clients for %s...\n", baseURL, clients, duration)
+		}
+		res, err := loadtest.Run(ctx, client, cfg)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		deleted := 0
+		if !keep && len(res.Created) > 0 {
+			deleted, err = loadtest.Cleanup(context.Background(), client, res.Created)
+			if err != nil {
+				output.Warning("cleanup: %v", err)
+			}
+		}
+
+		if asJSON {
+			data, _ := json.MarshalIndent(res, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+		renderLoadtest(res)
+		switch {
+		case keep && len(res.Created) > 0:
+			output.Info("Kept %d issues labeled %q", len(res.Created), loadtest.Label)
+		case deleted > 0:
+			output.Info("Deleted %d issues created by the run", deleted)
+		}
+		return nil
+	},
+}
+
+// parseLoadMix parses "op=weight,..." into a mix
+func parseLoadMix(s string) (map[loadtest.Op]int, error) {
+	valid := map[loadtest.Op]bool{}
+	for _, op := range loadtest.Ops {
+		valid[op] = true
+	}
+	mix := map[loadtest.Op]int{}
+	for _, part := range strings.Split(s, ",") {
+		name, weight, ok := strings.Cut(strings.TrimSpace(part), "=")
+		op := loadtest.Op(strings.TrimSpace(name))
+		if !ok || !valid[op] {
+			return nil, fmt.Errorf("invalid --mix entry %q: want op=weight with op one of list, get, monitor, create, update, comment", part)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(weight))
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid --mix weight %q for %s", weight, op)
+		}
+		mix[op] = n
+	}
+	return mix, nil
+}
+
+// renderLoadtest prints the run summary and a latency table
+func renderLoadtest(res *loadtest.Result) {
+	fmt.Println(output.SectionHeader("LOAD TEST"))
+	fmt.Printf("  %d requests in %.1fs from %d clients: %.1f req/s, %.2f%% errors\n\n",
+		res.Requests, res.Elapsed, res.Clients, res.RPS, res.ErrorRate*100)
+
+	fmt.Printf("  %-8s %8s %7s %9s %9s %9s %9s\n", "OP", "COUNT", "ERRORS", "P50", "P90", "P99", "MAX")
+	for _, s := range res.Ops {
+		fmt.Printf("  %-8s %8d %7d %7.1fms %7.1fms %7.1fms %7.1fms\n",
+			s.Op, s.Count, s.Errors, s.P50, s.P90, s.P99, s.Max)
+	}
+
+	if len(res.ErrorKind) > 0 {
+		fmt.Println()
+		kinds := make([]string, 0, len(res.ErrorKind))
+		for kind := range res.ErrorKind {
+			kinds = append(kinds, kind)
+		}
+		sort.Strings(kinds)
+		for _, kind := range kinds {
+			output.Warning("%d × %s", res.ErrorKind[kind], kind)
+		}
+	}
+	fmt.Println()
+}
+
+func init() {
+	rootCmd.AddCommand(loadtestCmd)
+
+	loadtestCmd.Flags().Int("clients", 10, "Concurrent clients")
+	loadtestCmd.Flags().Duration("duration", 30*time.Second, "How long to run")
+	loadtestCmd.Flags().String("url", "", "Server URL (default: this project's running td serve)")
+	loadtestCmd.Flags().String("token", "", "Bearer token if the server requires one")
+	loadtestCmd.Flags().String("mix", "", "Operation weights, e.g. list=30,get=25,monitor=20,create=5,update=12,comment=8")
+	loadtestCmd.Flags().Bool("read-only", false, "Only send reads")
+	loadtestCmd.Flags().Bool("keep", false, "Keep the issues the run creates")
+	loadtestCmd.Flags().Int64("seed", 1, "Random seed for the clients' choice of requests")
+	loadtestCmd.Flags().Bool("json", false, "Output as JSON")
+}
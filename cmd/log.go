@@ -18,7 +18,7 @@ import (
 )
 
 // issueIDPattern matches valid issue IDs like "td-a1b2c3" or "td-a1b2c3d4"
-var issueIDPattern = regexp.MustCompile(`^td-[0-9a-f]{6,8}$`)
+var issueIDPattern = regexp.MustCompile(`^[a-z][a-z0-9]{0,9}-[0-9a-f]{6,8}$`)
 
 var logCmd = &cobra.Command{
 	Use:   "log [issue-id] <message>",
@@ -74,7 +74,7 @@ Supports stdin input for multi-line messages or piped input:
 			}
 		} else if len(args) == 1 {
 			// One arg: check if it's an issue ID or message
-			// Issue IDs match pattern "td-[8 hex chars]", otherwise it's a message
+			// Issue IDs match pattern "<prefix>-[6-8 hex chars]", otherwise it's a message
 			if issueIDPattern.MatchString(args[0]) {
 				// It's an issue ID, get message from stdin
 				issueID = args[0]
@@ -0,0 +1,245 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/logcompact"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var logsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Compact and archive issue logs",
+	Long: `Agents can write hundreds of progress logs to one issue. td logs compact
+replaces each long run of consecutive progress logs with one digest log,
+leaving decisions, blockers, results and the latest logs as written. The
+originals move to the log archive: td logs archive shows them.
+
+The digest is written by a built-in summarizer keeping the first and last
+messages of the run, or by a command set with td logs config --summarizer.
+The command gets {"issue": ..., "logs": [...]} as JSON on stdin and prints
+the digest.
+
+Once td logs config has been run, td serve also compacts every issue on its
+--compact-interval. Compaction only changes this database and is not
+synced.`,
+	Example: `  td logs compact td-a1b2 --dry-run
+  td logs compact --all
+  td logs config --min-run 30 --summarizer "./scripts/summarize-logs"
+  td logs archive td-a1b2`,
+	GroupID: "workflow",
+}
+
+var logsCompactCmd = &cobra.Command{
+	Use:   "compact [issue-id...]",
+	Short: "Replace long runs of progress logs with a digest",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		baseDir := getBaseDir()
+		all, _ := cmd.Flags().GetBool("all")
+		if all == (len(args) > 0) {
+			err := fmt.Errorf("give issue IDs or --all")
+			output.Error("%v", err)
+			return err
+		}
+
+		lc, err := config.GetLogCompactionConfig(baseDir)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		settings := logcompact.WithDefaults(lc)
+		if cmd.Flags().Changed("min-run") {
+			settings.MinRun, _ = cmd.Flags().GetInt("min-run")
+		}
+		if cmd.Flags().Changed("keep") {
+			settings.Keep, _ = cmd.Flags().GetInt("keep")
+		}
+		if cmd.Flags().Changed("summarizer") {
+			settings.Summarizer, _ = cmd.Flags().GetString("summarizer")
+		}
+		if settings.MinRun < 2 || settings.Keep < 0 {
+			err := fmt.Errorf("--min-run must be at least 2 and --keep not negative")
+			output.Error("%v", err)
+			return err
+		}
+		opts := logcompact.Options{
+			MinRun:     settings.MinRun,
+			Keep:       settings.Keep,
+			Summarizer: logcompact.SummarizerFor(settings.Summarizer, baseDir),
+		}
+		opts.DryRun, _ = cmd.Flags().GetBool("dry-run")
+
+		database, err := db.Open(baseDir)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		results := []logcompact.Result{}
+		if all {
+			results, err = logcompact.CompactAll(database, opts)
+		} else {
+			for _, id := range args {
+				var issue *models.Issue
+				if issue, err = database.GetIssue(id); err != nil {
+					break
+				}
+				var r *logcompact.Result
+				if r, err = logcompact.Compact(database, issue, opts); err != nil {
+					err = fmt.Errorf("%s: %w", issue.ID, err)
+					break
+				}
+				results = append(results, *r)
+			}
+		}
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			data, _ := json.MarshalIndent(results, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		verb := "Compacted"
+		if opts.DryRun {
+			verb = "Would compact"
+		}
+		compacted := 0
+		for _, r := range results {
+			if len(r.Runs) == 0 {
+				fmt.Printf("%s  no runs of %d or more progress logs\n", r.IssueID, opts.MinRun)
+				continue
+			}
+			compacted += r.Compacted
+			noun := "digests"
+			if len(r.Runs) == 1 {
+				noun = "digest"
+			}
+			fmt.Printf("%s  %s %d logs into %d %s\n", r.IssueID, verb, r.Compacted, len(r.Runs), noun)
+			for _, run := range r.Runs {
+				fmt.Printf("  %d logs, %s to %s\n", run.Logs, run.From.Format("2006-01-02 15:04"), run.To.Format("2006-01-02 15:04"))
+			}
+		}
+		if all && len(results) == 0 {
+			output.Info("Nothing to compact")
+		} else if compacted > 0 && !opts.DryRun {
+			output.Success("Archived %d logs (td logs archive <issue-id>)", compacted)
+		}
+		return nil
+	},
+}
+
+var logsArchiveCmd = &cobra.Command{
+	Use:   "archive <issue-id>",
+	Short: "Show an issue's logs replaced by digests",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		issue, err := database.GetIssue(args[0])
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		logs, err := database.GetArchivedLogs(issue.ID)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			data, _ := json.MarshalIndent(logs, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+		if len(logs) == 0 {
+			output.Info("No archived logs for %s", issue.ID)
+			return nil
+		}
+		digest := ""
+		for _, l := range logs {
+			if l.DigestID != digest {
+				digest = l.DigestID
+				fmt.Printf("\nDigest %s:\n", digest)
+			}
+			fmt.Printf("[%s] %s\n", l.Timestamp.Format("2006-01-02 15:04"), l.Message)
+		}
+		return nil
+	},
+}
+
+var logsConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Configure log compaction and turn on the td serve job",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		baseDir := getBaseDir()
+		lc, err := config.GetLogCompactionConfig(baseDir)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		cfg := models.LogCompactionConfig{}
+		if lc != nil {
+			cfg = *lc
+		}
+		if cmd.Flags().Changed("min-run") {
+			cfg.MinRun, _ = cmd.Flags().GetInt("min-run")
+		}
+		if cmd.Flags().Changed("keep") {
+			cfg.Keep, _ = cmd.Flags().GetInt("keep")
+		}
+		if cmd.Flags().Changed("summarizer") {
+			cfg.Summarizer, _ = cmd.Flags().GetString("summarizer")
+		}
+		if cfg.MinRun < 0 || cfg.MinRun == 1 || cfg.Keep < 0 {
+			err := fmt.Errorf("--min-run must be at least 2 and --keep not negative")
+			output.Error("%v", err)
+			return err
+		}
+
+		if err := config.SetLogCompactionConfig(baseDir, cfg); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		settings := logcompact.WithDefaults(&cfg)
+		summarizer := "built-in digest"
+		if settings.Summarizer != "" {
+			summarizer = settings.Summarizer
+		}
+		output.Success("Log compaction: runs of %d+ progress logs, latest %d kept, %s", settings.MinRun, settings.Keep, summarizer)
+		return nil
+	},
+}
+
+func init() {
+	logsCompactCmd.Flags().Bool("all", false, "Compact every issue")
+	logsCompactCmd.Flags().Bool("dry-run", false, "Show the runs that would be compacted")
+	logsCompactCmd.Flags().Int("min-run", 0, "Shortest run of progress logs to compact (default from td logs config, else 20)")
+	logsCompactCmd.Flags().Int("keep", 0, "Latest logs of each issue to leave alone (default from td logs config, else 10)")
+	logsCompactCmd.Flags().String("summarizer", "", "Command that writes the digest (default from td logs config, else built-in)")
+	logsCompactCmd.Flags().Bool("json", false, "Output as JSON")
+	logsArchiveCmd.Flags().Bool("json", false, "Output as JSON")
+	logsConfigCmd.Flags().Int("min-run", 0, "Shortest run of progress logs to compact (0 = default 20)")
+	logsConfigCmd.Flags().Int("keep", 0, "Latest logs of each issue to leave alone (0 = default 10)")
+	logsConfigCmd.Flags().String("summarizer", "", "Command that writes the digest, run with sh -c (empty = built-in)")
+	logsCmd.AddCommand(logsCompactCmd, logsArchiveCmd, logsConfigCmd)
+	rootCmd.AddCommand(logsCmd)
+}
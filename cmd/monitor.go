@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -38,7 +41,19 @@ Key bindings:
 Mouse support:
   Click          Select panel/row
   Double-click   Open issue details
-  Scroll wheel   Scroll hovered panel`,
+  Scroll wheel   Scroll hovered panel
+  Drag divider   Resize panels (+/- resize the active panel from the keyboard)
+  --no-mouse     Keyboard only, for terminal multiplexers that break with
+                 mouse reporting. Also off with TD_MOUSE=0 or "mouse": false
+                 in ~/.config/td/config.json.
+
+Accessibility:
+  --accessible   High-contrast styles without color cues, ASCII instead of
+                 symbols and box drawing, and a footer announcing the focused
+                 panel and row. Also on with TD_ACCESSIBLE=1 or "accessible":
+                 true in ~/.config/td/config.json.
+  --plain        No TUI: print the board as plain lines, then one line per
+                 change each refresh, for screen readers and logs.`,
 	GroupID: "system",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		baseDir := getBaseDir()
@@ -61,7 +76,39 @@ Mouse support:
 			interval = 2 * time.Second
 		}
 
+		useScoreFormula(baseDir)
+
+		if plain, _ := cmd.Flags().GetBool("plain"); plain {
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			session.StartHeartbeat(ctx, database, sess.ID)
+			return monitor.RunPlain(ctx, database, sess.ID, interval, os.Stdout)
+		}
+
 		model := monitor.NewModel(database, sess.ID, interval, versionStr, baseDir)
+		accessible := syncconfig.GetAccessible()
+		if cmd.Flags().Changed("accessible") {
+			accessible, _ = cmd.Flags().GetBool("accessible")
+		}
+		if accessible {
+			model.EnableAccessible()
+		}
+		mouse := syncconfig.GetMouse()
+		if cmd.Flags().Changed("no-mouse") {
+			noMouse, _ := cmd.Flags().GetBool("no-mouse")
+			mouse = !noMouse
+		}
+		if !mouse {
+			model.DisableMouse()
+		}
+		if ref, _ := cmd.Flags().GetString("view"); ref != "" {
+			v, err := database.GetView(ref)
+			if err != nil {
+				output.Error("%v", err)
+				return err
+			}
+			model.OpenView(v)
+		}
 
 		// Enable periodic auto-sync in monitor if authenticated and linked
 		syncInterval := time.Duration(0)
@@ -79,6 +126,10 @@ Mouse support:
 		// can stall under certain terminal/PTY conditions, so we run sync outside
 		// the event loop to guarantee it fires reliably.
 		ctx, cancelSync := context.WithCancel(context.Background())
+
+		// Keep this session marked active while the monitor is open
+		session.StartHeartbeat(ctx, database, sess.ID)
+
 		if syncInterval > 0 {
 			go func() {
 				ticker := time.NewTicker(syncInterval)
@@ -94,7 +145,11 @@ Mouse support:
 			}()
 		}
 
-		p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseAllMotion())
+		opts := []tea.ProgramOption{tea.WithAltScreen()}
+		if mouse {
+			opts = append(opts, tea.WithMouseAllMotion())
+		}
+		p := tea.NewProgram(model, opts...)
 		if _, err := p.Run(); err != nil {
 			cancelSync()
 			return fmt.Errorf("error running monitor: %w", err)
@@ -108,4 +163,8 @@ Mouse support:
 func init() {
 	rootCmd.AddCommand(monitorCmd)
 	monitorCmd.Flags().Duration("interval", 2*time.Second, "Refresh interval (default 2s)")
+	monitorCmd.Flags().Bool("accessible", false, "High-contrast, ASCII-only TUI that announces focus")
+	monitorCmd.Flags().Bool("plain", false, "Print plain text lines instead of the TUI")
+	monitorCmd.Flags().Bool("no-mouse", false, "Keyboard only: don't turn on mouse reporting")
+	monitorCmd.Flags().String("view", "", "Open on a saved view's query and sort (see td views)")
 }
@@ -350,12 +350,19 @@ var noteUnarchiveCmd = &cobra.Command{
 // openEditorForContent opens the user's default editor with the given initial
 // content and returns the edited result. Uses $EDITOR or falls back to "vi".
 func openEditorForContent(initial string) (string, error) {
+	edited, err := editText(initial, "td-note-*.md")
+	return strings.TrimRight(edited, "\n"), err
+}
+
+// editText opens the user's default editor on initial, in a temp file named
+// by pattern, and returns the saved text exactly as written.
+func editText(initial, pattern string) (string, error) {
 	editor := os.Getenv("EDITOR")
 	if editor == "" {
 		editor = "vi"
 	}
 
-	tmpFile, err := os.CreateTemp("", "td-note-*.md")
+	tmpFile, err := os.CreateTemp("", pattern)
 	if err != nil {
 		return "", fmt.Errorf("create temp file: %w", err)
 	}
@@ -386,7 +393,7 @@ func openEditorForContent(initial string) (string, error) {
 		return "", fmt.Errorf("read edited file: %w", err)
 	}
 
-	return strings.TrimRight(string(data), "\n"), nil
+	return string(data), nil
 }
 
 func init() {
@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/notify"
+	"github.com/marcus/td/internal/syncconfig"
+	"github.com/spf13/cobra"
+)
+
+var notifyCmd = &cobra.Command{
+	Use:   "notify",
+	Short: "Manage notifications from td monitor",
+	Long: `Configure notifications raised by td monitor.
+
+Events:
+  review   an issue became reviewable by your session
+  mention  a log or comment mentions @<session-id> or @<session-name>
+  p0       a new P0 issue was created
+  reminder a reminder set with td remind came due
+
+By default notifications go to the desktop (osascript on macOS, notify-send
+on Linux). Use td notify route to send events to stdout, a webhook, Slack
+or email instead.`,
+	GroupID: "system",
+}
+
+// loadNotifyConfig returns the project or global notify config for editing
+func loadNotifyConfig(global bool) (*models.NotifyConfig, func(*models.NotifyConfig) error, error) {
+	if global {
+		gcfg, err := syncconfig.LoadConfig()
+		if err != nil {
+			return nil, nil, fmt.Errorf("load global config: %w", err)
+		}
+		save := func(nc *models.NotifyConfig) error {
+			gcfg.Notify = nc
+			if err := syncconfig.SaveConfig(gcfg); err != nil {
+				return fmt.Errorf("save global config: %w", err)
+			}
+			return nil
+		}
+		return gcfg.Notify, save, nil
+	}
+
+	baseDir := getBaseDir()
+	cfg, err := config.Load(baseDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load config: %w", err)
+	}
+	save := func(nc *models.NotifyConfig) error {
+		cfg.Notify = nc
+		if err := config.Save(baseDir, cfg); err != nil {
+			return fmt.Errorf("save config: %w", err)
+		}
+		return nil
+	}
+	return cfg.Notify, save, nil
+}
+
+var notifyOnCmd = &cobra.Command{
+	Use:   "on",
+	Short: "Enable notifications (optionally limit --events and set --quiet hours)",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		global, _ := cmd.Flags().GetBool("global")
+
+		nc, save, err := loadNotifyConfig(global)
+		if err != nil {
+			return err
+		}
+		if nc == nil {
+			nc = &models.NotifyConfig{}
+		}
+		nc.Enabled = true
+
+		if cmd.Flags().Changed("events") {
+			events, _ := cmd.Flags().GetStringSlice("events")
+			nc.Events = nil
+			for _, name := range events {
+				ev, err := notify.ParseEvent(name)
+				if err != nil {
+					return err
+				}
+				nc.Events = append(nc.Events, string(ev))
+			}
+		}
+		if cmd.Flags().Changed("quiet") {
+			quiet, _ := cmd.Flags().GetString("quiet")
+			q, err := notify.ParseQuietHours(quiet)
+			if err != nil {
+				return err
+			}
+			nc.QuietHours = q.String()
+		}
+
+		if err := save(nc); err != nil {
+			return err
+		}
+		fmt.Println("Desktop notifications enabled (restart td monitor to apply).")
+		printNotifySettings(notify.FromConfig(nc))
+		return nil
+	},
+}
+
+var notifyOffCmd = &cobra.Command{
+	Use:   "off",
+	Short: "Disable notifications",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		global, _ := cmd.Flags().GetBool("global")
+
+		nc, save, err := loadNotifyConfig(global)
+		if err != nil {
+			return err
+		}
+		if nc == nil {
+			nc = &models.NotifyConfig{}
+		}
+		nc.Enabled = false
+		if err := save(nc); err != nil {
+			return err
+		}
+		fmt.Println("Desktop notifications disabled.")
+		return nil
+	},
+}
+
+var notifyStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show effective notification settings",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s := notify.Load(getBaseDir())
+		if !s.Enabled {
+			fmt.Println("Notifications: disabled")
+			return nil
+		}
+		fmt.Println("Notifications: enabled")
+		printNotifySettings(s)
+		return nil
+	},
+}
+
+var notifyTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Send a test notification through the configured routes",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, _ := cmd.Flags().GetString("event")
+		ev, err := notify.ParseEvent(name)
+		if err != nil {
+			return err
+		}
+		dispatcher, err := loadNotifyDispatcher()
+		if err != nil {
+			return err
+		}
+		if !dispatcher.Routes(ev) {
+			return fmt.Errorf("no route delivers %s events (see td notify route list)", ev)
+		}
+		msg := notify.Message{Event: ev, Title: "td", Body: "Test notification from td", Time: time.Now()}
+		if err := dispatcher.Send(msg); err != nil {
+			return fmt.Errorf("notification failed: %w", err)
+		}
+		fmt.Println("Sent.")
+		return nil
+	},
+}
+
+func printNotifySettings(s notify.Settings) {
+	var events []string
+	for _, ev := range notify.AllEvents {
+		if s.Events[ev] {
+			events = append(events, string(ev))
+		}
+	}
+	fmt.Printf("Events: %s\n", strings.Join(events, ", "))
+	if q := s.Quiet.String(); q != "" {
+		fmt.Printf("Quiet hours: %s\n", q)
+	} else {
+		fmt.Println("Quiet hours: none")
+	}
+}
+
+func init() {
+	notifyOnCmd.Flags().StringSlice("events", nil, "Events to notify on: review, mention, p0, reminder, job_failed (default all)")
+	notifyOnCmd.Flags().String("quiet", "", "Quiet hours in local time, e.g. 22:00-08:00 (empty to clear)")
+	notifyOnCmd.Flags().BoolP("global", "g", false, "Set in global config (~/.config/td/config.json)")
+	notifyTestCmd.Flags().String("event", string(notify.EventReview), "Event to send as, which selects the routes used")
+	notifyOffCmd.Flags().BoolP("global", "g", false, "Set in global config (~/.config/td/config.json)")
+	notifyCmd.AddCommand(notifyOnCmd, notifyOffCmd, notifyStatusCmd, notifyTestCmd)
+	rootCmd.AddCommand(notifyCmd)
+}
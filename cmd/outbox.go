@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/output"
+	"github.com/marcus/td/internal/syncclient"
+	"github.com/marcus/td/internal/syncconfig"
+	"github.com/spf13/cobra"
+)
+
+var outboxCmd = &cobra.Command{
+	Use:   "outbox",
+	Short: "Show and push local changes waiting for the sync server",
+	Long: `Every change is written locally first and queued for the sync server.
+When the server cannot be reached the queue keeps the changes, and the
+next sync or auto-sync pushes them. Each change carries its own client
+action ID, so a change pushed twice is stored once.
+
+td outbox list shows what is queued, when a push was last tried and how
+it failed, and changes the server refused. td outbox flush pushes the
+queue now.`,
+	GroupID: "system",
+}
+
+var outboxListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List changes not yet pushed",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		limit, _ := cmd.Flags().GetInt("limit")
+		entries, err := database.ListOutbox(limit)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		pending, err := database.CountPendingEvents()
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		state, err := database.GetSyncState()
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			result := map[string]interface{}{
+				"pending": pending,
+				"linked":  state != nil,
+				"entries": entries,
+			}
+			if state != nil {
+				result["last_push_error"] = state.LastPushError
+				result["last_push_attempt_at"] = state.LastPushAttemptAt
+			}
+			data, _ := json.MarshalIndent(result, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		switch {
+		case state == nil:
+			fmt.Println("Not linked to a sync server; changes stay local (see td sync-project link).")
+		case state.LastPushAttemptAt == nil:
+			fmt.Println("No push attempted yet.")
+		case state.LastPushError != "":
+			fmt.Printf("Last push %s ago failed: %s\n", time.Since(*state.LastPushAttemptAt).Truncate(time.Second), state.LastPushError)
+		default:
+			fmt.Printf("Last push %s ago succeeded.\n", time.Since(*state.LastPushAttemptAt).Truncate(time.Second))
+		}
+		if pending == 0 {
+			fmt.Println("Outbox is empty.")
+			return nil
+		}
+		fmt.Printf("%d changes queued:\n", pending)
+		fmt.Printf("  %-8s %-19s %-14s %-22s %s\n", "ID", "QUEUED", "ACTION", "ENTITY", "STATUS")
+		rejected := 0
+		for _, e := range entries {
+			status := "pending"
+			if e.Rejection != "" {
+				rejected++
+				status = fmt.Sprintf("rejected ×%d: %s", e.Attempts, e.Rejection)
+			}
+			fmt.Printf("  %-8d %-19s %-14s %-22s %s\n", e.ClientActionID, e.QueuedAt.Local().Format("2006-01-02 15:04:05"),
+				e.ActionType, e.EntityType+" "+e.EntityID, status)
+		}
+		if int64(len(entries)) < pending {
+			fmt.Printf("  ... %d more (--limit 0 for all)\n", pending-int64(len(entries)))
+		}
+		if rejected > 0 {
+			output.Warning("%d changes were rejected by the server and will be retried on every push", rejected)
+		}
+		return nil
+	},
+}
+
+var outboxFlushCmd = &cobra.Command{
+	Use:   "flush",
+	Short: "Push queued changes to the sync server now",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		if !syncconfig.IsAuthenticated() {
+			output.Error("not logged in (run: td auth login)")
+			return fmt.Errorf("not authenticated")
+		}
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		state, err := database.GetSyncState()
+		if err != nil {
+			output.Error("get sync state: %v", err)
+			return err
+		}
+		if state == nil {
+			output.Error("project not linked (run: td sync-project link <id>)")
+			return fmt.Errorf("not linked")
+		}
+		deviceID, err := syncconfig.GetDeviceID()
+		if err != nil {
+			output.Error("get device id: %v", err)
+			return err
+		}
+		client := syncclient.New(syncconfig.GetServerURL(), syncconfig.GetAPIKey(), deviceID)
+
+		err = runPush(database, client, state, deviceID)
+		if recErr := database.RecordPushAttempt(err); recErr != nil {
+			slog.Debug("outbox: record push attempt", "err", recErr)
+		}
+		if err != nil {
+			output.Info("changes stay queued; td outbox flush again once the server is reachable")
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	outboxListCmd.Flags().Int("limit", 50, "Max changes to list (0 for all)")
+	outboxListCmd.Flags().Bool("json", false, "Output as JSON")
+	outboxCmd.AddCommand(outboxListCmd, outboxFlushCmd)
+	rootCmd.AddCommand(outboxCmd)
+}
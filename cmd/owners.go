@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/output"
+	"github.com/marcus/td/internal/owners"
+	"github.com/spf13/cobra"
+)
+
+var ownersCmd = &cobra.Command{
+	Use:   "owners",
+	Short: "Map repository paths to labels and reviewers",
+	Long: `Show how .todos/owners maps paths to labels and owners. The file works
+like CODEOWNERS: one rule per line, a path pattern followed by #labels and
+@owners, and the last matching rule wins.
+
+  *.sql                 #db
+  /internal/sync/       #sync @alice
+  docs/                 #docs @docs-team
+
+A pattern with a slash in it is anchored at the repository root; one
+without matches a file or directory name at any depth. A pattern matching
+a directory covers everything inside it.
+
+td link adds the labels of the files it links to the issue. The owners of
+an issue's linked files are suggested as its reviewers, matched to
+sessions by name (td session <name>) or ID.`,
+	Example: `  td owners match internal/sync/engine.go docs/intro.md
+  td owners reviewers td-a1b2
+  td owners reviewers`,
+	GroupID: "files",
+}
+
+var ownersMatchCmd = &cobra.Command{
+	Use:   "match <path>...",
+	Short: "Show the rule, labels and owners for paths",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		m, err := owners.Load(getBaseDir())
+		if err != nil {
+			output.Error("%s: %v", owners.Path(getBaseDir()), err)
+			return err
+		}
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			result := make(map[string]*owners.Rule, len(args))
+			for _, p := range args {
+				result[p] = m.Match(p)
+			}
+			data, _ := json.MarshalIndent(result, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		for _, p := range args {
+			rule := m.Match(p)
+			if rule == nil {
+				fmt.Printf("%s  (no rule)\n", p)
+				continue
+			}
+			var parts []string
+			for _, l := range rule.Labels {
+				parts = append(parts, "#"+l)
+			}
+			for _, o := range rule.Owners {
+				parts = append(parts, "@"+o)
+			}
+			fmt.Printf("%s  %s  (line %d: %s)\n", p, strings.Join(parts, " "), rule.Line, rule.Pattern)
+		}
+		return nil
+	},
+}
+
+var ownersReviewersCmd = &cobra.Command{
+	Use:   "reviewers [issue-id]",
+	Short: "Suggest reviewers from the owners of an issue's files",
+	Long: `Suggest reviewers for an issue from the owners of its linked files,
+those owning the most files first. Without an issue, every issue in review
+is covered. Sessions that worked on the implementation are not offered.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		baseDir := getBaseDir()
+		m, err := owners.Load(baseDir)
+		if err != nil {
+			output.Error("%s: %v", owners.Path(baseDir), err)
+			return err
+		}
+
+		database, err := db.Open(baseDir)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		var issues []models.Issue
+		if len(args) == 1 {
+			issue, err := database.GetIssue(args[0])
+			if err != nil {
+				output.Error("%v", err)
+				return err
+			}
+			issues = append(issues, *issue)
+		} else {
+			issues, err = database.ListIssues(db.ListIssuesOptions{Status: []models.Status{models.StatusInReview}})
+			if err != nil {
+				output.Error("%v", err)
+				return err
+			}
+		}
+
+		type issueReviewers struct {
+			IssueID   string            `json:"issue_id"`
+			Title     string            `json:"title"`
+			Reviewers []owners.Reviewer `json:"reviewers"`
+		}
+		result := []issueReviewers{}
+		for i := range issues {
+			reviewers, err := owners.Reviewers(database, m, &issues[i])
+			if err != nil {
+				output.Error("%v", err)
+				return err
+			}
+			result = append(result, issueReviewers{IssueID: issues[i].ID, Title: issues[i].Title, Reviewers: reviewers})
+		}
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			data, _ := json.MarshalIndent(result, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if len(result) == 0 {
+			output.Info("No issues in review")
+			return nil
+		}
+		for _, ir := range result {
+			fmt.Printf("%s  %s\n", ir.IssueID, ir.Title)
+			if len(ir.Reviewers) == 0 {
+				fmt.Println("  no owners for its linked files")
+				continue
+			}
+			for _, r := range ir.Reviewers {
+				sessions := "no eligible session"
+				if len(r.Sessions) > 0 {
+					sessions = strings.Join(r.Sessions, ", ")
+				}
+				noun := "files"
+				if len(r.Files) == 1 {
+					noun = "file"
+				}
+				fmt.Printf("  @%-16s %d %s  %s\n", r.Owner, len(r.Files), noun, sessions)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	ownersMatchCmd.Flags().Bool("json", false, "Output as JSON")
+	ownersReviewersCmd.Flags().Bool("json", false, "Output as JSON")
+	ownersCmd.AddCommand(ownersMatchCmd, ownersReviewersCmd)
+	rootCmd.AddCommand(ownersCmd)
+}
@@ -0,0 +1,286 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/output"
+	"github.com/marcus/td/internal/plan"
+	"github.com/marcus/td/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var planCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Preview bulk changes and apply them explicitly",
+	Long: `Plans are a checkpoint for wide-reaching changes. Creating a plan
+records exactly which issues will change and shows a preview diff;
+nothing is modified until someone runs td plan apply <plan-id> before
+the plan expires.
+
+Applying re-checks every issue: anything that changed since the plan was
+made is skipped, not overwritten.`,
+	Example: `  td plan close --query 'labels ~ stale AND updated < -90d'
+  td plan relabel frontend ui
+  td plan carryover s12 s13
+  td plan apply pl-1a2b3c4d`,
+	GroupID: "workflow",
+}
+
+var planCloseCmd = &cobra.Command{
+	Use:   "close [issue-id...]",
+	Short: "Plan closing issues by ID or TDQ query",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tdq, _ := cmd.Flags().GetString("query")
+		if len(args) == 0 && tdq == "" {
+			err := fmt.Errorf("give issue IDs or --query")
+			output.Error("%v", err)
+			return err
+		}
+		return createPlan(cmd, func(database *db.DB, sessionID string) (*models.Plan, error) {
+			return plan.Close(database, args, tdq, sessionID)
+		})
+	},
+}
+
+var planRelabelCmd = &cobra.Command{
+	Use:   "relabel <from> <to>",
+	Short: "Plan renaming a label on every issue that has it",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return createPlan(cmd, func(database *db.DB, sessionID string) (*models.Plan, error) {
+			return plan.Relabel(database, args[0], args[1], sessionID)
+		})
+	},
+}
+
+var planCarryOverCmd = &cobra.Command{
+	Use:     "carryover <from-sprint> <to-sprint>",
+	Aliases: []string{"carry-over"},
+	Short:   "Plan moving unclosed issues to the next sprint",
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return createPlan(cmd, func(database *db.DB, sessionID string) (*models.Plan, error) {
+			return plan.CarryOver(database, args[0], args[1], sessionID)
+		})
+	},
+}
+
+// createPlan builds, saves and previews a plan
+func createPlan(cmd *cobra.Command, build func(*db.DB, string) (*models.Plan, error)) error {
+	cmd.SilenceUsage = true
+	ttl, _ := cmd.Flags().GetDuration("ttl")
+
+	database, err := db.Open(getBaseDir())
+	if err != nil {
+		output.Error("%v", err)
+		return err
+	}
+	defer database.Close()
+
+	sess, err := session.GetOrCreate(database)
+	if err != nil {
+		output.Error("%v", err)
+		return err
+	}
+
+	p, err := build(database, sess.ID)
+	if errors.Is(err, plan.ErrNoChanges) {
+		output.Info("Nothing to change; no plan created")
+		return nil
+	}
+	if err != nil {
+		output.Error("%v", err)
+		return err
+	}
+	if err := plan.Save(database, p, ttl); err != nil {
+		output.Error("%v", err)
+		return err
+	}
+
+	if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+		data, _ := json.MarshalIndent(p, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+	printPlan(p)
+	fmt.Printf("\nApply with: td plan apply %s\n", p.ID)
+	return nil
+}
+
+var planShowCmd = &cobra.Command{
+	Use:   "show <plan-id>",
+	Short: "Show a plan's preview diff",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		p, err := database.GetPlan(args[0])
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			data, _ := json.MarshalIndent(p, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+		printPlan(p)
+		return nil
+	},
+}
+
+var planListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List pending plans",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		all, _ := cmd.Flags().GetBool("all")
+		plans, err := database.ListPlans(!all)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			if plans == nil {
+				plans = []models.Plan{}
+			}
+			data, _ := json.MarshalIndent(plans, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+		if len(plans) == 0 {
+			output.Info("No pending plans")
+			return nil
+		}
+		now := time.Now()
+		for _, p := range plans {
+			fmt.Printf("%s  %-9s  %s  %s\n", p.ID, planState(&p, now), p.Summary, p.SessionID)
+		}
+		return nil
+	},
+}
+
+var planApplyCmd = &cobra.Command{
+	Use:   "apply <plan-id>",
+	Short: "Apply a pending plan",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		sess, err := session.GetOrCreate(database)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		res, err := plan.Apply(database, args[0], sess.ID)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			data, _ := json.MarshalIndent(res, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+		for _, s := range res.Skipped {
+			output.Warning("skipped %s: %s", s.IssueID, s.Reason)
+		}
+		output.Success("Applied %s: %d changed, %d skipped", res.Plan.ID, len(res.Applied), len(res.Skipped))
+		return nil
+	},
+}
+
+var planDiscardCmd = &cobra.Command{
+	Use:     "discard <plan-id>",
+	Aliases: []string{"rm", "cancel"},
+	Short:   "Discard a pending plan",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		if err := database.DiscardPlan(args[0]); err != nil {
+			if errors.Is(err, db.ErrPlanNotPending) {
+				err = fmt.Errorf("plan %s is not pending", args[0])
+			}
+			output.Error("%v", err)
+			return err
+		}
+		output.Success("Discarded plan %s", args[0])
+		return nil
+	},
+}
+
+// printPlan prints a plan header and a -/+ diff per issue
+func printPlan(p *models.Plan) {
+	now := time.Now()
+	fmt.Printf("PLAN %s: %s\n", p.ID, p.Summary)
+	fmt.Printf("Created by %s, %s", p.SessionID, planState(p, now))
+	if p.Status == models.PlanPending && !p.Expired(now) {
+		fmt.Printf(", expires %s (in %s)", p.ExpiresAt.Local().Format("2006-01-02 15:04"), p.ExpiresAt.Sub(now).Round(time.Minute))
+	}
+	if p.AppliedAt != nil {
+		fmt.Printf(" at %s by %s", p.AppliedAt.Local().Format("2006-01-02 15:04"), p.AppliedBy)
+	}
+	fmt.Println()
+
+	for _, c := range p.Changes {
+		fmt.Printf("\n%s %s\n", c.IssueID, c.Title)
+		fmt.Printf("  - %s: %s\n", c.Field, c.From)
+		fmt.Printf("  + %s: %s\n", c.Field, c.To)
+	}
+}
+
+// planState reports pending plans past their TTL as expired
+func planState(p *models.Plan, now time.Time) string {
+	if p.Expired(now) {
+		return "expired"
+	}
+	return string(p.Status)
+}
+
+func init() {
+	for _, c := range []*cobra.Command{planCloseCmd, planRelabelCmd, planCarryOverCmd} {
+		c.Flags().Duration("ttl", plan.DefaultTTL, "How long the plan can be applied")
+		c.Flags().Bool("json", false, "Output as JSON")
+	}
+	planCloseCmd.Flags().StringP("query", "q", "", "TDQ query selecting issues to close")
+	planShowCmd.Flags().Bool("json", false, "Output as JSON")
+	planListCmd.Flags().Bool("all", false, "Include applied and discarded plans")
+	planListCmd.Flags().Bool("json", false, "Output as JSON")
+	planApplyCmd.Flags().Bool("json", false, "Output as JSON")
+
+	planCmd.AddCommand(planCloseCmd, planRelabelCmd, planCarryOverCmd, planShowCmd, planListCmd, planApplyCmd, planDiscardCmd)
+	rootCmd.AddCommand(planCmd)
+}
@@ -0,0 +1,333 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/output"
+	"github.com/marcus/td/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var pokerCmd = &cobra.Command{
+	Use:   "poker",
+	Short: "Estimate issues together by blind voting",
+	Long: `Planning poker without a separate tool. Open a round over the issues to
+estimate; each session votes story points (1, 2, 3, 5, 8, 13, 21) without
+seeing the others' votes. Reveal shows every vote, then record the agreed
+estimate per issue: it becomes the issue's points, and votes that differed
+are kept as dissent in a decision log on the issue. The round closes when
+every issue has an estimate.
+
+Votes can also be cast over the API and from the monitor's action menu.
+Rounds and votes stay in the local database; recorded estimates sync.`,
+	GroupID: "workflow",
+}
+
+var pokerStartCmd = &cobra.Command{
+	Use:     "start <issue-id>...",
+	Short:   "Open a round of blind voting",
+	Example: `  td poker start td-a1b2 td-c3d4 --title "Sprint 12 refinement"`,
+	Args:    cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		database, sess, err := openPokerDB()
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		title, _ := cmd.Flags().GetString("title")
+		round, err := database.CreatePokerRound(title, args, sess.ID)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		output.Success("Opened poker round %s for %d issue(s)", round.ID, len(round.Items))
+		fmt.Printf("Vote with: td poker vote <issue-id> <points>\n")
+		return nil
+	},
+}
+
+var pokerVoteCmd = &cobra.Command{
+	Use:   "vote <issue-id> <points>",
+	Short: "Cast or change your blind vote on an issue",
+	Long: `Cast your vote on an issue in an open round. Voting again replaces your
+vote. Without --round, the open round containing the issue is used.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		points, err := strconv.Atoi(args[1])
+		if err != nil {
+			err = fmt.Errorf("points must be a number: %s", args[1])
+			output.Error("%v", err)
+			return err
+		}
+		database, sess, err := openPokerDB()
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		roundID, _ := cmd.Flags().GetString("round")
+		if roundID == "" {
+			if roundID, err = openPokerRoundFor(database, args[0]); err != nil {
+				output.Error("%v", err)
+				return err
+			}
+		}
+		if err := database.CastPokerVote(roundID, args[0], sess.ID, points); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		output.Success("Voted %d on %s in %s", points, db.NormalizeIssueID(args[0]), roundID)
+		return nil
+	},
+}
+
+// openPokerRoundFor finds the one open round that has an issue
+func openPokerRoundFor(database *db.DB, issueID string) (string, error) {
+	rounds, err := database.OpenPokerRoundsForIssue(issueID)
+	if err != nil {
+		return "", err
+	}
+	switch len(rounds) {
+	case 0:
+		return "", fmt.Errorf("no open poker round for %s", db.NormalizeIssueID(issueID))
+	case 1:
+		return rounds[0].ID, nil
+	}
+	ids := make([]string, len(rounds))
+	for i, r := range rounds {
+		ids[i] = r.ID
+	}
+	return "", fmt.Errorf("%s is in several open rounds (%s): pass --round", db.NormalizeIssueID(issueID), strings.Join(ids, ", "))
+}
+
+var pokerRevealCmd = &cobra.Command{
+	Use:   "reveal <round-id>",
+	Short: "End voting and show every vote",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		database, sess, err := openPokerDB()
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		round, err := database.RevealPokerRound(args[0])
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		renderPokerRound(database, round, sess.ID)
+		return nil
+	},
+}
+
+var pokerRecordCmd = &cobra.Command{
+	Use:   "record <round-id> <issue-id> [points]",
+	Short: "Record the agreed estimate for an issue",
+	Long: `Record the estimate the team agreed on as the issue's story points.
+Points may be left out when every vote was the same. Votes that differ are
+kept as dissent; --note adds why.`,
+	Example: `  td poker record pk-1a2b3c4d td-a1b2 5 --note "8 if the API changes"
+  td poker record pk-1a2b3c4d td-c3d4`,
+	Args: cobra.RangeArgs(2, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		points := 0
+		if len(args) == 3 {
+			var err error
+			if points, err = strconv.Atoi(args[2]); err != nil {
+				err = fmt.Errorf("points must be a number: %s", args[2])
+				output.Error("%v", err)
+				return err
+			}
+		}
+		database, sess, err := openPokerDB()
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		note, _ := cmd.Flags().GetString("note")
+		item, err := database.RecordPokerEstimate(args[0], args[1], points, note, sess.ID)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		output.Success("Recorded %d points for %s", item.Points, item.IssueID)
+		if item.Dissent != "" {
+			fmt.Printf("Dissent: %s\n", item.Dissent)
+		}
+		return nil
+	},
+}
+
+var pokerShowCmd = &cobra.Command{
+	Use:   "show <round-id>",
+	Short: "Show a round; votes stay hidden until it is revealed",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		database, sess, err := openPokerDB()
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		round, err := database.GetPokerRound(args[0])
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		round.Blind(sess.ID)
+		if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+			data, _ := json.MarshalIndent(round, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+		renderPokerRound(database, round, sess.ID)
+		return nil
+	},
+}
+
+var pokerListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List open and revealed rounds",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		database, sess, err := openPokerDB()
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		all, _ := cmd.Flags().GetBool("all")
+		rounds, err := database.ListPokerRounds(all)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		for i := range rounds {
+			rounds[i].Blind(sess.ID)
+		}
+
+		if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+			data, _ := json.MarshalIndent(rounds, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+		if len(rounds) == 0 {
+			fmt.Println("No poker rounds (td poker start <issue-id>...)")
+			return nil
+		}
+		for _, r := range rounds {
+			recorded := 0
+			for _, item := range r.Items {
+				if item.RecordedAt != nil {
+					recorded++
+				}
+			}
+			fmt.Printf("%s  [%s]  %d/%d estimated  %s\n", r.ID, r.Status, recorded, len(r.Items), r.Title)
+		}
+		return nil
+	},
+}
+
+var pokerCloseCmd = &cobra.Command{
+	Use:   "close <round-id>",
+	Short: "Close a round without estimating every issue",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		database, _, err := openPokerDB()
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		if err := database.ClosePokerRound(args[0]); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		output.Success("Closed poker round %s", args[0])
+		return nil
+	},
+}
+
+// openPokerDB opens the project database and the current session
+func openPokerDB() (*db.DB, *session.Session, error) {
+	database, err := db.Open(getBaseDir())
+	if err != nil {
+		output.Error("%v", err)
+		return nil, nil, err
+	}
+	sess, err := session.GetOrCreate(database)
+	if err != nil {
+		database.Close()
+		output.Error("%v", err)
+		return nil, nil, err
+	}
+	return database, sess, nil
+}
+
+// renderPokerRound prints a round's issues with their votes as viewer
+// may see them
+func renderPokerRound(database *db.DB, round *models.PokerRound, viewer string) {
+	header := fmt.Sprintf("%s [%s]", round.ID, round.Status)
+	if round.Title != "" {
+		header += "  " + round.Title
+	}
+	fmt.Println(header)
+
+	for _, item := range round.Items {
+		if issue, err := database.GetIssue(item.IssueID); err == nil {
+			fmt.Printf("  %s\n", output.FormatIssueShort(issue))
+		} else {
+			fmt.Printf("  %s\n", item.IssueID)
+		}
+
+		var votes []string
+		for _, v := range item.Votes {
+			switch {
+			case v.Hidden:
+				votes = append(votes, "?")
+			case v.SessionID == viewer && round.Status == models.PokerOpen:
+				votes = append(votes, fmt.Sprintf("%d (you)", v.Points))
+			default:
+				votes = append(votes, strconv.Itoa(v.Points))
+			}
+		}
+		if len(votes) == 0 {
+			votes = []string{"none yet"}
+		}
+		fmt.Printf("      votes: %s\n", strings.Join(votes, ", "))
+		if item.RecordedAt != nil {
+			line := fmt.Sprintf("      estimate: %d", item.Points)
+			if item.Dissent != "" {
+				line += "  (dissent: " + item.Dissent + ")"
+			}
+			fmt.Println(line)
+		}
+	}
+}
+
+func init() {
+	pokerStartCmd.Flags().String("title", "", "what the round is for")
+	pokerVoteCmd.Flags().String("round", "", "round to vote in (default: the open round with the issue)")
+	pokerRecordCmd.Flags().String("note", "", "why votes differed, kept with the dissent")
+	pokerShowCmd.Flags().Bool("json", false, "output as JSON")
+	pokerListCmd.Flags().Bool("all", false, "include closed rounds")
+	pokerListCmd.Flags().Bool("json", false, "output as JSON")
+
+	pokerCmd.AddCommand(pokerStartCmd, pokerVoteCmd, pokerRevealCmd, pokerRecordCmd, pokerShowCmd, pokerListCmd, pokerCloseCmd)
+	rootCmd.AddCommand(pokerCmd)
+}
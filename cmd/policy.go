@@ -0,0 +1,687 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/hookscripts"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/output"
+	"github.com/marcus/td/internal/thrash"
+	"github.com/marcus/td/internal/triage"
+	"github.com/marcus/td/internal/workflow"
+	"github.com/spf13/cobra"
+)
+
+var policyCmd = &cobra.Command{
+	Use:     "policy",
+	Short:   "Configure project workflow policies",
+	GroupID: "system",
+}
+
+var policyShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the project's workflow policies",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tc, err := config.GetThrashConfig(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		cfg := thrash.WithDefaults(tc)
+		hooks, err := config.GetPolicyHooks(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		required, err := config.GetRequiredFields(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		checklists, err := config.GetReviewChecklists(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		readyGate, err := config.GetReadyGate(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		inboxSources, err := config.GetInboxSources(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		closedImmutable, err := config.GetClosedImmutable(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		inheritance, err := config.GetPriorityInheritance(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			if hooks == nil {
+				hooks = []models.PolicyHookConfig{}
+			}
+			if required == nil {
+				required = []models.RequiredFieldsRule{}
+			}
+			if checklists == nil {
+				checklists = []models.ReviewChecklist{}
+			}
+			if readyGate == nil {
+				readyGate = []string{}
+			}
+			if inboxSources == nil {
+				inboxSources = []string{}
+			}
+			data, _ := json.MarshalIndent(map[string]interface{}{
+				"thrash":               cfg,
+				"hooks":                hooks,
+				"required_fields":      required,
+				"review_checklists":    checklists,
+				"ready_gate":           readyGate,
+				"inbox_sources":        inboxSources,
+				"closed_immutable":     closedImmutable,
+				"priority_inheritance": inheritance,
+				"script_hooks":         hookScriptsStatus(getBaseDir()),
+			}, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		fmt.Print(output.SectionHeader("Anti-thrash guard"))
+		fmt.Printf("  Mode:          %s\n", cfg.Mode)
+		fmt.Printf("  Window:        %d min\n", cfg.WindowMinutes)
+		fmt.Printf("  Max reversals: %d per field\n", cfg.MaxReversals)
+		fmt.Print(output.SectionHeader("Closed issues"))
+		if closedImmutable {
+			fmt.Println("  Immutable: reopen or --override to edit")
+		} else {
+			fmt.Println("  Editable")
+		}
+		fmt.Print(output.SectionHeader("Priority inheritance"))
+		if inheritance {
+			fmt.Println("  On: blockers take the priority of the issues waiting on them")
+		} else {
+			fmt.Println("  Off")
+		}
+		fmt.Print(output.SectionHeader("Required fields"))
+		renderRequiredFields(required)
+		fmt.Print(output.SectionHeader("Review checklists"))
+		renderReviewChecklists(checklists)
+		fmt.Print(output.SectionHeader("Definition of ready"))
+		if len(readyGate) == 0 {
+			fmt.Println("  No ready gate")
+		} else {
+			fmt.Printf("  Open issues need %s\n", strings.Join(readyGate, ", "))
+		}
+		fmt.Print(output.SectionHeader("Triage inbox"))
+		if len(inboxSources) == 0 {
+			fmt.Println("  Only issues created with --inbox")
+		} else {
+			fmt.Printf("  Issues created from %s\n", strings.Join(inboxSources, ", "))
+		}
+		fmt.Print(output.SectionHeader("Transition hooks"))
+		renderPolicyHooks(hooks)
+		fmt.Print(output.SectionHeader("Hook scripts"))
+		renderHookScripts(getBaseDir())
+		return nil
+	},
+}
+
+// hookScriptStatus is one event's script in policy show --json
+type hookScriptStatus struct {
+	Event          string `json:"event"`
+	Path           string `json:"path,omitempty"` // empty when the project has no executable script
+	TimeoutSeconds int    `json:"timeout_seconds"`
+	OnFailure      string `json:"on_failure"`
+}
+
+// hookScriptsStatus reports each event's script and effective settings
+func hookScriptsStatus(baseDir string) []hookScriptStatus {
+	var status []hookScriptStatus
+	for _, event := range hookscripts.Events() {
+		timeout, policy := hookscripts.Settings(baseDir, event)
+		status = append(status, hookScriptStatus{
+			Event:          event,
+			Path:           hookscripts.Find(baseDir, event),
+			TimeoutSeconds: int(timeout.Seconds()),
+			OnFailure:      policy,
+		})
+	}
+	return status
+}
+
+func renderHookScripts(baseDir string) {
+	for _, s := range hookScriptsStatus(baseDir) {
+		if s.Path == "" {
+			fmt.Printf("  %-16s none\n", s.Event)
+			continue
+		}
+		fmt.Printf("  %-16s %s (%ds timeout, on failure: %s)\n", s.Event, s.Path, s.TimeoutSeconds, s.OnFailure)
+	}
+}
+
+var policyRequireCmd = &cobra.Command{
+	Use:   "require <type|any> <status> [field...]",
+	Short: "Require fields before issues move to a status",
+	Long: `Sets the fields an issue of a type must have before it can move to a
+status, replacing any earlier rule for that type and status. Use "any" to
+cover every type. With no fields, the rule is removed.
+
+Fields: ` + strings.Join(workflow.RequirableFields, ", ") + `
+
+Enforced for the CLI, the monitor and the API alike.`,
+	Example: `  td policy require bug in_progress description
+  td policy require feature in_review acceptance
+  td policy require any closed points labels
+  td policy require bug in_progress         # remove the rule`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rule := models.RequiredFieldsRule{Type: args[0], To: args[1], Fields: args[2:]}
+		if rule.Type == "any" {
+			rule.Type = ""
+		}
+		if err := workflow.ValidateRequiredFieldsRule(rule); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		if err := config.SetRequiredFields(getBaseDir(), rule); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if len(rule.Fields) == 0 {
+			output.Success("Removed required fields for %s", describeRequiredFieldsScope(rule))
+			return nil
+		}
+		output.Success("%s require %s", describeRequiredFieldsScope(rule), strings.Join(rule.Fields, ", "))
+		return nil
+	},
+}
+
+var policyChecklistCmd = &cobra.Command{
+	Use:   "checklist <type|any> [item...]",
+	Short: "Set the items reviewers must acknowledge to approve",
+	Long: `Sets the review checklist for a type, replacing any earlier one. Use
+"any" for items every approval must acknowledge; a type's own items are
+added to those. With no items, the checklist is removed.
+
+Reviewers acknowledge items with td approve --check, by text or number,
+or --check all. The acknowledged items are stored with the approval.
+Enforced for the CLI, the monitor and the API alike.`,
+	Example: `  td policy checklist any "tests added" "docs updated"
+  td policy checklist bug "regression test added" "security considered"
+  td policy checklist bug                   # remove the bug checklist`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		list := models.ReviewChecklist{Type: args[0], Items: args[1:]}
+		if list.Type == "any" {
+			list.Type = ""
+		}
+		if err := workflow.ValidateReviewChecklist(list); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		if err := config.SetReviewChecklist(getBaseDir(), list); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if len(list.Items) == 0 {
+			output.Success("Removed the review checklist for %s", describeChecklistScope(list))
+			return nil
+		}
+		output.Success("Approving %s requires acknowledging %s", describeChecklistScope(list), strings.Join(list.Items, ", "))
+		return nil
+	},
+}
+
+var policyReadyCmd = &cobra.Command{
+	Use:   "ready [criterion...]",
+	Short: "Set what open issues need before they count as ready",
+	Long: `Sets the project's definition of ready. Open, unblocked issues that
+fall short of it are kept out of td ready, td next and the monitor's ready
+section, and listed under needs triage instead (td triage), so agents are
+not handed half-specified work. With no criteria, the gate is removed.
+
+Criteria: ` + strings.Join(workflow.ReadyCriteria, ", ") + `
+
+resolved_parent requires an issue's parent, if it has one, to still exist.`,
+	Example: `  td policy ready points acceptance resolved_parent
+  td policy ready                           # remove the gate`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := workflow.ValidateReadyGate(args); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		if err := config.SetReadyGate(getBaseDir(), args); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if len(args) == 0 {
+			output.Success("Removed the ready gate")
+			return nil
+		}
+		output.Success("Open issues need %s to be ready", strings.Join(args, ", "))
+		return nil
+	},
+}
+
+var policyInboxCmd = &cobra.Command{
+	Use:   "inbox [source...]",
+	Short: "Route newly created issues to the triage inbox",
+	Long: `Sets where newly created issues land in the triage inbox (td inbox)
+rather than straight among the open issues. With no sources, only issues
+created with --inbox go there.
+
+Sources: ` + strings.Join(triage.Sources, ", ") + `
+
+cli is td create, api is POST /v1/issues (bots and scripts using td serve),
+import is td import and CSV imports, and integration is chat commands.`,
+	Example: `  td policy inbox import api integration
+  td policy inbox                           # only --inbox`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := triage.ValidateSources(args); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		if err := config.SetInboxSources(getBaseDir(), args); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if len(args) == 0 {
+			output.Success("Only issues created with --inbox go to the triage inbox")
+			return nil
+		}
+		output.Success("Issues created from %s go to the triage inbox", strings.Join(args, ", "))
+		return nil
+	},
+}
+
+var policyHookCmd = &cobra.Command{
+	Use:   "hook",
+	Short: "Manage hooks that can veto status transitions",
+	Long: `Hooks run before every status change, from the CLI, the monitor and the
+API alike. Any hook that fails vetoes the change with its message.
+
+A hook is either a builtin check:
+  ` + workflow.BuiltinRequireHandoff + `     the issue must have a handoff
+  ` + workflow.BuiltinRequireAcceptance + `  the issue must have acceptance criteria
+
+or a shell command, run from the project root with the issue as JSON on
+stdin and TD_ISSUE_ID, TD_FROM_STATUS, TD_TO_STATUS, TD_SESSION_ID,
+TD_PRIORITY and TD_TYPE set. A non-zero exit vetoes the change, with the
+first line of output as the message.
+
+--to and --priority limit a hook to some target statuses or priorities.`,
+}
+
+var policyHookListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List transition hooks",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		hooks, err := config.GetPolicyHooks(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			if hooks == nil {
+				hooks = []models.PolicyHookConfig{}
+			}
+			return output.JSON(hooks)
+		}
+		renderPolicyHooks(hooks)
+		return nil
+	},
+}
+
+var policyHookAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add or replace a transition hook",
+	Example: `  td policy hook add handoff-before-review --builtin require_handoff --to in_review
+  td policy hook add p0-acceptance --builtin require_acceptance --priority P0
+  td policy hook add tests-pass --command "make test" --to closed --timeout 120`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		hook := models.PolicyHookConfig{Name: args[0]}
+		hook.Builtin, _ = cmd.Flags().GetString("builtin")
+		hook.Command, _ = cmd.Flags().GetString("command")
+		hook.To, _ = cmd.Flags().GetStringSlice("to")
+		hook.Priorities, _ = cmd.Flags().GetStringSlice("priority")
+		hook.TimeoutSeconds, _ = cmd.Flags().GetInt("timeout")
+		for i, p := range hook.Priorities {
+			hook.Priorities[i] = strings.ToUpper(p)
+		}
+		if err := workflow.ValidateHookConfig(hook); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		if err := config.SetPolicyHook(getBaseDir(), hook); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		output.Success("Hook %s: %s", hook.Name, describePolicyHook(hook))
+		return nil
+	},
+}
+
+var policyHookRmCmd = &cobra.Command{
+	Use:     "rm <name>",
+	Aliases: []string{"remove"},
+	Short:   "Remove a transition hook",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		removed, err := config.RemovePolicyHook(getBaseDir(), args[0])
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if !removed {
+			err := fmt.Errorf("hook not found: %s", args[0])
+			output.Error("%v", err)
+			return err
+		}
+		output.Success("Removed hook %s", args[0])
+		return nil
+	},
+}
+
+// jsonUpdateError reports a failed issue update in --json output, listing
+// the missing fields when required-field rules refused it
+func jsonUpdateError(err error, message string) {
+	var rfe *db.RequiredFieldsError
+	if errors.As(err, &rfe) {
+		output.JSONErrorWithDetails(output.ErrCodeMissingFields, message, map[string]interface{}{
+			"type":           rfe.Type,
+			"to":             rfe.To,
+			"missing_fields": rfe.Missing,
+		})
+		return
+	}
+	output.JSONError(output.ErrCodeDatabaseError, message)
+}
+
+func renderRequiredFields(rules []models.RequiredFieldsRule) {
+	if len(rules) == 0 {
+		fmt.Println("  No required fields")
+		return
+	}
+	for _, r := range rules {
+		fmt.Printf("  %-28s %s\n", describeRequiredFieldsScope(r), strings.Join(r.Fields, ", "))
+	}
+}
+
+func renderReviewChecklists(lists []models.ReviewChecklist) {
+	if len(lists) == 0 {
+		fmt.Println("  No review checklists")
+		return
+	}
+	for _, c := range lists {
+		fmt.Printf("  %-28s %s\n", describeChecklistScope(c), strings.Join(c.Items, ", "))
+	}
+}
+
+// describeChecklistScope names the issues a review checklist covers
+func describeChecklistScope(c models.ReviewChecklist) string {
+	if c.Type == "" {
+		return "any issue"
+	}
+	return c.Type + "s"
+}
+
+// describeRequiredFieldsScope names the issues and transition a rule covers
+func describeRequiredFieldsScope(r models.RequiredFieldsRule) string {
+	t := "issues"
+	if r.Type != "" {
+		t = r.Type + "s"
+	}
+	return fmt.Sprintf("%s moving to %s", t, r.To)
+}
+
+func renderPolicyHooks(hooks []models.PolicyHookConfig) {
+	if len(hooks) == 0 {
+		fmt.Println("  No hooks configured")
+		return
+	}
+	for _, h := range hooks {
+		fmt.Printf("  %-20s %s\n", h.Name, describePolicyHook(h))
+	}
+}
+
+// describePolicyHook summarizes what a hook runs and when
+func describePolicyHook(h models.PolicyHookConfig) string {
+	what := h.Builtin
+	if h.Command != "" {
+		what = fmt.Sprintf("runs %q", h.Command)
+	}
+	var scope []string
+	if len(h.To) > 0 {
+		scope = append(scope, "to "+strings.Join(h.To, ", "))
+	}
+	if len(h.Priorities) > 0 {
+		scope = append(scope, "for "+strings.Join(h.Priorities, ", "))
+	}
+	if len(scope) == 0 {
+		return what + " on every transition"
+	}
+	return what + " on transitions " + strings.Join(scope, " ")
+}
+
+var policyThrashCmd = &cobra.Command{
+	Use:   "thrash",
+	Short: "Configure the guard against sessions flipping issues back and forth",
+	Long: `A session that keeps reversing its own changes to an issue (close then
+reopen, priority bouncing between two values) is flagged once it makes more
+than --max-reversals reversals of one field within --window minutes.
+
+Modes:
+  off       no checks
+  warn      allow the change
+  throttle  reject the change until the window cools down
+  confirm   reject the change unless ` + db.ThrashConfirmEnv + `=1 is set (default)
+
+Every flagged change is recorded in the td security log.`,
+	Example: `  td policy thrash --mode throttle
+  td policy thrash --window 15 --max-reversals 5`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		baseDir := getBaseDir()
+		tc, err := config.GetThrashConfig(baseDir)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		cfg := thrash.WithDefaults(tc)
+
+		if cmd.Flags().Changed("mode") {
+			cfg.Mode, _ = cmd.Flags().GetString("mode")
+			if !thrash.IsValidMode(cfg.Mode) {
+				err := fmt.Errorf("invalid mode %q: use off, warn, throttle or confirm", cfg.Mode)
+				output.Error("%v", err)
+				return err
+			}
+		}
+		if cmd.Flags().Changed("window") {
+			cfg.WindowMinutes, _ = cmd.Flags().GetInt("window")
+		}
+		if cmd.Flags().Changed("max-reversals") {
+			cfg.MaxReversals, _ = cmd.Flags().GetInt("max-reversals")
+		}
+		if cfg.WindowMinutes < 1 || cfg.MaxReversals < 1 {
+			err := fmt.Errorf("--window and --max-reversals must be at least 1")
+			output.Error("%v", err)
+			return err
+		}
+
+		if err := config.SetThrashConfig(baseDir, cfg); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		output.Success("Anti-thrash guard: %s, more than %d reversals in %d min", cfg.Mode, cfg.MaxReversals, cfg.WindowMinutes)
+		return nil
+	},
+}
+
+var policyScriptsCmd = &cobra.Command{
+	Use:   "scripts",
+	Short: "Configure the hook scripts run on local CLI events",
+	Long: `Executable scripts in .todos/hooks run when td commands on this machine
+act on issues, with the issue as JSON on stdin and TD_HOOK, TD_ISSUE_ID,
+TD_FROM_STATUS, TD_TO_STATUS, TD_SESSION_ID, TD_PRIORITY and TD_TYPE set:
+
+  post-create      after an issue is created
+  post-transition  after an issue's status changes
+  pre-close        before an issue is closed; a failure stops the close
+
+Scripts run from the project root. td serve runs none of them, and
+td monitor only runs pre-close.
+
+Failure policies, for a non-zero exit or a script past --timeout:
+  fail    stop the close, or fail the command after a post- script
+  warn    print a warning and carry on
+  ignore  carry on silently
+
+Unset, pre-close fails and post- scripts warn.`,
+	Example: `  td policy scripts --timeout 30
+  td policy scripts --on-failure warn`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		baseDir := getBaseDir()
+		sc, err := config.GetScriptHooksConfig(baseDir)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		var cfg models.ScriptHooksConfig
+		if sc != nil {
+			cfg = *sc
+		}
+
+		if cmd.Flags().Changed("timeout") {
+			cfg.TimeoutSeconds, _ = cmd.Flags().GetInt("timeout")
+			if cfg.TimeoutSeconds < 1 {
+				err := fmt.Errorf("--timeout must be at least 1")
+				output.Error("%v", err)
+				return err
+			}
+		}
+		if cmd.Flags().Changed("on-failure") {
+			cfg.OnFailure, _ = cmd.Flags().GetString("on-failure")
+			if !hookscripts.IsValidOnFailure(cfg.OnFailure) {
+				err := fmt.Errorf("invalid failure policy %q: use fail, warn or ignore", cfg.OnFailure)
+				output.Error("%v", err)
+				return err
+			}
+		}
+
+		if err := config.SetScriptHooksConfig(baseDir, cfg); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		output.Success("Hook scripts in %s:", hookscripts.Dir(baseDir))
+		renderHookScripts(baseDir)
+		return nil
+	},
+}
+
+var policyClosedCmd = &cobra.Command{
+	Use:   "closed <immutable|editable>",
+	Short: "Make closed issues immutable or editable",
+	Long: `While closed issues are immutable, td update, td comment and the
+HTTP API's PATCH and comment endpoints refuse closed issues, so months-old
+work cannot be rewritten under the reports built on it. Reopen the issue to
+change it, or pass --override (?override=closed over HTTP); every override
+is recorded in the td security log.`,
+	Example: `  td policy closed immutable
+  td policy closed editable`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"immutable", "editable"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var immutable bool
+		switch args[0] {
+		case "immutable":
+			immutable = true
+		case "editable":
+		default:
+			err := fmt.Errorf("invalid mode %q: use immutable or editable", args[0])
+			output.Error("%v", err)
+			return err
+		}
+		if err := config.SetClosedImmutable(getBaseDir(), immutable); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		output.Success("Closed issues are %s", args[0])
+		return nil
+	},
+}
+
+var policyInheritCmd = &cobra.Command{
+	Use:   "inherit <on|off>",
+	Short: "Let blockers inherit the priority of the issues waiting on them",
+	Long: `With priority inheritance on, an open issue that a higher-priority open
+issue depends on, directly or through other issues, takes that priority as
+its effective priority. Its own priority is unchanged. td ready and the
+monitor's ready section sort by effective priority, td show explains where
+a raised priority comes from, and the HTTP API returns it as
+effective_priority and priority_inheritance. Dependency cycles are safe.`,
+	Example: `  td policy inherit on
+  td policy inherit off`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"on", "off"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var on bool
+		switch args[0] {
+		case "on":
+			on = true
+		case "off":
+		default:
+			err := fmt.Errorf("invalid mode %q: use on or off", args[0])
+			output.Error("%v", err)
+			return err
+		}
+		if err := config.SetPriorityInheritance(getBaseDir(), on); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		output.Success("Priority inheritance is %s", args[0])
+		return nil
+	},
+}
+
+func init() {
+	policyShowCmd.Flags().Bool("json", false, "Output as JSON")
+	policyThrashCmd.Flags().String("mode", "", "off, warn, throttle or confirm")
+	policyThrashCmd.Flags().Int("window", thrash.DefaultWindowMinutes, "Window in minutes")
+	policyThrashCmd.Flags().Int("max-reversals", thrash.DefaultMaxReversals, "Reversals of one field allowed in the window")
+	policyHookListCmd.Flags().Bool("json", false, "Output as JSON")
+	policyHookAddCmd.Flags().String("builtin", "", workflow.BuiltinRequireHandoff+" or "+workflow.BuiltinRequireAcceptance)
+	policyHookAddCmd.Flags().String("command", "", "Shell command to run; non-zero exit vetoes")
+	policyHookAddCmd.Flags().StringSlice("to", nil, "Only run on transitions to these statuses")
+	policyHookAddCmd.Flags().StringSlice("priority", nil, "Only run for issues with these priorities")
+	policyHookAddCmd.Flags().Int("timeout", 0, "Command timeout in seconds (default 10)")
+	policyScriptsCmd.Flags().Int("timeout", int(hookscripts.DefaultTimeout.Seconds()), "Script timeout in seconds")
+	policyScriptsCmd.Flags().String("on-failure", "", "fail, warn or ignore")
+	policyHookCmd.AddCommand(policyHookListCmd, policyHookAddCmd, policyHookRmCmd)
+	policyCmd.AddCommand(policyShowCmd, policyThrashCmd, policyRequireCmd, policyChecklistCmd, policyReadyCmd, policyInboxCmd, policyHookCmd, policyClosedCmd, policyInheritCmd, policyScriptsCmd)
+	rootCmd.AddCommand(policyCmd)
+}
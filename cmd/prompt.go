@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+var promptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "Print a one-line issue summary for shell prompts",
+	Long: `Print a short summary of the project's issues, meant for a PS1 or
+starship prompt segment:
+
+  td: 2▶ 1⚑ 3✔
+
+counts issues in progress (▶), blocked (⚑) and in review (✔). The summary
+is cached in .todos and reused until the database changes, so the segment
+costs a couple of file reads. Outside a td project it prints nothing and
+succeeds, so it is safe to call from every prompt.
+
+--format replaces these placeholders:
+
+  {open}         issues not closed
+  {p0}           open P0 issues
+  {review}       issues in review
+  {in_progress}  issues in progress
+  {blocked}      blocked issues
+  {closed}       closed issues`,
+	Example: `  td prompt
+  td prompt --format '{open}o {review}r'
+  PS1='$(td prompt) \$ '
+
+  # starship.toml
+  [custom.td]
+  command = "td prompt"
+  when = "test -d .todos"`,
+	GroupID: "session",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		baseDir := db.ResolveBaseDir(getBaseDir())
+		// Prompts run everywhere; stay quiet where there is no project
+		stamp, err := prompt.CurrentStamp(baseDir)
+		if err != nil {
+			return nil
+		}
+		sum, ok := prompt.Load(baseDir, stamp)
+		if !ok {
+			if sum, err = promptSummary(baseDir); err != nil {
+				return nil
+			}
+		}
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			data, _ := json.MarshalIndent(sum, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+		format, _ := cmd.Flags().GetString("format")
+		fmt.Println(promptSegment(sum, format))
+		return nil
+	},
+}
+
+// promptSummary computes the summary and caches it. The cache is stamped
+// after the database is closed, since closing can checkpoint the WAL.
+func promptSummary(baseDir string) (*models.Summary, error) {
+	database, err := db.Open(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	sum, err := database.GetSummary()
+	database.Close()
+	if err != nil {
+		return nil, err
+	}
+	if stamp, err := prompt.CurrentStamp(baseDir); err == nil {
+		_ = prompt.Save(baseDir, stamp, sum)
+	}
+	return sum, nil
+}
+
+// promptSegment renders sum through format, or the default segment when
+// format is empty
+func promptSegment(sum *models.Summary, format string) string {
+	if format == "" {
+		return fmt.Sprintf("td: %d▶ %d⚑ %d✔", sum.ByStatus[models.StatusInProgress], sum.ByStatus[models.StatusBlocked], sum.ReviewQueue)
+	}
+	return strings.NewReplacer(
+		"{open}", strconv.Itoa(sum.Open),
+		"{p0}", strconv.Itoa(sum.OpenP0),
+		"{review}", strconv.Itoa(sum.ReviewQueue),
+		"{in_progress}", strconv.Itoa(sum.ByStatus[models.StatusInProgress]),
+		"{blocked}", strconv.Itoa(sum.ByStatus[models.StatusBlocked]),
+		"{closed}", strconv.Itoa(sum.ByStatus[models.StatusClosed]),
+	).Replace(format)
+}
+
+func init() {
+	promptCmd.Flags().String("format", "", "Segment format with {open}, {p0}, {review}, {in_progress}, {blocked}, {closed}")
+	promptCmd.Flags().Bool("json", false, "Output as JSON")
+	rootCmd.AddCommand(promptCmd)
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestPromptSegment(t *testing.T) {
+	sum := &models.Summary{
+		ByStatus: map[models.Status]int{models.StatusOpen: 4, models.StatusBlocked: 1, models.StatusInReview: 2, models.StatusClosed: 9},
+		Open:     7, OpenP0: 1, ReviewQueue: 2,
+	}
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"", "td: 0▶ 1⚑ 2✔"},
+		{"{open}o {review}r {blocked}b {closed}c", "7o 2r 1b 9c"},
+		{"[{p0} P0] {in_progress}", "[1 P0] 0"},
+	}
+	for _, tt := range tests {
+		if got := promptSegment(sum, tt.format); got != tt.want {
+			t.Errorf("promptSegment(%q) = %q, want %q", tt.format, got, tt.want)
+		}
+	}
+
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/marcus/td/internal/config"
 	"github.com/marcus/td/internal/db"
 	"github.com/marcus/td/internal/output"
 	"github.com/marcus/td/internal/query"
@@ -54,6 +55,7 @@ FIELDS:
   reviewer    session that reviewed
   parent      direct parent issue ID
   epic        ancestor epic ID (recursive)
+  project     project name (this project here; any in server-wide search)
 
 CROSS-ENTITY SEARCH:
   log.message ~ "text"     Search log messages
@@ -70,6 +72,8 @@ FUNCTIONS:
   blocked_by(id)         Issues blocked by given id
   descendant_of(id)      All children of epic (recursive)
   rework()               Issues rejected and awaiting rework
+  stale(age)             Open issues not updated within age (14d, 2w)
+  mine()                 Issues you created or are implementing
 
 SPECIAL VALUES:
   @me                    Current session ID
@@ -87,6 +91,7 @@ EXAMPLES:
   td query "log.type = blocker"
   td query "title ~ auth OR description ~ auth"
   td query "rework()"
+  td query "is(open) sort:-score"   Highest computed score first (see td score)
 
 BOARDS:
   Save queries as reusable boards with td board:
@@ -161,10 +166,12 @@ BOARDS:
 			sortBy = strings.TrimPrefix(sortBy, "-")
 		}
 
+		useScoreFormula(baseDir)
 		opts := query.ExecuteOptions{
 			Limit:    limit,
 			SortBy:   sortBy,
 			SortDesc: sortDesc,
+			Project:  config.GetProjectName(baseDir),
 		}
 
 		results, err := query.Execute(database, queryStr, sessionID, opts)
@@ -296,6 +303,7 @@ func printQueryFields() {
 		{"reviewer", "string", "session ID or @me"},
 		{"minor", "bool", "true, false"},
 		{"branch", "string", "git branch name"},
+		{"repo", "string", "repository (github.com/acme/api or directory name)"},
 		{"created", "date", "ISO or relative (-7d, today, etc.)"},
 		{"updated", "date", "ISO or relative"},
 		{"closed", "date", "ISO or relative"},
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/output"
+	"github.com/marcus/td/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var rankCmd = &cobra.Command{
+	Use:   "rank",
+	Short: "Order issues in a strict project-wide queue",
+	Long: `Priority buckets group issues, but can't say which P1 comes first. A rank
+puts issues in one project-wide queue: td ready and the monitor's ready
+section list ranked issues first, in queue order, then the rest by
+priority. Sort queries by it with sort:rank.
+
+Ranks are sparse keys, like board positions, so moving an issue changes
+only that issue; the queue is respaced when two neighbours run out of room.
+Rank changes are logged and sync like other edits.`,
+	GroupID: "workflow",
+}
+
+var rankMoveCmd = &cobra.Command{
+	Use:   "move <issue-id>... (above|below <other-id> | top | bottom)",
+	Short: "Put issues in the queue, in the order given",
+	Example: `  td rank move td-a1b2 top
+  td rank move td-c3d4 above td-a1b2
+  td rank move td-e5f6 td-0a1b below td-c3d4
+  td rank move td-9f8e bottom`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		ids, place, other, err := parseRankMove(args)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		sess, err := session.GetOrCreate(database)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if err := database.MoveRank(ids, place, other, sess.ID); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		where := place
+		if other != "" {
+			where = place + " " + db.NormalizeIssueID(other)
+		}
+		if len(ids) == 1 {
+			output.Success("Ranked %s %s", db.NormalizeIssueID(ids[0]), where)
+		} else {
+			output.Success("Ranked %d issues %s", len(ids), where)
+		}
+		return nil
+	},
+}
+
+// parseRankMove splits `td rank move` arguments into the issues to move
+// and where they go
+func parseRankMove(args []string) (ids []string, place, other string, err error) {
+	n := len(args)
+	switch args[n-1] {
+	case db.RankTop, db.RankBottom:
+		return args[:n-1], args[n-1], "", nil
+	}
+	if n >= 3 {
+		switch args[n-2] {
+		case db.RankAbove, db.RankBelow:
+			return args[:n-2], args[n-2], args[n-1], nil
+		}
+	}
+	return nil, "", "", fmt.Errorf("say where: above <id>, below <id>, top or bottom")
+}
+
+var rankClearCmd = &cobra.Command{
+	Use:   "clear <issue-id>...",
+	Short: "Take issues out of the queue",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		sess, err := session.GetOrCreate(database)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if err := database.ClearRank(args, sess.ID); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		output.Success("Cleared the rank of %d issue(s)", len(args))
+		return nil
+	},
+}
+
+var rankListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List ranked issues in queue order",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		all, _ := cmd.Flags().GetBool("all")
+		issues, err := database.RankedIssues(all)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+			data, _ := json.MarshalIndent(issues, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+		if len(issues) == 0 {
+			fmt.Println("No ranked issues (td rank move <id> top)")
+			return nil
+		}
+		for i := range issues {
+			fmt.Printf("%3d. %s\n", i+1, output.FormatIssueShort(&issues[i]))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rankListCmd.Flags().Bool("all", false, "include closed issues")
+	rankListCmd.Flags().Bool("json", false, "output as JSON")
+
+	rankCmd.AddCommand(rankMoveCmd, rankClearCmd, rankListCmd)
+	rootCmd.AddCommand(rankCmd)
+}
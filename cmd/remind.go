@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/dateparse"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/output"
+	"github.com/marcus/td/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var remindCmd = &cobra.Command{
+	Use:   "remind <issue-id> [in|on] <when> [message...]",
+	Short: "Set a reminder on an issue",
+	Long: `Set a lightweight personal nudge on an issue. When it comes due, a
+running td monitor raises a desktop notification and shows it in the
+reminders panel (m), and td serve sends a "reminder" SSE event.
+
+<when> is an offset (30m, 2h, 3d, 1w) or a date (tomorrow, friday,
+2026-03-01); dates fire at 09:00 local time.`,
+	Example: `  td remind td-a1b2 in 3d "check CI flake"
+  td remind td-a1b2 on friday follow up with design
+  td remind list
+  td remind cancel rm-1a2b3c4d`,
+	GroupID: "workflow",
+	Args:    cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		when, message, err := parseRemindArgs(args[1:])
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		remindAt, err := dateparse.ParseTimeFrom(when, dateparse.Now())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if !remindAt.After(time.Now()) {
+			err := fmt.Errorf("reminder time %s is in the past", remindAt.Local().Format("Mon Jan 2 15:04"))
+			output.Error("%v", err)
+			return err
+		}
+
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		issue, err := database.GetIssue(args[0])
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		sess, err := session.GetOrCreate(database)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		reminder := &models.Reminder{
+			IssueID:   issue.ID,
+			SessionID: sess.ID,
+			Message:   message,
+			RemindAt:  remindAt,
+		}
+		if err := database.CreateReminder(reminder); err != nil {
+			output.Error("failed to create reminder: %v", err)
+			return err
+		}
+
+		output.Success("Reminder %s set for %s on %s", reminder.ID, issue.ID, remindAt.Local().Format("Mon Jan 2 15:04"))
+		return nil
+	},
+}
+
+// parseRemindArgs splits "[in|on] <when> [message...]"
+func parseRemindArgs(args []string) (when, message string, err error) {
+	switch strings.ToLower(args[0]) {
+	case "in", "on", "at":
+		if len(args) < 2 {
+			return "", "", fmt.Errorf("missing time after %q", args[0])
+		}
+		args = args[1:]
+	}
+	return args[0], strings.Join(args[1:], " "), nil
+}
+
+var remindListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List pending reminders",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		filter := db.ReminderFilter{Status: []models.ReminderStatus{models.ReminderPending}}
+		if all, _ := cmd.Flags().GetBool("all"); all {
+			filter.Status = nil
+		}
+		if issueID, _ := cmd.Flags().GetString("issue"); issueID != "" {
+			filter.IssueID = db.NormalizeIssueID(issueID)
+		}
+		reminders, err := database.ListReminders(filter)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			if reminders == nil {
+				reminders = []models.Reminder{}
+			}
+			data, _ := json.MarshalIndent(reminders, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if len(reminders) == 0 {
+			output.Info("No reminders")
+			return nil
+		}
+		now := time.Now()
+		for _, r := range reminders {
+			status := string(r.Status)
+			if r.Status == models.ReminderPending && !r.RemindAt.After(now) {
+				status = "due"
+			}
+			fmt.Printf("%s  %s  %s  %-9s  %s\n", r.ID, r.IssueID, r.RemindAt.Local().Format("Mon Jan 2 15:04"), status, r.Message)
+		}
+		return nil
+	},
+}
+
+var remindCancelCmd = &cobra.Command{
+	Use:   "cancel <reminder-id>",
+	Short: "Cancel a pending reminder",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		if _, err := database.GetReminder(args[0]); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if err := database.CancelReminder(args[0]); err != nil {
+			if errors.Is(err, db.ErrReminderNotPending) {
+				output.Error("reminder %s already fired or was cancelled", args[0])
+			} else {
+				output.Error("%v", err)
+			}
+			return err
+		}
+		output.Success("Cancelled reminder %s", args[0])
+		return nil
+	},
+}
+
+func init() {
+	remindListCmd.Flags().Bool("all", false, "Include fired and cancelled reminders")
+	remindListCmd.Flags().String("issue", "", "Only reminders for this issue")
+	remindListCmd.Flags().Bool("json", false, "Output as JSON")
+	remindCmd.AddCommand(remindListCmd, remindCancelCmd)
+	rootCmd.AddCommand(remindCmd)
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/output"
+	"github.com/marcus/td/internal/serve"
+	"github.com/spf13/cobra"
+)
+
+var replayCmd = &cobra.Command{
+	Use:   "replay <file>",
+	Short: "Re-execute a td serve --record recording against a fresh database",
+	Long: `Replays the API requests in a recording made with td serve --record, in
+order, against a freshly initialized project. Each request runs as the
+session that made it, with the clock pinned to the time it was recorded
+and IDs drawn from --seed, so the same recording and seed always produce
+the same database. IDs the original server returned are mapped onto the
+ones the replay creates, so later requests refer to the same records.
+
+Every replayed status is compared with the recorded one; mismatches are
+listed and make the command exit non-zero. Event streams, requests whose
+bodies were too large to record and requests on confidential issues,
+whose bodies are never recorded, are skipped.
+
+The project is created in a new temporary directory, or in --dir, and
+kept so the result can be inspected with td -w <dir>.`,
+	Example: `  td serve --record agent.jsonl
+  td replay agent.jsonl
+  td replay agent.jsonl --dir /tmp/repro --seed 7`,
+	GroupID: "system",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		dir, _ := cmd.Flags().GetString("dir")
+		seed, _ := cmd.Flags().GetUint64("seed")
+		asJSON, _ := cmd.Flags().GetBool("json")
+		verbose, _ := cmd.Flags().GetBool("verbose")
+
+		f, err := os.Open(args[0])
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		records, err := serve.ReadRecording(f)
+		f.Close()
+		if err != nil {
+			output.Error("read recording: %v", err)
+			return err
+		}
+
+		if dir == "" {
+			if dir, err = os.MkdirTemp("", "td-replay-"); err != nil {
+				output.Error("%v", err)
+				return err
+			}
+		} else if _, err := os.Stat(filepath.Join(dir, ".todos")); err == nil {
+			err := fmt.Errorf("--dir needs a fresh directory: %s/.todos already exists", dir)
+			output.Error("%v", err)
+			return err
+		}
+
+		database, err := db.Initialize(dir)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		if !verbose {
+			prev := slog.Default()
+			slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+			defer slog.SetDefault(prev)
+		}
+		res, err := serve.Replay(database, dir, records, serve.ReplayOptions{Seed: seed})
+		if err != nil {
+			output.Error("replay: %v", err)
+			return err
+		}
+
+		if asJSON {
+			data, _ := json.MarshalIndent(map[string]interface{}{
+				"dir":    dir,
+				"result": res,
+			}, "", "  ")
+			fmt.Println(string(data))
+		} else {
+			for _, m := range res.Mismatches {
+				output.Warning("#%d %s %s: status %d, recorded %d", m.Seq, m.Method, m.Path, m.Got, m.Want)
+				if m.Response != "" {
+					fmt.Printf("    %s\n", m.Response)
+				}
+			}
+			fmt.Printf("Replayed %d of %d requests (%d skipped): %d matched, %d mismatched\n",
+				res.Replayed, res.Requests, res.Skipped, res.Matched, len(res.Mismatches))
+			fmt.Printf("Project: %s\n", dir)
+		}
+
+		if len(res.Mismatches) > 0 {
+			return fmt.Errorf("%d replayed request(s) differ from the recording", len(res.Mismatches))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(replayCmd)
+
+	replayCmd.Flags().String("dir", "", "Replay into this fresh directory instead of a new temporary one")
+	replayCmd.Flags().Uint64("seed", 1, "Seed for the IDs the replay generates")
+	replayCmd.Flags().BoolP("verbose", "v", false, "Log each replayed request")
+	replayCmd.Flags().Bool("json", false, "Output as JSON")
+}
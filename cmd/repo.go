@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/git"
+	"github.com/marcus/td/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var repoCmd = &cobra.Command{
+	Use:   "repo",
+	Short: "Git repositories tracked by this project",
+	Long: `A td project can span several git repositories that share one database
+through .td-root. Issues, sessions and git snapshots record the repository
+they came from: its origin remote (github.com/acme/api), or the directory
+name when there is no remote. td remembers where each repository is checked
+out on this machine, so git details are read from the right checkout.
+
+Filter by repository with the repo field: td query "repo = github.com/acme/api".`,
+	GroupID: "system",
+}
+
+var repoListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List repositories seen on this machine",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		repos, err := database.ListRepos()
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			if repos == nil {
+				repos = []db.Repo{}
+			}
+			data, _ := json.MarshalIndent(repos, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if len(repos) == 0 {
+			output.Info("No repositories recorded yet")
+			return nil
+		}
+		for _, r := range repos {
+			fmt.Printf("%-40s  %s  (%s)\n", r.Name, r.Path, output.FormatTimeAgo(r.LastSeen))
+		}
+		return nil
+	},
+}
+
+var repoWhichCmd = &cobra.Command{
+	Use:   "which <commit|branch>",
+	Short: "Find which repository a commit or branch belongs to",
+	Example: `  td repo which 3f2a9c1
+  td repo which feature/login`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		matches, err := resolveRepoRef(database, args[0])
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if len(matches) == 0 {
+			err := fmt.Errorf("no known repository has %s", args[0])
+			output.Error("%v", err)
+			return err
+		}
+		for _, r := range matches {
+			fmt.Printf("%s  %s\n", r.Name, r.Path)
+		}
+		return nil
+	},
+}
+
+// resolveRepoRef returns the known repositories whose checkout contains
+// ref as a commit or a branch
+func resolveRepoRef(database *db.DB, ref string) ([]db.Repo, error) {
+	repos, err := database.ListRepos()
+	if err != nil {
+		return nil, err
+	}
+	var matches []db.Repo
+	for _, r := range repos {
+		if _, err := os.Stat(r.Path); err != nil {
+			continue
+		}
+		if git.HasCommit(r.Path, ref) || git.HasBranch(r.Path, ref) {
+			matches = append(matches, r)
+		}
+	}
+	return matches, nil
+}
+
+// gitStateForRepo returns the git state of repo's checkout and the
+// directory to run git in ("" for the working directory). Snapshots that
+// predate repo tracking (repo "") and the current repository use the
+// working directory; others use the checkout remembered in the repos
+// registry. Returns nil when the repository isn't checked out here.
+func gitStateForRepo(database *db.DB, repo string) (*git.State, string) {
+	current, err := git.GetState()
+	if repo == "" || (err == nil && current.Repo == repo) {
+		return current, ""
+	}
+	path, _ := database.RepoPath(repo)
+	if path == "" {
+		return nil, ""
+	}
+	state, err := git.GetStateIn(path)
+	if err != nil {
+		return nil, ""
+	}
+	return state, path
+}
+
+func init() {
+	repoListCmd.Flags().Bool("json", false, "Output as JSON")
+	repoCmd.AddCommand(repoListCmd, repoWhichCmd)
+	rootCmd.AddCommand(repoCmd)
+}
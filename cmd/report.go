@@ -0,0 +1,435 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/dateparse"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/output"
+	"github.com/marcus/td/internal/report"
+	"github.com/marcus/td/internal/session"
+	"github.com/marcus/td/pkg/monitor"
+	"github.com/spf13/cobra"
+)
+
+var reportCmd = &cobra.Command{
+	Use:     "report",
+	Short:   "Generate shareable reports",
+	GroupID: "system",
+}
+
+var reportSnapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Render a self-contained snapshot of a board or the monitor task list",
+	Long: `Render a point-in-time snapshot with status counts and issue lists,
+grouped the same way as td monitor. Suitable for weekly updates or release notes.
+
+Without --board the snapshot covers the monitor's default task list.
+
+Examples:
+  td report snapshot --board "Sprint 12" --format md
+  td report snapshot --format html -o snapshot.html
+  td report snapshot --board bd-1234 --link-base https://tracker.example.com/issues`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		format = strings.ToLower(format)
+		if format != "md" && format != "markdown" && format != "html" {
+			err := fmt.Errorf("invalid format %q: use md or html", format)
+			output.Error("%v", err)
+			return err
+		}
+
+		baseDir := getBaseDir()
+		database, err := db.Open(baseDir)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		sessionID := ""
+		if sess, err := session.GetOrCreate(database); err == nil {
+			sessionID = sess.ID
+		}
+
+		boardRef, _ := cmd.Flags().GetString("board")
+		includeClosed, _ := cmd.Flags().GetBool("include-closed")
+		linkBase, _ := cmd.Flags().GetString("link-base")
+
+		snap := &report.Snapshot{
+			Title:       "All Issues",
+			GeneratedAt: time.Now(),
+			LinkBase:    linkBase,
+		}
+
+		var data monitor.TaskListData
+		if boardRef != "" {
+			board, err := database.ResolveBoardRef(boardRef)
+			if err != nil {
+				output.Error("%v", err)
+				return err
+			}
+			snap.Title = "Board: " + board.Name
+			snap.Query = board.Query
+
+			statusFilter := monitor.DefaultBoardStatusFilter()
+			if includeClosed {
+				statusFilter[models.StatusClosed] = true
+			}
+			issues, err := monitor.LoadBoardIssues(database, board, sessionID, statusFilter)
+			if err != nil {
+				output.Error("%v", err)
+				return err
+			}
+			rejectedIDs, _ := database.GetRejectedInProgressIssueIDs()
+			data = monitor.CategorizeBoardIssues(database, issues, sessionID, monitor.SortByPriority, rejectedIDs)
+		} else {
+			data = monitor.FetchData(database, sessionID, time.Time{}, "", includeClosed, monitor.SortByPriority).TaskList
+		}
+		snap.Sections = snapshotSections(data)
+
+		var buf bytes.Buffer
+		if format == "html" {
+			err = report.RenderHTML(&buf, snap)
+		} else {
+			err = report.RenderMarkdown(&buf, snap)
+		}
+		if err != nil {
+			output.Error("render snapshot: %v", err)
+			return err
+		}
+
+		outPath, _ := cmd.Flags().GetString("output")
+		if outPath == "" {
+			fmt.Print(buf.String())
+			return nil
+		}
+		if err := os.WriteFile(outPath, buf.Bytes(), 0644); err != nil {
+			output.Error("write %s: %v", outPath, err)
+			return err
+		}
+		output.Success("Wrote snapshot to %s", outPath)
+		return nil
+	},
+}
+
+var reportReworkCmd = &cobra.Command{
+	Use:   "rework",
+	Short: "List frequently reopened issues and the project's rework rate",
+	Long: `List issues reopened at least --min times, most reworked first, with how
+often each reason category came up. The rework rate is the share of issues
+ever closed that were reopened at least once.
+
+Examples:
+  td report rework
+  td report rework --min 3 --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		minCount, _ := cmd.Flags().GetInt("min")
+		if minCount < 1 {
+			err := fmt.Errorf("--min must be at least 1")
+			output.Error("%v", err)
+			return err
+		}
+
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		issues, err := database.ListReworkedIssues(minCount)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		stats, err := database.GetExtendedStats()
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			if issues == nil {
+				issues = []models.ReworkedIssue{}
+			}
+			data, _ := json.MarshalIndent(map[string]interface{}{
+				"min":             minCount,
+				"rework_rate":     stats.ReworkRate,
+				"total_reworks":   stats.TotalReworks,
+				"reworked_issues": stats.ReworkedIssues,
+				"issues":          issues,
+			}, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		fmt.Printf("Rework rate: %.0f%% (%d reworked issues, %d reopens)\n",
+			stats.ReworkRate*100, stats.ReworkedIssues, stats.TotalReworks)
+		if len(issues) == 0 {
+			fmt.Printf("No issues reopened %d or more times\n", minCount)
+			return nil
+		}
+		fmt.Print(output.SectionHeader(fmt.Sprintf("Reopened %d+ times", minCount)))
+		for _, ri := range issues {
+			fmt.Printf("  %s  %dx  [%s]  %s  (%s)\n", ri.IssueID, ri.Count, ri.Status, ri.Title, describeReworkCategories(ri.ByCategory))
+		}
+		return nil
+	},
+}
+
+// describeReworkCategories renders category counts, e.g. "bug 2, incomplete 1"
+func describeReworkCategories(counts map[models.ReworkCategory]int) string {
+	var parts []string
+	for _, c := range models.ReworkCategories() {
+		if n := counts[c]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", c, n))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+var reportOverridesCmd = &cobra.Command{
+	Use:   "overrides",
+	Short: "Audit uses of bypass-prevention overrides",
+	Long: `List every time a review rule was bypassed, newest first, with the
+justification given: minor self-approvals and self-closes, creator approval
+and self-close exceptions, forced starts of blocked issues and edits to
+immutable closed issues.
+
+--since takes a date (2026-03-01) or an offset back from now (7d, 2w).
+
+Examples:
+  td report overrides
+  td report overrides --kind minor_self_approve --since 7d
+  td report overrides --session ses_a1b2c3 --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		kindFlag, _ := cmd.Flags().GetString("kind")
+		filter := db.OverrideFilter{Kind: models.OverrideKind(kindFlag)}
+		filter.SessionID, _ = cmd.Flags().GetString("session")
+		filter.IssueID, _ = cmd.Flags().GetString("issue")
+		if filter.Kind != "" && !models.IsValidOverrideKind(filter.Kind) {
+			err := fmt.Errorf("invalid --kind %q: use one of %v", kindFlag, models.OverrideKinds())
+			output.Error("%v", err)
+			return err
+		}
+		if since, _ := cmd.Flags().GetString("since"); since != "" {
+			t, err := parseSince(since)
+			if err != nil {
+				output.Error("invalid --since: %v", err)
+				return err
+			}
+			filter.Since = t
+		}
+
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		overrides, err := database.ListOverrides(filter)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			if overrides == nil {
+				overrides = []models.Override{}
+			}
+			data, _ := json.MarshalIndent(overrides, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if len(overrides) == 0 {
+			output.Info("No overrides recorded")
+			return nil
+		}
+		counts := make(map[models.OverrideKind]int)
+		for _, o := range overrides {
+			counts[o.Kind]++
+			justification := o.Justification
+			if justification == "" {
+				justification = "(no justification)"
+			}
+			fmt.Printf("%s  %s  %-18s  %s  %s\n", o.CreatedAt.Local().Format("2006-01-02 15:04"),
+				o.IssueID, o.Kind, o.SessionID, justification)
+		}
+		var parts []string
+		for _, k := range models.OverrideKinds() {
+			if n := counts[k]; n > 0 {
+				parts = append(parts, fmt.Sprintf("%s %d", k, n))
+			}
+		}
+		noun := "overrides"
+		if len(overrides) == 1 {
+			noun = "override"
+		}
+		fmt.Printf("\n%d %s (%s)\n", len(overrides), noun, strings.Join(parts, ", "))
+		return nil
+	},
+}
+
+var reportContributorsCmd = &cobra.Command{
+	Use:   "contributors",
+	Short: "Show which sessions and work sessions contributed to each issue",
+	Long: `Break the work logged on each issue down by the agent sessions that
+logged it and the work sessions (td ws) it was logged in. Issues with the
+most contributors come first. Work session logs count for every issue
+tagged in the work session.
+
+--since takes a date (2026-03-01) or an offset back from now (7d, 2w).
+
+Examples:
+  td report contributors
+  td report contributors --min 2 --since 2w
+  td report contributors --session ses_a1b2c3 --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		minCount, _ := cmd.Flags().GetInt("min")
+		if minCount < 1 {
+			err := fmt.Errorf("--min must be at least 1")
+			output.Error("%v", err)
+			return err
+		}
+		var filter db.EffortFilter
+		filter.SessionID, _ = cmd.Flags().GetString("session")
+		filter.IssueID, _ = cmd.Flags().GetString("issue")
+		if since, _ := cmd.Flags().GetString("since"); since != "" {
+			t, err := parseSince(since)
+			if err != nil {
+				output.Error("invalid --since: %v", err)
+				return err
+			}
+			filter.Since = t
+		}
+
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		efforts, err := database.ListIssueEfforts(filter)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		issues := []models.IssueEffort{}
+		for _, e := range efforts {
+			if len(e.Contributors) >= minCount {
+				issues = append(issues, e)
+			}
+		}
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			data, _ := json.MarshalIndent(map[string]interface{}{
+				"min":    minCount,
+				"issues": issues,
+			}, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if len(issues) == 0 {
+			output.Info("No issues with %d or more contributors", minCount)
+			return nil
+		}
+		for _, e := range issues {
+			fmt.Printf("%s  [%s]  %s  (%s)\n", e.IssueID, e.Status, e.Title, describeEffort(e))
+			for _, c := range e.Contributors {
+				fmt.Printf("    %s\n", describeContribution(c))
+			}
+		}
+		return nil
+	},
+}
+
+// describeEffort summarizes an issue's effort, e.g. "2 sessions, 3 work
+// sessions, 14 logs"
+func describeEffort(e models.IssueEffort) string {
+	return fmt.Sprintf("%s, %s, %s", plural(len(e.Contributors), "session"),
+		plural(e.WorkSessions, "work session"), plural(e.Logs, "log"))
+}
+
+// describeContribution renders one contributor, e.g.
+// "ses_a1b2c3 (planner) claude-code  9 logs in 2 work sessions"
+func describeContribution(c models.Contribution) string {
+	who := c.SessionID
+	if c.SessionName != "" {
+		who += " (" + c.SessionName + ")"
+	}
+	if c.AgentType != "" {
+		who += " " + c.AgentType
+	}
+	return fmt.Sprintf("%s  %s in %s", who, plural(c.Logs, "log"), plural(c.WorkSessions, "work session"))
+}
+
+// plural formats a count with its noun, e.g. "1 log", "3 logs"
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// parseSince reads a YYYY-MM-DD date or an offset back from now (7d, 2w)
+func parseSince(s string) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", s, dateparse.Location()); err == nil {
+		return t, nil
+	}
+	d, err := dateparse.ParseOffset(s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Now().Add(-d), nil
+}
+
+// snapshotSections maps monitor task list categories to report sections
+func snapshotSections(data monitor.TaskListData) []report.Section {
+	return []report.Section{
+		{Name: "Reviewable", Issues: data.Reviewable},
+		{Name: "Needs Rework", Issues: data.NeedsRework},
+		{Name: "In Progress", Issues: data.InProgress},
+		{Name: "Ready", Issues: data.Ready},
+		{Name: "Needs Triage", Issues: data.NeedsTriage},
+		{Name: "Pending Review", Issues: data.PendingReview},
+		{Name: "Blocked", Issues: data.Blocked},
+		{Name: "Closed", Issues: data.Closed},
+	}
+}
+
+func init() {
+	reportSnapshotCmd.Flags().String("board", "", "Board name or ID (default: monitor task list)")
+	reportSnapshotCmd.Flags().StringP("format", "f", "md", "Output format: md or html")
+	reportSnapshotCmd.Flags().StringP("output", "o", "", "Write to file instead of stdout")
+	reportSnapshotCmd.Flags().String("link-base", "", "URL prefix for issue links (e.g. https://tracker.example.com/issues)")
+	reportSnapshotCmd.Flags().Bool("include-closed", false, "Include closed issues")
+	reportReworkCmd.Flags().Int("min", 2, "Only list issues reopened at least this many times")
+	reportReworkCmd.Flags().Bool("json", false, "Output as JSON")
+	reportOverridesCmd.Flags().String("kind", "", "Only overrides of this kind, e.g. minor_self_approve")
+	reportOverridesCmd.Flags().String("session", "", "Only overrides made by this session")
+	reportOverridesCmd.Flags().String("issue", "", "Only overrides on this issue")
+	reportOverridesCmd.Flags().String("since", "", "Only overrides since a date or offset (e.g. 7d)")
+	reportOverridesCmd.Flags().Bool("json", false, "Output as JSON")
+	reportContributorsCmd.Flags().Int("min", 1, "Only list issues with at least this many contributing sessions")
+	reportContributorsCmd.Flags().String("session", "", "Only issues this session logged on")
+	reportContributorsCmd.Flags().String("issue", "", "Only this issue")
+	reportContributorsCmd.Flags().String("since", "", "Only logs since a date or offset (e.g. 7d)")
+	reportContributorsCmd.Flags().Bool("json", false, "Output as JSON")
+	reportCmd.AddCommand(reportSnapshotCmd, reportReworkCmd, reportOverridesCmd, reportContributorsCmd)
+	rootCmd.AddCommand(reportCmd)
+}
@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var retentionCmd = &cobra.Command{
+	Use:   "retention",
+	Short: "Prune old logs and activity history",
+	Long: `Long-running projects collect logs and action history faster than anyone
+reads them. A retention policy says how many days to keep each:
+
+  logs      older logs are replaced by one summary log per issue
+  activity  older action log rows are dropped once pushed to the sync
+            server (any of them when the project is not linked)
+
+Issues still referenced by open work keep all their logs: open issues,
+issues an open issue depends on, parents of open children, and issues in
+a work session that has not ended.
+
+td retention run applies the policy; td serve also applies it on its
+--retention-interval. Retention only changes this database and is not
+synced.`,
+	Example: `  td retention set --logs 365 --activity 90
+  td retention run --dry-run
+  td retention run --vacuum`,
+	GroupID: "system",
+}
+
+var retentionShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the retention policy",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		rc, err := config.GetRetentionConfig(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if rc == nil {
+			rc = &models.RetentionConfig{}
+		}
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			data, _ := json.MarshalIndent(rc, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+		fmt.Printf("Logs:      %s\n", retentionDays(rc.LogDays))
+		fmt.Printf("Activity:  %s\n", retentionDays(rc.ActivityDays))
+		return nil
+	},
+}
+
+var retentionSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set how many days logs and activity history are kept",
+	Long:  `Set the retention policy. 0 keeps records forever.`,
+	Example: `  td retention set --logs 365
+  td retention set --activity 0`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		baseDir := getBaseDir()
+		rc, err := config.GetRetentionConfig(baseDir)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		cfg := models.RetentionConfig{}
+		if rc != nil {
+			cfg = *rc
+		}
+		if cmd.Flags().Changed("logs") {
+			cfg.LogDays, _ = cmd.Flags().GetInt("logs")
+		}
+		if cmd.Flags().Changed("activity") {
+			cfg.ActivityDays, _ = cmd.Flags().GetInt("activity")
+		}
+		if cfg.LogDays < 0 || cfg.ActivityDays < 0 {
+			err := fmt.Errorf("--logs and --activity must not be negative")
+			output.Error("%v", err)
+			return err
+		}
+
+		if err := config.SetRetentionConfig(baseDir, cfg); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		output.Success("Retention: logs %s, activity %s", retentionDays(cfg.LogDays), retentionDays(cfg.ActivityDays))
+		return nil
+	},
+}
+
+var retentionRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Apply the retention policy now",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		baseDir := getBaseDir()
+		rc, err := config.GetRetentionConfig(baseDir)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		opts := db.RetentionOptionsFor(rc, clock.Now())
+		opts.DryRun, _ = cmd.Flags().GetBool("dry-run")
+		vacuum, _ := cmd.Flags().GetBool("vacuum")
+		asJSON, _ := cmd.Flags().GetBool("json")
+		if opts.LogsBefore.IsZero() && opts.ActivityBefore.IsZero() && !asJSON {
+			output.Info("No retention policy; everything is kept (see td retention set)")
+			return nil
+		}
+
+		database, err := db.Open(baseDir)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		report, err := database.ApplyRetention(opts)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if vacuum && !opts.DryRun {
+			if err := database.Vacuum(); err != nil {
+				output.Error("vacuum: %v", err)
+				return err
+			}
+		}
+
+		if asJSON {
+			data, _ := json.MarshalIndent(report, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		verb := "Pruned"
+		if report.DryRun {
+			verb = "Would prune"
+		}
+		if !opts.LogsBefore.IsZero() {
+			fmt.Printf("%s %d logs across %d issues\n", verb, report.LogsPruned, len(report.Issues))
+			for _, r := range report.Issues {
+				id := r.IssueID
+				if id == "" {
+					id = "(no issue)"
+				}
+				fmt.Printf("  %-12s %d logs, %s to %s\n", id, r.Pruned, r.From.Format("2006-01-02"), r.To.Format("2006-01-02"))
+			}
+			if report.LogsExempt > 0 {
+				fmt.Printf("Kept %d old logs on %d issues referenced by open work\n", report.LogsExempt, len(report.ExemptIssues))
+			}
+		}
+		if !opts.ActivityBefore.IsZero() {
+			fmt.Printf("%s %d action log rows\n", verb, report.ActionsPruned)
+			if report.ActionsKept > 0 {
+				fmt.Printf("Kept %d old action log rows not yet synced\n", report.ActionsKept)
+			}
+		}
+		return nil
+	},
+}
+
+// retentionDays describes a retention period in days
+func retentionDays(days int) string {
+	switch days {
+	case 0:
+		return "kept forever"
+	case 1:
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", days)
+}
+
+func init() {
+	retentionShowCmd.Flags().Bool("json", false, "Output as JSON")
+	retentionSetCmd.Flags().Int("logs", 0, "Days to keep logs (0 = forever)")
+	retentionSetCmd.Flags().Int("activity", 0, "Days to keep action log rows (0 = forever)")
+	retentionRunCmd.Flags().Bool("dry-run", false, "Report what would be pruned without changing anything")
+	retentionRunCmd.Flags().Bool("vacuum", false, "Compact the database file afterwards to free the space")
+	retentionRunCmd.Flags().Bool("json", false, "Output as JSON")
+	retentionCmd.AddCommand(retentionShowCmd, retentionSetCmd, retentionRunCmd)
+	rootCmd.AddCommand(retentionCmd)
+}
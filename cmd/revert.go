@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/output"
+	"github.com/marcus/td/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var revertCmd = &cobra.Command{
+	Use:   "revert <revision-id>",
+	Short: "Restore a description, acceptance or comment to an earlier revision",
+	Long: `Restore the text a revision recorded. Revision IDs are listed by
+td show <issue-id> --history. The text being replaced is kept as a new
+revision, so a revert can itself be reverted.`,
+	GroupID: "system",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		sess, err := session.GetOrCreate(database)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		target, err := database.GetRevision(args[0])
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		issue, err := database.GetIssue(target.IssueID)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if err := requireConfidentialAccess(database, issue, sess.ID); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		override, _ := cmd.Flags().GetBool("override")
+		if err := database.CheckClosedEdit(issue.ID, sess.ID, "revert", override); err != nil {
+			output.Error("%v (or pass --override)", err)
+			return err
+		}
+		if override {
+			database = database.WithClosedOverride()
+		}
+
+		rev, err := database.RevertRevision(target.ID, sess.ID)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		fmt.Printf("REVERTED %s %s to %s\n", rev.EntityID, rev.Field, rev.ID)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(revertCmd)
+
+	revertCmd.Flags().Bool("override", false, "Revert on a closed issue while closed issues are immutable (recorded in the security log)")
+}
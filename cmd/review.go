@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -25,6 +26,7 @@ func clearFocusIfNeeded(baseDir, issueID string) {
 type SubmitReviewResult struct {
 	Success bool
 	Message string
+	Err     error // the update error, when the update failed
 }
 
 // submitIssueForReview submits a single issue for review with proper validation,
@@ -64,6 +66,7 @@ func submitIssueForReview(database *db.DB, issue *models.Issue, sess *session.Se
 		return SubmitReviewResult{
 			Success: false,
 			Message: fmt.Sprintf("failed to update %s: %v", issue.ID, err),
+			Err:     err,
 		}
 	}
 
@@ -180,7 +183,7 @@ Supports bulk operations:
 			result := submitIssueForReview(database, issue, sess, baseDir, logMsg)
 			if !result.Success {
 				if jsonOutput {
-					output.JSONError(output.ErrCodeDatabaseError, result.Message)
+					jsonUpdateError(result.Err, result.Message)
 				} else {
 					output.Warning("%s", result.Message)
 				}
@@ -242,14 +245,27 @@ func approvalReason(cmd *cobra.Command) string {
 	return ""
 }
 
+// renderChecklist prints a review checklist numbered for --check
+func renderChecklist(items []string) {
+	fmt.Println("Review checklist (acknowledge with --check <n|item|all>):")
+	for i, item := range items {
+		fmt.Printf("  %d. %s\n", i+1, item)
+	}
+}
+
 var approveCmd = &cobra.Command{
 	Use:   "approve [issue-id...]",
 	Short: "Approve and close one or more issues",
 	Long: `Approves and closes the issue(s). Must be a different session than the implementer.
 
+When the project has a review checklist for the issue's type (see td
+policy checklist), acknowledge each item with --check, by text or number,
+or --check all. The acknowledged items are stored with the approval.
+
 Supports bulk operations:
   td approve td-abc1 td-abc2 td-abc3    # Approve multiple issues
-  td approve --all                      # Approve all reviewable issues`,
+  td approve --all                      # Approve all reviewable issues
+  td approve td-abc1 --check 1 --check "docs updated"`,
 	GroupID: "workflow",
 	Args:    cobra.MinimumNArgs(0),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -373,8 +389,21 @@ Supports bulk operations:
 			now := time.Now()
 			issue.ClosedAt = &now
 
-			if err := database.UpdateIssueLogged(issue, sess.ID, models.ActionApprove); err != nil {
-				output.Warning("failed to update %s: %v", issueID, err)
+			checks, _ := cmd.Flags().GetStringArray("check")
+			if err := database.ApproveIssueLogged(issue, sess.ID, checks, false); err != nil {
+				var ce *db.ChecklistError
+				switch {
+				case !errors.As(err, &ce):
+					output.Warning("failed to update %s: %v", issueID, err)
+				case jsonOutput:
+					output.JSONErrorWithDetails(output.ErrCodeChecklist, err.Error(), map[string]interface{}{
+						"checklist": ce.Items,
+						"missing":   ce.Missing,
+					})
+				default:
+					output.Error("%v", err)
+					renderChecklist(ce.Items)
+				}
 				skipped++
 				continue
 			}
@@ -403,6 +432,9 @@ Supports bulk operations:
 					AgentType: sess.AgentType,
 					Reason:    "creator_approval_exception: " + reason,
 				})
+				recordOverride(database, issueID, sess.ID, models.OverrideCreatorApproval, reason)
+			} else if eligibility.MinorSelfApproval {
+				recordOverride(database, issueID, sess.ID, models.OverrideMinorSelfApprove, reason)
 			}
 
 			if err := database.AddLog(&models.Log{
@@ -419,6 +451,8 @@ Supports bulk operations:
 
 			if eligibility.CreatorException {
 				fmt.Printf("APPROVED %s (reviewer: %s, creator exception)\n", issueID, sess.ID)
+			} else if eligibility.MinorSelfApproval {
+				fmt.Printf("APPROVED %s (reviewer: %s, minor self-approval)\n", issueID, sess.ID)
 			} else {
 				fmt.Printf("APPROVED %s (reviewer: %s)\n", issueID, sess.ID)
 			}
@@ -514,7 +548,7 @@ Supports bulk operations:
 
 			if err := database.UpdateIssueLogged(issue, sess.ID, models.ActionReject); err != nil {
 				if jsonOutput {
-					output.JSONError(output.ErrCodeDatabaseError, err.Error())
+					jsonUpdateError(err, err.Error())
 				} else {
 					output.Warning("failed to update %s: %v", issueID, err)
 				}
@@ -627,44 +661,11 @@ Examples:
 			}
 
 			// Check if self-closing (comprehensive check using session history)
-			// Handle DB errors conservatively - assume involvement on error
-			wasInvolved, err := database.WasSessionInvolved(issueID, sess.ID)
-			if err != nil {
-				output.Warning("failed to check session history for %s: %v", issueID, err)
-				wasInvolved = true // Conservative: assume involvement on error
-			}
-
-			isCreator := issue.CreatorSession != "" && issue.CreatorSession == sess.ID
-			isImplementer := issue.ImplementerSession != "" && issue.ImplementerSession == sess.ID
-			hasOtherImplementer := issue.ImplementerSession != "" && !isImplementer
-
-			// Was ever involved = in history OR creator OR implementer
-			wasEverInvolved := wasInvolved || isCreator || isImplementer
-
-			// Can close if:
-			// 1. Never involved at all, OR
-			// 2. Only created it AND someone else implemented (not self), OR
-			// 3. Minor task (allows self-close)
-			var canClose bool
-			if !wasEverInvolved {
-				canClose = true
-			} else if isCreator && hasOtherImplementer && !isImplementer {
-				canClose = true
-			} else if issue.Minor {
-				canClose = true
-			} else {
-				canClose = false
-			}
-
+			minorSelfClose, closeErr := database.CheckSelfClose(issue, sess.ID)
+			canClose := closeErr == nil
 			if !canClose {
 				if selfCloseException == "" {
-					if isImplementer {
-						output.Error("cannot close own implementation: %s", issueID)
-					} else if isCreator && !hasOtherImplementer {
-						output.Error("cannot close: you created %s and no one else implemented it", issueID)
-					} else {
-						output.Error("cannot close: you previously worked on %s", issueID)
-					}
+					output.Error("%v", closeErr)
 					output.Error("  Submit for review: td review %s", issueID)
 					skipped++
 					continue
@@ -674,11 +675,7 @@ Examples:
 			}
 
 			// Update issue (atomic update + action log)
-			issue.Status = models.StatusClosed
-			now := time.Now()
-			issue.ClosedAt = &now
-
-			if err := database.UpdateIssueLogged(issue, sess.ID, models.ActionClose); err != nil {
+			if err := database.CloseIssueLogged(issue, sess.ID); err != nil {
 				output.Warning("failed to update %s: %v", issueID, err)
 				skipped++
 				continue
@@ -704,9 +701,13 @@ Examples:
 					AgentType: sess.AgentType,
 					Reason:    selfCloseException,
 				})
+				recordOverride(database, issueID, sess.ID, models.OverrideSelfClose, selfCloseException)
 			} else if reason != "" {
 				logMsg = "Closed: " + reason
 			}
+			if minorSelfClose {
+				recordOverride(database, issueID, sess.ID, models.OverrideMinorSelfClose, reason)
+			}
 
 			if err := database.AddLog(&models.Log{
 				IssueID:   issueID,
@@ -722,22 +723,19 @@ Examples:
 
 			if !canClose && selfCloseException != "" {
 				fmt.Printf("CLOSED %s (self-close exception)\n", issueID)
+			} else if minorSelfClose {
+				fmt.Printf("CLOSED %s (minor self-close)\n", issueID)
 			} else {
 				fmt.Printf("CLOSED %s\n", issueID)
 			}
 
-			// Cascade up: if all siblings are closed, update parent epic
-			if count, ids := database.CascadeUpParentStatus(issueID, models.StatusClosed, sess.ID); count > 0 {
-				for _, id := range ids {
-					fmt.Printf("  ↑ Parent %s auto-cascaded to %s\n", id, models.StatusClosed)
-				}
+			// Cascade up to parent epics and unblock dependents
+			parents, unblocked := database.CascadeClose(issue.ID, sess.ID)
+			for _, id := range parents {
+				fmt.Printf("  ↑ Parent %s auto-cascaded to %s\n", id, models.StatusClosed)
 			}
-
-			// Auto-unblock dependents whose dependencies are now all closed
-			if count, ids := database.CascadeUnblockDependents(issueID, sess.ID); count > 0 {
-				for _, id := range ids {
-					fmt.Printf("  ↓ Dependent %s auto-unblocked\n", id)
-				}
+			for _, id := range unblocked {
+				fmt.Printf("  ↓ Dependent %s auto-unblocked\n", id)
 			}
 
 			closed++
@@ -770,6 +768,7 @@ func init() {
 	approveCmd.Flags().String("notes", "", "Reason for approval (alias for --reason)")
 	approveCmd.Flags().Bool("json", false, "JSON output")
 	approveCmd.Flags().Bool("all", false, "Approve all reviewable issues")
+	approveCmd.Flags().StringArray("check", nil, "Acknowledge a review checklist item by text or number, or \"all\" (repeatable)")
 	rejectCmd.Flags().StringP("reason", "m", "", "Reason for rejection")
 	rejectCmd.Flags().StringP("comment", "c", "", "Reason for rejection (alias for --reason)")
 	rejectCmd.Flags().String("message", "", "Reason for rejection (alias for --reason)")
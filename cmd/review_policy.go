@@ -6,13 +6,15 @@ import (
 	"github.com/marcus/td/internal/db"
 	"github.com/marcus/td/internal/features"
 	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/output"
 )
 
 type approveEligibility struct {
-	Allowed          bool
-	CreatorException bool
-	RequiresReason   bool
-	RejectionMessage string
+	Allowed           bool
+	CreatorException  bool
+	MinorSelfApproval bool // allowed only because the issue is minor
+	RequiresReason    bool
+	RejectionMessage  string
 }
 
 func balancedReviewPolicyEnabled(baseDir string) bool {
@@ -34,14 +36,18 @@ func evaluateApproveEligibility(issue *models.Issue, sessionID string, wasInvolv
 		}
 	}
 
-	// Minor tasks intentionally bypass all self-review restrictions.
-	if issue.Minor {
-		return approveEligibility{Allowed: true}
-	}
-
 	isCreator := issue.CreatorSession != "" && issue.CreatorSession == sessionID
 	isImplementer := issue.ImplementerSession != "" && issue.ImplementerSession == sessionID
 
+	// Minor tasks intentionally bypass all self-review restrictions, but a
+	// session approving its own work is still audited as an override.
+	if issue.Minor {
+		return approveEligibility{
+			Allowed:           true,
+			MinorSelfApproval: wasInvolved || isCreator || isImplementer,
+		}
+	}
+
 	if !balancedPolicy {
 		if wasInvolved || isCreator || isImplementer {
 			return approveEligibility{
@@ -80,3 +86,16 @@ func evaluateApproveEligibility(issue *models.Issue, sessionID string, wasInvolv
 
 	return approveEligibility{Allowed: true}
 }
+
+// recordOverride audits a use of a bypass-prevention override. Failures
+// only warn: the override has already been applied.
+func recordOverride(database *db.DB, issueID, sessionID string, kind models.OverrideKind, justification string) {
+	if err := database.RecordOverride(&models.Override{
+		IssueID:       issueID,
+		Kind:          kind,
+		Justification: justification,
+		SessionID:     sessionID,
+	}); err != nil {
+		output.Warning("failed to record %s override for %s: %v", kind, issueID, err)
+	}
+}
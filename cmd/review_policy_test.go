@@ -26,6 +26,7 @@ func TestEvaluateApproveEligibility(t *testing.T) {
 		noImplementer             bool
 		wantAllowed               bool
 		wantCreatorException      bool
+		wantMinorSelfApproval     bool
 		wantRequiresReason        bool
 	}{
 		{
@@ -86,6 +87,16 @@ func TestEvaluateApproveEligibility(t *testing.T) {
 			balanced:                  false,
 			minor:                     true,
 			wantAllowed:               true,
+			wantMinorSelfApproval:     true,
+		},
+		{
+			name:                      "minor approval by uninvolved reviewer is not an override",
+			sessionID:                 "ses_reviewer",
+			wasInvolved:               false,
+			wasImplementationInvolved: false,
+			balanced:                  true,
+			minor:                     true,
+			wantAllowed:               true,
 		},
 		{
 			name:                      "balanced blocks creator when no implementer set",
@@ -112,6 +123,9 @@ func TestEvaluateApproveEligibility(t *testing.T) {
 			if got.CreatorException != tt.wantCreatorException {
 				t.Fatalf("CreatorException=%v, want %v", got.CreatorException, tt.wantCreatorException)
 			}
+			if got.MinorSelfApproval != tt.wantMinorSelfApproval {
+				t.Fatalf("MinorSelfApproval=%v, want %v", got.MinorSelfApproval, tt.wantMinorSelfApproval)
+			}
 			if got.RequiresReason != tt.wantRequiresReason {
 				t.Fatalf("RequiresReason=%v, want %v", got.RequiresReason, tt.wantRequiresReason)
 			}
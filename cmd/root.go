@@ -2,6 +2,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
@@ -10,7 +11,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/marcus/td/internal/crash"
 	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/i18n"
 	"github.com/marcus/td/internal/session"
 	"github.com/marcus/td/internal/suggest"
 	"github.com/marcus/td/internal/workdir"
@@ -31,6 +34,7 @@ var (
 func SetVersion(v string) {
 	versionStr = v
 	rootCmd.Version = v
+	crash.Version = v
 }
 
 var rootCmd = &cobra.Command{
@@ -41,14 +45,17 @@ var rootCmd = &cobra.Command{
 Optimized for session continuity—capturing working state so new context windows can resume where previous ones stopped.`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		cmdStartTime = time.Now()
-		captureWebhookState()
+		useLocale()
+		useTimezone(getBaseDir())
+		captureHookScriptState(cmd)
 		runGatedSyncStartupHook(cmd)
 	},
-	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+	PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
 		// Capture executed command for analytics (logged in Execute() to avoid double logging)
 		executedCmd = cmd
 		runGatedSyncMutationHook(cmd)
-		dispatchWebhookAsync()
+		dispatchDeliveries()
+		return runPostHookScripts(cmd)
 	},
 }
 
@@ -75,6 +82,7 @@ func Execute() {
 
 	cmdStartTime = time.Now()
 	executedCmd = nil // Reset for this execution
+	defer recoverCLIPanic()
 
 	err := rootCmd.Execute()
 
@@ -102,6 +110,23 @@ func Execute() {
 	}
 }
 
+// recoverCLIPanic turns a panic in a command into a crash report and a
+// short message instead of a bare stack trace. It exits with status 2.
+func recoverCLIPanic() {
+	rec := recover()
+	if rec == nil {
+		return
+	}
+	report, saved := crash.Capture(getBaseDir(), "cli", rec, map[string]string{"command": firstNonFlagArg(os.Args[1:])})
+	fmt.Fprintf(os.Stderr, "td: internal error: %s\n", report.Panic)
+	if saved {
+		fmt.Fprintf(os.Stderr, "A crash report was saved; see td crash show %s\n", report.ID)
+	} else {
+		fmt.Fprintf(os.Stderr, "\n%s", report.Stack)
+	}
+	os.Exit(2)
+}
+
 // logAnalytics logs command usage analytics once after execution completes
 func logAnalytics(err error) {
 	if !db.AnalyticsEnabled() {
@@ -116,7 +141,7 @@ func logAnalytics(err error) {
 		return
 	}
 
-	// Build event using captured command (set in PersistentPostRun) or args fallback
+	// Build event using captured command (set in PersistentPostRunE) or args fallback
 	event := buildCommandEvent(executedCmd, err)
 
 	// If no command was captured (e.g., unknown command), find first non-flag arg
@@ -373,7 +398,7 @@ func getBaseDir() string {
 // Returns an error with helpful usage info if invalid
 func ValidateIssueID(id string, cmdUsage string) error {
 	if strings.TrimSpace(id) == "" {
-		return fmt.Errorf("issue ID required. Usage: td %s", cmdUsage)
+		return errors.New(i18n.T("issue ID required. Usage: td %s", cmdUsage))
 	}
 	return nil
 }
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/dateparse"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/output"
+	"github.com/marcus/td/internal/score"
+	"github.com/spf13/cobra"
+)
+
+var scoreCmd = &cobra.Command{
+	Use:   "score [issue-id...]",
+	Short: "Rank open issues by computed score",
+	Long: `Score issues with the project's scoring formula and list them highest
+first. With no IDs, ranks every open issue.
+
+The formula combines priority with urgency that builds over time. The
+default is:
+
+  ` + score.DefaultFormula + `
+
+Change it with td score formula. Scores also order td next, the
+"score" field in td serve responses, and TDQ queries ending in sort:-score.`,
+	Example: `  td score
+  td score td-a1b2 td-c3d4
+  td score formula "priority * 10 + urgency * 3 + idle / 2"
+  td score formula --reset`,
+	GroupID: "query",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		baseDir := getBaseDir()
+
+		database, err := db.Open(baseDir)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		formula, err := config.GetScoreFormula(baseDir)
+		if err != nil {
+			output.Warning("invalid score_formula in config, using default: %v", err)
+		}
+
+		var issues []models.Issue
+		if len(args) > 0 {
+			issues, err = database.ListIssues(db.ListIssuesOptions{IDs: args})
+		} else {
+			issues, err = database.ListIssues(db.ListIssuesOptions{
+				Status: []models.Status{models.StatusOpen, models.StatusInProgress, models.StatusBlocked, models.StatusInReview},
+			})
+		}
+		if err != nil {
+			output.Error("failed to list issues: %v", err)
+			return err
+		}
+
+		now := dateparse.Now()
+		formula.Sort(issues, now, false)
+		if limit, _ := cmd.Flags().GetInt("limit"); limit > 0 && len(issues) > limit {
+			issues = issues[:limit]
+		}
+
+		if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+			type scored struct {
+				ID    string  `json:"id"`
+				Title string  `json:"title"`
+				Score float64 `json:"score"`
+			}
+			out := make([]scored, len(issues))
+			for i := range issues {
+				out[i] = scored{ID: issues[i].ID, Title: issues[i].Title, Score: formula.Eval(&issues[i], now)}
+			}
+			data, _ := json.MarshalIndent(map[string]interface{}{"formula": formula.String(), "issues": out}, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if len(issues) == 0 {
+			fmt.Println("No open issues")
+			return nil
+		}
+		for i := range issues {
+			fmt.Printf("%8.2f  %s\n", formula.Eval(&issues[i], now), output.FormatIssueShort(&issues[i]))
+		}
+		return nil
+	},
+}
+
+var scoreFormulaCmd = &cobra.Command{
+	Use:   "formula [expression]",
+	Short: "Show or set the scoring formula",
+	Long: `Show the project's scoring formula, or set it. Formulas use numbers,
++ - * /, parentheses, min(a, b, ...), max(a, b, ...) and these variables:
+
+` + scoreVariableHelp(),
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		baseDir := getBaseDir()
+
+		if reset, _ := cmd.Flags().GetBool("reset"); reset {
+			if err := config.SetScoreFormula(baseDir, ""); err != nil {
+				output.Error("%v", err)
+				return err
+			}
+			output.Success("Score formula reset to default: %s", score.DefaultFormula)
+			return nil
+		}
+
+		if len(args) == 0 {
+			formula, err := config.GetScoreFormula(baseDir)
+			if err != nil {
+				output.Warning("invalid score_formula in config, using default: %v", err)
+			}
+			fmt.Println(formula.String())
+			return nil
+		}
+
+		if err := config.SetScoreFormula(baseDir, args[0]); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		output.Success("Score formula set: %s", args[0])
+		return nil
+	},
+}
+
+func scoreVariableHelp() string {
+	var help string
+	for _, v := range score.Variables() {
+		help += fmt.Sprintf("  %-9s %s\n", v.Name, v.Help)
+	}
+	return help
+}
+
+// useScoreFormula makes the project's configured formula the one used for
+// DTO scores and sort:score in this process. A broken formula falls back
+// to the default with a warning rather than failing the command.
+func useScoreFormula(baseDir string) {
+	formula, err := config.GetScoreFormula(baseDir)
+	if err != nil {
+		slog.Warn("score formula", "err", err)
+	}
+	score.Use(formula)
+}
+
+func init() {
+	rootCmd.AddCommand(scoreCmd)
+	scoreCmd.AddCommand(scoreFormulaCmd)
+
+	scoreCmd.Flags().IntP("limit", "n", 0, "Limit results (0 = all)")
+	scoreCmd.Flags().Bool("json", false, "JSON output")
+	scoreFormulaCmd.Flags().Bool("reset", false, "Restore the default formula")
+}
@@ -10,9 +10,10 @@ import (
 )
 
 var securityCmd = &cobra.Command{
-	Use:     "security",
-	Short:   "View security exception log (review/close exceptions)",
-	Long:    `Shows audit log of creator-approval and self-close workflow exceptions.`,
+	Use:   "security",
+	Short: "View security exception log (review/close exceptions)",
+	Long: `Shows audit log of creator-approval and self-close workflow exceptions,
+and changes flagged by the anti-thrash guard (td policy thrash).`,
 	GroupID: "system",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		baseDir := getBaseDir()
@@ -14,6 +14,7 @@ import (
 
 	"github.com/marcus/td/internal/db"
 	"github.com/marcus/td/internal/serve"
+	"github.com/marcus/td/internal/settings"
 	"github.com/spf13/cobra"
 )
 
@@ -24,10 +25,21 @@ var serveCmd = &cobra.Command{
 
 The server provides JSON endpoints for creating, reading, updating,
 and managing issues, boards, sessions, and more. It supports optional
-bearer token authentication and CORS for browser-based clients.
+bearer token authentication and CORS for browser-based clients. Agents
+can authenticate as their own session with tokens from td token create.
 
 If --port is 0 (the default), a random available port is assigned.
-The actual port is written to .todos/serve-port for discovery.`,
+The actual port is written to .todos/serve-port for discovery.
+
+Port, address, CORS origin and poll interval default to the "serve"
+section of the project config, then the user config, and can be set with
+TD_SERVE_PORT, TD_SERVE_ADDR, TD_SERVE_CORS and TD_SERVE_INTERVAL; flags
+win over all of them. See td config doctor --area serve.
+
+--record appends every API request and its response to a file that
+td replay can re-execute against a fresh database, to reproduce what a
+sequence of agent requests did. Authorization headers are never recorded,
+but request bodies are, so treat the file like the database.`,
 	GroupID: "system",
 	RunE:    runServe,
 }
@@ -40,6 +52,37 @@ func init() {
 	serveCmd.Flags().String("token", "", "Bearer token for authentication (optional)")
 	serveCmd.Flags().String("cors", "", "Allowed CORS origin (optional, e.g. http://localhost:3000)")
 	serveCmd.Flags().Duration("interval", 2*time.Second, "Poll interval for SSE events")
+	serveCmd.Flags().Duration("dedupe-interval", time.Hour, "How often to rebuild the duplicate report (0 = on request only)")
+	serveCmd.Flags().Duration("retention-interval", 24*time.Hour, "How often to apply the retention policy (0 = td retention run only)")
+	serveCmd.Flags().Duration("compact-interval", 6*time.Hour, "How often to compact issue logs once td logs config is set (0 = td logs compact only)")
+	serveCmd.Flags().Duration("delivery-interval", 30*time.Second, "How often to send and retry queued webhooks and notifications (0 = after writes only)")
+	serveCmd.Flags().Duration("external-poll-interval", 5*time.Minute, "How often to check external dependencies such as PRs and CI runs (0 = td dep poll only)")
+	serveCmd.Flags().String("record", "", "Append every API request and response to this file for td replay")
+}
+
+// serveSettingFlags maps td serve flags to the settings that default them
+var serveSettingFlags = map[string]string{
+	"port":     "serve.port",
+	"addr":     "serve.addr",
+	"cors":     "serve.cors",
+	"interval": "serve.interval",
+}
+
+// applyServeSettings sets each unset flag from its setting when a config
+// file or environment variable gives one
+func applyServeSettings(cmd *cobra.Command, dir string) error {
+	values, _ := settings.Resolve(dir, os.Getenv)
+	for _, v := range values {
+		for flag, key := range serveSettingFlags {
+			if v.Key != key || v.Source == settings.SourceDefault || cmd.Flags().Changed(flag) {
+				continue
+			}
+			if err := cmd.Flags().Set(flag, v.Value); err != nil {
+				return fmt.Errorf("%s from %s: %w", key, v.Source, err)
+			}
+		}
+	}
+	return nil
 }
 
 func runServe(cmd *cobra.Command, args []string) error {
@@ -66,12 +109,21 @@ func runServe(cmd *cobra.Command, args []string) error {
 	defer cancel()
 	serve.StartSessionHeartbeat(ctx, database, session.ID)
 
-	// Read flags
+	// Read flags, falling back to the layered serve settings
+	if err := applyServeSettings(cmd, dir); err != nil {
+		return err
+	}
 	port, _ := cmd.Flags().GetInt("port")
 	addr, _ := cmd.Flags().GetString("addr")
 	token, _ := cmd.Flags().GetString("token")
 	cors, _ := cmd.Flags().GetString("cors")
 	interval, _ := cmd.Flags().GetDuration("interval")
+	dedupeInterval, _ := cmd.Flags().GetDuration("dedupe-interval")
+	retentionInterval, _ := cmd.Flags().GetDuration("retention-interval")
+	compactInterval, _ := cmd.Flags().GetDuration("compact-interval")
+	deliveryInterval, _ := cmd.Flags().GetDuration("delivery-interval")
+	externalPollInterval, _ := cmd.Flags().GetDuration("external-poll-interval")
+	recordPath, _ := cmd.Flags().GetString("record")
 
 	config := serve.ServeConfig{
 		Port:         port,
@@ -79,8 +131,25 @@ func runServe(cmd *cobra.Command, args []string) error {
 		Token:        token,
 		CORSOrigin:   cors,
 		PollInterval: interval,
+
+		DedupeInterval:       dedupeInterval,
+		RetentionInterval:    retentionInterval,
+		CompactInterval:      compactInterval,
+		DeliveryInterval:     deliveryInterval,
+		ExternalPollInterval: externalPollInterval,
+	}
+	if recordPath != "" {
+		f, err := os.OpenFile(recordPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+		if err != nil {
+			return fmt.Errorf("open recording: %w", err)
+		}
+		defer f.Close()
+		config.Record = f
 	}
 
+	useScoreFormula(dir)
+	useTimezone(dir)
+
 	// Create server
 	srv := serve.NewServer(database, dir, session.ID, config)
 
@@ -121,6 +190,9 @@ func runServe(cmd *cobra.Command, args []string) error {
 	fmt.Fprintf(os.Stderr, "  database:   %s\n", dbPath)
 	fmt.Fprintf(os.Stderr, "  session:    %s (web)\n", session.ID)
 	fmt.Fprintf(os.Stderr, "  port file:  %s\n", portFilePath)
+	if recordPath != "" {
+		fmt.Fprintf(os.Stderr, "  recording:  %s\n", recordPath)
+	}
 
 	// Start HTTP server in background
 	srv.StartBackground(ctx)
@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/dateparse"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/output"
+	"github.com/marcus/td/internal/serve"
+	"github.com/marcus/td/internal/session"
+	"github.com/marcus/td/internal/share"
+	"github.com/spf13/cobra"
+)
+
+var shareCmd = &cobra.Command{
+	Use:   "share <issue-id>",
+	Short: "Create a read-only share link for one issue",
+	Long: `Create a revocable, signed link that shows a single issue read-only to
+someone without access to the rest of the project (GET
+/v1/issues/<id>?share_token=... on td serve). Add --comments to include the
+issue's comments.
+
+Links expire after 30 days by default; --expires takes an offset (7d, 2w),
+a date, or "never". Revoke a link with td share revoke.`,
+	Example: `  td share td-a1b2
+  td share td-a1b2 --comments --expires 7d --base-url https://td.example.com
+  td share list
+  td share revoke sh-1a2b3c4d`,
+	GroupID: "workflow",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		baseDir := getBaseDir()
+
+		var expiresAt *time.Time
+		if expires, _ := cmd.Flags().GetString("expires"); !strings.EqualFold(expires, "never") {
+			t, err := dateparse.ParseTimeFrom(expires, time.Now())
+			if err != nil {
+				output.Error("invalid --expires: %v", err)
+				return err
+			}
+			if !t.After(time.Now()) {
+				err := fmt.Errorf("--expires %s is in the past", expires)
+				output.Error("%v", err)
+				return err
+			}
+			expiresAt = &t
+		}
+
+		database, err := db.Open(baseDir)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		issue, err := database.GetIssue(args[0])
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		sess, err := session.GetOrCreate(database)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		secret, err := config.GetShareSecret(baseDir)
+		if err != nil {
+			output.Error("failed to load share secret: %v", err)
+			return err
+		}
+
+		comments, _ := cmd.Flags().GetBool("comments")
+		link := &models.ShareLink{
+			IssueID:         issue.ID,
+			IncludeComments: comments,
+			SessionID:       sess.ID,
+			ExpiresAt:       expiresAt,
+		}
+		if err := database.CreateShareLink(link); err != nil {
+			output.Error("failed to create share link: %v", err)
+			return err
+		}
+
+		path := fmt.Sprintf("/v1/issues/%s?share_token=%s", issue.ID, share.Sign(secret, link.ID, issue.ID))
+		baseURL, _ := cmd.Flags().GetString("base-url")
+		if baseURL == "" {
+			if info, err := serve.ReadPortFile(baseDir); err == nil && info != nil {
+				baseURL = fmt.Sprintf("http://localhost:%d", info.Port)
+			}
+		}
+
+		output.Success("Share link %s created for %s", link.ID, issue.ID)
+		if expiresAt != nil {
+			fmt.Printf("Expires: %s\n", expiresAt.Local().Format("Mon Jan 2 15:04"))
+		}
+		if baseURL != "" {
+			fmt.Println(strings.TrimRight(baseURL, "/") + path)
+		} else {
+			fmt.Println(path)
+			output.Info("Serve it with td serve (and --addr to reach it from other machines)")
+		}
+		return nil
+	},
+}
+
+var shareListCmd = &cobra.Command{
+	Use:   "list [issue-id]",
+	Short: "List active share links",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		issueID := ""
+		if len(args) > 0 {
+			issueID = db.NormalizeIssueID(args[0])
+		}
+		all, _ := cmd.Flags().GetBool("all")
+		links, err := database.ListShareLinks(issueID, all)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			if links == nil {
+				links = []models.ShareLink{}
+			}
+			data, _ := json.MarshalIndent(links, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if len(links) == 0 {
+			output.Info("No share links")
+			return nil
+		}
+		now := time.Now()
+		for _, l := range links {
+			state := "active"
+			switch {
+			case l.RevokedAt != nil:
+				state = "revoked"
+			case !l.Active(now):
+				state = "expired"
+			}
+			expires := "never"
+			if l.ExpiresAt != nil {
+				expires = l.ExpiresAt.Local().Format("2006-01-02 15:04")
+			}
+			extra := ""
+			if l.IncludeComments {
+				extra = "  +comments"
+			}
+			fmt.Printf("%s  %s  %-7s  expires %s%s\n", l.ID, l.IssueID, state, expires, extra)
+		}
+		return nil
+	},
+}
+
+var shareRevokeCmd = &cobra.Command{
+	Use:   "revoke <share-id>",
+	Short: "Revoke a share link",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		if err := database.RevokeShareLink(args[0]); err != nil {
+			if errors.Is(err, db.ErrShareLinkRevoked) {
+				output.Warning("share link %s was already revoked", args[0])
+				return nil
+			}
+			output.Error("%v", err)
+			return err
+		}
+		output.Success("Revoked share link %s", args[0])
+		return nil
+	},
+}
+
+func init() {
+	shareCmd.Flags().Bool("comments", false, "Include the issue's comments")
+	shareCmd.Flags().String("expires", "30d", `When the link expires (offset, date or "never")`)
+	shareCmd.Flags().String("base-url", "", "URL of the td serve instance recipients will use (default: the running local server)")
+	shareListCmd.Flags().Bool("all", false, "Include revoked and expired links")
+	shareListCmd.Flags().Bool("json", false, "Output as JSON")
+	shareCmd.AddCommand(shareListCmd, shareRevokeCmd)
+	rootCmd.AddCommand(shareCmd)
+}
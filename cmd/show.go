@@ -2,12 +2,16 @@ package cmd
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/marcus/td/internal/config"
 	"github.com/marcus/td/internal/db"
 	"github.com/marcus/td/internal/git"
+	"github.com/marcus/td/internal/issuetmpl"
 	"github.com/marcus/td/internal/models"
 	"github.com/marcus/td/internal/output"
+	"github.com/marcus/td/internal/textdiff"
 	"github.com/spf13/cobra"
 )
 
@@ -20,6 +24,7 @@ var showCmd = &cobra.Command{
 Examples:
   td show td-abc1                  # Show issue details
   td show td-abc1 --children       # Show issue with child tasks
+  td show td-abc1 --history        # Show description and comment edits
   td show td-abc1 td-abc2          # Show multiple issues`,
 	GroupID: "core",
 	Args:    cobra.MinimumNArgs(0),
@@ -70,6 +75,28 @@ Examples:
 			return err
 		}
 
+		tmpl, err := issueTemplate(cmd, baseDir)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if tmpl != nil {
+			items := make([]issuetmpl.Data, 0, len(args))
+			for _, id := range args {
+				issue, err := database.GetIssue(id)
+				if err != nil {
+					output.Error("%v", err)
+					return err
+				}
+				revealIssue(database, issue, nil)
+				item := issuetmpl.Data{Issue: *issue}
+				item.Logs, _ = database.GetLogs(issue.ID, 0)
+				item.Handoff, _ = database.GetLatestHandoff(issue.ID)
+				items = append(items, item)
+			}
+			return renderIssueTemplate(tmpl, items)
+		}
+
 		// Handle multiple issues
 		if len(args) > 1 {
 			return showMultipleIssues(cmd, database, args)
@@ -111,6 +138,8 @@ Examples:
 			return err
 		}
 
+		revealIssue(database, issue, nil)
+
 		// Get logs and handoff
 		logs, _ := database.GetLogs(issueID, 0)
 		handoff, _ := database.GetLatestHandoff(issueID)
@@ -122,15 +151,38 @@ Examples:
 		deps, _ := database.GetDependencies(issueID)
 		blocked, _ := database.GetBlockedBy(issueID)
 
+		// Get linked decisions
+		decisions, _ := database.ListDecisions(db.DecisionFilter{IssueID: issue.ID})
+		reworks, _ := database.GetReworks(issue.ID)
+		reviewAcks, _ := database.ListReviewAcks(issue.ID)
+		effort, _ := database.GetIssueEffort(issue)
+		inherited, _ := database.InheritedPriorities()
+		ip, elevated := inherited[issue.ID]
+		var revisions []models.Revision
+		if showHistory, _ := cmd.Flags().GetBool("history"); showHistory {
+			revisions, _ = database.ListRevisions(issue.ID)
+			if issue.Confidential {
+				var texts []*string
+				for i := range revisions {
+					texts = append(texts, &revisions[i].Before, &revisions[i].After)
+				}
+				_, _ = database.RevealText(issue, cliViewer(database), texts...)
+			}
+		}
+
 		// Get git snapshots
 		startSnapshot, _ := database.GetStartSnapshot(issueID)
 		var gitState *git.State
+		var gitDir string
 		var commitsSinceStart int
 		var diffStats *git.DiffStats
 		if startSnapshot != nil {
-			gitState, _ = git.GetState()
-			commitsSinceStart, _ = git.GetCommitsSince(startSnapshot.CommitSHA)
-			diffStats, _ = git.GetDiffStatsSince(startSnapshot.CommitSHA)
+			// Work may have started in another repository of this project
+			gitState, gitDir = gitStateForRepo(database, startSnapshot.Repo)
+			if gitState != nil {
+				commitsSinceStart, _ = git.GetCommitsSinceIn(gitDir, startSnapshot.CommitSHA)
+				diffStats, _ = git.GetDiffStatsSinceIn(gitDir, startSnapshot.CommitSHA)
+			}
 		}
 
 		// Check output format (support both --json and --format json)
@@ -156,6 +208,9 @@ Examples:
 				"updated_at":          issue.UpdatedAt,
 				"minor":               issue.Minor,
 			}
+			if issue.Confidential {
+				result["confidential"] = true
+			}
 			if issue.ClosedAt != nil {
 				result["closed_at"] = issue.ClosedAt
 			}
@@ -165,6 +220,9 @@ Examples:
 			if issue.DueDate != nil {
 				result["due_date"] = *issue.DueDate
 			}
+			if issue.Inbox {
+				result["inbox"] = true
+			}
 			if issue.DeferCount > 0 {
 				result["defer_count"] = issue.DeferCount
 			}
@@ -178,6 +236,24 @@ Examples:
 					"uncertain": handoff.Uncertain,
 				}
 			}
+			if len(decisions) > 0 {
+				result["decisions"] = decisions
+			}
+			if elevated {
+				result["inherited_priority"] = ip
+			}
+			if len(reworks) > 0 {
+				result["reworks"] = reworks
+			}
+			if len(reviewAcks) > 0 {
+				result["review_acks"] = reviewAcks
+			}
+			if len(revisions) > 0 {
+				result["revisions"] = revisions
+			}
+			if effort != nil && len(effort.Contributors) > 0 {
+				result["effort"] = effort
+			}
 			if len(logs) > 0 {
 				logEntries := make([]map[string]interface{}, len(logs))
 				for i, log := range logs {
@@ -196,6 +272,9 @@ Examples:
 					"start_branch": startSnapshot.Branch,
 					"started_at":   startSnapshot.Timestamp,
 				}
+				if startSnapshot.Repo != "" {
+					gitInfo["repo"] = startSnapshot.Repo
+				}
 				if gitState != nil {
 					gitInfo["current_commit"] = gitState.CommitSHA
 					gitInfo["current_branch"] = gitState.Branch
@@ -227,9 +306,23 @@ Examples:
 		// Long format (default)
 		fmt.Print(output.FormatIssueLong(issueForOutput, logs, handoff))
 
+		if elevated {
+			fmt.Print(output.SectionHeader("Inherited Priority"))
+			fmt.Printf("  Effective: %s (own %s)\n", ip.Priority, issue.Priority)
+			if between := ip.Via[1 : len(ip.Via)-1]; len(between) > 0 {
+				fmt.Printf("  Because:   %s (%s) depends on it through %s\n", ip.From, ip.Priority, strings.Join(between, ", "))
+			} else {
+				fmt.Printf("  Because:   %s (%s) depends on it\n", ip.From, ip.Priority)
+			}
+		}
+
 		// Add git state section
 		if startSnapshot != nil {
 			fmt.Print(output.SectionHeader("Git State"))
+			// Name the repository when it isn't the one we're in
+			if startSnapshot.Repo != "" && (gitState == nil || gitDir != "") {
+				fmt.Printf("  Repo:    %s\n", startSnapshot.Repo)
+			}
 			fmt.Printf("  Started: %s (%s) %s\n",
 				output.ShortSHA(startSnapshot.CommitSHA), startSnapshot.Branch, output.FormatTimeAgo(startSnapshot.Timestamp))
 			if gitState != nil {
@@ -242,25 +335,30 @@ Examples:
 			}
 		}
 
-		// Add session history from logs
-		sessionMap := make(map[string]bool)
-		for _, log := range logs {
-			if log.SessionID != "" {
-				sessionMap[log.SessionID] = true
+		// Add session history: contributors by effort, then sessions that
+		// only hold a role or handoff
+		var sessions []string
+		contributions := make(map[string]models.Contribution)
+		if effort != nil {
+			for _, c := range effort.Contributors {
+				sessions = append(sessions, c.SessionID)
+				contributions[c.SessionID] = c
 			}
 		}
-		if handoff != nil && handoff.SessionID != "" {
-			sessionMap[handoff.SessionID] = true
-		}
-		if issue.ImplementerSession != "" {
-			sessionMap[issue.ImplementerSession] = true
+		for _, sess := range []string{issue.ImplementerSession, issue.ReviewerSession} {
+			if sess != "" && !slices.Contains(sessions, sess) {
+				sessions = append(sessions, sess)
+			}
 		}
-		if issue.ReviewerSession != "" {
-			sessionMap[issue.ReviewerSession] = true
+		if handoff != nil && handoff.SessionID != "" && !slices.Contains(sessions, handoff.SessionID) {
+			sessions = append(sessions, handoff.SessionID)
 		}
-		if len(sessionMap) > 0 {
+		if len(sessions) > 0 {
 			fmt.Print(output.SectionHeader("Sessions Involved"))
-			for sess := range sessionMap {
+			if effort != nil && effort.WorkSessions > 0 {
+				fmt.Printf("  %s\n", describeEffort(*effort))
+			}
+			for _, sess := range sessions {
 				role := ""
 				if sess == issue.ImplementerSession {
 					role = " (implementer)"
@@ -268,7 +366,11 @@ Examples:
 				if sess == issue.ReviewerSession {
 					role = " (reviewer)"
 				}
-				fmt.Printf("  %s%s\n", sess, role)
+				if c, ok := contributions[sess]; ok {
+					fmt.Printf("  %s%s\n", describeContribution(c), role)
+				} else {
+					fmt.Printf("  %s%s\n", sess, role)
+				}
 			}
 		}
 
@@ -280,6 +382,37 @@ Examples:
 			}
 		}
 
+		// Show linked decisions
+		if len(decisions) > 0 {
+			fmt.Print(output.SectionHeader("Decisions"))
+			for _, d := range decisions {
+				fmt.Printf("  %s %s: %s\n", d.ID, d.Title, d.Decision)
+			}
+		}
+
+		// Show rework cycles
+		if len(reworks) > 0 {
+			fmt.Print(output.SectionHeader("Rework"))
+			for _, rw := range reworks {
+				line := fmt.Sprintf("  %s %s", rw.CreatedAt.Local().Format("2006-01-02"), rw.Category)
+				if rw.Approved {
+					line += " (after approval)"
+				}
+				if rw.Reason != "" {
+					line += ": " + rw.Reason
+				}
+				fmt.Println(line)
+			}
+		}
+
+		// Show review checklists acknowledged at approval
+		if len(reviewAcks) > 0 {
+			fmt.Print(output.SectionHeader("Review Checklist"))
+			for _, a := range reviewAcks {
+				fmt.Printf("  %s %s: %s\n", a.CreatedAt.Local().Format("2006-01-02"), a.SessionID, strings.Join(a.Items, ", "))
+			}
+		}
+
 		// Show dependencies
 		if len(deps) > 0 {
 			fmt.Print(output.SectionHeader("Blocked By"))
@@ -352,10 +485,28 @@ Examples:
 			}
 		}
 
+		if len(revisions) > 0 {
+			renderRevisions(revisions)
+		}
+
 		return nil
 	},
 }
 
+// renderRevisions prints each edit as a diff, newest first
+func renderRevisions(revisions []models.Revision) {
+	fmt.Print(output.SectionHeader("Revision History"))
+	for _, rev := range revisions {
+		what := rev.Field
+		if rev.Field == models.RevisionComment {
+			what = "comment " + rev.EntityID
+		}
+		fmt.Printf("  %s  %s edited %s by %s\n", rev.ID, what, output.FormatTimeAgo(rev.CreatedAt), rev.SessionID)
+		fmt.Print(output.FormatDiff(textdiff.Lines(rev.Before, rev.After), "    "))
+	}
+	fmt.Println("  Restore an earlier text with: td revert <revision-id>")
+}
+
 // showMultipleIssues displays multiple issues with separators
 func showMultipleIssues(cmd *cobra.Command, database *db.DB, issueIDs []string) error {
 	jsonOutput, _ := cmd.Flags().GetBool("json")
@@ -370,6 +521,7 @@ func showMultipleIssues(cmd *cobra.Command, database *db.DB, issueIDs []string)
 			if err != nil {
 				continue
 			}
+			revealIssue(database, issue, nil)
 			entry := map[string]interface{}{
 				"id":          issue.ID,
 				"title":       issue.Title,
@@ -405,6 +557,7 @@ func showMultipleIssues(cmd *cobra.Command, database *db.DB, issueIDs []string)
 			output.Warning("issue not found: %s", id)
 			continue
 		}
+		revealIssue(database, issue, nil)
 
 		if short {
 			fmt.Println(output.FormatIssueShort(issue))
@@ -462,7 +615,9 @@ func init() {
 	showCmd.Flags().Bool("short", false, "Compact summary")
 	showCmd.Flags().Bool("json", false, "Machine-readable JSON")
 	showCmd.Flags().StringP("format", "f", "", "Output format (json)")
+	showCmd.Flags().String("template", "", "Render each issue with a Go template, or @name (see td config template)")
 	showCmd.Flags().Bool("children", false, "Display child issues inline (alternative to 'td tree')")
 	showCmd.Flags().Bool("tree", false, "Display issue as tree with descendants (alias for 'td tree')")
 	showCmd.Flags().BoolP("render-markdown", "m", false, "Render markdown in description and acceptance")
+	showCmd.Flags().Bool("history", false, "Show edits to the description, acceptance and comments as diffs")
 }
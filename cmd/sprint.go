@@ -0,0 +1,392 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/marcus/td/internal/capacity"
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/dateparse"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/output"
+	"github.com/marcus/td/internal/retro"
+	"github.com/marcus/td/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var sprintCmd = &cobra.Command{
+	Use:   "sprint",
+	Short: "Manage sprint date ranges",
+	Long: `Record start and end dates for sprints. Issues join a sprint with
+td update <id> --sprint <name>; sprint ranges appear in the td serve
+calendar feed (/v1/calendar.ics).`,
+	GroupID: "core",
+}
+
+var sprintSetCmd = &cobra.Command{
+	Use:   "set <name>",
+	Short: "Set a sprint's start and end dates",
+	Example: `  td sprint set s12 --start 2026-03-02 --end 2026-03-13
+  td sprint set s13 --start monday --end +2w`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		startStr, _ := cmd.Flags().GetString("start")
+		endStr, _ := cmd.Flags().GetString("end")
+		if startStr == "" || endStr == "" {
+			err := fmt.Errorf("--start and --end are required")
+			output.Error("%v", err)
+			return err
+		}
+
+		start, err := dateparse.ParseDate(startStr)
+		if err != nil {
+			output.Error("invalid --start: %v", err)
+			return err
+		}
+		end, err := dateparse.ParseDate(endStr)
+		if err != nil {
+			output.Error("invalid --end: %v", err)
+			return err
+		}
+		if end < start {
+			err := fmt.Errorf("end %s is before start %s", end, start)
+			output.Error("%v", err)
+			return err
+		}
+
+		sprint := models.Sprint{Name: args[0], Start: start, End: end}
+		if err := config.SetSprint(getBaseDir(), sprint); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		output.Success("Sprint %s: %s to %s", sprint.Name, sprint.Start, sprint.End)
+		return nil
+	},
+}
+
+var sprintListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List sprints",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sprints, err := config.GetSprints(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			if sprints == nil {
+				sprints = []models.Sprint{}
+			}
+			data, _ := json.MarshalIndent(sprints, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if len(sprints) == 0 {
+			output.Info("No sprints defined")
+			return nil
+		}
+		for _, sp := range sprints {
+			fmt.Printf("%s: %s to %s\n", sp.Name, sp.Start, sp.End)
+		}
+		return nil
+	},
+}
+
+var sprintRmCmd = &cobra.Command{
+	Use:     "rm <name>",
+	Aliases: []string{"remove", "delete"},
+	Short:   "Remove a sprint's date range",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		removed, err := config.RemoveSprint(getBaseDir(), args[0])
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if !removed {
+			err := fmt.Errorf("sprint not found: %s", args[0])
+			output.Error("%v", err)
+			return err
+		}
+		output.Success("Removed sprint %s", args[0])
+		return nil
+	},
+}
+
+var sprintCapacityCmd = &cobra.Command{
+	Use:   "capacity [name]",
+	Short: "Compare committed sprint points with recent throughput",
+	Long: `Shows, per session, the open points it holds in the sprint against what
+it closed over the last --window days, scaled to the sprint days left.
+Sessions and sprints whose load is above 100% are over-committed.
+Without a name, uses the sprint in progress (or the next one).`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		baseDir := getBaseDir()
+		name := ""
+		if len(args) == 1 {
+			name = args[0]
+		}
+		sprint, err := resolveSprint(baseDir, name)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		window, _ := cmd.Flags().GetInt("window")
+		if window < 1 || window > capacity.MaxWindowDays {
+			err := fmt.Errorf("--window must be between 1 and %d days", capacity.MaxWindowDays)
+			output.Error("%v", err)
+			return err
+		}
+
+		database, err := db.Open(baseDir)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		report, err := capacity.Compute(database, sprint, window, time.Now())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			data, _ := json.MarshalIndent(report, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+		renderCapacity(report)
+		return nil
+	},
+}
+
+var sprintRetroCmd = &cobra.Command{
+	Use:   "retro [name]",
+	Short: "Show a sprint's retrospective",
+	Long: `Shows what went well, what needs improvement and the action items
+recorded for a sprint, next to the sprint's delivery metrics. Action items
+are tracked as issues labelled "retro"; their status is shown alongside.
+Without a name, uses the sprint in progress (or the next one).`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		baseDir := getBaseDir()
+		name := ""
+		if len(args) == 1 {
+			name = args[0]
+		}
+		sprint, err := resolveSprint(baseDir, name)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		database, err := db.Open(baseDir)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		report, err := retro.Compute(database, sprint)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			data, _ := json.MarshalIndent(report, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+		renderRetro(report)
+		return nil
+	},
+}
+
+var sprintRetroAddCmd = &cobra.Command{
+	Use:   "add <kind> <text>",
+	Short: "Add a retro item",
+	Long: `Add a retro item. kind is went-well (or well), needs-improvement (or
+improve) or action. An action item also creates a task labelled "retro".`,
+	Example: `  td sprint retro add went-well "Reviews turned around same day"
+  td sprint retro add improve "CI flaked on most PRs"
+  td sprint retro add action "Quarantine flaky tests" --sprint s12`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		kind := models.NormalizeRetroKind(args[0])
+		if !models.IsValidRetroKind(kind) {
+			err := fmt.Errorf("invalid kind %q: use went-well, needs-improvement or action", args[0])
+			output.Error("%v", err)
+			return err
+		}
+
+		baseDir := getBaseDir()
+		name, _ := cmd.Flags().GetString("sprint")
+		sprint, err := resolveSprint(baseDir, name)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		database, err := db.Open(baseDir)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		sess, err := session.GetOrCreate(database)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		item, err := retro.AddItem(database, sprint.Name, kind, args[1], sess.ID)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if item.IssueID != "" {
+			fmt.Printf("ADDED %s to %s (created %s)\n", item.ID, sprint.Name, item.IssueID)
+		} else {
+			fmt.Printf("ADDED %s to %s\n", item.ID, sprint.Name)
+		}
+		return nil
+	},
+}
+
+var sprintRetroRmCmd = &cobra.Command{
+	Use:     "rm <item-id>",
+	Aliases: []string{"remove", "delete"},
+	Short:   "Remove a retro item (an action item's issue is kept)",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		if err := database.DeleteRetroItem(args[0]); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		fmt.Printf("DELETED %s\n", args[0])
+		return nil
+	},
+}
+
+// resolveSprint finds a sprint by name, or with an empty name the sprint
+// in progress (or the next one)
+func resolveSprint(baseDir, name string) (models.Sprint, error) {
+	sprints, err := config.GetSprints(baseDir)
+	if err != nil {
+		return models.Sprint{}, err
+	}
+	if name == "" {
+		if sprint, ok := capacity.CurrentSprint(sprints, time.Now()); ok {
+			return sprint, nil
+		}
+		return models.Sprint{}, fmt.Errorf("no sprint defined: add one with td sprint set")
+	}
+	for _, sp := range sprints {
+		if sp.Name == name {
+			return sp, nil
+		}
+	}
+	return models.Sprint{}, fmt.Errorf("sprint not found: %s", name)
+}
+
+// renderRetro prints a retro report
+func renderRetro(r *retro.Report) {
+	m := r.Metrics
+	fmt.Printf("Sprint %s: %s to %s\n", r.Sprint.Name, r.Sprint.Start, r.Sprint.End)
+	fmt.Printf("%d issues, %d closed, %d carried over (%d blocked), %d bugs\n", m.Issues, m.Closed, m.CarriedOver, m.Blocked, m.Bugs)
+	fmt.Printf("%d of %d pts done, %.0f%% complete\n", m.DonePoints, m.Points, m.Completion*100)
+
+	printItems := func(title string, items []models.RetroItem) {
+		fmt.Print(output.SectionHeader(title))
+		if len(items) == 0 {
+			fmt.Println("  (none)")
+		}
+		for _, item := range items {
+			fmt.Printf("  %s  %s\n", item.ID, item.Text)
+		}
+	}
+	printItems("Went Well", r.WentWell)
+	printItems("Needs Improvement", r.NeedsImprovement)
+
+	fmt.Print(output.SectionHeader("Action Items"))
+	if len(r.ActionItems) == 0 {
+		fmt.Println("  (none)")
+	}
+	for _, a := range r.ActionItems {
+		status := string(a.IssueStatus)
+		if status == "" {
+			status = "deleted"
+		}
+		fmt.Printf("  %s  %s  [%s %s]\n", a.ID, a.Text, a.IssueID, status)
+	}
+}
+
+// renderCapacity prints a capacity report as a table
+func renderCapacity(r *capacity.Report) {
+	fmt.Printf("Sprint %s: %s to %s, %d of %d days left (throughput over %d days)\n\n",
+		r.Sprint.Name, r.Sprint.Start, r.Sprint.End, r.RemainingDays, r.Days, r.WindowDays)
+
+	if len(r.Sessions) > 0 {
+		fmt.Printf("%-28s %9s %9s %6s %6s\n", "SESSION", "COMMITTED", "CAPACITY", "LOAD", "DONE")
+		for _, l := range r.Sessions {
+			name := l.Session
+			if l.Name != "" {
+				name = fmt.Sprintf("%s (%s)", l.Name, l.Session)
+			}
+			load := "-"
+			if !l.NoHistory {
+				load = fmt.Sprintf("%.0f%%", l.Load*100)
+			}
+			line := fmt.Sprintf("%-28s %9d %9.1f %6s %6d", name, l.Committed, l.Capacity, load, l.Done)
+			if l.OverCommitted {
+				line += "  over-committed"
+			} else if l.NoHistory && l.Committed > 0 {
+				line += "  no recent history"
+			}
+			fmt.Println(line)
+		}
+		fmt.Println()
+	}
+
+	fmt.Printf("Committed %d pts (%d unassigned), capacity %.1f pts, %d done\n", r.Committed, r.Unassigned, r.Capacity, r.Done)
+	if r.Unestimated > 0 {
+		fmt.Printf("%d open issues have no points\n", r.Unestimated)
+	}
+	if r.OverCommitted {
+		output.Warning("sprint %s is over-committed by %.1f pts", r.Sprint.Name, float64(r.Committed)-r.Capacity)
+	}
+}
+
+func init() {
+	sprintCapacityCmd.Flags().Int("window", capacity.DefaultWindowDays, "Days of history used for throughput")
+	sprintCapacityCmd.Flags().Bool("json", false, "Output as JSON")
+	sprintSetCmd.Flags().String("start", "", "Start date (e.g. 2026-03-02, monday, +1w)")
+	sprintSetCmd.Flags().String("end", "", "End date, inclusive")
+	sprintListCmd.Flags().Bool("json", false, "Output as JSON")
+	sprintRetroCmd.Flags().Bool("json", false, "Output as JSON")
+	sprintRetroAddCmd.Flags().String("sprint", "", "Sprint name (default: current sprint)")
+	sprintRetroCmd.AddCommand(sprintRetroAddCmd, sprintRetroRmCmd)
+	sprintCmd.AddCommand(sprintSetCmd, sprintListCmd, sprintRmCmd, sprintCapacityCmd, sprintRetroCmd)
+	rootCmd.AddCommand(sprintCmd)
+}
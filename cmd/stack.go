@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/dependency"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/output"
+	"github.com/marcus/td/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var stackCmd = &cobra.Command{
+	Use:   "stack <issue>",
+	Short: "Manage stacked issues that must land in order",
+	Long: `Manage stacks: chains of issues that must land in order, like stacked
+diffs. An issue stacked on another stays out of td ready, td next and the
+monitor's ready section until the one below it is closed.
+
+Usage:
+  td stack add <issue> <base>   Stack issue on base
+  td stack rm <issue>           Take issue off its base
+  td stack <issue>              Show the stack issue belongs to
+
+Examples:
+  td stack add td-b2 td-a1    # td-b2 lands after td-a1
+  td stack add td-c3 td-b2    # then td-c3
+  td stack td-c3              # td-a1 -> td-b2 -> td-c3`,
+	GroupID: "workflow",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		issue, err := database.GetIssue(args[0])
+		if err != nil {
+			output.Error("issue not found: %s", args[0])
+			return err
+		}
+
+		bases, err := database.GetStackBases()
+		if err != nil {
+			output.Error("failed to get stacks: %v", err)
+			return err
+		}
+		chain := dependency.Chain(bases, issue.ID)
+		issues := make([]models.Issue, 0, len(chain))
+		for _, id := range chain {
+			if i, err := database.GetIssue(id); err == nil {
+				issues = append(issues, *i)
+			}
+		}
+
+		if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+			return output.JSON(map[string]interface{}{
+				"issue": issue.ID,
+				"stack": issues,
+			})
+		}
+
+		if len(issues) == 0 {
+			fmt.Printf("%s is not stacked\n", issue.ID)
+			return nil
+		}
+		fmt.Printf("STACK (%d issues, landing in this order):\n", len(issues))
+		for n, i := range issues {
+			marker := " "
+			if i.ID == issue.ID {
+				marker = "*"
+			}
+			fmt.Printf("%s %d. %s\n", marker, n+1, output.FormatIssueShort(&i))
+		}
+		return nil
+	},
+}
+
+var stackAddCmd = &cobra.Command{
+	Use:   "add <issue> <base>",
+	Short: "Stack an issue on another so it lands after it",
+	Long: `Stacks an issue on a base issue: it lands after the base, and stays out
+of the ready queue until the base is closed. An issue already stacked
+elsewhere moves. Only one issue can sit directly on a base, so each stack
+stays a single chain.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		sess, err := session.GetOrCreate(database)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		err = dependency.ValidateStack(database, args[0], args[1])
+		if err == dependency.ErrStackExists {
+			output.Warning("%s is already stacked on %s", args[0], args[1])
+			return nil
+		}
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		issue, _ := database.GetIssue(args[0])
+		base, _ := database.GetIssue(args[1])
+
+		// Moving to a new base leaves the old one
+		oldBase, err := stackBase(database, issue.ID)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if oldBase != "" {
+			if err := database.RemoveRelationLogged(issue.ID, oldBase, models.RelationStackedOn, sess.ID); err != nil {
+				output.Error("failed to unstack: %v", err)
+				return err
+			}
+		}
+
+		if err := database.AddDependencyLogged(issue.ID, base.ID, models.RelationStackedOn, sess.ID); err != nil {
+			output.Error("failed to stack: %v", err)
+			return err
+		}
+
+		fmt.Printf("STACKED: %s lands after %s\n", issue.ID, base.ID)
+		fmt.Printf("  %s: %s\n", base.ID, base.Title)
+		fmt.Printf("  └── %s: %s\n", issue.ID, issue.Title)
+		return nil
+	},
+}
+
+var stackRmCmd = &cobra.Command{
+	Use:     "rm <issue>",
+	Aliases: []string{"remove"},
+	Short:   "Take an issue off its stack base",
+	Long: `Takes an issue off the issue it is stacked on. Issues stacked on it stay
+stacked on it, starting a stack of their own.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		sess, err := session.GetOrCreate(database)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		issue, err := database.GetIssue(args[0])
+		if err != nil {
+			output.Error("issue not found: %s", args[0])
+			return err
+		}
+
+		base, err := stackBase(database, issue.ID)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if base == "" {
+			err := fmt.Errorf("%s is not stacked on anything", issue.ID)
+			output.Error("%v", err)
+			return err
+		}
+
+		if err := database.RemoveRelationLogged(issue.ID, base, models.RelationStackedOn, sess.ID); err != nil {
+			output.Error("failed to unstack: %v", err)
+			return err
+		}
+
+		fmt.Printf("UNSTACKED: %s no longer lands after %s\n", issue.ID, base)
+		return nil
+	},
+}
+
+// stackBase returns the issue issueID is stacked on, or "" if none
+func stackBase(database *db.DB, issueID string) (string, error) {
+	bases, err := database.GetStackBases()
+	if err != nil {
+		return "", fmt.Errorf("failed to get stacks: %w", err)
+	}
+	return bases[issueID], nil
+}
+
+func init() {
+	rootCmd.AddCommand(stackCmd)
+	stackCmd.AddCommand(stackAddCmd)
+	stackCmd.AddCommand(stackRmCmd)
+
+	stackCmd.Flags().Bool("json", false, "JSON output")
+}
@@ -99,6 +99,9 @@ Examples:
 				continue
 			}
 
+			// Getting past a blocked status is a --force override
+			forced := issue.Status == models.StatusBlocked
+
 			// Run guards (for advisory warnings in future)
 			if results, _ := sm.Validate(ctx); len(results) > 0 {
 				for _, r := range results {
@@ -122,6 +125,9 @@ Examples:
 			if err := database.RecordSessionAction(issueID, sess.ID, models.ActionSessionStarted); err != nil {
 				output.Warning("failed to record session history: %v", err)
 			}
+			if forced {
+				recordOverride(database, issueID, sess.ID, models.OverrideForceStart, reason)
+			}
 
 			// Log the start
 			logMsg := "Started work"
@@ -143,6 +149,7 @@ Examples:
 					Event:      "start",
 					CommitSHA:  gitState.CommitSHA,
 					Branch:     gitState.Branch,
+					Repo:       gitState.Repo,
 					DirtyFiles: gitState.DirtyFiles,
 				})
 			}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"github.com/marcus/td/internal/heatmap"
 	"github.com/spf13/cobra"
 )
 
@@ -12,10 +13,34 @@ var statsCmd = &cobra.Command{
 Subcommands:
   analytics  - Command usage statistics (most/least used, never used)
   security   - Security exception audit log
-  errors     - Failed command attempts`,
+  errors     - Failed command attempts
+
+With --calendar, shows project activity as a contribution calendar: actions
+per day over the last --months, Monday to Sunday rows, darker cells for busier
+days. --session and --type count only one session's or one action type's
+actions.`,
+	Example: `  td stats --calendar
+  td stats --calendar --months 3 --session ses_a1b2c3`,
 	GroupID: "system",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if calendar, _ := cmd.Flags().GetBool("calendar"); !calendar {
+			return cmd.Help()
+		}
+		cmd.SilenceUsage = true
+		months, _ := cmd.Flags().GetInt("months")
+		sessionID, _ := cmd.Flags().GetString("session")
+		actionType, _ := cmd.Flags().GetString("type")
+		jsonOut, _ := cmd.Flags().GetBool("json")
+		return runStatsCalendar(months, sessionID, actionType, jsonOut)
+	},
 }
 
 func init() {
+	statsCmd.Flags().Bool("calendar", false, "Show activity per day as a contribution calendar")
+	statsCmd.Flags().Int("months", heatmap.DefaultMonths, "Months of activity for --calendar")
+	statsCmd.Flags().String("session", "", "Only count this session's actions (--calendar)")
+	statsCmd.Flags().String("type", "", "Only count this action type, e.g. close (--calendar)")
+	statsCmd.Flags().Bool("json", false, "JSON output (--calendar)")
 	rootCmd.AddCommand(statsCmd)
 }
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/heatmap"
+	"github.com/marcus/td/internal/output"
+)
+
+// calendarLevels are the cells for heatmap levels 0 to heatmap.Levels
+var calendarLevels = []string{"·", "░", "▒", "▓", "█"}
+
+// runStatsCalendar prints the project's activity as a contribution calendar
+func runStatsCalendar(months int, sessionID, actionType string, jsonOut bool) error {
+	database, err := db.Open(getBaseDir())
+	if err != nil {
+		output.Error("%v", err)
+		return err
+	}
+	defer database.Close()
+
+	if months < 1 || months > heatmap.MaxMonths {
+		err := fmt.Errorf("--months must be from 1 to %d", heatmap.MaxMonths)
+		output.Error("%v", err)
+		return err
+	}
+	report, err := heatmap.Compute(database, heatmap.Options{Months: months, SessionID: sessionID, Type: actionType}, clock.Now())
+	if err != nil {
+		output.Error("failed to compute activity: %v", err)
+		return err
+	}
+	if jsonOut {
+		return output.JSON(report)
+	}
+
+	fmt.Println(analyticsHeaderStyle.Render(fmt.Sprintf("Activity %s to %s", report.From, report.To)))
+	fmt.Println()
+	for _, line := range renderCalendar(report) {
+		fmt.Println(line)
+	}
+	fmt.Println()
+	fmt.Printf("%s %s\n", analyticsLabelStyle.Render("Less"), strings.Join(calendarLevels, " ")+" "+analyticsLabelStyle.Render("More"))
+	fmt.Printf("%s on %s · current streak %s · longest %s\n",
+		analyticsValueStyle.Render(plural(report.Total, "action")), analyticsValueStyle.Render(plural(report.ActiveDays, "day")),
+		plural(report.CurrentStreak, "day"), plural(report.LongestStreak, "day"))
+	if line := topCounts(report.Sessions, 3); line != "" {
+		fmt.Printf("%s %s\n", analyticsLabelStyle.Render("Sessions:"), line)
+	}
+	if line := topCounts(report.Types, 5); line != "" {
+		fmt.Printf("%s %s\n", analyticsLabelStyle.Render("Types:   "), line)
+	}
+	return nil
+}
+
+// renderCalendar lays the days out as week columns and weekday rows,
+// Monday first, under a row of month labels
+func renderCalendar(r *heatmap.Report) []string {
+	if len(r.Days) == 0 {
+		return nil
+	}
+	first, _ := time.Parse("2006-01-02", r.Days[0].Date)
+	offset := (int(first.Weekday()) + 6) % 7 // days since Monday
+	weeks := (offset + len(r.Days) + 6) / 7
+
+	grid := make([][]string, 7)
+	for row := range grid {
+		grid[row] = make([]string, weeks)
+		for col := range grid[row] {
+			grid[row][col] = " "
+		}
+	}
+	months := make([]byte, weeks+3)
+	for i := range months {
+		months[i] = ' '
+	}
+	// Each month is labelled above the week it starts in; the partial
+	// first month only when its label fits before the next one
+	nextLabel := len(months)
+	for i := len(r.Days) - 1; i >= 0; i-- {
+		pos := offset + i
+		col, row := pos/7, pos%7
+		cell := calendarLevels[r.Days[i].Level]
+		if r.Days[i].Level == 0 {
+			cell = analyticsLabelStyle.Render(cell)
+		}
+		grid[row][col] = cell
+
+		d := first.AddDate(0, 0, i)
+		if (d.Day() == 1 || i == 0) && col+4 <= nextLabel {
+			copy(months[col:], d.Format("Jan"))
+			nextLabel = col
+		}
+	}
+
+	labels := []string{"Mon", "", "Wed", "", "Fri", "", "Sun"}
+	lines := []string{"    " + analyticsLabelStyle.Render(strings.TrimRight(string(months), " "))}
+	for row := range grid {
+		lines = append(lines, fmt.Sprintf("%-4s", labels[row])+strings.Join(grid[row], ""))
+	}
+	return lines
+}
+
+// topCounts formats the first n counts as "key count, ..."
+func topCounts(counts []heatmap.Count, n int) string {
+	var parts []string
+	for i, c := range counts {
+		if i == n {
+			parts = append(parts, fmt.Sprintf("+%d more", len(counts)-n))
+			break
+		}
+		parts = append(parts, fmt.Sprintf("%s %d", c.Key, c.Count))
+	}
+	return strings.Join(parts, ", ")
+}
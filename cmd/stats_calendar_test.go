@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/heatmap"
+)
+
+func TestRenderCalendar(t *testing.T) {
+	// 2026-10-17 is a Saturday; the month starts on a Thursday
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	events := []db.ActivityEvent{
+		{SessionID: "ses_a", ActionType: "create", At: time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC)},
+		{SessionID: "ses_a", ActionType: "update", At: time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)},
+		{SessionID: "ses_a", ActionType: "update", At: time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)},
+	}
+	report := heatmap.Build(events, heatmap.Options{Months: 1}, now, time.UTC)
+
+	lines := renderCalendar(report)
+	if len(lines) != 8 {
+		t.Fatalf("lines = %d, want month labels and 7 weekdays", len(lines))
+	}
+	if strings.Contains(lines[0], "Sep") || !strings.Contains(lines[0], "Oct") {
+		t.Errorf("month labels = %q, want only Oct", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "Mon") || !strings.HasPrefix(lines[7], "Sun") {
+		t.Errorf("weekday rows = %q .. %q", lines[1], lines[7])
+	}
+	// Monday the 12th is half the busiest day; Saturday the 17th the busiest
+	if !strings.HasSuffix(lines[1], "▒") {
+		t.Errorf("Monday row = %q, want the last week at level 2", lines[1])
+	}
+	if !strings.HasSuffix(lines[6], "█") {
+		t.Errorf("Saturday row = %q, want today at level 4", lines[6])
+	}
+
+	if got := topCounts(report.Types, 1); got != "update 2, +1 more" {
+		t.Errorf("topCounts = %q", got)
+	}
+}
@@ -116,7 +116,11 @@ var syncCmd = &cobra.Command{
 		}
 
 		if !pullOnly {
-			if err := runPush(database, client, syncState, deviceID); err != nil {
+			err := runPush(database, client, syncState, deviceID)
+			if recErr := database.RecordPushAttempt(err); recErr != nil {
+				slog.Debug("sync: record push attempt", "err", recErr)
+			}
+			if err != nil {
 				return err
 			}
 		}
@@ -331,6 +335,7 @@ func runPush(database *db.DB, client *syncclient.Client, state *db.SyncState, de
 	var maxActionID int64
 	totalAccepted := 0
 	var allHistoryEntries []db.SyncHistoryEntry
+	rejected := make(map[int64]string) // refused for reasons other than being a duplicate
 
 	// Push in batches to stay within server limits
 	for i := 0; i < len(events); i += pushBatchSize {
@@ -378,7 +383,11 @@ func runPush(database *db.DB, client *syncclient.Client, state *db.SyncState, de
 		}
 		// Treat duplicate rejections as idempotent success — mark them synced too
 		for _, r := range pushResp.Rejected {
-			if r.Reason == "duplicate" && r.ServerSeq > 0 {
+			if r.Reason != "duplicate" {
+				rejected[r.ClientActionID] = r.Reason
+				continue
+			}
+			if r.ServerSeq > 0 {
 				allAcks = append(allAcks, tdsync.Ack{
 					ClientActionID: r.ClientActionID,
 					ServerSeq:      r.ServerSeq,
@@ -409,6 +418,15 @@ func runPush(database *db.DB, client *syncclient.Client, state *db.SyncState, de
 		}
 	}
 
+	acked := make([]int64, len(allAcks))
+	for i, a := range allAcks {
+		acked[i] = a.ClientActionID
+	}
+	if err := db.RecordPushRejectionsTx(tx, rejected, acked); err != nil {
+		output.Error("record rejections: %v", err)
+		return err
+	}
+
 	if err := tdsync.MarkEventsSynced(tx, allAcks); err != nil {
 		output.Error("mark synced: %v", err)
 		return err
@@ -432,6 +450,9 @@ func runPush(database *db.DB, client *syncclient.Client, state *db.SyncState, de
 	}
 
 	fmt.Printf("Pushed %d events.\n", totalAccepted)
+	if len(rejected) > 0 {
+		output.Warning("server rejected %d events; they stay queued (see td outbox list)", len(rejected))
+	}
 	return nil
 }
 
@@ -5,15 +5,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
 
+	"github.com/marcus/td/internal/config"
 	"github.com/marcus/td/internal/db"
 	"github.com/marcus/td/internal/models"
 	"github.com/marcus/td/internal/output"
 	"github.com/marcus/td/internal/session"
+	"github.com/marcus/td/internal/triage"
 	"github.com/marcus/td/internal/version"
 	"github.com/spf13/cobra"
 )
@@ -45,8 +46,7 @@ var infoCmd = &cobra.Command{
 			return err
 		}
 
-		// Get project name from directory
-		projectName := filepath.Base(baseDir)
+		projectName := config.GetProjectName(baseDir)
 
 		// Review queue
 		reviewable, _ := database.ListIssues(reviewableByOptions(baseDir, sess.ID))
@@ -171,8 +171,11 @@ var versionCmd = &cobra.Command{
 }
 
 var whoamiCmd = &cobra.Command{
-	Use:     "whoami",
-	Short:   "Show current session identity",
+	Use:   "whoami",
+	Short: "Show current session identity",
+	Long: `Show the session this repo, branch and agent resolve to, and how the
+agent was detected: TD_SESSION_ID, an agent found among the parent
+processes, or the terminal. td session switch selects a different session.`,
 	GroupID: "session",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		baseDir := getBaseDir()
@@ -192,9 +195,26 @@ var whoamiCmd = &cobra.Command{
 
 		// Get issues touched by this session
 		touchedIssues, _ := database.GetIssueSessionLog(sess.ID)
+		fp := session.GetAgentFingerprint()
+
+		if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+			data, _ := json.MarshalIndent(map[string]interface{}{
+				"session":        sess,
+				"fingerprint":    fp.String(),
+				"detected_via":   fp.Source(),
+				"issues_touched": touchedIssues,
+			}, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
 
 		fmt.Printf("SESSION: %s\n", sess.Display())
 		fmt.Printf("STARTED: %s\n", sess.StartedAt.Format("2006-01-02T15:04:05Z"))
+		fmt.Printf("BRANCH: %s\n", sess.Branch)
+		if sess.Repo != "" {
+			fmt.Printf("REPO: %s\n", sess.Repo)
+		}
+		fmt.Printf("AGENT: %s (detected via %s)\n", fp.String(), fp.Source())
 
 		if sess.PreviousSessionID != "" {
 			fmt.Printf("PREVIOUS SESSION: %s\n", sess.PreviousSessionID)
@@ -275,6 +295,53 @@ var sessionNameCmd = &cobra.Command{
 	},
 }
 
+var sessionSwitchCmd = &cobra.Command{
+	Use:   "switch <id|name>",
+	Short: "Make this branch and agent act as another session",
+	Long: `Select the session this repo, branch and agent act as, instead of the
+one detected from the environment. The session is rebound to the current
+context, so an agent or terminal it belonged to gets a session of its own
+on its next command.
+
+Switching is refused when it would let a context approve its own work:
+when the current session implemented issues in review that the target
+did not, or when the target implemented issues in review and belongs to
+another context, which would be left with a fresh session.`,
+	Example: `  td session switch ses_a1b2c3
+  td session switch reviewer-bot`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		res, err := session.Switch(database, args[0])
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if res.Previous != nil && res.Previous.ID == res.Session.ID {
+			fmt.Printf("ALREADY ON SESSION: %s\n", res.Session.DisplayWithAgent())
+			return nil
+		}
+
+		was := res.Was
+		if was.AgentType != res.Session.AgentType || was.AgentPID != res.Session.AgentPID || was.Branch != res.Session.Branch {
+			output.Warning("%s was bound to %s on branch %s; that context will get a new session", was.ID, was.AgentType, was.Branch)
+		}
+		if res.Previous != nil {
+			fmt.Printf("SWITCHED SESSION: %s → %s on branch: %s\n", res.Previous.Display(), res.Session.Display(), res.Session.Branch)
+		} else {
+			fmt.Printf("SWITCHED SESSION: %s on branch: %s\n", res.Session.Display(), res.Session.Branch)
+		}
+		return nil
+	},
+}
+
 var sessionListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all sessions (branch + agent scoped)",
@@ -516,6 +583,8 @@ var importCmd = &cobra.Command{
 		dryRun, _ := cmd.Flags().GetBool("dry-run")
 		force, _ := cmd.Flags().GetBool("force")
 		format, _ := cmd.Flags().GetString("format")
+		inbox, _ := cmd.Flags().GetBool("inbox")
+		inbox = inbox || triage.Routes(baseDir, triage.SourceImport)
 
 		// Auto-detect format from extension if not specified
 		if format == "" || format == "json" {
@@ -539,9 +608,9 @@ var importCmd = &cobra.Command{
 		var imported int
 
 		if format == "md" {
-			imported, err = importMarkdown(database, string(data), dryRun, force, sess.ID)
+			imported, err = importMarkdown(database, string(data), dryRun, force, inbox, sess.ID)
 		} else {
-			imported, err = importJSON(database, data, dryRun, force, sess.ID)
+			imported, err = importJSON(database, data, dryRun, force, inbox, sess.ID)
 		}
 
 		if err != nil {
@@ -555,8 +624,9 @@ var importCmd = &cobra.Command{
 	},
 }
 
-// importJSON imports issues from JSON format
-func importJSON(database *db.DB, data []byte, dryRun, force bool, sessionID string) (int, error) {
+// importJSON imports issues from JSON format. With inbox set, new open
+// issues land in the triage inbox.
+func importJSON(database *db.DB, data []byte, dryRun, force, inbox bool, sessionID string) (int, error) {
 	var importData []map[string]json.RawMessage
 	if err := json.Unmarshal(data, &importData); err != nil {
 		return 0, fmt.Errorf("failed to parse JSON: %v", err)
@@ -600,6 +670,9 @@ func importJSON(database *db.DB, data []byte, dryRun, force bool, sessionID stri
 			continue
 		}
 
+		if inbox && existing == nil && issue.Status != models.StatusClosed {
+			issue.Inbox = true
+		}
 		if err := database.UpsertIssueRaw(&issue); err != nil {
 			output.Warning("failed to import '%s': %v", issue.Title, err)
 			continue
@@ -673,7 +746,7 @@ func importJSON(database *db.DB, data []byte, dryRun, force bool, sessionID stri
 //	- Points: 3
 //	- Labels: label1, label2
 //	Description text
-func importMarkdown(database *db.DB, data string, dryRun, force bool, sessionID string) (int, error) {
+func importMarkdown(database *db.DB, data string, dryRun, force, inbox bool, sessionID string) (int, error) {
 	scanner := bufio.NewScanner(strings.NewReader(data))
 	imported := 0
 
@@ -682,8 +755,8 @@ func importMarkdown(database *db.DB, data string, dryRun, force bool, sessionID
 	inDescription := false
 
 	// Regex patterns
-	// Match "## td-xxxx: Title" or "## Title"
-	headerWithIDRegex := regexp.MustCompile(`^##\s+(td-[a-f0-9]+):\s*(.+)$`)
+	// Match "## td-xxxx: Title" (any issue prefix) or "## Title"
+	headerWithIDRegex := regexp.MustCompile(`^##\s+([a-z][a-z0-9]{0,9}-[a-f0-9]+):\s*(.+)$`)
 	headerRegex := regexp.MustCompile(`^##\s+(.+)$`)
 	statusRegex := regexp.MustCompile(`^-\s*Status:\s*(.+)$`)
 	typeRegex := regexp.MustCompile(`^-\s*Type:\s*(.+)$`)
@@ -728,6 +801,7 @@ func importMarkdown(database *db.DB, data string, dryRun, force bool, sessionID
 					imported++
 				}
 			} else {
+				currentIssue.Inbox = inbox
 				if err := database.CreateIssueLogged(currentIssue, sessionID); err != nil {
 					output.Warning("failed to import '%s': %v", currentIssue.Title, err)
 				} else {
@@ -879,6 +953,7 @@ func init() {
 	rootCmd.AddCommand(upgradeCmd)
 
 	infoCmd.Flags().Bool("json", false, "JSON output")
+	whoamiCmd.Flags().Bool("json", false, "JSON output")
 
 	exportCmd.Flags().String("format", "json", "Export format: json or md")
 	exportCmd.Flags().StringP("output", "o", "", "Output file (default: stdout)")
@@ -888,11 +963,13 @@ func init() {
 	importCmd.Flags().String("format", "json", "Import format: json or md")
 	importCmd.Flags().Bool("dry-run", false, "Preview changes")
 	importCmd.Flags().Bool("force", false, "Overwrite existing")
+	importCmd.Flags().Bool("inbox", false, "Put new issues in the triage inbox (see td inbox)")
 
 	sessionNameCmd.Flags().Bool("new", false, "Force create a new session")
 
 	// Session subcommands
 	sessionNameCmd.AddCommand(sessionListCmd)
+	sessionNameCmd.AddCommand(sessionSwitchCmd)
 	sessionNameCmd.AddCommand(sessionCleanupCmd)
 	sessionCleanupCmd.Flags().String("older-than", "7d", "Delete sessions older than this duration")
 	sessionCleanupCmd.Flags().Bool("force", false, "Actually delete (otherwise preview)")
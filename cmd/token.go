@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/dateparse"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/output"
+	"github.com/marcus/td/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var tokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Manage session-scoped API tokens for td serve",
+	Long: `Manage API tokens for agents that talk to td serve over HTTP instead of
+opening the local database.
+
+A token is bound to a session: requests made with it act as that session,
+so issues it creates, starts or reviews are attributed to the agent rather
+than to the server's shared web session. Scopes limit what it can do: read
+allows GET requests, write everything else, and admin the /v1/admin repair
+endpoints (see td admin). Tokens are accepted whether or
+not td serve was started with --token, and can also be listed and revoked
+over HTTP at /v1/tokens.`,
+	Example: `  td token create --name ci-agent --scope read,write --ttl 30d
+  td token list
+  td token revoke tk-1a2b3c4d`,
+	GroupID: "system",
+}
+
+var tokenCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create an API token bound to a session",
+	Long: `Create an API token for td serve, bound to the current session or the
+one given with --session. The token is printed once; only a hash of it is
+stored.
+
+--ttl takes an offset (30m, 12h, 30d, 2w) or "never".`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		scopeFlag, _ := cmd.Flags().GetString("scope")
+		scopes, err := models.ParseTokenScopes(scopeFlag)
+		if err != nil {
+			output.Error("invalid --scope: %v", err)
+			return err
+		}
+
+		var expiresAt *time.Time
+		if ttl, _ := cmd.Flags().GetString("ttl"); !strings.EqualFold(ttl, "never") {
+			d, err := dateparse.ParseOffset(ttl)
+			if err != nil {
+				output.Error("invalid --ttl: %v", err)
+				return err
+			}
+			t := time.Now().Add(d)
+			expiresAt = &t
+		}
+
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		sessionID, _ := cmd.Flags().GetString("session")
+		if sessionID == "" {
+			sess, err := session.GetOrCreate(database)
+			if err != nil {
+				output.Error("%v", err)
+				return err
+			}
+			sessionID = sess.ID
+		} else if row, err := database.GetSessionByID(sessionID); err != nil || row == nil {
+			if err == nil {
+				err = fmt.Errorf("session not found: %s", sessionID)
+			}
+			output.Error("%v", err)
+			return err
+		}
+
+		name, _ := cmd.Flags().GetString("name")
+		tok := &models.APIToken{
+			Name:      name,
+			SessionID: sessionID,
+			Scopes:    scopes,
+			ExpiresAt: expiresAt,
+		}
+		secret, err := database.CreateAPIToken(tok)
+		if err != nil {
+			output.Error("failed to create token: %v", err)
+			return err
+		}
+
+		output.Success("Token %s created for session %s (%s)", tok.ID, sessionID, strings.Join(scopes, ","))
+		if expiresAt != nil {
+			fmt.Printf("Expires: %s\n", expiresAt.Local().Format("Mon Jan 2 15:04"))
+		}
+		fmt.Println(secret)
+		output.Info("Copy it now: it is not shown again. Send it as Authorization: Bearer <token>")
+		return nil
+	},
+}
+
+var tokenListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List active API tokens",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		sessionID, _ := cmd.Flags().GetString("session")
+		all, _ := cmd.Flags().GetBool("all")
+		tokens, err := database.ListAPITokens(sessionID, all)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			if tokens == nil {
+				tokens = []models.APIToken{}
+			}
+			data, _ := json.MarshalIndent(tokens, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if len(tokens) == 0 {
+			output.Info("No API tokens")
+			return nil
+		}
+		now := time.Now()
+		for _, t := range tokens {
+			state := "active"
+			switch {
+			case t.RevokedAt != nil:
+				state = "revoked"
+			case !t.Active(now):
+				state = "expired"
+			}
+			expires := "never"
+			if t.ExpiresAt != nil {
+				expires = t.ExpiresAt.Local().Format("2006-01-02 15:04")
+			}
+			name := ""
+			if t.Name != "" {
+				name = "  " + t.Name
+			}
+			fmt.Printf("%s  %s  %-10s  %-7s  expires %s%s\n",
+				t.ID, t.SessionID, strings.Join(t.Scopes, ","), state, expires, name)
+		}
+		return nil
+	},
+}
+
+var tokenRevokeCmd = &cobra.Command{
+	Use:   "revoke <token-id>",
+	Short: "Revoke an API token",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		if err := database.RevokeAPIToken(args[0]); err != nil {
+			if errors.Is(err, db.ErrAPITokenRevoked) {
+				output.Warning("token %s was already revoked", args[0])
+				return nil
+			}
+			output.Error("%v", err)
+			return err
+		}
+		output.Success("Revoked token %s", args[0])
+		return nil
+	},
+}
+
+func init() {
+	tokenCreateCmd.Flags().String("name", "", "Label to tell tokens apart, e.g. the agent using it")
+	tokenCreateCmd.Flags().String("scope", models.TokenScopeRead, "Comma-separated scopes: read, write, admin")
+	tokenCreateCmd.Flags().String("ttl", "30d", `How long the token lasts (offset or "never")`)
+	tokenCreateCmd.Flags().String("session", "", "Session to bind the token to (default: the current session)")
+	tokenListCmd.Flags().String("session", "", "Only tokens bound to this session")
+	tokenListCmd.Flags().Bool("all", false, "Include revoked and expired tokens")
+	tokenListCmd.Flags().Bool("json", false, "Output as JSON")
+	tokenCmd.AddCommand(tokenCreateCmd, tokenListCmd, tokenRevokeCmd)
+	rootCmd.AddCommand(tokenCmd)
+}
@@ -148,6 +148,14 @@ var commentCmd = &cobra.Command{
 			output.Error("%v", err)
 			return err
 		}
+		override, _ := cmd.Flags().GetBool("override")
+		if err := database.CheckClosedEdit(issueID, sess.ID, "comment on", override); err != nil {
+			output.Error("%v (or pass --override)", err)
+			return err
+		}
+		if override {
+			database = database.WithClosedOverride()
+		}
 
 		comment := &models.Comment{
 			IssueID:   issueID,
@@ -183,7 +191,7 @@ var commentsCmd = &cobra.Command{
 		issueID := args[0]
 
 		// Verify issue exists
-		_, err = database.GetIssue(issueID)
+		issue, err := database.GetIssue(issueID)
 		if err != nil {
 			output.Error("%v", err)
 			return err
@@ -194,9 +202,10 @@ var commentsCmd = &cobra.Command{
 			output.Error("failed to get comments: %v", err)
 			return err
 		}
+		revealIssue(database, issue, comments)
 
 		for _, c := range comments {
-			fmt.Printf("[%s] (%s) %s\n", c.CreatedAt.Format("2006-01-02 15:04"), c.SessionID, c.Text)
+			fmt.Printf("[%s] %s (%s) %s\n", c.CreatedAt.Format("2006-01-02 15:04"), c.ID, c.SessionID, c.Text)
 		}
 
 		if len(comments) == 0 {
@@ -236,6 +245,14 @@ var commentsAddCmd = &cobra.Command{
 			output.Error("%v", err)
 			return err
 		}
+		override, _ := cmd.Flags().GetBool("override")
+		if err := database.CheckClosedEdit(issueID, sess.ID, "comment on", override); err != nil {
+			output.Error("%v (or pass --override)", err)
+			return err
+		}
+		if override {
+			database = database.WithClosedOverride()
+		}
 
 		comment := &models.Comment{
 			IssueID:   issueID,
@@ -253,12 +270,64 @@ var commentsAddCmd = &cobra.Command{
 	},
 }
 
+var commentsEditCmd = &cobra.Command{
+	Use:   "edit <comment-id> \"text\"",
+	Short: "Replace a comment's text",
+	Long: `Replace a comment's text. The previous text is kept as a revision:
+see td show <issue-id> --history, and td revert to restore it.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		baseDir := getBaseDir()
+
+		database, err := db.Open(baseDir)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		sess, err := session.GetOrCreate(database)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		comment, err := database.GetCommentByID(args[0])
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if comment != nil {
+			override, _ := cmd.Flags().GetBool("override")
+			if err := database.CheckClosedEdit(comment.IssueID, sess.ID, "edit comments on", override); err != nil {
+				output.Error("%v (or pass --override)", err)
+				return err
+			}
+			if override {
+				database = database.WithClosedOverride()
+			}
+		}
+
+		if err := database.UpdateCommentLogged(args[0], args[1], sess.ID); err != nil {
+			output.Error("failed to edit comment: %v", err)
+			return err
+		}
+
+		fmt.Printf("COMMENT EDITED %s\n", args[0])
+		return nil
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(treeCmd)
 	rootCmd.AddCommand(commentCmd)
 	rootCmd.AddCommand(commentsCmd)
 
-	commentsCmd.AddCommand(commentsAddCmd)
+	commentsCmd.AddCommand(commentsAddCmd, commentsEditCmd)
+
+	for _, c := range []*cobra.Command{commentCmd, commentsAddCmd, commentsEditCmd} {
+		c.Flags().Bool("override", false, "Comment on a closed issue while closed issues are immutable (recorded in the security log)")
+	}
 
 	treeCmd.Flags().Int("depth", 0, "Max depth (0=unlimited)")
 	treeCmd.Flags().Bool("json", false, "JSON output")
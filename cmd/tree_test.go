@@ -422,7 +422,7 @@ func TestTreeDeleteParent(t *testing.T) {
 	database.DeleteIssue(parent.ID)
 
 	// Verify parent is deleted
-	pDeleted, _ := database.GetIssue(parent.ID)
+	pDeleted, _ := database.GetIssueWithDeleted(parent.ID)
 	if pDeleted.DeletedAt == nil {
 		t.Error("Parent should be deleted")
 	}
@@ -162,6 +162,13 @@ func undoIssueAction(database *db.DB, action *models.ActionLog, sessionID string
 		if err := json.Unmarshal([]byte(action.PreviousData), &issue); err != nil {
 			return fmt.Errorf("failed to parse previous data: %w", err)
 		}
+		// Making an issue confidential logs its text redacted, so undo
+		// decrypts the current text rather than restoring the snapshot
+		if !issue.Confidential {
+			if current, err := database.GetIssue(action.EntityID); err == nil && current.Confidential {
+				return database.SetConfidential(action.EntityID, false, sessionID)
+			}
+		}
 		// Use logged variant to generate sync event
 		return database.UpdateIssueLogged(&issue, sessionID, models.ActionUpdate)
 
@@ -171,22 +178,30 @@ func undoIssueAction(database *db.DB, action *models.ActionLog, sessionID string
 }
 
 func undoDependencyAction(database *db.DB, action *models.ActionLog, sessionID string) error {
-	// Parse the dependency info from entity_id (format: "issueID:dependsOnID")
+	// Parse the dependency info; a removal only has the row it removed
 	var depInfo struct {
-		IssueID     string `json:"issue_id"`
-		DependsOnID string `json:"depends_on_id"`
+		IssueID      string `json:"issue_id"`
+		DependsOnID  string `json:"depends_on_id"`
+		RelationType string `json:"relation_type"`
 	}
-	if err := json.Unmarshal([]byte(action.NewData), &depInfo); err != nil {
+	data := action.NewData
+	if data == "" {
+		data = action.PreviousData
+	}
+	if err := json.Unmarshal([]byte(data), &depInfo); err != nil {
 		return fmt.Errorf("failed to parse dependency data: %w", err)
 	}
+	if depInfo.RelationType == "" {
+		depInfo.RelationType = "depends_on"
+	}
 
 	switch action.ActionType {
 	case models.ActionAddDep:
 		// Use logged variant to generate sync event
-		return database.RemoveDependencyLogged(depInfo.IssueID, depInfo.DependsOnID, sessionID)
+		return database.RemoveRelationLogged(depInfo.IssueID, depInfo.DependsOnID, depInfo.RelationType, sessionID)
 	case models.ActionRemoveDep:
 		// Use logged variant to generate sync event
-		return database.AddDependencyLogged(depInfo.IssueID, depInfo.DependsOnID, "depends_on", sessionID)
+		return database.AddDependencyLogged(depInfo.IssueID, depInfo.DependsOnID, depInfo.RelationType, sessionID)
 	default:
 		return fmt.Errorf("cannot undo dependency action: %s", action.ActionType)
 	}
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/marcus/td/internal/confidential"
 	"github.com/marcus/td/internal/db"
 	"github.com/marcus/td/internal/models"
 )
@@ -224,6 +225,41 @@ func TestUndoIssueUpdate(t *testing.T) {
 	}
 }
 
+// TestUndoSetConfidential tests that undoing a confidential toggle decrypts
+// the text instead of restoring the redacted snapshot
+func TestUndoSetConfidential(t *testing.T) {
+	dir := t.TempDir()
+	database, err := db.Initialize(dir)
+	if err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	defer database.Close()
+	t.Setenv(confidential.KeyEnv, "")
+	if _, _, err := confidential.EnsureKey(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	issue := &models.Issue{Title: "Test Issue", Description: "secret plan"}
+	if err := database.CreateIssue(issue); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	if err := database.SetConfidential(issue.ID, true, "ses_test"); err != nil {
+		t.Fatal(err)
+	}
+	action, err := database.GetLastAction("ses_test")
+	if err != nil || action == nil {
+		t.Fatalf("GetLastAction = %v, %v", action, err)
+	}
+
+	if err := undoIssueAction(database, action, "ses_test"); err != nil {
+		t.Fatalf("undoIssueAction failed: %v", err)
+	}
+	retrieved, _ := database.GetIssue(issue.ID)
+	if retrieved.Confidential || retrieved.Description != "secret plan" {
+		t.Errorf("after undo: confidential=%v description=%q", retrieved.Confidential, retrieved.Description)
+	}
+}
+
 // TestUndoIssueStart tests undoing start action (reverts to open)
 func TestUndoIssueStart(t *testing.T) {
 	dir := t.TempDir()
@@ -2,14 +2,17 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/marcus/td/internal/dateparse"
 	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/input"
 	"github.com/marcus/td/internal/models"
 	"github.com/marcus/td/internal/output"
 	"github.com/marcus/td/internal/session"
 	"github.com/marcus/td/internal/workflow"
+	"github.com/marcus/td/internal/xref"
 	"github.com/spf13/cobra"
 )
 
@@ -17,6 +20,16 @@ var updateCmd = &cobra.Command{
 	Use:     "update [issue-id...]",
 	Aliases: []string{"edit"},
 	Short:   "Update one or more fields on existing issues",
+	Long: `Update one or more fields on existing issues.
+
+Pass - as the description or acceptance to read it from stdin, so long or
+pasted text keeps its formatting. --editor opens $EDITOR on one issue with
+its fields as YAML front matter above the description.
+
+Examples:
+  td update td-a1b2 --priority P1 --labels auth,backend
+  pbpaste | td edit td-a1b2 --description -
+  td edit td-a1b2 --editor`,
 	GroupID: "core",
 	Args:    cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -35,6 +48,38 @@ var updateCmd = &cobra.Command{
 			return err
 		}
 
+		useEditor, _ := cmd.Flags().GetBool("editor")
+		if useEditor && len(args) > 1 {
+			err := fmt.Errorf("--editor edits one issue at a time")
+			output.Error("%v", err)
+			return err
+		}
+
+		// Check description and its aliases (--desc, --body, -d)
+		desc, _ := cmd.Flags().GetString("description")
+		if desc == "" {
+			desc, _ = cmd.Flags().GetString("desc")
+		}
+		if desc == "" {
+			desc, _ = cmd.Flags().GetString("body")
+		}
+		acceptance, _ := cmd.Flags().GetString("acceptance")
+
+		// "-" reads the text from stdin, once for every issue
+		if desc == "-" && acceptance == "-" {
+			err := fmt.Errorf("only one of --description and --acceptance can read stdin")
+			output.Error("%v", err)
+			return err
+		}
+		for _, text := range []*string{&desc, &acceptance} {
+			if *text == "-" {
+				if *text, err = input.ReadText(os.Stdin); err != nil {
+					output.Error("failed to read stdin: %v", err)
+					return err
+				}
+			}
+		}
+
 		for _, issueID := range args {
 			issue, err := database.GetIssue(issueID)
 			if err != nil {
@@ -42,6 +87,16 @@ var updateCmd = &cobra.Command{
 				continue
 			}
 
+			// Editing a confidential issue's text needs access to it;
+			// the text is decrypted here and sealed again on save
+			if issue.Confidential && (desc != "" || acceptance != "" || useEditor) {
+				if err := requireConfidentialAccess(database, issue, sess.ID); err != nil {
+					output.Error("%v", err)
+					continue
+				}
+				revealIssue(database, issue, nil)
+			}
+
 			// (previous state captured atomically by UpdateIssueLogged)
 
 			// Update fields if flags are set
@@ -51,14 +106,6 @@ var updateCmd = &cobra.Command{
 
 			appendMode, _ := cmd.Flags().GetBool("append")
 
-			// Check description and its aliases (--desc, --body, -d)
-			desc, _ := cmd.Flags().GetString("description")
-			if desc == "" {
-				desc, _ = cmd.Flags().GetString("desc")
-			}
-			if desc == "" {
-				desc, _ = cmd.Flags().GetString("body")
-			}
 			if desc != "" {
 				if appendMode && issue.Description != "" {
 					issue.Description = issue.Description + "\n\n" + desc
@@ -67,7 +114,7 @@ var updateCmd = &cobra.Command{
 				}
 			}
 
-			if acceptance, _ := cmd.Flags().GetString("acceptance"); acceptance != "" {
+			if acceptance != "" {
 				if appendMode && issue.Acceptance != "" {
 					issue.Acceptance = issue.Acceptance + "\n\n" + acceptance
 				} else {
@@ -115,6 +162,10 @@ var updateCmd = &cobra.Command{
 			}
 
 			if parent, _ := cmd.Flags().GetString("parent"); cmd.Flags().Changed("parent") {
+				if xref.IsQualified(parent) {
+					output.Error("parent %s is in another project; use td dep add for cross-project links", parent)
+					continue
+				}
 				issue.ParentID = parent
 			}
 
@@ -150,7 +201,30 @@ var updateCmd = &cobra.Command{
 				}
 			}
 
+			// Edit the fields, flag changes included, in $EDITOR
+			if useEditor {
+				buf := formatIssueBuffer(issue)
+				edited, err := editText(buf, issue.ID+"-*.md")
+				if err != nil {
+					output.Error("editor failed: %v", err)
+					continue
+				}
+				if edited == buf && cmd.Flags().NFlag() == 1 {
+					output.Info("no changes to %s", issueID)
+					continue
+				}
+				if err := parseIssueBuffer(edited, issue); err != nil {
+					if path, keepErr := keepRejectedBuffer(issue.ID, edited); keepErr == nil {
+						output.Error("%v; %s not updated, edits saved to %s", err, issueID, path)
+					} else {
+						output.Error("%v; %s not updated", err, issueID)
+					}
+					continue
+				}
+			}
+
 			// Handle --status flag for convenience
+			var rework *models.Rework
 			if status, _ := cmd.Flags().GetString("status"); status != "" {
 				newStatus := models.NormalizeStatus(status)
 				if !models.IsValidStatus(newStatus) {
@@ -165,6 +239,11 @@ var updateCmd = &cobra.Command{
 				}
 				oldStatus := issue.Status
 				issue.Status = newStatus
+				if oldStatus == models.StatusClosed && newStatus != models.StatusClosed {
+					rework = &models.Rework{
+						IssueID: issue.ID, Category: models.ReworkOther, Approved: issue.ReviewerSession != "", SessionID: sess.ID,
+					}
+				}
 
 				// Record session action for bypass prevention based on transition type
 				var sessionAction models.IssueSessionAction
@@ -183,6 +262,18 @@ var updateCmd = &cobra.Command{
 				}
 			}
 
+			// Closed issues stay immutable unless this update reopens them
+			if issue.Status == models.StatusClosed {
+				override, _ := cmd.Flags().GetBool("override")
+				if err := database.CheckClosedEdit(issueID, sess.ID, "update", override); err != nil {
+					output.Error("%v (or pass --override)", err)
+					continue
+				}
+				if override {
+					database = database.WithClosedOverride()
+				}
+			}
+
 			// Update dependencies
 			if dependsOn, _ := cmd.Flags().GetString("depends-on"); cmd.Flags().Changed("depends-on") {
 				// Clear existing and set new
@@ -220,6 +311,11 @@ var updateCmd = &cobra.Command{
 			}
 
 			fmt.Printf("UPDATED %s\n", issueID)
+			if rework != nil {
+				if err := database.RecordRework(rework); err != nil {
+					output.Warning("failed to record rework for %s: %v", issueID, err)
+				}
+			}
 
 			// Add inline comment if --comment/-m or -c was provided
 			commentText, _ := cmd.Flags().GetString("comment")
@@ -246,10 +342,10 @@ func init() {
 	rootCmd.AddCommand(updateCmd)
 
 	updateCmd.Flags().String("title", "", "New title")
-	updateCmd.Flags().StringP("description", "d", "", "New description")
+	updateCmd.Flags().StringP("description", "d", "", "New description (- reads stdin)")
 	updateCmd.Flags().String("desc", "", "Alias for --description")
 	updateCmd.Flags().String("body", "", "Alias for --description")
-	updateCmd.Flags().String("acceptance", "", "New acceptance criteria")
+	updateCmd.Flags().String("acceptance", "", "New acceptance criteria (- reads stdin)")
 	updateCmd.Flags().String("type", "", "New type")
 	updateCmd.Flags().String("priority", "", "New priority")
 	updateCmd.Flags().Int("points", 0, "New story points")
@@ -259,10 +355,12 @@ func init() {
 	updateCmd.Flags().String("depends-on", "", "Replace dependencies")
 	updateCmd.Flags().String("blocks", "", "Replace blocked issues")
 	updateCmd.Flags().Bool("append", false, "Append to text fields instead of replacing")
+	updateCmd.Flags().Bool("editor", false, "Edit fields and description in $EDITOR")
 	updateCmd.Flags().String("status", "", "New status (open, in_progress, in_review, blocked, closed)")
 	updateCmd.Flags().StringP("comment", "m", "", "Add a comment to the updated issue(s)")
 	updateCmd.Flags().StringP("note", "c", "", "Alias for --comment")
 	updateCmd.Flags().MarkHidden("note")
 	updateCmd.Flags().String("defer", "", "Defer until date (e.g., +7d, monday, 2026-03-01; empty to clear)")
 	updateCmd.Flags().String("due", "", "Due date (e.g., friday, +2w, 2026-03-15; empty to clear)")
+	updateCmd.Flags().Bool("override", false, "Edit a closed issue while closed issues are immutable (recorded in the security log)")
 }
@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/marcus/td/internal/dateparse"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/xref"
+)
+
+// issueBufferHelp heads the front matter of td update --editor buffers
+const issueBufferHelp = `# Edit the fields, and the description below the closing ---, then save
+# and quit. Empty values clear optional fields. Save unchanged to cancel.`
+
+// formatIssueBuffer renders an issue's editable fields as YAML front matter
+// followed by the description, for td update --editor.
+func formatIssueBuffer(issue *models.Issue) string {
+	var b strings.Builder
+	b.WriteString("---\n" + issueBufferHelp + "\n")
+	field := func(key, value string) {
+		fmt.Fprintf(&b, "%s: %s\n", key, quoteBufferValue(value))
+	}
+	field("title", issue.Title)
+	field("type", string(issue.Type))
+	field("priority", string(issue.Priority))
+	points := ""
+	if issue.Points > 0 {
+		points = strconv.Itoa(issue.Points)
+	}
+	field("points", points)
+	field("labels", strings.Join(issue.Labels, ", "))
+	field("sprint", issue.Sprint)
+	field("parent", issue.ParentID)
+	field("due", derefString(issue.DueDate))
+	field("defer", derefString(issue.DeferUntil))
+
+	// Acceptance criteria are a block scalar, indented two spaces
+	if issue.Acceptance == "" {
+		b.WriteString("acceptance:\n")
+	} else {
+		b.WriteString("acceptance: |\n")
+		for _, line := range strings.Split(issue.Acceptance, "\n") {
+			if line != "" {
+				line = "  " + line
+			}
+			b.WriteString(line + "\n")
+		}
+	}
+
+	b.WriteString("---\n")
+	b.WriteString(issue.Description + "\n")
+	return b.String()
+}
+
+// parseIssueBuffer applies an edited formatIssueBuffer buffer to issue. The
+// issue is left untouched when the buffer is invalid.
+func parseIssueBuffer(buf string, issue *models.Issue) error {
+	buf = strings.ReplaceAll(buf, "\r\n", "\n")
+	rest, ok := strings.CutPrefix(strings.TrimLeft(buf, "\n"), "---\n")
+	if !ok {
+		return fmt.Errorf("buffer must start with a --- line")
+	}
+	front, desc, ok := strings.Cut(rest, "\n---\n")
+	if !ok {
+		if front, ok = strings.CutSuffix(rest, "\n---"); !ok {
+			return fmt.Errorf("front matter must end with a --- line")
+		}
+	}
+
+	edited := *issue
+	edited.Description = strings.TrimSuffix(desc, "\n")
+	lines := strings.Split(front, "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, raw, ok := strings.Cut(line, ":")
+		if !ok {
+			return fmt.Errorf("line %q is not key: value", line)
+		}
+		key = strings.TrimSpace(key)
+
+		if key == "acceptance" && strings.TrimSpace(raw) == "|" {
+			var block []string
+			for i+1 < len(lines) && (lines[i+1] == "" || strings.HasPrefix(lines[i+1], "  ")) {
+				i++
+				block = append(block, strings.TrimPrefix(lines[i], "  "))
+			}
+			edited.Acceptance = strings.TrimRight(strings.Join(block, "\n"), "\n")
+			continue
+		}
+
+		value, err := unquoteBufferValue(raw)
+		if err != nil {
+			return fmt.Errorf("%s: %v", key, err)
+		}
+		if err := setBufferField(&edited, key, value); err != nil {
+			return err
+		}
+	}
+
+	if edited.Title == "" {
+		return fmt.Errorf("title is required")
+	}
+	*issue = edited
+	return nil
+}
+
+// setBufferField validates and sets one front matter field
+func setBufferField(issue *models.Issue, key, value string) error {
+	switch key {
+	case "title":
+		issue.Title = value
+	case "type":
+		issue.Type = models.NormalizeType(strings.ToLower(value))
+		if !models.IsValidType(issue.Type) {
+			return fmt.Errorf("invalid type: %s (valid: bug, feature, task, epic, chore)", value)
+		}
+	case "priority":
+		issue.Priority = models.NormalizePriority(value)
+		if !models.IsValidPriority(issue.Priority) {
+			return fmt.Errorf("invalid priority: %s (valid: P0, P1, P2, P3, P4)", value)
+		}
+	case "points":
+		pts := 0
+		if value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || !models.IsValidPoints(n) {
+				return fmt.Errorf("invalid points: %s (valid: 1, 2, 3, 5, 8, 13, 21)", value)
+			}
+			pts = n
+		}
+		issue.Points = pts
+	case "labels":
+		issue.Labels = nil
+		for _, label := range strings.Split(strings.Trim(value, "[]"), ",") {
+			if label = strings.TrimSpace(label); label != "" {
+				issue.Labels = append(issue.Labels, label)
+			}
+		}
+	case "acceptance":
+		issue.Acceptance = value
+	case "sprint":
+		issue.Sprint = value
+	case "parent":
+		if xref.IsQualified(value) {
+			return fmt.Errorf("parent %s is in another project; use td dep add for cross-project links", value)
+		}
+		issue.ParentID = value
+	case "due":
+		due, err := parseBufferDate(value)
+		if err != nil {
+			return fmt.Errorf("invalid due date: %v", err)
+		}
+		issue.DueDate = due
+	case "defer":
+		until, err := parseBufferDate(value)
+		if err != nil {
+			return fmt.Errorf("invalid defer date: %v", err)
+		}
+		// Pushing to a later date counts as another deferral, as with --defer
+		if until != nil && issue.DeferUntil != nil && *until > *issue.DeferUntil {
+			issue.DeferCount++
+		}
+		issue.DeferUntil = until
+	default:
+		return fmt.Errorf("unknown field %q", key)
+	}
+	return nil
+}
+
+// parseBufferDate reads a date in any --due form; empty clears it
+func parseBufferDate(value string) (*string, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := dateparse.ParseDate(value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+// quoteBufferValue quotes values that would not survive a round trip bare
+func quoteBufferValue(v string) string {
+	if v != strings.TrimSpace(v) || strings.HasPrefix(v, `"`) || strings.HasPrefix(v, "#") || strings.Contains(v, "\n") {
+		return strconv.Quote(v)
+	}
+	return v
+}
+
+// unquoteBufferValue reverses quoteBufferValue
+func unquoteBufferValue(raw string) (string, error) {
+	v := strings.TrimSpace(raw)
+	if strings.HasPrefix(v, `"`) {
+		return strconv.Unquote(v)
+	}
+	return v, nil
+}
+
+// derefString returns *s, or "" for nil
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// keepRejectedBuffer saves an edit that could not be applied so it is not
+// lost, returning the file's path.
+func keepRejectedBuffer(issueID, buf string) (string, error) {
+	f, err := os.CreateTemp("", issueID+"-rejected-*.md")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := f.WriteString(buf); err != nil {
+		return "", err
+	}
+	return f.Name(), nil
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestIssueBufferRoundTrip(t *testing.T) {
+	due := "2026-03-06"
+	issue := &models.Issue{
+		ID:          "td-a1b2",
+		Title:       `"Quoted" title with # in it`,
+		Type:        models.TypeBug,
+		Priority:    models.PriorityP1,
+		Points:      3,
+		Labels:      []string{"auth", "backend"},
+		Sprint:      "sprint-7",
+		DueDate:     &due,
+		Acceptance:  "Tokens refresh\n\n  - before expiry\n  ",
+		Description: "## Steps\n\n---\n\nindented:\n    code\n",
+	}
+
+	got := *issue
+	if err := parseIssueBuffer(formatIssueBuffer(issue), &got); err != nil {
+		t.Fatalf("parseIssueBuffer: %v", err)
+	}
+	if !reflect.DeepEqual(got, *issue) {
+		t.Errorf("round trip changed the issue:\n got %+v\nwant %+v", got, *issue)
+	}
+}
+
+func TestParseIssueBufferEdits(t *testing.T) {
+	issue := &models.Issue{Title: "Fix login timeout", Type: models.TypeTask, Priority: models.PriorityP2, Labels: []string{"auth"}}
+	buf := formatIssueBuffer(issue)
+	buf = strings.Replace(buf, "priority: P2", "priority: high", 1)
+	buf = strings.Replace(buf, "labels: auth", "labels: [auth, ui]", 1)
+	buf = strings.Replace(buf, "acceptance:\n", "acceptance: |\n  Session survives an hour\n", 1)
+	buf = strings.TrimSuffix(buf, "\n") + "Users are logged out after five minutes.\n"
+
+	unchanged := *issue
+	if err := parseIssueBuffer(formatIssueBuffer(issue), &unchanged); err != nil || !reflect.DeepEqual(unchanged, *issue) {
+		t.Fatalf("unedited buffer: %v, issue %+v", err, unchanged)
+	}
+
+	if err := parseIssueBuffer(buf, issue); err != nil {
+		t.Fatalf("parseIssueBuffer: %v", err)
+	}
+	if issue.Priority != models.PriorityP1 || !reflect.DeepEqual(issue.Labels, []string{"auth", "ui"}) ||
+		issue.Acceptance != "Session survives an hour" || issue.Description != "Users are logged out after five minutes." {
+		t.Errorf("edited issue = %+v", issue)
+	}
+}
+
+func TestParseIssueBufferErrors(t *testing.T) {
+	issue := &models.Issue{Title: "Fix login timeout", Type: models.TypeTask, Priority: models.PriorityP2}
+	buf := formatIssueBuffer(issue)
+	for name, bad := range map[string]string{
+		"no front matter": "Just a description",
+		"unclosed":        strings.TrimSuffix(buf, "---\n\n"),
+		"unknown field":   strings.Replace(buf, "sprint:", "owner: bob\nsprint:", 1),
+		"bad points":      strings.Replace(buf, "points: ", "points: 4", 1),
+		"bad due":         strings.Replace(buf, "due: ", "due: someday", 1),
+		"no title":        strings.Replace(buf, "title: Fix login timeout", "title:", 1),
+	} {
+		before := *issue
+		if err := parseIssueBuffer(bad, issue); err == nil {
+			t.Errorf("%s: parseIssueBuffer succeeded, want error", name)
+		}
+		if !reflect.DeepEqual(*issue, before) {
+			t.Errorf("%s: issue changed on error", name)
+		}
+	}
+}
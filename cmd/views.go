@@ -0,0 +1,326 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/output"
+	"github.com/marcus/td/internal/query"
+	"github.com/marcus/td/internal/session"
+	"github.com/marcus/td/internal/view"
+	"github.com/spf13/cobra"
+)
+
+var viewsCmd = &cobra.Command{
+	Use:   "views",
+	Short: "Manage saved views",
+	Long: `Save a query together with its sort, columns, grouping and layout
+under a name, then open it by that name from td views show, td monitor
+--view, or the HTTP API (/v1/views).
+
+Views are stored in the project database and are not synced. (td view
+is an alias of td show.)`,
+	GroupID: "core",
+}
+
+var viewsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved views",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		views, err := database.ListViews()
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			if views == nil {
+				views = []models.View{}
+			}
+			data, _ := json.MarshalIndent(views, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if len(views) == 0 {
+			output.Info("No views found. Create one with: td views create <name> --query '...'")
+			return nil
+		}
+		for _, v := range views {
+			fmt.Printf("%s: %s [%s]%s\n", v.ID, v.Name, v.Layout, describeViewSettings(&v))
+		}
+		return nil
+	},
+}
+
+var viewsCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Save a new view",
+	Example: `  td views create triage --query 'status = open AND priority <= P1' --sort -created
+  td views create sprint --query 'sprint = "24"' --group status --layout board
+  td views create review --fields id,title,implementer_session --layout table`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		v := &models.View{Name: args[0]}
+		applyViewFlags(cmd, v)
+		if err := view.Normalize(v); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if sess, _ := session.GetOrCreate(database); sess != nil {
+			v.SessionID = sess.ID
+		}
+
+		if err := database.CreateView(v); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		output.Success("Created view %s (%s)", v.Name, v.ID)
+		return nil
+	},
+}
+
+var viewsEditCmd = &cobra.Command{
+	Use:   "edit <view>",
+	Short: "Change a view's name, query, sort, fields, grouping or layout",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		v, err := database.GetView(args[0])
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if name, _ := cmd.Flags().GetString("name"); name != "" {
+			v.Name = name
+		}
+		applyViewFlags(cmd, v)
+		if err := view.Normalize(v); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		if err := database.UpdateView(v); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		output.Success("Updated view %s", v.Name)
+		return nil
+	},
+}
+
+var viewsDeleteCmd = &cobra.Command{
+	Use:   "delete <view>",
+	Short: "Delete a saved view",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		v, err := database.GetView(args[0])
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if err := database.DeleteView(v.ID); err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		output.Success("Deleted view %s", v.Name)
+		return nil
+	},
+}
+
+var viewsShowCmd = &cobra.Command{
+	Use:   "show <view>",
+	Short: "Run a saved view and print its issues",
+	Long: `Run a view's query with its sort and print the matching issues with its
+fields, in its groups. The list layout prints one line per issue; table,
+board and backlog print aligned columns under a header. Board views that
+do not set a grouping are grouped by status.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		baseDir := getBaseDir()
+		database, err := db.Open(baseDir)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		v, err := database.GetView(args[0])
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		sessionID := ""
+		if sess, _ := session.GetOrCreate(database); sess != nil {
+			sessionID = sess.ID
+		}
+		useScoreFormula(baseDir)
+		limit, _ := cmd.Flags().GetInt("limit")
+		groups, err := view.Run(database, v, sessionID, query.ExecuteOptions{
+			Limit:   limit,
+			Project: config.GetProjectName(baseDir),
+		})
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			data, _ := json.MarshalIndent(map[string]interface{}{"view": v, "groups": groups}, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		fmt.Printf("View: %s (%s)\n", v.Name, v.ID)
+		if v.Query != "" {
+			fmt.Printf("Query: %s\n", v.Query)
+		}
+		fmt.Println()
+		printViewGroups(v, groups)
+		return nil
+	},
+}
+
+// applyViewFlags copies the view flags the user set onto v
+func applyViewFlags(cmd *cobra.Command, v *models.View) {
+	if cmd.Flags().Changed("query") {
+		v.Query, _ = cmd.Flags().GetString("query")
+	}
+	if cmd.Flags().Changed("sort") {
+		v.Sort, _ = cmd.Flags().GetString("sort")
+	}
+	if cmd.Flags().Changed("fields") {
+		v.Fields, _ = cmd.Flags().GetStringSlice("fields")
+	}
+	if cmd.Flags().Changed("group") {
+		v.GroupBy, _ = cmd.Flags().GetString("group")
+	}
+	if cmd.Flags().Changed("layout") {
+		v.Layout, _ = cmd.Flags().GetString("layout")
+	}
+}
+
+// describeViewSettings summarizes a view's query, sort and grouping for td views list
+func describeViewSettings(v *models.View) string {
+	var parts []string
+	if v.Query != "" {
+		parts = append(parts, v.Query)
+	}
+	if v.Sort != "" {
+		parts = append(parts, "sort "+v.Sort)
+	}
+	if v.GroupBy != "" {
+		parts = append(parts, "by "+v.GroupBy)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(parts, "; ") + ")"
+}
+
+// printViewGroups prints a view's results in its layout
+func printViewGroups(v *models.View, groups []view.Group) {
+	grouped := view.GroupField(v) != ""
+	columns := view.Columns(v)
+	total := 0
+	for _, g := range groups {
+		total += len(g.Issues)
+	}
+	if total == 0 {
+		output.Info("No issues match this view")
+		return
+	}
+
+	for i, g := range groups {
+		if grouped {
+			if i > 0 {
+				fmt.Println()
+			}
+			key := g.Key
+			if key == "" {
+				key = "(none)"
+			}
+			fmt.Printf("%s: %s (%d)\n", view.GroupField(v), key, len(g.Issues))
+		}
+
+		if v.Layout == view.LayoutList {
+			for j := range g.Issues {
+				fmt.Println(strings.Join(viewRow(&g.Issues[j], columns), "  "))
+			}
+			continue
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, strings.ToUpper(strings.Join(columns, "\t")))
+		for j := range g.Issues {
+			fmt.Fprintln(w, strings.Join(viewRow(&g.Issues[j], columns), "\t"))
+		}
+		w.Flush()
+	}
+}
+
+// viewRow renders an issue's values for columns
+func viewRow(issue *models.Issue, columns []string) []string {
+	values := make([]string, len(columns))
+	for i, c := range columns {
+		values[i] = view.Value(issue, c)
+	}
+	return values
+}
+
+func init() {
+	rootCmd.AddCommand(viewsCmd)
+	viewsCmd.AddCommand(viewsListCmd, viewsCreateCmd, viewsEditCmd, viewsDeleteCmd, viewsShowCmd)
+
+	viewsListCmd.Flags().Bool("json", false, "Output as JSON")
+	viewsShowCmd.Flags().Bool("json", false, "Output the view and its grouped issues as JSON")
+	viewsShowCmd.Flags().IntP("limit", "n", 0, "Maximum issues to show (0 = all)")
+
+	for _, c := range []*cobra.Command{viewsCreateCmd, viewsEditCmd} {
+		c.Flags().StringP("query", "q", "", "TDQ query selecting the view's issues")
+		c.Flags().String("sort", "", "Sort field, - prefix for descending (e.g. -created, priority)")
+		c.Flags().StringSlice("fields", nil, "Columns to show, e.g. id,title,status (default id,priority,status,title)")
+		c.Flags().String("group", "", "Group by: "+strings.Join(view.Groupings, ", "))
+		c.Flags().String("layout", "", "Layout: "+strings.Join(view.Layouts, ", ")+" (default list)")
+	}
+	viewsEditCmd.Flags().StringP("name", "n", "", "New name for the view")
+}
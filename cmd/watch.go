@@ -0,0 +1,372 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/output"
+	"github.com/marcus/td/internal/query"
+	"github.com/marcus/td/internal/serve"
+	"github.com/marcus/td/internal/serveclient"
+	"github.com/marcus/td/internal/session"
+	"github.com/spf13/cobra"
+)
+
+// watchColumn is a column td watch can show
+type watchColumn struct {
+	name  string
+	value func(models.Issue) string
+}
+
+// watchColumns are the columns td watch can show, in the order listed in
+// its help
+var watchColumns = []watchColumn{
+	{"id", func(i models.Issue) string { return i.ID }},
+	{"status", func(i models.Issue) string { return string(i.Status) }},
+	{"priority", func(i models.Issue) string { return string(i.Priority) }},
+	{"type", func(i models.Issue) string { return string(i.Type) }},
+	{"title", func(i models.Issue) string { return i.Title }},
+	{"points", func(i models.Issue) string {
+		if i.Points == 0 {
+			return ""
+		}
+		return strconv.Itoa(i.Points)
+	}},
+	{"labels", func(i models.Issue) string { return strings.Join(i.Labels, ",") }},
+	{"parent", func(i models.Issue) string { return i.ParentID }},
+	{"sprint", func(i models.Issue) string { return i.Sprint }},
+	{"implementer", func(i models.Issue) string { return i.ImplementerSession }},
+	{"reviewer", func(i models.Issue) string { return i.ReviewerSession }},
+	{"due", func(i models.Issue) string {
+		if i.DueDate == nil {
+			return ""
+		}
+		return *i.DueDate
+	}},
+	{"created", func(i models.Issue) string { return i.CreatedAt.Local().Format("2006-01-02 15:04") }},
+	{"updated", func(i models.Issue) string { return i.UpdatedAt.Local().Format("2006-01-02 15:04") }},
+}
+
+const defaultWatchColumns = "id,status,priority,type,title"
+
+// parseWatchColumns resolves a comma-separated --columns value
+func parseWatchColumns(s string) ([]watchColumn, error) {
+	var cols []watchColumn
+	for _, name := range strings.Split(s, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		found := false
+		for _, c := range watchColumns {
+			if c.name == name {
+				cols = append(cols, c)
+				found = true
+				break
+			}
+		}
+		if !found {
+			names := make([]string, len(watchColumns))
+			for i, c := range watchColumns {
+				names[i] = c.name
+			}
+			return nil, fmt.Errorf("unknown column %q: use %s", name, strings.Join(names, ", "))
+		}
+	}
+	if len(cols) == 0 {
+		return nil, fmt.Errorf("no columns given")
+	}
+	return cols, nil
+}
+
+// watchRows renders issues as table rows, one cell per column
+func watchRows(issues []models.Issue, cols []watchColumn) [][]string {
+	rows := make([][]string, len(issues))
+	for i, issue := range issues {
+		row := make([]string, len(cols))
+		for j, c := range cols {
+			row[j] = c.value(issue)
+		}
+		rows[i] = row
+	}
+	return rows
+}
+
+// sameWatchRows reports whether two renders show the same table
+func sameWatchRows(a, b [][]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if len(a[i]) != len(b[i]) {
+			return false
+		}
+		for j := range a[i] {
+			if a[i][j] != b[i][j] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// writeWatchTable prints the rows under a header of column names
+func writeWatchTable(w io.Writer, cols []watchColumn, rows [][]string) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	header := make([]string, len(cols))
+	for i, c := range cols {
+		header[i] = strings.ToUpper(c.name)
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	tw.Flush()
+	if len(rows) == 0 {
+		fmt.Fprintln(w, "No issues matching query")
+	}
+}
+
+// watchIDDiff lists the IDs that entered and left the result set
+func watchIDDiff(prev, next []models.Issue) (added, removed []string) {
+	before := make(map[string]bool, len(prev))
+	for _, issue := range prev {
+		before[issue.ID] = true
+	}
+	after := make(map[string]bool, len(next))
+	for _, issue := range next {
+		after[issue.ID] = true
+		if !before[issue.ID] {
+			added = append(added, issue.ID)
+		}
+	}
+	for _, issue := range prev {
+		if !after[issue.ID] {
+			removed = append(removed, issue.ID)
+		}
+	}
+	return added, removed
+}
+
+// watchExecEnv describes a change to the command run by --exec
+func watchExecEnv(expr string, prev, next []models.Issue) []string {
+	added, removed := watchIDDiff(prev, next)
+	ids := make([]string, len(next))
+	for i, issue := range next {
+		ids[i] = issue.ID
+	}
+	return append(os.Environ(),
+		"TD_WATCH_QUERY="+expr,
+		"TD_WATCH_COUNT="+strconv.Itoa(len(next)),
+		"TD_WATCH_IDS="+strings.Join(ids, " "),
+		"TD_WATCH_ADDED="+strings.Join(added, " "),
+		"TD_WATCH_REMOVED="+strings.Join(removed, " "),
+	)
+}
+
+// watchTriggers signals when the results may have changed: on every event
+// from a running td serve, or when the change token moves if there is none
+// or its stream drops. source describes where changes come from.
+func watchTriggers(ctx context.Context, database *db.DB, baseDir, token string, interval time.Duration, poll bool) (<-chan struct{}, <-chan string) {
+	triggers := make(chan struct{}, 1)
+	sources := make(chan string, 1)
+	notify := func() {
+		select {
+		case triggers <- struct{}{}:
+		default:
+		}
+	}
+
+	go func() {
+		if !poll {
+			if info, err := serve.ReadPortFile(baseDir); err == nil && !serve.IsPortFileStale(info) {
+				sources <- fmt.Sprintf("td serve on port %d", info.Port)
+				client := serveclient.New(fmt.Sprintf("http://127.0.0.1:%d", info.Port), token)
+				err := client.Events(ctx, nil, func(serve.SSEEvent) { notify() })
+				if ctx.Err() != nil {
+					return
+				}
+				output.Warning("td serve events: %v; polling instead", err)
+				notify()
+			}
+		}
+		sources <- fmt.Sprintf("polling every %s", interval)
+
+		last, _ := database.GetChangeToken()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			if changeToken, err := database.GetChangeToken(); err == nil && changeToken != last {
+				last = changeToken
+				notify()
+			}
+		}
+	}()
+	return triggers, sources
+}
+
+var watchCmd = &cobra.Command{
+	Use:   "watch [expression]",
+	Short: "Show a live-updating table of a TDQ query's results",
+	Long: `Run a TDQ query and keep its results on screen, re-running it whenever the
+project changes. Changes come from a running td serve's event stream when
+there is one, and from polling the database otherwise.
+
+On a terminal the table is redrawn in place. When output is piped, as in CI,
+each new result is printed once under a timestamp.
+
+--exec runs a shell command each time the table changes, not on the first
+render. It gets the results in its environment:
+  TD_WATCH_QUERY    the query
+  TD_WATCH_COUNT    number of matching issues
+  TD_WATCH_IDS      matching issue IDs, space-separated, in table order
+  TD_WATCH_ADDED    IDs that started matching
+  TD_WATCH_REMOVED  IDs that stopped matching
+
+Columns: id, status, priority, type, title, points, labels, parent, sprint,
+implementer, reviewer, due, created, updated`,
+	Example: `  td watch 'status = in_review sort:-updated'
+  td watch 'is(blocked)' --columns id,priority,title,updated
+  td watch 'priority = P0 AND is(open)' --exec 'notify-send "P0 issues: $TD_WATCH_COUNT"'`,
+	GroupID: "query",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		expr := args[0]
+
+		parsedQuery, err := query.Parse(expr)
+		if err != nil {
+			output.Error("Parse error: %v", err)
+			printQuerySyntaxHelp()
+			return err
+		}
+		if errs := parsedQuery.Validate(); len(errs) > 0 {
+			output.Error("Validation errors:")
+			for _, e := range errs {
+				output.Error("  - %v", e)
+			}
+			return errs[0]
+		}
+
+		columnsFlag, _ := cmd.Flags().GetString("columns")
+		cols, err := parseWatchColumns(columnsFlag)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		interval, _ := cmd.Flags().GetDuration("interval")
+		if interval <= 0 {
+			err := fmt.Errorf("--interval must be positive")
+			output.Error("%v", err)
+			return err
+		}
+		execCmd, _ := cmd.Flags().GetString("exec")
+		token, _ := cmd.Flags().GetString("token")
+		poll, _ := cmd.Flags().GetBool("poll")
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		baseDir := getBaseDir()
+		database, err := db.Open(baseDir)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		sessionID := ""
+		if sess, _ := session.GetOrCreate(database); sess != nil {
+			sessionID = sess.ID
+		}
+		useScoreFormula(baseDir)
+		opts := query.ExecuteOptions{Limit: limit, Project: config.GetProjectName(baseDir)}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		live := stdoutIsTerminal()
+		triggers, sources := watchTriggers(ctx, database, baseDir, token, interval, poll)
+		source := <-sources
+
+		var prev []models.Issue
+		var prevRows [][]string
+		first, redraw := true, false
+		for {
+			results, err := query.ExecuteQuery(database, parsedQuery, sessionID, opts)
+			if err != nil {
+				output.Error("Query error: %v", err)
+				return err
+			}
+			rows := watchRows(results, cols)
+
+			changed := !first && !sameWatchRows(prevRows, rows)
+			if first || changed || (redraw && live) {
+				now := time.Now().Format("15:04:05")
+				if live {
+					fmt.Print("\x1b[H\x1b[2J")
+					fmt.Printf("td watch %q  ·  %d matching  ·  %s  ·  updated %s\n\n", expr, len(results), source, now)
+				} else {
+					if first {
+						fmt.Printf("td watch %q via %s\n\n", expr, source)
+					}
+					fmt.Printf("== %s  %d matching\n", now, len(results))
+				}
+				writeWatchTable(os.Stdout, cols, rows)
+				if !live {
+					fmt.Println()
+				}
+
+				if changed && execCmd != "" {
+					c := exec.CommandContext(ctx, "sh", "-c", execCmd)
+					c.Env = watchExecEnv(expr, prev, results)
+					c.Stdout, c.Stderr = os.Stderr, os.Stderr
+					if err := c.Run(); err != nil && ctx.Err() == nil {
+						output.Warning("--exec: %v", err)
+					}
+				}
+				prev, prevRows, first = results, rows, false
+			}
+
+			redraw = false
+			select {
+			case <-ctx.Done():
+				return nil
+			case source = <-sources:
+				redraw = true
+			case <-triggers:
+			}
+		}
+	},
+}
+
+// stdoutIsTerminal reports whether stdout is interactive
+func stdoutIsTerminal() bool {
+	stat, err := os.Stdout.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
+
+func init() {
+	watchCmd.Flags().String("columns", defaultWatchColumns, "Comma-separated columns to show")
+	watchCmd.Flags().String("exec", "", "Shell command to run when the results change")
+	watchCmd.Flags().Duration("interval", 2*time.Second, "How often to check for changes when polling")
+	watchCmd.Flags().Bool("poll", false, "Poll the database even when td serve is running")
+	watchCmd.Flags().String("token", "", "Bearer token for a td serve started with --token")
+	watchCmd.Flags().IntP("limit", "n", 0, "Show at most this many issues (0 = all)")
+	rootCmd.AddCommand(watchCmd)
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestParseWatchColumns(t *testing.T) {
+	cols, err := parseWatchColumns(" ID, title ,updated,")
+	if err != nil {
+		t.Fatalf("parseWatchColumns() error = %v", err)
+	}
+	var names []string
+	for _, c := range cols {
+		names = append(names, c.name)
+	}
+	if want := []string{"id", "title", "updated"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("columns = %v, want %v", names, want)
+	}
+
+	if _, err := parseWatchColumns("id,owner"); err == nil || !strings.Contains(err.Error(), `"owner"`) {
+		t.Errorf("unknown column error = %v", err)
+	}
+	if _, err := parseWatchColumns(" , "); err == nil {
+		t.Error("expected an error for no columns")
+	}
+}
+
+func TestWatchRowsAndTable(t *testing.T) {
+	due := "2026-03-01"
+	issues := []models.Issue{
+		{ID: "td-a", Status: models.StatusInReview, Priority: models.PriorityP1, Title: "Fix login timeout", Labels: []string{"auth", "api"}, DueDate: &due},
+		{ID: "td-b", Status: models.StatusOpen, Priority: models.PriorityP2, Title: "Add export command", Points: 3},
+	}
+	cols, err := parseWatchColumns("id,status,labels,points,due")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows := watchRows(issues, cols)
+	want := [][]string{
+		{"td-a", "in_review", "auth,api", "", "2026-03-01"},
+		{"td-b", "open", "", "3", ""},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("watchRows() = %v, want %v", rows, want)
+	}
+
+	if !sameWatchRows(rows, watchRows(issues, cols)) {
+		t.Error("sameWatchRows() = false for identical renders")
+	}
+	issues[1].Status = models.StatusInProgress
+	if sameWatchRows(rows, watchRows(issues, cols)) {
+		t.Error("sameWatchRows() = true after a status change")
+	}
+	if sameWatchRows(rows, rows[:1]) {
+		t.Error("sameWatchRows() = true after a row left")
+	}
+
+	var buf bytes.Buffer
+	writeWatchTable(&buf, cols, rows)
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "ID") || !strings.Contains(lines[0], "LABELS") {
+		t.Errorf("table =\n%s", buf.String())
+	}
+
+	buf.Reset()
+	writeWatchTable(&buf, cols, nil)
+	if !strings.Contains(buf.String(), "No issues matching query") {
+		t.Errorf("empty table = %q", buf.String())
+	}
+}
+
+func TestWatchIDDiff(t *testing.T) {
+	prev := []models.Issue{{ID: "td-a"}, {ID: "td-b"}}
+	next := []models.Issue{{ID: "td-b"}, {ID: "td-c"}, {ID: "td-d"}}
+
+	added, removed := watchIDDiff(prev, next)
+	if want := []string{"td-c", "td-d"}; !reflect.DeepEqual(added, want) {
+		t.Errorf("added = %v, want %v", added, want)
+	}
+	if want := []string{"td-a"}; !reflect.DeepEqual(removed, want) {
+		t.Errorf("removed = %v, want %v", removed, want)
+	}
+}
@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/delivery"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var webhookDeliveriesCmd = &cobra.Command{
+	Use:   "deliveries",
+	Short: "List queued, sent and failed deliveries",
+	Long: `Webhooks, subscription webhooks, chat channel posts and network
+notifications go through a delivery outbox. Each change is queued in the
+same transaction that records it, then sent by a background process after
+the command (or by td serve's deliveries job). A failed send is retried
+after 30s, 2m, 8m, 32m and so on, at most 6h apart, and marked failed after
+8 attempts. Receivers may see a delivery more than once; the X-TD-Delivery
+header carries its ID for deduplication.
+
+Delivered entries are kept 7 days and failed ones 30 days.`,
+	Example: `  td webhook deliveries
+  td webhook deliveries --status failed
+  td webhook deliveries retry dl-1a2b3c4d
+  td webhook deliveries flush`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		status, _ := cmd.Flags().GetString("status")
+		limit, _ := cmd.Flags().GetInt("limit")
+		switch status {
+		case "", models.DeliveryPending, models.DeliveryDelivered, models.DeliveryFailed:
+		default:
+			err := fmt.Errorf("invalid status %q (pending, delivered or failed)", status)
+			output.Error("%v", err)
+			return err
+		}
+
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		deliveries, err := database.ListDeliveries(status, limit)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			for i := range deliveries {
+				deliveries[i].Payload = ""
+			}
+			data, _ := json.MarshalIndent(deliveries, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+		if len(deliveries) == 0 {
+			output.Info("No deliveries")
+			return nil
+		}
+		for _, d := range deliveries {
+			fmt.Printf("%s  %s  %-9s  %-12s  %d  %s", d.ID, d.CreatedAt.Local().Format("2006-01-02 15:04"),
+				d.Status, d.Kind, d.Attempts, deliveryTarget(d))
+			if d.Status != models.DeliveryDelivered && d.LastError != "" {
+				fmt.Printf("  (%s)", d.LastError)
+			}
+			fmt.Println()
+		}
+		return nil
+	},
+}
+
+var webhookDeliveriesShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Show a delivery with its payload",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		d, err := database.GetDelivery(args[0])
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			data, _ := json.MarshalIndent(d, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+		fmt.Printf("ID:        %s\n", d.ID)
+		fmt.Printf("Kind:      %s\n", d.Kind)
+		fmt.Printf("Target:    %s\n", deliveryTarget(*d))
+		fmt.Printf("Status:    %s\n", d.Status)
+		fmt.Printf("Attempts:  %d of %d\n", d.Attempts, delivery.MaxAttempts)
+		fmt.Printf("Created:   %s\n", d.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+		if d.DeliveredAt != nil {
+			fmt.Printf("Delivered: %s\n", d.DeliveredAt.Local().Format("2006-01-02 15:04:05"))
+		}
+		if d.Status == models.DeliveryPending && d.NextAttemptAt != nil {
+			fmt.Printf("Next try:  %s\n", d.NextAttemptAt.Local().Format("2006-01-02 15:04:05"))
+		}
+		if d.LastError != "" {
+			fmt.Printf("Error:     %s\n", d.LastError)
+		}
+		fmt.Printf("\n%s\n", d.Payload)
+		return nil
+	},
+}
+
+var webhookDeliveriesRetryCmd = &cobra.Command{
+	Use:   "retry <id>",
+	Short: "Send a failed or pending delivery again now",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		d, err := database.RetryDelivery(args[0])
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		output.Success("Queued %s for another %d attempts", d.ID, delivery.MaxAttempts)
+		return nil
+	},
+}
+
+var webhookDeliveriesFlushCmd = &cobra.Command{
+	Use:   "flush",
+	Short: "Send everything due now and wait for it",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		baseDir := getBaseDir()
+		database, err := db.Open(baseDir)
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		defer database.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), deliverTimeout)
+		defer cancel()
+		res, err := delivery.Run(ctx, database, baseDir)
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			data, _ := json.MarshalIndent(res, "", "  ")
+			fmt.Println(string(data))
+		} else {
+			fmt.Printf("Queued %d, delivered %d, retrying %d, failed %d\n", res.Queued, res.Delivered, res.Retrying, res.Failed)
+		}
+		if err != nil {
+			output.Error("%v", err)
+			return err
+		}
+		return nil
+	},
+}
+
+// deliveryTarget describes where a delivery goes
+func deliveryTarget(d models.Delivery) string {
+	switch d.Kind {
+	case delivery.KindSubscription:
+		return d.Ref + " " + d.Target
+	case delivery.KindNotify:
+		return d.Target + " route " + d.Ref
+	}
+	return d.Target
+}
+
+func init() {
+	webhookDeliveriesCmd.Flags().String("status", "", "Only deliveries with this status (pending, delivered, failed)")
+	webhookDeliveriesCmd.Flags().Int("limit", 50, "Maximum number of deliveries to list (0 = all)")
+	webhookDeliveriesCmd.Flags().Bool("json", false, "Output as JSON, without payloads")
+	webhookDeliveriesShowCmd.Flags().Bool("json", false, "Output as JSON")
+	webhookDeliveriesFlushCmd.Flags().Bool("json", false, "Output as JSON")
+	webhookDeliveriesCmd.AddCommand(webhookDeliveriesShowCmd, webhookDeliveriesRetryCmd, webhookDeliveriesFlushCmd)
+	webhookCmd.AddCommand(webhookDeliveriesCmd)
+}
@@ -1,96 +1,96 @@
 package cmd
 
 import (
+	"context"
 	"log/slog"
 	"os"
 	"os/exec"
 	"syscall"
+	"time"
 
+	"github.com/marcus/td/internal/clock"
 	"github.com/marcus/td/internal/db"
-	"github.com/marcus/td/internal/webhook"
+	"github.com/marcus/td/internal/delivery"
+	"github.com/spf13/cobra"
 )
 
-// webhookPreRunRowid is captured in PersistentPreRun so we can query
-// action_log entries created during the command. We use rowid instead of
-// timestamps because the sidecar writes RFC3339 ("…T…Z") while the
-// modernc.org/sqlite driver serializes time.Time via Go's .String()
-// ("… …-0500 EST m=+…"). SQLite text comparison on these mixed formats
-// is unreliable — 'T' > ' ' makes sidecar actions appear newer than any
-// CLI-written timestamp.
-var webhookPreRunRowid int64
+// deliverTimeout bounds the detached delivery process, so an endpoint that
+// hangs can't keep it around; what it doesn't get to waits for the next
+// command or td serve's deliveries job
+const deliverTimeout = 2 * time.Minute
 
-// captureWebhookState saves the current max action_log rowid for later
-// use by dispatchWebhookAsync.
-func captureWebhookState() {
+// dispatchDeliveries hands the changes a command made to the delivery
+// outbox: the project webhook, subscription webhooks whose filter they
+// match, and chat channel integrations each get a delivery. A detached
+// child process then sends them, along with earlier deliveries due for a
+// retry. The parent does not wait for it.
+func dispatchDeliveries() {
 	dir := getBaseDir()
-	if dir == "" || !webhook.IsEnabled(dir) {
+	if dir == "" || !delivery.Configured(dir) {
 		return
 	}
 
 	database, err := db.Open(dir)
 	if err != nil {
+		slog.Debug("delivery: open db", "err", err)
 		return
 	}
 	defer database.Close()
 
-	webhookPreRunRowid, _ = database.MaxActionRowid()
-}
-
-// dispatchWebhookAsync checks for new action_log entries since the pre-run
-// rowid snapshot, writes a temp file, and spawns a detached child process to
-// POST the webhook. The parent does not wait for the child.
-func dispatchWebhookAsync() {
-	dir := getBaseDir()
-	if dir == "" {
-		return
-	}
-
-	if !webhook.IsEnabled(dir) {
-		return
-	}
-
-	database, err := db.Open(dir)
+	queued, err := delivery.FanOut(database, dir)
 	if err != nil {
-		slog.Debug("webhook: open db", "err", err)
-		return
+		slog.Debug("delivery: fan out", "err", err)
 	}
-	defer database.Close()
-
-	actions, err := database.GetActionsAfterRowid(webhookPreRunRowid)
-	if err != nil {
-		slog.Debug("webhook: query actions", "err", err)
-		return
-	}
-	if len(actions) == 0 {
-		return
-	}
-
-	payload := webhook.BuildPayload(dir, actions)
-	tf := &webhook.TempFile{
-		URL:     webhook.GetURL(dir),
-		Secret:  webhook.GetSecret(dir),
-		Payload: payload,
-	}
-
-	path, err := webhook.WriteTempFile(tf)
-	if err != nil {
-		slog.Debug("webhook: write temp file", "err", err)
-		return
+	if queued == 0 {
+		due, err := database.DueDeliveries(clock.Now(), 1)
+		if err != nil || len(due) == 0 {
+			return
+		}
 	}
+	spawnDetached("_deliver")
+}
 
-	// Spawn detached child: td _webhook-send <tempfile>
-	child := exec.Command(os.Args[0], "_webhook-send", path)
+// spawnDetached runs `td <args>` in a new process group without waiting
+// for it
+func spawnDetached(args ...string) {
+	child := exec.Command(os.Args[0], args...)
 	child.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
 	child.Stdout = nil
 	child.Stderr = nil
 	child.Stdin = nil
 
 	if err := child.Start(); err != nil {
-		slog.Debug("webhook: spawn child", "err", err)
-		os.Remove(path)
+		slog.Debug("delivery: spawn child", "args", args, "err", err)
 		return
 	}
 
-	slog.Debug("webhook: dispatched", "pid", child.Process.Pid, "actions", len(actions))
+	slog.Debug("delivery: dispatched", "args", args, "pid", child.Process.Pid)
 	// Don't wait — parent exits immediately.
 }
+
+var deliverCmd = &cobra.Command{
+	Use:    "_deliver",
+	Short:  "Internal: send the deliveries waiting in the outbox",
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := db.Open(getBaseDir())
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), deliverTimeout)
+		defer cancel()
+		res, err := delivery.Run(ctx, database, getBaseDir())
+		slog.Debug("delivery: pass", "delivered", res.Delivered, "retrying", res.Retrying, "failed", res.Failed, "err", err)
+		return nil
+	},
+	// Disable all hooks for the internal delivery command.
+	PersistentPreRun:  func(cmd *cobra.Command, args []string) {},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {},
+}
+
+func init() {
+	rootCmd.AddCommand(deliverCmd)
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/output"
+	"github.com/marcus/td/internal/subscription"
+	"github.com/spf13/cobra"
+)
+
+var webhookSubscribeCmd = &cobra.Command{
+	Use:   "subscribe",
+	Short: "Add a filtered webhook or event stream subscription",
+	Long: `Adds a subscription to changes touching the issues that match a label,
+an epic (with everything under it) or a TDQ query; with several, all must
+match. With --url, matching changes are posted there in the same format as
+the project webhook. Without one, the subscription is a named filter for
+td serve's event stream (GET /v1/events?subscription=<id>).`,
+	Example: `  td webhook subscribe --label security --url https://example.com/hooks/security
+  td webhook subscribe --epic td-a1b2 --name "checkout epic"
+  td webhook subscribe --query "priority <= P1 AND type = bug" --url https://example.com/hooks/p1 --secret s3cret`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sub := models.Subscription{}
+		sub.Name, _ = cmd.Flags().GetString("name")
+		sub.Label, _ = cmd.Flags().GetString("label")
+		sub.Epic, _ = cmd.Flags().GetString("epic")
+		sub.Query, _ = cmd.Flags().GetString("query")
+		sub.URL, _ = cmd.Flags().GetString("url")
+		sub.Secret, _ = cmd.Flags().GetString("secret")
+		if sub.Secret != "" && sub.URL == "" {
+			return fmt.Errorf("--secret needs --url")
+		}
+		if err := subscription.Validate(sub); err != nil {
+			return err
+		}
+
+		if err := config.AddSubscription(getBaseDir(), &sub); err != nil {
+			return fmt.Errorf("save config: %w", err)
+		}
+		fmt.Printf("Subscription %s: %s\n", sub.ID, subscription.Describe(sub))
+		if sub.URL != "" {
+			fmt.Printf("Delivering to: %s\n", sub.URL)
+		} else {
+			fmt.Printf("Stream with: GET /v1/events?subscription=%s\n", sub.ID)
+		}
+		return nil
+	},
+}
+
+var webhookSubscriptionsCmd = &cobra.Command{
+	Use:     "subscriptions",
+	Aliases: []string{"subs"},
+	Short:   "List subscriptions",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		subs, err := config.GetSubscriptions(getBaseDir())
+		if err != nil {
+			return fmt.Errorf("load config: %w", err)
+		}
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			for i := range subs {
+				subs[i].Secret = ""
+			}
+			if subs == nil {
+				subs = []models.Subscription{}
+			}
+			return output.JSON(subs)
+		}
+		if len(subs) == 0 {
+			fmt.Println("No subscriptions")
+			return nil
+		}
+		for _, sub := range subs {
+			target := "event stream"
+			if sub.URL != "" {
+				target = sub.URL
+			}
+			name := ""
+			if sub.Name != "" {
+				name = fmt.Sprintf(" (%s)", sub.Name)
+			}
+			fmt.Printf("%s%s  %s  -> %s\n", sub.ID, name, subscription.Describe(sub), target)
+		}
+		return nil
+	},
+}
+
+var webhookUnsubscribeCmd = &cobra.Command{
+	Use:   "unsubscribe <id>",
+	Short: "Remove a subscription",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		removed, err := config.RemoveSubscription(getBaseDir(), args[0])
+		if err != nil {
+			return fmt.Errorf("save config: %w", err)
+		}
+		if !removed {
+			return fmt.Errorf("subscription not found: %s", args[0])
+		}
+		fmt.Printf("Subscription %s removed.\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	webhookSubscribeCmd.Flags().String("name", "", "Name to recognize the subscription by")
+	webhookSubscribeCmd.Flags().String("label", "", "Only issues with this label")
+	webhookSubscribeCmd.Flags().String("epic", "", "Only this epic and the issues under it")
+	webhookSubscribeCmd.Flags().String("query", "", "Only issues matching this TDQ query")
+	webhookSubscribeCmd.Flags().String("url", "", "Post matching changes to this URL")
+	webhookSubscribeCmd.Flags().String("secret", "", "HMAC-SHA256 signing secret for --url")
+	webhookSubscriptionsCmd.Flags().Bool("json", false, "JSON output")
+	webhookCmd.AddCommand(webhookSubscribeCmd, webhookSubscriptionsCmd, webhookUnsubscribeCmd)
+}
@@ -163,6 +163,7 @@ var wsTagCmd = &cobra.Command{
 						Event:      "start",
 						CommitSHA:  gitState.CommitSHA,
 						Branch:     gitState.Branch,
+						Repo:       gitState.Repo,
 						DirtyFiles: gitState.DirtyFiles,
 					})
 				}
@@ -501,6 +502,7 @@ Flags support values, stdin (-), or file (@path):
 					Event:      "handoff",
 					CommitSHA:  gitState.CommitSHA,
 					Branch:     gitState.Branch,
+					Repo:       gitState.Repo,
 					DirtyFiles: gitState.DirtyFiles,
 				})
 			}
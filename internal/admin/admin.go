@@ -0,0 +1,134 @@
+// Package admin repairs stuck workflow state on behalf of another session:
+// handing an issue to a new implementer or clearing a reviewer who has gone
+// away. Each repair is applied as a logged update attributed to the session
+// acted for and recorded as an impersonation, so the audit trail shows both
+// who made the change and whose state it changed.
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+// ErrReasonRequired is returned when a repair is attempted without a reason
+var ErrReasonRequired = errors.New("a reason is required")
+
+// Result is a repaired issue and the impersonation record it produced
+type Result struct {
+	Issue         *models.Issue         `json:"issue"`
+	Impersonation *models.Impersonation `json:"impersonation"`
+}
+
+// Reassign makes implementer the issue's implementer in place of its
+// current one. The new session must exist; it is recorded as having
+// started the issue, so it cannot later review its own work.
+func Reassign(database *db.DB, issueID, implementer, actor, reason string) (*Result, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrReasonRequired
+	}
+	issue, err := database.GetIssue(issueID)
+	if err != nil {
+		return nil, err
+	}
+	sess, err := database.GetSessionByID(implementer)
+	if err != nil {
+		return nil, err
+	}
+	if sess == nil {
+		return nil, fmt.Errorf("session not found: %s", implementer)
+	}
+	if issue.ImplementerSession == implementer {
+		return nil, fmt.Errorf("%s is already implemented by %s", issue.ID, implementer)
+	}
+
+	before := issue.ImplementerSession
+	issue.ImplementerSession = implementer
+	im := &models.Impersonation{
+		IssueID:      issue.ID,
+		Action:       models.ImpersonateReassign,
+		ActorSession: actor,
+		AsSession:    before,
+		Before:       before,
+		After:        implementer,
+		Reason:       reason,
+	}
+	if err := apply(database, issue, im); err != nil {
+		return nil, err
+	}
+	if err := database.RecordSessionAction(issue.ID, implementer, models.ActionSessionStarted); err != nil {
+		return nil, err
+	}
+	return &Result{Issue: issue, Impersonation: im}, nil
+}
+
+// ClearReviewer removes the issue's reviewer so another session can pick
+// up the review
+func ClearReviewer(database *db.DB, issueID, actor, reason string) (*Result, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrReasonRequired
+	}
+	issue, err := database.GetIssue(issueID)
+	if err != nil {
+		return nil, err
+	}
+	if issue.ReviewerSession == "" {
+		return nil, fmt.Errorf("%s has no reviewer", issue.ID)
+	}
+
+	before := issue.ReviewerSession
+	issue.ReviewerSession = ""
+	im := &models.Impersonation{
+		IssueID:      issue.ID,
+		Action:       models.ImpersonateClearReviewer,
+		ActorSession: actor,
+		AsSession:    before,
+		Before:       before,
+		Reason:       reason,
+	}
+	if err := apply(database, issue, im); err != nil {
+		return nil, err
+	}
+	return &Result{Issue: issue, Impersonation: im}, nil
+}
+
+// apply saves issue as the session im acts for (or the actor when there
+// was nobody to act for), notes the repair on the issue and records im
+func apply(database *db.DB, issue *models.Issue, im *models.Impersonation) error {
+	as := im.AsSession
+	if as == "" {
+		as = im.ActorSession
+	}
+	if err := database.UpdateIssueLogged(issue, as, models.ActionUpdate); err != nil {
+		return err
+	}
+
+	msg := fmt.Sprintf("Admin %s: %s", im.ActorSession, describe(im))
+	if err := database.AddLog(&models.Log{
+		IssueID:   issue.ID,
+		SessionID: im.ActorSession,
+		Message:   msg + " (" + im.Reason + ")",
+		Type:      models.LogTypeProgress,
+	}); err != nil {
+		return err
+	}
+	return database.RecordImpersonation(im)
+}
+
+func describe(im *models.Impersonation) string {
+	switch im.Action {
+	case models.ImpersonateReassign:
+		if im.Before == "" {
+			return "assigned implementer " + im.After
+		}
+		return fmt.Sprintf("reassigned implementer %s -> %s", im.Before, im.After)
+	case models.ImpersonateClearReviewer:
+		return "cleared reviewer " + im.Before
+	}
+	return string(im.Action)
+}
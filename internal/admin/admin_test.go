@@ -0,0 +1,77 @@
+package admin
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+func TestRepairs(t *testing.T) {
+	database, err := db.Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	defer database.Close()
+
+	if err := database.UpsertSession(&db.SessionRow{ID: "ses_new", StartedAt: time.Now(), LastActivity: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	issue := &models.Issue{Title: "Stuck migration rollout"}
+	if err := database.CreateIssue(issue); err != nil {
+		t.Fatal(err)
+	}
+	issue.Status = models.StatusInReview
+	issue.ImplementerSession = "ses_gone"
+	issue.ReviewerSession = "ses_rev"
+	if err := database.UpdateIssue(issue); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Reassign(database, issue.ID, "ses_new", "ses_admin", "  "); !errors.Is(err, ErrReasonRequired) {
+		t.Errorf("blank reason: err = %v", err)
+	}
+	if _, err := Reassign(database, issue.ID, "ses_nobody", "ses_admin", "agent crashed"); err == nil {
+		t.Error("reassign to an unknown session succeeded")
+	}
+
+	res, err := Reassign(database, issue.ID, "ses_new", "ses_admin", "agent crashed")
+	if err != nil {
+		t.Fatalf("Reassign: %v", err)
+	}
+	got, _ := database.GetIssue(issue.ID)
+	if got.ImplementerSession != "ses_new" || res.Impersonation.AsSession != "ses_gone" || res.Impersonation.After != "ses_new" {
+		t.Errorf("after reassign: issue %+v, record %+v", got, res.Impersonation)
+	}
+	if involved, _ := database.WasSessionInvolved(issue.ID, "ses_new"); !involved {
+		t.Error("new implementer not recorded as involved")
+	}
+
+	if _, err := ClearReviewer(database, issue.ID, "ses_admin", "reviewer went away"); err != nil {
+		t.Fatalf("ClearReviewer: %v", err)
+	}
+	if got, _ := database.GetIssue(issue.ID); got.ReviewerSession != "" {
+		t.Errorf("reviewer = %q, want cleared", got.ReviewerSession)
+	}
+	if _, err := ClearReviewer(database, issue.ID, "ses_admin", "again"); err == nil {
+		t.Error("clearing an absent reviewer succeeded")
+	}
+
+	records, _ := database.ListImpersonations(db.ImpersonationFilter{IssueID: issue.ID})
+	if len(records) != 2 || records[0].Action != models.ImpersonateClearReviewer || records[0].ActorSession != "ses_admin" {
+		t.Errorf("impersonations = %+v", records)
+	}
+	logs, _ := database.GetLogs(issue.ID, 0)
+	var noted int
+	for _, l := range logs {
+		if strings.HasPrefix(l.Message, "Admin ses_admin:") {
+			noted++
+		}
+	}
+	if noted != 2 {
+		t.Errorf("admin log notes = %d, want 2", noted)
+	}
+}
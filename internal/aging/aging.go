@@ -0,0 +1,190 @@
+// Package aging measures how long in-flight issues have sat in their current
+// status against how long issues have historically spent in that status, to
+// surface stuck work before anyone marks it blocked.
+package aging
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+// DefaultHistoryDays is how far back historical time-in-status is sampled
+const DefaultHistoryDays = 90
+
+// MaxHistoryDays bounds the history window
+const MaxHistoryDays = 730
+
+// DefaultOutlierPercentile is the historical percentile an issue's age must
+// exceed to be flagged
+const DefaultOutlierPercentile = 85
+
+// MinSamples is the number of historical spans a status needs before its
+// issues can be flagged
+const MinSamples = 3
+
+// InFlight lists the statuses aged, in board order
+var InFlight = []models.Status{
+	models.StatusInProgress,
+	models.StatusBlocked,
+	models.StatusInReview,
+}
+
+// Item is one in-flight issue
+type Item struct {
+	ID         string        `json:"id"`
+	Title      string        `json:"title"`
+	Status     models.Status `json:"status"`
+	Priority   string        `json:"priority"`
+	Since      time.Time     `json:"since"`
+	AgeDays    float64       `json:"age_days"`
+	Percentile *int          `json:"percentile"` // nil without enough history for the status
+	Outlier    bool          `json:"outlier"`
+	Estimated  bool          `json:"estimated,omitempty"` // no status change logged; Since is the last update
+}
+
+// Norm is the historical time spent in one status
+type Norm struct {
+	Status    models.Status `json:"status"`
+	Samples   int           `json:"samples"`
+	P50Days   float64       `json:"p50_days"`
+	P85Days   float64       `json:"p85_days"`
+	P95Days   float64       `json:"p95_days"`
+	Threshold float64       `json:"threshold_days"` // age beyond which an issue is an outlier; 0 without enough samples
+}
+
+// Report is the aging picture of work in progress, oldest first
+type Report struct {
+	Items             []Item    `json:"items"`
+	Norms             []Norm    `json:"norms"`
+	Outliers          int       `json:"outliers"`
+	HistoryDays       int       `json:"history_days"`
+	OutlierPercentile int       `json:"outlier_percentile"`
+	GeneratedAt       time.Time `json:"generated_at"`
+}
+
+// Options tune a report; zero values take the defaults
+type Options struct {
+	HistoryDays       int
+	OutlierPercentile int
+}
+
+// Analyze ages issues against the spans between transitions, which must be
+// oldest first. Spans that ended before the history window are ignored.
+func Analyze(issues []models.Issue, transitions []db.StatusTransition, opts Options, now time.Time) *Report {
+	if opts.HistoryDays <= 0 {
+		opts.HistoryDays = DefaultHistoryDays
+	}
+	if opts.OutlierPercentile <= 0 {
+		opts.OutlierPercentile = DefaultOutlierPercentile
+	}
+	r := &Report{
+		Items:             []Item{},
+		Norms:             []Norm{},
+		HistoryDays:       opts.HistoryDays,
+		OutlierPercentile: opts.OutlierPercentile,
+		GeneratedAt:       now,
+	}
+
+	// Walk each issue's transitions: the time from entering a status to
+	// leaving it is one historical span; the last entry is its current since
+	windowStart := now.AddDate(0, 0, -opts.HistoryDays)
+	spans := map[models.Status][]float64{}
+	entered := map[string]db.StatusTransition{}
+	for _, t := range transitions {
+		if prev, ok := entered[t.IssueID]; ok && !t.At.Before(windowStart) {
+			spans[prev.To] = append(spans[prev.To], days(t.At.Sub(prev.At)))
+		}
+		entered[t.IssueID] = t
+	}
+
+	thresholds := map[models.Status]float64{}
+	for _, status := range InFlight {
+		s := spans[status]
+		sort.Float64s(s)
+		n := Norm{Status: status, Samples: len(s)}
+		if len(s) > 0 {
+			n.P50Days = round2(quantile(s, 50))
+			n.P85Days = round2(quantile(s, 85))
+			n.P95Days = round2(quantile(s, 95))
+		}
+		if len(s) >= MinSamples {
+			n.Threshold = round2(quantile(s, opts.OutlierPercentile))
+			thresholds[status] = n.Threshold
+		}
+		r.Norms = append(r.Norms, n)
+	}
+
+	inFlight := map[models.Status]bool{}
+	for _, s := range InFlight {
+		inFlight[s] = true
+	}
+	for _, issue := range issues {
+		if !inFlight[issue.Status] {
+			continue
+		}
+		it := Item{
+			ID:       issue.ID,
+			Title:    issue.Title,
+			Status:   issue.Status,
+			Priority: string(issue.Priority),
+			Since:    issue.UpdatedAt,
+		}
+		if t, ok := entered[issue.ID]; ok && t.To == issue.Status {
+			it.Since = t.At
+		} else {
+			it.Estimated = true
+		}
+		it.AgeDays = round2(days(now.Sub(it.Since)))
+
+		if s := spans[issue.Status]; len(s) >= MinSamples {
+			pct := int(math.Round(100 * float64(sort.SearchFloat64s(s, it.AgeDays)) / float64(len(s))))
+			it.Percentile = &pct
+			it.Outlier = it.AgeDays > thresholds[issue.Status]
+		}
+		if it.Outlier {
+			r.Outliers++
+		}
+		r.Items = append(r.Items, it)
+	}
+	sort.SliceStable(r.Items, func(i, j int) bool { return r.Items[i].AgeDays > r.Items[j].AgeDays })
+	return r
+}
+
+// Compute builds the report for all in-flight issues in the database
+func Compute(database *db.DB, opts Options, now time.Time) (*Report, error) {
+	issues, err := database.ListIssues(db.ListIssuesOptions{Status: InFlight})
+	if err != nil {
+		return nil, err
+	}
+	// The full log: an issue may have entered its status long before the
+	// history window
+	transitions, err := database.GetStatusTransitions(time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	return Analyze(issues, transitions, opts, now), nil
+}
+
+// quantile returns the p-th percentile of sorted by nearest rank
+func quantile(sorted []float64, p int) float64 {
+	idx := int(math.Ceil(float64(p)/100*float64(len(sorted)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return sorted[idx]
+}
+
+func days(d time.Duration) float64 {
+	if d < 0 {
+		return 0
+	}
+	return d.Hours() / 24
+}
+
+func round2(f float64) float64 {
+	return math.Round(f*100) / 100
+}
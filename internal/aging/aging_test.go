@@ -0,0 +1,95 @@
+package aging
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+func TestAnalyze(t *testing.T) {
+	now := time.Date(2026, 3, 20, 12, 0, 0, 0, time.UTC)
+	at := func(daysAgo float64) time.Time { return now.Add(-time.Duration(daysAgo * 24 * float64(time.Hour))) }
+	move := func(id string, from, to models.Status, daysAgo float64) db.StatusTransition {
+		return db.StatusTransition{IssueID: id, From: from, To: to, At: at(daysAgo)}
+	}
+
+	// Four past issues spent 1, 2, 3 and 4 days in review
+	var ts []db.StatusTransition
+	for i, d := range []float64{1, 2, 3, 4} {
+		id := []string{"td-h1", "td-h2", "td-h3", "td-h4"}[i]
+		ts = append(ts,
+			move(id, models.StatusInProgress, models.StatusInReview, 30),
+			move(id, models.StatusInReview, models.StatusClosed, 30-d),
+		)
+	}
+	// A span that ended before the window doesn't count
+	ts = append(ts,
+		move("td-old", models.StatusInProgress, models.StatusInReview, 200),
+		move("td-old", models.StatusInReview, models.StatusClosed, 150),
+	)
+	ts = append(ts,
+		move("td-stuck", models.StatusInProgress, models.StatusInReview, 6),
+		move("td-fresh", models.StatusInProgress, models.StatusInReview, 0.5),
+	)
+
+	issues := []models.Issue{
+		{ID: "td-stuck", Status: models.StatusInReview},
+		{ID: "td-fresh", Status: models.StatusInReview},
+		{ID: "td-wip", Status: models.StatusInProgress, UpdatedAt: at(2)},
+		{ID: "td-h1", Status: models.StatusClosed},
+	}
+
+	r := Analyze(issues, ts, Options{}, now)
+	if len(r.Items) != 3 || r.HistoryDays != DefaultHistoryDays || r.Outliers != 1 {
+		t.Fatalf("report = %+v", r)
+	}
+
+	stuck, wip, fresh := r.Items[0], r.Items[1], r.Items[2]
+	if stuck.ID != "td-stuck" || stuck.AgeDays != 6 || !stuck.Outlier || *stuck.Percentile != 100 {
+		t.Errorf("stuck = %+v", stuck)
+	}
+	if fresh.Outlier || *fresh.Percentile != 0 || fresh.Estimated {
+		t.Errorf("fresh = %+v", fresh)
+	}
+	// No transitions logged and no history for in_progress
+	if wip.ID != "td-wip" || !wip.Estimated || wip.Percentile != nil || wip.Outlier || wip.AgeDays != 2 {
+		t.Errorf("wip = %+v", wip)
+	}
+
+	var review Norm
+	for _, n := range r.Norms {
+		if n.Status == models.StatusInReview {
+			review = n
+		}
+	}
+	if review.Samples != 4 || review.P50Days != 2 || review.Threshold != 4 {
+		t.Errorf("review norm = %+v", review)
+	}
+}
+
+func TestCompute(t *testing.T) {
+	database, err := db.Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	defer database.Close()
+
+	issue := &models.Issue{Title: "Review me"}
+	if err := database.CreateIssueLogged(issue, "ses_a"); err != nil {
+		t.Fatalf("CreateIssueLogged: %v", err)
+	}
+	issue.Status = models.StatusInReview
+	if err := database.UpdateIssueLogged(issue, "ses_a", models.ActionReview); err != nil {
+		t.Fatalf("UpdateIssueLogged: %v", err)
+	}
+
+	r, err := Compute(database, Options{}, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Compute: %v", err)
+	}
+	if len(r.Items) != 1 || r.Items[0].Estimated || r.Items[0].AgeDays < 0.04 {
+		t.Errorf("items = %+v", r.Items)
+	}
+}
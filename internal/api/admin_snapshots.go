@@ -3,6 +3,7 @@ package api
 import (
 	"database/sql"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -188,64 +189,13 @@ func (s *Server) handleAdminSnapshotQuery(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	// Get head seq from events.db
-	eventsDB, err := s.dbPool.Get(projectID)
-	if err != nil {
-		writeError(w, http.StatusNotFound, ErrCodeSnapshotUnavailable, "no events for project")
-		return
-	}
-
-	var headSeq int64
-	if err := eventsDB.QueryRow(`SELECT COALESCE(MAX(server_seq), 0) FROM events`).Scan(&headSeq); err != nil {
-		slog.Error("snapshot query: head seq", "err", err)
-		writeError(w, http.StatusInternalServerError, ErrCodeInternal, "database error")
-		return
-	}
-	if headSeq == 0 {
+	snapDB, snapshotSeq, err := s.openSnapshot(projectID)
+	if errors.Is(err, errNoEvents) {
 		writeError(w, http.StatusNotFound, ErrCodeSnapshotUnavailable, "no events for project")
 		return
 	}
-
-	// Find cached snapshot
-	cacheDir := filepath.Join(s.config.ProjectDataDir, "snapshots", projectID)
-	snapshotSeq := int64(0)
-	snapshotPath := ""
-
-	entries, err := os.ReadDir(cacheDir)
-	if err == nil {
-		for _, e := range entries {
-			if e.IsDir() || !strings.HasSuffix(e.Name(), ".db") || strings.Contains(e.Name(), ".tmp") {
-				continue
-			}
-			name := strings.TrimSuffix(e.Name(), ".db")
-			if seq, err := strconv.ParseInt(name, 10, 64); err == nil && seq > snapshotSeq {
-				snapshotSeq = seq
-				snapshotPath = filepath.Join(cacheDir, e.Name())
-			}
-		}
-	}
-
-	// If no cached snapshot or snapshot is too stale, build a fresh one
-	if snapshotPath == "" || (headSeq-snapshotSeq) > snapshotStalenessThreshold {
-		newPath, newSeq, err := s.buildAndCacheSnapshot(projectID, eventsDB, headSeq, cacheDir)
-		if err != nil {
-			slog.Error("snapshot query: build snapshot", "project", projectID, "err", err)
-			if snapshotPath == "" {
-				writeError(w, http.StatusInternalServerError, ErrCodeInternal, "failed to build snapshot")
-				return
-			}
-			// Fall through with stale snapshot if rebuild failed
-			slog.Warn("snapshot query: using stale snapshot", "project", projectID, "staleness", headSeq-snapshotSeq)
-		} else {
-			snapshotPath = newPath
-			snapshotSeq = newSeq
-		}
-	}
-
-	// Open snapshot read-only
-	snapDB, err := sql.Open("sqlite", snapshotPath+"?mode=ro")
 	if err != nil {
-		slog.Error("snapshot query: open snapshot", "err", err)
+		slog.Error("snapshot query", "project", projectID, "err", err)
 		writeError(w, http.StatusInternalServerError, ErrCodeInternal, "failed to open snapshot")
 		return
 	}
@@ -256,7 +206,8 @@ func (s *Server) handleAdminSnapshotQuery(w http.ResponseWriter, r *http.Request
 
 	// Execute with a high limit to get all matching results for pagination
 	execOpts := query.ExecuteOptions{
-		Limit: 0, // no limit - we paginate after
+		Limit:   0, // no limit - we paginate after
+		Project: project.Name,
 	}
 	results, err := query.Execute(src, q, "", execOpts)
 	if err != nil {
@@ -301,6 +252,67 @@ func (s *Server) handleAdminSnapshotQuery(w http.ResponseWriter, r *http.Request
 	})
 }
 
+// errNoEvents is returned by openSnapshot for projects with no events yet
+var errNoEvents = errors.New("no events for project")
+
+// openSnapshot opens a read-only snapshot of a project's state, reusing
+// the newest cached snapshot unless it is too stale. A stale snapshot is
+// still used if a rebuild fails. The caller closes the database.
+func (s *Server) openSnapshot(projectID string) (*sql.DB, int64, error) {
+	eventsDB, err := s.dbPool.Get(projectID)
+	if err != nil {
+		return nil, 0, errNoEvents
+	}
+
+	var headSeq int64
+	if err := eventsDB.QueryRow(`SELECT COALESCE(MAX(server_seq), 0) FROM events`).Scan(&headSeq); err != nil {
+		return nil, 0, fmt.Errorf("head seq: %w", err)
+	}
+	if headSeq == 0 {
+		return nil, 0, errNoEvents
+	}
+
+	// Find cached snapshot
+	cacheDir := filepath.Join(s.config.ProjectDataDir, "snapshots", projectID)
+	snapshotSeq := int64(0)
+	snapshotPath := ""
+
+	entries, err := os.ReadDir(cacheDir)
+	if err == nil {
+		for _, e := range entries {
+			if e.IsDir() || !strings.HasSuffix(e.Name(), ".db") || strings.Contains(e.Name(), ".tmp") {
+				continue
+			}
+			name := strings.TrimSuffix(e.Name(), ".db")
+			if seq, err := strconv.ParseInt(name, 10, 64); err == nil && seq > snapshotSeq {
+				snapshotSeq = seq
+				snapshotPath = filepath.Join(cacheDir, e.Name())
+			}
+		}
+	}
+
+	// If no cached snapshot or snapshot is too stale, build a fresh one
+	if snapshotPath == "" || (headSeq-snapshotSeq) > snapshotStalenessThreshold {
+		newPath, newSeq, err := s.buildAndCacheSnapshot(projectID, eventsDB, headSeq, cacheDir)
+		if err != nil {
+			if snapshotPath == "" {
+				return nil, 0, fmt.Errorf("build snapshot: %w", err)
+			}
+			// Fall through with stale snapshot if rebuild failed
+			slog.Warn("snapshot: using stale snapshot", "project", projectID, "staleness", headSeq-snapshotSeq, "err", err)
+		} else {
+			snapshotPath = newPath
+			snapshotSeq = newSeq
+		}
+	}
+
+	snapDB, err := sql.Open("sqlite", snapshotPath+"?mode=ro")
+	if err != nil {
+		return nil, 0, fmt.Errorf("open snapshot: %w", err)
+	}
+	return snapDB, snapshotSeq, nil
+}
+
 // buildAndCacheSnapshot builds a new snapshot and caches it, returning the path and seq.
 func (s *Server) buildAndCacheSnapshot(projectID string, eventsDB *sql.DB, headSeq int64, cacheDir string) (string, int64, error) {
 	tmpFile, err := os.CreateTemp("", "td-snapshot-*.db")
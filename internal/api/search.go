@@ -0,0 +1,112 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/query"
+)
+
+// searchProjectResult holds one project's matches in a workspace search.
+type searchProjectResult struct {
+	ProjectID   string         `json:"project_id"`
+	ProjectName string         `json:"project_name"`
+	SnapshotSeq int64          `json:"snapshot_seq"`
+	Total       int            `json:"total"`
+	HasMore     bool           `json:"has_more"`
+	Issues      []models.Issue `json:"issues"`
+}
+
+// searchProjectError reports a project that could not be searched.
+type searchProjectError struct {
+	ProjectID   string `json:"project_id"`
+	ProjectName string `json:"project_name"`
+	Error       string `json:"error"`
+}
+
+// searchResponse is the JSON response for GET /v1/search.
+type searchResponse struct {
+	Data   []searchProjectResult `json:"data"`
+	Total  int                   `json:"total"`
+	Errors []searchProjectError  `json:"errors,omitempty"`
+}
+
+// handleSearch runs a TDQ expression against the snapshot of every project
+// the caller is a member of. Results are grouped by project, each capped
+// at limit; the project field in TDQ matches the project name. The
+// optional session parameter is the session @me resolves to.
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	user := getUserFromContext(r.Context())
+
+	q := r.URL.Query().Get("q")
+	if q == "" {
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidQuery, "missing required query parameter 'q'")
+		return
+	}
+	parsed, err := query.Parse(q)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidQuery, "parse error: "+err.Error())
+		return
+	}
+	if errs := parsed.Validate(); len(errs) > 0 {
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidQuery, "validation error: "+errs[0].Error())
+		return
+	}
+
+	limit := defaultQueryLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		n, err := strconv.Atoi(limitStr)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, ErrCodeBadRequest, "invalid limit parameter")
+			return
+		}
+		limit = min(n, maxQueryLimit)
+	}
+	sessionID := r.URL.Query().Get("session")
+
+	projects, err := s.store.ListProjectsForUser(user.UserID)
+	if err != nil {
+		logFor(r.Context()).Error("search: list projects", "err", err)
+		writeError(w, http.StatusInternalServerError, ErrCodeInternal, "failed to list projects")
+		return
+	}
+
+	resp := searchResponse{Data: []searchProjectResult{}}
+	for _, p := range projects {
+		snapDB, seq, err := s.openSnapshot(p.ID)
+		if errors.Is(err, errNoEvents) {
+			continue
+		}
+		if err != nil {
+			logFor(r.Context()).Error("search: open snapshot", "project", p.ID, "err", err)
+			resp.Errors = append(resp.Errors, searchProjectError{ProjectID: p.ID, ProjectName: p.Name, Error: "snapshot unavailable"})
+			continue
+		}
+
+		results, err := query.ExecuteQuery(NewSnapshotQuerySource(snapDB), parsed, sessionID, query.ExecuteOptions{Project: p.Name})
+		snapDB.Close()
+		if err != nil {
+			logFor(r.Context()).Error("search: execute", "project", p.ID, "err", err)
+			resp.Errors = append(resp.Errors, searchProjectError{ProjectID: p.ID, ProjectName: p.Name, Error: "query execution failed"})
+			continue
+		}
+		if len(results) == 0 {
+			continue
+		}
+
+		result := searchProjectResult{
+			ProjectID:   p.ID,
+			ProjectName: p.Name,
+			SnapshotSeq: seq,
+			Total:       len(results),
+			HasMore:     len(results) > limit,
+			Issues:      results[:min(len(results), limit)],
+		}
+		resp.Data = append(resp.Data, result)
+		resp.Total += result.Total
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestSearch_AcrossMemberProjects(t *testing.T) {
+	srv, store := newTestServer(t)
+	_, token := createTestUser(t, store, "search@test.com")
+	_, otherToken := createTestUser(t, store, "other@test.com")
+
+	push := func(token, name string, issues ...string) string {
+		t.Helper()
+		w := doRequest(srv, "POST", "/v1/projects", token, CreateProjectRequest{Name: name})
+		if w.Code != http.StatusCreated {
+			t.Fatalf("create %s: expected 201, got %d: %s", name, w.Code, w.Body.String())
+		}
+		var project ProjectResponse
+		json.NewDecoder(w.Body).Decode(&project)
+		if len(issues) == 0 {
+			return project.ID
+		}
+
+		var events []EventInput
+		for i, data := range issues {
+			events = append(events, EventInput{
+				ClientActionID: int64(i + 1), ActionType: "create", EntityType: "issues",
+				EntityID:        fmt.Sprintf("td-%s%d", name[:3], i),
+				Payload:         json.RawMessage(`{"schema_version":1,"new_data":` + data + `}`),
+				ClientTimestamp: fmt.Sprintf("2025-01-01T00:00:%02dZ", i),
+			})
+		}
+		w = doRequest(srv, "POST", fmt.Sprintf("/v1/projects/%s/sync/push", project.ID), token, PushRequest{
+			DeviceID: "dev1", SessionID: "sess1", Events: events,
+		})
+		if w.Code != http.StatusOK {
+			t.Fatalf("push %s: expected 200, got %d: %s", name, w.Code, w.Body.String())
+		}
+		return project.ID
+	}
+
+	review := `{"title":"needs review","status":"in_review","type":"task","priority":"P1","implementer_session":"ses_other"}`
+	mine := `{"title":"my own work","status":"in_review","type":"task","priority":"P1","implementer_session":"ses_me"}`
+	open := `{"title":"still open","status":"open","type":"task","priority":"P2"}`
+	apiID := push(token, "api", review, mine, open)
+	push(token, "web", review, review)
+	push(token, "empty-project")
+	push(otherToken, "private", review)
+
+	search := func(q string, extra string) searchResponse {
+		t.Helper()
+		w := doRequest(srv, "GET", "/v1/search?q="+url.QueryEscape(q)+extra, token, nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("search %q: expected 200, got %d: %s", q, w.Code, w.Body.String())
+		}
+		var resp searchResponse
+		json.NewDecoder(w.Body).Decode(&resp)
+		return resp
+	}
+
+	// The review queue spans both member projects, not the private one
+	resp := search("status = in_review AND implementer != @me", "&session=ses_me")
+	if resp.Total != 3 || len(resp.Data) != 2 {
+		t.Fatalf("review queue: total %d across %d projects, want 3 across 2: %+v", resp.Total, len(resp.Data), resp)
+	}
+	for _, p := range resp.Data {
+		if p.ProjectName == "private" {
+			t.Errorf("results include a project the user is not a member of")
+		}
+		for _, issue := range p.Issues {
+			if issue.ImplementerSession == "ses_me" {
+				t.Errorf("%s: @me did not resolve to the session parameter", issue.ID)
+			}
+		}
+	}
+
+	// The project field scopes results
+	resp = search(`project = "api" AND is(in_review)`, "")
+	if len(resp.Data) != 1 || resp.Data[0].ProjectID != apiID || resp.Data[0].Total != 2 {
+		t.Fatalf("project-scoped search = %+v, want 2 issues in api", resp.Data)
+	}
+
+	// limit caps each project's results
+	resp = search("is(in_review)", "&limit=1")
+	for _, p := range resp.Data {
+		if len(p.Issues) != 1 || !p.HasMore || p.Total != 2 {
+			t.Errorf("%s: %d issues, total %d, has_more %v; want 1 of 2", p.ProjectName, len(p.Issues), p.Total, p.HasMore)
+		}
+	}
+}
+
+func TestSearch_InvalidQuery(t *testing.T) {
+	srv, store := newTestServer(t)
+	_, token := createTestUser(t, store, "search-bad@test.com")
+
+	for _, path := range []string{"/v1/search", "/v1/search?q=" + url.QueryEscape("nosuchfield = 1")} {
+		w := doRequest(srv, "GET", path, token, nil)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d: %s", path, w.Code, w.Body.String())
+		}
+		var resp ErrorResponse
+		json.NewDecoder(w.Body).Decode(&resp)
+		if resp.Error.Code != ErrCodeInvalidQuery {
+			t.Errorf("%s: code %q, want %q", path, resp.Error.Code, ErrCodeInvalidQuery)
+		}
+	}
+
+	if w := doRequest(srv, "GET", "/v1/search?q=is(open)", "", nil); w.Code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated: expected 401, got %d", w.Code)
+	}
+}
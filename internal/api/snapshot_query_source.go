@@ -28,7 +28,7 @@ var _ query.QuerySource = (*SnapshotQuerySource)(nil)
 // issueColumns is the SELECT column list matching the scan order used throughout.
 const issueColumns = `id, title, description, status, type, priority, points, labels, parent_id, acceptance, sprint,
        implementer_session, creator_session, reviewer_session, created_at, updated_at, closed_at, deleted_at, minor, created_branch,
-       defer_until, due_date, defer_count`
+       defer_until, due_date, defer_count, created_repo, blocked_reason, blocked_ref, inbox, confidential, rank`
 
 // scanIssue scans a single issue row using the standard column order.
 func scanIssue(scanner interface{ Scan(dest ...any) error }) (models.Issue, error) {
@@ -37,7 +37,7 @@ func scanIssue(scanner interface{ Scan(dest ...any) error }) (models.Issue, erro
 	var closedAt, deletedAt sql.NullTime
 	var parentID, acceptance, sprint sql.NullString
 	var implSession, creatorSession, reviewerSession sql.NullString
-	var createdBranch sql.NullString
+	var createdBranch, createdRepo, blockedReason, blockedRef sql.NullString
 	var pointsNull sql.NullInt64
 	var deferUntil, dueDate sql.NullString
 
@@ -45,7 +45,7 @@ func scanIssue(scanner interface{ Scan(dest ...any) error }) (models.Issue, erro
 		&issue.ID, &issue.Title, &issue.Description, &issue.Status, &issue.Type, &issue.Priority,
 		&pointsNull, &labels, &parentID, &acceptance, &sprint,
 		&implSession, &creatorSession, &reviewerSession, &issue.CreatedAt, &issue.UpdatedAt, &closedAt, &deletedAt, &issue.Minor, &createdBranch,
-		&deferUntil, &dueDate, &issue.DeferCount,
+		&deferUntil, &dueDate, &issue.DeferCount, &createdRepo, &blockedReason, &blockedRef, &issue.Inbox, &issue.Confidential, &issue.Rank,
 	)
 	if err != nil {
 		return issue, err
@@ -68,6 +68,9 @@ func scanIssue(scanner interface{ Scan(dest ...any) error }) (models.Issue, erro
 	issue.CreatorSession = creatorSession.String
 	issue.ReviewerSession = reviewerSession.String
 	issue.CreatedBranch = createdBranch.String
+	issue.CreatedRepo = createdRepo.String
+	issue.BlockedReason = models.BlockedReason(blockedReason.String)
+	issue.BlockedRef = blockedRef.String
 	if deferUntil.Valid {
 		issue.DeferUntil = &deferUntil.String
 	}
@@ -270,11 +273,11 @@ func (s *SnapshotQuerySource) ListIssues(opts db.ListIssuesOptions) ([]models.Is
 	return issues, nil
 }
 
-// GetIssue retrieves a single issue by ID.
+// GetIssue retrieves a single issue by ID. Soft-deleted issues are not found.
 func (s *SnapshotQuerySource) GetIssue(id string) (*models.Issue, error) {
 	id = db.NormalizeIssueID(id)
 
-	row := s.db.QueryRow(`SELECT `+issueColumns+` FROM issues WHERE id = ?`, id)
+	row := s.db.QueryRow(`SELECT `+issueColumns+` FROM issues WHERE id = ? AND deleted_at IS NULL`, id)
 	issue, err := scanIssue(row)
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("issue not found: %s", id)
@@ -458,13 +461,14 @@ func (s *SnapshotQuerySource) GetRejectedInProgressIssueIDs() (map[string]bool,
 	return result, nil
 }
 
-// GetIssuesWithOpenDeps returns issue IDs that have at least one non-closed dependency.
+// GetIssuesWithOpenDeps returns issue IDs that have at least one non-closed
+// dependency or stack base.
 func (s *SnapshotQuerySource) GetIssuesWithOpenDeps() (map[string]bool, error) {
 	rows, err := s.db.Query(`
 		SELECT DISTINCT d.issue_id
 		FROM issue_dependencies d
 		JOIN issues i ON d.depends_on_id = i.id
-		WHERE d.relation_type = 'depends_on'
+		WHERE d.relation_type IN ('depends_on', 'stacked_on')
 		  AND i.status != 'closed'
 		  AND i.deleted_at IS NULL
 	`)
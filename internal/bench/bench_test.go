@@ -0,0 +1,65 @@
+package bench
+
+import (
+	"testing"
+
+	"github.com/marcus/td/internal/db"
+)
+
+func seeded(tb testing.TB, opts Options) *db.DB {
+	tb.Helper()
+	database, err := db.Initialize(tb.TempDir())
+	if err != nil {
+		tb.Fatalf("Initialize: %v", err)
+	}
+	tb.Cleanup(func() { database.Close() })
+	if _, err := Seed(database, opts); err != nil {
+		tb.Fatalf("Seed: %v", err)
+	}
+	return database
+}
+
+func TestSeedDeterministic(t *testing.T) {
+	opts := Options{Issues: 60, Seed: 3}
+	a, b := seeded(t, opts), seeded(t, opts)
+
+	for i := 0; i < opts.Issues; i++ {
+		x, err := a.GetIssue(IssueID(i))
+		if err != nil {
+			t.Fatalf("GetIssue(%s): %v", IssueID(i), err)
+		}
+		y, err := b.GetIssue(IssueID(i))
+		if err != nil {
+			t.Fatalf("GetIssue(%s): %v", IssueID(i), err)
+		}
+		if x.Title != y.Title || x.Status != y.Status || x.Priority != y.Priority || x.ParentID != y.ParentID {
+			t.Errorf("%s differs between runs: %+v vs %+v", x.ID, x, y)
+		}
+	}
+
+	depsA, _ := a.GetAllDependencies()
+	depsB, _ := b.GetAllDependencies()
+	if len(depsA) == 0 || len(depsA) != len(depsB) {
+		t.Errorf("dependencies = %d and %d, want equal and non-zero", len(depsA), len(depsB))
+	}
+}
+
+func TestCompare(t *testing.T) {
+	base := &Report{Results: []Measurement{{Name: "a", NsPerOp: 100}, {Name: "b", NsPerOp: 100}, {Name: "gone", NsPerOp: 1}}}
+	cur := &Report{Results: []Measurement{{Name: "a", NsPerOp: 115}, {Name: "b", NsPerOp: 150}, {Name: "new", NsPerOp: 1e9}}}
+
+	regs := Compare(base, cur, 20)
+	if len(regs) != 1 || regs[0].Name != "b" || regs[0].Percent != 50 {
+		t.Errorf("Compare = %+v, want only b at 50%%", regs)
+	}
+	if regs := Compare(base, cur, 10); len(regs) != 2 || regs[0].Name != "b" {
+		t.Errorf("Compare(10%%) = %+v, want b then a", regs)
+	}
+}
+
+func BenchmarkCases(b *testing.B) {
+	database := seeded(b, Options{Issues: 1000})
+	for _, c := range Cases(database) {
+		b.Run(c.Name, c.Run)
+	}
+}
@@ -0,0 +1,105 @@
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/query"
+	"github.com/marcus/td/internal/serve"
+	"github.com/marcus/td/pkg/monitor"
+)
+
+// SSEClients is how many subscribers the broadcast benchmark fans out to
+const SSEClients = 100
+
+// Queries are the TDQ queries the parse and execute benchmarks run, from a
+// plain filter to functions and nested boolean logic
+var Queries = []string{
+	"status = open",
+	"type = bug AND priority <= P1",
+	"labels ~ backend OR labels ~ api",
+	`status != closed AND (title ~ "cache" OR title ~ "search") AND NOT labels ~ flaky`,
+	"is(blocked) OR blocks(td-b00001)",
+	"descendant_of(td-b00000) AND (status = open OR status = in_progress)",
+}
+
+// Case is one benchmark run against a seeded database
+type Case struct {
+	Name string
+	Desc string
+	Run  func(b *testing.B)
+}
+
+// Cases returns the benchmarks over database, which Seed has filled
+func Cases(database *db.DB) []Case {
+	return []Case{
+		{"tdq/parse", "parse every benchmark query", benchParse},
+		{"tdq/execute", "run every benchmark query against the database", func(b *testing.B) { benchExecute(b, database) }},
+		{"monitor/fetch", "assemble the monitor's refresh data", func(b *testing.B) { benchMonitor(b, database) }},
+		{"list/serialize", "convert all issues to API DTOs and encode as JSON", func(b *testing.B) { benchSerialize(b, database) }},
+		{"sse/broadcast", fmt.Sprintf("broadcast a refresh to %d SSE clients", SSEClients), func(b *testing.B) { benchBroadcast(b, database) }},
+	}
+}
+
+func benchParse(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, q := range Queries {
+			if _, err := query.Parse(q); err != nil {
+				b.Fatalf("parse %q: %v", q, err)
+			}
+		}
+	}
+}
+
+func benchExecute(b *testing.B, database *db.DB) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, q := range Queries {
+			if _, err := query.Execute(database, q, SessionID, query.ExecuteOptions{}); err != nil {
+				b.Fatalf("execute %q: %v", q, err)
+			}
+		}
+	}
+}
+
+func benchMonitor(b *testing.B, database *db.DB) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		monitor.FetchDataWithSearchMode(database, SessionID, baseTime, "", "auto", false, monitor.SortByPriority)
+	}
+}
+
+func benchSerialize(b *testing.B, database *db.DB) {
+	issues, err := database.ListIssues(db.ListIssuesOptions{})
+	if err != nil {
+		b.Fatalf("list issues: %v", err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := json.Marshal(serve.IssuesToDTOs(issues)); err != nil {
+			b.Fatalf("marshal: %v", err)
+		}
+	}
+}
+
+func benchBroadcast(b *testing.B, database *db.DB) {
+	hub := serve.NewSSEHub(database, time.Hour)
+	for i := 0; i < SSEClients; i++ {
+		ch, unsubscribe := hub.Subscribe()
+		defer unsubscribe()
+		go func() {
+			for range ch {
+			}
+		}()
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		hub.Broadcast(fmt.Sprintf("bench-%d", i), "")
+	}
+}
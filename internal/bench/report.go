@@ -0,0 +1,111 @@
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"runtime"
+	"sort"
+	"testing"
+	"time"
+)
+
+// Measurement is the result of one case
+type Measurement struct {
+	Name        string  `json:"name"`
+	N           int     `json:"n"`
+	NsPerOp     float64 `json:"ns_per_op"`
+	BytesPerOp  int64   `json:"bytes_per_op"`
+	AllocsPerOp int64   `json:"allocs_per_op"`
+}
+
+// Report is a full benchmark run. Saved as JSON it is a baseline for
+// later runs.
+type Report struct {
+	GeneratedAt time.Time     `json:"generated_at"`
+	GoVersion   string        `json:"go_version"`
+	Issues      int           `json:"issues"`
+	Seed        int64         `json:"seed"`
+	Results     []Measurement `json:"results"`
+}
+
+// Run benchmarks each case whose name matches filter (all when nil)
+func Run(cases []Case, filter *regexp.Regexp, opts Options) *Report {
+	opts = opts.withDefaults()
+	rep := &Report{
+		GeneratedAt: time.Now().UTC(),
+		GoVersion:   runtime.Version(),
+		Issues:      opts.Issues,
+		Seed:        opts.Seed,
+	}
+	for _, c := range cases {
+		if filter != nil && !filter.MatchString(c.Name) {
+			continue
+		}
+		r := testing.Benchmark(c.Run)
+		if r.N == 0 {
+			continue // the case failed; testing.Benchmark has no error to return
+		}
+		rep.Results = append(rep.Results, Measurement{
+			Name:        c.Name,
+			N:           r.N,
+			NsPerOp:     float64(r.T.Nanoseconds()) / float64(r.N),
+			BytesPerOp:  r.AllocedBytesPerOp(),
+			AllocsPerOp: r.AllocsPerOp(),
+		})
+	}
+	return rep
+}
+
+// Regression is a case that got slower than the baseline allows
+type Regression struct {
+	Name     string  `json:"name"`
+	Baseline float64 `json:"baseline_ns_per_op"`
+	Current  float64 `json:"current_ns_per_op"`
+	Percent  float64 `json:"percent"` // slowdown over the baseline
+}
+
+// Compare returns the cases in rep more than maxPercent slower than in
+// baseline, worst first. Cases missing from either report are skipped.
+func Compare(baseline, rep *Report, maxPercent float64) []Regression {
+	base := make(map[string]float64, len(baseline.Results))
+	for _, m := range baseline.Results {
+		base[m.Name] = m.NsPerOp
+	}
+	var out []Regression
+	for _, m := range rep.Results {
+		was, ok := base[m.Name]
+		if !ok || was <= 0 {
+			continue
+		}
+		pct := (m.NsPerOp - was) / was * 100
+		if pct > maxPercent {
+			out = append(out, Regression{Name: m.Name, Baseline: was, Current: m.NsPerOp, Percent: pct})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Percent > out[j].Percent })
+	return out
+}
+
+// LoadReport reads a report saved as JSON
+func LoadReport(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rep Report
+	if err := json.Unmarshal(data, &rep); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return &rep, nil
+}
+
+// SaveReport writes a report as JSON
+func SaveReport(path string, rep *Report) error {
+	data, err := json.MarshalIndent(rep, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
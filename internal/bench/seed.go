@@ -0,0 +1,190 @@
+// Package bench seeds large synthetic projects and runs the performance
+// benchmarks shared by `go test -bench` and `td bench`.
+//
+// The data set is derived from a size and a random seed, so a slow query or
+// a sluggish monitor can be reported as "td bench --issues 20000 --seed 7"
+// and reproduced on any machine.
+package bench
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+// Defaults for an unset Options
+const (
+	DefaultIssues = 5000
+	DefaultSeed   = 1
+)
+
+// SessionID is the session that creates the synthetic data
+const SessionID = "ses_bench1"
+
+// baseTime anchors issue timestamps so the data does not depend on the clock
+var baseTime = time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+
+// Options sizes the synthetic data set
+type Options struct {
+	Issues int   // issues to create, epics included
+	Seed   int64 // random seed; the same seed and size give the same data
+}
+
+// withDefaults fills in unset options
+func (o Options) withDefaults() Options {
+	if o.Issues <= 0 {
+		o.Issues = DefaultIssues
+	}
+	if o.Seed == 0 {
+		o.Seed = DefaultSeed
+	}
+	return o
+}
+
+// Result counts what Seed created
+type Result struct {
+	Issues       int `json:"issues"`
+	Dependencies int `json:"dependencies"`
+	Comments     int `json:"comments"`
+	Logs         int `json:"logs"`
+	Boards       int `json:"boards"`
+}
+
+var (
+	titleVerbs = []string{"Fix", "Add", "Refactor", "Document", "Speed up", "Remove", "Migrate", "Validate", "Cache", "Test"}
+	titleNouns = []string{"checkout flow", "login form", "search index", "payment webhook", "session store",
+		"export job", "settings page", "rate limiter", "email templates", "audit log", "image uploads",
+		"order history", "API pagination", "feature flags", "background worker", "retry policy"}
+	labelPool = []string{"frontend", "backend", "api", "db", "perf", "security", "ux", "infra", "docs", "flaky"}
+	statuses  = []models.Status{models.StatusOpen, models.StatusOpen, models.StatusOpen, models.StatusInProgress,
+		models.StatusBlocked, models.StatusInReview, models.StatusClosed, models.StatusClosed}
+	types      = []models.Type{models.TypeTask, models.TypeTask, models.TypeBug, models.TypeFeature, models.TypeChore}
+	priorities = []models.Priority{models.PriorityP0, models.PriorityP1, models.PriorityP2, models.PriorityP2, models.PriorityP3, models.PriorityP4}
+	points     = []int{0, 1, 2, 3, 5, 8, 13}
+	logTypes   = []models.LogType{models.LogTypeProgress, models.LogTypeDecision, models.LogTypeTried, models.LogTypeResult}
+)
+
+// boards are created on top of the issues
+var boards = []struct{ name, query string }{
+	{"Bench: open bugs", "type = bug AND status != closed"},
+	{"Bench: backend", "labels ~ backend"},
+	{"Bench: urgent", "priority <= P1 AND status = open"},
+}
+
+// IssueID returns the ID Seed gives the i-th issue (from 0)
+func IssueID(i int) string {
+	return fmt.Sprintf("td-b%05x", i)
+}
+
+// Seed fills database with a synthetic project. One issue in ten is an
+// epic; about half the rest belong to an epic, a fifth depend on an earlier
+// issue, and most carry a few comments and logs.
+func Seed(database *db.DB, opts Options) (*Result, error) {
+	opts = opts.withDefaults()
+	rng := rand.New(rand.NewSource(opts.Seed))
+	res := &Result{}
+
+	if err := database.UpsertSession(&db.SessionRow{
+		ID:           SessionID,
+		Name:         "bench",
+		Branch:       "main",
+		StartedAt:    baseTime,
+		LastActivity: baseTime,
+	}); err != nil {
+		return res, fmt.Errorf("create session: %w", err)
+	}
+
+	var epics []string
+	for i := 0; i < opts.Issues; i++ {
+		created := baseTime.Add(time.Duration(i) * time.Minute)
+		issue := &models.Issue{
+			ID:             IssueID(i),
+			Title:          fmt.Sprintf("%s %s #%d", pick(rng, titleVerbs), pick(rng, titleNouns), i),
+			Description:    strings.Repeat("Synthetic benchmark issue. ", 1+rng.Intn(8)),
+			Status:         pick(rng, statuses),
+			Type:           pick(rng, types),
+			Priority:       pick(rng, priorities),
+			Points:         pick(rng, points),
+			Labels:         pickLabels(rng),
+			CreatorSession: SessionID,
+			CreatedAt:      created,
+			UpdatedAt:      created.Add(time.Duration(rng.Intn(72*60)) * time.Minute),
+		}
+		switch {
+		case i%10 == 0:
+			issue.Type = models.TypeEpic
+			epics = append(epics, issue.ID)
+		case len(epics) > 0 && rng.Intn(2) == 0:
+			issue.ParentID = pick(rng, epics)
+		}
+		if issue.Status == models.StatusInProgress || issue.Status == models.StatusInReview {
+			issue.ImplementerSession = SessionID
+		}
+		if issue.Status == models.StatusClosed {
+			closed := issue.UpdatedAt
+			issue.ClosedAt = &closed
+		}
+		if err := database.UpsertIssueRaw(issue); err != nil {
+			return res, fmt.Errorf("create %s: %w", issue.ID, err)
+		}
+		res.Issues++
+
+		if i > 0 && rng.Intn(5) == 0 {
+			if err := database.AddDependency(issue.ID, IssueID(rng.Intn(i)), "depends_on"); err != nil {
+				return res, fmt.Errorf("add dependency for %s: %w", issue.ID, err)
+			}
+			res.Dependencies++
+		}
+		for n := rng.Intn(4); n > 0; n-- {
+			if err := database.AddComment(&models.Comment{
+				IssueID:   issue.ID,
+				SessionID: SessionID,
+				Text:      fmt.Sprintf("Looked into %s again.", pick(rng, titleNouns)),
+			}); err != nil {
+				return res, fmt.Errorf("add comment to %s: %w", issue.ID, err)
+			}
+			res.Comments++
+		}
+		for n := rng.Intn(3); n > 0; n-- {
+			if err := database.AddLog(&models.Log{
+				IssueID:   issue.ID,
+				SessionID: SessionID,
+				Message:   fmt.Sprintf("Worked on %s", pick(rng, titleNouns)),
+				Type:      pick(rng, logTypes),
+			}); err != nil {
+				return res, fmt.Errorf("add log to %s: %w", issue.ID, err)
+			}
+			res.Logs++
+		}
+	}
+
+	for _, b := range boards {
+		if _, err := database.CreateBoard(b.name, b.query); err != nil {
+			return res, fmt.Errorf("create board %q: %w", b.name, err)
+		}
+		res.Boards++
+	}
+	return res, nil
+}
+
+func pick[T any](rng *rand.Rand, from []T) T {
+	return from[rng.Intn(len(from))]
+}
+
+// pickLabels returns zero to three distinct labels
+func pickLabels(rng *rand.Rand) []string {
+	n := rng.Intn(4)
+	if n == 0 {
+		return nil
+	}
+	perm := rng.Perm(len(labelPool))[:n]
+	labels := make([]string, n)
+	for i, p := range perm {
+		labels[i] = labelPool[p]
+	}
+	return labels
+}
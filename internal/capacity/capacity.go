@@ -0,0 +1,222 @@
+// Package capacity compares the points committed to a sprint with what
+// each session has closed recently, to flag over-commitment while there is
+// still time to rebalance.
+//
+// A session's throughput is the points of issues it implemented and closed
+// in a trailing window, per day. Its capacity for a sprint is that rate
+// over the sprint days still ahead; its load is the open points it holds in
+// the sprint divided by that capacity.
+package capacity
+
+import (
+	"sort"
+	"time"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+// DefaultWindowDays is how far back throughput is measured
+const DefaultWindowDays = 28
+
+// MaxWindowDays bounds the throughput window
+const MaxWindowDays = 365
+
+const dateLayout = "2006-01-02"
+
+// SessionLoad is one session's share of a sprint
+type SessionLoad struct {
+	Session       string  `json:"session"`
+	Name          string  `json:"name,omitempty"`
+	ClosedPoints  int     `json:"closed_points"`  // points closed in the window, any sprint
+	PointsPerDay  float64 `json:"points_per_day"` // ClosedPoints / window days
+	Capacity      float64 `json:"capacity"`       // expected points over the remaining sprint days
+	Committed     int     `json:"committed"`      // open sprint points implemented by this session
+	Done          int     `json:"done"`           // closed sprint points implemented by this session
+	Load          float64 `json:"load"`           // Committed / Capacity; 0 without history
+	NoHistory     bool    `json:"no_history"`     // nothing closed in the window, so capacity is unknown
+	OverCommitted bool    `json:"over_committed"`
+}
+
+// Report is the capacity picture for one sprint
+type Report struct {
+	Sprint        models.Sprint `json:"sprint"`
+	Days          int           `json:"days"`
+	RemainingDays int           `json:"remaining_days"`
+	WindowDays    int           `json:"window_days"`
+	Sessions      []SessionLoad `json:"sessions"`
+	Unassigned    int           `json:"unassigned"`  // open sprint points nobody has started
+	Unestimated   int           `json:"unestimated"` // open sprint issues without points
+	Committed     int           `json:"committed"`   // all open sprint points, including unassigned
+	Done          int           `json:"done"`
+	Capacity      float64       `json:"capacity"`
+	Load          float64       `json:"load"`
+	OverCommitted bool          `json:"over_committed"`
+}
+
+// Plan builds the report from the sprint's issues and the issues closed
+// during the throughput window. now picks the remaining sprint days.
+func Plan(sprint models.Sprint, sprintIssues, closed []models.Issue, windowDays int, now time.Time) (*Report, error) {
+	start, err := time.ParseInLocation(dateLayout, sprint.Start, now.Location())
+	if err != nil {
+		return nil, err
+	}
+	end, err := time.ParseInLocation(dateLayout, sprint.End, now.Location())
+	if err != nil {
+		return nil, err
+	}
+	if windowDays <= 0 {
+		windowDays = DefaultWindowDays
+	}
+
+	today := truncateDay(now)
+	from := start
+	if today.After(from) {
+		from = today
+	}
+	r := &Report{
+		Sprint:        sprint,
+		Days:          daysInclusive(start, end),
+		RemainingDays: daysInclusive(from, end),
+		WindowDays:    windowDays,
+	}
+
+	loads := map[string]*SessionLoad{}
+	load := func(session string) *SessionLoad {
+		if l, ok := loads[session]; ok {
+			return l
+		}
+		l := &SessionLoad{Session: session}
+		loads[session] = l
+		return l
+	}
+
+	for _, issue := range closed {
+		if issue.ImplementerSession != "" && issue.Points > 0 {
+			load(issue.ImplementerSession).ClosedPoints += issue.Points
+		}
+	}
+
+	for _, issue := range sprintIssues {
+		if issue.Status == models.StatusClosed {
+			r.Done += issue.Points
+			if issue.ImplementerSession != "" {
+				load(issue.ImplementerSession).Done += issue.Points
+			}
+			continue
+		}
+		if issue.Points == 0 {
+			r.Unestimated++
+		}
+		r.Committed += issue.Points
+		if issue.ImplementerSession == "" {
+			r.Unassigned += issue.Points
+		} else {
+			load(issue.ImplementerSession).Committed += issue.Points
+		}
+	}
+
+	history := false
+	for _, l := range loads {
+		l.PointsPerDay = float64(l.ClosedPoints) / float64(windowDays)
+		l.Capacity = l.PointsPerDay * float64(r.RemainingDays)
+		l.NoHistory = l.ClosedPoints == 0
+		if l.Capacity > 0 {
+			l.Load = float64(l.Committed) / l.Capacity
+			l.OverCommitted = l.Load > 1
+		}
+		if !l.NoHistory {
+			history = true
+		}
+		r.Capacity += l.Capacity
+		r.Sessions = append(r.Sessions, *l)
+	}
+	if r.Capacity > 0 {
+		r.Load = float64(r.Committed) / r.Capacity
+	}
+	// Without any history the team's capacity is unknown rather than zero;
+	// a sprint that has ended has no capacity left for open work.
+	r.OverCommitted = (history || r.RemainingDays == 0) && r.Committed > 0 && float64(r.Committed) > r.Capacity
+
+	sort.Slice(r.Sessions, func(i, j int) bool {
+		if r.Sessions[i].Load != r.Sessions[j].Load {
+			return r.Sessions[i].Load > r.Sessions[j].Load
+		}
+		if r.Sessions[i].Committed != r.Sessions[j].Committed {
+			return r.Sessions[i].Committed > r.Sessions[j].Committed
+		}
+		return r.Sessions[i].Session < r.Sessions[j].Session
+	})
+	if r.Sessions == nil {
+		r.Sessions = []SessionLoad{}
+	}
+	return r, nil
+}
+
+// Compute loads the sprint's issues and recent throughput from the
+// database and plans the sprint
+func Compute(database *db.DB, sprint models.Sprint, windowDays int, now time.Time) (*Report, error) {
+	if windowDays <= 0 {
+		windowDays = DefaultWindowDays
+	}
+	sprintIssues, err := database.ListIssues(db.ListIssuesOptions{Sprint: sprint.Name})
+	if err != nil {
+		return nil, err
+	}
+	closed, err := database.ListIssues(db.ListIssuesOptions{
+		Status:      []models.Status{models.StatusClosed},
+		ClosedAfter: truncateDay(now).AddDate(0, 0, -windowDays),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := Plan(sprint, sprintIssues, closed, windowDays, now)
+	if err != nil {
+		return nil, err
+	}
+	for i := range r.Sessions {
+		if sess, err := database.GetSessionByID(r.Sessions[i].Session); err == nil && sess != nil {
+			r.Sessions[i].Name = sess.Name
+		}
+	}
+	return r, nil
+}
+
+// CurrentSprint picks the sprint in progress on today's date, else the
+// next one to start, else the most recent. ok is false with no sprints.
+func CurrentSprint(sprints []models.Sprint, now time.Time) (models.Sprint, bool) {
+	if len(sprints) == 0 {
+		return models.Sprint{}, false
+	}
+	sorted := append([]models.Sprint(nil), sprints...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+	today := now.Format(dateLayout)
+	for _, sp := range sorted {
+		if sp.Start <= today && today <= sp.End {
+			return sp, true
+		}
+	}
+	for _, sp := range sorted {
+		if sp.Start > today {
+			return sp, true
+		}
+	}
+	return sorted[len(sorted)-1], true
+}
+
+func truncateDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// daysInclusive counts the calendar days from a to b, both included; zero
+// when b is before a
+func daysInclusive(a, b time.Time) int {
+	if b.Before(a) {
+		return 0
+	}
+	// Round to absorb DST shifts between the two midnights
+	return int(b.Sub(a).Hours()/24+0.5) + 1
+}
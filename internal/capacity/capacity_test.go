@@ -0,0 +1,111 @@
+package capacity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestPlan(t *testing.T) {
+	sprint := models.Sprint{Name: "s12", Start: "2026-03-02", End: "2026-03-15"}
+	now := time.Date(2026, 3, 9, 14, 0, 0, 0, time.UTC) // 7 days left
+
+	// Over 28 days alice closed 28 points (1/day), bob 8
+	closed := []models.Issue{
+		{Status: models.StatusClosed, Points: 13, ImplementerSession: "ses_alice"},
+		{Status: models.StatusClosed, Points: 13, ImplementerSession: "ses_alice"},
+		{Status: models.StatusClosed, Points: 2, ImplementerSession: "ses_alice"},
+		{Status: models.StatusClosed, Points: 8, ImplementerSession: "ses_bob"},
+		{Status: models.StatusClosed, Points: 5}, // no implementer: not anyone's throughput
+	}
+	sprintIssues := []models.Issue{
+		{Status: models.StatusInProgress, Points: 5, ImplementerSession: "ses_alice"},
+		{Status: models.StatusClosed, Points: 3, ImplementerSession: "ses_alice"},
+		{Status: models.StatusInProgress, Points: 5, ImplementerSession: "ses_bob"},
+		{Status: models.StatusOpen, Points: 3, ImplementerSession: "ses_carol"},
+		{Status: models.StatusOpen, Points: 2},
+		{Status: models.StatusOpen},
+	}
+
+	r, err := Plan(sprint, sprintIssues, closed, 28, now)
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	if r.Days != 14 || r.RemainingDays != 7 {
+		t.Errorf("days = %d, remaining = %d; want 14, 7", r.Days, r.RemainingDays)
+	}
+	if r.Committed != 15 || r.Done != 3 || r.Unassigned != 2 || r.Unestimated != 1 {
+		t.Errorf("report = %+v", r)
+	}
+
+	byID := map[string]SessionLoad{}
+	for _, l := range r.Sessions {
+		byID[l.Session] = l
+	}
+	alice, bob, carol := byID["ses_alice"], byID["ses_bob"], byID["ses_carol"]
+	if alice.Capacity != 7 || alice.Committed != 5 || alice.Done != 3 || alice.OverCommitted {
+		t.Errorf("alice = %+v", alice)
+	}
+	if bob.Capacity != 2 || bob.Load != 2.5 || !bob.OverCommitted {
+		t.Errorf("bob = %+v", bob)
+	}
+	if !carol.NoHistory || carol.OverCommitted || carol.Load != 0 {
+		t.Errorf("carol = %+v", carol)
+	}
+	if r.Sessions[0].Session != "ses_bob" {
+		t.Errorf("most loaded first, got %s", r.Sessions[0].Session)
+	}
+	// 15 open points against 9 points of capacity
+	if r.Capacity != 9 || !r.OverCommitted {
+		t.Errorf("team capacity = %v, over = %v", r.Capacity, r.OverCommitted)
+	}
+}
+
+func TestPlanBeforeAndAfterSprint(t *testing.T) {
+	sprint := models.Sprint{Name: "s1", Start: "2026-03-02", End: "2026-03-06"}
+	open := []models.Issue{{Status: models.StatusOpen, Points: 3, ImplementerSession: "ses_a"}}
+
+	r, _ := Plan(sprint, open, nil, 0, time.Date(2026, 2, 20, 9, 0, 0, 0, time.UTC))
+	if r.RemainingDays != 5 || r.WindowDays != DefaultWindowDays {
+		t.Errorf("before: remaining = %d, window = %d", r.RemainingDays, r.WindowDays)
+	}
+	if r.OverCommitted {
+		t.Error("no history should not flag the team")
+	}
+
+	r, _ = Plan(sprint, open, nil, 0, time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC))
+	if r.RemainingDays != 0 || !r.OverCommitted {
+		t.Errorf("after: remaining = %d, over = %v", r.RemainingDays, r.OverCommitted)
+	}
+
+	if _, err := Plan(models.Sprint{Start: "soon", End: "2026-03-06"}, nil, nil, 0, time.Now()); err == nil {
+		t.Error("expected error for bad start date")
+	}
+}
+
+func TestCurrentSprint(t *testing.T) {
+	sprints := []models.Sprint{
+		{Name: "s2", Start: "2026-03-16", End: "2026-03-29"},
+		{Name: "s1", Start: "2026-03-02", End: "2026-03-15"},
+	}
+	tests := []struct {
+		day  string
+		want string
+	}{
+		{"2026-03-10", "s1"},
+		{"2026-03-15", "s1"},
+		{"2026-02-01", "s1"},
+		{"2026-03-16", "s2"},
+		{"2026-05-01", "s2"},
+	}
+	for _, tt := range tests {
+		now, _ := time.Parse("2006-01-02", tt.day)
+		if got, ok := CurrentSprint(sprints, now); !ok || got.Name != tt.want {
+			t.Errorf("CurrentSprint(%s) = %s, %v; want %s", tt.day, got.Name, ok, tt.want)
+		}
+	}
+	if _, ok := CurrentSprint(nil, time.Now()); ok {
+		t.Error("expected ok=false without sprints")
+	}
+}
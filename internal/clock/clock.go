@@ -0,0 +1,72 @@
+// Package clock is the time source for the timestamps td records. It reads
+// the wall clock unless a test swaps in another source with Set; the
+// pkg/tdtest harness uses a Fake so created_at, updated_at and action log
+// times are the same on every run.
+//
+// Only times that end up in data go through this package. Lock deadlines,
+// request latencies and other durations keep using the time package, since
+// a frozen clock would stall them.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	mu  sync.RWMutex
+	now = time.Now
+)
+
+// Now returns the current time from the active source.
+func Now() time.Time {
+	mu.RLock()
+	defer mu.RUnlock()
+	return now()
+}
+
+// Set replaces the time source until the returned restore func is called.
+func Set(f func() time.Time) (restore func()) {
+	mu.Lock()
+	prev := now
+	now = f
+	mu.Unlock()
+	return func() {
+		mu.Lock()
+		now = prev
+		mu.Unlock()
+	}
+}
+
+// Fake is a clock that only moves when told to. It is safe for concurrent
+// use.
+type Fake struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+// NewFake returns a Fake stopped at t.
+func NewFake(t time.Time) *Fake {
+	return &Fake{t: t}
+}
+
+// Now returns the fake's current time.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.t
+}
+
+// Advance moves the fake forward by d.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	f.t = f.t.Add(d)
+	f.mu.Unlock()
+}
+
+// SetTime moves the fake to t, which may be in the past.
+func (f *Fake) SetTime(t time.Time) {
+	f.mu.Lock()
+	f.t = t
+	f.mu.Unlock()
+}
@@ -0,0 +1,29 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetAndFake(t *testing.T) {
+	start := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+	fake := NewFake(start)
+	restore := Set(fake.Now)
+
+	if got := Now(); !got.Equal(start) {
+		t.Errorf("Now() = %v, want %v", got, start)
+	}
+	fake.Advance(90 * time.Minute)
+	if got := Now(); !got.Equal(start.Add(90 * time.Minute)) {
+		t.Errorf("after Advance, Now() = %v", got)
+	}
+	fake.SetTime(start)
+	if got := Now(); !got.Equal(start) {
+		t.Errorf("after SetTime, Now() = %v", got)
+	}
+
+	restore()
+	if got := Now(); time.Since(got) > time.Minute || got.Equal(start) {
+		t.Errorf("restored Now() = %v, want the wall clock", got)
+	}
+}
@@ -0,0 +1,205 @@
+// Package clone copies issues, optionally with their acceptance
+// checklist, labels, links and child subtree, so recurring work (sprint
+// rituals, release checklists, onboarding epics) keeps its structure
+// instead of being recreated by hand.
+package clone
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+// Options chooses what a clone carries over. Title, description, type,
+// priority, points and sprint are always copied; clones start open.
+type Options struct {
+	Checklist bool // acceptance criteria
+	Labels    bool
+	Links     bool // dependencies and linked files
+	Children  bool // the whole subtree under each issue
+
+	// Sprint puts every clone in this sprint instead of the source's
+	Sprint string
+	// Parent is the parent of top-level clones instead of the source's
+	Parent string
+	// CreatedBranch and CreatedRepo record where the clones were made
+	CreatedBranch string
+	CreatedRepo   string
+}
+
+// Result maps each source issue to its clone
+type Result struct {
+	Clones       []Pair `json:"clones"`
+	Dependencies int    `json:"dependencies"`
+	Files        int    `json:"files"`
+}
+
+// Pair is one cloned issue
+type Pair struct {
+	Source string `json:"source"`
+	Clone  string `json:"clone"`
+	Title  string `json:"title"`
+}
+
+// Clone copies the given issues. When an issue's parent is copied too, the
+// clone goes under the parent's clone, and dependencies between copied
+// issues point at the clones, so a cloned subtree or sprint keeps its
+// shape. Every change is logged, so it syncs and can be undone.
+func Clone(database *db.DB, ids []string, opts Options, sessionID string) (*Result, error) {
+	sources, err := collect(database, ids, opts.Children)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Parent != "" {
+		if _, err := database.GetIssue(opts.Parent); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &Result{Clones: []Pair{}}
+	cloneOf := make(map[string]string, len(sources))
+	for _, src := range sources {
+		c := &models.Issue{
+			Title:          src.Title,
+			Description:    src.Description,
+			Type:           src.Type,
+			Priority:       src.Priority,
+			Points:         src.Points,
+			Sprint:         src.Sprint,
+			ParentID:       src.ParentID,
+			Minor:          src.Minor,
+			CreatorSession: sessionID,
+			CreatedBranch:  opts.CreatedBranch,
+			CreatedRepo:    opts.CreatedRepo,
+		}
+		if opts.Sprint != "" {
+			c.Sprint = opts.Sprint
+		}
+		if parent, ok := cloneOf[src.ParentID]; ok {
+			c.ParentID = parent
+		} else if opts.Parent != "" {
+			c.ParentID = opts.Parent
+		}
+		if opts.Checklist {
+			c.Acceptance = src.Acceptance
+		}
+		if opts.Labels {
+			c.Labels = append([]string(nil), src.Labels...)
+		}
+
+		if err := database.CreateIssueLogged(c, sessionID); err != nil {
+			return result, fmt.Errorf("clone %s: %w", src.ID, err)
+		}
+		if err := database.RecordSessionAction(c.ID, sessionID, models.ActionSessionCreated); err != nil {
+			return result, err
+		}
+		if err := database.AddLog(&models.Log{
+			IssueID:   c.ID,
+			SessionID: sessionID,
+			Message:   "Cloned from " + src.ID,
+			Type:      models.LogTypeProgress,
+		}); err != nil {
+			return result, err
+		}
+		cloneOf[src.ID] = c.ID
+		result.Clones = append(result.Clones, Pair{Source: src.ID, Clone: c.ID, Title: c.Title})
+	}
+
+	if !opts.Links {
+		return result, nil
+	}
+	for _, src := range sources {
+		deps, err := database.GetDependencies(src.ID)
+		if err != nil {
+			return result, err
+		}
+		for _, on := range deps {
+			if mapped, ok := cloneOf[on]; ok {
+				on = mapped
+			}
+			if err := database.AddDependencyLogged(cloneOf[src.ID], on, "depends_on", sessionID); err != nil {
+				return result, err
+			}
+			result.Dependencies++
+		}
+
+		files, err := database.GetLinkedFiles(src.ID)
+		if err != nil {
+			return result, err
+		}
+		for _, f := range files {
+			if err := database.LinkFileLogged(cloneOf[src.ID], f.FilePath, f.Role, f.LinkedSHA, sessionID); err != nil {
+				return result, err
+			}
+			result.Files++
+		}
+	}
+	return result, nil
+}
+
+// SelectQuery combines a TDQ query and a sprint into the query bulk clones
+// pick their issues with. Either may be empty.
+func SelectQuery(queryStr, sprint string) string {
+	if sprint == "" {
+		return queryStr
+	}
+	cond := "sprint = " + strconv.Quote(sprint)
+	if queryStr == "" {
+		return cond
+	}
+	return "(" + queryStr + ") AND " + cond
+}
+
+// collect loads the issues to clone, with their subtrees when children is
+// set, parents before children and each issue once
+func collect(database *db.DB, ids []string, children bool) ([]*models.Issue, error) {
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("no issues to clone")
+	}
+	var all []*models.Issue
+	seen := make(map[string]bool)
+	add := func(issue *models.Issue) {
+		if !seen[issue.ID] {
+			seen[issue.ID] = true
+			all = append(all, issue)
+		}
+	}
+	for _, id := range ids {
+		issue, err := database.GetIssue(id)
+		if err != nil {
+			return nil, err
+		}
+		add(issue)
+		if !children {
+			continue
+		}
+		descendants, err := database.GetDescendantIssues(issue.ID, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range descendants {
+			add(d)
+		}
+	}
+
+	// Emit an issue once its parent, if it is being cloned, has been
+	ordered := make([]*models.Issue, 0, len(all))
+	done := make(map[string]bool, len(all))
+	for len(ordered) < len(all) {
+		progressed := false
+		for _, issue := range all {
+			if done[issue.ID] || (seen[issue.ParentID] && !done[issue.ParentID]) {
+				continue
+			}
+			done[issue.ID] = true
+			ordered = append(ordered, issue)
+			progressed = true
+		}
+		if !progressed {
+			return nil, fmt.Errorf("parent cycle among issues to clone")
+		}
+	}
+	return ordered, nil
+}
@@ -0,0 +1,82 @@
+package clone
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+func TestClone(t *testing.T) {
+	database, err := db.Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	defer database.Close()
+
+	mk := func(issue *models.Issue) *models.Issue {
+		if err := database.CreateIssue(issue); err != nil {
+			t.Fatalf("CreateIssue: %v", err)
+		}
+		return issue
+	}
+	other := mk(&models.Issue{Title: "Staging environment refresh"})
+	epic := mk(&models.Issue{Title: "Sprint review ritual", Type: models.TypeEpic, Sprint: "24", Labels: []string{"ritual"}, Acceptance: "- [ ] demo recorded"})
+	prep := mk(&models.Issue{Title: "Prepare sprint demo", ParentID: epic.ID, Sprint: "24", Status: models.StatusClosed})
+	notes := mk(&models.Issue{Title: "Publish sprint notes", ParentID: epic.ID, Sprint: "24"})
+	for _, dep := range [][2]string{{notes.ID, prep.ID}, {prep.ID, other.ID}} {
+		if err := database.AddDependency(dep[0], dep[1], "depends_on"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := database.LinkFile(prep.ID, "docs/demo.md", models.FileRoleReference, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without options only the issue itself is copied
+	res, err := Clone(database, []string{epic.ID}, Options{}, "ses_test")
+	if err != nil {
+		t.Fatalf("Clone: %v", err)
+	}
+	plain, _ := database.GetIssue(res.Clones[0].Clone)
+	if len(res.Clones) != 1 || plain.Title != epic.Title || plain.Sprint != "24" || len(plain.Labels) != 0 || plain.Acceptance != "" {
+		t.Errorf("plain clone = %+v (%+v)", plain, res)
+	}
+
+	res, err = Clone(database, []string{epic.ID}, Options{Checklist: true, Labels: true, Links: true, Children: true, Sprint: "25"}, "ses_test")
+	if err != nil {
+		t.Fatalf("Clone: %v", err)
+	}
+	if len(res.Clones) != 3 || res.Clones[0].Source != epic.ID || res.Dependencies != 2 || res.Files != 1 {
+		t.Fatalf("subtree clone = %+v", res)
+	}
+	cloneOf := map[string]string{}
+	for _, p := range res.Clones {
+		cloneOf[p.Source] = p.Clone
+	}
+
+	newEpic, _ := database.GetIssue(cloneOf[epic.ID])
+	if newEpic.Sprint != "25" || !reflect.DeepEqual(newEpic.Labels, epic.Labels) || newEpic.Acceptance != epic.Acceptance {
+		t.Errorf("cloned epic = %+v", newEpic)
+	}
+	newPrep, _ := database.GetIssue(cloneOf[prep.ID])
+	if newPrep.ParentID != newEpic.ID || newPrep.Status != models.StatusOpen {
+		t.Errorf("cloned child = %+v, want open under %s", newPrep, newEpic.ID)
+	}
+
+	// Dependencies inside the subtree point at the clones; outside ones are kept
+	if deps, _ := database.GetDependencies(cloneOf[notes.ID]); !reflect.DeepEqual(deps, []string{newPrep.ID}) {
+		t.Errorf("notes clone depends on %v, want %s", deps, newPrep.ID)
+	}
+	if deps, _ := database.GetDependencies(newPrep.ID); !reflect.DeepEqual(deps, []string{other.ID}) {
+		t.Errorf("prep clone depends on %v, want %s", deps, other.ID)
+	}
+	if files, _ := database.GetLinkedFiles(newPrep.ID); len(files) != 1 {
+		t.Errorf("prep clone files = %+v", files)
+	}
+
+	if _, err := Clone(database, []string{"td-missing"}, Options{}, "ses_test"); err == nil {
+		t.Error("cloning a missing issue succeeded, want error")
+	}
+}
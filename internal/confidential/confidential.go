@@ -0,0 +1,202 @@
+// Package confidential encrypts the text of confidential issues and decides
+// who may read it.
+//
+// A confidential issue's description, acceptance criteria and comments are
+// stored sealed with AES-256-GCM under a project key kept in
+// .todos/confidential.key, outside the database. The database, its action
+// log, sync and webhooks only ever carry the sealed text. Sessions, session
+// names and API token scopes granted access to an issue see it decrypted;
+// everyone else sees Redacted.
+package confidential
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/marcus/td/internal/crypto"
+	"github.com/marcus/td/internal/models"
+)
+
+// Prefix marks sealed text
+const Prefix = "tdenc:v1:"
+
+// Redacted replaces confidential text for those without access
+const Redacted = "[confidential]"
+
+// KeyEnv holds a base64 project key, taking precedence over the key file
+const KeyEnv = "TD_CONFIDENTIAL_KEY"
+
+// RolePrefix marks a grant to every API token with a scope, e.g. role:admin
+const RolePrefix = "role:"
+
+// ErrNoKey is returned when sealing or opening without a project key
+var ErrNoKey = errors.New("no confidential key (td confidential key init, or import the project's key)")
+
+// KeyPath returns where the project key is kept
+func KeyPath(baseDir string) string {
+	return filepath.Join(baseDir, ".todos", "confidential.key")
+}
+
+// LoadKey returns the project key from TD_CONFIDENTIAL_KEY or the key file
+func LoadKey(baseDir string) ([]byte, error) {
+	encoded := os.Getenv(KeyEnv)
+	if encoded == "" {
+		data, err := os.ReadFile(KeyPath(baseDir))
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNoKey
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read confidential key: %w", err)
+		}
+		encoded = string(data)
+	}
+	return DecodeKey(encoded)
+}
+
+// DecodeKey parses a base64 key as printed by EncodeKey
+func DecodeKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("invalid confidential key: want 32 bytes of base64")
+	}
+	return key, nil
+}
+
+// EncodeKey formats a key for export
+func EncodeKey(key []byte) string {
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+// EnsureKey returns the project key, generating and saving one when there
+// is none. created reports whether it did.
+func EnsureKey(baseDir string) (key []byte, created bool, err error) {
+	key, err = LoadKey(baseDir)
+	if !errors.Is(err, ErrNoKey) {
+		return key, false, err
+	}
+	if key, err = crypto.GenerateDEK(); err != nil {
+		return nil, false, err
+	}
+	if err := SaveKey(baseDir, key, false); err != nil {
+		return nil, false, err
+	}
+	return key, true, nil
+}
+
+// SaveKey writes the key file, readable by the owner only. It refuses to
+// replace a different key unless force is set, since text sealed under the
+// old key could no longer be read.
+func SaveKey(baseDir string, key []byte, force bool) error {
+	path := KeyPath(baseDir)
+	if data, err := os.ReadFile(path); err == nil && !force {
+		if old, err := DecodeKey(string(data)); err == nil && string(old) != string(key) {
+			return fmt.Errorf("a different confidential key already exists at %s", path)
+		}
+	}
+	if err := os.WriteFile(path, []byte(EncodeKey(key)+"\n"), 0600); err != nil {
+		return fmt.Errorf("write confidential key: %w", err)
+	}
+	return nil
+}
+
+// IsSealed reports whether s is sealed text
+func IsSealed(s string) bool {
+	return strings.HasPrefix(s, Prefix)
+}
+
+// Seal encrypts s. Empty and already sealed text is returned as is.
+func Seal(key []byte, s string) (string, error) {
+	if s == "" || IsSealed(s) {
+		return s, nil
+	}
+	if key == nil {
+		return "", ErrNoKey
+	}
+	sealed, err := crypto.Encrypt(key, []byte(s))
+	if err != nil {
+		return "", err
+	}
+	return Prefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts sealed text. Text that isn't sealed is returned as is.
+func Open(key []byte, s string) (string, error) {
+	if !IsSealed(s) {
+		return s, nil
+	}
+	if key == nil {
+		return "", ErrNoKey
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(s, Prefix))
+	if err != nil {
+		return "", fmt.Errorf("confidential text is corrupt: %w", err)
+	}
+	plain, err := crypto.Decrypt(key, data)
+	if err != nil {
+		return "", fmt.Errorf("confidential text can't be decrypted with this key")
+	}
+	return string(plain), nil
+}
+
+// Redact replaces sealed text with Redacted
+func Redact(s string) string {
+	if IsSealed(s) {
+		return Redacted
+	}
+	return s
+}
+
+// Viewer is who is reading: a session, its name, and for API requests the
+// scopes of the token used
+type Viewer struct {
+	SessionID   string
+	SessionName string
+	Roles       []string
+}
+
+// CanRead reports whether v may read issue given its grants. Anyone may
+// read an issue that isn't confidential; the session that created one may
+// always read it.
+func CanRead(issue *models.Issue, grants []models.ConfidentialGrant, v Viewer) bool {
+	if !issue.Confidential {
+		return true
+	}
+	if v.SessionID != "" && v.SessionID == issue.CreatorSession {
+		return true
+	}
+	for _, g := range grants {
+		switch {
+		case g.Grantee == v.SessionID && v.SessionID != "":
+			return true
+		case g.Grantee == v.SessionName && v.SessionName != "":
+			return true
+		case strings.HasPrefix(g.Grantee, RolePrefix):
+			for _, r := range v.Roles {
+				if g.Grantee == RolePrefix+r {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// ValidGrantee checks a grantee: a session ID, a session name, or
+// role:read, role:write or role:admin
+func ValidGrantee(grantee string) error {
+	if strings.TrimSpace(grantee) == "" {
+		return fmt.Errorf("grantee is required")
+	}
+	if role, ok := strings.CutPrefix(grantee, RolePrefix); ok {
+		switch role {
+		case models.TokenScopeRead, models.TokenScopeWrite, models.TokenScopeAdmin:
+			return nil
+		}
+		return fmt.Errorf("unknown role %q (read, write or admin)", role)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package confidential
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestSealOpen(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, ".todos"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(KeyEnv, "")
+
+	if _, err := LoadKey(dir); err != ErrNoKey {
+		t.Fatalf("LoadKey without a key = %v, want ErrNoKey", err)
+	}
+	key, created, err := EnsureKey(dir)
+	if err != nil || !created {
+		t.Fatalf("EnsureKey = %v, %v", created, err)
+	}
+	if again, created, err := EnsureKey(dir); err != nil || created || string(again) != string(key) {
+		t.Fatalf("second EnsureKey = %v, %v", created, err)
+	}
+
+	sealed, err := Seal(key, "the secret plan")
+	if err != nil || !IsSealed(sealed) {
+		t.Fatalf("Seal = %q, %v", sealed, err)
+	}
+	if again, _ := Seal(key, sealed); again != sealed {
+		t.Error("sealing sealed text changed it")
+	}
+	if plain, err := Open(key, sealed); err != nil || plain != "the secret plan" {
+		t.Fatalf("Open = %q, %v", plain, err)
+	}
+	if plain, _ := Open(key, "not sealed"); plain != "not sealed" {
+		t.Errorf("Open of plain text = %q", plain)
+	}
+	if Redact(sealed) != Redacted || Redact("plain") != "plain" {
+		t.Error("Redact")
+	}
+
+	other, _ := DecodeKey(EncodeKey(make([]byte, 32)))
+	if _, err := Open(other, sealed); err == nil {
+		t.Error("opened with the wrong key")
+	}
+	if err := SaveKey(dir, other, false); err == nil {
+		t.Error("SaveKey replaced a different key without force")
+	}
+
+	t.Setenv(KeyEnv, EncodeKey(other))
+	if got, _ := LoadKey(dir); string(got) != string(other) {
+		t.Error("TD_CONFIDENTIAL_KEY did not take precedence")
+	}
+}
+
+func TestCanRead(t *testing.T) {
+	issue := &models.Issue{ID: "td-1", Confidential: true, CreatorSession: "ses_owner"}
+	grants := []models.ConfidentialGrant{
+		{IssueID: "td-1", Grantee: "ses_pal"},
+		{IssueID: "td-1", Grantee: "alice"},
+		{IssueID: "td-1", Grantee: "role:admin"},
+	}
+	tests := []struct {
+		name string
+		v    Viewer
+		want bool
+	}{
+		{"creator", Viewer{SessionID: "ses_owner"}, true},
+		{"granted session", Viewer{SessionID: "ses_pal"}, true},
+		{"granted name", Viewer{SessionID: "ses_x", SessionName: "alice"}, true},
+		{"granted role", Viewer{SessionID: "ses_x", Roles: []string{"read", "admin"}}, true},
+		{"other role", Viewer{SessionID: "ses_x", Roles: []string{"read", "write"}}, false},
+		{"stranger", Viewer{SessionID: "ses_x"}, false},
+		{"nobody", Viewer{}, false},
+	}
+	for _, tt := range tests {
+		if got := CanRead(issue, grants, tt.v); got != tt.want {
+			t.Errorf("%s: CanRead = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if !CanRead(&models.Issue{ID: "td-2"}, nil, Viewer{}) {
+		t.Error("an issue that isn't confidential should be readable")
+	}
+}
+
+func TestValidGrantee(t *testing.T) {
+	for _, g := range []string{"ses_abc", "alice", "role:read", "role:write", "role:admin"} {
+		if err := ValidGrantee(g); err != nil {
+			t.Errorf("ValidGrantee(%q) = %v", g, err)
+		}
+	}
+	for _, g := range []string{"", " ", "role:owner"} {
+		if err := ValidGrantee(g); err == nil {
+			t.Errorf("ValidGrantee(%q) accepted", g)
+		}
+	}
+}
@@ -3,12 +3,20 @@
 package config
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/score"
+	"github.com/marcus/td/internal/share"
 )
 
 const configFile = ".todos/config.json"
@@ -20,6 +28,9 @@ const (
 	DefaultTitleMaxLength = 100
 )
 
+// DefaultMaxHierarchyDepth limits how deeply issues nest under parents
+const DefaultMaxHierarchyDepth = 10
+
 // Load reads the config from disk
 func Load(baseDir string) (*models.Config, error) {
 	configPath := filepath.Join(baseDir, configFile)
@@ -192,6 +203,42 @@ func SetPaneHeights(baseDir string, heights [3]float64) error {
 	})
 }
 
+// DefaultPreviewRatio is the default width ratio of the monitor preview pane
+const DefaultPreviewRatio = 0.45
+
+// Preview pane width bounds
+const (
+	MinPreviewRatio = 0.2
+	MaxPreviewRatio = 0.8
+)
+
+// GetPreviewLayout returns whether the preview pane is open and its width
+// ratio, falling back to DefaultPreviewRatio if unset or out of range
+func GetPreviewLayout(baseDir string) (open bool, ratio float64, err error) {
+	cfg, err := Load(baseDir)
+	if err != nil {
+		return false, DefaultPreviewRatio, err
+	}
+	ratio = cfg.PreviewRatio
+	if ratio < MinPreviewRatio || ratio > MaxPreviewRatio {
+		ratio = DefaultPreviewRatio
+	}
+	return cfg.PreviewOpen, ratio, nil
+}
+
+// SetPreviewLayout saves the preview pane visibility and width ratio to config
+func SetPreviewLayout(baseDir string, open bool, ratio float64) error {
+	return withConfigLock(baseDir, func() error {
+		cfg, err := Load(baseDir)
+		if err != nil {
+			return err
+		}
+		cfg.PreviewOpen = open
+		cfg.PreviewRatio = ratio
+		return Save(baseDir, cfg)
+	})
+}
+
 // FilterState holds the current filter/search state for the monitor
 type FilterState struct {
 	SearchQuery   string
@@ -229,6 +276,223 @@ func SetFilterState(baseDir string, state *FilterState) error {
 	})
 }
 
+// SectionFilterScopeAll is the scope key used for section filters when no
+// board is active (the default "All Issues" view).
+const SectionFilterScopeAll = "all"
+
+// GetSectionFilters returns the saved per-section TDQ filters for a board
+// scope, keyed by category. Returns an empty map if none are set.
+func GetSectionFilters(baseDir, scope string) (map[string]string, error) {
+	cfg, err := Load(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	filters := make(map[string]string)
+	for category, q := range cfg.SectionFilters[scope] {
+		filters[category] = q
+	}
+	return filters, nil
+}
+
+// SetSectionFilter saves the TDQ filter for one section of a board scope.
+// An empty query removes the filter.
+func SetSectionFilter(baseDir, scope, category, query string) error {
+	return withConfigLock(baseDir, func() error {
+		cfg, err := Load(baseDir)
+		if err != nil {
+			return err
+		}
+		if query == "" {
+			delete(cfg.SectionFilters[scope], category)
+			if len(cfg.SectionFilters[scope]) == 0 {
+				delete(cfg.SectionFilters, scope)
+			}
+		} else {
+			if cfg.SectionFilters == nil {
+				cfg.SectionFilters = make(map[string]map[string]string)
+			}
+			if cfg.SectionFilters[scope] == nil {
+				cfg.SectionFilters[scope] = make(map[string]string)
+			}
+			cfg.SectionFilters[scope][category] = query
+		}
+		return Save(baseDir, cfg)
+	})
+}
+
+// GetSprints returns the configured sprints ordered by start date.
+func GetSprints(baseDir string) ([]models.Sprint, error) {
+	cfg, err := Load(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	sprints := append([]models.Sprint(nil), cfg.Sprints...)
+	sort.Slice(sprints, func(i, j int) bool { return sprints[i].Start < sprints[j].Start })
+	return sprints, nil
+}
+
+// SetSprint adds or replaces the date range for a sprint.
+func SetSprint(baseDir string, sprint models.Sprint) error {
+	return withConfigLock(baseDir, func() error {
+		cfg, err := Load(baseDir)
+		if err != nil {
+			return err
+		}
+		for i := range cfg.Sprints {
+			if cfg.Sprints[i].Name == sprint.Name {
+				cfg.Sprints[i] = sprint
+				return Save(baseDir, cfg)
+			}
+		}
+		cfg.Sprints = append(cfg.Sprints, sprint)
+		return Save(baseDir, cfg)
+	})
+}
+
+// RemoveSprint deletes a sprint's date range. Returns false if it was not set.
+func RemoveSprint(baseDir, name string) (bool, error) {
+	removed := false
+	err := withConfigLock(baseDir, func() error {
+		cfg, err := Load(baseDir)
+		if err != nil {
+			return err
+		}
+		for i := range cfg.Sprints {
+			if cfg.Sprints[i].Name == name {
+				cfg.Sprints = append(cfg.Sprints[:i], cfg.Sprints[i+1:]...)
+				removed = true
+				return Save(baseDir, cfg)
+			}
+		}
+		return nil
+	})
+	return removed, err
+}
+
+// GetSubscriptions returns the event subscriptions, oldest first.
+func GetSubscriptions(baseDir string) ([]models.Subscription, error) {
+	cfg, err := Load(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Subscriptions, nil
+}
+
+// GetSubscription returns a subscription by ID, or nil if there is none.
+func GetSubscription(baseDir, id string) (*models.Subscription, error) {
+	subs, err := GetSubscriptions(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	for i := range subs {
+		if subs[i].ID == id {
+			return &subs[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// AddSubscription stores a new subscription, filling in its ID and
+// CreatedAt.
+func AddSubscription(baseDir string, sub *models.Subscription) error {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return err
+	}
+	sub.ID = "sub-" + hex.EncodeToString(b)
+	sub.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	return withConfigLock(baseDir, func() error {
+		cfg, err := Load(baseDir)
+		if err != nil {
+			return err
+		}
+		cfg.Subscriptions = append(cfg.Subscriptions, *sub)
+		return Save(baseDir, cfg)
+	})
+}
+
+// RemoveSubscription deletes a subscription. Returns false if there was
+// none with the ID.
+func RemoveSubscription(baseDir, id string) (bool, error) {
+	removed := false
+	err := withConfigLock(baseDir, func() error {
+		cfg, err := Load(baseDir)
+		if err != nil {
+			return err
+		}
+		for i := range cfg.Subscriptions {
+			if cfg.Subscriptions[i].ID == id {
+				cfg.Subscriptions = append(cfg.Subscriptions[:i], cfg.Subscriptions[i+1:]...)
+				removed = true
+				return Save(baseDir, cfg)
+			}
+		}
+		return nil
+	})
+	return removed, err
+}
+
+// GetIntegrations returns the configured chat integrations.
+func GetIntegrations(baseDir string) ([]models.IntegrationConfig, error) {
+	cfg, err := Load(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Integrations, nil
+}
+
+// GetIntegration returns the named integration, or nil if it is not configured.
+func GetIntegration(baseDir, name string) (*models.IntegrationConfig, error) {
+	integrations, err := GetIntegrations(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	for i := range integrations {
+		if integrations[i].Name == name {
+			return &integrations[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// SetIntegration adds or replaces an integration by name.
+func SetIntegration(baseDir string, ic models.IntegrationConfig) error {
+	return withConfigLock(baseDir, func() error {
+		cfg, err := Load(baseDir)
+		if err != nil {
+			return err
+		}
+		for i := range cfg.Integrations {
+			if cfg.Integrations[i].Name == ic.Name {
+				cfg.Integrations[i] = ic
+				return Save(baseDir, cfg)
+			}
+		}
+		cfg.Integrations = append(cfg.Integrations, ic)
+		return Save(baseDir, cfg)
+	})
+}
+
+// RemoveIntegration deletes an integration. Returns false if it was not set.
+func RemoveIntegration(baseDir, name string) (bool, error) {
+	removed := false
+	err := withConfigLock(baseDir, func() error {
+		cfg, err := Load(baseDir)
+		if err != nil {
+			return err
+		}
+		for i := range cfg.Integrations {
+			if cfg.Integrations[i].Name == name {
+				cfg.Integrations = append(cfg.Integrations[:i], cfg.Integrations[i+1:]...)
+				removed = true
+				return Save(baseDir, cfg)
+			}
+		}
+		return nil
+	})
+	return removed, err
+}
+
 // GetTitleLengthLimits returns min/max title length limits from config (with defaults)
 func GetTitleLengthLimits(baseDir string) (min, max int, err error) {
 	cfg, err := Load(baseDir)
@@ -249,6 +513,19 @@ func GetTitleLengthLimits(baseDir string) (min, max int, err error) {
 	return min, max, nil
 }
 
+// GetMaxHierarchyDepth returns the deepest allowed parent chain from config
+// (with default)
+func GetMaxHierarchyDepth(baseDir string) (int, error) {
+	cfg, err := Load(baseDir)
+	if err != nil {
+		return DefaultMaxHierarchyDepth, err
+	}
+	if cfg.MaxHierarchyDepth <= 0 {
+		return DefaultMaxHierarchyDepth, nil
+	}
+	return cfg.MaxHierarchyDepth, nil
+}
+
 // GetFeatureFlag returns a feature flag from local config.
 // The second return value indicates whether the flag is explicitly set.
 func GetFeatureFlag(baseDir, name string) (bool, bool, error) {
@@ -291,3 +568,499 @@ func UnsetFeatureFlag(baseDir, name string) error {
 	}
 	return Save(baseDir, cfg)
 }
+
+// GetScoreFormula returns the project's issue scoring formula, or
+// score.Default when none is configured
+func GetScoreFormula(baseDir string) (*score.Formula, error) {
+	cfg, err := Load(baseDir)
+	if err != nil {
+		return score.Default, err
+	}
+	if cfg.ScoreFormula == "" {
+		return score.Default, nil
+	}
+	f, err := score.Parse(cfg.ScoreFormula)
+	if err != nil {
+		return score.Default, err
+	}
+	return f, nil
+}
+
+// GetProjectName returns the project's display name, falling back to the
+// name of the project directory
+func GetProjectName(baseDir string) string {
+	if cfg, err := Load(baseDir); err == nil && cfg.ProjectName != "" {
+		return cfg.ProjectName
+	}
+	return filepath.Base(baseDir)
+}
+
+// SetProjectName persists the project's display name
+func SetProjectName(baseDir, name string) error {
+	return withConfigLock(baseDir, func() error {
+		cfg, err := Load(baseDir)
+		if err != nil {
+			return err
+		}
+		cfg.ProjectName = strings.TrimSpace(name)
+		return Save(baseDir, cfg)
+	})
+}
+
+// GetShareSecret returns the key that signs issue share links, generating
+// and saving one on first use.
+func GetShareSecret(baseDir string) (string, error) {
+	var secret string
+	err := withConfigLock(baseDir, func() error {
+		cfg, err := Load(baseDir)
+		if err != nil {
+			return err
+		}
+		if cfg.ShareSecret == "" {
+			if cfg.ShareSecret, err = share.NewSecret(); err != nil {
+				return err
+			}
+			if err := Save(baseDir, cfg); err != nil {
+				return err
+			}
+		}
+		secret = cfg.ShareSecret
+		return nil
+	})
+	return secret, err
+}
+
+// SetScoreFormula validates and persists the scoring formula. An empty
+// formula restores the default.
+func SetScoreFormula(baseDir, formula string) error {
+	if formula != "" {
+		f, err := score.Parse(formula)
+		if err != nil {
+			return err
+		}
+		formula = f.String()
+	}
+	return withConfigLock(baseDir, func() error {
+		cfg, err := Load(baseDir)
+		if err != nil {
+			return err
+		}
+		cfg.ScoreFormula = formula
+		return Save(baseDir, cfg)
+	})
+}
+
+// GetLinkedProjects returns the other td projects this one references,
+// keyed by reference name
+func GetLinkedProjects(baseDir string) (map[string]string, error) {
+	cfg, err := Load(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.LinkedProjects, nil
+}
+
+// SetLinkedProject adds or replaces the path of a linked project
+func SetLinkedProject(baseDir, name, path string) error {
+	return withConfigLock(baseDir, func() error {
+		cfg, err := Load(baseDir)
+		if err != nil {
+			return err
+		}
+		if cfg.LinkedProjects == nil {
+			cfg.LinkedProjects = make(map[string]string)
+		}
+		cfg.LinkedProjects[name] = path
+		return Save(baseDir, cfg)
+	})
+}
+
+// RemoveLinkedProject forgets a linked project. Returns false if it was not set.
+func RemoveLinkedProject(baseDir, name string) (bool, error) {
+	removed := false
+	err := withConfigLock(baseDir, func() error {
+		cfg, err := Load(baseDir)
+		if err != nil {
+			return err
+		}
+		if _, ok := cfg.LinkedProjects[name]; !ok {
+			return nil
+		}
+		delete(cfg.LinkedProjects, name)
+		removed = true
+		return Save(baseDir, cfg)
+	})
+	return removed, err
+}
+
+// GetTemplates returns the project's output templates, keyed by name
+func GetTemplates(baseDir string) (map[string]string, error) {
+	cfg, err := Load(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Templates, nil
+}
+
+// SetTemplate adds or replaces an output template
+func SetTemplate(baseDir, name, text string) error {
+	return withConfigLock(baseDir, func() error {
+		cfg, err := Load(baseDir)
+		if err != nil {
+			return err
+		}
+		if cfg.Templates == nil {
+			cfg.Templates = make(map[string]string)
+		}
+		cfg.Templates[name] = text
+		return Save(baseDir, cfg)
+	})
+}
+
+// RemoveTemplate deletes an output template. Returns false if it was not set.
+func RemoveTemplate(baseDir, name string) (bool, error) {
+	removed := false
+	err := withConfigLock(baseDir, func() error {
+		cfg, err := Load(baseDir)
+		if err != nil {
+			return err
+		}
+		if _, ok := cfg.Templates[name]; !ok {
+			return nil
+		}
+		delete(cfg.Templates, name)
+		removed = true
+		return Save(baseDir, cfg)
+	})
+	return removed, err
+}
+
+// GetThrashConfig returns the anti-thrash guard settings, nil when unset
+func GetThrashConfig(baseDir string) (*models.ThrashConfig, error) {
+	cfg, err := Load(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Thrash, nil
+}
+
+// SetThrashConfig replaces the anti-thrash guard settings
+func SetThrashConfig(baseDir string, tc models.ThrashConfig) error {
+	return withConfigLock(baseDir, func() error {
+		cfg, err := Load(baseDir)
+		if err != nil {
+			return err
+		}
+		cfg.Thrash = &tc
+		return Save(baseDir, cfg)
+	})
+}
+
+// GetRetentionConfig returns the retention policy, nil when unset
+func GetRetentionConfig(baseDir string) (*models.RetentionConfig, error) {
+	cfg, err := Load(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Retention, nil
+}
+
+// SetRetentionConfig replaces the retention policy. A policy keeping
+// everything removes it.
+func SetRetentionConfig(baseDir string, rc models.RetentionConfig) error {
+	return withConfigLock(baseDir, func() error {
+		cfg, err := Load(baseDir)
+		if err != nil {
+			return err
+		}
+		if rc == (models.RetentionConfig{}) {
+			cfg.Retention = nil
+		} else {
+			cfg.Retention = &rc
+		}
+		return Save(baseDir, cfg)
+	})
+}
+
+// GetLogCompactionConfig returns the log compaction settings, nil when unset
+func GetLogCompactionConfig(baseDir string) (*models.LogCompactionConfig, error) {
+	cfg, err := Load(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.LogCompaction, nil
+}
+
+// SetLogCompactionConfig replaces the log compaction settings
+func SetLogCompactionConfig(baseDir string, lc models.LogCompactionConfig) error {
+	return withConfigLock(baseDir, func() error {
+		cfg, err := Load(baseDir)
+		if err != nil {
+			return err
+		}
+		cfg.LogCompaction = &lc
+		return Save(baseDir, cfg)
+	})
+}
+
+// GetClosedImmutable reports whether closed issues refuse edits and comments
+func GetClosedImmutable(baseDir string) (bool, error) {
+	cfg, err := Load(baseDir)
+	if err != nil {
+		return false, err
+	}
+	return cfg.ClosedImmutable, nil
+}
+
+// SetClosedImmutable turns closed-issue immutability on or off
+func SetClosedImmutable(baseDir string, immutable bool) error {
+	return withConfigLock(baseDir, func() error {
+		cfg, err := Load(baseDir)
+		if err != nil {
+			return err
+		}
+		cfg.ClosedImmutable = immutable
+		return Save(baseDir, cfg)
+	})
+}
+
+// GetPriorityInheritance reports whether blockers inherit the priority of
+// the issues waiting on them
+func GetPriorityInheritance(baseDir string) (bool, error) {
+	cfg, err := Load(baseDir)
+	if err != nil {
+		return false, err
+	}
+	return cfg.PriorityInheritance, nil
+}
+
+// SetPriorityInheritance turns priority inheritance on or off
+func SetPriorityInheritance(baseDir string, on bool) error {
+	return withConfigLock(baseDir, func() error {
+		cfg, err := Load(baseDir)
+		if err != nil {
+			return err
+		}
+		cfg.PriorityInheritance = on
+		return Save(baseDir, cfg)
+	})
+}
+
+// GetReadyGate returns the criteria of the project's definition of ready
+func GetReadyGate(baseDir string) ([]string, error) {
+	cfg, err := Load(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.ReadyGate, nil
+}
+
+// SetReadyGate replaces the definition of ready. No criteria turns the
+// gate off.
+func SetReadyGate(baseDir string, criteria []string) error {
+	return withConfigLock(baseDir, func() error {
+		cfg, err := Load(baseDir)
+		if err != nil {
+			return err
+		}
+		cfg.ReadyGate = criteria
+		return Save(baseDir, cfg)
+	})
+}
+
+// GetInboxSources returns where newly created issues land in the triage
+// inbox
+func GetInboxSources(baseDir string) ([]string, error) {
+	cfg, err := Load(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.InboxSources, nil
+}
+
+// SetInboxSources replaces the sources routed to the triage inbox. No
+// sources turns routing off.
+func SetInboxSources(baseDir string, sources []string) error {
+	return withConfigLock(baseDir, func() error {
+		cfg, err := Load(baseDir)
+		if err != nil {
+			return err
+		}
+		cfg.InboxSources = sources
+		return Save(baseDir, cfg)
+	})
+}
+
+// GetTimezone returns the project's timezone, or time.Local when none is
+// configured or the configured name is unknown
+func GetTimezone(baseDir string) (*time.Location, error) {
+	cfg, err := Load(baseDir)
+	if err != nil {
+		return time.Local, err
+	}
+	if cfg.Timezone == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return time.Local, fmt.Errorf("invalid timezone %q", cfg.Timezone)
+	}
+	return loc, nil
+}
+
+// SetTimezone sets the project's timezone; "" goes back to the machine's
+func SetTimezone(baseDir, name string) error {
+	if name != "" {
+		if _, err := time.LoadLocation(name); err != nil {
+			return fmt.Errorf("invalid timezone %q: use an IANA name like America/New_York", name)
+		}
+	}
+	return withConfigLock(baseDir, func() error {
+		cfg, err := Load(baseDir)
+		if err != nil {
+			return err
+		}
+		cfg.Timezone = name
+		return Save(baseDir, cfg)
+	})
+}
+
+// GetScriptHooksConfig returns the hook script settings, nil when unset
+func GetScriptHooksConfig(baseDir string) (*models.ScriptHooksConfig, error) {
+	cfg, err := Load(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.ScriptHooks, nil
+}
+
+// SetScriptHooksConfig replaces the hook script settings
+func SetScriptHooksConfig(baseDir string, sc models.ScriptHooksConfig) error {
+	return withConfigLock(baseDir, func() error {
+		cfg, err := Load(baseDir)
+		if err != nil {
+			return err
+		}
+		cfg.ScriptHooks = &sc
+		return Save(baseDir, cfg)
+	})
+}
+
+// GetPolicyHooks returns the configured pre-transition policy hooks
+func GetPolicyHooks(baseDir string) ([]models.PolicyHookConfig, error) {
+	cfg, err := Load(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.PolicyHooks, nil
+}
+
+// SetPolicyHook adds or replaces a policy hook by name
+func SetPolicyHook(baseDir string, hook models.PolicyHookConfig) error {
+	return withConfigLock(baseDir, func() error {
+		cfg, err := Load(baseDir)
+		if err != nil {
+			return err
+		}
+		for i := range cfg.PolicyHooks {
+			if cfg.PolicyHooks[i].Name == hook.Name {
+				cfg.PolicyHooks[i] = hook
+				return Save(baseDir, cfg)
+			}
+		}
+		cfg.PolicyHooks = append(cfg.PolicyHooks, hook)
+		return Save(baseDir, cfg)
+	})
+}
+
+// RemovePolicyHook deletes a policy hook. Returns false if it was not set.
+func RemovePolicyHook(baseDir, name string) (bool, error) {
+	removed := false
+	err := withConfigLock(baseDir, func() error {
+		cfg, err := Load(baseDir)
+		if err != nil {
+			return err
+		}
+		for i := range cfg.PolicyHooks {
+			if cfg.PolicyHooks[i].Name == name {
+				cfg.PolicyHooks = append(cfg.PolicyHooks[:i], cfg.PolicyHooks[i+1:]...)
+				removed = true
+				return Save(baseDir, cfg)
+			}
+		}
+		return nil
+	})
+	return removed, err
+}
+
+// GetRequiredFields returns the configured required-field rules
+func GetRequiredFields(baseDir string) ([]models.RequiredFieldsRule, error) {
+	cfg, err := Load(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.RequiredFields, nil
+}
+
+// SetRequiredFields adds or replaces the rule for a type and status. A
+// rule with no fields removes it.
+func SetRequiredFields(baseDir string, rule models.RequiredFieldsRule) error {
+	return withConfigLock(baseDir, func() error {
+		cfg, err := Load(baseDir)
+		if err != nil {
+			return err
+		}
+		for i := range cfg.RequiredFields {
+			if cfg.RequiredFields[i].Type != rule.Type || cfg.RequiredFields[i].To != rule.To {
+				continue
+			}
+			if len(rule.Fields) == 0 {
+				cfg.RequiredFields = append(cfg.RequiredFields[:i], cfg.RequiredFields[i+1:]...)
+			} else {
+				cfg.RequiredFields[i] = rule
+			}
+			return Save(baseDir, cfg)
+		}
+		if len(rule.Fields) == 0 {
+			return nil
+		}
+		cfg.RequiredFields = append(cfg.RequiredFields, rule)
+		return Save(baseDir, cfg)
+	})
+}
+
+// GetReviewChecklists returns the configured review checklists
+func GetReviewChecklists(baseDir string) ([]models.ReviewChecklist, error) {
+	cfg, err := Load(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.ReviewChecklists, nil
+}
+
+// SetReviewChecklist adds or replaces the checklist for a type. A
+// checklist with no items removes it.
+func SetReviewChecklist(baseDir string, list models.ReviewChecklist) error {
+	return withConfigLock(baseDir, func() error {
+		cfg, err := Load(baseDir)
+		if err != nil {
+			return err
+		}
+		for i := range cfg.ReviewChecklists {
+			if cfg.ReviewChecklists[i].Type != list.Type {
+				continue
+			}
+			if len(list.Items) == 0 {
+				cfg.ReviewChecklists = append(cfg.ReviewChecklists[:i], cfg.ReviewChecklists[i+1:]...)
+			} else {
+				cfg.ReviewChecklists[i] = list
+			}
+			return Save(baseDir, cfg)
+		}
+		if len(list.Items) == 0 {
+			return nil
+		}
+		cfg.ReviewChecklists = append(cfg.ReviewChecklists, list)
+		return Save(baseDir, cfg)
+	})
+}
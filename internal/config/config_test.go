@@ -5,8 +5,10 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/score"
 )
 
 func TestLoad(t *testing.T) {
@@ -535,6 +537,53 @@ func TestPaneHeights(t *testing.T) {
 	})
 }
 
+func TestPreviewLayout(t *testing.T) {
+	t.Run("defaults when unset", func(t *testing.T) {
+		dir := t.TempDir()
+
+		open, ratio, err := GetPreviewLayout(dir)
+		if err != nil {
+			t.Fatalf("GetPreviewLayout failed: %v", err)
+		}
+		if open {
+			t.Error("expected preview closed by default")
+		}
+		if ratio != DefaultPreviewRatio {
+			t.Errorf("ratio: got %v, want %v", ratio, DefaultPreviewRatio)
+		}
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		dir := t.TempDir()
+
+		if err := SetPreviewLayout(dir, true, 0.6); err != nil {
+			t.Fatalf("SetPreviewLayout failed: %v", err)
+		}
+		open, ratio, err := GetPreviewLayout(dir)
+		if err != nil {
+			t.Fatalf("GetPreviewLayout failed: %v", err)
+		}
+		if !open || ratio != 0.6 {
+			t.Errorf("got open=%v ratio=%v, want open=true ratio=0.6", open, ratio)
+		}
+	})
+
+	t.Run("out of range ratio falls back to default", func(t *testing.T) {
+		dir := t.TempDir()
+
+		if err := SetPreviewLayout(dir, true, 0.95); err != nil {
+			t.Fatalf("SetPreviewLayout failed: %v", err)
+		}
+		_, ratio, err := GetPreviewLayout(dir)
+		if err != nil {
+			t.Fatalf("GetPreviewLayout failed: %v", err)
+		}
+		if ratio != DefaultPreviewRatio {
+			t.Errorf("ratio: got %v, want %v", ratio, DefaultPreviewRatio)
+		}
+	})
+}
+
 func TestFilterState(t *testing.T) {
 	t.Run("GetFilterState on empty config", func(t *testing.T) {
 		dir := t.TempDir()
@@ -613,6 +662,66 @@ func TestFilterState(t *testing.T) {
 	})
 }
 
+func TestSectionFilters(t *testing.T) {
+	t.Run("GetSectionFilters on empty config", func(t *testing.T) {
+		dir := t.TempDir()
+
+		filters, err := GetSectionFilters(dir, SectionFilterScopeAll)
+		if err != nil {
+			t.Fatalf("GetSectionFilters failed: %v", err)
+		}
+		if len(filters) != 0 {
+			t.Errorf("expected no filters, got %v", filters)
+		}
+	})
+
+	t.Run("filters are scoped per board", func(t *testing.T) {
+		dir := t.TempDir()
+
+		if err := SetSectionFilter(dir, SectionFilterScopeAll, "ready", "priority <= P1"); err != nil {
+			t.Fatalf("SetSectionFilter failed: %v", err)
+		}
+		if err := SetSectionFilter(dir, "bd-123", "ready", "type = bug"); err != nil {
+			t.Fatalf("SetSectionFilter failed: %v", err)
+		}
+
+		all, err := GetSectionFilters(dir, SectionFilterScopeAll)
+		if err != nil {
+			t.Fatalf("GetSectionFilters failed: %v", err)
+		}
+		if all["ready"] != "priority <= P1" {
+			t.Errorf("all scope ready: got %q", all["ready"])
+		}
+
+		board, err := GetSectionFilters(dir, "bd-123")
+		if err != nil {
+			t.Fatalf("GetSectionFilters failed: %v", err)
+		}
+		if board["ready"] != "type = bug" {
+			t.Errorf("board scope ready: got %q", board["ready"])
+		}
+	})
+
+	t.Run("empty query clears filter", func(t *testing.T) {
+		dir := t.TempDir()
+
+		if err := SetSectionFilter(dir, "bd-1", "blocked", "labels ~ infra"); err != nil {
+			t.Fatalf("SetSectionFilter failed: %v", err)
+		}
+		if err := SetSectionFilter(dir, "bd-1", "blocked", ""); err != nil {
+			t.Fatalf("SetSectionFilter clear failed: %v", err)
+		}
+
+		cfg, err := Load(dir)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if _, ok := cfg.SectionFilters["bd-1"]; ok {
+			t.Errorf("expected empty scope to be removed, got %v", cfg.SectionFilters)
+		}
+	})
+}
+
 func TestTitleLengthLimits(t *testing.T) {
 	t.Run("returns defaults for empty config", func(t *testing.T) {
 		dir := t.TempDir()
@@ -882,3 +991,181 @@ func TestPermissionErrors(t *testing.T) {
 		}
 	})
 }
+
+func TestSprints(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := SetSprint(dir, models.Sprint{Name: "s2", Start: "2025-02-03", End: "2025-02-14"}); err != nil {
+		t.Fatalf("SetSprint failed: %v", err)
+	}
+	if err := SetSprint(dir, models.Sprint{Name: "s1", Start: "2025-01-20", End: "2025-01-31"}); err != nil {
+		t.Fatalf("SetSprint failed: %v", err)
+	}
+	// Replacing keeps a single entry
+	if err := SetSprint(dir, models.Sprint{Name: "s2", Start: "2025-02-03", End: "2025-02-16"}); err != nil {
+		t.Fatalf("SetSprint failed: %v", err)
+	}
+
+	sprints, err := GetSprints(dir)
+	if err != nil {
+		t.Fatalf("GetSprints failed: %v", err)
+	}
+	if len(sprints) != 2 || sprints[0].Name != "s1" || sprints[1].End != "2025-02-16" {
+		t.Errorf("sprints = %+v", sprints)
+	}
+
+	if removed, err := RemoveSprint(dir, "s1"); err != nil || !removed {
+		t.Fatalf("RemoveSprint = %v, %v", removed, err)
+	}
+	if removed, _ := RemoveSprint(dir, "nope"); removed {
+		t.Error("expected false for unknown sprint")
+	}
+	if sprints, _ := GetSprints(dir); len(sprints) != 1 {
+		t.Errorf("after remove: %+v", sprints)
+	}
+}
+
+func TestIntegrations(t *testing.T) {
+	dir := t.TempDir()
+
+	if ic, err := GetIntegration(dir, "team"); err != nil || ic != nil {
+		t.Fatalf("GetIntegration on empty config = %v, %v", ic, err)
+	}
+
+	if err := SetIntegration(dir, models.IntegrationConfig{Name: "team", Kind: "slack", SigningSecret: "s1"}); err != nil {
+		t.Fatalf("SetIntegration failed: %v", err)
+	}
+	if err := SetIntegration(dir, models.IntegrationConfig{Name: "team", Kind: "slack", SigningSecret: "s2", VerifySignature: true}); err != nil {
+		t.Fatalf("SetIntegration failed: %v", err)
+	}
+	ic, err := GetIntegration(dir, "team")
+	if err != nil || ic == nil {
+		t.Fatalf("GetIntegration = %v, %v", ic, err)
+	}
+	if ic.SigningSecret != "s2" || !ic.VerifySignature {
+		t.Errorf("integration = %+v", ic)
+	}
+	if all, _ := GetIntegrations(dir); len(all) != 1 {
+		t.Errorf("expected 1 integration, got %d", len(all))
+	}
+
+	if removed, err := RemoveIntegration(dir, "team"); err != nil || !removed {
+		t.Fatalf("RemoveIntegration = %v, %v", removed, err)
+	}
+	if removed, _ := RemoveIntegration(dir, "team"); removed {
+		t.Error("expected false removing twice")
+	}
+}
+
+func TestScoreFormula(t *testing.T) {
+	dir := t.TempDir()
+
+	f, err := GetScoreFormula(dir)
+	if err != nil {
+		t.Fatalf("GetScoreFormula failed: %v", err)
+	}
+	if f != score.Default {
+		t.Errorf("got %q, want default", f)
+	}
+
+	if err := SetScoreFormula(dir, "priority * 5 + urgency"); err != nil {
+		t.Fatalf("SetScoreFormula failed: %v", err)
+	}
+	if f, _ := GetScoreFormula(dir); f.String() != "priority * 5 + urgency" {
+		t.Errorf("got %q after set", f)
+	}
+
+	if err := SetScoreFormula(dir, "priority * bogus"); err == nil {
+		t.Error("expected error for unknown variable")
+	}
+	if f, _ := GetScoreFormula(dir); f.String() != "priority * 5 + urgency" {
+		t.Errorf("invalid formula replaced the stored one: %q", f)
+	}
+
+	if err := SetScoreFormula(dir, ""); err != nil {
+		t.Fatalf("reset failed: %v", err)
+	}
+	if f, _ := GetScoreFormula(dir); f != score.Default {
+		t.Errorf("got %q after reset, want default", f)
+	}
+
+	// A broken formula edited into config.json falls back to the default
+	if err := Save(dir, &models.Config{ScoreFormula: "age +"}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if f, err := GetScoreFormula(dir); err == nil || f != score.Default {
+		t.Errorf("got %q, %v; want default and an error", f, err)
+	}
+}
+
+func TestGetShareSecret(t *testing.T) {
+	dir := t.TempDir()
+
+	first, err := GetShareSecret(dir)
+	if err != nil || first == "" {
+		t.Fatalf("GetShareSecret = %q, %v", first, err)
+	}
+	second, err := GetShareSecret(dir)
+	if err != nil || second != first {
+		t.Errorf("second call = %q, %v; want the saved secret %q", second, err, first)
+	}
+}
+
+func TestLinkedProjects(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := SetLinkedProject(dir, "api", "/src/api"); err != nil {
+		t.Fatalf("SetLinkedProject failed: %v", err)
+	}
+	if err := SetLinkedProject(dir, "web", "/src/web"); err != nil {
+		t.Fatalf("SetLinkedProject failed: %v", err)
+	}
+	if err := SetLinkedProject(dir, "api", "/work/api"); err != nil {
+		t.Fatalf("SetLinkedProject failed: %v", err)
+	}
+
+	projects, err := GetLinkedProjects(dir)
+	if err != nil {
+		t.Fatalf("GetLinkedProjects failed: %v", err)
+	}
+	if len(projects) != 2 || projects["api"] != "/work/api" {
+		t.Errorf("projects = %v", projects)
+	}
+
+	if removed, err := RemoveLinkedProject(dir, "web"); err != nil || !removed {
+		t.Fatalf("RemoveLinkedProject = %v, %v", removed, err)
+	}
+	if removed, _ := RemoveLinkedProject(dir, "web"); removed {
+		t.Error("expected false removing twice")
+	}
+}
+
+func TestTimezone(t *testing.T) {
+	dir := t.TempDir()
+
+	loc, err := GetTimezone(dir)
+	if err != nil || loc != time.Local {
+		t.Fatalf("GetTimezone with none set = %v, %v; want Local", loc, err)
+	}
+
+	if err := SetTimezone(dir, "Not/AZone"); err == nil {
+		t.Error("SetTimezone accepted an unknown timezone")
+	}
+	if err := SetTimezone(dir, "Asia/Tokyo"); err != nil {
+		t.Fatalf("SetTimezone failed: %v", err)
+	}
+	loc, err = GetTimezone(dir)
+	if err != nil {
+		t.Fatalf("GetTimezone failed: %v", err)
+	}
+	if loc.String() != "Asia/Tokyo" {
+		t.Errorf("timezone = %s, want Asia/Tokyo", loc)
+	}
+
+	if err := SetTimezone(dir, ""); err != nil {
+		t.Fatalf("SetTimezone reset failed: %v", err)
+	}
+	if loc, _ := GetTimezone(dir); loc != time.Local {
+		t.Errorf("timezone after reset = %s, want Local", loc)
+	}
+}
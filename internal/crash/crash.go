@@ -0,0 +1,305 @@
+// Package crash turns panics into crash reports. td serve, the monitor and
+// the CLI recover from panics instead of dying with them; each one is
+// logged with its stack trace and, unless turned off, saved as a crash dump
+// under .todos/crashes so it can be looked at, or shared, later.
+//
+// Dumps stay on the machine. td crash report prints one with paths, user
+// names and request details taken out, to attach to a bug report.
+package crash
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/marcus/td/internal/syncconfig"
+)
+
+// Version is the td version recorded in reports, set at startup
+var Version = "dev"
+
+// MaxDumps is how many crash dumps are kept; older ones are removed
+const MaxDumps = 50
+
+// dedupeWindow is how long a panic with the same message and origin isn't
+// saved again, so a panic on every redraw doesn't fill the disk
+const dedupeWindow = time.Minute
+
+// Report is one recovered panic
+type Report struct {
+	ID        string            `json:"id"`
+	Time      time.Time         `json:"time"`
+	Component string            `json:"component"` // serve, monitor or cli
+	Panic     string            `json:"panic"`
+	Stack     string            `json:"stack"`
+	Version   string            `json:"version"`
+	GoVersion string            `json:"go_version"`
+	OS        string            `json:"os"`
+	Arch      string            `json:"arch"`
+	Context   map[string]string `json:"context,omitempty"` // what was running: request route, TUI message...
+}
+
+// New builds a report for a recovered panic. Call it from the deferred
+// function that recovered, so the stack still shows where it happened.
+func New(component string, rec interface{}, context map[string]string) *Report {
+	return &Report{
+		ID:        newID(),
+		Time:      time.Now().UTC(),
+		Component: component,
+		Panic:     fmt.Sprint(rec),
+		Stack:     string(debug.Stack()),
+		Version:   Version,
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		Context:   context,
+	}
+}
+
+// Capture builds the report for a recovered panic and saves it. saved is
+// false when no dump was written: dumps are off, the same panic was just
+// saved, or writing failed; the report still carries the stack.
+func Capture(baseDir, component string, rec interface{}, context map[string]string) (r *Report, saved bool) {
+	r = New(component, rec, context)
+	path, err := r.Save(baseDir)
+	if err != nil {
+		slog.Debug("crash: save dump", "id", r.ID, "err", err)
+	}
+	return r, path != ""
+}
+
+// Recover stops a panic in a background goroutine from taking the process
+// down with it: deferred at the top of the goroutine, it logs the panic
+// with its stack and saves a crash dump.
+//
+//	go func() {
+//		defer crash.Recover(baseDir, "serve", map[string]string{"task": "autosync"})
+//		...
+//	}()
+func Recover(baseDir, component string, context map[string]string) {
+	rec := recover()
+	if rec == nil {
+		return
+	}
+	r, _ := Capture(baseDir, component, rec, context)
+	slog.Error("panic recovered",
+		"crash_id", r.ID,
+		"component", component,
+		"panic", r.Panic,
+		"context", context,
+		"stack", r.Stack,
+	)
+}
+
+func newID() string {
+	b := make([]byte, 3)
+	_, _ = rand.Read(b)
+	return "cr-" + time.Now().UTC().Format("20060102-150405") + "-" + hex.EncodeToString(b)
+}
+
+// Dir returns the directory crash dumps are saved in
+func Dir(baseDir string) string {
+	return filepath.Join(baseDir, ".todos", "crashes")
+}
+
+// Enabled reports whether crash dumps are saved.
+// Priority: TD_CRASH_DUMPS env > config.json crash_dumps > true
+func Enabled() bool {
+	return syncconfig.GetCrashDumps()
+}
+
+var (
+	recentMu sync.Mutex
+	recent   = make(map[string]time.Time) // signature -> last saved
+)
+
+// signature identifies a panic by its message and the frame that panicked
+func (r *Report) signature() string {
+	origin := ""
+	if i := strings.Index(r.Stack, "panic("); i >= 0 {
+		// The frame after panic's own two lines is where it was raised
+		lines := strings.SplitN(r.Stack[i:], "\n", 5)
+		if len(lines) >= 4 {
+			origin = strings.TrimSpace(lines[3])
+		}
+	}
+	return r.Component + "\x00" + r.Panic + "\x00" + origin
+}
+
+// Save writes the report to the project's crash directory and returns its
+// path. It saves nothing, returning "", when dumps are turned off, there is
+// no project, or the same panic was saved within the last minute.
+func (r *Report) Save(baseDir string) (string, error) {
+	if baseDir == "" || !Enabled() {
+		return "", nil
+	}
+	if _, err := os.Stat(filepath.Join(baseDir, ".todos")); err != nil {
+		return "", nil
+	}
+
+	sig := r.signature()
+	recentMu.Lock()
+	if last, ok := recent[sig]; ok && r.Time.Sub(last) < dedupeWindow {
+		recentMu.Unlock()
+		return "", nil
+	}
+	recent[sig] = r.Time
+	recentMu.Unlock()
+
+	dir := Dir(baseDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, r.ID+".json")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return "", err
+	}
+	prune(dir)
+	return path, nil
+}
+
+// prune removes the oldest dumps beyond MaxDumps
+func prune(dir string) {
+	names, err := dumpNames(dir)
+	if err != nil || len(names) <= MaxDumps {
+		return
+	}
+	for _, name := range names[MaxDumps:] {
+		_ = os.Remove(filepath.Join(dir, name))
+	}
+}
+
+// dumpNames lists the dump files in dir, newest first. IDs start with the
+// time, so name order is time order.
+func dumpNames(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasPrefix(e.Name(), "cr-") && strings.HasSuffix(e.Name(), ".json") {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(names)))
+	return names, nil
+}
+
+// List returns the saved crash reports, newest first
+func List(baseDir string) ([]Report, error) {
+	names, err := dumpNames(Dir(baseDir))
+	if err != nil {
+		return nil, err
+	}
+	reports := []Report{}
+	for _, name := range names {
+		r, err := Load(baseDir, strings.TrimSuffix(name, ".json"))
+		if err != nil {
+			continue // a dump cut short or edited by hand
+		}
+		reports = append(reports, *r)
+	}
+	return reports, nil
+}
+
+// Load reads a saved crash report. A unique prefix of the ID is enough.
+func Load(baseDir, id string) (*Report, error) {
+	names, err := dumpNames(Dir(baseDir))
+	if err != nil {
+		return nil, err
+	}
+	var match []string
+	for _, name := range names {
+		if strings.HasPrefix(name, id) {
+			match = append(match, name)
+		}
+	}
+	switch len(match) {
+	case 0:
+		return nil, fmt.Errorf("crash report not found: %s", id)
+	case 1:
+	default:
+		return nil, fmt.Errorf("%s matches %d crash reports", id, len(match))
+	}
+	data, err := os.ReadFile(filepath.Join(Dir(baseDir), match[0]))
+	if err != nil {
+		return nil, err
+	}
+	var r Report
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("%s: %w", match[0], err)
+	}
+	return &r, nil
+}
+
+// Clear removes every saved crash report and returns how many there were
+func Clear(baseDir string) (int, error) {
+	names, err := dumpNames(Dir(baseDir))
+	if err != nil {
+		return 0, err
+	}
+	for _, name := range names {
+		if err := os.Remove(filepath.Join(Dir(baseDir), name)); err != nil {
+			return 0, err
+		}
+	}
+	return len(names), nil
+}
+
+// anonymousContext lists the context keys kept in an anonymized report.
+// Request paths, IDs and the like are dropped; a route pattern such
+// as "GET /v1/issues/{id}" says where it happened without them.
+var anonymousContext = map[string]bool{"route": true, "method": true, "command": true, "msg": true, "task": true}
+
+// Anonymize returns a copy of r fit to share: the project directory, home
+// directory and user name are replaced in the panic and stack, context
+// other than routes and message types is dropped, and the time is cut to
+// the day.
+func (r Report) Anonymize(baseDir string) Report {
+	var pairs []string
+	if baseDir != "" {
+		pairs = append(pairs, baseDir, "<project>")
+	}
+	if home, err := os.UserHomeDir(); err == nil && home != "" && home != "/" {
+		pairs = append(pairs, home, "~")
+	}
+	for _, env := range []string{"USER", "USERNAME", "LOGNAME"} {
+		if u := os.Getenv(env); len(u) > 2 {
+			pairs = append(pairs, u, "<user>")
+		}
+	}
+	replace := strings.NewReplacer(pairs...)
+
+	out := r
+	out.Time = r.Time.Truncate(24 * time.Hour)
+	out.Panic = replace.Replace(r.Panic)
+	out.Stack = replace.Replace(r.Stack)
+	out.Context = nil
+	for k, v := range r.Context {
+		if anonymousContext[k] {
+			if out.Context == nil {
+				out.Context = make(map[string]string)
+			}
+			out.Context[k] = replace.Replace(v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,142 @@
+package crash
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newProject(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, ".todos"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TD_CRASH_DUMPS", "1")
+	return dir
+}
+
+// panicked recovers a panic raised by fn and captures it
+func panicked(baseDir string, fn func()) (r *Report, saved bool) {
+	func() {
+		defer func() {
+			if rec := recover(); rec != nil {
+				r, saved = Capture(baseDir, "serve", rec, map[string]string{"route": "GET /v1/issues/{id}", "path": "/v1/issues/td-abc"})
+			}
+		}()
+		fn()
+	}()
+	return r, saved
+}
+
+func TestCapture_SavesAndLoads(t *testing.T) {
+	dir := newProject(t)
+	r, saved := panicked(dir, func() { panic("boom") })
+	if !saved {
+		t.Fatal("dump not saved")
+	}
+	if r.Panic != "boom" || !strings.Contains(r.Stack, "crash_test.go") {
+		t.Errorf("report = %q, stack missing the panicking frame:\n%s", r.Panic, r.Stack)
+	}
+
+	info, err := os.Stat(filepath.Join(Dir(dir), r.ID+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	got, err := Load(dir, r.ID[:len(r.ID)-2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != r.ID || got.Context["path"] != "/v1/issues/td-abc" {
+		t.Errorf("loaded %+v", got)
+	}
+}
+
+func TestCapture_SamePanicSavedOnce(t *testing.T) {
+	dir := newProject(t)
+	boom := func() { panic("again") }
+	if _, saved := panicked(dir, boom); !saved {
+		t.Fatal("first panic not saved")
+	}
+	if _, saved := panicked(dir, boom); saved {
+		t.Error("same panic saved twice within a minute")
+	}
+	reports, _ := List(dir)
+	if len(reports) != 1 {
+		t.Errorf("got %d reports, want 1", len(reports))
+	}
+}
+
+func TestCapture_Disabled(t *testing.T) {
+	dir := newProject(t)
+	t.Setenv("TD_CRASH_DUMPS", "0")
+	r, saved := panicked(dir, func() { panic("off") })
+	if saved || r == nil {
+		t.Fatalf("saved = %v, report = %v", saved, r)
+	}
+	if _, err := os.Stat(Dir(dir)); !os.IsNotExist(err) {
+		t.Errorf("crash dir created with dumps off: %v", err)
+	}
+}
+
+func TestList_PrunesOldest(t *testing.T) {
+	dir := newProject(t)
+	for i := 0; i < MaxDumps+3; i++ {
+		r := &Report{ID: "cr-" + time.Unix(int64(i), 0).UTC().Format("20060102-150405") + "-000000", Panic: string(rune('a' + i%26)), Time: time.Unix(int64(i)*120, 0)}
+		if _, err := r.Save(dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+	reports, err := List(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != MaxDumps {
+		t.Fatalf("kept %d reports, want %d", len(reports), MaxDumps)
+	}
+	if reports[0].Time.Before(reports[len(reports)-1].Time) {
+		t.Error("reports not newest first")
+	}
+
+	n, err := Clear(dir)
+	if err != nil || n != MaxDumps {
+		t.Errorf("Clear = %d, %v", n, err)
+	}
+}
+
+func TestAnonymize(t *testing.T) {
+	dir := newProject(t)
+	t.Setenv("USER", "jdoe-test")
+	r := Report{
+		ID:    "cr-1",
+		Time:  time.Date(2026, 3, 4, 15, 16, 17, 0, time.UTC),
+		Panic: "open " + dir + "/.todos/x: jdoe-test denied",
+		Stack: "main.go in " + dir + "/cmd",
+		Context: map[string]string{
+			"route": "GET /v1/issues/{id}", "path": "/v1/issues/td-abc", "request_id": "req_1",
+		},
+	}
+	got := r.Anonymize(dir)
+
+	if strings.Contains(got.Panic, dir) || strings.Contains(got.Stack, dir) || strings.Contains(got.Panic, "jdoe-test") {
+		t.Errorf("identifying text left: %q / %q", got.Panic, got.Stack)
+	}
+	if !strings.Contains(got.Panic, "<project>/.todos/x") || !strings.Contains(got.Panic, "<user>") {
+		t.Errorf("panic = %q", got.Panic)
+	}
+	if len(got.Context) != 1 || got.Context["route"] != "GET /v1/issues/{id}" {
+		t.Errorf("context = %v, want only the route", got.Context)
+	}
+	if !got.Time.Equal(time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("time = %v, want the day only", got.Time)
+	}
+	if r.Context["path"] == "" {
+		t.Error("Anonymize changed the original report")
+	}
+}
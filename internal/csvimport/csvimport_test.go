@@ -0,0 +1,140 @@
+package csvimport
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+func TestParseMapping(t *testing.T) {
+	m, err := ParseMapping(`title=Title, prio=Prio, description="Notes, extra", type='bug'`)
+	if err != nil {
+		t.Fatalf("ParseMapping: %v", err)
+	}
+	if m[FieldTitle].Column != "Title" || m[FieldPriority].Column != "Prio" {
+		t.Errorf("columns = %+v", m)
+	}
+	if m[FieldDescription].Column != "Notes, extra" {
+		t.Errorf("quoted column = %q", m[FieldDescription].Column)
+	}
+	if src := m[FieldType]; !src.IsConst || src.Literal != "bug" {
+		t.Errorf("literal = %+v", src)
+	}
+	if got := m.String(); got != `title=Title,description="Notes, extra",type='bug',priority=Prio` {
+		t.Errorf("String() = %s", got)
+	}
+
+	for _, bad := range []string{"title", "nope=X", "title=A,title=B", `title="A`, "title="} {
+		if _, err := ParseMapping(bad); err == nil {
+			t.Errorf("ParseMapping(%q) expected error", bad)
+		}
+	}
+}
+
+func TestParseAutoMappingAndValidation(t *testing.T) {
+	csv := "\ufeffTitle,Priority,Points,Tags,Due Date,Minor\n" +
+		"Import backlog from spreadsheet,P1,3,\"a, b\",2030-01-15,yes\n" +
+		"\n" +
+		"x,P9,4,,someday,maybe\n"
+
+	rows, mapping, err := Parse(strings.NewReader(csv), Options{TitleMin: 15, TitleMax: 100})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if _, ok := mapping[FieldDue]; !ok {
+		t.Errorf("expected Due Date auto-mapped, got %s", mapping)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("rows = %d, want 2 (blank line skipped)", len(rows))
+	}
+
+	ok := rows[0]
+	if !ok.Valid() {
+		t.Fatalf("row 1 errors: %+v", ok.Errors)
+	}
+	if ok.Line != 2 || ok.Issue.Priority != models.PriorityP1 || ok.Issue.Points != 3 || !ok.Issue.Minor {
+		t.Errorf("row 1 issue = %+v", ok.Issue)
+	}
+	if len(ok.Issue.Labels) != 2 || ok.Issue.DueDate == nil || *ok.Issue.DueDate != "2030-01-15" {
+		t.Errorf("row 1 labels/due = %v %v", ok.Issue.Labels, ok.Issue.DueDate)
+	}
+
+	bad := rows[1]
+	if bad.Line != 4 {
+		t.Errorf("row 2 line = %d, want 4", bad.Line)
+	}
+	fields := map[string]bool{}
+	for _, e := range bad.Errors {
+		fields[e.Field] = true
+	}
+	for _, f := range []string{FieldTitle, FieldPriority, FieldPoints, FieldDue, FieldMinor} {
+		if !fields[f] {
+			t.Errorf("expected %s error, got %+v", f, bad.Errors)
+		}
+	}
+}
+
+func TestParseFileErrors(t *testing.T) {
+	if _, _, err := Parse(strings.NewReader(""), Options{}); err == nil {
+		t.Error("expected error for empty file")
+	}
+	if _, _, err := Parse(strings.NewReader("Foo,Bar\n1,2\n"), Options{}); err == nil {
+		t.Error("expected error when no title column")
+	}
+	m, _ := ParseMapping("title=Missing")
+	if _, _, err := Parse(strings.NewReader("Title\nabc\n"), Options{Mapping: m}); err == nil {
+		t.Error("expected error for unknown column")
+	}
+}
+
+func TestImport(t *testing.T) {
+	database, err := db.Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+
+	csv := "Name,Prio,Parent\n" +
+		"Set up billing export,P0,\n" +
+		"Write migration guide,,td-missing\n"
+	m, err := ParseMapping("title=Name,priority=Prio,parent=Parent,labels='imported'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Dry run creates nothing but reports the bad parent
+	res, err := Import(database, strings.NewReader(csv), Options{Mapping: m, DryRun: true}, "ses_test")
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if res.Invalid != 1 || res.Created != 0 {
+		t.Errorf("dry run result = %+v", res)
+	}
+
+	// Invalid rows abort the whole import by default
+	_, err = Import(database, strings.NewReader(csv), Options{Mapping: m}, "ses_test")
+	if !errors.Is(err, ErrInvalidRows) {
+		t.Fatalf("expected ErrInvalidRows, got %v", err)
+	}
+	if issues, _ := database.ListIssues(db.ListIssuesOptions{}); len(issues) != 0 {
+		t.Fatalf("expected no issues after aborted import, got %d", len(issues))
+	}
+
+	res, err = Import(database, strings.NewReader(csv), Options{Mapping: m, SkipInvalid: true}, "ses_test")
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if res.Created != 1 || res.Rows[0].ID == "" {
+		t.Fatalf("import result = %+v", res)
+	}
+	issue, err := database.GetIssue(res.Rows[0].ID)
+	if err != nil {
+		t.Fatalf("get issue: %v", err)
+	}
+	if issue.Priority != models.PriorityP0 || issue.CreatorSession != "ses_test" || len(issue.Labels) != 1 || issue.Labels[0] != "imported" {
+		t.Errorf("created issue = %+v", issue)
+	}
+}
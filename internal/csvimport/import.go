@@ -0,0 +1,291 @@
+package csvimport
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/marcus/td/internal/dateparse"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/git"
+	"github.com/marcus/td/internal/models"
+)
+
+// Options controls how a CSV file is parsed and imported.
+type Options struct {
+	Mapping     Mapping // nil = AutoMapping from the header row
+	TitleMin    int
+	TitleMax    int
+	DryRun      bool // validate and preview only
+	SkipInvalid bool // import valid rows even when others fail validation
+	Inbox       bool // put the new issues in the triage inbox
+}
+
+// RowError is a validation failure for one field of one row.
+type RowError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Row is one CSV data row and its outcome.
+type Row struct {
+	Line   int           `json:"line"` // 1-based line in the file, header is line 1
+	Issue  *models.Issue `json:"-"`
+	Title  string        `json:"title"`
+	ID     string        `json:"id,omitempty"` // set once created
+	Errors []RowError    `json:"errors,omitempty"`
+}
+
+// Valid reports whether the row passed validation.
+func (r *Row) Valid() bool { return len(r.Errors) == 0 }
+
+func (r *Row) addError(field, format string, args ...interface{}) {
+	r.Errors = append(r.Errors, RowError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Result summarizes an import.
+type Result struct {
+	DryRun  bool   `json:"dry_run"`
+	Mapping string `json:"mapping"`
+	Rows    []Row  `json:"rows"`
+	Created int    `json:"created"`
+	Invalid int    `json:"invalid"`
+}
+
+// ErrInvalidRows is returned by Import when rows fail validation and
+// SkipInvalid is not set. Nothing is created in that case.
+var ErrInvalidRows = errors.New("rows failed validation")
+
+// Parse reads a CSV file and builds one validated issue per data row.
+// It fails only for problems with the file as a whole; per-row problems are
+// recorded on each Row.
+func Parse(r io.Reader, opts Options) ([]Row, Mapping, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil, fmt.Errorf("empty CSV file")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("read header: %w", err)
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff") // spreadsheet BOM
+	}
+
+	mapping := opts.Mapping
+	if mapping == nil {
+		mapping = AutoMapping(header)
+	}
+	if _, ok := mapping[FieldTitle]; !ok {
+		return nil, nil, fmt.Errorf("no column mapped to title (columns: %s)", strings.Join(header, ", "))
+	}
+
+	// Resolve column names to indexes (case-insensitive)
+	index := make(map[string]int, len(mapping))
+	for field, src := range mapping {
+		if src.IsConst {
+			continue
+		}
+		idx := -1
+		for i, col := range header {
+			if strings.EqualFold(strings.TrimSpace(col), strings.TrimSpace(src.Column)) {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			return nil, nil, fmt.Errorf("column %q (for %s) not found (columns: %s)", src.Column, field, strings.Join(header, ", "))
+		}
+		index[field] = idx
+	}
+
+	var rows []Row
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		if isBlank(record) {
+			continue
+		}
+
+		get := func(field string) string {
+			if src, ok := mapping[field]; ok && src.IsConst {
+				return src.Literal
+			}
+			if idx, ok := index[field]; ok && idx < len(record) {
+				return strings.TrimSpace(record[idx])
+			}
+			return ""
+		}
+		rows = append(rows, buildRow(line, get, opts))
+	}
+	return rows, mapping, nil
+}
+
+// buildRow converts one record into an issue and validates it
+func buildRow(line int, get func(string) string, opts Options) Row {
+	row := Row{Line: line}
+	issue := &models.Issue{
+		Title:       get(FieldTitle),
+		Description: get(FieldDescription),
+		Acceptance:  get(FieldAcceptance),
+		Sprint:      get(FieldSprint),
+		Type:        models.TypeTask,
+		Priority:    models.PriorityP2,
+	}
+	row.Title = issue.Title
+	row.Issue = issue
+
+	titleLen := utf8.RuneCountInString(issue.Title)
+	switch {
+	case issue.Title == "":
+		row.addError(FieldTitle, "title is required")
+	case opts.TitleMin > 0 && titleLen < opts.TitleMin:
+		row.addError(FieldTitle, "title too short (%d chars, min %d)", titleLen, opts.TitleMin)
+	case opts.TitleMax > 0 && titleLen > opts.TitleMax:
+		row.addError(FieldTitle, "title too long (%d chars, max %d)", titleLen, opts.TitleMax)
+	}
+
+	if v := get(FieldType); v != "" {
+		issue.Type = models.NormalizeType(v)
+		if !models.IsValidType(issue.Type) {
+			row.addError(FieldType, "invalid type %q (bug, feature, task, epic, chore)", v)
+		}
+	}
+	if v := get(FieldPriority); v != "" {
+		issue.Priority = models.NormalizePriority(v)
+		if !models.IsValidPriority(issue.Priority) {
+			row.addError(FieldPriority, "invalid priority %q (P0-P4)", v)
+		}
+	}
+	if v := get(FieldPoints); v != "" {
+		points, err := strconv.Atoi(v)
+		if err != nil || !models.IsValidPoints(points) {
+			row.addError(FieldPoints, "invalid points %q (must be Fibonacci: 1,2,3,5,8,13,21)", v)
+		} else {
+			issue.Points = points
+		}
+	}
+	if v := get(FieldLabels); v != "" {
+		for _, l := range strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ';' }) {
+			if l = strings.TrimSpace(l); l != "" {
+				issue.Labels = append(issue.Labels, l)
+			}
+		}
+	}
+	if v := get(FieldParent); v != "" {
+		issue.ParentID = db.NormalizeIssueID(v)
+	}
+	if v := get(FieldDue); v != "" {
+		if d, err := dateparse.ParseDate(v); err != nil {
+			row.addError(FieldDue, "invalid due date %q: %v", v, err)
+		} else {
+			issue.DueDate = &d
+		}
+	}
+	if v := get(FieldDefer); v != "" {
+		if d, err := dateparse.ParseDate(v); err != nil {
+			row.addError(FieldDefer, "invalid defer date %q: %v", v, err)
+		} else {
+			issue.DeferUntil = &d
+		}
+	}
+	if v := get(FieldMinor); v != "" {
+		minor, ok := parseBool(v)
+		if !ok {
+			row.addError(FieldMinor, "invalid minor flag %q (yes/no)", v)
+		}
+		issue.Minor = minor
+	}
+	return row
+}
+
+// Import parses a CSV file and creates its issues. Rows are validated
+// against the database (e.g. parent existence) before anything is created.
+// Unless opts.SkipInvalid is set, any invalid row aborts the whole import
+// with ErrInvalidRows so a corrected file can be re-imported without
+// duplicates. The returned Result is populated in every case but file-level
+// errors.
+func Import(database *db.DB, r io.Reader, opts Options, sessionID string) (*Result, error) {
+	rows, mapping, err := Parse(r, opts)
+	if err != nil {
+		return nil, err
+	}
+	res := &Result{DryRun: opts.DryRun, Mapping: mapping.String(), Rows: rows}
+
+	for i := range res.Rows {
+		row := &res.Rows[i]
+		if row.Issue.ParentID != "" {
+			if _, err := database.GetIssue(row.Issue.ParentID); err != nil {
+				row.addError(FieldParent, "parent issue not found: %s", row.Issue.ParentID)
+			}
+		}
+		if !row.Valid() {
+			res.Invalid++
+		}
+	}
+
+	if opts.DryRun {
+		return res, nil
+	}
+	if res.Invalid > 0 && !opts.SkipInvalid {
+		return res, fmt.Errorf("%d of %d %w", res.Invalid, len(res.Rows), ErrInvalidRows)
+	}
+
+	branch, repo := "", ""
+	if gitState, _ := git.GetState(); gitState != nil {
+		branch, repo = gitState.Branch, gitState.Repo
+	}
+
+	for i := range res.Rows {
+		row := &res.Rows[i]
+		if !row.Valid() {
+			continue
+		}
+		row.Issue.CreatorSession = sessionID
+		row.Issue.CreatedBranch = branch
+		row.Issue.CreatedRepo = repo
+		row.Issue.Inbox = opts.Inbox
+		if err := database.CreateIssueLogged(row.Issue, sessionID); err != nil {
+			return res, fmt.Errorf("line %d: create issue: %w", row.Line, err)
+		}
+		if err := database.RecordSessionAction(row.Issue.ID, sessionID, models.ActionSessionCreated); err != nil {
+			slog.Warn("failed to record session history", "err", err, "issue", row.Issue.ID)
+		}
+		row.ID = row.Issue.ID
+		res.Created++
+	}
+	return res, nil
+}
+
+func isBlank(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func parseBool(v string) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "1", "true", "yes", "y", "x":
+		return true, true
+	case "0", "false", "no", "n":
+		return false, true
+	}
+	return false, false
+}
@@ -0,0 +1,188 @@
+// Package csvimport creates issues in bulk from CSV files, such as backlogs
+// maintained in a spreadsheet.
+package csvimport
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Importable issue fields, in the order they are reported.
+const (
+	FieldTitle       = "title"
+	FieldDescription = "description"
+	FieldType        = "type"
+	FieldPriority    = "priority"
+	FieldPoints      = "points"
+	FieldLabels      = "labels"
+	FieldParent      = "parent"
+	FieldAcceptance  = "acceptance"
+	FieldSprint      = "sprint"
+	FieldDue         = "due"
+	FieldDefer       = "defer"
+	FieldMinor       = "minor"
+)
+
+// Fields lists every field a mapping may target.
+var Fields = []string{
+	FieldTitle, FieldDescription, FieldType, FieldPriority, FieldPoints, FieldLabels,
+	FieldParent, FieldAcceptance, FieldSprint, FieldDue, FieldDefer, FieldMinor,
+}
+
+// fieldAliases lets auto-mapping and --map accept common spellings
+var fieldAliases = map[string]string{
+	"summary":      FieldTitle,
+	"name":         FieldTitle,
+	"desc":         FieldDescription,
+	"body":         FieldDescription,
+	"kind":         FieldType,
+	"prio":         FieldPriority,
+	"estimate":     FieldPoints,
+	"story_points": FieldPoints,
+	"tags":         FieldLabels,
+	"label":        FieldLabels,
+	"parent_id":    FieldParent,
+	"epic":         FieldParent,
+	"due_date":     FieldDue,
+	"defer_until":  FieldDefer,
+}
+
+// Source is where a field's value comes from: a CSV column, or a literal
+// applied to every row.
+type Source struct {
+	Column  string
+	Literal string
+	IsConst bool
+}
+
+// Mapping maps issue fields to their sources.
+type Mapping map[string]Source
+
+// resolveField returns the canonical field name for name, or "".
+func resolveField(name string) string {
+	key := strings.ToLower(strings.TrimSpace(name))
+	key = strings.ReplaceAll(key, " ", "_")
+	for _, f := range Fields {
+		if key == f {
+			return f
+		}
+	}
+	return fieldAliases[key]
+}
+
+// ParseMapping parses a mapping spec of comma-separated field=source pairs.
+//
+//	title=Title,priority=Prio            map fields to CSV columns
+//	description="Notes, extra"           double quotes allow commas in names
+//	type='bug',labels='imported'         single quotes set a literal for every row
+func ParseMapping(spec string) (Mapping, error) {
+	m := Mapping{}
+	pairs, err := splitPairs(spec)
+	if err != nil {
+		return nil, err
+	}
+	for _, pair := range pairs {
+		eq := strings.IndexByte(pair, '=')
+		if eq < 0 {
+			return nil, fmt.Errorf("invalid mapping %q: expected field=column", pair)
+		}
+		name := strings.TrimSpace(pair[:eq])
+		field := resolveField(name)
+		if field == "" {
+			return nil, fmt.Errorf("unknown field %q (valid: %s)", name, strings.Join(Fields, ", "))
+		}
+		if _, dup := m[field]; dup {
+			return nil, fmt.Errorf("field %q mapped more than once", field)
+		}
+
+		value := strings.TrimSpace(pair[eq+1:])
+		var src Source
+		switch {
+		case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+			src = Source{Literal: value[1 : len(value)-1], IsConst: true}
+		case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+			src = Source{Column: value[1 : len(value)-1]}
+		default:
+			src = Source{Column: value}
+		}
+		if !src.IsConst && src.Column == "" {
+			return nil, fmt.Errorf("invalid mapping %q: empty column name", pair)
+		}
+		m[field] = src
+	}
+	return m, nil
+}
+
+// splitPairs splits spec on commas that are not inside quotes
+func splitPairs(spec string) ([]string, error) {
+	var pairs []string
+	var cur strings.Builder
+	var quote rune
+	for _, r := range spec {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+			cur.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote = r
+			cur.WriteRune(r)
+		case r == ',':
+			if s := strings.TrimSpace(cur.String()); s != "" {
+				pairs = append(pairs, s)
+			}
+			cur.Reset()
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in mapping %q", spec)
+	}
+	if s := strings.TrimSpace(cur.String()); s != "" {
+		pairs = append(pairs, s)
+	}
+	return pairs, nil
+}
+
+// AutoMapping maps header columns whose names match a field or alias.
+func AutoMapping(header []string) Mapping {
+	m := Mapping{}
+	for _, col := range header {
+		if field := resolveField(col); field != "" {
+			if _, dup := m[field]; !dup {
+				m[field] = Source{Column: col}
+			}
+		}
+	}
+	return m
+}
+
+// String renders the mapping in ParseMapping syntax, in field order.
+func (m Mapping) String() string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	order := make(map[string]int, len(Fields))
+	for i, f := range Fields {
+		order[f] = i
+	}
+	sort.Slice(keys, func(i, j int) bool { return order[keys[i]] < order[keys[j]] })
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		src := m[k]
+		switch {
+		case src.IsConst:
+			parts = append(parts, fmt.Sprintf("%s='%s'", k, src.Literal))
+		case strings.ContainsAny(src.Column, ",'\""):
+			parts = append(parts, fmt.Sprintf("%s=%q", k, src.Column))
+		default:
+			parts = append(parts, k+"="+src.Column)
+		}
+	}
+	return strings.Join(parts, ",")
+}
@@ -10,7 +10,7 @@ import (
 )
 
 // ParseDate parses a date input string and returns an ISO 8601 date (YYYY-MM-DD).
-// Uses the current time as the reference point.
+// Uses the current time in Location as the reference point.
 //
 // Supported formats:
 //   - Exact dates: "2026-03-01"
@@ -20,7 +20,7 @@ import (
 //   - Day names: "monday", "tuesday", etc. (next occurrence)
 //   - Keywords: "today", "tomorrow", "next-week", "next-month"
 func ParseDate(input string) (string, error) {
-	return ParseDateFrom(input, time.Now())
+	return ParseDateFrom(input, Now())
 }
 
 // ParseDateFrom parses a date input string relative to the given reference time.
@@ -99,3 +99,49 @@ func ParseDateFrom(input string, now time.Time) (string, error) {
 func formatDate(t time.Time) string {
 	return t.Format("2006-01-02")
 }
+
+// ParseOffset parses a short relative offset such as "30m", "2h", "3d" or
+// "1w" (an optional leading "+" is allowed). Months are not accepted since
+// their length varies; use ParseDate for calendar dates.
+func ParseOffset(input string) (time.Duration, error) {
+	input = strings.TrimPrefix(strings.TrimSpace(strings.ToLower(input)), "+")
+	if len(input) < 2 {
+		return 0, fmt.Errorf("invalid offset %q (use e.g. 30m, 2h, 3d, 1w)", input)
+	}
+	n, err := strconv.Atoi(input[:len(input)-1])
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid offset %q (use e.g. 30m, 2h, 3d, 1w)", input)
+	}
+	switch input[len(input)-1] {
+	case 'm':
+		return time.Duration(n) * time.Minute, nil
+	case 'h':
+		return time.Duration(n) * time.Hour, nil
+	case 'd':
+		return time.Duration(n) * 24 * time.Hour, nil
+	case 'w':
+		return time.Duration(n) * 7 * 24 * time.Hour, nil
+	}
+	return 0, fmt.Errorf("unknown offset unit %q in %q (use m, h, d, or w)", string(input[len(input)-1]), input)
+}
+
+// ReminderHour is the local hour a date-only time resolves to in ParseTimeFrom
+const ReminderHour = 9
+
+// ParseTimeFrom resolves a point in time relative to now: an offset such as
+// "3d" or "2h" (see ParseOffset), an RFC 3339 timestamp, or any ParseDate
+// input, which resolves to ReminderHour local time on that day.
+func ParseTimeFrom(input string, now time.Time) (time.Time, error) {
+	if d, err := ParseOffset(input); err == nil {
+		return now.Add(d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, strings.TrimSpace(input)); err == nil {
+		return t, nil
+	}
+	date, err := ParseDateFrom(input, now)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unrecognized time %q (use e.g. 2h, 3d, friday, 2026-03-01)", input)
+	}
+	day, _ := time.ParseInLocation("2006-01-02", date, now.Location())
+	return day.Add(ReminderHour * time.Hour), nil
+}
@@ -228,3 +228,61 @@ func TestParseDate_UsesCurrentTime(t *testing.T) {
 		t.Errorf("ParseDate('today') = %q, want %q", result, expected)
 	}
 }
+
+func TestParseOffset(t *testing.T) {
+	tests := []struct {
+		input string
+		want  time.Duration
+	}{
+		{"30m", 30 * time.Minute},
+		{"2h", 2 * time.Hour},
+		{"3d", 72 * time.Hour},
+		{"+1w", 7 * 24 * time.Hour},
+		{" 1D ", 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		got, err := ParseOffset(tt.input)
+		if err != nil {
+			t.Errorf("ParseOffset(%q) error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseOffset(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "d", "3", "0d", "-2h", "3y", "soon"} {
+		if _, err := ParseOffset(bad); err == nil {
+			t.Errorf("ParseOffset(%q) should fail", bad)
+		}
+	}
+}
+
+func TestParseTimeFrom(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 30, 0, 0, time.UTC) // Tuesday
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		{"2h", now.Add(2 * time.Hour)},
+		{"+3d", now.AddDate(0, 0, 3)},
+		{"tomorrow", time.Date(2026, 3, 11, 9, 0, 0, 0, time.UTC)},
+		{"friday", time.Date(2026, 3, 13, 9, 0, 0, 0, time.UTC)},
+		{"2026-04-01", time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC)},
+		{"2026-03-12T08:15:00Z", time.Date(2026, 3, 12, 8, 15, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ParseTimeFrom(tt.input, now)
+		if err != nil {
+			t.Errorf("ParseTimeFrom(%q) error: %v", tt.input, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseTimeFrom(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+
+	if _, err := ParseTimeFrom("whenever", now); err == nil {
+		t.Error("ParseTimeFrom(whenever) should fail")
+	}
+}
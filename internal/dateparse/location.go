@@ -0,0 +1,34 @@
+package dateparse
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+var location atomic.Pointer[time.Location]
+
+// Use makes loc the timezone that decides which day it is for relative
+// dates, TDQ keywords and due dates; nil restores the machine's local
+// timezone. td calls it with the project's configured timezone, so a team
+// spread across timezones agrees on when an issue is overdue.
+func Use(loc *time.Location) {
+	location.Store(loc)
+}
+
+// Location returns the timezone set with Use, or time.Local
+func Location() *time.Location {
+	if loc := location.Load(); loc != nil {
+		return loc
+	}
+	return time.Local
+}
+
+// Now returns the current time in Location
+func Now() time.Time {
+	return time.Now().In(Location())
+}
+
+// Today returns the current date in Location as YYYY-MM-DD
+func Today() string {
+	return Now().Format("2006-01-02")
+}
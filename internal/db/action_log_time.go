@@ -1,11 +1,15 @@
 package db
 
-import "time"
+import (
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+)
 
 // actionLogTimestampNow returns the canonical action_log timestamp format:
 // UTC RFC3339Nano text for reliable SQLite lexicographic comparisons.
 func actionLogTimestampNow() string {
-	return formatActionLogTimestamp(time.Now())
+	return formatActionLogTimestamp(clock.Now())
 }
 
 func formatActionLogTimestamp(t time.Time) string {
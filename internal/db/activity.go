@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/marcus/td/internal/clock"
 	"github.com/marcus/td/internal/models"
 )
 
@@ -16,7 +17,7 @@ import (
 // AddLog adds a log entry to an issue
 func (db *DB) AddLog(log *models.Log) error {
 	return db.withWriteLock(func() error {
-		log.Timestamp = time.Now()
+		log.Timestamp = clock.Now()
 
 		id, err := generateLogID()
 		if err != nil {
@@ -199,7 +200,7 @@ func (db *DB) GetActiveSessions(since time.Time) ([]string, error) {
 // AddHandoff adds a handoff entry and logs it to action_log for sync/undo.
 func (db *DB) AddHandoff(handoff *models.Handoff) error {
 	return db.withWriteLock(func() error {
-		handoff.Timestamp = time.Now()
+		handoff.Timestamp = clock.Now()
 
 		doneJSON, _ := json.Marshal(handoff.Done)
 		remainingJSON, _ := json.Marshal(handoff.Remaining)
@@ -284,6 +285,43 @@ func (db *DB) GetLatestHandoff(issueID string) (*models.Handoff, error) {
 	return &handoff, nil
 }
 
+// GetHandoffs retrieves every handoff for an issue, oldest first
+func (db *DB) GetHandoffs(issueID string) ([]models.Handoff, error) {
+	rows, err := db.conn.Query(`
+		SELECT CAST(id AS TEXT), issue_id, session_id, done, remaining, decisions, uncertain, timestamp
+		FROM handoffs WHERE issue_id = ? ORDER BY timestamp
+	`, issueID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var handoffs []models.Handoff
+	for rows.Next() {
+		var h models.Handoff
+		var doneJSON, remainingJSON, decisionsJSON, uncertainJSON string
+		err := rows.Scan(&h.ID, &h.IssueID, &h.SessionID,
+			&doneJSON, &remainingJSON, &decisionsJSON, &uncertainJSON, &h.Timestamp)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan handoff row: %w", err)
+		}
+		if err := json.Unmarshal([]byte(doneJSON), &h.Done); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal done: %w", err)
+		}
+		if err := json.Unmarshal([]byte(remainingJSON), &h.Remaining); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal remaining: %w", err)
+		}
+		if err := json.Unmarshal([]byte(decisionsJSON), &h.Decisions); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal decisions: %w", err)
+		}
+		if err := json.Unmarshal([]byte(uncertainJSON), &h.Uncertain); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal uncertain: %w", err)
+		}
+		handoffs = append(handoffs, h)
+	}
+	return handoffs, rows.Err()
+}
+
 // DeleteHandoff removes a handoff by ID (for undo support)
 func (db *DB) DeleteHandoff(handoffID string) error {
 	return db.withWriteLock(func() error {
@@ -338,13 +376,19 @@ func (db *DB) GetRecentHandoffs(limit int, since time.Time) ([]models.Handoff, e
 // AddComment adds a comment to an issue
 func (db *DB) AddComment(comment *models.Comment) error {
 	return db.withWriteLock(func() error {
-		comment.CreatedAt = time.Now()
+		if err := db.checkClosedWrite(comment.IssueID, "comment on"); err != nil {
+			return err
+		}
+		comment.CreatedAt = clock.Now()
 
 		id, err := generateCommentID()
 		if err != nil {
 			return fmt.Errorf("generate ID: %w", err)
 		}
 		comment.ID = id
+		if comment.Text, err = db.sealCommentText(comment.IssueID, comment.Text, ""); err != nil {
+			return err
+		}
 
 		_, err = db.conn.Exec(`
 			INSERT INTO comments (id, issue_id, session_id, text, created_at)
@@ -454,6 +498,9 @@ func (db *DB) DeleteCommentLogged(commentID, sessionID string) error {
 		if err != nil {
 			return err
 		}
+		if err := db.checkClosedWrite(c.IssueID, "delete comments on"); err != nil {
+			return err
+		}
 
 		// Delete the comment
 		_, err = db.conn.Exec(`DELETE FROM comments WHERE id = ?`, commentID)
@@ -488,7 +535,7 @@ func (db *DB) DeleteCommentLogged(commentID, sessionID string) error {
 // LogAction records an action for undo support
 func (db *DB) LogAction(action *models.ActionLog) error {
 	return db.withWriteLock(func() error {
-		action.Timestamp = time.Now().UTC()
+		action.Timestamp = clock.Now().UTC()
 
 		id, err := generateActionID()
 		if err != nil {
@@ -619,7 +666,7 @@ func (db *DB) GetRecentActionsAll(limit int) ([]models.ActionLog, error) {
 }
 
 // MaxActionRowid returns the current maximum rowid in action_log, or 0 if empty.
-// Used by webhook dispatch to avoid timestamp-format-dependent comparisons.
+// Used by hook scripts to avoid timestamp-format-dependent comparisons.
 func (db *DB) MaxActionRowid() (int64, error) {
 	var rowid sql.NullInt64
 	err := db.conn.QueryRow(`SELECT MAX(rowid) FROM action_log`).Scan(&rowid)
@@ -635,7 +682,7 @@ func (db *DB) MaxActionRowid() (int64, error) {
 // GetActionsAfterRowid returns action_log entries with rowid > afterRowid
 // that have not been undone. Results are ordered oldest-first.
 //
-// This is used by webhook dispatch instead of GetActionsSince to avoid a
+// This is used by hook scripts instead of GetActionsSince to avoid a
 // timestamp format mismatch: the sidecar writes RFC3339 ("…T…Z") while
 // the modernc.org/sqlite driver serializes time.Time via Go's .String()
 // ("… …-0500 EST m=+…"). SQLite text comparison on these mixed formats
@@ -732,7 +779,7 @@ func (db *DB) GetRejectedInProgressIssueIDs() (map[string]bool, error) {
 // AddGitSnapshot records a git state snapshot
 func (db *DB) AddGitSnapshot(snapshot *models.GitSnapshot) error {
 	return db.withWriteLock(func() error {
-		snapshot.Timestamp = time.Now()
+		snapshot.Timestamp = clock.Now()
 
 		id, err := generateSnapshotID()
 		if err != nil {
@@ -741,9 +788,9 @@ func (db *DB) AddGitSnapshot(snapshot *models.GitSnapshot) error {
 		snapshot.ID = id
 
 		_, err = db.conn.Exec(`
-			INSERT INTO git_snapshots (id, issue_id, event, commit_sha, branch, dirty_files, timestamp)
-			VALUES (?, ?, ?, ?, ?, ?, ?)
-		`, snapshot.ID, snapshot.IssueID, snapshot.Event, snapshot.CommitSHA, snapshot.Branch, snapshot.DirtyFiles, snapshot.Timestamp)
+			INSERT INTO git_snapshots (id, issue_id, event, commit_sha, branch, repo, dirty_files, timestamp)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`, snapshot.ID, snapshot.IssueID, snapshot.Event, snapshot.CommitSHA, snapshot.Branch, snapshot.Repo, snapshot.DirtyFiles, snapshot.Timestamp)
 		if err != nil {
 			return err
 		}
@@ -757,11 +804,11 @@ func (db *DB) GetStartSnapshot(issueID string) (*models.GitSnapshot, error) {
 	var snapshot models.GitSnapshot
 
 	err := db.conn.QueryRow(`
-		SELECT CAST(id AS TEXT), issue_id, event, commit_sha, branch, dirty_files, timestamp
+		SELECT CAST(id AS TEXT), issue_id, event, commit_sha, branch, repo, dirty_files, timestamp
 		FROM git_snapshots WHERE issue_id = ? AND event = 'start' ORDER BY timestamp DESC LIMIT 1
 	`, issueID).Scan(
 		&snapshot.ID, &snapshot.IssueID, &snapshot.Event,
-		&snapshot.CommitSHA, &snapshot.Branch, &snapshot.DirtyFiles, &snapshot.Timestamp,
+		&snapshot.CommitSHA, &snapshot.Branch, &snapshot.Repo, &snapshot.DirtyFiles, &snapshot.Timestamp,
 	)
 
 	if err == sql.ErrNoRows {
@@ -773,3 +820,89 @@ func (db *DB) GetStartSnapshot(issueID string) (*models.GitSnapshot, error) {
 
 	return &snapshot, nil
 }
+
+// StatusTransition is a change of an issue's status recovered from the
+// action log
+type StatusTransition struct {
+	IssueID string
+	From    models.Status // empty when the issue was created
+	To      models.Status
+	At      time.Time
+}
+
+// GetStatusTransitions returns the status changes recorded in the action log
+// at or after since, oldest first. Undone actions are skipped.
+func (db *DB) GetStatusTransitions(since time.Time) ([]StatusTransition, error) {
+	// Ordered by rowid: timestamps are stored in mixed formats, see
+	// GetActionsAfterRowid
+	rows, err := db.conn.Query(`
+		SELECT entity_id, previous_data, new_data, timestamp
+		FROM action_log
+		WHERE entity_type = 'issue' AND undone = 0 AND new_data != ''
+		ORDER BY rowid ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []StatusTransition
+	for rows.Next() {
+		var id, prevData, newData string
+		var at time.Time
+		if err := rows.Scan(&id, &prevData, &newData, &at); err != nil {
+			return nil, err
+		}
+		if at.Before(since) {
+			continue
+		}
+		var prev, next struct {
+			Status models.Status `json:"status"`
+		}
+		if err := json.Unmarshal([]byte(newData), &next); err != nil || next.Status == "" {
+			continue
+		}
+		if prevData != "" {
+			_ = json.Unmarshal([]byte(prevData), &prev)
+		}
+		if prev.Status == next.Status {
+			continue
+		}
+		out = append(out, StatusTransition{IssueID: id, From: prev.Status, To: next.Status, At: at})
+	}
+	return out, rows.Err()
+}
+
+// ActivityEvent is one action from the action log, for activity reports
+type ActivityEvent struct {
+	SessionID  string
+	ActionType string
+	At         time.Time
+}
+
+// GetActivityEvents returns the actions recorded at or after since, oldest
+// first. Undone actions are skipped.
+func (db *DB) GetActivityEvents(since time.Time) ([]ActivityEvent, error) {
+	// Filtered here rather than in SQL: timestamps are stored in mixed
+	// formats, see GetActionsAfterRowid
+	rows, err := db.conn.Query(`
+		SELECT session_id, action_type, timestamp
+		FROM action_log
+		WHERE undone = 0
+		ORDER BY rowid ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []ActivityEvent
+	for rows.Next() {
+		var e ActivityEvent
+		if err := rows.Scan(&e.SessionID, &e.ActionType, &e.At); err != nil {
+			return nil, err
+		}
+		if !e.At.Before(since) {
+			out = append(out, e)
+		}
+	}
+	return out, rows.Err()
+}
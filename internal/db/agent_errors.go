@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"time"
+
+	"github.com/marcus/td/internal/clock"
 )
 
 const agentErrorsFile = ".todos/agent_errors.jsonl"
@@ -30,7 +32,7 @@ func LogAgentError(baseDir string, args []string, errMsg string, sessionID strin
 	}
 
 	entry := AgentError{
-		Timestamp: time.Now().UTC(),
+		Timestamp: clock.Now().UTC(),
 		Args:      args,
 		Error:     errMsg,
 		SessionID: sessionID,
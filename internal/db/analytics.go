@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/marcus/td/internal/clock"
 )
 
 const commandUsageFile = ".todos/command_usage.jsonl"
@@ -65,7 +67,7 @@ func LogCommandUsage(baseDir string, event CommandUsageEvent) error {
 	}
 
 	if event.Timestamp.IsZero() {
-		event.Timestamp = time.Now().UTC()
+		event.Timestamp = clock.Now().UTC()
 	}
 
 	data, err := json.Marshal(event)
@@ -5,8 +5,8 @@ import (
 	"fmt"
 	"sort"
 	"strings"
-	"time"
 
+	"github.com/marcus/td/internal/clock"
 	"github.com/marcus/td/internal/models"
 )
 
@@ -43,7 +43,7 @@ func (db *DB) CreateBoard(name, queryStr string) (*models.Board, error) {
 			return err
 		}
 
-		now := time.Now()
+		now := clock.Now()
 		board = &models.Board{
 			ID:        id,
 			Name:      name,
@@ -191,7 +191,7 @@ func (db *DB) UpdateBoard(board *models.Board) error {
 			}
 		}
 
-		board.UpdatedAt = time.Now()
+		board.UpdatedAt = clock.Now()
 		_, err = db.conn.Exec(`
 			UPDATE boards SET name = ?, query = ?, updated_at = ?
 			WHERE id = ?
@@ -220,7 +220,7 @@ func (db *DB) DeleteBoard(id string) error {
 		}
 
 		// Soft-delete positions first
-		_, err = db.conn.Exec(`UPDATE board_issue_positions SET deleted_at = ? WHERE board_id = ? AND deleted_at IS NULL`, time.Now().UTC(), id)
+		_, err = db.conn.Exec(`UPDATE board_issue_positions SET deleted_at = ? WHERE board_id = ? AND deleted_at IS NULL`, clock.Now().UTC(), id)
 		if err != nil {
 			return err
 		}
@@ -283,7 +283,7 @@ func (db *DB) GetLastViewedBoard() (*models.Board, error) {
 // UpdateBoardLastViewed updates the last_viewed_at timestamp for a board
 func (db *DB) UpdateBoardLastViewed(boardID string) error {
 	return db.withWriteLock(func() error {
-		now := time.Now()
+		now := clock.Now()
 		_, err := db.conn.Exec(`UPDATE boards SET last_viewed_at = ? WHERE id = ?`, now, boardID)
 		return err
 	})
@@ -296,7 +296,7 @@ func (db *DB) UpdateBoardViewMode(boardID, viewMode string) error {
 	}
 	return db.withWriteLock(func() error {
 		_, err := db.conn.Exec(`UPDATE boards SET view_mode = ?, updated_at = ? WHERE id = ?`,
-			viewMode, time.Now(), boardID)
+			viewMode, clock.Now(), boardID)
 		return err
 	})
 }
@@ -347,14 +347,14 @@ func (db *DB) SetIssuePosition(boardID, issueID string, position int) error {
 		if existing > 0 {
 			// Update existing row: set new position and clear deleted_at
 			_, err = tx.Exec(`UPDATE board_issue_positions SET position = ?, deleted_at = NULL, added_at = ? WHERE board_id = ? AND issue_id = ?`,
-				position, time.Now(), boardID, issueID)
+				position, clock.Now(), boardID, issueID)
 		} else {
 			// Insert new row
 			bipID := BoardIssuePosID(boardID, issueID)
 			_, err = tx.Exec(`
 				INSERT INTO board_issue_positions (id, board_id, issue_id, position, added_at)
 				VALUES (?, ?, ?, ?, ?)
-			`, bipID, boardID, issueID, position, time.Now())
+			`, bipID, boardID, issueID, position, clock.Now())
 		}
 		if err != nil {
 			return err
@@ -505,7 +505,7 @@ func (db *DB) RemoveIssuePosition(boardID, issueID string) error {
 	issueID = NormalizeIssueID(issueID)
 	return db.withWriteLock(func() error {
 		_, err := db.conn.Exec(`UPDATE board_issue_positions SET deleted_at = ? WHERE board_id = ? AND issue_id = ? AND deleted_at IS NULL`,
-			time.Now().UTC(), boardID, issueID)
+			clock.Now().UTC(), boardID, issueID)
 		return err
 	})
 }
@@ -3,8 +3,8 @@ package db
 import (
 	"encoding/json"
 	"fmt"
-	"time"
 
+	"github.com/marcus/td/internal/clock"
 	"github.com/marcus/td/internal/models"
 )
 
@@ -39,7 +39,7 @@ func (db *DB) CreateBoardLogged(name, queryStr, sessionID string) (*models.Board
 			return err
 		}
 
-		now := time.Now()
+		now := clock.Now()
 		board = &models.Board{
 			ID:        id,
 			Name:      name,
@@ -98,7 +98,7 @@ func (db *DB) UpdateBoardLogged(board *models.Board, sessionID string) error {
 			}
 		}
 
-		board.UpdatedAt = time.Now()
+		board.UpdatedAt = clock.Now()
 		_, err = db.conn.Exec(`
 			UPDATE boards SET name = ?, query = ?, updated_at = ?
 			WHERE id = ?
@@ -128,7 +128,7 @@ func (db *DB) UpdateBoardLogged(board *models.Board, sessionID string) error {
 func (db *DB) SetIssuePositionLogged(boardID, issueID string, position int, sessionID string) error {
 	issueID = NormalizeIssueID(issueID)
 	return db.withWriteLock(func() error {
-		now := time.Now()
+		now := clock.Now()
 		tx, err := db.conn.Begin()
 		if err != nil {
 			return err
@@ -180,7 +180,7 @@ func (db *DB) SetIssuePositionLogged(boardID, issueID string, position int, sess
 func (db *DB) RemoveIssuePositionLogged(boardID, issueID, sessionID string) error {
 	issueID = NormalizeIssueID(issueID)
 	return db.withWriteLock(func() error {
-		now := time.Now()
+		now := clock.Now()
 
 		// Read current position for PreviousData
 		var pos int
@@ -233,7 +233,7 @@ func (db *DB) DeleteBoardLogged(boardID, sessionID string) error {
 		previousData := marshalBoard(prev)
 
 		// Query active positions before soft-deleting them
-		now := time.Now()
+		now := clock.Now()
 		rows, err := db.conn.Query(`SELECT issue_id, position FROM board_issue_positions WHERE board_id = ? AND deleted_at IS NULL`, boardID)
 		if err != nil {
 			return err
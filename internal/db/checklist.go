@@ -0,0 +1,145 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/workflow"
+)
+
+// ChecklistError is returned when an approval does not acknowledge every
+// item of the issue's review checklist, or acknowledges one that is not on
+// it (Err)
+type ChecklistError struct {
+	IssueID string
+	Type    models.Type
+	Items   []string // the full checklist
+	Missing []string
+	Err     error
+}
+
+func (e *ChecklistError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("cannot approve %s: %v", e.IssueID, e.Err)
+	}
+	return fmt.Sprintf("cannot approve %s %s: review checklist not acknowledged: %s", e.Type, e.IssueID, strings.Join(e.Missing, ", "))
+}
+
+// ApproveIssueLogged is UpdateIssueLogged for an approval. When the project
+// configures a review checklist for the issue's type, acks must cover every
+// item (by text, 1-based number or "all") or the approval is refused with a
+// *ChecklistError; the acknowledged items are stored with the approval.
+// confirmed is passed to the anti-thrash guard as for
+// UpdateIssueLoggedConfirmed.
+func (db *DB) ApproveIssueLogged(issue *models.Issue, sessionID string, acks []string, confirmed bool) error {
+	ack, err := db.checkReviewChecklist(issue, sessionID, acks)
+	if err != nil {
+		return err
+	}
+	if err := db.checkTransitionPolicy(issue, sessionID); err != nil {
+		return err
+	}
+	return db.withWriteLock(func() error {
+		if err := db.checkParentLocked(issue); err != nil {
+			return err
+		}
+		if err := db.checkThrashLocked(issue, sessionID, confirmed); err != nil {
+			return err
+		}
+		if err := db.updateIssueAndLog(issue, sessionID, models.ActionApprove); err != nil {
+			return err
+		}
+		if ack == nil {
+			return nil
+		}
+		return db.insertReviewAckLocked(ack)
+	})
+}
+
+// checkReviewChecklist runs the review checklist guard for an approval and
+// returns the acknowledgment to store, or nil when no checklist applies
+func (db *DB) checkReviewChecklist(issue *models.Issue, sessionID string, acks []string) (*models.ReviewAck, error) {
+	lists, err := config.GetReviewChecklists(db.baseDir)
+	if err != nil {
+		slog.Debug("checklist: load config", "err", err)
+		return nil, nil
+	}
+	items := workflow.ChecklistFor(issue.Type, lists)
+	if len(items) == 0 {
+		return nil, nil
+	}
+	acked, err := workflow.ResolveAcks(items, acks)
+	if err != nil {
+		return nil, &ChecklistError{IssueID: issue.ID, Type: issue.Type, Items: items, Err: err}
+	}
+	guard := &workflow.ReviewChecklistGuard{Items: items}
+	res := guard.Check(&workflow.TransitionContext{
+		Issue:        issue,
+		ToStatus:     models.StatusClosed,
+		SessionID:    sessionID,
+		Acknowledged: acked,
+	})
+	if !res.Passed {
+		return nil, &ChecklistError{
+			IssueID: issue.ID,
+			Type:    issue.Type,
+			Items:   items,
+			Missing: workflow.UnacknowledgedItems(items, acked),
+		}
+	}
+	return &models.ReviewAck{IssueID: issue.ID, SessionID: sessionID, Items: acked}, nil
+}
+
+// insertReviewAckLocked stores a review acknowledgment, filling in ID and
+// CreatedAt. Like overrides, acknowledgments are local audit records.
+// Caller must hold the write lock.
+func (db *DB) insertReviewAckLocked(a *models.ReviewAck) error {
+	id, err := generateReviewAckID()
+	if err != nil {
+		return err
+	}
+	a.ID = id
+	a.IssueID = NormalizeIssueID(a.IssueID)
+	a.CreatedAt = clock.Now().UTC()
+	items, err := json.Marshal(a.Items)
+	if err != nil {
+		return err
+	}
+	_, err = db.conn.Exec(`INSERT INTO review_acks (id, issue_id, session_id, items, created_at) VALUES (?, ?, ?, ?, ?)`,
+		a.ID, a.IssueID, a.SessionID, string(items), a.CreatedAt.Format(time.RFC3339))
+	return err
+}
+
+// ListReviewAcks returns the review checklist acknowledgments stored with
+// an issue's approvals, newest first
+func (db *DB) ListReviewAcks(issueID string) ([]models.ReviewAck, error) {
+	rows, err := db.conn.Query(`
+		SELECT id, issue_id, session_id, items, created_at FROM review_acks
+		WHERE issue_id = ? ORDER BY created_at DESC, rowid DESC
+	`, NormalizeIssueID(issueID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var acks []models.ReviewAck
+	for rows.Next() {
+		var a models.ReviewAck
+		var items, createdAt string
+		if err := rows.Scan(&a.ID, &a.IssueID, &a.SessionID, &items, &createdAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(items), &a.Items); err != nil {
+			return nil, fmt.Errorf("review ack %s: %w", a.ID, err)
+		}
+		a.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		acks = append(acks, a)
+	}
+	return acks, rows.Err()
+}
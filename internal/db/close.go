@@ -0,0 +1,56 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/models"
+)
+
+// CheckSelfClose applies the td close rules to sessionID closing issue. A
+// session may close an issue it never touched, or one it created that
+// someone else implemented. Minor issues may be self-closed; minor is true
+// when the close relies on that, so the caller can record the override.
+func (db *DB) CheckSelfClose(issue *models.Issue, sessionID string) (minor bool, err error) {
+	involved, err := db.WasSessionInvolved(issue.ID, sessionID)
+	if err != nil {
+		involved = true // Conservative: assume involvement on error
+	}
+
+	isCreator := issue.CreatorSession != "" && issue.CreatorSession == sessionID
+	isImplementer := issue.ImplementerSession != "" && issue.ImplementerSession == sessionID
+	hasOtherImplementer := issue.ImplementerSession != "" && !isImplementer
+
+	switch {
+	case !involved && !isCreator && !isImplementer:
+		return false, nil
+	case isCreator && hasOtherImplementer:
+		return false, nil
+	case issue.Minor:
+		return true, nil
+	case isImplementer:
+		return false, fmt.Errorf("cannot close own implementation: %s", issue.ID)
+	case isCreator && !hasOtherImplementer:
+		return false, fmt.Errorf("cannot close: you created %s and no one else implemented it", issue.ID)
+	default:
+		return false, fmt.Errorf("cannot close: you previously worked on %s", issue.ID)
+	}
+}
+
+// CloseIssueLogged marks an issue closed now and logs the action. Callers
+// run CascadeClose once their own close log is written.
+func (db *DB) CloseIssueLogged(issue *models.Issue, sessionID string) error {
+	issue.Status = models.StatusClosed
+	now := clock.Now()
+	issue.ClosedAt = &now
+	return db.UpdateIssueLogged(issue, sessionID, models.ActionClose)
+}
+
+// CascadeClose runs the side effects of closing an issue: parents whose
+// children are now all closed are closed, and blocked dependents with no
+// open dependencies left are unblocked. It returns the IDs of both.
+func (db *DB) CascadeClose(issueID, sessionID string) (parents, unblocked []string) {
+	_, parents = db.CascadeUpParentStatus(issueID, models.StatusClosed, sessionID)
+	_, unblocked = db.CascadeUnblockDependents(issueID, sessionID)
+	return parents, unblocked
+}
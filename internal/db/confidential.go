@@ -0,0 +1,329 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/confidential"
+	"github.com/marcus/td/internal/models"
+)
+
+// sealIssue seals the description and acceptance criteria of a
+// confidential issue before it is written. Text that decrypts to what is
+// already stored keeps its stored ciphertext, so an unchanged field
+// doesn't look changed.
+func (db *DB) sealIssue(issue *models.Issue) error {
+	if !issue.Confidential || (sealedOrEmpty(issue.Description) && sealedOrEmpty(issue.Acceptance)) {
+		return nil
+	}
+	key, err := confidential.LoadKey(db.baseDir)
+	if err != nil {
+		return err
+	}
+	var storedDesc, storedAcc sql.NullString
+	_ = db.conn.QueryRow(`SELECT description, acceptance FROM issues WHERE id = ?`, issue.ID).Scan(&storedDesc, &storedAcc)
+	if issue.Description, err = reseal(key, issue.Description, storedDesc.String); err != nil {
+		return err
+	}
+	issue.Acceptance, err = reseal(key, issue.Acceptance, storedAcc.String)
+	return err
+}
+
+// sealCommentText seals the text of a comment on a confidential issue
+func (db *DB) sealCommentText(issueID, text, stored string) (string, error) {
+	if sealedOrEmpty(text) {
+		return text, nil
+	}
+	var isConfidential bool
+	err := db.conn.QueryRow(`SELECT COALESCE(confidential, 0) FROM issues WHERE id = ?`, issueID).Scan(&isConfidential)
+	if err != nil || !isConfidential {
+		return text, nil
+	}
+	key, err := confidential.LoadKey(db.baseDir)
+	if err != nil {
+		return "", err
+	}
+	return reseal(key, text, stored)
+}
+
+// redactedIssue copies issue with plaintext description and acceptance
+// criteria replaced by confidential.Redacted, for logging the state of an
+// issue that is about to be made confidential
+func redactedIssue(issue *models.Issue) *models.Issue {
+	c := *issue
+	c.Description, c.Acceptance = redactPlain(c.Description), redactPlain(c.Acceptance)
+	return &c
+}
+
+// redactPlain replaces text that isn't sealed with confidential.Redacted
+func redactPlain(s string) string {
+	if sealedOrEmpty(s) {
+		return s
+	}
+	return confidential.Redacted
+}
+
+func sealedOrEmpty(s string) bool {
+	return s == "" || confidential.IsSealed(s)
+}
+
+func reseal(key []byte, text, stored string) (string, error) {
+	if sealedOrEmpty(text) {
+		return text, nil
+	}
+	if confidential.IsSealed(stored) {
+		if plain, err := confidential.Open(key, stored); err == nil && plain == text {
+			return stored, nil
+		}
+	}
+	return confidential.Seal(key, text)
+}
+
+// GrantConfidential lets grantee read a confidential issue
+func (db *DB) GrantConfidential(issueID, grantee, grantedBy string) error {
+	return db.withWriteLock(func() error {
+		_, err := db.conn.Exec(`INSERT INTO confidential_grants (issue_id, grantee, granted_by, created_at)
+			VALUES (?, ?, ?, ?) ON CONFLICT(issue_id, grantee) DO NOTHING`,
+			issueID, grantee, grantedBy, clock.Now().UTC().Format(time.RFC3339))
+		return err
+	})
+}
+
+// RevokeConfidential removes a grant, reporting whether there was one
+func (db *DB) RevokeConfidential(issueID, grantee string) (bool, error) {
+	var removed bool
+	err := db.withWriteLock(func() error {
+		res, err := db.conn.Exec(`DELETE FROM confidential_grants WHERE issue_id = ? AND grantee = ?`, issueID, grantee)
+		if err != nil {
+			return err
+		}
+		n, _ := res.RowsAffected()
+		removed = n > 0
+		return nil
+	})
+	return removed, err
+}
+
+// ListConfidentialGrants returns the grants on an issue, oldest first
+func (db *DB) ListConfidentialGrants(issueID string) ([]models.ConfidentialGrant, error) {
+	rows, err := db.conn.Query(`SELECT issue_id, grantee, granted_by, created_at
+		FROM confidential_grants WHERE issue_id = ? ORDER BY created_at, grantee`, issueID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	grants := []models.ConfidentialGrant{}
+	for rows.Next() {
+		var g models.ConfidentialGrant
+		var created string
+		if err := rows.Scan(&g.IssueID, &g.Grantee, &g.GrantedBy, &created); err != nil {
+			return nil, err
+		}
+		g.CreatedAt, _ = time.Parse(time.RFC3339, created)
+		grants = append(grants, g)
+	}
+	return grants, rows.Err()
+}
+
+// ConfidentialViewer describes a session as a reader of confidential
+// issues, with the scopes of the API token it used, if any
+func (db *DB) ConfidentialViewer(sessionID string, roles ...string) confidential.Viewer {
+	v := confidential.Viewer{SessionID: sessionID, Roles: roles}
+	if row, err := db.GetSessionByID(sessionID); err == nil && row != nil {
+		v.SessionName = row.Name
+	}
+	return v
+}
+
+// CanReadConfidential reports whether v may read issue
+func (db *DB) CanReadConfidential(issue *models.Issue, v confidential.Viewer) (bool, error) {
+	if !issue.Confidential {
+		return true, nil
+	}
+	grants, err := db.ListConfidentialGrants(issue.ID)
+	if err != nil {
+		return false, err
+	}
+	return confidential.CanRead(issue, grants, v), nil
+}
+
+// RevealIssue prepares a confidential issue and its comments for v: the
+// text is decrypted when v may read it and the key is at hand, and
+// replaced with confidential.Redacted otherwise. It reports whether the
+// text was decrypted. Issues that aren't confidential are left alone.
+func (db *DB) RevealIssue(issue *models.Issue, comments []models.Comment, v confidential.Viewer) (bool, error) {
+	texts := []*string{&issue.Description, &issue.Acceptance}
+	for i := range comments {
+		texts = append(texts, &comments[i].Text)
+	}
+	return db.RevealText(issue, v, texts...)
+}
+
+// RevealText decrypts or redacts other text belonging to a confidential
+// issue, such as revisions, the way RevealIssue does. Viewers without
+// access get every non-empty text redacted, sealed or not.
+func (db *DB) RevealText(issue *models.Issue, v confidential.Viewer, texts ...*string) (bool, error) {
+	if !issue.Confidential {
+		return true, nil
+	}
+	allowed, err := db.CanReadConfidential(issue, v)
+	if err != nil {
+		return false, err
+	}
+	var key []byte
+	if allowed {
+		if key, err = confidential.LoadKey(db.baseDir); err != nil {
+			allowed = false
+		}
+	}
+	for _, t := range texts {
+		if !allowed {
+			// Older text, such as revisions from before the issue was
+			// made confidential, may not be sealed
+			if *t != "" {
+				*t = confidential.Redacted
+			}
+			continue
+		}
+		plain, err := confidential.Open(key, *t)
+		if err != nil {
+			plain = confidential.Redacted
+		}
+		*t = plain
+	}
+	return allowed, nil
+}
+
+// SetConfidential marks an issue confidential, sealing its text and its
+// existing comments, or clears the mark, decrypting them. Either way the
+// change is logged as an update by sessionID. When sealing, the logged
+// previous text is redacted so the plaintext never reaches the action log
+// or sync. The issue's revisions are sealed or decrypted along with it;
+// earlier versions of the text in the action log are not rewritten.
+func (db *DB) SetConfidential(issueID string, on bool, sessionID string) error {
+	issue, err := db.GetIssue(issueID)
+	if err != nil {
+		return err
+	}
+	if issue.Confidential == on {
+		return nil
+	}
+	key, err := confidential.LoadKey(db.baseDir)
+	if err != nil {
+		return err
+	}
+	previous, err := db.GetComments(issueID)
+	if err != nil {
+		return err
+	}
+	comments := append([]models.Comment(nil), previous...)
+
+	convert := func(s string) (string, error) {
+		if on {
+			return confidential.Seal(key, s)
+		}
+		return confidential.Open(key, s)
+	}
+	if issue.Description, err = convert(issue.Description); err != nil {
+		return err
+	}
+	if issue.Acceptance, err = convert(issue.Acceptance); err != nil {
+		return err
+	}
+	for i := range comments {
+		if comments[i].Text, err = convert(comments[i].Text); err != nil {
+			return fmt.Errorf("comment %s: %w", comments[i].ID, err)
+		}
+	}
+
+	issue.Confidential = on
+	if err := db.UpdateIssueLogged(issue, sessionID, models.ActionUpdate); err != nil {
+		return err
+	}
+	return db.withWriteLock(func() error {
+		if err := db.convertRevisionsLocked(issueID, convert); err != nil {
+			return err
+		}
+		for i, c := range comments {
+			if _, err := db.conn.Exec(`UPDATE comments SET text = ? WHERE id = ?`, c.Text, c.ID); err != nil {
+				return err
+			}
+			prev := previous[i]
+			if on {
+				prev.Text = redactPlain(prev.Text)
+			}
+			if err := db.logCommentUpdateLocked(prev, c.Text, sessionID); err != nil {
+				return err
+			}
+		}
+		if on {
+			_, err := db.conn.Exec(`INSERT INTO confidential_grants (issue_id, grantee, granted_by, created_at)
+				VALUES (?, ?, ?, ?) ON CONFLICT(issue_id, grantee) DO NOTHING`,
+				issueID, sessionID, sessionID, clock.Now().UTC().Format(time.RFC3339))
+			return err
+		}
+		return nil
+	})
+}
+
+// convertRevisionsLocked seals or decrypts the text kept in an issue's
+// revisions. Callers hold the write lock.
+func (db *DB) convertRevisionsLocked(issueID string, convert func(string) (string, error)) error {
+	rows, err := db.conn.Query(`SELECT id, before_text FROM revisions WHERE issue_id = ?`, issueID)
+	if err != nil {
+		return err
+	}
+	before := map[string]string{}
+	for rows.Next() {
+		var id, text string
+		if err := rows.Scan(&id, &text); err != nil {
+			rows.Close()
+			return err
+		}
+		before[id] = text
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for id, text := range before {
+		converted, err := convert(text)
+		if err != nil {
+			return fmt.Errorf("revision %s: %w", id, err)
+		}
+		if converted == text {
+			continue
+		}
+		if _, err := db.conn.Exec(`UPDATE revisions SET before_text = ? WHERE id = ?`, converted, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// logCommentUpdateLocked records a change of a comment's text in the
+// action log, so it syncs like any other edit
+func (db *DB) logCommentUpdateLocked(c models.Comment, text, sessionID string) error {
+	actionID, err := generateActionID()
+	if err != nil {
+		return fmt.Errorf("generate action ID: %w", err)
+	}
+	previousData, _ := json.Marshal(map[string]interface{}{
+		"id": c.ID, "issue_id": c.IssueID, "session_id": c.SessionID,
+		"text": c.Text, "created_at": c.CreatedAt,
+	})
+	newData, _ := json.Marshal(map[string]interface{}{
+		"id": c.ID, "issue_id": c.IssueID, "session_id": c.SessionID,
+		"text": text, "created_at": c.CreatedAt,
+	})
+	_, err = db.conn.Exec(`INSERT INTO action_log (id, session_id, action_type, entity_type, entity_id, previous_data, new_data, timestamp, undone) VALUES (?, ?, ?, ?, ?, ?, ?, ?, 0)`,
+		actionID, sessionID, "update", "comments", c.ID, string(previousData), string(newData), actionLogTimestampNow())
+	if err != nil {
+		return fmt.Errorf("log action: %w", err)
+	}
+	return nil
+}
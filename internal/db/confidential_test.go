@@ -0,0 +1,188 @@
+package db
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/marcus/td/internal/confidential"
+	"github.com/marcus/td/internal/models"
+)
+
+func TestConfidentialIssueSealing(t *testing.T) {
+	dir := t.TempDir()
+	database, err := Initialize(dir)
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+	t.Setenv(confidential.KeyEnv, "")
+	if _, _, err := confidential.EnsureKey(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	issue := &models.Issue{Title: "Rotate leaked credentials", Description: "the staging key leaked", Confidential: true}
+	if err := database.CreateIssueLogged(issue, "ses_owner"); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.AddComment(&models.Comment{IssueID: issue.ID, SessionID: "ses_owner", Text: "rotated it"}); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, _ := database.GetIssue(issue.ID)
+	comments, _ := database.GetComments(issue.ID)
+	if !confidential.IsSealed(stored.Description) || !confidential.IsSealed(comments[0].Text) {
+		t.Fatalf("stored text not sealed: %q, %q", stored.Description, comments[0].Text)
+	}
+	sealed := stored.Description
+
+	// Saving unchanged text keeps its ciphertext
+	stored.Title = "Rotate the leaked credentials"
+	if err := database.UpdateIssueLogged(stored, "ses_owner", models.ActionUpdate); err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := database.GetIssue(issue.ID); again.Description != sealed {
+		t.Error("unchanged description was resealed")
+	}
+
+	stranger := *stored
+	strangerComments := append([]models.Comment(nil), comments...)
+	if ok, err := database.RevealIssue(&stranger, strangerComments, database.ConfidentialViewer("ses_x")); err != nil || ok {
+		t.Fatalf("stranger reveal = %v, %v", ok, err)
+	}
+	if stranger.Description != confidential.Redacted || strangerComments[0].Text != confidential.Redacted {
+		t.Errorf("stranger sees %q, %q", stranger.Description, strangerComments[0].Text)
+	}
+
+	if err := database.GrantConfidential(issue.ID, "ses_x", "ses_owner"); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := database.RevealIssue(stored, comments, database.ConfidentialViewer("ses_x")); !ok ||
+		stored.Description != "the staging key leaked" || comments[0].Text != "rotated it" {
+		t.Errorf("granted reveal = %v: %q, %q", ok, stored.Description, comments[0].Text)
+	}
+	if removed, _ := database.RevokeConfidential(issue.ID, "ses_x"); !removed {
+		t.Error("RevokeConfidential removed nothing")
+	}
+
+	if err := database.SetConfidential(issue.ID, false, "ses_owner"); err != nil {
+		t.Fatal(err)
+	}
+	plain, _ := database.GetIssue(issue.ID)
+	comments, _ = database.GetComments(issue.ID)
+	if plain.Confidential || plain.Description != "the staging key leaked" || comments[0].Text != "rotated it" {
+		t.Errorf("after off: %v %q %q", plain.Confidential, plain.Description, comments[0].Text)
+	}
+
+	if err := database.SetConfidential(issue.ID, true, "ses_other"); err != nil {
+		t.Fatal(err)
+	}
+	resealed, _ := database.GetIssue(issue.ID)
+	grants, _ := database.ListConfidentialGrants(issue.ID)
+	if !confidential.IsSealed(resealed.Description) || len(grants) != 1 || grants[0].Grantee != "ses_other" {
+		t.Errorf("after on: %q, grants %v", resealed.Description, grants)
+	}
+}
+
+func TestSetConfidentialKeepsPlaintextOutOfLog(t *testing.T) {
+	dir := t.TempDir()
+	database, err := Initialize(dir)
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+	t.Setenv(confidential.KeyEnv, "")
+	if _, _, err := confidential.EnsureKey(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	issue := &models.Issue{Title: "Payroll export", Description: "salary spreadsheet lives in s3", Acceptance: "only finance can read it"}
+	if err := database.CreateIssue(issue); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.AddComment(&models.Comment{IssueID: issue.ID, SessionID: "ses_owner", Text: "bucket is payroll-2026"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.SetConfidential(issue.ID, true, "ses_owner"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The updates SetConfidential logged; the comment's creation predates it
+	rows, err := database.conn.Query(`SELECT previous_data, new_data FROM action_log WHERE action_type = 'update'`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	logged := 0
+	for rows.Next() {
+		var prev, next string
+		if err := rows.Scan(&prev, &next); err != nil {
+			t.Fatal(err)
+		}
+		for _, secret := range []string{"salary spreadsheet", "only finance", "payroll-2026"} {
+			if strings.Contains(prev, secret) || strings.Contains(next, secret) {
+				t.Errorf("action log carries %q: %s -> %s", secret, prev, next)
+			}
+		}
+		logged++
+	}
+	if logged != 2 {
+		t.Errorf("logged %d actions, want the issue and comment updates", logged)
+	}
+
+	revs, _ := database.ListRevisions(issue.ID)
+	for _, rev := range revs {
+		if strings.Contains(rev.Before, "salary spreadsheet") || strings.Contains(rev.Before, "only finance") {
+			t.Errorf("revision keeps plaintext %q", rev.Before)
+		}
+	}
+}
+
+func TestSetConfidentialSealsRevisions(t *testing.T) {
+	dir := t.TempDir()
+	database, err := Initialize(dir)
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+	t.Setenv(confidential.KeyEnv, "")
+	if _, _, err := confidential.EnsureKey(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	issue := &models.Issue{Title: "Vendor contract", Description: "SECRET PLAINTEXT"}
+	if err := database.CreateIssueLogged(issue, "ses_owner"); err != nil {
+		t.Fatal(err)
+	}
+	issue.Description = "rewritten"
+	if err := database.UpdateIssueLogged(issue, "ses_owner", models.ActionUpdate); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.SetConfidential(issue.ID, true, "ses_owner"); err != nil {
+		t.Fatal(err)
+	}
+
+	revs, _ := database.ListRevisions(issue.ID)
+	if len(revs) != 1 || !confidential.IsSealed(revs[0].Before) {
+		t.Fatalf("revisions = %+v, want the old description sealed", revs)
+	}
+	stored, _ := database.GetIssue(issue.ID)
+	if _, err := database.RevealText(stored, database.ConfidentialViewer("ses_x"), &revs[0].Before, &revs[0].After); err != nil {
+		t.Fatal(err)
+	}
+	if revs[0].Before != confidential.Redacted || revs[0].After != confidential.Redacted {
+		t.Errorf("stranger sees %q -> %q", revs[0].Before, revs[0].After)
+	}
+
+	revs, _ = database.ListRevisions(issue.ID)
+	if ok, _ := database.RevealText(stored, database.ConfidentialViewer("ses_owner"), &revs[0].Before); !ok || revs[0].Before != "SECRET PLAINTEXT" {
+		t.Errorf("owner sees %q", revs[0].Before)
+	}
+
+	// Clearing the mark decrypts them again
+	if err := database.SetConfidential(issue.ID, false, "ses_owner"); err != nil {
+		t.Fatal(err)
+	}
+	if revs, _ = database.ListRevisions(issue.ID); len(revs) != 1 || revs[0].Before != "SECRET PLAINTEXT" {
+		t.Errorf("after off = %+v", revs)
+	}
+}
@@ -24,6 +24,8 @@ const (
 type DB struct {
 	conn    *sql.DB
 	baseDir string
+
+	closedOverride bool // writes may edit closed issues (see WithClosedOverride)
 }
 
 // ResolveBaseDir checks for a .td-root file in the given directory.
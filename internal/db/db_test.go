@@ -3,6 +3,7 @@ package db
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/marcus/td/internal/models"
@@ -1998,3 +1999,51 @@ func TestCreateIssue_CollisionMaxRetries(t *testing.T) {
 		t.Errorf("Expected error %q, got %q", expectedErr, err.Error())
 	}
 }
+
+func TestIssuePrefix(t *testing.T) {
+	db, err := Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	defer db.Close()
+
+	before := &models.Issue{Title: "Created before the prefix change"}
+	if err := db.CreateIssue(before); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	if db.IssuePrefix() != "td-" || !strings.HasPrefix(before.ID, "td-") {
+		t.Fatalf("default prefix: IssuePrefix=%q, ID=%q", db.IssuePrefix(), before.ID)
+	}
+
+	for _, bad := range []string{"", "9web", "web_app", "averyverylongprefix"} {
+		if err := db.SetIssuePrefix(bad); err == nil {
+			t.Errorf("SetIssuePrefix(%q) should fail", bad)
+		}
+	}
+	if err := db.SetIssuePrefix("Web-"); err != nil {
+		t.Fatalf("SetIssuePrefix failed: %v", err)
+	}
+
+	after := &models.Issue{Title: "Created after the prefix change"}
+	if err := db.CreateIssue(after); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	if !strings.HasPrefix(after.ID, "web-") {
+		t.Errorf("ID = %q, want web- prefix", after.ID)
+	}
+
+	// Bare IDs resolve with the project prefix; prefixed IDs are left alone
+	bare := strings.TrimPrefix(after.ID, "web-")
+	if got, err := db.GetIssue(bare); err != nil || got.ID != after.ID {
+		t.Errorf("GetIssue(%q) = %v, %v", bare, got, err)
+	}
+	if got, err := db.GetIssue(before.ID); err != nil || got.ID != before.ID {
+		t.Errorf("GetIssue(%q) = %v, %v", before.ID, got, err)
+	}
+	if got := NormalizeIssueID("web-abc123"); got != "web-abc123" {
+		t.Errorf("NormalizeIssueID kept custom prefix: got %q", got)
+	}
+	if got := NormalizeIssueID("abc123"); got != "td-abc123" {
+		t.Errorf("NormalizeIssueID(bare) = %q", got)
+	}
+}
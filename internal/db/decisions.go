@@ -0,0 +1,207 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/models"
+)
+
+const decisionColumns = `id, title, context, options, decision, session_id, created_at, updated_at`
+
+// DecisionFilter narrows ListDecisions. Zero values match everything.
+type DecisionFilter struct {
+	IssueID string // only decisions linked to this issue
+	Search  string // substring of title, context or decision
+	Limit   int
+}
+
+// CreateDecision stores a decision and its issue links. ID, CreatedAt and
+// UpdatedAt are filled in. Decisions live in the project database and are
+// not written to the action log or synced.
+func (db *DB) CreateDecision(d *models.Decision) error {
+	return db.withWriteLock(func() error {
+		id, err := generateDecisionID()
+		if err != nil {
+			return err
+		}
+		d.ID = id
+		d.CreatedAt = clock.Now().UTC()
+		d.UpdatedAt = d.CreatedAt
+
+		options, _ := json.Marshal(nonNilStrings(d.Options))
+		_, err = db.conn.Exec(`INSERT INTO decisions (`+decisionColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			d.ID, d.Title, d.Context, string(options), d.Decision, d.SessionID,
+			d.CreatedAt.Format(time.RFC3339), d.UpdatedAt.Format(time.RFC3339))
+		if err != nil {
+			return err
+		}
+		return db.setDecisionIssuesLocked(d.ID, d.IssueIDs)
+	})
+}
+
+// GetDecision retrieves a decision, with its linked issues, by ID
+func (db *DB) GetDecision(id string) (*models.Decision, error) {
+	row := db.conn.QueryRow(`SELECT `+decisionColumns+` FROM decisions WHERE id = ?`, id)
+	d, err := scanDecision(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("decision not found: %s", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	d.IssueIDs, err = db.decisionIssueIDs(d.ID)
+	return d, err
+}
+
+// ListDecisions returns decisions newest first, with their linked issues
+func (db *DB) ListDecisions(filter DecisionFilter) ([]models.Decision, error) {
+	query := `SELECT ` + decisionColumns + ` FROM decisions`
+	var where []string
+	var args []interface{}
+	if filter.IssueID != "" {
+		where = append(where, "id IN (SELECT decision_id FROM decision_issues WHERE issue_id = ?)")
+		args = append(args, filter.IssueID)
+	}
+	if filter.Search != "" {
+		where = append(where, "(title LIKE ? OR context LIKE ? OR decision LIKE ?)")
+		pattern := "%" + filter.Search + "%"
+		args = append(args, pattern, pattern, pattern)
+	}
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += ` ORDER BY created_at DESC, id`
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	var decisions []models.Decision
+	for rows.Next() {
+		d, err := scanDecision(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		decisions = append(decisions, *d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range decisions {
+		if decisions[i].IssueIDs, err = db.decisionIssueIDs(decisions[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return decisions, nil
+}
+
+// UpdateDecision overwrites a decision's fields and replaces its issue
+// links. UpdatedAt is refreshed.
+func (db *DB) UpdateDecision(d *models.Decision) error {
+	return db.withWriteLock(func() error {
+		d.UpdatedAt = clock.Now().UTC()
+		options, _ := json.Marshal(nonNilStrings(d.Options))
+		res, err := db.conn.Exec(`UPDATE decisions SET title = ?, context = ?, options = ?, decision = ?, updated_at = ?
+			WHERE id = ?`,
+			d.Title, d.Context, string(options), d.Decision, d.UpdatedAt.Format(time.RFC3339), d.ID)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return fmt.Errorf("decision not found: %s", d.ID)
+		}
+		return db.setDecisionIssuesLocked(d.ID, d.IssueIDs)
+	})
+}
+
+// DeleteDecision removes a decision and its issue links
+func (db *DB) DeleteDecision(id string) error {
+	return db.withWriteLock(func() error {
+		res, err := db.conn.Exec(`DELETE FROM decisions WHERE id = ?`, id)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return fmt.Errorf("decision not found: %s", id)
+		}
+		_, err = db.conn.Exec(`DELETE FROM decision_issues WHERE decision_id = ?`, id)
+		return err
+	})
+}
+
+// setDecisionIssuesLocked replaces a decision's issue links. Caller must
+// hold the write lock.
+func (db *DB) setDecisionIssuesLocked(decisionID string, issueIDs []string) error {
+	if _, err := db.conn.Exec(`DELETE FROM decision_issues WHERE decision_id = ?`, decisionID); err != nil {
+		return err
+	}
+	for _, issueID := range issueIDs {
+		if _, err := db.conn.Exec(`INSERT OR IGNORE INTO decision_issues (decision_id, issue_id) VALUES (?, ?)`,
+			decisionID, issueID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decisionIssueIDs returns the issues linked to a decision, sorted by ID
+func (db *DB) decisionIssueIDs(decisionID string) ([]string, error) {
+	rows, err := db.conn.Query(`SELECT issue_id FROM decision_issues WHERE decision_id = ? ORDER BY issue_id`, decisionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// decisionScanner is satisfied by *sql.Row and *sql.Rows
+type decisionScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanDecision(row decisionScanner) (*models.Decision, error) {
+	var d models.Decision
+	var options, createdAt, updatedAt string
+
+	if err := row.Scan(&d.ID, &d.Title, &d.Context, &options, &d.Decision, &d.SessionID,
+		&createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+
+	if options != "" {
+		_ = json.Unmarshal([]byte(options), &d.Options)
+	}
+	d.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	d.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+	return &d, nil
+}
+
+// nonNilStrings returns s, or an empty slice when s is nil, so it
+// marshals as [] rather than null
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
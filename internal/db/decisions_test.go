@@ -0,0 +1,77 @@
+package db
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestDecisionsLifecycle(t *testing.T) {
+	database, err := Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+
+	queue := &models.Decision{
+		Title:    "Queue backend",
+		Context:  "Workers need durable jobs",
+		Options:  []string{"redis", "postgres"},
+		Decision: "postgres, we already run it",
+		IssueIDs: []string{"td-b", "td-a", "td-a"},
+	}
+	other := &models.Decision{Title: "Logging format", Decision: "slog JSON"}
+	for _, d := range []*models.Decision{queue, other} {
+		if err := database.CreateDecision(d); err != nil {
+			t.Fatalf("CreateDecision: %v", err)
+		}
+	}
+	if queue.ID == "" || queue.CreatedAt.IsZero() {
+		t.Fatalf("created decision = %+v", queue)
+	}
+
+	got, err := database.GetDecision(queue.ID)
+	if err != nil {
+		t.Fatalf("GetDecision: %v", err)
+	}
+	if !slices.Equal(got.Options, queue.Options) || !slices.Equal(got.IssueIDs, []string{"td-a", "td-b"}) {
+		t.Errorf("GetDecision = %+v", got)
+	}
+
+	byIssue, _ := database.ListDecisions(DecisionFilter{IssueID: "td-a"})
+	if len(byIssue) != 1 || byIssue[0].ID != queue.ID {
+		t.Errorf("ListDecisions by issue = %+v", byIssue)
+	}
+	if c, _ := database.CountIssueRelations("td-a"); c.Decisions != 1 {
+		t.Errorf("CountIssueRelations.Decisions = %d, want 1", c.Decisions)
+	}
+	if found, _ := database.ListDecisions(DecisionFilter{Search: "slog"}); len(found) != 1 || found[0].ID != other.ID {
+		t.Errorf("ListDecisions search = %+v", found)
+	}
+
+	got.IssueIDs = []string{"td-c"}
+	got.Options = nil
+	if err := database.UpdateDecision(got); err != nil {
+		t.Fatalf("UpdateDecision: %v", err)
+	}
+	if list, _ := database.ListDecisions(DecisionFilter{IssueID: "td-a"}); len(list) != 0 {
+		t.Errorf("td-a still linked after update: %+v", list)
+	}
+	if updated, _ := database.GetDecision(queue.ID); len(updated.Options) != 0 || !slices.Equal(updated.IssueIDs, []string{"td-c"}) {
+		t.Errorf("after update = %+v", updated)
+	}
+
+	if err := database.DeleteDecision(queue.ID); err != nil {
+		t.Fatalf("DeleteDecision: %v", err)
+	}
+	if _, err := database.GetDecision(queue.ID); err == nil {
+		t.Error("GetDecision after delete succeeded")
+	}
+	if err := database.DeleteDecision(queue.ID); err == nil {
+		t.Error("second DeleteDecision succeeded")
+	}
+	if list, _ := database.ListDecisions(DecisionFilter{IssueID: "td-c"}); len(list) != 0 {
+		t.Errorf("links left after delete: %+v", list)
+	}
+}
@@ -0,0 +1,305 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/models"
+)
+
+// PendingDeliveryEvents returns the changes recorded in delivery_events
+// that fan-out hasn't handled yet, oldest first, at most limit of them.
+// through is the last event covered, including events whose action was
+// undone or pruned since; pass it to EnqueueDeliveries to clear them all.
+func (db *DB) PendingDeliveryEvents(limit int) (actions []models.ActionLog, through int64, err error) {
+	rows, err := db.conn.Query(`
+		SELECT e.action_rowid, CAST(a.id AS TEXT), a.session_id, a.action_type, a.entity_type, a.entity_id,
+			a.previous_data, a.new_data, a.timestamp, a.undone
+		FROM delivery_events e
+		LEFT JOIN action_log a ON a.rowid = e.action_rowid
+		ORDER BY e.action_rowid ASC
+		LIMIT ?`, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rowid int64
+		var id, sessionID, actionType, entityType, entityID, prevData, newData sql.NullString
+		var ts sql.NullTime
+		var undone sql.NullInt64
+		if err := rows.Scan(&rowid, &id, &sessionID, &actionType, &entityType, &entityID, &prevData, &newData, &ts, &undone); err != nil {
+			return nil, 0, err
+		}
+		through = rowid
+		if !id.Valid || undone.Int64 == 1 {
+			continue
+		}
+		actions = append(actions, models.ActionLog{
+			ID:           id.String,
+			SessionID:    sessionID.String,
+			ActionType:   models.ActionType(actionType.String),
+			EntityType:   entityType.String,
+			EntityID:     entityID.String,
+			PreviousData: prevData.String,
+			NewData:      newData.String,
+			Timestamp:    ts.Time,
+		})
+	}
+	return actions, through, rows.Err()
+}
+
+// EnqueueDeliveries stores deliveries and clears the events up to through
+// in one transaction, so each change is handed to its targets exactly once.
+// IDs and times are filled in; deliveries are due at once.
+func (db *DB) EnqueueDeliveries(through int64, deliveries []models.Delivery) error {
+	return db.withWriteLock(func() error {
+		tx, err := db.conn.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		now := clock.Now().UTC()
+		for i := range deliveries {
+			if err := insertDelivery(tx, &deliveries[i], now); err != nil {
+				return err
+			}
+		}
+		if _, err := tx.Exec(`DELETE FROM delivery_events WHERE action_rowid <= ?`, through); err != nil {
+			return fmt.Errorf("clear delivery events: %w", err)
+		}
+		return tx.Commit()
+	})
+}
+
+// AddDelivery stores one delivery, due at once. ID and times are filled in.
+func (db *DB) AddDelivery(d *models.Delivery) error {
+	return db.withWriteLock(func() error {
+		tx, err := db.conn.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+		if err := insertDelivery(tx, d, clock.Now().UTC()); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+}
+
+func insertDelivery(tx *sql.Tx, d *models.Delivery, now time.Time) error {
+	id, err := generateDeliveryID()
+	if err != nil {
+		return err
+	}
+	d.ID = id
+	d.Status = models.DeliveryPending
+	d.CreatedAt = now
+	d.UpdatedAt = now
+	d.NextAttemptAt = &now
+	ts := now.Format(time.RFC3339)
+	_, err = tx.Exec(`INSERT INTO deliveries (id, kind, ref, target, payload, status, next_attempt_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		d.ID, d.Kind, d.Ref, d.Target, d.Payload, d.Status, ts, ts, ts)
+	if err != nil {
+		return fmt.Errorf("insert delivery: %w", err)
+	}
+	return nil
+}
+
+const deliveryColumns = `id, kind, ref, target, payload, status, attempts, last_error,
+	next_attempt_at, created_at, updated_at, delivered_at`
+
+func scanDelivery(scan func(dest ...any) error) (models.Delivery, error) {
+	var d models.Delivery
+	var next, delivered sql.NullString
+	var created, updated string
+	err := scan(&d.ID, &d.Kind, &d.Ref, &d.Target, &d.Payload, &d.Status, &d.Attempts, &d.LastError,
+		&next, &created, &updated, &delivered)
+	if err != nil {
+		return d, err
+	}
+	d.CreatedAt, _ = time.Parse(time.RFC3339, created)
+	d.UpdatedAt, _ = time.Parse(time.RFC3339, updated)
+	if next.Valid {
+		if t, err := time.Parse(time.RFC3339, next.String); err == nil {
+			d.NextAttemptAt = &t
+		}
+	}
+	if delivered.Valid {
+		if t, err := time.Parse(time.RFC3339, delivered.String); err == nil {
+			d.DeliveredAt = &t
+		}
+	}
+	return d, nil
+}
+
+func (db *DB) queryDeliveries(query string, args ...any) ([]models.Delivery, error) {
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []models.Delivery{}
+	for rows.Next() {
+		d, err := scanDelivery(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// DueDeliveries returns pending deliveries whose next attempt is due at
+// now, the longest waiting first
+func (db *DB) DueDeliveries(now time.Time, limit int) ([]models.Delivery, error) {
+	return db.queryDeliveries(`SELECT `+deliveryColumns+` FROM deliveries
+		WHERE status = ? AND next_attempt_at <= ?
+		ORDER BY next_attempt_at, created_at LIMIT ?`,
+		models.DeliveryPending, now.UTC().Format(time.RFC3339), limit)
+}
+
+// ListDeliveries returns deliveries newest first, only those with status
+// when it isn't empty. A limit of 0 returns all of them.
+func (db *DB) ListDeliveries(status string, limit int) ([]models.Delivery, error) {
+	query := `SELECT ` + deliveryColumns + ` FROM deliveries`
+	var args []any
+	if status != "" {
+		query += ` WHERE status = ?`
+		args = append(args, status)
+	}
+	query += ` ORDER BY created_at DESC, id`
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+	return db.queryDeliveries(query, args...)
+}
+
+// GetDelivery returns one delivery
+func (db *DB) GetDelivery(id string) (*models.Delivery, error) {
+	d, err := scanDelivery(db.conn.QueryRow(`SELECT `+deliveryColumns+` FROM deliveries WHERE id = ?`, id).Scan)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("delivery not found: %s", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// CountDeliveries returns how many deliveries have each status
+func (db *DB) CountDeliveries() (map[string]int, error) {
+	counts := map[string]int{models.DeliveryPending: 0, models.DeliveryDelivered: 0, models.DeliveryFailed: 0}
+	rows, err := db.conn.Query(`SELECT status, COUNT(*) FROM deliveries GROUP BY status`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var status string
+		var n int
+		if err := rows.Scan(&status, &n); err != nil {
+			return nil, err
+		}
+		counts[status] = n
+	}
+	return counts, rows.Err()
+}
+
+// ClaimDelivery starts an attempt at a due delivery: it counts the attempt
+// and holds the delivery until leaseUntil, so another process sending at
+// the same time skips it, and a process that dies mid-send leaves it to be
+// retried after the lease. It reports false when the delivery is no longer
+// due, because someone else claimed it first.
+func (db *DB) ClaimDelivery(id string, now, leaseUntil time.Time) (bool, error) {
+	var claimed bool
+	err := db.withWriteLock(func() error {
+		res, err := db.conn.Exec(`UPDATE deliveries SET attempts = attempts + 1, next_attempt_at = ?, updated_at = ?
+			WHERE id = ? AND status = ? AND next_attempt_at <= ?`,
+			leaseUntil.UTC().Format(time.RFC3339), now.UTC().Format(time.RFC3339),
+			id, models.DeliveryPending, now.UTC().Format(time.RFC3339))
+		if err != nil {
+			return err
+		}
+		n, _ := res.RowsAffected()
+		claimed = n == 1
+		return nil
+	})
+	return claimed, err
+}
+
+// FinishDelivery records how an attempt went. With sendErr nil the
+// delivery is delivered; otherwise it is retried at next, or failed for
+// good when next is nil.
+func (db *DB) FinishDelivery(id string, sendErr error, next *time.Time) error {
+	now := clock.Now().UTC().Format(time.RFC3339)
+	return db.withWriteLock(func() error {
+		var err error
+		switch {
+		case sendErr == nil:
+			_, err = db.conn.Exec(`UPDATE deliveries SET status = ?, last_error = '', next_attempt_at = NULL,
+				delivered_at = ?, updated_at = ? WHERE id = ?`,
+				models.DeliveryDelivered, now, now, id)
+		case next == nil:
+			_, err = db.conn.Exec(`UPDATE deliveries SET status = ?, last_error = ?, next_attempt_at = NULL,
+				updated_at = ? WHERE id = ?`,
+				models.DeliveryFailed, sendErr.Error(), now, id)
+		default:
+			_, err = db.conn.Exec(`UPDATE deliveries SET status = ?, last_error = ?, next_attempt_at = ?,
+				updated_at = ? WHERE id = ?`,
+				models.DeliveryPending, sendErr.Error(), next.UTC().Format(time.RFC3339), now, id)
+		}
+		return err
+	})
+}
+
+// RetryDelivery makes a failed or pending delivery due now with a fresh
+// set of attempts. Delivered ones are left alone.
+func (db *DB) RetryDelivery(id string) (*models.Delivery, error) {
+	now := clock.Now().UTC().Format(time.RFC3339)
+	err := db.withWriteLock(func() error {
+		res, err := db.conn.Exec(`UPDATE deliveries SET status = ?, attempts = 0, next_attempt_at = ?, updated_at = ?
+			WHERE id = ? AND status != ?`,
+			models.DeliveryPending, now, now, id, models.DeliveryDelivered)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			d, err := db.GetDelivery(id)
+			if err != nil {
+				return err
+			}
+			return fmt.Errorf("delivery %s was already delivered", d.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return db.GetDelivery(id)
+}
+
+// PruneDeliveries deletes deliveries delivered before deliveredBefore and
+// failures last tried before failedBefore, returning how many went
+func (db *DB) PruneDeliveries(deliveredBefore, failedBefore time.Time) (int64, error) {
+	var n int64
+	err := db.withWriteLock(func() error {
+		res, err := db.conn.Exec(`DELETE FROM deliveries
+			WHERE (status = ? AND updated_at < ?) OR (status = ? AND updated_at < ?)`,
+			models.DeliveryDelivered, deliveredBefore.UTC().Format(time.RFC3339),
+			models.DeliveryFailed, failedBefore.UTC().Format(time.RFC3339))
+		if err != nil {
+			return err
+		}
+		n, _ = res.RowsAffected()
+		return nil
+	})
+	return n, err
+}
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestDeliveryEventsAndClaims(t *testing.T) {
+	database, err := Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+
+	for _, title := range []string{"first", "second"} {
+		if err := database.CreateIssueLogged(&models.Issue{Title: title}, "ses_a"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	last, err := database.GetLastAction("ses_a")
+	if err != nil || last == nil {
+		t.Fatalf("GetLastAction = %v, %v", last, err)
+	}
+	if err := database.MarkActionUndone(last.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// The trigger recorded both actions; the undone one is skipped but
+	// still covered by through
+	actions, through, err := database.PendingDeliveryEvents(10)
+	if err != nil || len(actions) != 1 || through == 0 {
+		t.Fatalf("PendingDeliveryEvents = %v, %d, %v", actions, through, err)
+	}
+
+	d := models.Delivery{Kind: "webhook", Target: "https://example.com/hook", Payload: "{}"}
+	if err := database.EnqueueDeliveries(through, []models.Delivery{d}); err != nil {
+		t.Fatalf("EnqueueDeliveries: %v", err)
+	}
+	if actions, through, _ := database.PendingDeliveryEvents(10); len(actions) != 0 || through != 0 {
+		t.Errorf("events left = %v, %d", actions, through)
+	}
+
+	now := time.Now()
+	due, err := database.DueDeliveries(now, 10)
+	if err != nil || len(due) != 1 {
+		t.Fatalf("DueDeliveries = %v, %v", due, err)
+	}
+	id := due[0].ID
+
+	ok, err := database.ClaimDelivery(id, now, now.Add(time.Minute))
+	if err != nil || !ok {
+		t.Fatalf("first claim = %v, %v", ok, err)
+	}
+	if ok, _ := database.ClaimDelivery(id, now, now.Add(time.Minute)); ok {
+		t.Error("second claim succeeded while the lease was held")
+	}
+	// A lease that runs out makes the delivery due again
+	later := now.Add(2 * time.Minute)
+	if ok, _ := database.ClaimDelivery(id, later, later.Add(time.Minute)); !ok {
+		t.Error("claim after the lease expired failed")
+	}
+
+	if err := database.FinishDelivery(id, errors.New("503"), nil); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := database.GetDelivery(id)
+	if got.Status != models.DeliveryFailed || got.Attempts != 2 || got.LastError != "503" {
+		t.Errorf("after failure = %+v", got)
+	}
+	counts, _ := database.CountDeliveries()
+	if counts[models.DeliveryFailed] != 1 || counts[models.DeliveryPending] != 0 {
+		t.Errorf("counts = %v", counts)
+	}
+
+	if n, err := database.PruneDeliveries(later, later.Add(time.Hour)); err != nil || n != 1 {
+		t.Errorf("PruneDeliveries = %d, %v", n, err)
+	}
+	if _, err := database.GetDelivery(id); err == nil {
+		t.Error("pruned delivery still found")
+	}
+}
@@ -0,0 +1,135 @@
+package db
+
+import (
+	"sort"
+	"time"
+
+	"github.com/marcus/td/internal/models"
+)
+
+// EffortFilter narrows ListIssueEfforts. Zero fields match everything.
+type EffortFilter struct {
+	IssueID   string
+	SessionID string    // only issues this session logged on
+	Since     time.Time // only logs at or after this time
+}
+
+// ListIssueEfforts breaks down the work logged on each live issue by agent
+// session and work session, issues with the most contributors first. Like
+// GetLogs, an issue's logs include work session logs from the work
+// sessions it is tagged in.
+func (db *DB) ListIssueEfforts(f EffortFilter) ([]models.IssueEffort, error) {
+	issueID := NormalizeIssueID(f.IssueID)
+	rows, err := db.conn.Query(`
+		SELECT t.issue_id, i.title, i.status, t.session_id, COALESCE(s.name, ''), COALESCE(s.agent_type, ''),
+			COALESCE(t.work_session_id, ''), t.timestamp
+		FROM (
+			SELECT issue_id, session_id, work_session_id, timestamp FROM logs WHERE issue_id != ''
+			UNION ALL
+			SELECT wsi.issue_id, l.session_id, l.work_session_id, l.timestamp
+			FROM logs l JOIN work_session_issues wsi ON wsi.work_session_id = l.work_session_id
+			WHERE l.issue_id = ''
+		) t
+		JOIN issues i ON i.id = t.issue_id AND i.deleted_at IS NULL
+		LEFT JOIN sessions s ON s.id = t.session_id
+		WHERE ?1 = '' OR t.issue_id = ?1
+		ORDER BY t.timestamp
+	`, issueID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type tally struct {
+		effort       models.IssueEffort
+		workSessions map[string]bool
+		bySession    map[string]*models.Contribution
+		sessionWS    map[string]map[string]bool
+		order        []string
+	}
+	byIssue := make(map[string]*tally)
+	var order []string
+	for rows.Next() {
+		var id, title, status, sessionID, name, agent, ws string
+		var ts time.Time
+		if err := rows.Scan(&id, &title, &status, &sessionID, &name, &agent, &ws, &ts); err != nil {
+			return nil, err
+		}
+		if !f.Since.IsZero() && ts.Before(f.Since) {
+			continue
+		}
+		t := byIssue[id]
+		if t == nil {
+			t = &tally{
+				effort:       models.IssueEffort{IssueID: id, Title: title, Status: models.Status(status)},
+				workSessions: make(map[string]bool),
+				bySession:    make(map[string]*models.Contribution),
+				sessionWS:    make(map[string]map[string]bool),
+			}
+			byIssue[id] = t
+			order = append(order, id)
+		}
+		c := t.bySession[sessionID]
+		if c == nil {
+			c = &models.Contribution{SessionID: sessionID, SessionName: name, AgentType: agent, FirstAt: ts}
+			t.bySession[sessionID] = c
+			t.sessionWS[sessionID] = make(map[string]bool)
+			t.order = append(t.order, sessionID)
+		}
+		c.Logs++
+		c.LastAt = ts
+		t.effort.Logs++
+		if ws != "" {
+			t.workSessions[ws] = true
+			t.sessionWS[sessionID][ws] = true
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var efforts []models.IssueEffort
+	for _, id := range order {
+		t := byIssue[id]
+		if f.SessionID != "" && t.bySession[f.SessionID] == nil {
+			continue
+		}
+		t.effort.WorkSessions = len(t.workSessions)
+		t.effort.Contributors = make([]models.Contribution, 0, len(t.order))
+		for _, sid := range t.order {
+			c := t.bySession[sid]
+			c.WorkSessions = len(t.sessionWS[sid])
+			t.effort.Contributors = append(t.effort.Contributors, *c)
+		}
+		// Heaviest contributors first, earliest first among equals
+		sort.SliceStable(t.effort.Contributors, func(a, b int) bool {
+			return t.effort.Contributors[a].Logs > t.effort.Contributors[b].Logs
+		})
+		efforts = append(efforts, t.effort)
+	}
+
+	sort.SliceStable(efforts, func(a, b int) bool {
+		ea, eb := efforts[a], efforts[b]
+		if len(ea.Contributors) != len(eb.Contributors) {
+			return len(ea.Contributors) > len(eb.Contributors)
+		}
+		if ea.WorkSessions != eb.WorkSessions {
+			return ea.WorkSessions > eb.WorkSessions
+		}
+		return ea.Logs > eb.Logs
+	})
+	return efforts, nil
+}
+
+// GetIssueEffort returns the effort breakdown for one issue. An issue
+// nobody has logged on gets an empty breakdown.
+func (db *DB) GetIssueEffort(issue *models.Issue) (*models.IssueEffort, error) {
+	efforts, err := db.ListIssueEfforts(EffortFilter{IssueID: issue.ID})
+	if err != nil {
+		return nil, err
+	}
+	if len(efforts) == 0 {
+		return &models.IssueEffort{IssueID: issue.ID, Title: issue.Title, Status: issue.Status, Contributors: []models.Contribution{}}, nil
+	}
+	return &efforts[0], nil
+}
@@ -0,0 +1,72 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestListIssueEfforts(t *testing.T) {
+	database, err := Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	defer database.Close()
+
+	busy := &models.Issue{Title: "Retry failed payments"}
+	quiet := &models.Issue{Title: "Show payment retries"}
+	for _, issue := range []*models.Issue{busy, quiet} {
+		if err := database.CreateIssue(issue); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ws := &models.WorkSession{Name: "payments", SessionID: "ses_a"}
+	if err := database.CreateWorkSession(ws); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.TagIssueToWorkSession(ws.ID, busy.ID, "ses_a"); err != nil {
+		t.Fatal(err)
+	}
+
+	addLog := func(issueID, sessionID, workSessionID string) {
+		t.Helper()
+		if err := database.AddLog(&models.Log{IssueID: issueID, SessionID: sessionID, WorkSessionID: workSessionID, Message: "progress"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	addLog(busy.ID, "ses_a", ws.ID)
+	addLog(busy.ID, "ses_a", "")
+	addLog("", "ses_a", ws.ID) // work session log, tagged to busy
+	addLog(busy.ID, "ses_b", "ws-other")
+	addLog(quiet.ID, "ses_b", "")
+
+	efforts, err := database.ListIssueEfforts(EffortFilter{})
+	if err != nil {
+		t.Fatalf("ListIssueEfforts: %v", err)
+	}
+	if len(efforts) != 2 || efforts[0].IssueID != busy.ID {
+		t.Fatalf("efforts = %+v, want %s first", efforts, busy.ID)
+	}
+	got := efforts[0]
+	if got.Logs != 4 || got.WorkSessions != 2 || len(got.Contributors) != 2 {
+		t.Errorf("effort = %d logs, %d work sessions, %d contributors; want 4, 2, 2", got.Logs, got.WorkSessions, len(got.Contributors))
+	}
+	if c := got.Contributors[0]; c.SessionID != "ses_a" || c.Logs != 3 || c.WorkSessions != 1 {
+		t.Errorf("top contributor = %+v, want ses_a with 3 logs in 1 work session", c)
+	}
+
+	efforts, _ = database.ListIssueEfforts(EffortFilter{SessionID: "ses_a"})
+	if len(efforts) != 1 || efforts[0].IssueID != busy.ID {
+		t.Errorf("ses_a efforts = %+v, want only %s", efforts, busy.ID)
+	}
+	efforts, _ = database.ListIssueEfforts(EffortFilter{Since: time.Now().Add(time.Hour)})
+	if len(efforts) != 0 {
+		t.Errorf("efforts since the future = %+v, want none", efforts)
+	}
+
+	effort, err := database.GetIssueEffort(quiet)
+	if err != nil || effort.Logs != 1 || effort.Contributors[0].SessionID != "ses_b" {
+		t.Errorf("GetIssueEffort = %+v, %v", effort, err)
+	}
+}
@@ -0,0 +1,281 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/models"
+)
+
+const externalDependencyColumns = `id, issue_id, url, provider, state, detail, last_error, checked_at, satisfied_at, session_id, created_at`
+
+// ErrExternalExists is returned for adding an external dependency an issue
+// already has
+var ErrExternalExists = errors.New("external dependency already exists")
+
+// AddExternalDependency makes an issue wait on an external URL. An open or
+// in-progress issue is blocked on it (reason external, ref the URL).
+// External dependencies are local to the project database and not synced;
+// blocking and unblocking the issue are. Reports whether the issue was
+// blocked.
+func (db *DB) AddExternalDependency(issueID, url, provider, sessionID string) (*models.ExternalDependency, bool, error) {
+	issueID = NormalizeIssueID(issueID)
+	dep := &models.ExternalDependency{
+		IssueID:   issueID,
+		URL:       strings.TrimSpace(url),
+		Provider:  provider,
+		State:     models.ExternalUnsatisfied,
+		SessionID: sessionID,
+		CreatedAt: clock.Now().UTC(),
+	}
+	blocked := false
+	err := db.withWriteLock(func() error {
+		issue, err := db.scanIssueRow(issueID)
+		if err != nil {
+			return err
+		}
+		var exists int
+		if err := db.conn.QueryRow(`SELECT COUNT(*) FROM external_dependencies WHERE issue_id = ? AND url = ?`,
+			issueID, dep.URL).Scan(&exists); err != nil {
+			return err
+		}
+		if exists > 0 {
+			return fmt.Errorf("%w: %s already waits on %s", ErrExternalExists, issueID, dep.URL)
+		}
+
+		if dep.ID, err = generateExternalID(); err != nil {
+			return err
+		}
+		if _, err := db.conn.Exec(`INSERT INTO external_dependencies (id, issue_id, url, provider, state, session_id, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			dep.ID, issueID, dep.URL, dep.Provider, string(dep.State), sessionID, dep.CreatedAt.Format(time.RFC3339)); err != nil {
+			return err
+		}
+
+		if issue.Status != models.StatusOpen && issue.Status != models.StatusInProgress {
+			return nil
+		}
+		issue.Status = models.StatusBlocked
+		issue.BlockedReason = models.BlockedReasonExternal
+		issue.BlockedRef = dep.URL
+		if err := db.updateIssueAndLog(issue, sessionID, models.ActionBlock); err != nil {
+			return err
+		}
+		blocked = true
+		return db.addLogEntry(issueID, sessionID, "Blocked: waiting on "+dep.URL, models.LogTypeBlocker)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return dep, blocked, nil
+}
+
+// GetExternalDependency fetches an external dependency by ID
+func (db *DB) GetExternalDependency(id string) (*models.ExternalDependency, error) {
+	rows, err := db.conn.Query(`SELECT `+externalDependencyColumns+` FROM external_dependencies WHERE id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	deps, err := scanExternalDependencies(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(deps) == 0 {
+		return nil, fmt.Errorf("external dependency not found: %s", id)
+	}
+	return &deps[0], nil
+}
+
+// ListExternalDependencies returns an issue's external dependencies,
+// oldest first
+func (db *DB) ListExternalDependencies(issueID string) ([]models.ExternalDependency, error) {
+	rows, err := db.conn.Query(`SELECT `+externalDependencyColumns+` FROM external_dependencies
+		WHERE issue_id = ? ORDER BY created_at, id`, NormalizeIssueID(issueID))
+	if err != nil {
+		return nil, err
+	}
+	return scanExternalDependencies(rows)
+}
+
+// ListUnsatisfiedExternalDependencies returns the external dependencies
+// still waited on by issues that are neither closed nor deleted, least
+// recently checked first
+func (db *DB) ListUnsatisfiedExternalDependencies() ([]models.ExternalDependency, error) {
+	rows, err := db.conn.Query(`SELECT e.id, e.issue_id, e.url, e.provider, e.state, e.detail, e.last_error,
+		       e.checked_at, e.satisfied_at, e.session_id, e.created_at
+		FROM external_dependencies e
+		JOIN issues i ON i.id = e.issue_id
+		WHERE e.state = ? AND i.status != ? AND i.deleted_at IS NULL
+		ORDER BY COALESCE(e.checked_at, ''), e.created_at, e.id`,
+		string(models.ExternalUnsatisfied), string(models.StatusClosed))
+	if err != nil {
+		return nil, err
+	}
+	return scanExternalDependencies(rows)
+}
+
+// RemoveExternalDependency removes an issue's external dependency named by
+// ID or URL and unblocks the issue if nothing else holds it. Reports
+// whether the issue was unblocked.
+func (db *DB) RemoveExternalDependency(issueID, ref, sessionID string) (*models.ExternalDependency, bool, error) {
+	issueID = NormalizeIssueID(issueID)
+	ref = strings.TrimSpace(ref)
+	deps, err := db.ListExternalDependencies(issueID)
+	if err != nil {
+		return nil, false, err
+	}
+	var removed *models.ExternalDependency
+	for _, dep := range deps {
+		if dep.ID == ref || dep.URL == ref {
+			d := dep
+			removed = &d
+			break
+		}
+	}
+	if removed == nil {
+		return nil, false, fmt.Errorf("external dependency not found: %s on %s", ref, issueID)
+	}
+
+	unblocked := false
+	err = db.withWriteLock(func() error {
+		if _, err := db.conn.Exec(`DELETE FROM external_dependencies WHERE id = ?`, removed.ID); err != nil {
+			return err
+		}
+		var err error
+		unblocked, err = db.unblockIfExternalResolvedLocked(issueID, sessionID, removed.URL, "Unblocked (no longer waiting on "+removed.URL+")")
+		if err != nil || unblocked {
+			return err
+		}
+		return db.moveExternalBlockLocked(issueID, removed.URL, sessionID)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return removed, unblocked, nil
+}
+
+// moveExternalBlockLocked points an issue still blocked on a removed URL
+// at another of its unsatisfied external dependencies, so the poller can
+// still unblock it
+func (db *DB) moveExternalBlockLocked(issueID, removedURL, sessionID string) error {
+	issue, err := db.scanIssueRow(issueID)
+	if err != nil {
+		return err
+	}
+	if issue.Status != models.StatusBlocked || issue.BlockedReason != models.BlockedReasonExternal || issue.BlockedRef != removedURL {
+		return nil
+	}
+	var next string
+	err = db.conn.QueryRow(`SELECT url FROM external_dependencies WHERE issue_id = ? AND state = ? ORDER BY created_at, id LIMIT 1`,
+		issueID, string(models.ExternalUnsatisfied)).Scan(&next)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	issue.BlockedRef = next
+	return db.updateIssueAndLog(issue, sessionID, models.ActionUpdate)
+}
+
+// RecordExternalCheck stores the outcome of polling an external dependency.
+// A failed check keeps the last known state and records the error.
+func (db *DB) RecordExternalCheck(id string, satisfied bool, detail, checkErr string) error {
+	now := clock.Now().UTC().Format(time.RFC3339)
+	return db.withWriteLock(func() error {
+		if checkErr != "" {
+			_, err := db.conn.Exec(`UPDATE external_dependencies SET last_error = ?, checked_at = ? WHERE id = ?`,
+				checkErr, now, id)
+			return err
+		}
+		state := models.ExternalUnsatisfied
+		if satisfied {
+			state = models.ExternalSatisfied
+		}
+		_, err := db.conn.Exec(`UPDATE external_dependencies
+			SET state = ?, detail = ?, last_error = '', checked_at = ?,
+			    satisfied_at = CASE WHEN ? THEN COALESCE(satisfied_at, ?) ELSE NULL END
+			WHERE id = ?`,
+			string(state), detail, now, satisfied, now, id)
+		return err
+	})
+}
+
+// UnblockIfExternalResolved reopens a blocked issue once every external
+// dependency is satisfied and every issue it depends on is closed;
+// resolvedURL is the dependency that just resolved. Issues blocked on a
+// decision, or on an external ref td doesn't poll, stay blocked. Reports
+// whether it was unblocked.
+func (db *DB) UnblockIfExternalResolved(issueID, sessionID, resolvedURL, message string) (bool, error) {
+	var unblocked bool
+	err := db.withWriteLock(func() error {
+		var err error
+		unblocked, err = db.unblockIfExternalResolvedLocked(NormalizeIssueID(issueID), sessionID, resolvedURL, message)
+		return err
+	})
+	return unblocked, err
+}
+
+// unblockIfExternalResolvedLocked is UnblockIfExternalResolved with the
+// write lock held
+func (db *DB) unblockIfExternalResolvedLocked(issueID, sessionID, resolvedURL, message string) (bool, error) {
+	issue, err := db.scanIssueRow(issueID)
+	if err != nil {
+		return false, err
+	}
+	if issue.Status != models.StatusBlocked || issue.BlockedReason == models.BlockedReasonDecision {
+		return false, nil
+	}
+	if issue.BlockedReason == models.BlockedReasonExternal && issue.BlockedRef != "" && issue.BlockedRef != resolvedURL {
+		var polled int
+		if err := db.conn.QueryRow(`SELECT COUNT(*) FROM external_dependencies WHERE issue_id = ? AND url = ?`,
+			issueID, issue.BlockedRef).Scan(&polled); err != nil || polled == 0 {
+			return false, err
+		}
+	}
+	if waiting, err := db.hasUnsatisfiedExternal(issueID); err != nil || waiting {
+		return false, err
+	}
+	if open, err := db.HasOpenDependencies(issueID); err != nil || open {
+		return false, err
+	}
+
+	issue.Status = models.StatusOpen
+	if err := db.updateIssueAndLog(issue, sessionID, models.ActionUnblock); err != nil {
+		return false, err
+	}
+	return true, db.addLogEntry(issueID, sessionID, message, models.LogTypeProgress)
+}
+
+// hasUnsatisfiedExternal reports whether an issue still waits on an
+// external dependency
+func (db *DB) hasUnsatisfiedExternal(issueID string) (bool, error) {
+	var n int
+	err := db.conn.QueryRow(`SELECT COUNT(*) FROM external_dependencies WHERE issue_id = ? AND state = ?`,
+		issueID, string(models.ExternalUnsatisfied)).Scan(&n)
+	return n > 0, err
+}
+
+func scanExternalDependencies(rows *sql.Rows) ([]models.ExternalDependency, error) {
+	defer rows.Close()
+	var deps []models.ExternalDependency
+	for rows.Next() {
+		var dep models.ExternalDependency
+		var state, createdAt string
+		var checkedAt, satisfiedAt sql.NullString
+		if err := rows.Scan(&dep.ID, &dep.IssueID, &dep.URL, &dep.Provider, &state, &dep.Detail, &dep.LastError,
+			&checkedAt, &satisfiedAt, &dep.SessionID, &createdAt); err != nil {
+			return nil, err
+		}
+		dep.State = models.ExternalState(state)
+		dep.CheckedAt = parseOptionalTime(checkedAt)
+		dep.SatisfiedAt = parseOptionalTime(satisfiedAt)
+		dep.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		deps = append(deps, dep)
+	}
+	return deps, rows.Err()
+}
@@ -0,0 +1,179 @@
+package db
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestExternalDependencyBlocksAndUnblocks(t *testing.T) {
+	database, err := Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+
+	issue := &models.Issue{Title: "Ship after upstream fix"}
+	if err := database.CreateIssueLogged(issue, "ses_a"); err != nil {
+		t.Fatal(err)
+	}
+	const prURL = "https://github.com/acme/lib/pull/42"
+	dep, blocked, err := database.AddExternalDependency(issue.ID, prURL, "github_pr", "ses_a")
+	if err != nil || !blocked {
+		t.Fatalf("add = %v, blocked %v", err, blocked)
+	}
+	if _, _, err := database.AddExternalDependency(issue.ID, prURL, "github_pr", "ses_a"); !errors.Is(err, ErrExternalExists) {
+		t.Errorf("duplicate add = %v, want ErrExternalExists", err)
+	}
+	got, _ := database.GetIssue(issue.ID)
+	if got.Status != models.StatusBlocked || got.BlockedReason != models.BlockedReasonExternal || got.BlockedRef != prURL {
+		t.Fatalf("issue = %s (%s: %s), want blocked on the PR", got.Status, got.BlockedReason, got.BlockedRef)
+	}
+
+	pending, err := database.ListUnsatisfiedExternalDependencies()
+	if err != nil || len(pending) != 1 || pending[0].ID != dep.ID {
+		t.Fatalf("unsatisfied = %+v, %v", pending, err)
+	}
+
+	// A failed check keeps the state; an unmet one leaves the issue blocked
+	if err := database.RecordExternalCheck(dep.ID, false, "", "HTTP 502"); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.RecordExternalCheck(dep.ID, false, "open", ""); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := database.UnblockIfExternalResolved(issue.ID, "ses_poll", prURL, "Unblocked"); ok {
+		t.Error("unblocked while the PR is open")
+	}
+
+	if err := database.RecordExternalCheck(dep.ID, true, "merged", ""); err != nil {
+		t.Fatal(err)
+	}
+	stored, _ := database.GetExternalDependency(dep.ID)
+	if stored.State != models.ExternalSatisfied || stored.SatisfiedAt == nil || stored.LastError != "" || stored.Detail != "merged" {
+		t.Errorf("stored = %+v, want satisfied", stored)
+	}
+	if ok, err := database.UnblockIfExternalResolved(issue.ID, "ses_poll", prURL, "Unblocked (merged)"); err != nil || !ok {
+		t.Fatalf("unblock = %v, %v", ok, err)
+	}
+	got, _ = database.GetIssue(issue.ID)
+	if got.Status != models.StatusOpen || got.BlockedRef != "" {
+		t.Errorf("issue = %s, want open", got.Status)
+	}
+	if pending, _ := database.ListUnsatisfiedExternalDependencies(); len(pending) != 0 {
+		t.Errorf("unsatisfied after merge = %d, want 0", len(pending))
+	}
+}
+
+func TestExternalDependencyHoldsCascadeAndRemove(t *testing.T) {
+	database, err := Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+
+	var blocker, issue models.Issue
+	for _, i := range []*models.Issue{&blocker, &issue} {
+		i.Title = "Issue with dependencies"
+		if err := database.CreateIssueLogged(i, "ses_a"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := database.AddDependency(issue.ID, blocker.ID, "depends_on"); err != nil {
+		t.Fatal(err)
+	}
+	issue.Status = models.StatusBlocked
+	issue.BlockedReason = models.BlockedReasonDependency
+	if err := database.UpdateIssueLogged(&issue, "ses_a", models.ActionBlock); err != nil {
+		t.Fatal(err)
+	}
+	const runURL = "https://github.com/acme/app/actions/runs/7"
+	if _, blocked, err := database.AddExternalDependency(issue.ID, runURL, "github_run", "ses_a"); err != nil || blocked {
+		t.Fatalf("add to blocked issue = %v, blocked %v", err, blocked)
+	}
+
+	// Closing the blocking issue doesn't unblock while the run is pending
+	blocker.Status = models.StatusClosed
+	if err := database.UpdateIssueLogged(&blocker, "ses_a", models.ActionClose); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := database.CascadeUnblockDependents(blocker.ID, "ses_a"); n != 0 {
+		t.Errorf("cascade unblocked %d, want 0", n)
+	}
+
+	if _, _, err := database.RemoveExternalDependency(issue.ID, "https://example.com/other", "ses_a"); err == nil {
+		t.Error("removing an unknown dependency should fail")
+	}
+	removed, unblocked, err := database.RemoveExternalDependency(issue.ID, runURL, "ses_a")
+	if err != nil || removed.URL != runURL || !unblocked {
+		t.Fatalf("remove = %+v, unblocked %v, %v", removed, unblocked, err)
+	}
+	if got, _ := database.GetIssue(issue.ID); got.Status != models.StatusOpen {
+		t.Errorf("issue = %s, want open", got.Status)
+	}
+}
+
+func TestExternalDependencyKeepsUnpolledBlock(t *testing.T) {
+	database, err := Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+
+	issue := &models.Issue{Title: "Waiting on the vendor"}
+	if err := database.CreateIssueLogged(issue, "ses_a"); err != nil {
+		t.Fatal(err)
+	}
+	issue.Status = models.StatusBlocked
+	issue.BlockedReason = models.BlockedReasonExternal
+	issue.BlockedRef = "VENDOR-123"
+	if err := database.UpdateIssueLogged(issue, "ses_a", models.ActionBlock); err != nil {
+		t.Fatal(err)
+	}
+	const url = "https://status.example.com/ok"
+	dep, _, err := database.AddExternalDependency(issue.ID, url, "http", "ses_a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := database.RecordExternalCheck(dep.ID, true, "HTTP 200", ""); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := database.UnblockIfExternalResolved(issue.ID, "ses_poll", url, "Unblocked"); ok {
+		t.Error("unblocked an issue blocked on a ref td doesn't poll")
+	}
+}
+
+func TestRemoveExternalDependencyMovesBlock(t *testing.T) {
+	database, err := Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+
+	issue := &models.Issue{Title: "Waiting on two upstream changes"}
+	if err := database.CreateIssueLogged(issue, "ses_a"); err != nil {
+		t.Fatal(err)
+	}
+	const first, second = "https://github.com/acme/lib/pull/1", "https://github.com/acme/lib/pull/2"
+	for _, url := range []string{first, second} {
+		if _, _, err := database.AddExternalDependency(issue.ID, url, "github_pr", "ses_a"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, unblocked, err := database.RemoveExternalDependency(issue.ID, first, "ses_a"); err != nil || unblocked {
+		t.Fatalf("remove = %v, unblocked %v", err, unblocked)
+	}
+	got, _ := database.GetIssue(issue.ID)
+	if got.Status != models.StatusBlocked || got.BlockedRef != second {
+		t.Fatalf("issue = %s on %q, want blocked on %s", got.Status, got.BlockedRef, second)
+	}
+
+	deps, _ := database.ListExternalDependencies(issue.ID)
+	if err := database.RecordExternalCheck(deps[0].ID, true, "merged", ""); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := database.UnblockIfExternalResolved(issue.ID, "ses_poll", second, "Unblocked"); err != nil || !ok {
+		t.Errorf("unblock after the remaining PR merged = %v, %v", ok, err)
+	}
+}
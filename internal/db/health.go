@@ -0,0 +1,162 @@
+package db
+
+import (
+	"fmt"
+	"time"
+)
+
+// JournalMode returns the database's journal mode; td expects "wal"
+func (db *DB) JournalMode() (string, error) {
+	var mode string
+	err := db.conn.QueryRow(`PRAGMA journal_mode`).Scan(&mode)
+	return mode, err
+}
+
+// QuickCheck runs SQLite's quick_check and returns the problems it reports,
+// none for a healthy file
+func (db *DB) QuickCheck() ([]string, error) {
+	rows, err := db.conn.Query(`PRAGMA quick_check`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var problems []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return nil, err
+		}
+		if line != "ok" {
+			problems = append(problems, line)
+		}
+	}
+	return problems, rows.Err()
+}
+
+// DanglingRefs is a column whose rows point at rows that no longer exist
+type DanglingRefs struct {
+	Table   string
+	Column  string
+	Count   int
+	Missing []string // the first few missing ids
+}
+
+// danglingMissingSample bounds DanglingRefs.Missing
+const danglingMissingSample = 5
+
+// danglingChecks are the references DanglingReferences follows
+var danglingChecks = []struct {
+	table, column, target, where string
+}{
+	{"issues", "parent_id", "issues", "t.parent_id != ''"},
+	{"issue_dependencies", "issue_id", "issues", ""},
+	{"issue_dependencies", "depends_on_id", "issues", ""},
+	{"comments", "issue_id", "issues", ""},
+	{"logs", "issue_id", "issues", "t.issue_id != ''"},
+	{"handoffs", "issue_id", "issues", ""},
+	{"issue_files", "issue_id", "issues", ""},
+	{"board_issue_positions", "issue_id", "issues", "t.deleted_at IS NULL"},
+	{"board_issue_positions", "board_id", "boards", "t.deleted_at IS NULL"},
+	{"work_session_issues", "issue_id", "issues", ""},
+	{"work_session_issues", "work_session_id", "work_sessions", ""},
+	{"reminders", "issue_id", "issues", ""},
+	{"decision_issues", "issue_id", "issues", ""},
+}
+
+// DanglingReferences finds rows that refer to issues, boards or work
+// sessions missing from the database, as partial imports and interrupted
+// syncs can leave. Soft-deleted issues still exist and don't count.
+func (db *DB) DanglingReferences() ([]DanglingRefs, error) {
+	var found []DanglingRefs
+	for _, c := range danglingChecks {
+		where := "1 = 1"
+		if c.where != "" {
+			where = c.where
+		}
+		rows, err := db.conn.Query(fmt.Sprintf(
+			`SELECT t.%[2]s FROM %[1]s t WHERE %[4]s
+			 AND NOT EXISTS (SELECT 1 FROM %[3]s x WHERE x.id = t.%[2]s)`,
+			c.table, c.column, c.target, where))
+		if err != nil {
+			return nil, fmt.Errorf("check %s.%s: %w", c.table, c.column, err)
+		}
+		refs := DanglingRefs{Table: c.table, Column: c.column}
+		seen := map[string]bool{}
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			refs.Count++
+			if !seen[id] && len(refs.Missing) < danglingMissingSample {
+				seen[id] = true
+				refs.Missing = append(refs.Missing, id)
+			}
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+		if refs.Count > 0 {
+			found = append(found, refs)
+		}
+	}
+	return found, nil
+}
+
+// OrphanedWork is work still claimed by sessions that have gone away
+type OrphanedWork struct {
+	IssueIDs       []string // in-progress issues untouched since the cutoff
+	WorkSessionIDs []string // work sessions that were never ended
+}
+
+// FindOrphanedWork finds in-progress issues and open work sessions whose
+// session is missing from this machine or has been idle since before.
+// Issues also have to be untouched since before, so work another machine
+// is doing, whose sessions only exist there, isn't flagged while it moves.
+func (db *DB) FindOrphanedWork(before time.Time) (*OrphanedWork, error) {
+	const sessionGone = `NOT EXISTS (SELECT 1 FROM sessions s WHERE s.id = %s
+		AND COALESCE(s.last_activity, s.started_at) >= ?)`
+
+	var orphans OrphanedWork
+	rows, err := db.conn.Query(`SELECT id FROM issues
+		WHERE status = 'in_progress' AND deleted_at IS NULL AND updated_at < ?
+		AND `+fmt.Sprintf(sessionGone, "issues.implementer_session")+`
+		ORDER BY updated_at`, before, before)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		orphans.IssueIDs = append(orphans.IssueIDs, id)
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err = db.conn.Query(`SELECT id FROM work_sessions
+		WHERE ended_at IS NULL AND started_at < ?
+		AND `+fmt.Sprintf(sessionGone, "work_sessions.session_id")+`
+		ORDER BY started_at`, before, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		orphans.WorkSessionIDs = append(orphans.WorkSessionIDs, id)
+	}
+	return &orphans, rows.Err()
+}
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestHealthChecks(t *testing.T) {
+	dir := t.TempDir()
+	database, err := Initialize(dir)
+	if err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	defer database.Close()
+
+	if mode, err := database.JournalMode(); err != nil || mode != "wal" {
+		t.Errorf("JournalMode = %q, %v, want wal", mode, err)
+	}
+	if problems, err := database.QuickCheck(); err != nil || len(problems) != 0 {
+		t.Errorf("QuickCheck = %v, %v, want no problems", problems, err)
+	}
+
+	parent := &models.Issue{Title: "Parent to be removed"}
+	child := &models.Issue{Title: "Child left behind"}
+	for _, issue := range []*models.Issue{parent, child} {
+		if err := database.CreateIssue(issue); err != nil {
+			t.Fatalf("CreateIssue: %v", err)
+		}
+	}
+	if _, err := database.conn.Exec(`UPDATE issues SET parent_id = ? WHERE id = ?`, parent.ID, child.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := database.conn.Exec(`INSERT INTO comments (id, issue_id, session_id, text) VALUES ('c-1', 'td-gone', 'ses_1', 'hi')`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := database.conn.Exec(`DELETE FROM issues WHERE id = ?`, parent.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	refs, err := database.DanglingReferences()
+	if err != nil {
+		t.Fatalf("DanglingReferences: %v", err)
+	}
+	got := map[string]DanglingRefs{}
+	for _, r := range refs {
+		got[r.Table+"."+r.Column] = r
+	}
+	if r := got["issues.parent_id"]; r.Count != 1 || len(r.Missing) != 1 || r.Missing[0] != parent.ID {
+		t.Errorf("issues.parent_id = %+v, want the removed parent", r)
+	}
+	if r := got["comments.issue_id"]; r.Count != 1 || r.Missing[0] != "td-gone" {
+		t.Errorf("comments.issue_id = %+v, want td-gone", r)
+	}
+	if len(refs) != 2 {
+		t.Errorf("DanglingReferences = %+v, want 2 columns", refs)
+	}
+
+	// An in-progress issue whose session is missing and that nobody has
+	// touched in a week is orphaned
+	old := time.Now().Add(-8 * 24 * time.Hour)
+	if _, err := database.conn.Exec(`UPDATE issues SET status = 'in_progress', implementer_session = 'ses_gone', updated_at = ? WHERE id = ?`, old, child.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := database.conn.Exec(`INSERT INTO work_sessions (id, name, session_id, started_at) VALUES ('ws-1', 'old', 'ses_gone', ?)`, old); err != nil {
+		t.Fatal(err)
+	}
+	orphans, err := database.FindOrphanedWork(time.Now().Add(-7 * 24 * time.Hour))
+	if err != nil {
+		t.Fatalf("FindOrphanedWork: %v", err)
+	}
+	if len(orphans.IssueIDs) != 1 || orphans.IssueIDs[0] != child.ID || len(orphans.WorkSessionIDs) != 1 {
+		t.Errorf("FindOrphanedWork = %+v, want %s and ws-1", orphans, child.ID)
+	}
+
+	// A live session keeps its work
+	if err := database.UpsertSession(&SessionRow{ID: "ses_gone", StartedAt: old, LastActivity: time.Now()}); err != nil {
+		t.Fatalf("UpsertSession: %v", err)
+	}
+	if orphans, _ := database.FindOrphanedWork(time.Now().Add(-7 * 24 * time.Hour)); len(orphans.IssueIDs)+len(orphans.WorkSessionIDs) != 0 {
+		t.Errorf("FindOrphanedWork with an active session = %+v, want none", orphans)
+	}
+}
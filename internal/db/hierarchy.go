@@ -0,0 +1,184 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/models"
+)
+
+// Hierarchy rules a parent change can break
+const (
+	HierarchySelf  = "self"  // an issue parented to itself
+	HierarchyCycle = "cycle" // an issue parented to its own descendant
+	HierarchyDepth = "depth" // nesting deeper than max_hierarchy_depth
+)
+
+// HierarchyError is returned when a parent change would break the issue
+// hierarchy
+type HierarchyError struct {
+	IssueID  string
+	ParentID string
+	Rule     string // HierarchySelf, HierarchyCycle or HierarchyDepth
+	MaxDepth int
+}
+
+func (e *HierarchyError) Error() string {
+	subject := e.IssueID
+	if subject == "" {
+		subject = "new issue"
+	}
+	switch e.Rule {
+	case HierarchySelf:
+		return fmt.Sprintf("cannot make %s its own parent", subject)
+	case HierarchyCycle:
+		return fmt.Sprintf("cannot move %s under %s: %s is its descendant", subject, e.ParentID, e.ParentID)
+	default:
+		return fmt.Sprintf("cannot move %s under %s: hierarchy would be deeper than %d levels", subject, e.ParentID, e.MaxDepth)
+	}
+}
+
+// ValidateParent checks that an issue can take parentID as its parent: not
+// itself, not one of its descendants, and not so deep that the issue's
+// subtree would pass the configured max depth. An empty issueID checks a
+// new issue. Returns a *HierarchyError when the move is refused.
+func (db *DB) ValidateParent(issueID, parentID string) error {
+	_, err := db.validateParent(issueID, parentID)
+	return err
+}
+
+// validateParent is ValidateParent that also returns the issue's subtree
+// (the issue and its live descendants)
+func (db *DB) validateParent(issueID, parentID string) ([]string, error) {
+	var subtree []string
+	height := 1
+	if issueID != "" {
+		issueID = NormalizeIssueID(issueID)
+		var err error
+		if subtree, height, err = db.issueSubtree(issueID); err != nil {
+			return nil, err
+		}
+	}
+	if parentID == "" {
+		return subtree, nil
+	}
+	parentID = NormalizeIssueID(parentID)
+	if parentID == issueID {
+		return nil, &HierarchyError{IssueID: issueID, ParentID: parentID, Rule: HierarchySelf}
+	}
+
+	// Walk up from the new parent; reaching the issue means a cycle
+	depth := 0
+	seen := make(map[string]bool)
+	for id := parentID; id != "" && !seen[id]; {
+		if id == issueID {
+			return nil, &HierarchyError{IssueID: issueID, ParentID: parentID, Rule: HierarchyCycle}
+		}
+		seen[id] = true
+		depth++
+		var next sql.NullString
+		err := db.conn.QueryRow(`SELECT parent_id FROM issues WHERE id = ?`, id).Scan(&next)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, err
+		}
+		id = next.String
+	}
+
+	maxDepth, _ := config.GetMaxHierarchyDepth(db.baseDir)
+	if depth+height > maxDepth {
+		return nil, &HierarchyError{IssueID: issueID, ParentID: parentID, Rule: HierarchyDepth, MaxDepth: maxDepth}
+	}
+	return subtree, nil
+}
+
+// issueSubtree returns an issue and its live descendants, and how many
+// levels they span (1 for an issue with no children)
+func (db *DB) issueSubtree(issueID string) ([]string, int, error) {
+	subtree := []string{issueID}
+	seen := map[string]bool{issueID: true}
+	level := []string{issueID}
+	height := 1
+	for {
+		placeholders := strings.TrimSuffix(strings.Repeat("?,", len(level)), ",")
+		args := make([]interface{}, len(level))
+		for i, id := range level {
+			args[i] = id
+		}
+		children, err := db.queryIDs(`SELECT id FROM issues WHERE parent_id IN (`+placeholders+`) AND deleted_at IS NULL`, args...)
+		if err != nil {
+			return nil, 0, err
+		}
+		var next []string
+		for _, id := range children {
+			if !seen[id] {
+				seen[id] = true
+				next = append(next, id)
+			}
+		}
+		if len(next) == 0 {
+			return subtree, height, nil
+		}
+		subtree = append(subtree, next...)
+		level = next
+		height++
+	}
+}
+
+// checkParentLocked validates an update's parent when it changes it.
+// Caller MUST already hold the write lock.
+func (db *DB) checkParentLocked(issue *models.Issue) error {
+	if issue.ParentID == "" {
+		return nil
+	}
+	var current sql.NullString
+	err := db.conn.QueryRow(`SELECT parent_id FROM issues WHERE id = ?`, issue.ID).Scan(&current)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if NormalizeIssueID(issue.ParentID) == current.String {
+		return nil
+	}
+	return db.ValidateParent(issue.ID, issue.ParentID)
+}
+
+// MoveIssueLogged reparents an issue, and with it its whole subtree, under
+// parentID ("" makes it a root). The hierarchy is validated and the issue
+// updated under one write lock, so a concurrent move cannot slip a cycle
+// in between. Returns the moved issue and its subtree.
+func (db *DB) MoveIssueLogged(issueID, parentID, sessionID string) (*models.Issue, []string, error) {
+	issueID = NormalizeIssueID(issueID)
+	if parentID != "" {
+		parentID = NormalizeIssueID(parentID)
+	}
+	var issue *models.Issue
+	var subtree []string
+	err := db.withWriteLock(func() error {
+		var err error
+		if issue, err = db.scanIssueRow(issueID); err != nil {
+			return err
+		}
+		if issue.DeletedAt != nil {
+			return fmt.Errorf("issue not found: %s", issueID)
+		}
+		if parentID != "" {
+			parent, err := db.scanIssueRow(parentID)
+			if err != nil || parent.DeletedAt != nil {
+				return fmt.Errorf("parent issue not found: %s", parentID)
+			}
+		}
+		if subtree, err = db.validateParent(issueID, parentID); err != nil {
+			return err
+		}
+		if issue.ParentID == parentID {
+			return nil
+		}
+		issue.ParentID = parentID
+		return db.updateIssueAndLog(issue, sessionID, models.ActionUpdate)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return issue, subtree, nil
+}
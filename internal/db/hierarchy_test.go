@@ -0,0 +1,120 @@
+package db
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/models"
+)
+
+func TestValidateParent(t *testing.T) {
+	dir := t.TempDir()
+	database, err := Initialize(dir)
+	if err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	defer database.Close()
+
+	// epic > story > task, plus an unrelated root
+	var chain []*models.Issue
+	parentID := ""
+	for _, title := range []string{"Epic", "Story", "Task"} {
+		issue := &models.Issue{Title: title, ParentID: parentID}
+		if err := database.CreateIssue(issue); err != nil {
+			t.Fatalf("CreateIssue: %v", err)
+		}
+		chain = append(chain, issue)
+		parentID = issue.ID
+	}
+	other := &models.Issue{Title: "Other"}
+	if err := database.CreateIssue(other); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+	epic, story, task := chain[0], chain[1], chain[2]
+
+	tests := []struct {
+		name     string
+		issue    string
+		parent   string
+		maxDepth int
+		wantRule string
+	}{
+		{"root", story.ID, "", 0, ""},
+		{"sibling subtree", other.ID, task.ID, 0, ""},
+		{"self", epic.ID, epic.ID, 0, HierarchySelf},
+		{"child", epic.ID, story.ID, 0, HierarchyCycle},
+		{"grandchild", epic.ID, task.ID, 0, HierarchyCycle},
+		{"within depth", story.ID, other.ID, 3, ""},
+		{"too deep", story.ID, task.ID, 3, HierarchyCycle},
+		{"subtree too deep", epic.ID, other.ID, 3, HierarchyDepth},
+		{"new issue too deep", "", task.ID, 3, HierarchyDepth},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := config.Save(dir, &models.Config{MaxHierarchyDepth: tt.maxDepth}); err != nil {
+				t.Fatalf("save config: %v", err)
+			}
+			err := database.ValidateParent(tt.issue, tt.parent)
+			var he *HierarchyError
+			switch {
+			case tt.wantRule == "" && err != nil:
+				t.Errorf("ValidateParent(%s, %s) = %v, want ok", tt.issue, tt.parent, err)
+			case tt.wantRule != "" && (!errors.As(err, &he) || he.Rule != tt.wantRule):
+				t.Errorf("ValidateParent(%s, %s) = %v, want %s", tt.issue, tt.parent, err, tt.wantRule)
+			}
+		})
+	}
+}
+
+func TestMoveIssueLogged(t *testing.T) {
+	dir := t.TempDir()
+	database, err := Initialize(dir)
+	if err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	defer database.Close()
+
+	epic := &models.Issue{Title: "Epic", Type: models.TypeEpic}
+	other := &models.Issue{Title: "Other epic", Type: models.TypeEpic}
+	for _, issue := range []*models.Issue{epic, other} {
+		if err := database.CreateIssue(issue); err != nil {
+			t.Fatalf("CreateIssue: %v", err)
+		}
+	}
+	story := &models.Issue{Title: "Story", ParentID: epic.ID}
+	if err := database.CreateIssue(story); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+	task := &models.Issue{Title: "Task", ParentID: story.ID}
+	if err := database.CreateIssue(task); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+
+	moved, subtree, err := database.MoveIssueLogged(story.ID, other.ID, "sess-1")
+	if err != nil {
+		t.Fatalf("MoveIssueLogged: %v", err)
+	}
+	if moved.ParentID != other.ID || len(subtree) != 2 {
+		t.Errorf("moved = %s under %s with subtree %v, want under %s with 2 issues", moved.ID, moved.ParentID, subtree, other.ID)
+	}
+	if got, _ := database.GetIssue(task.ID); got.ParentID != story.ID {
+		t.Errorf("task parent = %s, want it to move with %s", got.ParentID, story.ID)
+	}
+
+	// A cycle through the moved subtree is refused and nothing changes
+	var he *HierarchyError
+	if _, _, err := database.MoveIssueLogged(other.ID, task.ID, "sess-1"); !errors.As(err, &he) || he.Rule != HierarchyCycle {
+		t.Errorf("move into own subtree: err = %v, want cycle", err)
+	}
+	if got, _ := database.GetIssue(other.ID); got.ParentID != "" {
+		t.Errorf("refused move changed parent to %s", got.ParentID)
+	}
+
+	// The same check guards plain updates
+	got, _ := database.GetIssue(epic.ID)
+	got.ParentID = epic.ID
+	if err := database.UpdateIssueLogged(got, "sess-1", models.ActionUpdate); !errors.As(err, &he) || he.Rule != HierarchySelf {
+		t.Errorf("self-parent update: err = %v, want self", err)
+	}
+}
@@ -4,19 +4,39 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
+	"fmt"
+	"io"
+	"regexp"
 	"strings"
+	"sync"
 )
 
 const (
-	idPrefix         = "td-"
-	wsIDPrefix       = "ws-"
-	boardIDPrefix    = "bd-"
-	logIDPrefix      = "lg-"
-	handoffIDPrefix  = "ho-"
-	commentIDPrefix  = "cm-"
-	snapshotIDPrefix = "gs-"
-	noteIDPrefix     = "nt-"
-	actionIDPrefix = "al-"
+	idPrefix              = "td-"
+	wsIDPrefix            = "ws-"
+	boardIDPrefix         = "bd-"
+	logIDPrefix           = "lg-"
+	handoffIDPrefix       = "ho-"
+	commentIDPrefix       = "cm-"
+	snapshotIDPrefix      = "gs-"
+	noteIDPrefix          = "nt-"
+	planIDPrefix          = "pl-"
+	reminderIDPrefix      = "rm-"
+	shareIDPrefix         = "sh-"
+	decisionIDPrefix      = "dc-"
+	retroIDPrefix         = "rt-"
+	revisionIDPrefix      = "rv-"
+	reworkIDPrefix        = "rw-"
+	tokenIDPrefix         = "tk-"
+	overrideIDPrefix      = "ov-"
+	reviewAckIDPrefix     = "ra-"
+	routeIDPrefix         = "nr-"
+	viewIDPrefix          = "vw-"
+	impersonationIDPrefix = "im-"
+	deliveryIDPrefix      = "dl-"
+	pokerIDPrefix         = "pk-"
+	externalIDPrefix      = "ed-"
+	actionIDPrefix        = "al-"
 
 	// Deterministic ID prefixes for composite-key tables
 	boardIssuePosIDPrefix = "bip_"
@@ -25,16 +45,95 @@ const (
 	wsiIDPrefix           = "wsi_"
 )
 
-// NormalizeIssueID ensures an issue ID has the td- prefix
-// Accepts bare hex IDs like "abc123" and returns "td-abc123"
+// NormalizeIssueID ensures an issue ID has a prefix
+// Accepts bare hex IDs like "abc123" and returns "td-abc123". IDs that
+// already carry a prefix, including a project's custom one, are unchanged.
 func NormalizeIssueID(id string) string {
-	if id == "" {
+	if id == "" || strings.Contains(id, "-") || isCrossProjectID(id) {
 		return id
 	}
-	if !strings.HasPrefix(id, idPrefix) {
-		return idPrefix + id
+	return idPrefix + id
+}
+
+// issuePrefixPattern matches custom issue ID prefixes (without the dash)
+var issuePrefixPattern = regexp.MustCompile(`^[a-z][a-z0-9]{0,9}$`)
+
+// IssuePrefix returns the prefix of new issue IDs: "td-" unless the
+// project picked another one with SetIssuePrefix.
+func (db *DB) IssuePrefix() string {
+	var prefix string
+	err := db.conn.QueryRow(`SELECT value FROM schema_info WHERE key = 'issue_prefix'`).Scan(&prefix)
+	if err != nil || prefix == "" {
+		return idPrefix
+	}
+	return prefix
+}
+
+// ValidateIssuePrefix normalizes a custom issue ID prefix ("Web-" becomes
+// "web") and checks that it is usable
+func ValidateIssuePrefix(prefix string) (string, error) {
+	prefix = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(prefix)), "-")
+	if !issuePrefixPattern.MatchString(prefix) {
+		return "", fmt.Errorf("invalid issue ID prefix %q: use up to 10 lowercase letters and digits, starting with a letter", prefix)
+	}
+	return prefix, nil
+}
+
+// SetIssuePrefix sets the prefix of issues created from now on, e.g. "web"
+// gives IDs like web-a1b2c3. Existing issues keep their IDs.
+func (db *DB) SetIssuePrefix(prefix string) error {
+	prefix, err := ValidateIssuePrefix(prefix)
+	if err != nil {
+		return err
 	}
-	return id
+	return db.withWriteLock(func() error {
+		_, err := db.conn.Exec(`INSERT OR REPLACE INTO schema_info (key, value) VALUES ('issue_prefix', ?)`, prefix+"-")
+		return err
+	})
+}
+
+// normalizeIssueID is NormalizeIssueID using the project's own prefix for
+// bare IDs
+func (db *DB) normalizeIssueID(id string) string {
+	if id == "" || strings.Contains(id, "-") || isCrossProjectID(id) {
+		return id
+	}
+	return db.IssuePrefix() + id
+}
+
+// isCrossProjectID reports whether id refers to an issue in another
+// project (<project>/<issue-id>). Such IDs never match local issues.
+func isCrossProjectID(id string) bool {
+	return strings.Contains(id, "/")
+}
+
+// idSource supplies the random bytes of generated IDs. SetIDSource swaps
+// in a seeded reader so a test run creates the same IDs every time.
+var (
+	idSourceMu sync.Mutex
+	idSource   io.Reader = rand.Reader
+)
+
+// SetIDSource makes generated IDs read their random bytes from r until the
+// returned restore func is called. r need not be safe for concurrent use.
+func SetIDSource(r io.Reader) (restore func()) {
+	idSourceMu.Lock()
+	prev := idSource
+	idSource = r
+	idSourceMu.Unlock()
+	return func() {
+		idSourceMu.Lock()
+		idSource = prev
+		idSourceMu.Unlock()
+	}
+}
+
+// RandomBytes fills b from the ID source, like crypto/rand.Read. Packages
+// that mint their own IDs use it so SetIDSource covers them too.
+func RandomBytes(b []byte) (int, error) {
+	idSourceMu.Lock()
+	defer idSourceMu.Unlock()
+	return io.ReadFull(idSource, b)
 }
 
 // idGenerator is the function used to generate issue IDs.
@@ -44,7 +143,7 @@ var idGenerator = defaultGenerateID
 // defaultGenerateID generates a unique issue ID using crypto/rand
 func defaultGenerateID() (string, error) {
 	bytes := make([]byte, 3) // 6 hex characters - balances brevity with collision resistance
-	if _, err := rand.Read(bytes); err != nil {
+	if _, err := RandomBytes(bytes); err != nil {
 		return "", err
 	}
 	return idPrefix + hex.EncodeToString(bytes), nil
@@ -55,10 +154,22 @@ func generateID() (string, error) {
 	return idGenerator()
 }
 
+// generateIssueID generates an issue ID carrying the project's prefix
+func (db *DB) generateIssueID() (string, error) {
+	id, err := generateID()
+	if err != nil {
+		return "", err
+	}
+	if prefix := db.IssuePrefix(); prefix != idPrefix {
+		id = prefix + strings.TrimPrefix(id, idPrefix)
+	}
+	return id, nil
+}
+
 // generateWSID generates a unique work session ID
 func generateWSID() (string, error) {
 	bytes := make([]byte, 2) // 4 hex characters
-	if _, err := rand.Read(bytes); err != nil {
+	if _, err := RandomBytes(bytes); err != nil {
 		return "", err
 	}
 	return wsIDPrefix + hex.EncodeToString(bytes), nil
@@ -67,7 +178,7 @@ func generateWSID() (string, error) {
 // generateBoardID generates a unique board ID
 func generateBoardID() (string, error) {
 	bytes := make([]byte, 4) // 8 hex characters
-	if _, err := rand.Read(bytes); err != nil {
+	if _, err := RandomBytes(bytes); err != nil {
 		return "", err
 	}
 	return boardIDPrefix + hex.EncodeToString(bytes), nil
@@ -76,7 +187,7 @@ func generateBoardID() (string, error) {
 // generateLogID generates a unique log entry ID
 func generateLogID() (string, error) {
 	bytes := make([]byte, 4) // 8 hex characters
-	if _, err := rand.Read(bytes); err != nil {
+	if _, err := RandomBytes(bytes); err != nil {
 		return "", err
 	}
 	return logIDPrefix + hex.EncodeToString(bytes), nil
@@ -85,7 +196,7 @@ func generateLogID() (string, error) {
 // generateHandoffID generates a unique handoff ID
 func generateHandoffID() (string, error) {
 	bytes := make([]byte, 4) // 8 hex characters
-	if _, err := rand.Read(bytes); err != nil {
+	if _, err := RandomBytes(bytes); err != nil {
 		return "", err
 	}
 	return handoffIDPrefix + hex.EncodeToString(bytes), nil
@@ -94,7 +205,7 @@ func generateHandoffID() (string, error) {
 // generateCommentID generates a unique comment ID
 func generateCommentID() (string, error) {
 	bytes := make([]byte, 4) // 8 hex characters
-	if _, err := rand.Read(bytes); err != nil {
+	if _, err := RandomBytes(bytes); err != nil {
 		return "", err
 	}
 	return commentIDPrefix + hex.EncodeToString(bytes), nil
@@ -103,7 +214,7 @@ func generateCommentID() (string, error) {
 // generateSnapshotID generates a unique goal snapshot ID
 func generateSnapshotID() (string, error) {
 	bytes := make([]byte, 4) // 8 hex characters
-	if _, err := rand.Read(bytes); err != nil {
+	if _, err := RandomBytes(bytes); err != nil {
 		return "", err
 	}
 	return snapshotIDPrefix + hex.EncodeToString(bytes), nil
@@ -112,16 +223,160 @@ func generateSnapshotID() (string, error) {
 // generateNoteID generates a unique note ID
 func generateNoteID() (string, error) {
 	bytes := make([]byte, 3) // 6 hex characters
-	if _, err := rand.Read(bytes); err != nil {
+	if _, err := RandomBytes(bytes); err != nil {
 		return "", err
 	}
 	return noteIDPrefix + hex.EncodeToString(bytes), nil
 }
 
+// generatePlanID generates a unique plan ID
+func generatePlanID() (string, error) {
+	bytes := make([]byte, 4) // 8 hex characters
+	if _, err := RandomBytes(bytes); err != nil {
+		return "", err
+	}
+	return planIDPrefix + hex.EncodeToString(bytes), nil
+}
+
+// generateReminderID generates a unique reminder ID
+func generateReminderID() (string, error) {
+	bytes := make([]byte, 4) // 8 hex characters
+	if _, err := RandomBytes(bytes); err != nil {
+		return "", err
+	}
+	return reminderIDPrefix + hex.EncodeToString(bytes), nil
+}
+
+// generateShareID generates a unique share link ID
+func generateShareID() (string, error) {
+	bytes := make([]byte, 4) // 8 hex characters
+	if _, err := RandomBytes(bytes); err != nil {
+		return "", err
+	}
+	return shareIDPrefix + hex.EncodeToString(bytes), nil
+}
+
+// generateDecisionID generates a unique decision ID
+func generateDecisionID() (string, error) {
+	bytes := make([]byte, 4) // 8 hex characters
+	if _, err := RandomBytes(bytes); err != nil {
+		return "", err
+	}
+	return decisionIDPrefix + hex.EncodeToString(bytes), nil
+}
+
+// generateRetroID generates a unique retro item ID
+func generateRetroID() (string, error) {
+	bytes := make([]byte, 4) // 8 hex characters
+	if _, err := RandomBytes(bytes); err != nil {
+		return "", err
+	}
+	return retroIDPrefix + hex.EncodeToString(bytes), nil
+}
+
+// generatePokerID generates a unique poker round ID
+func generatePokerID() (string, error) {
+	bytes := make([]byte, 4) // 8 hex characters
+	if _, err := RandomBytes(bytes); err != nil {
+		return "", err
+	}
+	return pokerIDPrefix + hex.EncodeToString(bytes), nil
+}
+
+// generateExternalID generates a unique external dependency ID
+func generateExternalID() (string, error) {
+	bytes := make([]byte, 4) // 8 hex characters
+	if _, err := RandomBytes(bytes); err != nil {
+		return "", err
+	}
+	return externalIDPrefix + hex.EncodeToString(bytes), nil
+}
+
+// generateRevisionID generates a unique revision ID
+func generateRevisionID() (string, error) {
+	bytes := make([]byte, 4) // 8 hex characters
+	if _, err := RandomBytes(bytes); err != nil {
+		return "", err
+	}
+	return revisionIDPrefix + hex.EncodeToString(bytes), nil
+}
+
+// generateReworkID generates a unique rework ID
+func generateReworkID() (string, error) {
+	bytes := make([]byte, 4) // 8 hex characters
+	if _, err := RandomBytes(bytes); err != nil {
+		return "", err
+	}
+	return reworkIDPrefix + hex.EncodeToString(bytes), nil
+}
+
+// generateTokenID generates a unique API token ID
+func generateTokenID() (string, error) {
+	bytes := make([]byte, 4) // 8 hex characters
+	if _, err := RandomBytes(bytes); err != nil {
+		return "", err
+	}
+	return tokenIDPrefix + hex.EncodeToString(bytes), nil
+}
+
+// generateOverrideID generates a unique override ID
+func generateOverrideID() (string, error) {
+	bytes := make([]byte, 4) // 8 hex characters
+	if _, err := RandomBytes(bytes); err != nil {
+		return "", err
+	}
+	return overrideIDPrefix + hex.EncodeToString(bytes), nil
+}
+
+// generateReviewAckID generates a unique review acknowledgment ID
+func generateReviewAckID() (string, error) {
+	bytes := make([]byte, 4) // 8 hex characters
+	if _, err := RandomBytes(bytes); err != nil {
+		return "", err
+	}
+	return reviewAckIDPrefix + hex.EncodeToString(bytes), nil
+}
+
+// generateNotifyRouteID generates a unique notification route ID
+func generateNotifyRouteID() (string, error) {
+	bytes := make([]byte, 4) // 8 hex characters
+	if _, err := RandomBytes(bytes); err != nil {
+		return "", err
+	}
+	return routeIDPrefix + hex.EncodeToString(bytes), nil
+}
+
+// generateViewID generates a unique saved view ID
+func generateViewID() (string, error) {
+	bytes := make([]byte, 4) // 8 hex characters
+	if _, err := RandomBytes(bytes); err != nil {
+		return "", err
+	}
+	return viewIDPrefix + hex.EncodeToString(bytes), nil
+}
+
+// generateImpersonationID generates a unique impersonation record ID
+func generateImpersonationID() (string, error) {
+	bytes := make([]byte, 4) // 8 hex characters
+	if _, err := RandomBytes(bytes); err != nil {
+		return "", err
+	}
+	return impersonationIDPrefix + hex.EncodeToString(bytes), nil
+}
+
+// generateDeliveryID generates a unique delivery ID
+func generateDeliveryID() (string, error) {
+	bytes := make([]byte, 4) // 8 hex characters
+	if _, err := RandomBytes(bytes); err != nil {
+		return "", err
+	}
+	return deliveryIDPrefix + hex.EncodeToString(bytes), nil
+}
+
 // generateActionID generates a unique action log ID
 func generateActionID() (string, error) {
 	bytes := make([]byte, 4) // 8 hex characters
-	if _, err := rand.Read(bytes); err != nil {
+	if _, err := RandomBytes(bytes); err != nil {
 		return "", err
 	}
 	return actionIDPrefix + hex.EncodeToString(bytes), nil
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/models"
+)
+
+// ClosedIssueError is returned when editing a closed issue while closed
+// issues are immutable
+type ClosedIssueError struct {
+	IssueID string
+	Action  string // what was attempted, e.g. "update" or "comment"
+}
+
+func (e *ClosedIssueError) Error() string {
+	return fmt.Sprintf("cannot %s %s: closed issues are immutable; reopen it first", e.Action, e.IssueID)
+}
+
+// CheckClosedEdit guards an edit to an issue's fields or comments when
+// closed_immutable is set. A closed issue is refused with a
+// *ClosedIssueError unless override is set; every override is recorded in
+// the security event log and the overrides audit, and the edit itself must
+// then be written through WithClosedOverride. Callers reopening the issue
+// should not call it. A missing issue passes, leaving the caller to report
+// it.
+func (db *DB) CheckClosedEdit(issueID, sessionID, action string, override bool) error {
+	issueID = NormalizeIssueID(issueID)
+	if !db.closedImmutable() || !db.issueClosed(issueID) {
+		return nil
+	}
+	if !override {
+		return &ClosedIssueError{IssueID: issueID, Action: action}
+	}
+	if err := db.RecordOverride(&models.Override{
+		IssueID:       issueID,
+		Kind:          models.OverrideClosedEdit,
+		Justification: action,
+		SessionID:     sessionID,
+	}); err != nil {
+		slog.Debug("closed immutable: record override", "err", err)
+	}
+	return LogSecurityEvent(db.baseDir, SecurityEvent{
+		IssueID:   issueID,
+		SessionID: sessionID,
+		Reason:    fmt.Sprintf("closed issue override: %s", action),
+	})
+}
+
+// WithClosedOverride returns a handle on the same database whose writes may
+// edit closed issues, for an edit CheckClosedEdit let through with an
+// override. It shares the connection: close the original, not the handle.
+func (db *DB) WithClosedOverride() *DB {
+	c := *db
+	c.closedOverride = true
+	return &c
+}
+
+// checkClosedWrite refuses a write to a closed issue while closed_immutable
+// is set, so every entry point gets the guard whether or not it called
+// CheckClosedEdit. Handles from WithClosedOverride pass.
+func (db *DB) checkClosedWrite(issueID, action string) error {
+	issueID = NormalizeIssueID(issueID)
+	if db.closedOverride || !db.closedImmutable() || !db.issueClosed(issueID) {
+		return nil
+	}
+	return &ClosedIssueError{IssueID: issueID, Action: action}
+}
+
+func (db *DB) closedImmutable() bool {
+	immutable, err := config.GetClosedImmutable(db.baseDir)
+	if err != nil {
+		slog.Debug("closed immutable: load config", "err", err)
+	}
+	return immutable
+}
+
+func (db *DB) issueClosed(issueID string) bool {
+	var status string
+	err := db.conn.QueryRow(`SELECT status FROM issues WHERE id = ?`, issueID).Scan(&status)
+	return err == nil && models.Status(status) == models.StatusClosed
+}
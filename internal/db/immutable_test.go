@@ -0,0 +1,124 @@
+package db
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/models"
+)
+
+func TestCheckClosedEdit(t *testing.T) {
+	dir := t.TempDir()
+	database, err := Initialize(dir)
+	if err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	defer database.Close()
+
+	closed := &models.Issue{Title: "Closed", Status: models.StatusClosed}
+	open := &models.Issue{Title: "Open"}
+	for _, issue := range []*models.Issue{closed, open} {
+		if err := database.CreateIssue(issue); err != nil {
+			t.Fatalf("CreateIssue: %v", err)
+		}
+	}
+
+	// Off by default
+	if err := database.CheckClosedEdit(closed.ID, "sess-1", "update", false); err != nil {
+		t.Errorf("default config: err = %v, want nil", err)
+	}
+
+	if err := config.SetClosedImmutable(dir, true); err != nil {
+		t.Fatalf("SetClosedImmutable: %v", err)
+	}
+	var ce *ClosedIssueError
+	if err := database.CheckClosedEdit(closed.ID, "sess-1", "update", false); !errors.As(err, &ce) || ce.IssueID != closed.ID {
+		t.Errorf("closed issue: err = %v, want *ClosedIssueError", err)
+	}
+	if err := database.CheckClosedEdit(open.ID, "sess-1", "update", false); err != nil {
+		t.Errorf("open issue: err = %v, want nil", err)
+	}
+
+	// An override passes and is audited
+	if err := database.CheckClosedEdit(closed.ID, "sess-1", "comment on", true); err != nil {
+		t.Fatalf("override: err = %v", err)
+	}
+	events, err := ReadSecurityEvents(dir)
+	if err != nil {
+		t.Fatalf("ReadSecurityEvents: %v", err)
+	}
+	if len(events) != 1 || events[0].IssueID != closed.ID || events[0].SessionID != "sess-1" {
+		t.Errorf("security events = %+v, want one override for %s", events, closed.ID)
+	}
+}
+
+func TestClosedIssueWritesRefused(t *testing.T) {
+	dir := t.TempDir()
+	database, err := Initialize(dir)
+	if err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	defer database.Close()
+
+	issue := &models.Issue{Title: "Shipped", Description: "first draft"}
+	if err := database.CreateIssueLogged(issue, "sess-1"); err != nil {
+		t.Fatalf("CreateIssueLogged: %v", err)
+	}
+	issue.Description = "final text"
+	if err := database.UpdateIssueLogged(issue, "sess-1", models.ActionUpdate); err != nil {
+		t.Fatal(err)
+	}
+	comment := &models.Comment{IssueID: issue.ID, SessionID: "sess-1", Text: "done"}
+	if err := database.AddComment(comment); err != nil {
+		t.Fatal(err)
+	}
+	revs, _ := database.ListRevisions(issue.ID)
+	if len(revs) != 1 {
+		t.Fatalf("revisions = %d, want 1", len(revs))
+	}
+	issue.Status = models.StatusClosed
+	if err := database.UpdateIssueLogged(issue, "sess-2", models.ActionClose); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.SetClosedImmutable(dir, true); err != nil {
+		t.Fatalf("SetClosedImmutable: %v", err)
+	}
+
+	var ce *ClosedIssueError
+	if _, err := database.RevertRevision(revs[0].ID, "sess-1"); !errors.As(err, &ce) {
+		t.Errorf("revert: err = %v, want *ClosedIssueError", err)
+	}
+	if got, _ := database.GetIssue(issue.ID); got.Description != "final text" {
+		t.Errorf("refused revert changed the description to %q", got.Description)
+	}
+	issue.Title = "Shipped, renamed"
+	if err := database.UpdateIssueLogged(issue, "sess-1", models.ActionUpdate); !errors.As(err, &ce) {
+		t.Errorf("update: err = %v", err)
+	}
+	if err := database.AddComment(&models.Comment{IssueID: issue.ID, SessionID: "sess-1", Text: "late"}); !errors.As(err, &ce) {
+		t.Errorf("comment: err = %v", err)
+	}
+	if err := database.UpdateCommentLogged(comment.ID, "edited", "sess-1"); !errors.As(err, &ce) {
+		t.Errorf("edit comment: err = %v", err)
+	}
+	if err := database.DeleteCommentLogged(comment.ID, "sess-1"); !errors.As(err, &ce) {
+		t.Errorf("delete comment: err = %v", err)
+	}
+
+	// An override handle writes through
+	if _, err := database.WithClosedOverride().RevertRevision(revs[0].ID, "sess-1"); err != nil {
+		t.Errorf("override revert: %v", err)
+	}
+	if got, _ := database.GetIssue(issue.ID); got.Description != "first draft" {
+		t.Errorf("override revert left %q", got.Description)
+	}
+
+	// Reopening is always allowed
+	issue, _ = database.GetIssue(issue.ID)
+	issue.Status = models.StatusOpen
+	issue.ClosedAt = nil
+	if err := database.UpdateIssueLogged(issue, "sess-1", models.ActionReopen); err != nil {
+		t.Errorf("reopen: %v", err)
+	}
+}
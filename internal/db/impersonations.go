@@ -0,0 +1,80 @@
+package db
+
+import (
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/models"
+)
+
+const impersonationColumns = `id, issue_id, action, actor_session, as_session, before_value, after_value, reason, created_at`
+
+// ImpersonationFilter narrows ListImpersonations. Zero fields match
+// everything; Session matches either the actor or the session acted for.
+type ImpersonationFilter struct {
+	IssueID string
+	Session string
+	Since   time.Time
+}
+
+// RecordImpersonation stores an admin repair made on behalf of another
+// session. ID and CreatedAt are filled in. Like overrides, these are local
+// audit records: they are not written to the action log or synced.
+func (db *DB) RecordImpersonation(im *models.Impersonation) error {
+	return db.withWriteLock(func() error {
+		id, err := generateImpersonationID()
+		if err != nil {
+			return err
+		}
+		im.ID = id
+		im.IssueID = NormalizeIssueID(im.IssueID)
+		im.CreatedAt = clock.Now().UTC()
+
+		_, err = db.conn.Exec(`INSERT INTO impersonations (`+impersonationColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			im.ID, im.IssueID, string(im.Action), im.ActorSession, im.AsSession, im.Before, im.After, im.Reason,
+			im.CreatedAt.Format(time.RFC3339))
+		return err
+	})
+}
+
+// ListImpersonations returns the impersonation records matching f, newest
+// first
+func (db *DB) ListImpersonations(f ImpersonationFilter) ([]models.Impersonation, error) {
+	var where []string
+	var args []interface{}
+	if f.IssueID != "" {
+		where = append(where, "issue_id = ?")
+		args = append(args, NormalizeIssueID(f.IssueID))
+	}
+	if f.Session != "" {
+		where = append(where, "(actor_session = ? OR as_session = ?)")
+		args = append(args, f.Session, f.Session)
+	}
+	if !f.Since.IsZero() {
+		where = append(where, "created_at >= ?")
+		args = append(args, f.Since.UTC().Format(time.RFC3339))
+	}
+	q := `SELECT ` + impersonationColumns + ` FROM impersonations`
+	if len(where) > 0 {
+		q += ` WHERE ` + strings.Join(where, " AND ")
+	}
+	rows, err := db.conn.Query(q+` ORDER BY created_at DESC, rowid DESC`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.Impersonation
+	for rows.Next() {
+		var im models.Impersonation
+		var action, createdAt string
+		if err := rows.Scan(&im.ID, &im.IssueID, &action, &im.ActorSession, &im.AsSession, &im.Before, &im.After, &im.Reason, &createdAt); err != nil {
+			return nil, err
+		}
+		im.Action = models.ImpersonationAction(action)
+		im.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		out = append(out, im)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,49 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestImpersonations(t *testing.T) {
+	database, err := Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+
+	for _, im := range []*models.Impersonation{
+		{IssueID: "td-aaa111", Action: models.ImpersonateReassign, ActorSession: "ses_admin", AsSession: "ses_old", Before: "ses_old", After: "ses_new", Reason: "agent crashed"},
+		{IssueID: "td-bbb222", Action: models.ImpersonateClearReviewer, ActorSession: "ses_admin", AsSession: "ses_rev", Before: "ses_rev", Reason: "reviewer gone"},
+	} {
+		if err := database.RecordImpersonation(im); err != nil {
+			t.Fatalf("RecordImpersonation: %v", err)
+		}
+		if im.ID == "" || im.CreatedAt.IsZero() {
+			t.Fatalf("impersonation not filled in: %+v", im)
+		}
+	}
+
+	all, err := database.ListImpersonations(ImpersonationFilter{})
+	if err != nil || len(all) != 2 {
+		t.Fatalf("ListImpersonations = %d, %v; want 2", len(all), err)
+	}
+	if all[0].Action != models.ImpersonateClearReviewer {
+		t.Errorf("newest first: got %s", all[0].Action)
+	}
+	byIssue, _ := database.ListImpersonations(ImpersonationFilter{IssueID: "td-aaa111"})
+	if len(byIssue) != 1 || byIssue[0].After != "ses_new" || byIssue[0].Reason != "agent crashed" {
+		t.Errorf("ListImpersonations(issue) = %+v", byIssue)
+	}
+	if got, _ := database.ListImpersonations(ImpersonationFilter{Session: "ses_rev"}); len(got) != 1 {
+		t.Errorf("ListImpersonations(as session) = %d, want 1", len(got))
+	}
+	if got, _ := database.ListImpersonations(ImpersonationFilter{Session: "ses_admin"}); len(got) != 2 {
+		t.Errorf("ListImpersonations(actor) = %d, want 2", len(got))
+	}
+	if got, _ := database.ListImpersonations(ImpersonationFilter{Since: time.Now().Add(time.Hour)}); len(got) != 0 {
+		t.Errorf("ListImpersonations(since) = %d, want 0", len(got))
+	}
+}
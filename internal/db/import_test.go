@@ -286,7 +286,7 @@ func TestUpsertIssueRaw_PreservesAllFields(t *testing.T) {
 		t.Fatalf("UpsertIssueRaw: %v", err)
 	}
 
-	got, err := database.GetIssue("td-import1")
+	got, err := database.GetIssueWithDeleted("td-import1")
 	if err != nil {
 		t.Fatalf("GetIssue: %v", err)
 	}
@@ -313,7 +313,7 @@ func TestUpsertIssueRaw_PreservesAllFields(t *testing.T) {
 		t.Fatalf("UpsertIssueRaw replace: %v", err)
 	}
 
-	got2, err := database.GetIssue("td-import1")
+	got2, err := database.GetIssueWithDeleted("td-import1")
 	if err != nil {
 		t.Fatalf("GetIssue after replace: %v", err)
 	}
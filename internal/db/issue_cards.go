@@ -0,0 +1,210 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/models"
+)
+
+const issueCardColumns = `id, title, status, priority, points, labels, blocked_by_count, comment_count, last_activity`
+
+// GetIssueCards returns the cards for the given issues, keyed by ID.
+// Deleted and unknown issues have no card.
+func (db *DB) GetIssueCards(ids []string) (map[string]models.IssueCard, error) {
+	cards := make(map[string]models.IssueCard, len(ids))
+	if len(ids) == 0 {
+		return cards, nil
+	}
+
+	seen := make(map[string]bool)
+	placeholders := make([]string, 0, len(ids))
+	args := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		nid := NormalizeIssueID(id)
+		if !seen[nid] {
+			seen[nid] = true
+			placeholders = append(placeholders, "?")
+			args = append(args, nid)
+		}
+	}
+
+	rows, err := db.conn.Query(fmt.Sprintf(`SELECT `+issueCardColumns+` FROM issue_cards WHERE id IN (%s)`, strings.Join(placeholders, ",")), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		card, err := scanIssueCard(rows)
+		if err != nil {
+			return nil, err
+		}
+		cards[card.ID] = *card
+	}
+	return cards, rows.Err()
+}
+
+// GetDependencyBlockedIDs returns the issues with at least one depends_on
+// target that is not closed, and those stacked on an issue that is not
+// closed
+func (db *DB) GetDependencyBlockedIDs() (map[string]bool, error) {
+	rows, err := db.conn.Query(`
+		SELECT id FROM issue_cards WHERE blocked_by_count > 0
+		UNION
+		SELECT d.issue_id FROM issue_dependencies d JOIN issues b ON b.id = d.depends_on_id
+		WHERE d.relation_type = 'stacked_on' AND b.status != 'closed' AND b.deleted_at IS NULL
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	blocked := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		blocked[id] = true
+	}
+	return blocked, rows.Err()
+}
+
+// RebuildIssueCards recomputes every card from the source tables. Triggers
+// keep cards current, so this is only needed to repair drift.
+func (db *DB) RebuildIssueCards() (int, error) {
+	var n int
+	err := db.withWriteLock(func() error {
+		var err error
+		n, err = db.rebuildIssueCards()
+		return err
+	})
+	return n, err
+}
+
+// rebuildIssueCards recomputes every card without taking the write lock
+func (db *DB) rebuildIssueCards() (int, error) {
+	if _, err := db.conn.Exec(`DELETE FROM issue_cards`); err != nil {
+		return 0, err
+	}
+	res, err := db.conn.Exec(`
+		INSERT INTO issue_cards (` + issueCardColumns + `)
+		SELECT i.id, i.title, i.status, i.priority, COALESCE(i.points, 0), COALESCE(i.labels, ''),
+			(SELECT COUNT(*) FROM issue_dependencies d JOIN issues b ON b.id = d.depends_on_id
+			 WHERE d.issue_id = i.id AND d.relation_type = 'depends_on' AND b.status != 'closed'),
+			(SELECT COUNT(*) FROM comments WHERE issue_id = i.id),
+			i.updated_at
+		FROM issues i WHERE i.deleted_at IS NULL
+	`)
+	if err != nil {
+		return 0, err
+	}
+	n, _ := res.RowsAffected()
+
+	// Timestamps are stored in more than one text format, so the latest
+	// activity is picked after parsing rather than with MAX() in SQL
+	latest := make(map[string]time.Time)
+	for _, q := range []string{
+		`SELECT id, last_activity FROM issue_cards`,
+		`SELECT issue_id, created_at FROM comments`,
+		`SELECT issue_id, timestamp FROM logs WHERE issue_id != ''`,
+	} {
+		if err := db.latestActivity(q, latest); err != nil {
+			return 0, err
+		}
+	}
+	stmt, err := db.conn.Prepare(`UPDATE issue_cards SET last_activity = ? WHERE id = ?`)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+	for id, at := range latest {
+		if _, err := stmt.Exec(at, id); err != nil {
+			return 0, err
+		}
+	}
+	return int(n), nil
+}
+
+// latestActivity folds (issue ID, time) rows into the latest time per issue
+func (db *DB) latestActivity(query string, latest map[string]time.Time) error {
+	rows, err := db.conn.Query(query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		var at sql.NullTime
+		if err := rows.Scan(&id, &at); err != nil {
+			return err
+		}
+		if at.Valid && at.Time.After(latest[id]) {
+			latest[id] = at.Time
+		}
+	}
+	return rows.Err()
+}
+
+func scanIssueCard(rows *sql.Rows) (*models.IssueCard, error) {
+	var card models.IssueCard
+	var labels string
+	var lastActivity sql.NullTime
+	if err := rows.Scan(&card.ID, &card.Title, &card.Status, &card.Priority, &card.Points, &labels,
+		&card.BlockedBy, &card.Comments, &lastActivity); err != nil {
+		return nil, err
+	}
+	card.Labels = []string{}
+	if labels != "" {
+		card.Labels = strings.Split(labels, ",")
+	}
+	card.LastActivity = lastActivity.Time
+	return &card, nil
+}
+
+// GetSummary counts the cards by status and returns the latest activity
+// across them. Every status is present in ByStatus, zero or not.
+func (db *DB) GetSummary() (*models.Summary, error) {
+	sum := &models.Summary{ByStatus: map[models.Status]int{
+		models.StatusOpen:       0,
+		models.StatusInProgress: 0,
+		models.StatusBlocked:    0,
+		models.StatusInReview:   0,
+		models.StatusClosed:     0,
+	}}
+	rows, err := db.conn.Query(`
+		SELECT status, COUNT(*), SUM(CASE WHEN priority = 'P0' THEN 1 ELSE 0 END)
+		FROM issue_cards GROUP BY status
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var status models.Status
+		var n, p0 int
+		if err := rows.Scan(&status, &n, &p0); err != nil {
+			return nil, err
+		}
+		sum.ByStatus[status] = n
+		if status != models.StatusClosed {
+			sum.Open += n
+			sum.OpenP0 += p0
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sum.ReviewQueue = sum.ByStatus[models.StatusInReview]
+
+	latest := make(map[string]time.Time)
+	if err := db.latestActivity(`SELECT '', last_activity FROM issue_cards`, latest); err != nil {
+		return nil, err
+	}
+	if at, ok := latest[""]; ok {
+		sum.LastActivity = &at
+	}
+	return sum, nil
+}
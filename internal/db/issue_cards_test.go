@@ -0,0 +1,138 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestIssueCardsFollowWrites(t *testing.T) {
+	database, err := Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+
+	card := func(id string) (models.IssueCard, bool) {
+		t.Helper()
+		cards, err := database.GetIssueCards([]string{id})
+		if err != nil {
+			t.Fatalf("GetIssueCards: %v", err)
+		}
+		c, ok := cards[id]
+		return c, ok
+	}
+
+	blocker := &models.Issue{Title: "Schema change", Priority: models.PriorityP1}
+	issue := &models.Issue{Title: "Backfill", Labels: []string{"db", "ops"}, Points: 3}
+	for _, is := range []*models.Issue{blocker, issue} {
+		if err := database.CreateIssueLogged(is, "ses_a"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c, ok := card(issue.ID)
+	if !ok || c.Title != "Backfill" || c.Points != 3 || len(c.Labels) != 2 || c.BlockedBy != 0 || c.Comments != 0 {
+		t.Fatalf("new card = %+v (found %v)", c, ok)
+	}
+
+	if err := database.AddDependencyLogged(issue.ID, blocker.ID, "depends_on", "ses_a"); err != nil {
+		t.Fatal(err)
+	}
+	if c, _ := card(issue.ID); c.BlockedBy != 1 {
+		t.Errorf("blocked_by after dep = %d, want 1", c.BlockedBy)
+	}
+	if blocked, _ := database.GetDependencyBlockedIDs(); !blocked[issue.ID] || blocked[blocker.ID] {
+		t.Errorf("dependency-blocked = %v", blocked)
+	}
+
+	blocker.Status = models.StatusClosed
+	if err := database.UpdateIssueLogged(blocker, "ses_a", models.ActionClose); err != nil {
+		t.Fatal(err)
+	}
+	if c, _ := card(issue.ID); c.BlockedBy != 0 {
+		t.Errorf("blocked_by after closing blocker = %d, want 0", c.BlockedBy)
+	}
+	if c, _ := card(blocker.ID); c.Status != models.StatusClosed {
+		t.Errorf("blocker card status = %s", c.Status)
+	}
+
+	before, _ := card(issue.ID)
+	time.Sleep(10 * time.Millisecond)
+	comment := &models.Comment{IssueID: issue.ID, SessionID: "ses_a", Text: "started"}
+	if err := database.AddComment(comment); err != nil {
+		t.Fatal(err)
+	}
+	c, _ = card(issue.ID)
+	if c.Comments != 1 || !c.LastActivity.After(before.LastActivity) {
+		t.Errorf("card after comment = %+v, last activity before %v", c, before.LastActivity)
+	}
+	// Rebuilding from scratch matches the incremental result
+	want, _ := card(issue.ID)
+	if n, err := database.RebuildIssueCards(); err != nil || n != 2 {
+		t.Fatalf("RebuildIssueCards = %d, %v", n, err)
+	}
+	if got, _ := card(issue.ID); got.BlockedBy != want.BlockedBy || got.Comments != want.Comments || !got.LastActivity.Equal(want.LastActivity) {
+		t.Errorf("rebuilt card = %+v, want %+v", got, want)
+	}
+
+	if err := database.DeleteCommentLogged(comment.ID, "ses_a"); err != nil {
+		t.Fatal(err)
+	}
+	if c, _ := card(issue.ID); c.Comments != 0 {
+		t.Errorf("comments after delete = %d", c.Comments)
+	}
+
+	if err := database.DeleteIssueLogged(issue.ID, "ses_a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := card(issue.ID); ok {
+		t.Error("deleted issue still has a card")
+	}
+}
+
+func TestGetSummary(t *testing.T) {
+	database, err := Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+
+	sum, err := database.GetSummary()
+	if err != nil || len(sum.ByStatus) != 5 || sum.LastActivity != nil {
+		t.Fatalf("empty summary = %+v, %v", sum, err)
+	}
+
+	issues := []*models.Issue{
+		{Title: "Outage", Priority: models.PriorityP0},
+		{Title: "Fixed outage", Priority: models.PriorityP0},
+		{Title: "Review me", Priority: models.PriorityP1},
+		{Title: "Gone", Priority: models.PriorityP0},
+	}
+	for _, is := range issues {
+		if err := database.CreateIssueLogged(is, "ses_a"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	issues[1].Status = models.StatusClosed
+	issues[2].Status = models.StatusInReview
+	for _, is := range issues[1:3] {
+		if err := database.UpdateIssueLogged(is, "ses_a", models.ActionUpdate); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := database.DeleteIssueLogged(issues[3].ID, "ses_a"); err != nil {
+		t.Fatal(err)
+	}
+
+	sum, err = database.GetSummary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum.ByStatus[models.StatusOpen] != 1 || sum.ByStatus[models.StatusClosed] != 1 || sum.ByStatus[models.StatusInReview] != 1 {
+		t.Errorf("by status = %v", sum.ByStatus)
+	}
+	if sum.Open != 2 || sum.OpenP0 != 1 || sum.ReviewQueue != 1 || sum.LastActivity == nil {
+		t.Errorf("summary = %+v", sum)
+	}
+}
@@ -0,0 +1,274 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/models"
+)
+
+// IssueReferences lists what still points at an issue: live children
+// (parent_id), dependency edges with live issues in either direction,
+// board positions and the project focus. Deleting the issue would leave
+// each of them dangling.
+type IssueReferences struct {
+	IssueID      string   `json:"issue_id"`
+	Children     []string `json:"children,omitempty"`
+	Dependents   []string `json:"dependents,omitempty"`   // issues that depend on it
+	Dependencies []string `json:"dependencies,omitempty"` // issues it depends on
+	Boards       []string `json:"boards,omitempty"`       // boards that position it
+	Focused      bool     `json:"focused,omitempty"`
+}
+
+// Empty reports whether nothing references the issue
+func (r *IssueReferences) Empty() bool {
+	return len(r.Children) == 0 && len(r.Dependents) == 0 && len(r.Dependencies) == 0 &&
+		len(r.Boards) == 0 && !r.Focused
+}
+
+// String summarizes the references, e.g. "children td-a1, td-b2; board bd-x"
+func (r *IssueReferences) String() string {
+	var parts []string
+	add := func(label string, ids []string) {
+		if len(ids) > 0 {
+			parts = append(parts, label+" "+strings.Join(ids, ", "))
+		}
+	}
+	add("children", r.Children)
+	add("dependents", r.Dependents)
+	add("dependencies", r.Dependencies)
+	add("boards", r.Boards)
+	if r.Focused {
+		parts = append(parts, "focus")
+	}
+	return strings.Join(parts, "; ")
+}
+
+// ReferencesError is returned when deleting an issue that is still
+// referenced without cascading
+type ReferencesError struct {
+	Refs *IssueReferences
+}
+
+func (e *ReferencesError) Error() string {
+	return fmt.Sprintf("cannot delete %s: still referenced by %s", e.Refs.IssueID, e.Refs)
+}
+
+// GetIssueReferences returns what still references an issue
+func (db *DB) GetIssueReferences(issueID string) (*IssueReferences, error) {
+	issueID = NormalizeIssueID(issueID)
+	refs := &IssueReferences{IssueID: issueID}
+
+	queries := []struct {
+		dest  *[]string
+		query string
+	}{
+		{&refs.Children, `SELECT id FROM issues WHERE parent_id = ? AND deleted_at IS NULL ORDER BY id`},
+		{&refs.Dependents, `SELECT d.issue_id FROM issue_dependencies d
+			JOIN issues i ON i.id = d.issue_id AND i.deleted_at IS NULL
+			WHERE d.depends_on_id = ? ORDER BY d.issue_id`},
+		{&refs.Dependencies, `SELECT d.depends_on_id FROM issue_dependencies d
+			JOIN issues i ON i.id = d.depends_on_id AND i.deleted_at IS NULL
+			WHERE d.issue_id = ? ORDER BY d.depends_on_id`},
+		{&refs.Boards, `SELECT board_id FROM board_issue_positions WHERE issue_id = ? AND deleted_at IS NULL ORDER BY board_id`},
+	}
+	for _, q := range queries {
+		ids, err := db.queryIDs(q.query, issueID)
+		if err != nil {
+			return nil, err
+		}
+		*q.dest = ids
+	}
+
+	if focused, err := config.GetFocus(db.baseDir); err == nil && focused == issueID {
+		refs.Focused = true
+	}
+	return refs, nil
+}
+
+// queryIDs runs a query selecting a single string column
+func (db *DB) queryIDs(query string, args ...interface{}) ([]string, error) {
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// DeleteIssueRefsLogged soft-deletes an issue and logs the action. An issue
+// that is still referenced is refused with a *ReferencesError unless cascade
+// is set; then, in the same transaction, its children are detached, its
+// dependency edges and board positions removed, each with its own action
+// log entry, and the focus is cleared once the delete commits. Returns the
+// references that were cleaned up.
+func (db *DB) DeleteIssueRefsLogged(issueID, sessionID string, cascade bool) (*IssueReferences, error) {
+	issueID = NormalizeIssueID(issueID)
+	var refs *IssueReferences
+	err := db.withWriteLock(func() error {
+		prev, err := db.scanIssueRow(issueID)
+		if err != nil {
+			return err
+		}
+		if refs, err = db.GetIssueReferences(issueID); err != nil {
+			return err
+		}
+		if !refs.Empty() && !cascade {
+			return &ReferencesError{Refs: refs}
+		}
+
+		// Read everything the cleanup rewrites before the transaction
+		// holds the only connection
+		children := make([]*models.Issue, 0, len(refs.Children))
+		for _, id := range refs.Children {
+			child, err := db.scanIssueRow(id)
+			if err != nil {
+				return err
+			}
+			children = append(children, child)
+		}
+		deps, err := db.issueDependencyRows(issueID)
+		if err != nil {
+			return err
+		}
+		positions, err := db.issuePositionRows(issueID)
+		if err != nil {
+			return err
+		}
+
+		now := clock.Now()
+		tx, err := db.conn.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		for _, child := range children {
+			previousData := marshalIssue(child)
+			child.ParentID = ""
+			child.UpdatedAt = now
+			if _, err := tx.Exec(`UPDATE issues SET parent_id = '', updated_at = ? WHERE id = ?`, now, child.ID); err != nil {
+				return err
+			}
+			if err := logActionTx(tx, sessionID, models.ActionUpdate, "issue", child.ID, previousData, marshalIssue(child), now); err != nil {
+				return err
+			}
+		}
+		for _, d := range deps {
+			if _, err := tx.Exec(`DELETE FROM issue_dependencies WHERE id = ?`, d.id); err != nil {
+				return err
+			}
+			previousData := marshalDependency(d.id, d.issueID, d.dependsOnID, d.relationType)
+			if err := logActionTx(tx, sessionID, models.ActionRemoveDep, "issue_dependencies", d.id, previousData, "", now); err != nil {
+				return err
+			}
+		}
+		for _, p := range positions {
+			if _, err := tx.Exec(`UPDATE board_issue_positions SET deleted_at = ? WHERE id = ?`, now.UTC(), p.id); err != nil {
+				return err
+			}
+			previousData, _ := json.Marshal(map[string]interface{}{
+				"id": p.id, "board_id": p.boardID, "issue_id": issueID, "position": p.position,
+			})
+			if err := logActionTx(tx, sessionID, models.ActionBoardUnposition, "board_issue_positions", p.id, string(previousData), "", now); err != nil {
+				return err
+			}
+		}
+
+		// The delete itself is logged last so undo reverses it first
+		if _, err := tx.Exec(`UPDATE issues SET deleted_at = ?, updated_at = ? WHERE id = ?`, now, now, issueID); err != nil {
+			return err
+		}
+		if err := logActionTx(tx, sessionID, models.ActionDelete, "issue", issueID, marshalIssue(prev), "", now); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if refs.Focused {
+		if err := config.ClearFocus(db.baseDir); err != nil {
+			return refs, fmt.Errorf("clear focus: %w", err)
+		}
+	}
+	return refs, nil
+}
+
+// logActionTx appends an action log entry within tx
+func logActionTx(tx *sql.Tx, sessionID string, action models.ActionType, entityType, entityID, previousData, newData string, ts time.Time) error {
+	actionID, err := generateActionID()
+	if err != nil {
+		return fmt.Errorf("generate action ID: %w", err)
+	}
+	_, err = tx.Exec(`INSERT INTO action_log (id, session_id, action_type, entity_type, entity_id, previous_data, new_data, timestamp, undone) VALUES (?, ?, ?, ?, ?, ?, ?, ?, 0)`,
+		actionID, sessionID, string(action), entityType, entityID, previousData, newData, formatActionLogTimestamp(ts))
+	if err != nil {
+		return fmt.Errorf("log action: %w", err)
+	}
+	return nil
+}
+
+type dependencyRow struct {
+	id, issueID, dependsOnID, relationType string
+}
+
+// issueDependencyRows returns every dependency edge touching an issue
+func (db *DB) issueDependencyRows(issueID string) ([]dependencyRow, error) {
+	rows, err := db.conn.Query(`SELECT id, issue_id, depends_on_id, relation_type FROM issue_dependencies
+		WHERE issue_id = ? OR depends_on_id = ?`, issueID, issueID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deps []dependencyRow
+	for rows.Next() {
+		var d dependencyRow
+		if err := rows.Scan(&d.id, &d.issueID, &d.dependsOnID, &d.relationType); err != nil {
+			return nil, err
+		}
+		deps = append(deps, d)
+	}
+	return deps, rows.Err()
+}
+
+type positionRow struct {
+	id, boardID string
+	position    int
+}
+
+// issuePositionRows returns an issue's live board positions
+func (db *DB) issuePositionRows(issueID string) ([]positionRow, error) {
+	rows, err := db.conn.Query(`SELECT id, board_id, position FROM board_issue_positions
+		WHERE issue_id = ? AND deleted_at IS NULL`, issueID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var positions []positionRow
+	for rows.Next() {
+		var p positionRow
+		if err := rows.Scan(&p.id, &p.boardID, &p.position); err != nil {
+			return nil, err
+		}
+		positions = append(positions, p)
+	}
+	return positions, rows.Err()
+}
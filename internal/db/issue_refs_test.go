@@ -0,0 +1,101 @@
+package db
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/models"
+)
+
+func TestDeleteIssueRefsLogged(t *testing.T) {
+	dir := t.TempDir()
+	database, err := Initialize(dir)
+	if err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	defer database.Close()
+
+	parent := &models.Issue{Title: "Parent", Type: models.TypeEpic}
+	if err := database.CreateIssue(parent); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+	child := &models.Issue{Title: "Child", ParentID: parent.ID}
+	dependent := &models.Issue{Title: "Dependent"}
+	for _, issue := range []*models.Issue{child, dependent} {
+		if err := database.CreateIssue(issue); err != nil {
+			t.Fatalf("CreateIssue: %v", err)
+		}
+	}
+	if err := database.AddDependency(dependent.ID, parent.ID, "depends_on"); err != nil {
+		t.Fatalf("AddDependency: %v", err)
+	}
+	board, err := database.CreateBoard("Refs", "")
+	if err != nil {
+		t.Fatalf("CreateBoard: %v", err)
+	}
+	if err := database.SetIssuePosition(board.ID, parent.ID, 1); err != nil {
+		t.Fatalf("SetIssuePosition: %v", err)
+	}
+	if err := config.SetFocus(dir, parent.ID); err != nil {
+		t.Fatalf("SetFocus: %v", err)
+	}
+
+	// Without cascade the delete is refused and nothing changes
+	_, err = database.DeleteIssueRefsLogged(parent.ID, "sess-1", false)
+	var refErr *ReferencesError
+	if !errors.As(err, &refErr) {
+		t.Fatalf("err = %v, want *ReferencesError", err)
+	}
+	refs := refErr.Refs
+	if len(refs.Children) != 1 || len(refs.Dependents) != 1 || len(refs.Boards) != 1 || !refs.Focused {
+		t.Errorf("refs = %+v, want child, dependent, board and focus", refs)
+	}
+	if _, err := database.GetIssue(parent.ID); err != nil {
+		t.Errorf("refused delete removed the issue: %v", err)
+	}
+
+	// With cascade everything is cleaned up with the delete
+	if _, err := database.DeleteIssueRefsLogged(parent.ID, "sess-1", true); err != nil {
+		t.Fatalf("cascade delete: %v", err)
+	}
+	if got, _ := database.GetIssue(child.ID); got == nil || got.ParentID != "" {
+		t.Errorf("child = %+v, want detached", got)
+	}
+	if deps, _ := database.GetDependencies(dependent.ID); len(deps) != 0 {
+		t.Errorf("dependent still depends on %v", deps)
+	}
+	if positions, _ := database.GetBoardIssuePositions(board.ID); len(positions) != 0 {
+		t.Errorf("board positions = %v, want none", positions)
+	}
+	if focus, _ := config.GetFocus(dir); focus != "" {
+		t.Errorf("focus = %q, want cleared", focus)
+	}
+	after, err := database.GetIssueReferences(parent.ID)
+	if err != nil || !after.Empty() {
+		t.Errorf("references after cascade = %+v, %v", after, err)
+	}
+
+	// Every cleanup is in the action log, with the delete last
+	rows, err := database.conn.Query(`SELECT action_type FROM action_log WHERE session_id = 'sess-1' ORDER BY rowid`)
+	if err != nil {
+		t.Fatalf("query action_log: %v", err)
+	}
+	defer rows.Close()
+	var actions []string
+	for rows.Next() {
+		var a string
+		rows.Scan(&a)
+		actions = append(actions, a)
+	}
+	want := []string{string(models.ActionUpdate), string(models.ActionRemoveDep), string(models.ActionBoardUnposition), string(models.ActionDelete)}
+	if len(actions) != len(want) {
+		t.Fatalf("actions = %v, want %v", actions, want)
+	}
+	for i := range want {
+		if actions[i] != want[i] {
+			t.Errorf("actions = %v, want %v", actions, want)
+			break
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/marcus/td/internal/clock"
 	"github.com/marcus/td/internal/models"
 )
 
@@ -53,6 +54,40 @@ func (db *DB) getDescendants(parentID string) ([]string, error) {
 	return descendants, nil
 }
 
+// IssueRelationCounts holds the sizes of an issue's related collections
+type IssueRelationCounts struct {
+	Logs         int `json:"logs"`
+	Comments     int `json:"comments"`
+	Handoffs     int `json:"handoffs"`
+	Dependencies int `json:"dependencies"`
+	BlockedBy    int `json:"blocked_by"`
+	Children     int `json:"children"`
+	Decisions    int `json:"decisions"`
+	Reworks      int `json:"reworks"`
+}
+
+// CountIssueRelations counts an issue's logs, comments, handoffs,
+// dependency edges, live children, linked decisions and reworks in one query. Logs
+// are counted the same way GetLogs selects them.
+func (db *DB) CountIssueRelations(issueID string) (IssueRelationCounts, error) {
+	var c IssueRelationCounts
+	err := db.conn.QueryRow(`
+		SELECT
+			(SELECT COUNT(*) FROM logs l WHERE l.issue_id = ?1
+				OR (l.issue_id = '' AND l.work_session_id IN (
+					SELECT work_session_id FROM work_session_issues WHERE issue_id = ?1
+				))),
+			(SELECT COUNT(*) FROM comments WHERE issue_id = ?1),
+			(SELECT COUNT(*) FROM handoffs WHERE issue_id = ?1),
+			(SELECT COUNT(*) FROM issue_dependencies WHERE issue_id = ?1 AND relation_type = 'depends_on'),
+			(SELECT COUNT(*) FROM issue_dependencies WHERE depends_on_id = ?1 AND relation_type = 'depends_on'),
+			(SELECT COUNT(*) FROM issues WHERE parent_id = ?1 AND deleted_at IS NULL),
+			(SELECT COUNT(*) FROM decision_issues WHERE issue_id = ?1),
+			(SELECT COUNT(*) FROM issue_reworks WHERE issue_id = ?1)
+	`, issueID).Scan(&c.Logs, &c.Comments, &c.Handoffs, &c.Dependencies, &c.BlockedBy, &c.Children, &c.Decisions, &c.Reworks)
+	return c, err
+}
+
 // HasChildren returns true if the issue has any child issues
 func (db *DB) HasChildren(issueID string) (bool, error) {
 	var count int
@@ -68,7 +103,7 @@ func (db *DB) GetDirectChildren(issueID string) ([]*models.Issue, error) {
 	rows, err := db.conn.Query(`
 		SELECT id, title, description, status, type, priority, points, labels, parent_id, acceptance, sprint,
 		       implementer_session, creator_session, reviewer_session, created_at, updated_at, closed_at, deleted_at, minor, created_branch,
-		       defer_until, due_date, defer_count
+		       defer_until, due_date, defer_count, created_repo, blocked_reason, blocked_ref, inbox, confidential, rank
 		FROM issues WHERE parent_id = ? AND deleted_at IS NULL
 	`, issueID)
 	if err != nil {
@@ -83,7 +118,7 @@ func (db *DB) GetDirectChildren(issueID string) ([]*models.Issue, error) {
 		var closedAt, deletedAt sql.NullTime
 		var parentID, acceptance, sprint sql.NullString
 		var implSession, creatorSession, reviewerSession sql.NullString
-		var createdBranch sql.NullString
+		var createdBranch, createdRepo, blockedReason, blockedRef sql.NullString
 		var pointsNull sql.NullInt64
 		var deferUntil, dueDate sql.NullString
 
@@ -91,7 +126,7 @@ func (db *DB) GetDirectChildren(issueID string) ([]*models.Issue, error) {
 			&issue.ID, &issue.Title, &issue.Description, &issue.Status, &issue.Type, &issue.Priority,
 			&pointsNull, &labels, &parentID, &acceptance, &sprint,
 			&implSession, &creatorSession, &reviewerSession, &issue.CreatedAt, &issue.UpdatedAt, &closedAt, &deletedAt, &issue.Minor, &createdBranch,
-			&deferUntil, &dueDate, &issue.DeferCount,
+			&deferUntil, &dueDate, &issue.DeferCount, &createdRepo, &blockedReason, &blockedRef, &issue.Inbox, &issue.Confidential, &issue.Rank,
 		)
 		if err != nil {
 			return nil, err
@@ -114,6 +149,9 @@ func (db *DB) GetDirectChildren(issueID string) ([]*models.Issue, error) {
 		issue.CreatorSession = creatorSession.String
 		issue.ReviewerSession = reviewerSession.String
 		issue.CreatedBranch = createdBranch.String
+		issue.CreatedRepo = createdRepo.String
+		issue.BlockedReason = models.BlockedReason(blockedReason.String)
+		issue.BlockedRef = blockedRef.String
 		if deferUntil.Valid {
 			issue.DeferUntil = &deferUntil.String
 		}
@@ -228,7 +266,7 @@ func (db *DB) cascadeUpParentStatusLocked(issueID string, targetStatus models.St
 	// All children at target - update parent
 	parent.Status = targetStatus
 	if targetStatus == models.StatusClosed {
-		now := time.Now()
+		now := clock.Now()
 		parent.ClosedAt = &now
 	}
 
@@ -297,6 +335,14 @@ func (db *DB) cascadeUnblockDependentsLocked(closedIssueID, sessionID string) (i
 		if issue.Status != models.StatusBlocked {
 			continue
 		}
+		// Closing a dependency doesn't resolve an external wait or a
+		// pending decision
+		if issue.BlockedReason == models.BlockedReasonExternal || issue.BlockedReason == models.BlockedReasonDecision {
+			continue
+		}
+		if waiting, err := db.hasUnsatisfiedExternal(depID); err != nil || waiting {
+			continue
+		}
 
 		// Check if ALL dependencies of this issue are now closed
 		deps, err := db.GetDependencies(depID)
@@ -306,6 +352,13 @@ func (db *DB) cascadeUnblockDependentsLocked(closedIssueID, sessionID string) (i
 
 		allClosed := true
 		for _, d := range deps {
+			// Another project's issues can't be checked from here: a
+			// cross-project dependency keeps the dependent blocked until
+			// it is removed or the dependent is unblocked by hand.
+			if isCrossProjectID(d) {
+				allClosed = false
+				break
+			}
 			depIssue, err := db.GetIssue(d)
 			if err != nil || depIssue == nil {
 				allClosed = false
@@ -443,14 +496,19 @@ func (db *DB) GetDependencyByDepID(depID string) (*models.IssueDependency, error
 
 // GetIssuesWithOpenDeps returns a set of issue IDs that have at least one open (non-closed) dependency.
 // This is used by the is_ready() and has_open_deps() query functions.
+// Cross-project dependencies always count as open, matching CascadeUnblockDependents.
+// An issue stacked on one that isn't closed counts too: stacks land in order.
 func (db *DB) GetIssuesWithOpenDeps() (map[string]bool, error) {
 	rows, err := db.conn.Query(`
 		SELECT DISTINCT d.issue_id
 		FROM issue_dependencies d
 		JOIN issues i ON d.depends_on_id = i.id
-		WHERE d.relation_type = 'depends_on'
+		WHERE d.relation_type IN ('depends_on', 'stacked_on')
 		  AND i.status != 'closed'
 		  AND i.deleted_at IS NULL
+		UNION
+		SELECT issue_id FROM issue_dependencies
+		WHERE relation_type = 'depends_on' AND depends_on_id LIKE '%/%'
 	`)
 	if err != nil {
 		return nil, err
@@ -468,6 +526,28 @@ func (db *DB) GetIssuesWithOpenDeps() (map[string]bool, error) {
 	return result, nil
 }
 
+// HasOpenDependencies reports whether issueID depends on any issue that
+// isn't closed. Dependencies on other projects count as open.
+func (db *DB) HasOpenDependencies(issueID string) (bool, error) {
+	deps, err := db.GetDependencies(issueID)
+	if err != nil || len(deps) == 0 {
+		return false, err
+	}
+	statuses, err := db.GetIssueStatuses(deps)
+	if err != nil {
+		return false, err
+	}
+	for _, d := range deps {
+		if isCrossProjectID(d) {
+			return true, nil
+		}
+		if status, ok := statuses[NormalizeIssueID(d)]; ok && status != models.StatusClosed {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // GetIssueStatuses fetches statuses for multiple issues in a single query
 func (db *DB) GetIssueStatuses(ids []string) (map[string]models.Status, error) {
 	if len(ids) == 0 {
@@ -511,6 +591,40 @@ func (db *DB) GetIssueStatuses(ids []string) (map[string]models.Status, error) {
 	return statuses, nil
 }
 
+// ============================================================================
+// Stack Functions
+// ============================================================================
+
+// GetStackBases returns every stacked_on relation as a map from each
+// stacked issue to the issue it is stacked on. Relations touching deleted
+// issues are left out.
+func (db *DB) GetStackBases() (map[string]string, error) {
+	rows, err := db.conn.Query(`
+		SELECT d.issue_id, d.depends_on_id FROM issue_dependencies d
+		JOIN issues i ON i.id = d.issue_id AND i.deleted_at IS NULL
+		JOIN issues b ON b.id = d.depends_on_id AND b.deleted_at IS NULL
+		WHERE d.relation_type = 'stacked_on'
+		ORDER BY d.issue_id, d.depends_on_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	bases := make(map[string]string)
+	for rows.Next() {
+		var issueID, baseID string
+		if err := rows.Scan(&issueID, &baseID); err != nil {
+			return nil, err
+		}
+		// Keep the first if sync ever leaves an issue on two bases
+		if _, ok := bases[issueID]; !ok {
+			bases[issueID] = baseID
+		}
+	}
+	return bases, rows.Err()
+}
+
 // ============================================================================
 // Issue File Functions
 // ============================================================================
@@ -524,7 +638,7 @@ func (db *DB) LinkFile(issueID, filePath string, role models.FileRole, sha strin
 		_, err := db.conn.Exec(`
 			INSERT OR REPLACE INTO issue_files (id, issue_id, file_path, role, linked_sha, linked_at)
 			VALUES (?, ?, ?, ?, ?, ?)
-		`, id, issueID, filePath, role, sha, time.Now())
+		`, id, issueID, filePath, role, sha, clock.Now())
 		return err
 	})
 }
@@ -577,7 +691,7 @@ func (db *DB) RecordSessionAction(issueID, sessionID string, action models.Issue
 		_, err = db.conn.Exec(`
 			INSERT INTO issue_session_history (id, issue_id, session_id, action, created_at)
 			VALUES (?, ?, ?, ?, ?)
-		`, id, issueID, sessionID, action, time.Now())
+		`, id, issueID, sessionID, action, clock.Now())
 		return err
 	})
 }
@@ -633,6 +747,46 @@ func (db *DB) GetSessionHistory(issueID string) ([]models.IssueSessionHistory, e
 	return history, nil
 }
 
+// GetFirstStartTimes returns when each issue was first started, for the
+// issues that have been. Issues never started are absent from the map.
+func (db *DB) GetFirstStartTimes(ids []string) (map[string]time.Time, error) {
+	starts := make(map[string]time.Time)
+	if len(ids) == 0 {
+		return starts, nil
+	}
+
+	placeholders := make([]string, len(ids))
+	args := make([]interface{}, 0, len(ids)+1)
+	args = append(args, models.ActionSessionStarted)
+	for i, id := range ids {
+		placeholders[i] = "?"
+		args = append(args, NormalizeIssueID(id))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT issue_id, created_at FROM issue_session_history
+		WHERE action = ? AND issue_id IN (%s)
+		ORDER BY created_at ASC
+	`, strings.Join(placeholders, ","))
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		var at time.Time
+		if err := rows.Scan(&id, &at); err != nil {
+			return nil, err
+		}
+		if _, ok := starts[id]; !ok {
+			starts[id] = at
+		}
+	}
+	return starts, rows.Err()
+}
+
 // GetIssueSessionLog returns issues touched by a session
 func (db *DB) GetIssueSessionLog(sessionID string) ([]string, error) {
 	rows, err := db.conn.Query(`
@@ -1506,6 +1506,97 @@ func TestCascadeUnblockDependents_PartialResolution(t *testing.T) {
 	}
 }
 
+func TestCascadeUnblockDependents_CrossProject(t *testing.T) {
+	dir := t.TempDir()
+	db, err := Initialize(dir)
+	if err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	defer db.Close()
+
+	a := &models.Issue{Title: "A", Status: models.StatusClosed}
+	b := &models.Issue{Title: "B", Status: models.StatusBlocked}
+	c := &models.Issue{Title: "C", Status: models.StatusBlocked}
+	db.CreateIssue(a)
+	db.CreateIssue(b)
+	db.CreateIssue(c)
+	// The other project happens to have an issue with the same ID as a
+	external := "api/" + a.ID
+	db.AddDependency(b.ID, a.ID, "depends_on")
+	db.AddDependency(b.ID, external, "depends_on")
+	db.AddDependency(c.ID, external, "depends_on")
+
+	if got := NormalizeIssueID(external); got != external {
+		t.Errorf("NormalizeIssueID(%q) = %q", external, got)
+	}
+
+	count, _ := db.CascadeUnblockDependents(a.ID, "test-session")
+	if count != 0 {
+		t.Errorf("expected 0 unblocked, got %d", count)
+	}
+	for _, id := range []string{b.ID, c.ID} {
+		updated, _ := db.GetIssue(id)
+		if updated.Status != models.StatusBlocked {
+			t.Errorf("%s: expected blocked, got %s", id, updated.Status)
+		}
+	}
+
+	openDeps, err := db.GetIssuesWithOpenDeps()
+	if err != nil {
+		t.Fatalf("GetIssuesWithOpenDeps failed: %v", err)
+	}
+	if !openDeps[b.ID] || !openDeps[c.ID] {
+		t.Errorf("cross-project deps should count as open: %v", openDeps)
+	}
+}
+
+func TestCascadeUnblockDependents_BlockedReason(t *testing.T) {
+	dir := t.TempDir()
+	db, err := Initialize(dir)
+	if err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	defer db.Close()
+
+	dep := &models.Issue{Title: "Dependency", Status: models.StatusOpen}
+	db.CreateIssue(dep)
+	waiting := map[models.BlockedReason]*models.Issue{}
+	for _, reason := range []models.BlockedReason{"", models.BlockedReasonDependency, models.BlockedReasonExternal, models.BlockedReasonDecision} {
+		issue := &models.Issue{Title: "Waiting " + string(reason), Status: models.StatusBlocked, BlockedReason: reason}
+		db.CreateIssue(issue)
+		db.UpdateIssue(issue)
+		db.AddDependency(issue.ID, dep.ID, "depends_on")
+		waiting[reason] = issue
+	}
+
+	if open, _ := db.HasOpenDependencies(waiting[""].ID); !open {
+		t.Error("HasOpenDependencies = false with an open dependency")
+	}
+	dep.Status = models.StatusClosed
+	db.UpdateIssue(dep)
+	if open, _ := db.HasOpenDependencies(waiting[""].ID); open {
+		t.Error("HasOpenDependencies = true after the dependency closed")
+	}
+
+	count, _ := db.CascadeUnblockDependents(dep.ID, "test-session")
+	if count != 2 {
+		t.Errorf("expected 2 unblocked, got %d", count)
+	}
+	for reason, issue := range waiting {
+		updated, _ := db.GetIssue(issue.ID)
+		stillBlocked := reason == models.BlockedReasonExternal || reason == models.BlockedReasonDecision
+		if (updated.Status == models.StatusBlocked) != stillBlocked {
+			t.Errorf("reason %q: status %s", reason, updated.Status)
+		}
+		if !stillBlocked && updated.BlockedReason != "" {
+			t.Errorf("reason %q: not cleared on unblock", reason)
+		}
+		if stillBlocked && updated.BlockedReason != reason {
+			t.Errorf("reason %q: got %q", reason, updated.BlockedReason)
+		}
+	}
+}
+
 func TestCascadeUnblockDependents_NonBlockedSkipped(t *testing.T) {
 	dir := t.TempDir()
 	db, err := Initialize(dir)
@@ -1730,3 +1821,62 @@ func TestCascadeUnblockDependents_UndoData(t *testing.T) {
 		t.Errorf("NewData should contain 'open', got: %s", action.NewData)
 	}
 }
+
+func TestCountIssueRelations(t *testing.T) {
+	db, err := Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	defer db.Close()
+
+	parent := &models.Issue{Title: "Parent"}
+	other := &models.Issue{Title: "Other"}
+	for _, issue := range []*models.Issue{parent, other} {
+		if err := db.CreateIssue(issue); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+	live := &models.Issue{Title: "Live child", ParentID: parent.ID}
+	gone := &models.Issue{Title: "Deleted child", ParentID: parent.ID}
+	for _, issue := range []*models.Issue{live, gone} {
+		if err := db.CreateIssue(issue); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+	if err := db.DeleteIssue(gone.ID); err != nil {
+		t.Fatalf("DeleteIssue failed: %v", err)
+	}
+
+	if err := db.AddDependency(parent.ID, other.ID, "depends_on"); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+	if err := db.AddComment(&models.Comment{IssueID: parent.ID, SessionID: "ses_a", Text: "hi"}); err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+	if err := db.AddLog(&models.Log{IssueID: parent.ID, SessionID: "ses_a", Message: "progress", Type: models.LogTypeProgress}); err != nil {
+		t.Fatalf("AddLog failed: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := db.AddHandoff(&models.Handoff{IssueID: parent.ID, SessionID: "ses_a", Done: []string{"x"}}); err != nil {
+			t.Fatalf("AddHandoff failed: %v", err)
+		}
+	}
+
+	c, err := db.CountIssueRelations(parent.ID)
+	if err != nil {
+		t.Fatalf("CountIssueRelations failed: %v", err)
+	}
+	want := IssueRelationCounts{Logs: 1, Comments: 1, Handoffs: 2, Dependencies: 1, Children: 1}
+	if c != want {
+		t.Errorf("counts = %+v, want %+v", c, want)
+	}
+
+	if c, _ := db.CountIssueRelations(other.ID); c.BlockedBy != 1 {
+		t.Errorf("other.BlockedBy = %d, want 1", c.BlockedBy)
+	}
+
+	handoffs, err := db.GetHandoffs(parent.ID)
+	if err != nil || len(handoffs) != 2 {
+		t.Errorf("GetHandoffs = %d, %v", len(handoffs), err)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/dateparse"
 	"github.com/marcus/td/internal/models"
 )
 
@@ -24,6 +26,7 @@ type ListIssuesOptions struct {
 	BalancedReviewPolicy bool   // Allow creator-only approvals/reviews when externally implemented
 	ParentID             string
 	EpicID               string // Filter by epic (parent_id matches epic, recursively)
+	Sprint               string
 	PointsMin            int
 	PointsMax            int
 	CreatedAfter         time.Time
@@ -41,7 +44,9 @@ type ListIssuesOptions struct {
 	OverdueOnly          bool // Show ONLY overdue issues (due_date < today, not closed)
 	SurfacingOnly        bool // Show ONLY surfacing issues (defer_until <= today, defer_count > 0)
 	DueSoonDays          int  // Show issues due within N days (0 = disabled)
-	ExcludeHasOpenDeps   bool // Hide issues that have unresolved (non-closed) dependencies
+	ExcludeHasOpenDeps   bool // Hide issues that have unresolved (non-closed) dependencies or stack bases
+	InboxOnly            bool // Show ONLY issues awaiting triage
+	ExcludeInbox         bool // Hide issues awaiting triage
 }
 
 // CreateIssue creates a new issue WITHOUT logging to action_log.
@@ -49,6 +54,9 @@ type ListIssuesOptions struct {
 // This unlogged variant exists for sync receiver applying remote events.
 func (db *DB) CreateIssue(issue *models.Issue) error {
 	return db.withWriteLock(func() error {
+		if err := db.sealIssue(issue); err != nil {
+			return err
+		}
 		if issue.Status == "" {
 			issue.Status = models.StatusOpen
 		}
@@ -59,7 +67,7 @@ func (db *DB) CreateIssue(issue *models.Issue) error {
 			issue.Priority = models.PriorityP2
 		}
 
-		now := time.Now()
+		now := clock.Now()
 		issue.CreatedAt = now
 		issue.UpdatedAt = now
 
@@ -68,7 +76,7 @@ func (db *DB) CreateIssue(issue *models.Issue) error {
 		// Retry loop for rare ID collisions (6 hex chars = 16.7M keyspace)
 		const maxRetries = 3
 		for attempt := 0; attempt < maxRetries; attempt++ {
-			id, err := generateID()
+			id, err := db.generateIssueID()
 			if err != nil {
 				return err
 			}
@@ -84,9 +92,9 @@ func (db *DB) CreateIssue(issue *models.Issue) error {
 			}
 
 			_, err = db.conn.Exec(`
-				INSERT INTO issues (id, title, description, status, type, priority, points, labels, parent_id, acceptance, created_at, updated_at, minor, created_branch, creator_session, defer_until, due_date, defer_count)
-				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-			`, issue.ID, issue.Title, issue.Description, issue.Status, issue.Type, issue.Priority, issue.Points, labels, issue.ParentID, issue.Acceptance, issue.CreatedAt, issue.UpdatedAt, issue.Minor, issue.CreatedBranch, issue.CreatorSession, deferUntil, dueDate, issue.DeferCount)
+				INSERT INTO issues (id, title, description, status, type, priority, points, labels, parent_id, acceptance, sprint, created_at, updated_at, minor, created_branch, created_repo, creator_session, defer_until, due_date, defer_count, inbox, confidential, rank)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			`, issue.ID, issue.Title, issue.Description, issue.Status, issue.Type, issue.Priority, issue.Points, labels, issue.ParentID, issue.Acceptance, issue.Sprint, issue.CreatedAt, issue.UpdatedAt, issue.Minor, issue.CreatedBranch, issue.CreatedRepo, issue.CreatorSession, deferUntil, dueDate, issue.DeferCount, issue.Inbox, issue.Confidential, issue.Rank)
 
 			if err == nil {
 				return nil
@@ -101,28 +109,42 @@ func (db *DB) CreateIssue(issue *models.Issue) error {
 }
 
 // GetIssue retrieves an issue by ID
-// Accepts bare IDs without the td- prefix (e.g., "abc123" becomes "td-abc123")
+// Accepts bare IDs without the prefix (e.g., "abc123" becomes "td-abc123")
+// Soft-deleted issues are reported as not found; see GetIssueWithDeleted.
 func (db *DB) GetIssue(id string) (*models.Issue, error) {
-	id = NormalizeIssueID(id)
+	issue, err := db.GetIssueWithDeleted(id)
+	if err != nil {
+		return nil, err
+	}
+	if issue.DeletedAt != nil {
+		return nil, fmt.Errorf("issue not found: %s", issue.ID)
+	}
+	return issue, nil
+}
+
+// GetIssueWithDeleted retrieves an issue by ID whether or not it is
+// soft-deleted, for restore, undo and admin tooling
+func (db *DB) GetIssueWithDeleted(id string) (*models.Issue, error) {
+	id = db.normalizeIssueID(id)
 	var issue models.Issue
 	var labels string
 	var closedAt, deletedAt sql.NullTime
 	var parentID, acceptance, sprint sql.NullString
 	var implSession, creatorSession, reviewerSession sql.NullString
-	var createdBranch sql.NullString
+	var createdBranch, createdRepo, blockedReason, blockedRef sql.NullString
 	var pointsNull sql.NullInt64
 	var deferUntil, dueDate sql.NullString
 
 	err := db.conn.QueryRow(`
 		SELECT id, title, description, status, type, priority, points, labels, parent_id, acceptance, sprint,
 		       implementer_session, creator_session, reviewer_session, created_at, updated_at, closed_at, deleted_at, minor, created_branch,
-		       defer_until, due_date, defer_count
+		       defer_until, due_date, defer_count, created_repo, blocked_reason, blocked_ref, inbox, confidential, rank
 	FROM issues WHERE id = ?
 	`, id).Scan(
 		&issue.ID, &issue.Title, &issue.Description, &issue.Status, &issue.Type, &issue.Priority,
 		&pointsNull, &labels, &parentID, &acceptance, &sprint,
 		&implSession, &creatorSession, &reviewerSession, &issue.CreatedAt, &issue.UpdatedAt, &closedAt, &deletedAt, &issue.Minor, &createdBranch,
-		&deferUntil, &dueDate, &issue.DeferCount,
+		&deferUntil, &dueDate, &issue.DeferCount, &createdRepo, &blockedReason, &blockedRef, &issue.Inbox, &issue.Confidential, &issue.Rank,
 	)
 
 	if err == sql.ErrNoRows {
@@ -149,6 +171,9 @@ func (db *DB) GetIssue(id string) (*models.Issue, error) {
 	issue.CreatorSession = creatorSession.String
 	issue.ReviewerSession = reviewerSession.String
 	issue.CreatedBranch = createdBranch.String
+	issue.CreatedRepo = createdRepo.String
+	issue.BlockedReason = models.BlockedReason(blockedReason.String)
+	issue.BlockedRef = blockedRef.String
 	if deferUntil.Valid {
 		issue.DeferUntil = &deferUntil.String
 	}
@@ -186,7 +211,7 @@ func (db *DB) GetIssuesByIDs(ids []string) ([]models.Issue, error) {
 	query := fmt.Sprintf(`
 		SELECT id, title, description, status, type, priority, points, labels, parent_id, acceptance, sprint,
 		       implementer_session, creator_session, reviewer_session, created_at, updated_at, closed_at, deleted_at, minor, created_branch,
-		       defer_until, due_date, defer_count
+		       defer_until, due_date, defer_count, created_repo, blocked_reason, blocked_ref, inbox, confidential, rank
 		FROM issues WHERE id IN (%s)
 	`, strings.Join(placeholders, ","))
 
@@ -203,14 +228,14 @@ func (db *DB) GetIssuesByIDs(ids []string) ([]models.Issue, error) {
 		var closedAt, deletedAt sql.NullTime
 		var parentID, acceptance, sprint sql.NullString
 		var implSession, creatorSession, reviewerSession sql.NullString
-		var createdBranch sql.NullString
+		var createdBranch, createdRepo, blockedReason, blockedRef sql.NullString
 		var pointsNull sql.NullInt64
 		var deferUntil, dueDate sql.NullString
 		if err := rows.Scan(
 			&issue.ID, &issue.Title, &issue.Description, &issue.Status, &issue.Type, &issue.Priority,
 			&pointsNull, &labels, &parentID, &acceptance, &sprint,
 			&implSession, &creatorSession, &reviewerSession, &issue.CreatedAt, &issue.UpdatedAt, &closedAt, &deletedAt, &issue.Minor, &createdBranch,
-			&deferUntil, &dueDate, &issue.DeferCount,
+			&deferUntil, &dueDate, &issue.DeferCount, &createdRepo, &blockedReason, &blockedRef, &issue.Inbox, &issue.Confidential, &issue.Rank,
 		); err != nil {
 			return nil, err
 		}
@@ -231,6 +256,9 @@ func (db *DB) GetIssuesByIDs(ids []string) ([]models.Issue, error) {
 		issue.CreatorSession = creatorSession.String
 		issue.ReviewerSession = reviewerSession.String
 		issue.CreatedBranch = createdBranch.String
+		issue.CreatedRepo = createdRepo.String
+		issue.BlockedReason = models.BlockedReason(blockedReason.String)
+		issue.BlockedRef = blockedRef.String
 		if deferUntil.Valid {
 			issue.DeferUntil = &deferUntil.String
 		}
@@ -292,7 +320,10 @@ func (db *DB) GetIssueTitles(ids []string) (map[string]string, error) {
 // This unlogged variant exists for sync receiver applying remote events.
 func (db *DB) UpdateIssue(issue *models.Issue) error {
 	return db.withWriteLock(func() error {
-		issue.UpdatedAt = time.Now()
+		if err := db.sealIssue(issue); err != nil {
+			return err
+		}
+		issue.UpdatedAt = clock.Now()
 		labels := strings.Join(issue.Labels, ",")
 
 		deferUntil := sql.NullString{String: "", Valid: false}
@@ -309,13 +340,15 @@ func (db *DB) UpdateIssue(issue *models.Issue) error {
 			                  points = ?, labels = ?, parent_id = ?, acceptance = ?, sprint = ?,
 			                  implementer_session = ?, reviewer_session = ?, updated_at = ?,
 			                  closed_at = ?, deleted_at = ?,
-			                  defer_until = ?, due_date = ?, defer_count = ?
+			                  defer_until = ?, due_date = ?, defer_count = ?,
+			                  blocked_reason = ?, blocked_ref = ?, inbox = ?, confidential = ?, rank = ?
 			WHERE id = ?
 		`, issue.Title, issue.Description, issue.Status, issue.Type, issue.Priority,
 			issue.Points, labels, issue.ParentID, issue.Acceptance, issue.Sprint,
 			issue.ImplementerSession, issue.ReviewerSession, issue.UpdatedAt,
 			issue.ClosedAt, issue.DeletedAt,
-			deferUntil, dueDate, issue.DeferCount, issue.ID)
+			deferUntil, dueDate, issue.DeferCount,
+			issue.BlockedReason, issue.BlockedRef, issue.Inbox, issue.Confidential, issue.Rank, issue.ID)
 
 		return err
 	})
@@ -326,7 +359,7 @@ func (db *DB) UpdateIssue(issue *models.Issue) error {
 // This unlogged variant exists for sync receiver applying remote events.
 func (db *DB) DeleteIssue(id string) error {
 	return db.withWriteLock(func() error {
-		now := time.Now()
+		now := clock.Now()
 		_, err := db.conn.Exec(`UPDATE issues SET deleted_at = ?, updated_at = ? WHERE id = ?`, now, now, id)
 		return err
 	})
@@ -335,7 +368,7 @@ func (db *DB) DeleteIssue(id string) error {
 // RestoreIssue restores a soft-deleted issue
 func (db *DB) RestoreIssue(id string) error {
 	return db.withWriteLock(func() error {
-		_, err := db.conn.Exec(`UPDATE issues SET deleted_at = NULL, updated_at = ? WHERE id = ?`, time.Now(), id)
+		_, err := db.conn.Exec(`UPDATE issues SET deleted_at = NULL, updated_at = ? WHERE id = ?`, clock.Now(), id)
 		return err
 	})
 }
@@ -392,7 +425,7 @@ func ReviewableByFilter(sessionID string, balanced bool) (string, []interface{})
 func (db *DB) ListIssues(opts ListIssuesOptions) ([]models.Issue, error) {
 	query := `SELECT id, title, description, status, type, priority, points, labels, parent_id, acceptance, sprint,
                  implementer_session, creator_session, reviewer_session, created_at, updated_at, closed_at, deleted_at, minor, created_branch,
-                 defer_until, due_date, defer_count
+                 defer_until, due_date, defer_count, created_repo, blocked_reason, blocked_ref, inbox, confidential, rank
           FROM issues WHERE 1=1`
 	var args []interface{}
 
@@ -494,6 +527,11 @@ func (db *DB) ListIssues(opts ListIssuesOptions) ([]models.Issue, error) {
 		args = append(args, opts.ParentID)
 	}
 
+	if opts.Sprint != "" {
+		query += " AND sprint = ?"
+		args = append(args, opts.Sprint)
+	}
+
 	// Epic filter (find all descendants of an epic)
 	if opts.EpicID != "" {
 		// Get all descendants recursively
@@ -550,26 +588,40 @@ func (db *DB) ListIssues(opts ListIssuesOptions) ([]models.Issue, error) {
 		args = append(args, opts.ClosedBefore)
 	}
 
-	// Temporal filters (GTD deferral)
+	// Temporal filters (GTD deferral). Days are the project's, not
+	// SQLite's date('now'), which is UTC.
+	today := dateparse.Today()
 	if opts.DeferredOnly {
-		query += " AND defer_until IS NOT NULL AND defer_until > date('now')"
+		query += " AND defer_until IS NOT NULL AND defer_until > ?"
+		args = append(args, today)
 	} else if opts.OverdueOnly {
-		query += " AND due_date IS NOT NULL AND due_date < date('now') AND status != 'closed'"
+		query += " AND due_date IS NOT NULL AND due_date < ? AND status != 'closed'"
+		args = append(args, today)
 	} else if opts.SurfacingOnly {
-		query += " AND defer_until IS NOT NULL AND defer_until <= date('now') AND defer_count > 0"
+		query += " AND defer_until IS NOT NULL AND defer_until <= ? AND defer_count > 0"
+		args = append(args, today)
 	} else if opts.DueSoonDays > 0 {
-		query += fmt.Sprintf(" AND due_date IS NOT NULL AND due_date >= date('now') AND due_date <= date('now', '+%d days')", opts.DueSoonDays)
+		query += " AND due_date IS NOT NULL AND due_date >= ? AND due_date <= ?"
+		args = append(args, today, dateparse.Now().AddDate(0, 0, opts.DueSoonDays).Format("2006-01-02"))
 	} else if opts.ExcludeDeferred {
-		query += " AND (defer_until IS NULL OR defer_until <= date('now'))"
+		query += " AND (defer_until IS NULL OR defer_until <= ?)"
+		args = append(args, today)
 	}
 
-	// Exclude issues with open (non-closed) dependencies
+	// Triage inbox
+	if opts.InboxOnly {
+		query += " AND inbox = 1"
+	} else if opts.ExcludeInbox {
+		query += " AND COALESCE(inbox, 0) = 0"
+	}
+
+	// Exclude issues with open (non-closed) dependencies or stack bases
 	if opts.ExcludeHasOpenDeps {
 		query += ` AND NOT EXISTS (
 			SELECT 1 FROM issue_dependencies d
 			JOIN issues dep ON d.depends_on_id = dep.id
 			WHERE d.issue_id = issues.id
-			  AND d.relation_type = 'depends_on'
+			  AND d.relation_type IN ('depends_on', 'stacked_on')
 			  AND dep.status != 'closed'
 			  AND dep.deleted_at IS NULL
 		)`
@@ -581,6 +633,7 @@ func (db *DB) ListIssues(opts ListIssuesOptions) ([]models.Issue, error) {
 		"priority": true, "points": true, "created_at": true,
 		"updated_at": true, "closed_at": true, "deleted_at": true,
 		"defer_until": true, "due_date": true, "defer_count": true,
+		"sprint": true, "blocked_reason": true, "rank": true,
 	}
 	sortCol := "priority"
 	if opts.SortBy != "" && allowedSortCols[opts.SortBy] {
@@ -590,7 +643,12 @@ func (db *DB) ListIssues(opts ListIssuesOptions) ([]models.Issue, error) {
 	if opts.SortDesc {
 		sortDir = "DESC"
 	}
-	query += fmt.Sprintf(" ORDER BY %s %s", sortCol, sortDir)
+	if sortCol == "rank" {
+		// Ranked issues first, in queue order; the rest by priority
+		query += fmt.Sprintf(" ORDER BY rank = 0, rank %s, priority ASC", sortDir)
+	} else {
+		query += fmt.Sprintf(" ORDER BY %s %s", sortCol, sortDir)
+	}
 
 	// Limit
 	if opts.Limit > 0 {
@@ -611,7 +669,7 @@ func (db *DB) ListIssues(opts ListIssuesOptions) ([]models.Issue, error) {
 		var closedAt, deletedAt sql.NullTime
 		var parentID, acceptance, sprint sql.NullString
 		var implSession, creatorSession, reviewerSession sql.NullString
-		var createdBranch sql.NullString
+		var createdBranch, createdRepo, blockedReason, blockedRef sql.NullString
 		var pointsNull sql.NullInt64
 		var deferUntil, dueDate sql.NullString
 
@@ -619,7 +677,7 @@ func (db *DB) ListIssues(opts ListIssuesOptions) ([]models.Issue, error) {
 			&issue.ID, &issue.Title, &issue.Description, &issue.Status, &issue.Type, &issue.Priority,
 			&pointsNull, &labels, &parentID, &acceptance, &sprint,
 			&implSession, &creatorSession, &reviewerSession, &issue.CreatedAt, &issue.UpdatedAt, &closedAt, &deletedAt, &issue.Minor, &createdBranch,
-			&deferUntil, &dueDate, &issue.DeferCount,
+			&deferUntil, &dueDate, &issue.DeferCount, &createdRepo, &blockedReason, &blockedRef, &issue.Inbox, &issue.Confidential, &issue.Rank,
 		)
 		if err != nil {
 			return nil, err
@@ -642,6 +700,9 @@ func (db *DB) ListIssues(opts ListIssuesOptions) ([]models.Issue, error) {
 		issue.CreatorSession = creatorSession.String
 		issue.ReviewerSession = reviewerSession.String
 		issue.CreatedBranch = createdBranch.String
+		issue.CreatedRepo = createdRepo.String
+		issue.BlockedReason = models.BlockedReason(blockedReason.String)
+		issue.BlockedRef = blockedRef.String
 		if deferUntil.Valid {
 			issue.DeferUntil = &deferUntil.String
 		}
@@ -683,13 +744,15 @@ func (db *DB) UpsertIssueRaw(issue *models.Issue) error {
 				parent_id, acceptance, sprint,
 				implementer_session, creator_session, reviewer_session,
 				created_at, updated_at, closed_at, deleted_at,
-				minor, created_branch, defer_until, due_date, defer_count
-			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+				minor, created_branch, created_repo, defer_until, due_date, defer_count,
+				blocked_reason, blocked_ref, inbox, confidential, rank
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		`, issue.ID, issue.Title, issue.Description, issue.Status, issue.Type, issue.Priority,
 			issue.Points, labels, issue.ParentID, issue.Acceptance, issue.Sprint,
 			issue.ImplementerSession, issue.CreatorSession, issue.ReviewerSession,
 			issue.CreatedAt, issue.UpdatedAt, closedAt, deletedAt,
-			issue.Minor, issue.CreatedBranch, deferUntil, dueDate, issue.DeferCount)
+			issue.Minor, issue.CreatedBranch, issue.CreatedRepo, deferUntil, dueDate, issue.DeferCount,
+			issue.BlockedReason, issue.BlockedRef, issue.Inbox, issue.Confidential, issue.Rank)
 		return err
 	})
 }
@@ -5,8 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
-	"time"
 
+	"github.com/marcus/td/internal/clock"
 	"github.com/marcus/td/internal/models"
 )
 
@@ -24,20 +24,20 @@ func (db *DB) scanIssueRow(id string) (*models.Issue, error) {
 	var closedAt, deletedAt sql.NullTime
 	var parentID, acceptance, sprint sql.NullString
 	var implSession, creatorSession, reviewerSession sql.NullString
-	var createdBranch sql.NullString
+	var createdBranch, createdRepo, blockedReason, blockedRef sql.NullString
 	var pointsNull sql.NullInt64
 	var deferUntil, dueDate sql.NullString
 
 	err := db.conn.QueryRow(`
 		SELECT id, title, description, status, type, priority, points, labels, parent_id, acceptance, sprint,
 		       implementer_session, creator_session, reviewer_session, created_at, updated_at, closed_at, deleted_at, minor, created_branch,
-		       defer_until, due_date, defer_count
+		       defer_until, due_date, defer_count, created_repo, blocked_reason, blocked_ref, inbox, confidential, rank
 		FROM issues WHERE id = ?
 	`, id).Scan(
 		&issue.ID, &issue.Title, &issue.Description, &issue.Status, &issue.Type, &issue.Priority,
 		&pointsNull, &labels, &parentID, &acceptance, &sprint,
 		&implSession, &creatorSession, &reviewerSession, &issue.CreatedAt, &issue.UpdatedAt, &closedAt, &deletedAt, &issue.Minor, &createdBranch,
-		&deferUntil, &dueDate, &issue.DeferCount,
+		&deferUntil, &dueDate, &issue.DeferCount, &createdRepo, &blockedReason, &blockedRef, &issue.Inbox, &issue.Confidential, &issue.Rank,
 	)
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("issue not found: %s", id)
@@ -63,6 +63,9 @@ func (db *DB) scanIssueRow(id string) (*models.Issue, error) {
 	issue.CreatorSession = creatorSession.String
 	issue.ReviewerSession = reviewerSession.String
 	issue.CreatedBranch = createdBranch.String
+	issue.CreatedRepo = createdRepo.String
+	issue.BlockedReason = models.BlockedReason(blockedReason.String)
+	issue.BlockedRef = blockedRef.String
 	if deferUntil.Valid {
 		issue.DeferUntil = &deferUntil.String
 	}
@@ -76,6 +79,12 @@ func (db *DB) scanIssueRow(id string) (*models.Issue, error) {
 // CreateIssueLogged creates an issue and logs the action atomically within a single withWriteLock call.
 func (db *DB) CreateIssueLogged(issue *models.Issue, sessionID string) error {
 	return db.withWriteLock(func() error {
+		if err := db.sealIssue(issue); err != nil {
+			return err
+		}
+		if err := db.ValidateParent("", issue.ParentID); err != nil {
+			return err
+		}
 		if issue.Status == "" {
 			issue.Status = models.StatusOpen
 		}
@@ -86,7 +95,7 @@ func (db *DB) CreateIssueLogged(issue *models.Issue, sessionID string) error {
 			issue.Priority = models.PriorityP2
 		}
 
-		now := time.Now()
+		now := clock.Now()
 		issue.CreatedAt = now
 		issue.UpdatedAt = now
 
@@ -94,7 +103,7 @@ func (db *DB) CreateIssueLogged(issue *models.Issue, sessionID string) error {
 
 		const maxRetries = 3
 		for attempt := range maxRetries {
-			id, err := generateID()
+			id, err := db.generateIssueID()
 			if err != nil {
 				return err
 			}
@@ -110,9 +119,9 @@ func (db *DB) CreateIssueLogged(issue *models.Issue, sessionID string) error {
 			}
 
 			_, err = db.conn.Exec(`
-				INSERT INTO issues (id, title, description, status, type, priority, points, labels, parent_id, acceptance, created_at, updated_at, minor, created_branch, creator_session, defer_until, due_date, defer_count)
-				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-			`, issue.ID, issue.Title, issue.Description, issue.Status, issue.Type, issue.Priority, issue.Points, labels, issue.ParentID, issue.Acceptance, issue.CreatedAt, issue.UpdatedAt, issue.Minor, issue.CreatedBranch, issue.CreatorSession, deferUntil, dueDate, issue.DeferCount)
+				INSERT INTO issues (id, title, description, status, type, priority, points, labels, parent_id, acceptance, sprint, created_at, updated_at, minor, created_branch, created_repo, creator_session, defer_until, due_date, defer_count, inbox, confidential, rank)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			`, issue.ID, issue.Title, issue.Description, issue.Status, issue.Type, issue.Priority, issue.Points, labels, issue.ParentID, issue.Acceptance, issue.Sprint, issue.CreatedAt, issue.UpdatedAt, issue.Minor, issue.CreatedBranch, issue.CreatedRepo, issue.CreatorSession, deferUntil, dueDate, issue.DeferCount, issue.Inbox, issue.Confidential, issue.Rank)
 
 			if err == nil {
 				break
@@ -151,10 +160,31 @@ func (db *DB) updateIssueAndLog(issue *models.Issue, sessionID string, actionTyp
 	if err != nil {
 		return err
 	}
-	previousData := marshalIssue(prev)
+	// A closed issue only changes by being reopened
+	if prev.Status == models.StatusClosed && issue.Status == models.StatusClosed {
+		if err := db.checkClosedWrite(issue.ID, "update"); err != nil {
+			return err
+		}
+	}
+
+	// Text being sealed for the first time stays out of the log
+	logged := prev
+	if issue.Confidential && !prev.Confidential {
+		logged = redactedIssue(prev)
+	}
+	previousData := marshalIssue(logged)
+
+	if err := db.sealIssue(issue); err != nil {
+		return err
+	}
+
+	// A blocked reason only describes the current block
+	if issue.Status != models.StatusBlocked {
+		issue.BlockedReason, issue.BlockedRef = "", ""
+	}
 
 	// Apply update
-	issue.UpdatedAt = time.Now()
+	issue.UpdatedAt = clock.Now()
 	labels := strings.Join(issue.Labels, ",")
 
 	deferUntil := sql.NullString{String: "", Valid: false}
@@ -171,17 +201,27 @@ func (db *DB) updateIssueAndLog(issue *models.Issue, sessionID string, actionTyp
 		                  points = ?, labels = ?, parent_id = ?, acceptance = ?, sprint = ?,
 		                  implementer_session = ?, reviewer_session = ?, updated_at = ?,
 		                  closed_at = ?, deleted_at = ?,
-		                  defer_until = ?, due_date = ?, defer_count = ?
+		                  defer_until = ?, due_date = ?, defer_count = ?,
+		                  blocked_reason = ?, blocked_ref = ?, inbox = ?, confidential = ?, rank = ?
 		WHERE id = ?
 	`, issue.Title, issue.Description, issue.Status, issue.Type, issue.Priority,
 		issue.Points, labels, issue.ParentID, issue.Acceptance, issue.Sprint,
 		issue.ImplementerSession, issue.ReviewerSession, issue.UpdatedAt,
 		issue.ClosedAt, issue.DeletedAt,
-		deferUntil, dueDate, issue.DeferCount, issue.ID)
+		deferUntil, dueDate, issue.DeferCount,
+		issue.BlockedReason, issue.BlockedRef, issue.Inbox, issue.Confidential, issue.Rank, issue.ID)
 	if err != nil {
 		return err
 	}
 
+	// Keep the replaced description and acceptance text. Sealing or
+	// decrypting only re-encodes it (see SetConfidential).
+	if prev.Confidential == issue.Confidential {
+		if err := db.recordIssueRevisionsLocked(prev, issue, sessionID); err != nil {
+			return fmt.Errorf("record revisions: %w", err)
+		}
+	}
+
 	// Log the action
 	actionID, err := generateActionID()
 	if err != nil {
@@ -205,7 +245,7 @@ func (db *DB) addLogEntry(issueID, sessionID, message string, logType models.Log
 	if err != nil {
 		return fmt.Errorf("generate log ID: %w", err)
 	}
-	now := time.Now()
+	now := clock.Now()
 	_, err = db.conn.Exec(`
 		INSERT INTO logs (id, issue_id, session_id, work_session_id, message, type, timestamp)
 		VALUES (?, ?, ?, ?, ?, ?, ?)
@@ -215,45 +255,32 @@ func (db *DB) addLogEntry(issueID, sessionID, message string, logType models.Log
 
 // UpdateIssueLogged updates an issue and logs the action atomically within a single withWriteLock call.
 // It reads the current DB state for PreviousData before applying the update.
+// A status change may be refused with a *RequiredFieldsError or vetoed by a
+// policy hook with a *PolicyError; the anti-thrash guard may reject the
+// update with a *ThrashError, and a parent change that breaks the hierarchy
+// is refused with a *HierarchyError.
 func (db *DB) UpdateIssueLogged(issue *models.Issue, sessionID string, actionType models.ActionType) error {
+	if err := db.checkTransitionPolicy(issue, sessionID); err != nil {
+		return err
+	}
 	return db.withWriteLock(func() error {
-		return db.updateIssueAndLog(issue, sessionID, actionType)
-	})
-}
-
-// DeleteIssueLogged soft-deletes an issue and logs the action atomically within a single withWriteLock call.
-func (db *DB) DeleteIssueLogged(issueID, sessionID string) error {
-	return db.withWriteLock(func() error {
-		// Read current state for PreviousData
-		prev, err := db.scanIssueRow(issueID)
-		if err != nil {
+		if err := db.checkParentLocked(issue); err != nil {
 			return err
 		}
-		previousData := marshalIssue(prev)
-
-		// Soft delete
-		now := time.Now()
-		_, err = db.conn.Exec(`UPDATE issues SET deleted_at = ?, updated_at = ? WHERE id = ?`, now, now, issueID)
-		if err != nil {
+		if err := db.checkThrashLocked(issue, sessionID, false); err != nil {
 			return err
 		}
-
-		// Log the action
-		actionID, err := generateActionID()
-		if err != nil {
-			return fmt.Errorf("generate action ID: %w", err)
-		}
-		actionTS := formatActionLogTimestamp(now)
-		_, err = db.conn.Exec(`INSERT INTO action_log (id, session_id, action_type, entity_type, entity_id, previous_data, new_data, timestamp, undone) VALUES (?, ?, ?, ?, ?, ?, ?, ?, 0)`,
-			actionID, sessionID, string(models.ActionDelete), "issue", issueID, previousData, "", actionTS)
-		if err != nil {
-			return fmt.Errorf("log action: %w", err)
-		}
-
-		return nil
+		return db.updateIssueAndLog(issue, sessionID, actionType)
 	})
 }
 
+// DeleteIssueLogged soft-deletes an issue and logs the action atomically,
+// cleaning up anything that still references it (see DeleteIssueRefsLogged).
+func (db *DB) DeleteIssueLogged(issueID, sessionID string) error {
+	_, err := db.DeleteIssueRefsLogged(issueID, sessionID, true)
+	return err
+}
+
 // RestoreIssueLogged restores a soft-deleted issue and logs the action atomically.
 func (db *DB) RestoreIssueLogged(issueID, sessionID string) error {
 	return db.withWriteLock(func() error {
@@ -265,7 +292,7 @@ func (db *DB) RestoreIssueLogged(issueID, sessionID string) error {
 		previousData := marshalIssue(prev)
 
 		// Restore (clear deleted_at)
-		now := time.Now()
+		now := clock.Now()
 		_, err = db.conn.Exec(`UPDATE issues SET deleted_at = NULL, updated_at = ? WHERE id = ?`, now, issueID)
 		if err != nil {
 			return err
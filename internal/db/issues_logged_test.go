@@ -2,6 +2,7 @@ package db
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/marcus/td/internal/models"
@@ -174,10 +175,13 @@ func TestDeleteIssueLogged(t *testing.T) {
 		t.Fatalf("DeleteIssueLogged failed: %v", err)
 	}
 
-	// Verify soft delete
-	got, err := database.GetIssue(issue.ID)
+	// Verify soft delete: hidden from GetIssue, visible with deleted
+	if _, err := database.GetIssue(issue.ID); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("GetIssue on deleted issue: err = %v, want not found", err)
+	}
+	got, err := database.GetIssueWithDeleted(issue.ID)
 	if err != nil {
-		t.Fatalf("GetIssue failed: %v", err)
+		t.Fatalf("GetIssueWithDeleted failed: %v", err)
 	}
 	if got.DeletedAt == nil {
 		t.Error("DeletedAt should be set after soft delete")
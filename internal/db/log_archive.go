@@ -0,0 +1,149 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/models"
+)
+
+// CompactionSessionID is the session digest logs written by log compaction
+// are attributed to
+const CompactionSessionID = "compaction"
+
+// ArchivedLog is a log replaced by a compaction digest, kept in log_archive
+type ArchivedLog struct {
+	models.Log
+	DigestID   string    `json:"digest_id"`
+	ArchivedAt time.Time `json:"archived_at"`
+}
+
+// GetIssueLogs returns the logs written to an issue itself, oldest first.
+// Unlike GetLogs it leaves out work session logs.
+func (db *DB) GetIssueLogs(issueID string) ([]models.Log, error) {
+	rows, err := db.conn.Query(`SELECT CAST(id AS TEXT), issue_id, session_id, work_session_id, message, type, timestamp
+		FROM logs WHERE issue_id = ? ORDER BY timestamp, id`, issueID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var logs []models.Log
+	for rows.Next() {
+		var l models.Log
+		if err := rows.Scan(&l.ID, &l.IssueID, &l.SessionID, &l.WorkSessionID, &l.Message, &l.Type, &l.Timestamp); err != nil {
+			return nil, err
+		}
+		logs = append(logs, l)
+	}
+	return logs, rows.Err()
+}
+
+// IssuesWithProgressLogs returns the issues with at least minLogs progress
+// logs not written by compaction, the ones worth compacting
+func (db *DB) IssuesWithProgressLogs(minLogs int) ([]string, error) {
+	rows, err := db.conn.Query(`SELECT issue_id FROM logs
+		WHERE issue_id != '' AND type = ? AND session_id != ?
+		GROUP BY issue_id HAVING COUNT(*) >= ? ORDER BY issue_id`,
+		models.LogTypeProgress, CompactionSessionID, minLogs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// CompactLogs replaces run, consecutive logs of one issue, with a single
+// digest log carrying message. The digest takes the time of the last log in
+// the run so the issue's history keeps its order, and the originals move to
+// log_archive. Fails without changing anything if any log in the run has
+// gone since it was read.
+//
+// Compaction is local housekeeping and is not recorded in the action log.
+func (db *DB) CompactLogs(run []models.Log, message string) (*models.Log, error) {
+	if len(run) == 0 {
+		return nil, fmt.Errorf("no logs to compact")
+	}
+	digest := &models.Log{
+		IssueID:   run[0].IssueID,
+		SessionID: CompactionSessionID,
+		Message:   message,
+		Type:      models.LogTypeProgress,
+		Timestamp: run[len(run)-1].Timestamp,
+	}
+	err := db.withWriteLock(func() error {
+		id, err := generateLogID()
+		if err != nil {
+			return fmt.Errorf("generate ID: %w", err)
+		}
+		digest.ID = id
+
+		tx, err := db.conn.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		archivedAt := clock.Now().UTC().Format(time.RFC3339)
+		for _, l := range run {
+			if l.IssueID != digest.IssueID {
+				return fmt.Errorf("log %s belongs to %s, not %s", l.ID, l.IssueID, digest.IssueID)
+			}
+			res, err := tx.Exec(`DELETE FROM logs WHERE id = ?`, l.ID)
+			if err != nil {
+				return err
+			}
+			if n, _ := res.RowsAffected(); n != 1 {
+				return fmt.Errorf("log %s changed while compacting; try again", l.ID)
+			}
+			if _, err := tx.Exec(`INSERT INTO log_archive (id, issue_id, session_id, work_session_id, message, type, timestamp, digest_id, archived_at)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				l.ID, l.IssueID, l.SessionID, l.WorkSessionID, l.Message, l.Type, l.Timestamp, digest.ID, archivedAt); err != nil {
+				return fmt.Errorf("archive log %s: %w", l.ID, err)
+			}
+		}
+		if _, err := tx.Exec(`INSERT INTO logs (id, issue_id, session_id, work_session_id, message, type, timestamp)
+			VALUES (?, ?, ?, '', ?, ?, ?)`,
+			digest.ID, digest.IssueID, digest.SessionID, digest.Message, digest.Type, digest.Timestamp); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return digest, nil
+}
+
+// GetArchivedLogs returns an issue's archived logs, oldest first
+func (db *DB) GetArchivedLogs(issueID string) ([]ArchivedLog, error) {
+	rows, err := db.conn.Query(`SELECT id, issue_id, session_id, work_session_id, message, type, timestamp, digest_id, archived_at
+		FROM log_archive WHERE issue_id = ? ORDER BY timestamp, id`, issueID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	logs := []ArchivedLog{}
+	for rows.Next() {
+		var l ArchivedLog
+		var archivedAt string
+		if err := rows.Scan(&l.ID, &l.IssueID, &l.SessionID, &l.WorkSessionID, &l.Message, &l.Type, &l.Timestamp,
+			&l.DigestID, &archivedAt); err != nil {
+			return nil, err
+		}
+		l.ArchivedAt, _ = time.Parse(time.RFC3339, archivedAt)
+		logs = append(logs, l)
+	}
+	return logs, rows.Err()
+}
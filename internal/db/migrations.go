@@ -159,6 +159,66 @@ func (db *DB) runMigrationsInternal() (int, error) {
 				migrationsRun++
 				continue
 			}
+			if migration.Version == 35 {
+				if err := db.migrateBlockedReason(); err != nil {
+					return migrationsRun, fmt.Errorf("migration 35 (blocked reason): %w", err)
+				}
+				if err := db.setSchemaVersionInternal(migration.Version); err != nil {
+					return migrationsRun, fmt.Errorf("set version %d: %w", migration.Version, err)
+				}
+				migrationsRun++
+				continue
+			}
+			if migration.Version == 52 {
+				if err := db.migrateConfidential(); err != nil {
+					return migrationsRun, fmt.Errorf("migration 52 (confidential): %w", err)
+				}
+				if err := db.setSchemaVersionInternal(migration.Version); err != nil {
+					return migrationsRun, fmt.Errorf("set version %d: %w", migration.Version, err)
+				}
+				migrationsRun++
+				continue
+			}
+			if migration.Version == 53 {
+				if err := db.migrateRank(); err != nil {
+					return migrationsRun, fmt.Errorf("migration 53 (rank): %w", err)
+				}
+				if err := db.setSchemaVersionInternal(migration.Version); err != nil {
+					return migrationsRun, fmt.Errorf("set version %d: %w", migration.Version, err)
+				}
+				migrationsRun++
+				continue
+			}
+			if migration.Version == 49 {
+				if err := db.migrateOutbox(); err != nil {
+					return migrationsRun, fmt.Errorf("migration 49 (outbox): %w", err)
+				}
+				if err := db.setSchemaVersionInternal(migration.Version); err != nil {
+					return migrationsRun, fmt.Errorf("set version %d: %w", migration.Version, err)
+				}
+				migrationsRun++
+				continue
+			}
+			if migration.Version == 44 {
+				if err := db.migrateInbox(); err != nil {
+					return migrationsRun, fmt.Errorf("migration 44 (inbox): %w", err)
+				}
+				if err := db.setSchemaVersionInternal(migration.Version); err != nil {
+					return migrationsRun, fmt.Errorf("set version %d: %w", migration.Version, err)
+				}
+				migrationsRun++
+				continue
+			}
+			if migration.Version == 34 {
+				if err := db.migrateRepoIdentity(); err != nil {
+					return migrationsRun, fmt.Errorf("migration 34 (repo identity): %w", err)
+				}
+				if err := db.setSchemaVersionInternal(migration.Version); err != nil {
+					return migrationsRun, fmt.Errorf("set version %d: %w", migration.Version, err)
+				}
+				migrationsRun++
+				continue
+			}
 			if migration.Version == 25 {
 				if err := db.migrateBoardPositionSoftDelete(); err != nil {
 					return migrationsRun, fmt.Errorf("migration 25 (board position soft delete): %w", err)
@@ -229,6 +289,19 @@ func (db *DB) runMigrationsInternal() (int, error) {
 				migrationsRun++
 				continue
 			}
+			if migration.Version == 39 {
+				if _, err := db.conn.Exec(migration.SQL); err != nil {
+					return migrationsRun, fmt.Errorf("migration 39 (issue cards): %w", err)
+				}
+				if _, err := db.rebuildIssueCards(); err != nil {
+					return migrationsRun, fmt.Errorf("migration 39 (issue cards backfill): %w", err)
+				}
+				if err := db.setSchemaVersionInternal(migration.Version); err != nil {
+					return migrationsRun, fmt.Errorf("set version %d: %w", migration.Version, err)
+				}
+				migrationsRun++
+				continue
+			}
 			if migration.Version == 29 {
 				exists, err := db.columnExists("issues", "defer_until")
 				if err != nil {
@@ -1327,6 +1400,138 @@ func (db *DB) migrateBoardPositionSoftDelete() error {
 	return err
 }
 
+// migrateRepoIdentity adds the repository columns used by multi-repo
+// projects and the local repos registry. Each column is added only if
+// missing, so the migration can be re-run.
+func (db *DB) migrateRepoIdentity() error {
+	for _, c := range []struct{ table, column string }{
+		{"issues", "created_repo"},
+		{"sessions", "repo"},
+		{"git_snapshots", "repo"},
+	} {
+		exists, err := db.columnExists(c.table, c.column)
+		if err != nil {
+			return fmt.Errorf("check %s.%s: %w", c.table, c.column, err)
+		}
+		if exists {
+			continue
+		}
+		if _, err := db.conn.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s TEXT DEFAULT ''`, c.table, c.column)); err != nil {
+			return fmt.Errorf("add %s.%s: %w", c.table, c.column, err)
+		}
+	}
+	_, err := db.conn.Exec(`CREATE TABLE IF NOT EXISTS repos (
+    name TEXT PRIMARY KEY,
+    path TEXT NOT NULL,
+    last_seen TEXT NOT NULL
+)`)
+	return err
+}
+
+// migrateBlockedReason adds the blocked_reason and blocked_ref columns to
+// issues, skipping any that already exist
+func (db *DB) migrateBlockedReason() error {
+	for _, column := range []string{"blocked_reason", "blocked_ref"} {
+		exists, err := db.columnExists("issues", column)
+		if err != nil {
+			return fmt.Errorf("check issues.%s: %w", column, err)
+		}
+		if exists {
+			continue
+		}
+		if _, err := db.conn.Exec(fmt.Sprintf(`ALTER TABLE issues ADD COLUMN %s TEXT DEFAULT ''`, column)); err != nil {
+			return fmt.Errorf("add issues.%s: %w", column, err)
+		}
+	}
+	return nil
+}
+
+// migrateInbox adds the inbox column to issues unless it already exists
+func (db *DB) migrateInbox() error {
+	exists, err := db.columnExists("issues", "inbox")
+	if err != nil {
+		return fmt.Errorf("check issues.inbox: %w", err)
+	}
+	if exists {
+		return nil
+	}
+	if _, err := db.conn.Exec(`ALTER TABLE issues ADD COLUMN inbox INTEGER DEFAULT 0`); err != nil {
+		return fmt.Errorf("add issues.inbox: %w", err)
+	}
+	return nil
+}
+
+// migrateConfidential adds the confidential flag to issues and the table of
+// who may read them (idempotent)
+func (db *DB) migrateConfidential() error {
+	exists, err := db.columnExists("issues", "confidential")
+	if err != nil {
+		return fmt.Errorf("check issues.confidential: %w", err)
+	}
+	if !exists {
+		if _, err := db.conn.Exec(`ALTER TABLE issues ADD COLUMN confidential INTEGER DEFAULT 0`); err != nil {
+			return fmt.Errorf("add issues.confidential: %w", err)
+		}
+	}
+	if _, err := db.conn.Exec(`CREATE TABLE IF NOT EXISTS confidential_grants (
+		issue_id TEXT NOT NULL,
+		grantee TEXT NOT NULL,
+		granted_by TEXT NOT NULL DEFAULT '',
+		created_at TEXT NOT NULL,
+		PRIMARY KEY (issue_id, grantee)
+	)`); err != nil {
+		return fmt.Errorf("create confidential_grants: %w", err)
+	}
+	return nil
+}
+
+// migrateRank adds the project-wide rank to issues, with an index for
+// reading the queue in order (idempotent)
+func (db *DB) migrateRank() error {
+	exists, err := db.columnExists("issues", "rank")
+	if err != nil {
+		return fmt.Errorf("check issues.rank: %w", err)
+	}
+	if !exists {
+		if _, err := db.conn.Exec(`ALTER TABLE issues ADD COLUMN rank INTEGER DEFAULT 0`); err != nil {
+			return fmt.Errorf("add issues.rank: %w", err)
+		}
+	}
+	if _, err := db.conn.Exec(`CREATE INDEX IF NOT EXISTS idx_issues_rank ON issues(rank) WHERE rank > 0`); err != nil {
+		return fmt.Errorf("create idx_issues_rank: %w", err)
+	}
+	return nil
+}
+
+// migrateOutbox adds the table of server-rejected pushes and the columns
+// recording the last push attempt (idempotent)
+func (db *DB) migrateOutbox() error {
+	if _, err := db.conn.Exec(`CREATE TABLE IF NOT EXISTS sync_push_rejections (
+		client_action_id INTEGER PRIMARY KEY,
+		reason TEXT NOT NULL,
+		attempts INTEGER NOT NULL DEFAULT 1,
+		last_rejected_at TEXT NOT NULL
+	)`); err != nil {
+		return fmt.Errorf("create sync_push_rejections: %w", err)
+	}
+	for _, col := range []struct{ name, def string }{
+		{"last_push_error", "TEXT NOT NULL DEFAULT ''"},
+		{"last_push_attempt_at", "TEXT"},
+	} {
+		exists, err := db.columnExists("sync_state", col.name)
+		if err != nil {
+			return fmt.Errorf("check sync_state.%s: %w", col.name, err)
+		}
+		if exists {
+			continue
+		}
+		if _, err := db.conn.Exec(`ALTER TABLE sync_state ADD COLUMN ` + col.name + ` ` + col.def); err != nil {
+			return fmt.Errorf("add sync_state.%s: %w", col.name, err)
+		}
+	}
+	return nil
+}
+
 // migrateActionLogNotNullID fixes NULL/empty ids in action_log and recreates
 // the table with a NOT NULL constraint on the id column.
 func (db *DB) migrateActionLogNotNullID() error {
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/marcus/td/internal/clock"
 	"github.com/marcus/td/internal/models"
 )
 
@@ -62,7 +63,7 @@ func (db *DB) scanNoteRow(id string) (*models.Note, error) {
 func (db *DB) CreateNote(title, content string) (*models.Note, error) {
 	var note models.Note
 	err := db.withWriteLock(func() error {
-		now := time.Now()
+		now := clock.Now()
 		note.Title = title
 		note.Content = content
 		note.CreatedAt = now
@@ -221,7 +222,7 @@ func (db *DB) UpdateNote(id, title, content string) (*models.Note, error) {
 		}
 		previousData := marshalNote(prev)
 
-		now := time.Now()
+		now := clock.Now()
 		_, err = db.conn.Exec(`
 			UPDATE notes SET title = ?, content = ?, updated_at = ? WHERE id = ?
 		`, title, content, now.Format(time.RFC3339), id)
@@ -268,7 +269,7 @@ func (db *DB) DeleteNote(id string) error {
 		}
 		previousData := marshalNote(prev)
 
-		now := time.Now()
+		now := clock.Now()
 		_, err = db.conn.Exec(`UPDATE notes SET deleted_at = ?, updated_at = ? WHERE id = ?`,
 			now.Format(time.RFC3339), now.Format(time.RFC3339), id)
 		if err != nil {
@@ -294,7 +295,7 @@ func (db *DB) DeleteNote(id string) error {
 // PinNote sets a note's pinned status to true.
 func (db *DB) PinNote(id string) error {
 	return db.withWriteLock(func() error {
-		now := time.Now()
+		now := clock.Now()
 		result, err := db.conn.Exec(`UPDATE notes SET pinned = 1, updated_at = ? WHERE id = ? AND deleted_at IS NULL`,
 			now.Format(time.RFC3339), id)
 		if err != nil {
@@ -311,7 +312,7 @@ func (db *DB) PinNote(id string) error {
 // UnpinNote sets a note's pinned status to false.
 func (db *DB) UnpinNote(id string) error {
 	return db.withWriteLock(func() error {
-		now := time.Now()
+		now := clock.Now()
 		result, err := db.conn.Exec(`UPDATE notes SET pinned = 0, updated_at = ? WHERE id = ? AND deleted_at IS NULL`,
 			now.Format(time.RFC3339), id)
 		if err != nil {
@@ -328,7 +329,7 @@ func (db *DB) UnpinNote(id string) error {
 // ArchiveNote sets a note's archived status to true.
 func (db *DB) ArchiveNote(id string) error {
 	return db.withWriteLock(func() error {
-		now := time.Now()
+		now := clock.Now()
 		result, err := db.conn.Exec(`UPDATE notes SET archived = 1, updated_at = ? WHERE id = ? AND deleted_at IS NULL`,
 			now.Format(time.RFC3339), id)
 		if err != nil {
@@ -345,7 +346,7 @@ func (db *DB) ArchiveNote(id string) error {
 // UnarchiveNote sets a note's archived status to false.
 func (db *DB) UnarchiveNote(id string) error {
 	return db.withWriteLock(func() error {
-		now := time.Now()
+		now := clock.Now()
 		result, err := db.conn.Exec(`UPDATE notes SET archived = 0, updated_at = ? WHERE id = ? AND deleted_at IS NULL`,
 			now.Format(time.RFC3339), id)
 		if err != nil {
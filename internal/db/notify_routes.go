@@ -0,0 +1,64 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/models"
+)
+
+// AddNotifyRoute stores a notification route. ID and CreatedAt are filled
+// in. Routes often carry machine-specific targets and credentials, so they
+// are not written to the action log or synced.
+func (db *DB) AddNotifyRoute(r *models.NotifyRoute) error {
+	return db.withWriteLock(func() error {
+		id, err := generateNotifyRouteID()
+		if err != nil {
+			return err
+		}
+		r.ID = id
+		r.CreatedAt = clock.Now().UTC()
+
+		_, err = db.conn.Exec(`INSERT INTO notify_routes (id, event, transport, target, created_at)
+			VALUES (?, ?, ?, ?, ?)`,
+			r.ID, r.Event, r.Transport, r.Target, r.CreatedAt.Format(time.RFC3339))
+		return err
+	})
+}
+
+// ListNotifyRoutes returns every notification route, oldest first
+func (db *DB) ListNotifyRoutes() ([]models.NotifyRoute, error) {
+	rows, err := db.conn.Query(`SELECT id, event, transport, target, created_at
+		FROM notify_routes ORDER BY created_at, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var routes []models.NotifyRoute
+	for rows.Next() {
+		var r models.NotifyRoute
+		var createdAt string
+		if err := rows.Scan(&r.ID, &r.Event, &r.Transport, &r.Target, &createdAt); err != nil {
+			return nil, err
+		}
+		r.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		routes = append(routes, r)
+	}
+	return routes, rows.Err()
+}
+
+// RemoveNotifyRoute deletes a notification route
+func (db *DB) RemoveNotifyRoute(id string) error {
+	return db.withWriteLock(func() error {
+		res, err := db.conn.Exec(`DELETE FROM notify_routes WHERE id = ?`, id)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return fmt.Errorf("notify route not found: %s", id)
+		}
+		return nil
+	})
+}
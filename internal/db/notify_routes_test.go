@@ -0,0 +1,44 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestNotifyRoutes(t *testing.T) {
+	database, err := Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+
+	desktop := &models.NotifyRoute{Event: "*", Transport: "desktop"}
+	hook := &models.NotifyRoute{Event: "p0", Transport: "webhook", Target: "https://example.com/hook"}
+	for _, r := range []*models.NotifyRoute{desktop, hook} {
+		if err := database.AddNotifyRoute(r); err != nil {
+			t.Fatalf("AddNotifyRoute: %v", err)
+		}
+	}
+	if hook.ID == "" || hook.CreatedAt.IsZero() {
+		t.Fatalf("added route = %+v", hook)
+	}
+
+	routes, err := database.ListNotifyRoutes()
+	if err != nil || len(routes) != 2 {
+		t.Fatalf("ListNotifyRoutes = %+v, %v", routes, err)
+	}
+	if routes[1].Target != hook.Target && routes[0].Target != hook.Target {
+		t.Errorf("webhook target not stored: %+v", routes)
+	}
+
+	if err := database.RemoveNotifyRoute(desktop.ID); err != nil {
+		t.Fatalf("RemoveNotifyRoute: %v", err)
+	}
+	if err := database.RemoveNotifyRoute(desktop.ID); err == nil {
+		t.Error("removing a missing route succeeded, want error")
+	}
+	if routes, _ := database.ListNotifyRoutes(); len(routes) != 1 || routes[0].ID != hook.ID {
+		t.Errorf("after remove = %+v, want only %s", routes, hook.ID)
+	}
+}
@@ -0,0 +1,93 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+)
+
+// OutboxEntry is a local change waiting to be pushed to the sync server.
+// ClientActionID is the action_log rowid the server dedupes pushes by, so
+// an entry pushed twice is stored once.
+type OutboxEntry struct {
+	ClientActionID int64     `json:"client_action_id"`
+	ActionID       string    `json:"action_id"`
+	ActionType     string    `json:"action_type"`
+	EntityType     string    `json:"entity_type"`
+	EntityID       string    `json:"entity_id"`
+	QueuedAt       time.Time `json:"queued_at"`
+	Rejection      string    `json:"rejection,omitempty"` // why the server last refused it, "" if it never did
+	Attempts       int       `json:"attempts,omitempty"`  // pushes the server has refused
+}
+
+// ListOutbox returns the changes not yet pushed, oldest first, with any
+// rejection the server gave them. A limit of 0 returns all of them.
+func (db *DB) ListOutbox(limit int) ([]OutboxEntry, error) {
+	query := `
+		SELECT a.rowid, a.id, a.action_type, a.entity_type, a.entity_id, a.timestamp,
+			COALESCE(r.reason, ''), COALESCE(r.attempts, 0)
+		FROM action_log a LEFT JOIN sync_push_rejections r ON r.client_action_id = a.rowid
+		WHERE a.synced_at IS NULL AND a.undone = 0
+		ORDER BY a.rowid`
+	args := []interface{}{}
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []OutboxEntry{}
+	for rows.Next() {
+		var e OutboxEntry
+		var id sql.NullString
+		var queued sql.NullTime
+		if err := rows.Scan(&e.ClientActionID, &id, &e.ActionType, &e.EntityType, &e.EntityID, &queued,
+			&e.Rejection, &e.Attempts); err != nil {
+			return nil, err
+		}
+		e.ActionID, e.QueuedAt = id.String, queued.Time
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// RecordPushRejectionsTx records why the server refused events, keyed by
+// client action ID, counting repeated refusals. Events acked in the same
+// push are cleared of earlier rejections.
+func RecordPushRejectionsTx(tx *sql.Tx, rejected map[int64]string, acked []int64) error {
+	now := clock.Now().UTC().Format(time.RFC3339)
+	for id, reason := range rejected {
+		if _, err := tx.Exec(`INSERT INTO sync_push_rejections (client_action_id, reason, attempts, last_rejected_at)
+			VALUES (?, ?, 1, ?)
+			ON CONFLICT(client_action_id) DO UPDATE SET
+				reason = excluded.reason, attempts = attempts + 1, last_rejected_at = excluded.last_rejected_at`,
+			id, reason, now); err != nil {
+			return err
+		}
+	}
+	for _, id := range acked {
+		if _, err := tx.Exec(`DELETE FROM sync_push_rejections WHERE client_action_id = ?`, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RecordPushAttempt notes when a push was last tried and the error it
+// failed with, "" for success, for td outbox list to report
+func (db *DB) RecordPushAttempt(pushErr error) error {
+	msg := ""
+	if pushErr != nil {
+		msg = pushErr.Error()
+	}
+	return db.withWriteLock(func() error {
+		_, err := db.conn.Exec(`UPDATE sync_state SET last_push_error = ?, last_push_attempt_at = ?`,
+			msg, clock.Now().UTC().Format(time.RFC3339))
+		return err
+	})
+}
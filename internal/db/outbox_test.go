@@ -0,0 +1,36 @@
+package db
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPushAttemptRecorded(t *testing.T) {
+	database, err := Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+
+	if err := database.SetSyncState("proj-1"); err != nil {
+		t.Fatal(err)
+	}
+	state, err := database.GetSyncState()
+	if err != nil || state.LastPushAttemptAt != nil || state.LastPushError != "" {
+		t.Fatalf("new sync state = %+v, %v", state, err)
+	}
+
+	if err := database.RecordPushAttempt(errors.New("dial tcp: connection refused")); err != nil {
+		t.Fatal(err)
+	}
+	state, _ = database.GetSyncState()
+	if state.LastPushAttemptAt == nil || state.LastPushError != "dial tcp: connection refused" {
+		t.Errorf("after failed push = %+v", state)
+	}
+	if err := database.RecordPushAttempt(nil); err != nil {
+		t.Fatal(err)
+	}
+	if state, _ = database.GetSyncState(); state.LastPushError != "" {
+		t.Errorf("error kept after a successful push: %q", state.LastPushError)
+	}
+}
@@ -0,0 +1,128 @@
+package db
+
+import (
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/models"
+)
+
+const overrideColumns = `id, issue_id, kind, justification, session_id, created_at`
+
+// OverrideFilter narrows ListOverrides. Zero fields match everything.
+type OverrideFilter struct {
+	IssueID   string
+	SessionID string
+	Kind      models.OverrideKind
+	Since     time.Time
+}
+
+// RecordOverride stores a use of a bypass-prevention override. ID and
+// CreatedAt are filled in. Like reworks, overrides are local audit records:
+// they are not written to the action log or synced.
+func (db *DB) RecordOverride(o *models.Override) error {
+	return db.withWriteLock(func() error {
+		id, err := generateOverrideID()
+		if err != nil {
+			return err
+		}
+		o.ID = id
+		o.IssueID = NormalizeIssueID(o.IssueID)
+		o.CreatedAt = clock.Now().UTC()
+
+		_, err = db.conn.Exec(`INSERT INTO issue_overrides (`+overrideColumns+`) VALUES (?, ?, ?, ?, ?, ?)`,
+			o.ID, o.IssueID, string(o.Kind), o.Justification, o.SessionID, o.CreatedAt.Format(time.RFC3339))
+		return err
+	})
+}
+
+// ListOverrides returns the overrides matching f, newest first
+func (db *DB) ListOverrides(f OverrideFilter) ([]models.Override, error) {
+	var where []string
+	var args []interface{}
+	if f.IssueID != "" {
+		where = append(where, "issue_id = ?")
+		args = append(args, NormalizeIssueID(f.IssueID))
+	}
+	if f.SessionID != "" {
+		where = append(where, "session_id = ?")
+		args = append(args, f.SessionID)
+	}
+	if f.Kind != "" {
+		where = append(where, "kind = ?")
+		args = append(args, string(f.Kind))
+	}
+	if !f.Since.IsZero() {
+		where = append(where, "created_at >= ?")
+		args = append(args, f.Since.UTC().Format(time.RFC3339))
+	}
+	q := `SELECT ` + overrideColumns + ` FROM issue_overrides`
+	if len(where) > 0 {
+		q += ` WHERE ` + strings.Join(where, " AND ")
+	}
+	rows, err := db.conn.Query(q+` ORDER BY created_at DESC, rowid DESC`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var overrides []models.Override
+	for rows.Next() {
+		var o models.Override
+		var kind, createdAt string
+		if err := rows.Scan(&o.ID, &o.IssueID, &kind, &o.Justification, &o.SessionID, &createdAt); err != nil {
+			return nil, err
+		}
+		o.Kind = models.OverrideKind(kind)
+		o.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		overrides = append(overrides, o)
+	}
+	return overrides, rows.Err()
+}
+
+// ClosedViaOverride reports which of the given issues were closed by an
+// override rather than an independent review. An issue counts when it is
+// closed and a closing override was recorded no earlier than its current
+// close, so a later reopen and ordinary close clears the flag.
+func (db *DB) ClosedViaOverride(issues []models.Issue) (map[string]bool, error) {
+	closedAt := make(map[string]time.Time)
+	var ids []interface{}
+	for _, issue := range issues {
+		if issue.Status == models.StatusClosed && issue.ClosedAt != nil {
+			closedAt[issue.ID] = issue.ClosedAt.Truncate(time.Second)
+			ids = append(ids, issue.ID)
+		}
+	}
+	flagged := make(map[string]bool)
+	if len(ids) == 0 {
+		return flagged, nil
+	}
+
+	var kinds []string
+	for _, k := range models.OverrideKinds() {
+		if k.Closes() {
+			kinds = append(kinds, "'"+string(k)+"'")
+		}
+	}
+	rows, err := db.conn.Query(`
+		SELECT issue_id, MAX(created_at) FROM issue_overrides
+		WHERE kind IN (`+strings.Join(kinds, ",")+`)
+		AND issue_id IN (?`+strings.Repeat(",?", len(ids)-1)+`)
+		GROUP BY issue_id
+	`, ids...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, last string
+		if err := rows.Scan(&id, &last); err != nil {
+			return nil, err
+		}
+		if t, err := time.Parse(time.RFC3339, last); err == nil && !t.Before(closedAt[id]) {
+			flagged[id] = true
+		}
+	}
+	return flagged, rows.Err()
+}
@@ -0,0 +1,82 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestOverrides(t *testing.T) {
+	database, err := Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+
+	closeIssue := func(title string) *models.Issue {
+		t.Helper()
+		issue := &models.Issue{Title: title, Minor: true}
+		if err := database.CreateIssue(issue); err != nil {
+			t.Fatal(err)
+		}
+		now := time.Now()
+		issue.Status = models.StatusClosed
+		issue.ClosedAt = &now
+		if err := database.UpdateIssue(issue); err != nil {
+			t.Fatal(err)
+		}
+		return issue
+	}
+	approved := closeIssue("Self-approved typo fix")
+	started := closeIssue("Forced start, reviewed normally")
+	reviewed := closeIssue("Reviewed independently")
+
+	for _, o := range []*models.Override{
+		{IssueID: approved.ID, Kind: models.OverrideMinorSelfApprove, Justification: "typo only", SessionID: "ses_a"},
+		{IssueID: started.ID, Kind: models.OverrideForceStart, SessionID: "ses_b"},
+	} {
+		if err := database.RecordOverride(o); err != nil {
+			t.Fatalf("RecordOverride: %v", err)
+		}
+		if o.ID == "" || o.CreatedAt.IsZero() {
+			t.Fatalf("override not filled in: %+v", o)
+		}
+	}
+
+	all, err := database.ListOverrides(OverrideFilter{})
+	if err != nil || len(all) != 2 {
+		t.Fatalf("ListOverrides = %d, %v; want 2", len(all), err)
+	}
+	mine, _ := database.ListOverrides(OverrideFilter{SessionID: "ses_a"})
+	if len(mine) != 1 || mine[0].Justification != "typo only" || mine[0].Kind != models.OverrideMinorSelfApprove {
+		t.Errorf("ListOverrides(session) = %+v", mine)
+	}
+	if got, _ := database.ListOverrides(OverrideFilter{Kind: models.OverrideSelfClose}); len(got) != 0 {
+		t.Errorf("ListOverrides(kind) = %d, want 0", len(got))
+	}
+	if got, _ := database.ListOverrides(OverrideFilter{Since: time.Now().Add(time.Hour)}); len(got) != 0 {
+		t.Errorf("ListOverrides(since) = %d, want 0", len(got))
+	}
+
+	// Only closing overrides flag an issue
+	issues := []models.Issue{*approved, *started, *reviewed}
+	flagged, err := database.ClosedViaOverride(issues)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !flagged[approved.ID] || flagged[started.ID] || flagged[reviewed.ID] {
+		t.Errorf("ClosedViaOverride = %v, want only %s", flagged, approved.ID)
+	}
+
+	// A later ordinary close clears the flag
+	later := time.Now().Add(time.Minute)
+	approved.ClosedAt = &later
+	if flagged, _ := database.ClosedViaOverride([]models.Issue{*approved}); flagged[approved.ID] {
+		t.Error("issue closed again after the override is still flagged")
+	}
+	approved.Status = models.StatusOpen
+	if flagged, _ := database.ClosedViaOverride([]models.Issue{*approved}); flagged[approved.ID] {
+		t.Error("open issue is flagged")
+	}
+}
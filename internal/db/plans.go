@@ -0,0 +1,143 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/models"
+)
+
+// ErrPlanNotPending is returned when claiming a plan that was already
+// applied, discarded, or has expired.
+var ErrPlanNotPending = errors.New("plan is not pending")
+
+const planColumns = `id, kind, summary, changes, session_id, status, created_at, expires_at, applied_at, applied_by`
+
+// CreatePlan stores a pending plan. ID, Status and CreatedAt are filled in.
+// Plans are local checkpoints and are not written to the action log.
+func (db *DB) CreatePlan(plan *models.Plan) error {
+	changes, err := json.Marshal(plan.Changes)
+	if err != nil {
+		return fmt.Errorf("marshal plan changes: %w", err)
+	}
+	return db.withWriteLock(func() error {
+		id, err := generatePlanID()
+		if err != nil {
+			return err
+		}
+		plan.ID = id
+		plan.Status = models.PlanPending
+		plan.CreatedAt = clock.Now().UTC()
+
+		_, err = db.conn.Exec(`INSERT INTO plans (id, kind, summary, changes, session_id, status, created_at, expires_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			plan.ID, string(plan.Kind), plan.Summary, string(changes), plan.SessionID, string(plan.Status),
+			plan.CreatedAt.Format(time.RFC3339), plan.ExpiresAt.UTC().Format(time.RFC3339))
+		return err
+	})
+}
+
+// GetPlan retrieves a plan by ID
+func (db *DB) GetPlan(id string) (*models.Plan, error) {
+	row := db.conn.QueryRow(`SELECT `+planColumns+` FROM plans WHERE id = ?`, id)
+	plan, err := scanPlan(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("plan not found: %s", id)
+	}
+	return plan, err
+}
+
+// ListPlans returns plans newest first. With pendingOnly, applied and
+// discarded plans are omitted (expired pending plans are still returned).
+func (db *DB) ListPlans(pendingOnly bool) ([]models.Plan, error) {
+	query := `SELECT ` + planColumns + ` FROM plans`
+	if pendingOnly {
+		query += ` WHERE status = 'pending'`
+	}
+	query += ` ORDER BY created_at DESC, id`
+
+	rows, err := db.conn.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var plans []models.Plan
+	for rows.Next() {
+		plan, err := scanPlan(rows)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, *plan)
+	}
+	return plans, rows.Err()
+}
+
+// ClaimPlan atomically marks a pending, unexpired plan as applied by
+// sessionID so it cannot be applied twice. Returns ErrPlanNotPending if the
+// plan was already applied, discarded, or is past its TTL.
+func (db *DB) ClaimPlan(id, sessionID string, now time.Time) error {
+	return db.withWriteLock(func() error {
+		nowStr := now.UTC().Format(time.RFC3339)
+		res, err := db.conn.Exec(`UPDATE plans SET status = ?, applied_at = ?, applied_by = ?
+			WHERE id = ? AND status = ? AND expires_at > ?`,
+			string(models.PlanApplied), nowStr, sessionID, id, string(models.PlanPending), nowStr)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return ErrPlanNotPending
+		}
+		return nil
+	})
+}
+
+// DiscardPlan marks a pending plan as discarded
+func (db *DB) DiscardPlan(id string) error {
+	return db.withWriteLock(func() error {
+		res, err := db.conn.Exec(`UPDATE plans SET status = ? WHERE id = ? AND status = ?`,
+			string(models.PlanDiscarded), id, string(models.PlanPending))
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return ErrPlanNotPending
+		}
+		return nil
+	})
+}
+
+// planScanner is satisfied by *sql.Row and *sql.Rows
+type planScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanPlan(row planScanner) (*models.Plan, error) {
+	var plan models.Plan
+	var kind, status, changes, createdAt, expiresAt string
+	var appliedAt, appliedBy sql.NullString
+
+	if err := row.Scan(&plan.ID, &kind, &plan.Summary, &changes, &plan.SessionID, &status,
+		&createdAt, &expiresAt, &appliedAt, &appliedBy); err != nil {
+		return nil, err
+	}
+
+	plan.Kind = models.PlanKind(kind)
+	plan.Status = models.PlanStatus(status)
+	plan.AppliedBy = appliedBy.String
+	if err := json.Unmarshal([]byte(changes), &plan.Changes); err != nil {
+		return nil, fmt.Errorf("parse plan %s changes: %w", plan.ID, err)
+	}
+	plan.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	plan.ExpiresAt, _ = time.Parse(time.RFC3339, expiresAt)
+	if appliedAt.Valid && appliedAt.String != "" {
+		if t, err := time.Parse(time.RFC3339, appliedAt.String); err == nil {
+			plan.AppliedAt = &t
+		}
+	}
+	return &plan, nil
+}
@@ -0,0 +1,420 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/models"
+)
+
+const pokerRoundColumns = `id, title, status, session_id, created_at, revealed_at, closed_at`
+
+// ErrPokerStage is returned for acting on a poker round at the wrong stage,
+// such as voting after the reveal
+var ErrPokerStage = errors.New("wrong stage")
+
+// ErrNoConsensus is returned for recording an estimate without points when
+// the votes differ
+var ErrNoConsensus = errors.New("votes differ")
+
+// CreatePokerRound opens a round of blind voting on issues, kept in the
+// order given. Rounds and votes are local to the project database and not
+// synced; the estimates recorded from them are.
+func (db *DB) CreatePokerRound(title string, issueIDs []string, sessionID string) (*models.PokerRound, error) {
+	var ids []string
+	seen := map[string]bool{}
+	for _, id := range issueIDs {
+		id = NormalizeIssueID(strings.TrimSpace(id))
+		if id == "" || seen[id] {
+			continue
+		}
+		if _, err := db.GetIssue(id); err != nil {
+			return nil, err
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("no issues to estimate")
+	}
+
+	round := &models.PokerRound{
+		Title:     strings.TrimSpace(title),
+		Status:    models.PokerOpen,
+		SessionID: sessionID,
+		CreatedAt: clock.Now().UTC(),
+	}
+	err := db.withWriteLock(func() error {
+		id, err := generatePokerID()
+		if err != nil {
+			return err
+		}
+		round.ID = id
+
+		tx, err := db.conn.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if _, err := tx.Exec(`INSERT INTO poker_rounds (id, title, status, session_id, created_at) VALUES (?, ?, ?, ?, ?)`,
+			round.ID, round.Title, string(round.Status), sessionID, round.CreatedAt.Format(time.RFC3339)); err != nil {
+			return err
+		}
+		for i, issueID := range ids {
+			if _, err := tx.Exec(`INSERT INTO poker_items (round_id, issue_id, position) VALUES (?, ?, ?)`,
+				round.ID, issueID, i); err != nil {
+				return err
+			}
+			round.Items = append(round.Items, models.PokerItem{IssueID: issueID, Votes: []models.PokerVote{}})
+		}
+		return tx.Commit()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return round, nil
+}
+
+// GetPokerRound retrieves a round with its issues and every vote. Callers
+// showing it to a session should call Blind first.
+func (db *DB) GetPokerRound(id string) (*models.PokerRound, error) {
+	row := db.conn.QueryRow(`SELECT `+pokerRoundColumns+` FROM poker_rounds WHERE id = ?`, id)
+	round, err := scanPokerRound(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("poker round not found: %s", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := db.loadPokerItems(round); err != nil {
+		return nil, err
+	}
+	return round, nil
+}
+
+// ListPokerRounds returns rounds newest first. Closed rounds are left out
+// unless includeClosed is set.
+func (db *DB) ListPokerRounds(includeClosed bool) ([]models.PokerRound, error) {
+	query := `SELECT ` + pokerRoundColumns + ` FROM poker_rounds`
+	if !includeClosed {
+		query += ` WHERE status != 'closed'`
+	}
+	return db.queryPokerRounds(query + ` ORDER BY created_at DESC, id DESC`)
+}
+
+// OpenPokerRoundsForIssue returns the rounds still taking votes on an
+// issue, newest first
+func (db *DB) OpenPokerRoundsForIssue(issueID string) ([]models.PokerRound, error) {
+	return db.queryPokerRounds(`SELECT `+pokerRoundColumns+` FROM poker_rounds
+		WHERE status = 'open' AND id IN (SELECT round_id FROM poker_items WHERE issue_id = ?)
+		ORDER BY created_at DESC, id DESC`, NormalizeIssueID(issueID))
+}
+
+func (db *DB) queryPokerRounds(query string, args ...any) ([]models.PokerRound, error) {
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	rounds := []models.PokerRound{}
+	for rows.Next() {
+		round, err := scanPokerRound(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		rounds = append(rounds, *round)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range rounds {
+		if err := db.loadPokerItems(&rounds[i]); err != nil {
+			return nil, err
+		}
+	}
+	return rounds, nil
+}
+
+// CastPokerVote records sessionID's estimate of an issue in an open round,
+// replacing its earlier vote
+func (db *DB) CastPokerVote(roundID, issueID, sessionID string, points int) error {
+	if !models.IsValidPoints(points) {
+		return fmt.Errorf("invalid points %d: use %s", points, pointsList())
+	}
+	round, err := db.GetPokerRound(roundID)
+	if err != nil {
+		return err
+	}
+	if round.Status != models.PokerOpen {
+		return fmt.Errorf("%w: round %s is %s, voting is over", ErrPokerStage, round.ID, round.Status)
+	}
+	issueID = NormalizeIssueID(issueID)
+	if round.Item(issueID) == nil {
+		return fmt.Errorf("issue %s is not in round %s", issueID, round.ID)
+	}
+
+	return db.withWriteLock(func() error {
+		_, err := db.conn.Exec(`INSERT INTO poker_votes (round_id, issue_id, session_id, points, created_at)
+			VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT(round_id, issue_id, session_id) DO UPDATE SET points = excluded.points, created_at = excluded.created_at`,
+			round.ID, issueID, sessionID, points, clock.Now().UTC().Format(time.RFC3339))
+		return err
+	})
+}
+
+// RevealPokerRound ends voting and shows every vote
+func (db *DB) RevealPokerRound(id string) (*models.PokerRound, error) {
+	err := db.withWriteLock(func() error {
+		res, err := db.conn.Exec(`UPDATE poker_rounds SET status = 'revealed', revealed_at = ? WHERE id = ? AND status = 'open'`,
+			clock.Now().UTC().Format(time.RFC3339), id)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return db.pokerStageError(id, "only open rounds can be revealed")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return db.GetPokerRound(id)
+}
+
+// RecordPokerEstimate sets an issue's story points from a revealed round.
+// With points 0 the votes must agree. Votes that differ from the estimate
+// are kept as dissent, with note, on the round and in a decision log on the
+// issue. The points change is logged as an update by sessionID, so it
+// syncs. The round closes once every issue has an estimate.
+func (db *DB) RecordPokerEstimate(roundID, issueID string, points int, note, sessionID string) (*models.PokerItem, error) {
+	round, err := db.GetPokerRound(roundID)
+	if err != nil {
+		return nil, err
+	}
+	switch round.Status {
+	case models.PokerOpen:
+		return nil, fmt.Errorf("%w: round %s is open, reveal the votes first", ErrPokerStage, round.ID)
+	case models.PokerClosed:
+		return nil, fmt.Errorf("%w: round %s is closed", ErrPokerStage, round.ID)
+	}
+	issueID = NormalizeIssueID(issueID)
+	item := round.Item(issueID)
+	if item == nil {
+		return nil, fmt.Errorf("issue %s is not in round %s", issueID, round.ID)
+	}
+	if points == 0 {
+		var ok bool
+		if points, ok = unanimousPoints(item.Votes); !ok {
+			return nil, fmt.Errorf("%w on %s: give the points to record", ErrNoConsensus, issueID)
+		}
+	}
+	if !models.IsValidPoints(points) {
+		return nil, fmt.Errorf("invalid points %d: use %s", points, pointsList())
+	}
+
+	item.Points = points
+	item.Dissent = db.pokerDissent(item.Votes, points, note)
+	now := clock.Now().UTC()
+	item.RecordedAt = &now
+
+	err = db.withWriteLock(func() error {
+		issue, err := db.scanIssueRow(issueID)
+		if err != nil {
+			return fmt.Errorf("issue %s: %w", issueID, err)
+		}
+		if issue.Points != points {
+			issue.Points = points
+			if err := db.updateIssueAndLog(issue, sessionID, models.ActionUpdate); err != nil {
+				return err
+			}
+		}
+		if _, err := db.conn.Exec(`UPDATE poker_items SET points = ?, dissent = ?, recorded_at = ? WHERE round_id = ? AND issue_id = ?`,
+			points, item.Dissent, now.Format(time.RFC3339), round.ID, issueID); err != nil {
+			return err
+		}
+		_, err = db.conn.Exec(`UPDATE poker_rounds SET status = 'closed', closed_at = ?
+			WHERE id = ? AND NOT EXISTS (SELECT 1 FROM poker_items WHERE round_id = ? AND recorded_at IS NULL)`,
+			now.Format(time.RFC3339), round.ID, round.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	msg := fmt.Sprintf("Estimated %d points in poker round %s (%d votes)", points, round.ID, len(item.Votes))
+	if item.Dissent != "" {
+		msg += ". Dissent: " + item.Dissent
+	}
+	if err := db.AddLog(&models.Log{
+		IssueID:   issueID,
+		SessionID: sessionID,
+		Message:   msg,
+		Type:      models.LogTypeDecision,
+	}); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// ClosePokerRound ends a round, whether or not every issue has an estimate
+func (db *DB) ClosePokerRound(id string) error {
+	return db.withWriteLock(func() error {
+		res, err := db.conn.Exec(`UPDATE poker_rounds SET status = 'closed', closed_at = ? WHERE id = ? AND status != 'closed'`,
+			clock.Now().UTC().Format(time.RFC3339), id)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return db.pokerStageError(id, "already closed")
+		}
+		return nil
+	})
+}
+
+// pokerStageError explains why a round couldn't change stage: it doesn't
+// exist, or it is at the wrong stage
+func (db *DB) pokerStageError(id, why string) error {
+	var status string
+	err := db.conn.QueryRow(`SELECT status FROM poker_rounds WHERE id = ?`, id).Scan(&status)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("poker round not found: %s", id)
+	}
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: round %s is %s, %s", ErrPokerStage, id, status, why)
+}
+
+// pokerDissent describes the votes that differ from the recorded points,
+// followed by note
+func (db *DB) pokerDissent(votes []models.PokerVote, points int, note string) string {
+	var parts []string
+	for _, v := range votes {
+		if v.Points == points {
+			continue
+		}
+		who := v.SessionID
+		if row, err := db.GetSessionByID(v.SessionID); err == nil && row != nil && row.Name != "" {
+			who = row.Name
+		}
+		parts = append(parts, fmt.Sprintf("%s voted %d", who, v.Points))
+	}
+	dissent := strings.Join(parts, ", ")
+	if note = strings.TrimSpace(note); note != "" {
+		if dissent != "" {
+			dissent += "; "
+		}
+		dissent += note
+	}
+	return dissent
+}
+
+// unanimousPoints returns the points every vote agrees on
+func unanimousPoints(votes []models.PokerVote) (int, bool) {
+	if len(votes) == 0 {
+		return 0, false
+	}
+	for _, v := range votes[1:] {
+		if v.Points != votes[0].Points {
+			return 0, false
+		}
+	}
+	return votes[0].Points, true
+}
+
+func pointsList() string {
+	var s []string
+	for _, p := range models.ValidPoints() {
+		s = append(s, fmt.Sprint(p))
+	}
+	return strings.Join(s, ", ")
+}
+
+// loadPokerItems fills in a round's issues and their votes
+func (db *DB) loadPokerItems(round *models.PokerRound) error {
+	rows, err := db.conn.Query(`SELECT issue_id, points, dissent, recorded_at
+		FROM poker_items WHERE round_id = ? ORDER BY position, issue_id`, round.ID)
+	if err != nil {
+		return err
+	}
+	round.Items = []models.PokerItem{}
+	for rows.Next() {
+		item := models.PokerItem{Votes: []models.PokerVote{}}
+		var recorded sql.NullString
+		if err := rows.Scan(&item.IssueID, &item.Points, &item.Dissent, &recorded); err != nil {
+			rows.Close()
+			return err
+		}
+		item.RecordedAt = parseOptionalTime(recorded)
+		round.Items = append(round.Items, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	rows, err = db.conn.Query(`SELECT issue_id, session_id, points, created_at
+		FROM poker_votes WHERE round_id = ? ORDER BY created_at, session_id`, round.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var issueID, created string
+		var v models.PokerVote
+		if err := rows.Scan(&issueID, &v.SessionID, &v.Points, &created); err != nil {
+			return err
+		}
+		v.CreatedAt, _ = time.Parse(time.RFC3339, created)
+		if item := round.Item(issueID); item != nil {
+			item.Votes = append(item.Votes, v)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for i := range round.Items {
+		votes := round.Items[i].Votes
+		sort.SliceStable(votes, func(a, b int) bool { return votes[a].Points < votes[b].Points })
+	}
+	return nil
+}
+
+// pokerScanner is satisfied by *sql.Row and *sql.Rows
+type pokerScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanPokerRound(row pokerScanner) (*models.PokerRound, error) {
+	var round models.PokerRound
+	var status, created string
+	var revealed, closed sql.NullString
+
+	if err := row.Scan(&round.ID, &round.Title, &status, &round.SessionID, &created, &revealed, &closed); err != nil {
+		return nil, err
+	}
+	round.Status = models.PokerStatus(status)
+	round.CreatedAt, _ = time.Parse(time.RFC3339, created)
+	round.RevealedAt = parseOptionalTime(revealed)
+	round.ClosedAt = parseOptionalTime(closed)
+	return &round, nil
+}
+
+func parseOptionalTime(s sql.NullString) *time.Time {
+	if !s.Valid || s.String == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, s.String)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,137 @@
+package db
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestPokerRound(t *testing.T) {
+	database, err := Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+
+	var a, b models.Issue
+	for _, issue := range []*models.Issue{&a, &b} {
+		issue.Title = "Issue to estimate"
+		if err := database.CreateIssueLogged(issue, "ses_a"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	round, err := database.CreatePokerRound("Sprint 12", []string{a.ID, b.ID, a.ID}, "ses_a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if round.Status != models.PokerOpen || len(round.Items) != 2 {
+		t.Fatalf("round = %+v, want open with 2 issues", round)
+	}
+
+	for _, v := range []struct {
+		issue, session string
+		points         int
+	}{
+		{a.ID, "ses_a", 5}, {a.ID, "ses_b", 8}, {a.ID, "ses_c", 5},
+		{b.ID, "ses_a", 3}, {b.ID, "ses_b", 2}, {b.ID, "ses_b", 3},
+	} {
+		if err := database.CastPokerVote(round.ID, v.issue, v.session, v.points); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := database.CastPokerVote(round.ID, a.ID, "ses_a", 4); err == nil {
+		t.Error("voting 4 points should fail")
+	}
+
+	got, err := database.GetPokerRound(round.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got.Blind("ses_b")
+	for _, v := range got.Item(a.ID).Votes {
+		if (v.SessionID == "ses_b") == v.Hidden {
+			t.Errorf("blind vote %+v seen by ses_b", v)
+		}
+	}
+	if n := len(got.Item(b.ID).Votes); n != 2 {
+		t.Errorf("votes on b = %d, want 2 (revote replaces)", n)
+	}
+
+	if _, err := database.RecordPokerEstimate(round.ID, a.ID, 5, "", "ses_a"); !errors.Is(err, ErrPokerStage) {
+		t.Errorf("record before reveal = %v, want ErrPokerStage", err)
+	}
+	if _, err := database.RevealPokerRound(round.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.CastPokerVote(round.ID, a.ID, "ses_d", 3); !errors.Is(err, ErrPokerStage) {
+		t.Errorf("vote after reveal = %v, want ErrPokerStage", err)
+	}
+
+	if _, err := database.RecordPokerEstimate(round.ID, a.ID, 0, "", "ses_a"); !errors.Is(err, ErrNoConsensus) {
+		t.Errorf("record split vote without points = %v, want ErrNoConsensus", err)
+	}
+	item, err := database.RecordPokerEstimate(round.ID, a.ID, 5, "ses_b worries about the migration", "ses_a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.Dissent != "ses_b voted 8; ses_b worries about the migration" {
+		t.Errorf("dissent = %q", item.Dissent)
+	}
+	issue, _ := database.GetIssue(a.ID)
+	if issue.Points != 5 {
+		t.Errorf("points = %d, want 5", issue.Points)
+	}
+	logs, _ := database.GetLogs(a.ID, 0)
+	if len(logs) == 0 || logs[0].Type != models.LogTypeDecision || !strings.Contains(logs[0].Message, "Dissent: ses_b voted 8") {
+		t.Errorf("logs = %+v, want a decision log with the dissent", logs)
+	}
+
+	// Unanimous votes record without points; the last estimate closes the round
+	if _, err := database.RecordPokerEstimate(round.ID, b.ID, 0, "", "ses_a"); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = database.GetPokerRound(round.ID)
+	if got.Status != models.PokerClosed || got.ClosedAt == nil {
+		t.Errorf("round = %s, want closed after every estimate", got.Status)
+	}
+	if open, _ := database.ListPokerRounds(false); len(open) != 0 {
+		t.Errorf("open rounds = %d, want 0", len(open))
+	}
+	if err := database.ClosePokerRound(round.ID); !errors.Is(err, ErrPokerStage) {
+		t.Errorf("close closed round = %v, want ErrPokerStage", err)
+	}
+}
+
+func TestOpenPokerRoundsForIssue(t *testing.T) {
+	database, err := Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+
+	issue := &models.Issue{Title: "Issue to estimate"}
+	if err := database.CreateIssueLogged(issue, "ses_a"); err != nil {
+		t.Fatal(err)
+	}
+	first, err := database.CreatePokerRound("", []string{issue.ID}, "ses_a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := database.CreatePokerRound("", []string{"td-missing"}, "ses_a"); err == nil {
+		t.Error("round over a missing issue should fail")
+	}
+
+	rounds, err := database.OpenPokerRoundsForIssue(issue.ID)
+	if err != nil || len(rounds) != 1 || rounds[0].ID != first.ID {
+		t.Fatalf("open rounds = %+v, %v", rounds, err)
+	}
+	if err := database.ClosePokerRound(first.ID); err != nil {
+		t.Fatal(err)
+	}
+	if rounds, _ := database.OpenPokerRoundsForIssue(issue.ID); len(rounds) != 0 {
+		t.Errorf("open rounds after close = %d, want 0", len(rounds))
+	}
+}
@@ -0,0 +1,97 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/workflow"
+)
+
+// PolicyError is returned when policy hooks veto a status transition
+type PolicyError struct {
+	IssueID string
+	From    models.Status
+	To      models.Status
+	Vetoes  []*workflow.GuardError
+}
+
+func (e *PolicyError) Error() string {
+	reasons := make([]string, len(e.Vetoes))
+	for i, v := range e.Vetoes {
+		reasons[i] = v.GuardName + ": " + v.Reason
+	}
+	return fmt.Sprintf("cannot move %s from %s to %s: %s", e.IssueID, e.From, e.To, strings.Join(reasons, "; "))
+}
+
+// RequiredFieldsError is returned when an issue lacks fields the project
+// requires before moving it to a status
+type RequiredFieldsError struct {
+	IssueID string
+	Type    models.Type
+	To      models.Status
+	Missing []string
+}
+
+func (e *RequiredFieldsError) Error() string {
+	return fmt.Sprintf("cannot move %s %s to %s: missing required %s", e.Type, e.IssueID, e.To, strings.Join(e.Missing, ", "))
+}
+
+// checkTransitionPolicy enforces the project's required fields and runs
+// the registered and configured policy hooks when an update changes an
+// issue's status. It runs outside the write lock so command hooks can
+// read from td while they run.
+func (db *DB) checkTransitionPolicy(issue *models.Issue, sessionID string) error {
+	cfg, err := config.Load(db.baseDir)
+	if err != nil {
+		slog.Debug("policy: load config", "err", err)
+		cfg = &models.Config{}
+	}
+	hooks := workflow.RegisteredHooks()
+	configured, err := workflow.HooksFromConfig(cfg.PolicyHooks, db.baseDir)
+	if err != nil {
+		return fmt.Errorf("policy hooks: %w", err)
+	}
+	hooks = append(hooks, configured...)
+	if len(hooks) == 0 && len(cfg.RequiredFields) == 0 {
+		return nil
+	}
+
+	prev, err := db.scanIssueRow(issue.ID)
+	if err != nil || prev.Status == issue.Status {
+		return nil // the update reports a missing issue
+	}
+	if missing := workflow.MissingFields(issue, issue.Status, cfg.RequiredFields); len(missing) > 0 {
+		return &RequiredFieldsError{IssueID: issue.ID, Type: issue.Type, To: issue.Status, Missing: missing}
+	}
+	if len(hooks) == 0 {
+		return nil
+	}
+	handoff, err := db.GetLatestHandoff(issue.ID)
+	if err != nil {
+		return err
+	}
+
+	err = workflow.RunHooks(&workflow.TransitionContext{
+		Issue:      issue,
+		FromStatus: prev.Status,
+		ToStatus:   issue.Status,
+		SessionID:  sessionID,
+		HasHandoff: handoff != nil,
+	}, hooks)
+	var verr *workflow.ValidationError
+	if !errors.As(err, &verr) {
+		return err
+	}
+	perr := &PolicyError{IssueID: issue.ID, From: prev.Status, To: issue.Status}
+	for _, e := range verr.Errors {
+		var ge *workflow.GuardError
+		if errors.As(e, &ge) {
+			perr.Vetoes = append(perr.Vetoes, ge)
+		}
+	}
+	return perr
+}
@@ -0,0 +1,151 @@
+package db
+
+import (
+	"log/slog"
+	"slices"
+	"sort"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/models"
+)
+
+// InheritedPriorities returns the raised priority of every open issue that
+// an open issue of higher priority waits on, directly or through other
+// issues, keyed by issue ID. It is empty unless the project has turned
+// priority inheritance on (td policy inherit).
+func (db *DB) InheritedPriorities() (map[string]models.InheritedPriority, error) {
+	on, err := config.GetPriorityInheritance(db.baseDir)
+	if err != nil {
+		slog.Debug("priority inheritance: load config", "err", err)
+	}
+	if !on {
+		return map[string]models.InheritedPriority{}, nil
+	}
+
+	priorities := make(map[string]models.Priority)
+	rows, err := db.conn.Query(`SELECT id, priority FROM issues WHERE status != 'closed' AND deleted_at IS NULL`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		var p models.Priority
+		if err := rows.Scan(&id, &p); err != nil {
+			return nil, err
+		}
+		priorities[id] = p
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	deps := make(map[string][]string)
+	edges, err := db.conn.Query(`
+		SELECT issue_id, depends_on_id FROM issue_dependencies
+		WHERE relation_type IN ('depends_on', 'stacked_on')
+		ORDER BY issue_id, depends_on_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer edges.Close()
+	for edges.Next() {
+		var issueID, depID string
+		if err := edges.Scan(&issueID, &depID); err != nil {
+			return nil, err
+		}
+		deps[issueID] = append(deps[issueID], depID)
+	}
+	if err := edges.Err(); err != nil {
+		return nil, err
+	}
+	return InheritPriorities(priorities, deps), nil
+}
+
+// InheritPriorities raises each issue in priorities to the highest
+// priority among the issues that depend on it, directly or transitively;
+// deps maps an issue to the issues it depends on, and issues missing from
+// priorities (closed ones) break the chain. Only raised issues are
+// returned. Issues are walked from highest priority down and each one is
+// expanded once, so the first to reach an issue is the one it inherits
+// from and dependency cycles can't loop.
+func InheritPriorities(priorities map[string]models.Priority, deps map[string][]string) map[string]models.InheritedPriority {
+	sources := make([]string, 0, len(priorities))
+	for id := range priorities {
+		sources = append(sources, id)
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		a, b := priorities[sources[i]], priorities[sources[j]]
+		if a != b {
+			return a < b
+		}
+		return sources[i] < sources[j]
+	})
+
+	inherited := make(map[string]models.InheritedPriority)
+	covered := make(map[string]bool, len(priorities))
+	for _, src := range sources {
+		if covered[src] {
+			continue
+		}
+		covered[src] = true
+		p := priorities[src]
+		via := map[string]string{} // issue -> the dependent it was reached from
+		queue := []string{src}
+		for len(queue) > 0 {
+			id := queue[0]
+			queue = queue[1:]
+			for _, dep := range deps[id] {
+				if _, open := priorities[dep]; !open || covered[dep] {
+					continue
+				}
+				covered[dep] = true
+				via[dep] = id
+				queue = append(queue, dep)
+				if priorities[dep] > p {
+					inherited[dep] = models.InheritedPriority{Priority: p, From: src, Via: inheritanceChain(via, src, dep)}
+				}
+			}
+		}
+	}
+	return inherited
+}
+
+// inheritanceChain walks back from id to src
+func inheritanceChain(via map[string]string, src, id string) []string {
+	chain := []string{id}
+	for id != src {
+		id = via[id]
+		chain = append(chain, id)
+	}
+	slices.Reverse(chain)
+	return chain
+}
+
+// EffectivePriority is an issue's priority after inheritance
+func EffectivePriority(issue *models.Issue, inherited map[string]models.InheritedPriority) models.Priority {
+	if ip, ok := inherited[issue.ID]; ok {
+		return ip.Priority
+	}
+	return issue.Priority
+}
+
+// SortByEffectivePriority reorders the unranked issues of a ready queue by
+// effective priority. Ranked issues keep their place at the front (see
+// td rank), and ties keep their order.
+func SortByEffectivePriority(issues []models.Issue, inherited map[string]models.InheritedPriority) {
+	if len(inherited) == 0 {
+		return
+	}
+	unranked := issues[len(issues):]
+	for i := range issues {
+		if issues[i].Rank == 0 {
+			unranked = issues[i:]
+			break
+		}
+	}
+	sort.SliceStable(unranked, func(i, j int) bool {
+		return EffectivePriority(&unranked[i], inherited) < EffectivePriority(&unranked[j], inherited)
+	})
+}
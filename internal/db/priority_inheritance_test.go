@@ -0,0 +1,94 @@
+package db
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/models"
+)
+
+func TestInheritPriorities(t *testing.T) {
+	priorities := map[string]models.Priority{
+		"urgent": "P0", "mid": "P2", "leaf": "P3", "high": "P1", "own": "P1",
+		"c1": "P3", "c2": "P4", "beyond": "P4",
+	}
+	deps := map[string][]string{
+		"urgent": {"mid", "own"},
+		"mid":    {"leaf", "closed"},
+		"closed": {"beyond"},
+		"high":   {"leaf"},
+		// A cycle, one of whose issues depends on the P0
+		"c1": {"c2"},
+		"c2": {"c1", "urgent"},
+	}
+	got := InheritPriorities(priorities, deps)
+
+	if ip := got["leaf"]; ip.Priority != "P0" || ip.From != "urgent" || !slices.Equal(ip.Via, []string{"urgent", "mid", "leaf"}) {
+		t.Errorf("leaf = %+v, want P0 from urgent via mid", ip)
+	}
+	if ip := got["mid"]; ip.Priority != "P0" || !slices.Equal(ip.Via, []string{"urgent", "mid"}) {
+		t.Errorf("mid = %+v", ip)
+	}
+	if ip, ok := got["own"]; !ok || ip.Priority != "P0" {
+		t.Errorf("own = %+v, want raised from P1", ip)
+	}
+	if _, ok := got["beyond"]; ok {
+		t.Error("inherited through a closed issue")
+	}
+	if ip := got["c2"]; ip.Priority != "P3" || ip.From != "c1" {
+		t.Errorf("c2 = %+v, want P3 from c1", ip)
+	}
+	for _, id := range []string{"urgent", "high", "c1"} {
+		if ip, ok := got[id]; ok {
+			t.Errorf("%s raised to %+v", id, ip)
+		}
+	}
+}
+
+func TestInheritedPrioritiesPolicy(t *testing.T) {
+	dir := t.TempDir()
+	database, err := Initialize(dir)
+	if err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	defer database.Close()
+
+	urgent := &models.Issue{Title: "Urgent", Priority: models.PriorityP0}
+	blocker := &models.Issue{Title: "Blocker", Priority: models.PriorityP3}
+	other := &models.Issue{Title: "Other", Priority: models.PriorityP2}
+	for _, issue := range []*models.Issue{urgent, blocker, other} {
+		if err := database.CreateIssue(issue); err != nil {
+			t.Fatalf("CreateIssue: %v", err)
+		}
+	}
+	if err := database.AddDependency(urgent.ID, blocker.ID, "depends_on"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := database.InheritedPriorities(); err != nil || len(got) != 0 {
+		t.Fatalf("policy off = %v, %v; want nothing raised", got, err)
+	}
+	if err := config.SetPriorityInheritance(dir, true); err != nil {
+		t.Fatal(err)
+	}
+	inherited, err := database.InheritedPriorities()
+	if err != nil || inherited[blocker.ID].Priority != models.PriorityP0 || len(inherited) != 1 {
+		t.Fatalf("policy on = %v, %v", inherited, err)
+	}
+
+	queue := []models.Issue{*other, *blocker}
+	SortByEffectivePriority(queue, inherited)
+	if queue[0].ID != blocker.ID {
+		t.Errorf("queue = %s, %s; want the raised blocker first", queue[0].ID, queue[1].ID)
+	}
+
+	// Closing the dependent drops the inheritance
+	urgent.Status = models.StatusClosed
+	if err := database.UpdateIssue(urgent); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := database.InheritedPriorities(); len(got) != 0 {
+		t.Errorf("after close = %v", got)
+	}
+}
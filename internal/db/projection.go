@@ -0,0 +1,436 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+	tdevents "github.com/marcus/td/internal/events"
+	"github.com/marcus/td/internal/models"
+)
+
+// ============================================================================
+// Issue Projection
+// ============================================================================
+//
+// The action log is the source of truth for local issue changes: every
+// logged mutation stores a full issue snapshot in new_data. The issues table
+// is a projection of that log and can be rebuilt by replaying it.
+
+// ErrRemoteHistory is returned when rebuilding a project that has pulled
+// from a sync server. Remote events are applied directly to the issues
+// table and are not in the local action log, so a rebuild would drop them.
+var ErrRemoteHistory = errors.New("project has pulled remote changes that are not in the local action log")
+
+// RebuildOptions controls RebuildIssueProjection.
+type RebuildOptions struct {
+	// AsOf replays only events at or before this time (point-in-time
+	// restore). Zero means replay everything.
+	AsOf time.Time
+	// DryRun reports drift without writing.
+	DryRun bool
+	// Force rebuilds even if remote history is missing from the log.
+	Force bool
+	// SessionID attributes restore events when AsOf is set.
+	SessionID string
+}
+
+// ProjectionDrift describes an issue whose row differs from its projection.
+type ProjectionDrift struct {
+	IssueID string   `json:"issue_id"`
+	Fields  []string `json:"fields,omitempty"`
+	Missing bool     `json:"missing,omitempty"` // in the log but not the table
+	Removed bool     `json:"removed,omitempty"` // created after AsOf; will be deleted
+}
+
+// RebuildResult summarizes a projection rebuild.
+type RebuildResult struct {
+	Events    int               `json:"events"`    // issue events replayed
+	Projected int               `json:"projected"` // issues with at least one event
+	Drift     []ProjectionDrift `json:"drift"`
+	Unlogged  []string          `json:"unlogged"` // rows with no events; left untouched
+	Written   int               `json:"written"`
+	DryRun    bool              `json:"dry_run"`
+}
+
+// ProjectIssues replays the action log into issue snapshots keyed by ID.
+// Undone events are skipped. If asOf is non-zero, later events are ignored.
+// Hard-deleted issues are omitted. Returns the number of events replayed.
+func (db *DB) ProjectIssues(asOf time.Time) (map[string]*models.Issue, int, error) {
+	rows, err := db.conn.Query(`
+		SELECT action_type, entity_id, new_data, timestamp
+		FROM action_log
+		WHERE entity_type IN ('issue', 'issues') AND undone = 0
+		ORDER BY rowid ASC`)
+	if err != nil {
+		return nil, 0, fmt.Errorf("query issue events: %w", err)
+	}
+	defer rows.Close()
+
+	issues := make(map[string]*models.Issue)
+	events := 0
+	for rows.Next() {
+		var actionType, entityID string
+		var newData, ts sql.NullString
+		if err := rows.Scan(&actionType, &entityID, &newData, &ts); err != nil {
+			return nil, 0, fmt.Errorf("scan issue event: %w", err)
+		}
+		at, err := parseEventTimestamp(ts.String)
+		if err != nil {
+			return nil, 0, fmt.Errorf("issue event %s: bad timestamp %q", entityID, ts.String)
+		}
+		if !asOf.IsZero() && at.After(asOf) {
+			continue
+		}
+		events++
+
+		switch tdevents.NormalizeActionType(actionType) {
+		case tdevents.ActionCreate, tdevents.ActionUpdate, tdevents.ActionRestore:
+			if newData.String == "" {
+				if cur := issues[entityID]; cur != nil && tdevents.NormalizeActionType(actionType) == tdevents.ActionRestore {
+					cur.DeletedAt = nil
+				}
+				continue
+			}
+			issue, err := decodeIssueSnapshot(newData.String)
+			if err != nil {
+				return nil, 0, fmt.Errorf("issue event %s: %w", entityID, err)
+			}
+			issue.ID = entityID
+			issues[entityID] = issue
+		case tdevents.ActionSoftDelete:
+			if cur := issues[entityID]; cur != nil {
+				deletedAt := at
+				cur.DeletedAt = &deletedAt
+			}
+		case tdevents.ActionDelete:
+			delete(issues, entityID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return issues, events, nil
+}
+
+// decodeIssueSnapshot parses an action_log issue snapshot. Snapshots are
+// usually models.Issue JSON, but backfilled events store raw rows with
+// comma-separated labels, integer booleans and SQLite timestamps.
+func decodeIssueSnapshot(data string) (*models.Issue, error) {
+	var issue models.Issue
+	if err := json.Unmarshal([]byte(data), &issue); err == nil {
+		return &issue, nil
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(data), &fields); err != nil {
+		return nil, fmt.Errorf("parse snapshot: %w", err)
+	}
+	if s, ok := fields["labels"].(string); ok {
+		if s == "" {
+			delete(fields, "labels")
+		} else {
+			fields["labels"] = strings.Split(s, ",")
+		}
+	}
+	for _, k := range []string{"minor", "inbox", "confidential"} {
+		if n, ok := fields[k].(float64); ok {
+			fields[k] = n != 0
+		}
+	}
+	for _, k := range []string{"created_at", "updated_at", "closed_at", "deleted_at"} {
+		s, ok := fields[k].(string)
+		if !ok {
+			continue
+		}
+		if t, err := parseEventTimestamp(s); err == nil {
+			fields[k] = t.Format(time.RFC3339Nano)
+		} else if s == "" {
+			delete(fields, k)
+		}
+	}
+	normalized, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(normalized, &issue); err != nil {
+		return nil, fmt.Errorf("parse snapshot: %w", err)
+	}
+	return &issue, nil
+}
+
+// parseEventTimestamp accepts action_log timestamps written by current and
+// older versions of td.
+func parseEventTimestamp(s string) (time.Time, error) {
+	for _, layout := range []string{
+		"2006-01-02 15:04:05.999999999-07:00",
+		"2006-01-02 15:04:05.999999999",
+	} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return parseTimestamp(s)
+}
+
+// RebuildIssueProjection replays the action log and rewrites issue rows
+// that drifted from it.
+//
+// Without AsOf this repairs the table to match the log; no events are
+// written because the log already describes the result. With AsOf the
+// table is restored to that point in time, and each change is appended to
+// the log as a new event so sync, undo and later rebuilds see it.
+// Issues with no events at all are never touched.
+func (db *DB) RebuildIssueProjection(opts RebuildOptions) (*RebuildResult, error) {
+	if !opts.Force && !opts.DryRun {
+		if state, err := db.GetSyncState(); err == nil && state != nil && state.LastPulledServerSeq > 0 {
+			return nil, ErrRemoteHistory
+		}
+	}
+
+	result := &RebuildResult{DryRun: opts.DryRun, Drift: []ProjectionDrift{}, Unlogged: []string{}}
+	err := db.withWriteLock(func() error {
+		projected, events, err := db.ProjectIssues(opts.AsOf)
+		if err != nil {
+			return err
+		}
+		result.Events = events
+		result.Projected = len(projected)
+
+		current, err := db.allIssueRows()
+		if err != nil {
+			return err
+		}
+
+		// Issues with events only after AsOf did not exist at that time
+		var laterIDs map[string]bool
+		if !opts.AsOf.IsZero() {
+			allProjected, _, err := db.ProjectIssues(time.Time{})
+			if err != nil {
+				return err
+			}
+			laterIDs = make(map[string]bool)
+			for id := range allProjected {
+				if projected[id] == nil {
+					laterIDs[id] = true
+				}
+			}
+		}
+
+		ids := make([]string, 0, len(current)+len(projected))
+		seen := make(map[string]bool)
+		for id := range current {
+			ids, seen[id] = append(ids, id), true
+		}
+		for id := range projected {
+			if !seen[id] {
+				ids = append(ids, id)
+			}
+		}
+		sort.Strings(ids)
+
+		var writes []*models.Issue
+		for _, id := range ids {
+			row, want := current[id], projected[id]
+			switch {
+			case want == nil && laterIDs[id]:
+				if row.DeletedAt != nil {
+					continue
+				}
+				result.Drift = append(result.Drift, ProjectionDrift{IssueID: id, Removed: true})
+				removed := *row
+				now := clock.Now()
+				removed.DeletedAt = &now
+				writes = append(writes, &removed)
+			case want == nil:
+				result.Unlogged = append(result.Unlogged, id)
+			case row == nil:
+				result.Drift = append(result.Drift, ProjectionDrift{IssueID: id, Missing: true})
+				writes = append(writes, want)
+			default:
+				if fields := diffIssueFields(row, want); len(fields) > 0 {
+					result.Drift = append(result.Drift, ProjectionDrift{IssueID: id, Fields: fields})
+					writes = append(writes, want)
+				}
+			}
+		}
+
+		if opts.DryRun || len(writes) == 0 {
+			return nil
+		}
+
+		tx, err := db.conn.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+		for _, issue := range writes {
+			if err := writeIssueProjectionTx(tx, issue); err != nil {
+				return fmt.Errorf("write %s: %w", issue.ID, err)
+			}
+			if !opts.AsOf.IsZero() {
+				if err := logRestoreEventTx(tx, current[issue.ID], issue, opts.SessionID); err != nil {
+					return err
+				}
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		result.Written = len(writes)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// allIssueRows loads every issue row, including soft-deleted ones.
+// Caller must hold the write lock.
+func (db *DB) allIssueRows() (map[string]*models.Issue, error) {
+	rows, err := db.conn.Query(`SELECT id FROM issues`)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	issues := make(map[string]*models.Issue, len(ids))
+	for _, id := range ids {
+		issue, err := db.scanIssueRow(id)
+		if err != nil {
+			return nil, err
+		}
+		issues[id] = issue
+	}
+	return issues, nil
+}
+
+// diffIssueFields lists the column names where row differs from want.
+// updated_at is ignored; it moves with every write.
+func diffIssueFields(row, want *models.Issue) []string {
+	var fields []string
+	check := func(name string, differs bool) {
+		if differs {
+			fields = append(fields, name)
+		}
+	}
+	check("title", row.Title != want.Title)
+	check("description", row.Description != want.Description)
+	check("status", row.Status != want.Status)
+	check("type", row.Type != want.Type)
+	check("priority", row.Priority != want.Priority)
+	check("points", row.Points != want.Points)
+	check("labels", strings.Join(row.Labels, ",") != strings.Join(want.Labels, ","))
+	check("parent_id", row.ParentID != want.ParentID)
+	check("acceptance", row.Acceptance != want.Acceptance)
+	check("sprint", row.Sprint != want.Sprint)
+	check("implementer_session", row.ImplementerSession != want.ImplementerSession)
+	check("creator_session", row.CreatorSession != want.CreatorSession)
+	check("reviewer_session", row.ReviewerSession != want.ReviewerSession)
+	check("closed_at", !sameTime(row.ClosedAt, want.ClosedAt))
+	check("deleted_at", !sameTime(row.DeletedAt, want.DeletedAt))
+	check("minor", row.Minor != want.Minor)
+	check("created_branch", row.CreatedBranch != want.CreatedBranch)
+	check("created_repo", row.CreatedRepo != want.CreatedRepo)
+	check("defer_until", derefString(row.DeferUntil) != derefString(want.DeferUntil))
+	check("due_date", derefString(row.DueDate) != derefString(want.DueDate))
+	check("defer_count", row.DeferCount != want.DeferCount)
+	check("blocked_reason", row.BlockedReason != want.BlockedReason)
+	check("blocked_ref", row.BlockedRef != want.BlockedRef)
+	check("inbox", row.Inbox != want.Inbox)
+	check("confidential", row.Confidential != want.Confidential)
+	check("rank", row.Rank != want.Rank)
+	return fields
+}
+
+// sameTime compares optional timestamps to the second
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Truncate(time.Second).Equal(b.Truncate(time.Second))
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// writeIssueProjectionTx inserts or overwrites a full issue row
+func writeIssueProjectionTx(tx *sql.Tx, issue *models.Issue) error {
+	deferUntil := sql.NullString{}
+	if issue.DeferUntil != nil {
+		deferUntil = sql.NullString{String: *issue.DeferUntil, Valid: true}
+	}
+	dueDate := sql.NullString{}
+	if issue.DueDate != nil {
+		dueDate = sql.NullString{String: *issue.DueDate, Valid: true}
+	}
+	_, err := tx.Exec(`
+		INSERT INTO issues (id, title, description, status, type, priority, points, labels, parent_id, acceptance, sprint,
+		                    implementer_session, creator_session, reviewer_session, created_at, updated_at, closed_at, deleted_at,
+		                    minor, created_branch, created_repo, defer_until, due_date, defer_count,
+		                    blocked_reason, blocked_ref, inbox, confidential, rank)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			title = excluded.title, description = excluded.description, status = excluded.status,
+			type = excluded.type, priority = excluded.priority, points = excluded.points, labels = excluded.labels,
+			parent_id = excluded.parent_id, acceptance = excluded.acceptance, sprint = excluded.sprint,
+			implementer_session = excluded.implementer_session, creator_session = excluded.creator_session,
+			reviewer_session = excluded.reviewer_session, created_at = excluded.created_at,
+			updated_at = excluded.updated_at, closed_at = excluded.closed_at, deleted_at = excluded.deleted_at,
+			minor = excluded.minor, created_branch = excluded.created_branch,
+			created_repo = excluded.created_repo, defer_until = excluded.defer_until,
+			due_date = excluded.due_date, defer_count = excluded.defer_count,
+			blocked_reason = excluded.blocked_reason, blocked_ref = excluded.blocked_ref,
+			inbox = excluded.inbox, confidential = excluded.confidential, rank = excluded.rank
+	`, issue.ID, issue.Title, issue.Description, issue.Status, issue.Type, issue.Priority, issue.Points,
+		strings.Join(issue.Labels, ","), issue.ParentID, issue.Acceptance, issue.Sprint,
+		issue.ImplementerSession, issue.CreatorSession, issue.ReviewerSession, issue.CreatedAt, issue.UpdatedAt,
+		issue.ClosedAt, issue.DeletedAt, issue.Minor, issue.CreatedBranch, issue.CreatedRepo, deferUntil, dueDate, issue.DeferCount,
+		issue.BlockedReason, issue.BlockedRef, issue.Inbox, issue.Confidential, issue.Rank)
+	return err
+}
+
+// logRestoreEventTx appends a point-in-time restore to the action log
+func logRestoreEventTx(tx *sql.Tx, prev, next *models.Issue, sessionID string) error {
+	actionID, err := generateActionID()
+	if err != nil {
+		return fmt.Errorf("generate action ID: %w", err)
+	}
+	action, previousData, newData := models.ActionUpdate, "", marshalIssue(next)
+	if prev != nil {
+		previousData = marshalIssue(prev)
+	} else {
+		action = models.ActionCreate
+	}
+	if next.DeletedAt != nil && (prev == nil || prev.DeletedAt == nil) {
+		action, newData = models.ActionDelete, ""
+	}
+	_, err = tx.Exec(`INSERT INTO action_log (id, session_id, action_type, entity_type, entity_id, previous_data, new_data, timestamp, undone) VALUES (?, ?, ?, ?, ?, ?, ?, ?, 0)`,
+		actionID, sessionID, string(action), "issue", next.ID, previousData, newData, actionLogTimestampNow())
+	if err != nil {
+		return fmt.Errorf("log restore of %s: %w", next.ID, err)
+	}
+	return nil
+}
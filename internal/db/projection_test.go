@@ -0,0 +1,183 @@
+package db
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestProjectIssues_ReplaysLog(t *testing.T) {
+	database, err := Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	defer database.Close()
+
+	a := &models.Issue{Title: "Projected issue", Labels: []string{"x"}}
+	if err := database.CreateIssueLogged(a, "sess-1"); err != nil {
+		t.Fatal(err)
+	}
+	a.Status = models.StatusInProgress
+	if err := database.UpdateIssueLogged(a, "sess-1", models.ActionStart); err != nil {
+		t.Fatal(err)
+	}
+	b := &models.Issue{Title: "Deleted issue"}
+	if err := database.CreateIssueLogged(b, "sess-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.DeleteIssueLogged(b.ID, "sess-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	issues, events, err := database.ProjectIssues(time.Time{})
+	if err != nil {
+		t.Fatalf("ProjectIssues failed: %v", err)
+	}
+	if events != 4 {
+		t.Errorf("events = %d, want 4", events)
+	}
+	if got := issues[a.ID]; got == nil || got.Status != models.StatusInProgress || got.Labels[0] != "x" {
+		t.Errorf("projected a = %+v", got)
+	}
+	if got := issues[b.ID]; got == nil || got.DeletedAt == nil {
+		t.Errorf("projected b should be soft-deleted: %+v", got)
+	}
+}
+
+func TestRebuildIssueProjection_RepairsDrift(t *testing.T) {
+	database, err := Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	defer database.Close()
+
+	logged := &models.Issue{Title: "Logged title"}
+	if err := database.CreateIssueLogged(logged, "sess-1"); err != nil {
+		t.Fatal(err)
+	}
+	unlogged := &models.Issue{Title: "No history"}
+	if err := database.CreateIssue(unlogged); err != nil {
+		t.Fatal(err)
+	}
+
+	// Corrupt the row behind the log's back
+	if _, err := database.conn.Exec(`UPDATE issues SET title = 'Tampered', status = 'closed' WHERE id = ?`, logged.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := database.RebuildIssueProjection(RebuildOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if len(res.Drift) != 1 || res.Drift[0].IssueID != logged.ID || res.Written != 0 {
+		t.Fatalf("dry run result = %+v", res)
+	}
+	if len(res.Unlogged) != 1 || res.Unlogged[0] != unlogged.ID {
+		t.Errorf("unlogged = %v", res.Unlogged)
+	}
+	if got, _ := database.GetIssue(logged.ID); got.Title != "Tampered" {
+		t.Fatalf("dry run wrote: %s", got.Title)
+	}
+
+	res, err = database.RebuildIssueProjection(RebuildOptions{})
+	if err != nil {
+		t.Fatalf("rebuild failed: %v", err)
+	}
+	if res.Written != 1 {
+		t.Errorf("written = %d, want 1", res.Written)
+	}
+	got, _ := database.GetIssue(logged.ID)
+	if got.Title != "Logged title" || got.Status != models.StatusOpen {
+		t.Errorf("after rebuild = %s/%s", got.Title, got.Status)
+	}
+	if got, _ := database.GetIssue(unlogged.ID); got.Title != "No history" {
+		t.Errorf("unlogged issue touched: %s", got.Title)
+	}
+
+	// A second rebuild finds nothing to do
+	if res, err = database.RebuildIssueProjection(RebuildOptions{}); err != nil || len(res.Drift) != 0 {
+		t.Errorf("second rebuild = %+v, %v", res, err)
+	}
+}
+
+func TestRebuildIssueProjection_AsOf(t *testing.T) {
+	database, err := Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	defer database.Close()
+
+	early := &models.Issue{Title: "Before cutoff"}
+	if err := database.CreateIssueLogged(early, "sess-1"); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	cutoff := time.Now()
+	time.Sleep(10 * time.Millisecond)
+
+	early.Title = "Edited after cutoff"
+	if err := database.UpdateIssueLogged(early, "sess-1", models.ActionUpdate); err != nil {
+		t.Fatal(err)
+	}
+	late := &models.Issue{Title: "Created after cutoff"}
+	if err := database.CreateIssueLogged(late, "sess-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := database.RebuildIssueProjection(RebuildOptions{AsOf: cutoff, SessionID: "sess-2"})
+	if err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+	if res.Written != 2 {
+		t.Fatalf("written = %d, want 2: %+v", res.Written, res.Drift)
+	}
+	if got, _ := database.GetIssue(early.ID); got.Title != "Before cutoff" {
+		t.Errorf("early title = %s", got.Title)
+	}
+	if got, _ := database.GetIssueWithDeleted(late.ID); got.DeletedAt == nil {
+		t.Error("issue created after cutoff should be deleted")
+	}
+
+	// Restore is recorded as new events, so replaying the full log agrees
+	var restoreEvents int
+	database.conn.QueryRow(`SELECT COUNT(*) FROM action_log WHERE session_id = 'sess-2'`).Scan(&restoreEvents)
+	if restoreEvents != 2 {
+		t.Errorf("restore events = %d, want 2", restoreEvents)
+	}
+	if res, err = database.RebuildIssueProjection(RebuildOptions{DryRun: true}); err != nil || len(res.Drift) != 0 {
+		t.Errorf("full replay after restore = %+v, %v", res, err)
+	}
+}
+
+func TestRebuildIssueProjection_RefusesAfterPull(t *testing.T) {
+	database, err := Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	defer database.Close()
+
+	if err := database.SetSyncState("proj-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.UpdateSyncPulled(42); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := database.RebuildIssueProjection(RebuildOptions{}); !errors.Is(err, ErrRemoteHistory) {
+		t.Errorf("err = %v, want ErrRemoteHistory", err)
+	}
+	if _, err := database.RebuildIssueProjection(RebuildOptions{Force: true}); err != nil {
+		t.Errorf("forced rebuild: %v", err)
+	}
+}
+
+func TestDecodeIssueSnapshot_Backfill(t *testing.T) {
+	issue, err := decodeIssueSnapshot(`{"id":"td-1","title":"Old","labels":"a,b","minor":1,"created_at":"2024-01-02 03:04:05","status":"open"}`)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(issue.Labels) != 2 || !issue.Minor || issue.CreatedAt.Year() != 2024 {
+		t.Errorf("decoded = %+v", issue)
+	}
+}
@@ -0,0 +1,231 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/marcus/td/internal/models"
+)
+
+// RankGap is the spacing of fresh rank keys, as PositionGap is for boards
+const RankGap = PositionGap
+
+// Where MoveRank puts issues
+const (
+	RankTop    = "top"
+	RankBottom = "bottom"
+	RankAbove  = "above"
+	RankBelow  = "below"
+)
+
+// ErrNotRanked is returned for moving an issue next to one that isn't
+// ranked
+var ErrNotRanked = errors.New("not ranked")
+
+// rankKey is an issue's place in the queue
+type rankKey struct {
+	id   string
+	rank int
+}
+
+// RankedIssues returns the ranked issues in queue order. Closed issues keep
+// their rank but are left out unless includeClosed is set.
+func (db *DB) RankedIssues(includeClosed bool) ([]models.Issue, error) {
+	opts := ListIssuesOptions{SortBy: "rank"}
+	if !includeClosed {
+		opts.Status = []models.Status{models.StatusOpen, models.StatusInProgress, models.StatusBlocked, models.StatusInReview}
+	}
+	issues, err := db.ListIssues(opts)
+	if err != nil {
+		return nil, err
+	}
+	ranked := make([]models.Issue, 0, len(issues))
+	for _, issue := range issues {
+		if issue.Rank > 0 {
+			ranked = append(ranked, issue)
+		}
+	}
+	return ranked, nil
+}
+
+// MoveRank puts issues in the queue, in the order given: at the top or the
+// bottom, or right above or below other, which must be ranked. Each issue
+// gets a key between its new neighbours; when two neighbours have no room
+// between them, every ranked issue is respaced RankGap apart first. All
+// changes are logged as updates by sessionID, so they sync.
+func (db *DB) MoveRank(ids []string, place, other, sessionID string) error {
+	switch place {
+	case RankTop, RankBottom:
+	case RankAbove, RankBelow:
+		other = NormalizeIssueID(other)
+		for _, id := range ids {
+			if NormalizeIssueID(id) == other {
+				return fmt.Errorf("can't rank %s relative to itself", other)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown place %q: use top, bottom, above or below", place)
+	}
+	if err := db.checkRankIssues(ids); err != nil {
+		return err
+	}
+
+	return db.withWriteLock(func() error {
+		for i, id := range ids {
+			id = NormalizeIssueID(id)
+			p, o := place, other
+			if i > 0 {
+				// Later issues follow the one before them
+				p, o = RankBelow, NormalizeIssueID(ids[i-1])
+			}
+			if err := db.moveRankLocked(id, p, o, sessionID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// checkRankIssues makes sure issues exist before their ranks change
+func (db *DB) checkRankIssues(ids []string) error {
+	if len(ids) == 0 {
+		return fmt.Errorf("no issues to rank")
+	}
+	for _, id := range ids {
+		if _, err := db.GetIssue(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// moveRankLocked ranks one issue. Caller must hold the write lock.
+func (db *DB) moveRankLocked(id, place, other, sessionID string) error {
+	issue, err := db.scanIssueRow(id)
+	if err != nil {
+		return fmt.Errorf("issue %s: %w", id, err)
+	}
+	keys, err := db.rankKeysLocked(id)
+	if err != nil {
+		return err
+	}
+
+	rank, ok, err := rankBetween(keys, place, other)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		if keys, err = db.respaceRanksLocked(keys, sessionID); err != nil {
+			return err
+		}
+		if rank, _, err = rankBetween(keys, place, other); err != nil {
+			return err
+		}
+	}
+	if issue.Rank == rank {
+		return nil
+	}
+	issue.Rank = rank
+	return db.updateIssueAndLog(issue, sessionID, models.ActionUpdate)
+}
+
+// rankBetween works out a key for place. ok is false when the neighbours
+// have no room between them.
+func rankBetween(keys []rankKey, place, other string) (rank int, ok bool, err error) {
+	slot := 0
+	switch place {
+	case RankTop:
+	case RankBottom:
+		slot = len(keys)
+	default:
+		slot = -1
+		for i, k := range keys {
+			if k.id == other {
+				slot = i
+				break
+			}
+		}
+		if slot < 0 {
+			return 0, false, fmt.Errorf("%s is %w: rank it first", other, ErrNotRanked)
+		}
+		if place == RankBelow {
+			slot++
+		}
+	}
+
+	lo := 0
+	if slot > 0 {
+		lo = keys[slot-1].rank
+	}
+	if slot == len(keys) {
+		return lo + RankGap, true, nil
+	}
+	hi := keys[slot].rank
+	mid := lo + (hi-lo)/2
+	return mid, mid > lo && mid < hi, nil
+}
+
+// rankKeysLocked returns the keys of ranked issues other than exclude, in
+// queue order
+func (db *DB) rankKeysLocked(exclude string) ([]rankKey, error) {
+	rows, err := db.conn.Query(`SELECT id, rank FROM issues
+		WHERE rank > 0 AND deleted_at IS NULL AND id != ? ORDER BY rank, id`, exclude)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []rankKey
+	for rows.Next() {
+		var k rankKey
+		if err := rows.Scan(&k.id, &k.rank); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+// respaceRanksLocked spreads the keys RankGap apart, keeping their order
+func (db *DB) respaceRanksLocked(keys []rankKey, sessionID string) ([]rankKey, error) {
+	for i := range keys {
+		rank := (i + 1) * RankGap
+		if keys[i].rank == rank {
+			continue
+		}
+		issue, err := db.scanIssueRow(keys[i].id)
+		if err != nil {
+			return nil, err
+		}
+		issue.Rank = rank
+		if err := db.updateIssueAndLog(issue, sessionID, models.ActionUpdate); err != nil {
+			return nil, fmt.Errorf("respace %s: %w", issue.ID, err)
+		}
+		keys[i].rank = rank
+	}
+	return keys, nil
+}
+
+// ClearRank takes issues out of the queue, logging each change. Issues
+// that aren't ranked are skipped.
+func (db *DB) ClearRank(ids []string, sessionID string) error {
+	if err := db.checkRankIssues(ids); err != nil {
+		return err
+	}
+	return db.withWriteLock(func() error {
+		for _, id := range ids {
+			issue, err := db.scanIssueRow(NormalizeIssueID(id))
+			if err != nil {
+				return fmt.Errorf("issue %s: %w", id, err)
+			}
+			if issue.Rank == 0 {
+				continue
+			}
+			issue.Rank = 0
+			if err := db.updateIssueAndLog(issue, sessionID, models.ActionUpdate); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,124 @@
+package db
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func rankOrder(t *testing.T, database *DB) []string {
+	t.Helper()
+	issues, err := database.RankedIssues(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, issue := range issues {
+		ids = append(ids, issue.ID)
+	}
+	return ids
+}
+
+func TestMoveRank(t *testing.T) {
+	database, err := Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+
+	var a, b, c, d models.Issue
+	for _, issue := range []*models.Issue{&a, &b, &c, &d} {
+		issue.Title = "Issue to rank"
+		issue.Priority = models.PriorityP2
+		if err := database.CreateIssueLogged(issue, "ses_a"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := database.MoveRank([]string{a.ID}, RankTop, "", "ses_a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.MoveRank([]string{b.ID, c.ID}, RankAbove, a.ID, "ses_a"); err != nil {
+		t.Fatal(err)
+	}
+	if got := rankOrder(t, database); !slices.Equal(got, []string{b.ID, c.ID, a.ID}) {
+		t.Fatalf("order = %v", got)
+	}
+
+	if err := database.MoveRank([]string{b.ID}, RankBelow, a.ID, "ses_a"); err != nil {
+		t.Fatal(err)
+	}
+	if got := rankOrder(t, database); !slices.Equal(got, []string{c.ID, a.ID, b.ID}) {
+		t.Fatalf("order after below = %v", got)
+	}
+
+	if err := database.MoveRank([]string{c.ID}, RankAbove, d.ID, "ses_a"); !errors.Is(err, ErrNotRanked) {
+		t.Errorf("above an unranked issue = %v, want ErrNotRanked", err)
+	}
+	if err := database.MoveRank([]string{c.ID}, "middle", "", "ses_a"); err == nil {
+		t.Error("accepted an unknown place")
+	}
+
+	// Ranked issues come first in rank order, the rest after by priority
+	d.Priority = models.PriorityP0
+	if err := database.UpdateIssueLogged(&d, "ses_a", models.ActionUpdate); err != nil {
+		t.Fatal(err)
+	}
+	issues, err := database.ListIssues(ListIssuesOptions{SortBy: "rank"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.ID)
+	}
+	if !slices.Equal(got, []string{c.ID, a.ID, b.ID, d.ID}) {
+		t.Errorf("ListIssues by rank = %v", got)
+	}
+
+	if err := database.ClearRank([]string{a.ID}, "ses_a"); err != nil {
+		t.Fatal(err)
+	}
+	if got := rankOrder(t, database); !slices.Equal(got, []string{c.ID, b.ID}) {
+		t.Errorf("order after clear = %v", got)
+	}
+}
+
+func TestMoveRankRespaces(t *testing.T) {
+	database, err := Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+
+	var a, b, c models.Issue
+	for _, issue := range []*models.Issue{&a, &b, &c} {
+		issue.Title = "Issue to rank"
+		if err := database.CreateIssueLogged(issue, "ses_a"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := database.MoveRank([]string{a.ID, b.ID}, RankTop, "", "ses_a"); err != nil {
+		t.Fatal(err)
+	}
+	// Leave no room between a and b
+	if _, err := database.conn.Exec(`UPDATE issues SET rank = 1 WHERE id = ?`, a.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := database.conn.Exec(`UPDATE issues SET rank = 2 WHERE id = ?`, b.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := database.MoveRank([]string{c.ID}, RankBelow, a.ID, "ses_a"); err != nil {
+		t.Fatal(err)
+	}
+	if got := rankOrder(t, database); !slices.Equal(got, []string{a.ID, c.ID, b.ID}) {
+		t.Fatalf("order = %v", got)
+	}
+	respaced, _ := database.GetIssue(b.ID)
+	if respaced.Rank != 2*RankGap {
+		t.Errorf("b's rank = %d, want %d", respaced.Rank, 2*RankGap)
+	}
+}
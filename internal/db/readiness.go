@@ -0,0 +1,98 @@
+package db
+
+import (
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/workflow"
+)
+
+// ReadinessGaps checks issues against the project's definition of ready
+// and returns what each failing issue lacks, keyed by issue ID. Issues that
+// pass are absent; with no gate configured only issues in the triage inbox
+// fail. A parent is unresolved when the issue points at one that is missing
+// or deleted.
+func (db *DB) ReadinessGaps(issues []models.Issue) (map[string][]string, error) {
+	gaps := make(map[string][]string)
+	criteria, err := config.GetReadyGate(db.baseDir)
+	if err != nil {
+		slog.Debug("ready gate: load config", "err", err)
+		criteria = nil
+	}
+
+	var unresolved map[string]bool
+	if slices.Contains(criteria, workflow.ReadyResolvedParent) {
+		if unresolved, err = db.unresolvedParentIDs(issues); err != nil {
+			return nil, err
+		}
+	}
+	for i := range issues {
+		issue := &issues[i]
+		missing := workflow.ReadyGaps(issue, criteria)
+		if unresolved[issue.ID] {
+			missing = append(missing, workflow.ReadyResolvedParent)
+		}
+		if len(missing) > 0 {
+			gaps[issue.ID] = missing
+		}
+	}
+	return gaps, nil
+}
+
+// SplitReady separates issues that meet the definition of ready from those
+// that need triage, keeping their order. Lookup errors leave every issue
+// ready rather than hiding work.
+func (db *DB) SplitReady(issues []models.Issue) (ready, triage []models.Issue) {
+	gaps, err := db.ReadinessGaps(issues)
+	if err != nil {
+		slog.Debug("ready gate", "err", err)
+		return issues, nil
+	}
+	if len(gaps) == 0 {
+		return issues, nil
+	}
+	for _, issue := range issues {
+		if _, ok := gaps[issue.ID]; ok {
+			triage = append(triage, issue)
+		} else {
+			ready = append(ready, issue)
+		}
+	}
+	return ready, triage
+}
+
+// unresolvedParentIDs returns which of the issues have a parent that is
+// missing or deleted
+func (db *DB) unresolvedParentIDs(issues []models.Issue) (map[string]bool, error) {
+	ids := make([]interface{}, 0, len(issues))
+	for _, issue := range issues {
+		if issue.ParentID != "" {
+			ids = append(ids, issue.ID)
+		}
+	}
+	unresolved := make(map[string]bool)
+	if len(ids) == 0 {
+		return unresolved, nil
+	}
+	rows, err := db.conn.Query(`
+		SELECT i.id FROM issues i
+		LEFT JOIN issues p ON p.id = i.parent_id AND p.deleted_at IS NULL
+		WHERE i.id IN (?`+strings.Repeat(",?", len(ids)-1)+`)
+		AND COALESCE(i.parent_id, '') != '' AND p.id IS NULL
+	`, ids...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		unresolved[id] = true
+	}
+	return unresolved, rows.Err()
+}
@@ -0,0 +1,66 @@
+package db
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/models"
+)
+
+func TestReadinessGaps(t *testing.T) {
+	dir := t.TempDir()
+	database, err := Initialize(dir)
+	if err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	defer database.Close()
+
+	parent := &models.Issue{Title: "Parent", Type: models.TypeEpic}
+	gone := &models.Issue{Title: "Deleted parent", Type: models.TypeEpic}
+	for _, issue := range []*models.Issue{parent, gone} {
+		if err := database.CreateIssue(issue); err != nil {
+			t.Fatalf("CreateIssue: %v", err)
+		}
+	}
+	ready := &models.Issue{Title: "Ready", Points: 2, Acceptance: "Done", ParentID: parent.ID}
+	bare := &models.Issue{Title: "Bare"}
+	orphan := &models.Issue{Title: "Orphan", Points: 1, Acceptance: "Done", ParentID: gone.ID}
+	for _, issue := range []*models.Issue{ready, bare, orphan} {
+		if err := database.CreateIssue(issue); err != nil {
+			t.Fatalf("CreateIssue: %v", err)
+		}
+	}
+	if err := database.DeleteIssue(gone.ID); err != nil {
+		t.Fatalf("DeleteIssue: %v", err)
+	}
+	issues := []models.Issue{*ready, *bare, *orphan}
+
+	// No gate: everything is ready
+	gaps, err := database.ReadinessGaps(issues)
+	if err != nil || len(gaps) != 0 {
+		t.Fatalf("no gate: gaps = %v, err = %v, want none", gaps, err)
+	}
+
+	if err := config.SetReadyGate(dir, []string{"points", "acceptance", "resolved_parent"}); err != nil {
+		t.Fatalf("SetReadyGate: %v", err)
+	}
+	gaps, err = database.ReadinessGaps(issues)
+	if err != nil {
+		t.Fatalf("ReadinessGaps: %v", err)
+	}
+	if _, ok := gaps[ready.ID]; ok {
+		t.Errorf("ready issue has gaps %v", gaps[ready.ID])
+	}
+	if got, want := gaps[bare.ID], []string{"points", "acceptance"}; !slices.Equal(got, want) {
+		t.Errorf("bare gaps = %v, want %v", got, want)
+	}
+	if got, want := gaps[orphan.ID], []string{"resolved_parent"}; !slices.Equal(got, want) {
+		t.Errorf("orphan gaps = %v, want %v", got, want)
+	}
+
+	readyIssues, triage := database.SplitReady(issues)
+	if len(readyIssues) != 1 || readyIssues[0].ID != ready.ID || len(triage) != 2 {
+		t.Errorf("SplitReady = %d ready, %d triage; want only %s ready", len(readyIssues), len(triage), ready.ID)
+	}
+}
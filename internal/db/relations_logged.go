@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/marcus/td/internal/clock"
 	"github.com/marcus/td/internal/models"
 )
 
@@ -33,7 +34,7 @@ func (db *DB) AddDependencyLogged(issueID, dependsOnID, relationType, sessionID
 		if err != nil {
 			return fmt.Errorf("generate action ID: %w", err)
 		}
-		now := time.Now()
+		now := clock.Now()
 		newData := marshalDependency(depID, issueID, dependsOnID, relationType)
 		actionTS := formatActionLogTimestamp(now)
 		_, err = db.conn.Exec(`INSERT INTO action_log (id, session_id, action_type, entity_type, entity_id, previous_data, new_data, timestamp, undone) VALUES (?, ?, ?, ?, ?, ?, ?, ?, 0)`,
@@ -58,7 +59,7 @@ func marshalFileLink(id, issueID, filePath, role, sha, linkedAt string) string {
 func (db *DB) LinkFileLogged(issueID, filePath string, role models.FileRole, sha, sessionID string) error {
 	return db.withWriteLock(func() error {
 		id := IssueFileID(issueID, filePath)
-		now := time.Now()
+		now := clock.Now()
 		_, err := db.conn.Exec(`
 			INSERT OR REPLACE INTO issue_files (id, issue_id, file_path, role, linked_sha, linked_at)
 			VALUES (?, ?, ?, ?, ?, ?)
@@ -106,7 +107,7 @@ func (db *DB) UnlinkFileLogged(issueID, filePath, sessionID string) error {
 		if err != nil {
 			return fmt.Errorf("generate action ID: %w", err)
 		}
-		now := time.Now()
+		now := clock.Now()
 		actionTS := formatActionLogTimestamp(now)
 		_, err = db.conn.Exec(`INSERT INTO action_log (id, session_id, action_type, entity_type, entity_id, previous_data, new_data, timestamp, undone) VALUES (?, ?, ?, ?, ?, ?, ?, ?, 0)`,
 			actionID, sessionID, string(models.ActionUnlinkFile), "issue_files", id, previousData, "", actionTS)
@@ -120,12 +121,20 @@ func (db *DB) UnlinkFileLogged(issueID, filePath, sessionID string) error {
 // RemoveDependencyLogged removes a dependency and logs the action atomically within a single withWriteLock call.
 // If the dependency does not exist locally, this is a no-op (no action_log entry is created).
 func (db *DB) RemoveDependencyLogged(issueID, dependsOnID, sessionID string) error {
+	return db.RemoveRelationLogged(issueID, dependsOnID, "depends_on", sessionID)
+}
+
+// RemoveRelationLogged removes one relation of the given type between two
+// issues and logs the action, leaving relations of other types in place.
+// If the relation does not exist locally, this is a no-op.
+func (db *DB) RemoveRelationLogged(issueID, dependsOnID, relationType, sessionID string) error {
 	return db.withWriteLock(func() error {
-		depID := DependencyID(issueID, dependsOnID, "depends_on")
+		depID := DependencyID(issueID, dependsOnID, relationType)
 
-		// Check if the dependency exists before deleting
-		var relationType string
-		err := db.conn.QueryRow(`SELECT relation_type FROM issue_dependencies WHERE issue_id = ? AND depends_on_id = ?`, issueID, dependsOnID).Scan(&relationType)
+		// Check if the relation exists before deleting
+		var exists int
+		err := db.conn.QueryRow(`SELECT 1 FROM issue_dependencies WHERE issue_id = ? AND depends_on_id = ? AND relation_type = ?`,
+			issueID, dependsOnID, relationType).Scan(&exists)
 		if err != nil {
 			// Row doesn't exist, nothing to remove
 			return nil
@@ -133,7 +142,8 @@ func (db *DB) RemoveDependencyLogged(issueID, dependsOnID, sessionID string) err
 
 		previousData := marshalDependency(depID, issueID, dependsOnID, relationType)
 
-		_, err = db.conn.Exec(`DELETE FROM issue_dependencies WHERE issue_id = ? AND depends_on_id = ?`, issueID, dependsOnID)
+		_, err = db.conn.Exec(`DELETE FROM issue_dependencies WHERE issue_id = ? AND depends_on_id = ? AND relation_type = ?`,
+			issueID, dependsOnID, relationType)
 		if err != nil {
 			return err
 		}
@@ -143,7 +153,7 @@ func (db *DB) RemoveDependencyLogged(issueID, dependsOnID, sessionID string) err
 		if err != nil {
 			return fmt.Errorf("generate action ID: %w", err)
 		}
-		now := time.Now()
+		now := clock.Now()
 		actionTS := formatActionLogTimestamp(now)
 		_, err = db.conn.Exec(`INSERT INTO action_log (id, session_id, action_type, entity_type, entity_id, previous_data, new_data, timestamp, undone) VALUES (?, ?, ?, ?, ?, ?, ?, ?, 0)`,
 			actionID, sessionID, string(models.ActionRemoveDep), "issue_dependencies", depID, previousData, "", actionTS)
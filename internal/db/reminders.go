@@ -0,0 +1,181 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/models"
+)
+
+// ErrReminderNotPending is returned when cancelling a reminder that already
+// fired or was cancelled.
+var ErrReminderNotPending = errors.New("reminder is not pending")
+
+const reminderColumns = `id, issue_id, session_id, message, status, remind_at, created_at, fired_at`
+
+// ReminderFilter narrows ListReminders. Zero values match everything.
+type ReminderFilter struct {
+	IssueID string
+	Status  []models.ReminderStatus
+}
+
+// CreateReminder stores a pending reminder. ID, Status and CreatedAt are
+// filled in. Reminders are personal nudges and are not written to the
+// action log or synced.
+func (db *DB) CreateReminder(r *models.Reminder) error {
+	return db.withWriteLock(func() error {
+		id, err := generateReminderID()
+		if err != nil {
+			return err
+		}
+		r.ID = id
+		r.Status = models.ReminderPending
+		r.CreatedAt = clock.Now().UTC()
+
+		_, err = db.conn.Exec(`INSERT INTO reminders (id, issue_id, session_id, message, status, remind_at, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			r.ID, r.IssueID, r.SessionID, r.Message, string(r.Status),
+			r.RemindAt.UTC().Format(time.RFC3339), r.CreatedAt.Format(time.RFC3339))
+		return err
+	})
+}
+
+// GetReminder retrieves a reminder by ID
+func (db *DB) GetReminder(id string) (*models.Reminder, error) {
+	row := db.conn.QueryRow(`SELECT `+reminderColumns+` FROM reminders WHERE id = ?`, id)
+	r, err := scanReminder(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("reminder not found: %s", id)
+	}
+	return r, err
+}
+
+// ListReminders returns reminders soonest first
+func (db *DB) ListReminders(filter ReminderFilter) ([]models.Reminder, error) {
+	query := `SELECT ` + reminderColumns + ` FROM reminders`
+	var where []string
+	var args []interface{}
+	if filter.IssueID != "" {
+		where = append(where, "issue_id = ?")
+		args = append(args, filter.IssueID)
+	}
+	if len(filter.Status) > 0 {
+		placeholders := make([]string, len(filter.Status))
+		for i, st := range filter.Status {
+			placeholders[i] = "?"
+			args = append(args, string(st))
+		}
+		where = append(where, "status IN ("+strings.Join(placeholders, ", ")+")")
+	}
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += ` ORDER BY remind_at, id`
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reminders []models.Reminder
+	for rows.Next() {
+		r, err := scanReminder(rows)
+		if err != nil {
+			return nil, err
+		}
+		reminders = append(reminders, *r)
+	}
+	return reminders, rows.Err()
+}
+
+// CancelReminder cancels a pending reminder
+func (db *DB) CancelReminder(id string) error {
+	return db.withWriteLock(func() error {
+		res, err := db.conn.Exec(`UPDATE reminders SET status = ? WHERE id = ? AND status = ?`,
+			string(models.ReminderCancelled), id, string(models.ReminderPending))
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return ErrReminderNotPending
+		}
+		return nil
+	})
+}
+
+// FireDueReminders marks every pending reminder due at or before now as
+// fired and returns them. Each reminder is claimed atomically, so when a
+// monitor and td serve both poll, only one of them delivers it.
+func (db *DB) FireDueReminders(now time.Time) ([]models.Reminder, error) {
+	nowStr := now.UTC().Format(time.RFC3339)
+	var fired []models.Reminder
+	err := db.withWriteLock(func() error {
+		rows, err := db.conn.Query(`SELECT `+reminderColumns+` FROM reminders
+			WHERE status = ? AND remind_at <= ? ORDER BY remind_at, id`,
+			string(models.ReminderPending), nowStr)
+		if err != nil {
+			return err
+		}
+		var due []models.Reminder
+		for rows.Next() {
+			r, err := scanReminder(rows)
+			if err != nil {
+				rows.Close()
+				return err
+			}
+			due = append(due, *r)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		firedAt, _ := time.Parse(time.RFC3339, nowStr)
+		for _, r := range due {
+			res, err := db.conn.Exec(`UPDATE reminders SET status = ?, fired_at = ? WHERE id = ? AND status = ?`,
+				string(models.ReminderFired), nowStr, r.ID, string(models.ReminderPending))
+			if err != nil {
+				return err
+			}
+			if n, _ := res.RowsAffected(); n == 0 {
+				continue
+			}
+			r.Status = models.ReminderFired
+			r.FiredAt = &firedAt
+			fired = append(fired, r)
+		}
+		return nil
+	})
+	return fired, err
+}
+
+// reminderScanner is satisfied by *sql.Row and *sql.Rows
+type reminderScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanReminder(row reminderScanner) (*models.Reminder, error) {
+	var r models.Reminder
+	var status, remindAt, createdAt string
+	var firedAt sql.NullString
+
+	if err := row.Scan(&r.ID, &r.IssueID, &r.SessionID, &r.Message, &status,
+		&remindAt, &createdAt, &firedAt); err != nil {
+		return nil, err
+	}
+
+	r.Status = models.ReminderStatus(status)
+	r.RemindAt, _ = time.Parse(time.RFC3339, remindAt)
+	r.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	if firedAt.Valid && firedAt.String != "" {
+		if t, err := time.Parse(time.RFC3339, firedAt.String); err == nil {
+			r.FiredAt = &t
+		}
+	}
+	return &r, nil
+}
@@ -0,0 +1,65 @@
+package db
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestRemindersLifecycle(t *testing.T) {
+	database, err := Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+
+	now := time.Now().Truncate(time.Second)
+	soon := &models.Reminder{IssueID: "td-a", Message: "check CI flake", RemindAt: now.Add(-time.Minute)}
+	later := &models.Reminder{IssueID: "td-b", RemindAt: now.Add(72 * time.Hour)}
+	for _, r := range []*models.Reminder{later, soon} {
+		if err := database.CreateReminder(r); err != nil {
+			t.Fatalf("CreateReminder: %v", err)
+		}
+	}
+	if soon.Status != models.ReminderPending || soon.ID == "" {
+		t.Fatalf("created reminder = %+v", soon)
+	}
+
+	list, err := database.ListReminders(ReminderFilter{})
+	if err != nil || len(list) != 2 || list[0].ID != soon.ID {
+		t.Fatalf("ListReminders = %+v, %v; want soonest first", list, err)
+	}
+
+	fired, err := database.FireDueReminders(now)
+	if err != nil {
+		t.Fatalf("FireDueReminders: %v", err)
+	}
+	if len(fired) != 1 || fired[0].ID != soon.ID || fired[0].Status != models.ReminderFired || fired[0].FiredAt == nil {
+		t.Fatalf("fired = %+v, want only %s", fired, soon.ID)
+	}
+	if again, _ := database.FireDueReminders(now); len(again) != 0 {
+		t.Errorf("reminder fired twice: %+v", again)
+	}
+
+	if err := database.CancelReminder(soon.ID); !errors.Is(err, ErrReminderNotPending) {
+		t.Errorf("cancel fired reminder err = %v, want ErrReminderNotPending", err)
+	}
+	if err := database.CancelReminder(later.ID); err != nil {
+		t.Fatalf("CancelReminder: %v", err)
+	}
+	got, err := database.GetReminder(later.ID)
+	if err != nil || got.Status != models.ReminderCancelled {
+		t.Errorf("GetReminder after cancel = %+v, %v", got, err)
+	}
+
+	pending, _ := database.ListReminders(ReminderFilter{Status: []models.ReminderStatus{models.ReminderPending}})
+	if len(pending) != 0 {
+		t.Errorf("pending after fire+cancel = %+v", pending)
+	}
+	byIssue, _ := database.ListReminders(ReminderFilter{IssueID: "td-b"})
+	if len(byIssue) != 1 || byIssue[0].ID != later.ID {
+		t.Errorf("ListReminders by issue = %+v", byIssue)
+	}
+}
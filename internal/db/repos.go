@@ -0,0 +1,64 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+)
+
+// Repo is a git repository seen by this project on this machine. Several
+// repositories can share one project (via .td-root); the registry maps each
+// repository's identity to its local checkout so git lookups can run in
+// the right place. It is local and not synced, since paths differ per
+// machine.
+type Repo struct {
+	Name     string    `json:"name"`
+	Path     string    `json:"path"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// RecordRepo remembers where a repository is checked out
+func (db *DB) RecordRepo(name, path string) error {
+	if name == "" || path == "" {
+		return nil
+	}
+	return db.withWriteLock(func() error {
+		_, err := db.conn.Exec(`INSERT INTO repos (name, path, last_seen) VALUES (?, ?, ?)
+			ON CONFLICT(name) DO UPDATE SET path = excluded.path, last_seen = excluded.last_seen`,
+			name, path, clock.Now().UTC().Format(time.RFC3339))
+		return err
+	})
+}
+
+// RepoPath returns the local checkout of a repository, or "" if this
+// project has not seen it on this machine
+func (db *DB) RepoPath(name string) (string, error) {
+	var path string
+	err := db.conn.QueryRow(`SELECT path FROM repos WHERE name = ?`, name).Scan(&path)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return path, err
+}
+
+// ListRepos returns known repositories, most recently seen first
+func (db *DB) ListRepos() ([]Repo, error) {
+	rows, err := db.conn.Query(`SELECT name, path, last_seen FROM repos ORDER BY last_seen DESC, name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var repos []Repo
+	for rows.Next() {
+		var r Repo
+		var lastSeen string
+		if err := rows.Scan(&r.Name, &r.Path, &lastSeen); err != nil {
+			return nil, err
+		}
+		r.LastSeen, _ = time.Parse(time.RFC3339, lastSeen)
+		repos = append(repos, r)
+	}
+	return repos, rows.Err()
+}
@@ -0,0 +1,297 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/models"
+)
+
+// RetentionSessionID is the session summary logs written by retention are
+// attributed to
+const RetentionSessionID = "retention"
+
+// RetentionOptions says what ApplyRetention prunes. A zero cutoff leaves
+// that kind of record alone.
+type RetentionOptions struct {
+	LogsBefore     time.Time // logs older than this are summarized and pruned
+	ActivityBefore time.Time // action_log rows older than this are pruned
+	DryRun         bool      // report what would go without changing anything
+}
+
+// RetentionOptionsFor turns a retention policy into cutoffs as of now.
+// A nil policy prunes nothing.
+func RetentionOptionsFor(cfg *models.RetentionConfig, now time.Time) RetentionOptions {
+	var opts RetentionOptions
+	if cfg == nil {
+		return opts
+	}
+	if cfg.LogDays > 0 {
+		opts.LogsBefore = now.AddDate(0, 0, -cfg.LogDays)
+	}
+	if cfg.ActivityDays > 0 {
+		opts.ActivityBefore = now.AddDate(0, 0, -cfg.ActivityDays)
+	}
+	return opts
+}
+
+// LogRetention is what retention did, or would do, to one issue's logs
+type LogRetention struct {
+	IssueID string         `json:"issue_id"`
+	Pruned  int            `json:"pruned"`
+	From    time.Time      `json:"from"`
+	To      time.Time      `json:"to"`
+	ByType  map[string]int `json:"by_type"`
+}
+
+// RetentionReport describes a retention run
+type RetentionReport struct {
+	DryRun        bool           `json:"dry_run"`
+	LogsPruned    int            `json:"logs_pruned"`
+	Issues        []LogRetention `json:"issues"`
+	LogsExempt    int            `json:"logs_exempt"`    // old logs kept because open work references their issue
+	ExemptIssues  []string       `json:"exempt_issues"`  // issues whose old logs were kept
+	ActionsPruned int            `json:"actions_pruned"` // action_log rows removed
+	ActionsKept   int            `json:"actions_kept"`   // old action_log rows kept because they are not yet synced
+}
+
+// ApplyRetention prunes logs and activity history older than the cutoffs
+// in opts. Each issue's pruned logs are replaced by one summary log, so
+// td show still says what happened. Issues referenced by open work keep
+// all their logs: open issues, issues an open issue depends on, parents of
+// open children, and issues tagged to a work session still in progress.
+// Action log rows are only pruned once pushed to the sync server, or
+// freely when the project is not linked, so the outbox is never lost.
+//
+// Retention is local housekeeping and is not recorded in the action log.
+func (db *DB) ApplyRetention(opts RetentionOptions) (*RetentionReport, error) {
+	report := &RetentionReport{DryRun: opts.DryRun, Issues: []LogRetention{}, ExemptIssues: []string{}}
+	err := db.withWriteLock(func() error {
+		var logIDs, actionIDs []interface{}
+		var summaries []models.Log
+		if !opts.LogsBefore.IsZero() {
+			var err error
+			if logIDs, summaries, err = db.planLogRetention(opts.LogsBefore, report); err != nil {
+				return fmt.Errorf("plan log retention: %w", err)
+			}
+		}
+		if !opts.ActivityBefore.IsZero() {
+			var err error
+			if actionIDs, err = db.planActivityRetention(opts.ActivityBefore, report); err != nil {
+				return fmt.Errorf("plan activity retention: %w", err)
+			}
+		}
+		if opts.DryRun || len(logIDs) == 0 && len(actionIDs) == 0 {
+			return nil
+		}
+
+		tx, err := db.conn.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+		if err := deleteByIDsTx(tx, "logs", "id", logIDs); err != nil {
+			return fmt.Errorf("prune logs: %w", err)
+		}
+		for _, l := range summaries {
+			id, err := generateLogID()
+			if err != nil {
+				return fmt.Errorf("generate ID: %w", err)
+			}
+			if _, err := tx.Exec(`INSERT INTO logs (id, issue_id, session_id, work_session_id, message, type, timestamp)
+				VALUES (?, ?, ?, '', ?, ?, ?)`, id, l.IssueID, l.SessionID, l.Message, l.Type, l.Timestamp); err != nil {
+				return fmt.Errorf("summarize logs: %w", err)
+			}
+		}
+		if err := deleteByIDsTx(tx, "action_log", "rowid", actionIDs); err != nil {
+			return fmt.Errorf("prune activity: %w", err)
+		}
+		return tx.Commit()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// planLogRetention finds the logs older than cutoff on issues not
+// referenced by open work, and builds each issue's summary log
+func (db *DB) planLogRetention(cutoff time.Time, report *RetentionReport) ([]interface{}, []models.Log, error) {
+	exempt, err := db.retentionExemptIssues()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Timestamps are stored in more than one text format, so the age check
+	// happens here rather than in SQL
+	rows, err := db.conn.Query(`SELECT id, issue_id, type, timestamp FROM logs WHERE session_id != ?`, RetentionSessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var ids []interface{}
+	byIssue := make(map[string]*LogRetention)
+	exemptSeen := make(map[string]bool)
+	for rows.Next() {
+		var id, issueID, typ string
+		var at sql.NullTime
+		if err := rows.Scan(&id, &issueID, &typ, &at); err != nil {
+			return nil, nil, err
+		}
+		if !at.Valid || !at.Time.Before(cutoff) {
+			continue
+		}
+		if exempt[issueID] {
+			report.LogsExempt++
+			exemptSeen[issueID] = true
+			continue
+		}
+		ids = append(ids, id)
+		r := byIssue[issueID]
+		if r == nil {
+			r = &LogRetention{IssueID: issueID, From: at.Time, To: at.Time, ByType: map[string]int{}}
+			byIssue[issueID] = r
+		}
+		r.Pruned++
+		r.ByType[typ]++
+		if at.Time.Before(r.From) {
+			r.From = at.Time
+		}
+		if at.Time.After(r.To) {
+			r.To = at.Time
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	var summaries []models.Log
+	for _, r := range byIssue {
+		report.Issues = append(report.Issues, *r)
+		report.LogsPruned += r.Pruned
+		if r.IssueID == "" {
+			continue
+		}
+		summaries = append(summaries, models.Log{
+			IssueID:   r.IssueID,
+			SessionID: RetentionSessionID,
+			Message:   retentionSummary(r),
+			Type:      models.LogTypeProgress,
+			Timestamp: r.To.UTC(),
+		})
+	}
+	sort.Slice(report.Issues, func(i, j int) bool { return report.Issues[i].IssueID < report.Issues[j].IssueID })
+	for id := range exemptSeen {
+		report.ExemptIssues = append(report.ExemptIssues, id)
+	}
+	sort.Strings(report.ExemptIssues)
+	return ids, summaries, nil
+}
+
+// retentionSummary is the log message standing in for an issue's pruned logs
+func retentionSummary(r *LogRetention) string {
+	types := make([]string, 0, len(r.ByType))
+	for t := range r.ByType {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	parts := make([]string, len(types))
+	for i, t := range types {
+		parts[i] = fmt.Sprintf("%d %s", r.ByType[t], t)
+	}
+	return fmt.Sprintf("Retention: summarized %d logs from %s to %s (%s)",
+		r.Pruned, r.From.UTC().Format("2006-01-02"), r.To.UTC().Format("2006-01-02"), strings.Join(parts, ", "))
+}
+
+// retentionExemptIssues returns the issues open work references, whose
+// logs retention keeps
+func (db *DB) retentionExemptIssues() (map[string]bool, error) {
+	exempt := make(map[string]bool)
+	for _, query := range []string{
+		`SELECT id FROM issues WHERE status != 'closed' AND deleted_at IS NULL`,
+		`SELECT d.depends_on_id FROM issue_dependencies d JOIN issues i ON i.id = d.issue_id
+			WHERE i.status != 'closed' AND i.deleted_at IS NULL`,
+		`SELECT parent_id FROM issues WHERE parent_id != '' AND status != 'closed' AND deleted_at IS NULL`,
+		`SELECT wsi.issue_id FROM work_session_issues wsi JOIN work_sessions ws ON ws.id = wsi.work_session_id
+			WHERE ws.ended_at IS NULL`,
+	} {
+		rows, err := db.conn.Query(query)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			exempt[id] = true
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return exempt, nil
+}
+
+// planActivityRetention finds the action_log rows older than cutoff that
+// may go: those already pushed, or any when the project is not linked
+func (db *DB) planActivityRetention(cutoff time.Time, report *RetentionReport) ([]interface{}, error) {
+	state, err := db.GetSyncState()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.conn.Query(`SELECT rowid, timestamp, synced_at IS NOT NULL FROM action_log`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []interface{}
+	for rows.Next() {
+		var id int64
+		var at sql.NullTime
+		var synced bool
+		if err := rows.Scan(&id, &at, &synced); err != nil {
+			return nil, err
+		}
+		if !at.Valid || !at.Time.Before(cutoff) {
+			continue
+		}
+		if state != nil && !synced {
+			report.ActionsKept++
+			continue
+		}
+		ids = append(ids, id)
+	}
+	report.ActionsPruned = len(ids)
+	return ids, rows.Err()
+}
+
+// deleteByIDsTx deletes rows of table whose column is one of ids, in
+// batches that stay under SQLite's variable limit
+func deleteByIDsTx(tx *sql.Tx, table, column string, ids []interface{}) error {
+	const batch = 500
+	for start := 0; start < len(ids); start += batch {
+		args := ids[start:min(start+batch, len(ids))]
+		query := fmt.Sprintf(`DELETE FROM %s WHERE %s IN (?%s)`, table, column, strings.Repeat(", ?", len(args)-1))
+		if _, err := tx.Exec(query, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Vacuum rebuilds the database file to return the space freed by deleted
+// rows to the filesystem
+func (db *DB) Vacuum() error {
+	return db.withWriteLock(func() error {
+		_, err := db.conn.Exec(`VACUUM`)
+		return err
+	})
+}
@@ -0,0 +1,106 @@
+package db
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/models"
+)
+
+func TestApplyRetention(t *testing.T) {
+	database, err := Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+
+	now := time.Now()
+	fake := clock.NewFake(now.AddDate(-2, 0, 0))
+	restore := clock.Set(fake.Now)
+	defer restore()
+
+	issue := func(title string, status models.Status) string {
+		i := &models.Issue{Title: title}
+		if err := database.CreateIssue(i); err != nil {
+			t.Fatal(err)
+		}
+		i.Status = status
+		if err := database.UpdateIssue(i); err != nil {
+			t.Fatal(err)
+		}
+		for _, typ := range []models.LogType{models.LogTypeProgress, models.LogTypeProgress, models.LogTypeDecision} {
+			if err := database.AddLog(&models.Log{IssueID: i.ID, SessionID: "ses_a", Message: "worked on it", Type: typ}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return i.ID
+	}
+	done := issue("Finished work long ago", models.StatusClosed)
+	open := issue("Still open after years", models.StatusOpen)
+	blocker := issue("Closed but blocking open work", models.StatusClosed)
+	if err := database.AddDependency(open, blocker, "depends_on"); err != nil {
+		t.Fatal(err)
+	}
+	fake.SetTime(now)
+	if err := database.AddLog(&models.Log{IssueID: done, SessionID: "ses_a", Message: "recent note", Type: models.LogTypeProgress}); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := RetentionOptionsFor(&models.RetentionConfig{LogDays: 365, ActivityDays: 365}, now)
+	opts.DryRun = true
+	report, err := database.ApplyRetention(opts)
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if report.LogsPruned != 3 || len(report.Issues) != 1 || report.Issues[0].IssueID != done || report.Issues[0].ByType["progress"] != 2 {
+		t.Errorf("dry run logs = %+v", report)
+	}
+	exempt := []string{open, blocker}
+	sort.Strings(exempt)
+	if report.LogsExempt != 6 || !reflect.DeepEqual(report.ExemptIssues, exempt) {
+		t.Errorf("exempt = %d %v", report.LogsExempt, report.ExemptIssues)
+	}
+	if report.ActionsPruned == 0 {
+		t.Error("dry run found no old action log rows")
+	}
+	if logs, _ := database.GetLogs(done, 0); len(logs) != 4 {
+		t.Fatalf("dry run changed logs: %d", len(logs))
+	}
+
+	opts.DryRun = false
+	if report, err = database.ApplyRetention(opts); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	logs, _ := database.GetLogs(done, 0)
+	if len(logs) != 2 {
+		t.Fatalf("logs after retention = %+v", logs)
+	}
+	var summary string
+	for _, l := range logs {
+		if l.SessionID == RetentionSessionID {
+			summary = l.Message
+		}
+	}
+	if !strings.Contains(summary, "summarized 3 logs") || !strings.Contains(summary, "1 decision, 2 progress") {
+		t.Errorf("summary = %q", summary)
+	}
+	if logs, _ := database.GetLogs(blocker, 0); len(logs) != 3 {
+		t.Errorf("exempt issue lost logs: %d", len(logs))
+	}
+
+	// Once linked, unsynced history is the outbox and stays
+	if err := database.SetSyncState("proj-1"); err != nil {
+		t.Fatal(err)
+	}
+	opts.ActivityBefore = now.Add(time.Hour)
+	if report, err = database.ApplyRetention(opts); err != nil {
+		t.Fatal(err)
+	}
+	if report.ActionsPruned != 0 || report.ActionsKept == 0 || report.LogsPruned != 0 {
+		t.Errorf("linked rerun = %+v", report)
+	}
+}
@@ -0,0 +1,91 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/models"
+)
+
+const retroColumns = `id, sprint, kind, text, issue_id, session_id, created_at`
+
+// CreateRetroItem stores a retrospective item. ID and CreatedAt are filled
+// in. Retro items are not written to the action log or synced.
+func (db *DB) CreateRetroItem(item *models.RetroItem) error {
+	return db.withWriteLock(func() error {
+		id, err := generateRetroID()
+		if err != nil {
+			return err
+		}
+		item.ID = id
+		item.CreatedAt = clock.Now().UTC()
+
+		_, err = db.conn.Exec(`INSERT INTO retro_items (`+retroColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			item.ID, item.Sprint, string(item.Kind), item.Text, item.IssueID, item.SessionID,
+			item.CreatedAt.Format(time.RFC3339))
+		return err
+	})
+}
+
+// GetRetroItem retrieves a retro item by ID
+func (db *DB) GetRetroItem(id string) (*models.RetroItem, error) {
+	row := db.conn.QueryRow(`SELECT `+retroColumns+` FROM retro_items WHERE id = ?`, id)
+	item, err := scanRetroItem(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("retro item not found: %s", id)
+	}
+	return item, err
+}
+
+// ListRetroItems returns a sprint's retro items in the order they were added
+func (db *DB) ListRetroItems(sprint string) ([]models.RetroItem, error) {
+	rows, err := db.conn.Query(`SELECT `+retroColumns+` FROM retro_items WHERE sprint = ? ORDER BY created_at, id`, sprint)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []models.RetroItem
+	for rows.Next() {
+		item, err := scanRetroItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *item)
+	}
+	return items, rows.Err()
+}
+
+// DeleteRetroItem removes a retro item. An action item's issue is kept.
+func (db *DB) DeleteRetroItem(id string) error {
+	return db.withWriteLock(func() error {
+		res, err := db.conn.Exec(`DELETE FROM retro_items WHERE id = ?`, id)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return fmt.Errorf("retro item not found: %s", id)
+		}
+		return nil
+	})
+}
+
+// retroScanner is satisfied by *sql.Row and *sql.Rows
+type retroScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRetroItem(row retroScanner) (*models.RetroItem, error) {
+	var item models.RetroItem
+	var kind, createdAt string
+
+	if err := row.Scan(&item.ID, &item.Sprint, &kind, &item.Text, &item.IssueID, &item.SessionID, &createdAt); err != nil {
+		return nil, err
+	}
+
+	item.Kind = models.RetroKind(kind)
+	item.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	return &item, nil
+}
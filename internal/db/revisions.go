@@ -0,0 +1,217 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/models"
+)
+
+const revisionColumns = `id, issue_id, field, entity_id, before_text, session_id, created_at`
+
+// recordRevisionLocked stores the text a field had before an edit. Callers
+// hold the write lock. Revisions are local history: they are not written
+// to the action log or synced.
+func (db *DB) recordRevisionLocked(issueID, field, entityID, before, sessionID string) error {
+	id, err := generateRevisionID()
+	if err != nil {
+		return err
+	}
+	_, err = db.conn.Exec(`INSERT INTO revisions (`+revisionColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		id, issueID, field, entityID, before, sessionID, clock.Now().UTC().Format(time.RFC3339Nano))
+	return err
+}
+
+// recordIssueRevisionsLocked stores a revision for each of the description
+// and acceptance criteria that an update changes
+func (db *DB) recordIssueRevisionsLocked(prev, next *models.Issue, sessionID string) error {
+	if prev.Description != next.Description {
+		if err := db.recordRevisionLocked(prev.ID, models.RevisionDescription, prev.ID, prev.Description, sessionID); err != nil {
+			return err
+		}
+	}
+	if prev.Acceptance != next.Acceptance {
+		if err := db.recordRevisionLocked(prev.ID, models.RevisionAcceptance, prev.ID, prev.Acceptance, sessionID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetRevision retrieves a revision by ID. After is not filled in.
+func (db *DB) GetRevision(id string) (*models.Revision, error) {
+	row := db.conn.QueryRow(`SELECT `+revisionColumns+` FROM revisions WHERE id = ?`, id)
+	rev, err := scanRevision(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("revision not found: %s", id)
+	}
+	return rev, err
+}
+
+// ListRevisions returns an issue's revisions, newest first. Each revision's
+// After is the text that replaced it: the next revision's Before, or the
+// current text for the latest one (empty once a comment is deleted).
+func (db *DB) ListRevisions(issueID string) ([]models.Revision, error) {
+	rows, err := db.conn.Query(`SELECT `+revisionColumns+` FROM revisions WHERE issue_id = ? ORDER BY created_at DESC, rowid DESC`, issueID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revs []models.Revision
+	for rows.Next() {
+		rev, err := scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revs = append(revs, *rev)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Walking newest first, the text after a revision is the Before of the
+	// newer revision of the same field seen just before it
+	newer := map[string]string{}
+	for i := range revs {
+		key := revs[i].Field + "\x00" + revs[i].EntityID
+		after, ok := newer[key]
+		if !ok {
+			after, err = db.currentRevisionText(&revs[i])
+			if err != nil {
+				return nil, err
+			}
+		}
+		revs[i].After = after
+		newer[key] = revs[i].Before
+	}
+	return revs, nil
+}
+
+// currentRevisionText returns the live text of a revision's field
+func (db *DB) currentRevisionText(rev *models.Revision) (string, error) {
+	switch rev.Field {
+	case models.RevisionComment:
+		c, err := db.GetCommentByID(rev.EntityID)
+		if err != nil || c == nil {
+			return "", err
+		}
+		return c.Text, nil
+	default:
+		issue, err := db.GetIssue(rev.IssueID)
+		if err != nil {
+			return "", nil // deleted issues keep their history
+		}
+		if rev.Field == models.RevisionAcceptance {
+			return issue.Acceptance, nil
+		}
+		return issue.Description, nil
+	}
+}
+
+// RevertRevision restores the text a revision recorded. The text being
+// replaced is itself kept as a new revision, so a revert can be reverted.
+func (db *DB) RevertRevision(id, sessionID string) (*models.Revision, error) {
+	rev, err := db.GetRevision(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.checkClosedWrite(rev.IssueID, "revert"); err != nil {
+		return nil, err
+	}
+
+	switch rev.Field {
+	case models.RevisionComment:
+		if err := db.UpdateCommentLogged(rev.EntityID, rev.Before, sessionID); err != nil {
+			return nil, err
+		}
+	case models.RevisionDescription, models.RevisionAcceptance:
+		issue, err := db.GetIssue(rev.IssueID)
+		if err != nil {
+			return nil, err
+		}
+		if rev.Field == models.RevisionAcceptance {
+			issue.Acceptance = rev.Before
+		} else {
+			issue.Description = rev.Before
+		}
+		if err := db.UpdateIssueLogged(issue, sessionID, models.ActionUpdate); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("cannot revert %s revision", rev.Field)
+	}
+	return rev, nil
+}
+
+// UpdateCommentLogged replaces a comment's text, keeping the old text as a
+// revision, and logs the action atomically
+func (db *DB) UpdateCommentLogged(commentID, text, sessionID string) error {
+	return db.withWriteLock(func() error {
+		var c models.Comment
+		err := db.conn.QueryRow(`
+			SELECT CAST(id AS TEXT), issue_id, session_id, text, created_at
+			FROM comments WHERE id = ?
+		`, commentID).Scan(&c.ID, &c.IssueID, &c.SessionID, &c.Text, &c.CreatedAt)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("comment not found: %s", commentID)
+		}
+		if err != nil {
+			return err
+		}
+		if err := db.checkClosedWrite(c.IssueID, "edit comments on"); err != nil {
+			return err
+		}
+		if text, err = db.sealCommentText(c.IssueID, text, c.Text); err != nil {
+			return err
+		}
+		if c.Text == text {
+			return nil
+		}
+
+		if _, err := db.conn.Exec(`UPDATE comments SET text = ? WHERE id = ?`, text, commentID); err != nil {
+			return err
+		}
+		if err := db.recordRevisionLocked(c.IssueID, models.RevisionComment, c.ID, c.Text, sessionID); err != nil {
+			return fmt.Errorf("record revision: %w", err)
+		}
+
+		actionID, err := generateActionID()
+		if err != nil {
+			return fmt.Errorf("generate action ID: %w", err)
+		}
+		previousData, _ := json.Marshal(map[string]interface{}{
+			"id": c.ID, "issue_id": c.IssueID, "session_id": c.SessionID,
+			"text": c.Text, "created_at": c.CreatedAt,
+		})
+		newData, _ := json.Marshal(map[string]interface{}{
+			"id": c.ID, "issue_id": c.IssueID, "session_id": c.SessionID,
+			"text": text, "created_at": c.CreatedAt,
+		})
+		actionTS := actionLogTimestampNow()
+		_, err = db.conn.Exec(`INSERT INTO action_log (id, session_id, action_type, entity_type, entity_id, previous_data, new_data, timestamp, undone) VALUES (?, ?, ?, ?, ?, ?, ?, ?, 0)`,
+			actionID, sessionID, "update", "comments", commentID, string(previousData), string(newData), actionTS)
+		if err != nil {
+			return fmt.Errorf("log action: %w", err)
+		}
+		return nil
+	})
+}
+
+// revisionScanner is satisfied by *sql.Row and *sql.Rows
+type revisionScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRevision(row revisionScanner) (*models.Revision, error) {
+	var rev models.Revision
+	var createdAt string
+	if err := row.Scan(&rev.ID, &rev.IssueID, &rev.Field, &rev.EntityID, &rev.Before, &rev.SessionID, &createdAt); err != nil {
+		return nil, err
+	}
+	rev.CreatedAt, _ = time.Parse(time.RFC3339Nano, createdAt)
+	return &rev, nil
+}
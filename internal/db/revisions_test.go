@@ -0,0 +1,73 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestRevisionsLifecycle(t *testing.T) {
+	database, err := Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+
+	issue := &models.Issue{Title: "Parser", Description: "v1"}
+	if err := database.CreateIssueLogged(issue, "ses_a"); err != nil {
+		t.Fatal(err)
+	}
+	for _, desc := range []string{"v2", "v3"} {
+		issue.Description = desc
+		if err := database.UpdateIssueLogged(issue, "ses_a", models.ActionUpdate); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A status change alone records nothing
+	issue.Status = models.StatusInProgress
+	if err := database.UpdateIssueLogged(issue, "ses_a", models.ActionStart); err != nil {
+		t.Fatal(err)
+	}
+
+	comment := &models.Comment{IssueID: issue.ID, SessionID: "ses_a", Text: "first"}
+	if err := database.AddComment(comment); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.UpdateCommentLogged(comment.ID, "second", "ses_b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.UpdateCommentLogged("cm-missing", "x", "ses_b"); err == nil {
+		t.Error("editing a missing comment succeeded")
+	}
+
+	revs, err := database.ListRevisions(issue.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revs) != 3 {
+		t.Fatalf("revisions = %+v", revs)
+	}
+	if revs[0].Field != models.RevisionComment || revs[0].Before != "first" || revs[0].After != "second" {
+		t.Errorf("comment revision = %+v", revs[0])
+	}
+	if revs[1].Before != "v2" || revs[1].After != "v3" || revs[2].Before != "v1" || revs[2].After != "v2" {
+		t.Errorf("description revisions = %+v", revs[1:])
+	}
+
+	// Reverting to v1 keeps v3 as a new revision
+	if _, err := database.RevertRevision(revs[2].ID, "ses_b"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := database.GetIssue(issue.ID); got.Description != "v1" {
+		t.Errorf("description after revert = %q", got.Description)
+	}
+	if _, err := database.RevertRevision(revs[0].ID, "ses_b"); err != nil {
+		t.Fatal(err)
+	}
+	if c, _ := database.GetCommentByID(comment.ID); c.Text != "first" {
+		t.Errorf("comment after revert = %q", c.Text)
+	}
+	if revs, _ := database.ListRevisions(issue.ID); len(revs) != 5 || revs[1].Before != "v3" || revs[1].After != "v1" {
+		t.Errorf("revisions after revert = %+v", revs)
+	}
+}
@@ -0,0 +1,150 @@
+package db
+
+import (
+	"sort"
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/models"
+)
+
+const reworkColumns = `id, issue_id, category, reason, approved, session_id, created_at`
+
+// RecordRework stores a reopen of a closed issue. ID and CreatedAt are
+// filled in. Like retro items, reworks are not written to the action log
+// or synced.
+func (db *DB) RecordRework(rw *models.Rework) error {
+	return db.withWriteLock(func() error {
+		id, err := generateReworkID()
+		if err != nil {
+			return err
+		}
+		rw.ID = id
+		rw.IssueID = NormalizeIssueID(rw.IssueID)
+		if rw.Category == "" {
+			rw.Category = models.ReworkOther
+		}
+		rw.CreatedAt = clock.Now().UTC()
+
+		_, err = db.conn.Exec(`INSERT INTO issue_reworks (`+reworkColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			rw.ID, rw.IssueID, string(rw.Category), rw.Reason, rw.Approved, rw.SessionID,
+			rw.CreatedAt.Format(time.RFC3339))
+		return err
+	})
+}
+
+// GetReworks returns an issue's rework cycles, oldest first
+func (db *DB) GetReworks(issueID string) ([]models.Rework, error) {
+	rows, err := db.conn.Query(`SELECT `+reworkColumns+` FROM issue_reworks WHERE issue_id = ? ORDER BY created_at, rowid`,
+		NormalizeIssueID(issueID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reworks []models.Rework
+	for rows.Next() {
+		var rw models.Rework
+		var category, createdAt string
+		if err := rows.Scan(&rw.ID, &rw.IssueID, &category, &rw.Reason, &rw.Approved, &rw.SessionID, &createdAt); err != nil {
+			return nil, err
+		}
+		rw.Category = models.ReworkCategory(category)
+		rw.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		reworks = append(reworks, rw)
+	}
+	return reworks, rows.Err()
+}
+
+// ListReworkedIssues returns live issues reopened at least minCount times,
+// most reworked first
+func (db *DB) ListReworkedIssues(minCount int) ([]models.ReworkedIssue, error) {
+	rows, err := db.conn.Query(`
+		SELECT r.issue_id, i.title, i.status, r.category, COUNT(*), MAX(r.created_at)
+		FROM issue_reworks r JOIN issues i ON i.id = r.issue_id AND i.deleted_at IS NULL
+		GROUP BY r.issue_id, r.category
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byID := make(map[string]*models.ReworkedIssue)
+	var order []string
+	for rows.Next() {
+		var id, title, status, category, last string
+		var count int
+		if err := rows.Scan(&id, &title, &status, &category, &count, &last); err != nil {
+			return nil, err
+		}
+		ri := byID[id]
+		if ri == nil {
+			ri = &models.ReworkedIssue{IssueID: id, Title: title, Status: models.Status(status), ByCategory: make(map[models.ReworkCategory]int)}
+			byID[id] = ri
+			order = append(order, id)
+		}
+		ri.Count += count
+		ri.ByCategory[models.ReworkCategory(category)] = count
+		if t, err := time.Parse(time.RFC3339, last); err == nil && t.After(ri.LastReopened) {
+			ri.LastReopened = t
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var issues []models.ReworkedIssue
+	for _, id := range order {
+		if byID[id].Count >= minCount {
+			issues = append(issues, *byID[id])
+		}
+	}
+	// Most reworked first, then most recently reopened
+	sort.Slice(issues, func(a, b int) bool {
+		if issues[a].Count != issues[b].Count {
+			return issues[a].Count > issues[b].Count
+		}
+		return issues[a].LastReopened.After(issues[b].LastReopened)
+	})
+	return issues, nil
+}
+
+// reworkStats fills the rework figures of the extended stats. The rate is
+// the share of issues ever closed (closed now, or reopened since) that were
+// reopened at least once.
+func (db *DB) reworkStats(stats *models.ExtendedStats) error {
+	stats.ByReworkCategory = make(map[models.ReworkCategory]int)
+	var everClosed int
+	err := db.conn.QueryRow(`
+		SELECT
+			(SELECT COUNT(*) FROM issue_reworks r JOIN issues i ON i.id = r.issue_id AND i.deleted_at IS NULL),
+			(SELECT COUNT(DISTINCT r.issue_id) FROM issue_reworks r JOIN issues i ON i.id = r.issue_id AND i.deleted_at IS NULL),
+			(SELECT COUNT(*) FROM issues WHERE deleted_at IS NULL
+				AND (status = ? OR id IN (SELECT issue_id FROM issue_reworks)))
+	`, models.StatusClosed).Scan(&stats.TotalReworks, &stats.ReworkedIssues, &everClosed)
+	if err != nil {
+		return err
+	}
+	if everClosed > 0 {
+		stats.ReworkRate = float64(stats.ReworkedIssues) / float64(everClosed)
+	}
+
+	rows, err := db.conn.Query(`
+		SELECT r.category, COUNT(*) FROM issue_reworks r
+		JOIN issues i ON i.id = r.issue_id AND i.deleted_at IS NULL
+		GROUP BY r.category
+	`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var category string
+		var count int
+		if err := rows.Scan(&category, &count); err != nil {
+			return err
+		}
+		stats.ByReworkCategory[models.ReworkCategory(category)] = count
+	}
+	return rows.Err()
+}
@@ -0,0 +1,65 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestReworks(t *testing.T) {
+	database, err := Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	defer database.Close()
+
+	flaky := &models.Issue{Title: "Flaky", Status: models.StatusOpen}
+	once := &models.Issue{Title: "Once", Status: models.StatusOpen}
+	done := &models.Issue{Title: "Done", Status: models.StatusClosed}
+	for _, issue := range []*models.Issue{flaky, once, done} {
+		if err := database.CreateIssue(issue); err != nil {
+			t.Fatalf("CreateIssue: %v", err)
+		}
+	}
+	for _, rw := range []models.Rework{
+		{IssueID: flaky.ID, Category: models.ReworkBug, Reason: "crashes on empty input", Approved: true},
+		{IssueID: flaky.ID, Category: models.ReworkIncomplete},
+		{IssueID: flaky.ID, Category: models.ReworkBug},
+		{IssueID: once.ID},
+	} {
+		if err := database.RecordRework(&rw); err != nil {
+			t.Fatalf("RecordRework: %v", err)
+		}
+	}
+
+	reworks, err := database.GetReworks(flaky.ID)
+	if err != nil || len(reworks) != 3 {
+		t.Fatalf("GetReworks = %d, %v; want 3", len(reworks), err)
+	}
+	if first := reworks[0]; first.Category != models.ReworkBug || !first.Approved || first.Reason == "" {
+		t.Errorf("first rework = %+v, want the approved bug with its reason", first)
+	}
+
+	issues, err := database.ListReworkedIssues(2)
+	if err != nil {
+		t.Fatalf("ListReworkedIssues: %v", err)
+	}
+	if len(issues) != 1 || issues[0].IssueID != flaky.ID || issues[0].Count != 3 || issues[0].ByCategory[models.ReworkBug] != 2 {
+		t.Errorf("reworked issues = %+v, want only %s with 3 reopens, 2 bugs", issues, flaky.ID)
+	}
+	if all, _ := database.ListReworkedIssues(1); len(all) != 2 || all[0].IssueID != flaky.ID {
+		t.Errorf("min 1 = %+v, want both, most reworked first", all)
+	}
+
+	// 2 of the 3 issues ever closed were reopened
+	stats, err := database.GetExtendedStats()
+	if err != nil {
+		t.Fatalf("GetExtendedStats: %v", err)
+	}
+	if stats.TotalReworks != 4 || stats.ReworkedIssues != 2 || stats.ByReworkCategory[models.ReworkOther] != 1 {
+		t.Errorf("stats = %d reworks, %d issues, %v", stats.TotalReworks, stats.ReworkedIssues, stats.ByReworkCategory)
+	}
+	if want := 2.0 / 3.0; stats.ReworkRate < want-0.001 || stats.ReworkRate > want+0.001 {
+		t.Errorf("rework rate = %v, want %v", stats.ReworkRate, want)
+	}
+}
@@ -1,7 +1,7 @@
 package db
 
 // SchemaVersion is the current database schema version
-const SchemaVersion = 29
+const SchemaVersion = 55
 
 const schema = `
 -- Issues table
@@ -478,6 +478,526 @@ ALTER TABLE issues ADD COLUMN due_date TEXT;
 ALTER TABLE issues ADD COLUMN defer_count INTEGER DEFAULT 0;
 CREATE INDEX IF NOT EXISTS idx_issues_defer_until ON issues(defer_until);
 CREATE INDEX IF NOT EXISTS idx_issues_due_date ON issues(due_date);
+`,
+	},
+	{
+		Version:     30,
+		Description: "Add plans table for previewed bulk changes",
+		SQL: `
+CREATE TABLE IF NOT EXISTS plans (
+    id TEXT PRIMARY KEY,
+    kind TEXT NOT NULL,
+    summary TEXT NOT NULL DEFAULT '',
+    changes TEXT NOT NULL DEFAULT '[]',
+    session_id TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'pending',
+    created_at TEXT NOT NULL,
+    expires_at TEXT NOT NULL,
+    applied_at TEXT,
+    applied_by TEXT DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_plans_status ON plans(status);
+`,
+	},
+	{
+		Version:     31,
+		Description: "Index action_log entity_type for per-collection change tokens",
+		SQL: `
+CREATE INDEX IF NOT EXISTS idx_action_log_entity ON action_log(entity_type);
+`,
+	},
+	{
+		Version:     32,
+		Description: "Add reminders table for per-issue nudges",
+		SQL: `
+CREATE TABLE IF NOT EXISTS reminders (
+    id TEXT PRIMARY KEY,
+    issue_id TEXT NOT NULL,
+    session_id TEXT NOT NULL DEFAULT '',
+    message TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'pending',
+    remind_at TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    fired_at TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_reminders_due ON reminders(status, remind_at);
+`,
+	},
+	{
+		Version:     33,
+		Description: "Add share_links table for read-only issue share tokens",
+		SQL: `
+CREATE TABLE IF NOT EXISTS share_links (
+    id TEXT PRIMARY KEY,
+    issue_id TEXT NOT NULL,
+    include_comments INTEGER NOT NULL DEFAULT 0,
+    session_id TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL,
+    expires_at TEXT,
+    revoked_at TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_share_links_issue ON share_links(issue_id);
+`,
+	},
+	{
+		Version:     34,
+		Description: "Add repository identity to issues, sessions and git snapshots",
+		// Handled by custom Go code in migrations.go (migrateRepoIdentity)
+		SQL: "",
+	},
+	{
+		Version:     35,
+		Description: "Add structured blocked reason and external reference to issues",
+		// Handled by custom Go code in migrations.go (migrateBlockedReason)
+		SQL: "",
+	},
+	{
+		Version:     36,
+		Description: "Add decisions table and decision_issues links",
+		SQL: `
+CREATE TABLE IF NOT EXISTS decisions (
+    id TEXT PRIMARY KEY,
+    title TEXT NOT NULL,
+    context TEXT NOT NULL DEFAULT '',
+    options TEXT NOT NULL DEFAULT '[]',
+    decision TEXT NOT NULL DEFAULT '',
+    session_id TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS decision_issues (
+    decision_id TEXT NOT NULL,
+    issue_id TEXT NOT NULL,
+    PRIMARY KEY (decision_id, issue_id)
+);
+CREATE INDEX IF NOT EXISTS idx_decision_issues_issue ON decision_issues(issue_id);
+`,
+	},
+	{
+		Version:     37,
+		Description: "Add retro_items table for sprint retrospectives",
+		SQL: `
+CREATE TABLE IF NOT EXISTS retro_items (
+    id TEXT PRIMARY KEY,
+    sprint TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    text TEXT NOT NULL,
+    issue_id TEXT NOT NULL DEFAULT '',
+    session_id TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_retro_items_sprint ON retro_items(sprint);
+`,
+	},
+	{
+		Version:     38,
+		Description: "Add revisions table for description, acceptance and comment edits",
+		SQL: `
+CREATE TABLE IF NOT EXISTS revisions (
+    id TEXT PRIMARY KEY,
+    issue_id TEXT NOT NULL,
+    field TEXT NOT NULL,
+    entity_id TEXT NOT NULL,
+    before_text TEXT NOT NULL DEFAULT '',
+    session_id TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_revisions_issue ON revisions(issue_id);
+`,
+	},
+	{
+		Version:     39,
+		Description: "Add issue_cards read model maintained by triggers",
+		// Backfilled by custom Go code in migrations.go (rebuildIssueCards)
+		SQL: issueCardsSchema,
+	},
+	{
+		Version:     40,
+		Description: "Add issue_reworks table recording reopens of closed issues",
+		SQL: `
+CREATE TABLE IF NOT EXISTS issue_reworks (
+    id TEXT PRIMARY KEY,
+    issue_id TEXT NOT NULL,
+    category TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    approved INTEGER NOT NULL DEFAULT 0,
+    session_id TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_issue_reworks_issue ON issue_reworks(issue_id);
+`,
+	},
+	{
+		Version:     41,
+		Description: "Add api_tokens table for session-scoped td serve tokens",
+		SQL: `
+CREATE TABLE IF NOT EXISTS api_tokens (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL DEFAULT '',
+    token_hash TEXT NOT NULL UNIQUE,
+    session_id TEXT NOT NULL,
+    scopes TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    expires_at TEXT,
+    revoked_at TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_api_tokens_session ON api_tokens(session_id);
+`,
+	},
+	{
+		Version:     42,
+		Description: "Add issue_overrides table auditing bypass-prevention overrides",
+		SQL: `
+CREATE TABLE IF NOT EXISTS issue_overrides (
+    id TEXT PRIMARY KEY,
+    issue_id TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    justification TEXT NOT NULL DEFAULT '',
+    session_id TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_issue_overrides_issue ON issue_overrides(issue_id);
+`,
+	},
+	{
+		Version:     43,
+		Description: "Add review_acks table storing acknowledged review checklist items",
+		SQL: `
+CREATE TABLE IF NOT EXISTS review_acks (
+    id TEXT PRIMARY KEY,
+    issue_id TEXT NOT NULL,
+    session_id TEXT NOT NULL DEFAULT '',
+    items TEXT NOT NULL DEFAULT '[]',
+    created_at TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_review_acks_issue ON review_acks(issue_id);
+`,
+	},
+	{
+		Version:     44,
+		Description: "Add inbox flag to issues awaiting triage",
+		// Handled by custom Go code in migrations.go (migrateInbox)
+		SQL: "",
+	},
+	{
+		Version:     45,
+		Description: "Add notify_routes table mapping notification events to transports",
+		SQL: `
+CREATE TABLE IF NOT EXISTS notify_routes (
+    id TEXT PRIMARY KEY,
+    event TEXT NOT NULL,
+    transport TEXT NOT NULL,
+    target TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL
+);
+`,
+	},
+	{
+		Version:     46,
+		Description: "Add views table for saved queries with sort, fields and grouping",
+		SQL: `
+CREATE TABLE IF NOT EXISTS views (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE COLLATE NOCASE,
+    query TEXT NOT NULL DEFAULT '',
+    sort TEXT NOT NULL DEFAULT '',
+    fields TEXT NOT NULL DEFAULT '[]',
+    group_by TEXT NOT NULL DEFAULT '',
+    layout TEXT NOT NULL DEFAULT 'list',
+    session_id TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+`,
+	},
+	{
+		Version:     47,
+		Description: "Add impersonations table auditing admin repairs made on behalf of other sessions",
+		SQL: `
+CREATE TABLE IF NOT EXISTS impersonations (
+    id TEXT PRIMARY KEY,
+    issue_id TEXT NOT NULL,
+    action TEXT NOT NULL,
+    actor_session TEXT NOT NULL,
+    as_session TEXT NOT NULL DEFAULT '',
+    before_value TEXT NOT NULL DEFAULT '',
+    after_value TEXT NOT NULL DEFAULT '',
+    reason TEXT NOT NULL,
+    created_at TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_impersonations_issue ON impersonations(issue_id);
+`,
+	},
+	{
+		Version:     48,
+		Description: "Add relation_suggestions table recording accepted and dismissed relation suggestions",
+		SQL: `
+CREATE TABLE IF NOT EXISTS relation_suggestions (
+    issue_id TEXT NOT NULL,
+    other_id TEXT NOT NULL,
+    status TEXT NOT NULL,
+    relation_type TEXT NOT NULL DEFAULT '',
+    session_id TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL,
+    PRIMARY KEY (issue_id, other_id)
+);
+`,
+	},
+	{
+		Version:     49,
+		Description: "Track sync push failures and server rejections for the outbox",
+		// Handled by custom Go code in migrations.go (migrateOutbox)
+		SQL: "",
+	},
+	{
+		Version:     50,
+		Description: "Add log_archive table holding logs replaced by a compaction digest",
+		SQL: `
+CREATE TABLE IF NOT EXISTS log_archive (
+    id TEXT PRIMARY KEY,
+    issue_id TEXT NOT NULL,
+    session_id TEXT NOT NULL,
+    work_session_id TEXT DEFAULT '',
+    message TEXT NOT NULL,
+    type TEXT NOT NULL DEFAULT 'progress',
+    timestamp DATETIME NOT NULL,
+    digest_id TEXT NOT NULL,
+    archived_at TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_log_archive_issue ON log_archive(issue_id);
+CREATE INDEX IF NOT EXISTS idx_log_archive_digest ON log_archive(digest_id);
+`,
+	},
+	{
+		Version:     51,
+		Description: "Add the delivery outbox for webhooks and notifications",
+		SQL:         deliveriesSchema,
+	},
+	{
+		Version:     52,
+		Description: "Add confidential issues and their access grants",
+		// Handled by custom Go code in migrations.go (migrateConfidential)
+		SQL: "",
+	},
+	{
+		Version:     53,
+		Description: "Add a project-wide rank to issues",
+		// Handled by custom Go code in migrations.go (migrateRank)
+		SQL: "",
+	},
+	{
+		Version:     54,
+		Description: "Add estimation poker rounds and their votes",
+		SQL: `
+CREATE TABLE IF NOT EXISTS poker_rounds (
+    id TEXT PRIMARY KEY,
+    title TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'open',
+    session_id TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL,
+    revealed_at TEXT,
+    closed_at TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_poker_rounds_status ON poker_rounds(status);
+CREATE TABLE IF NOT EXISTS poker_items (
+    round_id TEXT NOT NULL,
+    issue_id TEXT NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    points INTEGER NOT NULL DEFAULT 0,
+    dissent TEXT NOT NULL DEFAULT '',
+    recorded_at TEXT,
+    PRIMARY KEY (round_id, issue_id)
+);
+CREATE INDEX IF NOT EXISTS idx_poker_items_issue ON poker_items(issue_id);
+CREATE TABLE IF NOT EXISTS poker_votes (
+    round_id TEXT NOT NULL,
+    issue_id TEXT NOT NULL,
+    session_id TEXT NOT NULL,
+    points INTEGER NOT NULL,
+    created_at TEXT NOT NULL,
+    PRIMARY KEY (round_id, issue_id, session_id)
+);
+`,
+	},
+	{
+		Version:     55,
+		Description: "Add dependencies on external systems, polled for their state",
+		SQL: `
+CREATE TABLE IF NOT EXISTS external_dependencies (
+    id TEXT PRIMARY KEY,
+    issue_id TEXT NOT NULL,
+    url TEXT NOT NULL,
+    provider TEXT NOT NULL,
+    state TEXT NOT NULL DEFAULT 'unsatisfied',
+    detail TEXT NOT NULL DEFAULT '',
+    last_error TEXT NOT NULL DEFAULT '',
+    checked_at TEXT,
+    satisfied_at TEXT,
+    session_id TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL,
+    UNIQUE(issue_id, url)
+);
+CREATE INDEX IF NOT EXISTS idx_external_dependencies_state ON external_dependencies(state);
 `,
 	},
 }
+
+// deliveriesSchema creates the delivery outbox. A trigger records every
+// action_log row in delivery_events as part of the statement that inserts
+// it, so a change can't be committed without its event, whichever code
+// path wrote it. Delivery fan-out turns events into deliveries rows, one
+// per webhook, channel or notification route, and deletes them. Events
+// older than a day are dropped, so nothing piles up while no webhook is
+// configured and a webhook added later isn't sent old changes.
+const deliveriesSchema = `
+CREATE TABLE IF NOT EXISTS delivery_events (
+    action_rowid INTEGER PRIMARY KEY,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_delivery_events_created ON delivery_events(created_at);
+
+CREATE TRIGGER IF NOT EXISTS action_log_delivery_event AFTER INSERT ON action_log
+BEGIN
+    INSERT OR IGNORE INTO delivery_events (action_rowid) VALUES (NEW.rowid);
+    DELETE FROM delivery_events WHERE created_at < datetime('now', '-1 day');
+END;
+
+CREATE TABLE IF NOT EXISTS deliveries (
+    id TEXT PRIMARY KEY,
+    kind TEXT NOT NULL,
+    ref TEXT NOT NULL DEFAULT '',
+    target TEXT NOT NULL DEFAULT '',
+    payload TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TEXT,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    delivered_at TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_deliveries_due ON deliveries(status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_deliveries_created ON deliveries(created_at);
+`
+
+// issueCardsSchema creates the issue_cards read model and the triggers that
+// keep it current. A card holds what list views show for an issue, with
+// the counts that would otherwise be joined per request. Every write path,
+// including sync apply, goes through these triggers, so no Go code
+// maintains cards directly.
+//
+// blocked_by_count is the number of depends_on targets that are not
+// closed. Comment counts are recounted rather than incremented because
+// sync applies rows with INSERT OR REPLACE, which skips delete triggers.
+const issueCardsSchema = `
+CREATE TABLE IF NOT EXISTS issue_cards (
+    id TEXT PRIMARY KEY,
+    title TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'open',
+    priority TEXT NOT NULL DEFAULT 'P2',
+    points INTEGER NOT NULL DEFAULT 0,
+    labels TEXT NOT NULL DEFAULT '',
+    blocked_by_count INTEGER NOT NULL DEFAULT 0,
+    comment_count INTEGER NOT NULL DEFAULT 0,
+    last_activity DATETIME
+);
+CREATE INDEX IF NOT EXISTS idx_issue_cards_status ON issue_cards(status);
+CREATE INDEX IF NOT EXISTS idx_issue_dependencies_depends_on ON issue_dependencies(depends_on_id);
+
+CREATE TRIGGER IF NOT EXISTS issue_cards_issue_insert AFTER INSERT ON issues
+WHEN NEW.deleted_at IS NULL
+BEGIN
+    INSERT OR REPLACE INTO issue_cards (id, title, status, priority, points, labels, blocked_by_count, comment_count, last_activity)
+    VALUES (NEW.id, NEW.title, NEW.status, NEW.priority, COALESCE(NEW.points, 0), COALESCE(NEW.labels, ''),
+        (SELECT COUNT(*) FROM issue_dependencies d JOIN issues b ON b.id = d.depends_on_id
+         WHERE d.issue_id = NEW.id AND d.relation_type = 'depends_on' AND b.status != 'closed'),
+        (SELECT COUNT(*) FROM comments WHERE issue_id = NEW.id),
+        NEW.updated_at);
+END;
+
+CREATE TRIGGER IF NOT EXISTS issue_cards_issue_update AFTER UPDATE ON issues
+BEGIN
+    DELETE FROM issue_cards WHERE id = OLD.id AND (NEW.deleted_at IS NOT NULL OR OLD.id != NEW.id);
+    INSERT INTO issue_cards (id, title, status, priority, points, labels, blocked_by_count, comment_count, last_activity)
+    SELECT NEW.id, NEW.title, NEW.status, NEW.priority, COALESCE(NEW.points, 0), COALESCE(NEW.labels, ''),
+        (SELECT COUNT(*) FROM issue_dependencies d JOIN issues b ON b.id = d.depends_on_id
+         WHERE d.issue_id = NEW.id AND d.relation_type = 'depends_on' AND b.status != 'closed'),
+        (SELECT COUNT(*) FROM comments WHERE issue_id = NEW.id),
+        NEW.updated_at
+    WHERE NEW.deleted_at IS NULL
+    ON CONFLICT(id) DO UPDATE SET
+        title = excluded.title,
+        status = excluded.status,
+        priority = excluded.priority,
+        points = excluded.points,
+        labels = excluded.labels,
+        last_activity = excluded.last_activity;
+END;
+
+CREATE TRIGGER IF NOT EXISTS issue_cards_issue_delete AFTER DELETE ON issues
+BEGIN
+    DELETE FROM issue_cards WHERE id = OLD.id;
+END;
+
+-- A blocker appearing, disappearing or changing status recounts its dependents
+CREATE TRIGGER IF NOT EXISTS issue_cards_blocker_insert AFTER INSERT ON issues
+BEGIN
+    UPDATE issue_cards SET blocked_by_count = (
+        SELECT COUNT(*) FROM issue_dependencies d JOIN issues b ON b.id = d.depends_on_id
+        WHERE d.issue_id = issue_cards.id AND d.relation_type = 'depends_on' AND b.status != 'closed')
+    WHERE id IN (SELECT issue_id FROM issue_dependencies WHERE depends_on_id = NEW.id);
+END;
+
+CREATE TRIGGER IF NOT EXISTS issue_cards_blocker_status AFTER UPDATE OF status ON issues
+WHEN OLD.status IS NOT NEW.status
+BEGIN
+    UPDATE issue_cards SET blocked_by_count = (
+        SELECT COUNT(*) FROM issue_dependencies d JOIN issues b ON b.id = d.depends_on_id
+        WHERE d.issue_id = issue_cards.id AND d.relation_type = 'depends_on' AND b.status != 'closed')
+    WHERE id IN (SELECT issue_id FROM issue_dependencies WHERE depends_on_id = NEW.id);
+END;
+
+CREATE TRIGGER IF NOT EXISTS issue_cards_blocker_delete AFTER DELETE ON issues
+BEGIN
+    UPDATE issue_cards SET blocked_by_count = (
+        SELECT COUNT(*) FROM issue_dependencies d JOIN issues b ON b.id = d.depends_on_id
+        WHERE d.issue_id = issue_cards.id AND d.relation_type = 'depends_on' AND b.status != 'closed')
+    WHERE id IN (SELECT issue_id FROM issue_dependencies WHERE depends_on_id = OLD.id);
+END;
+
+CREATE TRIGGER IF NOT EXISTS issue_cards_dependency_insert AFTER INSERT ON issue_dependencies
+BEGIN
+    UPDATE issue_cards SET blocked_by_count = (
+        SELECT COUNT(*) FROM issue_dependencies d JOIN issues b ON b.id = d.depends_on_id
+        WHERE d.issue_id = NEW.issue_id AND d.relation_type = 'depends_on' AND b.status != 'closed')
+    WHERE id = NEW.issue_id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS issue_cards_dependency_delete AFTER DELETE ON issue_dependencies
+BEGIN
+    UPDATE issue_cards SET blocked_by_count = (
+        SELECT COUNT(*) FROM issue_dependencies d JOIN issues b ON b.id = d.depends_on_id
+        WHERE d.issue_id = OLD.issue_id AND d.relation_type = 'depends_on' AND b.status != 'closed')
+    WHERE id = OLD.issue_id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS issue_cards_comment_insert AFTER INSERT ON comments
+BEGIN
+    UPDATE issue_cards SET
+        comment_count = (SELECT COUNT(*) FROM comments WHERE issue_id = NEW.issue_id),
+        last_activity = NEW.created_at
+    WHERE id = NEW.issue_id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS issue_cards_comment_delete AFTER DELETE ON comments
+BEGIN
+    UPDATE issue_cards SET comment_count = (SELECT COUNT(*) FROM comments WHERE issue_id = OLD.issue_id)
+    WHERE id = OLD.issue_id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS issue_cards_log_insert AFTER INSERT ON logs
+WHEN NEW.issue_id != ''
+BEGIN
+    UPDATE issue_cards SET last_activity = NEW.timestamp WHERE id = NEW.issue_id;
+END;
+`
@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"time"
+
+	"github.com/marcus/td/internal/clock"
 )
 
 const securityEventsFile = ".todos/security_events.jsonl"
@@ -32,7 +34,7 @@ func LogSecurityEvent(baseDir string, event SecurityEvent) error {
 	}
 
 	if event.Timestamp.IsZero() {
-		event.Timestamp = time.Now().UTC()
+		event.Timestamp = clock.Now().UTC()
 	}
 
 	data, err := json.Marshal(event)
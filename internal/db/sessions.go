@@ -20,19 +20,20 @@ type SessionRow struct {
 	PreviousSessionID string
 	StartedAt         time.Time
 	LastActivity      time.Time
+	Repo              string // repository the session runs in; "" for sessions predating multi-repo
 }
 
 const sessionSelectCols = `id, name, branch, agent_type, agent_pid, context_id,
-	previous_session_id, started_at, last_activity`
+	previous_session_id, started_at, last_activity, repo`
 
 // UpsertSession inserts or replaces a session in the database
 func (db *DB) UpsertSession(sess *SessionRow) error {
 	return db.withWriteLock(func() error {
 		_, err := db.conn.Exec(`INSERT OR REPLACE INTO sessions
-			(id, name, branch, agent_type, agent_pid, context_id, previous_session_id, started_at, last_activity)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			(id, name, branch, agent_type, agent_pid, context_id, previous_session_id, started_at, last_activity, repo)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 			sess.ID, sess.Name, sess.Branch, sess.AgentType, sess.AgentPID,
-			sess.ContextID, sess.PreviousSessionID, sess.StartedAt, sess.LastActivity)
+			sess.ContextID, sess.PreviousSessionID, sess.StartedAt, sess.LastActivity, sess.Repo)
 		return err
 	})
 }
@@ -47,6 +48,26 @@ func (db *DB) GetSessionByBranchAgent(branch, agentType string, agentPID int) (*
 	return scanSessionRow(row)
 }
 
+// GetSessionByRepoBranchAgent is GetSessionByBranchAgent scoped to a
+// repository, so the same branch name in two repositories sharing a
+// project gets separate sessions. Sessions recorded before repositories
+// were tracked (repo "") still match; an exact repo match wins.
+func (db *DB) GetSessionByRepoBranchAgent(repo, branch, agentType string, agentPID int) (*SessionRow, error) {
+	row := db.conn.QueryRow(`SELECT `+sessionSelectCols+`
+		FROM sessions WHERE branch = ? AND agent_type = ? AND agent_pid = ? AND repo IN (?, '')
+		ORDER BY repo = ? DESC, COALESCE(last_activity, started_at) DESC LIMIT 1`,
+		branch, agentType, agentPID, repo, repo)
+	return scanSessionRow(row)
+}
+
+// UpdateSessionRepo records the repository of a session
+func (db *DB) UpdateSessionRepo(id, repo string) error {
+	return db.withWriteLock(func() error {
+		_, err := db.conn.Exec(`UPDATE sessions SET repo = ? WHERE id = ?`, repo, id)
+		return err
+	})
+}
+
 // GetSessionByID looks up a session by ID. Returns nil, nil if not found.
 func (db *DB) GetSessionByID(id string) (*SessionRow, error) {
 	row := db.conn.QueryRow(`SELECT `+sessionSelectCols+`
@@ -62,6 +83,17 @@ func (db *DB) UpdateSessionActivity(id string, t time.Time) error {
 	})
 }
 
+// RebindSession moves a session to another repo, branch and agent
+// fingerprint and marks it active at t, so lookups from that context
+// resolve to it
+func (db *DB) RebindSession(id, repo, branch, agentType string, agentPID int, t time.Time) error {
+	return db.withWriteLock(func() error {
+		_, err := db.conn.Exec(`UPDATE sessions SET repo = ?, branch = ?, agent_type = ?, agent_pid = ?, last_activity = ?
+			WHERE id = ?`, repo, branch, agentType, agentPID, t, id)
+		return err
+	})
+}
+
 // UpdateSessionName updates the name of a session
 func (db *DB) UpdateSessionName(id, name string) error {
 	return db.withWriteLock(func() error {
@@ -109,7 +141,7 @@ func scanSessionRow(row *sql.Row) (*SessionRow, error) {
 	var s SessionRow
 	var lastActivity sql.NullTime
 	err := row.Scan(&s.ID, &s.Name, &s.Branch, &s.AgentType, &s.AgentPID,
-		&s.ContextID, &s.PreviousSessionID, &s.StartedAt, &lastActivity)
+		&s.ContextID, &s.PreviousSessionID, &s.StartedAt, &lastActivity, &s.Repo)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -257,7 +289,7 @@ func scanSessionRows(rows *sql.Rows) (*SessionRow, error) {
 	var s SessionRow
 	var lastActivity sql.NullTime
 	err := rows.Scan(&s.ID, &s.Name, &s.Branch, &s.AgentType, &s.AgentPID,
-		&s.ContextID, &s.PreviousSessionID, &s.StartedAt, &lastActivity)
+		&s.ContextID, &s.PreviousSessionID, &s.StartedAt, &lastActivity, &s.Repo)
 	if err != nil {
 		return nil, err
 	}
@@ -119,6 +119,70 @@ func TestGetSessionByBranchAgent(t *testing.T) {
 	}
 }
 
+func TestGetSessionByRepoBranchAgent(t *testing.T) {
+	db := setupSessionTestDB(t)
+
+	now := time.Now().Truncate(time.Second)
+	for _, sess := range []*SessionRow{
+		{ID: "ses_legacy", Branch: "main", AgentType: "claude-code", AgentPID: 100, StartedAt: now, LastActivity: now},
+		{ID: "ses_api", Branch: "main", AgentType: "claude-code", AgentPID: 100, Repo: "github.com/acme/api",
+			StartedAt: now.Add(-time.Hour), LastActivity: now.Add(-time.Hour)},
+	} {
+		if err := db.UpsertSession(sess); err != nil {
+			t.Fatalf("upsert: %v", err)
+		}
+	}
+
+	// An exact repo match wins over a newer session without a repo
+	got, err := db.GetSessionByRepoBranchAgent("github.com/acme/api", "main", "claude-code", 100)
+	if err != nil || got == nil || got.ID != "ses_api" || got.Repo != "github.com/acme/api" {
+		t.Fatalf("api lookup = %+v, %v", got, err)
+	}
+
+	// Another repo falls back to the session without a repo
+	got, err = db.GetSessionByRepoBranchAgent("github.com/acme/web", "main", "claude-code", 100)
+	if err != nil || got == nil || got.ID != "ses_legacy" {
+		t.Fatalf("web lookup = %+v, %v", got, err)
+	}
+
+	// Once claimed by a repo it no longer matches others
+	if err := db.UpdateSessionRepo("ses_legacy", "github.com/acme/web"); err != nil {
+		t.Fatalf("UpdateSessionRepo: %v", err)
+	}
+	got, err = db.GetSessionByRepoBranchAgent("github.com/acme/docs", "main", "claude-code", 100)
+	if err != nil || got != nil {
+		t.Errorf("docs lookup = %+v, %v; want nil", got, err)
+	}
+}
+
+func TestRepos(t *testing.T) {
+	db := setupSessionTestDB(t)
+
+	if err := db.RecordRepo("github.com/acme/api", "/src/api"); err != nil {
+		t.Fatalf("RecordRepo: %v", err)
+	}
+	if err := db.RecordRepo("web", "/src/web"); err != nil {
+		t.Fatalf("RecordRepo: %v", err)
+	}
+	if err := db.RecordRepo("github.com/acme/api", "/work/api"); err != nil {
+		t.Fatalf("RecordRepo: %v", err)
+	}
+	if err := db.RecordRepo("", "/nowhere"); err != nil {
+		t.Fatalf("RecordRepo without a name should be a no-op: %v", err)
+	}
+
+	if path, _ := db.RepoPath("github.com/acme/api"); path != "/work/api" {
+		t.Errorf("RepoPath = %q, want the latest checkout", path)
+	}
+	if path, err := db.RepoPath("unknown"); err != nil || path != "" {
+		t.Errorf("RepoPath(unknown) = %q, %v", path, err)
+	}
+	repos, err := db.ListRepos()
+	if err != nil || len(repos) != 2 {
+		t.Fatalf("ListRepos = %+v, %v", repos, err)
+	}
+}
+
 func TestGetSessionByIDNotFound(t *testing.T) {
 	db := setupSessionTestDB(t)
 	got, err := db.GetSessionByID("nonexistent")
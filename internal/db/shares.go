@@ -0,0 +1,130 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/models"
+)
+
+// ErrShareLinkRevoked is returned when revoking a link that was already revoked
+var ErrShareLinkRevoked = errors.New("share link already revoked")
+
+const shareLinkColumns = `id, issue_id, include_comments, session_id, created_at, expires_at, revoked_at`
+
+// CreateShareLink stores a new share link. ID and CreatedAt are filled in.
+// Share links are local to this database and are not synced.
+func (db *DB) CreateShareLink(l *models.ShareLink) error {
+	return db.withWriteLock(func() error {
+		id, err := generateShareID()
+		if err != nil {
+			return err
+		}
+		l.ID = id
+		l.CreatedAt = clock.Now().UTC().Truncate(time.Second)
+
+		var expiresAt interface{}
+		if l.ExpiresAt != nil {
+			expiresAt = l.ExpiresAt.UTC().Format(time.RFC3339)
+		}
+		_, err = db.conn.Exec(`INSERT INTO share_links (id, issue_id, include_comments, session_id, created_at, expires_at)
+			VALUES (?, ?, ?, ?, ?, ?)`,
+			l.ID, l.IssueID, l.IncludeComments, l.SessionID, l.CreatedAt.Format(time.RFC3339), expiresAt)
+		return err
+	})
+}
+
+// GetShareLink retrieves a share link by ID
+func (db *DB) GetShareLink(id string) (*models.ShareLink, error) {
+	row := db.conn.QueryRow(`SELECT `+shareLinkColumns+` FROM share_links WHERE id = ?`, id)
+	l, err := scanShareLink(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("share link not found: %s", id)
+	}
+	return l, err
+}
+
+// ListShareLinks returns share links newest first, optionally for one issue.
+// Revoked and expired links are only included when all is set.
+func (db *DB) ListShareLinks(issueID string, all bool) ([]models.ShareLink, error) {
+	query := `SELECT ` + shareLinkColumns + ` FROM share_links`
+	var args []interface{}
+	if issueID != "" {
+		query += ` WHERE issue_id = ?`
+		args = append(args, issueID)
+	}
+	query += ` ORDER BY created_at DESC, id`
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	now := clock.Now()
+	var links []models.ShareLink
+	for rows.Next() {
+		l, err := scanShareLink(rows)
+		if err != nil {
+			return nil, err
+		}
+		if all || l.Active(now) {
+			links = append(links, *l)
+		}
+	}
+	return links, rows.Err()
+}
+
+// RevokeShareLink permanently disables a share link
+func (db *DB) RevokeShareLink(id string) error {
+	return db.withWriteLock(func() error {
+		res, err := db.conn.Exec(`UPDATE share_links SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL`,
+			clock.Now().UTC().Format(time.RFC3339), id)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			if _, err := db.GetShareLink(id); err != nil {
+				return err
+			}
+			return ErrShareLinkRevoked
+		}
+		return nil
+	})
+}
+
+// shareLinkScanner is satisfied by *sql.Row and *sql.Rows
+type shareLinkScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanShareLink(row shareLinkScanner) (*models.ShareLink, error) {
+	var l models.ShareLink
+	var createdAt string
+	var expiresAt, revokedAt sql.NullString
+
+	if err := row.Scan(&l.ID, &l.IssueID, &l.IncludeComments, &l.SessionID,
+		&createdAt, &expiresAt, &revokedAt); err != nil {
+		return nil, err
+	}
+
+	l.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	l.ExpiresAt = parseNullTime(expiresAt)
+	l.RevokedAt = parseNullTime(revokedAt)
+	return &l, nil
+}
+
+// parseNullTime parses an optional RFC 3339 column
+func parseNullTime(s sql.NullString) *time.Time {
+	if !s.Valid || s.String == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, s.String)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"modernc.org/sqlite"
+)
+
+// backuper is the online backup support of the SQLite driver's connections
+type backuper interface {
+	NewBackup(dstURI string) (*sqlite.Backup, error)
+}
+
+// Snapshot writes a consistent copy of the database to path with SQLite's
+// online backup API, for querying with other tools without touching the
+// live file. The copy is built beside path and renamed into place, taken out
+// of WAL mode so it is one self-contained file, and made read-only. path
+// must not exist yet.
+func (db *DB) Snapshot(path string) error {
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s already exists", path)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("create snapshot: %w", err)
+	}
+	tmpPath := tmp.Name()
+	tmp.Close()
+	defer os.Remove(tmpPath) // no-op once renamed
+
+	// Step(-1) copies every page in one read transaction, so the copy is
+	// consistent even while other processes write
+	conn, err := db.conn.Conn(context.Background())
+	if err != nil {
+		return err
+	}
+	err = conn.Raw(func(driverConn interface{}) error {
+		b, ok := driverConn.(backuper)
+		if !ok {
+			return fmt.Errorf("sqlite driver does not support backups")
+		}
+		bck, err := b.NewBackup(tmpPath)
+		if err != nil {
+			return err
+		}
+		for more := true; more; {
+			if more, err = bck.Step(-1); err != nil {
+				bck.Finish()
+				return err
+			}
+		}
+		return bck.Finish()
+	})
+	conn.Close()
+	if err != nil {
+		return fmt.Errorf("backup database: %w", err)
+	}
+
+	// A WAL-mode copy needs -wal and -shm files beside it, even to read
+	snap, err := sql.Open("sqlite", tmpPath)
+	if err != nil {
+		return err
+	}
+	_, err = snap.Exec("PRAGMA journal_mode=DELETE")
+	snap.Close()
+	if err != nil {
+		return fmt.Errorf("finalize snapshot: %w", err)
+	}
+
+	if err := os.Chmod(tmpPath, 0444); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
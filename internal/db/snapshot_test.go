@@ -0,0 +1,56 @@
+package db
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	database, err := Initialize(dir)
+	if err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	defer database.Close()
+
+	issue := &models.Issue{Title: "Snapshot me"}
+	if err := database.CreateIssue(issue); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "snapshot.db")
+	if err := database.Snapshot(path); err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+	if err := database.Snapshot(path); err == nil {
+		t.Error("second snapshot to the same path succeeded, want an error")
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat snapshot: %v", err)
+	}
+	if info.Mode().Perm()&0222 != 0 {
+		t.Errorf("snapshot mode = %v, want read-only", info.Mode())
+	}
+	if matches, _ := filepath.Glob(path + "*-wal"); len(matches) > 0 {
+		t.Errorf("snapshot left WAL files %v", matches)
+	}
+
+	snap, err := sql.Open("sqlite", "file:"+path+"?mode=ro")
+	if err != nil {
+		t.Fatalf("open snapshot: %v", err)
+	}
+	defer snap.Close()
+	var title, mode string
+	if err := snap.QueryRow(`SELECT title FROM issues WHERE id = ?`, issue.ID).Scan(&title); err != nil || title != issue.Title {
+		t.Errorf("snapshot title = %q, %v, want %q", title, err, issue.Title)
+	}
+	if err := snap.QueryRow(`PRAGMA journal_mode`).Scan(&mode); err != nil || mode != "delete" {
+		t.Errorf("snapshot journal_mode = %q, %v, want delete", mode, err)
+	}
+}
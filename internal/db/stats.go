@@ -2,9 +2,13 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/marcus/td/internal/clock"
 	"github.com/marcus/td/internal/models"
 )
 
@@ -58,9 +62,11 @@ func (db *DB) GetExtendedStats() (*models.ExtendedStats, error) {
 		ByStatus:   make(map[models.Status]int),
 		ByType:     make(map[models.Type]int),
 		ByPriority: make(map[models.Priority]int),
+
+		ByBlockedReason: make(map[models.BlockedReason]int),
 	}
 
-	now := time.Now()
+	now := clock.Now()
 	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	tomorrow := today.AddDate(0, 0, 1)
 	weekAgo := now.AddDate(0, 0, -7)
@@ -90,6 +96,9 @@ func (db *DB) GetExtendedStats() (*models.ExtendedStats, error) {
 		SELECT 'type' as category, type as value, COUNT(*) as cnt FROM issues WHERE deleted_at IS NULL GROUP BY type
 		UNION ALL
 		SELECT 'priority' as category, priority as value, COUNT(*) as cnt FROM issues WHERE deleted_at IS NULL GROUP BY priority
+		UNION ALL
+		SELECT 'blocked_reason' as category, COALESCE(blocked_reason, '') as value, COUNT(*) as cnt FROM issues
+		WHERE deleted_at IS NULL AND status = 'blocked' GROUP BY COALESCE(blocked_reason, '')
 	`)
 	if err != nil {
 		return nil, err
@@ -108,6 +117,8 @@ func (db *DB) GetExtendedStats() (*models.ExtendedStats, error) {
 			stats.ByType[models.Type(value)] = count
 		case "priority":
 			stats.ByPriority[models.Priority(value)] = count
+		case "blocked_reason":
+			stats.ByBlockedReason[models.BlockedReason(value)] = count
 		}
 	}
 
@@ -117,19 +128,19 @@ func (db *DB) GetExtendedStats() (*models.ExtendedStats, error) {
 	var closedAt, deletedAt sql.NullTime
 	var parentID1, acceptance1, sprint1 sql.NullString
 	var implSession1, creatorSession1, reviewerSession1 sql.NullString
-	var createdBranch1 sql.NullString
+	var createdBranch1, createdRepo1, blockedReason1, blockedRef1 sql.NullString
 	var deferUntil1, dueDate1 sql.NullString
 	err = db.conn.QueryRow(`
 		SELECT id, title, description, status, type, priority, points, labels, parent_id, acceptance, sprint,
 		       implementer_session, creator_session, reviewer_session, created_at, updated_at, closed_at, deleted_at, minor, created_branch,
-		       defer_until, due_date, defer_count
+		       defer_until, due_date, defer_count, created_repo, blocked_reason, blocked_ref, inbox, confidential, rank
 		FROM issues WHERE status = ? AND deleted_at IS NULL ORDER BY created_at ASC LIMIT 1
 	`, models.StatusOpen).Scan(
 		&oldestIssue.ID, &oldestIssue.Title, &oldestIssue.Description, &oldestIssue.Status, &oldestIssue.Type,
 		&oldestIssue.Priority, &oldestIssue.Points, &labels, &parentID1, &acceptance1, &sprint1,
 		&implSession1, &creatorSession1, &reviewerSession1, &oldestIssue.CreatedAt, &oldestIssue.UpdatedAt,
 		&closedAt, &deletedAt, &oldestIssue.Minor, &createdBranch1,
-		&deferUntil1, &dueDate1, &oldestIssue.DeferCount,
+		&deferUntil1, &dueDate1, &oldestIssue.DeferCount, &createdRepo1, &blockedReason1, &blockedRef1, &oldestIssue.Inbox, &oldestIssue.Confidential, &oldestIssue.Rank,
 	)
 	if err == nil {
 		if labels != "" {
@@ -148,6 +159,9 @@ func (db *DB) GetExtendedStats() (*models.ExtendedStats, error) {
 		oldestIssue.CreatorSession = creatorSession1.String
 		oldestIssue.ReviewerSession = reviewerSession1.String
 		oldestIssue.CreatedBranch = createdBranch1.String
+		oldestIssue.CreatedRepo = createdRepo1.String
+		oldestIssue.BlockedReason = models.BlockedReason(blockedReason1.String)
+		oldestIssue.BlockedRef = blockedRef1.String
 		if deferUntil1.Valid {
 			oldestIssue.DeferUntil = &deferUntil1.String
 		}
@@ -164,19 +178,19 @@ func (db *DB) GetExtendedStats() (*models.ExtendedStats, error) {
 	deletedAt = sql.NullTime{}
 	var parentID2, acceptance2, sprint2 sql.NullString
 	var implSession2, creatorSession2, reviewerSession2 sql.NullString
-	var createdBranch2 sql.NullString
+	var createdBranch2, createdRepo2, blockedReason2, blockedRef2 sql.NullString
 	var deferUntil2, dueDate2 sql.NullString
 	err = db.conn.QueryRow(`
 		SELECT id, title, description, status, type, priority, points, labels, parent_id, acceptance, sprint,
 		       implementer_session, creator_session, reviewer_session, created_at, updated_at, closed_at, deleted_at, minor, created_branch,
-		       defer_until, due_date, defer_count
+		       defer_until, due_date, defer_count, created_repo, blocked_reason, blocked_ref, inbox, confidential, rank
 		FROM issues WHERE deleted_at IS NULL ORDER BY created_at DESC LIMIT 1
 	`).Scan(
 		&newestIssue.ID, &newestIssue.Title, &newestIssue.Description, &newestIssue.Status, &newestIssue.Type,
 		&newestIssue.Priority, &newestIssue.Points, &labels, &parentID2, &acceptance2, &sprint2,
 		&implSession2, &creatorSession2, &reviewerSession2, &newestIssue.CreatedAt, &newestIssue.UpdatedAt,
 		&closedAt, &deletedAt, &newestIssue.Minor, &createdBranch2,
-		&deferUntil2, &dueDate2, &newestIssue.DeferCount,
+		&deferUntil2, &dueDate2, &newestIssue.DeferCount, &createdRepo2, &blockedReason2, &blockedRef2, &newestIssue.Inbox, &newestIssue.Confidential, &newestIssue.Rank,
 	)
 	if err == nil {
 		if labels != "" {
@@ -195,6 +209,9 @@ func (db *DB) GetExtendedStats() (*models.ExtendedStats, error) {
 		newestIssue.CreatorSession = creatorSession2.String
 		newestIssue.ReviewerSession = reviewerSession2.String
 		newestIssue.CreatedBranch = createdBranch2.String
+		newestIssue.CreatedRepo = createdRepo2.String
+		newestIssue.BlockedReason = models.BlockedReason(blockedReason2.String)
+		newestIssue.BlockedRef = blockedRef2.String
 		if deferUntil2.Valid {
 			newestIssue.DeferUntil = &deferUntil2.String
 		}
@@ -211,12 +228,12 @@ func (db *DB) GetExtendedStats() (*models.ExtendedStats, error) {
 	deletedAt = sql.NullTime{}
 	var parentID3, acceptance3, sprint3 sql.NullString
 	var implSession3, creatorSession3, reviewerSession3 sql.NullString
-	var createdBranch3 sql.NullString
+	var createdBranch3, createdRepo3, blockedReason3, blockedRef3 sql.NullString
 	var deferUntil3, dueDate3 sql.NullString
 	err = db.conn.QueryRow(`
 		SELECT id, title, description, status, type, priority, points, labels, parent_id, acceptance, sprint,
 		       implementer_session, creator_session, reviewer_session, created_at, updated_at, closed_at, deleted_at, minor, created_branch,
-		       defer_until, due_date, defer_count
+		       defer_until, due_date, defer_count, created_repo, blocked_reason, blocked_ref, inbox, confidential, rank
 		FROM issues WHERE status = ? AND closed_at IS NOT NULL AND deleted_at IS NULL
 		ORDER BY closed_at DESC LIMIT 1
 	`, models.StatusClosed).Scan(
@@ -224,7 +241,7 @@ func (db *DB) GetExtendedStats() (*models.ExtendedStats, error) {
 		&closedIssue.Priority, &closedIssue.Points, &labels, &parentID3, &acceptance3, &sprint3,
 		&implSession3, &creatorSession3, &reviewerSession3, &closedIssue.CreatedAt, &closedIssue.UpdatedAt,
 		&closedAt, &deletedAt, &closedIssue.Minor, &createdBranch3,
-		&deferUntil3, &dueDate3, &closedIssue.DeferCount,
+		&deferUntil3, &dueDate3, &closedIssue.DeferCount, &createdRepo3, &blockedReason3, &blockedRef3, &closedIssue.Inbox, &closedIssue.Confidential, &closedIssue.Rank,
 	)
 	if err == nil {
 		if labels != "" {
@@ -243,6 +260,9 @@ func (db *DB) GetExtendedStats() (*models.ExtendedStats, error) {
 		closedIssue.CreatorSession = creatorSession3.String
 		closedIssue.ReviewerSession = reviewerSession3.String
 		closedIssue.CreatedBranch = createdBranch3.String
+		closedIssue.CreatedRepo = createdRepo3.String
+		closedIssue.BlockedReason = models.BlockedReason(blockedReason3.String)
+		closedIssue.BlockedRef = blockedRef3.String
 		if deferUntil3.Valid {
 			closedIssue.DeferUntil = &deferUntil3.String
 		}
@@ -269,6 +289,10 @@ func (db *DB) GetExtendedStats() (*models.ExtendedStats, error) {
 		stats.MostActiveSession = mostActiveSession
 	}
 
+	if err := db.reworkStats(stats); err != nil {
+		return nil, err
+	}
+
 	return stats, nil
 }
 
@@ -283,3 +307,136 @@ func (db *DB) GetChangeToken() (string, error) {
 	}
 	return token, nil
 }
+
+// Change token collections. Each groups the action_log entity types whose
+// changes invalidate that collection in a client.
+const (
+	CollectionIssues   = "issues"
+	CollectionBoards   = "boards"
+	CollectionSessions = "sessions"
+	CollectionComments = "comments"
+)
+
+// collectionEntityTypes maps each collection to the action_log entity types
+// (singular and plural spellings) that belong to it.
+var collectionEntityTypes = map[string][]string{
+	CollectionIssues: {
+		"issue", "issues", "log", "logs", "dependency", "issue_dependencies",
+		"file_link", "issue_files", "git_snapshot", "git_snapshots",
+	},
+	CollectionBoards:   {"board", "boards", "board_position", "board_issue_positions"},
+	CollectionSessions: {"handoff", "handoffs", "work_session", "work_sessions", "work_session_issue", "work_session_issues", "session", "sessions"},
+	CollectionComments: {"comment", "comments"},
+}
+
+// ChangeCollections lists the collections that have their own change token
+func ChangeCollections() []string {
+	return []string{CollectionIssues, CollectionBoards, CollectionSessions, CollectionComments}
+}
+
+// GetChangeTokens returns a change token per collection. A collection's
+// token changes only when something in that collection does, so clients
+// can skip refetching the rest. Tokens are opaque strings; compare them
+// for equality only.
+//
+// Session rows are not written to the action log, so the sessions token
+// also counts started and ended sessions. Activity heartbeats are left out
+// on purpose; they fire on every read and would make the token useless.
+func (db *DB) GetChangeTokens() (map[string]string, error) {
+	tokens := make(map[string]string, len(collectionEntityTypes))
+	for collection, types := range collectionEntityTypes {
+		var max int64
+		for _, t := range types {
+			var n int64
+			// One query per type keeps SQLite's MAX() index lookup
+			if err := db.conn.QueryRow(`SELECT COALESCE(MAX(rowid), 0) FROM action_log WHERE entity_type = ?`, t).Scan(&n); err != nil {
+				return nil, err
+			}
+			if n > max {
+				max = n
+			}
+		}
+		tokens[collection] = strconv.FormatInt(max, 10)
+	}
+
+	var started, ended int64
+	if err := db.conn.QueryRow(`SELECT COUNT(*), COUNT(ended_at) FROM sessions`).Scan(&started, &ended); err != nil {
+		return nil, err
+	}
+	tokens[CollectionSessions] = fmt.Sprintf("%s.%d.%d", tokens[CollectionSessions], started, ended)
+	return tokens, nil
+}
+
+// ActionIssueID returns the issue an action log entry touched: the entity
+// itself for issues, or the issue_id recorded in the entry's data for
+// comments, logs, dependencies and the like. It is empty when the entry
+// belongs to no issue.
+func ActionIssueID(entityType, entityID, prevData, newData string) string {
+	if entityType == "issue" || entityType == "issues" {
+		return entityID
+	}
+	var ref struct {
+		IssueID string `json:"issue_id"`
+	}
+	if json.Unmarshal([]byte(newData), &ref) != nil || ref.IssueID == "" {
+		_ = json.Unmarshal([]byte(prevData), &ref)
+	}
+	return ref.IssueID
+}
+
+// ChangedIssues returns the issues touched by action log entries after the
+// change token since: each issue as it is now (soft-deleted ones included),
+// followed by the state it had before the first of those entries changed
+// it, when the log recorded one. Matching a filter against both tells a subscriber about
+// issues that left its view as well as ones that entered it. Entries for
+// comments, logs, dependencies and the like count against their issue.
+func (db *DB) ChangedIssues(since string) ([]models.Issue, error) {
+	after, err := strconv.ParseInt(since, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid change token %q", since)
+	}
+	rows, err := db.conn.Query(`SELECT entity_type, entity_id, previous_data, new_data FROM action_log WHERE rowid > ? ORDER BY rowid`, after)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	seen := make(map[string]bool)
+	hasBefore := make(map[string]bool)
+	var before []models.Issue
+	for rows.Next() {
+		var entityType, entityID string
+		var prevData, newData sql.NullString
+		if err := rows.Scan(&entityType, &entityID, &prevData, &newData); err != nil {
+			return nil, err
+		}
+		id := ActionIssueID(entityType, entityID, prevData.String, newData.String)
+		if id == "" {
+			continue
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+		if (entityType == "issue" || entityType == "issues") && !hasBefore[id] {
+			var prev models.Issue
+			if json.Unmarshal([]byte(prevData.String), &prev) == nil && prev.ID != "" {
+				hasBefore[id] = true
+				before = append(before, prev)
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	issues, err := db.GetIssuesByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	return append(issues, before...), nil
+}
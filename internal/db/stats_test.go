@@ -0,0 +1,48 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestGetChangeTokens_PerCollection(t *testing.T) {
+	database, err := Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	defer database.Close()
+
+	before, err := database.GetChangeTokens()
+	if err != nil {
+		t.Fatalf("GetChangeTokens failed: %v", err)
+	}
+	for _, c := range ChangeCollections() {
+		if before[c] == "" {
+			t.Errorf("missing token for %s", c)
+		}
+	}
+
+	issue := &models.Issue{Title: "Token test issue"}
+	if err := database.CreateIssueLogged(issue, "sess-1"); err != nil {
+		t.Fatal(err)
+	}
+	afterIssue, _ := database.GetChangeTokens()
+	if afterIssue[CollectionIssues] == before[CollectionIssues] {
+		t.Error("issues token did not change after creating an issue")
+	}
+	if afterIssue[CollectionComments] != before[CollectionComments] || afterIssue[CollectionBoards] != before[CollectionBoards] {
+		t.Errorf("unrelated tokens changed: %v -> %v", before, afterIssue)
+	}
+
+	if err := database.AddComment(&models.Comment{IssueID: issue.ID, SessionID: "sess-1", Text: "hello"}); err != nil {
+		t.Fatal(err)
+	}
+	afterComment, _ := database.GetChangeTokens()
+	if afterComment[CollectionComments] == afterIssue[CollectionComments] {
+		t.Error("comments token did not change after adding a comment")
+	}
+	if afterComment[CollectionIssues] != afterIssue[CollectionIssues] {
+		t.Error("issues token changed after adding a comment")
+	}
+}
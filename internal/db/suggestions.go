@@ -0,0 +1,186 @@
+package db
+
+import (
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/models"
+)
+
+// RecordSuggestionDecision stores that the suggested relation between
+// d.IssueID and d.OtherID was accepted or dismissed, replacing any earlier
+// decision for the pair. CreatedAt is filled in. Decisions are local: they
+// are not written to the action log or synced (an accepted suggestion's
+// dependency is).
+func (db *DB) RecordSuggestionDecision(d *models.SuggestionDecision) error {
+	return db.withWriteLock(func() error {
+		d.IssueID = NormalizeIssueID(d.IssueID)
+		d.OtherID = NormalizeIssueID(d.OtherID)
+		d.CreatedAt = clock.Now().UTC()
+		_, err := db.conn.Exec(`INSERT OR REPLACE INTO relation_suggestions
+			(issue_id, other_id, status, relation_type, session_id, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+			d.IssueID, d.OtherID, string(d.Status), d.RelationType, d.SessionID, d.CreatedAt.Format(time.RFC3339))
+		return err
+	})
+}
+
+// SuggestionDecisions returns the decisions made about issueID's suggested
+// relations from either side, keyed by the other issue
+func (db *DB) SuggestionDecisions(issueID string) (map[string]models.SuggestionDecision, error) {
+	issueID = NormalizeIssueID(issueID)
+	rows, err := db.conn.Query(`SELECT issue_id, other_id, status, relation_type, session_id, created_at
+		FROM relation_suggestions WHERE issue_id = ? OR other_id = ?`, issueID, issueID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make(map[string]models.SuggestionDecision)
+	for rows.Next() {
+		var d models.SuggestionDecision
+		var status, createdAt string
+		if err := rows.Scan(&d.IssueID, &d.OtherID, &status, &d.RelationType, &d.SessionID, &createdAt); err != nil {
+			return nil, err
+		}
+		d.Status = models.SuggestionStatus(status)
+		d.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		other := d.OtherID
+		if other == issueID {
+			other = d.IssueID
+		}
+		out[other] = d
+	}
+	return out, rows.Err()
+}
+
+// SharedFiles returns the other live issues linked to any of issueID's
+// files, with the paths they share
+func (db *DB) SharedFiles(issueID string) (map[string][]string, error) {
+	rows, err := db.conn.Query(`
+		SELECT b.issue_id, b.file_path
+		FROM issue_files a
+		JOIN issue_files b ON b.file_path = a.file_path AND b.issue_id != a.issue_id
+		JOIN issues i ON i.id = b.issue_id AND i.deleted_at IS NULL
+		WHERE a.issue_id = ?
+		ORDER BY b.issue_id, b.file_path`, NormalizeIssueID(issueID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make(map[string][]string)
+	for rows.Next() {
+		var id, path string
+		if err := rows.Scan(&id, &path); err != nil {
+			return nil, err
+		}
+		out[id] = append(out[id], path)
+	}
+	return out, rows.Err()
+}
+
+// LinkedIssueIDs returns the issues already tied to issueID: through a
+// dependency of any relation type in either direction, or as its parent
+// or child
+func (db *DB) LinkedIssueIDs(issueID string) (map[string]bool, error) {
+	issueID = NormalizeIssueID(issueID)
+	rows, err := db.conn.Query(`
+		SELECT depends_on_id FROM issue_dependencies WHERE issue_id = ?
+		UNION SELECT issue_id FROM issue_dependencies WHERE depends_on_id = ?
+		UNION SELECT parent_id FROM issues WHERE id = ? AND parent_id != ''
+		UNION SELECT id FROM issues WHERE parent_id = ?`, issueID, issueID, issueID, issueID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out[id] = true
+	}
+	return out, rows.Err()
+}
+
+// IssueNotes returns the text of the logs and comments recorded directly
+// on issueID. Work session logs shared across issues are left out.
+func (db *DB) IssueNotes(issueID string) ([]string, error) {
+	issueID = NormalizeIssueID(issueID)
+	rows, err := db.conn.Query(`SELECT message FROM logs WHERE issue_id = ?
+		UNION ALL SELECT text FROM comments WHERE issue_id = ?`, issueID, issueID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []string
+	for rows.Next() {
+		var text string
+		if err := rows.Scan(&text); err != nil {
+			return nil, err
+		}
+		out = append(out, text)
+	}
+	return out, rows.Err()
+}
+
+// IssuesNoting returns the live issues other than excludeID whose logs or
+// comments contain text, matched case-sensitively
+func (db *DB) IssuesNoting(text, excludeID string) ([]string, error) {
+	excludeID = NormalizeIssueID(excludeID)
+	return db.queryIssueIDs(`
+		SELECT issue_id FROM logs WHERE issue_id != '' AND issue_id != ? AND instr(message, ?) > 0
+		UNION SELECT issue_id FROM comments WHERE issue_id != ? AND instr(text, ?) > 0`,
+		excludeID, text, excludeID, text)
+}
+
+// CommitSHAs returns the distinct commits recorded in issueID's git
+// snapshots
+func (db *DB) CommitSHAs(issueID string) ([]string, error) {
+	rows, err := db.conn.Query(`SELECT DISTINCT commit_sha FROM git_snapshots WHERE issue_id = ? AND commit_sha != ''`,
+		NormalizeIssueID(issueID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []string
+	for rows.Next() {
+		var sha string
+		if err := rows.Scan(&sha); err != nil {
+			return nil, err
+		}
+		out = append(out, sha)
+	}
+	return out, rows.Err()
+}
+
+// IssuesAtCommit returns the live issues other than excludeID with a git
+// snapshot at a commit starting with prefix
+func (db *DB) IssuesAtCommit(prefix, excludeID string) ([]string, error) {
+	return db.queryIssueIDs(`
+		SELECT DISTINCT issue_id FROM git_snapshots WHERE issue_id != ? AND substr(commit_sha, 1, ?) = ?`,
+		NormalizeIssueID(excludeID), len(prefix), prefix)
+}
+
+// queryIssueIDs runs a query selecting issue IDs and keeps the live ones
+func (db *DB) queryIssueIDs(q string, args ...interface{}) ([]string, error) {
+	rows, err := db.conn.Query(`SELECT id FROM issues WHERE deleted_at IS NULL AND id IN (`+q+`) ORDER BY id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
@@ -67,6 +67,8 @@ type SyncState struct {
 	LastPulledServerSeq int64
 	LastSyncAt          *time.Time
 	SyncDisabled        bool
+	LastPushError       string     // error the last push failed with, "" if it succeeded
+	LastPushAttemptAt   *time.Time // nil until a push is tried
 }
 
 // Conn returns the underlying *sql.DB connection for use in transactions
@@ -80,11 +82,14 @@ func (db *DB) GetSyncState() (*SyncState, error) {
 	var s SyncState
 	var lastSync sql.NullTime
 	var disabled int
+	var lastAttempt sql.NullString
 
 	err := db.conn.QueryRow(`
-		SELECT project_id, last_pushed_action_id, last_pulled_server_seq, last_sync_at, sync_disabled
+		SELECT project_id, last_pushed_action_id, last_pulled_server_seq, last_sync_at, sync_disabled,
+			last_push_error, last_push_attempt_at
 		FROM sync_state LIMIT 1
-	`).Scan(&s.ProjectID, &s.LastPushedActionID, &s.LastPulledServerSeq, &lastSync, &disabled)
+	`).Scan(&s.ProjectID, &s.LastPushedActionID, &s.LastPulledServerSeq, &lastSync, &disabled,
+		&s.LastPushError, &lastAttempt)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -97,6 +102,11 @@ func (db *DB) GetSyncState() (*SyncState, error) {
 		s.LastSyncAt = &lastSync.Time
 	}
 	s.SyncDisabled = disabled != 0
+	if lastAttempt.Valid {
+		if t, err := time.Parse(time.RFC3339, lastAttempt.String); err == nil {
+			s.LastPushAttemptAt = &t
+		}
+	}
 	return &s, nil
 }
 
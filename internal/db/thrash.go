@@ -0,0 +1,140 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/thrash"
+)
+
+// ThrashConfirmEnv set to 1 confirms changes the anti-thrash guard would
+// otherwise hold in confirm mode
+const ThrashConfirmEnv = "TD_CONFIRM_THRASH"
+
+// thrashHistoryLimit caps how many of a session's recent actions on an
+// issue are scanned for reversals
+const thrashHistoryLimit = 200
+
+// ThrashError is returned when the anti-thrash guard rejects an update
+type ThrashError struct {
+	IssueID string
+	Mode    string // thrash.ModeThrottle or thrash.ModeConfirm
+	Verdict thrash.Verdict
+}
+
+func (e *ThrashError) Error() string {
+	if e.Mode == thrash.ModeThrottle {
+		return fmt.Sprintf("%s: %s; throttled, retry in %s", e.IssueID, e.Verdict.String(), e.Verdict.RetryAfter.Round(time.Second))
+	}
+	return fmt.Sprintf("%s: %s; set %s=1 to confirm this change", e.IssueID, e.Verdict.String(), ThrashConfirmEnv)
+}
+
+// UpdateIssueLoggedConfirmed is UpdateIssueLogged for a change the caller
+// has explicitly confirmed, which the anti-thrash guard lets through in
+// confirm mode
+func (db *DB) UpdateIssueLoggedConfirmed(issue *models.Issue, sessionID string, actionType models.ActionType) error {
+	if err := db.checkTransitionPolicy(issue, sessionID); err != nil {
+		return err
+	}
+	return db.withWriteLock(func() error {
+		if err := db.checkThrashLocked(issue, sessionID, true); err != nil {
+			return err
+		}
+		return db.updateIssueAndLog(issue, sessionID, actionType)
+	})
+}
+
+// checkThrashLocked runs the anti-thrash guard on an update. Anomalies are
+// written to the security event log whatever the outcome.
+func (db *DB) checkThrashLocked(issue *models.Issue, sessionID string, confirmed bool) error {
+	if sessionID == "" {
+		return nil
+	}
+	tc, err := config.GetThrashConfig(db.baseDir)
+	if err != nil {
+		slog.Debug("thrash: load config", "err", err)
+	}
+	cfg := thrash.WithDefaults(tc)
+	if cfg.Mode == thrash.ModeOff {
+		return nil
+	}
+
+	prev, err := db.scanIssueRow(issue.ID)
+	if err != nil {
+		return nil // let the update report the missing issue
+	}
+	now := clock.Now()
+	proposed := thrash.Changes(prev, issue, now)
+	if len(proposed) == 0 {
+		return nil
+	}
+
+	since := now.Add(-time.Duration(cfg.WindowMinutes) * time.Minute)
+	history, err := db.sessionIssueChanges(issue.ID, sessionID, since)
+	if err != nil {
+		return err
+	}
+	verdict := thrash.Check(history, proposed, cfg, now)
+	if verdict == nil {
+		return nil
+	}
+
+	confirmed = confirmed || os.Getenv(ThrashConfirmEnv) == "1"
+	outcome := "rejected"
+	var rejection error
+	switch {
+	case cfg.Mode == thrash.ModeWarn:
+		outcome = "allowed"
+	case cfg.Mode == thrash.ModeConfirm && confirmed:
+		outcome = "confirmed"
+	default:
+		rejection = &ThrashError{IssueID: issue.ID, Mode: cfg.Mode, Verdict: *verdict}
+	}
+
+	if err := LogSecurityEvent(db.baseDir, SecurityEvent{
+		IssueID:   issue.ID,
+		SessionID: sessionID,
+		Reason:    fmt.Sprintf("thrash: %s (%s, %s)", verdict.String(), cfg.Mode, outcome),
+	}); err != nil {
+		slog.Debug("thrash: log anomaly", "err", err)
+	}
+	return rejection
+}
+
+// sessionIssueChanges reads a session's status and priority changes to an
+// issue since a time from the action log, skipping undone actions
+func (db *DB) sessionIssueChanges(issueID, sessionID string, since time.Time) ([]thrash.Change, error) {
+	rows, err := db.conn.Query(`
+		SELECT previous_data, new_data, timestamp FROM action_log
+		WHERE entity_type = 'issue' AND entity_id = ? AND session_id = ? AND undone = 0
+		ORDER BY rowid DESC LIMIT ?
+	`, issueID, sessionID, thrashHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []thrash.Change
+	for rows.Next() {
+		var prevData, newData string
+		var ts time.Time
+		if err := rows.Scan(&prevData, &newData, &ts); err != nil {
+			return nil, err
+		}
+		if ts.Before(since) || prevData == "" || newData == "" {
+			continue
+		}
+		var prev, next models.Issue
+		if json.Unmarshal([]byte(prevData), &prev) != nil || json.Unmarshal([]byte(newData), &next) != nil {
+			continue
+		}
+		changes = append(changes, thrash.Changes(&prev, &next, ts)...)
+	}
+	return changes, rows.Err()
+}
@@ -0,0 +1,149 @@
+package db
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/models"
+)
+
+// ErrAPITokenRevoked is returned when revoking a token that was already revoked
+var ErrAPITokenRevoked = errors.New("token already revoked")
+
+const apiTokenColumns = `id, name, session_id, scopes, created_at, expires_at, revoked_at`
+
+// CreateAPIToken stores a new API token and returns the token itself, which
+// is not kept: only its hash is stored. ID and CreatedAt are filled in.
+// Tokens are local to this database and are not synced.
+func (db *DB) CreateAPIToken(t *models.APIToken) (string, error) {
+	secret := make([]byte, 20)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	token := models.APITokenPrefix + hex.EncodeToString(secret)
+
+	err := db.withWriteLock(func() error {
+		id, err := generateTokenID()
+		if err != nil {
+			return err
+		}
+		t.ID = id
+		t.CreatedAt = clock.Now().UTC().Truncate(time.Second)
+
+		var expiresAt interface{}
+		if t.ExpiresAt != nil {
+			expiresAt = t.ExpiresAt.UTC().Format(time.RFC3339)
+		}
+		_, err = db.conn.Exec(`INSERT INTO api_tokens (id, name, token_hash, session_id, scopes, created_at, expires_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			t.ID, t.Name, hashAPIToken(token), t.SessionID, strings.Join(t.Scopes, ","),
+			t.CreatedAt.Format(time.RFC3339), expiresAt)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// GetAPIToken retrieves an API token by ID
+func (db *DB) GetAPIToken(id string) (*models.APIToken, error) {
+	row := db.conn.QueryRow(`SELECT `+apiTokenColumns+` FROM api_tokens WHERE id = ?`, id)
+	t, err := scanAPIToken(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("token not found: %s", id)
+	}
+	return t, err
+}
+
+// LookupAPIToken finds the stored token a bearer token was issued as. It
+// does not check that the token is still active.
+func (db *DB) LookupAPIToken(token string) (*models.APIToken, error) {
+	row := db.conn.QueryRow(`SELECT `+apiTokenColumns+` FROM api_tokens WHERE token_hash = ?`, hashAPIToken(token))
+	t, err := scanAPIToken(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("token not found")
+	}
+	return t, err
+}
+
+// ListAPITokens returns API tokens newest first, optionally for one session.
+// Revoked and expired tokens are only included when all is set.
+func (db *DB) ListAPITokens(sessionID string, all bool) ([]models.APIToken, error) {
+	query := `SELECT ` + apiTokenColumns + ` FROM api_tokens`
+	var args []interface{}
+	if sessionID != "" {
+		query += ` WHERE session_id = ?`
+		args = append(args, sessionID)
+	}
+	query += ` ORDER BY created_at DESC, id`
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	now := clock.Now()
+	var tokens []models.APIToken
+	for rows.Next() {
+		t, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		if all || t.Active(now) {
+			tokens = append(tokens, *t)
+		}
+	}
+	return tokens, rows.Err()
+}
+
+// RevokeAPIToken permanently disables an API token
+func (db *DB) RevokeAPIToken(id string) error {
+	return db.withWriteLock(func() error {
+		res, err := db.conn.Exec(`UPDATE api_tokens SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL`,
+			clock.Now().UTC().Format(time.RFC3339), id)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			if _, err := db.GetAPIToken(id); err != nil {
+				return err
+			}
+			return ErrAPITokenRevoked
+		}
+		return nil
+	})
+}
+
+// hashAPIToken returns the hex SHA-256 a token is stored as. Tokens are
+// random, so a plain hash is enough to keep a copied database from
+// yielding usable tokens.
+func hashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func scanAPIToken(row shareLinkScanner) (*models.APIToken, error) {
+	var t models.APIToken
+	var scopes, createdAt string
+	var expiresAt, revokedAt sql.NullString
+
+	if err := row.Scan(&t.ID, &t.Name, &t.SessionID, &scopes,
+		&createdAt, &expiresAt, &revokedAt); err != nil {
+		return nil, err
+	}
+
+	t.Scopes = strings.Split(scopes, ",")
+	t.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	t.ExpiresAt = parseNullTime(expiresAt)
+	t.RevokedAt = parseNullTime(revokedAt)
+	return &t, nil
+}
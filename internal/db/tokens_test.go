@@ -0,0 +1,76 @@
+package db
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestAPITokensLifecycle(t *testing.T) {
+	database, err := Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+
+	past := time.Now().Add(-time.Hour)
+	agent := &models.APIToken{Name: "ci", SessionID: "ses_agent", Scopes: []string{"read", "write"}}
+	expired := &models.APIToken{SessionID: "ses_agent", Scopes: []string{"read"}, ExpiresAt: &past}
+	other := &models.APIToken{SessionID: "ses_other", Scopes: []string{"read"}}
+
+	var secret string
+	for i, tok := range []*models.APIToken{agent, expired, other} {
+		s, err := database.CreateAPIToken(tok)
+		if err != nil {
+			t.Fatalf("CreateAPIToken: %v", err)
+		}
+		if !strings.HasPrefix(s, models.APITokenPrefix) || tok.ID == "" {
+			t.Fatalf("created token %q, %+v", s, tok)
+		}
+		if i == 0 {
+			secret = s
+		}
+	}
+
+	got, err := database.LookupAPIToken(secret)
+	if err != nil || got.ID != agent.ID || got.Name != "ci" || !got.HasScope("write") {
+		t.Fatalf("LookupAPIToken = %+v, %v", got, err)
+	}
+	if _, err := database.LookupAPIToken(secret + "x"); err == nil {
+		t.Error("LookupAPIToken found a token that was never issued")
+	}
+
+	// The token itself is never stored
+	var hash string
+	if err := database.conn.QueryRow(`SELECT token_hash FROM api_tokens WHERE id = ?`, agent.ID).Scan(&hash); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(hash, secret) || hash == "" {
+		t.Errorf("stored %q for token %q", hash, secret)
+	}
+
+	list, err := database.ListAPITokens("ses_agent", false)
+	if err != nil || len(list) != 1 || list[0].ID != agent.ID {
+		t.Fatalf("ListAPITokens(active) = %+v, %v", list, err)
+	}
+	if list, _ := database.ListAPITokens("", true); len(list) != 3 {
+		t.Errorf("ListAPITokens(all) = %d, want 3", len(list))
+	}
+
+	if err := database.RevokeAPIToken(agent.ID); err != nil {
+		t.Fatalf("RevokeAPIToken: %v", err)
+	}
+	if err := database.RevokeAPIToken(agent.ID); !errors.Is(err, ErrAPITokenRevoked) {
+		t.Errorf("second revoke = %v, want ErrAPITokenRevoked", err)
+	}
+	if err := database.RevokeAPIToken("tk-missing"); err == nil || errors.Is(err, ErrAPITokenRevoked) {
+		t.Errorf("revoke unknown = %v, want not found", err)
+	}
+	got, _ = database.LookupAPIToken(secret)
+	if got.Active(time.Now()) {
+		t.Error("revoked token is still active")
+	}
+}
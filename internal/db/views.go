@@ -0,0 +1,150 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/models"
+)
+
+// ============================================================================
+// Saved views
+// ============================================================================
+
+const viewColumns = `id, name, query, sort, fields, group_by, layout, session_id, created_at, updated_at`
+
+// CreateView stores a saved view. ID and timestamps are filled in. Callers
+// validate sort, fields, grouping and layout; the query's syntax and the
+// name's uniqueness are checked here.
+func (db *DB) CreateView(v *models.View) error {
+	if err := parseAndValidateQuery(v.Query); err != nil {
+		return fmt.Errorf("invalid query: %w", err)
+	}
+	return db.withWriteLock(func() error {
+		id, err := generateViewID()
+		if err != nil {
+			return err
+		}
+		v.ID = id
+		v.CreatedAt = clock.Now().UTC()
+		v.UpdatedAt = v.CreatedAt
+
+		fields, err := json.Marshal(nonNilFields(v.Fields))
+		if err != nil {
+			return err
+		}
+		_, err = db.conn.Exec(`INSERT INTO views (`+viewColumns+`)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			v.ID, v.Name, v.Query, v.Sort, string(fields), v.GroupBy, v.Layout, v.SessionID,
+			v.CreatedAt.Format(time.RFC3339), v.UpdatedAt.Format(time.RFC3339))
+		return viewNameError(err, v.Name)
+	})
+}
+
+// GetView returns a saved view by ID or by name (case-insensitive)
+func (db *DB) GetView(ref string) (*models.View, error) {
+	where := `name = ? COLLATE NOCASE`
+	if strings.HasPrefix(ref, viewIDPrefix) {
+		where = `id = ?`
+	}
+	v, err := scanView(db.conn.QueryRow(`SELECT `+viewColumns+` FROM views WHERE `+where, ref))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("view not found: %s", ref)
+	}
+	return v, err
+}
+
+// ListViews returns every saved view, by name
+func (db *DB) ListViews() ([]models.View, error) {
+	rows, err := db.conn.Query(`SELECT ` + viewColumns + ` FROM views ORDER BY name COLLATE NOCASE`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var views []models.View
+	for rows.Next() {
+		v, err := scanView(rows)
+		if err != nil {
+			return nil, err
+		}
+		views = append(views, *v)
+	}
+	return views, rows.Err()
+}
+
+// UpdateView saves every field of an existing view and bumps UpdatedAt
+func (db *DB) UpdateView(v *models.View) error {
+	if err := parseAndValidateQuery(v.Query); err != nil {
+		return fmt.Errorf("invalid query: %w", err)
+	}
+	return db.withWriteLock(func() error {
+		fields, err := json.Marshal(nonNilFields(v.Fields))
+		if err != nil {
+			return err
+		}
+		v.UpdatedAt = clock.Now().UTC()
+		res, err := db.conn.Exec(`UPDATE views
+			SET name = ?, query = ?, sort = ?, fields = ?, group_by = ?, layout = ?, updated_at = ?
+			WHERE id = ?`,
+			v.Name, v.Query, v.Sort, string(fields), v.GroupBy, v.Layout,
+			v.UpdatedAt.Format(time.RFC3339), v.ID)
+		if err != nil {
+			return viewNameError(err, v.Name)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return fmt.Errorf("view not found: %s", v.ID)
+		}
+		return nil
+	})
+}
+
+// DeleteView removes a saved view by ID
+func (db *DB) DeleteView(id string) error {
+	return db.withWriteLock(func() error {
+		res, err := db.conn.Exec(`DELETE FROM views WHERE id = ?`, id)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return fmt.Errorf("view not found: %s", id)
+		}
+		return nil
+	})
+}
+
+// scanView reads one views row selected with viewColumns
+func scanView(row interface{ Scan(...any) error }) (*models.View, error) {
+	var v models.View
+	var fields, createdAt, updatedAt string
+	if err := row.Scan(&v.ID, &v.Name, &v.Query, &v.Sort, &fields, &v.GroupBy, &v.Layout,
+		&v.SessionID, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(fields), &v.Fields); err != nil {
+		return nil, fmt.Errorf("view %s: bad fields: %w", v.ID, err)
+	}
+	v.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	v.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+	return &v, nil
+}
+
+// viewNameError turns a name collision into a readable error
+func viewNameError(err error, name string) error {
+	if err != nil && strings.Contains(err.Error(), "UNIQUE constraint") {
+		return fmt.Errorf("a view named %q already exists", name)
+	}
+	return err
+}
+
+// nonNilFields stores an empty field list as [] rather than null
+func nonNilFields(fields []string) []string {
+	if fields == nil {
+		return []string{}
+	}
+	return fields
+}
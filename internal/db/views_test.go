@@ -0,0 +1,66 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestViews(t *testing.T) {
+	database, err := Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+
+	v := &models.View{
+		Name:    "triage",
+		Query:   "status = open",
+		Sort:    "-created",
+		Fields:  []string{"id", "title", "priority"},
+		GroupBy: "priority",
+		Layout:  "table",
+	}
+	if err := database.CreateView(v); err != nil {
+		t.Fatalf("CreateView: %v", err)
+	}
+	if v.ID == "" || v.CreatedAt.IsZero() {
+		t.Fatalf("created view = %+v", v)
+	}
+	if err := database.CreateView(&models.View{Name: "Triage", Layout: "list"}); err == nil {
+		t.Error("creating a view with a taken name succeeded, want error")
+	}
+
+	byName, err := database.GetView("TRIAGE")
+	if err != nil {
+		t.Fatalf("GetView by name: %v", err)
+	}
+	byID, err := database.GetView(v.ID)
+	if err != nil {
+		t.Fatalf("GetView by id: %v", err)
+	}
+	if byName.ID != v.ID || byID.Name != "triage" || !reflect.DeepEqual(byID.Fields, v.Fields) {
+		t.Errorf("GetView = %+v / %+v, want %+v", byName, byID, v)
+	}
+
+	byID.Fields = nil
+	byID.GroupBy = ""
+	if err := database.UpdateView(byID); err != nil {
+		t.Fatalf("UpdateView: %v", err)
+	}
+	views, err := database.ListViews()
+	if err != nil || len(views) != 1 {
+		t.Fatalf("ListViews = %+v, %v", views, err)
+	}
+	if len(views[0].Fields) != 0 || views[0].GroupBy != "" || views[0].Sort != "-created" {
+		t.Errorf("after update = %+v", views[0])
+	}
+
+	if err := database.DeleteView(v.ID); err != nil {
+		t.Fatalf("DeleteView: %v", err)
+	}
+	if _, err := database.GetView(v.ID); err == nil {
+		t.Error("GetView after delete succeeded, want error")
+	}
+}
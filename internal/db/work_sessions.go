@@ -4,8 +4,8 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	"time"
 
+	"github.com/marcus/td/internal/clock"
 	"github.com/marcus/td/internal/models"
 )
 
@@ -17,7 +17,7 @@ func (db *DB) CreateWorkSession(ws *models.WorkSession) error {
 			return err
 		}
 		ws.ID = id
-		ws.StartedAt = time.Now()
+		ws.StartedAt = clock.Now()
 
 		_, err = db.conn.Exec(`
 			INSERT INTO work_sessions (id, name, session_id, started_at, start_sha)
@@ -105,7 +105,7 @@ func (db *DB) UpdateWorkSession(ws *models.WorkSession) error {
 func (db *DB) TagIssueToWorkSession(wsID, issueID, sessionID string) error {
 	return db.withWriteLock(func() error {
 		id := WsiID(wsID, issueID)
-		now := time.Now()
+		now := clock.Now()
 		_, err := db.conn.Exec(`
 			INSERT OR IGNORE INTO work_session_issues (id, work_session_id, issue_id, tagged_at)
 			VALUES (?, ?, ?, ?)
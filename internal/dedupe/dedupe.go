@@ -0,0 +1,224 @@
+// Package dedupe finds open issues that are likely duplicates of each other
+// and merges them.
+//
+// Similarity is the overlap (Jaccard index) of the significant words in
+// two issues' titles, blended with the overlap of their descriptions when
+// both have one. Pairs scoring at least the threshold are joined into
+// clusters, so three reports of the same crash form one cluster even if
+// the first and last are worded differently.
+package dedupe
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+// DefaultThreshold is the similarity at which two issues are reported
+const DefaultThreshold = 0.6
+
+// titleWeight is the share of the score taken by the title when both
+// issues have a description
+const titleWeight = 0.7
+
+// openStatuses are the statuses scanned for duplicates
+var openStatuses = []models.Status{
+	models.StatusOpen,
+	models.StatusInProgress,
+	models.StatusBlocked,
+	models.StatusInReview,
+}
+
+// stopWords are too common to say anything about an issue
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "for": true, "from": true, "in": true, "is": true,
+	"it": true, "of": true, "on": true, "or": true, "the": true, "to": true,
+	"when": true, "with": true, "not": true, "should": true, "can": true,
+}
+
+// Member is an issue in a cluster
+type Member struct {
+	ID       string          `json:"id"`
+	Title    string          `json:"title"`
+	Status   models.Status   `json:"status"`
+	Type     models.Type     `json:"type"`
+	Priority models.Priority `json:"priority"`
+	Created  time.Time       `json:"created_at"`
+}
+
+// Pair is the similarity of two issues in a cluster
+type Pair struct {
+	A     string  `json:"a"`
+	B     string  `json:"b"`
+	Score float64 `json:"score"`
+}
+
+// Cluster is a group of likely duplicates. Issues are oldest first; the
+// oldest is suggested as the one to keep.
+type Cluster struct {
+	Score  float64  `json:"score"` // the highest pair score
+	Keep   string   `json:"keep"`
+	Issues []Member `json:"issues"`
+	Pairs  []Pair   `json:"pairs"`
+}
+
+// Report lists the duplicate clusters among open issues, most similar
+// first
+type Report struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	Threshold   float64   `json:"threshold"`
+	Scanned     int       `json:"scanned"`
+	Clusters    []Cluster `json:"clusters"`
+}
+
+// terms is the word sets an issue is compared on
+type terms struct {
+	title map[string]bool
+	desc  map[string]bool
+}
+
+func termsOf(issue *models.Issue) terms {
+	return terms{title: words(issue.Title), desc: words(issue.Description)}
+}
+
+// words returns the significant lowercase words in s
+func words(s string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len(w) > 1 && !stopWords[w] {
+			set[w] = true
+		}
+	}
+	return set
+}
+
+// jaccard is the size of the intersection of two sets over their union
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for w := range a {
+		if b[w] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+func (t terms) similarity(o terms) float64 {
+	title := jaccard(t.title, o.title)
+	if len(t.desc) == 0 || len(o.desc) == 0 {
+		return title
+	}
+	return titleWeight*title + (1-titleWeight)*jaccard(t.desc, o.desc)
+}
+
+// Similarity scores how alike two issues are, from 0 to 1
+func Similarity(a, b *models.Issue) float64 {
+	return termsOf(a).similarity(termsOf(b))
+}
+
+// Scan clusters issues whose similarity reaches threshold. Only pairs
+// sharing a title word are compared.
+func Scan(issues []models.Issue, threshold float64, now time.Time) *Report {
+	if threshold <= 0 {
+		threshold = DefaultThreshold
+	}
+	report := &Report{GeneratedAt: now, Threshold: threshold, Scanned: len(issues), Clusters: []Cluster{}}
+
+	all := make([]terms, len(issues))
+	index := make(map[string][]int) // title word → issues using it
+	for i := range issues {
+		all[i] = termsOf(&issues[i])
+		for w := range all[i].title {
+			index[w] = append(index[w], i)
+		}
+	}
+
+	parent := make([]int, len(issues))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	type scored struct {
+		i, j  int
+		score float64
+	}
+	var pairs []scored
+	for i := range issues {
+		seen := make(map[int]bool)
+		for w := range all[i].title {
+			for _, j := range index[w] {
+				if j <= i || seen[j] {
+					continue
+				}
+				seen[j] = true
+				if s := all[i].similarity(all[j]); s >= threshold {
+					pairs = append(pairs, scored{i, j, s})
+					parent[find(i)] = find(j)
+				}
+			}
+		}
+	}
+
+	byRoot := make(map[int]*Cluster)
+	for _, p := range pairs {
+		root := find(p.i)
+		c := byRoot[root]
+		if c == nil {
+			c = &Cluster{}
+			byRoot[root] = c
+		}
+		c.Pairs = append(c.Pairs, Pair{A: issues[p.i].ID, B: issues[p.j].ID, Score: round(p.score)})
+		c.Score = max(c.Score, round(p.score))
+	}
+	for i := range issues {
+		if c := byRoot[find(i)]; c != nil {
+			is := &issues[i]
+			c.Issues = append(c.Issues, Member{ID: is.ID, Title: is.Title, Status: is.Status, Type: is.Type, Priority: is.Priority, Created: is.CreatedAt})
+		}
+	}
+
+	for _, c := range byRoot {
+		sort.SliceStable(c.Issues, func(i, j int) bool { return c.Issues[i].Created.Before(c.Issues[j].Created) })
+		sort.SliceStable(c.Pairs, func(i, j int) bool { return c.Pairs[i].Score > c.Pairs[j].Score })
+		c.Keep = c.Issues[0].ID
+		report.Clusters = append(report.Clusters, *c)
+	}
+	sort.SliceStable(report.Clusters, func(i, j int) bool {
+		if report.Clusters[i].Score != report.Clusters[j].Score {
+			return report.Clusters[i].Score > report.Clusters[j].Score
+		}
+		return report.Clusters[i].Keep < report.Clusters[j].Keep
+	})
+	return report
+}
+
+// Compute scans the project's open issues
+func Compute(database *db.DB, threshold float64, now time.Time) (*Report, error) {
+	issues, err := database.ListIssues(db.ListIssuesOptions{Status: openStatuses})
+	if err != nil {
+		return nil, err
+	}
+	return Scan(issues, threshold, now), nil
+}
+
+// round keeps scores to two decimals for reporting
+func round(f float64) float64 {
+	return float64(int(f*100+0.5)) / 100
+}
@@ -0,0 +1,119 @@
+package dedupe
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b models.Issue
+		want float64
+	}{
+		{"same words", models.Issue{Title: "Login page crashes on Safari"}, models.Issue{Title: "login PAGE crashes on safari!"}, 1},
+		{"stop words ignored", models.Issue{Title: "Crash in the parser"}, models.Issue{Title: "Crash with parser"}, 1},
+		{"disjoint", models.Issue{Title: "Add dark mode"}, models.Issue{Title: "Fix export bug"}, 0},
+		{"half", models.Issue{Title: "export csv"}, models.Issue{Title: "export json"}, 1.0 / 3},
+		{"description blended",
+			models.Issue{Title: "export csv", Description: "commas break rows"},
+			models.Issue{Title: "export csv", Description: "unrelated text"}, 0.7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Similarity(&tt.a, &tt.b); round(got) != round(tt.want) {
+				t.Errorf("Similarity = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScan(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	issues := []models.Issue{
+		{ID: "td-c", Title: "Login page crashes on Safari browser", CreatedAt: base.Add(2 * time.Hour)},
+		{ID: "td-a", Title: "Login page crashes on Safari", CreatedAt: base},
+		{ID: "td-b", Title: "Login page crashes Safari browser", CreatedAt: base.Add(time.Hour)},
+		{ID: "td-d", Title: "Add dark mode toggle", CreatedAt: base},
+		{ID: "td-e", Title: "Add dark mode toggle setting", CreatedAt: base.Add(time.Hour)},
+		{ID: "td-f", Title: "Unrelated refactor of storage", CreatedAt: base},
+	}
+	r := Scan(issues, DefaultThreshold, base)
+
+	if r.Scanned != 6 || len(r.Clusters) != 2 {
+		t.Fatalf("Scan = %+v", r)
+	}
+	first := r.Clusters[0]
+	if first.Keep != "td-a" || len(first.Issues) != 3 || first.Issues[2].ID != "td-c" {
+		t.Errorf("first cluster = %+v", first)
+	}
+	if r.Clusters[1].Keep != "td-d" || len(r.Clusters[1].Issues) != 2 {
+		t.Errorf("second cluster = %+v", r.Clusters[1])
+	}
+	for _, c := range r.Clusters {
+		for _, p := range c.Pairs {
+			if p.Score < DefaultThreshold || p.Score > c.Score {
+				t.Errorf("pair %+v out of range for cluster score %v", p, c.Score)
+			}
+		}
+	}
+
+	// Only td-b and td-c are identical once stop words are dropped
+	if r := Scan(issues, 0.95, base); len(r.Clusters) != 1 || r.Clusters[0].Keep != "td-b" {
+		t.Errorf("strict threshold clusters = %+v", r.Clusters)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	database, err := db.Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	defer database.Close()
+
+	create := func(issue *models.Issue) *models.Issue {
+		t.Helper()
+		if err := database.CreateIssueLogged(issue, "ses_a"); err != nil {
+			t.Fatalf("CreateIssueLogged: %v", err)
+		}
+		return issue
+	}
+	keep := create(&models.Issue{Title: "Login crashes", Labels: []string{"auth"}})
+	dup := create(&models.Issue{Title: "Login crash", Labels: []string{"auth", "safari"}})
+	child := create(&models.Issue{Title: "Repro steps", ParentID: dup.ID})
+	blocked := create(&models.Issue{Title: "Release"})
+	if err := database.AddDependencyLogged(blocked.ID, dup.ID, "depends_on", "ses_a"); err != nil {
+		t.Fatalf("AddDependencyLogged: %v", err)
+	}
+
+	res, err := Merge(database, keep.ID, []string{dup.ID}, "ses_b")
+	if err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if len(res.Closed) != 1 || len(res.Labels) != 1 || res.Labels[0] != "safari" || len(res.Children) != 1 || res.Dependencies != 1 {
+		t.Errorf("Merge = %+v", res)
+	}
+
+	if got, _ := database.GetIssue(dup.ID); got.Status != models.StatusClosed {
+		t.Errorf("duplicate status = %s", got.Status)
+	}
+	if got, _ := database.GetIssue(child.ID); got.ParentID != keep.ID {
+		t.Errorf("child parent = %s", got.ParentID)
+	}
+	if deps, _ := database.GetDependencies(blocked.ID); len(deps) != 1 || deps[0] != keep.ID {
+		t.Errorf("blocked deps = %v", deps)
+	}
+	if got, _ := database.GetIssue(keep.ID); len(got.Labels) != 2 {
+		t.Errorf("keep labels = %v", got.Labels)
+	}
+
+	if _, err := Merge(database, keep.ID, []string{keep.ID}, "ses_b"); err == nil {
+		t.Error("merging an issue into itself succeeded")
+	}
+	if _, err := Merge(database, keep.ID, []string{dup.ID}, "ses_b"); err == nil {
+		t.Error("merging a closed duplicate succeeded")
+	}
+}
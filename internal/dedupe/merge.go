@@ -0,0 +1,155 @@
+package dedupe
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+// MergeResult describes what a merge moved onto the kept issue
+type MergeResult struct {
+	Keep         string   `json:"keep"`
+	Closed       []string `json:"closed"`
+	Labels       []string `json:"labels_added,omitempty"`
+	Children     []string `json:"children_moved,omitempty"`
+	Dependencies int      `json:"dependencies_moved"`
+}
+
+// Merge folds duplicates into keep: their labels, children and
+// dependencies move to keep, and each duplicate is closed with a log
+// pointing at keep. Every change is logged, so it syncs and can be undone
+// action by action.
+func Merge(database *db.DB, keepID string, dupIDs []string, sessionID string) (*MergeResult, error) {
+	keep, err := database.GetIssue(keepID)
+	if err != nil {
+		return nil, err
+	}
+	var dups []*models.Issue
+	for _, id := range dupIDs {
+		if id == keepID {
+			return nil, fmt.Errorf("cannot merge %s into itself", id)
+		}
+		dup, err := database.GetIssue(id)
+		if err != nil {
+			return nil, err
+		}
+		if dup.Status == models.StatusClosed {
+			return nil, fmt.Errorf("%s is already closed", id)
+		}
+		dups = append(dups, dup)
+	}
+	if len(dups) == 0 {
+		return nil, fmt.Errorf("no duplicates given")
+	}
+
+	result := &MergeResult{Keep: keep.ID, Closed: []string{}}
+
+	// Labels
+	has := make(map[string]bool, len(keep.Labels))
+	for _, l := range keep.Labels {
+		has[l] = true
+	}
+	for _, dup := range dups {
+		for _, l := range dup.Labels {
+			if !has[l] {
+				has[l] = true
+				result.Labels = append(result.Labels, l)
+			}
+		}
+	}
+	if len(result.Labels) > 0 {
+		keep.Labels = append(keep.Labels, result.Labels...)
+		if err := database.UpdateIssueLogged(keep, sessionID, models.ActionUpdate); err != nil {
+			return nil, fmt.Errorf("update %s: %w", keep.ID, err)
+		}
+	}
+
+	for _, dup := range dups {
+		children, err := database.GetDirectChildren(dup.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, child := range children {
+			if child.ID == keep.ID {
+				continue
+			}
+			child.ParentID = keep.ID
+			if err := database.UpdateIssueLogged(child, sessionID, models.ActionUpdate); err != nil {
+				return nil, fmt.Errorf("move %s: %w", child.ID, err)
+			}
+			result.Children = append(result.Children, child.ID)
+		}
+
+		n, err := moveDependencies(database, dup.ID, keep.ID, sessionID)
+		if err != nil {
+			return nil, err
+		}
+		result.Dependencies += n
+
+		now := time.Now()
+		dup.Status = models.StatusClosed
+		dup.ClosedAt = &now
+		if err := database.UpdateIssueLogged(dup, sessionID, models.ActionClose); err != nil {
+			return nil, fmt.Errorf("close %s: %w", dup.ID, err)
+		}
+		if err := database.AddLog(&models.Log{
+			IssueID:   dup.ID,
+			SessionID: sessionID,
+			Message:   "Closed: duplicate of " + keep.ID,
+			Type:      models.LogTypeProgress,
+		}); err != nil {
+			return nil, err
+		}
+		result.Closed = append(result.Closed, dup.ID)
+	}
+
+	if err := database.AddComment(&models.Comment{
+		IssueID:   keep.ID,
+		SessionID: sessionID,
+		Text:      "Merged duplicates: " + strings.Join(result.Closed, ", "),
+	}); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// moveDependencies repoints dup's dependencies in both directions at keep,
+// dropping any that would make keep depend on itself
+func moveDependencies(database *db.DB, dupID, keepID, sessionID string) (int, error) {
+	moved := 0
+	deps, err := database.GetDependencies(dupID)
+	if err != nil {
+		return 0, err
+	}
+	for _, on := range deps {
+		if err := database.RemoveDependencyLogged(dupID, on, sessionID); err != nil {
+			return moved, err
+		}
+		if on != keepID {
+			if err := database.AddDependencyLogged(keepID, on, "depends_on", sessionID); err != nil {
+				return moved, err
+			}
+		}
+		moved++
+	}
+
+	blocked, err := database.GetBlockedBy(dupID)
+	if err != nil {
+		return moved, err
+	}
+	for _, issueID := range blocked {
+		if err := database.RemoveDependencyLogged(issueID, dupID, sessionID); err != nil {
+			return moved, err
+		}
+		if issueID != keepID {
+			if err := database.AddDependencyLogged(issueID, keepID, "depends_on", sessionID); err != nil {
+				return moved, err
+			}
+		}
+		moved++
+	}
+	return moved, nil
+}
@@ -0,0 +1,303 @@
+// Package delivery sends webhooks, chat channel posts and notifications
+// through an outbox, so a crash or an unreachable endpoint delays them
+// rather than losing them.
+//
+// Every action_log row is recorded in delivery_events by a trigger, in
+// the same statement that writes it. FanOut turns those events into
+// deliveries, one per configured target: the project webhook, each
+// subscription webhook whose filter matches, and each channel integration.
+// Notifications for routes that reach over the network are queued as
+// deliveries by NotifyQueue. Deliver then sends what is due, retrying
+// failures with backoff until MaxAttempts.
+//
+// td serve runs FanOut and Deliver as its deliveries job; td commands run
+// them in a detached process after each command.
+package delivery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/integrations"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/notify"
+	"github.com/marcus/td/internal/subscription"
+	"github.com/marcus/td/internal/webhook"
+)
+
+// Delivery kinds
+const (
+	KindWebhook      = "webhook"      // the project webhook
+	KindSubscription = "subscription" // a subscription webhook; Ref is its ID
+	KindIntegration  = "integration"  // a chat channel post; Ref is the integration's name
+	KindNotify       = "notify"       // a notification; Ref is the route ID
+)
+
+// MaxAttempts is how many times a delivery is tried before it fails
+const MaxAttempts = 8
+
+// Retention of finished deliveries, for td webhook deliveries to show
+const (
+	KeepDelivered = 7 * 24 * time.Hour
+	KeepFailed    = 30 * 24 * time.Hour
+)
+
+// lease is how long a claimed delivery is held by the process sending it
+const lease = 2 * time.Minute
+
+// eventBatch is how many change events one fan-out step reads
+const eventBatch = 500
+
+// ErrGone is returned for a delivery whose target was removed from the
+// config since it was queued. It fails at once, without retries.
+var ErrGone = errors.New("target is no longer configured")
+
+// Backoff returns how long to wait after the nth failed attempt: 30s, 2m,
+// 8m, 32m, then doubling, at most 6h
+func Backoff(attempt int) time.Duration {
+	d := 30 * time.Second
+	for i := 1; i < attempt; i++ {
+		if i < 4 {
+			d *= 4
+		} else {
+			d *= 2
+		}
+		if d >= 6*time.Hour {
+			return 6 * time.Hour
+		}
+	}
+	return d
+}
+
+// Configured reports whether the project has anywhere to send changes: a
+// webhook, a subscription webhook or a channel integration
+func Configured(baseDir string) bool {
+	return webhook.IsEnabled(baseDir) || len(subscription.Webhooks(baseDir)) > 0 ||
+		len(integrations.OutgoingTargets(baseDir)) > 0
+}
+
+// Result counts what a pass did
+type Result struct {
+	Queued    int `json:"queued"`    // deliveries created by fan-out
+	Delivered int `json:"delivered"` // sent and accepted
+	Retrying  int `json:"retrying"`  // failed, to be tried again
+	Failed    int `json:"failed"`    // failed for good
+	Pruned    int `json:"pruned"`    // old finished deliveries removed
+}
+
+// Run fans out new changes, sends everything due and prunes old finished
+// deliveries
+func Run(ctx context.Context, database *db.DB, baseDir string) (Result, error) {
+	var res Result
+	queued, err := FanOut(database, baseDir)
+	res.Queued = queued
+	if err != nil {
+		return res, err
+	}
+	sent, err := Deliver(ctx, database, baseDir)
+	res.Delivered, res.Retrying, res.Failed = sent.Delivered, sent.Retrying, sent.Failed
+	if err != nil {
+		return res, err
+	}
+	now := clock.Now()
+	pruned, err := database.PruneDeliveries(now.Add(-KeepDelivered), now.Add(-KeepFailed))
+	res.Pruned = int(pruned)
+	return res, err
+}
+
+// FanOut turns the pending change events into deliveries for the targets
+// configured now and returns how many it queued. Events are cleared in the
+// same transaction that stores their deliveries; with no targets they are
+// just cleared.
+func FanOut(database *db.DB, baseDir string) (int, error) {
+	queued := 0
+	for {
+		actions, through, err := database.PendingDeliveryEvents(eventBatch)
+		if err != nil {
+			return queued, fmt.Errorf("read delivery events: %w", err)
+		}
+		if through == 0 {
+			return queued, nil
+		}
+		deliveries := deliveriesFor(database, baseDir, actions)
+		if err := database.EnqueueDeliveries(through, deliveries); err != nil {
+			return queued, fmt.Errorf("queue deliveries: %w", err)
+		}
+		queued += len(deliveries)
+	}
+}
+
+// deliveriesFor builds the deliveries of a batch of changes
+func deliveriesFor(database *db.DB, baseDir string, actions []models.ActionLog) []models.Delivery {
+	if len(actions) == 0 {
+		return nil
+	}
+	var deliveries []models.Delivery
+	payload := webhook.BuildPayload(baseDir, actions)
+
+	if url := webhook.GetURL(baseDir); url != "" {
+		data, _ := json.Marshal(payload)
+		deliveries = append(deliveries, models.Delivery{Kind: KindWebhook, Target: url, Payload: string(data)})
+	}
+
+	for _, sub := range subscription.Webhooks(baseDir) {
+		filter, err := subscription.Query(sub)
+		if err != nil {
+			slog.Debug("delivery: subscription filter", "id", sub.ID, "err", err)
+			continue
+		}
+		matched, err := subscription.FilterActions(database, filter, actions)
+		if err != nil {
+			slog.Debug("delivery: match subscription", "id", sub.ID, "err", err)
+			continue
+		}
+		if len(matched) == 0 {
+			continue
+		}
+		data, _ := json.Marshal(webhook.BuildPayload(baseDir, matched))
+		deliveries = append(deliveries, models.Delivery{Kind: KindSubscription, Ref: sub.ID, Target: sub.URL, Payload: string(data)})
+	}
+
+	for _, t := range integrations.OutgoingTargets(baseDir) {
+		text := integrations.FormatActions(payload.Actions, t.Events)
+		if text == "" {
+			continue
+		}
+		deliveries = append(deliveries, models.Delivery{Kind: KindIntegration, Ref: t.Name, Target: t.Kind + " " + t.Name, Payload: text})
+	}
+	return deliveries
+}
+
+// Deliver sends the deliveries that are due until none are left or ctx
+// is done. Each is claimed first, so processes delivering side by side
+// don't send one twice.
+func Deliver(ctx context.Context, database *db.DB, baseDir string) (Result, error) {
+	var res Result
+	for ctx.Err() == nil {
+		due, err := database.DueDeliveries(clock.Now(), 50)
+		if err != nil {
+			return res, err
+		}
+		progress := false
+		for _, d := range due {
+			if ctx.Err() != nil {
+				break
+			}
+			now := clock.Now()
+			claimed, err := database.ClaimDelivery(d.ID, now, now.Add(lease))
+			if err != nil {
+				return res, err
+			}
+			if !claimed {
+				continue
+			}
+			progress = true
+			d.Attempts++
+
+			sendErr := Send(database, baseDir, d)
+			var next *time.Time
+			switch {
+			case sendErr == nil:
+				res.Delivered++
+			case errors.Is(sendErr, ErrGone) || d.Attempts >= MaxAttempts:
+				res.Failed++
+			default:
+				t := clock.Now().Add(Backoff(d.Attempts))
+				next = &t
+				res.Retrying++
+			}
+			if sendErr != nil {
+				slog.Debug("delivery failed", "id", d.ID, "kind", d.Kind, "attempt", d.Attempts, "err", sendErr)
+			}
+			if err := database.FinishDelivery(d.ID, sendErr, next); err != nil {
+				return res, err
+			}
+		}
+		if !progress {
+			break
+		}
+	}
+	return res, ctx.Err()
+}
+
+// Send makes one attempt at a delivery. URLs and secrets are read from the
+// current config, so a changed secret applies to queued deliveries too.
+func Send(database *db.DB, baseDir string, d models.Delivery) error {
+	switch d.Kind {
+	case KindWebhook:
+		url := webhook.GetURL(baseDir)
+		if url == "" {
+			return fmt.Errorf("webhook: %w", ErrGone)
+		}
+		var p webhook.Payload
+		if err := json.Unmarshal([]byte(d.Payload), &p); err != nil {
+			return fmt.Errorf("%w: bad payload: %v", ErrGone, err)
+		}
+		return webhook.DispatchDelivery(url, webhook.GetSecret(baseDir), d.ID, p)
+
+	case KindSubscription:
+		var p webhook.Payload
+		if err := json.Unmarshal([]byte(d.Payload), &p); err != nil {
+			return fmt.Errorf("%w: bad payload: %v", ErrGone, err)
+		}
+		for _, sub := range subscription.Webhooks(baseDir) {
+			if sub.ID == d.Ref {
+				return webhook.DispatchDelivery(sub.URL, sub.Secret, d.ID, p)
+			}
+		}
+		return fmt.Errorf("subscription %s: %w", d.Ref, ErrGone)
+
+	case KindIntegration:
+		for _, t := range integrations.OutgoingTargets(baseDir) {
+			if t.Name == d.Ref {
+				return integrations.Post(t.Kind, t.URL, d.Payload)
+			}
+		}
+		return fmt.Errorf("integration %s: %w", d.Ref, ErrGone)
+
+	case KindNotify:
+		routes, err := database.ListNotifyRoutes()
+		if err != nil {
+			return err
+		}
+		for _, r := range routes {
+			if r.ID != d.Ref {
+				continue
+			}
+			t, err := notify.NewTransport(r.Transport, r.Target)
+			if err != nil {
+				return fmt.Errorf("%w: %v", ErrGone, err)
+			}
+			var msg notify.Message
+			if err := json.Unmarshal([]byte(d.Payload), &msg); err != nil {
+				return fmt.Errorf("%w: bad payload: %v", ErrGone, err)
+			}
+			return t.Send(msg)
+		}
+		return fmt.Errorf("notify route %s: %w", d.Ref, ErrGone)
+	}
+	return fmt.Errorf("unknown delivery kind %q: %w", d.Kind, ErrGone)
+}
+
+// NotifyQueue returns a notify.Queue that puts messages for network routes
+// in the outbox. Set it on a Dispatcher and run Deliver afterwards.
+func NotifyQueue(database *db.DB) notify.Queue {
+	return func(route models.NotifyRoute, msg notify.Message) error {
+		data, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		return database.AddDelivery(&models.Delivery{
+			Kind:    KindNotify,
+			Ref:     route.ID,
+			Target:  route.Transport,
+			Payload: string(data),
+		})
+	}
+}
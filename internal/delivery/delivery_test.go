@@ -0,0 +1,237 @@
+package delivery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/notify"
+	"github.com/marcus/td/internal/webhook"
+)
+
+// receiver is a webhook endpoint that fails while down is set
+type receiver struct {
+	mu       sync.Mutex
+	down     bool
+	payloads []webhook.Payload
+	ids      []string
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if rc.down {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	var p webhook.Payload
+	_ = json.NewDecoder(r.Body).Decode(&p)
+	rc.payloads = append(rc.payloads, p)
+	rc.ids = append(rc.ids, r.Header.Get("X-TD-Delivery"))
+}
+
+func setup(t *testing.T) (*db.DB, string, *receiver, *clock.Fake) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	rc := &receiver{}
+	srv := httptest.NewServer(rc)
+	t.Cleanup(srv.Close)
+	t.Setenv("TD_WEBHOOK_URL", srv.URL)
+	t.Setenv("TD_WEBHOOK_SECRET", "")
+
+	fake := clock.NewFake(time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC))
+	t.Cleanup(clock.Set(fake.Now))
+
+	dir := t.TempDir()
+	database, err := db.Initialize(dir)
+	if err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	return database, dir, rc, fake
+}
+
+func TestBackoff(t *testing.T) {
+	want := []time.Duration{30 * time.Second, 2 * time.Minute, 8 * time.Minute, 32 * time.Minute,
+		64 * time.Minute, 128 * time.Minute, 256 * time.Minute, 6 * time.Hour, 6 * time.Hour}
+	for i, w := range want {
+		if got := Backoff(i + 1); got != w {
+			t.Errorf("Backoff(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+}
+
+func TestRunDeliversChanges(t *testing.T) {
+	database, dir, rc, _ := setup(t)
+
+	issue := &models.Issue{Title: "Outbox me"}
+	if err := database.CreateIssueLogged(issue, "ses_a"); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Run(context.Background(), database, dir)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if res.Queued != 1 || res.Delivered != 1 {
+		t.Fatalf("result = %+v", res)
+	}
+	if len(rc.payloads) != 1 || len(rc.payloads[0].Actions) != 1 || rc.payloads[0].Actions[0].EntityID != issue.ID {
+		t.Fatalf("payloads = %+v", rc.payloads)
+	}
+
+	list, _ := database.ListDeliveries("", 0)
+	if len(list) != 1 || list[0].Status != models.DeliveryDelivered || rc.ids[0] != list[0].ID {
+		t.Fatalf("deliveries = %+v, header ids = %v", list, rc.ids)
+	}
+
+	// The change is handed out once
+	res, _ = Run(context.Background(), database, dir)
+	if res.Queued != 0 || res.Delivered != 0 || len(rc.payloads) != 1 {
+		t.Errorf("second run = %+v, payloads = %d", res, len(rc.payloads))
+	}
+}
+
+func TestDeliverRetriesWithBackoff(t *testing.T) {
+	database, dir, rc, fake := setup(t)
+	rc.down = true
+
+	if err := database.CreateIssueLogged(&models.Issue{Title: "Endpoint is down"}, "ses_a"); err != nil {
+		t.Fatal(err)
+	}
+	res, _ := Run(context.Background(), database, dir)
+	if res.Retrying != 1 {
+		t.Fatalf("result = %+v", res)
+	}
+	list, _ := database.ListDeliveries("", 0)
+	d := list[0]
+	if d.Status != models.DeliveryPending || d.Attempts != 1 || d.LastError == "" ||
+		!d.NextAttemptAt.Equal(fake.Now().Add(Backoff(1))) {
+		t.Fatalf("after failure = %+v", d)
+	}
+
+	// Not due yet
+	if res, _ := Deliver(context.Background(), database, dir); res != (Result{}) {
+		t.Errorf("early deliver = %+v", res)
+	}
+
+	rc.down = false
+	fake.Advance(Backoff(1))
+	if res, _ := Deliver(context.Background(), database, dir); res.Delivered != 1 {
+		t.Fatalf("retry = %+v", res)
+	}
+	got, _ := database.GetDelivery(d.ID)
+	if got.Status != models.DeliveryDelivered || got.Attempts != 2 || got.DeliveredAt == nil {
+		t.Errorf("after retry = %+v", got)
+	}
+}
+
+func TestDeliverGivesUp(t *testing.T) {
+	database, dir, rc, fake := setup(t)
+	rc.down = true
+
+	if err := database.CreateIssueLogged(&models.Issue{Title: "Never reachable"}, "ses_a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FanOut(database, dir); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= MaxAttempts; i++ {
+		if _, err := Deliver(context.Background(), database, dir); err != nil {
+			t.Fatal(err)
+		}
+		fake.Advance(Backoff(i))
+	}
+	list, _ := database.ListDeliveries(models.DeliveryFailed, 0)
+	if len(list) != 1 || list[0].Attempts != MaxAttempts {
+		t.Fatalf("failed = %+v", list)
+	}
+
+	d, err := database.RetryDelivery(list[0].ID)
+	if err != nil || d.Status != models.DeliveryPending || d.Attempts != 0 {
+		t.Fatalf("RetryDelivery = %+v, %v", d, err)
+	}
+	rc.down = false
+	if res, _ := Deliver(context.Background(), database, dir); res.Delivered != 1 {
+		t.Errorf("after manual retry = %+v", res)
+	}
+	if _, err := database.RetryDelivery(d.ID); err == nil {
+		t.Error("retrying a delivered delivery succeeded")
+	}
+}
+
+func TestSendRemovedTarget(t *testing.T) {
+	database, dir, _, _ := setup(t)
+
+	if err := database.CreateIssueLogged(&models.Issue{Title: "Webhook removed"}, "ses_a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FanOut(database, dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TD_WEBHOOK_URL", "")
+
+	res, _ := Deliver(context.Background(), database, dir)
+	if res.Failed != 1 {
+		t.Fatalf("result = %+v", res)
+	}
+	list, _ := database.ListDeliveries(models.DeliveryFailed, 0)
+	if len(list) != 1 || list[0].Attempts != 1 {
+		t.Errorf("failed = %+v", list)
+	}
+
+	err := Send(database, dir, models.Delivery{Kind: KindIntegration, Ref: "gone"})
+	if !errors.Is(err, ErrGone) {
+		t.Errorf("Send to a removed integration = %v", err)
+	}
+}
+
+func TestFanOutWithoutTargets(t *testing.T) {
+	database, dir, _, _ := setup(t)
+	t.Setenv("TD_WEBHOOK_URL", "")
+
+	if err := database.CreateIssueLogged(&models.Issue{Title: "Nobody listens"}, "ses_a"); err != nil {
+		t.Fatal(err)
+	}
+	if Configured(dir) {
+		t.Fatal("Configured with no targets")
+	}
+	if n, err := FanOut(database, dir); err != nil || n != 0 {
+		t.Fatalf("FanOut = %d, %v", n, err)
+	}
+	if _, through, _ := database.PendingDeliveryEvents(10); through != 0 {
+		t.Errorf("events left after fan-out: through = %d", through)
+	}
+}
+
+func TestNotifyQueue(t *testing.T) {
+	database, _, _, _ := setup(t)
+
+	route := models.NotifyRoute{ID: "nr-1", Event: notify.AnyEvent, Transport: "webhook", Target: "http://127.0.0.1:1/hook"}
+	d, err := notify.NewDispatcher([]models.NotifyRoute{route})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Queue = NotifyQueue(database)
+	msg := notify.Message{Event: "review", Title: "td-1 needs review", Time: clock.Now()}
+	if err := d.Send(msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	list, _ := database.ListDeliveries(models.DeliveryPending, 0)
+	if len(list) != 1 || list[0].Kind != KindNotify || list[0].Ref != route.ID {
+		t.Fatalf("queued = %+v", list)
+	}
+	var queued notify.Message
+	if err := json.Unmarshal([]byte(list[0].Payload), &queued); err != nil || queued.Title != msg.Title {
+		t.Errorf("payload = %s (%v)", list[0].Payload, err)
+	}
+}
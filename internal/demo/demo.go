@@ -0,0 +1,376 @@
+// Package demo seeds a project with realistic sample data: a small web shop
+// with epics, bugs, dependencies, reviews in flight, boards and a few agent
+// and human sessions. The data set is fixed so a bug report can say "run td
+// init --demo, then ..." and everyone starts from the same state.
+package demo
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/session"
+)
+
+// Session IDs used by the sample data
+const (
+	SessionMaya     = "ses_demo01" // human lead who creates and reviews work
+	SessionFrontend = "ses_demo02" // frontend agent
+	SessionBackend  = "ses_demo03" // backend agent
+)
+
+// Result counts what Seed created
+type Result struct {
+	Sessions int
+	Issues   int
+	Boards   int
+}
+
+type sampleSession struct {
+	id, name, agent string
+}
+
+var sessions = []sampleSession{
+	{SessionMaya, "maya", ""},
+	{SessionFrontend, "agent-frontend", string(session.AgentCursor)},
+	{SessionBackend, "agent-backend", string(session.AgentClaudeCode)},
+}
+
+// sampleIssue describes one issue. key names it for parent and dependency
+// references; those are resolved to real IDs as issues are created.
+type sampleIssue struct {
+	key         string
+	parent      string
+	title       string
+	description string
+	typ         models.Type
+	priority    models.Priority
+	points      int
+	labels      []string
+	status      models.Status
+	implementer string
+	dependsOn   []string
+	deferDays   int
+	dueDays     int
+	minor       bool
+	logs        []sampleLog
+	comments    []sampleComment
+	handoff     *models.Handoff
+}
+
+type sampleLog struct {
+	session string
+	typ     models.LogType
+	message string
+}
+
+type sampleComment struct {
+	session string
+	text    string
+}
+
+var issues = []sampleIssue{
+	{
+		key:         "checkout",
+		title:       "Checkout redesign",
+		description: "Rework the checkout flow to cut drop-off: fewer fields, saved carts, and a mobile-friendly summary.",
+		typ:         models.TypeEpic,
+		priority:    models.PriorityP1,
+		labels:      []string{"checkout"},
+		status:      models.StatusInProgress,
+		implementer: SessionFrontend,
+	},
+	{
+		key:         "autocomplete",
+		parent:      "checkout",
+		title:       "Add address autocomplete to checkout form",
+		description: "Use the maps places API to suggest addresses as the customer types. Fall back to manual entry when the API is unavailable.",
+		typ:         models.TypeTask,
+		priority:    models.PriorityP2,
+		points:      3,
+		labels:      []string{"checkout", "frontend"},
+		status:      models.StatusInProgress,
+		implementer: SessionFrontend,
+		logs: []sampleLog{
+			{SessionFrontend, models.LogTypeProgress, "Wired the places API into the address field behind a debounce"},
+			{SessionFrontend, models.LogTypeDecision, "Suggestions are limited to the shipping countries we support"},
+		},
+		handoff: &models.Handoff{
+			SessionID: SessionFrontend,
+			Done:      []string{"Autocomplete dropdown renders suggestions", "Debounced requests to 250ms"},
+			Remaining: []string{"Keyboard navigation in the dropdown", "Manual entry fallback when the API errors"},
+			Uncertain: []string{"Whether PO boxes should be filtered out"},
+		},
+	},
+	{
+		key:         "cart",
+		parent:      "checkout",
+		title:       "Persist cart across sessions for signed-in users",
+		description: "Store carts server-side for signed-in users so they survive logouts and device switches. Merge the anonymous cart on sign-in.",
+		typ:         models.TypeFeature,
+		priority:    models.PriorityP1,
+		points:      5,
+		labels:      []string{"checkout", "backend"},
+		status:      models.StatusInReview,
+		implementer: SessionBackend,
+		logs: []sampleLog{
+			{SessionBackend, models.LogTypeProgress, "Added carts table and merge-on-login logic"},
+			{SessionBackend, models.LogTypeResult, "Cart merge covered by tests for empty, disjoint and overlapping carts"},
+		},
+		comments: []sampleComment{
+			{SessionMaya, "What happens to quantities when both carts contain the same item?"},
+			{SessionBackend, "They are summed, capped at the per-item stock limit."},
+		},
+	},
+	{
+		key:         "summary",
+		parent:      "checkout",
+		title:       "Show order summary sidebar on mobile",
+		description: "On narrow screens the summary is hidden below the fold. Make it a collapsible sticky header.",
+		typ:         models.TypeTask,
+		priority:    models.PriorityP3,
+		points:      2,
+		labels:      []string{"checkout", "frontend"},
+		status:      models.StatusOpen,
+	},
+	{
+		key:         "e2e",
+		parent:      "checkout",
+		title:       "Write end-to-end tests for the new checkout flow",
+		description: "Cover guest checkout, signed-in checkout with a saved cart, and address autocomplete.",
+		typ:         models.TypeTask,
+		priority:    models.PriorityP2,
+		points:      3,
+		labels:      []string{"checkout", "testing"},
+		status:      models.StatusOpen,
+		dependsOn:   []string{"autocomplete", "cart"},
+	},
+	{
+		key:         "payments",
+		title:       "Payments reliability",
+		description: "Eliminate duplicate charges and duplicate notifications caused by webhook retries.",
+		typ:         models.TypeEpic,
+		priority:    models.PriorityP0,
+		labels:      []string{"payments"},
+		status:      models.StatusInProgress,
+		implementer: SessionBackend,
+	},
+	{
+		key:         "webhooks",
+		parent:      "payments",
+		title:       "Stripe webhook retries create duplicate orders",
+		description: "When our webhook handler times out, Stripe retries and we create a second order for the same payment intent.",
+		typ:         models.TypeBug,
+		priority:    models.PriorityP0,
+		points:      5,
+		labels:      []string{"payments", "backend"},
+		status:      models.StatusInProgress,
+		implementer: SessionBackend,
+		dependsOn:   []string{"idempotency"},
+		logs: []sampleLog{
+			{SessionBackend, models.LogTypeHypothesis, "The handler does order creation before acknowledging, so slow inventory checks trigger retries"},
+			{SessionBackend, models.LogTypeTried, "Raising the handler timeout only made the duplicates rarer"},
+			{SessionBackend, models.LogTypeBlocker, "Need the idempotency key from the payment intent to dedupe safely"},
+		},
+	},
+	{
+		key:         "refunds",
+		parent:      "payments",
+		title:       "Refund emails sent twice after partial refund",
+		description: "Partial refunds trigger both charge.refunded and charge.refund.updated, and we email on both.",
+		typ:         models.TypeBug,
+		priority:    models.PriorityP1,
+		points:      2,
+		labels:      []string{"payments", "email"},
+		status:      models.StatusBlocked,
+		dependsOn:   []string{"webhooks"},
+	},
+	{
+		key:         "idempotency",
+		parent:      "payments",
+		title:       "Add idempotency keys to payment intents",
+		description: "Send an idempotency key derived from the cart ID with every payment intent request.",
+		typ:         models.TypeTask,
+		priority:    models.PriorityP1,
+		points:      3,
+		labels:      []string{"payments", "backend"},
+		status:      models.StatusClosed,
+		implementer: SessionBackend,
+		logs: []sampleLog{
+			{SessionBackend, models.LogTypeProgress, "Idempotency keys sent on create and confirm calls"},
+		},
+	},
+	{
+		key:         "retina",
+		title:       "Product images blurry on retina displays",
+		description: "Product cards load the 1x image regardless of device pixel ratio. Use srcset.",
+		typ:         models.TypeBug,
+		priority:    models.PriorityP3,
+		points:      1,
+		labels:      []string{"frontend"},
+		status:      models.StatusOpen,
+	},
+	{
+		key:         "diacritics",
+		title:       "Search results ignore diacritics",
+		description: "Searching for \"creme\" does not find \"Crème brûlée cookbook\". Normalize both the index and the query.",
+		typ:         models.TypeBug,
+		priority:    models.PriorityP2,
+		points:      2,
+		labels:      []string{"search", "backend"},
+		status:      models.StatusOpen,
+	},
+	{
+		key:         "wishlist",
+		title:       "Wishlist sharing via public link",
+		description: "Let customers share a read-only wishlist. Deferred until checkout work lands.",
+		typ:         models.TypeFeature,
+		priority:    models.PriorityP3,
+		points:      5,
+		labels:      []string{"frontend", "backend"},
+		status:      models.StatusOpen,
+		deferDays:   14,
+	},
+	{
+		key:         "ci",
+		title:       "Upgrade Go toolchain in CI images",
+		description: "CI still builds with an old Go release; bump the base image and fix any new vet findings.",
+		typ:         models.TypeChore,
+		priority:    models.PriorityP2,
+		points:      1,
+		labels:      []string{"ci"},
+		status:      models.StatusOpen,
+		dueDays:     5,
+	},
+	{
+		key:         "docs",
+		title:       "Document local development setup",
+		description: "README steps for running the shop, the database and a Stripe CLI webhook forwarder locally.",
+		typ:         models.TypeTask,
+		priority:    models.PriorityP4,
+		points:      1,
+		labels:      []string{"docs"},
+		status:      models.StatusClosed,
+		implementer: SessionMaya,
+		minor:       true,
+	},
+}
+
+var boards = []struct{ name, query string }{
+	{"Checkout", "labels ~ checkout"},
+	{"Payments", "labels ~ payments"},
+	{"Bugs", "type = bug"},
+}
+
+// Seed creates the sample sessions, issues and boards. Writes go through
+// the logged DB methods, so the data syncs and shows in history like work
+// done through the CLI.
+func Seed(database *db.DB) (*Result, error) {
+	now := clock.Now()
+	res := &Result{}
+
+	for _, s := range sessions {
+		if err := database.UpsertSession(&db.SessionRow{
+			ID:           s.id,
+			Name:         s.name,
+			Branch:       "main",
+			AgentType:    s.agent,
+			StartedAt:    now,
+			LastActivity: now,
+		}); err != nil {
+			return res, fmt.Errorf("create session %s: %w", s.name, err)
+		}
+		res.Sessions++
+	}
+
+	ids := make(map[string]string, len(issues))
+	for _, si := range issues {
+		issue := &models.Issue{
+			Title:          si.title,
+			Description:    si.description,
+			Type:           si.typ,
+			Priority:       si.priority,
+			Points:         si.points,
+			Labels:         si.labels,
+			ParentID:       ids[si.parent],
+			Minor:          si.minor,
+			CreatorSession: SessionMaya,
+		}
+		if si.deferDays > 0 {
+			d := now.AddDate(0, 0, si.deferDays).Format("2006-01-02")
+			issue.DeferUntil = &d
+		}
+		if si.dueDays > 0 {
+			d := now.AddDate(0, 0, si.dueDays).Format("2006-01-02")
+			issue.DueDate = &d
+		}
+		if err := database.CreateIssueLogged(issue, SessionMaya); err != nil {
+			return res, fmt.Errorf("create %q: %w", si.title, err)
+		}
+		ids[si.key] = issue.ID
+		res.Issues++
+		database.RecordSessionAction(issue.ID, SessionMaya, models.ActionSessionCreated)
+
+		if err := applyStatus(database, issue, si, now); err != nil {
+			return res, fmt.Errorf("update %q: %w", si.title, err)
+		}
+		for _, l := range si.logs {
+			database.AddLog(&models.Log{IssueID: issue.ID, SessionID: l.session, Type: l.typ, Message: l.message})
+		}
+		for _, c := range si.comments {
+			database.AddComment(&models.Comment{IssueID: issue.ID, SessionID: c.session, Text: c.text})
+		}
+		if si.handoff != nil {
+			h := *si.handoff
+			h.IssueID = issue.ID
+			database.AddHandoff(&h)
+		}
+	}
+
+	for _, si := range issues {
+		for _, dep := range si.dependsOn {
+			if err := database.AddDependencyLogged(ids[si.key], ids[dep], "depends_on", SessionMaya); err != nil {
+				return res, fmt.Errorf("add dependency %s -> %s: %w", si.key, dep, err)
+			}
+		}
+	}
+
+	for _, b := range boards {
+		if _, err := database.CreateBoardLogged(b.name, b.query, SessionMaya); err != nil {
+			return res, fmt.Errorf("create board %s: %w", b.name, err)
+		}
+		res.Boards++
+	}
+
+	return res, nil
+}
+
+// applyStatus moves a freshly created issue to its sample status the way
+// the workflow commands would: implementer set on start, reviewer on close.
+func applyStatus(database *db.DB, issue *models.Issue, si sampleIssue, now time.Time) error {
+	if si.status == models.StatusOpen {
+		return nil
+	}
+
+	action := models.ActionUpdate
+	issue.Status = si.status
+	issue.ImplementerSession = si.implementer
+	switch si.status {
+	case models.StatusInProgress:
+		action = models.ActionStart
+	case models.StatusInReview:
+		action = models.ActionReview
+	case models.StatusBlocked:
+		action = models.ActionBlock
+	case models.StatusClosed:
+		action = models.ActionApprove
+		issue.ReviewerSession = SessionMaya
+		issue.ClosedAt = &now
+	}
+	if si.implementer != "" {
+		database.RecordSessionAction(issue.ID, si.implementer, models.ActionSessionStarted)
+	}
+	return database.UpdateIssueLogged(issue, SessionMaya, action)
+}
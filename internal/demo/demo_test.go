@@ -0,0 +1,72 @@
+package demo
+
+import (
+	"testing"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+func TestSeed(t *testing.T) {
+	database, err := db.Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	defer database.Close()
+
+	res, err := Seed(database)
+	if err != nil {
+		t.Fatalf("Seed: %v", err)
+	}
+	if res.Sessions != len(sessions) || res.Issues != len(issues) || res.Boards != len(boards) {
+		t.Errorf("Seed result = %+v", res)
+	}
+
+	all, err := database.ListIssues(db.ListIssuesOptions{})
+	if err != nil {
+		t.Fatalf("ListIssues: %v", err)
+	}
+	byTitle := make(map[string]models.Issue, len(all))
+	for _, i := range all {
+		byTitle[i.Title] = i
+	}
+	if len(byTitle) != len(issues) {
+		t.Fatalf("got %d issues, want %d", len(byTitle), len(issues))
+	}
+
+	for _, si := range issues {
+		got := byTitle[si.title]
+		if got.Status != si.status {
+			t.Errorf("%s: status = %s, want %s", si.key, got.Status, si.status)
+		}
+		if si.parent != "" {
+			var parentTitle string
+			for _, p := range issues {
+				if p.key == si.parent {
+					parentTitle = p.title
+				}
+			}
+			if got.ParentID != byTitle[parentTitle].ID {
+				t.Errorf("%s: parent = %q, want %q", si.key, got.ParentID, byTitle[parentTitle].ID)
+			}
+		}
+	}
+
+	e2e := byTitle["Write end-to-end tests for the new checkout flow"]
+	deps, err := database.GetDependencies(e2e.ID)
+	if err != nil {
+		t.Fatalf("GetDependencies: %v", err)
+	}
+	if len(deps) != 2 {
+		t.Errorf("e2e dependencies = %v, want 2", deps)
+	}
+
+	for _, b := range boards {
+		if _, err := database.GetBoardByName(b.name); err != nil {
+			t.Errorf("board %s: %v", b.name, err)
+		}
+	}
+	if _, err := database.GetSessionByID(SessionBackend); err != nil {
+		t.Errorf("session %s: %v", SessionBackend, err)
+	}
+}
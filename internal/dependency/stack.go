@@ -0,0 +1,90 @@
+package dependency
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/marcus/td/internal/db"
+)
+
+// ErrStackExists is returned when an issue is already stacked on the given base.
+var ErrStackExists = fmt.Errorf("already stacked")
+
+// ValidateStack checks that issueID can be stacked on baseID: both issues
+// exist, the stack stays a single chain and no cycle forms. An issue
+// already stacked elsewhere may move; nothing else may sit on baseID.
+func ValidateStack(database *db.DB, issueID, baseID string) error {
+	issue, err := database.GetIssue(issueID)
+	if err != nil {
+		return fmt.Errorf("issue not found: %s", issueID)
+	}
+	base, err := database.GetIssue(baseID)
+	if err != nil {
+		return fmt.Errorf("issue not found: %s", baseID)
+	}
+	if issue.ID == base.ID {
+		return fmt.Errorf("cannot stack %s on itself", issue.ID)
+	}
+
+	bases, err := database.GetStackBases()
+	if err != nil {
+		return err
+	}
+	if bases[issue.ID] == base.ID {
+		return ErrStackExists
+	}
+	for id, b := range bases {
+		if b == base.ID && id != issue.ID {
+			return fmt.Errorf("%s is already stacked on %s; stack on the top of the chain instead", id, base.ID)
+		}
+	}
+
+	// Walking down from the base must not reach the issue
+	seen := make(map[string]bool)
+	for id := base.ID; id != "" && !seen[id]; id = bases[id] {
+		if id == issue.ID {
+			return fmt.Errorf("cannot stack %s on %s: would create a cycle", issue.ID, base.ID)
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// Chain returns the stack issueID belongs to, bottom first, from the
+// relations db.GetStackBases returns. Each issue comes after the one it is
+// stacked on; issues stacked side by side on one base (which only sync can
+// produce) follow in ID order. It returns nil when issueID is in no stack.
+func Chain(bases map[string]string, issueID string) []string {
+	root := issueID
+	seen := map[string]bool{root: true}
+	for b, ok := bases[root]; ok && !seen[b]; b, ok = bases[root] {
+		seen[b] = true
+		root = b
+	}
+
+	above := make(map[string][]string)
+	for id, b := range bases {
+		above[b] = append(above[b], id)
+	}
+	if root == issueID && len(above[issueID]) == 0 {
+		return nil
+	}
+
+	var chain []string
+	visited := make(map[string]bool)
+	var walk func(id string)
+	walk = func(id string) {
+		if visited[id] {
+			return
+		}
+		visited[id] = true
+		chain = append(chain, id)
+		next := above[id]
+		sort.Strings(next)
+		for _, n := range next {
+			walk(n)
+		}
+	}
+	walk(root)
+	return chain
+}
@@ -0,0 +1,119 @@
+package dependency
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+func TestValidateStack(t *testing.T) {
+	database, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	a := createTestIssue(t, database, "Extract payment client")
+	b := createTestIssue(t, database, "Retry failed payments")
+	c := createTestIssue(t, database, "Show payment retries")
+	for _, edge := range [][2]string{{b.ID, a.ID}, {c.ID, b.ID}} {
+		if err := database.AddDependency(edge[0], edge[1], models.RelationStackedOn); err != nil {
+			t.Fatal(err)
+		}
+	}
+	d := createTestIssue(t, database, "Log payment retries")
+
+	tests := []struct {
+		name        string
+		issue, base string
+		wantErr     bool
+	}{
+		{"top of the chain", d.ID, c.ID, false},
+		{"move to another base", c.ID, d.ID, false},
+		{"already there", b.ID, a.ID, true},
+		{"base taken", d.ID, a.ID, true},
+		{"cycle", a.ID, c.ID, true},
+		{"itself", d.ID, d.ID, true},
+		{"missing base", d.ID, "td-missing", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateStack(database, tt.issue, tt.base); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateStack(%s, %s) = %v, want error %v", tt.issue, tt.base, err, tt.wantErr)
+			}
+		})
+	}
+	if err := ValidateStack(database, b.ID, a.ID); err != ErrStackExists {
+		t.Errorf("ValidateStack on the same base = %v, want ErrStackExists", err)
+	}
+}
+
+func TestChain(t *testing.T) {
+	bases := map[string]string{"td-b": "td-a", "td-c": "td-b", "td-y": "td-x", "td-x": "td-y"}
+
+	tests := []struct {
+		id   string
+		want []string
+	}{
+		{"td-a", []string{"td-a", "td-b", "td-c"}},
+		{"td-c", []string{"td-a", "td-b", "td-c"}},
+		{"td-z", nil},
+		{"td-x", []string{"td-y", "td-x"}}, // a synced cycle still ends
+	}
+	for _, tt := range tests {
+		if got := Chain(bases, tt.id); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Chain(%s) = %v, want %v", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestStackedIssuesWaitInQueue(t *testing.T) {
+	database, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	a := createTestIssue(t, database, "Extract payment client")
+	b := createTestIssue(t, database, "Retry failed payments")
+	if err := database.AddDependencyLogged(b.ID, a.ID, models.RelationStackedOn, "ses_a"); err != nil {
+		t.Fatal(err)
+	}
+
+	ready := func() []string {
+		t.Helper()
+		issues, err := database.ListIssues(db.ListIssuesOptions{Status: []models.Status{models.StatusOpen}, ExcludeHasOpenDeps: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, issue := range issues {
+			ids = append(ids, issue.ID)
+		}
+		return ids
+	}
+	if got := ready(); !reflect.DeepEqual(got, []string{a.ID}) {
+		t.Errorf("ready = %v, want only the base %s", got, a.ID)
+	}
+	if blocked, _ := database.GetDependencyBlockedIDs(); !blocked[b.ID] {
+		t.Errorf("GetDependencyBlockedIDs misses %s", b.ID)
+	}
+	if deps, _ := database.GetDependencies(b.ID); len(deps) != 0 {
+		t.Errorf("GetDependencies = %v, want stacks kept apart", deps)
+	}
+
+	a.Status = models.StatusClosed
+	if err := database.UpdateIssue(a); err != nil {
+		t.Fatal(err)
+	}
+	if got := ready(); !reflect.DeepEqual(got, []string{b.ID}) {
+		t.Errorf("ready after the base closed = %v, want %s", got, b.ID)
+	}
+
+	// Removing a dependency leaves the stack alone, and the reverse
+	if err := database.AddDependencyLogged(b.ID, a.ID, "depends_on", "ses_a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.RemoveDependencyLogged(b.ID, a.ID, "ses_a"); err != nil {
+		t.Fatal(err)
+	}
+	if bases, _ := database.GetStackBases(); bases[b.ID] != a.ID {
+		t.Errorf("stack bases = %v after removing the dependency", bases)
+	}
+}
@@ -0,0 +1,244 @@
+// Package external checks dependencies on things outside td: a GitHub
+// pull request that must merge, a GitHub Actions run that must pass, or
+// any URL that must answer with a 2xx status.
+//
+// Poll checks every unsatisfied external dependency and, when one's
+// condition is met, unblocks its issue if nothing else holds it. td serve
+// runs Poll as its external-deps job; td dep poll runs it once.
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/db"
+)
+
+// Providers
+const (
+	ProviderGitHubPR  = "github_pr"  // satisfied when the pull request is merged
+	ProviderGitHubRun = "github_run" // satisfied when the Actions run succeeded
+	ProviderHTTP      = "http"       // satisfied when the URL answers 2xx
+)
+
+// DefaultGitHubAPI is the GitHub REST API root
+const DefaultGitHubAPI = "https://api.github.com"
+
+// Target is a parsed external dependency URL
+type Target struct {
+	URL      string
+	Provider string
+	Owner    string // GitHub targets only
+	Repo     string
+	Number   string // pull request number or run ID
+}
+
+// IsURL reports whether s looks like an external dependency rather than
+// an issue ID
+func IsURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
+
+// Parse recognises GitHub pull request and Actions run URLs; any other
+// http(s) URL is checked for a 2xx response.
+func Parse(raw string) (Target, error) {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return Target{}, fmt.Errorf("not an http(s) URL: %s", raw)
+	}
+	t := Target{URL: raw, Provider: ProviderHTTP}
+	if u.Host != "github.com" && u.Host != "www.github.com" {
+		return t, nil
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	switch {
+	case len(parts) >= 4 && parts[2] == "pull" && isNumber(parts[3]):
+		t.Provider, t.Owner, t.Repo, t.Number = ProviderGitHubPR, parts[0], parts[1], parts[3]
+	case len(parts) >= 5 && parts[2] == "actions" && parts[3] == "runs" && isNumber(parts[4]):
+		t.Provider, t.Owner, t.Repo, t.Number = ProviderGitHubRun, parts[0], parts[1], parts[4]
+	}
+	return t, nil
+}
+
+func isNumber(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Result is the outcome of checking a target
+type Result struct {
+	Satisfied bool
+	Detail    string // what was seen, e.g. "open" or "completed: failure"
+}
+
+// Checker checks targets over HTTP
+type Checker struct {
+	Client    *http.Client
+	GitHubAPI string // defaults to DefaultGitHubAPI
+	Token     string // GitHub token, for private repositories and rate limits
+}
+
+// NewChecker returns a checker that authenticates to GitHub with
+// GITHUB_TOKEN or GH_TOKEN when set.
+func NewChecker() *Checker {
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		token = os.Getenv("GH_TOKEN")
+	}
+	return &Checker{
+		Client:    &http.Client{Timeout: 15 * time.Second},
+		GitHubAPI: DefaultGitHubAPI,
+		Token:     token,
+	}
+}
+
+// Check fetches a target's current state. An error means the state could
+// not be seen, not that the condition is unmet.
+func (c *Checker) Check(ctx context.Context, t Target) (Result, error) {
+	switch t.Provider {
+	case ProviderGitHubPR:
+		var pr struct {
+			State  string `json:"state"`
+			Merged bool   `json:"merged"`
+		}
+		if err := c.getGitHub(ctx, fmt.Sprintf("/repos/%s/%s/pulls/%s", t.Owner, t.Repo, t.Number), &pr); err != nil {
+			return Result{}, err
+		}
+		if pr.Merged {
+			return Result{Satisfied: true, Detail: "merged"}, nil
+		}
+		return Result{Detail: pr.State}, nil
+
+	case ProviderGitHubRun:
+		var run struct {
+			Status     string `json:"status"`
+			Conclusion string `json:"conclusion"`
+		}
+		if err := c.getGitHub(ctx, fmt.Sprintf("/repos/%s/%s/actions/runs/%s", t.Owner, t.Repo, t.Number), &run); err != nil {
+			return Result{}, err
+		}
+		detail := run.Status
+		if run.Conclusion != "" {
+			detail += ": " + run.Conclusion
+		}
+		return Result{Satisfied: run.Status == "completed" && run.Conclusion == "success", Detail: detail}, nil
+
+	case ProviderHTTP:
+		resp, err := c.do(ctx, t.URL, nil)
+		if err != nil {
+			return Result{}, err
+		}
+		resp.Body.Close()
+		return Result{Satisfied: resp.StatusCode >= 200 && resp.StatusCode < 300, Detail: fmt.Sprintf("HTTP %d", resp.StatusCode)}, nil
+	}
+	return Result{}, fmt.Errorf("unknown provider %q", t.Provider)
+}
+
+// getGitHub fetches a GitHub API path into v
+func (c *Checker) getGitHub(ctx context.Context, path string, v interface{}) error {
+	api := c.GitHubAPI
+	if api == "" {
+		api = DefaultGitHubAPI
+	}
+	headers := map[string]string{"Accept": "application/vnd.github+json"}
+	if c.Token != "" {
+		headers["Authorization"] = "Bearer " + c.Token
+	}
+	resp, err := c.do(ctx, strings.TrimRight(api, "/")+path, headers)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GitHub API %s: HTTP %d", path, resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(v)
+}
+
+func (c *Checker) do(ctx context.Context, target string, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "td")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return client.Do(req)
+}
+
+// PollResult summarises one Poll
+type PollResult struct {
+	Checked   int      `json:"checked"`
+	Satisfied int      `json:"satisfied"`
+	Errors    int      `json:"errors"`
+	Unblocked []string `json:"unblocked"`
+}
+
+// Poll checks every unsatisfied external dependency once, records what it
+// saw, and unblocks issues whose external and issue dependencies are all
+// resolved. Failed checks are recorded on the dependency and counted, not
+// returned.
+func Poll(ctx context.Context, database *db.DB, checker *Checker, sessionID string) (*PollResult, error) {
+	deps, err := database.ListUnsatisfiedExternalDependencies()
+	if err != nil {
+		return nil, err
+	}
+	res := &PollResult{Unblocked: []string{}}
+	for _, dep := range deps {
+		if err := ctx.Err(); err != nil {
+			return res, err
+		}
+		res.Checked++
+
+		target, err := Parse(dep.URL)
+		var result Result
+		if err == nil {
+			result, err = checker.Check(ctx, target)
+		}
+		if err != nil {
+			res.Errors++
+			if err := database.RecordExternalCheck(dep.ID, false, "", err.Error()); err != nil {
+				return res, err
+			}
+			continue
+		}
+		if err := database.RecordExternalCheck(dep.ID, result.Satisfied, result.Detail, ""); err != nil {
+			return res, err
+		}
+		if !result.Satisfied {
+			continue
+		}
+		res.Satisfied++
+
+		msg := fmt.Sprintf("Auto-unblocked (%s %s)", dep.URL, result.Detail)
+		unblocked, err := database.UnblockIfExternalResolved(dep.IssueID, sessionID, dep.URL, msg)
+		if err != nil {
+			return res, err
+		}
+		if unblocked {
+			res.Unblocked = append(res.Unblocked, dep.IssueID)
+		}
+	}
+	return res, nil
+}
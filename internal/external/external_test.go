@@ -0,0 +1,144 @@
+package external
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		url, provider, number string
+		wantErr               bool
+	}{
+		{"https://github.com/acme/lib/pull/42", ProviderGitHubPR, "42", false},
+		{"https://github.com/acme/lib/pull/42/files", ProviderGitHubPR, "42", false},
+		{"https://github.com/acme/app/actions/runs/123456", ProviderGitHubRun, "123456", false},
+		{"https://github.com/acme/lib/issues/7", ProviderHTTP, "", false},
+		{"https://status.example.com/health", ProviderHTTP, "", false},
+		{"ftp://example.com/file", "", "", true},
+		{"td-abc1", "", "", true},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.url)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Parse(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+			continue
+		}
+		if got.Provider != tt.provider || got.Number != tt.number {
+			t.Errorf("Parse(%q) = %+v, want %s %s", tt.url, got, tt.provider, tt.number)
+		}
+	}
+}
+
+func TestPoll(t *testing.T) {
+	prMerged := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/acme/lib/pulls/42":
+			if prMerged {
+				w.Write([]byte(`{"state":"closed","merged":true}`))
+			} else {
+				w.Write([]byte(`{"state":"open","merged":false}`))
+			}
+		case "/repos/acme/app/actions/runs/7":
+			w.Write([]byte(`{"status":"completed","conclusion":"failure"}`))
+		case "/down":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	database, err := db.Initialize(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+
+	var waiting, failing models.Issue
+	for _, i := range []*models.Issue{&waiting, &failing} {
+		i.Title = "Issue waiting on CI"
+		if err := database.CreateIssueLogged(i, "ses_a"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	add := func(issueID, url string) {
+		target, err := Parse(url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := database.AddExternalDependency(issueID, url, target.Provider, "ses_a"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	add(waiting.ID, "https://github.com/acme/lib/pull/42")
+	add(waiting.ID, srv.URL+"/down")
+	add(failing.ID, "https://github.com/acme/app/actions/runs/7")
+
+	checker := &Checker{Client: srv.Client(), GitHubAPI: srv.URL}
+	res, err := Poll(context.Background(), database, checker, "ses_poll")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Checked != 3 || res.Satisfied != 0 || len(res.Unblocked) != 0 {
+		t.Errorf("first poll = %+v, want 3 checked and none satisfied", res)
+	}
+	deps, _ := database.ListExternalDependencies(failing.ID)
+	if deps[0].Detail != "completed: failure" {
+		t.Errorf("run detail = %q", deps[0].Detail)
+	}
+
+	// The PR merges, but the other URL still holds the issue
+	prMerged = true
+	if res, _ = Poll(context.Background(), database, checker, "ses_poll"); res.Satisfied != 1 || len(res.Unblocked) != 0 {
+		t.Errorf("poll after merge = %+v, want 1 satisfied and none unblocked", res)
+	}
+	if _, unblocked, err := database.RemoveExternalDependency(waiting.ID, srv.URL+"/down", "ses_a"); err != nil || !unblocked {
+		t.Errorf("remove last unsatisfied = %v, %v; want unblocked", unblocked, err)
+	}
+	if res, _ = Poll(context.Background(), database, checker, "ses_poll"); res.Checked != 1 {
+		t.Errorf("poll after resolve = %+v, want only the failing run checked", res)
+	}
+}
+
+func TestPollUnblocksWhenSatisfied(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	database, err := db.Initialize(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+
+	issue := &models.Issue{Title: "Deploy after the status page is up"}
+	if err := database.CreateIssueLogged(issue, "ses_a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := database.AddExternalDependency(issue.ID, srv.URL+"/ok", ProviderHTTP, "ses_a"); err != nil {
+		t.Fatal(err)
+	}
+	res, err := Poll(context.Background(), database, &Checker{Client: srv.Client()}, "ses_poll")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Unblocked) != 1 || res.Unblocked[0] != issue.ID {
+		t.Fatalf("unblocked = %v, want %s", res.Unblocked, issue.ID)
+	}
+	got, _ := database.GetIssue(issue.ID)
+	if got.Status != models.StatusOpen {
+		t.Errorf("status = %s, want open", got.Status)
+	}
+	logs, _ := database.GetLogs(issue.ID, 0)
+	if len(logs) == 0 || logs[len(logs)-1].Message != "Auto-unblocked ("+srv.URL+"/ok HTTP 204)" {
+		t.Errorf("logs = %+v", logs)
+	}
+}
@@ -0,0 +1,287 @@
+// Package forecast projects when a set of issues will be done by replaying
+// historical cycle times in a Monte Carlo simulation.
+//
+// Each trial draws a cycle time for every unfinished issue from the cycle
+// times of issues closed recently, and schedules them across as many
+// parallel lanes as the project has typically had in progress. Started
+// issues draw only from cycle times longer than their current age. The
+// spread of finish dates across trials gives the percentiles.
+package forecast
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+// DefaultTrials is the number of simulated futures per forecast
+const DefaultTrials = 10000
+
+// MaxTrials bounds the number of simulated futures
+const MaxTrials = 100000
+
+// DefaultHistoryDays is how far back cycle times are sampled
+const DefaultHistoryDays = 90
+
+// MaxHistoryDays bounds the history window
+const MaxHistoryDays = 730
+
+// MaxParallelism bounds the number of simulated lanes
+const MaxParallelism = 100
+
+// Percentiles reported by a forecast
+var Percentiles = []int{50, 70, 85, 95}
+
+// ErrNoHistory means no issue closed in the history window, so there are no
+// cycle times to sample
+var ErrNoHistory = errors.New("no issues closed in the history window to sample cycle times from")
+
+const day = 24 * time.Hour
+
+// History is the sample a forecast draws from
+type History struct {
+	CycleTimes  []time.Duration // first start (or creation) to close
+	Parallelism float64         // average issues in progress over the window
+	WindowDays  int
+}
+
+// Item is an unfinished issue to forecast
+type Item struct {
+	ID        string
+	StartedAt *time.Time // nil if not started
+}
+
+// Projection is one percentile of the simulated finish dates: in that
+// share of trials, everything was done by Date
+type Projection struct {
+	Percentile int       `json:"percentile"`
+	Date       time.Time `json:"date"`
+	Days       float64   `json:"days"`
+}
+
+// Result is a forecast for a set of issues
+type Result struct {
+	Issues      int          `json:"issues"`
+	Closed      int          `json:"closed"`
+	Remaining   int          `json:"remaining"`
+	InProgress  int          `json:"in_progress"`
+	Trials      int          `json:"trials"`
+	Parallelism int          `json:"parallelism"`
+	HistoryDays int          `json:"history_days"`
+	Samples     int          `json:"samples"`      // cycle times sampled from
+	MedianCycle float64      `json:"median_cycle"` // days
+	Projections []Projection `json:"projections"`  // empty when nothing remains
+	GeneratedAt time.Time    `json:"generated_at"`
+}
+
+// Options tune a simulation; zero values take the defaults
+type Options struct {
+	Trials      int
+	Parallelism int // 0 = from history
+	Rand        *rand.Rand
+}
+
+// Simulate forecasts the completion of remaining. closed is the number of
+// issues in the set already done, reported as-is.
+func Simulate(h History, remaining []Item, closed int, opts Options, now time.Time) (*Result, error) {
+	trials := opts.Trials
+	if trials <= 0 {
+		trials = DefaultTrials
+	}
+	lanes := opts.Parallelism
+	if lanes <= 0 {
+		lanes = int(math.Round(h.Parallelism))
+	}
+	if lanes < 1 {
+		lanes = 1
+	}
+	rng := opts.Rand
+	if rng == nil {
+		rng = rand.New(rand.NewSource(now.UnixNano()))
+	}
+
+	samples := make([]float64, len(h.CycleTimes))
+	for i, c := range h.CycleTimes {
+		samples[i] = c.Hours() / 24
+	}
+	sort.Float64s(samples)
+
+	res := &Result{
+		Issues:      len(remaining) + closed,
+		Closed:      closed,
+		Remaining:   len(remaining),
+		Trials:      trials,
+		Parallelism: lanes,
+		HistoryDays: h.WindowDays,
+		Samples:     len(samples),
+		Projections: []Projection{},
+		GeneratedAt: now,
+	}
+	if len(samples) > 0 {
+		res.MedianCycle = round2(samples[len(samples)/2])
+	}
+	if len(remaining) == 0 {
+		return res, nil
+	}
+	if len(samples) == 0 {
+		return nil, ErrNoHistory
+	}
+
+	// Started issues keep their own lane; open ones queue behind whichever
+	// lane frees up first
+	var ages []float64
+	open := 0
+	for _, it := range remaining {
+		if it.StartedAt != nil {
+			ages = append(ages, now.Sub(*it.StartedAt).Hours()/24)
+		} else {
+			open++
+		}
+	}
+	res.InProgress = len(ages)
+	if lanes < len(ages) {
+		lanes = len(ages)
+	}
+
+	finishes := make([]float64, trials)
+	busy := make([]float64, lanes)
+	for t := range finishes {
+		for i := range busy {
+			busy[i] = 0
+		}
+		for i, age := range ages {
+			busy[i] = remainingCycle(samples, age, rng)
+		}
+		for n := 0; n < open; n++ {
+			next := 0
+			for i := range busy {
+				if busy[i] < busy[next] {
+					next = i
+				}
+			}
+			busy[next] += samples[rng.Intn(len(samples))]
+		}
+		end := 0.0
+		for _, b := range busy {
+			end = math.Max(end, b)
+		}
+		finishes[t] = end
+	}
+	sort.Float64s(finishes)
+
+	for _, p := range Percentiles {
+		idx := int(math.Ceil(float64(p)/100*float64(trials))) - 1
+		if idx < 0 {
+			idx = 0
+		}
+		days := finishes[idx]
+		res.Projections = append(res.Projections, Projection{
+			Percentile: p,
+			Date:       now.Add(time.Duration(days * float64(day))),
+			Days:       round2(days),
+		})
+	}
+	return res, nil
+}
+
+// remainingCycle draws how much longer an issue already age days old will
+// take: a cycle time that exceeds its age, minus the age. An issue older
+// than anything in the sample is assumed to need one more typical cycle.
+func remainingCycle(sorted []float64, age float64, rng *rand.Rand) float64 {
+	i := sort.SearchFloat64s(sorted, age)
+	for i < len(sorted) && sorted[i] <= age {
+		i++
+	}
+	if i == len(sorted) {
+		return sorted[rng.Intn(len(sorted))]
+	}
+	return sorted[i+rng.Intn(len(sorted)-i)] - age
+}
+
+// LoadHistory collects cycle times of issues closed in the last windowDays.
+// Parallelism follows Little's law: the total time issues spent in progress
+// divided by the window.
+func LoadHistory(database *db.DB, windowDays int, now time.Time) (History, error) {
+	if windowDays <= 0 {
+		windowDays = DefaultHistoryDays
+	}
+	h := History{WindowDays: windowDays}
+	closed, err := database.ListIssues(db.ListIssuesOptions{
+		Status:      []models.Status{models.StatusClosed},
+		ClosedAfter: now.AddDate(0, 0, -windowDays),
+	})
+	if err != nil {
+		return h, err
+	}
+	ids := make([]string, len(closed))
+	for i, issue := range closed {
+		ids[i] = issue.ID
+	}
+	starts, err := database.GetFirstStartTimes(ids)
+	if err != nil {
+		return h, err
+	}
+
+	var total time.Duration
+	for _, issue := range closed {
+		if issue.ClosedAt == nil {
+			continue
+		}
+		from := issue.CreatedAt
+		if at, ok := starts[issue.ID]; ok {
+			from = at
+		}
+		c := issue.ClosedAt.Sub(from)
+		if c < 0 {
+			c = 0
+		}
+		h.CycleTimes = append(h.CycleTimes, c)
+		total += c
+	}
+	h.Parallelism = total.Hours() / 24 / float64(windowDays)
+	return h, nil
+}
+
+// Forecast loads history and simulates the completion of issues, which may
+// include closed ones
+func Forecast(database *db.DB, issues []models.Issue, historyDays int, opts Options, now time.Time) (*Result, error) {
+	h, err := LoadHistory(database, historyDays, now)
+	if err != nil {
+		return nil, err
+	}
+
+	var open []models.Issue
+	closed := 0
+	ids := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		if issue.Status == models.StatusClosed {
+			closed++
+			continue
+		}
+		open = append(open, issue)
+		ids = append(ids, issue.ID)
+	}
+	starts, err := database.GetFirstStartTimes(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]Item, len(open))
+	for i, issue := range open {
+		items[i] = Item{ID: issue.ID}
+		// An issue sent back to open is queued again, whatever its history
+		if at, ok := starts[issue.ID]; ok && issue.Status != models.StatusOpen {
+			items[i].StartedAt = &at
+		}
+	}
+	return Simulate(h, items, closed, opts, now)
+}
+
+func round2(f float64) float64 {
+	return math.Round(f*100) / 100
+}
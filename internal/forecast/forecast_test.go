@@ -0,0 +1,111 @@
+package forecast
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+func days(ds ...float64) []time.Duration {
+	out := make([]time.Duration, len(ds))
+	for i, d := range ds {
+		out[i] = time.Duration(d * float64(day))
+	}
+	return out
+}
+
+func TestSimulateFixedCycle(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	h := History{CycleTimes: days(2, 2, 2), Parallelism: 2, WindowDays: 30}
+	items := []Item{{ID: "a"}, {ID: "b"}, {ID: "c"}, {ID: "d"}, {ID: "e"}}
+
+	r, err := Simulate(h, items, 1, Options{Trials: 100, Rand: rand.New(rand.NewSource(1))}, now)
+	if err != nil {
+		t.Fatalf("Simulate: %v", err)
+	}
+	if r.Issues != 6 || r.Remaining != 5 || r.Parallelism != 2 || r.MedianCycle != 2 {
+		t.Errorf("result = %+v", r)
+	}
+	// Five 2-day issues over two lanes take three rounds
+	for _, p := range r.Projections {
+		if p.Days != 6 || !p.Date.Equal(now.AddDate(0, 0, 6)) {
+			t.Errorf("p%d = %v days, %v", p.Percentile, p.Days, p.Date)
+		}
+	}
+}
+
+func TestSimulateStartedAndSpread(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	started := now.AddDate(0, 0, -3)
+	h := History{CycleTimes: days(1, 2, 4, 8, 16), Parallelism: 1}
+
+	// Only cycle times over the 3-day age are drawn, so 1 to 13 days remain
+	r, err := Simulate(h, []Item{{ID: "a", StartedAt: &started}}, 0, Options{Trials: 2000, Rand: rand.New(rand.NewSource(7))}, now)
+	if err != nil {
+		t.Fatalf("Simulate: %v", err)
+	}
+	if r.InProgress != 1 {
+		t.Errorf("in progress = %d", r.InProgress)
+	}
+	prev := 0.0
+	for _, p := range r.Projections {
+		if p.Days < 1 || p.Days > 13 || p.Days < prev {
+			t.Errorf("p%d = %v days", p.Percentile, p.Days)
+		}
+		prev = p.Days
+	}
+	if r.Projections[len(r.Projections)-1].Days != 13 {
+		t.Errorf("p95 = %v, want 13", r.Projections[len(r.Projections)-1].Days)
+	}
+}
+
+func TestSimulateEdges(t *testing.T) {
+	now := time.Now()
+	r, err := Simulate(History{}, nil, 3, Options{}, now)
+	if err != nil || r.Remaining != 0 || len(r.Projections) != 0 {
+		t.Errorf("all closed: %+v, %v", r, err)
+	}
+	if _, err := Simulate(History{}, []Item{{ID: "a"}}, 0, Options{}, now); !errors.Is(err, ErrNoHistory) {
+		t.Errorf("err = %v, want ErrNoHistory", err)
+	}
+}
+
+func TestForecast(t *testing.T) {
+	database, err := db.Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	defer database.Close()
+
+	done := &models.Issue{Title: "Shipped", Status: models.StatusOpen}
+	if err := database.CreateIssue(done); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+	database.RecordSessionAction(done.ID, "ses_a", models.ActionSessionStarted)
+	closedAt := time.Now()
+	done.Status = models.StatusClosed
+	done.ClosedAt = &closedAt
+	if err := database.UpdateIssue(done); err != nil {
+		t.Fatalf("UpdateIssue: %v", err)
+	}
+
+	todo := models.Issue{Title: "Next", Status: models.StatusOpen}
+	if err := database.CreateIssue(&todo); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+
+	r, err := Forecast(database, []models.Issue{*done, todo}, 0, Options{Trials: 10}, time.Now())
+	if err != nil {
+		t.Fatalf("Forecast: %v", err)
+	}
+	if r.Closed != 1 || r.Remaining != 1 || r.Samples != 1 || r.HistoryDays != DefaultHistoryDays {
+		t.Errorf("result = %+v", r)
+	}
+	if len(r.Projections) != len(Percentiles) {
+		t.Errorf("projections = %v", r.Projections)
+	}
+}
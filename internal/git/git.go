@@ -6,6 +6,7 @@ import (
 	"bytes"
 	"fmt"
 	"os/exec"
+	"path/filepath"
 	"strconv"
 	"strings"
 )
@@ -14,6 +15,8 @@ import (
 type State struct {
 	CommitSHA  string
 	Branch     string
+	Repo       string // Repository identity, see RepoName
+	Root       string // Top-level directory of the working tree
 	IsClean    bool
 	Modified   int
 	Untracked  int
@@ -22,24 +25,33 @@ type State struct {
 
 // GetState returns the current git state
 func GetState() (*State, error) {
+	return GetStateIn("")
+}
+
+// GetStateIn returns the git state of the repository at dir
+func GetStateIn(dir string) (*State, error) {
 	state := &State{}
 
 	// Get current commit SHA
-	sha, err := runGit("rev-parse", "HEAD")
+	sha, err := runGitIn(dir, "rev-parse", "HEAD")
 	if err != nil {
 		return nil, fmt.Errorf("not a git repository")
 	}
 	state.CommitSHA = strings.TrimSpace(sha)
 
 	// Get current branch
-	branch, err := runGit("rev-parse", "--abbrev-ref", "HEAD")
+	branch, err := runGitIn(dir, "rev-parse", "--abbrev-ref", "HEAD")
 	if err != nil {
 		branch = "HEAD"
 	}
 	state.Branch = strings.TrimSpace(branch)
+	if root, err := runGitIn(dir, "rev-parse", "--show-toplevel"); err == nil {
+		state.Root = strings.TrimSpace(root)
+	}
+	state.Repo = repoName(dir, state.Root)
 
 	// Get status
-	status, _ := runGit("status", "--porcelain")
+	status, _ := runGitIn(dir, "status", "--porcelain")
 	lines := strings.Split(strings.TrimSpace(status), "\n")
 
 	if status == "" || (len(lines) == 1 && lines[0] == "") {
@@ -64,7 +76,12 @@ func GetState() (*State, error) {
 
 // GetCommitsSince returns the number of commits since a given SHA
 func GetCommitsSince(sha string) (int, error) {
-	output, err := runGit("rev-list", "--count", sha+"..HEAD")
+	return GetCommitsSinceIn("", sha)
+}
+
+// GetCommitsSinceIn is GetCommitsSince for the repository at dir
+func GetCommitsSinceIn(dir, sha string) (int, error) {
+	output, err := runGitIn(dir, "rev-list", "--count", sha+"..HEAD")
 	if err != nil {
 		return 0, err
 	}
@@ -138,7 +155,12 @@ type DiffStats struct {
 
 // GetDiffStatsSince returns diff statistics since a given SHA
 func GetDiffStatsSince(sha string) (*DiffStats, error) {
-	output, err := runGit("diff", "--shortstat", sha+"..HEAD")
+	return GetDiffStatsSinceIn("", sha)
+}
+
+// GetDiffStatsSinceIn is GetDiffStatsSince for the repository at dir
+func GetDiffStatsSinceIn(dir, sha string) (*DiffStats, error) {
+	output, err := runGitIn(dir, "diff", "--shortstat", sha+"..HEAD")
 	if err != nil {
 		return nil, err
 	}
@@ -183,6 +205,12 @@ func IsRepo() bool {
 	return err == nil
 }
 
+// IsRepoIn checks if dir is inside a git repository
+func IsRepoIn(dir string) bool {
+	_, err := runGitIn(dir, "rev-parse", "--git-dir")
+	return err == nil
+}
+
 // GetRootDir returns the git repository root directory
 func GetRootDir() (string, error) {
 	output, err := runGit("rev-parse", "--show-toplevel")
@@ -192,8 +220,79 @@ func GetRootDir() (string, error) {
 	return strings.TrimSpace(output), nil
 }
 
+// RepoName identifies the repository at dir ("" for the working
+// directory) so one td project can track several repositories. It is the
+// origin remote normalized to host/path (github.com/acme/api), or the name
+// of the top-level directory when there is no origin. Returns "" outside a
+// repository.
+func RepoName(dir string) string {
+	root, _ := runGitIn(dir, "rev-parse", "--show-toplevel")
+	return repoName(dir, strings.TrimSpace(root))
+}
+
+func repoName(dir, root string) string {
+	if url, err := runGitIn(dir, "config", "--get", "remote.origin.url"); err == nil {
+		if name := NormalizeRemoteURL(url); name != "" {
+			return name
+		}
+	}
+	if root == "" {
+		return ""
+	}
+	return filepath.Base(root)
+}
+
+// NormalizeRemoteURL reduces the https, ssh and scp-style forms of a
+// remote URL to host/path, so clones of the same repository agree:
+// git@github.com:acme/api.git and https://github.com/acme/api both give
+// github.com/acme/api.
+func NormalizeRemoteURL(url string) string {
+	url = strings.TrimSpace(url)
+	if i := strings.Index(url, "://"); i >= 0 {
+		url = url[i+3:]
+	} else if i := strings.Index(url, ":"); i >= 0 && !strings.Contains(url[:i], "/") {
+		url = url[:i] + "/" + url[i+1:] // scp-style host:path
+	}
+	if i := strings.Index(url, "@"); i >= 0 && i < strings.Index(url+"/", "/") {
+		url = url[i+1:]
+	}
+	url = strings.TrimSuffix(strings.TrimSuffix(url, "/"), ".git")
+	host, path, _ := strings.Cut(url, "/")
+	if i := strings.Index(host, ":"); i >= 0 {
+		host = host[:i] // drop port
+	}
+	if path == "" {
+		return strings.ToLower(host)
+	}
+	return strings.ToLower(host) + "/" + path
+}
+
+// HasCommit reports whether the repository at dir contains the commit ref
+// (a full or abbreviated SHA)
+func HasCommit(dir, ref string) bool {
+	_, err := runGitIn(dir, "cat-file", "-e", ref+"^{commit}")
+	return err == nil
+}
+
+// HasBranch reports whether the repository at dir has a local branch, or a
+// remote-tracking branch, with this name
+func HasBranch(dir, branch string) bool {
+	for _, ref := range []string{"refs/heads/" + branch, "refs/remotes/origin/" + branch} {
+		if _, err := runGitIn(dir, "show-ref", "--verify", "--quiet", ref); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
 func runGit(args ...string) (string, error) {
+	return runGitIn("", args...)
+}
+
+// runGitIn runs git in dir, or in the working directory when dir is ""
+func runGitIn(dir string, args ...string) (string, error) {
 	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
@@ -403,3 +403,64 @@ func TestStateBranchName(t *testing.T) {
 		t.Logf("Branch name is %q (expected main/master/HEAD)", state.Branch)
 	}
 }
+
+// TestNormalizeRemoteURL tests that remote URL forms agree
+func TestNormalizeRemoteURL(t *testing.T) {
+	tests := []struct{ url, want string }{
+		{"git@github.com:acme/api.git", "github.com/acme/api"},
+		{"https://github.com/acme/api", "github.com/acme/api"},
+		{"https://user@GitHub.com/acme/api.git/", "github.com/acme/api"},
+		{"ssh://git@gitlab.example.com:2222/group/sub/api.git", "gitlab.example.com/group/sub/api"},
+		{"  https://github.com/acme/api.git\n", "github.com/acme/api"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeRemoteURL(tt.url); got != tt.want {
+			t.Errorf("NormalizeRemoteURL(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+// TestRepoName tests repository identity from the origin remote or directory
+func TestRepoName(t *testing.T) {
+	dir := initTestRepo(t)
+	if got := RepoName(dir); got != filepath.Base(dir) {
+		t.Errorf("RepoName without origin = %q, want %q", got, filepath.Base(dir))
+	}
+
+	runCmd(dir, "git", "remote", "add", "origin", "git@github.com:acme/api.git")
+	if got := RepoName(dir); got != "github.com/acme/api" {
+		t.Errorf("RepoName = %q", got)
+	}
+
+	if got := RepoName(t.TempDir()); got != "" {
+		t.Errorf("RepoName outside a repo = %q, want empty", got)
+	}
+}
+
+// TestHasCommitAndBranch tests resolving references against a given repo
+func TestHasCommitAndBranch(t *testing.T) {
+	api := initTestRepo(t)
+	web := initTestRepo(t)
+	runCmd(web, "git", "checkout", "-b", "feature/login")
+
+	// Identical initial commits in the same second share a SHA
+	os.WriteFile(filepath.Join(api, "api.go"), []byte("package api"), 0644)
+	runCmd(api, "git", "add", ".")
+	runCmd(api, "git", "commit", "-m", "Add api")
+
+	sha, err := runGitIn(api, "rev-parse", "HEAD")
+	if err != nil {
+		t.Fatalf("rev-parse: %v", err)
+	}
+	sha = sha[:12]
+	if !HasCommit(api, sha) {
+		t.Errorf("api should contain %s", sha)
+	}
+	if HasCommit(web, sha) {
+		t.Errorf("web should not contain %s", sha)
+	}
+
+	if !HasBranch(web, "feature/login") || HasBranch(api, "feature/login") {
+		t.Error("feature/login should exist only in web")
+	}
+}
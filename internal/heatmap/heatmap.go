@@ -0,0 +1,181 @@
+// Package heatmap counts activity per day from the action log, broken down
+// by session and action type, for calendar heatmaps like a contribution
+// graph.
+package heatmap
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/marcus/td/internal/dateparse"
+	"github.com/marcus/td/internal/db"
+)
+
+// DefaultMonths is how many months back a heatmap covers
+const DefaultMonths = 12
+
+// MaxMonths bounds the window
+const MaxMonths = 36
+
+// Levels is the number of non-zero intensity levels a day can have
+const Levels = 4
+
+// Day is one calendar day's activity
+type Day struct {
+	Date     string         `json:"date"` // YYYY-MM-DD in the project timezone
+	Count    int            `json:"count"`
+	Level    int            `json:"level"` // 0 for none, else 1 to Levels relative to the busiest day
+	Sessions map[string]int `json:"sessions,omitempty"`
+	Types    map[string]int `json:"types,omitempty"`
+}
+
+// Count is the activity of one session or action type over the window
+type Count struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+}
+
+// Report is a heatmap of every day in the window, oldest first
+type Report struct {
+	From          string    `json:"from"`
+	To            string    `json:"to"`
+	Months        int       `json:"months"`
+	Timezone      string    `json:"timezone"`
+	Total         int       `json:"total"`
+	Max           int       `json:"max"`
+	ActiveDays    int       `json:"active_days"`
+	CurrentStreak int       `json:"current_streak"`
+	LongestStreak int       `json:"longest_streak"`
+	Days          []Day     `json:"days"`
+	Sessions      []Count   `json:"sessions"`
+	Types         []Count   `json:"types"`
+	GeneratedAt   time.Time `json:"generated_at"`
+}
+
+// Options narrow a heatmap; zero values take the defaults
+type Options struct {
+	Months    int
+	SessionID string // only this session's actions
+	Type      string // only this action type
+}
+
+// Build counts events per day over the months up to now, in loc. Days
+// without activity are included so the result can be drawn as is.
+func Build(events []db.ActivityEvent, opts Options, now time.Time, loc *time.Location) *Report {
+	if opts.Months <= 0 {
+		opts.Months = DefaultMonths
+	}
+	now = now.In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	from := today.AddDate(0, -opts.Months, 1)
+
+	r := &Report{
+		From:        from.Format("2006-01-02"),
+		To:          today.Format("2006-01-02"),
+		Months:      opts.Months,
+		Timezone:    loc.String(),
+		Days:        []Day{},
+		Sessions:    []Count{},
+		Types:       []Count{},
+		GeneratedAt: now,
+	}
+	index := map[string]int{}
+	for d := from; !d.After(today); d = d.AddDate(0, 0, 1) {
+		date := d.Format("2006-01-02")
+		index[date] = len(r.Days)
+		r.Days = append(r.Days, Day{Date: date})
+	}
+
+	sessions, types := map[string]int{}, map[string]int{}
+	for _, e := range events {
+		if (opts.SessionID != "" && e.SessionID != opts.SessionID) || (opts.Type != "" && e.ActionType != opts.Type) {
+			continue
+		}
+		i, ok := index[e.At.In(loc).Format("2006-01-02")]
+		if !ok {
+			continue
+		}
+		day := &r.Days[i]
+		if day.Count == 0 {
+			day.Sessions, day.Types = map[string]int{}, map[string]int{}
+		}
+		day.Count++
+		day.Sessions[e.SessionID]++
+		day.Types[e.ActionType]++
+		sessions[e.SessionID]++
+		types[e.ActionType]++
+		r.Total++
+	}
+
+	streak := 0
+	for i := range r.Days {
+		day := &r.Days[i]
+		if day.Count > r.Max {
+			r.Max = day.Count
+		}
+		if day.Count == 0 {
+			streak = 0
+			continue
+		}
+		r.ActiveDays++
+		streak++
+		if streak > r.LongestStreak {
+			r.LongestStreak = streak
+		}
+	}
+	// Today without activity yet doesn't break the current streak
+	for i := len(r.Days) - 1; i >= 0; i-- {
+		if r.Days[i].Count == 0 {
+			if i == len(r.Days)-1 {
+				continue
+			}
+			break
+		}
+		r.CurrentStreak++
+	}
+	for i := range r.Days {
+		r.Days[i].Level = level(r.Days[i].Count, r.Max)
+	}
+
+	r.Sessions = sortedCounts(sessions)
+	r.Types = sortedCounts(types)
+	return r
+}
+
+// Compute builds the heatmap from the database in the project timezone
+func Compute(database *db.DB, opts Options, now time.Time) (*Report, error) {
+	if opts.Months <= 0 {
+		opts.Months = DefaultMonths
+	}
+	loc := dateparse.Location()
+	// A day of slack: the window starts at midnight in loc
+	events, err := database.GetActivityEvents(now.AddDate(0, -opts.Months, -1))
+	if err != nil {
+		return nil, err
+	}
+	return Build(events, opts, now, loc), nil
+}
+
+// level scales a day's count to 1..Levels against the busiest day
+func level(count, max int) int {
+	if count == 0 || max == 0 {
+		return 0
+	}
+	return int(math.Ceil(float64(count) * Levels / float64(max)))
+}
+
+// sortedCounts orders counts busiest first, by key among equals
+func sortedCounts(m map[string]int) []Count {
+	out := make([]Count, 0, len(m))
+	for k, n := range m {
+		out = append(out, Count{Key: k, Count: n})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
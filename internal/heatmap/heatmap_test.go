@@ -0,0 +1,83 @@
+package heatmap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+func TestBuild(t *testing.T) {
+	loc := time.FixedZone("UTC-5", -5*3600)
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, loc)
+	at := func(day, hour int) time.Time { return time.Date(2026, 10, day, hour, 0, 0, 0, loc) }
+	events := []db.ActivityEvent{
+		{SessionID: "ses_a", ActionType: "create", At: at(14, 9)},
+		{SessionID: "ses_a", ActionType: "update", At: at(15, 9)},
+		{SessionID: "ses_b", ActionType: "close", At: at(15, 10)},
+		{SessionID: "ses_a", ActionType: "update", At: at(15, 11)},
+		{SessionID: "ses_b", ActionType: "update", At: at(16, 23)},
+		// 01:00 UTC on the 17th is still the 16th here
+		{SessionID: "ses_b", ActionType: "update", At: time.Date(2026, 10, 17, 1, 0, 0, 0, time.UTC)},
+		{SessionID: "ses_a", ActionType: "create", At: at(10, 9)},
+		{SessionID: "ses_a", ActionType: "create", At: time.Date(2025, 1, 1, 0, 0, 0, 0, loc)},
+	}
+
+	r := Build(events, Options{Months: 1}, now, loc)
+	if r.From != "2026-09-18" || r.To != "2026-10-17" || len(r.Days) != 30 {
+		t.Fatalf("window = %s..%s with %d days", r.From, r.To, len(r.Days))
+	}
+	if r.Total != 7 || r.Max != 3 || r.ActiveDays != 4 {
+		t.Errorf("total %d, max %d, active %d; want 7, 3, 4", r.Total, r.Max, r.ActiveDays)
+	}
+	if r.CurrentStreak != 3 || r.LongestStreak != 3 {
+		t.Errorf("streaks = %d current, %d longest; want 3, 3", r.CurrentStreak, r.LongestStreak)
+	}
+	day := r.Days[len(r.Days)-3]
+	if day.Date != "2026-10-15" || day.Count != 3 || day.Level != Levels || day.Sessions["ses_a"] != 2 || day.Types["close"] != 1 {
+		t.Errorf("2026-10-15 = %+v", day)
+	}
+	if got := r.Days[len(r.Days)-2]; got.Count != 2 || got.Level != 3 {
+		t.Errorf("2026-10-16 = %+v, want 2 at level 3", got)
+	}
+	if got := r.Days[0]; got.Count != 0 || got.Level != 0 || got.Sessions != nil {
+		t.Errorf("quiet day = %+v", got)
+	}
+	if len(r.Sessions) != 2 || r.Sessions[0] != (Count{Key: "ses_a", Count: 4}) {
+		t.Errorf("sessions = %+v", r.Sessions)
+	}
+	if r.Types[0] != (Count{Key: "update", Count: 4}) {
+		t.Errorf("types = %+v", r.Types)
+	}
+
+	r = Build(events, Options{Months: 1, SessionID: "ses_b", Type: "update"}, now, loc)
+	if r.Total != 2 || r.ActiveDays != 1 {
+		t.Errorf("filtered = %d on %d days, want 2 on 1", r.Total, r.ActiveDays)
+	}
+}
+
+func TestCompute(t *testing.T) {
+	database, err := db.Initialize(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+
+	issue := &models.Issue{Title: "Issue with some activity"}
+	if err := database.CreateIssueLogged(issue, "ses_a"); err != nil {
+		t.Fatal(err)
+	}
+	issue.Status = models.StatusInProgress
+	if err := database.UpdateIssueLogged(issue, "ses_b", models.ActionStart); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := Compute(database, Options{}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Months != DefaultMonths || r.Total != 2 || r.Days[len(r.Days)-1].Count != 2 {
+		t.Errorf("report = %d months, %d total, today %+v", r.Months, r.Total, r.Days[len(r.Days)-1])
+	}
+}
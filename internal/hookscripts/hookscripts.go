@@ -0,0 +1,165 @@
+// Package hookscripts runs a project's hook scripts on local CLI events.
+//
+// Scripts live in .todos/hooks, named after the event they handle, and
+// must be executable. Each runs from the project root with the issue as
+// JSON on stdin and the event in TD_* environment variables, like a git
+// hook. pre- scripts run before the action and can stop it by exiting
+// non-zero; post- scripts run once the command has finished. Unlike policy
+// hooks and webhooks, they only run for td commands on this machine.
+package hookscripts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/models"
+)
+
+// Events
+const (
+	PostCreate     = "post-create"     // after an issue is created
+	PostTransition = "post-transition" // after an issue's status changes
+	PreClose       = "pre-close"       // before an issue is closed; non-zero exit stops it
+)
+
+// Failure policies
+const (
+	OnFailureFail   = "fail"   // stop a pre- action, or fail the command after a post- script
+	OnFailureWarn   = "warn"   // print a warning and carry on
+	OnFailureIgnore = "ignore" // carry on silently
+)
+
+// DefaultTimeout bounds a script when no timeout is configured
+const DefaultTimeout = 10 * time.Second
+
+// Events lists the events a script can handle, in the order they are
+// documented
+func Events() []string {
+	return []string{PostCreate, PostTransition, PreClose}
+}
+
+// IsValidOnFailure reports whether policy is a known failure policy
+func IsValidOnFailure(policy string) bool {
+	switch policy {
+	case OnFailureFail, OnFailureWarn, OnFailureIgnore:
+		return true
+	}
+	return false
+}
+
+// Dir returns the project's hook script directory
+func Dir(baseDir string) string {
+	return filepath.Join(baseDir, ".todos", "hooks")
+}
+
+// Find returns the script for an event, or "" when the project has no
+// executable script for it
+func Find(baseDir, event string) string {
+	path := filepath.Join(Dir(baseDir), event)
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0111 == 0 {
+		return ""
+	}
+	return path
+}
+
+// Any reports whether the project has a script for any event
+func Any(baseDir string) bool {
+	for _, event := range Events() {
+		if Find(baseDir, event) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Settings returns the configured timeout and failure policy for an event,
+// with defaults filled in: fail for pre- scripts, warn for post- scripts
+func Settings(baseDir, event string) (time.Duration, string) {
+	var sc models.ScriptHooksConfig
+	if cfg, err := config.GetScriptHooksConfig(baseDir); err == nil && cfg != nil {
+		sc = *cfg
+	}
+	timeout := DefaultTimeout
+	if sc.TimeoutSeconds > 0 {
+		timeout = time.Duration(sc.TimeoutSeconds) * time.Second
+	}
+	policy := sc.OnFailure
+	if policy == "" {
+		policy = OnFailureWarn
+		if strings.HasPrefix(event, "pre-") {
+			policy = OnFailureFail
+		}
+	}
+	return timeout, policy
+}
+
+// Event is one occurrence of an event for a script
+type Event struct {
+	Name      string
+	Issue     *models.Issue
+	From      models.Status // status before a transition; empty otherwise
+	To        models.Status // status after a transition, or the new issue's status
+	SessionID string
+}
+
+// Error is a script that exited non-zero or ran past its timeout
+type Error struct {
+	Event   string
+	IssueID string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s hook failed for %s: %s", e.Event, e.IssueID, e.Message)
+}
+
+// Run runs the project's script for an event, if it has one. It returns an
+// *Error when the script fails, whatever the failure policy; callers apply
+// the policy from Settings.
+func Run(baseDir string, ev Event) error {
+	path := Find(baseDir, ev.Name)
+	if path == "" {
+		return nil
+	}
+	timeout, _ := Settings(baseDir, ev.Name)
+	c, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	issueJSON, _ := json.Marshal(ev.Issue)
+	cmd := exec.CommandContext(c, path)
+	cmd.Dir = baseDir
+	cmd.Stdin = bytes.NewReader(issueJSON)
+	cmd.Env = append(os.Environ(),
+		"TD_HOOK="+ev.Name,
+		"TD_ISSUE_ID="+ev.Issue.ID,
+		"TD_FROM_STATUS="+string(ev.From),
+		"TD_TO_STATUS="+string(ev.To),
+		"TD_SESSION_ID="+ev.SessionID,
+		"TD_PRIORITY="+string(ev.Issue.Priority),
+		"TD_TYPE="+string(ev.Issue.Type),
+	)
+	// Don't let a background child holding the output pipe outlive the timeout
+	cmd.WaitDelay = time.Second
+
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	if c.Err() == context.DeadlineExceeded {
+		return &Error{Event: ev.Name, IssueID: ev.Issue.ID, Message: fmt.Sprintf("timed out after %s", timeout)}
+	}
+	msg, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	if msg == "" {
+		msg = err.Error()
+	}
+	return &Error{Event: ev.Name, IssueID: ev.Issue.ID, Message: msg}
+}
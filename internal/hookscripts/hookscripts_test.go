@@ -0,0 +1,91 @@
+package hookscripts
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/models"
+)
+
+func writeScript(t *testing.T, baseDir, event, body string) {
+	t.Helper()
+	if err := os.MkdirAll(Dir(baseDir), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(Dir(baseDir), event), []byte("#!/bin/sh\n"+body+"\n"), 0755); err != nil {
+		t.Fatalf("write script: %v", err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	issue := &models.Issue{ID: "td-abc123", Title: "Hook script test", Status: models.StatusClosed, Priority: models.PriorityP1, Type: models.TypeBug}
+	ev := Event{Name: PreClose, Issue: issue, From: models.StatusInReview, To: models.StatusClosed, SessionID: "ses_1"}
+
+	// No script is not an error
+	if err := Run(dir, ev); err != nil {
+		t.Fatalf("Run without script: %v", err)
+	}
+
+	out := filepath.Join(dir, "out")
+	writeScript(t, dir, PreClose, `echo "$TD_HOOK $TD_ISSUE_ID $TD_FROM_STATUS $TD_TO_STATUS $TD_PRIORITY" > out; cat >> out`)
+	if err := Run(dir, ev); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("script output: %v", err)
+	}
+	if got := string(data); !strings.HasPrefix(got, "pre-close td-abc123 in_review closed P1\n") || !strings.Contains(got, `"title":"Hook script test"`) {
+		t.Errorf("script saw %q", got)
+	}
+
+	writeScript(t, dir, PreClose, `echo "tests are failing" >&2; exit 1`)
+	var serr *Error
+	if err := Run(dir, ev); !errors.As(err, &serr) || serr.Message != "tests are failing" {
+		t.Errorf("Run failing script = %v, want message from stderr", err)
+	}
+
+	// A script without the executable bit is not run
+	if err := os.Chmod(filepath.Join(Dir(dir), PreClose), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if Find(dir, PreClose) != "" || Run(dir, ev) != nil {
+		t.Error("non-executable script should be ignored")
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	dir := t.TempDir()
+	if err := config.SetScriptHooksConfig(dir, models.ScriptHooksConfig{TimeoutSeconds: 1}); err != nil {
+		t.Fatalf("set config: %v", err)
+	}
+	writeScript(t, dir, PostCreate, "sleep 5")
+
+	var serr *Error
+	err := Run(dir, Event{Name: PostCreate, Issue: &models.Issue{ID: "td-1"}})
+	if !errors.As(err, &serr) || !strings.Contains(serr.Message, "timed out") {
+		t.Errorf("Run = %v, want timeout", err)
+	}
+}
+
+func TestSettings(t *testing.T) {
+	dir := t.TempDir()
+	if timeout, policy := Settings(dir, PreClose); timeout != DefaultTimeout || policy != OnFailureFail {
+		t.Errorf("pre-close defaults = %s, %s", timeout, policy)
+	}
+	if _, policy := Settings(dir, PostTransition); policy != OnFailureWarn {
+		t.Errorf("post-transition default policy = %s, want warn", policy)
+	}
+
+	if err := config.SetScriptHooksConfig(dir, models.ScriptHooksConfig{TimeoutSeconds: 30, OnFailure: OnFailureIgnore}); err != nil {
+		t.Fatalf("set config: %v", err)
+	}
+	if timeout, policy := Settings(dir, PreClose); timeout.Seconds() != 30 || policy != OnFailureIgnore {
+		t.Errorf("configured settings = %s, %s", timeout, policy)
+	}
+}
@@ -0,0 +1,124 @@
+package i18n
+
+// es is the Spanish catalog
+var es = map[string]string{
+	// CLI validation
+	"issue ID required. Usage: td %s":                                              "se requiere el ID de la issue. Uso: td %s",
+	"title '%s' is too generic - describe what it does or fixes":                   "el título '%s' es demasiado genérico: describe qué hace o qué corrige",
+	"title too short (%d chars, need %d) - e.g. 'Fix login timeout' not 'Fix bug'": "título demasiado corto (%d caracteres, se necesitan %d): p. ej. 'Corregir timeout del login', no 'Corregir bug'",
+	"title too long (%d chars, max %d) - move details to description":              "título demasiado largo (%d caracteres, máximo %d): pasa los detalles a la descripción",
+
+	// Monitor forms and actions
+	"title is required":                      "el título es obligatorio",
+	"A reason is required to block":          "Hace falta un motivo para bloquear",
+	"Comment cannot be empty":                "El comentario no puede estar vacío",
+	"Points must be 1, 2, 3, 5, 8, 13 or 21": "Los puntos deben ser 1, 2, 3, 5, 8, 13 o 21",
+
+	// Monitor footer
+	"n:new e:edit x:del a:approve r:review  S:sort T:type c:closed b:boards  /:search s:stats tab:panel ?:help":        "n:nueva e:editar x:borrar a:aprobar r:revisión  S:orden T:tipo c:cerradas b:tableros  /:buscar s:stats tab:panel ?:ayuda",
+	"n:new e:edit x:del a:approve  v:view S:sort T:type F:filter c:closed b:boards  /:search s:stats tab:panel ?:help": "n:nueva e:editar x:borrar a:aprobar  v:vista S:orden T:tipo F:filtro c:cerradas b:tableros  /:buscar s:stats tab:panel ?:ayuda",
+	"↑↓:scroll  ←→:prev/next  y:copy  esc:close  r:refresh":                                                            "↑↓:desplazar  ←→:anterior/siguiente  y:copiar  esc:cerrar  r:actualizar",
+	"↑↓:scroll  Ctrl+d/u:½page  esc:close  r:refresh":                                                                  "↑↓:desplazar  Ctrl+d/u:½página  esc:cerrar  r:actualizar",
+	"%d active":           "%d activas",
+	"%d active · %d idle": "%d activas · %d inactivas",
+	"%d HANDOFF":          "%d HANDOFF",
+	"%d TO REVIEW":        "%d POR REVISAR",
+	"UPDATE: %s":          "ACTUALIZACIÓN: %s",
+	"Last: %s":            "Últ.: %s",
+
+	// Monitor due dates
+	"OVERDUE by 1 day":   "VENCIDA hace 1 día",
+	"OVERDUE by %d days": "VENCIDA hace %d días",
+	"due TODAY":          "vence HOY",
+	"(%d days)":          "(%d días)",
+
+	// Monitor help
+	"MONITOR TUI - Key Bindings":   "MONITOR TUI - Atajos de teclado",
+	"NAVIGATION:":                  "NAVEGACIÓN:",
+	"MODALS:":                      "VENTANAS:",
+	"EPIC TASKS (when focused):":   "TAREAS DE LA ÉPICA (con foco):",
+	"CRUD:":                        "CREAR Y EDITAR:",
+	"CONFIRMATION DIALOGS:":        "DIÁLOGOS DE CONFIRMACIÓN:",
+	"FORM (when editing):":         "FORMULARIO (al editar):",
+	"ACTIONS:":                     "ACCIONES:",
+	"GETTING STARTED:":             "PRIMEROS PASOS:",
+	"HANDOFFS MODAL:":              "VENTANA DE HANDOFFS:",
+	"BOARDS:":                      "TABLEROS:",
+	"SEARCH (TDQ Query Language):": "BÚSQUEDA (lenguaje de consulta TDQ):",
+	"MOUSE:":                       "RATÓN:",
+	"Press ? to close help":        "Pulsa ? para cerrar la ayuda",
+	"YOUR KEY BINDINGS:":           "TUS ATAJOS:",
+	"(no key)":                     "(sin tecla)",
+	"Unbound":                      "Sin asignar",
+
+	"Actions menu (start/review/approve/block/comment)":  "Menú de acciones (empezar/revisar/aprobar/bloquear/comentar)",
+	"Approve issue (Task List reviewable)":               "Aprobar la issue (pendientes de revisión en la lista)",
+	"Cancel (delete dialog)":                             "Cancelar (diálogo de borrado)",
+	"Cancel and close":                                   "Cancelar y cerrar",
+	"Cancel form":                                        "Cancelar el formulario",
+	"Cancel search":                                      "Cancelar la búsqueda",
+	"Clear search filter":                                "Quitar el filtro de búsqueda",
+	"Click buttons directly":                             "Pulsar los botones directamente",
+	"Close handoffs modal":                               "Cerrar la ventana de handoffs",
+	"Close issue":                                        "Cerrar la issue",
+	"Close modal":                                        "Cerrar la ventana",
+	"Close modal (return to previous)":                   "Cerrar la ventana (volver a la anterior)",
+	"Close picker / exit board":                          "Cerrar el selector / salir del tablero",
+	"Confirm (delete dialog)":                            "Confirmar (diálogo de borrado)",
+	"Confirm search":                                     "Confirmar la búsqueda",
+	"Copy epic to clipboard (markdown)":                  "Copiar la épica al portapapeles (markdown)",
+	"Copy to clipboard (markdown)":                       "Copiar al portapapeles (markdown)",
+	"Cycle sort (priority/created/updated)":              "Cambiar el orden (prioridad/creación/actualización)",
+	"Cycle status filter":                                "Cambiar el filtro de estado",
+	"Cycle type filter (epic/task/bug/...)":              "Cambiar el filtro de tipo (épica/tarea/bug/...)",
+	"Delete character":                                   "Borrar un carácter",
+	"Delete issue (confirmation required)":               "Borrar la issue (pide confirmación)",
+	"Edit description in $EDITOR":                        "Editar la descripción en $EDITOR",
+	"Edit selected/open issue":                           "Editar la issue seleccionada o abierta",
+	"Execute focused button":                             "Ejecutar el botón con foco",
+	"Exit task list":                                     "Salir de la lista de tareas",
+	"Filter section under cursor (TDQ, saved per board)": "Filtrar la sección bajo el cursor (TDQ, se guarda por tablero)",
+	"Focus epic task list (if epic)":                     "Enfocar la lista de tareas de la épica (si es épica)",
+	"Full page down/up":                                  "Página completa abajo/arriba",
+	"Half page down/up":                                  "Media página abajo/arriba",
+	"Install td instructions to agent file":              "Instalar las instrucciones de td en el archivo del agente",
+	"Jump to bottom/top":                                 "Ir al final/al principio",
+	"Mark for review (Current Work) / Refresh":           "Enviar a revisión (Trabajo actual) / Actualizar",
+	"Move cursor":                                        "Mover el cursor",
+	"Move issue down/up in column":                       "Bajar/subir la issue en la columna",
+	"Move issue to bottom/top":                           "Llevar la issue al final/al principio",
+	"Narrow/widen preview pane":                          "Estrechar/ensanchar la vista previa",
+	"Navigate prev/next issue":                           "Ir a la issue anterior/siguiente",
+	"New issue":                                          "Nueva issue",
+	"Open board picker":                                  "Abrir el selector de tableros",
+	"Open focused epic / close modal":                    "Abrir la épica con foco / cerrar la ventana",
+	"Open getting started guide":                         "Abrir la guía de primeros pasos",
+	"Open issue details":                                 "Abrir los detalles de la issue",
+	"Open issue for selected handoff":                    "Abrir la issue del handoff seleccionado",
+	"Open selected task":                                 "Abrir la tarea seleccionada",
+	"Quit":                                               "Salir",
+	"Refresh handoffs":                                   "Actualizar los handoffs",
+	"Refresh modal content":                              "Actualizar el contenido de la ventana",
+	"Resize panels":                                      "Cambiar el tamaño de los paneles",
+	"Reopen closed issue":                                "Reabrir una issue cerrada",
+	"Save form":                                          "Guardar el formulario",
+	"Scroll (k at top focuses parent epic)":              "Desplazar (k arriba del todo enfoca la épica padre)",
+	"Scroll hovered panel":                               "Desplazar el panel bajo el ratón",
+	"Search tasks":                                       "Buscar tareas",
+	"Select board":                                       "Elegir un tablero",
+	"Select handoff":                                     "Elegir un handoff",
+	"Select panel/row":                                   "Elegir panel/fila",
+	"Select task in list":                                "Elegir una tarea de la lista",
+	"Show TDQ syntax help":                               "Mostrar la ayuda de sintaxis TDQ",
+	"Show handoffs modal":                                "Mostrar la ventana de handoffs",
+	"Show statistics dashboard":                          "Mostrar el panel de estadísticas",
+	"Switch between buttons":                             "Cambiar de botón",
+	"Switch between panels":                              "Cambiar de panel",
+	"Switch columns (swimlanes)":                         "Cambiar de columna (carriles)",
+	"Toggle closed issues":                               "Mostrar/ocultar issues cerradas",
+	"Toggle closed tasks":                                "Mostrar/ocultar tareas cerradas",
+	"Toggle extended fields":                             "Mostrar/ocultar campos extra",
+	"Toggle issue preview pane":                          "Mostrar/ocultar la vista previa",
+	"Taller/shorter panel":                               "Panel más alto/más bajo",
+	"Toggle swimlanes/backlog view":                      "Alternar vista de carriles/backlog",
+}
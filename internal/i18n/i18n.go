@@ -0,0 +1,107 @@
+// Package i18n translates td's user-facing messages.
+//
+// Messages are looked up by their English text, gettext style, so call
+// sites stay readable and an untranslated message falls back to English:
+//
+//	output.Error("%s", i18n.T("title too long (%d chars, max %d)", n, max))
+//
+// Catalogs map that English text, format verbs included, to a translation.
+// Only messages a catalog lists are translated; so far that is the monitor's
+// hints and help and the CLI's validation errors.
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// DefaultLocale is the language messages are written in
+const DefaultLocale = "en"
+
+// catalogs holds the translations for each locale other than English
+var catalogs = map[string]map[string]string{
+	"es": es,
+}
+
+var current atomic.Value // string
+
+// Locales lists the supported locales, English first
+func Locales() []string {
+	locales := []string{DefaultLocale}
+	var others []string
+	for l := range catalogs {
+		others = append(others, l)
+	}
+	sort.Strings(others)
+	return append(locales, others...)
+}
+
+// Normalize reduces a locale name like "es_ES.UTF-8" or "es-MX" to a
+// supported locale, or "" when td has no catalog for its language
+func Normalize(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if i := strings.IndexAny(name, "_-.@"); i >= 0 {
+		name = name[:i]
+	}
+	if name == DefaultLocale {
+		return name
+	}
+	if _, ok := catalogs[name]; ok {
+		return name
+	}
+	return ""
+}
+
+// Detect picks the locale for a process: configured if it is supported,
+// else the first supported language in LC_ALL, LC_MESSAGES and LANG, as
+// gettext does, else English
+func Detect(configured string, getenv func(string) string) string {
+	if l := Normalize(configured); l != "" {
+		return l
+	}
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if l := Normalize(getenv(key)); l != "" {
+			return l
+		}
+	}
+	return DefaultLocale
+}
+
+// Use makes locale the one T translates into; unsupported locales and ""
+// restore English
+func Use(locale string) {
+	if l := Normalize(locale); l != "" {
+		current.Store(l)
+		return
+	}
+	current.Store(DefaultLocale)
+}
+
+// Current returns the locale set with Use, or English
+func Current() string {
+	if l, ok := current.Load().(string); ok {
+		return l
+	}
+	return DefaultLocale
+}
+
+// T translates msg into the current locale and formats it with args.
+// Messages without a translation come back in English.
+func T(msg string, args ...interface{}) string {
+	msg = Text(msg)
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// Text translates msg without formatting it, for messages held in
+// variables, like help tables
+func Text(msg string) string {
+	if translated, ok := catalogs[Current()][msg]; ok {
+		return translated
+	}
+	return msg
+}
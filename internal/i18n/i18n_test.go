@@ -0,0 +1,64 @@
+package i18n
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestT(t *testing.T) {
+	defer Use("")
+
+	Use("es_ES.UTF-8")
+	if got := Current(); got != "es" {
+		t.Fatalf("Current() = %q, want es", got)
+	}
+	if got := T("%d TO REVIEW", 3); got != "3 POR REVISAR" {
+		t.Errorf("T translated = %q", got)
+	}
+	if got := T("no catalog has this %s", "message"); got != "no catalog has this message" {
+		t.Errorf("T untranslated = %q", got)
+	}
+
+	Use("xx")
+	if got := T("%d TO REVIEW", 3); got != "3 TO REVIEW" {
+		t.Errorf("T after unsupported locale = %q", got)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(key string) string { return vars[key] }
+	}
+	tests := []struct {
+		configured string
+		vars       map[string]string
+		want       string
+	}{
+		{"", nil, "en"},
+		{"es", nil, "es"},
+		{"", map[string]string{"LANG": "es_MX.UTF-8"}, "es"},
+		{"", map[string]string{"LC_ALL": "C", "LANG": "es_ES"}, "es"},
+		{"", map[string]string{"LC_MESSAGES": "en_US", "LANG": "es_ES"}, "en"},
+		{"fr", map[string]string{"LANG": "es_ES"}, "es"},
+		{"en", map[string]string{"LANG": "es_ES"}, "en"},
+	}
+	for _, tt := range tests {
+		if got := Detect(tt.configured, env(tt.vars)); got != tt.want {
+			t.Errorf("Detect(%q, %v) = %q, want %q", tt.configured, tt.vars, got, tt.want)
+		}
+	}
+}
+
+// A translation must take the same arguments as its English message, or
+// T would print %!d(MISSING) and friends
+func TestCatalogVerbsMatch(t *testing.T) {
+	verb := regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+	for locale, catalog := range catalogs {
+		for msg, translated := range catalog {
+			if want, got := verb.FindAllString(msg, -1), verb.FindAllString(translated, -1); !slices.Equal(want, got) {
+				t.Errorf("%s: %q has verbs %v, want %v", locale, translated, got, want)
+			}
+		}
+	}
+}
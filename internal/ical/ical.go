@@ -0,0 +1,126 @@
+// Package ical writes minimal iCalendar (RFC 5545) feeds of all-day events.
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const dateFormat = "20060102"
+
+// Event is an all-day calendar event spanning Start through End inclusive.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	URL         string
+	Categories  []string
+	Start       time.Time
+	End         time.Time // zero = single-day event
+}
+
+// Calendar is a named collection of events.
+type Calendar struct {
+	Name   string
+	Events []Event
+	Stamp  time.Time // DTSTAMP for every event; zero = now
+}
+
+// Write renders the calendar as text/calendar with CRLF line endings and
+// lines folded at 75 octets.
+func (c *Calendar) Write(w io.Writer) error {
+	stamp := c.Stamp
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+	bw := bufio.NewWriter(w)
+	lw := &lineWriter{w: bw}
+
+	lw.line("BEGIN:VCALENDAR")
+	lw.line("VERSION:2.0")
+	lw.line("PRODID:-//td//td calendar//EN")
+	lw.line("CALSCALE:GREGORIAN")
+	lw.line("METHOD:PUBLISH")
+	if c.Name != "" {
+		lw.line("X-WR-CALNAME:" + escapeText(c.Name))
+	}
+
+	for _, ev := range c.Events {
+		end := ev.End
+		if end.IsZero() || end.Before(ev.Start) {
+			end = ev.Start
+		}
+		lw.line("BEGIN:VEVENT")
+		lw.line("UID:" + ev.UID)
+		lw.line("DTSTAMP:" + stamp.UTC().Format("20060102T150405Z"))
+		lw.line("DTSTART;VALUE=DATE:" + ev.Start.Format(dateFormat))
+		// DTEND is exclusive for all-day events
+		lw.line("DTEND;VALUE=DATE:" + end.AddDate(0, 0, 1).Format(dateFormat))
+		lw.line("SUMMARY:" + escapeText(ev.Summary))
+		if ev.Description != "" {
+			lw.line("DESCRIPTION:" + escapeText(ev.Description))
+		}
+		if ev.URL != "" {
+			lw.line("URL:" + ev.URL)
+		}
+		if len(ev.Categories) > 0 {
+			cats := make([]string, len(ev.Categories))
+			for i, cat := range ev.Categories {
+				cats[i] = escapeText(cat)
+			}
+			lw.line("CATEGORIES:" + strings.Join(cats, ","))
+		}
+		lw.line("TRANSP:TRANSPARENT")
+		lw.line("END:VEVENT")
+	}
+
+	lw.line("END:VCALENDAR")
+	if lw.err != nil {
+		return lw.err
+	}
+	return bw.Flush()
+}
+
+// lineWriter writes folded content lines, remembering the first error
+type lineWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (lw *lineWriter) line(s string) {
+	if lw.err != nil {
+		return
+	}
+	_, lw.err = lw.w.WriteString(fold(s) + "\r\n")
+}
+
+// fold splits a content line into 75-octet chunks joined by CRLF + space,
+// never splitting a UTF-8 sequence.
+func fold(s string) string {
+	const limit = 75
+	if len(s) <= limit {
+		return s
+	}
+	var sb strings.Builder
+	width, max := 0, limit
+	for _, r := range s {
+		n := utf8.RuneLen(r)
+		if width+n > max {
+			// Continuation lines start with a space, which counts toward the limit
+			sb.WriteString("\r\n ")
+			width, max = 0, limit-1
+		}
+		sb.WriteRune(r)
+		width += n
+	}
+	return sb.String()
+}
+
+// escapeText escapes a TEXT property value.
+func escapeText(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return r.Replace(s)
+}
@@ -0,0 +1,66 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWrite(t *testing.T) {
+	day := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+	cal := &Calendar{
+		Name:  "td: project",
+		Stamp: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Events: []Event{
+			{UID: "due-td-1@td", Summary: "Due: td-1 Fix login, again; now", Start: day("2025-01-10"), Categories: []string{"due"}},
+			{UID: "sprint-s1@td", Summary: "Sprint s1", Start: day("2025-01-06"), End: day("2025-01-17"), Description: "line one\nline two"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := cal.Write(&buf); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"X-WR-CALNAME:td: project\r\n",
+		"DTSTAMP:20250102T030405Z\r\n",
+		"DTSTART;VALUE=DATE:20250110\r\nDTEND;VALUE=DATE:20250111\r\n",
+		`SUMMARY:Due: td-1 Fix login\, again\; now`,
+		"DTSTART;VALUE=DATE:20250106\r\nDTEND;VALUE=DATE:20250118\r\n",
+		`DESCRIPTION:line one\nline two`,
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in\n%s", want, out)
+		}
+	}
+	if strings.Count(out, "BEGIN:VEVENT") != 2 {
+		t.Error("expected two events")
+	}
+}
+
+func TestFold(t *testing.T) {
+	long := "SUMMARY:" + strings.Repeat("é", 100)
+	folded := fold(long)
+	for i, line := range strings.Split(folded, "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line %d is %d octets", i, len(line))
+		}
+		if i > 0 && !strings.HasPrefix(line, " ") {
+			t.Errorf("continuation line %d missing leading space", i)
+		}
+	}
+	if unfolded := strings.ReplaceAll(folded, "\r\n ", ""); unfolded != long {
+		t.Error("unfolding should restore the original line")
+	}
+	if fold("short") != "short" {
+		t.Error("short lines should not be folded")
+	}
+}
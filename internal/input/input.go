@@ -53,3 +53,14 @@ func ReadLinesFromReader(r io.Reader) []string {
 	}
 	return lines
 }
+
+// ReadText reads a whole text from r, as for a description piped to a - flag
+// value. Lines keep their whitespace; the trailing newline is dropped.
+func ReadText(r io.Reader) (string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	text := strings.TrimSuffix(string(data), "\n")
+	return strings.TrimSuffix(text, "\r"), nil
+}
@@ -264,3 +264,14 @@ func TestExpandFlagValuesNilSlice(t *testing.T) {
 		t.Errorf("Expected nil or empty result, got %v", result)
 	}
 }
+
+// TestReadTextKeepsFormatting tests that only the final newline is dropped
+func TestReadTextKeepsFormatting(t *testing.T) {
+	text, err := ReadText(strings.NewReader("## Steps\n\n  1. indented\n\n"))
+	if err != nil {
+		t.Fatalf("ReadText: %v", err)
+	}
+	if text != "## Steps\n\n  1. indented\n" {
+		t.Errorf("ReadText() = %q", text)
+	}
+}
@@ -0,0 +1,215 @@
+// Package integrations bridges td to chat platforms. It answers Slack and
+// Discord slash commands (/td create ..., /td show td-abc) and posts td
+// activity to channel webhooks.
+package integrations
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/marcus/td/internal/confidential"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+// Supported integration kinds.
+const (
+	KindSlack   = "slack"
+	KindDiscord = "discord"
+)
+
+// listLimit caps the issues returned by the list command
+const listLimit = 10
+
+// maxDescription caps description text shown by the show command
+const maxDescription = 300
+
+// Field is a labeled value in a reply, rendered as a Slack field or
+// Discord embed field.
+type Field struct {
+	Label string
+	Value string
+}
+
+// Reply is a platform-neutral response to a chat command.
+type Reply struct {
+	Title   string
+	Text    string  // plain text; lines are preserved
+	Fields  []Field // optional label/value pairs
+	Public  bool    // visible to the whole channel, not just the caller
+	Error   bool
+	Changed bool // the command modified project data
+}
+
+func errorReply(format string, args ...interface{}) Reply {
+	return Reply{Text: fmt.Sprintf(format, args...), Error: true}
+}
+
+// Commander runs chat commands against a project database.
+type Commander struct {
+	DB        *db.DB
+	SessionID string
+	TitleMin  int
+	TitleMax  int
+	Inbox     bool // created issues go to the triage inbox
+}
+
+// Run executes one command line, e.g. "show td-abc" or
+// "create -p P1 Fix login timeout". user and source (e.g. "slack") are
+// recorded on created issues.
+func (c *Commander) Run(text, user, source string) Reply {
+	args := strings.Fields(text)
+	if len(args) == 0 {
+		return helpReply()
+	}
+	switch strings.ToLower(args[0]) {
+	case "help":
+		return helpReply()
+	case "show", "view":
+		if len(args) != 2 {
+			return errorReply("usage: show <issue-id>")
+		}
+		return c.show(args[1])
+	case "create", "new", "add":
+		return c.create(args[1:], user, source)
+	case "list", "ls":
+		return c.list()
+	default:
+		return errorReply("unknown command %q - try help", args[0])
+	}
+}
+
+func helpReply() Reply {
+	return Reply{
+		Title: "td commands",
+		Text: strings.Join([]string{
+			"create [-p P0-P4] [-t type] [-l label,label] <title>",
+			"show <issue-id>",
+			"list",
+			"help",
+		}, "\n"),
+	}
+}
+
+func (c *Commander) show(id string) Reply {
+	issue, err := c.DB.GetIssue(db.NormalizeIssueID(id))
+	if err != nil {
+		return errorReply("issue not found: %s", id)
+	}
+	reply := issueReply(issue)
+	// Chat channels never see a confidential issue's text
+	desc := confidential.Redact(issue.Description)
+	if utf8.RuneCountInString(desc) > maxDescription {
+		desc = string([]rune(desc)[:maxDescription]) + "…"
+	}
+	reply.Text = desc
+	return reply
+}
+
+func (c *Commander) create(args []string, user, source string) Reply {
+	issue := &models.Issue{
+		Type:     models.TypeTask,
+		Priority: models.PriorityP2,
+	}
+
+	var titleWords []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "-p", "--priority", "-t", "--type", "-l", "--labels":
+			if i+1 >= len(args) {
+				return errorReply("%s needs a value", arg)
+			}
+			i++
+			val := args[i]
+			switch arg {
+			case "-p", "--priority":
+				issue.Priority = models.NormalizePriority(val)
+				if !models.IsValidPriority(issue.Priority) {
+					return errorReply("invalid priority %q (P0-P4)", val)
+				}
+			case "-t", "--type":
+				issue.Type = models.NormalizeType(val)
+				if !models.IsValidType(issue.Type) {
+					return errorReply("invalid type %q (bug, feature, task, epic, chore)", val)
+				}
+			default:
+				for _, l := range strings.Split(val, ",") {
+					if l = strings.TrimSpace(l); l != "" {
+						issue.Labels = append(issue.Labels, l)
+					}
+				}
+			}
+		default:
+			titleWords = append(titleWords, arg)
+		}
+	}
+
+	issue.Title = strings.Join(titleWords, " ")
+	titleLen := utf8.RuneCountInString(issue.Title)
+	switch {
+	case issue.Title == "":
+		return errorReply("usage: create [-p P0-P4] [-t type] [-l labels] <title>")
+	case c.TitleMin > 0 && titleLen < c.TitleMin:
+		return errorReply("title too short (%d chars, min %d)", titleLen, c.TitleMin)
+	case c.TitleMax > 0 && titleLen > c.TitleMax:
+		return errorReply("title too long (%d chars, max %d)", titleLen, c.TitleMax)
+	}
+
+	if user != "" {
+		issue.Description = fmt.Sprintf("Created from %s by %s", source, user)
+	}
+	issue.CreatorSession = c.SessionID
+	issue.Inbox = c.Inbox
+	if err := c.DB.CreateIssueLogged(issue, c.SessionID); err != nil {
+		return errorReply("failed to create issue: %v", err)
+	}
+	_ = c.DB.RecordSessionAction(issue.ID, c.SessionID, models.ActionSessionCreated)
+
+	reply := issueReply(issue)
+	reply.Title = "Created " + reply.Title
+	reply.Public = true
+	reply.Changed = true
+	return reply
+}
+
+func (c *Commander) list() Reply {
+	issues, err := c.DB.ListIssues(db.ListIssuesOptions{
+		Status:          []models.Status{models.StatusOpen, models.StatusInProgress, models.StatusBlocked, models.StatusInReview},
+		ExcludeDeferred: true,
+		SortBy:          "priority",
+		Limit:           listLimit,
+	})
+	if err != nil {
+		return errorReply("failed to list issues: %v", err)
+	}
+	if len(issues) == 0 {
+		return Reply{Title: "Open issues", Text: "No open issues"}
+	}
+	lines := make([]string, len(issues))
+	for i, issue := range issues {
+		lines[i] = fmt.Sprintf("%s  %s  %s  %s", issue.ID, issue.Priority, issue.Status, issue.Title)
+	}
+	return Reply{Title: fmt.Sprintf("Open issues (top %d)", len(issues)), Text: strings.Join(lines, "\n")}
+}
+
+// issueReply summarizes an issue as title and fields
+func issueReply(issue *models.Issue) Reply {
+	fields := []Field{
+		{Label: "Status", Value: string(issue.Status)},
+		{Label: "Priority", Value: string(issue.Priority)},
+		{Label: "Type", Value: string(issue.Type)},
+	}
+	if issue.Points > 0 {
+		fields = append(fields, Field{Label: "Points", Value: strconv.Itoa(issue.Points)})
+	}
+	if len(issue.Labels) > 0 {
+		fields = append(fields, Field{Label: "Labels", Value: strings.Join(issue.Labels, ", ")})
+	}
+	if issue.ImplementerSession != "" {
+		fields = append(fields, Field{Label: "Implementer", Value: issue.ImplementerSession})
+	}
+	return Reply{Title: fmt.Sprintf("%s %s", issue.ID, issue.Title), Fields: fields}
+}
@@ -0,0 +1,158 @@
+package integrations
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Discord interaction and response types.
+const (
+	DiscordPing               = 1
+	DiscordApplicationCommand = 2
+
+	discordPong              = 1
+	discordChannelMessage    = 4
+	discordEphemeralFlag     = 1 << 6
+	discordSubcommandOption  = 1
+	discordSubcommandGroup   = 2
+	discordColorDefault      = 0x5865F2
+	discordColorError        = 0xED4245
+	discordMaxEmbedFields    = 25
+	discordMaxEmbedFieldSize = 1024
+)
+
+// VerifyDiscord checks the Ed25519 signature of an inbound interaction
+// against the application's public key.
+func VerifyDiscord(publicKeyHex string, header http.Header, body []byte) error {
+	key, err := hex.DecodeString(publicKeyHex)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("%w: invalid public key configured", ErrBadSignature)
+	}
+	sig, err := hex.DecodeString(header.Get("X-Signature-Ed25519"))
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return fmt.Errorf("%w: missing or malformed signature", ErrBadSignature)
+	}
+	msg := append([]byte(header.Get("X-Signature-Timestamp")), body...)
+	if !ed25519.Verify(ed25519.PublicKey(key), msg, sig) {
+		return ErrBadSignature
+	}
+	return nil
+}
+
+// DiscordUser is the user who invoked an interaction.
+type DiscordUser struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+}
+
+// DiscordOption is a slash-command option or subcommand.
+type DiscordOption struct {
+	Name    string          `json:"name"`
+	Type    int             `json:"type"`
+	Value   interface{}     `json:"value,omitempty"`
+	Options []DiscordOption `json:"options,omitempty"`
+}
+
+// DiscordInteraction is the subset of an interaction payload td uses.
+type DiscordInteraction struct {
+	Type int `json:"type"`
+	Data struct {
+		Name    string          `json:"name"`
+		Options []DiscordOption `json:"options"`
+	} `json:"data"`
+	Member *struct {
+		User DiscordUser `json:"user"`
+	} `json:"member,omitempty"`
+	User *DiscordUser `json:"user,omitempty"`
+}
+
+// ParseDiscordInteraction decodes an interaction request body.
+func ParseDiscordInteraction(body []byte) (DiscordInteraction, error) {
+	var in DiscordInteraction
+	if err := json.Unmarshal(body, &in); err != nil {
+		return in, fmt.Errorf("parse interaction: %w", err)
+	}
+	return in, nil
+}
+
+// CommandText flattens the command's subcommands and option values into a
+// command line, so both "/td text:show td-abc" and "/td show id:td-abc"
+// become "show td-abc".
+func (in DiscordInteraction) CommandText() string {
+	var parts []string
+	var walk func(opts []DiscordOption)
+	walk = func(opts []DiscordOption) {
+		for _, o := range opts {
+			switch o.Type {
+			case discordSubcommandOption, discordSubcommandGroup:
+				parts = append(parts, o.Name)
+				walk(o.Options)
+			default:
+				if o.Value != nil {
+					parts = append(parts, fmt.Sprint(o.Value))
+				}
+			}
+		}
+	}
+	walk(in.Data.Options)
+	return strings.Join(parts, " ")
+}
+
+// UserName returns the invoking user's name, from either a guild member or
+// a direct-message user.
+func (in DiscordInteraction) UserName() string {
+	if in.Member != nil {
+		return in.Member.User.Username
+	}
+	if in.User != nil {
+		return in.User.Username
+	}
+	return ""
+}
+
+// DiscordPong is the response to a ping interaction.
+func DiscordPong() map[string]interface{} {
+	return map[string]interface{}{"type": discordPong}
+}
+
+// DiscordResponse renders a reply as an interaction response with an embed.
+func DiscordResponse(r Reply) map[string]interface{} {
+	embed := map[string]interface{}{"color": discordColorDefault}
+	if r.Error {
+		embed["color"] = discordColorError
+	}
+	if r.Title != "" {
+		embed["title"] = r.Title
+	}
+	if r.Text != "" {
+		text := r.Text
+		if strings.Contains(text, "\n") && !r.Error && r.Title != "" {
+			text = "```\n" + text + "\n```"
+		}
+		embed["description"] = text
+	}
+	if len(r.Fields) > 0 {
+		fields := make([]map[string]interface{}, 0, len(r.Fields))
+		for i, f := range r.Fields {
+			if i == discordMaxEmbedFields {
+				break
+			}
+			value := f.Value
+			if len(value) > discordMaxEmbedFieldSize {
+				value = value[:discordMaxEmbedFieldSize]
+			}
+			fields = append(fields, map[string]interface{}{"name": f.Label, "value": value, "inline": true})
+		}
+		embed["fields"] = fields
+	}
+
+	data := map[string]interface{}{"embeds": []interface{}{embed}}
+	if !r.Public {
+		data["flags"] = discordEphemeralFlag
+	}
+	return map[string]interface{}{"type": discordChannelMessage, "data": data}
+}
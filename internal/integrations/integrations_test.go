@@ -0,0 +1,188 @@
+package integrations
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/confidential"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/webhook"
+)
+
+func signSlack(secret, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + ts + ":"))
+	mac.Write(body)
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifySlack(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte("command=%2Ftd&text=show+td-abc")
+	ts := strconv.FormatInt(now.Unix(), 10)
+
+	h := http.Header{}
+	h.Set("X-Slack-Request-Timestamp", ts)
+	h.Set("X-Slack-Signature", signSlack("shh", ts, body))
+	if err := VerifySlack("shh", h, body, now); err != nil {
+		t.Errorf("valid signature rejected: %v", err)
+	}
+	if err := VerifySlack("other", h, body, now); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("wrong secret: err = %v", err)
+	}
+	if err := VerifySlack("shh", h, body, now.Add(10*time.Minute)); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("stale timestamp: err = %v", err)
+	}
+	if err := VerifySlack("shh", http.Header{}, body, now); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("missing headers: err = %v", err)
+	}
+}
+
+func TestVerifyDiscord(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := []byte(`{"type":1}`)
+	h := http.Header{}
+	h.Set("X-Signature-Timestamp", "1700000000")
+	h.Set("X-Signature-Ed25519", hex.EncodeToString(ed25519.Sign(priv, append([]byte("1700000000"), body...))))
+
+	if err := VerifyDiscord(hex.EncodeToString(pub), h, body); err != nil {
+		t.Errorf("valid signature rejected: %v", err)
+	}
+	if err := VerifyDiscord(hex.EncodeToString(pub), h, []byte(`{"type":2}`)); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("tampered body: err = %v", err)
+	}
+	if err := VerifyDiscord("not-hex", h, body); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("bad key: err = %v", err)
+	}
+}
+
+func TestDiscordCommandText(t *testing.T) {
+	raw := `{"type":2,"data":{"name":"td","options":[{"name":"show","type":1,"options":[{"name":"id","type":3,"value":"td-abc"}]}]},"member":{"user":{"id":"1","username":"ana"}}}`
+	in, err := ParseDiscordInteraction([]byte(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := in.CommandText(); got != "show td-abc" {
+		t.Errorf("CommandText = %q", got)
+	}
+	if in.UserName() != "ana" {
+		t.Errorf("UserName = %q", in.UserName())
+	}
+}
+
+func TestCommander(t *testing.T) {
+	database, err := db.Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+	c := &Commander{DB: database, SessionID: "ses_chat", TitleMin: 15, TitleMax: 100}
+
+	reply := c.Run("create -p P1 -t bug -l api,chat Login times out on mobile", "ana", "Slack")
+	if reply.Error || !reply.Changed || !reply.Public {
+		t.Fatalf("create reply = %+v", reply)
+	}
+	id := strings.Fields(strings.TrimPrefix(reply.Title, "Created "))[0]
+	issue, err := database.GetIssue(id)
+	if err != nil {
+		t.Fatalf("get created issue: %v", err)
+	}
+	if issue.Priority != "P1" || issue.Type != "bug" || len(issue.Labels) != 2 || issue.Description != "Created from Slack by ana" {
+		t.Errorf("created issue = %+v", issue)
+	}
+
+	if r := c.Run("show "+id, "", ""); r.Error || !strings.Contains(r.Title, "Login times out") || r.Public {
+		t.Errorf("show reply = %+v", r)
+	}
+	if r := c.Run("list", "", ""); !strings.Contains(r.Text, id) {
+		t.Errorf("list reply = %+v", r)
+	}
+
+	// Confidential text stays out of chat, sealed or not
+	t.Setenv(confidential.KeyEnv, "")
+	if _, _, err := confidential.EnsureKey(database.BaseDir()); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.SetConfidential(id, true, "ses_chat"); err != nil {
+		t.Fatal(err)
+	}
+	if r := c.Run("show "+id, "", ""); r.Text != confidential.Redacted {
+		t.Errorf("confidential show text = %q", r.Text)
+	}
+
+	for _, text := range []string{"create short", "create -p P9 A perfectly long title", "show td-nope", "frobnicate"} {
+		if r := c.Run(text, "", ""); !r.Error || r.Changed {
+			t.Errorf("Run(%q) = %+v, want error", text, r)
+		}
+	}
+	if r := c.Run("", "", ""); r.Title != "td commands" {
+		t.Errorf("empty command should show help, got %+v", r)
+	}
+}
+
+func TestResponses(t *testing.T) {
+	reply := Reply{Title: "td-1 <Fix>", Fields: []Field{{Label: "Status", Value: "open"}}, Public: true}
+
+	slack := SlackResponse(reply)
+	if slack["response_type"] != "in_channel" {
+		t.Errorf("slack response_type = %v", slack["response_type"])
+	}
+	blocks := slack["blocks"].([]map[string]interface{})
+	if got := blocks[0]["text"].(map[string]string)["text"]; got != "*td-1 &lt;Fix&gt;*" {
+		t.Errorf("slack title block = %v", got)
+	}
+
+	discord := DiscordResponse(Reply{Text: "nope", Error: true})
+	d := discord["data"].(map[string]interface{})
+	if discord["type"] != 4 || d["flags"] != discordEphemeralFlag {
+		t.Errorf("discord response = %v", discord)
+	}
+}
+
+func TestFormatActionsAndPost(t *testing.T) {
+	actions := []webhook.ActionPayload{
+		{ActionType: "create", EntityType: "issue", EntityID: "td-1", NewData: `{"title":"Add CSV import"}`, SessionID: "ses_a"},
+		{ActionType: "update", EntityType: "issue", EntityID: "td-1"},
+		{ActionType: "add_dependency", EntityType: "dependency", EntityID: "td-1"},
+	}
+	if got := FormatActions(actions, []string{"create"}); got != "td-1 create: Add CSV import (ses_a)" {
+		t.Errorf("filtered = %q", got)
+	}
+	if got := FormatActions(actions, nil); strings.Count(got, "\n") != 2 {
+		t.Errorf("unfiltered = %q", got)
+	}
+	if got := FormatActions(actions, []string{"close"}); got != "" {
+		t.Errorf("no match = %q", got)
+	}
+
+	var got map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &got)
+	}))
+	defer srv.Close()
+
+	if err := PostActions([]Target{{Name: "d", Kind: KindDiscord, URL: srv.URL}}, webhook.Payload{Actions: actions[:1]}); err != nil {
+		t.Fatalf("PostActions: %v", err)
+	}
+	if got["content"] != "td-1 create: Add CSV import (ses_a)" {
+		t.Errorf("discord payload = %v", got)
+	}
+	if err := Post("teams", srv.URL, "x"); err == nil {
+		t.Error("expected error for unknown kind")
+	}
+}
@@ -0,0 +1,174 @@
+package integrations
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/webhook"
+)
+
+// Target is an outgoing channel webhook. It deliberately omits signing
+// secrets so it can be handed to a detached child process on disk.
+type Target struct {
+	Name   string   `json:"name"`
+	Kind   string   `json:"kind"`
+	URL    string   `json:"url"`
+	Events []string `json:"events,omitempty"`
+}
+
+// OutgoingTargets returns integrations that post to a channel webhook.
+func OutgoingTargets(baseDir string) []Target {
+	integrations, err := config.GetIntegrations(baseDir)
+	if err != nil {
+		return nil
+	}
+	var targets []Target
+	for _, ic := range integrations {
+		if ic.WebhookURL != "" {
+			targets = append(targets, Target{Name: ic.Name, Kind: ic.Kind, URL: ic.WebhookURL, Events: ic.Events})
+		}
+	}
+	return targets
+}
+
+// FormatActions renders action log entries as chat message lines, keeping
+// only action types listed in events (empty = all). Returns "" when nothing
+// matches.
+func FormatActions(actions []webhook.ActionPayload, events []string) string {
+	allowed := make(map[string]bool, len(events))
+	for _, e := range events {
+		allowed[e] = true
+	}
+
+	var lines []string
+	for _, a := range actions {
+		if len(allowed) > 0 && !allowed[a.ActionType] {
+			continue
+		}
+		line := fmt.Sprintf("%s %s %s", a.EntityID, a.ActionType, a.EntityType)
+		if title := actionTitle(a); title != "" {
+			line = fmt.Sprintf("%s %s: %s", a.EntityID, a.ActionType, title)
+		}
+		if a.SessionID != "" {
+			line += " (" + a.SessionID + ")"
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// actionTitle extracts an issue title from an action's JSON snapshot
+func actionTitle(a webhook.ActionPayload) string {
+	if a.EntityType != "issue" {
+		return ""
+	}
+	for _, data := range []string{a.NewData, a.PreviousData} {
+		var snap struct {
+			Title string `json:"title"`
+		}
+		if data != "" && json.Unmarshal([]byte(data), &snap) == nil && snap.Title != "" {
+			return snap.Title
+		}
+	}
+	return ""
+}
+
+// Post sends a plain-text message to a Slack or Discord channel webhook.
+func Post(kind, url, text string) error {
+	var payload map[string]string
+	switch kind {
+	case KindSlack:
+		payload = map[string]string{"text": text}
+	case KindDiscord:
+		payload = map[string]string{"content": text}
+	default:
+		return fmt.Errorf("unknown integration kind: %s", kind)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "td-integrations/1")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("POST %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("POST %s: status %d", url, resp.StatusCode)
+	}
+	return nil
+}
+
+// PostActions posts actions to every target whose event filter matches.
+// It returns the first error but attempts every target.
+func PostActions(targets []Target, payload webhook.Payload) error {
+	var firstErr error
+	for _, t := range targets {
+		text := FormatActions(payload.Actions, t.Events)
+		if text == "" {
+			continue
+		}
+		if err := Post(t.Kind, t.URL, text); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("%s: %w", t.Name, err)
+		}
+	}
+	return firstErr
+}
+
+// TempFile is the JSON blob handed to the detached posting process.
+type TempFile struct {
+	Targets []Target        `json:"targets"`
+	Payload webhook.Payload `json:"payload"`
+}
+
+// WriteTempFile writes a TempFile to os.TempDir and returns the path.
+func WriteTempFile(tf *TempFile) (string, error) {
+	data, err := json.Marshal(tf)
+	if err != nil {
+		return "", fmt.Errorf("marshal temp file: %w", err)
+	}
+	f, err := os.CreateTemp("", "td-integrations-*.json")
+	if err != nil {
+		return "", fmt.Errorf("create temp file: %w", err)
+	}
+	path := f.Name()
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(path)
+		return "", fmt.Errorf("write temp file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(path)
+		return "", fmt.Errorf("close temp file: %w", err)
+	}
+	return path, nil
+}
+
+// ReadTempFile reads and parses a TempFile from disk.
+func ReadTempFile(path string) (*TempFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read temp file: %w", err)
+	}
+	var tf TempFile
+	if err := json.Unmarshal(data, &tf); err != nil {
+		return nil, fmt.Errorf("parse temp file: %w", err)
+	}
+	return &tf, nil
+}
@@ -0,0 +1,134 @@
+package integrations
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// slackMaxSkew rejects requests with timestamps further than this from now,
+// which guards against replayed requests.
+const slackMaxSkew = 5 * time.Minute
+
+// slackMaxFields is Slack's limit on fields in one section block
+const slackMaxFields = 10
+
+// ErrBadSignature is returned when an inbound request fails verification.
+var ErrBadSignature = errors.New("invalid request signature")
+
+// VerifySlack checks the X-Slack-Signature header of an inbound request
+// against the app's signing secret.
+func VerifySlack(secret string, header http.Header, body []byte, now time.Time) error {
+	if secret == "" {
+		return fmt.Errorf("%w: no signing secret configured", ErrBadSignature)
+	}
+	tsHeader := header.Get("X-Slack-Request-Timestamp")
+	sig := header.Get("X-Slack-Signature")
+	if tsHeader == "" || sig == "" {
+		return fmt.Errorf("%w: missing signature headers", ErrBadSignature)
+	}
+	ts, err := strconv.ParseInt(tsHeader, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: bad timestamp", ErrBadSignature)
+	}
+	if skew := now.Sub(time.Unix(ts, 0)); skew > slackMaxSkew || skew < -slackMaxSkew {
+		return fmt.Errorf("%w: stale timestamp", ErrBadSignature)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + tsHeader + ":"))
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(sig)) {
+		return ErrBadSignature
+	}
+	return nil
+}
+
+// SlashCommand is the subset of a Slack slash-command payload td uses.
+type SlashCommand struct {
+	Command   string // e.g. "/td"
+	Text      string // everything after the command
+	UserID    string
+	UserName  string
+	ChannelID string
+}
+
+// ParseSlackCommand parses a form-encoded slash-command request body.
+func ParseSlackCommand(body []byte) (SlashCommand, error) {
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return SlashCommand{}, fmt.Errorf("parse slash command: %w", err)
+	}
+	return SlashCommand{
+		Command:   values.Get("command"),
+		Text:      strings.TrimSpace(values.Get("text")),
+		UserID:    values.Get("user_id"),
+		UserName:  values.Get("user_name"),
+		ChannelID: values.Get("channel_id"),
+	}, nil
+}
+
+// SlackResponse renders a reply as a slash-command response with Block Kit
+// blocks and a plain-text fallback.
+func SlackResponse(r Reply) map[string]interface{} {
+	responseType := "ephemeral"
+	if r.Public {
+		responseType = "in_channel"
+	}
+
+	var blocks []map[string]interface{}
+	var fallback []string
+	if r.Title != "" {
+		blocks = append(blocks, slackSection("*"+slackEscape(r.Title)+"*"))
+		fallback = append(fallback, r.Title)
+	}
+	if r.Text != "" {
+		text := slackEscape(r.Text)
+		if r.Error {
+			text = ":warning: " + text
+		} else if strings.Contains(r.Text, "\n") && r.Title != "" {
+			text = "```" + text + "```"
+		}
+		blocks = append(blocks, slackSection(text))
+		fallback = append(fallback, r.Text)
+	}
+	if len(r.Fields) > 0 {
+		fields := make([]map[string]string, 0, len(r.Fields))
+		for i, f := range r.Fields {
+			if i == slackMaxFields {
+				break
+			}
+			fields = append(fields, map[string]string{
+				"type": "mrkdwn",
+				"text": "*" + slackEscape(f.Label) + "*\n" + slackEscape(f.Value),
+			})
+		}
+		blocks = append(blocks, map[string]interface{}{"type": "section", "fields": fields})
+	}
+
+	return map[string]interface{}{
+		"response_type": responseType,
+		"text":          strings.Join(fallback, "\n"),
+		"blocks":        blocks,
+	}
+}
+
+func slackSection(mrkdwn string) map[string]interface{} {
+	return map[string]interface{}{
+		"type": "section",
+		"text": map[string]string{"type": "mrkdwn", "text": mrkdwn},
+	}
+}
+
+// slackEscape escapes the characters Slack treats as control sequences
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
@@ -0,0 +1,148 @@
+// Package issuetmpl renders issues through user-defined Go templates, so
+// td list and td show can produce whatever line or block a team or script
+// wants without post-processing JSON. A template runs once per issue on a
+// Data value: the issue's fields ({{.ID}}, {{.Title}}, {{.Labels}}, ...)
+// plus the records td show fetches.
+package issuetmpl
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/output"
+)
+
+// Builtins are the templates every project has, referred to as @name.
+// Project templates of the same name take precedence.
+var Builtins = map[string]string{
+	"compact":  `{{.ID}}  {{.Priority}}  {{pad 11 .Status}}  {{.Title}}`,
+	"ids":      `{{.ID}}`,
+	"markdown": `- [{{if eq .Status "closed"}}x{{else}} {{end}}] **{{.ID}}** {{.Title}}{{if .Labels}} ({{join ", " .Labels}}){{end}}`,
+	"tsv":      "{{.ID}}\t{{.Status}}\t{{.Priority}}\t{{.Type}}\t{{.Points}}\t{{join \",\" .Labels}}\t{{.Title}}",
+}
+
+// Data is what a template sees for one issue. Logs and Handoff are only
+// filled in by td show.
+type Data struct {
+	models.Issue
+	Logs    []models.Log
+	Handoff *models.Handoff
+}
+
+// funcs are the helpers templates can call beyond the text/template builtins
+var funcs = template.FuncMap{
+	"join":  func(sep string, items []string) string { return strings.Join(items, sep) },
+	"upper": func(v interface{}) string { return strings.ToUpper(fmt.Sprint(v)) },
+	"lower": func(v interface{}) string { return strings.ToLower(fmt.Sprint(v)) },
+	"pad": func(width int, v interface{}) string {
+		s := fmt.Sprint(v)
+		if n := utf8.RuneCountInString(s); n < width {
+			s += strings.Repeat(" ", width-n)
+		}
+		return s
+	},
+	"trunc": func(width int, v interface{}) string {
+		s := fmt.Sprint(v)
+		if utf8.RuneCountInString(s) <= width || width < 1 {
+			return s
+		}
+		return string([]rune(s)[:width-1]) + "…"
+	},
+	"date": func(v interface{}) string {
+		switch t := v.(type) {
+		case time.Time:
+			if t.IsZero() {
+				return ""
+			}
+			return t.Local().Format("2006-01-02")
+		case *time.Time:
+			if t == nil || t.IsZero() {
+				return ""
+			}
+			return t.Local().Format("2006-01-02")
+		case *string:
+			if t == nil {
+				return ""
+			}
+			return *t
+		}
+		return fmt.Sprint(v)
+	},
+	"ago": func(t time.Time) string { return output.FormatTimeAgo(t) },
+}
+
+// Names lists the built-in and project template names, sorted.
+func Names(custom map[string]string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, m := range []map[string]string{custom, Builtins} {
+		for name := range m {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Lookup returns the text of the named template, project templates first.
+func Lookup(name string, custom map[string]string) (string, bool) {
+	if text, ok := custom[name]; ok {
+		return text, true
+	}
+	text, ok := Builtins[name]
+	return text, ok
+}
+
+// Parse compiles a --template value: @name for a built-in or project
+// template, anything else is the template text itself.
+func Parse(spec string, custom map[string]string) (*template.Template, error) {
+	text := spec
+	if name, ok := strings.CutPrefix(spec, "@"); ok {
+		if text, ok = Lookup(name, custom); !ok {
+			return nil, fmt.Errorf("unknown template @%s (have: @%s)", name, strings.Join(Names(custom), ", @"))
+		}
+	}
+	t, err := template.New("issue").Funcs(funcs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return t, nil
+}
+
+// Render runs t once per issue, ending each issue's output with a newline
+// unless the template already does.
+func Render(w io.Writer, t *template.Template, items []Data) error {
+	var buf bytes.Buffer
+	for i := range items {
+		buf.Reset()
+		if err := t.Execute(&buf, &items[i]); err != nil {
+			return fmt.Errorf("template: %w", err)
+		}
+		if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+			buf.WriteByte('\n')
+		}
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FromIssues wraps issues as template data without related records.
+func FromIssues(issues []models.Issue) []Data {
+	items := make([]Data, len(issues))
+	for i := range issues {
+		items[i].Issue = issues[i]
+	}
+	return items
+}
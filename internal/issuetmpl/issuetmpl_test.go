@@ -0,0 +1,59 @@
+package issuetmpl
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestRender(t *testing.T) {
+	issues := []models.Issue{
+		{ID: "td-a1", Title: "Fix login timeout for SSO users", Status: models.StatusOpen, Priority: models.PriorityP1, Labels: []string{"auth", "sso"}},
+		{ID: "td-b2", Title: "Drop legacy session table", Status: models.StatusClosed, Priority: models.PriorityP3},
+	}
+	custom := map[string]string{"short": `{{.ID}} {{lower .Priority}} {{trunc 10 .Title}}`}
+
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{"@ids", "td-a1\ntd-b2\n"},
+		{"@compact", "td-a1  P1  open         Fix login timeout for SSO users\ntd-b2  P3  closed       Drop legacy session table\n"},
+		{"@markdown", "- [ ] **td-a1** Fix login timeout for SSO users (auth, sso)\n- [x] **td-b2** Drop legacy session table\n"},
+		{"@short", "td-a1 p1 Fix login…\ntd-b2 p3 Drop lega…\n"},
+		{"{{.ID}}: {{join \"+\" .Labels}}\n", "td-a1: auth+sso\ntd-b2: \n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			tmpl, err := Parse(tt.spec, custom)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			var buf bytes.Buffer
+			if err := Render(&buf, tmpl, FromIssues(issues)); err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("Render() = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, spec := range []string{"@nope", "{{.ID"} {
+		if _, err := Parse(spec, nil); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", spec)
+		}
+	}
+
+	// Unknown fields fail when rendered, not silently print <no value>
+	tmpl, err := Parse("{{.Owner}}", nil)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if err := Render(&bytes.Buffer{}, tmpl, FromIssues([]models.Issue{{ID: "td-a1"}})); err == nil {
+		t.Error("Render with unknown field succeeded, want error")
+	}
+}
@@ -0,0 +1,272 @@
+// Package jobs schedules td serve's background work. Each job runs on its
+// own interval, at most one run at a time, and can be triggered by hand;
+// the scheduler keeps each job's last and next run so the server can
+// report on it, and calls a hook when a run fails so failures are noticed
+// rather than only logged.
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+)
+
+// ErrUnknownJob is returned for a job name that was never added
+var ErrUnknownJob = errors.New("unknown job")
+
+// ErrRunning is returned when triggering a job that is already running
+var ErrRunning = errors.New("job is already running")
+
+// Job is a unit of background work
+type Job struct {
+	Name        string
+	Description string
+	// Interval between scheduled runs; zero runs the job only when triggered
+	Interval time.Duration
+	// Immediate runs the job once as soon as the scheduler starts
+	Immediate bool
+	Run       func(ctx context.Context) error
+}
+
+// Status is a job's schedule and run history
+type Status struct {
+	Name                string     `json:"name"`
+	Description         string     `json:"description"`
+	Interval            string     `json:"interval,omitempty"` // empty for manual-only jobs
+	Running             bool       `json:"running"`
+	LastRun             *time.Time `json:"last_run,omitempty"`
+	LastDurationMs      int64      `json:"last_duration_ms"`
+	LastError           string     `json:"last_error,omitempty"`
+	NextRun             *time.Time `json:"next_run,omitempty"`
+	Runs                int        `json:"runs"`
+	Failures            int        `json:"failures"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+}
+
+// FailureFunc is called after a failed run with the job's updated status
+type FailureFunc func(st Status, err error)
+
+type entry struct {
+	job Job
+	st  Status
+}
+
+// Scheduler runs jobs. Add jobs before Start; Trigger, Statuses and Get are
+// safe to call at any time, including before Start and after Stop.
+type Scheduler struct {
+	// OnFailure, when set, is called after every failed run
+	OnFailure FailureFunc
+
+	mu      sync.Mutex
+	entries []*entry
+	byName  map[string]*entry
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	runs    sync.WaitGroup // runs in flight, for Wait
+	started bool
+}
+
+// New returns an empty scheduler
+func New() *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{byName: make(map[string]*entry), ctx: ctx, cancel: cancel}
+}
+
+// Add registers a job. Names must be unique.
+func (s *Scheduler) Add(job Job) error {
+	if job.Name == "" || job.Run == nil {
+		return fmt.Errorf("job needs a name and a run function")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.byName[job.Name]; ok {
+		return fmt.Errorf("job %q already added", job.Name)
+	}
+	e := &entry{job: job, st: Status{Name: job.Name, Description: job.Description}}
+	if job.Interval > 0 {
+		e.st.Interval = job.Interval.String()
+	}
+	s.entries = append(s.entries, e)
+	s.byName[job.Name] = e
+	return nil
+}
+
+// Start runs every job with an interval on its schedule until ctx is
+// cancelled or Stop is called
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.started = true
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.cancel()
+		case <-s.ctx.Done():
+		}
+	}()
+	for _, e := range s.entries {
+		if e.job.Interval <= 0 {
+			continue
+		}
+		next := clock.Now()
+		if !e.job.Immediate {
+			next = next.Add(e.job.Interval)
+		}
+		e.st.NextRun = &next
+		s.wg.Add(1)
+		go s.loop(e)
+	}
+}
+
+// Stop cancels the schedule and waits for running jobs to return
+func (s *Scheduler) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// Trigger starts a run of the named job now, in the background
+func (s *Scheduler) Trigger(name string) error {
+	s.mu.Lock()
+	e, ok := s.byName[name]
+	if !ok {
+		s.mu.Unlock()
+		return ErrUnknownJob
+	}
+	if e.st.Running {
+		s.mu.Unlock()
+		return ErrRunning
+	}
+	e.st.Running = true
+	s.wg.Add(1)
+	s.runs.Add(1)
+	s.mu.Unlock()
+
+	go func() {
+		defer s.wg.Done()
+		s.run(e)
+	}()
+	return nil
+}
+
+// Wait blocks until every run in flight, triggered or scheduled, has
+// returned. Unlike Stop it leaves the schedule running.
+func (s *Scheduler) Wait() {
+	s.runs.Wait()
+}
+
+// Statuses reports every job in the order they were added
+func (s *Scheduler) Statuses() []Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Status, 0, len(s.entries))
+	for _, e := range s.entries {
+		out = append(out, e.st)
+	}
+	return out
+}
+
+// Get reports one job
+func (s *Scheduler) Get(name string) (Status, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.byName[name]
+	if !ok {
+		return Status{}, false
+	}
+	return e.st, true
+}
+
+// loop runs e on its interval, skipping a run while a triggered one is
+// still going
+func (s *Scheduler) loop(e *entry) {
+	defer s.wg.Done()
+	timer := time.NewTimer(s.untilNext(e))
+	defer timer.Stop()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-timer.C:
+		}
+		if s.claim(e) {
+			s.run(e)
+		} else {
+			s.mu.Lock()
+			next := clock.Now().Add(e.job.Interval)
+			e.st.NextRun = &next
+			s.mu.Unlock()
+		}
+		timer.Reset(s.untilNext(e))
+	}
+}
+
+// untilNext is the wait until e's next scheduled run
+func (s *Scheduler) untilNext(e *entry) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e.st.NextRun == nil {
+		return e.job.Interval
+	}
+	if d := e.st.NextRun.Sub(clock.Now()); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// claim marks e running; false when it already is
+func (s *Scheduler) claim(e *entry) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e.st.Running {
+		return false
+	}
+	e.st.Running = true
+	s.runs.Add(1)
+	return true
+}
+
+// run executes a claimed job and records the outcome. A panic counts as
+// a failure so one bad job can't take the server down.
+func (s *Scheduler) run(e *entry) {
+	defer s.runs.Done()
+	start := clock.Now()
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic: %v", r)
+			}
+		}()
+		return e.job.Run(s.ctx)
+	}()
+	end := clock.Now()
+
+	s.mu.Lock()
+	e.st.Running = false
+	e.st.LastRun = &start
+	e.st.LastDurationMs = end.Sub(start).Milliseconds()
+	e.st.Runs++
+	if s.started && e.job.Interval > 0 {
+		next := end.Add(e.job.Interval)
+		e.st.NextRun = &next
+	}
+	if err != nil {
+		e.st.LastError = err.Error()
+		e.st.Failures++
+		e.st.ConsecutiveFailures++
+	} else {
+		e.st.LastError = ""
+		e.st.ConsecutiveFailures = 0
+	}
+	st := e.st
+	onFailure := s.OnFailure
+	s.mu.Unlock()
+
+	if err != nil && onFailure != nil {
+		onFailure(st, err)
+	}
+}
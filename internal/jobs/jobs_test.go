@@ -0,0 +1,115 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitRuns polls until the job has completed n runs
+func waitRuns(t *testing.T, s *Scheduler, name string, n int) Status {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if st, _ := s.Get(name); st.Runs >= n && !st.Running {
+			return st
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	st, _ := s.Get(name)
+	t.Fatalf("%s: %d runs, want %d", name, st.Runs, n)
+	return st
+}
+
+func TestSchedulerRunsOnInterval(t *testing.T) {
+	s := New()
+	var count atomic.Int32
+	if err := s.Add(Job{Name: "tick", Interval: 10 * time.Millisecond, Immediate: true, Run: func(context.Context) error {
+		count.Add(1)
+		return nil
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Add(Job{Name: "tick", Run: func(context.Context) error { return nil }}); err == nil {
+		t.Error("duplicate job name accepted")
+	}
+
+	s.Start(context.Background())
+	st := waitRuns(t, s, "tick", 3)
+	s.Stop()
+	if st.Interval != "10ms" || st.LastRun == nil || st.NextRun == nil || st.Failures != 0 {
+		t.Errorf("status = %+v", st)
+	}
+
+	// Stopped: no more runs
+	n := count.Load()
+	time.Sleep(30 * time.Millisecond)
+	if count.Load() != n {
+		t.Error("job ran after Stop")
+	}
+}
+
+func TestSchedulerTriggerAndFailures(t *testing.T) {
+	s := New()
+	release := make(chan struct{})
+	var fail atomic.Bool
+	fail.Store(true)
+	if err := s.Add(Job{Name: "digest", Run: func(context.Context) error {
+		<-release
+		if fail.Load() {
+			return errors.New("smtp down")
+		}
+		return nil
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Add(Job{Name: "boom", Run: func(context.Context) error { panic("bad job") }}); err != nil {
+		t.Fatal(err)
+	}
+	var alerts []Status
+	failed := make(chan struct{}, 4)
+	s.OnFailure = func(st Status, err error) {
+		alerts = append(alerts, st)
+		failed <- struct{}{}
+	}
+
+	if err := s.Trigger("nope"); !errors.Is(err, ErrUnknownJob) {
+		t.Errorf("unknown job err = %v", err)
+	}
+	if err := s.Trigger("digest"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Trigger("digest"); !errors.Is(err, ErrRunning) {
+		t.Errorf("second trigger err = %v, want ErrRunning", err)
+	}
+	release <- struct{}{}
+	<-failed
+	st := waitRuns(t, s, "digest", 1)
+	if st.LastError != "smtp down" || st.ConsecutiveFailures != 1 || st.NextRun != nil {
+		t.Errorf("failed status = %+v", st)
+	}
+
+	fail.Store(false)
+	if err := s.Trigger("digest"); err != nil {
+		t.Fatal(err)
+	}
+	release <- struct{}{}
+	st = waitRuns(t, s, "digest", 2)
+	if st.LastError != "" || st.ConsecutiveFailures != 0 || st.Failures != 1 {
+		t.Errorf("recovered status = %+v", st)
+	}
+
+	if err := s.Trigger("boom"); err != nil {
+		t.Fatal(err)
+	}
+	<-failed
+	if st := waitRuns(t, s, "boom", 1); st.LastError != "panic: bad job" {
+		t.Errorf("panic status = %+v", st)
+	}
+	s.Stop()
+	if len(alerts) != 2 {
+		t.Errorf("alerts = %+v, want 2", alerts)
+	}
+}
@@ -0,0 +1,315 @@
+// Package loadtest drives a running td serve with concurrent clients doing
+// a weighted mix of reads and writes, and summarizes latency percentiles
+// and errors per operation.
+//
+// Writes only touch issues the run creates itself. Those carry the Label
+// label so they are easy to find, and Cleanup deletes them afterwards.
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/marcus/td/internal/serve"
+	"github.com/marcus/td/internal/serveclient"
+)
+
+// Label marks the issues a run creates
+const Label = "loadtest"
+
+// Op is one kind of request a client makes
+type Op string
+
+// Operations
+const (
+	OpList    Op = "list"    // GET /v1/issues
+	OpGet     Op = "get"     // GET /v1/issues/{id}
+	OpMonitor Op = "monitor" // GET /v1/monitor
+	OpCreate  Op = "create"  // POST /v1/issues
+	OpUpdate  Op = "update"  // PATCH /v1/issues/{id} on an issue the run created
+	OpComment Op = "comment" // POST /v1/issues/{id}/comments on an issue the run created
+)
+
+// Ops lists the operations in report order
+var Ops = []Op{OpList, OpGet, OpMonitor, OpCreate, OpUpdate, OpComment}
+
+// DefaultMix weights the operations roughly like a board UI with a few
+// agents writing: three reads for every write
+var DefaultMix = map[Op]int{
+	OpList:    30,
+	OpGet:     25,
+	OpMonitor: 20,
+	OpCreate:  5,
+	OpUpdate:  12,
+	OpComment: 8,
+}
+
+// IsWrite reports whether op changes data
+func (op Op) IsWrite() bool {
+	return op == OpCreate || op == OpUpdate || op == OpComment
+}
+
+// Config controls a run
+type Config struct {
+	Clients  int
+	Duration time.Duration
+	Mix      map[Op]int // weights; nil means DefaultMix
+	ReadOnly bool       // drop writes from the mix
+	Seed     int64      // seeds each client's choice of operations
+}
+
+// OpStats summarizes one operation's requests
+type OpStats struct {
+	Op     Op      `json:"op"`
+	Count  int     `json:"count"`
+	Errors int     `json:"errors"`
+	P50    float64 `json:"p50_ms"`
+	P90    float64 `json:"p90_ms"`
+	P99    float64 `json:"p99_ms"`
+	Max    float64 `json:"max_ms"`
+}
+
+// Result summarizes a run
+type Result struct {
+	Clients   int            `json:"clients"`
+	Elapsed   float64        `json:"elapsed_seconds"`
+	Requests  int            `json:"requests"`
+	Errors    int            `json:"errors"`
+	ErrorRate float64        `json:"error_rate"` // 0 to 1
+	RPS       float64        `json:"requests_per_second"`
+	Ops       []OpStats      `json:"ops"`
+	ErrorKind map[string]int `json:"error_kinds,omitempty"` // error code or message, by count
+	Created   []string       `json:"created,omitempty"`     // issues the run created
+}
+
+// sample is one request's outcome
+type sample struct {
+	op      Op
+	latency time.Duration
+	err     error
+}
+
+// worker is one simulated client
+type worker struct {
+	id      int
+	client  *serveclient.Client
+	rng     *rand.Rand
+	choices []Op     // ops repeated by weight
+	known   []string // issue IDs to read
+	created []string // issue IDs this worker created
+	samples []sample
+}
+
+// Run loads the server for cfg.Duration or until ctx is done
+func Run(ctx context.Context, client *serveclient.Client, cfg Config) (*Result, error) {
+	if cfg.Clients <= 0 {
+		return nil, fmt.Errorf("clients must be positive")
+	}
+	if cfg.Duration <= 0 {
+		return nil, fmt.Errorf("duration must be positive")
+	}
+	choices := weightedOps(cfg)
+	if len(choices) == 0 {
+		return nil, fmt.Errorf("the operation mix is empty")
+	}
+	if err := client.Health(ctx); err != nil {
+		return nil, fmt.Errorf("server health check: %w", err)
+	}
+
+	issues, err := client.ListIssues(ctx, url.Values{"limit": {"200"}})
+	if err != nil {
+		return nil, fmt.Errorf("list issues: %w", err)
+	}
+	known := make([]string, len(issues))
+	for i, iss := range issues {
+		known[i] = iss.ID
+	}
+
+	// Requests use ctx rather than the deadline, so ones in flight when the
+	// run ends finish and every issue created is recorded for Cleanup
+	deadline, cancel := context.WithTimeout(ctx, cfg.Duration)
+	defer cancel()
+
+	workers := make([]*worker, cfg.Clients)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := range workers {
+		w := &worker{
+			id:      i,
+			client:  client,
+			rng:     rand.New(rand.NewSource(cfg.Seed + int64(i))),
+			choices: choices,
+			known:   known,
+		}
+		workers[i] = w
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.run(ctx, deadline)
+		}()
+	}
+	wg.Wait()
+
+	return summarize(workers, time.Since(start)), nil
+}
+
+// Cleanup deletes the issues a run created, returning how many it deleted
+func Cleanup(ctx context.Context, client *serveclient.Client, ids []string) (int, error) {
+	deleted := 0
+	for _, id := range ids {
+		if err := client.DeleteIssue(ctx, id); err != nil {
+			return deleted, fmt.Errorf("delete %s: %w", id, err)
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// weightedOps expands the mix into a slice to pick from uniformly
+func weightedOps(cfg Config) []Op {
+	mix := cfg.Mix
+	if mix == nil {
+		mix = DefaultMix
+	}
+	var out []Op
+	for _, op := range Ops {
+		if cfg.ReadOnly && op.IsWrite() {
+			continue
+		}
+		for n := mix[op]; n > 0; n-- {
+			out = append(out, op)
+		}
+	}
+	return out
+}
+
+func (w *worker) run(ctx, deadline context.Context) {
+	for n := 0; deadline.Err() == nil; n++ {
+		op := w.choices[w.rng.Intn(len(w.choices))]
+		// Updates and comments need an issue of our own first
+		if (op == OpUpdate || op == OpComment) && len(w.created) == 0 {
+			op = OpCreate
+		}
+		start := time.Now()
+		err := w.do(ctx, op, n)
+		if ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+			return // the run was cancelled, not a server error
+		}
+		w.samples = append(w.samples, sample{op, time.Since(start), err})
+	}
+}
+
+func (w *worker) do(ctx context.Context, op Op, n int) error {
+	switch op {
+	case OpList:
+		_, err := w.client.ListIssues(ctx, url.Values{"limit": {"50"}})
+		return err
+	case OpGet:
+		ids := w.known
+		if len(w.created) > 0 && (len(ids) == 0 || w.rng.Intn(2) == 0) {
+			ids = w.created
+		}
+		if len(ids) == 0 {
+			_, err := w.client.ListIssues(ctx, url.Values{"limit": {"50"}})
+			return err
+		}
+		_, err := w.client.GetIssue(ctx, ids[w.rng.Intn(len(ids))])
+		return err
+	case OpMonitor:
+		_, err := w.client.Monitor(ctx)
+		return err
+	case OpCreate:
+		issue, err := w.client.CreateIssue(ctx, serve.IssueCreateBody{
+			Title:       fmt.Sprintf("Load test issue %d-%d", w.id, n),
+			Description: "Created by td loadtest.",
+			Labels:      []string{Label},
+		})
+		if err == nil {
+			w.created = append(w.created, issue.ID)
+		}
+		return err
+	case OpUpdate:
+		desc := fmt.Sprintf("Created by td loadtest. Update %d.", n)
+		_, err := w.client.UpdateIssue(ctx, w.pickCreated(), serve.IssueUpdateBody{Description: &desc})
+		return err
+	case OpComment:
+		return w.client.AddComment(ctx, w.pickCreated(), fmt.Sprintf("Load test comment %d", n))
+	}
+	return fmt.Errorf("unknown operation %q", op)
+}
+
+func (w *worker) pickCreated() string {
+	return w.created[w.rng.Intn(len(w.created))]
+}
+
+// summarize merges the workers' samples into a Result
+func summarize(workers []*worker, elapsed time.Duration) *Result {
+	res := &Result{Clients: len(workers), Elapsed: elapsed.Seconds(), ErrorKind: map[string]int{}}
+	byOp := map[Op][]time.Duration{}
+	errs := map[Op]int{}
+	for _, w := range workers {
+		res.Created = append(res.Created, w.created...)
+		for _, s := range w.samples {
+			byOp[s.op] = append(byOp[s.op], s.latency)
+			res.Requests++
+			if s.err != nil {
+				errs[s.op]++
+				res.Errors++
+				res.ErrorKind[errorKind(s.err)]++
+			}
+		}
+	}
+
+	for _, op := range Ops {
+		lat := byOp[op]
+		if len(lat) == 0 {
+			continue
+		}
+		sort.Slice(lat, func(i, j int) bool { return lat[i] < lat[j] })
+		res.Ops = append(res.Ops, OpStats{
+			Op:     op,
+			Count:  len(lat),
+			Errors: errs[op],
+			P50:    ms(percentile(lat, 50)),
+			P90:    ms(percentile(lat, 90)),
+			P99:    ms(percentile(lat, 99)),
+			Max:    ms(lat[len(lat)-1]),
+		})
+	}
+	if res.Requests > 0 {
+		res.ErrorRate = float64(res.Errors) / float64(res.Requests)
+	}
+	if elapsed > 0 {
+		res.RPS = float64(res.Requests) / elapsed.Seconds()
+	}
+	return res
+}
+
+// percentile returns the nearest-rank percentile of sorted latencies
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// errorKind groups errors by API error code and status, or by message for
+// transport errors
+func errorKind(err error) string {
+	var apiErr *serveclient.APIError
+	if errors.As(err, &apiErr) {
+		return fmt.Sprintf("%d %s", apiErr.Status, apiErr.Code)
+	}
+	return err.Error()
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,77 @@
+package loadtest
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/serve"
+	"github.com/marcus/td/internal/serveclient"
+)
+
+func TestPercentile(t *testing.T) {
+	lat := make([]time.Duration, 100)
+	for i := range lat {
+		lat[i] = time.Duration(i+1) * time.Millisecond
+	}
+	for _, tt := range []struct {
+		p    int
+		want time.Duration
+	}{{50, 50 * time.Millisecond}, {90, 90 * time.Millisecond}, {99, 99 * time.Millisecond}, {100, 100 * time.Millisecond}} {
+		if got := percentile(lat, tt.p); got != tt.want {
+			t.Errorf("p%d = %s, want %s", tt.p, got, tt.want)
+		}
+	}
+	if got := percentile([]time.Duration{time.Second}, 50); got != time.Second {
+		t.Errorf("p50 of one = %s", got)
+	}
+}
+
+func TestWeightedOps(t *testing.T) {
+	ops := weightedOps(Config{Mix: map[Op]int{OpList: 2, OpCreate: 1}})
+	if len(ops) != 3 {
+		t.Errorf("ops = %v, want 3 entries", ops)
+	}
+	for _, op := range weightedOps(Config{ReadOnly: true}) {
+		if op.IsWrite() {
+			t.Fatalf("read-only mix contains %s", op)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	database, err := db.Initialize(dir)
+	if err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	defer database.Close()
+	ts := httptest.NewServer(serve.NewServer(database, dir, "ses_load01", serve.ServeConfig{}).Handler())
+	defer ts.Close()
+
+	client := serveclient.New(ts.URL, "")
+	res, err := Run(context.Background(), client, Config{Clients: 3, Duration: 500 * time.Millisecond, Seed: 1})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if res.Requests == 0 || len(res.Ops) == 0 {
+		t.Fatalf("no requests recorded: %+v", res)
+	}
+	if res.Errors != 0 {
+		t.Errorf("errors = %d (%v), want 0", res.Errors, res.ErrorKind)
+	}
+	if len(res.Created) == 0 {
+		t.Fatal("run created no issues")
+	}
+	issue, err := database.GetIssue(res.Created[0])
+	if err != nil || len(issue.Labels) != 1 || issue.Labels[0] != Label {
+		t.Errorf("created issue = %+v, %v; want label %q", issue, err, Label)
+	}
+
+	deleted, err := Cleanup(context.Background(), client, res.Created)
+	if err != nil || deleted != len(res.Created) {
+		t.Errorf("Cleanup = %d, %v; want %d", deleted, err, len(res.Created))
+	}
+}
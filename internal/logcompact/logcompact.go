@@ -0,0 +1,164 @@
+// Package logcompact condenses long runs of progress logs on an issue into
+// a single digest log. Agents can write hundreds of progress lines to one
+// issue; the decisions, blockers and results among them matter more than
+// the chatter between, and td show and td resume stay readable once the
+// chatter is a digest.
+//
+// A run is a stretch of consecutive progress logs, in time order, not
+// broken by any other log type. Runs of at least MinRun logs are compacted,
+// except that an issue's latest Keep logs are always left as written. The
+// originals move to the log archive, so nothing is lost.
+package logcompact
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+// Defaults for an unset LogCompactionConfig
+const (
+	DefaultMinRun = 20
+	DefaultKeep   = 10
+)
+
+// WithDefaults returns cfg with unset fields filled in
+func WithDefaults(cfg *models.LogCompactionConfig) models.LogCompactionConfig {
+	var c models.LogCompactionConfig
+	if cfg != nil {
+		c = *cfg
+	}
+	if c.MinRun <= 0 {
+		c.MinRun = DefaultMinRun
+	}
+	if c.Keep <= 0 {
+		c.Keep = DefaultKeep
+	}
+	return c
+}
+
+// OptionsFor turns compaction settings into Options, with a configured
+// summarizer command run in dir
+func OptionsFor(cfg *models.LogCompactionConfig, dir string) Options {
+	c := WithDefaults(cfg)
+	return Options{MinRun: c.MinRun, Keep: c.Keep, Summarizer: SummarizerFor(c.Summarizer, dir)}
+}
+
+// Options controls a compaction
+type Options struct {
+	MinRun     int
+	Keep       int
+	Summarizer Summarizer // nil uses Digest
+	DryRun     bool       // find the runs without summarizing or changing them
+}
+
+// Run is one compacted run of logs
+type Run struct {
+	Logs     int       `json:"logs"`
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	DigestID string    `json:"digest_id,omitempty"` // empty on a dry run
+	Digest   string    `json:"digest,omitempty"`
+}
+
+// Result is what compacting an issue did, or would do
+type Result struct {
+	IssueID   string `json:"issue_id"`
+	Runs      []Run  `json:"runs"`
+	Compacted int    `json:"compacted"` // logs replaced by digests
+}
+
+// FindRuns returns the runs in logs, given oldest first, that are long
+// enough to compact. Logs written by compaction or retention are never part
+// of a run.
+func FindRuns(logs []models.Log, minRun, keep int) [][]models.Log {
+	if keep > 0 {
+		if keep >= len(logs) {
+			return nil
+		}
+		logs = logs[:len(logs)-keep]
+	}
+	var runs [][]models.Log
+	start := 0
+	for i := 0; i <= len(logs); i++ {
+		if i < len(logs) && compactable(logs[i]) {
+			continue
+		}
+		if i-start >= minRun {
+			runs = append(runs, logs[start:i])
+		}
+		start = i + 1
+	}
+	return runs
+}
+
+func compactable(l models.Log) bool {
+	return l.Type == models.LogTypeProgress && l.SessionID != db.CompactionSessionID && l.SessionID != db.RetentionSessionID
+}
+
+// Compact replaces each long run of progress logs on issue with a digest
+func Compact(database *db.DB, issue *models.Issue, opts Options) (*Result, error) {
+	logs, err := database.GetIssueLogs(issue.ID)
+	if err != nil {
+		return nil, err
+	}
+	summarizer := opts.Summarizer
+	if summarizer == nil {
+		summarizer = Digest{}
+	}
+
+	result := &Result{IssueID: issue.ID, Runs: []Run{}}
+	for _, run := range FindRuns(logs, opts.MinRun, opts.Keep) {
+		r := Run{Logs: len(run), From: run[0].Timestamp, To: run[len(run)-1].Timestamp}
+		if !opts.DryRun {
+			message, err := summarizer.Summarize(issue, run)
+			if err != nil {
+				return nil, fmt.Errorf("summarize %d logs: %w", len(run), err)
+			}
+			digest, err := database.CompactLogs(run, message)
+			if err != nil {
+				return nil, err
+			}
+			r.DigestID, r.Digest = digest.ID, digest.Message
+		}
+		result.Runs = append(result.Runs, r)
+		result.Compacted += len(run)
+	}
+	return result, nil
+}
+
+// CompactAll compacts every issue with enough progress logs to hold a
+// run. Issues with nothing to compact are left out of the results.
+func CompactAll(database *db.DB, opts Options) ([]Result, error) {
+	ids, err := database.IssuesWithProgressLogs(opts.MinRun)
+	if err != nil {
+		return nil, err
+	}
+	results := []Result{}
+	for _, id := range ids {
+		issue, err := database.GetIssue(id)
+		if err != nil {
+			continue // logs of a deleted or unsynced issue
+		}
+		r, err := Compact(database, issue, opts)
+		if err != nil {
+			return results, fmt.Errorf("%s: %w", id, err)
+		}
+		if len(r.Runs) > 0 {
+			results = append(results, *r)
+		}
+	}
+	return results, nil
+}
+
+// firstLine returns the first line of s, cut to at most n runes
+func firstLine(s string, n int) string {
+	s, _, _ = strings.Cut(strings.TrimSpace(s), "\n")
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return s
+}
@@ -0,0 +1,118 @@
+package logcompact
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+func TestFindRuns(t *testing.T) {
+	var logs []models.Log
+	add := func(n int, typ models.LogType) {
+		for i := 0; i < n; i++ {
+			logs = append(logs, models.Log{ID: fmt.Sprint(len(logs)), Type: typ, SessionID: "ses_a"})
+		}
+	}
+	add(5, models.LogTypeProgress)
+	add(1, models.LogTypeDecision)
+	add(2, models.LogTypeProgress)
+	add(1, models.LogTypeBlocker)
+	add(6, models.LogTypeProgress)
+
+	runs := FindRuns(logs, 3, 2)
+	if len(runs) != 2 || len(runs[0]) != 5 || len(runs[1]) != 4 || runs[1][0].ID != "9" {
+		t.Fatalf("runs = %v", runs)
+	}
+	if runs := FindRuns(logs, 3, len(logs)); len(runs) != 0 {
+		t.Errorf("keeping every log = %v", runs)
+	}
+
+	// A digest breaks a run, so it is never compacted again
+	logs[2].SessionID = db.CompactionSessionID
+	if runs := FindRuns(logs, 3, 0); len(runs) != 1 || len(runs[0]) != 6 {
+		t.Errorf("runs around a digest = %v", runs)
+	}
+}
+
+func TestCompact(t *testing.T) {
+	database, err := db.Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	defer database.Close()
+
+	fake := clock.NewFake(time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC))
+	defer clock.Set(fake.Now)()
+
+	issue := &models.Issue{Title: "Agent fleet writes too many logs"}
+	if err := database.CreateIssue(issue); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 30; i++ {
+		typ := models.LogTypeProgress
+		if i == 20 {
+			typ = models.LogTypeDecision
+		}
+		if err := database.AddLog(&models.Log{IssueID: issue.ID, SessionID: "ses_a", Message: fmt.Sprintf("step %d", i), Type: typ}); err != nil {
+			t.Fatal(err)
+		}
+		fake.Advance(time.Minute)
+	}
+
+	opts := Options{MinRun: 10, Keep: 5, DryRun: true}
+	r, err := Compact(database, issue, opts)
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if r.Compacted != 20 || len(r.Runs) != 1 || r.Runs[0].DigestID != "" {
+		t.Fatalf("dry run = %+v", r)
+	}
+
+	opts.DryRun = false
+	if r, err = Compact(database, issue, opts); err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+	logs, _ := database.GetIssueLogs(issue.ID)
+	if len(logs) != 11 || logs[0].SessionID != db.CompactionSessionID {
+		t.Fatalf("logs after compaction = %d, first %+v", len(logs), logs[0])
+	}
+	if msg := logs[0].Message; !strings.HasPrefix(msg, "Digest of 20 progress logs") ||
+		!strings.Contains(msg, "- step 0") || !strings.Contains(msg, "- … 14 more") || !strings.Contains(msg, "- step 19") {
+		t.Errorf("digest = %q", msg)
+	}
+	if !logs[0].Timestamp.Equal(r.Runs[0].To) {
+		t.Errorf("digest at %v, want the last compacted log's time %v", logs[0].Timestamp, r.Runs[0].To)
+	}
+	archived, err := database.GetArchivedLogs(issue.ID)
+	if err != nil || len(archived) != 20 || archived[0].Message != "step 0" || archived[0].DigestID != logs[0].ID {
+		t.Fatalf("archive = %d logs, %v", len(archived), err)
+	}
+
+	// Nothing left long enough to compact
+	if r, err = Compact(database, issue, opts); err != nil || r.Compacted != 0 {
+		t.Errorf("second compaction = %+v, %v", r, err)
+	}
+}
+
+func TestCommandSummarizer(t *testing.T) {
+	issue := &models.Issue{ID: "td-a1b2c3", Title: "Summarize me"}
+	logs := []models.Log{{Message: "one"}, {Message: "two"}}
+
+	s := &CommandSummarizer{Command: `grep -c '"message"' >/dev/null && echo "$TD_ISSUE_ID: two steps"`}
+	if got, err := s.Summarize(issue, logs); err != nil || got != "td-a1b2c3: two steps" {
+		t.Errorf("Summarize = %q, %v", got, err)
+	}
+	s.Command = "echo model unavailable >&2; exit 1"
+	if _, err := s.Summarize(issue, logs); err == nil || !strings.Contains(err.Error(), "model unavailable") {
+		t.Errorf("failing command err = %v", err)
+	}
+	s.Command = "true"
+	if _, err := s.Summarize(issue, logs); err == nil {
+		t.Error("empty digest accepted")
+	}
+}
@@ -0,0 +1,102 @@
+package logcompact
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/models"
+)
+
+// Summarizer writes the digest message standing in for a run of logs
+type Summarizer interface {
+	Summarize(issue *models.Issue, logs []models.Log) (string, error)
+}
+
+// Digest is the built-in summarizer. It keeps the first and last few
+// messages of the run, one line each, and counts the rest.
+type Digest struct{}
+
+// digestEnds is how many messages Digest keeps from each end of a run
+const digestEnds = 3
+
+func (Digest) Summarize(issue *models.Issue, logs []models.Log) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Digest of %d progress logs, %s to %s:", len(logs),
+		logs[0].Timestamp.Format("2006-01-02 15:04"), logs[len(logs)-1].Timestamp.Format("2006-01-02 15:04"))
+	for i, l := range logs {
+		if i == digestEnds && len(logs) > 2*digestEnds {
+			fmt.Fprintf(&b, "\n- … %d more", len(logs)-2*digestEnds)
+		}
+		if i >= digestEnds && i < len(logs)-digestEnds {
+			continue
+		}
+		fmt.Fprintf(&b, "\n- %s", firstLine(l.Message, 120))
+	}
+	return b.String(), nil
+}
+
+// DefaultSummarizerTimeout bounds a summarizer command
+const DefaultSummarizerTimeout = 2 * time.Minute
+
+// CommandSummarizer runs an external command with sh -c to write the
+// digest, for instance one that asks a language model. The issue and the
+// run's logs are passed as JSON on stdin, {"issue": ..., "logs": [...]},
+// and the command prints the digest on stdout.
+type CommandSummarizer struct {
+	Command string
+	Dir     string // working directory, normally the project root
+	Timeout time.Duration
+}
+
+func (s *CommandSummarizer) Summarize(issue *models.Issue, logs []models.Log) (string, error) {
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = DefaultSummarizerTimeout
+	}
+	c, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	input, err := json.Marshal(map[string]interface{}{"issue": issue, "logs": logs})
+	if err != nil {
+		return "", err
+	}
+	cmd := exec.CommandContext(c, "sh", "-c", s.Command)
+	cmd.Dir = s.Dir
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Env = append(os.Environ(), "TD_ISSUE_ID="+issue.ID)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	// Don't let a background child holding the output pipe outlive the timeout
+	cmd.WaitDelay = time.Second
+
+	out, err := cmd.Output()
+	if c.Err() == context.DeadlineExceeded {
+		return "", fmt.Errorf("summarizer timed out after %s", timeout)
+	}
+	if err != nil {
+		if msg := firstLine(stderr.String(), 200); msg != "" {
+			return "", fmt.Errorf("summarizer: %s", msg)
+		}
+		return "", fmt.Errorf("summarizer: %w", err)
+	}
+	digest := strings.TrimSpace(string(out))
+	if digest == "" {
+		return "", fmt.Errorf("summarizer printed nothing")
+	}
+	return digest, nil
+}
+
+// SummarizerFor returns the summarizer a configured command names, the
+// built-in digest when command is empty
+func SummarizerFor(command, dir string) Summarizer {
+	if command == "" {
+		return Digest{}
+	}
+	return &CommandSummarizer{Command: command, Dir: dir}
+}
@@ -3,6 +3,7 @@
 package models
 
 import (
+	"fmt"
 	"strings"
 	"time"
 )
@@ -18,6 +19,15 @@ const (
 	StatusClosed     Status = "closed"
 )
 
+// BlockedReason records why a blocked issue is blocked
+type BlockedReason string
+
+const (
+	BlockedReasonDependency BlockedReason = "dependency" // waiting on other issues
+	BlockedReasonExternal   BlockedReason = "external"   // waiting on something outside td (vendor, other team, CI)
+	BlockedReasonDecision   BlockedReason = "decision"   // needs a decision before work can continue
+)
+
 // Type represents issue type
 type Type string
 
@@ -76,29 +86,35 @@ const (
 
 // Issue represents a task/issue in the system
 type Issue struct {
-	ID                 string     `json:"id"`
-	Title              string     `json:"title"`
-	Description        string     `json:"description,omitempty"`
-	Status             Status     `json:"status"`
-	Type               Type       `json:"type"`
-	Priority           Priority   `json:"priority"`
-	Points             int        `json:"points"`
-	Labels             []string   `json:"labels,omitempty"`
-	ParentID           string     `json:"parent_id,omitempty"`
-	Acceptance         string     `json:"acceptance,omitempty"`
-	Sprint             string     `json:"sprint,omitempty"`
-	ImplementerSession string     `json:"implementer_session"`
-	CreatorSession     string     `json:"creator_session"`
-	ReviewerSession    string     `json:"reviewer_session"`
-	CreatedAt          time.Time  `json:"created_at"`
-	UpdatedAt          time.Time  `json:"updated_at"`
-	ClosedAt           *time.Time `json:"closed_at,omitempty"`
-	DeletedAt          *time.Time `json:"deleted_at,omitempty"`
-	Minor              bool       `json:"minor"`
-	CreatedBranch      string     `json:"created_branch,omitempty"`
-	DeferUntil         *string    `json:"defer_until,omitempty"`
-	DueDate            *string    `json:"due_date,omitempty"`
-	DeferCount         int        `json:"defer_count"`
+	ID                 string        `json:"id"`
+	Title              string        `json:"title"`
+	Description        string        `json:"description,omitempty"`
+	Status             Status        `json:"status"`
+	Type               Type          `json:"type"`
+	Priority           Priority      `json:"priority"`
+	Points             int           `json:"points"`
+	Labels             []string      `json:"labels,omitempty"`
+	ParentID           string        `json:"parent_id,omitempty"`
+	Acceptance         string        `json:"acceptance,omitempty"`
+	Sprint             string        `json:"sprint,omitempty"`
+	ImplementerSession string        `json:"implementer_session"`
+	CreatorSession     string        `json:"creator_session"`
+	ReviewerSession    string        `json:"reviewer_session"`
+	CreatedAt          time.Time     `json:"created_at"`
+	UpdatedAt          time.Time     `json:"updated_at"`
+	ClosedAt           *time.Time    `json:"closed_at,omitempty"`
+	DeletedAt          *time.Time    `json:"deleted_at,omitempty"`
+	Minor              bool          `json:"minor"`
+	CreatedBranch      string        `json:"created_branch,omitempty"`
+	CreatedRepo        string        `json:"created_repo,omitempty"`
+	DeferUntil         *string       `json:"defer_until,omitempty"`
+	DueDate            *string       `json:"due_date,omitempty"`
+	DeferCount         int           `json:"defer_count"`
+	BlockedReason      BlockedReason `json:"blocked_reason,omitempty"` // set only while blocked
+	BlockedRef         string        `json:"blocked_ref,omitempty"`    // external reference, e.g. a ticket URL
+	Inbox              bool          `json:"inbox,omitempty"`          // awaiting triage; see td inbox
+	Confidential       bool          `json:"confidential,omitempty"`   // description and comments encrypted; see td confidential
+	Rank               int           `json:"rank,omitempty"`           // place in the project-wide queue, lowest first; 0 = unranked (see td rank)
 }
 
 // Log represents a session log entry
@@ -131,6 +147,7 @@ type GitSnapshot struct {
 	Event      string    `json:"event"` // start, handoff
 	CommitSHA  string    `json:"commit_sha"`
 	Branch     string    `json:"branch"`
+	Repo       string    `json:"repo,omitempty"`
 	DirtyFiles int       `json:"dirty_files"`
 	Timestamp  time.Time `json:"timestamp"`
 }
@@ -149,7 +166,44 @@ type IssueFile struct {
 type IssueDependency struct {
 	IssueID      string `json:"issue_id"`
 	DependsOnID  string `json:"depends_on_id"`
-	RelationType string `json:"relation_type"` // blocks, depends_on
+	RelationType string `json:"relation_type"` // blocks, depends_on, stacked_on
+}
+
+// RelationStackedOn marks an issue that must land after the one it is
+// stacked on, as in a chain of stacked diffs
+const RelationStackedOn = "stacked_on"
+
+// ExternalState is whether an external dependency's condition has been met
+type ExternalState string
+
+const (
+	ExternalUnsatisfied ExternalState = "unsatisfied"
+	ExternalSatisfied   ExternalState = "satisfied"
+)
+
+// InheritedPriority is an issue's priority raised by the open issues that
+// depend on it, directly or through other issues (see td policy inherit)
+type InheritedPriority struct {
+	Priority Priority `json:"priority"` // the effective priority
+	From     string   `json:"from"`     // the issue it comes from
+	Via      []string `json:"via"`      // the dependency chain from From to this issue, both ends included
+}
+
+// ExternalDependency makes an issue wait on something outside td, such as
+// a pull request being merged or a CI run passing. A poller checks the
+// URL until the condition is met.
+type ExternalDependency struct {
+	ID          string        `json:"id"`
+	IssueID     string        `json:"issue_id"`
+	URL         string        `json:"url"`
+	Provider    string        `json:"provider"` // github_pr, github_run or http
+	State       ExternalState `json:"state"`
+	Detail      string        `json:"detail,omitempty"`     // last observed state, e.g. "open" or "completed: failure"
+	LastError   string        `json:"last_error,omitempty"` // why the last check failed, if it did
+	CheckedAt   *time.Time    `json:"checked_at,omitempty"`
+	SatisfiedAt *time.Time    `json:"satisfied_at,omitempty"`
+	SessionID   string        `json:"session_id"`
+	CreatedAt   time.Time     `json:"created_at"`
 }
 
 // WorkSession represents a multi-issue work session
@@ -217,6 +271,60 @@ type Comment struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// ConfidentialGrant gives a session, a session name or an API token scope
+// access to a confidential issue
+type ConfidentialGrant struct {
+	IssueID   string    `json:"issue_id"`
+	Grantee   string    `json:"grantee"` // ses_ ID, session name, or role:<scope>
+	GrantedBy string    `json:"granted_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Revision fields
+const (
+	RevisionDescription = "description"
+	RevisionAcceptance  = "acceptance"
+	RevisionComment     = "comment"
+)
+
+// Revision keeps the text an issue description, acceptance criteria or
+// comment had before an edit replaced it
+type Revision struct {
+	ID        string    `json:"id"`
+	IssueID   string    `json:"issue_id"`
+	Field     string    `json:"field"`     // description, acceptance or comment
+	EntityID  string    `json:"entity_id"` // issue ID, or comment ID for comments
+	Before    string    `json:"before"`
+	After     string    `json:"after"` // text that replaced Before; filled in when listed
+	SessionID string    `json:"session_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// IssueCard is the denormalized summary list views show for an issue,
+// kept current by the database as issues, comments, logs and dependencies
+// change
+type IssueCard struct {
+	ID           string    `json:"id"`
+	Title        string    `json:"title"`
+	Status       Status    `json:"status"`
+	Priority     Priority  `json:"priority"`
+	Points       int       `json:"points"`
+	Labels       []string  `json:"labels"`
+	BlockedBy    int       `json:"blocked_by_count"` // depends_on targets not yet closed
+	Comments     int       `json:"comment_count"`
+	LastActivity time.Time `json:"last_activity"`
+}
+
+// Summary is a compact overview of a project's issues, small enough for
+// status pages and shell prompts
+type Summary struct {
+	ByStatus     map[Status]int `json:"by_status"`
+	Open         int            `json:"open"`          // issues that are not closed
+	OpenP0       int            `json:"open_p0"`       // P0 issues that are not closed
+	ReviewQueue  int            `json:"review_queue"`  // issues waiting in review
+	LastActivity *time.Time     `json:"last_activity"` // nil when there are no issues
+}
+
 // Note represents a freeform note (synced via sidecar)
 type Note struct {
 	ID        string     `json:"id"`
@@ -229,28 +337,670 @@ type Note struct {
 	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
+// PlanKind identifies the bulk operation a plan performs
+type PlanKind string
+
+const (
+	PlanClose     PlanKind = "close"     // close matching issues
+	PlanRelabel   PlanKind = "relabel"   // rename a label on every issue carrying it
+	PlanCarryOver PlanKind = "carryover" // move unclosed issues to the next sprint
+)
+
+// PlanStatus represents the lifecycle of a plan
+type PlanStatus string
+
+const (
+	PlanPending   PlanStatus = "pending"
+	PlanApplied   PlanStatus = "applied"
+	PlanDiscarded PlanStatus = "discarded"
+)
+
+// PlanChange is one field change a plan will make to an issue
+type PlanChange struct {
+	IssueID string `json:"issue_id"`
+	Title   string `json:"title"`
+	Field   string `json:"field"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+}
+
+// Plan is a previewed bulk change that must be applied explicitly before
+// it expires.
+type Plan struct {
+	ID        string       `json:"id"`
+	Kind      PlanKind     `json:"kind"`
+	Summary   string       `json:"summary"`
+	Changes   []PlanChange `json:"changes"`
+	SessionID string       `json:"session_id"` // session that created the plan
+	Status    PlanStatus   `json:"status"`
+	CreatedAt time.Time    `json:"created_at"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	AppliedAt *time.Time   `json:"applied_at,omitempty"`
+	AppliedBy string       `json:"applied_by,omitempty"`
+}
+
+// Expired reports whether a pending plan is past its TTL
+func (p *Plan) Expired(now time.Time) bool {
+	return p.Status == PlanPending && !now.Before(p.ExpiresAt)
+}
+
+// ReminderStatus represents the lifecycle of a reminder
+type ReminderStatus string
+
+const (
+	ReminderPending   ReminderStatus = "pending"
+	ReminderFired     ReminderStatus = "fired"
+	ReminderCancelled ReminderStatus = "cancelled"
+)
+
+// Reminder is a personal nudge about an issue, delivered once at RemindAt
+// by whichever monitor or td serve process sees it first.
+type Reminder struct {
+	ID        string         `json:"id"`
+	IssueID   string         `json:"issue_id"`
+	SessionID string         `json:"session_id"` // session that set the reminder
+	Message   string         `json:"message"`
+	Status    ReminderStatus `json:"status"`
+	RemindAt  time.Time      `json:"remind_at"`
+	CreatedAt time.Time      `json:"created_at"`
+	FiredAt   *time.Time     `json:"fired_at,omitempty"`
+}
+
+// NotifyRoute sends one notification event, or every event ("*"), to a
+// transport such as desktop, webhook or smtp. Routes are local to the
+// machine and are not synced.
+type NotifyRoute struct {
+	ID        string    `json:"id"`
+	Event     string    `json:"event"`
+	Transport string    `json:"transport"`
+	Target    string    `json:"target,omitempty"` // URL or address; empty for desktop and stdout
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Delivery statuses
+const (
+	DeliveryPending   = "pending"   // waiting for its first or next attempt
+	DeliveryDelivered = "delivered" // the target accepted it
+	DeliveryFailed    = "failed"    // out of attempts, or its target was removed
+)
+
+// Delivery is one webhook POST, channel post or notification waiting in
+// the delivery outbox, or already sent. Payload is what will be sent: the
+// webhook JSON, the chat message text or the notification message. Secrets
+// are not stored; they are looked up from the target's config when sent.
+type Delivery struct {
+	ID            string     `json:"id"`
+	Kind          string     `json:"kind"`   // webhook, subscription, integration or notify
+	Ref           string     `json:"ref"`    // subscription ID, integration name or route ID
+	Target        string     `json:"target"` // where it goes, for display
+	Payload       string     `json:"payload"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error"`
+	NextAttemptAt *time.Time `json:"next_attempt_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	DeliveredAt   *time.Time `json:"delivered_at"`
+}
+
+// View is a saved, named way of looking at issues: a TDQ query plus the
+// sort, columns, grouping and layout to present its results with. Boards
+// are views that also keep a manual order. Views are local to the project
+// database and are not synced.
+type View struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Query     string    `json:"query"`            // TDQ query; empty matches every issue
+	Sort      string    `json:"sort,omitempty"`   // sort field, "-" prefix for descending; empty for priority
+	Fields    []string  `json:"fields,omitempty"` // issue fields to show, by JSON name
+	GroupBy   string    `json:"group_by,omitempty"`
+	Layout    string    `json:"layout"` // list, table, board or backlog
+	SessionID string    `json:"session_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ShareLink grants read-only access to a single issue, through a signed
+// token, to people without access to the rest of the project.
+type ShareLink struct {
+	ID              string     `json:"id"`
+	IssueID         string     `json:"issue_id"`
+	IncludeComments bool       `json:"include_comments"`
+	SessionID       string     `json:"session_id"` // session that created the link
+	CreatedAt       time.Time  `json:"created_at"`
+	ExpiresAt       *time.Time `json:"expires_at,omitempty"` // nil never expires
+	RevokedAt       *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Active reports whether the link can still be used at now
+func (l *ShareLink) Active(now time.Time) bool {
+	if l.RevokedAt != nil {
+		return false
+	}
+	return l.ExpiresAt == nil || now.Before(*l.ExpiresAt)
+}
+
+// API token scopes: read allows GET requests, write everything else, and
+// admin the /v1/admin routes that act on behalf of other sessions
+const (
+	TokenScopeRead  = "read"
+	TokenScopeWrite = "write"
+	TokenScopeAdmin = "admin"
+)
+
+// APITokenPrefix starts every API token, telling it apart from td serve's
+// own --token
+const APITokenPrefix = "tdt_"
+
+// APIToken is a bearer token for td serve bound to a session: requests made
+// with it act as that session, within its scopes. Only a hash of the token
+// is stored; the token itself is shown once, when it is created.
+type APIToken struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name,omitempty"`
+	SessionID string     `json:"session_id"` // session the token acts as
+	Scopes    []string   `json:"scopes"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // nil never expires
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Active reports whether the token can still be used at now
+func (t *APIToken) Active(now time.Time) bool {
+	if t.RevokedAt != nil {
+		return false
+	}
+	return t.ExpiresAt == nil || now.Before(*t.ExpiresAt)
+}
+
+// HasScope reports whether the token was granted scope
+func (t *APIToken) HasScope(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseTokenScopes parses a comma-separated scope list such as
+// "read,write", dropping duplicates
+func ParseTokenScopes(s string) ([]string, error) {
+	var scopes []string
+	seen := map[string]bool{}
+	for _, part := range strings.Split(s, ",") {
+		scope := strings.ToLower(strings.TrimSpace(part))
+		if scope == "" || seen[scope] {
+			continue
+		}
+		if scope != TokenScopeRead && scope != TokenScopeWrite && scope != TokenScopeAdmin {
+			return nil, fmt.Errorf("unknown scope %q (use read, write, admin)", scope)
+		}
+		seen[scope] = true
+		scopes = append(scopes, scope)
+	}
+	if len(scopes) == 0 {
+		return nil, fmt.Errorf("no scopes given (use read, write, admin)")
+	}
+	return scopes, nil
+}
+
+// Decision records a choice made on the project: the context that called
+// for it, the options weighed and what was decided. Unlike handoff
+// decisions it outlives any one issue and can be linked to several.
+type Decision struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Context   string    `json:"context,omitempty"`
+	Options   []string  `json:"options,omitempty"` // options considered
+	Decision  string    `json:"decision"`
+	IssueIDs  []string  `json:"issue_ids,omitempty"`
+	SessionID string    `json:"session_id"` // session that recorded the decision
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RetroKind classifies a retrospective item
+type RetroKind string
+
+const (
+	RetroWentWell         RetroKind = "went_well"
+	RetroNeedsImprovement RetroKind = "needs_improvement"
+	RetroActionItem       RetroKind = "action_item" // creates a follow-up issue
+)
+
+// RetroItem is one entry in a sprint retrospective
+type RetroItem struct {
+	ID        string    `json:"id"`
+	Sprint    string    `json:"sprint"`
+	Kind      RetroKind `json:"kind"`
+	Text      string    `json:"text"`
+	IssueID   string    `json:"issue_id,omitempty"` // issue created for an action item
+	SessionID string    `json:"session_id"`         // session that added the item
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PokerStatus is the stage of an estimation poker round
+type PokerStatus string
+
+const (
+	PokerOpen     PokerStatus = "open"     // collecting blind votes
+	PokerRevealed PokerStatus = "revealed" // votes shown, estimates being recorded
+	PokerClosed   PokerStatus = "closed"
+)
+
+// PokerRound is an estimation session over a set of issues: sessions vote
+// story points blind, the votes are revealed together and an agreed
+// estimate is recorded per issue
+type PokerRound struct {
+	ID         string      `json:"id"`
+	Title      string      `json:"title,omitempty"`
+	Status     PokerStatus `json:"status"`
+	SessionID  string      `json:"session_id"` // session that opened the round
+	CreatedAt  time.Time   `json:"created_at"`
+	RevealedAt *time.Time  `json:"revealed_at,omitempty"`
+	ClosedAt   *time.Time  `json:"closed_at,omitempty"`
+	Items      []PokerItem `json:"items"`
+}
+
+// PokerItem is one issue in a poker round, with its votes and, once
+// recorded, the agreed estimate
+type PokerItem struct {
+	IssueID    string      `json:"issue_id"`
+	Votes      []PokerVote `json:"votes"`
+	Points     int         `json:"points,omitempty"`  // recorded estimate; 0 until recorded
+	Dissent    string      `json:"dissent,omitempty"` // votes that differed, and why
+	RecordedAt *time.Time  `json:"recorded_at,omitempty"`
+}
+
+// PokerVote is one session's estimate of an issue. Points are hidden from
+// other sessions until the round is revealed.
+type PokerVote struct {
+	SessionID string    `json:"session_id"`
+	Points    int       `json:"points,omitempty"`
+	Hidden    bool      `json:"hidden,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Blind hides the points of votes cast by sessions other than viewer while
+// the round is still open
+func (r *PokerRound) Blind(viewer string) {
+	if r.Status != PokerOpen {
+		return
+	}
+	for i := range r.Items {
+		for j := range r.Items[i].Votes {
+			if v := &r.Items[i].Votes[j]; v.SessionID != viewer {
+				v.Points, v.Hidden = 0, true
+			}
+		}
+	}
+}
+
+// Item returns the round's entry for an issue, or nil
+func (r *PokerRound) Item(issueID string) *PokerItem {
+	for i := range r.Items {
+		if r.Items[i].IssueID == issueID {
+			return &r.Items[i]
+		}
+	}
+	return nil
+}
+
+// ReworkCategory classifies why a closed issue was reopened
+type ReworkCategory string
+
+const (
+	ReworkBug          ReworkCategory = "bug"          // the work did not do what it should
+	ReworkIncomplete   ReworkCategory = "incomplete"   // acceptance criteria were not met
+	ReworkRequirements ReworkCategory = "requirements" // the requirements changed after closing
+	ReworkRegression   ReworkCategory = "regression"   // it worked, then broke again
+	ReworkOther        ReworkCategory = "other"
+)
+
+// ReworkCategories lists the valid rework categories
+func ReworkCategories() []ReworkCategory {
+	return []ReworkCategory{ReworkBug, ReworkIncomplete, ReworkRequirements, ReworkRegression, ReworkOther}
+}
+
+// IsValidReworkCategory checks if a rework category is valid
+func IsValidReworkCategory(c ReworkCategory) bool {
+	for _, valid := range ReworkCategories() {
+		if c == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// Rework records one reopening of a closed issue: a cycle of work that was
+// thought done and was not
+type Rework struct {
+	ID        string         `json:"id"`
+	IssueID   string         `json:"issue_id"`
+	Category  ReworkCategory `json:"category"`
+	Reason    string         `json:"reason,omitempty"`
+	Approved  bool           `json:"approved"` // the issue had passed review before it was reopened
+	SessionID string         `json:"session_id"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// ReworkedIssue summarizes the rework cycles of one issue
+type ReworkedIssue struct {
+	IssueID      string                 `json:"issue_id"`
+	Title        string                 `json:"title"`
+	Status       Status                 `json:"status"`
+	Count        int                    `json:"count"`
+	ByCategory   map[ReworkCategory]int `json:"by_category"`
+	LastReopened time.Time              `json:"last_reopened"`
+}
+
+// Contribution is one agent session's share of the work logged on an issue
+type Contribution struct {
+	SessionID    string    `json:"session_id"`
+	SessionName  string    `json:"session_name,omitempty"`
+	AgentType    string    `json:"agent_type,omitempty"`
+	Logs         int       `json:"logs"`
+	WorkSessions int       `json:"work_sessions"`
+	FirstAt      time.Time `json:"first_at"`
+	LastAt       time.Time `json:"last_at"`
+}
+
+// IssueEffort breaks the work logged on an issue down by the agent
+// sessions that logged it and the work sessions (td ws) it was logged in
+type IssueEffort struct {
+	IssueID      string         `json:"issue_id"`
+	Title        string         `json:"title"`
+	Status       Status         `json:"status"`
+	Logs         int            `json:"logs"`
+	WorkSessions int            `json:"work_sessions"`
+	Contributors []Contribution `json:"contributors"`
+}
+
+// OverrideKind names a bypass-prevention rule that was overridden
+type OverrideKind string
+
+const (
+	OverrideMinorSelfApprove OverrideKind = "minor_self_approve" // approved a minor issue the session worked on
+	OverrideMinorSelfClose   OverrideKind = "minor_self_close"   // closed a minor issue the session worked on
+	OverrideCreatorApproval  OverrideKind = "creator_approval"   // creator approved under the balanced review policy
+	OverrideSelfClose        OverrideKind = "self_close"         // closed with --self-close-exception
+	OverrideForceStart       OverrideKind = "force_start"        // started a blocked issue with --force
+	OverrideClosedEdit       OverrideKind = "closed_edit"        // edited an immutable closed issue
+)
+
+// OverrideKinds lists the valid override kinds
+func OverrideKinds() []OverrideKind {
+	return []OverrideKind{
+		OverrideMinorSelfApprove, OverrideMinorSelfClose, OverrideCreatorApproval,
+		OverrideSelfClose, OverrideForceStart, OverrideClosedEdit,
+	}
+}
+
+// IsValidOverrideKind checks if an override kind is valid
+func IsValidOverrideKind(k OverrideKind) bool {
+	for _, valid := range OverrideKinds() {
+		if k == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// Closes reports whether the override let an issue be closed without an
+// independent review
+func (k OverrideKind) Closes() bool {
+	switch k {
+	case OverrideMinorSelfApprove, OverrideMinorSelfClose, OverrideCreatorApproval, OverrideSelfClose:
+		return true
+	}
+	return false
+}
+
+// Override records one use of a bypass-prevention override and why
+type Override struct {
+	ID            string       `json:"id"`
+	IssueID       string       `json:"issue_id"`
+	Kind          OverrideKind `json:"kind"`
+	Justification string       `json:"justification,omitempty"`
+	SessionID     string       `json:"session_id"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
+// ImpersonationAction names an admin repair made on behalf of a session
+type ImpersonationAction string
+
+const (
+	ImpersonateReassign      ImpersonationAction = "reassign_implementer" // moved an issue to another implementer
+	ImpersonateClearReviewer ImpersonationAction = "clear_reviewer"       // released a stale reviewer's claim
+)
+
+// Impersonation records an admin acting on behalf of another session to
+// repair stuck state. The change itself is logged as AsSession; this
+// record names who really made it, what it changed and why.
+type Impersonation struct {
+	ID           string              `json:"id"`
+	IssueID      string              `json:"issue_id"`
+	Action       ImpersonationAction `json:"action"`
+	ActorSession string              `json:"actor_session"` // the admin
+	AsSession    string              `json:"as_session"`    // the session acted for
+	Before       string              `json:"before"`
+	After        string              `json:"after"`
+	Reason       string              `json:"reason"`
+	CreatedAt    time.Time           `json:"created_at"`
+}
+
+// SuggestionStatus is what was decided about a suggested relation
+type SuggestionStatus string
+
+const (
+	SuggestionAccepted  SuggestionStatus = "accepted"  // turned into a dependency
+	SuggestionDismissed SuggestionStatus = "dismissed" // not related; stop suggesting it
+)
+
+// SuggestionDecision records that a suggested relation between two issues
+// was accepted or dismissed, so it is not suggested again from either side
+type SuggestionDecision struct {
+	IssueID      string           `json:"issue_id"`
+	OtherID      string           `json:"other_id"`
+	Status       SuggestionStatus `json:"status"`
+	RelationType string           `json:"relation_type,omitempty"`
+	SessionID    string           `json:"session_id"`
+	CreatedAt    time.Time        `json:"created_at"`
+}
+
 // WebhookConfig holds webhook delivery settings.
 type WebhookConfig struct {
 	URL    string `json:"url,omitempty"`
 	Secret string `json:"secret,omitempty"`
 }
 
+// Subscription narrows events to the issues it matches: by label, by epic
+// (the epic and everything under it) or by TDQ, every given criterion
+// applying. With a URL, matching changes are posted there as a webhook;
+// without one it is a named filter for td serve's event stream.
+type Subscription struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name,omitempty"`
+	Label     string    `json:"label,omitempty"`
+	Epic      string    `json:"epic,omitempty"`
+	Query     string    `json:"query,omitempty"`
+	URL       string    `json:"url,omitempty"`
+	Secret    string    `json:"secret,omitempty"` // HMAC secret for URL deliveries
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NotifyConfig holds desktop notification settings for the monitor.
+type NotifyConfig struct {
+	Enabled    bool     `json:"enabled"`
+	Events     []string `json:"events,omitempty"`      // "review", "mention", "p0", "reminder"; empty = all
+	QuietHours string   `json:"quiet_hours,omitempty"` // "HH:MM-HH:MM" local time, may wrap midnight
+}
+
+// IntegrationConfig configures a chat integration (Slack or Discord): an
+// inbound slash-command endpoint and optional outgoing channel posts.
+type IntegrationConfig struct {
+	Name            string   `json:"name"`
+	Kind            string   `json:"kind"`                     // "slack" or "discord"
+	VerifySignature bool     `json:"verify_signature"`         // Verify inbound request signatures
+	SigningSecret   string   `json:"signing_secret,omitempty"` // Slack app signing secret
+	PublicKey       string   `json:"public_key,omitempty"`     // Discord application public key (hex)
+	WebhookURL      string   `json:"webhook_url,omitempty"`    // Outgoing channel webhook
+	Events          []string `json:"events,omitempty"`         // Action types to post; empty = all
+}
+
+// Sprint records the date range of a named sprint. Issues join a sprint
+// through their Sprint field.
+type Sprint struct {
+	Name  string `json:"name"`
+	Start string `json:"start"` // YYYY-MM-DD
+	End   string `json:"end"`   // YYYY-MM-DD, inclusive
+}
+
 // Config represents the local config state
 type Config struct {
+	ProjectName       string          `json:"project_name,omitempty"` // Display name; defaults to the directory name
 	FocusedIssueID    string          `json:"focused_issue_id,omitempty"`
 	ActiveWorkSession string          `json:"active_work_session,omitempty"`
 	PaneHeights       [3]float64      `json:"pane_heights,omitempty"`  // Ratios for 3 horizontal panes (sum=1.0)
+	PreviewOpen       bool            `json:"preview_open,omitempty"`  // Monitor issue preview pane visible
+	PreviewRatio      float64         `json:"preview_ratio,omitempty"` // Width ratio of the preview pane (0.2-0.8)
 	FeatureFlags      map[string]bool `json:"feature_flags,omitempty"` // Experimental feature gates
 	// Filter state for monitor
 	SearchQuery   string `json:"search_query,omitempty"`
 	SortMode      string `json:"sort_mode,omitempty"`   // "priority", "created", "updated"
 	TypeFilter    string `json:"type_filter,omitempty"` // "epic", "task", "bug", "feature", "chore", ""
 	IncludeClosed bool   `json:"include_closed,omitempty"`
+	// Per-section TDQ filters for monitor, keyed by board scope then category
+	SectionFilters map[string]map[string]string `json:"section_filters,omitempty"`
 	// Title validation limits
 	TitleMinLength int `json:"title_min_length,omitempty"` // Default: 15
 	TitleMaxLength int `json:"title_max_length,omitempty"` // Default: 100
+	// Deepest allowed parent chain, counting the root as level 1
+	MaxHierarchyDepth int `json:"max_hierarchy_depth,omitempty"` // Default: 10
+	// Issue scoring expression (see internal/score); empty uses the default
+	ScoreFormula string `json:"score_formula,omitempty"`
 	// Webhook settings
 	Webhook *WebhookConfig `json:"webhook,omitempty"`
+	// Filtered webhooks and event stream filters
+	Subscriptions []Subscription `json:"subscriptions,omitempty"`
+	// Desktop notification settings
+	Notify *NotifyConfig `json:"notify,omitempty"`
+	// Sprint date ranges
+	Sprints []Sprint `json:"sprints,omitempty"`
+	// Chat integrations
+	Integrations []IntegrationConfig `json:"integrations,omitempty"`
+	// Key that signs issue share links; generated by the first td share
+	ShareSecret string `json:"share_secret,omitempty"`
+	// Other td projects by reference name, for <name>/<issue-id> references
+	LinkedProjects map[string]string `json:"linked_projects,omitempty"`
+	// Output templates for td list and td show --template @name
+	Templates map[string]string `json:"templates,omitempty"`
+	// Guard against a session flipping issues back and forth
+	Thrash *ThrashConfig `json:"thrash,omitempty"`
+	// How long logs and activity history are kept
+	Retention *RetentionConfig `json:"retention,omitempty"`
+	// How runs of progress logs are compacted into digests
+	LogCompaction *LogCompactionConfig `json:"log_compaction,omitempty"`
+	// Checks run before status transitions, able to veto them
+	PolicyHooks []PolicyHookConfig `json:"policy_hooks,omitempty"`
+	// Fields issues must have before moving to a status
+	RequiredFields []RequiredFieldsRule `json:"required_fields,omitempty"`
+	// Items a reviewer must acknowledge to approve an issue
+	ReviewChecklists []ReviewChecklist `json:"review_checklists,omitempty"`
+	// Definition of ready: what an open issue needs before it counts as
+	// ready work rather than needing triage
+	ReadyGate []string `json:"ready_gate,omitempty"`
+	// Where newly created issues land in the triage inbox: cli, api,
+	// import, integration
+	InboxSources []string `json:"inbox_sources,omitempty"`
+	// Refuse edits and comments on closed issues until they are reopened
+	ClosedImmutable bool `json:"closed_immutable,omitempty"`
+	// Raise open issues to the priority of the open issues waiting on them
+	PriorityInheritance bool `json:"priority_inheritance,omitempty"`
+	// Timeout and failure policy for the scripts in .todos/hooks
+	ScriptHooks *ScriptHooksConfig `json:"script_hooks,omitempty"`
+	// Defaults for td serve; overrides the user config
+	Serve *ServeConfig `json:"serve,omitempty"`
+	// IANA timezone that decides which day it is for due dates and TDQ
+	// keywords like today; empty uses the machine's timezone
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// ServeConfig holds td serve defaults, settable in the user and project
+// config. TD_SERVE_* variables and command flags override them.
+type ServeConfig struct {
+	Port     int    `json:"port,omitempty"`
+	Addr     string `json:"addr,omitempty"`
+	CORS     string `json:"cors,omitempty"`
+	Interval string `json:"interval,omitempty"` // SSE poll interval, duration string
+}
+
+// ThrashConfig tunes the guard against a session rapidly reversing its own
+// status and priority changes. Zero values take the defaults.
+type ThrashConfig struct {
+	Mode          string `json:"mode,omitempty"`           // off, warn, throttle or confirm (default)
+	WindowMinutes int    `json:"window_minutes,omitempty"` // default 10
+	MaxReversals  int    `json:"max_reversals,omitempty"`  // reversals allowed per field in the window; default 3
+}
+
+// RetentionConfig says how long records are kept before td retention and
+// the td serve retention job prune them. Zero keeps them forever.
+type RetentionConfig struct {
+	LogDays      int `json:"log_days,omitempty"`      // logs are summarized per issue and pruned after this many days
+	ActivityDays int `json:"activity_days,omitempty"` // action log rows are pruned after this many days once synced
+}
+
+// LogCompactionConfig tunes td logs compact. Setting it also turns on the
+// td serve log compaction job. Zero values take the defaults.
+type LogCompactionConfig struct {
+	MinRun     int    `json:"min_run,omitempty"`    // shortest run of progress logs worth compacting; default 20
+	Keep       int    `json:"keep,omitempty"`       // latest logs of an issue never compacted; default 10
+	Summarizer string `json:"summarizer,omitempty"` // command writing the digest, run with sh -c; empty uses the built-in digest
+}
+
+// ScriptHooksConfig tunes the hook scripts the CLI runs from .todos/hooks.
+// Zero values take the defaults.
+type ScriptHooksConfig struct {
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty"` // default 10
+	OnFailure      string `json:"on_failure,omitempty"`      // fail, warn or ignore; default fail for pre- scripts, warn for post- scripts
+}
+
+// PolicyHookConfig configures a pre-transition policy hook: either a
+// built-in check or an external command. To and Priorities narrow which
+// transitions it runs on; empty matches all.
+type PolicyHookConfig struct {
+	Name           string   `json:"name"`
+	Builtin        string   `json:"builtin,omitempty"`         // require_handoff or require_acceptance
+	Command        string   `json:"command,omitempty"`         // run with sh -c; non-zero exit vetoes
+	To             []string `json:"to,omitempty"`              // target statuses
+	Priorities     []string `json:"priorities,omitempty"`      // issue priorities
+	TimeoutSeconds int      `json:"timeout_seconds,omitempty"` // command timeout; default 10
+}
+
+// RequiredFieldsRule lists the fields an issue of a type must have before
+// it moves to a status. An empty Type matches every type.
+type RequiredFieldsRule struct {
+	Type   string   `json:"type,omitempty"`
+	To     string   `json:"to"`
+	Fields []string `json:"fields"`
+}
+
+// ReviewChecklist lists the items a reviewer must acknowledge before
+// approving an issue of a type. An empty Type matches every type.
+type ReviewChecklist struct {
+	Type  string   `json:"type,omitempty"`
+	Items []string `json:"items"`
+}
+
+// ReviewAck records the checklist items a reviewer acknowledged when
+// approving an issue
+type ReviewAck struct {
+	ID        string    `json:"id"`
+	IssueID   string    `json:"issue_id"`
+	SessionID string    `json:"session_id"`
+	Items     []string  `json:"items"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 // ActionType represents the type of action that was performed
@@ -323,6 +1073,55 @@ func IsValidStatus(s Status) bool {
 	return false
 }
 
+// IsValidBlockedReason checks if a blocked reason is valid
+func IsValidBlockedReason(r BlockedReason) bool {
+	switch r {
+	case BlockedReasonDependency, BlockedReasonExternal, BlockedReasonDecision:
+		return true
+	}
+	return false
+}
+
+// NormalizeBlockedReason converts alternate blocked reason names to
+// canonical form. Accepts: "dep"/"deps", "waiting"/"waiting-on-external"
+// and "needs-decision".
+func NormalizeBlockedReason(r string) BlockedReason {
+	switch strings.ReplaceAll(strings.ToLower(r), "_", "-") {
+	case "dep", "deps", "dependencies":
+		return BlockedReasonDependency
+	case "waiting", "waiting-on-external", "ext":
+		return BlockedReasonExternal
+	case "needs-decision":
+		return BlockedReasonDecision
+	default:
+		return BlockedReason(strings.ToLower(r))
+	}
+}
+
+// IsValidRetroKind checks if a retro item kind is valid
+func IsValidRetroKind(k RetroKind) bool {
+	switch k {
+	case RetroWentWell, RetroNeedsImprovement, RetroActionItem:
+		return true
+	}
+	return false
+}
+
+// NormalizeRetroKind converts alternate retro item kinds to canonical
+// form. Accepts hyphens for underscores and "well", "improve", "action".
+func NormalizeRetroKind(k string) RetroKind {
+	switch strings.ReplaceAll(strings.ToLower(k), "-", "_") {
+	case "well", "good":
+		return RetroWentWell
+	case "improve", "improvement", "bad":
+		return RetroNeedsImprovement
+	case "action", "todo":
+		return RetroActionItem
+	default:
+		return RetroKind(strings.ReplaceAll(strings.ToLower(k), "-", "_"))
+	}
+}
+
 // IsValidType checks if a type is valid
 func IsValidType(t Type) bool {
 	switch t {
@@ -395,6 +1194,8 @@ type ExtendedStats struct {
 	ByStatus   map[Status]int
 	ByType     map[Type]int
 	ByPriority map[Priority]int
+	// Blocked issues by reason; "" counts those blocked without one
+	ByBlockedReason map[BlockedReason]int
 
 	// Timeline
 	OldestOpen      *Issue
@@ -412,4 +1213,10 @@ type ExtendedStats struct {
 	TotalLogs         int
 	TotalHandoffs     int
 	MostActiveSession string
+
+	// Rework: reopens of closed issues
+	TotalReworks     int
+	ReworkedIssues   int
+	ReworkRate       float64 // reworked issues / issues ever closed
+	ByReworkCategory map[ReworkCategory]int
 }
@@ -399,3 +399,18 @@ func TestFibonacciPropertyBetween(t *testing.T) {
 		}
 	}
 }
+
+func TestParseTokenScopes(t *testing.T) {
+	got, err := ParseTokenScopes(" Read, write,read ")
+	if err != nil || len(got) != 2 || got[0] != TokenScopeRead || got[1] != TokenScopeWrite {
+		t.Errorf("ParseTokenScopes = %v, %v; want [read write]", got, err)
+	}
+	if got, err := ParseTokenScopes("read,admin"); err != nil || len(got) != 2 || got[1] != TokenScopeAdmin {
+		t.Errorf("ParseTokenScopes(read,admin) = %v, %v", got, err)
+	}
+	for _, bad := range []string{"", " , ", "root", "read,root"} {
+		if _, err := ParseTokenScopes(bad); err == nil {
+			t.Errorf("ParseTokenScopes(%q) should fail", bad)
+		}
+	}
+}
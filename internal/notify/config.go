@@ -0,0 +1,82 @@
+// Package notify delivers monitor events through pluggable transports
+// (desktop, stdout, webhook, Slack, SMTP) chosen by per-event routes.
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/syncconfig"
+)
+
+// Event is a kind of notification the monitor or td serve can raise.
+type Event string
+
+const (
+	EventReview    Event = "review"     // an issue became reviewable by this session
+	EventMention   Event = "mention"    // a log or comment mentioned this session
+	EventP0        Event = "p0"         // a new P0 issue was created
+	EventReminder  Event = "reminder"   // a td remind reminder came due
+	EventJobFailed Event = "job_failed" // a td serve background job started failing
+)
+
+// AllEvents lists every supported event in display order.
+var AllEvents = []Event{EventReview, EventMention, EventP0, EventReminder, EventJobFailed}
+
+// ParseEvent validates an event name.
+func ParseEvent(s string) (Event, error) {
+	for _, ev := range AllEvents {
+		if string(ev) == strings.ToLower(strings.TrimSpace(s)) {
+			return ev, nil
+		}
+	}
+	return "", fmt.Errorf("unknown event %q (valid: review, mention, p0, reminder, job_failed)", s)
+}
+
+// Settings is the resolved notification configuration.
+type Settings struct {
+	Enabled bool
+	Events  map[Event]bool
+	Quiet   QuietHours
+}
+
+// Load resolves notification settings for a project.
+// Priority: project-local config > global config. Invalid values are ignored.
+func Load(baseDir string) Settings {
+	var nc *models.NotifyConfig
+	if cfg, err := config.Load(baseDir); err == nil && cfg.Notify != nil {
+		nc = cfg.Notify
+	} else if gcfg, err := syncconfig.LoadActiveConfig(); err == nil && gcfg.Notify != nil {
+		nc = gcfg.Notify
+	}
+	return FromConfig(nc)
+}
+
+// FromConfig converts a stored config into Settings. A nil config is disabled.
+func FromConfig(nc *models.NotifyConfig) Settings {
+	s := Settings{Events: make(map[Event]bool)}
+	if nc == nil {
+		return s
+	}
+	s.Enabled = nc.Enabled
+	for _, name := range nc.Events {
+		if ev, err := ParseEvent(name); err == nil {
+			s.Events[ev] = true
+		}
+	}
+	if len(s.Events) == 0 {
+		for _, ev := range AllEvents {
+			s.Events[ev] = true
+		}
+	}
+	s.Quiet, _ = ParseQuietHours(nc.QuietHours)
+	return s
+}
+
+// Allows reports whether an event should be delivered at the given time.
+func (s Settings) Allows(ev Event, now time.Time) bool {
+	return s.Enabled && s.Events[ev] && !s.Quiet.Contains(now)
+}
@@ -0,0 +1,47 @@
+package notify
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+func init() {
+	Register("desktop", func(target string) (Transport, error) {
+		if target != "" {
+			return nil, fmt.Errorf("takes no target")
+		}
+		return TransportFunc(func(msg Message) error { return Desktop(msg.Title, msg.Body) }), nil
+	})
+}
+
+// Desktop shows an OS notification.
+// Uses osascript on macOS and notify-send on Linux.
+func Desktop(title, body string) error {
+	var cmd *exec.Cmd
+
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s",
+			appleScriptQuote(body), appleScriptQuote(title))
+		cmd = exec.Command("osascript", "-e", script)
+	case "linux":
+		if _, err := exec.LookPath("notify-send"); err != nil {
+			return fmt.Errorf("notify-send not found (install libnotify)")
+		}
+		// "--" keeps a title or body starting with "-" from parsing as an option
+		cmd = exec.Command("notify-send", "--app-name=td", "--", title, body)
+	default:
+		return fmt.Errorf("desktop notifications unsupported on %s", runtime.GOOS)
+	}
+
+	return cmd.Run()
+}
+
+// appleScriptQuote returns s as an AppleScript string literal.
+func appleScriptQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
@@ -0,0 +1,90 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/marcus/td/internal/models"
+)
+
+// AnyEvent is the route event that matches every event.
+const AnyEvent = "*"
+
+// Dispatcher sends each message to the transports of the routes matching
+// its event. Core delivery only sees the Transport interface, so adding a
+// channel means registering a new kind, nothing more.
+type Dispatcher struct {
+	routes []boundRoute
+
+	// Queue, when set, takes messages for routes that reach over the
+	// network instead of sending them, so the delivery outbox can retry
+	// them; desktop and stdout routes are still sent directly.
+	Queue Queue
+}
+
+// Queue hands a message for a route to someone else to deliver
+type Queue func(route models.NotifyRoute, msg Message) error
+
+type boundRoute struct {
+	route     models.NotifyRoute
+	name      string // kind and target, for error messages
+	transport Transport
+}
+
+// Local reports whether a transport kind delivers on this machine, with
+// nothing to retry
+func Local(kind string) bool {
+	return kind == "desktop" || kind == "stdout"
+}
+
+// NewDispatcher builds the transports for routes. With no routes every
+// event goes to the desktop, which is how td notified before routes
+// existed.
+func NewDispatcher(routes []models.NotifyRoute) (*Dispatcher, error) {
+	if len(routes) == 0 {
+		routes = []models.NotifyRoute{{Event: AnyEvent, Transport: "desktop"}}
+	}
+	d := &Dispatcher{}
+	for _, r := range routes {
+		t, err := NewTransport(r.Transport, r.Target)
+		if err != nil {
+			return nil, fmt.Errorf("route %s: %w", r.ID, err)
+		}
+		name := r.Transport
+		if r.Target != "" {
+			name += " " + r.Target
+		}
+		d.routes = append(d.routes, boundRoute{route: r, name: name, transport: t})
+	}
+	return d, nil
+}
+
+// Send delivers msg through every matching route. Each route is tried even
+// when an earlier one fails; the errors are joined.
+func (d *Dispatcher) Send(msg Message) error {
+	var errs []error
+	for _, r := range d.routes {
+		if r.route.Event != AnyEvent && r.route.Event != string(msg.Event) {
+			continue
+		}
+		send := r.transport.Send
+		if d.Queue != nil && !Local(r.route.Transport) {
+			route := r.route
+			send = func(msg Message) error { return d.Queue(route, msg) }
+		}
+		if err := send(msg); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", r.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Routes reports whether any route delivers ev.
+func (d *Dispatcher) Routes(ev Event) bool {
+	for _, r := range d.routes {
+		if r.route.Event == AnyEvent || r.route.Event == string(ev) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package notify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func at(hh, mm int) time.Time {
+	return time.Date(2025, 1, 15, hh, mm, 0, 0, time.Local)
+}
+
+func TestParseQuietHours(t *testing.T) {
+	tests := []struct {
+		in      string
+		wantErr bool
+		inside  []time.Time
+		outside []time.Time
+	}{
+		{in: "", outside: []time.Time{at(0, 0), at(12, 0)}},
+		{in: "12:00-13:30", inside: []time.Time{at(12, 0), at(13, 29)}, outside: []time.Time{at(11, 59), at(13, 30)}},
+		{in: "22:00-07:00", inside: []time.Time{at(22, 0), at(3, 0), at(6, 59)}, outside: []time.Time{at(7, 0), at(21, 59)}},
+		{in: "9-5", wantErr: true},
+		{in: "22:00", wantErr: true},
+	}
+	for _, tt := range tests {
+		q, err := ParseQuietHours(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseQuietHours(%q) err = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		for _, ts := range tt.inside {
+			if !q.Contains(ts) {
+				t.Errorf("%q should contain %s", tt.in, ts.Format("15:04"))
+			}
+		}
+		for _, ts := range tt.outside {
+			if q.Contains(ts) {
+				t.Errorf("%q should not contain %s", tt.in, ts.Format("15:04"))
+			}
+		}
+	}
+}
+
+func TestFromConfig(t *testing.T) {
+	if s := FromConfig(nil); s.Enabled {
+		t.Error("nil config should be disabled")
+	}
+
+	s := FromConfig(&models.NotifyConfig{Enabled: true})
+	for _, ev := range AllEvents {
+		if !s.Allows(ev, at(12, 0)) {
+			t.Errorf("empty event list should allow %s", ev)
+		}
+	}
+
+	s = FromConfig(&models.NotifyConfig{Enabled: true, Events: []string{"p0"}, QuietHours: "22:00-07:00"})
+	if s.Allows(EventReview, at(12, 0)) {
+		t.Error("review should be filtered out")
+	}
+	if !s.Allows(EventP0, at(12, 0)) {
+		t.Error("p0 should be allowed outside quiet hours")
+	}
+	if s.Allows(EventP0, at(23, 0)) {
+		t.Error("p0 should be suppressed during quiet hours")
+	}
+}
+
+func TestAppleScriptQuote(t *testing.T) {
+	got := appleScriptQuote(`say "hi" \ bye`)
+	want := `"say \"hi\" \\ bye"`
+	if got != want {
+		t.Errorf("appleScriptQuote = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,27 @@
+package notify
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+func init() {
+	Register("stdout", func(target string) (Transport, error) {
+		if target != "" {
+			return nil, fmt.Errorf("takes no target")
+		}
+		return &writerTransport{w: os.Stdout}, nil
+	})
+}
+
+// writerTransport prints one line per message, for scripts that pipe the
+// monitor's output and for checking routes.
+type writerTransport struct {
+	w io.Writer
+}
+
+func (t *writerTransport) Send(msg Message) error {
+	_, err := fmt.Fprintf(t.w, "[%s] %s: %s\n", msg.Event, msg.Title, msg.Body)
+	return err
+}
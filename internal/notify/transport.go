@@ -0,0 +1,82 @@
+package notify
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Message is one notification handed to a transport.
+type Message struct {
+	Event   Event     `json:"event"`
+	Title   string    `json:"title"`
+	Body    string    `json:"body"`
+	IssueID string    `json:"issue_id,omitempty"` // empty when the event isn't about one issue
+	Time    time.Time `json:"time"`
+}
+
+// Transport delivers messages to one channel: the desktop, a chat room, a
+// mailbox, ...
+type Transport interface {
+	Send(msg Message) error
+}
+
+// Factory builds a transport for a route's target, such as a webhook URL
+// or SMTP address. Factories validate the target up front so a bad route
+// is rejected when it is added rather than when an event fires.
+type Factory func(target string) (Transport, error)
+
+// Sender delivers a notification. A Dispatcher's Send is the usual one;
+// tests substitute their own.
+type Sender func(msg Message) error
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = make(map[string]Factory)
+)
+
+// Register makes a transport kind available to routes. Built-in kinds
+// register themselves from init; registering a kind twice replaces it.
+func Register(kind string, f Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	factories[strings.ToLower(kind)] = f
+}
+
+// Kinds lists the registered transport kinds, sorted.
+func Kinds() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+	kinds := make([]string, 0, len(factories))
+	for kind := range factories {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// NewTransport builds a transport of the given kind. $VAR references in
+// target are expanded from the environment, so secrets such as SMTP
+// passwords need not be stored in the database.
+func NewTransport(kind, target string) (Transport, error) {
+	factoriesMu.RLock()
+	f, ok := factories[strings.ToLower(kind)]
+	factoriesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown transport %q (valid: %s)", kind, strings.Join(Kinds(), ", "))
+	}
+	t, err := f(os.ExpandEnv(target))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", kind, err)
+	}
+	return t, nil
+}
+
+// TransportFunc adapts a plain function to a Transport.
+type TransportFunc func(msg Message) error
+
+// Send calls f(msg).
+func (f TransportFunc) Send(msg Message) error { return f(msg) }
@@ -0,0 +1,84 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/marcus/td/internal/integrations"
+)
+
+func init() {
+	Register("webhook", func(target string) (Transport, error) {
+		if err := checkHTTPURL(target); err != nil {
+			return nil, err
+		}
+		return &webhookTransport{url: target}, nil
+	})
+	Register("slack", func(target string) (Transport, error) {
+		if err := checkHTTPURL(target); err != nil {
+			return nil, err
+		}
+		return TransportFunc(func(msg Message) error {
+			return integrations.Post(integrations.KindSlack, target, fmt.Sprintf("*%s*\n%s", msg.Title, msg.Body))
+		}), nil
+	})
+}
+
+// webhookPayload is the JSON body the webhook transport POSTs
+type webhookPayload struct {
+	Event   string    `json:"event"`
+	Title   string    `json:"title"`
+	Body    string    `json:"body"`
+	IssueID string    `json:"issue_id,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+// webhookTransport POSTs each message as JSON to a URL
+type webhookTransport struct {
+	url string
+}
+
+func (t *webhookTransport) Send(msg Message) error {
+	body, err := json.Marshal(webhookPayload{
+		Event:   string(msg.Event),
+		Title:   msg.Title,
+		Body:    msg.Body,
+		IssueID: msg.IssueID,
+		Time:    msg.Time.UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", t.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "td-notify/1")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("POST %s: %w", t.url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("POST %s: status %d", t.url, resp.StatusCode)
+	}
+	return nil
+}
+
+// checkHTTPURL validates a transport target that must be an http(s) URL
+func checkHTTPURL(target string) error {
+	u, err := url.Parse(target)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("target must be an http(s) URL, got %q", target)
+	}
+	return nil
+}
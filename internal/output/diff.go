@@ -0,0 +1,27 @@
+package output
+
+import (
+	"strings"
+
+	"github.com/marcus/td/internal/textdiff"
+)
+
+// FormatDiff renders a line diff with each line indented, deletions in red
+// and insertions in green
+func FormatDiff(lines []textdiff.Line, indent string) string {
+	var sb strings.Builder
+	for _, l := range lines {
+		line := indent + string(l.Op) + " " + l.Text
+		switch l.Op {
+		case textdiff.Delete:
+			line = errorStyle.Render(line)
+		case textdiff.Insert:
+			line = successStyle.Render(line)
+		default:
+			line = subtleStyle.Render(line)
+		}
+		sb.WriteString(line)
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
@@ -78,6 +78,8 @@ const (
 	ErrCodeDatabaseError     = "database_error"
 	ErrCodeGitError          = "git_error"
 	ErrCodeNoActiveSession   = "no_active_session"
+	ErrCodeMissingFields     = "missing_required_fields"
+	ErrCodeChecklist         = "review_checklist_incomplete"
 )
 
 // JSONError outputs an error as JSON
@@ -116,6 +118,12 @@ func FormatPriority(p models.Priority) string {
 	return priorityStyle.Render(fmt.Sprintf("[%s]", p))
 }
 
+// FormatInheritedPriority marks a priority raised by inheritance, e.g.
+// "↑P0 from td-a1b2c3"
+func FormatInheritedPriority(ip models.InheritedPriority) string {
+	return priorityStyle.Render("↑"+string(ip.Priority)) + subtleStyle.Render(" from "+ip.From)
+}
+
 // FormatPoints returns empty string if points is 0, otherwise "Npts"
 func FormatPoints(points int) string {
 	if points == 0 {
@@ -146,6 +154,12 @@ func FormatIssueShort(issue *models.Issue) string {
 
 	parts = append(parts, subtleStyle.Render(string(issue.Type)))
 	parts = append(parts, FormatStatus(issue.Status))
+	if issue.Inbox {
+		parts = append(parts, subtleStyle.Render("[inbox]"))
+	}
+	if issue.Confidential {
+		parts = append(parts, subtleStyle.Render("[confidential]"))
+	}
 
 	return strings.Join(parts, "  ")
 }
@@ -182,6 +196,12 @@ func FormatIssueLong(issue *models.Issue, logs []models.Log, handoff *models.Han
 	if issue.Minor {
 		sb.WriteString(" | Minor")
 	}
+	if issue.Inbox {
+		sb.WriteString(" | Inbox")
+	}
+	if issue.Confidential {
+		sb.WriteString(" | Confidential")
+	}
 	sb.WriteString("\n")
 
 	if len(issue.Labels) > 0 {
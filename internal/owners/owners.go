@@ -0,0 +1,161 @@
+// Package owners maps repository paths to labels and owners, like a
+// CODEOWNERS file. The map lives in .todos/owners, one rule per line:
+//
+//	# comment
+//	*.sql                 #db
+//	/internal/sync/       #sync @alice
+//	docs/                 #docs @docs-team
+//
+// A rule is a path pattern followed by #labels and @owners. Patterns use
+// path.Match syntax within a segment. One with a slash in it (other than a
+// trailing one) is anchored at the repository root; one without matches a
+// file or directory name at any depth. A pattern that matches a directory
+// covers everything inside it, and a trailing slash matches directories
+// only. As in CODEOWNERS, the last matching rule wins.
+//
+// td link labels issues with the labels of the files linked to them, and
+// the owners of an issue's files are suggested as its reviewers.
+package owners
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Rule is one line of the owners file
+type Rule struct {
+	Pattern string   `json:"pattern"`
+	Labels  []string `json:"labels,omitempty"`
+	Owners  []string `json:"owners,omitempty"`
+	Line    int      `json:"line"`
+}
+
+// Map is a parsed owners file. The zero Map matches nothing.
+type Map struct {
+	Rules []Rule
+}
+
+// Path returns where the project's owners file lives
+func Path(baseDir string) string {
+	return filepath.Join(baseDir, ".todos", "owners")
+}
+
+// Load reads the project's owners file. A project without one gets an
+// empty map.
+func Load(baseDir string) (*Map, error) {
+	f, err := os.Open(Path(baseDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return &Map{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// Parse reads owners rules from r, rejecting lines whose entries are
+// neither #labels nor @owners
+func Parse(r io.Reader) (*Map, error) {
+	m := &Map{}
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		rule := Rule{Pattern: fields[0], Line: n}
+		if _, err := path.Match(strings.Trim(rule.Pattern, "/"), ""); err != nil {
+			return nil, fmt.Errorf("line %d: invalid pattern %q", n, rule.Pattern)
+		}
+		for _, f := range fields[1:] {
+			switch {
+			case len(f) > 1 && f[0] == '#':
+				rule.Labels = append(rule.Labels, f[1:])
+			case len(f) > 1 && f[0] == '@':
+				rule.Owners = append(rule.Owners, f[1:])
+			default:
+				return nil, fmt.Errorf("line %d: %q is neither a #label nor an @owner", n, f)
+			}
+		}
+		if len(rule.Labels) == 0 && len(rule.Owners) == 0 {
+			return nil, fmt.Errorf("line %d: %s has no labels or owners", n, rule.Pattern)
+		}
+		m.Rules = append(m.Rules, rule)
+	}
+	return m, sc.Err()
+}
+
+// Match returns the rule that applies to file, a slash-separated path
+// relative to the repository root, or nil when none does
+func (m *Map) Match(file string) *Rule {
+	file = strings.TrimPrefix(path.Clean(filepath.ToSlash(file)), "/")
+	for i := len(m.Rules) - 1; i >= 0; i-- {
+		if matches(m.Rules[i].Pattern, file) {
+			return &m.Rules[i]
+		}
+	}
+	return nil
+}
+
+func matches(pattern, file string) bool {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	pattern = strings.Trim(pattern, "/")
+	segs := strings.Split(file, "/")
+
+	// The pattern must match the whole path, or a directory it is in
+	last := len(segs)
+	if dirOnly {
+		last--
+	}
+	for i := 1; i <= last; i++ {
+		var ok bool
+		if anchored {
+			ok, _ = path.Match(pattern, strings.Join(segs[:i], "/"))
+		} else {
+			ok, _ = path.Match(pattern, segs[i-1])
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Ownership is what the rules say about a set of files
+type Ownership struct {
+	Labels []string            `json:"labels"`
+	Owners map[string][]string `json:"owners"` // owner -> the files they own
+}
+
+// For collects the labels and owners of files, each sorted and listed
+// once
+func (m *Map) For(files []string) Ownership {
+	o := Ownership{Labels: []string{}, Owners: map[string][]string{}}
+	seen := make(map[string]bool)
+	for _, f := range files {
+		rule := m.Match(f)
+		if rule == nil {
+			continue
+		}
+		for _, l := range rule.Labels {
+			if !seen[l] {
+				seen[l] = true
+				o.Labels = append(o.Labels, l)
+			}
+		}
+		for _, owner := range rule.Owners {
+			o.Owners[owner] = append(o.Owners[owner], f)
+		}
+	}
+	sort.Strings(o.Labels)
+	return o
+}
@@ -0,0 +1,126 @@
+package owners
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+const sample = `# Ownership for the monorepo
+*.sql                   #db
+/internal/sync/         #sync @alice
+docs/                   #docs @docs-team
+/internal/sync/*_test.go #sync #tests @bob
+`
+
+func TestMatch(t *testing.T) {
+	m, err := Parse(strings.NewReader(sample))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	tests := []struct {
+		file string
+		want string // pattern of the matching rule, "" for none
+	}{
+		{"migrations/001_init.sql", "*.sql"},
+		{"internal/sync/engine.go", "/internal/sync/"},
+		{"internal/sync/engine_test.go", "/internal/sync/*_test.go"},
+		{"internal/sync", ""}, // a file named like the directory
+		{"website/docs/intro.md", "docs/"},
+		{"./docs/api.md", "docs/"},
+		{"cmd/root.go", ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if r := m.Match(tt.file); r != nil {
+			got = r.Pattern
+		}
+		if got != tt.want {
+			t.Errorf("Match(%s) = %q, want %q", tt.file, got, tt.want)
+		}
+	}
+
+	o := m.For([]string{"internal/sync/engine.go", "internal/sync/engine_test.go", "schema.sql", "cmd/root.go"})
+	if !reflect.DeepEqual(o.Labels, []string{"db", "sync", "tests"}) {
+		t.Errorf("labels = %v", o.Labels)
+	}
+	if len(o.Owners) != 2 || len(o.Owners["alice"]) != 1 || len(o.Owners["bob"]) != 1 {
+		t.Errorf("owners = %v", o.Owners)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, text := range []string{
+		"docs/ alice",    // owner without @
+		"docs/",          // nothing assigned
+		"[docs/ #docs\n", // bad pattern
+	} {
+		if _, err := Parse(strings.NewReader(text)); err == nil || !strings.Contains(err.Error(), "line 1") {
+			t.Errorf("Parse(%q) err = %v, want a line 1 error", text, err)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	if m, err := Load(dir); err != nil || len(m.Rules) != 0 {
+		t.Fatalf("Load without a file = %+v, %v", m, err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, ".todos"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(Path(dir), []byte(sample), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if m, err := Load(dir); err != nil || len(m.Rules) != 4 {
+		t.Errorf("Load = %d rules, %v", len(m.Rules), err)
+	}
+}
+
+func TestReviewers(t *testing.T) {
+	database, err := db.Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	defer database.Close()
+
+	for _, s := range []db.SessionRow{{ID: "ses_a1", Name: "alice"}, {ID: "ses_a2", Name: "alice"}, {ID: "ses_b1", Name: "bob"}} {
+		s.StartedAt, s.LastActivity = time.Now(), time.Now()
+		if err := database.UpsertSession(&s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	issue := &models.Issue{Title: "Retry sync pushes on timeout"}
+	if err := database.CreateIssue(issue); err != nil {
+		t.Fatal(err)
+	}
+	issue.Status = models.StatusInReview
+	issue.ImplementerSession = "ses_a2"
+	if err := database.UpdateIssue(issue); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range []string{"internal/sync/engine.go", "internal/sync/push.go", "internal/sync/engine_test.go"} {
+		if err := database.LinkFile(issue.ID, f, models.FileRoleImplementation, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m, _ := Parse(strings.NewReader(sample))
+	got, err := Reviewers(database, m, issue)
+	if err != nil {
+		t.Fatalf("Reviewers: %v", err)
+	}
+	if len(got) != 2 || got[0].Owner != "alice" || len(got[0].Files) != 2 || got[1].Owner != "bob" {
+		t.Fatalf("reviewers = %+v", got)
+	}
+	// ses_a2 implemented the issue, so only the other alice session may review
+	if !reflect.DeepEqual(got[0].Sessions, []string{"ses_a1"}) || !reflect.DeepEqual(got[1].Sessions, []string{"ses_b1"}) {
+		t.Errorf("sessions = %v, %v", got[0].Sessions, got[1].Sessions)
+	}
+}
@@ -0,0 +1,63 @@
+package owners
+
+import (
+	"sort"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+// Reviewer is an owner of some of an issue's files, suggested to review it
+type Reviewer struct {
+	Owner    string   `json:"owner"`
+	Files    []string `json:"files"`
+	Sessions []string `json:"sessions"` // sessions named or identified as Owner that may review the issue
+}
+
+// Reviewers suggests reviewers for issue from the owners of its linked
+// files, those owning the most files first. Sessions are matched to an
+// owner by name or ID; ones that worked on the implementation are left
+// out, since they cannot approve it.
+func Reviewers(database *db.DB, m *Map, issue *models.Issue) ([]Reviewer, error) {
+	linked, err := database.GetLinkedFiles(issue.ID)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, len(linked))
+	for i, f := range linked {
+		files[i] = f.FilePath
+	}
+	owned := m.For(files).Owners
+	if len(owned) == 0 {
+		return []Reviewer{}, nil
+	}
+
+	sessions, err := database.ListAllSessions()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Reviewer, 0, len(owned))
+	for owner, files := range owned {
+		r := Reviewer{Owner: owner, Files: files, Sessions: []string{}}
+		for _, s := range sessions {
+			if s.Name != owner && s.ID != owner || s.ID == issue.ImplementerSession {
+				continue
+			}
+			involved, err := database.WasSessionImplementationInvolved(issue.ID, s.ID)
+			if err != nil {
+				return nil, err
+			}
+			if !involved {
+				r.Sessions = append(r.Sessions, s.ID)
+			}
+		}
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if len(out[i].Files) != len(out[j].Files) {
+			return len(out[i].Files) > len(out[j].Files)
+		}
+		return out[i].Owner < out[j].Owner
+	})
+	return out, nil
+}
@@ -0,0 +1,335 @@
+// Package plan builds and applies previewed bulk changes. A plan records
+// the exact field changes a wide-reaching operation will make; nothing is
+// modified until the plan is applied, and only while it is unexpired.
+package plan
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/query"
+	"github.com/marcus/td/internal/workflow"
+)
+
+// DefaultTTL is how long a plan can be applied after it is created
+const DefaultTTL = time.Hour
+
+// Change fields
+const (
+	FieldStatus = "status"
+	FieldLabels = "labels"
+	FieldSprint = "sprint"
+)
+
+var (
+	// ErrNoChanges is returned when a plan would not modify any issue
+	ErrNoChanges = errors.New("nothing to change")
+	// ErrExpired is returned when applying a plan past its TTL
+	ErrExpired = errors.New("plan expired")
+)
+
+// Skip records a planned change that was not applied
+type Skip struct {
+	IssueID string `json:"issue_id"`
+	Reason  string `json:"reason"`
+}
+
+// Result reports the outcome of applying a plan
+type Result struct {
+	Plan    *models.Plan `json:"plan"`
+	Applied []string     `json:"applied"`
+	Skipped []Skip       `json:"skipped"`
+}
+
+// Close plans closing issues given by ID and/or a TDQ query. Issues that
+// are already closed or cannot transition to closed are left out.
+func Close(database *db.DB, ids []string, tdq, sessionID string) (*models.Plan, error) {
+	seen := make(map[string]bool)
+	var issues []models.Issue
+	for _, id := range ids {
+		issue, err := database.GetIssue(db.NormalizeIssueID(id))
+		if err != nil {
+			return nil, err
+		}
+		if !seen[issue.ID] {
+			seen[issue.ID] = true
+			issues = append(issues, *issue)
+		}
+	}
+	if tdq != "" {
+		matched, err := query.Execute(database, tdq, sessionID, query.ExecuteOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range matched {
+			if !seen[issue.ID] {
+				seen[issue.ID] = true
+				issues = append(issues, issue)
+			}
+		}
+	}
+
+	sm := workflow.DefaultMachine()
+	var changes []models.PlanChange
+	for _, issue := range issues {
+		if !sm.IsValidTransition(issue.Status, models.StatusClosed) {
+			continue
+		}
+		changes = append(changes, models.PlanChange{
+			IssueID: issue.ID,
+			Title:   issue.Title,
+			Field:   FieldStatus,
+			From:    string(issue.Status),
+			To:      string(models.StatusClosed),
+		})
+	}
+	return newPlan(models.PlanClose, fmt.Sprintf("Close %s", pluralIssues(len(changes))), changes, sessionID)
+}
+
+// Relabel plans renaming label from to label to on every issue carrying it.
+func Relabel(database *db.DB, from, to, sessionID string) (*models.Plan, error) {
+	from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+	if from == "" || to == "" {
+		return nil, fmt.Errorf("both labels are required")
+	}
+	if from == to {
+		return nil, ErrNoChanges
+	}
+	if strings.Contains(to, ",") {
+		return nil, fmt.Errorf("label cannot contain a comma: %q", to)
+	}
+
+	issues, err := database.ListIssues(db.ListIssuesOptions{Labels: []string{from}, SortBy: "id"})
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []models.PlanChange
+	for _, issue := range issues {
+		if !hasLabel(issue.Labels, from) {
+			continue
+		}
+		var labels []string
+		for _, l := range issue.Labels {
+			if l == from {
+				l = to
+			}
+			if !hasLabel(labels, l) {
+				labels = append(labels, l)
+			}
+		}
+		changes = append(changes, models.PlanChange{
+			IssueID: issue.ID,
+			Title:   issue.Title,
+			Field:   FieldLabels,
+			From:    strings.Join(issue.Labels, ","),
+			To:      strings.Join(labels, ","),
+		})
+	}
+	summary := fmt.Sprintf("Rename label %s to %s on %s", from, to, pluralIssues(len(changes)))
+	return newPlan(models.PlanRelabel, summary, changes, sessionID)
+}
+
+// CarryOver plans moving every unclosed issue in sprint from to sprint to.
+func CarryOver(database *db.DB, from, to, sessionID string) (*models.Plan, error) {
+	if from == "" || to == "" {
+		return nil, fmt.Errorf("both sprints are required")
+	}
+	if from == to {
+		return nil, ErrNoChanges
+	}
+
+	issues, err := database.ListIssues(db.ListIssuesOptions{
+		Status: []models.Status{models.StatusOpen, models.StatusInProgress, models.StatusBlocked, models.StatusInReview},
+		SortBy: "id",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []models.PlanChange
+	for _, issue := range issues {
+		if issue.Sprint != from {
+			continue
+		}
+		changes = append(changes, models.PlanChange{
+			IssueID: issue.ID,
+			Title:   issue.Title,
+			Field:   FieldSprint,
+			From:    from,
+			To:      to,
+		})
+	}
+	summary := fmt.Sprintf("Carry %s over from sprint %s to %s", pluralIssues(len(changes)), from, to)
+	return newPlan(models.PlanCarryOver, summary, changes, sessionID)
+}
+
+func newPlan(kind models.PlanKind, summary string, changes []models.PlanChange, sessionID string) (*models.Plan, error) {
+	if len(changes) == 0 {
+		return nil, ErrNoChanges
+	}
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].IssueID < changes[j].IssueID })
+	return &models.Plan{
+		Kind:      kind,
+		Summary:   summary,
+		Changes:   changes,
+		SessionID: sessionID,
+	}, nil
+}
+
+// Save persists a built plan with the given TTL (DefaultTTL if <= 0).
+func Save(database *db.DB, p *models.Plan, ttl time.Duration) error {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	p.ExpiresAt = clock.Now().Add(ttl).UTC().Truncate(time.Second)
+	return database.CreatePlan(p)
+}
+
+// Apply claims a pending plan and makes its changes as sessionID. Each
+// change is re-checked against the issue's current value; issues that
+// drifted since the plan was made are skipped rather than overwritten.
+func Apply(database *db.DB, planID, sessionID string) (*Result, error) {
+	p, err := database.GetPlan(planID)
+	if err != nil {
+		return nil, err
+	}
+	now := clock.Now()
+	if p.Expired(now) {
+		return nil, fmt.Errorf("%w at %s", ErrExpired, p.ExpiresAt.Local().Format("2006-01-02 15:04"))
+	}
+	if p.Status != models.PlanPending {
+		return nil, fmt.Errorf("%w (%s)", db.ErrPlanNotPending, p.Status)
+	}
+	if err := database.ClaimPlan(p.ID, sessionID, now); err != nil {
+		return nil, err
+	}
+	if p, err = database.GetPlan(p.ID); err != nil {
+		return nil, err
+	}
+
+	res := &Result{Plan: p, Applied: []string{}, Skipped: []Skip{}}
+	for _, c := range p.Changes {
+		if reason := applyChange(database, p, c, sessionID); reason != "" {
+			res.Skipped = append(res.Skipped, Skip{IssueID: c.IssueID, Reason: reason})
+			continue
+		}
+		res.Applied = append(res.Applied, c.IssueID)
+	}
+	return res, nil
+}
+
+// applyChange makes one change, returning a skip reason or "" on success
+func applyChange(database *db.DB, p *models.Plan, c models.PlanChange, sessionID string) string {
+	issue, err := database.GetIssue(c.IssueID)
+	if err != nil {
+		return "issue not found"
+	}
+	if current := fieldValue(issue, c.Field); current != c.From {
+		return fmt.Sprintf("%s changed since plan (now %q)", c.Field, current)
+	}
+
+	if c.Field == FieldStatus {
+		return applyClose(database, p, c, issue, sessionID)
+	}
+
+	switch c.Field {
+	case FieldLabels:
+		issue.Labels = nil
+		if c.To != "" {
+			issue.Labels = strings.Split(c.To, ",")
+		}
+	case FieldSprint:
+		issue.Sprint = c.To
+	default:
+		return "unknown field " + c.Field
+	}
+
+	if err := database.UpdateIssueLogged(issue, sessionID, models.ActionUpdate); err != nil {
+		return err.Error()
+	}
+	logChange(database, p, c, issue.ID, sessionID)
+	return ""
+}
+
+// applyClose closes an issue the way td close does: the transition and
+// self-close rules are checked, and parents and dependents are cascaded
+func applyClose(database *db.DB, p *models.Plan, c models.PlanChange, issue *models.Issue, sessionID string) string {
+	if !workflow.DefaultMachine().IsValidTransition(issue.Status, models.StatusClosed) {
+		return fmt.Sprintf("cannot close from %s", issue.Status)
+	}
+	minorSelfClose, err := database.CheckSelfClose(issue, sessionID)
+	if err != nil {
+		return err.Error()
+	}
+
+	if err := database.CloseIssueLogged(issue, sessionID); err != nil {
+		return err.Error()
+	}
+	logChange(database, p, c, issue.ID, sessionID)
+	if minorSelfClose {
+		_ = database.RecordOverride(&models.Override{
+			IssueID:       issue.ID,
+			Kind:          models.OverrideMinorSelfClose,
+			Justification: "plan " + p.ID,
+			SessionID:     sessionID,
+		})
+	}
+	database.CascadeClose(issue.ID, sessionID)
+	return ""
+}
+
+// logChange notes on the issue that a plan made a change
+func logChange(database *db.DB, p *models.Plan, c models.PlanChange, issueID, sessionID string) {
+	_ = database.AddLog(&models.Log{
+		IssueID:   issueID,
+		SessionID: sessionID,
+		Message:   fmt.Sprintf("%s via plan %s", changeVerb(c), p.ID),
+		Type:      models.LogTypeProgress,
+	})
+}
+
+func fieldValue(issue *models.Issue, field string) string {
+	switch field {
+	case FieldStatus:
+		return string(issue.Status)
+	case FieldLabels:
+		return strings.Join(issue.Labels, ",")
+	case FieldSprint:
+		return issue.Sprint
+	}
+	return ""
+}
+
+func changeVerb(c models.PlanChange) string {
+	switch c.Field {
+	case FieldStatus:
+		return "Closed"
+	case FieldSprint:
+		return fmt.Sprintf("Moved from sprint %s to %s", c.From, c.To)
+	default:
+		return fmt.Sprintf("Set %s to %q", c.Field, c.To)
+	}
+}
+
+func hasLabel(labels []string, label string) bool {
+	for _, l := range labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+func pluralIssues(n int) string {
+	if n == 1 {
+		return "1 issue"
+	}
+	return fmt.Sprintf("%d issues", n)
+}
@@ -0,0 +1,187 @@
+package plan
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+func setupDB(t *testing.T) *db.DB {
+	t.Helper()
+	database, err := db.Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	return database
+}
+
+func mustCreate(t *testing.T, database *db.DB, issue *models.Issue) *models.Issue {
+	t.Helper()
+	if err := database.CreateIssue(issue); err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+	return issue
+}
+
+func TestClosePlanAndApply(t *testing.T) {
+	database := setupDB(t)
+	a := mustCreate(t, database, &models.Issue{Title: "Stale thing one", Labels: []string{"stale"}})
+	b := mustCreate(t, database, &models.Issue{Title: "Stale thing two", Labels: []string{"stale"}})
+	mustCreate(t, database, &models.Issue{Title: "Already done", Labels: []string{"stale"}, Status: models.StatusClosed})
+	mine := mustCreate(t, database, &models.Issue{Title: "My own work"})
+	mine.ImplementerSession = "ses_me"
+	if err := database.UpdateIssue(mine); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := Close(database, []string{mine.ID}, "labels ~ stale", "ses_agent")
+	if err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if len(p.Changes) != 3 || p.Summary != "Close 3 issues" {
+		t.Fatalf("plan = %+v", p)
+	}
+	if err := Save(database, p, 0); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	// Nothing changes until apply
+	if got, _ := database.GetIssue(a.ID); got.Status != models.StatusOpen {
+		t.Fatalf("issue changed before apply: %s", got.Status)
+	}
+
+	// b drifts before apply and must be skipped, not overwritten
+	b.Status = models.StatusInProgress
+	if err := database.UpdateIssue(b); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Apply(database, p.ID, "ses_me")
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if len(res.Applied) != 1 || res.Applied[0] != a.ID {
+		t.Errorf("applied = %v", res.Applied)
+	}
+	if len(res.Skipped) != 2 {
+		t.Errorf("skipped = %+v (want drifted issue and own implementation)", res.Skipped)
+	}
+	if got, _ := database.GetIssue(a.ID); got.Status != models.StatusClosed || got.ClosedAt == nil {
+		t.Errorf("a after apply = %s", got.Status)
+	}
+	if res.Plan.Status != models.PlanApplied || res.Plan.AppliedBy != "ses_me" {
+		t.Errorf("plan after apply = %+v", res.Plan)
+	}
+
+	if _, err := Apply(database, p.ID, "ses_me"); !errors.Is(err, db.ErrPlanNotPending) {
+		t.Errorf("second apply err = %v", err)
+	}
+}
+
+func TestApplyCloseCascades(t *testing.T) {
+	database := setupDB(t)
+	epic := mustCreate(t, database, &models.Issue{Title: "Epic to finish", Type: models.TypeEpic})
+	child := mustCreate(t, database, &models.Issue{Title: "Last open child", ParentID: epic.ID})
+	dependent := mustCreate(t, database, &models.Issue{Title: "Waiting on child", Status: models.StatusBlocked})
+	if err := database.AddDependency(dependent.ID, child.ID, "depends_on"); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := Close(database, []string{child.ID}, "", "ses_agent")
+	if err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := Save(database, p, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Apply(database, p.ID, "ses_agent"); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	if got, _ := database.GetIssue(epic.ID); got.Status != models.StatusClosed {
+		t.Errorf("parent after apply = %s, want closed", got.Status)
+	}
+	if got, _ := database.GetIssue(dependent.ID); got.Status != models.StatusOpen {
+		t.Errorf("dependent after apply = %s, want open", got.Status)
+	}
+}
+
+func TestRelabelAndCarryOver(t *testing.T) {
+	database := setupDB(t)
+	a := mustCreate(t, database, &models.Issue{Title: "Frontend thing", Labels: []string{"frontend", "ui"}})
+	mustCreate(t, database, &models.Issue{Title: "Frontend-ish only", Labels: []string{"frontend-legacy"}})
+
+	p, err := Relabel(database, "frontend", "ui", "ses_a")
+	if err != nil {
+		t.Fatalf("Relabel: %v", err)
+	}
+	if len(p.Changes) != 1 || p.Changes[0].From != "frontend,ui" || p.Changes[0].To != "ui" {
+		t.Fatalf("relabel changes = %+v", p.Changes)
+	}
+	if err := Save(database, p, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Apply(database, p.ID, "ses_a"); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if got, _ := database.GetIssue(a.ID); len(got.Labels) != 1 || got.Labels[0] != "ui" {
+		t.Errorf("labels after apply = %v", got.Labels)
+	}
+
+	open := mustCreate(t, database, &models.Issue{Title: "Unfinished sprint work"})
+	done := mustCreate(t, database, &models.Issue{Title: "Finished sprint work", Status: models.StatusClosed})
+	for _, issue := range []*models.Issue{open, done} {
+		issue.Sprint = "s1"
+		if err := database.UpdateIssue(issue); err != nil {
+			t.Fatal(err)
+		}
+	}
+	p, err = CarryOver(database, "s1", "s2", "ses_a")
+	if err != nil {
+		t.Fatalf("CarryOver: %v", err)
+	}
+	if len(p.Changes) != 1 || p.Changes[0].IssueID != open.ID {
+		t.Errorf("carryover changes = %+v", p.Changes)
+	}
+
+	if _, err := CarryOver(database, "s9", "s10", "ses_a"); !errors.Is(err, ErrNoChanges) {
+		t.Errorf("empty carryover err = %v", err)
+	}
+}
+
+func TestApplyExpiredAndDiscarded(t *testing.T) {
+	database := setupDB(t)
+	mustCreate(t, database, &models.Issue{Title: "Something to close"})
+
+	p, err := Close(database, nil, "status = open", "ses_a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.ExpiresAt = time.Now().Add(-time.Minute)
+	if err := database.CreatePlan(p); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Apply(database, p.ID, "ses_b"); !errors.Is(err, ErrExpired) {
+		t.Errorf("expired apply err = %v", err)
+	}
+
+	p2, _ := Close(database, nil, "status = open", "ses_a")
+	if err := Save(database, p2, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.DiscardPlan(p2.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Apply(database, p2.ID, "ses_b"); !errors.Is(err, db.ErrPlanNotPending) {
+		t.Errorf("discarded apply err = %v", err)
+	}
+
+	pending, err := database.ListPlans(true)
+	if err != nil || len(pending) != 1 || pending[0].ID != p.ID {
+		t.Errorf("pending plans = %+v, %v", pending, err)
+	}
+}
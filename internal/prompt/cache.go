@@ -0,0 +1,98 @@
+// Package prompt caches the project summary behind td prompt. Shell
+// prompts run it on every command line, so it must answer in a few
+// milliseconds; opening SQLite and checking migrations takes longer than
+// that. The cache is keyed on the size and modification time of the
+// database and its write-ahead log, which change with every write, so a
+// cached summary is never older than the last one computed after a write.
+package prompt
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/marcus/td/internal/models"
+)
+
+const cacheFile = "prompt_cache.json"
+
+// Stamp identifies one state of the database files
+type Stamp struct {
+	DBSize     int64     `json:"db_size"`
+	DBModTime  time.Time `json:"db_mod_time"`
+	WALSize    int64     `json:"wal_size"`
+	WALModTime time.Time `json:"wal_mod_time"`
+}
+
+// CacheEntry is a summary and the database state it was computed from
+type CacheEntry struct {
+	Stamp   Stamp          `json:"stamp"`
+	Summary models.Summary `json:"summary"`
+}
+
+// cachePath returns where the cache lives for the project at baseDir
+func cachePath(baseDir string) string {
+	return filepath.Join(baseDir, ".todos", cacheFile)
+}
+
+// CurrentStamp stats the database files of the project at baseDir. It
+// fails when the project has no database.
+func CurrentStamp(baseDir string) (Stamp, error) {
+	var s Stamp
+	dbPath := filepath.Join(baseDir, ".todos", "issues.db")
+	info, err := os.Stat(dbPath)
+	if err != nil {
+		return s, err
+	}
+	s.DBSize, s.DBModTime = info.Size(), info.ModTime()
+	// The WAL exists only while a connection is open or uncheckpointed
+	if info, err := os.Stat(dbPath + "-wal"); err == nil {
+		s.WALSize, s.WALModTime = info.Size(), info.ModTime()
+	}
+	return s, nil
+}
+
+// Load returns the cached summary if the database is still in the state
+// it was computed from
+func Load(baseDir string, stamp Stamp) (*models.Summary, bool) {
+	data, err := os.ReadFile(cachePath(baseDir))
+	if err != nil {
+		return nil, false
+	}
+	var entry CacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || !entry.Stamp.Equal(stamp) {
+		return nil, false
+	}
+	return &entry.Summary, true
+}
+
+// Save caches sum for the database state stamp. Writing to a temporary
+// file and renaming keeps concurrent prompts from reading half a file.
+func Save(baseDir string, stamp Stamp, sum *models.Summary) error {
+	data, err := json.Marshal(CacheEntry{Stamp: stamp, Summary: *sum})
+	if err != nil {
+		return err
+	}
+	path := cachePath(baseDir)
+	tmp, err := os.CreateTemp(filepath.Dir(path), cacheFile+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Equal reports whether two stamps describe the same database state
+func (s Stamp) Equal(o Stamp) bool {
+	return s.DBSize == o.DBSize && s.DBModTime.Equal(o.DBModTime) &&
+		s.WALSize == o.WALSize && s.WALModTime.Equal(o.WALModTime)
+}
@@ -0,0 +1,51 @@
+package prompt
+
+import (
+	"testing"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+func TestCacheFollowsDatabase(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := CurrentStamp(dir); err == nil {
+		t.Fatal("CurrentStamp without a database succeeded")
+	}
+
+	database, err := db.Initialize(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	database.Close()
+	stamp, err := CurrentStamp(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := Load(dir, stamp); ok {
+		t.Fatal("Load hit before anything was saved")
+	}
+	sum := &models.Summary{ByStatus: map[models.Status]int{models.StatusInReview: 2}, ReviewQueue: 2}
+	if err := Save(dir, stamp, sum); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := Load(dir, stamp); !ok || got.ReviewQueue != 2 || got.ByStatus[models.StatusInReview] != 2 {
+		t.Fatalf("Load = %+v, %v", got, ok)
+	}
+
+	database, err = db.Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := database.CreateIssue(&models.Issue{Title: "Invalidate the prompt cache"}); err != nil {
+		t.Fatal(err)
+	}
+	database.Close()
+	after, err := CurrentStamp(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := Load(dir, after); ok {
+		t.Error("Load hit after a write")
+	}
+}
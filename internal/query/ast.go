@@ -24,6 +24,26 @@ func (b *BinaryExpr) String() string {
 
 func (b *BinaryExpr) nodeType() string { return "BinaryExpr" }
 
+// And joins nodes with AND, skipping nils. It returns nil if every node is nil.
+func And(nodes ...Node) Node { return join(OpAnd, nodes) }
+
+// Or joins nodes with OR, skipping nils. It returns nil if every node is nil.
+func Or(nodes ...Node) Node { return join(OpOr, nodes) }
+
+func join(op string, nodes []Node) Node {
+	var root Node
+	for _, n := range nodes {
+		switch {
+		case n == nil:
+		case root == nil:
+			root = n
+		default:
+			root = &BinaryExpr{Op: op, Left: root, Right: n}
+		}
+	}
+	return root
+}
+
 // UnaryExpr represents a unary expression (NOT)
 type UnaryExpr struct {
 	Op   string // "NOT"
@@ -141,24 +161,31 @@ const (
 // Known field names for validation
 var KnownFields = map[string]string{
 	// Issue fields
-	"id":          "string",
-	"title":       "string",
-	"description": "string",
-	"status":      "enum",
-	"type":        "enum",
-	"priority":    "ordinal",
-	"points":      "number",
-	"labels":      "string",
-	"parent":      "string",
-	"epic":        "string",
-	"implementer": "string",
-	"reviewer":    "string",
-	"minor":       "bool",
-	"branch":      "string",
-	"sprint":      "string",
-	"created":     "date",
-	"updated":     "date",
-	"closed":      "date",
+	"id":             "string",
+	"title":          "string",
+	"description":    "string",
+	"status":         "enum",
+	"type":           "enum",
+	"priority":       "ordinal",
+	"points":         "number",
+	"labels":         "string",
+	"parent":         "string",
+	"epic":           "string",
+	"implementer":    "string",
+	"reviewer":       "string",
+	"minor":          "bool",
+	"inbox":          "bool",
+	"confidential":   "bool",
+	"rank":           "number",
+	"branch":         "string",
+	"repo":           "string",
+	"sprint":         "string",
+	"project":        "string",
+	"blocked_reason": "enum",
+	"blocked_ref":    "string",
+	"created":        "date",
+	"updated":        "date",
+	"closed":         "date",
 
 	// Cross-entity prefixes (validated separately)
 	"log":     "prefix",
@@ -209,13 +236,19 @@ var CrossEntityFields = map[string]map[string]string{
 
 // Enum values for validation
 var EnumValues = map[string][]string{
-	"status":   {"open", "in_progress", "blocked", "in_review", "closed"},
-	"type":     {"bug", "feature", "task", "epic", "chore"},
-	"priority": {"P0", "P1", "P2", "P3", "P4"},
-	"log.type": {"progress", "blocker", "decision", "hypothesis", "tried", "result", "orchestration"},
-	"file.role": {"implementation", "test", "reference", "config"},
+	"status":         {"open", "in_progress", "blocked", "in_review", "closed"},
+	"type":           {"bug", "feature", "task", "epic", "chore"},
+	"priority":       {"P0", "P1", "P2", "P3", "P4"},
+	"blocked_reason": {"dependency", "external", "decision"},
+	"log.type":       {"progress", "blocker", "decision", "hypothesis", "tried", "result", "orchestration"},
+	"file.role":      {"implementation", "test", "reference", "config"},
 }
 
+// DeletedState is the is() argument matching soft-deleted issues. It is not
+// a status: deleted issues keep their status, and are only searched when a
+// query asks for them.
+const DeletedState = "deleted"
+
 // Known functions
 var KnownFunctions = map[string]struct {
 	MinArgs int
@@ -223,7 +256,7 @@ var KnownFunctions = map[string]struct {
 	Help    string
 }{
 	"has":           {1, 1, "has(field) - field is not empty"},
-	"is":            {1, 1, "is(status) - shorthand for status check"},
+	"is":            {1, 1, "is(status) - shorthand for status check; is(deleted) matches soft-deleted issues"},
 	"any":           {2, -1, "any(field, v1, v2, ...) - field matches any value"},
 	"all":           {2, -1, "all(field, v1, v2, ...) - field matches all values"},
 	"none":          {2, -1, "none(field, v1, v2, ...) - field matches none"},
@@ -232,6 +265,7 @@ var KnownFunctions = map[string]struct {
 	"child_of":      {1, 1, "child_of(id) - direct children of issue"},
 	"descendant_of": {1, 1, "descendant_of(id) - all descendants (recursive)"},
 	"linked_to":     {1, 1, "linked_to(path) - issues linked to file path"},
+	"references":    {1, 1, "references(project/id) - issues referencing an issue in a linked project"},
 	"rework":        {0, 0, "rework() - issues rejected and awaiting rework"},
 	"is_ready":      {0, 0, "is_ready() - issues with no open dependencies"},
 	"has_open_deps": {0, 0, "has_open_deps() - issues with open dependencies"},
@@ -264,6 +298,9 @@ var SortFieldToColumn = map[string]string{
 	"status":   "status",
 	"points":   "points",
 	"sprint":   "sprint",
+	"type":     "type",
+	"score":    "score", // computed; sorted in memory (see internal/score)
+	"rank":     "rank",  // ranked issues first (see td rank)
 }
 
 // NoteSortFieldToColumn maps user-facing sort field names to DB columns for notes
@@ -292,3 +329,32 @@ func (q *Query) String() string {
 	}
 	return strings.Join(parts, " ")
 }
+
+// ReferencesField reports whether the query constrains field, either as a
+// comparison or through a function such as is(), has() or any().
+func (q *Query) ReferencesField(field string) bool {
+	return q != nil && nodeReferencesField(q.Root, field)
+}
+
+func nodeReferencesField(n Node, field string) bool {
+	switch node := n.(type) {
+	case *BinaryExpr:
+		return nodeReferencesField(node.Left, field) || nodeReferencesField(node.Right, field)
+	case *UnaryExpr:
+		return nodeReferencesField(node.Expr, field)
+	case *FieldExpr:
+		return node.Field == field
+	case *FunctionCall:
+		if node.Name == "is" {
+			if len(node.Args) > 0 && fmt.Sprintf("%v", node.Args[0]) == DeletedState {
+				return field == "status" || field == "deleted"
+			}
+			return field == "status"
+		}
+		switch node.Name {
+		case "has", "any", "all", "none":
+			return len(node.Args) > 0 && fmt.Sprintf("%v", node.Args[0]) == field
+		}
+	}
+	return false
+}
@@ -7,20 +7,23 @@ import (
 	"strings"
 	"time"
 
+	"github.com/marcus/td/internal/dateparse"
 	"github.com/marcus/td/internal/models"
 )
 
 // EvalContext provides context for query evaluation
 type EvalContext struct {
-	CurrentSession string    // for @me resolution
-	Now            time.Time // for relative date calculation
+	CurrentSession string      // for @me resolution
+	Now            time.Time   // for relative date calculation, in the project's timezone
+	Source         QuerySource // set during Execute, for registered functions
+	Project        string      // name of the project being searched, for the project field
 }
 
 // NewEvalContext creates a new evaluation context
 func NewEvalContext(sessionID string) *EvalContext {
 	return &EvalContext{
 		CurrentSession: sessionID,
-		Now:            time.Now(),
+		Now:            dateparse.Now(),
 	}
 }
 
@@ -131,7 +134,7 @@ func (e *Evaluator) hasCrossEntity(n Node) bool {
 		}
 		return false
 	case *FunctionCall:
-		return node.Name == "blocks" || node.Name == "blocked_by" || node.Name == "linked_to" || node.Name == "descendant_of" || node.Name == "rework" || node.Name == "is_ready" || node.Name == "has_open_deps"
+		return node.Name == "blocks" || node.Name == "blocked_by" || node.Name == "linked_to" || node.Name == "descendant_of" || node.Name == "rework" || node.Name == "is_ready" || node.Name == "has_open_deps" || node.Name == "references"
 	default:
 		return false
 	}
@@ -153,7 +156,7 @@ func (e *Evaluator) isCrossEntityNode(n Node) bool {
 		}
 		return false
 	case *FunctionCall:
-		return node.Name == "blocks" || node.Name == "blocked_by" || node.Name == "linked_to" || node.Name == "descendant_of" || node.Name == "rework" || node.Name == "is_ready" || node.Name == "has_open_deps"
+		return node.Name == "blocks" || node.Name == "blocked_by" || node.Name == "linked_to" || node.Name == "descendant_of" || node.Name == "rework" || node.Name == "is_ready" || node.Name == "has_open_deps" || node.Name == "references"
 	default:
 		return false
 	}
@@ -304,6 +307,9 @@ func (e *Evaluator) functionToSQL(node *FunctionCall) ([]SQLCondition, error) {
 			return nil, fmt.Errorf("is() requires 1 argument")
 		}
 		status := fmt.Sprintf("%v", node.Args[0])
+		if strings.EqualFold(status, DeletedState) {
+			return []SQLCondition{{Clause: "deleted_at IS NOT NULL"}}, nil
+		}
 		// Normalize to canonical enum form for case-insensitive matching
 		if enumVals, ok := EnumValues["status"]; ok {
 			for _, v := range enumVals {
@@ -342,7 +348,7 @@ func (e *Evaluator) functionToSQL(node *FunctionCall) ([]SQLCondition, error) {
 		// This requires recursive query, return nil and handle in memory
 		return nil, nil
 
-	case "blocks", "blocked_by", "linked_to":
+	case "blocks", "blocked_by", "linked_to", "references":
 		// These require joins, handle in memory
 		return nil, nil
 
@@ -356,6 +362,9 @@ func (e *Evaluator) functionToSQL(node *FunctionCall) ([]SQLCondition, error) {
 			Args: []interface{}{label + ",%", "%," + label + ",%", "%," + label, label}}}, nil
 
 	default:
+		if _, ok := lookupFunction(node.Name); ok {
+			return nil, nil // registered functions are evaluated in memory
+		}
 		return nil, fmt.Errorf("unknown function: %s", node.Name)
 	}
 }
@@ -407,6 +416,8 @@ func (e *Evaluator) mapFieldToColumn(field string) string {
 		return "reviewer_session"
 	case "branch":
 		return "created_branch"
+	case "repo":
+		return "created_repo"
 	default:
 		return field
 	}
@@ -619,10 +630,24 @@ func (e *Evaluator) getFieldGetter(field string) func(models.Issue) interface{}
 		return func(i models.Issue) interface{} { return i.ReviewerSession }
 	case "branch", "created_branch":
 		return func(i models.Issue) interface{} { return i.CreatedBranch }
+	case "repo", "created_repo":
+		return func(i models.Issue) interface{} { return i.CreatedRepo }
 	case "sprint":
 		return func(i models.Issue) interface{} { return i.Sprint }
+	case "project":
+		return func(models.Issue) interface{} { return e.ctx.Project }
+	case "blocked_reason":
+		return func(i models.Issue) interface{} { return string(i.BlockedReason) }
+	case "blocked_ref":
+		return func(i models.Issue) interface{} { return i.BlockedRef }
 	case "minor":
 		return func(i models.Issue) interface{} { return i.Minor }
+	case "inbox":
+		return func(i models.Issue) interface{} { return i.Inbox }
+	case "confidential":
+		return func(i models.Issue) interface{} { return i.Confidential }
+	case "rank":
+		return func(i models.Issue) interface{} { return i.Rank }
 	case "created", "created_at":
 		return func(i models.Issue) interface{} { return i.CreatedAt }
 	case "updated", "updated_at":
@@ -648,10 +673,25 @@ func (e *Evaluator) compareEqual(a, b interface{}) bool {
 			return a == nil
 		}
 	}
+	if t, ok := a.(time.Time); ok {
+		if s, ok := b.(string); ok {
+			return compareDate(t, s, OpEq, e.ctx.Now.Location())
+		}
+	}
 	return fmt.Sprintf("%v", a) == fmt.Sprintf("%v", b)
 }
 
 func (e *Evaluator) compareOrder(a, b interface{}, op string) bool {
+	// Dates compare against the resolved date string, not as numbers
+	if t, ok := a.(time.Time); ok {
+		if s, ok := b.(string); ok {
+			return compareDate(t, s, op, e.ctx.Now.Location())
+		}
+	}
+	if a == nil {
+		return false
+	}
+
 	// Handle priority comparison specially
 	if priorityA, okA := a.(string); okA {
 		if priorityB, okB := b.(string); okB {
@@ -706,6 +746,9 @@ func (e *Evaluator) functionToMatcher(node *FunctionCall) (func(models.Issue) bo
 			return nil, fmt.Errorf("is() requires 1 argument")
 		}
 		status := fmt.Sprintf("%v", node.Args[0])
+		if strings.EqualFold(status, DeletedState) {
+			return func(i models.Issue) bool { return i.DeletedAt != nil }, nil
+		}
 		return func(i models.Issue) bool {
 			return strings.EqualFold(string(i.Status), status)
 		}, nil
@@ -793,7 +836,7 @@ func (e *Evaluator) functionToMatcher(node *FunctionCall) (func(models.Issue) bo
 		// Return placeholder that allows issue through (will be filtered in Execute)
 		return func(models.Issue) bool { return true }, nil
 
-	case "blocks", "blocked_by", "linked_to", "rework", "is_ready", "has_open_deps":
+	case "blocks", "blocked_by", "linked_to", "references", "rework", "is_ready", "has_open_deps":
 		// These require database lookups, handled via cross-entity filter
 		return func(models.Issue) bool { return true }, nil
 
@@ -812,6 +855,9 @@ func (e *Evaluator) functionToMatcher(node *FunctionCall) (func(models.Issue) bo
 		}, nil
 
 	default:
+		if fn, ok := lookupFunction(node.Name); ok && fn.Matcher != nil {
+			return fn.Matcher(e.ctx, node.Args)
+		}
 		return nil, fmt.Errorf("unknown function: %s", node.Name)
 	}
 }
@@ -850,6 +896,38 @@ func comparePriority(a, b, op string) bool {
 	}
 }
 
+// compareDate compares a timestamp with a date ("2006-01-02") at day
+// granularity, so "created <= 2024-01-15" includes that whole day, or with
+// a datetime ("2006-01-02 15:04:05") exactly. Days and datetimes are read
+// in loc, the project's timezone.
+func compareDate(t time.Time, s, op string, loc *time.Location) bool {
+	var cmp int
+	if len(s) == len("2006-01-02") {
+		cmp = strings.Compare(t.In(loc).Format("2006-01-02"), s)
+	} else {
+		bound, err := time.ParseInLocation("2006-01-02 15:04:05", s, loc)
+		if err != nil {
+			return false
+		}
+		cmp = t.Compare(bound)
+	}
+
+	switch op {
+	case OpEq:
+		return cmp == 0
+	case OpLt:
+		return cmp < 0
+	case OpGt:
+		return cmp > 0
+	case OpLte:
+		return cmp <= 0
+	case OpGte:
+		return cmp >= 0
+	default:
+		return false
+	}
+}
+
 func toNumber(v interface{}) float64 {
 	switch val := v.(type) {
 	case int:
@@ -162,6 +162,24 @@ func TestToMatcher(t *testing.T) {
 			},
 			matches: true,
 		},
+		{
+			name:  "repo equals",
+			query: `repo = "github.com/acme/api"`,
+			issue: models.Issue{
+				ID:          "td-017",
+				CreatedRepo: "github.com/acme/api",
+			},
+			matches: true,
+		},
+		{
+			name:  "repo contains",
+			query: `repo ~ "acme/web"`,
+			issue: models.Issue{
+				ID:          "td-018",
+				CreatedRepo: "github.com/acme/api",
+			},
+			matches: false,
+		},
 		{
 			name:  "empty query matches all",
 			query: "",
@@ -840,3 +858,78 @@ func TestComparePriorityCaseInsensitive(t *testing.T) {
 		})
 	}
 }
+
+func TestToMatcherDates(t *testing.T) {
+	now := time.Now()
+	issue := models.Issue{ID: "td-001", CreatedAt: now}
+	closedLastMonth := now.AddDate(0, -1, 0)
+	closed := models.Issue{ID: "td-002", CreatedAt: closedLastMonth, ClosedAt: &closedLastMonth}
+
+	tests := []struct {
+		query   string
+		issue   models.Issue
+		matches bool
+	}{
+		{"created >= today", issue, true},
+		{"created <= today", issue, true},
+		{"created = today", issue, true},
+		{"created > today", issue, false},
+		{"created >= 2099-01-01", issue, false},
+		{"created < 2000-01-01", issue, false},
+		{"created >= -7d", closed, false},
+		{"closed <= -7d", closed, true},
+		{"closed < today", issue, false}, // never closed
+	}
+
+	ctx := NewEvalContext("ses_test")
+	for _, tt := range tests {
+		q, err := Parse(tt.query)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.query, err)
+		}
+		matcher, err := NewEvaluator(ctx, q).ToMatcher()
+		if err != nil {
+			t.Fatalf("ToMatcher(%q): %v", tt.query, err)
+		}
+		if got := matcher(tt.issue); got != tt.matches {
+			t.Errorf("%q on %s = %v, want %v", tt.query, tt.issue.ID, got, tt.matches)
+		}
+	}
+}
+
+func TestToMatcherDatesInProjectTimezone(t *testing.T) {
+	// 23:30 on the 14th in New York is already the 15th in UTC
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no tzdata:", err)
+	}
+	now := time.Date(2026, 3, 14, 23, 30, 0, 0, ny)
+	issue := models.Issue{ID: "td-001", CreatedAt: now.UTC()}
+
+	tests := []struct {
+		query   string
+		loc     *time.Location
+		matches bool
+	}{
+		{"created = today", ny, true},
+		{"created = 2026-03-14", ny, true},
+		{"created = 2026-03-14", time.UTC, false},
+		{"created = 2026-03-15", time.UTC, true},
+		{"created >= this_week", ny, true},
+	}
+
+	for _, tt := range tests {
+		q, err := Parse(tt.query)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.query, err)
+		}
+		ctx := &EvalContext{Now: now.In(tt.loc)}
+		matcher, err := NewEvaluator(ctx, q).ToMatcher()
+		if err != nil {
+			t.Fatalf("ToMatcher(%q): %v", tt.query, err)
+		}
+		if got := matcher(issue); got != tt.matches {
+			t.Errorf("%q in %s = %v, want %v", tt.query, tt.loc, got, tt.matches)
+		}
+	}
+}
@@ -6,6 +6,8 @@ import (
 
 	"github.com/marcus/td/internal/db"
 	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/score"
+	"github.com/marcus/td/internal/xref"
 )
 
 const (
@@ -21,6 +23,14 @@ type ExecuteOptions struct {
 	SortBy     string
 	SortDesc   bool
 	MaxResults int // Max issues to process in-memory (0 = DefaultMaxResults)
+	// Score ranks issues when sorting by "score" (nil = score.Current())
+	Score *score.Formula
+	// Project names the project being searched, matched by the project
+	// field; queries run without one see an empty project
+	Project string
+	// WithDeleted searches soft-deleted issues too. Queries that use
+	// is(deleted) or the deleted field include them regardless.
+	WithDeleted bool
 }
 
 // Execute parses and executes a TDQ query
@@ -36,6 +46,12 @@ func Execute(database QuerySource, queryStr string, sessionID string, opts Execu
 		return nil, fmt.Errorf("validation error: %v", errs[0])
 	}
 
+	return ExecuteQuery(database, query, sessionID, opts)
+}
+
+// ExecuteQuery runs an already parsed and validated query. Callers that
+// build or combine ASTs (e.g. from HTTP filter params) use it directly.
+func ExecuteQuery(database QuerySource, query *Query, sessionID string, opts ExecuteOptions) ([]models.Issue, error) {
 	// Set memory limits
 	maxResults := opts.MaxResults
 	if maxResults <= 0 {
@@ -50,44 +66,42 @@ func Execute(database QuerySource, queryStr string, sessionID string, opts Execu
 		sortDesc = query.Sort.Descending
 	}
 
+	// score is computed, not stored, so it is sorted after filtering
+	byScore, scoreDesc := sortBy == "score", sortDesc
+	if byScore {
+		sortBy, sortDesc = "", false
+	}
+
 	// Create evaluation context
 	ctx := NewEvalContext(sessionID)
+	ctx.Source = database
+	ctx.Project = opts.Project
 	evaluator := NewEvaluator(ctx, query)
 
-	// Check if we need cross-entity queries
-	hasCrossEntity := evaluator.HasCrossEntityConditions()
-
 	// Fetch issues with a limit to prevent OOM
 	// We fetch more than maxResults to allow for filtering, but cap it
 	fetchOpts := db.ListIssuesOptions{
-		SortBy:   sortBy,
-		SortDesc: sortDesc,
-		Limit:    maxResults, // Cap fetch to prevent loading entire DB
+		SortBy:         sortBy,
+		SortDesc:       sortDesc,
+		Limit:          maxResults, // Cap fetch to prevent loading entire DB
+		IncludeDeleted: opts.WithDeleted || query.ReferencesField("deleted"),
 	}
 	issues, err := database.ListIssues(fetchOpts)
 	if err != nil {
 		return nil, fmt.Errorf("database error: %w", err)
 	}
 
-	var filtered []models.Issue
-	if hasCrossEntity {
-		// When cross-entity conditions exist, use the AST-walking evaluator
-		// which handles both cross-entity and regular fields with correct boolean logic
-		filtered, err = applyCrossEntityFilters(database, issues, query, ctx)
-		if err != nil {
-			return nil, fmt.Errorf("cross-entity filter error: %w", err)
-		}
-	} else {
-		// Pure regular-field queries: use in-memory matcher (faster, no DB lookups)
-		matcher, err := evaluator.ToMatcher()
-		if err != nil {
-			return nil, fmt.Errorf("matcher error: %w", err)
-		}
-		for _, issue := range issues {
-			if matcher(issue) {
-				filtered = append(filtered, issue)
-			}
+	filtered, err := filterIssues(database, evaluator, issues)
+	if err != nil {
+		return nil, err
+	}
+
+	if byScore {
+		formula := opts.Score
+		if formula == nil {
+			formula = score.Current()
 		}
+		formula.Sort(filtered, ctx.Now, !scoreDesc)
 	}
 
 	// Apply limit after filtering
@@ -98,6 +112,42 @@ func Execute(database QuerySource, queryStr string, sessionID string, opts Execu
 	return filtered, nil
 }
 
+// Match returns the given issues that a parsed and validated query matches,
+// without fetching any. Callers that already hold the issues they care
+// about, like the event stream checking the issues a write touched, use it
+// instead of running the query over the whole project.
+func Match(database QuerySource, query *Query, sessionID string, issues []models.Issue) ([]models.Issue, error) {
+	ctx := NewEvalContext(sessionID)
+	ctx.Source = database
+	return filterIssues(database, NewEvaluator(ctx, query), issues)
+}
+
+// filterIssues keeps the issues the evaluator's query matches
+func filterIssues(database QuerySource, evaluator *Evaluator, issues []models.Issue) ([]models.Issue, error) {
+	if evaluator.HasCrossEntityConditions() {
+		// When cross-entity conditions exist, use the AST-walking evaluator
+		// which handles both cross-entity and regular fields with correct boolean logic
+		filtered, err := applyCrossEntityFilters(database, issues, evaluator.query, evaluator.ctx)
+		if err != nil {
+			return nil, fmt.Errorf("cross-entity filter error: %w", err)
+		}
+		return filtered, nil
+	}
+
+	// Pure regular-field queries: use in-memory matcher (faster, no DB lookups)
+	matcher, err := evaluator.ToMatcher()
+	if err != nil {
+		return nil, fmt.Errorf("matcher error: %w", err)
+	}
+	var filtered []models.Issue
+	for _, issue := range issues {
+		if matcher(issue) {
+			filtered = append(filtered, issue)
+		}
+	}
+	return filtered, nil
+}
+
 func applyCrossEntityFilters(database QuerySource, issues []models.Issue, query *Query, ctx *EvalContext) ([]models.Issue, error) {
 	if query.Root == nil {
 		return issues, nil
@@ -273,7 +323,7 @@ func fieldExprToFilter(node *FieldExpr, negated bool) *crossEntityFilter {
 // functionCallToFilter converts a FunctionCall to a crossEntityFilter if it's a cross-entity function.
 // Returns nil for non-cross-entity functions.
 func functionCallToFilter(node *FunctionCall, negated bool) *crossEntityFilter {
-	if node.Name == "blocks" || node.Name == "blocked_by" || node.Name == "linked_to" || node.Name == "descendant_of" || node.Name == "rework" || node.Name == "is_ready" || node.Name == "has_open_deps" {
+	if node.Name == "blocks" || node.Name == "blocked_by" || node.Name == "linked_to" || node.Name == "descendant_of" || node.Name == "rework" || node.Name == "is_ready" || node.Name == "has_open_deps" || node.Name == "references" {
 		return &crossEntityFilter{
 			entity:   "function",
 			field:    node.Name,
@@ -562,6 +612,28 @@ func applyFunctionFilter(database QuerySource, issue models.Issue, filter crossE
 		}
 		return false, nil
 
+	case "references":
+		// Cross-project references: dependencies on, or mentions of,
+		// <project>/<issue-id>. A bare project name matches any reference
+		// into that project.
+		want, qualified := xref.Parse(targetID)
+		refs := xref.FindAll(issue.Description + "\n" + issue.Acceptance)
+		deps, err := database.GetDependencies(issue.ID)
+		if err != nil {
+			return false, err
+		}
+		for _, depID := range deps {
+			if ref, ok := xref.Parse(depID); ok {
+				refs = append(refs, ref)
+			}
+		}
+		for _, ref := range refs {
+			if (qualified && ref.Matches(want)) || (!qualified && ref.Project == targetID) {
+				return true, nil
+			}
+		}
+		return false, nil
+
 	case "linked_to":
 		// Check if this issue is linked to the file
 		files, err := database.GetLinkedFiles(issue.ID)
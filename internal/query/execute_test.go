@@ -2,10 +2,12 @@ package query
 
 import (
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/marcus/td/internal/db"
 	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/score"
 )
 
 func setupTestDB(t *testing.T) *db.DB {
@@ -133,6 +135,69 @@ func TestExecuteWithLimit(t *testing.T) {
 	}
 }
 
+func TestExecuteProjectField(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	createTestIssue(t, database, "td-proj1", "Issue", models.StatusOpen, models.TypeTask, models.PriorityP2)
+
+	tests := []struct {
+		query   string
+		project string
+		want    int
+	}{
+		{`project = "api"`, "api", 1},
+		{`project = "api"`, "web", 0},
+		{`project != "api" AND is(open)`, "web", 1},
+		{`project = "api"`, "", 0},
+	}
+	for _, tt := range tests {
+		results, err := Execute(database, tt.query, "ses_test", ExecuteOptions{Project: tt.project})
+		if err != nil {
+			t.Fatalf("Execute(%q) error = %v", tt.query, err)
+		}
+		if len(results) != tt.want {
+			t.Errorf("Execute(%q) in project %q returned %d results, want %d", tt.query, tt.project, len(results), tt.want)
+		}
+	}
+}
+
+func TestExecuteDeleted(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	createTestIssue(t, database, "", "Live", models.StatusOpen, models.TypeTask, models.PriorityP2)
+	gone := createTestIssue(t, database, "", "Gone", models.StatusOpen, models.TypeTask, models.PriorityP2)
+	if err := database.DeleteIssue(gone.ID); err != nil {
+		t.Fatalf("DeleteIssue: %v", err)
+	}
+
+	tests := []struct {
+		query       string
+		withDeleted bool
+		want        []string
+	}{
+		{"is(open)", false, []string{"Live"}},
+		{"is(open)", true, []string{"Gone", "Live"}},
+		{"is(deleted)", false, []string{"Gone"}},
+		{"is(Deleted) AND is(open)", false, []string{"Gone"}},
+		{"NOT is(deleted)", false, []string{"Live"}},
+	}
+	for _, tt := range tests {
+		results, err := Execute(database, tt.query, "ses_test", ExecuteOptions{WithDeleted: tt.withDeleted, SortBy: "title"})
+		if err != nil {
+			t.Fatalf("Execute(%q) error = %v", tt.query, err)
+		}
+		var got []string
+		for _, issue := range results {
+			got = append(got, issue.Title)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("Execute(%q, withDeleted=%v) = %v, want %v", tt.query, tt.withDeleted, got, tt.want)
+		}
+	}
+}
+
 func TestExecuteWithMaxResults(t *testing.T) {
 	database := setupTestDB(t)
 	defer database.Close()
@@ -1480,3 +1545,79 @@ func TestMain(m *testing.M) {
 	code := m.Run()
 	os.Exit(code)
 }
+
+func TestExecuteSortByScore(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	for _, tc := range []struct {
+		title  string
+		points int
+	}{{"small", 1}, {"large", 8}, {"medium", 3}} {
+		issue := createTestIssue(t, database, "", tc.title, models.StatusOpen, models.TypeTask, models.PriorityP2)
+		issue.Points = tc.points
+		if err := database.UpdateIssue(issue); err != nil {
+			t.Fatalf("UpdateIssue: %v", err)
+		}
+	}
+
+	opts := ExecuteOptions{Score: score.MustParse("points * 2")}
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"status = open sort:-score", []string{"large", "medium", "small"}},
+		{"status = open sort:score", []string{"small", "medium", "large"}},
+	}
+	for _, tt := range tests {
+		results, err := Execute(database, tt.query, "ses_test", opts)
+		if err != nil {
+			t.Fatalf("Execute(%q) error = %v", tt.query, err)
+		}
+		var got []string
+		for _, r := range results {
+			got = append(got, r.Title)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("Execute(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestExecuteReferences(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	mentions := createTestIssue(t, database, "", "Mentions", models.StatusOpen, models.TypeTask, models.PriorityP1)
+	depends := createTestIssue(t, database, "", "Depends", models.StatusOpen, models.TypeTask, models.PriorityP1)
+	createTestIssue(t, database, "", "Unrelated", models.StatusOpen, models.TypeTask, models.PriorityP2)
+
+	mentions.Description = "Needs the new endpoint from api/td-a1b2c3"
+	if err := database.UpdateIssue(mentions); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.AddDependency(depends.ID, "web/td-ffee01", "depends_on"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query string
+		want  map[string]bool
+	}{
+		{`references("api/td-a1b2c3")`, map[string]bool{mentions.ID: true}},
+		{`references("api/a1b2c3")`, map[string]bool{mentions.ID: true}},
+		{`references("web/td-ffee01")`, map[string]bool{depends.ID: true}},
+		{`references(web)`, map[string]bool{depends.ID: true}},
+		{`references(api) OR references(web)`, map[string]bool{mentions.ID: true, depends.ID: true}},
+		{`references("api/td-ffee01")`, map[string]bool{}},
+	}
+	for _, tt := range tests {
+		results, err := Execute(database, tt.query, "ses_test", ExecuteOptions{})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.query, err)
+		}
+		if got := idSet(results); !equalSets(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
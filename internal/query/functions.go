@@ -0,0 +1,199 @@
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/marcus/td/internal/models"
+)
+
+// ============================================================================
+// Function Registry
+// ============================================================================
+
+// Function describes a TDQ function. Built-ins are listed in KnownFunctions
+// and evaluated by the engine itself; functions added with RegisterFunction
+// carry their own Matcher.
+type Function struct {
+	Name    string   `json:"name"`
+	Args    []string `json:"args"` // argument names, for signatures and autocomplete
+	MinArgs int      `json:"min_args"`
+	MaxArgs int      `json:"max_args"` // -1 for no limit
+	Help    string   `json:"help"`
+	Builtin bool     `json:"builtin"`
+
+	// Matcher builds the predicate for one call. Args are passed as parsed:
+	// strings, ints, *DateValue or *SpecialValue. Validation also calls it to
+	// check arguments, so it should only inspect args and return errors;
+	// lookups belong in the returned predicate. ctx.Source is set while a
+	// query executes, for predicates that need related data.
+	Matcher func(ctx *EvalContext, args []interface{}) (func(models.Issue) bool, error) `json:"-"`
+}
+
+// Signature returns the function as it is written in a query, e.g. "stale(age)"
+func (f Function) Signature() string {
+	args := strings.Join(f.Args, ", ")
+	if f.MaxArgs < 0 {
+		args += ", ..."
+	}
+	return f.Name + "(" + args + ")"
+}
+
+var (
+	functionsMu     sync.RWMutex
+	customFunctions = map[string]Function{}
+
+	functionNameRe = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+	reservedNames  = map[string]bool{"and": true, "or": true, "not": true, "in": true, "sort": true}
+)
+
+// RegisterFunction adds a custom TDQ function, usable in every query from
+// then on. The name must be a lowercase identifier not already taken by a
+// built-in or an earlier registration.
+func RegisterFunction(fn Function) error {
+	if !functionNameRe.MatchString(fn.Name) || reservedNames[fn.Name] {
+		return fmt.Errorf("invalid function name %q", fn.Name)
+	}
+	if fn.Matcher == nil {
+		return fmt.Errorf("function %s: Matcher is required", fn.Name)
+	}
+	if fn.MaxArgs >= 0 && fn.MaxArgs < fn.MinArgs {
+		return fmt.Errorf("function %s: max args %d below min args %d", fn.Name, fn.MaxArgs, fn.MinArgs)
+	}
+
+	functionsMu.Lock()
+	defer functionsMu.Unlock()
+	if _, ok := KnownFunctions[fn.Name]; ok {
+		return fmt.Errorf("function %s is built in", fn.Name)
+	}
+	if _, ok := customFunctions[fn.Name]; ok {
+		return fmt.Errorf("function %s is already registered", fn.Name)
+	}
+	fn.Builtin = false
+	customFunctions[fn.Name] = fn
+	return nil
+}
+
+// MustRegisterFunction is RegisterFunction for use in init; it panics on error.
+func MustRegisterFunction(fn Function) {
+	if err := RegisterFunction(fn); err != nil {
+		panic(err)
+	}
+}
+
+// Functions returns every function a query may call, sorted by name
+func Functions() []Function {
+	functionsMu.RLock()
+	defer functionsMu.RUnlock()
+
+	fns := make([]Function, 0, len(KnownFunctions)+len(customFunctions))
+	for name, spec := range KnownFunctions {
+		fns = append(fns, Function{
+			Name:    name,
+			Args:    builtinArgNames(spec.Help),
+			MinArgs: spec.MinArgs,
+			MaxArgs: spec.MaxArgs,
+			Help:    spec.Help,
+			Builtin: true,
+		})
+	}
+	for _, fn := range customFunctions {
+		fns = append(fns, fn)
+	}
+	sort.Slice(fns, func(i, j int) bool { return fns[i].Name < fns[j].Name })
+	return fns
+}
+
+// lookupFunction returns the metadata for a built-in or registered function
+func lookupFunction(name string) (Function, bool) {
+	if spec, ok := KnownFunctions[name]; ok {
+		return Function{Name: name, MinArgs: spec.MinArgs, MaxArgs: spec.MaxArgs, Help: spec.Help, Builtin: true}, true
+	}
+	functionsMu.RLock()
+	defer functionsMu.RUnlock()
+	fn, ok := customFunctions[name]
+	return fn, ok
+}
+
+// builtinArgNames reads argument names from a KnownFunctions help string,
+// e.g. "any(field, v1, v2, ...) - ..." gives [field v1 v2].
+func builtinArgNames(help string) []string {
+	lp, rp := strings.Index(help, "("), strings.Index(help, ")")
+	if lp < 0 || rp < lp {
+		return nil
+	}
+	var names []string
+	for _, a := range strings.Split(help[lp+1:rp], ",") {
+		if a = strings.TrimSpace(a); a != "" && a != "..." {
+			names = append(names, a)
+		}
+	}
+	return names
+}
+
+// ============================================================================
+// Registered Functions
+// ============================================================================
+
+func init() {
+	MustRegisterFunction(Function{
+		Name:    "stale",
+		Args:    []string{"age"},
+		MinArgs: 1,
+		MaxArgs: 1,
+		Help:    "stale(age) - open issues not updated within age (e.g. 14d, 2w, 1m)",
+		Matcher: func(ctx *EvalContext, args []interface{}) (func(models.Issue) bool, error) {
+			cutoff, err := ageCutoff(ctx.Now, args[0])
+			if err != nil {
+				return nil, fmt.Errorf("stale(): %w", err)
+			}
+			return func(i models.Issue) bool {
+				return i.Status != models.StatusClosed && i.UpdatedAt.Before(cutoff)
+			}, nil
+		},
+	})
+
+	MustRegisterFunction(Function{
+		Name: "mine",
+		Help: "mine() - issues you created or are implementing",
+		Matcher: func(ctx *EvalContext, args []interface{}) (func(models.Issue) bool, error) {
+			me := ctx.CurrentSession
+			return func(i models.Issue) bool {
+				return me != "" && (i.ImplementerSession == me || i.CreatorSession == me)
+			}, nil
+		},
+	})
+}
+
+// ageCutoff turns an age argument (14d, 2w, 1m, 12h, or a bare number of
+// days) into the time that far before now.
+func ageCutoff(now time.Time, arg interface{}) (time.Time, error) {
+	raw := fmt.Sprintf("%v", arg)
+	if d, ok := arg.(*DateValue); ok {
+		raw = d.Raw
+	}
+	raw = strings.TrimPrefix(raw, "-")
+	if n, err := strconv.Atoi(raw); err == nil {
+		return now.AddDate(0, 0, -n), nil
+	}
+	if len(raw) >= 2 {
+		if n, err := strconv.Atoi(raw[:len(raw)-1]); err == nil {
+			switch raw[len(raw)-1] {
+			case 'd':
+				return now.AddDate(0, 0, -n), nil
+			case 'w':
+				return now.AddDate(0, 0, -7*n), nil
+			case 'm':
+				return now.AddDate(0, -n, 0), nil
+			case 'h':
+				return now.Add(-time.Duration(n) * time.Hour), nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid age %q (expected e.g. 14d, 2w, 1m)", raw)
+}
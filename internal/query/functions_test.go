@@ -0,0 +1,111 @@
+package query
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestRegisterFunction(t *testing.T) {
+	matcher := func(ctx *EvalContext, args []interface{}) (func(models.Issue) bool, error) {
+		want := strings.ToLower(args[0].(string))
+		return func(i models.Issue) bool { return strings.HasPrefix(strings.ToLower(i.Title), want) }, nil
+	}
+	if err := RegisterFunction(Function{Name: "test_title_prefix", Args: []string{"prefix"}, MinArgs: 1, MaxArgs: 1, Matcher: matcher}); err != nil {
+		t.Fatalf("RegisterFunction: %v", err)
+	}
+	t.Cleanup(func() {
+		functionsMu.Lock()
+		delete(customFunctions, "test_title_prefix")
+		functionsMu.Unlock()
+	})
+
+	q, err := Parse(`test_title_prefix("fix") AND status = open`)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if errs := q.Validate(); len(errs) > 0 {
+		t.Fatalf("Validate: %v", errs)
+	}
+	match, err := NewEvaluator(NewEvalContext("ses_a"), q).ToMatcher()
+	if err != nil {
+		t.Fatalf("ToMatcher: %v", err)
+	}
+	if !match(models.Issue{Title: "Fix login", Status: models.StatusOpen}) || match(models.Issue{Title: "Add login", Status: models.StatusOpen}) {
+		t.Error("registered function not applied")
+	}
+
+	for _, bad := range []Function{
+		{Name: "test_title_prefix", Matcher: matcher}, // duplicate
+		{Name: "has", Matcher: matcher},               // built-in
+		{Name: "Bad-Name", Matcher: matcher},
+		{Name: "sort", Matcher: matcher},
+		{Name: "no_matcher"},
+	} {
+		if err := RegisterFunction(bad); err == nil {
+			t.Errorf("RegisterFunction(%q) should fail", bad.Name)
+		}
+	}
+}
+
+func TestFunctions_Metadata(t *testing.T) {
+	byName := map[string]Function{}
+	for _, fn := range Functions() {
+		byName[fn.Name] = fn
+	}
+	if fn := byName["any"]; !fn.Builtin || fn.Signature() != "any(field, v1, v2, ...)" {
+		t.Errorf("any = %+v, signature %q", fn, fn.Signature())
+	}
+	if fn := byName["stale"]; fn.Builtin || fn.Signature() != "stale(age)" || fn.Help == "" {
+		t.Errorf("stale = %+v", fn)
+	}
+	if _, ok := byName["mine"]; !ok {
+		t.Error("mine() not listed")
+	}
+}
+
+func TestStaleAndMine(t *testing.T) {
+	ctx := NewEvalContext("ses_me")
+	old := ctx.Now.AddDate(0, 0, -20)
+	tests := []struct {
+		query   string
+		issue   models.Issue
+		matches bool
+	}{
+		{"stale(14d)", models.Issue{Status: models.StatusOpen, UpdatedAt: old}, true},
+		{"stale(30d)", models.Issue{Status: models.StatusOpen, UpdatedAt: old}, false},
+		{"stale(2w)", models.Issue{Status: models.StatusClosed, UpdatedAt: old}, false},
+		{"stale(14d)", models.Issue{Status: models.StatusOpen, UpdatedAt: time.Now()}, false},
+		{"mine()", models.Issue{CreatorSession: "ses_me"}, true},
+		{"mine()", models.Issue{ImplementerSession: "ses_me"}, true},
+		{"mine()", models.Issue{CreatorSession: "ses_other"}, false},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.query)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.query, err)
+		}
+		if errs := q.Validate(); len(errs) > 0 {
+			t.Fatalf("Validate(%q): %v", tt.query, errs)
+		}
+		match, err := NewEvaluator(ctx, q).ToMatcher()
+		if err != nil {
+			t.Fatalf("ToMatcher(%q): %v", tt.query, err)
+		}
+		if got := match(tt.issue); got != tt.matches {
+			t.Errorf("%s on %+v = %v, want %v", tt.query, tt.issue, got, tt.matches)
+		}
+	}
+
+	for _, bad := range []string{"stale()", "stale(soon)", "mine(x)"} {
+		q, err := Parse(bad)
+		if err != nil {
+			continue
+		}
+		if errs := q.Validate(); len(errs) == 0 {
+			t.Errorf("Validate(%q) should fail", bad)
+		}
+	}
+}
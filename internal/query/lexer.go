@@ -2,6 +2,7 @@ package query
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"unicode"
 )
@@ -549,22 +550,15 @@ func (l *Lexer) scanSortClause(startPos, startLine, startCol int) Token {
 		fieldName = field[1:]
 	}
 
-	validSortFields := map[string]bool{
-		"created":  true,
-		"updated":  true,
-		"closed":   true,
-		"deleted":  true,
-		"priority": true,
-		"id":       true,
-		"title":    true,
-		"status":   true,
-		"points":   true,
-	}
-
-	if !validSortFields[fieldName] {
+	if _, ok := SortFieldToColumn[fieldName]; !ok {
+		valid := make([]string, 0, len(SortFieldToColumn))
+		for name := range SortFieldToColumn {
+			valid = append(valid, name)
+		}
+		sort.Strings(valid)
 		return Token{
 			Type:   TokenError,
-			Value:  fmt.Sprintf("invalid sort field: %s (valid: created, updated, closed, deleted, priority, id, title, status, points)", fieldName),
+			Value:  fmt.Sprintf("invalid sort field: %s (valid: %s)", fieldName, strings.Join(valid, ", ")),
 			Pos:    startPos,
 			Line:   startLine,
 			Column: startCol,
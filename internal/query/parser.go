@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/marcus/td/internal/models"
 )
@@ -578,6 +579,25 @@ func isRelativeDate(s string) bool {
 	return false
 }
 
+// NewDateValue builds the DateValue a TDQ date literal would parse to:
+// an absolute date (2006-01-02), a keyword such as today, or an offset
+// such as -7d.
+func NewDateValue(s string) (*DateValue, error) {
+	if _, err := time.Parse("2006-01-02", s); err == nil {
+		return &DateValue{Raw: s}, nil
+	}
+	switch s {
+	case "today", "yesterday", "this_week", "last_week", "this_month", "last_month":
+		return &DateValue{Raw: s, Relative: true}, nil
+	}
+	if len(s) >= 2 && strings.ContainsRune("dwmh", rune(s[len(s)-1])) {
+		if _, err := strconv.Atoi(strings.TrimPrefix(s[:len(s)-1], "+")); err == nil {
+			return &DateValue{Raw: s, Relative: true}, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid date %q (expected YYYY-MM-DD, today, -7d, ...)", s)
+}
+
 // Validate checks the query AST for semantic errors
 func (q *Query) Validate() []error {
 	if q.Root == nil {
@@ -642,7 +662,7 @@ func validateFieldExpr(f *FieldExpr, errs *[]error) {
 }
 
 func validateFunctionCall(fn *FunctionCall, errs *[]error) {
-	spec, ok := KnownFunctions[fn.Name]
+	spec, ok := lookupFunction(fn.Name)
 	if !ok {
 		*errs = append(*errs, fmt.Errorf("unknown function: %s", fn.Name))
 		return
@@ -652,10 +672,20 @@ func validateFunctionCall(fn *FunctionCall, errs *[]error) {
 	if argc < spec.MinArgs {
 		*errs = append(*errs, fmt.Errorf("function %s requires at least %d argument(s), got %d",
 			fn.Name, spec.MinArgs, argc))
+		return
 	}
-	if spec.MaxArgs > 0 && argc > spec.MaxArgs {
+	if spec.MaxArgs >= 0 && argc > spec.MaxArgs {
 		*errs = append(*errs, fmt.Errorf("function %s accepts at most %d argument(s), got %d",
 			fn.Name, spec.MaxArgs, argc))
+		return
+	}
+
+	// Registered functions check their own arguments when building a matcher
+	if spec.Matcher != nil {
+		if _, err := spec.Matcher(NewEvalContext(""), fn.Args); err != nil {
+			*errs = append(*errs, err)
+		}
+		return
 	}
 
 	// Normalize enum values in function arguments.
@@ -665,7 +695,9 @@ func validateFunctionCall(fn *FunctionCall, errs *[]error) {
 		// is(status) - single arg is a status enum value
 		if len(fn.Args) >= 1 {
 			if strVal, ok := fn.Args[0].(string); ok {
-				if normalized, ok := normalizeEnumValue("status", strVal); ok {
+				if strings.EqualFold(strVal, DeletedState) {
+					fn.Args[0] = DeletedState
+				} else if normalized, ok := normalizeEnumValue("status", strVal); ok {
 					fn.Args[0] = normalized
 				}
 			}
@@ -704,6 +736,11 @@ func normalizeEnumValue(field, value string) (string, bool) {
 		if models.IsValidPriority(normalized) {
 			return string(normalized), true
 		}
+	case "blocked_reason":
+		normalized := models.NormalizeBlockedReason(value)
+		if models.IsValidBlockedReason(normalized) {
+			return string(normalized), true
+		}
 	}
 
 	if enumVals, ok := EnumValues[field]; ok {
@@ -536,3 +536,32 @@ func TestMultipleSortClauses(t *testing.T) {
 		t.Error("expected error for multiple sort clauses, got nil")
 	}
 }
+
+func TestNewDateValue(t *testing.T) {
+	for _, s := range []string{"2024-01-15", "today", "last_week", "-7d", "+2w", "3m"} {
+		if _, err := NewDateValue(s); err != nil {
+			t.Errorf("NewDateValue(%q): %v", s, err)
+		}
+	}
+	for _, s := range []string{"", "2024-13-01", "soon", "-xd", "7"} {
+		if _, err := NewDateValue(s); err == nil {
+			t.Errorf("NewDateValue(%q) should fail", s)
+		}
+	}
+}
+
+func TestBuildAndReferencesField(t *testing.T) {
+	q := &Query{Root: And(nil, &FieldExpr{Field: "type", Operator: OpEq, Value: "bug"}, Or(
+		&FunctionCall{Name: "is", Args: []interface{}{"open"}},
+		&FunctionCall{Name: "label", Args: []interface{}{"api"}},
+	))}
+	if got := q.String(); got != "(type = bug AND (is(open) OR label(api)))" {
+		t.Errorf("String() = %q", got)
+	}
+	if !q.ReferencesField("status") || !q.ReferencesField("type") || q.ReferencesField("priority") {
+		t.Error("ReferencesField mismatch")
+	}
+	if And() != nil || And(nil, nil) != nil {
+		t.Error("And of nothing should be nil")
+	}
+}
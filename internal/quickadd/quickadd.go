@@ -0,0 +1,86 @@
+// Package quickadd parses one-line issue descriptions such as
+// "Fix login timeout #bug !p1 @sprint-7 +auth due:friday 3pts" into issue
+// fields. td add and td serve's quick-add endpoint share the grammar:
+//
+//	#type      bug, feature, story, task, epic or chore
+//	!priority  p0-p4, 0-4 or a name such as high
+//	@sprint    sprint name
+//	+label     label (repeatable)
+//	due:date   due date, any form td create --due takes
+//	Npts       story points (also Npt)
+//
+// Words that only look like tokens, such as "#123" or "!important", stay in
+// the title.
+package quickadd
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/dateparse"
+	"github.com/marcus/td/internal/models"
+)
+
+// Fields are the issue fields a quick-add line sets. Zero fields were not
+// given.
+type Fields struct {
+	Title    string          `json:"title"`
+	Type     models.Type     `json:"type,omitempty"`
+	Priority models.Priority `json:"priority,omitempty"`
+	Sprint   string          `json:"sprint,omitempty"`
+	Labels   []string        `json:"labels,omitempty"`
+	DueDate  string          `json:"due_date,omitempty"` // YYYY-MM-DD
+	Points   int             `json:"points,omitempty"`
+}
+
+var pointsRe = regexp.MustCompile(`^(\d+)pts?$`)
+
+// Parse reads a quick-add line, resolving due dates against today.
+func Parse(text string) (Fields, error) {
+	return ParseFrom(text, dateparse.Now())
+}
+
+// ParseFrom reads a quick-add line, resolving due dates against now. A
+// later token of the same kind wins, except labels, which accumulate.
+func ParseFrom(text string, now time.Time) (Fields, error) {
+	var f Fields
+	var title []string
+	for _, word := range strings.Fields(text) {
+		lower := strings.ToLower(word)
+		switch {
+		case len(word) > 1 && word[0] == '#' && models.IsValidType(models.NormalizeType(lower[1:])):
+			f.Type = models.NormalizeType(lower[1:])
+		case len(word) > 1 && word[0] == '!' && models.IsValidPriority(models.NormalizePriority(word[1:])):
+			f.Priority = models.NormalizePriority(word[1:])
+		case len(word) > 1 && word[0] == '@':
+			f.Sprint = word[1:]
+		case len(word) > 1 && word[0] == '+' && !strings.HasPrefix(word, "++"):
+			if !slices.Contains(f.Labels, word[1:]) {
+				f.Labels = append(f.Labels, word[1:])
+			}
+		case strings.HasPrefix(lower, "due:"):
+			due, err := dateparse.ParseDateFrom(word[len("due:"):], now)
+			if err != nil {
+				return Fields{}, fmt.Errorf("invalid due date in %q: %w", word, err)
+			}
+			f.DueDate = due
+		case pointsRe.MatchString(lower):
+			pts, _ := strconv.Atoi(pointsRe.FindStringSubmatch(lower)[1])
+			if !models.IsValidPoints(pts) {
+				return Fields{}, fmt.Errorf("invalid points in %q (must be Fibonacci: 1,2,3,5,8,13,21)", word)
+			}
+			f.Points = pts
+		default:
+			title = append(title, word)
+		}
+	}
+	f.Title = strings.Join(title, " ")
+	if f.Title == "" {
+		return Fields{}, fmt.Errorf("title is required: %q has only tokens", text)
+	}
+	return f, nil
+}
@@ -0,0 +1,73 @@
+package quickadd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestParseFrom(t *testing.T) {
+	// A Wednesday
+	now := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		text string
+		want Fields
+	}{
+		{
+			name: "every token",
+			text: "Fix login timeout #bug !p1 @sprint-7 +auth +backend due:friday 3pts",
+			want: Fields{
+				Title:    "Fix login timeout",
+				Type:     models.TypeBug,
+				Priority: models.PriorityP1,
+				Sprint:   "sprint-7",
+				Labels:   []string{"auth", "backend"},
+				DueDate:  "2026-03-06",
+				Points:   3,
+			},
+		},
+		{
+			name: "tokens anywhere and aliases",
+			text: "#story Export invoices +billing as CSV !high",
+			want: Fields{Title: "Export invoices as CSV", Type: models.TypeFeature, Priority: models.PriorityP1, Labels: []string{"billing"}},
+		},
+		{
+			name: "look-alikes stay in the title",
+			text: "Handle #123 and !important + edge cases",
+			want: Fields{Title: "Handle #123 and !important + edge cases"},
+		},
+		{
+			name: "last token wins",
+			text: "Tidy release notes !p3 !p2 1pt",
+			want: Fields{Title: "Tidy release notes", Priority: models.PriorityP2, Points: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFrom(tt.text, now)
+			if err != nil {
+				t.Fatalf("ParseFrom: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFrom() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFromErrors(t *testing.T) {
+	now := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	for _, text := range []string{
+		"Fix login timeout due:someday",
+		"Fix login timeout 4pts",
+		"#bug !p1 +auth",
+	} {
+		if _, err := ParseFrom(text, now); err == nil {
+			t.Errorf("ParseFrom(%q) succeeded, want error", text)
+		}
+	}
+}
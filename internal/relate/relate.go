@@ -0,0 +1,257 @@
+// Package relate suggests relations between issues that nobody recorded:
+// issues linked to the same files, issues whose logs or comments mention
+// each other, and issues whose notes cite the same commits. Suggestions
+// can be accepted as a dependency or dismissed, and neither is suggested
+// again.
+package relate
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/dependency"
+	"github.com/marcus/td/internal/models"
+)
+
+// Reason kinds, one per signal
+const (
+	ReasonSharedFile   = "shared_file"   // both issues link the file
+	ReasonMentions     = "mentions"      // the issue's notes mention the other
+	ReasonMentionedBy  = "mentioned_by"  // the other's notes mention the issue
+	ReasonSharedCommit = "shared_commit" // one side's notes cite a commit the other touched or cited
+)
+
+// Relations an accepted suggestion can become
+const (
+	RelationDependsOn = "depends_on" // the issue depends on the other
+	RelationBlocks    = "blocks"     // the other depends on the issue
+)
+
+// DefaultLimit is how many suggestions Suggest returns when given no limit
+const DefaultLimit = 10
+
+// ErrUnknownRelation is returned by Accept for a relation other than
+// RelationDependsOn or RelationBlocks
+var ErrUnknownRelation = errors.New("relation must be depends_on or blocks")
+
+// weights score each reason; a mention is stronger evidence than one
+// shared file
+var weights = map[string]int{
+	ReasonSharedFile:   2,
+	ReasonMentions:     3,
+	ReasonMentionedBy:  3,
+	ReasonSharedCommit: 3,
+}
+
+var (
+	issueRefPattern = regexp.MustCompile(`\b[a-z][a-z0-9]{0,9}-[0-9a-f]{6,8}\b`)
+	shaPattern      = regexp.MustCompile(`\b[0-9a-f]{7,40}\b`)
+)
+
+// Reason is one piece of evidence for a suggestion
+type Reason struct {
+	Kind   string `json:"kind"`
+	Detail string `json:"detail"` // the file, commit or issue ID involved
+}
+
+// Suggestion is an issue probably related to the one asked about
+type Suggestion struct {
+	IssueID string        `json:"issue_id"`
+	Title   string        `json:"title"`
+	Status  models.Status `json:"status"`
+	Score   int           `json:"score"`
+	Reasons []Reason      `json:"reasons"`
+}
+
+// Suggest returns up to limit issues probably related to issueID, best
+// first. Issues already linked to it (dependencies either way, parent,
+// children) and pairs accepted or dismissed before are left out.
+func Suggest(database *db.DB, issueID string, limit int) ([]Suggestion, error) {
+	issue, err := database.GetIssue(issueID)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+
+	found := make(map[string]*Suggestion)
+	add := func(id, kind, detail string) {
+		s := found[id]
+		if s == nil {
+			s = &Suggestion{IssueID: id}
+			found[id] = s
+		}
+		for _, r := range s.Reasons {
+			if r.Kind == kind && r.Detail == detail {
+				return
+			}
+		}
+		s.Reasons = append(s.Reasons, Reason{Kind: kind, Detail: detail})
+		s.Score += weights[kind]
+	}
+
+	shared, err := database.SharedFiles(issue.ID)
+	if err != nil {
+		return nil, err
+	}
+	for id, paths := range shared {
+		for _, p := range paths {
+			add(id, ReasonSharedFile, p)
+		}
+	}
+
+	notes, err := database.IssueNotes(issue.ID)
+	if err != nil {
+		return nil, err
+	}
+	text := strings.Join(notes, "\n")
+	for _, ref := range issueRefPattern.FindAllString(text, -1) {
+		if ref != issue.ID {
+			add(ref, ReasonMentions, ref)
+		}
+	}
+	mentioning, err := database.IssuesNoting(issue.ID, issue.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range mentioning {
+		add(id, ReasonMentionedBy, issue.ID)
+	}
+
+	// Commits cited in notes are matched against other issues' notes and
+	// snapshots; commits only in snapshots against other issues' notes, as
+	// issues started from the same HEAD share a snapshot by coincidence
+	cited := commitRefs(issueRefPattern.ReplaceAllString(text, ""))
+	snapshots, err := database.CommitSHAs(issue.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, sha := range append(cited, snapshots...) {
+		short := sha[:7]
+		ids, err := database.IssuesNoting(short, issue.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			add(id, ReasonSharedCommit, short)
+		}
+	}
+	for _, sha := range cited {
+		ids, err := database.IssuesAtCommit(sha[:7], issue.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			add(id, ReasonSharedCommit, sha[:7])
+		}
+	}
+
+	linked, err := database.LinkedIssueIDs(issue.ID)
+	if err != nil {
+		return nil, err
+	}
+	decided, err := database.SuggestionDecisions(issue.ID)
+	if err != nil {
+		return nil, err
+	}
+	var out []Suggestion
+	for id, s := range found {
+		if linked[id] {
+			continue
+		}
+		if _, ok := decided[id]; ok {
+			continue
+		}
+		other, err := database.GetIssue(id)
+		if err != nil {
+			continue // a mention of an issue that does not exist, or was deleted
+		}
+		s.Title = other.Title
+		s.Status = other.Status
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		return out[i].IssueID < out[j].IssueID
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// commitRefs finds commit hashes in text: runs of 7 to 40 hex digits with
+// at least one letter and one digit, so plain numbers and words are skipped
+func commitRefs(text string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, m := range shaPattern.FindAllString(text, -1) {
+		if !strings.ContainsAny(m, "0123456789") || !strings.ContainsAny(m, "abcdef") || seen[m] {
+			continue
+		}
+		seen[m] = true
+		out = append(out, m)
+	}
+	return out
+}
+
+// Accept records the suggested relation between issueID and otherID as a
+// dependency: issueID depends on otherID for RelationDependsOn, and the
+// other way round for RelationBlocks. It returns the dependency added.
+func Accept(database *db.DB, issueID, otherID, relation, sessionID string) (*models.IssueDependency, error) {
+	issueID = db.NormalizeIssueID(issueID)
+	otherID = db.NormalizeIssueID(otherID)
+	if issueID == otherID {
+		return nil, fmt.Errorf("cannot relate %s to itself", issueID)
+	}
+	from, to := issueID, otherID
+	switch relation {
+	case RelationDependsOn, "":
+	case RelationBlocks:
+		from, to = otherID, issueID
+	default:
+		return nil, ErrUnknownRelation
+	}
+	if err := dependency.Validate(database, from, to); err != nil {
+		return nil, err
+	}
+	if err := database.AddDependencyLogged(from, to, "depends_on", sessionID); err != nil {
+		return nil, err
+	}
+	if relation == "" {
+		relation = RelationDependsOn
+	}
+	if err := database.RecordSuggestionDecision(&models.SuggestionDecision{
+		IssueID:      issueID,
+		OtherID:      otherID,
+		Status:       models.SuggestionAccepted,
+		RelationType: relation,
+		SessionID:    sessionID,
+	}); err != nil {
+		return nil, err
+	}
+	return &models.IssueDependency{IssueID: from, DependsOnID: to, RelationType: "depends_on"}, nil
+}
+
+// Dismiss records that issueID and otherID are not related, so neither is
+// suggested for the other again
+func Dismiss(database *db.DB, issueID, otherID, sessionID string) error {
+	for _, id := range []string{issueID, otherID} {
+		if _, err := database.GetIssue(id); err != nil {
+			return err
+		}
+	}
+	return database.RecordSuggestionDecision(&models.SuggestionDecision{
+		IssueID:   issueID,
+		OtherID:   otherID,
+		Status:    models.SuggestionDismissed,
+		SessionID: sessionID,
+	})
+}
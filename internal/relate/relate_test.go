@@ -0,0 +1,115 @@
+package relate
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+func TestSuggest(t *testing.T) {
+	database, err := db.Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	defer database.Close()
+
+	mk := func(title string) *models.Issue {
+		t.Helper()
+		issue := &models.Issue{Title: title}
+		if err := database.CreateIssue(issue); err != nil {
+			t.Fatalf("CreateIssue: %v", err)
+		}
+		return issue
+	}
+	note := func(issueID, msg string) {
+		t.Helper()
+		if err := database.AddLog(&models.Log{IssueID: issueID, SessionID: "ses_test", Message: msg, Type: models.LogTypeProgress}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cache := mk("Rework the session cache")
+	eviction := mk("Cache eviction misses stale keys")
+	docs := mk("Document cache configuration")
+	hotfix := mk("Hotfix for login redirect loop")
+	unrelated := mk("Refresh onboarding screenshots")
+	child := mk("Cache metrics dashboard")
+	child.ParentID = cache.ID
+	if err := database.UpdateIssue(child); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{cache.ID, eviction.ID, child.ID} {
+		if err := database.LinkFile(id, "internal/cache/cache.go", models.FileRoleImplementation, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	note(cache.ID, "Eviction is tracked separately, see "+docs.ID)
+	note(hotfix.ID, "Landed in 3f9a2c1d; it touches the cache too")
+	if err := database.AddGitSnapshot(&models.GitSnapshot{IssueID: cache.ID, Event: "handoff", CommitSHA: "3f9a2c1d0e5b7a6c4d3e2f1a0b9c8d7e6f5a4b3c", Branch: "main"}); err != nil {
+		t.Fatal(err)
+	}
+	note(unrelated.ID, "Sized at 1234567 bytes")
+
+	got, err := Suggest(database, cache.ID, 0)
+	if err != nil {
+		t.Fatalf("Suggest: %v", err)
+	}
+	byID := map[string]Suggestion{}
+	for _, s := range got {
+		byID[s.IssueID] = s
+	}
+	if len(got) != 3 {
+		t.Fatalf("suggestions = %+v, want eviction, docs and hotfix", got)
+	}
+	if s := byID[eviction.ID]; s.Score != 2 || s.Reasons[0].Kind != ReasonSharedFile || s.Title != eviction.Title {
+		t.Errorf("eviction = %+v", s)
+	}
+	if s := byID[docs.ID]; s.Reasons[0].Kind != ReasonMentions {
+		t.Errorf("docs = %+v", s)
+	}
+	if s := byID[hotfix.ID]; s.Reasons[0].Kind != ReasonSharedCommit || s.Reasons[0].Detail != "3f9a2c1" {
+		t.Errorf("hotfix = %+v", s)
+	}
+	if _, ok := byID[child.ID]; ok {
+		t.Error("child suggested despite already being linked")
+	}
+
+	// The mention shows up from the other side too
+	if back, _ := Suggest(database, docs.ID, 0); len(back) != 1 || back[0].Reasons[0].Kind != ReasonMentionedBy {
+		t.Errorf("from docs = %+v", back)
+	}
+
+	// Accepting adds a dependency; dismissing hides the pair both ways
+	if _, err := Accept(database, cache.ID, eviction.ID, "relates", "ses_test"); !errors.Is(err, ErrUnknownRelation) {
+		t.Errorf("bad relation: err = %v", err)
+	}
+	dep, err := Accept(database, cache.ID, eviction.ID, RelationBlocks, "ses_test")
+	if err != nil {
+		t.Fatalf("Accept: %v", err)
+	}
+	if deps, _ := database.GetDependencies(eviction.ID); dep.IssueID != eviction.ID || len(deps) != 1 || deps[0] != cache.ID {
+		t.Errorf("dependency = %+v, deps = %v", dep, deps)
+	}
+	if err := Dismiss(database, docs.ID, cache.ID, "ses_test"); err != nil {
+		t.Fatalf("Dismiss: %v", err)
+	}
+	got, _ = Suggest(database, cache.ID, 0)
+	if len(got) != 1 || got[0].IssueID != hotfix.ID {
+		t.Errorf("after accept and dismiss = %+v, want only hotfix", got)
+	}
+}
+
+func TestCommitRefs(t *testing.T) {
+	got := commitRefs("fixed in a1b2c3d, reverted 0a1b2c3d4e5f again in a1b2c3d; 12345678 and facade0 deadbeef")
+	want := []string{"a1b2c3d", "0a1b2c3d4e5f", "facade0"}
+	if len(got) != len(want) {
+		t.Fatalf("commitRefs = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("commitRefs[%d] = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
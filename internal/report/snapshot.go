@@ -0,0 +1,141 @@
+// Package report renders shareable snapshots of td state.
+package report
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/models"
+)
+
+//go:embed templates/snapshot.html
+var templatesFS embed.FS
+
+var snapshotTmpl = template.Must(template.New("snapshot.html").Funcs(template.FuncMap{
+	"issueURL": func(s *Snapshot, id string) string { return s.IssueURL(id) },
+}).ParseFS(templatesFS, "templates/snapshot.html"))
+
+// Section is a named group of issues, e.g. "Ready" or "Blocked".
+type Section struct {
+	Name   string
+	Issues []models.Issue
+}
+
+// StatusCount is the number of issues in one status.
+type StatusCount struct {
+	Status models.Status
+	Count  int
+}
+
+// Snapshot is a point-in-time view of a board or the monitor task list.
+type Snapshot struct {
+	Title       string // e.g. "Board: Sprint 12" or "All Issues"
+	Query       string // board query, if any
+	GeneratedAt time.Time
+	Sections    []Section
+	LinkBase    string // optional URL prefix for issue links; "" = no links
+}
+
+// statusOrder fixes the order statuses appear in counts
+var statusOrder = []models.Status{
+	models.StatusOpen,
+	models.StatusInProgress,
+	models.StatusBlocked,
+	models.StatusInReview,
+	models.StatusClosed,
+}
+
+// IssueURL returns the link for an issue, or "" when no LinkBase is set.
+func (s *Snapshot) IssueURL(id string) string {
+	if s.LinkBase == "" {
+		return ""
+	}
+	return strings.TrimRight(s.LinkBase, "/") + "/" + id
+}
+
+// Total returns the number of distinct issues in the snapshot.
+func (s *Snapshot) Total() int {
+	seen := make(map[string]bool)
+	for _, sec := range s.Sections {
+		for _, issue := range sec.Issues {
+			seen[issue.ID] = true
+		}
+	}
+	return len(seen)
+}
+
+// StatusCounts returns distinct issue counts per status, omitting zeroes.
+func (s *Snapshot) StatusCounts() []StatusCount {
+	seen := make(map[string]bool)
+	counts := make(map[models.Status]int)
+	for _, sec := range s.Sections {
+		for _, issue := range sec.Issues {
+			if seen[issue.ID] {
+				continue
+			}
+			seen[issue.ID] = true
+			counts[issue.Status]++
+		}
+	}
+	var out []StatusCount
+	for _, st := range statusOrder {
+		if counts[st] > 0 {
+			out = append(out, StatusCount{Status: st, Count: counts[st]})
+		}
+	}
+	return out
+}
+
+// RenderMarkdown writes the snapshot as GitHub-flavored markdown.
+func RenderMarkdown(w io.Writer, s *Snapshot) error {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("# %s\n\n", s.Title))
+	if s.Query != "" {
+		sb.WriteString(fmt.Sprintf("**Query:** `%s`  \n", s.Query))
+	}
+	sb.WriteString(fmt.Sprintf("**Generated:** %s  \n", s.GeneratedAt.Format("2006-01-02 15:04 MST")))
+	sb.WriteString(fmt.Sprintf("**Issues:** %d\n\n", s.Total()))
+
+	if counts := s.StatusCounts(); len(counts) > 0 {
+		sb.WriteString("| Status | Count |\n|--------|------:|\n")
+		for _, c := range counts {
+			sb.WriteString(fmt.Sprintf("| %s | %d |\n", c.Status, c.Count))
+		}
+		sb.WriteString("\n")
+	}
+
+	for _, sec := range s.Sections {
+		if len(sec.Issues) == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("## %s (%d)\n\n", sec.Name, len(sec.Issues)))
+		for _, issue := range sec.Issues {
+			id := "`" + issue.ID + "`"
+			if url := s.IssueURL(issue.ID); url != "" {
+				id = fmt.Sprintf("[`%s`](%s)", issue.ID, url)
+			}
+			sb.WriteString(fmt.Sprintf("- %s **%s** %s — %s\n", id, issue.Priority, issue.Type, markdownEscape(issue.Title)))
+		}
+		sb.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// markdownEscape escapes characters that would change list item rendering
+func markdownEscape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`, "<", "&lt;")
+	return r.Replace(s)
+}
+
+// RenderHTML writes the snapshot as a self-contained HTML page with inline
+// styles and no external assets.
+func RenderHTML(w io.Writer, s *Snapshot) error {
+	return snapshotTmpl.Execute(w, s)
+}
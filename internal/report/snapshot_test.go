@@ -0,0 +1,106 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func testSnapshot() *Snapshot {
+	return &Snapshot{
+		Title:       "Board: Sprint <12>",
+		Query:       "labels ~ sprint",
+		GeneratedAt: time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC),
+		Sections: []Section{
+			{Name: "Ready", Issues: []models.Issue{
+				{ID: "td-a1", Title: "Fix *login* <script>", Status: models.StatusOpen, Priority: models.PriorityP0, Type: models.TypeBug},
+				{ID: "td-a2", Title: "Write docs", Status: models.StatusOpen, Priority: models.PriorityP2, Type: models.TypeTask},
+			}},
+			{Name: "Blocked", Issues: []models.Issue{
+				{ID: "td-b1", Title: "Deploy", Status: models.StatusBlocked, Priority: models.PriorityP1, Type: models.TypeTask},
+			}},
+			{Name: "Closed"},
+		},
+	}
+}
+
+func TestStatusCounts(t *testing.T) {
+	s := testSnapshot()
+	// Duplicate across sections is counted once
+	s.Sections = append(s.Sections, Section{Name: "Mine", Issues: s.Sections[0].Issues[:1]})
+
+	if got := s.Total(); got != 3 {
+		t.Errorf("Total = %d, want 3", got)
+	}
+	counts := s.StatusCounts()
+	if len(counts) != 2 || counts[0].Status != models.StatusOpen || counts[0].Count != 2 || counts[1].Count != 1 {
+		t.Errorf("StatusCounts = %+v", counts)
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	s := testSnapshot()
+	s.LinkBase = "https://td.example.com/issues/"
+
+	var buf bytes.Buffer
+	if err := RenderMarkdown(&buf, s); err != nil {
+		t.Fatalf("RenderMarkdown: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"# Board: Sprint <12>",
+		"**Query:** `labels ~ sprint`",
+		"| open | 2 |",
+		"## Ready (2)",
+		"[`td-a1`](https://td.example.com/issues/td-a1)",
+		`Fix \*login\* &lt;script>`,
+		"## Blocked (1)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("markdown missing %q\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "## Closed") {
+		t.Error("empty sections should be omitted")
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	s := testSnapshot()
+
+	var buf bytes.Buffer
+	if err := RenderHTML(&buf, s); err != nil {
+		t.Fatalf("RenderHTML: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"<title>Board: Sprint &lt;12&gt;</title>",
+		"<h2>Ready (2)</h2>",
+		"Fix *login* &lt;script&gt;",
+		`class="pri P0"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("html missing %q", want)
+		}
+	}
+	if strings.Contains(out, "<a href") {
+		t.Error("no links expected without LinkBase")
+	}
+	if strings.Contains(out, "<link") || strings.Contains(out, "<script src") {
+		t.Error("snapshot must be self-contained")
+	}
+
+	s.LinkBase = "https://td.example.com/i"
+	buf.Reset()
+	if err := RenderHTML(&buf, s); err != nil {
+		t.Fatalf("RenderHTML: %v", err)
+	}
+	if !strings.Contains(buf.String(), `<a href="https://td.example.com/i/td-b1">td-b1</a>`) {
+		t.Error("expected issue link with LinkBase")
+	}
+}
@@ -0,0 +1,155 @@
+// Package retro collects sprint retrospective items and combines them with
+// the sprint's delivery metrics.
+//
+// Items are what went well, what needs improvement and action items. An
+// action item opens a follow-up task labelled "retro" so it is tracked like
+// any other work; the report shows each action item's issue status.
+package retro
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+// Label is put on issues created for action items
+const Label = "retro"
+
+// Metrics summarizes how a sprint went
+type Metrics struct {
+	Issues      int     `json:"issues"`
+	Closed      int     `json:"closed"`
+	CarriedOver int     `json:"carried_over"` // issues still open
+	Blocked     int     `json:"blocked"`
+	Bugs        int     `json:"bugs"`
+	Points      int     `json:"points"`
+	DonePoints  int     `json:"done_points"`
+	Completion  float64 `json:"completion"` // DonePoints / Points, or Closed / Issues without points
+}
+
+// ActionItem is an action item with the state of its follow-up issue
+type ActionItem struct {
+	models.RetroItem
+	IssueStatus models.Status `json:"issue_status,omitempty"`
+}
+
+// Report is a sprint's retrospective
+type Report struct {
+	Sprint           models.Sprint      `json:"sprint"`
+	Metrics          Metrics            `json:"metrics"`
+	WentWell         []models.RetroItem `json:"went_well"`
+	NeedsImprovement []models.RetroItem `json:"needs_improvement"`
+	ActionItems      []ActionItem       `json:"action_items"`
+}
+
+// Measure computes sprint metrics from the sprint's issues
+func Measure(issues []models.Issue) Metrics {
+	var m Metrics
+	for _, issue := range issues {
+		m.Issues++
+		m.Points += issue.Points
+		if issue.Type == models.TypeBug {
+			m.Bugs++
+		}
+		switch issue.Status {
+		case models.StatusClosed:
+			m.Closed++
+			m.DonePoints += issue.Points
+		case models.StatusBlocked:
+			m.Blocked++
+			m.CarriedOver++
+		default:
+			m.CarriedOver++
+		}
+	}
+	if m.Points > 0 {
+		m.Completion = float64(m.DonePoints) / float64(m.Points)
+	} else if m.Issues > 0 {
+		m.Completion = float64(m.Closed) / float64(m.Issues)
+	}
+	return m
+}
+
+// Build groups a sprint's retro items by kind and attaches the metrics.
+// statuses maps action item issue IDs to their current status.
+func Build(sprint models.Sprint, issues []models.Issue, items []models.RetroItem, statuses map[string]models.Status) *Report {
+	r := &Report{
+		Sprint:           sprint,
+		Metrics:          Measure(issues),
+		WentWell:         []models.RetroItem{},
+		NeedsImprovement: []models.RetroItem{},
+		ActionItems:      []ActionItem{},
+	}
+	for _, item := range items {
+		switch item.Kind {
+		case models.RetroWentWell:
+			r.WentWell = append(r.WentWell, item)
+		case models.RetroNeedsImprovement:
+			r.NeedsImprovement = append(r.NeedsImprovement, item)
+		case models.RetroActionItem:
+			r.ActionItems = append(r.ActionItems, ActionItem{RetroItem: item, IssueStatus: statuses[item.IssueID]})
+		}
+	}
+	return r
+}
+
+// Compute loads the sprint's issues and retro items and builds the report
+func Compute(database *db.DB, sprint models.Sprint) (*Report, error) {
+	issues, err := database.ListIssues(db.ListIssuesOptions{Sprint: sprint.Name})
+	if err != nil {
+		return nil, err
+	}
+	items, err := database.ListRetroItems(sprint.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := map[string]models.Status{}
+	for _, item := range items {
+		if item.IssueID == "" {
+			continue
+		}
+		if issue, err := database.GetIssue(item.IssueID); err == nil {
+			statuses[item.IssueID] = issue.Status
+		}
+	}
+	return Build(sprint, issues, items, statuses), nil
+}
+
+// AddItem records a retro item for a sprint. An action item first creates
+// its follow-up task, logged against sessionID.
+func AddItem(database *db.DB, sprint string, kind models.RetroKind, text, sessionID string) (*models.RetroItem, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, fmt.Errorf("retro item text is required")
+	}
+	if !models.IsValidRetroKind(kind) {
+		return nil, fmt.Errorf("invalid retro item kind: %s", kind)
+	}
+
+	item := &models.RetroItem{Sprint: sprint, Kind: kind, Text: text, SessionID: sessionID}
+	if kind == models.RetroActionItem {
+		issue := &models.Issue{
+			Title:          text,
+			Description:    fmt.Sprintf("Action item from the %s retrospective.", sprint),
+			Type:           models.TypeTask,
+			Labels:         []string{Label},
+			CreatorSession: sessionID,
+		}
+		if err := database.CreateIssueLogged(issue, sessionID); err != nil {
+			return nil, fmt.Errorf("create action item issue: %w", err)
+		}
+		if sessionID != "" {
+			// Best effort, as in td create
+			_ = database.RecordSessionAction(issue.ID, sessionID, models.ActionSessionCreated)
+		}
+		item.IssueID = issue.ID
+	}
+
+	if err := database.CreateRetroItem(item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
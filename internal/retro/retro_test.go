@@ -0,0 +1,50 @@
+package retro
+
+import (
+	"testing"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestMeasure(t *testing.T) {
+	m := Measure([]models.Issue{
+		{Status: models.StatusClosed, Points: 5},
+		{Status: models.StatusClosed, Points: 3, Type: models.TypeBug},
+		{Status: models.StatusInProgress, Points: 8},
+		{Status: models.StatusBlocked, Type: models.TypeBug},
+	})
+	want := Metrics{Issues: 4, Closed: 2, CarriedOver: 2, Blocked: 1, Bugs: 2, Points: 16, DonePoints: 8, Completion: 0.5}
+	if m != want {
+		t.Errorf("Measure = %+v, want %+v", m, want)
+	}
+
+	// Without points, completion falls back to the issue count
+	if m := Measure([]models.Issue{{Status: models.StatusClosed}, {}, {}, {}}); m.Completion != 0.25 {
+		t.Errorf("unestimated completion = %v, want 0.25", m.Completion)
+	}
+	if m := Measure(nil); m.Completion != 0 {
+		t.Errorf("empty completion = %v", m.Completion)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	items := []models.RetroItem{
+		{ID: "rt-1", Kind: models.RetroWentWell, Text: "Fast reviews"},
+		{ID: "rt-2", Kind: models.RetroNeedsImprovement, Text: "Flaky CI"},
+		{ID: "rt-3", Kind: models.RetroActionItem, Text: "Quarantine flaky tests", IssueID: "td-a"},
+		{ID: "rt-4", Kind: models.RetroActionItem, Text: "Issue was deleted", IssueID: "td-gone"},
+	}
+	r := Build(models.Sprint{Name: "s1"}, nil, items, map[string]models.Status{"td-a": models.StatusClosed})
+
+	if len(r.WentWell) != 1 || len(r.NeedsImprovement) != 1 || len(r.ActionItems) != 2 {
+		t.Fatalf("Build = %+v", r)
+	}
+	if r.ActionItems[0].IssueStatus != models.StatusClosed || r.ActionItems[1].IssueStatus != "" {
+		t.Errorf("action items = %+v", r.ActionItems)
+	}
+
+	empty := Build(models.Sprint{Name: "s2"}, nil, nil, nil)
+	if empty.WentWell == nil || empty.NeedsImprovement == nil || empty.ActionItems == nil {
+		t.Errorf("empty report has nil lists: %+v", empty)
+	}
+}
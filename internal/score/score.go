@@ -0,0 +1,434 @@
+// Package score computes a per-issue priority score from a configurable
+// arithmetic formula. Static P0-P4 priorities don't capture urgency that
+// builds over time; a formula can weigh priority against age, due dates and
+// size, e.g. "priority * 10 + age / 7 + urgency * 2 - points / 2".
+package score
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode"
+
+	"github.com/marcus/td/internal/models"
+)
+
+// DefaultFormula is used when the project config does not set score_formula
+const DefaultFormula = "priority * 10 + age / 7 + urgency * 2 - points / 2"
+
+// urgencyWindow is how many days before its due date an issue starts
+// gaining urgency
+const urgencyWindow = 14
+
+// Variable is a name a formula may reference
+type Variable struct {
+	Name string `json:"name"`
+	Help string `json:"help"`
+	eval func(issue *models.Issue, now time.Time) float64
+}
+
+var variables = map[string]Variable{
+	"priority": {Help: "priority weight: P0 = 4 down to P4 = 0", eval: func(i *models.Issue, _ time.Time) float64 {
+		return priorityWeight(i.Priority)
+	}},
+	"age": {Help: "days since the issue was created", eval: func(i *models.Issue, now time.Time) float64 {
+		return daysSince(i.CreatedAt, now)
+	}},
+	"idle": {Help: "days since the issue was last updated", eval: func(i *models.Issue, now time.Time) float64 {
+		return daysSince(i.UpdatedAt, now)
+	}},
+	"urgency": {Help: fmt.Sprintf("0 until %d days before the due date, then rising by 1 a day (%d on the day, more once overdue)", urgencyWindow, urgencyWindow), eval: func(i *models.Issue, now time.Time) float64 {
+		left, ok := daysUntilDue(i, now)
+		if !ok {
+			return 0
+		}
+		return math.Max(0, urgencyWindow-left)
+	}},
+	"overdue": {Help: "1 if the due date has passed, else 0", eval: func(i *models.Issue, now time.Time) float64 {
+		if left, ok := daysUntilDue(i, now); ok && left < 0 {
+			return 1
+		}
+		return 0
+	}},
+	"points": {Help: "story points", eval: func(i *models.Issue, _ time.Time) float64 {
+		return float64(i.Points)
+	}},
+	"defers": {Help: "times the issue has been deferred", eval: func(i *models.Issue, _ time.Time) float64 {
+		return float64(i.DeferCount)
+	}},
+	"blocked": {Help: "1 if the issue is blocked, else 0", eval: func(i *models.Issue, _ time.Time) float64 {
+		if i.Status == models.StatusBlocked {
+			return 1
+		}
+		return 0
+	}},
+}
+
+// Variables returns the names a formula may use, sorted by name
+func Variables() []Variable {
+	out := make([]Variable, 0, len(variables))
+	for name, v := range variables {
+		v.Name = name
+		out = append(out, v)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func priorityWeight(p models.Priority) float64 {
+	switch p {
+	case models.PriorityP0:
+		return 4
+	case models.PriorityP1:
+		return 3
+	case models.PriorityP2:
+		return 2
+	case models.PriorityP3:
+		return 1
+	}
+	return 0
+}
+
+func daysSince(t, now time.Time) float64 {
+	if t.IsZero() || t.After(now) {
+		return 0
+	}
+	return now.Sub(t).Hours() / 24
+}
+
+// daysUntilDue returns whole days from today to the due date, negative once
+// overdue
+func daysUntilDue(i *models.Issue, now time.Time) (float64, bool) {
+	if i.DueDate == nil || *i.DueDate == "" {
+		return 0, false
+	}
+	due, err := time.ParseInLocation("2006-01-02", *i.DueDate, now.Location())
+	if err != nil {
+		return 0, false
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return math.Round(due.Sub(today).Hours() / 24), true
+}
+
+// ============================================================================
+// Formula
+// ============================================================================
+
+// Formula is a parsed scoring expression. Formulas support numbers, the
+// variables listed by Variables, + - * /, unary minus, parentheses, and
+// min(a, b, ...) / max(a, b, ...).
+type Formula struct {
+	src  string
+	root expr
+}
+
+// Parse compiles a formula, rejecting unknown variables and functions
+func Parse(src string) (*Formula, error) {
+	p := &parser{src: src}
+	p.next()
+	root, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf("unexpected %q", p.tok.text)
+	}
+	return &Formula{src: strings.TrimSpace(src), root: root}, nil
+}
+
+// MustParse is Parse for formulas known to be valid; it panics on error
+func MustParse(src string) *Formula {
+	f, err := Parse(src)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// Default is DefaultFormula, parsed
+var Default = MustParse(DefaultFormula)
+
+func (f *Formula) String() string { return f.src }
+
+// Eval scores issue as of now. Results are rounded to two decimals so
+// equal-looking scores compare equal; division by zero yields 0.
+func (f *Formula) Eval(issue *models.Issue, now time.Time) float64 {
+	v := f.root.eval(issue, now)
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0
+	}
+	return math.Round(v*100) / 100
+}
+
+// Sort orders issues by score, highest first unless ascending is set. Ties
+// keep their existing order.
+func (f *Formula) Sort(issues []models.Issue, now time.Time, ascending bool) {
+	scores := make(map[string]float64, len(issues))
+	for i := range issues {
+		scores[issues[i].ID] = f.Eval(&issues[i], now)
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := scores[issues[i].ID], scores[issues[j].ID]
+		if ascending {
+			return a < b
+		}
+		return a > b
+	})
+}
+
+var current atomic.Pointer[Formula]
+
+// Use makes f the formula returned by Current; nil restores the default.
+// Long-running processes (td serve) call it with the project's configured
+// formula at startup.
+func Use(f *Formula) {
+	current.Store(f)
+}
+
+// Current returns the formula set with Use, or Default
+func Current() *Formula {
+	if f := current.Load(); f != nil {
+		return f
+	}
+	return Default
+}
+
+// ============================================================================
+// Expression tree
+// ============================================================================
+
+type expr interface {
+	eval(issue *models.Issue, now time.Time) float64
+}
+
+type numberExpr float64
+
+func (n numberExpr) eval(*models.Issue, time.Time) float64 { return float64(n) }
+
+type varExpr struct{ v Variable }
+
+func (e varExpr) eval(issue *models.Issue, now time.Time) float64 { return e.v.eval(issue, now) }
+
+type negExpr struct{ x expr }
+
+func (e negExpr) eval(issue *models.Issue, now time.Time) float64 { return -e.x.eval(issue, now) }
+
+type binaryExpr struct {
+	op   byte
+	l, r expr
+}
+
+func (e binaryExpr) eval(issue *models.Issue, now time.Time) float64 {
+	l, r := e.l.eval(issue, now), e.r.eval(issue, now)
+	switch e.op {
+	case '+':
+		return l + r
+	case '-':
+		return l - r
+	case '*':
+		return l * r
+	default:
+		if r == 0 {
+			return 0
+		}
+		return l / r
+	}
+}
+
+type callExpr struct {
+	max  bool
+	args []expr
+}
+
+func (e callExpr) eval(issue *models.Issue, now time.Time) float64 {
+	out := e.args[0].eval(issue, now)
+	for _, a := range e.args[1:] {
+		if v := a.eval(issue, now); (e.max && v > out) || (!e.max && v < out) {
+			out = v
+		}
+	}
+	return out
+}
+
+// ============================================================================
+// Parser
+// ============================================================================
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokIdent
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+type parser struct {
+	src string
+	pos int
+	tok token
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("score formula: at column %d: %s", p.tok.pos+1, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) next() {
+	for p.pos < len(p.src) && unicode.IsSpace(rune(p.src[p.pos])) {
+		p.pos++
+	}
+	start := p.pos
+	if p.pos >= len(p.src) {
+		p.tok = token{kind: tokEOF, pos: start}
+		return
+	}
+	c := p.src[p.pos]
+	switch {
+	case c >= '0' && c <= '9' || c == '.':
+		for p.pos < len(p.src) && (p.src[p.pos] >= '0' && p.src[p.pos] <= '9' || p.src[p.pos] == '.') {
+			p.pos++
+		}
+		p.tok = token{kind: tokNumber, text: p.src[start:p.pos], pos: start}
+	case c == '_' || unicode.IsLetter(rune(c)):
+		for p.pos < len(p.src) && (p.src[p.pos] == '_' || unicode.IsLetter(rune(p.src[p.pos])) || unicode.IsDigit(rune(p.src[p.pos]))) {
+			p.pos++
+		}
+		p.tok = token{kind: tokIdent, text: strings.ToLower(p.src[start:p.pos]), pos: start}
+	default:
+		p.pos++
+		p.tok = token{kind: tokOp, text: string(c), pos: start}
+	}
+}
+
+func (p *parser) isOp(ops string) bool {
+	return p.tok.kind == tokOp && strings.Contains(ops, p.tok.text)
+}
+
+// parseSum handles + and -
+func (p *parser) parseSum() (expr, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("+-") {
+		op := p.tok.text[0]
+		p.next()
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryExpr{op: op, l: left, r: right}
+	}
+	return left, nil
+}
+
+// parseProduct handles * and /
+func (p *parser) parseProduct() (expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("*/") {
+		op := p.tok.text[0]
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryExpr{op: op, l: left, r: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (expr, error) {
+	if p.isOp("-") {
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return negExpr{x: x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (expr, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokNumber:
+		n, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, p.errorf("invalid number %q", tok.text)
+		}
+		p.next()
+		return numberExpr(n), nil
+	case tokIdent:
+		p.next()
+		if p.isOp("(") {
+			return p.parseCall(tok)
+		}
+		v, ok := variables[tok.text]
+		if !ok {
+			return nil, fmt.Errorf("score formula: unknown variable %q (valid: %s)", tok.text, variableNames())
+		}
+		return varExpr{v: v}, nil
+	case tokOp:
+		if tok.text == "(" {
+			p.next()
+			x, err := p.parseSum()
+			if err != nil {
+				return nil, err
+			}
+			if !p.isOp(")") {
+				return nil, p.errorf("expected )")
+			}
+			p.next()
+			return x, nil
+		}
+		return nil, p.errorf("unexpected %q", tok.text)
+	}
+	return nil, p.errorf("unexpected end of formula")
+}
+
+func (p *parser) parseCall(name token) (expr, error) {
+	if name.text != "min" && name.text != "max" {
+		return nil, fmt.Errorf("score formula: unknown function %q (valid: min, max)", name.text)
+	}
+	p.next() // (
+	call := callExpr{max: name.text == "max"}
+	for {
+		arg, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		call.args = append(call.args, arg)
+		if p.isOp(",") {
+			p.next()
+			continue
+		}
+		if !p.isOp(")") {
+			return nil, p.errorf("expected , or )")
+		}
+		p.next()
+		return call, nil
+	}
+}
+
+func variableNames() string {
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,132 @@
+package score
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestEval(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	due := func(s string) *string { return &s }
+	issue := &models.Issue{
+		Priority:  models.PriorityP1,
+		Points:    5,
+		CreatedAt: now.AddDate(0, 0, -14),
+		UpdatedAt: now.AddDate(0, 0, -3),
+		DueDate:   due("2026-03-13"),
+	}
+
+	tests := []struct {
+		formula string
+		want    float64
+	}{
+		{"priority", 3},
+		{"age", 14},
+		{"idle", 3},
+		{"urgency", 11},
+		{"overdue", 0},
+		{"points", 5},
+		{"priority * 10 + age / 7 - points", 27},
+		{"(priority + 1) * 2", 8},
+		{"-points + 1", -4},
+		{"max(points, 8) - min(1, 2, 3)", 7},
+		{"points / 0", 0},
+		{"PRIORITY * 2", 6},
+		{"10 / 3", 3.33},
+		{DefaultFormula, 30 + 2 + 22 - 2.5},
+	}
+	for _, tt := range tests {
+		f, err := Parse(tt.formula)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.formula, err)
+		}
+		if got := f.Eval(issue, now); got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.formula, got, tt.want)
+		}
+	}
+}
+
+func TestUrgency(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	f := MustParse("urgency")
+	o := MustParse("overdue")
+	tests := []struct {
+		due     string
+		urgency float64
+		overdue float64
+	}{
+		{"", 0, 0},
+		{"2026-06-01", 0, 0},
+		{"2026-03-24", 0, 0},
+		{"2026-03-10", 14, 0},
+		{"2026-03-07", 17, 1},
+		{"not a date", 0, 0},
+	}
+	for _, tt := range tests {
+		issue := &models.Issue{}
+		if tt.due != "" {
+			issue.DueDate = &tt.due
+		}
+		if got := f.Eval(issue, now); got != tt.urgency {
+			t.Errorf("urgency(due %q) = %v, want %v", tt.due, got, tt.urgency)
+		}
+		if got := o.Eval(issue, now); got != tt.overdue {
+			t.Errorf("overdue(due %q) = %v, want %v", tt.due, got, tt.overdue)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, tt := range []struct{ formula, want string }{
+		{"", "unexpected end"},
+		{"priority +", "unexpected end"},
+		{"color * 2", `unknown variable "color"`},
+		{"avg(1, 2)", `unknown function "avg"`},
+		{"(age + 1", "expected )"},
+		{"age 2", `unexpected "2"`},
+		{"1..2", "invalid number"},
+		{"age % 2", `unexpected "%"`},
+	} {
+		_, err := Parse(tt.formula)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Parse(%q) error = %v, want %q", tt.formula, err, tt.want)
+		}
+	}
+}
+
+func TestSort(t *testing.T) {
+	now := time.Now()
+	issues := []models.Issue{
+		{ID: "td-a", Points: 3},
+		{ID: "td-b", Points: 8},
+		{ID: "td-c", Points: 3},
+		{ID: "td-d", Points: 1},
+	}
+	MustParse("points").Sort(issues, now, false)
+	var ids []string
+	for _, i := range issues {
+		ids = append(ids, i.ID)
+	}
+	if got := strings.Join(ids, ","); got != "td-b,td-a,td-c,td-d" {
+		t.Errorf("Sort = %s", got)
+	}
+}
+
+func TestCurrent(t *testing.T) {
+	t.Cleanup(func() { Use(nil) })
+	if Current() != Default {
+		t.Fatal("Current should start as Default")
+	}
+	f := MustParse("points")
+	Use(f)
+	if Current() != f {
+		t.Error("Use did not replace the current formula")
+	}
+	Use(nil)
+	if Current() != Default {
+		t.Error("Use(nil) should restore Default")
+	}
+}
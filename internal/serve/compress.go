@@ -0,0 +1,205 @@
+package serve
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ============================================================================
+// Response Compression
+// ============================================================================
+
+// compressMinSize is the smallest body worth compressing. Smaller responses
+// are buffered and sent as-is.
+const compressMinSize = 1024
+
+var (
+	gzipPool = sync.Pool{New: func() interface{} {
+		w, _ := gzip.NewWriterLevel(io.Discard, gzip.DefaultCompression)
+		return w
+	}}
+	// HTTP's deflate coding is zlib-wrapped DEFLATE (RFC 9110 §8.4.1.2)
+	zlibPool = sync.Pool{New: func() interface{} {
+		w, _ := zlib.NewWriterLevel(io.Discard, zlib.DefaultCompression)
+		return w
+	}}
+)
+
+// compressMiddleware compresses responses with gzip or deflate when the
+// client accepts it. Event streams, bodiless statuses and responses that
+// already carry a Content-Encoding pass through untouched.
+func (s *Server) compressMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Not deferred: after a panic the recovery middleware must still be
+		// able to write its 500 to the untouched writer.
+		cw := &compressWriter{ResponseWriter: w, encoding: encoding, status: http.StatusOK}
+		next.ServeHTTP(cw, r)
+		cw.close()
+	})
+}
+
+// negotiateEncoding picks gzip or deflate from an Accept-Encoding header,
+// preferring gzip on ties. Returns "" when neither is acceptable.
+func negotiateEncoding(header string) string {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		if name == "*" {
+			name = "gzip"
+		}
+		if (name != "gzip" && name != "deflate") || q <= 0 {
+			continue
+		}
+		if q > bestQ || (q == bestQ && name == "gzip") {
+			best, bestQ = name, q
+		}
+	}
+	return best
+}
+
+// compressWriter buffers the start of a response and decides whether to
+// compress once the headers and first compressMinSize bytes are known.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	status   int
+
+	buf         []byte
+	enc         io.WriteCloser // set once compressing
+	decided     bool           // status known and passthrough chosen or ruled out
+	passthrough bool
+	wroteHeader bool
+}
+
+func (cw *compressWriter) WriteHeader(code int) {
+	if cw.decided {
+		return
+	}
+	cw.decided = true
+	cw.status = code
+	h := cw.Header()
+	if code == http.StatusNoContent || code == http.StatusNotModified || code < 200 ||
+		h.Get("Content-Encoding") != "" ||
+		strings.HasPrefix(h.Get("Content-Type"), "text/event-stream") {
+		cw.passthrough = true
+		cw.wroteHeader = true
+		cw.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if !cw.decided {
+		cw.WriteHeader(cw.status)
+	}
+	if cw.passthrough {
+		return cw.ResponseWriter.Write(p)
+	}
+	if cw.enc != nil {
+		return cw.enc.Write(p)
+	}
+	cw.buf = append(cw.buf, p...)
+	if len(cw.buf) >= compressMinSize {
+		if err := cw.startCompression(); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// startCompression sends the headers and flushes the buffer into the encoder
+func (cw *compressWriter) startCompression() error {
+	h := cw.Header()
+	h.Set("Content-Encoding", cw.encoding)
+	h.Del("Content-Length")
+	cw.wroteHeader = true
+	cw.ResponseWriter.WriteHeader(cw.status)
+
+	switch cw.encoding {
+	case "gzip":
+		gz := gzipPool.Get().(*gzip.Writer)
+		gz.Reset(cw.ResponseWriter)
+		cw.enc = gz
+	default:
+		zw := zlibPool.Get().(*zlib.Writer)
+		zw.Reset(cw.ResponseWriter)
+		cw.enc = zw
+	}
+	_, err := cw.enc.Write(cw.buf)
+	cw.buf = nil
+	return err
+}
+
+// Flush forwards streaming flushes. A flush means the handler wants bytes on
+// the wire now, so buffered output starts compressing immediately.
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		cw.WriteHeader(cw.status)
+	}
+	if !cw.passthrough && cw.enc == nil {
+		_ = cw.startCompression()
+	}
+	switch enc := cw.enc.(type) {
+	case *gzip.Writer:
+		_ = enc.Flush()
+	case *zlib.Writer:
+		_ = enc.Flush()
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer for http.ResponseController.
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// close finishes the response: it closes the encoder, or writes a small
+// buffered body uncompressed.
+func (cw *compressWriter) close() {
+	if cw.enc != nil {
+		_ = cw.enc.Close()
+		switch enc := cw.enc.(type) {
+		case *gzip.Writer:
+			gzipPool.Put(enc)
+		case *zlib.Writer:
+			zlibPool.Put(enc)
+		}
+		return
+	}
+	if cw.passthrough {
+		return
+	}
+	if !cw.decided {
+		cw.WriteHeader(cw.status)
+		if cw.passthrough {
+			return
+		}
+	}
+	if !cw.wroteHeader {
+		cw.wroteHeader = true
+		cw.ResponseWriter.WriteHeader(cw.status)
+	}
+	if len(cw.buf) > 0 {
+		_, _ = cw.ResponseWriter.Write(cw.buf)
+	}
+}
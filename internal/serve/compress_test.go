@@ -0,0 +1,168 @@
+package serve
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		header, want string
+	}{
+		{"", ""},
+		{"gzip", "gzip"},
+		{"deflate, gzip", "gzip"},
+		{"deflate", "deflate"},
+		{"gzip;q=0.5, deflate", "deflate"},
+		{"gzip;q=0", ""},
+		{"br", ""},
+		{"*", "gzip"},
+		{"identity", ""},
+	}
+	for _, tt := range tests {
+		if got := negotiateEncoding(tt.header); got != tt.want {
+			t.Errorf("negotiateEncoding(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+// rawGet fetches without Go's transparent gzip handling so the
+// Content-Encoding header can be inspected.
+func rawGet(t *testing.T, url, acceptEncoding string) *http.Response {
+	t.Helper()
+	req, _ := http.NewRequest("GET", url, nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	client := &http.Client{Transport: &http.Transport{DisableCompression: true}}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestCompress_LargeIssueListStreamsGzip(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	for i := 0; i < 50; i++ {
+		issue := &models.Issue{Title: fmt.Sprintf("Streamed issue number %d", i), Description: strings.Repeat("body ", 40)}
+		if err := srv.db.CreateIssue(issue); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resp := rawGet(t, ts.URL+"/v1/issues?limit=1000", "gzip")
+	if resp.Header.Get("Content-Encoding") != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", resp.Header.Get("Content-Encoding"))
+	}
+	gz, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		t.Fatalf("gzip reader: %v", err)
+	}
+	var env struct {
+		OK   bool `json:"ok"`
+		Data struct {
+			Issues []IssueDTO `json:"issues"`
+			Total  int        `json:"total"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(gz).Decode(&env); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !env.OK || len(env.Data.Issues) != 50 || env.Data.Total != 50 {
+		t.Errorf("ok=%v issues=%d total=%d", env.OK, len(env.Data.Issues), env.Data.Total)
+	}
+}
+
+func TestCompress_DeflateIsZlibWrapped(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	for i := 0; i < 20; i++ {
+		issue := &models.Issue{Title: fmt.Sprintf("Deflated issue number %d", i), Description: strings.Repeat("body ", 40)}
+		if err := srv.db.CreateIssue(issue); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resp := rawGet(t, ts.URL+"/v1/issues", "deflate")
+	if resp.Header.Get("Content-Encoding") != "deflate" {
+		t.Fatalf("Content-Encoding = %q, want deflate", resp.Header.Get("Content-Encoding"))
+	}
+	zr, err := zlib.NewReader(resp.Body)
+	if err != nil {
+		t.Fatalf("zlib reader: %v", err)
+	}
+	var env Envelope
+	if err := json.NewDecoder(zr).Decode(&env); err != nil || !env.OK {
+		t.Fatalf("decode = %+v, %v", env, err)
+	}
+}
+
+func TestCompress_SkipsSmallAndUnaccepted(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	if resp := rawGet(t, ts.URL+"/health", "gzip"); resp.Header.Get("Content-Encoding") != "" {
+		t.Errorf("small response encoded as %q", resp.Header.Get("Content-Encoding"))
+	}
+	resp := rawGet(t, ts.URL+"/v1/issues", "")
+	if resp.Header.Get("Content-Encoding") != "" {
+		t.Errorf("response encoded without Accept-Encoding: %q", resp.Header.Get("Content-Encoding"))
+	}
+	var env Envelope
+	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil || !env.OK {
+		t.Errorf("empty list envelope = %+v, %v", env, err)
+	}
+}
+
+func TestCompress_EventStreamPassesThrough(t *testing.T) {
+	srv := newTestServer(ServeConfig{})
+	h := srv.compressMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		io.WriteString(w, strings.Repeat("data: x\n\n", 500))
+		w.(http.Flusher).Flush()
+	}))
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/v1/events", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	h.ServeHTTP(rec, req)
+	if rec.Header().Get("Content-Encoding") != "" {
+		t.Errorf("event stream encoded as %q", rec.Header().Get("Content-Encoding"))
+	}
+	if !strings.HasPrefix(rec.Body.String(), "data: x") {
+		t.Errorf("body = %.20q", rec.Body.String())
+	}
+}
+
+func TestCompress_PanicStillReturns500(t *testing.T) {
+	srv := newTestServer(ServeConfig{})
+	h := srv.recoveryMiddleware(srv.compressMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})))
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/v1/issues", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", rec.Code)
+	}
+}
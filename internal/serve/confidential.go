@@ -0,0 +1,223 @@
+package serve
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/marcus/td/internal/confidential"
+	"github.com/marcus/td/internal/models"
+)
+
+// requestViewer returns who a request reads confidential issues as: the
+// session it acts as, with the scopes of its API token as roles.
+func (s *Server) requestViewer(r *http.Request) confidential.Viewer {
+	var roles []string
+	if tok := requestToken(r); tok != nil {
+		roles = tok.Scopes
+	}
+	return s.db.ConfidentialViewer(s.requestSession(r), roles...)
+}
+
+// revealIssue decrypts a confidential issue and its comments in place when
+// the request may read them. IssueToDTO and CommentToDTO redact whatever
+// is left sealed.
+func (s *Server) revealIssue(r *http.Request, issue *models.Issue, comments []models.Comment) {
+	if _, err := s.db.RevealIssue(issue, comments, s.requestViewer(r)); err != nil {
+		requestLog(r).Warn("reveal confidential issue", "err", err, "id", issue.ID)
+	}
+}
+
+// requireConfidentialAccess writes a 403 and returns false when issue is
+// confidential and the request may not read it.
+func (s *Server) requireConfidentialAccess(w http.ResponseWriter, r *http.Request, issue *models.Issue) bool {
+	allowed, err := s.db.CanReadConfidential(issue, s.requestViewer(r))
+	if err != nil {
+		requestLog(r).Error("check confidential access", "err", err, "id", issue.ID)
+		WriteError(w, ErrInternal, "failed to check access", http.StatusInternalServerError)
+		return false
+	}
+	if !allowed {
+		WriteError(w, ErrForbidden, "no access to confidential issue "+issue.ID, http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// lookupIssue fetches the issue named in the path, writing a 404 or 500
+// when it can't.
+func (s *Server) lookupIssue(w http.ResponseWriter, r *http.Request) (*models.Issue, bool) {
+	id := r.PathValue("id")
+	issue, err := s.db.GetIssue(id)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			WriteError(w, ErrNotFound, fmt.Sprintf("issue not found: %s", id), http.StatusNotFound)
+		} else {
+			requestLog(r).Error("get issue", "err", err, "id", id)
+			WriteError(w, ErrInternal, "failed to fetch issue", http.StatusInternalServerError)
+		}
+		return nil, false
+	}
+	return issue, true
+}
+
+// ConfidentialBody is the JSON body for POST /v1/issues/{id}/confidential.
+type ConfidentialBody struct {
+	Confidential *bool `json:"confidential"`
+}
+
+// GrantBody is the JSON body for POST /v1/issues/{id}/grants.
+type GrantBody struct {
+	Grantee string `json:"grantee"` // a session ID or name, or role:read|write|admin
+}
+
+// ConfidentialGrantDTO is the API representation of a confidential grant.
+type ConfidentialGrantDTO struct {
+	IssueID   string `json:"issue_id"`
+	Grantee   string `json:"grantee"`
+	GrantedBy string `json:"granted_by"`
+	CreatedAt string `json:"created_at"`
+}
+
+// ConfidentialGrantToDTO converts a models.ConfidentialGrant to its DTO.
+func ConfidentialGrantToDTO(g models.ConfidentialGrant) ConfidentialGrantDTO {
+	return ConfidentialGrantDTO{
+		IssueID:   g.IssueID,
+		Grantee:   g.Grantee,
+		GrantedBy: g.GrantedBy,
+		CreatedAt: formatTimestamp(g.CreatedAt),
+	}
+}
+
+// ============================================================================
+// POST /v1/issues/{id}/confidential
+// ============================================================================
+
+// handleSetConfidential marks an issue confidential, sealing its text and
+// comments, or clears the mark. Marking generates the project key when
+// there is none; clearing needs access to the issue.
+func (s *Server) handleSetConfidential(w http.ResponseWriter, r *http.Request) {
+	var body ConfidentialBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		WriteError(w, ErrValidation, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if body.Confidential == nil {
+		WriteValidation(w, []FieldError{{Field: "confidential", Rule: "required", Message: "confidential is required"}})
+		return
+	}
+	issue, ok := s.lookupIssue(w, r)
+	if !ok {
+		return
+	}
+	on := *body.Confidential
+	if on {
+		if _, _, err := confidential.EnsureKey(s.baseDir); err != nil {
+			requestLog(r).Error("confidential key", "err", err)
+			WriteError(w, ErrInternal, "failed to set up the confidential key", http.StatusInternalServerError)
+			return
+		}
+	} else if !s.requireConfidentialAccess(w, r, issue) {
+		return
+	}
+
+	if err := s.db.SetConfidential(issue.ID, on, s.requestSession(r)); err != nil {
+		if !writeRejection(w, err) {
+			requestLog(r).Error("set confidential", "err", err, "id", issue.ID)
+			WriteError(w, ErrInternal, "failed to update issue", http.StatusInternalServerError)
+		}
+		return
+	}
+	s.NotifyChange(r)
+
+	issue, ok = s.lookupIssue(w, r)
+	if !ok {
+		return
+	}
+	s.revealIssue(r, issue, nil)
+	WriteSuccess(w, map[string]interface{}{"issue": IssueToDTO(issue)}, http.StatusOK)
+}
+
+// ============================================================================
+// GET /v1/issues/{id}/grants
+// ============================================================================
+
+// handleListGrants lists who may read a confidential issue besides its
+// creator.
+func (s *Server) handleListGrants(w http.ResponseWriter, r *http.Request) {
+	issue, ok := s.lookupIssue(w, r)
+	if !ok || !s.requireConfidentialAccess(w, r, issue) {
+		return
+	}
+	grants, err := s.db.ListConfidentialGrants(issue.ID)
+	if err != nil {
+		requestLog(r).Error("list grants", "err", err, "id", issue.ID)
+		WriteError(w, ErrInternal, "failed to list grants", http.StatusInternalServerError)
+		return
+	}
+	dtos := make([]ConfidentialGrantDTO, 0, len(grants))
+	for _, g := range grants {
+		dtos = append(dtos, ConfidentialGrantToDTO(g))
+	}
+	WriteSuccess(w, map[string]interface{}{"grants": dtos}, http.StatusOK)
+}
+
+// ============================================================================
+// POST /v1/issues/{id}/grants
+// ============================================================================
+
+// handleAddGrant lets a session, session name or token scope read a
+// confidential issue. Only those who can read it may grant access.
+func (s *Server) handleAddGrant(w http.ResponseWriter, r *http.Request) {
+	var body GrantBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		WriteError(w, ErrValidation, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := confidential.ValidGrantee(body.Grantee); err != nil {
+		WriteValidation(w, []FieldError{{Field: "grantee", Rule: "format", Value: body.Grantee, Message: err.Error()}})
+		return
+	}
+	issue, ok := s.lookupIssue(w, r)
+	if !ok {
+		return
+	}
+	if !issue.Confidential {
+		WriteError(w, ErrConflict, "issue "+issue.ID+" is not confidential", http.StatusConflict)
+		return
+	}
+	if !s.requireConfidentialAccess(w, r, issue) {
+		return
+	}
+	if err := s.db.GrantConfidential(issue.ID, body.Grantee, s.requestSession(r)); err != nil {
+		requestLog(r).Error("grant confidential", "err", err, "id", issue.ID)
+		WriteError(w, ErrInternal, "failed to add grant", http.StatusInternalServerError)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"issue_id": issue.ID, "grantee": body.Grantee}, http.StatusCreated)
+}
+
+// ============================================================================
+// DELETE /v1/issues/{id}/grants/{grantee}
+// ============================================================================
+
+// handleDeleteGrant revokes a grant on a confidential issue.
+func (s *Server) handleDeleteGrant(w http.ResponseWriter, r *http.Request) {
+	issue, ok := s.lookupIssue(w, r)
+	if !ok || !s.requireConfidentialAccess(w, r, issue) {
+		return
+	}
+	grantee := r.PathValue("grantee")
+	removed, err := s.db.RevokeConfidential(issue.ID, grantee)
+	if err != nil {
+		requestLog(r).Error("revoke confidential", "err", err, "id", issue.ID)
+		WriteError(w, ErrInternal, "failed to revoke grant", http.StatusInternalServerError)
+		return
+	}
+	if !removed {
+		WriteError(w, ErrNotFound, "no grant for "+grantee+" on "+issue.ID, http.StatusNotFound)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"issue_id": issue.ID, "grantee": grantee, "revoked": true}, http.StatusOK)
+}
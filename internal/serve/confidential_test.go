@@ -0,0 +1,75 @@
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marcus/td/internal/confidential"
+	"github.com/marcus/td/internal/models"
+)
+
+func TestConfidentialIssueAccess(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+	t.Setenv(confidential.KeyEnv, "")
+	if _, _, err := confidential.EnsureKey(srv.baseDir); err != nil {
+		t.Fatal(err)
+	}
+
+	// Created by another session, so the server's session can't read it
+	issue := &models.Issue{Title: "Investigate the security report", Description: "XSS in the login form", Confidential: true}
+	if err := srv.db.CreateIssueLogged(issue, "ses_other"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, env := doJSON(t, ts, "GET", "/v1/issues/"+issue.ID, nil)
+	got := env.Data.(map[string]interface{})["issue"].(map[string]interface{})
+	if got["description"] != confidential.Redacted || got["confidential"] != true {
+		t.Fatalf("without access: %v", got)
+	}
+	resp, _ := doJSON(t, ts, "PATCH", "/v1/issues/"+issue.ID, map[string]string{"description": "overwritten"})
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("edit without access = %d, want 403", resp.StatusCode)
+	}
+	if resp, _ := doJSON(t, ts, "GET", "/v1/issues/"+issue.ID+"/grants", nil); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("grants without access = %d, want 403", resp.StatusCode)
+	}
+
+	if err := srv.db.GrantConfidential(issue.ID, "ses_test123", "ses_other"); err != nil {
+		t.Fatal(err)
+	}
+	_, env = doJSON(t, ts, "GET", "/v1/issues/"+issue.ID, nil)
+	got = env.Data.(map[string]interface{})["issue"].(map[string]interface{})
+	if got["description"] != "XSS in the login form" {
+		t.Errorf("with a grant: %v", got["description"])
+	}
+
+	// Lists always redact
+	_, env = doJSON(t, ts, "GET", "/v1/issues?confidential=true", nil)
+	list := env.Data.(map[string]interface{})["issues"].([]interface{})
+	if len(list) != 1 || list[0].(map[string]interface{})["description"] != confidential.Redacted {
+		t.Errorf("list = %v", list)
+	}
+
+	resp, _ = doJSON(t, ts, "POST", "/v1/issues/"+issue.ID+"/grants", map[string]string{"grantee": "role:owner"})
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("bad grantee = %d, want 400", resp.StatusCode)
+	}
+	if resp, _ := doJSON(t, ts, "POST", "/v1/issues/"+issue.ID+"/grants", map[string]string{"grantee": "role:read"}); resp.StatusCode != http.StatusCreated {
+		t.Errorf("grant = %d", resp.StatusCode)
+	}
+	if resp, _ := doJSON(t, ts, "DELETE", "/v1/issues/"+issue.ID+"/grants/role:read", nil); resp.StatusCode != http.StatusOK {
+		t.Errorf("revoke = %d", resp.StatusCode)
+	}
+
+	resp, env = doJSON(t, ts, "POST", "/v1/issues/"+issue.ID+"/confidential", map[string]bool{"confidential": false})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("clear confidential = %d", resp.StatusCode)
+	}
+	stored, _ := srv.db.GetIssue(issue.ID)
+	if stored.Confidential || stored.Description != "XSS in the login form" {
+		t.Errorf("after clearing: %v %q", stored.Confidential, stored.Description)
+	}
+}
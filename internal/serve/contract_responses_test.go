@@ -0,0 +1,121 @@
+package serve_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/marcus/td/internal/serve"
+	"github.com/marcus/td/pkg/tdtest"
+)
+
+// TestContractResponses records the JSON shape of real responses: every
+// key path with the JSON types seen there. Shapes are compared rather
+// than bodies, so new data doesn't churn the golden file but a renamed,
+// removed or retyped field fails. The tdtest server keeps IDs and times
+// fixed, so the shapes are the same on every run.
+func TestContractResponses(t *testing.T) {
+	srv := tdtest.New(t, tdtest.Options{Issues: []tdtest.Issue{
+		{Key: "epic", Title: "Single sign-on for enterprise accounts", Type: "epic"},
+		{Key: "login", Title: "Fix login timeout for SSO users", Priority: "P1", Labels: []string{"auth"}, Parent: "epic"},
+		{Key: "docs", Title: "Document the SSO setup steps", DependsOn: []string{"login"}},
+	}})
+	login := srv.ID("login")
+
+	requests := []struct {
+		method, path string
+		body         any
+	}{
+		{"GET", "/health", nil},
+		{"GET", "/versions", nil},
+		{"POST", "/v1/issues", map[string]any{"title": "Rotate the SAML signing certificate", "labels": []string{"auth"}}},
+		{"POST", "/v1/issues", map[string]any{}},
+		{"GET", "/v1/issues", nil},
+		{"GET", "/v1/issues/" + login + "?include=all", nil},
+		{"GET", "/v1/issues/td-missing", nil},
+		{"PATCH", "/v1/issues/" + login, map[string]any{"priority": "P0"}},
+		{"POST", "/v1/issues/" + login + "/comments", map[string]any{"text": "Reproduced with a 30 minute idle session"}},
+		{"POST", "/v1/issues/" + login + "/start", map[string]any{}},
+		{"GET", "/v1/monitor", nil},
+		{"GET", "/v1/boards", nil},
+		{"GET", "/v1/sessions", nil},
+		{"GET", "/v1/stats", nil},
+	}
+
+	var lines []string
+	for _, req := range requests {
+		name := req.method + " " + strings.Replace(req.path, login, "{id}", 1)
+		resp, err := srv.Do(req.method, req.path, req.body)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("%s: read body: %v", name, err)
+		}
+		var body any
+		if err := json.Unmarshal(data, &body); err != nil {
+			t.Fatalf("%s: decode body: %v", name, err)
+		}
+
+		shape := make(map[string]map[string]bool)
+		jsonShape(shape, "", body)
+		lines = append(lines, fmt.Sprintf("%s %d", name, resp.StatusCode))
+		for _, path := range sortedKeys(shape) {
+			lines = append(lines, fmt.Sprintf("%s   %s %s", name, path, strings.Join(sortedKeys(shape[path]), "|")))
+		}
+	}
+	serve.CheckContract(t, "v1-responses.golden", lines)
+}
+
+// idKey matches object keys that are IDs, such as the issue IDs keying
+// monitor cards; they are recorded as {id}
+var idKey = regexp.MustCompile(`^[a-z]+[-_][0-9a-f]{6,}$`)
+
+// jsonShape records the JSON type of v at path, and of everything inside
+// it. Array elements share the path "path[]".
+func jsonShape(shape map[string]map[string]bool, path string, v any) {
+	kind := "null"
+	switch v := v.(type) {
+	case map[string]any:
+		kind = "object"
+		for k, child := range v {
+			if idKey.MatchString(k) {
+				k = "{id}"
+			}
+			jsonShape(shape, path+"."+k, child)
+		}
+	case []any:
+		kind = "array"
+		for _, child := range v {
+			jsonShape(shape, path+"[]", child)
+		}
+	case string:
+		kind = "string"
+	case float64:
+		kind = "number"
+	case bool:
+		kind = "bool"
+	}
+	if path == "" {
+		path = "."
+	}
+	if shape[path] == nil {
+		shape[path] = make(map[string]bool)
+	}
+	shape[path][kind] = true
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
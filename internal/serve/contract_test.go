@@ -0,0 +1,274 @@
+package serve
+
+import (
+	"flag"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// ============================================================================
+// API Contract Tests
+// ============================================================================
+//
+// The v1 API is frozen (see versioning.go). These tests compare the routes
+// and DTO fields declared in this package, and the shape of real responses
+// (contract_responses_test.go), with golden files under testdata/contract.
+// After an intended change, rewrite them with
+//
+//	go test ./internal/serve -run Contract -update
+//
+// and review the diff: removed or changed lines break v1 clients.
+
+// UpdateContract rewrites the golden files instead of comparing with them.
+// Exported for the external contract tests.
+var UpdateContract = flag.Bool("update", false, "rewrite testdata/contract golden files")
+
+// CheckContract compares lines with the named golden file, or rewrites it
+// with -update.
+func CheckContract(t *testing.T, name string, lines []string) {
+	t.Helper()
+	path := filepath.Join("testdata", "contract", name)
+	got := strings.Join(lines, "\n") + "\n"
+	if *UpdateContract {
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v (run with -update to create it)", path, err)
+	}
+	want := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	have := make(map[string]bool, len(lines))
+	for _, l := range lines {
+		have[l] = true
+	}
+	recorded := make(map[string]bool, len(want))
+	for _, l := range want {
+		recorded[l] = true
+		if !have[l] {
+			t.Errorf("%s: %q removed or changed; this breaks v1 clients", name, l)
+		}
+	}
+	for _, l := range lines {
+		if !recorded[l] {
+			t.Errorf("%s: %q is new; if intended, record it with go test ./internal/serve -run Contract -update", name, l)
+		}
+	}
+}
+
+// parsePackage parses the non-test Go files of this package
+func parsePackage(t *testing.T) []*ast.File {
+	t.Helper()
+	paths, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, p := range paths {
+		if strings.HasSuffix(p, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, p, nil, parser.SkipObjectResolution)
+		if err != nil {
+			t.Fatalf("parse %s: %v", p, err)
+		}
+		files = append(files, f)
+	}
+	return files
+}
+
+// stringConsts collects the package's string constants, for resolving
+// route patterns built from them
+func stringConsts(files []*ast.File) map[string]string {
+	consts := make(map[string]string)
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				vs := spec.(*ast.ValueSpec)
+				for i, name := range vs.Names {
+					if i < len(vs.Values) {
+						if lit, ok := vs.Values[i].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+							consts[name.Name], _ = strconv.Unquote(lit.Value)
+						}
+					}
+				}
+			}
+		}
+	}
+	return consts
+}
+
+// evalString evaluates a string literal, constant, or concatenation of them
+func evalString(expr ast.Expr, consts map[string]string) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		s, err := strconv.Unquote(e.Value)
+		return s, err == nil
+	case *ast.Ident:
+		s, ok := consts[e.Name]
+		return s, ok
+	case *ast.BinaryExpr:
+		l, ok1 := evalString(e.X, consts)
+		r, ok2 := evalString(e.Y, consts)
+		return l + r, ok1 && ok2 && e.Op == token.ADD
+	}
+	return "", false
+}
+
+func TestContractRoutes(t *testing.T) {
+	files := parsePackage(t)
+	consts := stringConsts(files)
+
+	var routes []string
+	for _, f := range files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) != 2 {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "HandleFunc" || types.ExprString(sel.X) != "s.mux" {
+				return true
+			}
+			pattern, ok := evalString(call.Args[0], consts)
+			if !ok {
+				t.Errorf("route pattern %s is not a constant string", types.ExprString(call.Args[0]))
+				return true
+			}
+			routes = append(routes, pattern)
+			return true
+		})
+	}
+	if len(routes) < 10 {
+		t.Fatalf("found only %d routes; is registerRoutes still using s.mux.HandleFunc?", len(routes))
+	}
+
+	// Every route found in the source must be what the mux serves
+	srv := newTestServer(ServeConfig{})
+	for _, pattern := range routes {
+		if _, registered := srv.mux.Handler(requestFor(t, pattern)); registered != pattern {
+			t.Errorf("route %q resolves to %q", pattern, registered)
+		}
+	}
+
+	// v2 is a preview and not frozen
+	var frozen []string
+	for _, pattern := range routes {
+		if _, path, _ := strings.Cut(pattern, " "); !strings.HasPrefix(path, "/v2/") && !strings.HasPrefix(pattern, "/v2/") {
+			frozen = append(frozen, pattern)
+		}
+	}
+	sort.Strings(frozen)
+	CheckContract(t, "v1-routes.golden", frozen)
+}
+
+// requestFor builds a request that pattern matches, filling wildcards
+func requestFor(t *testing.T, pattern string) *http.Request {
+	t.Helper()
+	method, path, ok := strings.Cut(pattern, " ")
+	if !ok {
+		method, path = "GET", pattern
+	}
+	var segs []string
+	for _, seg := range strings.Split(path, "/") {
+		if strings.HasPrefix(seg, "{") {
+			seg = "x"
+		}
+		segs = append(segs, seg)
+	}
+	req, err := http.NewRequest(method, "http://td.test"+strings.Join(segs, "/"), nil)
+	if err != nil {
+		t.Fatalf("request for %q: %v", pattern, err)
+	}
+	return req
+}
+
+// contractType reports whether a struct type is part of the API contract
+func contractType(name string) bool {
+	return strings.HasSuffix(name, "DTO") || name == "Envelope" || name == "ErrorPayload" ||
+		name == "FieldError" || name == "ValidationDetails"
+}
+
+func TestContractDTOs(t *testing.T) {
+	var lines []string
+	for _, f := range parsePackage(t) {
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec)
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok || !ast.IsExported(ts.Name.Name) || !contractType(ts.Name.Name) {
+					continue
+				}
+				lines = append(lines, structFields(ts.Name.Name, st)...)
+			}
+		}
+	}
+	if len(lines) == 0 {
+		t.Fatal("no DTOs found")
+	}
+	sort.Strings(lines)
+	CheckContract(t, "v1-dto.golden", lines)
+}
+
+// structFields renders the JSON fields of a struct as "Type.field gotype[,options]"
+func structFields(typeName string, st *ast.StructType) []string {
+	var lines []string
+	for _, field := range st.Fields.List {
+		goType := types.ExprString(field.Type)
+		tag := ""
+		if field.Tag != nil {
+			raw, _ := strconv.Unquote(field.Tag.Value)
+			tag = reflect.StructTag(raw).Get("json")
+		}
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if opts != "" {
+			goType += "," + opts
+		}
+
+		if len(field.Names) == 0 {
+			// Embedded: its fields are promoted unless the tag names it
+			if name == "" {
+				lines = append(lines, typeName+".(embedded) "+goType)
+				continue
+			}
+			lines = append(lines, typeName+"."+name+" "+goType)
+			continue
+		}
+		for _, ident := range field.Names {
+			if !ident.IsExported() {
+				continue
+			}
+			jsonName := name
+			if jsonName == "" {
+				jsonName = ident.Name
+			}
+			lines = append(lines, typeName+"."+jsonName+" "+goType)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,54 @@
+package serve
+
+import (
+	"context"
+	"sync"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/dedupe"
+	"github.com/marcus/td/internal/jobs"
+)
+
+// duplicateCache holds the latest duplicate report at the default
+// threshold, refreshed by the periodic scan and dropped after a merge
+type duplicateCache struct {
+	mu     sync.Mutex
+	report *dedupe.Report
+}
+
+func (c *duplicateCache) get() *dedupe.Report {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.report
+}
+
+func (c *duplicateCache) set(r *dedupe.Report) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.report = r
+}
+
+// scanDuplicates computes the default-threshold report and caches it
+func (s *Server) scanDuplicates() (*dedupe.Report, error) {
+	report, err := dedupe.Compute(s.db, dedupe.DefaultThreshold, clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	s.duplicates.set(report)
+	return report, nil
+}
+
+// dedupeJob rescans for duplicates every DedupeInterval. A zero interval
+// leaves the job manual-only; reports are then computed on request.
+func (s *Server) dedupeJob() jobs.Job {
+	return jobs.Job{
+		Name:        "duplicates",
+		Description: "Rebuild the cached duplicate issue report",
+		Interval:    s.config.DedupeInterval,
+		Immediate:   true,
+		Run: func(context.Context) error {
+			_, err := s.scanDuplicates()
+			return err
+		},
+	}
+}
@@ -0,0 +1,124 @@
+package serve
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/marcus/td/internal/delivery"
+	"github.com/marcus/td/internal/jobs"
+	"github.com/marcus/td/internal/models"
+)
+
+// deliveryJob sends what is waiting in the delivery outbox: webhooks,
+// channel posts and notifications queued by this server or by td commands.
+// Writes through the API trigger it too, so changes go out without waiting
+// for the interval.
+func (s *Server) deliveryJob() jobs.Job {
+	return jobs.Job{
+		Name:        "deliveries",
+		Description: "Send queued webhooks, channel posts and notifications",
+		Interval:    s.config.DeliveryInterval,
+		Run: func(ctx context.Context) error {
+			res, err := delivery.Run(ctx, s.db, s.baseDir)
+			if res.Delivered+res.Retrying+res.Failed > 0 {
+				slog.Info("deliveries", "queued", res.Queued, "delivered", res.Delivered,
+					"retrying", res.Retrying, "failed", res.Failed)
+			}
+			return err
+		},
+	}
+}
+
+// triggerDeliveries starts a deliveries run unless one is already going;
+// anything it misses is picked up on the next interval
+func (s *Server) triggerDeliveries() {
+	if s.jobs != nil {
+		_ = s.jobs.Trigger("deliveries")
+	}
+}
+
+// ============================================================================
+// GET /v1/deliveries
+// ============================================================================
+
+// handleListDeliveries lists deliveries newest first, without payloads,
+// along with how many have each status.
+func (s *Server) handleListDeliveries(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	status := q.Get("status")
+	switch status {
+	case "", models.DeliveryPending, models.DeliveryDelivered, models.DeliveryFailed:
+	default:
+		WriteValidation(w, []FieldError{{Field: "status", Rule: "invalid", Value: status,
+			Message: "status must be one of " + strings.Join([]string{models.DeliveryPending, models.DeliveryDelivered, models.DeliveryFailed}, ", ")}})
+		return
+	}
+	limit := 100
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			WriteValidation(w, []FieldError{{Field: "limit", Rule: "min", Value: v, Message: "limit must be a positive number"}})
+			return
+		}
+		limit = n
+	}
+
+	deliveries, err := s.db.ListDeliveries(status, limit)
+	if err != nil {
+		WriteError(w, ErrInternal, "failed to list deliveries: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	counts, err := s.db.CountDeliveries()
+	if err != nil {
+		WriteError(w, ErrInternal, "failed to count deliveries: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for i := range deliveries {
+		deliveries[i].Payload = ""
+	}
+	WriteSuccess(w, map[string]interface{}{"deliveries": deliveries, "counts": counts}, http.StatusOK)
+}
+
+// ============================================================================
+// GET /v1/deliveries/{id}
+// ============================================================================
+
+func (s *Server) handleGetDelivery(w http.ResponseWriter, r *http.Request) {
+	d, err := s.db.GetDelivery(r.PathValue("id"))
+	if err != nil {
+		writeDeliveryError(w, err)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"delivery": d}, http.StatusOK)
+}
+
+// ============================================================================
+// POST /v1/deliveries/{id}/retry
+// ============================================================================
+
+// handleRetryDelivery makes a failed or pending delivery due now with a
+// fresh set of attempts and starts a deliveries run.
+func (s *Server) handleRetryDelivery(w http.ResponseWriter, r *http.Request) {
+	d, err := s.db.RetryDelivery(r.PathValue("id"))
+	if err != nil {
+		writeDeliveryError(w, err)
+		return
+	}
+	requestLog(r).Info("delivery retried", "delivery", d.ID)
+	s.triggerDeliveries()
+	WriteSuccess(w, map[string]interface{}{"delivery": d}, http.StatusAccepted)
+}
+
+func writeDeliveryError(w http.ResponseWriter, err error) {
+	switch msg := err.Error(); {
+	case strings.HasPrefix(msg, "delivery not found"):
+		WriteError(w, ErrNotFound, msg, http.StatusNotFound)
+	case strings.HasSuffix(msg, "already delivered"):
+		WriteError(w, ErrConflict, msg, http.StatusConflict)
+	default:
+		WriteError(w, ErrInternal, msg, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,69 @@
+package serve
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestDeliveriesAPI(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	sent := &models.Delivery{Kind: "webhook", Target: "https://example.com/hook", Payload: `{"actions":[]}`}
+	broken := &models.Delivery{Kind: "webhook", Target: "https://example.com/down", Payload: `{"actions":[]}`}
+	now := time.Now()
+	for _, d := range []*models.Delivery{sent, broken} {
+		if err := srv.db.AddDelivery(d); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := srv.db.ClaimDelivery(d.ID, now, now.Add(time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = srv.db.FinishDelivery(sent.ID, nil, nil)
+	_ = srv.db.FinishDelivery(broken.ID, errors.New("503 Service Unavailable"), nil)
+
+	resp, env := doJSON(t, ts, "GET", "/v1/deliveries?status=failed", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("list status = %d", resp.StatusCode)
+	}
+	data := env.Data.(map[string]interface{})
+	list := data["deliveries"].([]interface{})
+	if len(list) != 1 || list[0].(map[string]interface{})["id"] != broken.ID || list[0].(map[string]interface{})["payload"] != "" {
+		t.Fatalf("deliveries = %v", list)
+	}
+	if counts := data["counts"].(map[string]interface{}); counts["failed"] != 1.0 || counts["delivered"] != 1.0 {
+		t.Errorf("counts = %v", counts)
+	}
+
+	_, env = doJSON(t, ts, "GET", "/v1/deliveries/"+sent.ID, nil)
+	got := env.Data.(map[string]interface{})["delivery"].(map[string]interface{})
+	if got["status"] != models.DeliveryDelivered || got["payload"] != sent.Payload {
+		t.Errorf("delivery = %v", got)
+	}
+
+	resp, env = doJSON(t, ts, "POST", "/v1/deliveries/"+broken.ID+"/retry", nil)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("retry status = %d", resp.StatusCode)
+	}
+	got = env.Data.(map[string]interface{})["delivery"].(map[string]interface{})
+	if got["status"] != models.DeliveryPending || got["attempts"] != 0.0 {
+		t.Errorf("retried = %v", got)
+	}
+
+	if resp, _ := doJSON(t, ts, "POST", "/v1/deliveries/"+sent.ID+"/retry", nil); resp.StatusCode != http.StatusConflict {
+		t.Errorf("retry delivered = %d", resp.StatusCode)
+	}
+	if resp, _ := doJSON(t, ts, "GET", "/v1/deliveries/dl-nope", nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("get unknown = %d", resp.StatusCode)
+	}
+	if resp, _ := doJSON(t, ts, "GET", "/v1/deliveries?status=lost", nil); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("bad status filter = %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,59 @@
+package serve
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+)
+
+// ============================================================================
+// Conditional GET
+// ============================================================================
+
+// etagWindow bounds how long an ETag stays valid without a write. Monitor
+// data and TDQ relative dates depend on the clock as well as the change
+// tokens, so tags roll over at least this often.
+const etagWindow = time.Minute
+
+// computeETag builds a weak ETag from the change tokens a response depends
+// on, the request's query string and the current etagWindow.
+func computeETag(r *http.Request, sessionID string, parts ...string) string {
+	h := fnv.New64a()
+	for _, p := range append(parts, r.URL.Path, r.URL.RawQuery, sessionID) {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+	}
+	fmt.Fprintf(h, "%d", clock.Now().Unix()/int64(etagWindow/time.Second))
+	return fmt.Sprintf(`W/"%x"`, h.Sum64())
+}
+
+// checkNotModified sets the ETag header and, if the request's If-None-Match
+// matches it, writes 304 with no body and returns true.
+func checkNotModified(w http.ResponseWriter, r *http.Request, etag string) bool {
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	if !etagMatches(r.Header.Get("If-None-Match"), etag) {
+		return false
+	}
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// etagMatches applies the weak comparison If-None-Match requires
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	want := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == want {
+			return true
+		}
+	}
+	return false
+}
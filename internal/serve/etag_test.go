@@ -0,0 +1,140 @@
+package serve
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func conditionalGet(t *testing.T, url, etag string) *http.Response {
+	t.Helper()
+	req, _ := http.NewRequest("GET", url, nil)
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestConditionalGet_Issues(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	if err := srv.db.CreateIssueLogged(&models.Issue{Title: "Cached issue"}, srv.sessionID); err != nil {
+		t.Fatal(err)
+	}
+
+	first := conditionalGet(t, ts.URL+"/v1/issues", "")
+	etag := first.Header.Get("ETag")
+	if first.StatusCode != http.StatusOK || etag == "" {
+		t.Fatalf("first GET status=%d etag=%q", first.StatusCode, etag)
+	}
+
+	second := conditionalGet(t, ts.URL+"/v1/issues", etag)
+	if second.StatusCode != http.StatusNotModified {
+		t.Fatalf("second GET status = %d, want 304", second.StatusCode)
+	}
+	if body, _ := io.ReadAll(second.Body); len(body) != 0 {
+		t.Errorf("304 body = %q", body)
+	}
+
+	// Different filters are different representations
+	if resp := conditionalGet(t, ts.URL+"/v1/issues?include_closed=true", etag); resp.StatusCode != http.StatusOK {
+		t.Errorf("other query status = %d, want 200", resp.StatusCode)
+	}
+
+	// A comment leaves the issues list alone, a new issue does not
+	if err := srv.db.AddComment(&models.Comment{IssueID: "td-none", SessionID: srv.sessionID, Text: "hi"}); err != nil {
+		t.Fatal(err)
+	}
+	if resp := conditionalGet(t, ts.URL+"/v1/issues", etag); resp.StatusCode != http.StatusNotModified {
+		t.Errorf("after comment status = %d, want 304", resp.StatusCode)
+	}
+	if err := srv.db.CreateIssueLogged(&models.Issue{Title: "Another issue"}, srv.sessionID); err != nil {
+		t.Fatal(err)
+	}
+	if resp := conditionalGet(t, ts.URL+"/v1/issues", etag); resp.StatusCode != http.StatusOK {
+		t.Errorf("after create status = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestConditionalGet_MonitorAndBoard(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	board, err := srv.db.CreateBoardLogged("Cache board", "", srv.sessionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first monitor read creates the CLI session, which is a real change
+	conditionalGet(t, ts.URL+"/v1/monitor", "")
+
+	for _, path := range []string{"/v1/monitor", "/v1/boards/" + board.ID} {
+		first := conditionalGet(t, ts.URL+path, "")
+		etag := first.Header.Get("ETag")
+		if etag == "" {
+			t.Fatalf("%s: no ETag", path)
+		}
+		if resp := conditionalGet(t, ts.URL+path, etag); resp.StatusCode != http.StatusNotModified {
+			t.Errorf("%s: status = %d, want 304", path, resp.StatusCode)
+		}
+		if resp := conditionalGet(t, ts.URL+path, `"stale", `+etag); resp.StatusCode != http.StatusNotModified {
+			t.Errorf("%s: list If-None-Match status = %d, want 304", path, resp.StatusCode)
+		}
+	}
+
+	if resp := conditionalGet(t, ts.URL+"/v1/boards/bd-missing", "*"); resp.StatusCode != http.StatusNotFound || resp.Header.Get("ETag") != "" {
+		t.Errorf("missing board status = %d etag = %q", resp.StatusCode, resp.Header.Get("ETag"))
+	}
+}
+
+func TestConditionalGet_Summary(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	urgent := &models.Issue{Title: "Outage in sync", Priority: models.PriorityP0}
+	for _, issue := range []*models.Issue{urgent, {Title: "Tidy docs"}} {
+		if err := srv.db.CreateIssueLogged(issue, srv.sessionID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resp, env := doJSON(t, ts, "GET", "/v1/summary", nil)
+	etag := resp.Header.Get("ETag")
+	if resp.StatusCode != http.StatusOK || etag == "" {
+		t.Fatalf("status = %d etag = %q", resp.StatusCode, etag)
+	}
+	data := env.Data.(map[string]interface{})
+	if data["open"] != float64(2) || data["open_p0"] != float64(1) || data["review_queue"] != float64(0) || data["last_activity"] == nil {
+		t.Errorf("summary = %v", data)
+	}
+	if byStatus := data["by_status"].(map[string]interface{}); len(byStatus) != 5 || byStatus["open"] != float64(2) {
+		t.Errorf("by_status = %v", byStatus)
+	}
+	if resp := conditionalGet(t, ts.URL+"/v1/summary", etag); resp.StatusCode != http.StatusNotModified {
+		t.Errorf("unchanged status = %d, want 304", resp.StatusCode)
+	}
+
+	urgent.Status = models.StatusInReview
+	if err := srv.db.UpdateIssueLogged(urgent, srv.sessionID, models.ActionReview); err != nil {
+		t.Fatal(err)
+	}
+	if resp := conditionalGet(t, ts.URL+"/v1/summary", etag); resp.StatusCode != http.StatusOK {
+		t.Fatalf("after review status = %d, want 200", resp.StatusCode)
+	}
+	_, env = doJSON(t, ts, "GET", "/v1/summary", nil)
+	if data := env.Data.(map[string]interface{}); data["review_queue"] != float64(1) || data["open_p0"] != float64(1) {
+		t.Errorf("after review = %v", data)
+	}
+}
@@ -0,0 +1,244 @@
+package serve
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ============================================================================
+// Sparse Fieldsets (?fields=)
+// ============================================================================
+
+var (
+	issueDTOType = reflect.TypeOf(IssueDTO{})
+
+	// issueFieldIndex maps IssueDTO JSON names to struct field indexes
+	issueFieldIndex = func() map[string]int {
+		m := make(map[string]int, issueDTOType.NumField())
+		for i := 0; i < issueDTOType.NumField(); i++ {
+			m[jsonFieldName(issueDTOType.Field(i))] = i
+		}
+		return m
+	}()
+)
+
+// IssueFields selects which IssueDTO fields a response includes, as struct
+// field indexes in declaration order. A nil IssueFields means every field.
+type IssueFields []int
+
+// ParseIssueFields reads the ?fields= parameter (comma-separated, may be
+// repeated). id is always included. Unknown names are validation errors.
+func ParseIssueFields(q url.Values) (IssueFields, []FieldError) {
+	raw := strings.Join(q["fields"], ",")
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+
+	selected := map[int]bool{issueFieldIndex["id"]: true}
+	var errs []FieldError
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		idx, ok := issueFieldIndex[name]
+		if !ok {
+			errs = append(errs, FieldError{
+				Field:   "fields",
+				Rule:    "enum",
+				Value:   name,
+				Message: fmt.Sprintf("unknown issue field %q", name),
+			})
+			continue
+		}
+		selected[idx] = true
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	fields := make(IssueFields, 0, len(selected))
+	for i := 0; i < issueDTOType.NumField(); i++ {
+		if selected[i] {
+			fields = append(fields, i)
+		}
+	}
+	return fields, nil
+}
+
+// Issue returns dto itself, or a value that encodes only the selected fields.
+func (f IssueFields) Issue(dto IssueDTO) interface{} {
+	if f == nil {
+		return dto
+	}
+	return sparseIssue{dto: dto, fields: f}
+}
+
+// Apply returns v with every IssueDTO inside it (at any depth, in structs,
+// slices, maps or pointers) reduced to the selected fields. Values with no
+// issues inside are returned unchanged, so Apply composes with any handler
+// payload. With a nil IssueFields it returns v as-is.
+func (f IssueFields) Apply(v interface{}) interface{} {
+	if f == nil || v == nil {
+		return v
+	}
+	return f.apply(reflect.ValueOf(v))
+}
+
+func (f IssueFields) apply(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	if !containsIssueDTO(v.Type()) {
+		return v.Interface()
+	}
+
+	switch v.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() {
+			return nil
+		}
+		return f.apply(v.Elem())
+	case reflect.Struct:
+		if v.Type() == issueDTOType {
+			return sparseIssue{dto: v.Interface().(IssueDTO), fields: f}
+		}
+		out := make(map[string]interface{}, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			sf := v.Type().Field(i)
+			name := jsonFieldName(sf)
+			if !sf.IsExported() || name == "-" {
+				continue
+			}
+			fv := v.Field(i)
+			if strings.Contains(sf.Tag.Get("json"), ",omitempty") && isEmptyJSONValue(fv) {
+				continue
+			}
+			if sf.Anonymous && sf.Tag.Get("json") == "" {
+				// Embedded structs are flattened, as encoding/json does
+				if embedded, ok := f.apply(fv).(map[string]interface{}); ok {
+					for k, ev := range embedded {
+						out[k] = ev
+					}
+					continue
+				}
+			}
+			out[name] = f.apply(fv)
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		out := make([]interface{}, v.Len())
+		for i := range out {
+			out[i] = f.apply(v.Index(i))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		out := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out[fmt.Sprint(iter.Key().Interface())] = f.apply(iter.Value())
+		}
+		return out
+	}
+	return v.Interface()
+}
+
+// sparseIssue encodes the selected IssueDTO fields in declaration order.
+type sparseIssue struct {
+	dto    IssueDTO
+	fields IssueFields
+}
+
+func (s sparseIssue) MarshalJSON() ([]byte, error) {
+	v := reflect.ValueOf(s.dto)
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, idx := range s.fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(jsonFieldName(issueDTOType.Field(idx)))
+		val, err := json.Marshal(v.Field(idx).Interface())
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// containsIssueCache memoizes containsIssueDTO by type
+var containsIssueCache sync.Map
+
+// containsIssueDTO reports whether values of t can hold an IssueDTO.
+// Interfaces are assumed to, since their dynamic type is unknown.
+func containsIssueDTO(t reflect.Type) bool {
+	if cached, ok := containsIssueCache.Load(t); ok {
+		return cached.(bool)
+	}
+	found := typeContainsIssueDTO(t, map[reflect.Type]bool{})
+	containsIssueCache.Store(t, found)
+	return found
+}
+
+func typeContainsIssueDTO(t reflect.Type, visiting map[reflect.Type]bool) bool {
+	if visiting[t] {
+		return false // recursive type; answered by the outer call
+	}
+	visiting[t] = true
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return typeContainsIssueDTO(t.Elem(), visiting)
+	case reflect.Struct:
+		if t == issueDTOType {
+			return true
+		}
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).IsExported() && typeContainsIssueDTO(t.Field(i).Type, visiting) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jsonFieldName returns the encoding/json name of a struct field
+func jsonFieldName(sf reflect.StructField) string {
+	name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	if name == "" {
+		return sf.Name
+	}
+	return name
+}
+
+// isEmptyJSONValue mirrors encoding/json's omitempty test
+func isEmptyJSONValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return v.IsZero()
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	}
+	return false
+}
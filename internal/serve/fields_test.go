@@ -0,0 +1,88 @@
+package serve
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestParseIssueFields(t *testing.T) {
+	fields, errs := ParseIssueFields(url.Values{"fields": {"status, title", "priority"}})
+	if len(errs) > 0 {
+		t.Fatalf("errs = %+v", errs)
+	}
+	data, _ := json.Marshal(fields.Issue(IssueDTO{ID: "td-1", Title: "T", Status: "open", Priority: "P1", Description: "long"}))
+	if string(data) != `{"id":"td-1","title":"T","status":"open","priority":"P1"}` {
+		t.Errorf("sparse issue = %s", data)
+	}
+
+	if fields, errs := ParseIssueFields(url.Values{}); fields != nil || errs != nil {
+		t.Errorf("empty fields = %v, %v", fields, errs)
+	}
+	if _, errs := ParseIssueFields(url.Values{"fields": {"title,bogus"}}); len(errs) != 1 || errs[0].Value != "bogus" {
+		t.Errorf("unknown field errs = %+v", errs)
+	}
+}
+
+func TestIssueFieldsApply_Monitor(t *testing.T) {
+	fields, _ := ParseIssueFields(url.Values{"fields": {"title"}})
+	dto := MonitorDTO{
+		FocusedIssue: &IssueDTO{ID: "td-1", Title: "Focused", Description: "x"},
+		InProgress:   []IssueDTO{{ID: "td-2", Title: "Working", Description: "y"}},
+		TaskList:     TaskListDTO{Ready: []IssueDTO{{ID: "td-3", Title: "Ready", Description: "z"}}, Blocked: []IssueDTO{}},
+		Timestamp:    "2026-01-01T00:00:00Z",
+	}
+	data, err := json.Marshal(fields.Apply(dto))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		FocusedIssue map[string]interface{}   `json:"focused_issue"`
+		InProgress   []map[string]interface{} `json:"in_progress"`
+		TaskList     struct {
+			Ready   []map[string]interface{} `json:"ready"`
+			Blocked []map[string]interface{} `json:"blocked"`
+		} `json:"task_list"`
+		Timestamp string `json:"timestamp"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("unmarshal %s: %v", data, err)
+	}
+	for _, issue := range []map[string]interface{}{got.FocusedIssue, got.InProgress[0], got.TaskList.Ready[0]} {
+		if len(issue) != 2 || issue["title"] == nil {
+			t.Errorf("sparse issue = %v", issue)
+		}
+	}
+	if got.TaskList.Blocked == nil || got.Timestamp == "" {
+		t.Errorf("non-issue fields lost: %s", data)
+	}
+}
+
+func TestListIssues_Fields(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	if err := srv.db.CreateIssue(&models.Issue{Title: "Sparse list issue", Description: "not wanted"}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, env := doJSON(t, ts, "GET", "/v1/issues?fields=title,status", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d", resp.StatusCode)
+	}
+	issues := env.Data.(map[string]interface{})["issues"].([]interface{})
+	issue := issues[0].(map[string]interface{})
+	if len(issue) != 3 || issue["title"] != "Sparse list issue" {
+		t.Errorf("issue = %v", issue)
+	}
+
+	resp, _ = doJSON(t, ts, "GET", "/v1/monitor?fields=nope", nil)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("bad field status = %d", resp.StatusCode)
+	}
+}
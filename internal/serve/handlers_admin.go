@@ -0,0 +1,155 @@
+package serve
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/admin"
+	"github.com/marcus/td/internal/dateparse"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+// ============================================================================
+// POST /v1/admin/issues/{id}/reassign
+// ============================================================================
+
+// AdminReassignBody is the request body for handing an issue to another
+// implementer
+type AdminReassignBody struct {
+	Implementer string `json:"implementer"`
+	Reason      string `json:"reason"`
+}
+
+// handleAdminReassign replaces an issue's implementer on behalf of the
+// current one, recording the impersonation
+func (s *Server) handleAdminReassign(w http.ResponseWriter, r *http.Request) {
+	var body AdminReassignBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		WriteError(w, ErrValidation, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var errs []FieldError
+	if body.Implementer == "" {
+		errs = append(errs, FieldError{Field: "implementer", Rule: "required", Message: "implementer is required"})
+	} else if sess, err := s.db.GetSessionByID(body.Implementer); err == nil && sess == nil {
+		errs = append(errs, FieldError{Field: "implementer", Rule: "exists", Value: body.Implementer, Message: "session not found"})
+	}
+	if strings.TrimSpace(body.Reason) == "" {
+		errs = append(errs, FieldError{Field: "reason", Rule: "required", Message: "reason is required"})
+	}
+	if len(errs) > 0 {
+		WriteValidation(w, errs)
+		return
+	}
+
+	issue, ok := s.adminIssue(w, r)
+	if !ok {
+		return
+	}
+	if issue.ImplementerSession == body.Implementer {
+		WriteError(w, ErrConflict, fmt.Sprintf("%s is already implemented by %s", issue.ID, body.Implementer), http.StatusConflict)
+		return
+	}
+
+	res, err := admin.Reassign(s.db, issue.ID, body.Implementer, s.requestSession(r), body.Reason)
+	s.writeAdminResult(w, r, res, err)
+}
+
+// ============================================================================
+// POST /v1/admin/issues/{id}/clear-reviewer
+// ============================================================================
+
+// AdminClearReviewerBody is the request body for clearing a reviewer
+type AdminClearReviewerBody struct {
+	Reason string `json:"reason"`
+}
+
+// handleAdminClearReviewer removes an issue's reviewer on their behalf,
+// recording the impersonation
+func (s *Server) handleAdminClearReviewer(w http.ResponseWriter, r *http.Request) {
+	var body AdminClearReviewerBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		WriteError(w, ErrValidation, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(body.Reason) == "" {
+		WriteValidation(w, []FieldError{{Field: "reason", Rule: "required", Message: "reason is required"}})
+		return
+	}
+
+	issue, ok := s.adminIssue(w, r)
+	if !ok {
+		return
+	}
+	if issue.ReviewerSession == "" {
+		WriteError(w, ErrConflict, issue.ID+" has no reviewer", http.StatusConflict)
+		return
+	}
+
+	res, err := admin.ClearReviewer(s.db, issue.ID, s.requestSession(r), body.Reason)
+	s.writeAdminResult(w, r, res, err)
+}
+
+// adminIssue loads the issue named in the path, writing a 404 if it does
+// not exist
+func (s *Server) adminIssue(w http.ResponseWriter, r *http.Request) (*models.Issue, bool) {
+	id := r.PathValue("id")
+	issue, err := s.db.GetIssue(id)
+	if err != nil {
+		WriteError(w, ErrNotFound, "issue not found: "+id, http.StatusNotFound)
+		return nil, false
+	}
+	return issue, true
+}
+
+func (s *Server) writeAdminResult(w http.ResponseWriter, r *http.Request, res *admin.Result, err error) {
+	if err != nil {
+		if writeRejection(w, err) {
+			return
+		}
+		requestLog(r).Error("admin repair", "err", err, "issue", r.PathValue("id"))
+		WriteError(w, ErrInternal, "failed to apply repair", http.StatusInternalServerError)
+		return
+	}
+	im := res.Impersonation
+	requestLog(r).Warn("admin impersonation", "action", im.Action, "issue", im.IssueID,
+		"actor", im.ActorSession, "as", im.AsSession, "reason", im.Reason)
+	s.NotifyChange(r)
+	WriteSuccess(w, map[string]interface{}{
+		"issue":         IssueToDTO(res.Issue),
+		"impersonation": ImpersonationToDTO(*im),
+	}, http.StatusOK)
+}
+
+// ============================================================================
+// GET /v1/admin/impersonations
+// ============================================================================
+
+// handleImpersonations lists admin repairs made on behalf of other
+// sessions, newest first. ?issue= and ?session= narrow the list;
+// ?session= matches the admin or the session acted for.
+func (s *Server) handleImpersonations(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := db.ImpersonationFilter{IssueID: q.Get("issue"), Session: q.Get("session")}
+	if v := q.Get("since"); v != "" {
+		since, err := time.ParseInLocation("2006-01-02", v, dateparse.Location())
+		if err != nil {
+			WriteValidation(w, []FieldError{{Field: "since", Rule: "date", Value: v, Message: "since must be a YYYY-MM-DD date"}})
+			return
+		}
+		filter.Since = since
+	}
+
+	records, err := s.db.ListImpersonations(filter)
+	if err != nil {
+		requestLog(r).Error("list impersonations", "err", err)
+		WriteError(w, ErrInternal, "failed to list impersonations", http.StatusInternalServerError)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"impersonations": ImpersonationsToDTOs(records)}, http.StatusOK)
+}
@@ -0,0 +1,101 @@
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+func TestAdminRepairs(t *testing.T) {
+	tmpDir := t.TempDir()
+	database, err := db.Initialize(tmpDir)
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+
+	srv := NewServer(database, tmpDir, "ses_web", ServeConfig{Token: "server-secret"})
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	if err := database.UpsertSession(&db.SessionRow{ID: "ses_new", StartedAt: time.Now(), LastActivity: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	issue := &models.Issue{Title: "Stuck review of cache rework"}
+	if err := database.CreateIssue(issue); err != nil {
+		t.Fatal(err)
+	}
+	issue.Status = models.StatusInReview
+	issue.ImplementerSession = "ses_gone"
+	issue.ReviewerSession = "ses_rev"
+	if err := database.UpdateIssue(issue); err != nil {
+		t.Fatal(err)
+	}
+
+	writer, err := database.CreateAPIToken(&models.APIToken{SessionID: "ses_agent", Scopes: []string{"read", "write"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	adminTok, err := database.CreateAPIToken(&models.APIToken{SessionID: "ses_admin", Scopes: []string{"admin"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reassign := "/v1/admin/issues/" + issue.ID + "/reassign"
+	body := map[string]string{"implementer": "ses_new", "reason": "agent crashed mid-task"}
+
+	// Read and write scopes do not reach admin endpoints
+	if resp, _ := doWithToken(t, ts, writer, "POST", reassign, body); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("write token status = %d, want 403", resp.StatusCode)
+	}
+	if resp, _ := doWithToken(t, ts, writer, "GET", "/v1/admin/impersonations", nil); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("write token list status = %d, want 403", resp.StatusCode)
+	}
+
+	if resp, env := doWithToken(t, ts, adminTok, "POST", reassign, map[string]string{"implementer": "ses_nobody"}); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("invalid body status = %d: %+v", resp.StatusCode, env.Error)
+	}
+
+	resp, env := doWithToken(t, ts, adminTok, "POST", reassign, body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("reassign status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	im := env.Data.(map[string]interface{})["impersonation"].(map[string]interface{})
+	if im["actor_session"] != "ses_admin" || im["as_session"] != "ses_gone" || im["after"] != "ses_new" {
+		t.Errorf("impersonation = %v", im)
+	}
+	if got, _ := database.GetIssue(issue.ID); got.ImplementerSession != "ses_new" {
+		t.Errorf("implementer = %q, want ses_new", got.ImplementerSession)
+	}
+	if resp, _ := doWithToken(t, ts, adminTok, "POST", reassign, body); resp.StatusCode != http.StatusConflict {
+		t.Errorf("repeat reassign status = %d, want 409", resp.StatusCode)
+	}
+
+	// The server token keeps full access
+	clear := "/v1/admin/issues/" + issue.ID + "/clear-reviewer"
+	if resp, env := doWithToken(t, ts, "server-secret", "POST", clear, map[string]string{"reason": "reviewer left"}); resp.StatusCode != http.StatusOK {
+		t.Fatalf("clear reviewer status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	if resp, _ := doWithToken(t, ts, adminTok, "POST", clear, map[string]string{"reason": "again"}); resp.StatusCode != http.StatusConflict {
+		t.Errorf("repeat clear status = %d, want 409", resp.StatusCode)
+	}
+	if resp, _ := doWithToken(t, ts, adminTok, "POST", "/v1/admin/issues/td-nope/clear-reviewer", map[string]string{"reason": "x"}); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown issue status = %d, want 404", resp.StatusCode)
+	}
+
+	resp, env = doWithToken(t, ts, adminTok, "GET", "/v1/admin/impersonations?session=ses_admin", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("list status = %d", resp.StatusCode)
+	}
+	if list := env.Data.(map[string]interface{})["impersonations"].([]interface{}); len(list) != 1 {
+		t.Errorf("impersonations by ses_admin = %v", list)
+	}
+	_, env = doWithToken(t, ts, adminTok, "GET", "/v1/admin/impersonations?issue="+issue.ID, nil)
+	if list := env.Data.(map[string]interface{})["impersonations"].([]interface{}); len(list) != 2 {
+		t.Errorf("impersonations on issue = %d, want 2", len(list))
+	}
+}
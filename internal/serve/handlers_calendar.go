@@ -0,0 +1,117 @@
+package serve
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/ical"
+	"github.com/marcus/td/internal/models"
+)
+
+// calendarPath is the iCal feed route. Calendar clients cannot send an
+// Authorization header, so authMiddleware also accepts ?token= here.
+const calendarPath = "/v1/calendar.ics"
+
+// Calendar event kinds selectable with ?events=
+const (
+	calendarEventDue    = "due"
+	calendarEventDefer  = "defer"
+	calendarEventSprint = "sprint"
+)
+
+// ============================================================================
+// GET /v1/calendar.ics — iCal Feed
+// ============================================================================
+
+// handleCalendar serves an iCalendar feed with due dates and defer
+// resurfacing dates of unclosed issues, plus configured sprint date ranges.
+// ?events=due,defer,sprint limits the feed to some kinds.
+func (s *Server) handleCalendar(w http.ResponseWriter, r *http.Request) {
+	kinds := map[string]bool{calendarEventDue: true, calendarEventDefer: true, calendarEventSprint: true}
+	if v := r.URL.Query().Get("events"); v != "" {
+		kinds = map[string]bool{}
+		for _, k := range strings.Split(v, ",") {
+			k = strings.TrimSpace(k)
+			if k != calendarEventDue && k != calendarEventDefer && k != calendarEventSprint {
+				WriteError(w, ErrValidation, fmt.Sprintf("invalid event kind: %s (due, defer, sprint)", k), http.StatusBadRequest)
+				return
+			}
+			kinds[k] = true
+		}
+	}
+
+	cal := &ical.Calendar{Name: "td: " + config.GetProjectName(s.baseDir)}
+
+	if kinds[calendarEventDue] || kinds[calendarEventDefer] {
+		issues, err := s.db.ListIssues(db.ListIssuesOptions{
+			Status: []models.Status{models.StatusOpen, models.StatusInProgress, models.StatusBlocked, models.StatusInReview},
+		})
+		if err != nil {
+			requestLog(r).Error("calendar list issues", "err", err)
+			WriteError(w, ErrInternal, "failed to list issues", http.StatusInternalServerError)
+			return
+		}
+		for _, issue := range issues {
+			if kinds[calendarEventDue] {
+				if d, ok := parseCalendarDate(issue.DueDate); ok {
+					cal.Events = append(cal.Events, issueEvent(issue, "due", "Due", d))
+				}
+			}
+			if kinds[calendarEventDefer] {
+				if d, ok := parseCalendarDate(issue.DeferUntil); ok {
+					cal.Events = append(cal.Events, issueEvent(issue, "defer", "Resurfaces", d))
+				}
+			}
+		}
+	}
+
+	if kinds[calendarEventSprint] {
+		sprints, err := config.GetSprints(s.baseDir)
+		if err != nil {
+			requestLog(r).Warn("calendar load sprints", "err", err)
+		}
+		for _, sp := range sprints {
+			start, err1 := time.Parse("2006-01-02", sp.Start)
+			end, err2 := time.Parse("2006-01-02", sp.End)
+			if err1 != nil || err2 != nil {
+				continue
+			}
+			cal.Events = append(cal.Events, ical.Event{
+				UID:        "sprint-" + sp.Name + "@td",
+				Summary:    "Sprint: " + sp.Name,
+				Start:      start,
+				End:        end,
+				Categories: []string{"sprint"},
+			})
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="td.ics"`)
+	if err := cal.Write(w); err != nil {
+		requestLog(r).Error("write calendar", "err", err)
+	}
+}
+
+// issueEvent builds a one-day event for an issue date
+func issueEvent(issue models.Issue, kind, label string, day time.Time) ical.Event {
+	return ical.Event{
+		UID:         fmt.Sprintf("%s-%s@td", kind, issue.ID),
+		Summary:     fmt.Sprintf("%s: %s %s", label, issue.ID, issue.Title),
+		Description: fmt.Sprintf("%s %s %s", issue.Priority, issue.Type, issue.Status),
+		Start:       day,
+		Categories:  []string{kind, string(issue.Priority)},
+	}
+}
+
+func parseCalendarDate(s *string) (time.Time, bool) {
+	if s == nil || *s == "" {
+		return time.Time{}, false
+	}
+	d, err := time.Parse("2006-01-02", *s)
+	return d, err == nil
+}
@@ -0,0 +1,97 @@
+package serve
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+func getCalendar(t *testing.T, url string) (*http.Response, string) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp, string(body)
+}
+
+func TestCalendarFeed(t *testing.T) {
+	tmpDir := t.TempDir()
+	database, err := db.Initialize(tmpDir)
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+
+	due, deferUntil := "2030-05-01", "2030-04-15"
+	database.CreateIssue(&models.Issue{Title: "Ship the release notes", DueDate: &due, DeferUntil: &deferUntil})
+	database.CreateIssue(&models.Issue{Title: "Already finished work", DueDate: &due, Status: models.StatusClosed})
+	if err := config.SetSprint(tmpDir, models.Sprint{Name: "s7", Start: "2030-04-07", End: "2030-04-18"}); err != nil {
+		t.Fatalf("SetSprint: %v", err)
+	}
+
+	srv := NewServer(database, tmpDir, "ses_test123", ServeConfig{})
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	resp, body := getCalendar(t, ts.URL+"/v1/calendar.ics")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d: %s", resp.StatusCode, body)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/calendar") {
+		t.Errorf("content type = %q", ct)
+	}
+	for _, want := range []string{
+		"SUMMARY:Due: ",
+		"Ship the release notes",
+		"DTSTART;VALUE=DATE:20300501",
+		"SUMMARY:Resurfaces: ",
+		"SUMMARY:Sprint: s7",
+		"DTEND;VALUE=DATE:20300419",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("feed missing %q", want)
+		}
+	}
+	if strings.Contains(body, "Already finished work") {
+		t.Error("closed issues should be excluded")
+	}
+
+	_, body = getCalendar(t, ts.URL+"/v1/calendar.ics?events=sprint")
+	if strings.Contains(body, "Due:") || !strings.Contains(body, "Sprint: s7") {
+		t.Errorf("events=sprint feed:\n%s", body)
+	}
+
+	resp, _ = getCalendar(t, ts.URL+"/v1/calendar.ics?events=bogus")
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("bogus events status = %d", resp.StatusCode)
+	}
+}
+
+func TestCalendarFeed_QueryToken(t *testing.T) {
+	srv := NewServer(setupTestDB(t), t.TempDir(), "ses_test123", ServeConfig{Token: "secret-token"})
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	if resp, _ := getCalendar(t, ts.URL+"/v1/calendar.ics"); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("no token: status = %d", resp.StatusCode)
+	}
+	if resp, _ := getCalendar(t, ts.URL+"/v1/calendar.ics?token=wrong"); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("wrong token: status = %d", resp.StatusCode)
+	}
+	if resp, _ := getCalendar(t, ts.URL+"/v1/calendar.ics?token=secret-token"); resp.StatusCode != http.StatusOK {
+		t.Errorf("query token: status = %d", resp.StatusCode)
+	}
+	// Query tokens are only honored for the calendar feed
+	if resp, _ := getCalendar(t, ts.URL+"/v1/issues?token=secret-token"); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("query token on other route: status = %d", resp.StatusCode)
+	}
+}
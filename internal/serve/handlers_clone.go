@@ -0,0 +1,150 @@
+package serve
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/marcus/td/internal/clone"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/git"
+	"github.com/marcus/td/internal/query"
+)
+
+// CloneBody is the optional JSON body for cloning. The booleans choose
+// what the clones carry over besides title, description, type, priority,
+// points and sprint. IDs, Query and FromSprint pick the issues of a bulk
+// clone and are ignored when cloning a single issue.
+type CloneBody struct {
+	Checklist  bool     `json:"checklist"`
+	Labels     bool     `json:"labels"`
+	Links      bool     `json:"links"`
+	Children   bool     `json:"children"`
+	Sprint     string   `json:"sprint"`
+	ParentID   string   `json:"parent_id"`
+	IDs        []string `json:"ids"`
+	Query      string   `json:"query"`
+	FromSprint string   `json:"from_sprint"`
+}
+
+// options converts the body to clone options
+func (b CloneBody) options() clone.Options {
+	opts := clone.Options{
+		Checklist: b.Checklist,
+		Labels:    b.Labels,
+		Links:     b.Links,
+		Children:  b.Children,
+		Sprint:    strings.TrimSpace(b.Sprint),
+		Parent:    db.NormalizeIssueID(strings.TrimSpace(b.ParentID)),
+	}
+	if gitState, _ := git.GetState(); gitState != nil {
+		opts.CreatedBranch = gitState.Branch
+		opts.CreatedRepo = gitState.Repo
+	}
+	return opts
+}
+
+// ============================================================================
+// POST /v1/issues/{id}/clone
+// ============================================================================
+
+// handleCloneIssue copies one issue, and with children its subtree.
+func (s *Server) handleCloneIssue(w http.ResponseWriter, r *http.Request) {
+	var body CloneBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		WriteError(w, ErrValidation, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	issueID := r.PathValue("id")
+	if _, err := s.db.GetIssue(issueID); err != nil {
+		WriteError(w, ErrNotFound, fmt.Sprintf("issue not found: %s", issueID), http.StatusNotFound)
+		return
+	}
+	s.writeClones(w, r, []string{issueID}, body)
+}
+
+// ============================================================================
+// POST /v1/issues/clone
+// ============================================================================
+
+// handleBulkClone copies the issues named by ids plus those matching query
+// and from_sprint, e.g. last sprint's recurring rituals into the next one.
+func (s *Server) handleBulkClone(w http.ResponseWriter, r *http.Request) {
+	var body CloneBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		WriteError(w, ErrValidation, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var ids []string
+	var errs []FieldError
+	for _, raw := range body.IDs {
+		id := db.NormalizeIssueID(strings.TrimSpace(raw))
+		if id == "" {
+			continue
+		}
+		if _, err := s.db.GetIssue(id); err != nil {
+			errs = append(errs, FieldError{Field: "ids", Rule: "exists", Value: raw, Message: "issue not found: " + raw})
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if tdq := clone.SelectQuery(strings.TrimSpace(body.Query), strings.TrimSpace(body.FromSprint)); tdq != "" {
+		issues, err := query.Execute(s.db, tdq, s.requestSession(r), query.ExecuteOptions{})
+		if err != nil {
+			errs = append(errs, FieldError{Field: "query", Rule: "tdq", Value: tdq, Message: err.Error()})
+		}
+		for _, issue := range issues {
+			ids = append(ids, issue.ID)
+		}
+	}
+	if len(errs) > 0 {
+		WriteValidation(w, errs)
+		return
+	}
+	if len(ids) == 0 {
+		WriteValidation(w, []FieldError{{Field: "ids", Rule: "required", Message: "no issues to clone: give ids, query or from_sprint"}})
+		return
+	}
+	s.writeClones(w, r, ids, body)
+}
+
+// writeClones clones ids and writes the clones in source order. Clones
+// made before a failure are kept.
+func (s *Server) writeClones(w http.ResponseWriter, r *http.Request, ids []string, body CloneBody) {
+	result, err := clone.Clone(s.db, ids, body.options(), s.requestSession(r))
+	if result != nil && len(result.Clones) > 0 {
+		s.NotifyChange(r)
+	}
+	if err != nil {
+		if writeRejection(w, err) {
+			return
+		}
+		if body.ParentID != "" && strings.Contains(err.Error(), "not found") {
+			WriteValidation(w, []FieldError{{Field: "parent_id", Rule: "exists", Value: body.ParentID, Message: err.Error()}})
+			return
+		}
+		requestLog(r).Error("clone issues", "err", err)
+		WriteError(w, ErrInternal, "failed to clone: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	issues := make([]IssueDTO, 0, len(result.Clones))
+	for _, p := range result.Clones {
+		issue, err := s.db.GetIssue(p.Clone)
+		if err != nil {
+			continue
+		}
+		issues = append(issues, IssueToDTO(issue))
+	}
+	WriteSuccess(w, map[string]interface{}{
+		"clones":       result.Clones,
+		"issues":       issues,
+		"dependencies": result.Dependencies,
+		"files":        result.Files,
+	}, http.StatusCreated)
+}
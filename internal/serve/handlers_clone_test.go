@@ -0,0 +1,82 @@
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestCloneIssue(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	epic := &models.Issue{Title: "Sprint review ritual", Type: models.TypeEpic, Sprint: "24", Labels: []string{"ritual"}}
+	if err := srv.db.CreateIssue(epic); err != nil {
+		t.Fatal(err)
+	}
+	child := &models.Issue{Title: "Record the demo", ParentID: epic.ID, Sprint: "24", Labels: []string{"ritual"}}
+	if err := srv.db.CreateIssue(child); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, env := doJSON(t, ts, "POST", "/v1/issues/"+epic.ID+"/clone", nil)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("clone status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	issues := env.Data.(map[string]interface{})["issues"].([]interface{})
+	if len(issues) != 1 || len(issues[0].(map[string]interface{})["labels"].([]interface{})) != 0 {
+		t.Errorf("plain clone = %v, want one issue without labels", issues)
+	}
+
+	resp, _ = doJSON(t, ts, "POST", "/v1/issues/td-missing/clone", nil)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("missing issue status = %d", resp.StatusCode)
+	}
+}
+
+func TestBulkClone(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	epic := &models.Issue{Title: "Sprint review ritual", Type: models.TypeEpic, Sprint: "24", Labels: []string{"ritual"}}
+	if err := srv.db.CreateIssue(epic); err != nil {
+		t.Fatal(err)
+	}
+	for _, issue := range []*models.Issue{
+		{Title: "Record the demo", ParentID: epic.ID, Sprint: "24", Labels: []string{"ritual"}},
+		{Title: "Fix the flaky login test", Sprint: "24"},
+	} {
+		if err := srv.db.CreateIssue(issue); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resp, env := doJSON(t, ts, "POST", "/v1/issues/clone", map[string]interface{}{
+		"from_sprint": "24", "query": "labels ~ ritual", "sprint": "25", "labels": true,
+	})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("bulk clone status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	issues := env.Data.(map[string]interface{})["issues"].([]interface{})
+	if len(issues) != 2 {
+		t.Fatalf("bulk clone = %v, want the 2 ritual issues", issues)
+	}
+	newEpic := issues[0].(map[string]interface{})
+	newChild := issues[1].(map[string]interface{})
+	if newEpic["sprint"] != "25" || newChild["parent_id"] != newEpic["id"] {
+		t.Errorf("clones = %v / %v, want sprint 25 with the child under the epic's clone", newEpic, newChild)
+	}
+
+	resp, _ = doJSON(t, ts, "POST", "/v1/issues/clone", map[string]interface{}{"ids": []string{"td-missing"}})
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("unknown id status = %d", resp.StatusCode)
+	}
+	resp, _ = doJSON(t, ts, "POST", "/v1/issues/clone", map[string]interface{}{})
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("empty selection status = %d", resp.StatusCode)
+	}
+}
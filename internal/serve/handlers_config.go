@@ -0,0 +1,80 @@
+package serve
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/marcus/td/pkg/monitor/keymap"
+)
+
+// ============================================================================
+// GET /v1/config/keymap
+// ============================================================================
+
+// KeymapDTO is the monitor's key remapping: the user's bindings from
+// .todos/keymap.json, what is wrong with them, and with ?defaults=true the
+// default bindings they override.
+type KeymapDTO struct {
+	Bindings map[string]string `json:"bindings"`
+	Problems []keymap.Problem  `json:"problems"`
+	Defaults []keymap.Binding  `json:"defaults,omitempty"`
+}
+
+// keymapDTO builds the response for cfg
+func keymapDTO(cfg *keymap.Config, defaults bool) KeymapDTO {
+	dto := KeymapDTO{Bindings: cfg.Bindings, Problems: keymap.Check(cfg)}
+	if dto.Problems == nil {
+		dto.Problems = []keymap.Problem{}
+	}
+	if defaults {
+		dto.Defaults = keymap.DefaultBindings()
+	}
+	return dto
+}
+
+// handleGetKeymap returns the project's key remapping.
+func (s *Server) handleGetKeymap(w http.ResponseWriter, r *http.Request) {
+	cfg, err := keymap.LoadConfig(keymap.ConfigPath(s.baseDir))
+	if err != nil {
+		requestLog(r).Error("load keymap", "err", err)
+		WriteError(w, ErrInternal, "failed to load keymap", http.StatusInternalServerError)
+		return
+	}
+	WriteSuccess(w, keymapDTO(cfg, r.URL.Query().Get("defaults") == "true"), http.StatusOK)
+}
+
+// ============================================================================
+// PUT /v1/config/keymap
+// ============================================================================
+
+// handleSetKeymap replaces the project's key remapping. Bindings the
+// monitor would skip are rejected; conflicts it would apply come back as
+// warnings in problems.
+func (s *Server) handleSetKeymap(w http.ResponseWriter, r *http.Request) {
+	var cfg keymap.Config
+	if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+		WriteError(w, ErrValidation, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if cfg.Bindings == nil {
+		cfg.Bindings = make(map[string]string)
+	}
+
+	var errs []FieldError
+	for _, p := range keymap.Check(&cfg) {
+		if p.Severity == keymap.SeverityError {
+			errs = append(errs, FieldError{Field: "bindings." + p.Binding, Rule: "keymap", Value: p.Command, Message: p.Message})
+		}
+	}
+	if len(errs) > 0 {
+		WriteValidation(w, errs)
+		return
+	}
+
+	if err := keymap.SaveConfig(keymap.ConfigPath(s.baseDir), &cfg); err != nil {
+		requestLog(r).Error("save keymap", "err", err)
+		WriteError(w, ErrInternal, "failed to save keymap", http.StatusInternalServerError)
+		return
+	}
+	WriteSuccess(w, keymapDTO(&cfg, false), http.StatusOK)
+}
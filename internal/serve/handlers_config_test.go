@@ -0,0 +1,66 @@
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marcus/td/pkg/monitor/keymap"
+)
+
+func TestKeymap_SetAndGet(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	resp, env := doJSON(t, ts, "GET", "/v1/config/keymap", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("get status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	if b := env.Data.(map[string]interface{})["bindings"].(map[string]interface{}); len(b) != 0 {
+		t.Errorf("bindings before set = %v", b)
+	}
+
+	resp, env = doJSON(t, ts, "PUT", "/v1/config/keymap", map[string]interface{}{
+		"bindings": map[string]string{"main:ö": "cursor-down", "main:j": "none"},
+	})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("put status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	if problems := env.Data.(map[string]interface{})["problems"].([]interface{}); len(problems) != 0 {
+		t.Errorf("problems = %v", problems)
+	}
+	cfg, err := keymap.LoadConfig(keymap.ConfigPath(srv.baseDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Bindings["main:ö"] != "cursor-down" {
+		t.Errorf("saved bindings = %v", cfg.Bindings)
+	}
+
+	_, env = doJSON(t, ts, "GET", "/v1/config/keymap?defaults=true", nil)
+	data := env.Data.(map[string]interface{})
+	if len(data["bindings"].(map[string]interface{})) != 2 || len(data["defaults"].([]interface{})) == 0 {
+		t.Errorf("keymap = %v", data)
+	}
+}
+
+func TestKeymap_RejectsInvalidBindings(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	resp, env := doJSON(t, ts, "PUT", "/v1/config/keymap", map[string]interface{}{
+		"bindings": map[string]string{"nowhere:x": "quit", "main:z": "fly"},
+	})
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", resp.StatusCode)
+	}
+	if env.Error == nil || env.Error.Code != ErrValidation {
+		t.Errorf("error = %+v", env.Error)
+	}
+	cfg, _ := keymap.LoadConfig(keymap.ConfigPath(srv.baseDir))
+	if len(cfg.Bindings) != 0 {
+		t.Errorf("invalid keymap was saved: %v", cfg.Bindings)
+	}
+}
@@ -0,0 +1,202 @@
+package serve
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+// ============================================================================
+// GET /v1/decisions
+// ============================================================================
+
+// handleListDecisions lists decisions newest first. ?issue_id= narrows to
+// decisions linked to one issue; ?search= matches title, context and
+// decision text.
+func (s *Server) handleListDecisions(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := db.DecisionFilter{Search: q.Get("search")}
+	if issueID := q.Get("issue_id"); issueID != "" {
+		filter.IssueID = db.NormalizeIssueID(issueID)
+	}
+
+	decisions, err := s.db.ListDecisions(filter)
+	if err != nil {
+		requestLog(r).Error("list decisions", "err", err)
+		WriteError(w, ErrInternal, "failed to list decisions", http.StatusInternalServerError)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"decisions": DecisionsToDTOs(decisions)}, http.StatusOK)
+}
+
+// ============================================================================
+// GET /v1/decisions/{id}
+// ============================================================================
+
+// handleGetDecision returns one decision with its linked issue IDs.
+func (s *Server) handleGetDecision(w http.ResponseWriter, r *http.Request) {
+	decision, ok := s.lookupDecision(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"decision": DecisionToDTO(decision)}, http.StatusOK)
+}
+
+// ============================================================================
+// POST /v1/decisions
+// ============================================================================
+
+// DecisionBody is the JSON body for creating or updating a decision. On
+// update, omitted fields are left unchanged and issue_ids replaces the
+// existing links.
+type DecisionBody struct {
+	Title    *string   `json:"title"`
+	Context  *string   `json:"context"`
+	Options  *[]string `json:"options"`
+	Decision *string   `json:"decision"`
+	IssueIDs *[]string `json:"issue_ids"`
+}
+
+// handleCreateDecision records a decision.
+func (s *Server) handleCreateDecision(w http.ResponseWriter, r *http.Request) {
+	var body DecisionBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		WriteError(w, ErrValidation, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	decision := &models.Decision{SessionID: s.requestSession(r)}
+	if body.Title == nil {
+		body.Title = new(string)
+	}
+	if body.Decision == nil {
+		body.Decision = new(string)
+	}
+	if errs := s.applyDecisionBody(decision, body); len(errs) > 0 {
+		WriteValidation(w, errs)
+		return
+	}
+
+	if err := s.db.CreateDecision(decision); err != nil {
+		requestLog(r).Error("create decision", "err", err)
+		WriteError(w, ErrInternal, "failed to create decision", http.StatusInternalServerError)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"decision": DecisionToDTO(decision)}, http.StatusCreated)
+}
+
+// ============================================================================
+// PATCH /v1/decisions/{id}
+// ============================================================================
+
+// handleUpdateDecision updates the fields present in the body.
+func (s *Server) handleUpdateDecision(w http.ResponseWriter, r *http.Request) {
+	var body DecisionBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		WriteError(w, ErrValidation, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	decision, ok := s.lookupDecision(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+	if errs := s.applyDecisionBody(decision, body); len(errs) > 0 {
+		WriteValidation(w, errs)
+		return
+	}
+
+	if err := s.db.UpdateDecision(decision); err != nil {
+		requestLog(r).Error("update decision", "err", err, "id", decision.ID)
+		WriteError(w, ErrInternal, "failed to update decision", http.StatusInternalServerError)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"decision": DecisionToDTO(decision)}, http.StatusOK)
+}
+
+// ============================================================================
+// DELETE /v1/decisions/{id}
+// ============================================================================
+
+// handleDeleteDecision removes a decision and its issue links.
+func (s *Server) handleDeleteDecision(w http.ResponseWriter, r *http.Request) {
+	decision, ok := s.lookupDecision(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+	if err := s.db.DeleteDecision(decision.ID); err != nil {
+		requestLog(r).Error("delete decision", "err", err, "id", decision.ID)
+		WriteError(w, ErrInternal, "failed to delete decision", http.StatusInternalServerError)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"deleted": true}, http.StatusOK)
+}
+
+// lookupDecision fetches a decision, writing a 404 or 500 when it can't.
+func (s *Server) lookupDecision(w http.ResponseWriter, id string) (*models.Decision, bool) {
+	decision, err := s.db.GetDecision(id)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			WriteError(w, ErrNotFound, "decision not found: "+id, http.StatusNotFound)
+		} else {
+			slog.Error("get decision", "err", err, "id", id)
+			WriteError(w, ErrInternal, "failed to fetch decision", http.StatusInternalServerError)
+		}
+		return nil, false
+	}
+	return decision, true
+}
+
+// applyDecisionBody validates the body and copies the fields it sets onto
+// decision. Linked issues must exist.
+func (s *Server) applyDecisionBody(decision *models.Decision, body DecisionBody) []FieldError {
+	var errs []FieldError
+	if body.Title != nil {
+		decision.Title = strings.TrimSpace(*body.Title)
+		if decision.Title == "" {
+			errs = append(errs, FieldError{Field: "title", Rule: "required", Message: "title is required"})
+		}
+	}
+	if body.Decision != nil {
+		decision.Decision = strings.TrimSpace(*body.Decision)
+		if decision.Decision == "" {
+			errs = append(errs, FieldError{Field: "decision", Rule: "required", Message: "decision is required"})
+		}
+	}
+	if body.Context != nil {
+		decision.Context = strings.TrimSpace(*body.Context)
+	}
+	if body.Options != nil {
+		decision.Options = nil
+		for _, opt := range *body.Options {
+			if opt = strings.TrimSpace(opt); opt != "" {
+				decision.Options = append(decision.Options, opt)
+			}
+		}
+	}
+	if body.IssueIDs != nil {
+		decision.IssueIDs = nil
+		for _, raw := range *body.IssueIDs {
+			id := db.NormalizeIssueID(strings.TrimSpace(raw))
+			if id == "" {
+				continue
+			}
+			if _, err := s.db.GetIssue(id); err != nil {
+				errs = append(errs, FieldError{
+					Field:   "issue_ids",
+					Rule:    "exists",
+					Value:   raw,
+					Message: fmt.Sprintf("issue not found: %s", raw),
+				})
+				continue
+			}
+			decision.IssueIDs = append(decision.IssueIDs, id)
+		}
+	}
+	return errs
+}
@@ -0,0 +1,99 @@
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestDecisions_CRUD(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	issue := &models.Issue{Title: "Background jobs"}
+	if err := srv.db.CreateIssue(issue); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, env := doJSON(t, ts, "POST", "/v1/decisions", map[string]interface{}{"context": "no title"})
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("missing title/decision status = %d", resp.StatusCode)
+	}
+	resp, _ = doJSON(t, ts, "POST", "/v1/decisions", map[string]interface{}{
+		"title": "Queue", "decision": "postgres", "issue_ids": []string{"td-missing"},
+	})
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("unknown issue status = %d", resp.StatusCode)
+	}
+
+	resp, env = doJSON(t, ts, "POST", "/v1/decisions", map[string]interface{}{
+		"title":     "Queue backend",
+		"context":   "Workers need durable jobs",
+		"options":   []string{"redis", "postgres"},
+		"decision":  "postgres",
+		"issue_ids": []string{issue.ID},
+	})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	d := env.Data.(map[string]interface{})["decision"].(map[string]interface{})
+	id := d["id"].(string)
+	if d["decision"] != "postgres" || len(d["options"].([]interface{})) != 2 || len(d["issue_ids"].([]interface{})) != 1 {
+		t.Errorf("decision = %v", d)
+	}
+
+	resp, env = doJSON(t, ts, "PATCH", "/v1/decisions/"+id, map[string]interface{}{"decision": "redis streams", "issue_ids": []string{}})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("update status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	d = env.Data.(map[string]interface{})["decision"].(map[string]interface{})
+	if d["decision"] != "redis streams" || d["title"] != "Queue backend" || len(d["issue_ids"].([]interface{})) != 0 {
+		t.Errorf("updated decision = %v", d)
+	}
+
+	_, env = doJSON(t, ts, "GET", "/v1/decisions", nil)
+	if list := env.Data.(map[string]interface{})["decisions"].([]interface{}); len(list) != 1 {
+		t.Errorf("decisions = %v", list)
+	}
+	_, env = doJSON(t, ts, "GET", "/v1/decisions?issue_id="+issue.ID, nil)
+	if list := env.Data.(map[string]interface{})["decisions"].([]interface{}); len(list) != 0 {
+		t.Errorf("decisions for unlinked issue = %v", list)
+	}
+
+	resp, _ = doJSON(t, ts, "DELETE", "/v1/decisions/"+id, nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("delete status = %d", resp.StatusCode)
+	}
+	resp, _ = doJSON(t, ts, "GET", "/v1/decisions/"+id, nil)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("get deleted status = %d", resp.StatusCode)
+	}
+}
+
+func TestDecisions_IssueInclude(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	issue := &models.Issue{Title: "Background jobs"}
+	if err := srv.db.CreateIssue(issue); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.db.CreateDecision(&models.Decision{Title: "Queue", Decision: "postgres", IssueIDs: []string{issue.ID}}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, env := doJSON(t, ts, "GET", "/v1/issues/"+issue.ID, nil)
+	counts := env.Data.(map[string]interface{})["counts"].(map[string]interface{})
+	if counts["decisions"] != float64(1) {
+		t.Errorf("counts = %v", counts)
+	}
+
+	_, env = doJSON(t, ts, "GET", "/v1/issues/"+issue.ID+"?include=decisions", nil)
+	if list, _ := env.Data.(map[string]interface{})["decisions"].([]interface{}); len(list) != 1 {
+		t.Errorf("included decisions = %v", env.Data)
+	}
+}
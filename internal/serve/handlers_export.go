@@ -0,0 +1,183 @@
+package serve
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/query"
+)
+
+// exportMaxIssues caps how many issues one export can match. It is far past
+// the 1000-item page limit of GET /v1/issues; matching still happens in
+// memory before the first row is written.
+const exportMaxIssues = 1_000_000
+
+// exportIncludes are the collections an export can nest in each row
+var exportIncludes = []string{"comments", "logs"}
+
+// exportRow is one line of an NDJSON export. Collections are pointers so
+// an included but empty one still encodes as [].
+type exportRow struct {
+	Issue    interface{}   `json:"issue"`
+	Comments *[]CommentDTO `json:"comments,omitempty"`
+	Logs     *[]LogDTO     `json:"logs,omitempty"`
+}
+
+// ============================================================================
+// GET /v1/issues/export — Streaming Export
+// ============================================================================
+
+// handleExportIssues streams the issues matching ?query= as NDJSON, one
+// issue per line, with no pagination. ?include=comments,logs nests those
+// collections in each row; ?fields= and ?with_deleted= work as they do on
+// GET /v1/issues. Unlike the list, closed issues are only left out when the
+// query says so.
+func (s *Server) handleExportIssues(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	var errs []FieldError
+	if format := q.Get("format"); format != "" && format != "ndjson" {
+		errs = append(errs, FieldError{
+			Field:    "format",
+			Rule:     "enum",
+			Value:    format,
+			Expected: []string{"ndjson"},
+			Message:  fmt.Sprintf("unsupported format %q", format),
+		})
+	}
+
+	tdq := &query.Query{}
+	if raw := q.Get("query"); raw != "" {
+		parsed, err := query.Parse(raw)
+		if err == nil {
+			if verrs := parsed.Validate(); len(verrs) > 0 {
+				err = verrs[0]
+			}
+		}
+		if err != nil {
+			errs = append(errs, FieldError{Field: "query", Rule: "tdq", Value: raw, Message: "invalid TDQ query: " + err.Error()})
+		} else {
+			tdq = parsed
+		}
+	}
+
+	include := map[string]bool{}
+	for _, name := range strings.Split(strings.Join(q["include"], ","), ",") {
+		name = strings.TrimSpace(name)
+		switch {
+		case name == "":
+		case slices.Contains(exportIncludes, name):
+			include[name] = true
+		default:
+			errs = append(errs, FieldError{
+				Field:    "include",
+				Rule:     "enum",
+				Value:    name,
+				Expected: exportIncludes,
+				Message:  fmt.Sprintf("unknown include %q", name),
+			})
+		}
+	}
+
+	fields, fieldErrs := ParseIssueFields(q)
+	if errs = append(errs, fieldErrs...); len(errs) > 0 {
+		WriteValidation(w, errs)
+		return
+	}
+
+	issues, err := query.ExecuteQuery(s.db, tdq, s.requestSession(r), query.ExecuteOptions{
+		MaxResults:  exportMaxIssues,
+		WithDeleted: q.Get("with_deleted") == "true",
+	})
+	if err != nil {
+		WriteError(w, ErrInternal, "failed to export issues: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Large exports outlast the server's write timeout
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		requestLog(r).Debug("export: clear write deadline", "err", err)
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+
+	// Rows are encoded one at a time; the buffer goes out whenever it fills
+	bw := bufio.NewWriterSize(w, 32*1024)
+	enc := json.NewEncoder(bw)
+	for i := range issues {
+		issue := &issues[i]
+		row := exportRow{Issue: fields.Issue(IssueToDTO(issue))}
+		if include["comments"] {
+			comments, err := s.db.GetComments(issue.ID)
+			if err != nil {
+				requestLog(r).Error("export: get comments", "issue", issue.ID, "err", err)
+				break
+			}
+			dtos := commentsToDTOsNonNil(comments)
+			row.Comments = &dtos
+		}
+		if include["logs"] {
+			logs, err := s.db.GetLogs(issue.ID, 0)
+			if err != nil {
+				requestLog(r).Error("export: get logs", "issue", issue.ID, "err", err)
+				break
+			}
+			dtos := logsToDTOsNonNil(logs)
+			row.Logs = &dtos
+		}
+		if err := enc.Encode(row); err != nil {
+			requestLog(r).Error("export: write row", "err", err)
+			return
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		requestLog(r).Error("export: flush", "err", err)
+	}
+}
+
+// ============================================================================
+// GET /v1/export/sqlite — Database Snapshot
+// ============================================================================
+
+// handleExportSQLite sends a consistent read-only snapshot of the database,
+// taken with SQLite's online backup API, as a file download. It is the
+// server side of `td export sqlite`.
+func (s *Server) handleExportSQLite(w http.ResponseWriter, r *http.Request) {
+	dir, err := os.MkdirTemp("", "td-snapshot-")
+	if err != nil {
+		WriteError(w, ErrInternal, "failed to create snapshot: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "snapshot.db")
+	if err := s.db.Snapshot(path); err != nil {
+		WriteError(w, ErrInternal, "failed to create snapshot: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		WriteError(w, ErrInternal, "failed to read snapshot: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer f.Close()
+
+	// Large databases outlast the server's write timeout
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		requestLog(r).Debug("export: clear write deadline", "err", err)
+	}
+
+	taken := clock.Now().UTC()
+	w.Header().Set("Content-Type", "application/vnd.sqlite3")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="td-snapshot-%s.db"`, taken.Format("20060102-150405")))
+	http.ServeContent(w, r, "", taken, f)
+}
@@ -0,0 +1,109 @@
+package serve
+
+import (
+	"bufio"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExportIssues(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	bugID := createTestIssue(t, ts, "Crash when the config is empty")
+	doJSON(t, ts, "PATCH", "/v1/issues/"+bugID, map[string]string{"type": "bug"})
+	doJSON(t, ts, "POST", "/v1/issues/"+bugID+"/comments", map[string]string{"text": "Reproduced on main"})
+	taskID := createTestIssue(t, ts, "Document the export endpoint")
+	doJSON(t, ts, "POST", "/v1/issues/"+taskID+"/close", nil)
+
+	export := func(params string) []map[string]interface{} {
+		t.Helper()
+		resp, err := http.Get(ts.URL + "/v1/issues/export?" + params)
+		if err != nil {
+			t.Fatalf("GET export: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/x-ndjson" {
+			t.Fatalf("export %s: status = %d, content type = %q", params, resp.StatusCode, resp.Header.Get("Content-Type"))
+		}
+		var rows []map[string]interface{}
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			var row map[string]interface{}
+			if err := json.Unmarshal(scanner.Bytes(), &row); err != nil {
+				t.Fatalf("row %q: %v", scanner.Text(), err)
+			}
+			rows = append(rows, row)
+		}
+		return rows
+	}
+
+	// No query exports everything, closed issues included
+	if rows := export(""); len(rows) != 2 {
+		t.Errorf("export all = %d rows, want 2", len(rows))
+	}
+
+	rows := export("format=ndjson&include=comments,logs&fields=id,type&query=" + url.QueryEscape("type = bug"))
+	if len(rows) != 1 {
+		t.Fatalf("bug export = %d rows, want 1", len(rows))
+	}
+	issue := rows[0]["issue"].(map[string]interface{})
+	if issue["id"] != bugID || issue["title"] != nil {
+		t.Errorf("issue = %v, want only id and type of %s", issue, bugID)
+	}
+	if comments, _ := rows[0]["comments"].([]interface{}); len(comments) != 1 {
+		t.Errorf("comments = %v, want 1", rows[0]["comments"])
+	}
+	if _, ok := rows[0]["logs"].([]interface{}); !ok {
+		t.Errorf("logs = %v, want an array", rows[0]["logs"])
+	}
+
+	for _, params := range []string{"format=csv", "include=handoffs", "query=" + url.QueryEscape("type = = bug")} {
+		if resp, _ := doJSON(t, ts, "GET", "/v1/issues/export?"+params, nil); resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("export %s: status = %d, want 400", params, resp.StatusCode)
+		}
+	}
+}
+
+func TestExportSQLite(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	id := createTestIssue(t, ts, "Query me from the snapshot")
+
+	resp, err := http.Get(ts.URL + "/v1/export/sqlite")
+	if err != nil {
+		t.Fatalf("GET /v1/export/sqlite: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/vnd.sqlite3" {
+		t.Fatalf("status = %d, content type = %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	path := filepath.Join(t.TempDir(), "download.db")
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("write download: %v", err)
+	}
+	snap, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open download: %v", err)
+	}
+	defer snap.Close()
+	var title string
+	if err := snap.QueryRow(`SELECT title FROM issues WHERE id = ?`, id).Scan(&title); err != nil {
+		t.Errorf("issue %s missing from snapshot: %v", id, err)
+	}
+}
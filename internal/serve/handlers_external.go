@@ -0,0 +1,184 @@
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/external"
+	"github.com/marcus/td/internal/jobs"
+	"github.com/marcus/td/internal/models"
+)
+
+// externalDepsJob checks external dependencies and unblocks issues whose
+// pull requests merged, CI runs passed or URLs came up.
+func (s *Server) externalDepsJob() jobs.Job {
+	return jobs.Job{
+		Name:        "external-deps",
+		Description: "Check external dependencies and unblock resolved issues",
+		Interval:    s.config.ExternalPollInterval,
+		Run: func(ctx context.Context) error {
+			res, err := external.Poll(ctx, s.db, external.NewChecker(), s.sessionID)
+			if res != nil && res.Checked > 0 {
+				slog.Info("external dependencies", "checked", res.Checked, "satisfied", res.Satisfied,
+					"errors", res.Errors, "unblocked", len(res.Unblocked))
+			}
+			if res != nil && len(res.Unblocked) > 0 {
+				s.triggerDeliveries()
+			}
+			return err
+		},
+	}
+}
+
+// ExternalDependencyBody is the JSON body for
+// POST /v1/issues/{id}/external-dependencies.
+type ExternalDependencyBody struct {
+	URL string `json:"url"`
+}
+
+// ExternalDependencyDTO is the API representation of an external dependency.
+type ExternalDependencyDTO struct {
+	ID          string  `json:"id"`
+	IssueID     string  `json:"issue_id"`
+	URL         string  `json:"url"`
+	Provider    string  `json:"provider"`
+	State       string  `json:"state"`
+	Detail      string  `json:"detail"`
+	LastError   string  `json:"last_error"`
+	CheckedAt   *string `json:"checked_at"`
+	SatisfiedAt *string `json:"satisfied_at"`
+	SessionID   string  `json:"session_id"`
+	CreatedAt   string  `json:"created_at"`
+}
+
+// ExternalDependencyToDTO converts a models.ExternalDependency to its DTO.
+func ExternalDependencyToDTO(dep *models.ExternalDependency) ExternalDependencyDTO {
+	return ExternalDependencyDTO{
+		ID:          dep.ID,
+		IssueID:     dep.IssueID,
+		URL:         dep.URL,
+		Provider:    dep.Provider,
+		State:       string(dep.State),
+		Detail:      dep.Detail,
+		LastError:   dep.LastError,
+		CheckedAt:   nullableTime(dep.CheckedAt),
+		SatisfiedAt: nullableTime(dep.SatisfiedAt),
+		SessionID:   dep.SessionID,
+		CreatedAt:   formatTimestamp(dep.CreatedAt),
+	}
+}
+
+// ============================================================================
+// GET /v1/issues/{id}/external-dependencies
+// ============================================================================
+
+// handleListExternalDependencies lists an issue's external dependencies
+// with their last checked state.
+func (s *Server) handleListExternalDependencies(w http.ResponseWriter, r *http.Request) {
+	issue, ok := s.lookupExternalIssue(w, r)
+	if !ok {
+		return
+	}
+	deps, err := s.db.ListExternalDependencies(issue.ID)
+	if err != nil {
+		requestLog(r).Error("list external dependencies", "err", err, "id", issue.ID)
+		WriteError(w, ErrInternal, "failed to list external dependencies", http.StatusInternalServerError)
+		return
+	}
+	dtos := make([]ExternalDependencyDTO, 0, len(deps))
+	for i := range deps {
+		dtos = append(dtos, ExternalDependencyToDTO(&deps[i]))
+	}
+	WriteSuccess(w, map[string]interface{}{"external_dependencies": dtos}, http.StatusOK)
+}
+
+// ============================================================================
+// POST /v1/issues/{id}/external-dependencies
+// ============================================================================
+
+// handleAddExternalDependency makes an issue wait on a pull request, CI run
+// or URL, blocking it when it is open or in progress.
+func (s *Server) handleAddExternalDependency(w http.ResponseWriter, r *http.Request) {
+	var body ExternalDependencyBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		WriteError(w, ErrValidation, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if body.URL == "" {
+		WriteValidation(w, []FieldError{{Field: "url", Rule: "required", Message: "url is required"}})
+		return
+	}
+	target, err := external.Parse(body.URL)
+	if err != nil {
+		WriteValidation(w, []FieldError{{Field: "url", Rule: "format", Value: body.URL, Message: err.Error()}})
+		return
+	}
+	issue, ok := s.lookupExternalIssue(w, r)
+	if !ok {
+		return
+	}
+
+	dep, blocked, err := s.db.AddExternalDependency(issue.ID, target.URL, target.Provider, s.requestSession(r))
+	if err != nil {
+		if errors.Is(err, db.ErrExternalExists) {
+			WriteError(w, ErrConflict, err.Error(), http.StatusConflict)
+		} else if !writeRejection(w, err) {
+			requestLog(r).Error("add external dependency", "err", err, "id", issue.ID)
+			WriteError(w, ErrInternal, "failed to add external dependency", http.StatusInternalServerError)
+		}
+		return
+	}
+	s.NotifyChange(r)
+	WriteSuccess(w, map[string]interface{}{
+		"external_dependency": ExternalDependencyToDTO(dep),
+		"blocked":             blocked,
+	}, http.StatusCreated)
+}
+
+// ============================================================================
+// DELETE /v1/issues/{id}/external-dependencies/{ext_id}
+// ============================================================================
+
+// handleDeleteExternalDependency stops an issue waiting on an external
+// dependency, unblocking it when nothing else holds it.
+func (s *Server) handleDeleteExternalDependency(w http.ResponseWriter, r *http.Request) {
+	issue, ok := s.lookupExternalIssue(w, r)
+	if !ok {
+		return
+	}
+	_, unblocked, err := s.db.RemoveExternalDependency(issue.ID, r.PathValue("ext_id"), s.requestSession(r))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			WriteError(w, ErrNotFound, err.Error(), http.StatusNotFound)
+		} else if !writeRejection(w, err) {
+			requestLog(r).Error("remove external dependency", "err", err, "id", issue.ID)
+			WriteError(w, ErrInternal, "failed to remove external dependency", http.StatusInternalServerError)
+		}
+		return
+	}
+	s.NotifyChange(r)
+	WriteSuccess(w, map[string]interface{}{"deleted": true, "unblocked": unblocked}, http.StatusOK)
+}
+
+// lookupExternalIssue fetches the issue named in the path, writing a 404 or
+// 500 when it can't.
+func (s *Server) lookupExternalIssue(w http.ResponseWriter, r *http.Request) (*models.Issue, bool) {
+	id := r.PathValue("id")
+	issue, err := s.db.GetIssue(id)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			WriteError(w, ErrNotFound, fmt.Sprintf("issue not found: %s", id), http.StatusNotFound)
+		} else {
+			requestLog(r).Error("get issue", "err", err, "id", id)
+			WriteError(w, ErrInternal, "failed to fetch issue", http.StatusInternalServerError)
+		}
+		return nil, false
+	}
+	return issue, true
+}
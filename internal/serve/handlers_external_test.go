@@ -0,0 +1,64 @@
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestExternalDependencyAPI(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	issue := &models.Issue{Title: "Ship after the upstream fix"}
+	if err := srv.db.CreateIssueLogged(issue, "ses_test123"); err != nil {
+		t.Fatal(err)
+	}
+	base := "/v1/issues/" + issue.ID + "/external-dependencies"
+	const prURL = "https://github.com/acme/lib/pull/42"
+
+	resp, _ := doJSON(t, ts, "POST", base, map[string]interface{}{"url": "not a url"})
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("bad url = %d, want 400", resp.StatusCode)
+	}
+	resp, env := doJSON(t, ts, "POST", base, map[string]interface{}{"url": prURL})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("add = %d", resp.StatusCode)
+	}
+	data := env.Data.(map[string]interface{})
+	dep := data["external_dependency"].(map[string]interface{})
+	if dep["provider"] != "github_pr" || dep["state"] != "unsatisfied" || data["blocked"] != true {
+		t.Errorf("add = %v", data)
+	}
+	if got, _ := srv.db.GetIssue(issue.ID); got.Status != models.StatusBlocked || got.BlockedRef != prURL {
+		t.Errorf("issue = %s (%s), want blocked on the PR", got.Status, got.BlockedRef)
+	}
+	resp, _ = doJSON(t, ts, "POST", base, map[string]interface{}{"url": prURL})
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("duplicate = %d, want 409", resp.StatusCode)
+	}
+
+	_, env = doJSON(t, ts, "GET", base, nil)
+	if list := env.Data.(map[string]interface{})["external_dependencies"].([]interface{}); len(list) != 1 {
+		t.Errorf("list = %v", list)
+	}
+
+	resp, _ = doJSON(t, ts, "DELETE", base+"/ed-missing", nil)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("delete missing = %d, want 404", resp.StatusCode)
+	}
+	resp, env = doJSON(t, ts, "DELETE", base+"/"+dep["id"].(string), nil)
+	if resp.StatusCode != http.StatusOK || env.Data.(map[string]interface{})["unblocked"] != true {
+		t.Fatalf("delete = %d %v", resp.StatusCode, env.Data)
+	}
+	if got, _ := srv.db.GetIssue(issue.ID); got.Status != models.StatusOpen {
+		t.Errorf("issue = %s, want open", got.Status)
+	}
+	resp, _ = doJSON(t, ts, "GET", "/v1/issues/td-nope/external-dependencies", nil)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("missing issue = %d, want 404", resp.StatusCode)
+	}
+}
@@ -0,0 +1,103 @@
+package serve
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/marcus/td/internal/csvimport"
+	"github.com/marcus/td/internal/triage"
+)
+
+// maxImportSize caps CSV uploads accepted by POST /v1/import/csv.
+const maxImportSize = 10 << 20
+
+// ============================================================================
+// POST /v1/import/csv — Bulk CSV Import
+// ============================================================================
+
+// handleImportCSV creates issues from an uploaded CSV file. The request is
+// multipart/form-data with a "file" part and optional "map", "dry_run" and
+// "skip_invalid" fields, mirroring `td import csv`.
+func (s *Server) handleImportCSV(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+	if err := r.ParseMultipartForm(maxImportSize); err != nil {
+		WriteError(w, ErrValidation, "invalid multipart form: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		WriteError(w, ErrValidation, "file is required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	var mapping csvimport.Mapping
+	if spec := r.FormValue("map"); spec != "" {
+		m, err := csvimport.ParseMapping(spec)
+		if err != nil {
+			WriteError(w, ErrValidation, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mapping = m
+	}
+
+	dryRun, err := formBool(r, "dry_run")
+	if err != nil {
+		WriteError(w, ErrValidation, err.Error(), http.StatusBadRequest)
+		return
+	}
+	skipInvalid, err := formBool(r, "skip_invalid")
+	if err != nil {
+		WriteError(w, ErrValidation, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	titleMin, titleMax := s.titleLengthLimits()
+	res, err := csvimport.Import(s.db, file, csvimport.Options{
+		Mapping:     mapping,
+		TitleMin:    titleMin,
+		TitleMax:    titleMax,
+		DryRun:      dryRun,
+		SkipInvalid: skipInvalid,
+		Inbox:       triage.Routes(s.baseDir, triage.SourceImport),
+	}, s.requestSession(r))
+
+	switch {
+	case res == nil:
+		WriteError(w, ErrValidation, err.Error(), http.StatusBadRequest)
+		return
+	case errors.Is(err, csvimport.ErrInvalidRows):
+		WriteErrorDetails(w, ErrValidation, err.Error(), res, http.StatusBadRequest)
+		return
+	case err != nil:
+		requestLog(r).Error("import csv", "err", err)
+		if res.Created > 0 {
+			s.NotifyChange(r)
+		}
+		WriteErrorDetails(w, ErrInternal, "import failed partway", res, http.StatusInternalServerError)
+		return
+	}
+
+	status := http.StatusOK
+	if res.Created > 0 {
+		s.NotifyChange(r)
+		status = http.StatusCreated
+	}
+	WriteSuccess(w, res, status)
+}
+
+// formBool parses an optional boolean form field.
+func formBool(r *http.Request, name string) (bool, error) {
+	v := r.FormValue(name)
+	if v == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s: %q", name, v)
+	}
+	return b, nil
+}
@@ -0,0 +1,100 @@
+package serve
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// doMultipartCSV uploads csv to POST /v1/import/csv with the given form fields.
+func doMultipartCSV(t *testing.T, ts *httptest.Server, csv string, fields map[string]string) (*http.Response, Envelope) {
+	t.Helper()
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	fw, err := mw.CreateFormFile("file", "backlog.csv")
+	if err != nil {
+		t.Fatalf("create form file: %v", err)
+	}
+	fw.Write([]byte(csv))
+	for k, v := range fields {
+		mw.WriteField(k, v)
+	}
+	mw.Close()
+
+	resp, err := http.Post(ts.URL+"/v1/import/csv", mw.FormDataContentType(), &buf)
+	if err != nil {
+		t.Fatalf("POST /v1/import/csv: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var env Envelope
+	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return resp, env
+}
+
+func TestImportCSV(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	csv := "Title,Prio\nMigrate billing cron jobs,P1\nshort,P7\n"
+
+	// Dry run reports per-row validation and creates nothing
+	resp, env := doMultipartCSV(t, ts, csv, map[string]string{"map": "title=Title,priority=Prio", "dry_run": "true"})
+	if resp.StatusCode != http.StatusOK || !env.OK {
+		t.Fatalf("dry run status = %d, env = %+v", resp.StatusCode, env)
+	}
+	data := env.Data.(map[string]interface{})
+	if data["invalid"].(float64) != 1 || data["created"].(float64) != 0 {
+		t.Errorf("dry run data = %v", data)
+	}
+
+	// Invalid rows abort the import
+	resp, env = doMultipartCSV(t, ts, csv, map[string]string{"map": "title=Title,priority=Prio"})
+	if resp.StatusCode != http.StatusBadRequest || env.Error == nil || env.Error.Code != ErrValidation {
+		t.Fatalf("expected validation error, got %d %+v", resp.StatusCode, env)
+	}
+	if env.Error.Details == nil {
+		t.Error("expected row details in error")
+	}
+
+	resp, env = doMultipartCSV(t, ts, csv, map[string]string{"map": "title=Title,priority=Prio", "skip_invalid": "1"})
+	if resp.StatusCode != http.StatusCreated || !env.OK {
+		t.Fatalf("import status = %d, env = %+v", resp.StatusCode, env)
+	}
+	rows := env.Data.(map[string]interface{})["rows"].([]interface{})
+	id, _ := rows[0].(map[string]interface{})["id"].(string)
+	if id == "" {
+		t.Fatalf("expected created id, rows = %v", rows)
+	}
+	issue, err := srv.db.GetIssue(id)
+	if err != nil || issue.Priority != "P1" {
+		t.Errorf("created issue = %+v, err = %v", issue, err)
+	}
+}
+
+func TestImportCSV_BadRequest(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	resp, env := doMultipartCSV(t, ts, "Title\nSomething long enough\n", map[string]string{"map": "bogus=Title"})
+	if resp.StatusCode != http.StatusBadRequest || env.Error == nil {
+		t.Errorf("bad mapping: status = %d", resp.StatusCode)
+	}
+
+	resp, _ = doMultipartCSV(t, ts, "Foo\nbar\n", nil)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("no title column: status = %d", resp.StatusCode)
+	}
+
+	resp, _ = doMultipartCSV(t, ts, "Title\nSomething long enough\n", map[string]string{"dry_run": "maybe"})
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("bad dry_run: status = %d", resp.StatusCode)
+	}
+}
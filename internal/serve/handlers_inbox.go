@@ -0,0 +1,66 @@
+package serve
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/marcus/td/internal/triage"
+)
+
+// ============================================================================
+// GET /v1/inbox
+// ============================================================================
+
+// handleInbox lists the issues awaiting triage, oldest first.
+func (s *Server) handleInbox(w http.ResponseWriter, r *http.Request) {
+	issues, err := triage.List(s.db)
+	if err != nil {
+		requestLog(r).Error("list inbox", "err", err)
+		WriteError(w, ErrInternal, "failed to list the inbox", http.StatusInternalServerError)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"issues": IssuesToDTOs(issues)}, http.StatusOK)
+}
+
+// ============================================================================
+// POST /v1/inbox
+// ============================================================================
+
+// handleTriage applies a bulk triage decision (accept, reject, merge or
+// defer). Issues the action cannot apply to are reported as skipped rather
+// than failing the request.
+func (s *Server) handleTriage(w http.ResponseWriter, r *http.Request) {
+	var body triage.Request
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		WriteError(w, ErrValidation, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := body.Validate(); err != nil {
+		WriteError(w, ErrValidation, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if body.Action == triage.ActionMerge {
+		if _, err := s.db.GetIssue(body.Into); err != nil {
+			WriteError(w, ErrNotFound, fmt.Sprintf("issue not found: %s", body.Into), http.StatusNotFound)
+			return
+		}
+	}
+
+	result, err := triage.Apply(s.db, body, s.requestSession(r))
+	if body.Action == triage.ActionMerge {
+		s.duplicates.set(nil)
+	}
+	if err != nil {
+		if writeRejection(w, err) {
+			return
+		}
+		requestLog(r).Error("triage", "err", err, "action", body.Action)
+		WriteError(w, ErrInternal, "failed to apply triage", http.StatusInternalServerError)
+		return
+	}
+	if len(result.Done) > 0 {
+		s.NotifyChange(r)
+	}
+	WriteSuccess(w, map[string]interface{}{"triage": result}, http.StatusOK)
+}
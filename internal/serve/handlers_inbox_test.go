@@ -0,0 +1,71 @@
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/triage"
+)
+
+func TestInboxTriage(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	if err := config.SetInboxSources(srv.baseDir, []string{triage.SourceAPI}); err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, title := range []string{"Crash when saving a draft", "Support dark mode please"} {
+		resp, env := doJSON(t, ts, "POST", "/v1/issues", map[string]interface{}{"title": title})
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("create status = %d: %+v", resp.StatusCode, env.Error)
+		}
+		issue := env.Data.(map[string]interface{})["issue"].(map[string]interface{})
+		if issue["inbox"] != true {
+			t.Errorf("created issue inbox = %v, want true", issue["inbox"])
+		}
+		ids = append(ids, issue["id"].(string))
+	}
+
+	_, env := doJSON(t, ts, "GET", "/v1/inbox", nil)
+	if issues := env.Data.(map[string]interface{})["issues"].([]interface{}); len(issues) != 2 {
+		t.Fatalf("inbox = %d issues, want 2", len(issues))
+	}
+
+	if resp, _ := doJSON(t, ts, "POST", "/v1/inbox", map[string]interface{}{"action": "reject", "ids": ids}); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("reject without reason status = %d, want 400", resp.StatusCode)
+	}
+	if resp, _ := doJSON(t, ts, "POST", "/v1/inbox", map[string]interface{}{"action": "merge", "ids": ids, "into": "td-missing"}); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("merge into missing status = %d, want 404", resp.StatusCode)
+	}
+
+	resp, env := doJSON(t, ts, "POST", "/v1/inbox", map[string]interface{}{"action": "reject", "ids": []string{ids[0], "td-missing"}, "reason": "cannot reproduce"})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("reject status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	result := env.Data.(map[string]interface{})["triage"].(map[string]interface{})
+	if done := result["done"].([]interface{}); len(done) != 1 || done[0] != ids[0] {
+		t.Errorf("done = %v, want [%s]", done, ids[0])
+	}
+	if skipped := result["skipped"].([]interface{}); len(skipped) != 1 {
+		t.Errorf("skipped = %v, want the missing issue", skipped)
+	}
+	if got, _ := srv.db.GetIssue(ids[0]); got.Status != models.StatusClosed || got.Inbox {
+		t.Errorf("rejected issue status = %s, inbox = %v", got.Status, got.Inbox)
+	}
+
+	// Accepted issues drop out of the inbox but are still listed
+	doJSON(t, ts, "POST", "/v1/inbox", map[string]interface{}{"action": "accept", "ids": []string{ids[1]}})
+	_, env = doJSON(t, ts, "GET", "/v1/inbox", nil)
+	if issues := env.Data.(map[string]interface{})["issues"].([]interface{}); len(issues) != 0 {
+		t.Errorf("inbox after triage = %d issues, want 0", len(issues))
+	}
+	_, env = doJSON(t, ts, "GET", "/v1/issues?inbox=false", nil)
+	if issues := env.Data.(map[string]interface{})["issues"].([]interface{}); len(issues) != 1 {
+		t.Errorf("issues?inbox=false = %d, want 1", len(issues))
+	}
+}
@@ -0,0 +1,141 @@
+package serve
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/integrations"
+	"github.com/marcus/td/internal/triage"
+)
+
+// integrationsPathPrefix routes chat platform callbacks. Integrations that
+// verify request signatures authenticate with them, so authMiddleware skips
+// the bearer token for those (see signedIntegration).
+const integrationsPathPrefix = "/v1/integrations/"
+
+// maxIntegrationBody caps inbound slash-command payloads
+const maxIntegrationBody = 1 << 20
+
+// ============================================================================
+// POST /v1/integrations/{name} — Slack / Discord Slash Commands
+// ============================================================================
+
+// handleIntegration answers a slash command from the named integration.
+// Responses use the platform's own format rather than the API envelope.
+func (s *Server) handleIntegration(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	ic, err := config.GetIntegration(s.baseDir, name)
+	if err != nil {
+		requestLog(r).Error("load integration", "err", err, "name", name)
+		WriteError(w, ErrInternal, "failed to load integration", http.StatusInternalServerError)
+		return
+	}
+	if ic == nil {
+		WriteError(w, ErrNotFound, "integration not found: "+name, http.StatusNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIntegrationBody))
+	if err != nil {
+		WriteError(w, ErrValidation, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	titleMin, titleMax := s.titleLengthLimits()
+	commander := &integrations.Commander{
+		DB:        s.db,
+		SessionID: s.requestSession(r),
+		TitleMin:  titleMin,
+		TitleMax:  titleMax,
+		Inbox:     triage.Routes(s.baseDir, triage.SourceIntegration),
+	}
+
+	switch ic.Kind {
+	case integrations.KindSlack:
+		if ic.VerifySignature {
+			if err := integrations.VerifySlack(ic.SigningSecret, r.Header, body, clock.Now()); err != nil {
+				WriteError(w, ErrUnauthorized, err.Error(), http.StatusUnauthorized)
+				return
+			}
+		}
+		sc, err := integrations.ParseSlackCommand(body)
+		if err != nil {
+			WriteError(w, ErrValidation, err.Error(), http.StatusBadRequest)
+			return
+		}
+		reply := commander.Run(sc.Text, sc.UserName, "Slack")
+		s.notifyIfChanged(r, reply)
+		writePlatformJSON(w, integrations.SlackResponse(reply))
+
+	case integrations.KindDiscord:
+		if ic.VerifySignature {
+			if err := integrations.VerifyDiscord(ic.PublicKey, r.Header, body); err != nil {
+				WriteError(w, ErrUnauthorized, err.Error(), http.StatusUnauthorized)
+				return
+			}
+		}
+		in, err := integrations.ParseDiscordInteraction(body)
+		if err != nil {
+			WriteError(w, ErrValidation, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if in.Type == integrations.DiscordPing {
+			writePlatformJSON(w, integrations.DiscordPong())
+			return
+		}
+		if in.Type != integrations.DiscordApplicationCommand {
+			WriteError(w, ErrValidation, "unsupported interaction type", http.StatusBadRequest)
+			return
+		}
+		reply := commander.Run(in.CommandText(), in.UserName(), "Discord")
+		s.notifyIfChanged(r, reply)
+		writePlatformJSON(w, integrations.DiscordResponse(reply))
+
+	default:
+		WriteError(w, ErrInternal, "unknown integration kind: "+ic.Kind, http.StatusInternalServerError)
+	}
+}
+
+// signedIntegration reports whether r is a callback for an integration that
+// verifies request signatures and has the key to do so. Only those may skip
+// the bearer token; unverified integrations still need it.
+func (s *Server) signedIntegration(r *http.Request) bool {
+	if r.Method != http.MethodPost || !strings.HasPrefix(r.URL.Path, integrationsPathPrefix) {
+		return false
+	}
+	name := strings.TrimPrefix(r.URL.Path, integrationsPathPrefix)
+	if name == "" || strings.Contains(name, "/") {
+		return false
+	}
+	ic, err := config.GetIntegration(s.baseDir, name)
+	if err != nil || ic == nil || !ic.VerifySignature {
+		return false
+	}
+	switch ic.Kind {
+	case integrations.KindSlack:
+		return ic.SigningSecret != ""
+	case integrations.KindDiscord:
+		return ic.PublicKey != ""
+	}
+	return false
+}
+
+// notifyIfChanged wakes SSE subscribers after a command changed data
+func (s *Server) notifyIfChanged(r *http.Request, reply integrations.Reply) {
+	if reply.Changed {
+		s.NotifyChange(r)
+	}
+}
+
+// writePlatformJSON writes a bare JSON body for a chat platform.
+func writePlatformJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Error("write integration response", "err", err)
+	}
+}
@@ -0,0 +1,141 @@
+package serve
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+func postIntegration(t *testing.T, ts *httptest.Server, name, body string, header http.Header) (*http.Response, map[string]interface{}) {
+	t.Helper()
+	req, _ := http.NewRequest("POST", ts.URL+"/v1/integrations/"+name, strings.NewReader(body))
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST %s: %v", name, err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	var out map[string]interface{}
+	json.Unmarshal(data, &out)
+	return resp, out
+}
+
+func TestIntegration_SlackCommand(t *testing.T) {
+	tmpDir := t.TempDir()
+	database, err := db.Initialize(tmpDir)
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+	if err := config.SetIntegration(tmpDir, models.IntegrationConfig{
+		Name: "team", Kind: "slack", VerifySignature: true, SigningSecret: "shh",
+	}); err != nil {
+		t.Fatalf("SetIntegration: %v", err)
+	}
+
+	// Integration routes rely on signatures, not the bearer token
+	srv := NewServer(database, tmpDir, "ses_test123", ServeConfig{Token: "secret-token"})
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	body := url.Values{"command": {"/td"}, "text": {"create -p P1 Chat created issue title"}, "user_name": {"ana"}}.Encode()
+	stamp := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, []byte("shh"))
+	mac.Write([]byte("v0:" + stamp + ":" + body))
+	header := http.Header{}
+	header.Set("X-Slack-Request-Timestamp", stamp)
+	header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+
+	resp, out := postIntegration(t, ts, "team", body, header)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d: %v", resp.StatusCode, out)
+	}
+	if out["response_type"] != "in_channel" || !strings.Contains(out["text"].(string), "Chat created issue title") {
+		t.Errorf("response = %v", out)
+	}
+	issues, _ := database.ListIssues(db.ListIssuesOptions{})
+	if len(issues) != 1 || issues[0].Priority != models.PriorityP1 {
+		t.Errorf("issues = %+v", issues)
+	}
+
+	header.Set("X-Slack-Signature", "v0=deadbeef")
+	if resp, _ := postIntegration(t, ts, "team", body, header); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("bad signature status = %d", resp.StatusCode)
+	}
+	if resp, _ := postIntegration(t, ts, "nope", body, header); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("unknown integration without token status = %d", resp.StatusCode)
+	}
+	header.Set("Authorization", "Bearer secret-token")
+	if resp, _ := postIntegration(t, ts, "nope", body, header); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown integration status = %d", resp.StatusCode)
+	}
+}
+
+func TestIntegration_UnverifiedNeedsToken(t *testing.T) {
+	tmpDir := t.TempDir()
+	database := setupTestDB(t)
+	srv := NewServer(database, tmpDir, "ses_test123", ServeConfig{Token: "secret-token"})
+	// Verification on but no key configured is as good as off
+	for _, ic := range []models.IntegrationConfig{
+		{Name: "open", Kind: "slack", VerifySignature: false, SigningSecret: "shh"},
+		{Name: "nokey", Kind: "slack", VerifySignature: true},
+	} {
+		if err := config.SetIntegration(tmpDir, ic); err != nil {
+			t.Fatalf("SetIntegration: %v", err)
+		}
+	}
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	body := url.Values{"command": {"/td"}, "text": {"create Sneaky unauthenticated issue"}, "user_name": {"eve"}}.Encode()
+	for _, name := range []string{"open", "nokey"} {
+		if resp, out := postIntegration(t, ts, name, body, nil); resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("%s without token: status = %d: %v", name, resp.StatusCode, out)
+		}
+	}
+	if issues, _ := database.ListIssues(db.ListIssuesOptions{}); len(issues) != 0 {
+		t.Fatalf("unauthenticated command created %d issues", len(issues))
+	}
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer secret-token")
+	if resp, out := postIntegration(t, ts, "open", body, header); resp.StatusCode != http.StatusOK {
+		t.Errorf("with token: status = %d: %v", resp.StatusCode, out)
+	}
+}
+
+func TestIntegration_UnverifiedDiscord(t *testing.T) {
+	tmpDir := t.TempDir()
+	srv := NewServer(setupTestDB(t), tmpDir, "ses_test123", ServeConfig{})
+	if err := config.SetIntegration(tmpDir, models.IntegrationConfig{Name: "dev", Kind: "discord"}); err != nil {
+		t.Fatalf("SetIntegration: %v", err)
+	}
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	resp, out := postIntegration(t, ts, "dev", `{"type":1}`, nil)
+	if resp.StatusCode != http.StatusOK || out["type"] != float64(1) {
+		t.Errorf("ping: status = %d, body = %v", resp.StatusCode, out)
+	}
+
+	_, out = postIntegration(t, ts, "dev", `{"type":2,"data":{"name":"td","options":[{"name":"text","type":3,"value":"help"}]}}`, nil)
+	if out["type"] != float64(4) {
+		t.Errorf("command response = %v", out)
+	}
+}
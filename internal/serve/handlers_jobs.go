@@ -0,0 +1,58 @@
+package serve
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/marcus/td/internal/jobs"
+)
+
+// ============================================================================
+// GET /v1/jobs
+// ============================================================================
+
+// handleListJobs reports every background job with its schedule, last and
+// next run, and failure counts.
+func (s *Server) handleListJobs(w http.ResponseWriter, r *http.Request) {
+	WriteSuccess(w, map[string]interface{}{"jobs": s.jobs.Statuses()}, http.StatusOK)
+}
+
+// ============================================================================
+// GET /v1/jobs/{name}
+// ============================================================================
+
+func (s *Server) handleGetJob(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	st, ok := s.jobs.Get(name)
+	if !ok {
+		WriteError(w, ErrNotFound, "job not found: "+name, http.StatusNotFound)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"job": st}, http.StatusOK)
+}
+
+// ============================================================================
+// POST /v1/jobs/{name}/run
+// ============================================================================
+
+// handleRunJob starts a run of a job now. The run happens in the
+// background; poll GET /v1/jobs/{name} for the outcome.
+func (s *Server) handleRunJob(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	err := s.jobs.Trigger(name)
+	switch {
+	case errors.Is(err, jobs.ErrUnknownJob):
+		WriteError(w, ErrNotFound, "job not found: "+name, http.StatusNotFound)
+		return
+	case errors.Is(err, jobs.ErrRunning):
+		WriteError(w, ErrConflict, "job is already running: "+name, http.StatusConflict)
+		return
+	case err != nil:
+		WriteError(w, ErrInternal, "failed to run job: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	requestLog(r).Info("job triggered", "job", name)
+
+	st, _ := s.jobs.Get(name)
+	WriteSuccess(w, map[string]interface{}{"job": st}, http.StatusAccepted)
+}
@@ -0,0 +1,76 @@
+package serve
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/jobs"
+)
+
+func TestJobsAPI(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	if err := srv.jobs.Add(jobs.Job{Name: "broken", Description: "Always fails", Run: func(context.Context) error {
+		return errors.New("disk full")
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	events, unsubscribe := srv.sseHub.Subscribe()
+	defer unsubscribe()
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	resp, env := doJSON(t, ts, "GET", "/v1/jobs", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("list status = %d", resp.StatusCode)
+	}
+	list := env.Data.(map[string]interface{})["jobs"].([]interface{})
+	if len(list) != 6 || list[0].(map[string]interface{})["name"] != "duplicates" {
+		t.Fatalf("jobs = %v", list)
+	}
+
+	resp, env = doJSON(t, ts, "POST", "/v1/jobs/duplicates/run", nil)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("run status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	waitJob := func(name string) map[string]interface{} {
+		t.Helper()
+		for i := 0; i < 200; i++ {
+			_, env := doJSON(t, ts, "GET", "/v1/jobs/"+name, nil)
+			job := env.Data.(map[string]interface{})["job"].(map[string]interface{})
+			if job["runs"] == float64(1) && job["running"] == false {
+				return job
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("%s never finished", name)
+		return nil
+	}
+	if job := waitJob("duplicates"); job["last_error"] != nil || srv.duplicates.get() == nil {
+		t.Errorf("duplicates job = %v", job)
+	}
+
+	// A failing job records the error and is announced to SSE clients
+	doJSON(t, ts, "POST", "/v1/jobs/broken/run", nil)
+	if job := waitJob("broken"); job["last_error"] != "disk full" || job["consecutive_failures"] != float64(1) {
+		t.Errorf("broken job = %v", job)
+	}
+	select {
+	case ev := <-events:
+		if ev.Event != "job_failed" {
+			t.Errorf("event = %+v, want job_failed", ev)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("no job_failed event")
+	}
+
+	if resp, _ := doJSON(t, ts, "GET", "/v1/jobs/nope", nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown job status = %d", resp.StatusCode)
+	}
+	if resp, _ := doJSON(t, ts, "POST", "/v1/jobs/nope/run", nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown job run status = %d", resp.StatusCode)
+	}
+}
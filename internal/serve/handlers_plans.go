@@ -0,0 +1,168 @@
+package serve
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/plan"
+)
+
+// ============================================================================
+// POST /v1/plans — Create Plan
+// ============================================================================
+
+// PlanCreateBody represents the expected JSON body for creating a plan.
+// close uses issue_ids and/or query; relabel and carryover use from/to.
+type PlanCreateBody struct {
+	Kind       string   `json:"kind"`
+	IssueIDs   []string `json:"issue_ids"`
+	Query      string   `json:"query"`
+	From       string   `json:"from"`
+	To         string   `json:"to"`
+	TTLSeconds int      `json:"ttl_seconds"`
+}
+
+// handleCreatePlan builds and stores a pending plan. Nothing changes until
+// the plan is applied.
+func (s *Server) handleCreatePlan(w http.ResponseWriter, r *http.Request) {
+	var body PlanCreateBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		WriteError(w, ErrValidation, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var p *models.Plan
+	var err error
+	switch models.PlanKind(body.Kind) {
+	case models.PlanClose:
+		if len(body.IssueIDs) == 0 && body.Query == "" {
+			WriteValidation(w, []FieldError{{Field: "issue_ids", Rule: "required", Message: "issue_ids or query is required"}})
+			return
+		}
+		p, err = plan.Close(s.db, body.IssueIDs, body.Query, s.requestSession(r))
+	case models.PlanRelabel:
+		p, err = plan.Relabel(s.db, body.From, body.To, s.requestSession(r))
+	case models.PlanCarryOver:
+		p, err = plan.CarryOver(s.db, body.From, body.To, s.requestSession(r))
+	default:
+		WriteValidation(w, []FieldError{{
+			Field:   "kind",
+			Rule:    "enum",
+			Value:   body.Kind,
+			Message: "kind must be close, relabel or carryover",
+		}})
+		return
+	}
+	if errors.Is(err, plan.ErrNoChanges) {
+		WriteError(w, ErrValidation, "plan would not change any issues", http.StatusUnprocessableEntity)
+		return
+	}
+	if err != nil {
+		WriteError(w, ErrValidation, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := plan.Save(s.db, p, time.Duration(body.TTLSeconds)*time.Second); err != nil {
+		requestLog(r).Error("save plan", "err", err)
+		WriteError(w, ErrInternal, "failed to save plan", http.StatusInternalServerError)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"plan": PlanToDTO(p)}, http.StatusCreated)
+}
+
+// ============================================================================
+// GET /v1/plans, GET /v1/plans/{id}
+// ============================================================================
+
+// handleListPlans lists pending plans, or all plans with ?all=true.
+func (s *Server) handleListPlans(w http.ResponseWriter, r *http.Request) {
+	all := r.URL.Query().Get("all") == "true"
+	plans, err := s.db.ListPlans(!all)
+	if err != nil {
+		requestLog(r).Error("list plans", "err", err)
+		WriteError(w, ErrInternal, "failed to list plans", http.StatusInternalServerError)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"plans": PlansToDTOs(plans)}, http.StatusOK)
+}
+
+// handleGetPlan returns one plan with its preview diff.
+func (s *Server) handleGetPlan(w http.ResponseWriter, r *http.Request) {
+	p, ok := s.lookupPlan(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"plan": PlanToDTO(p)}, http.StatusOK)
+}
+
+// ============================================================================
+// POST /v1/plans/{id}/apply, DELETE /v1/plans/{id}
+// ============================================================================
+
+// handleApplyPlan applies a pending plan. Expired or already-applied plans
+// return 409.
+func (s *Server) handleApplyPlan(w http.ResponseWriter, r *http.Request) {
+	planID := r.PathValue("id")
+	if _, ok := s.lookupPlan(w, planID); !ok {
+		return
+	}
+
+	res, err := plan.Apply(s.db, planID, s.requestSession(r))
+	if errors.Is(err, plan.ErrExpired) || errors.Is(err, db.ErrPlanNotPending) {
+		WriteError(w, ErrConflict, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		requestLog(r).Error("apply plan", "err", err, "id", planID)
+		WriteError(w, ErrInternal, "failed to apply plan", http.StatusInternalServerError)
+		return
+	}
+
+	if len(res.Applied) > 0 {
+		s.NotifyChange(r)
+	}
+	WriteSuccess(w, map[string]interface{}{
+		"plan":    PlanToDTO(res.Plan),
+		"applied": res.Applied,
+		"skipped": res.Skipped,
+	}, http.StatusOK)
+}
+
+// handleDiscardPlan discards a pending plan.
+func (s *Server) handleDiscardPlan(w http.ResponseWriter, r *http.Request) {
+	planID := r.PathValue("id")
+	if _, ok := s.lookupPlan(w, planID); !ok {
+		return
+	}
+	if err := s.db.DiscardPlan(planID); err != nil {
+		if errors.Is(err, db.ErrPlanNotPending) {
+			WriteError(w, ErrConflict, err.Error(), http.StatusConflict)
+			return
+		}
+		requestLog(r).Error("discard plan", "err", err, "id", planID)
+		WriteError(w, ErrInternal, "failed to discard plan", http.StatusInternalServerError)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"discarded": true}, http.StatusOK)
+}
+
+// lookupPlan fetches a plan, writing a 404 or 500 when it cannot.
+func (s *Server) lookupPlan(w http.ResponseWriter, id string) (*models.Plan, bool) {
+	p, err := s.db.GetPlan(id)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			WriteError(w, ErrNotFound, "plan not found: "+id, http.StatusNotFound)
+		} else {
+			slog.Error("get plan", "err", err, "id", id)
+			WriteError(w, ErrInternal, "failed to fetch plan", http.StatusInternalServerError)
+		}
+		return nil, false
+	}
+	return p, true
+}
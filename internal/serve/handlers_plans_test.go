@@ -0,0 +1,67 @@
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestPlans_CreateApply(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	issue := &models.Issue{Title: "Old label issue", Labels: []string{"fe"}}
+	if err := srv.db.CreateIssue(issue); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, env := doJSON(t, ts, "POST", "/v1/plans", map[string]interface{}{"kind": "relabel", "from": "fe", "to": "frontend"})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	p := env.Data.(map[string]interface{})["plan"].(map[string]interface{})
+	planID := p["id"].(string)
+	if p["status"] != "pending" || len(p["changes"].([]interface{})) != 1 {
+		t.Errorf("plan = %v", p)
+	}
+
+	// Preview must not modify the issue
+	if got, _ := srv.db.GetIssue(issue.ID); got.Labels[0] != "fe" {
+		t.Fatalf("labels changed before apply: %v", got.Labels)
+	}
+
+	resp, env = doJSON(t, ts, "POST", "/v1/plans/"+planID+"/apply", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("apply status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	if got, _ := srv.db.GetIssue(issue.ID); got.Labels[0] != "frontend" {
+		t.Errorf("labels after apply = %v", got.Labels)
+	}
+
+	resp, _ = doJSON(t, ts, "POST", "/v1/plans/"+planID+"/apply", nil)
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("second apply status = %d", resp.StatusCode)
+	}
+	resp, _ = doJSON(t, ts, "GET", "/v1/plans/pl-missing", nil)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("missing plan status = %d", resp.StatusCode)
+	}
+}
+
+func TestPlans_CreateValidation(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	resp, _ := doJSON(t, ts, "POST", "/v1/plans", map[string]interface{}{"kind": "explode"})
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("bad kind status = %d", resp.StatusCode)
+	}
+	resp, _ = doJSON(t, ts, "POST", "/v1/plans", map[string]interface{}{"kind": "carryover", "from": "s1", "to": "s2"})
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("empty plan status = %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,312 @@
+package serve
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+// PokerRoundBody is the JSON body for POST /v1/poker.
+type PokerRoundBody struct {
+	Title    string   `json:"title"`
+	IssueIDs []string `json:"issue_ids"`
+}
+
+// PokerVoteBody is the JSON body for POST /v1/poker/{id}/votes.
+type PokerVoteBody struct {
+	IssueID string `json:"issue_id"`
+	Points  int    `json:"points"`
+}
+
+// PokerEstimateBody is the JSON body for POST /v1/poker/{id}/estimates.
+// Points may be omitted when every vote agrees.
+type PokerEstimateBody struct {
+	IssueID string `json:"issue_id"`
+	Points  int    `json:"points"`
+	Note    string `json:"note"`
+}
+
+// PokerVoteDTO is the API representation of a poker vote. Points are null
+// while the vote is hidden from the caller.
+type PokerVoteDTO struct {
+	SessionID string `json:"session_id"`
+	Points    *int   `json:"points"`
+	Hidden    bool   `json:"hidden"`
+	CreatedAt string `json:"created_at"`
+}
+
+// PokerItemDTO is the API representation of an issue in a poker round.
+type PokerItemDTO struct {
+	IssueID    string         `json:"issue_id"`
+	Votes      []PokerVoteDTO `json:"votes"`
+	Points     int            `json:"points"`
+	Dissent    string         `json:"dissent"`
+	RecordedAt *string        `json:"recorded_at"`
+}
+
+// PokerRoundDTO is the API representation of a poker round.
+type PokerRoundDTO struct {
+	ID         string         `json:"id"`
+	Title      string         `json:"title"`
+	Status     string         `json:"status"`
+	SessionID  string         `json:"session_id"`
+	CreatedAt  string         `json:"created_at"`
+	RevealedAt *string        `json:"revealed_at"`
+	ClosedAt   *string        `json:"closed_at"`
+	Items      []PokerItemDTO `json:"items"`
+}
+
+// PokerItemToDTO converts a models.PokerItem to its DTO.
+func PokerItemToDTO(item models.PokerItem) PokerItemDTO {
+	dto := PokerItemDTO{
+		IssueID:    item.IssueID,
+		Votes:      make([]PokerVoteDTO, 0, len(item.Votes)),
+		Points:     item.Points,
+		Dissent:    item.Dissent,
+		RecordedAt: nullableTime(item.RecordedAt),
+	}
+	for _, v := range item.Votes {
+		vote := PokerVoteDTO{SessionID: v.SessionID, Hidden: v.Hidden, CreatedAt: formatTimestamp(v.CreatedAt)}
+		if !v.Hidden {
+			points := v.Points
+			vote.Points = &points
+		}
+		dto.Votes = append(dto.Votes, vote)
+	}
+	return dto
+}
+
+// PokerRoundToDTO converts a models.PokerRound to its DTO. Blind the round
+// for the caller first.
+func PokerRoundToDTO(round *models.PokerRound) PokerRoundDTO {
+	dto := PokerRoundDTO{
+		ID:         round.ID,
+		Title:      round.Title,
+		Status:     string(round.Status),
+		SessionID:  round.SessionID,
+		CreatedAt:  formatTimestamp(round.CreatedAt),
+		RevealedAt: nullableTime(round.RevealedAt),
+		ClosedAt:   nullableTime(round.ClosedAt),
+		Items:      make([]PokerItemDTO, 0, len(round.Items)),
+	}
+	for _, item := range round.Items {
+		dto.Items = append(dto.Items, PokerItemToDTO(item))
+	}
+	return dto
+}
+
+// ============================================================================
+// GET /v1/poker
+// ============================================================================
+
+// handleListPokerRounds lists open and revealed rounds newest first;
+// ?all=true includes closed ones. Votes in open rounds are hidden except
+// the caller's own.
+func (s *Server) handleListPokerRounds(w http.ResponseWriter, r *http.Request) {
+	all, _ := strconv.ParseBool(r.URL.Query().Get("all"))
+	rounds, err := s.db.ListPokerRounds(all)
+	if err != nil {
+		requestLog(r).Error("list poker rounds", "err", err)
+		WriteError(w, ErrInternal, "failed to list poker rounds", http.StatusInternalServerError)
+		return
+	}
+	viewer := s.requestSession(r)
+	dtos := make([]PokerRoundDTO, 0, len(rounds))
+	for i := range rounds {
+		rounds[i].Blind(viewer)
+		dtos = append(dtos, PokerRoundToDTO(&rounds[i]))
+	}
+	WriteSuccess(w, map[string]interface{}{"rounds": dtos}, http.StatusOK)
+}
+
+// ============================================================================
+// POST /v1/poker
+// ============================================================================
+
+// handleCreatePokerRound opens a round of blind voting on issues.
+func (s *Server) handleCreatePokerRound(w http.ResponseWriter, r *http.Request) {
+	var body PokerRoundBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		WriteError(w, ErrValidation, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(body.IssueIDs) == 0 {
+		WriteValidation(w, []FieldError{{Field: "issue_ids", Rule: "required", Message: "issue_ids is required"}})
+		return
+	}
+
+	round, err := s.db.CreatePokerRound(body.Title, body.IssueIDs, s.requestSession(r))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			WriteError(w, ErrNotFound, err.Error(), http.StatusNotFound)
+		} else {
+			requestLog(r).Error("create poker round", "err", err)
+			WriteError(w, ErrInternal, "failed to create poker round", http.StatusInternalServerError)
+		}
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"round": PokerRoundToDTO(round)}, http.StatusCreated)
+}
+
+// ============================================================================
+// GET /v1/poker/{id}
+// ============================================================================
+
+// handleGetPokerRound returns a round as the caller may see it.
+func (s *Server) handleGetPokerRound(w http.ResponseWriter, r *http.Request) {
+	round, ok := s.lookupPokerRound(w, r)
+	if !ok {
+		return
+	}
+	round.Blind(s.requestSession(r))
+	WriteSuccess(w, map[string]interface{}{"round": PokerRoundToDTO(round)}, http.StatusOK)
+}
+
+// ============================================================================
+// POST /v1/poker/{id}/votes
+// ============================================================================
+
+// handleCastPokerVote casts or replaces the caller's blind vote on an
+// issue in an open round.
+func (s *Server) handleCastPokerVote(w http.ResponseWriter, r *http.Request) {
+	var body PokerVoteBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		WriteError(w, ErrValidation, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if errs := validatePokerPoints(body.IssueID, body.Points, true); len(errs) > 0 {
+		WriteValidation(w, errs)
+		return
+	}
+	round, ok := s.lookupPokerRound(w, r)
+	if !ok {
+		return
+	}
+
+	viewer := s.requestSession(r)
+	if err := s.db.CastPokerVote(round.ID, body.IssueID, viewer, body.Points); err != nil {
+		writePokerError(w, r, err, "cast poker vote")
+		return
+	}
+	round, ok = s.lookupPokerRound(w, r)
+	if !ok {
+		return
+	}
+	round.Blind(viewer)
+	WriteSuccess(w, map[string]interface{}{"round": PokerRoundToDTO(round)}, http.StatusOK)
+}
+
+// ============================================================================
+// POST /v1/poker/{id}/reveal
+// ============================================================================
+
+// handleRevealPokerRound ends voting and returns every vote.
+func (s *Server) handleRevealPokerRound(w http.ResponseWriter, r *http.Request) {
+	round, err := s.db.RevealPokerRound(r.PathValue("id"))
+	if err != nil {
+		writePokerError(w, r, err, "reveal poker round")
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"round": PokerRoundToDTO(round)}, http.StatusOK)
+}
+
+// ============================================================================
+// POST /v1/poker/{id}/estimates
+// ============================================================================
+
+// handleRecordPokerEstimate records the agreed estimate for an issue as
+// its story points, keeping differing votes as dissent.
+func (s *Server) handleRecordPokerEstimate(w http.ResponseWriter, r *http.Request) {
+	var body PokerEstimateBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		WriteError(w, ErrValidation, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if errs := validatePokerPoints(body.IssueID, body.Points, false); len(errs) > 0 {
+		WriteValidation(w, errs)
+		return
+	}
+
+	item, err := s.db.RecordPokerEstimate(r.PathValue("id"), body.IssueID, body.Points, body.Note, s.requestSession(r))
+	if err != nil {
+		writePokerError(w, r, err, "record poker estimate")
+		return
+	}
+	s.NotifyChange(r)
+	WriteSuccess(w, map[string]interface{}{"item": PokerItemToDTO(*item)}, http.StatusOK)
+}
+
+// ============================================================================
+// POST /v1/poker/{id}/close
+// ============================================================================
+
+// handleClosePokerRound closes a round whether or not every issue has an
+// estimate.
+func (s *Server) handleClosePokerRound(w http.ResponseWriter, r *http.Request) {
+	if err := s.db.ClosePokerRound(r.PathValue("id")); err != nil {
+		writePokerError(w, r, err, "close poker round")
+		return
+	}
+	round, ok := s.lookupPokerRound(w, r)
+	if !ok {
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"round": PokerRoundToDTO(round)}, http.StatusOK)
+}
+
+// lookupPokerRound fetches the round named in the path, writing a 404 or
+// 500 when it can't.
+func (s *Server) lookupPokerRound(w http.ResponseWriter, r *http.Request) (*models.PokerRound, bool) {
+	round, err := s.db.GetPokerRound(r.PathValue("id"))
+	if err != nil {
+		writePokerError(w, r, err, "get poker round")
+		return nil, false
+	}
+	return round, true
+}
+
+// validatePokerPoints checks the issue and points of a vote or estimate;
+// estimates may leave points out.
+func validatePokerPoints(issueID string, points int, required bool) []FieldError {
+	var errs []FieldError
+	if issueID == "" {
+		errs = append(errs, FieldError{Field: "issue_id", Rule: "required", Message: "issue_id is required"})
+	}
+	if (required || points != 0) && !models.IsValidPoints(points) {
+		expected := make([]string, 0, len(models.ValidPoints()))
+		for _, p := range models.ValidPoints() {
+			expected = append(expected, strconv.Itoa(p))
+		}
+		errs = append(errs, FieldError{
+			Field:    "points",
+			Rule:     "enum",
+			Value:    strconv.Itoa(points),
+			Expected: expected,
+			Message:  "points must be one of " + strings.Join(expected, ", "),
+		})
+	}
+	return errs
+}
+
+// writePokerError maps poker round errors to responses: missing rounds and
+// issues are 404s, the wrong stage a 409 and split votes a 400.
+func writePokerError(w http.ResponseWriter, r *http.Request, err error, what string) {
+	switch {
+	case errors.Is(err, db.ErrPokerStage):
+		WriteError(w, ErrConflict, err.Error(), http.StatusConflict)
+	case errors.Is(err, db.ErrNoConsensus):
+		WriteValidation(w, []FieldError{{Field: "points", Rule: "required", Message: err.Error()}})
+	case strings.Contains(err.Error(), "not found"), strings.Contains(err.Error(), "not in round"):
+		WriteError(w, ErrNotFound, err.Error(), http.StatusNotFound)
+	default:
+		if !writeRejection(w, err) {
+			requestLog(r).Error(what, "err", err, "id", r.PathValue("id"))
+			WriteError(w, ErrInternal, "failed to "+what, http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestPokerAPI(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	issue := &models.Issue{Title: "Issue to estimate"}
+	if err := srv.db.CreateIssueLogged(issue, "ses_test123"); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, _ := doJSON(t, ts, "POST", "/v1/poker", map[string]interface{}{"issue_ids": []string{"td-nope"}})
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown issue = %d, want 404", resp.StatusCode)
+	}
+	resp, env := doJSON(t, ts, "POST", "/v1/poker", map[string]interface{}{"title": "Refinement", "issue_ids": []string{issue.ID}})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create = %d", resp.StatusCode)
+	}
+	roundID := env.Data.(map[string]interface{})["round"].(map[string]interface{})["id"].(string)
+	base := "/v1/poker/" + roundID
+
+	if err := srv.db.CastPokerVote(roundID, issue.ID, "ses_other", 8); err != nil {
+		t.Fatal(err)
+	}
+	resp, _ = doJSON(t, ts, "POST", base+"/votes", map[string]interface{}{"issue_id": issue.ID, "points": 4})
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("vote 4 = %d, want 400", resp.StatusCode)
+	}
+	resp, env = doJSON(t, ts, "POST", base+"/votes", map[string]interface{}{"issue_id": issue.ID, "points": 5})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("vote = %d", resp.StatusCode)
+	}
+	votes := env.Data.(map[string]interface{})["round"].(map[string]interface{})["items"].([]interface{})[0].(map[string]interface{})["votes"].([]interface{})
+	for _, v := range votes {
+		vote := v.(map[string]interface{})
+		if own := vote["session_id"] == "ses_test123"; own == vote["hidden"].(bool) || own == (vote["points"] == nil) {
+			t.Errorf("blind vote = %v", vote)
+		}
+	}
+
+	resp, _ = doJSON(t, ts, "POST", base+"/estimates", map[string]interface{}{"issue_id": issue.ID, "points": 5})
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("estimate before reveal = %d, want 409", resp.StatusCode)
+	}
+	resp, _ = doJSON(t, ts, "POST", base+"/reveal", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("reveal = %d", resp.StatusCode)
+	}
+	resp, _ = doJSON(t, ts, "POST", base+"/estimates", map[string]interface{}{"issue_id": issue.ID})
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("estimate of split votes without points = %d, want 400", resp.StatusCode)
+	}
+	resp, env = doJSON(t, ts, "POST", base+"/estimates", map[string]interface{}{"issue_id": issue.ID, "points": 5, "note": "depends on the API"})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("estimate = %d", resp.StatusCode)
+	}
+	if item := env.Data.(map[string]interface{})["item"].(map[string]interface{}); item["dissent"] != "ses_other voted 8; depends on the API" {
+		t.Errorf("dissent = %v", item["dissent"])
+	}
+	if got, _ := srv.db.GetIssue(issue.ID); got.Points != 5 {
+		t.Errorf("points = %d, want 5", got.Points)
+	}
+
+	_, env = doJSON(t, ts, "GET", base, nil)
+	if status := env.Data.(map[string]interface{})["round"].(map[string]interface{})["status"]; status != "closed" {
+		t.Errorf("status = %v, want closed", status)
+	}
+	resp, _ = doJSON(t, ts, "POST", base+"/close", nil)
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("close closed round = %d, want 409", resp.StatusCode)
+	}
+	resp, _ = doJSON(t, ts, "GET", "/v1/poker/pk-missing", nil)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("missing round = %d, want 404", resp.StatusCode)
+	}
+}
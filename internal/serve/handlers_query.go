@@ -0,0 +1,109 @@
+package serve
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/marcus/td/internal/query"
+)
+
+// ============================================================================
+// GET /v1/query/validate
+// ============================================================================
+
+// QueryErrorDTO is one problem found in a TDQ query. Line and column are
+// set for syntax errors.
+type QueryErrorDTO struct {
+	Message string `json:"message"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+}
+
+// QueryFunctionDTO describes a TDQ function for autocomplete
+type QueryFunctionDTO struct {
+	query.Function
+	Signature string `json:"signature"`
+}
+
+// QueryFieldDTO describes a TDQ field for autocomplete
+type QueryFieldDTO struct {
+	Name   string   `json:"name"`
+	Type   string   `json:"type"`
+	Values []string `json:"values,omitempty"`
+}
+
+// handleValidateQuery checks ?q= without running it and returns the
+// language metadata (functions, fields, sort fields) editors need for
+// autocomplete. An invalid query is still a 200; see data.valid.
+func (s *Server) handleValidateQuery(w http.ResponseWriter, r *http.Request) {
+	raw := r.URL.Query().Get("q")
+
+	data := map[string]interface{}{
+		"valid":       true,
+		"functions":   queryFunctionDTOs(),
+		"fields":      queryFieldDTOs(),
+		"sort_fields": sortedKeys(query.SortFieldToColumn),
+	}
+
+	parsed, err := query.Parse(raw)
+	if err != nil {
+		dto := QueryErrorDTO{Message: err.Error()}
+		var perr *query.ParseError
+		if errors.As(err, &perr) {
+			dto.Line, dto.Column = perr.Line, perr.Column
+		}
+		data["valid"] = false
+		data["errors"] = []QueryErrorDTO{dto}
+		WriteSuccess(w, data, http.StatusOK)
+		return
+	}
+
+	if verrs := parsed.Validate(); len(verrs) > 0 {
+		dtos := make([]QueryErrorDTO, len(verrs))
+		for i, verr := range verrs {
+			dtos[i] = QueryErrorDTO{Message: verr.Error()}
+		}
+		data["valid"] = false
+		data["errors"] = dtos
+		WriteSuccess(w, data, http.StatusOK)
+		return
+	}
+
+	data["query"] = parsed.String()
+	WriteSuccess(w, data, http.StatusOK)
+}
+
+func queryFunctionDTOs() []QueryFunctionDTO {
+	fns := query.Functions()
+	dtos := make([]QueryFunctionDTO, len(fns))
+	for i, fn := range fns {
+		dtos[i] = QueryFunctionDTO{Function: fn, Signature: fn.Signature()}
+	}
+	return dtos
+}
+
+func queryFieldDTOs() []QueryFieldDTO {
+	var dtos []QueryFieldDTO
+	for _, name := range sortedKeys(query.KnownFields) {
+		typ := query.KnownFields[name]
+		if typ == "prefix" {
+			for _, sub := range sortedKeys(query.CrossEntityFields[name]) {
+				full := name + "." + sub
+				dtos = append(dtos, QueryFieldDTO{Name: full, Type: query.CrossEntityFields[name][sub], Values: query.EnumValues[full]})
+			}
+			continue
+		}
+		dtos = append(dtos, QueryFieldDTO{Name: name, Type: typ, Values: query.EnumValues[name]})
+	}
+	return dtos
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,47 @@
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestValidateQuery(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	resp, env := doJSON(t, ts, "GET", "/v1/query/validate?q="+url.QueryEscape("stale(14d) AND type = BUG"), nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d", resp.StatusCode)
+	}
+	data := env.Data.(map[string]interface{})
+	if data["valid"] != true || data["query"] != "(stale(14d) AND type = bug)" {
+		t.Errorf("valid query = %v, %v", data["valid"], data["query"])
+	}
+
+	var stale map[string]interface{}
+	for _, fn := range data["functions"].([]interface{}) {
+		if f := fn.(map[string]interface{}); f["name"] == "stale" {
+			stale = f
+		}
+	}
+	if stale == nil || stale["signature"] != "stale(age)" || stale["builtin"] != false {
+		t.Errorf("stale metadata = %v", stale)
+	}
+	if fields, _ := data["fields"].([]interface{}); len(fields) == 0 {
+		t.Error("fields missing")
+	}
+
+	for _, q := range []string{"status = = open", "stale(soon)", "frobnicate()"} {
+		_, env := doJSON(t, ts, "GET", "/v1/query/validate?q="+url.QueryEscape(q), nil)
+		data := env.Data.(map[string]interface{})
+		if data["valid"] != false {
+			t.Errorf("%q: valid = %v", q, data["valid"])
+		}
+		if errs, _ := data["errors"].([]interface{}); len(errs) == 0 {
+			t.Errorf("%q: no errors reported", q)
+		}
+	}
+}
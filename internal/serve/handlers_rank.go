@@ -0,0 +1,130 @@
+package serve
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/marcus/td/internal/db"
+)
+
+// RankMoveBody is the JSON body for POST /v1/rank.
+type RankMoveBody struct {
+	IDs   []string `json:"ids"`   // issues to move, in the order they should end up
+	Place string   `json:"place"` // top, bottom, above or below
+	Other string   `json:"other"` // the ranked issue to move above or below
+}
+
+// ============================================================================
+// GET /v1/rank
+// ============================================================================
+
+// handleListRank returns the ranked issues in queue order; ?all=true
+// includes closed ones.
+func (s *Server) handleListRank(w http.ResponseWriter, r *http.Request) {
+	all, _ := strconv.ParseBool(r.URL.Query().Get("all"))
+	issues, err := s.db.RankedIssues(all)
+	if err != nil {
+		requestLog(r).Error("list ranked issues", "err", err)
+		WriteError(w, ErrInternal, "failed to list ranked issues", http.StatusInternalServerError)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"issues": IssuesToDTOs(issues)}, http.StatusOK)
+}
+
+// ============================================================================
+// POST /v1/rank
+// ============================================================================
+
+// handleMoveRank puts issues in the project-wide queue, e.g. after they
+// were dragged into place. The issues keep the order of ids.
+func (s *Server) handleMoveRank(w http.ResponseWriter, r *http.Request) {
+	var body RankMoveBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		WriteError(w, ErrValidation, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	var errs []FieldError
+	if len(body.IDs) == 0 {
+		errs = append(errs, FieldError{Field: "ids", Rule: "required", Message: "ids is required"})
+	}
+	switch body.Place {
+	case db.RankTop, db.RankBottom:
+	case db.RankAbove, db.RankBelow:
+		if body.Other == "" {
+			errs = append(errs, FieldError{Field: "other", Rule: "required", Message: "other is required with above and below"})
+		}
+	default:
+		errs = append(errs, FieldError{
+			Field:    "place",
+			Rule:     "enum",
+			Value:    body.Place,
+			Expected: []string{db.RankTop, db.RankBottom, db.RankAbove, db.RankBelow},
+			Message:  "place must be top, bottom, above or below",
+		})
+	}
+	if len(errs) > 0 {
+		WriteValidation(w, errs)
+		return
+	}
+
+	if err := s.db.MoveRank(body.IDs, body.Place, body.Other, s.requestSession(r)); err != nil {
+		switch {
+		case errors.Is(err, db.ErrNotRanked):
+			WriteValidation(w, []FieldError{{Field: "other", Rule: "ranked", Value: body.Other, Message: err.Error()}})
+		case strings.Contains(err.Error(), "not found"):
+			WriteError(w, ErrNotFound, err.Error(), http.StatusNotFound)
+		case strings.Contains(err.Error(), "relative to itself"):
+			WriteValidation(w, []FieldError{{Field: "other", Rule: "distinct", Value: body.Other, Message: err.Error()}})
+		default:
+			if !writeRejection(w, err) {
+				requestLog(r).Error("move rank", "err", err)
+				WriteError(w, ErrInternal, "failed to rank issues", http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+	s.NotifyChange(r)
+
+	ids := make([]string, len(body.IDs))
+	for i, id := range body.IDs {
+		ids[i] = db.NormalizeIssueID(id)
+	}
+	issues, err := s.db.GetIssuesByIDs(ids)
+	if err != nil {
+		requestLog(r).Error("get ranked issues", "err", err)
+		WriteError(w, ErrInternal, "failed to fetch issues", http.StatusInternalServerError)
+		return
+	}
+	sort.Slice(issues, func(i, j int) bool { return issues[i].Rank < issues[j].Rank })
+	WriteSuccess(w, map[string]interface{}{"issues": IssuesToDTOs(issues)}, http.StatusOK)
+}
+
+// ============================================================================
+// DELETE /v1/issues/{id}/rank
+// ============================================================================
+
+// handleClearRank takes an issue out of the queue.
+func (s *Server) handleClearRank(w http.ResponseWriter, r *http.Request) {
+	issue, ok := s.lookupIssue(w, r)
+	if !ok {
+		return
+	}
+	if err := s.db.ClearRank([]string{issue.ID}, s.requestSession(r)); err != nil {
+		if !writeRejection(w, err) {
+			requestLog(r).Error("clear rank", "err", err, "id", issue.ID)
+			WriteError(w, ErrInternal, "failed to clear rank", http.StatusInternalServerError)
+		}
+		return
+	}
+	s.NotifyChange(r)
+
+	issue, ok = s.lookupIssue(w, r)
+	if !ok {
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"issue": IssueToDTO(issue)}, http.StatusOK)
+}
@@ -0,0 +1,59 @@
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestRankAPI(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	var a, b models.Issue
+	for _, issue := range []*models.Issue{&a, &b} {
+		issue.Title = "Issue for the queue"
+		if err := srv.db.CreateIssueLogged(issue, "ses_test123"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resp, _ := doJSON(t, ts, "POST", "/v1/rank", map[string]interface{}{"ids": []string{a.ID}, "place": "middle"})
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("bad place = %d, want 400", resp.StatusCode)
+	}
+	resp, _ = doJSON(t, ts, "POST", "/v1/rank", map[string]interface{}{"ids": []string{a.ID}, "place": "above", "other": b.ID})
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("above an unranked issue = %d, want 400", resp.StatusCode)
+	}
+	resp, _ = doJSON(t, ts, "POST", "/v1/rank", map[string]interface{}{"ids": []string{"td-nope"}, "place": "top"})
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown issue = %d, want 404", resp.StatusCode)
+	}
+
+	resp, env := doJSON(t, ts, "POST", "/v1/rank", map[string]interface{}{"ids": []string{b.ID, a.ID}, "place": "top"})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("move = %d", resp.StatusCode)
+	}
+	moved := env.Data.(map[string]interface{})["issues"].([]interface{})
+	if len(moved) != 2 || moved[0].(map[string]interface{})["id"] != b.ID {
+		t.Errorf("moved = %v", moved)
+	}
+
+	_, env = doJSON(t, ts, "GET", "/v1/rank", nil)
+	queue := env.Data.(map[string]interface{})["issues"].([]interface{})
+	if len(queue) != 2 || queue[0].(map[string]interface{})["id"] != b.ID || queue[1].(map[string]interface{})["id"] != a.ID {
+		t.Fatalf("queue = %v", queue)
+	}
+
+	resp, env = doJSON(t, ts, "DELETE", "/v1/issues/"+b.ID+"/rank", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("clear = %d", resp.StatusCode)
+	}
+	if got := env.Data.(map[string]interface{})["issue"].(map[string]interface{}); got["rank"] != 0.0 {
+		t.Errorf("cleared issue = %v", got["rank"])
+	}
+}
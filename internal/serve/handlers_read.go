@@ -1,12 +1,17 @@
 package serve
 
 import (
+	"fmt"
 	"net/http"
+	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/marcus/td/internal/clock"
 	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/dependency"
 	"github.com/marcus/td/internal/models"
 	"github.com/marcus/td/internal/query"
 	"github.com/marcus/td/internal/session"
@@ -19,14 +24,25 @@ import (
 
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	changeToken, _ := s.db.GetChangeToken()
+	changeTokens, _ := s.db.GetChangeTokens()
 
 	WriteSuccess(w, map[string]interface{}{
-		"status":       "ok",
-		"session_id":   s.sessionID,
-		"change_token": changeToken,
+		"status":        "ok",
+		"session_id":    s.requestSession(r),
+		"change_token":  changeToken,
+		"change_tokens": changeTokens,
 	}, http.StatusOK)
 }
 
+// collectionToken returns one collection's change token for list responses
+func (s *Server) collectionToken(collection string) string {
+	tokens, err := s.db.GetChangeTokens()
+	if err != nil {
+		return ""
+	}
+	return tokens[collection]
+}
+
 // ============================================================================
 // GET /v1/monitor
 // ============================================================================
@@ -34,11 +50,24 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 func (s *Server) handleMonitor(w http.ResponseWriter, r *http.Request) {
 	q := r.URL.Query()
 
+	// The snapshot covers every collection, so any change invalidates it
+	changeToken, _ := s.db.GetChangeToken()
+	changeTokens, _ := s.db.GetChangeTokens()
+	if checkNotModified(w, r, computeETag(r, s.requestSession(r), changeToken, changeTokens[db.CollectionSessions])) {
+		return
+	}
+
 	includeClosed := q.Get("include_closed") == "true"
 	sortMode := monitor.SortModeFromString(q.Get("sort"))
 	search := q.Get("search")
 	searchMode := q.Get("search_mode") // auto, text, tdq
 
+	fields, errs := ParseIssueFields(q)
+	if len(errs) > 0 {
+		WriteValidation(w, errs)
+		return
+	}
+
 	// For search_mode=tdq, validate the query first
 	if searchMode == "tdq" && search != "" {
 		_, err := query.Parse(search)
@@ -48,15 +77,14 @@ func (s *Server) handleMonitor(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	msg := monitor.FetchDataWithSearchMode(s.db, s.sessionID, time.Now().Add(-24*time.Hour), search, searchMode, includeClosed, sortMode)
+	msg := monitor.FetchDataWithSearchMode(s.db, s.requestSession(r), clock.Now().Add(-24*time.Hour), search, searchMode, includeClosed, sortMode)
 	dto := MonitorDataToDTO(&msg)
 
-	changeToken, _ := s.db.GetChangeToken()
-
 	WriteSuccess(w, map[string]interface{}{
-		"monitor":      dto,
-		"session_id":   s.sessionID,
-		"change_token": changeToken,
+		"monitor":       fields.Apply(dto),
+		"session_id":    s.requestSession(r),
+		"change_token":  changeToken,
+		"change_tokens": changeTokens,
 	}, http.StatusOK)
 }
 
@@ -67,6 +95,11 @@ func (s *Server) handleMonitor(w http.ResponseWriter, r *http.Request) {
 func (s *Server) handleListIssues(w http.ResponseWriter, r *http.Request) {
 	q := r.URL.Query()
 
+	issuesToken := s.collectionToken(db.CollectionIssues)
+	if checkNotModified(w, r, computeETag(r, s.requestSession(r), issuesToken)) {
+		return
+	}
+
 	// Parse pagination
 	limit := 200
 	if v := q.Get("limit"); v != "" {
@@ -87,93 +120,36 @@ func (s *Server) handleListIssues(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Parse filters
-	statuses := parseStatusParams(q["status"])
-	types := parseTypeParams(q["type"])
-	priorities := q["priority"]
-	search := q.Get("search")
-	searchMode := q.Get("search_mode") // auto, text, tdq
-	includeClosed := q.Get("include_closed") == "true"
-	sortBy := q.Get("sort")
-	order := q.Get("order")
-
-	// Determine sort column and direction
-	sortCol, sortDesc := resolveSortOptions(sortBy, order)
-
-	// If not include_closed and no explicit status filter, exclude closed
-	if !includeClosed && len(statuses) == 0 {
-		statuses = []models.Status{
-			models.StatusOpen,
-			models.StatusInProgress,
-			models.StatusBlocked,
-			models.StatusInReview,
-		}
-	}
-
-	// Build priority filter string (single value for ListIssuesOptions)
-	priorityFilter := ""
-	if len(priorities) == 1 {
-		priorityFilter = priorities[0]
-	}
-
-	// Handle TDQ search
-	if search != "" && (searchMode == "tdq" || searchMode == "auto" || searchMode == "") {
-		issues, err := s.tryTDQSearch(search, searchMode, statuses)
-		if err == nil {
-			// TDQ succeeded - apply type, priority filters and pagination manually
-			filtered := filterIssues(issues, types, priorities)
-			total := len(filtered)
-			paged := applyPagination(filtered, offset, limit)
-
-			WriteSuccess(w, map[string]interface{}{
-				"issues":   IssuesToDTOs(paged),
-				"total":    total,
-				"limit":    limit,
-				"offset":   offset,
-				"has_more": offset+limit < total,
-			}, http.StatusOK)
-			return
-		}
-		// TDQ failed
-		if searchMode == "tdq" {
-			// Explicit TDQ mode - return error
-			WriteError(w, ErrValidation, "invalid TDQ query: "+err.Error(), http.StatusBadRequest)
-			return
-		}
-		// Auto mode - fall through to text search
+	fields, errs := ParseIssueFields(q)
+	if len(errs) > 0 {
+		WriteValidation(w, errs)
+		return
 	}
 
-	// Text search or no search
-	opts := db.ListIssuesOptions{
-		Status:   statuses,
-		Type:     types,
-		Priority: priorityFilter,
-		Search:   search,
-		SortBy:   sortCol,
-		SortDesc: sortDesc,
+	// Filters, search and sort all become one TDQ query
+	tdq, errs := buildIssueListQuery(q)
+	if len(errs) > 0 {
+		WriteValidation(w, errs)
+		return
 	}
 
-	// Get all matching issues (we need total count)
-	allIssues, err := s.db.ListIssues(opts)
+	// Soft-deleted issues only show up when asked for (admin tooling)
+	withDeleted := q.Get("with_deleted") == "true"
+	allIssues, err := query.ExecuteQuery(s.db, tdq, s.requestSession(r), query.ExecuteOptions{WithDeleted: withDeleted})
 	if err != nil {
 		WriteError(w, ErrInternal, "failed to list issues: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// Apply multi-priority filter if more than one priority specified
-	if len(priorities) > 1 {
-		allIssues = filterByPriorities(allIssues, priorities)
-	}
-
 	total := len(allIssues)
 	paged := applyPagination(allIssues, offset, limit)
 
-	WriteSuccess(w, map[string]interface{}{
-		"issues":   issuesToDTOsNonNil(paged),
-		"total":    total,
-		"limit":    limit,
-		"offset":   offset,
-		"has_more": offset+limit < total,
+	WriteIssueList(w, paged, fields, s.closedViaOverride(r, paged...), s.inheritedPriorities(r), map[string]interface{}{
+		"total":        total,
+		"limit":        limit,
+		"offset":       offset,
+		"has_more":     offset+limit < total,
+		"change_token": issuesToken,
 	}, http.StatusOK)
 }
 
@@ -188,7 +164,25 @@ func (s *Server) handleGetIssue(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	issue, err := s.db.GetIssue(id)
+	if isShareRequest(r) {
+		s.handleSharedIssue(w, r, id)
+		return
+	}
+
+	q := r.URL.Query()
+	include, errs := parseIssueIncludes(q)
+	fields, fieldErrs := ParseIssueFields(q)
+	if errs = append(errs, fieldErrs...); len(errs) > 0 {
+		WriteValidation(w, errs)
+		return
+	}
+
+	// A soft-deleted issue is not found unless with_deleted=true
+	getIssue := s.db.GetIssue
+	if q.Get("with_deleted") == "true" {
+		getIssue = s.db.GetIssueWithDeleted
+	}
+	issue, err := getIssue(id)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			WriteError(w, ErrNotFound, "issue not found: "+id, http.StatusNotFound)
@@ -198,60 +192,172 @@ func (s *Server) handleGetIssue(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Fetch logs
-	logs, err := s.db.GetLogs(issue.ID, 0)
-	if err != nil {
-		logs = nil
+	s.revealIssue(r, issue, nil)
+	dto := IssueToDTO(issue)
+	dto.ClosedViaOverride = s.closedViaOverride(r, *issue)[issue.ID]
+	dto.withInheritance(s.inheritedPriorities(r))
+	data := map[string]interface{}{
+		"issue": fields.Issue(dto),
 	}
 
-	// Fetch comments
-	comments, err := s.db.GetComments(issue.ID)
-	if err != nil {
-		comments = nil
+	if include["logs"] {
+		logs, _ := s.db.GetLogs(issue.ID, 0)
+		data["logs"] = logsToDTOsNonNil(logs)
 	}
 
-	// Fetch latest handoff
-	handoff, _ := s.db.GetLatestHandoff(issue.ID)
+	if include["comments"] {
+		comments, _ := s.db.GetComments(issue.ID)
+		s.revealIssue(r, issue, comments)
+		data["comments"] = commentsToDTOsNonNil(comments)
+	}
 
-	// Fetch dependencies (outgoing: what this issue depends on)
-	depIDs, _ := s.db.GetDependencies(issue.ID)
-	dependencies := make([]DependencyDTO, 0, len(depIDs))
-	for _, depID := range depIDs {
-		dependencies = append(dependencies, DependencyDTO{
-			DepID:        db.DependencyID(issue.ID, depID, "depends_on"),
-			IssueID:      issue.ID,
-			DependsOnID:  depID,
-			RelationType: "depends_on",
-		})
+	if include["handoffs"] {
+		handoffs, _ := s.db.GetHandoffs(issue.ID)
+		dtos := make([]HandoffDTO, 0, len(handoffs))
+		for i := range handoffs {
+			dtos = append(dtos, HandoffToDTO(&handoffs[i]))
+		}
+		var latest *HandoffDTO
+		if len(dtos) > 0 {
+			latest = &dtos[len(dtos)-1]
+		}
+		data["handoffs"] = dtos
+		data["latest_handoff"] = latest
+	}
+
+	if include["dependencies"] {
+		// Outgoing: what this issue depends on
+		depIDs, _ := s.db.GetDependencies(issue.ID)
+		dependencies := make([]DependencyDTO, 0, len(depIDs))
+		for _, depID := range depIDs {
+			dependencies = append(dependencies, DependencyToDTO(&models.IssueDependency{
+				IssueID:      issue.ID,
+				DependsOnID:  depID,
+				RelationType: "depends_on",
+			}))
+		}
+
+		// Incoming: issues that depend on this one
+		blockedByIDs, _ := s.db.GetBlockedBy(issue.ID)
+		blockedBy := make([]DependencyDTO, 0, len(blockedByIDs))
+		for _, blockerID := range blockedByIDs {
+			blockedBy = append(blockedBy, DependencyDTO{
+				DepID:        db.DependencyID(blockerID, issue.ID, "depends_on"),
+				IssueID:      blockerID,
+				DependsOnID:  issue.ID,
+				RelationType: "depends_on",
+			})
+		}
+
+		data["dependencies"] = dependencies
+		data["blocked_by"] = blockedBy
 	}
 
-	// Fetch blocked_by (incoming: issues that depend on this one)
-	blockedByIDs, _ := s.db.GetBlockedBy(issue.ID)
-	blockedBy := make([]DependencyDTO, 0, len(blockedByIDs))
-	for _, blockerID := range blockedByIDs {
-		blockedBy = append(blockedBy, DependencyDTO{
-			DepID:        db.DependencyID(blockerID, issue.ID, "depends_on"),
-			IssueID:      blockerID,
-			DependsOnID:  issue.ID,
-			RelationType: "depends_on",
-		})
+	if include["references"] {
+		data["references"] = s.issueReferences(issue)
 	}
 
-	// Build response
-	var handoffDTO *HandoffDTO
-	if handoff != nil {
-		h := HandoffToDTO(handoff)
-		handoffDTO = &h
+	if include["children"] {
+		children, _ := s.db.ListIssues(db.ListIssuesOptions{ParentID: issue.ID})
+		dtos := issuesToDTOsNonNil(children)
+		flagged := s.closedViaOverride(r, children...)
+		for i := range dtos {
+			dtos[i].ClosedViaOverride = flagged[dtos[i].ID]
+		}
+		data["children"] = fields.Apply(dtos)
 	}
 
-	WriteSuccess(w, map[string]interface{}{
-		"issue":          IssueToDTO(issue),
-		"logs":           logsToDTOsNonNil(logs),
-		"comments":       commentsToDTOsNonNil(comments),
-		"latest_handoff": handoffDTO,
-		"dependencies":   dependencies,
-		"blocked_by":     blockedBy,
-	}, http.StatusOK)
+	if include["stack"] {
+		// The stack the issue belongs to, in landing order
+		bases, _ := s.db.GetStackBases()
+		stack := []IssueDTO{}
+		for _, id := range dependency.Chain(bases, issue.ID) {
+			if member, err := s.db.GetIssue(id); err == nil {
+				stack = append(stack, IssueToDTO(member))
+			}
+		}
+		data["stack"] = fields.Apply(stack)
+	}
+
+	if include["decisions"] {
+		decisions, _ := s.db.ListDecisions(db.DecisionFilter{IssueID: issue.ID})
+		data["decisions"] = DecisionsToDTOs(decisions)
+	}
+
+	if include["reworks"] {
+		reworks, _ := s.db.GetReworks(issue.ID)
+		data["reworks"] = ReworksToDTOs(reworks)
+	}
+
+	if include["contributors"] {
+		if effort, err := s.db.GetIssueEffort(issue); err == nil {
+			data["contributors"] = IssueEffortToDTO(effort).Contributors
+		}
+	}
+
+	// Sizes of the collections left out, so clients know what to fetch
+	if len(include) < len(issueIncludes) {
+		if c, err := s.db.CountIssueRelations(issue.ID); err == nil {
+			counts := map[string]int{}
+			if !include["logs"] {
+				counts["logs"] = c.Logs
+			}
+			if !include["comments"] {
+				counts["comments"] = c.Comments
+			}
+			if !include["handoffs"] {
+				counts["handoffs"] = c.Handoffs
+			}
+			if !include["dependencies"] {
+				counts["dependencies"] = c.Dependencies
+				counts["blocked_by"] = c.BlockedBy
+			}
+			if !include["children"] {
+				counts["children"] = c.Children
+			}
+			if !include["decisions"] {
+				counts["decisions"] = c.Decisions
+			}
+			if !include["reworks"] {
+				counts["reworks"] = c.Reworks
+			}
+			data["counts"] = counts
+		}
+	}
+
+	WriteSuccess(w, data, http.StatusOK)
+}
+
+// issueIncludes are the related collections GET /v1/issues/{id} can embed
+var issueIncludes = []string{"logs", "comments", "handoffs", "dependencies", "references", "children", "stack", "decisions", "reworks", "contributors"}
+
+// parseIssueIncludes reads ?include= (comma-separated, may be repeated).
+// "all" selects every collection; unknown names are validation errors.
+func parseIssueIncludes(q url.Values) (map[string]bool, []FieldError) {
+	include := map[string]bool{}
+	var errs []FieldError
+	for _, name := range strings.Split(strings.Join(q["include"], ","), ",") {
+		name = strings.TrimSpace(name)
+		switch {
+		case name == "":
+			continue
+		case name == "all":
+			for _, n := range issueIncludes {
+				include[n] = true
+			}
+		case slices.Contains(issueIncludes, name):
+			include[name] = true
+		default:
+			errs = append(errs, FieldError{
+				Field:    "include",
+				Rule:     "enum",
+				Value:    name,
+				Expected: issueIncludes,
+				Message:  fmt.Sprintf("unknown include %q", name),
+			})
+		}
+	}
+	return include, errs
 }
 
 // ============================================================================
@@ -267,7 +373,8 @@ func (s *Server) handleListSessions(w http.ResponseWriter, r *http.Request) {
 
 	WriteSuccess(w, map[string]interface{}{
 		"sessions":           SessionsToDTOs(sessions),
-		"current_session_id": s.sessionID,
+		"current_session_id": s.requestSession(r),
+		"change_token":       s.collectionToken(db.CollectionSessions),
 	}, http.StatusOK)
 }
 
@@ -285,6 +392,28 @@ func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
 	WriteSuccess(w, StatsToDTO(stats), http.StatusOK)
 }
 
+// ============================================================================
+// GET /v1/summary
+// ============================================================================
+
+// handleSummary serves a small overview for status pages and shell
+// prompts. It reads only the issue_cards read model and answers
+// conditional requests with 304, so polling it is cheap.
+func (s *Server) handleSummary(w http.ResponseWriter, r *http.Request) {
+	tokens, _ := s.db.GetChangeTokens()
+	if checkNotModified(w, r, computeETag(r, "", tokens[db.CollectionIssues], tokens[db.CollectionComments])) {
+		return
+	}
+
+	sum, err := s.db.GetSummary()
+	if err != nil {
+		WriteError(w, ErrInternal, "failed to get summary: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	WriteSuccess(w, SummaryToDTO(sum), http.StatusOK)
+}
+
 // ============================================================================
 // GET /v1/boards
 // ============================================================================
@@ -297,7 +426,8 @@ func (s *Server) handleListBoards(w http.ResponseWriter, r *http.Request) {
 	}
 
 	WriteSuccess(w, map[string]interface{}{
-		"boards": boardsToDTOsNonNil(boards),
+		"boards":       boardsToDTOsNonNil(boards),
+		"change_token": s.collectionToken(db.CollectionBoards),
 	}, http.StatusOK)
 }
 
@@ -322,8 +452,19 @@ func (s *Server) handleGetBoard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// A board view shows its issues, so both collections matter
+	tokens, _ := s.db.GetChangeTokens()
+	if checkNotModified(w, r, computeETag(r, s.requestSession(r), tokens[db.CollectionBoards], tokens[db.CollectionIssues])) {
+		return
+	}
+
 	q := r.URL.Query()
 	includeClosed := q.Get("include_closed") == "true"
+	fields, errs := ParseIssueFields(q)
+	if len(errs) > 0 {
+		WriteValidation(w, errs)
+		return
+	}
 
 	// Build status filter
 	var statusFilter []models.Status
@@ -372,18 +513,40 @@ func (s *Server) handleGetBoard(w http.ResponseWriter, r *http.Request) {
 		}
 	} else {
 		// Empty query - use GetBoardIssues
-		boardIssues, err = s.db.GetBoardIssues(board.ID, s.sessionID, statusFilter)
+		boardIssues, err = s.db.GetBoardIssues(board.ID, s.requestSession(r), statusFilter)
 		if err != nil {
 			WriteError(w, ErrInternal, "failed to get board issues: "+err.Error(), http.StatusInternalServerError)
 			return
 		}
 	}
 
+	// Card counts and override flags for every issue in one query each
+	ids := make([]string, len(boardIssues))
+	issues := make([]models.Issue, len(boardIssues))
+	for i, biv := range boardIssues {
+		ids[i] = biv.Issue.ID
+		issues[i] = biv.Issue
+	}
+	cards, err := s.db.GetIssueCards(ids)
+	if err != nil {
+		WriteError(w, ErrInternal, "failed to get issue cards: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	flagged := s.closedViaOverride(r, issues...)
+
 	// Convert board issues to DTOs
 	issueDTOs := make([]map[string]interface{}, 0, len(boardIssues))
 	for _, biv := range boardIssues {
+		var card *IssueCardDTO
+		if c, ok := cards[biv.Issue.ID]; ok {
+			dto := IssueCardToDTO(c)
+			card = &dto
+		}
+		dto := IssueToDTO(&biv.Issue)
+		dto.ClosedViaOverride = flagged[biv.Issue.ID]
 		issueDTOs = append(issueDTOs, map[string]interface{}{
-			"issue":        IssueToDTO(&biv.Issue),
+			"issue":        fields.Issue(dto),
+			"card":         card,
 			"board_id":     biv.BoardID,
 			"position":     biv.Position,
 			"has_position": biv.HasPosition,
@@ -401,156 +564,6 @@ func (s *Server) handleGetBoard(w http.ResponseWriter, r *http.Request) {
 // Helpers
 // ============================================================================
 
-// parseStatusParams converts repeated query params like ?status=open&status=closed
-// into a slice of models.Status values.
-func parseStatusParams(values []string) []models.Status {
-	var statuses []models.Status
-	for _, v := range values {
-		// Support comma-separated within a single param
-		for _, part := range strings.Split(v, ",") {
-			part = strings.TrimSpace(part)
-			if part == "" {
-				continue
-			}
-			status := models.NormalizeStatus(part)
-			if models.IsValidStatus(status) {
-				statuses = append(statuses, status)
-			}
-		}
-	}
-	return statuses
-}
-
-// parseTypeParams converts repeated query params into models.Type values.
-func parseTypeParams(values []string) []models.Type {
-	var types []models.Type
-	for _, v := range values {
-		for _, part := range strings.Split(v, ",") {
-			part = strings.TrimSpace(part)
-			if part == "" {
-				continue
-			}
-			t := models.NormalizeType(part)
-			if models.IsValidType(t) {
-				types = append(types, t)
-			}
-		}
-	}
-	return types
-}
-
-// resolveSortOptions converts sort/order query params to DB options.
-func resolveSortOptions(sortBy, order string) (string, bool) {
-	// Map API sort names to DB column names
-	colMap := map[string]string{
-		"priority": "priority",
-		"created":  "created_at",
-		"updated":  "updated_at",
-		"id":       "id",
-		"title":    "title",
-		"status":   "status",
-		"type":     "type",
-		"points":   "points",
-	}
-
-	col := "priority" // default
-	if mapped, ok := colMap[sortBy]; ok {
-		col = mapped
-	}
-
-	// Default direction depends on sort column
-	desc := false
-	switch col {
-	case "created_at", "updated_at":
-		desc = true // newest first by default
-	}
-
-	// Explicit order overrides default
-	if order == "asc" {
-		desc = false
-	} else if order == "desc" {
-		desc = true
-	}
-
-	return col, desc
-}
-
-// tryTDQSearch attempts a TDQ search and returns issues or an error.
-func (s *Server) tryTDQSearch(search, searchMode string, statuses []models.Status) ([]models.Issue, error) {
-	issues, err := query.Execute(s.db, search, s.sessionID, query.ExecuteOptions{})
-	if err != nil {
-		return nil, err
-	}
-
-	// Filter by statuses if provided
-	if len(statuses) > 0 {
-		statusSet := make(map[models.Status]bool)
-		for _, st := range statuses {
-			statusSet[st] = true
-		}
-		var filtered []models.Issue
-		for _, issue := range issues {
-			if statusSet[issue.Status] {
-				filtered = append(filtered, issue)
-			}
-		}
-		return filtered, nil
-	}
-
-	return issues, nil
-}
-
-// filterIssues applies type and priority filters to a slice of issues.
-func filterIssues(issues []models.Issue, types []models.Type, priorities []string) []models.Issue {
-	if len(types) == 0 && len(priorities) == 0 {
-		return issues
-	}
-
-	var typeSet map[models.Type]bool
-	if len(types) > 0 {
-		typeSet = make(map[models.Type]bool)
-		for _, t := range types {
-			typeSet[t] = true
-		}
-	}
-
-	var prioSet map[string]bool
-	if len(priorities) > 0 {
-		prioSet = make(map[string]bool)
-		for _, p := range priorities {
-			prioSet[p] = true
-		}
-	}
-
-	var result []models.Issue
-	for _, issue := range issues {
-		if typeSet != nil && !typeSet[issue.Type] {
-			continue
-		}
-		if prioSet != nil && !prioSet[string(issue.Priority)] {
-			continue
-		}
-		result = append(result, issue)
-	}
-	return result
-}
-
-// filterByPriorities filters issues by multiple priority values.
-func filterByPriorities(issues []models.Issue, priorities []string) []models.Issue {
-	prioSet := make(map[string]bool)
-	for _, p := range priorities {
-		prioSet[p] = true
-	}
-
-	var result []models.Issue
-	for _, issue := range issues {
-		if prioSet[string(issue.Priority)] {
-			result = append(result, issue)
-		}
-	}
-	return result
-}
-
 // applyPagination applies offset and limit to a slice of issues.
 func applyPagination(issues []models.Issue, offset, limit int) []models.Issue {
 	if offset >= len(issues) {
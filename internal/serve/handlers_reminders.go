@@ -0,0 +1,144 @@
+package serve
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/marcus/td/internal/dateparse"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+// ============================================================================
+// GET /v1/reminders
+// ============================================================================
+
+// handleListReminders lists reminders soonest first. ?status= takes
+// pending (default), fired, cancelled or all; ?issue_id= narrows to one
+// issue.
+func (s *Server) handleListReminders(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := db.ReminderFilter{IssueID: q.Get("issue_id")}
+	if filter.IssueID != "" {
+		filter.IssueID = db.NormalizeIssueID(filter.IssueID)
+	}
+
+	switch status := q.Get("status"); status {
+	case "", string(models.ReminderPending):
+		filter.Status = []models.ReminderStatus{models.ReminderPending}
+	case string(models.ReminderFired), string(models.ReminderCancelled):
+		filter.Status = []models.ReminderStatus{models.ReminderStatus(status)}
+	case "all":
+	default:
+		WriteValidation(w, []FieldError{{
+			Field:    "status",
+			Rule:     "enum",
+			Value:    status,
+			Expected: []string{"pending", "fired", "cancelled", "all"},
+			Message:  fmt.Sprintf("unknown status %q", status),
+		}})
+		return
+	}
+
+	reminders, err := s.db.ListReminders(filter)
+	if err != nil {
+		requestLog(r).Error("list reminders", "err", err)
+		WriteError(w, ErrInternal, "failed to list reminders", http.StatusInternalServerError)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"reminders": RemindersToDTOs(reminders)}, http.StatusOK)
+}
+
+// ============================================================================
+// POST /v1/reminders
+// ============================================================================
+
+// ReminderCreateBody is the JSON body for creating a reminder. When is an
+// offset (3d, 2h), a date (friday, 2026-03-01) or an RFC 3339 timestamp.
+type ReminderCreateBody struct {
+	IssueID string `json:"issue_id"`
+	When    string `json:"when"`
+	Message string `json:"message"`
+}
+
+// handleCreateReminder sets a reminder on an issue.
+func (s *Server) handleCreateReminder(w http.ResponseWriter, r *http.Request) {
+	var body ReminderCreateBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		WriteError(w, ErrValidation, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var errs []FieldError
+	if strings.TrimSpace(body.IssueID) == "" {
+		errs = append(errs, FieldError{Field: "issue_id", Rule: "required", Message: "issue_id is required"})
+	}
+	now := dateparse.Now()
+	remindAt, err := dateparse.ParseTimeFrom(body.When, now)
+	if err != nil {
+		errs = append(errs, FieldError{Field: "when", Rule: "format", Value: body.When, Message: err.Error()})
+	} else if !remindAt.After(now) {
+		errs = append(errs, FieldError{Field: "when", Rule: "future", Value: body.When, Message: "reminder time is in the past"})
+	}
+	if len(errs) > 0 {
+		WriteValidation(w, errs)
+		return
+	}
+
+	issueID := db.NormalizeIssueID(body.IssueID)
+	if _, err := s.db.GetIssue(issueID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			WriteError(w, ErrNotFound, fmt.Sprintf("issue not found: %s", body.IssueID), http.StatusNotFound)
+		} else {
+			requestLog(r).Error("lookup issue", "err", err, "id", issueID)
+			WriteError(w, ErrInternal, "failed to verify issue", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	reminder := &models.Reminder{
+		IssueID:   issueID,
+		SessionID: s.requestSession(r),
+		Message:   strings.TrimSpace(body.Message),
+		RemindAt:  remindAt,
+	}
+	if err := s.db.CreateReminder(reminder); err != nil {
+		requestLog(r).Error("create reminder", "err", err)
+		WriteError(w, ErrInternal, "failed to create reminder", http.StatusInternalServerError)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"reminder": ReminderToDTO(reminder)}, http.StatusCreated)
+}
+
+// ============================================================================
+// DELETE /v1/reminders/{id}
+// ============================================================================
+
+// handleCancelReminder cancels a pending reminder. Reminders that already
+// fired or were cancelled return 409.
+func (s *Server) handleCancelReminder(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if _, err := s.db.GetReminder(id); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			WriteError(w, ErrNotFound, "reminder not found: "+id, http.StatusNotFound)
+		} else {
+			requestLog(r).Error("get reminder", "err", err, "id", id)
+			WriteError(w, ErrInternal, "failed to fetch reminder", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if err := s.db.CancelReminder(id); err != nil {
+		if errors.Is(err, db.ErrReminderNotPending) {
+			WriteError(w, ErrConflict, err.Error(), http.StatusConflict)
+			return
+		}
+		requestLog(r).Error("cancel reminder", "err", err, "id", id)
+		WriteError(w, ErrInternal, "failed to cancel reminder", http.StatusInternalServerError)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"cancelled": true}, http.StatusOK)
+}
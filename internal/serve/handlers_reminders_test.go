@@ -0,0 +1,87 @@
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestReminders_CreateListCancel(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	issue := &models.Issue{Title: "Flaky CI"}
+	if err := srv.db.CreateIssue(issue); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, env := doJSON(t, ts, "POST", "/v1/reminders", map[string]interface{}{"issue_id": issue.ID, "when": "3d", "message": "check CI flake"})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	r := env.Data.(map[string]interface{})["reminder"].(map[string]interface{})
+	id := r["id"].(string)
+	if r["status"] != "pending" || r["issue_id"] != issue.ID || r["message"] != "check CI flake" {
+		t.Errorf("reminder = %v", r)
+	}
+
+	resp, env = doJSON(t, ts, "GET", "/v1/reminders?issue_id="+issue.ID, nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("list status = %d", resp.StatusCode)
+	}
+	if list := env.Data.(map[string]interface{})["reminders"].([]interface{}); len(list) != 1 {
+		t.Errorf("reminders = %v", list)
+	}
+
+	resp, env = doJSON(t, ts, "DELETE", "/v1/reminders/"+id, nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("cancel status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	resp, _ = doJSON(t, ts, "DELETE", "/v1/reminders/"+id, nil)
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("second cancel status = %d", resp.StatusCode)
+	}
+	resp, _ = doJSON(t, ts, "DELETE", "/v1/reminders/rm-missing", nil)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("missing reminder status = %d", resp.StatusCode)
+	}
+
+	_, env = doJSON(t, ts, "GET", "/v1/reminders", nil)
+	if list := env.Data.(map[string]interface{})["reminders"].([]interface{}); len(list) != 0 {
+		t.Errorf("pending reminders after cancel = %v", list)
+	}
+	_, env = doJSON(t, ts, "GET", "/v1/reminders?status=all", nil)
+	if list := env.Data.(map[string]interface{})["reminders"].([]interface{}); len(list) != 1 {
+		t.Errorf("all reminders = %v", list)
+	}
+}
+
+func TestReminders_CreateValidation(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	tests := []struct {
+		body map[string]interface{}
+		want int
+	}{
+		{map[string]interface{}{"when": "3d"}, http.StatusBadRequest},
+		{map[string]interface{}{"issue_id": "td-x", "when": "someday"}, http.StatusBadRequest},
+		{map[string]interface{}{"issue_id": "td-x", "when": "2001-01-01T00:00:00Z"}, http.StatusBadRequest},
+		{map[string]interface{}{"issue_id": "td-missing", "when": "1h"}, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		resp, env := doJSON(t, ts, "POST", "/v1/reminders", tt.body)
+		if resp.StatusCode != tt.want {
+			t.Errorf("POST %v status = %d, want %d (%+v)", tt.body, resp.StatusCode, tt.want, env.Error)
+		}
+	}
+
+	resp, _ := doJSON(t, ts, "GET", "/v1/reminders?status=snoozed", nil)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("bad status filter = %d", resp.StatusCode)
+	}
+}
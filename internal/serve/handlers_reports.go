@@ -0,0 +1,442 @@
+package serve
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/marcus/td/internal/aging"
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/dateparse"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/dedupe"
+	"github.com/marcus/td/internal/forecast"
+	"github.com/marcus/td/internal/heatmap"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/query"
+)
+
+// ============================================================================
+// GET /v1/reports/forecast
+// ============================================================================
+
+// handleForecast runs a Monte Carlo completion forecast for the issues
+// matching ?query=. Optional ?trials=, ?parallelism= and ?history= (days)
+// tune the simulation; ?seed= makes it repeatable.
+func (s *Server) handleForecast(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	var errs []FieldError
+	tdq := q.Get("query")
+	if tdq == "" {
+		errs = append(errs, FieldError{Field: "query", Rule: "required", Message: "query is required"})
+	} else if _, err := query.Parse(tdq); err != nil {
+		errs = append(errs, FieldError{Field: "query", Rule: "tdq", Value: tdq, Message: "invalid TDQ query: " + err.Error()})
+	}
+	intParam := func(name string, min, max int) int {
+		v := q.Get(name)
+		if v == "" {
+			return 0
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < min || n > max {
+			errs = append(errs, FieldError{
+				Field:   name,
+				Rule:    "range",
+				Value:   v,
+				Message: fmt.Sprintf("%s must be a number from %d to %d", name, min, max),
+			})
+			return 0
+		}
+		return n
+	}
+	opts := forecast.Options{
+		Trials:      intParam("trials", 100, forecast.MaxTrials),
+		Parallelism: intParam("parallelism", 1, forecast.MaxParallelism),
+	}
+	history := intParam("history", 1, forecast.MaxHistoryDays)
+	if v := q.Get("seed"); v != "" {
+		seed, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			errs = append(errs, FieldError{Field: "seed", Rule: "integer", Value: v, Message: "seed must be an integer"})
+		}
+		opts.Rand = rand.New(rand.NewSource(seed))
+	}
+	if len(errs) > 0 {
+		WriteValidation(w, errs)
+		return
+	}
+
+	issues, err := query.Execute(s.db, tdq, s.requestSession(r), query.ExecuteOptions{})
+	if err != nil {
+		WriteError(w, ErrValidation, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := forecast.Forecast(s.db, issues, history, opts, clock.Now())
+	if errors.Is(err, forecast.ErrNoHistory) {
+		WriteError(w, ErrValidation, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if err != nil {
+		requestLog(r).Error("forecast", "err", err, "query", tdq)
+		WriteError(w, ErrInternal, "failed to compute forecast", http.StatusInternalServerError)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{
+		"query":    tdq,
+		"forecast": result,
+	}, http.StatusOK)
+}
+
+// ============================================================================
+// GET /v1/reports/rework
+// ============================================================================
+
+// defaultReworkMin is how many reopens make an issue frequently reopened
+const defaultReworkMin = 2
+
+// handleRework lists issues reopened at least ?min= times (default 2),
+// most reworked first, with the project's overall rework rate.
+func (s *Server) handleRework(w http.ResponseWriter, r *http.Request) {
+	minCount := defaultReworkMin
+	if v := r.URL.Query().Get("min"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 1000 {
+			WriteValidation(w, []FieldError{{
+				Field:   "min",
+				Rule:    "range",
+				Value:   v,
+				Message: "min must be a number from 1 to 1000",
+			}})
+			return
+		}
+		minCount = n
+	}
+
+	issues, err := s.db.ListReworkedIssues(minCount)
+	if err != nil {
+		requestLog(r).Error("rework report", "err", err)
+		WriteError(w, ErrInternal, "failed to compute rework report", http.StatusInternalServerError)
+		return
+	}
+	stats, err := s.db.GetExtendedStats()
+	if err != nil {
+		requestLog(r).Error("rework report stats", "err", err)
+		WriteError(w, ErrInternal, "failed to compute rework report", http.StatusInternalServerError)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{
+		"min":             minCount,
+		"rework_rate":     stats.ReworkRate,
+		"total_reworks":   stats.TotalReworks,
+		"reworked_issues": stats.ReworkedIssues,
+		"issues":          ReworkedIssuesToDTOs(issues),
+	}, http.StatusOK)
+}
+
+// ============================================================================
+// GET /v1/reports/overrides
+// ============================================================================
+
+// handleOverrides lists bypass-prevention overrides, newest first, with
+// counts by kind and session. ?kind=, ?session= and ?issue= filter the
+// list; ?since= (YYYY-MM-DD) drops older overrides.
+func (s *Server) handleOverrides(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := db.OverrideFilter{
+		IssueID:   q.Get("issue"),
+		SessionID: q.Get("session"),
+		Kind:      models.OverrideKind(q.Get("kind")),
+	}
+
+	var errs []FieldError
+	if filter.Kind != "" && !models.IsValidOverrideKind(filter.Kind) {
+		errs = append(errs, FieldError{
+			Field:    "kind",
+			Rule:     "enum",
+			Value:    string(filter.Kind),
+			Expected: models.OverrideKinds(),
+			Message:  "unknown override kind",
+		})
+	}
+	if v := q.Get("since"); v != "" {
+		since, err := time.ParseInLocation("2006-01-02", v, dateparse.Location())
+		if err != nil {
+			errs = append(errs, FieldError{Field: "since", Rule: "date", Value: v, Message: "since must be a YYYY-MM-DD date"})
+		}
+		filter.Since = since
+	}
+	if len(errs) > 0 {
+		WriteValidation(w, errs)
+		return
+	}
+
+	overrides, err := s.db.ListOverrides(filter)
+	if err != nil {
+		requestLog(r).Error("overrides report", "err", err)
+		WriteError(w, ErrInternal, "failed to list overrides", http.StatusInternalServerError)
+		return
+	}
+	byKind := make(map[string]int)
+	bySession := make(map[string]int)
+	for _, o := range overrides {
+		byKind[string(o.Kind)]++
+		bySession[o.SessionID]++
+	}
+	WriteSuccess(w, map[string]interface{}{
+		"total":      len(overrides),
+		"by_kind":    byKind,
+		"by_session": bySession,
+		"overrides":  OverridesToDTOs(overrides),
+	}, http.StatusOK)
+}
+
+// ============================================================================
+// GET /v1/reports/contributors
+// ============================================================================
+
+// handleContributors breaks the work logged on each issue down by session
+// and work session, issues with the most contributors first. ?min= (default
+// 1) drops issues with fewer contributing sessions; ?session= and ?issue=
+// filter; ?since= (YYYY-MM-DD) counts only newer logs.
+func (s *Server) handleContributors(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := db.EffortFilter{
+		IssueID:   q.Get("issue"),
+		SessionID: q.Get("session"),
+	}
+
+	var errs []FieldError
+	minCount := 1
+	if v := q.Get("min"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 1000 {
+			errs = append(errs, FieldError{Field: "min", Rule: "range", Value: v, Message: "min must be a number from 1 to 1000"})
+		}
+		minCount = n
+	}
+	if v := q.Get("since"); v != "" {
+		since, err := time.ParseInLocation("2006-01-02", v, dateparse.Location())
+		if err != nil {
+			errs = append(errs, FieldError{Field: "since", Rule: "date", Value: v, Message: "since must be a YYYY-MM-DD date"})
+		}
+		filter.Since = since
+	}
+	if len(errs) > 0 {
+		WriteValidation(w, errs)
+		return
+	}
+
+	efforts, err := s.db.ListIssueEfforts(filter)
+	if err != nil {
+		requestLog(r).Error("contributors report", "err", err)
+		WriteError(w, ErrInternal, "failed to compute contributors report", http.StatusInternalServerError)
+		return
+	}
+	issues := []models.IssueEffort{}
+	for _, e := range efforts {
+		if len(e.Contributors) >= minCount {
+			issues = append(issues, e)
+		}
+	}
+	WriteSuccess(w, map[string]interface{}{
+		"min":    minCount,
+		"issues": IssueEffortsToDTOs(issues),
+	}, http.StatusOK)
+}
+
+// ============================================================================
+// GET /v1/reports/heatmap
+// ============================================================================
+
+// handleHeatmap counts actions per day for a calendar heatmap, every day of
+// the last ?months= (default 12) included, with each day broken down by
+// session and action type. ?session= and ?type= filter.
+func (s *Server) handleHeatmap(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	opts := heatmap.Options{SessionID: q.Get("session"), Type: q.Get("type")}
+	if v := q.Get("months"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > heatmap.MaxMonths {
+			WriteValidation(w, []FieldError{{
+				Field:   "months",
+				Rule:    "range",
+				Value:   v,
+				Message: fmt.Sprintf("months must be a number from 1 to %d", heatmap.MaxMonths),
+			}})
+			return
+		}
+		opts.Months = n
+	}
+
+	report, err := heatmap.Compute(s.db, opts, clock.Now())
+	if err != nil {
+		requestLog(r).Error("heatmap report", "err", err)
+		WriteError(w, ErrInternal, "failed to compute heatmap", http.StatusInternalServerError)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"heatmap": report}, http.StatusOK)
+}
+
+// ============================================================================
+// GET /v1/reports/aging
+// ============================================================================
+
+// handleAging reports how long each in-flight issue has been in its current
+// status against historical norms. ?history= sets the days of history and
+// ?outlier= the percentile beyond which an issue is flagged.
+func (s *Server) handleAging(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	var errs []FieldError
+	var opts aging.Options
+	for _, p := range []struct {
+		name     string
+		min, max int
+		dst      *int
+	}{
+		{"history", 1, aging.MaxHistoryDays, &opts.HistoryDays},
+		{"outlier", 50, 99, &opts.OutlierPercentile},
+	} {
+		v := q.Get(p.name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < p.min || n > p.max {
+			errs = append(errs, FieldError{
+				Field:   p.name,
+				Rule:    "range",
+				Value:   v,
+				Message: fmt.Sprintf("%s must be a number from %d to %d", p.name, p.min, p.max),
+			})
+			continue
+		}
+		*p.dst = n
+	}
+	if len(errs) > 0 {
+		WriteValidation(w, errs)
+		return
+	}
+
+	report, err := aging.Compute(s.db, opts, clock.Now())
+	if err != nil {
+		requestLog(r).Error("aging report", "err", err)
+		WriteError(w, ErrInternal, "failed to compute aging report", http.StatusInternalServerError)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"aging": report}, http.StatusOK)
+}
+
+// ============================================================================
+// GET /v1/reports/duplicates
+// ============================================================================
+
+// handleDuplicates returns clusters of likely-duplicate open issues. The
+// report from the last background scan is served unless ?refresh=true or
+// a non-default ?threshold= (0 to 1) asks for a fresh scan.
+func (s *Server) handleDuplicates(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	threshold := dedupe.DefaultThreshold
+	if v := q.Get("threshold"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f <= 0 || f > 1 {
+			WriteValidation(w, []FieldError{{
+				Field:   "threshold",
+				Rule:    "range",
+				Value:   v,
+				Message: "threshold must be a number above 0 and at most 1",
+			}})
+			return
+		}
+		threshold = f
+	}
+	refresh := q.Get("refresh") == "true" || q.Get("refresh") == "1"
+
+	var report *dedupe.Report
+	var err error
+	switch {
+	case threshold != dedupe.DefaultThreshold:
+		report, err = dedupe.Compute(s.db, threshold, clock.Now())
+	case refresh:
+		report, err = s.scanDuplicates()
+	default:
+		if report = s.duplicates.get(); report == nil {
+			report, err = s.scanDuplicates()
+		}
+	}
+	if err != nil {
+		requestLog(r).Error("duplicate report", "err", err)
+		WriteError(w, ErrInternal, "failed to compute duplicate report", http.StatusInternalServerError)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"duplicates": report}, http.StatusOK)
+}
+
+// ============================================================================
+// POST /v1/reports/duplicates/merge
+// ============================================================================
+
+// MergeDuplicatesBody is the request body for merging a duplicate cluster
+type MergeDuplicatesBody struct {
+	Keep       string   `json:"keep"`
+	Duplicates []string `json:"duplicates"`
+}
+
+// handleMergeDuplicates folds the duplicates into the kept issue and
+// closes them
+func (s *Server) handleMergeDuplicates(w http.ResponseWriter, r *http.Request) {
+	var body MergeDuplicatesBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		WriteError(w, ErrValidation, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var errs []FieldError
+	if body.Keep == "" {
+		errs = append(errs, FieldError{Field: "keep", Rule: "required", Message: "keep is required"})
+	}
+	if len(body.Duplicates) == 0 {
+		errs = append(errs, FieldError{Field: "duplicates", Rule: "required", Message: "duplicates must list at least one issue"})
+	}
+	for _, id := range body.Duplicates {
+		if id == body.Keep {
+			errs = append(errs, FieldError{Field: "duplicates", Rule: "distinct", Value: id, Message: "duplicates must not include keep"})
+		}
+	}
+	if len(errs) > 0 {
+		WriteValidation(w, errs)
+		return
+	}
+
+	for _, id := range append([]string{body.Keep}, body.Duplicates...) {
+		issue, err := s.db.GetIssue(id)
+		if err != nil {
+			WriteError(w, ErrNotFound, fmt.Sprintf("issue not found: %s", id), http.StatusNotFound)
+			return
+		}
+		if issue.Status == models.StatusClosed && id != body.Keep {
+			WriteError(w, ErrConflict, fmt.Sprintf("%s is already closed", id), http.StatusConflict)
+			return
+		}
+	}
+
+	result, err := dedupe.Merge(s.db, body.Keep, body.Duplicates, s.requestSession(r))
+	s.duplicates.set(nil)
+	if err != nil {
+		if writeRejection(w, err) {
+			return
+		}
+		requestLog(r).Error("merge duplicates", "err", err, "keep", body.Keep)
+		WriteError(w, ErrInternal, "failed to merge duplicates", http.StatusInternalServerError)
+		return
+	}
+	s.NotifyChange(r)
+	WriteSuccess(w, map[string]interface{}{"merge": result}, http.StatusOK)
+}
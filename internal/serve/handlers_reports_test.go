@@ -0,0 +1,380 @@
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestForecast(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	base := "/v1/reports/forecast?query=" + url.QueryEscape("labels ~ export")
+	path := base + "&trials=500&seed=1"
+
+	todo := &models.Issue{Title: "Export button", Labels: []string{"export"}}
+	if err := srv.db.CreateIssue(todo); err != nil {
+		t.Fatal(err)
+	}
+	resp, _ := doJSON(t, ts, "GET", path, nil)
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("no history status = %d, want 422", resp.StatusCode)
+	}
+
+	done := &models.Issue{Title: "Export endpoint", Labels: []string{"export"}}
+	if err := srv.db.CreateIssue(done); err != nil {
+		t.Fatal(err)
+	}
+	closedAt := time.Now()
+	done.Status, done.ClosedAt = models.StatusClosed, &closedAt
+	if err := srv.db.UpdateIssue(done); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, env := doJSON(t, ts, "GET", path, nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	f := env.Data.(map[string]interface{})["forecast"].(map[string]interface{})
+	if f["issues"] != float64(2) || f["closed"] != float64(1) || f["remaining"] != float64(1) || f["trials"] != float64(500) {
+		t.Errorf("forecast = %v", f)
+	}
+	if p := f["projections"].([]interface{}); len(p) != 4 || p[0].(map[string]interface{})["percentile"] != float64(50) {
+		t.Errorf("projections = %v", p)
+	}
+
+	for _, bad := range []string{
+		"/v1/reports/forecast",
+		"/v1/reports/forecast?query=" + url.QueryEscape("status = = open"),
+		base + "&trials=5",
+		base + "&parallelism=0",
+		base + "&seed=x",
+	} {
+		if resp, _ := doJSON(t, ts, "GET", bad, nil); resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", bad, resp.StatusCode)
+		}
+	}
+}
+
+func TestAging(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	issue := &models.Issue{Title: "Waiting on review"}
+	if err := srv.db.CreateIssueLogged(issue, "ses_a"); err != nil {
+		t.Fatal(err)
+	}
+	issue.Status = models.StatusInReview
+	if err := srv.db.UpdateIssueLogged(issue, "ses_a", models.ActionReview); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, env := doJSON(t, ts, "GET", "/v1/reports/aging?history=30", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	a := env.Data.(map[string]interface{})["aging"].(map[string]interface{})
+	items := a["items"].([]interface{})
+	if len(items) != 1 || a["history_days"] != float64(30) || len(a["norms"].([]interface{})) != 3 {
+		t.Fatalf("aging = %v", a)
+	}
+	if it := items[0].(map[string]interface{}); it["id"] != issue.ID || it["status"] != "in_review" || it["percentile"] != nil || it["outlier"] != false {
+		t.Errorf("item = %v", it)
+	}
+
+	for _, bad := range []string{"?history=0", "?outlier=10", "?outlier=x"} {
+		if resp, _ := doJSON(t, ts, "GET", "/v1/reports/aging"+bad, nil); resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", bad, resp.StatusCode)
+		}
+	}
+}
+
+func TestHeatmap(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	issue := &models.Issue{Title: "Issue with some activity"}
+	if err := srv.db.CreateIssueLogged(issue, "ses_a"); err != nil {
+		t.Fatal(err)
+	}
+	issue.Status = models.StatusInProgress
+	if err := srv.db.UpdateIssueLogged(issue, "ses_b", models.ActionStart); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, env := doJSON(t, ts, "GET", "/v1/reports/heatmap?months=1&session=ses_b", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	h := env.Data.(map[string]interface{})["heatmap"].(map[string]interface{})
+	days := h["days"].([]interface{})
+	if h["months"] != float64(1) || h["total"] != float64(1) || len(days) < 28 {
+		t.Fatalf("heatmap = %v", h)
+	}
+	today := days[len(days)-1].(map[string]interface{})
+	if today["count"] != float64(1) || today["level"] != float64(4) || today["types"].(map[string]interface{})["start"] != float64(1) {
+		t.Errorf("today = %v", today)
+	}
+
+	for _, bad := range []string{"?months=0", "?months=99", "?months=x"} {
+		if resp, _ := doJSON(t, ts, "GET", "/v1/reports/heatmap"+bad, nil); resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", bad, resp.StatusCode)
+		}
+	}
+}
+
+func TestDuplicatesReportAndMerge(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	var ids []string
+	for _, title := range []string{"Login page crashes on Safari", "Login page crashes in Safari", "Add dark mode toggle"} {
+		issue := &models.Issue{Title: title}
+		if err := srv.db.CreateIssueLogged(issue, "ses_a"); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, issue.ID)
+	}
+
+	resp, env := doJSON(t, ts, "GET", "/v1/reports/duplicates", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	report := env.Data.(map[string]interface{})["duplicates"].(map[string]interface{})
+	clusters := report["clusters"].([]interface{})
+	if report["scanned"] != float64(3) || len(clusters) != 1 {
+		t.Fatalf("report = %v", report)
+	}
+	if keep := clusters[0].(map[string]interface{})["keep"]; keep != ids[0] {
+		t.Errorf("keep = %v, want %s", keep, ids[0])
+	}
+
+	for _, bad := range []string{"threshold=0", "threshold=2", "threshold=x"} {
+		if resp, _ := doJSON(t, ts, "GET", "/v1/reports/duplicates?"+bad, nil); resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", bad, resp.StatusCode)
+		}
+	}
+	if resp, _ := doJSON(t, ts, "POST", "/v1/reports/duplicates/merge", map[string]interface{}{"keep": ids[0], "duplicates": []string{ids[0]}}); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("self merge status = %d, want 400", resp.StatusCode)
+	}
+	if resp, _ := doJSON(t, ts, "POST", "/v1/reports/duplicates/merge", map[string]interface{}{"keep": ids[0], "duplicates": []string{"td-missing"}}); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("missing duplicate status = %d, want 404", resp.StatusCode)
+	}
+
+	resp, env = doJSON(t, ts, "POST", "/v1/reports/duplicates/merge", map[string]interface{}{"keep": ids[0], "duplicates": []string{ids[1]}})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("merge status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	if dup, _ := srv.db.GetIssue(ids[1]); dup.Status != models.StatusClosed {
+		t.Errorf("duplicate status = %s", dup.Status)
+	}
+
+	// The merge dropped the cached report, so the closed duplicate is gone
+	_, env = doJSON(t, ts, "GET", "/v1/reports/duplicates", nil)
+	report = env.Data.(map[string]interface{})["duplicates"].(map[string]interface{})
+	if clusters := report["clusters"].([]interface{}); len(clusters) != 0 {
+		t.Errorf("clusters after merge = %v", clusters)
+	}
+}
+
+func TestReworkReport(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	issue := &models.Issue{Title: "Token refresh"}
+	if err := srv.db.CreateIssue(issue); err != nil {
+		t.Fatal(err)
+	}
+	issue.Status = models.StatusClosed
+	issue.ReviewerSession = "ses_reviewer"
+	if err := srv.db.UpdateIssue(issue); err != nil {
+		t.Fatal(err)
+	}
+	reopen := "/v1/issues/" + issue.ID + "/reopen"
+
+	if resp, _ := doJSON(t, ts, "POST", reopen, map[string]string{"category": "whim"}); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("bad category status = %d, want 400", resp.StatusCode)
+	}
+	if resp, env := doJSON(t, ts, "POST", reopen, map[string]string{"category": "incomplete", "reason": "no expiry test"}); resp.StatusCode != http.StatusOK {
+		t.Fatalf("reopen status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	if _, env := doJSON(t, ts, "POST", "/v1/issues/"+issue.ID+"/close", nil); !env.OK {
+		t.Fatalf("close: %+v", env.Error)
+	}
+	if _, env := doJSON(t, ts, "POST", reopen, nil); !env.OK {
+		t.Fatalf("second reopen: %+v", env.Error)
+	}
+
+	_, env := doJSON(t, ts, "GET", "/v1/issues/"+issue.ID+"?include=reworks", nil)
+	reworks, _ := env.Data.(map[string]interface{})["reworks"].([]interface{})
+	if len(reworks) != 2 {
+		t.Fatalf("reworks = %v, want 2", env.Data)
+	}
+	first := reworks[0].(map[string]interface{})
+	if first["category"] != "incomplete" || first["approved"] != true || first["reason"] != "no expiry test" {
+		t.Errorf("first rework = %v, want approved incomplete with reason", first)
+	}
+	if second := reworks[1].(map[string]interface{}); second["category"] != "other" || second["approved"] != false {
+		t.Errorf("second rework = %v, want unapproved other", second)
+	}
+
+	resp, env := doJSON(t, ts, "GET", "/v1/reports/rework", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("report status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	data := env.Data.(map[string]interface{})
+	issues := data["issues"].([]interface{})
+	if len(issues) != 1 || issues[0].(map[string]interface{})["count"] != float64(2) || data["rework_rate"] != float64(1) {
+		t.Errorf("report = %v, want the issue with 2 reopens at rate 1", data)
+	}
+	if resp, _ := doJSON(t, ts, "GET", "/v1/reports/rework?min=0", nil); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("min=0 status = %d, want 400", resp.StatusCode)
+	}
+
+	_, env = doJSON(t, ts, "GET", "/v1/stats", nil)
+	if stats := env.Data.(map[string]interface{}); stats["total_reworks"] != float64(2) {
+		t.Errorf("stats total_reworks = %v, want 2", stats["total_reworks"])
+	}
+}
+
+func TestContributorsReport(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	shared := &models.Issue{Title: "Retry failed payments"}
+	solo := &models.Issue{Title: "Show payment retries"}
+	for _, issue := range []*models.Issue{shared, solo} {
+		if err := srv.db.CreateIssue(issue); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, l := range []models.Log{
+		{IssueID: shared.ID, SessionID: "ses_a", WorkSessionID: "ws_1"},
+		{IssueID: shared.ID, SessionID: "ses_b", WorkSessionID: "ws_2"},
+		{IssueID: shared.ID, SessionID: "ses_b"},
+		{IssueID: solo.ID, SessionID: "ses_a"},
+	} {
+		l.Message = "progress"
+		if err := srv.db.AddLog(&l); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, env := doJSON(t, ts, "GET", "/v1/reports/contributors?min=2", nil)
+	if !env.OK {
+		t.Fatalf("contributors report: %+v", env.Error)
+	}
+	issues := env.Data.(map[string]interface{})["issues"].([]interface{})
+	if len(issues) != 1 {
+		t.Fatalf("issues = %v, want only the shared issue", issues)
+	}
+	first := issues[0].(map[string]interface{})
+	if first["issue_id"] != shared.ID || first["work_sessions"] != float64(2) || first["logs"] != float64(3) {
+		t.Errorf("shared = %v, want 3 logs in 2 work sessions", first)
+	}
+	if top := first["contributors"].([]interface{})[0].(map[string]interface{}); top["session_id"] != "ses_b" || top["logs"] != float64(2) {
+		t.Errorf("top contributor = %v, want ses_b with 2 logs", top)
+	}
+
+	_, env = doJSON(t, ts, "GET", "/v1/reports/contributors?session=ses_b", nil)
+	if issues := env.Data.(map[string]interface{})["issues"].([]interface{}); len(issues) != 1 {
+		t.Errorf("?session=ses_b issues = %v, want 1", issues)
+	}
+
+	_, env = doJSON(t, ts, "GET", "/v1/issues/"+solo.ID+"?include=contributors", nil)
+	contributors, _ := env.Data.(map[string]interface{})["contributors"].([]interface{})
+	if len(contributors) != 1 || contributors[0].(map[string]interface{})["session_id"] != "ses_a" {
+		t.Errorf("include=contributors = %v, want ses_a", env.Data)
+	}
+
+	for _, bad := range []string{"?min=0", "?since=yesterday"} {
+		if resp, _ := doJSON(t, ts, "GET", "/v1/reports/contributors"+bad, nil); resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s status = %d, want 400", bad, resp.StatusCode)
+		}
+	}
+}
+
+func TestOverridesReport(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	// The web session creates and implements a minor issue, then approves it
+	minor := &models.Issue{Title: "Fix typo in README", Minor: true}
+	normal := &models.Issue{Title: "Reviewed by someone else", Minor: true, CreatorSession: "ses_other"}
+	for _, issue := range []*models.Issue{minor, normal} {
+		if err := srv.db.CreateIssue(issue); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, step := range []string{"start", "review"} {
+		if _, env := doJSON(t, ts, "POST", "/v1/issues/"+minor.ID+"/"+step, nil); !env.OK {
+			t.Fatalf("%s: %+v", step, env.Error)
+		}
+	}
+	_, env := doJSON(t, ts, "POST", "/v1/issues/"+minor.ID+"/approve", map[string]string{"reason": "one-word typo"})
+	if !env.OK {
+		t.Fatalf("approve: %+v", env.Error)
+	}
+	issue := env.Data.(map[string]interface{})["issue"].(map[string]interface{})
+	if issue["closed_via_override"] != true {
+		t.Errorf("approve response closed_via_override = %v, want true", issue["closed_via_override"])
+	}
+	if _, env := doJSON(t, ts, "POST", "/v1/issues/"+normal.ID+"/close", nil); !env.OK {
+		t.Fatalf("close: %+v", env.Error)
+	}
+
+	// The flag shows up wherever the issue is read
+	_, env = doJSON(t, ts, "GET", "/v1/issues/"+minor.ID, nil)
+	if got := env.Data.(map[string]interface{})["issue"].(map[string]interface{})["closed_via_override"]; got != true {
+		t.Errorf("get closed_via_override = %v, want true", got)
+	}
+	_, env = doJSON(t, ts, "GET", "/v1/issues?status=closed", nil)
+	listed := env.Data.(map[string]interface{})["issues"].([]interface{})
+	if len(listed) != 2 {
+		t.Fatalf("listed %d closed issues, want 2", len(listed))
+	}
+	for _, item := range listed {
+		dto := item.(map[string]interface{})
+		if want := dto["id"] == minor.ID; dto["closed_via_override"] != want {
+			t.Errorf("list %v closed_via_override = %v, want %v", dto["id"], dto["closed_via_override"], want)
+		}
+	}
+
+	_, env = doJSON(t, ts, "GET", "/v1/reports/overrides", nil)
+	if !env.OK {
+		t.Fatalf("overrides report: %+v", env.Error)
+	}
+	data := env.Data.(map[string]interface{})
+	overrides := data["overrides"].([]interface{})
+	if data["total"] != float64(1) || len(overrides) != 1 {
+		t.Fatalf("report = %+v, want one override", data)
+	}
+	o := overrides[0].(map[string]interface{})
+	if o["issue_id"] != minor.ID || o["kind"] != "minor_self_approve" || o["justification"] != "one-word typo" || o["session_id"] != "ses_test123" {
+		t.Errorf("override = %+v", o)
+	}
+	if byKind := data["by_kind"].(map[string]interface{}); byKind["minor_self_approve"] != float64(1) {
+		t.Errorf("by_kind = %v", byKind)
+	}
+
+	if _, env := doJSON(t, ts, "GET", "/v1/reports/overrides?kind=force_start", nil); env.Data.(map[string]interface{})["total"] != float64(0) {
+		t.Errorf("?kind=force_start total = %v, want 0", env.Data.(map[string]interface{})["total"])
+	}
+	for _, bad := range []string{"?kind=steal", "?since=last-week"} {
+		if resp, _ := doJSON(t, ts, "GET", "/v1/reports/overrides"+bad, nil); resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s status = %d, want 400", bad, resp.StatusCode)
+		}
+	}
+}
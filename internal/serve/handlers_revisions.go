@@ -0,0 +1,98 @@
+package serve
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/marcus/td/internal/db"
+)
+
+// ============================================================================
+// GET /v1/issues/{id}/revisions
+// ============================================================================
+
+// handleListRevisions returns the edits made to an issue's description,
+// acceptance criteria and comments, newest first, each with a line diff.
+func (s *Server) handleListRevisions(w http.ResponseWriter, r *http.Request) {
+	issueID := db.NormalizeIssueID(r.PathValue("id"))
+	issue, err := s.db.GetIssue(issueID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			WriteError(w, ErrNotFound, fmt.Sprintf("issue not found: %s", issueID), http.StatusNotFound)
+		} else {
+			requestLog(r).Error("get issue for revisions", "err", err, "id", issueID)
+			WriteError(w, ErrInternal, "failed to fetch issue", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	revisions, err := s.db.ListRevisions(issueID)
+	if err != nil {
+		requestLog(r).Error("list revisions", "err", err, "id", issueID)
+		WriteError(w, ErrInternal, "failed to list revisions", http.StatusInternalServerError)
+		return
+	}
+	if issue.Confidential {
+		var texts []*string
+		for i := range revisions {
+			texts = append(texts, &revisions[i].Before, &revisions[i].After)
+		}
+		if _, err := s.db.RevealText(issue, s.requestViewer(r), texts...); err != nil {
+			requestLog(r).Warn("reveal revisions", "err", err, "id", issueID)
+		}
+	}
+	WriteSuccess(w, map[string]interface{}{"revisions": RevisionsToDTOs(revisions)}, http.StatusOK)
+}
+
+// ============================================================================
+// POST /v1/issues/{id}/revisions/{revision_id}/revert
+// ============================================================================
+
+// handleRevertRevision restores the text a revision recorded. The replaced
+// text becomes a new revision.
+func (s *Server) handleRevertRevision(w http.ResponseWriter, r *http.Request) {
+	issueID := db.NormalizeIssueID(r.PathValue("id"))
+	revisionID := r.PathValue("revision_id")
+
+	rev, err := s.db.GetRevision(revisionID)
+	if err != nil || rev.IssueID != issueID {
+		WriteError(w, ErrNotFound, fmt.Sprintf("revision %s not found on issue %s", revisionID, issueID), http.StatusNotFound)
+		return
+	}
+	if issue, ok := s.lookupIssue(w, r); !ok || !s.requireConfidentialAccess(w, r, issue) {
+		return
+	}
+	if err := s.db.CheckClosedEdit(issueID, s.requestSession(r), "revert", closedOverride(r)); err != nil {
+		if !writeRejection(w, err) {
+			requestLog(r).Error("check closed issue", "err", err, "id", issueID)
+			WriteError(w, ErrInternal, "failed to revert revision", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if _, err := s.writeDB(r).RevertRevision(rev.ID, s.requestSession(r)); err != nil {
+		if writeRejection(w, err) {
+			return
+		}
+		if strings.Contains(err.Error(), "not found") {
+			// The comment or issue the revision belongs to is gone
+			WriteError(w, ErrConflict, err.Error(), http.StatusConflict)
+		} else {
+			requestLog(r).Error("revert revision", "err", err, "id", rev.ID)
+			WriteError(w, ErrInternal, "failed to revert revision", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	s.NotifyChange(r)
+
+	issue, err := s.db.GetIssue(issueID)
+	if err != nil {
+		requestLog(r).Error("get issue after revert", "err", err, "id", issueID)
+		WriteError(w, ErrInternal, "failed to fetch issue", http.StatusInternalServerError)
+		return
+	}
+	s.revealIssue(r, issue, nil)
+	WriteSuccess(w, map[string]interface{}{"reverted": rev.ID, "issue": IssueToDTO(issue)}, http.StatusOK)
+}
@@ -0,0 +1,64 @@
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestRevisions_EditListRevert(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	issue := &models.Issue{Title: "Parser", Acceptance: "parses input"}
+	if err := srv.db.CreateIssue(issue); err != nil {
+		t.Fatal(err)
+	}
+	comment := &models.Comment{IssueID: issue.ID, Text: "looks good"}
+	if err := srv.db.AddComment(comment); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, env := doJSON(t, ts, "PATCH", "/v1/issues/"+issue.ID, map[string]interface{}{"acceptance": "parses input\nreports errors"})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("update status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	resp, env = doJSON(t, ts, "PATCH", "/v1/issues/"+issue.ID+"/comments/"+comment.ID, map[string]interface{}{"text": "looks good, ship it"})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("edit comment status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	if resp, _ := doJSON(t, ts, "PATCH", "/v1/issues/"+issue.ID+"/comments/"+comment.ID, map[string]interface{}{"text": " "}); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("empty comment status = %d, want 400", resp.StatusCode)
+	}
+	if resp, _ := doJSON(t, ts, "PATCH", "/v1/issues/td-other/comments/"+comment.ID, map[string]interface{}{"text": "x"}); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("comment on wrong issue status = %d, want 404", resp.StatusCode)
+	}
+
+	resp, env = doJSON(t, ts, "GET", "/v1/issues/"+issue.ID+"/revisions", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("list status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	revs := env.Data.(map[string]interface{})["revisions"].([]interface{})
+	if len(revs) != 2 {
+		t.Fatalf("revisions = %v", revs)
+	}
+	acceptance := revs[1].(map[string]interface{})
+	if acceptance["field"] != "acceptance" || !strings.Contains(acceptance["diff"].(string), "+reports errors") {
+		t.Errorf("acceptance revision = %v", acceptance)
+	}
+
+	resp, env = doJSON(t, ts, "POST", "/v1/issues/"+issue.ID+"/revisions/"+acceptance["id"].(string)+"/revert", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("revert status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	if got := env.Data.(map[string]interface{})["issue"].(map[string]interface{})["acceptance"]; got != "parses input" {
+		t.Errorf("acceptance after revert = %v", got)
+	}
+	if resp, _ := doJSON(t, ts, "POST", "/v1/issues/"+issue.ID+"/revisions/rv-missing/revert", nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown revision status = %d, want 404", resp.StatusCode)
+	}
+}
@@ -0,0 +1,83 @@
+package serve
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/share"
+)
+
+// sharedIssueFields is the read-only view a share token exposes: the issue
+// itself, without session IDs, sprint or scheduling internals.
+var sharedIssueFields, _ = ParseIssueFields(url.Values{"fields": {
+	"title,description,status,type,priority,points,labels,acceptance,due_date,created_at,updated_at,closed_at",
+}})
+
+// isShareRequest reports whether r is GET /v1/issues/{id}?share_token=...,
+// which authMiddleware lets through for handleSharedIssue to verify.
+func isShareRequest(r *http.Request) bool {
+	if r.Method != http.MethodGet || !r.URL.Query().Has("share_token") {
+		return false
+	}
+	id, ok := strings.CutPrefix(r.URL.Path, "/v1/issues/")
+	return ok && id != "" && !strings.Contains(id, "/")
+}
+
+// ============================================================================
+// GET /v1/issues/{id}?share_token=
+// ============================================================================
+
+// handleSharedIssue serves a single issue to a share token holder. Bad
+// signatures, unknown, revoked and expired links all get the same 401 so a
+// token reveals nothing about why it stopped working.
+func (s *Server) handleSharedIssue(w http.ResponseWriter, r *http.Request, id string) {
+	w.Header().Set("Cache-Control", "no-store")
+	unauthorized := func() {
+		WriteError(w, ErrUnauthorized, "invalid or revoked share token", http.StatusUnauthorized)
+	}
+
+	cfg, err := config.Load(s.baseDir)
+	if err != nil {
+		requestLog(r).Error("load config", "err", err)
+		WriteError(w, ErrInternal, "failed to load config", http.StatusInternalServerError)
+		return
+	}
+
+	issue, err := s.db.GetIssueWithDeleted(id)
+	if err != nil {
+		unauthorized()
+		return
+	}
+
+	linkID, err := share.Verify(cfg.ShareSecret, r.URL.Query().Get("share_token"), issue.ID)
+	if err != nil {
+		unauthorized()
+		return
+	}
+	link, err := s.db.GetShareLink(linkID)
+	if err != nil || link.IssueID != issue.ID || !link.Active(clock.Now()) {
+		unauthorized()
+		return
+	}
+	if issue.DeletedAt != nil {
+		WriteError(w, ErrNotFound, "issue not found: "+id, http.StatusNotFound)
+		return
+	}
+
+	data := map[string]interface{}{
+		"issue": sharedIssueFields.Issue(IssueToDTO(issue)),
+		"share": map[string]interface{}{
+			"id":               link.ID,
+			"include_comments": link.IncludeComments,
+			"expires_at":       nullableTime(link.ExpiresAt),
+		},
+	}
+	if link.IncludeComments {
+		comments, _ := s.db.GetComments(issue.ID)
+		data["comments"] = commentsToDTOsNonNil(comments)
+	}
+	WriteSuccess(w, data, http.StatusOK)
+}
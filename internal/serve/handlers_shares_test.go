@@ -0,0 +1,103 @@
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/share"
+)
+
+func TestSharedIssue(t *testing.T) {
+	tmpDir := t.TempDir()
+	database, err := db.Initialize(tmpDir)
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+
+	// Share tokens work without the server's bearer token
+	srv := NewServer(database, tmpDir, "ses_test123", ServeConfig{Token: "secret-token"})
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	issue := &models.Issue{Title: "Shared with a stakeholder"}
+	other := &models.Issue{Title: "Not shared with anyone"}
+	for _, i := range []*models.Issue{issue, other} {
+		if err := database.CreateIssue(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := database.AddComment(&models.Comment{IssueID: issue.ID, SessionID: "ses_a", Text: "ship it"}); err != nil {
+		t.Fatal(err)
+	}
+
+	secret, err := config.GetShareSecret(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newLink := func(l *models.ShareLink) string {
+		t.Helper()
+		if err := database.CreateShareLink(l); err != nil {
+			t.Fatal(err)
+		}
+		return share.Sign(secret, l.ID, l.IssueID)
+	}
+
+	plain := newLink(&models.ShareLink{IssueID: issue.ID})
+	withComments := newLink(&models.ShareLink{IssueID: issue.ID, IncludeComments: true})
+	past := time.Now().Add(-time.Hour)
+	expired := newLink(&models.ShareLink{IssueID: issue.ID, ExpiresAt: &past})
+
+	resp, env := doJSON(t, ts, "GET", "/v1/issues/"+issue.ID+"?share_token="+plain, nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	data := env.Data.(map[string]interface{})
+	got := data["issue"].(map[string]interface{})
+	if got["title"] != issue.Title {
+		t.Errorf("issue = %v", got)
+	}
+	if _, ok := got["creator_session"]; ok {
+		t.Error("shared issue should not expose session fields")
+	}
+	if _, ok := data["comments"]; ok {
+		t.Error("comments included without --comments")
+	}
+
+	_, env = doJSON(t, ts, "GET", "/v1/issues/"+issue.ID+"?share_token="+withComments+"&include=logs", nil)
+	data = env.Data.(map[string]interface{})
+	if c, _ := data["comments"].([]interface{}); len(c) != 1 {
+		t.Errorf("comments = %v", data["comments"])
+	}
+	if _, ok := data["logs"]; ok {
+		t.Error("?include= must not widen a shared view")
+	}
+
+	for name, path := range map[string]string{
+		"expired":     "/v1/issues/" + issue.ID + "?share_token=" + expired,
+		"other issue": "/v1/issues/" + other.ID + "?share_token=" + plain,
+		"forged":      "/v1/issues/" + issue.ID + "?share_token=" + strings.Split(plain, ".")[0] + ".forged",
+	} {
+		if resp, _ := doJSON(t, ts, "GET", path, nil); resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("%s: status = %d, want 401", name, resp.StatusCode)
+		}
+	}
+
+	if err := database.RevokeShareLink(strings.Split(plain, ".")[0]); err != nil {
+		t.Fatal(err)
+	}
+	if resp, _ := doJSON(t, ts, "GET", "/v1/issues/"+issue.ID+"?share_token="+plain, nil); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("revoked: status = %d, want 401", resp.StatusCode)
+	}
+
+	// A share token does not open up other routes
+	if resp, _ := doJSON(t, ts, "GET", "/v1/issues/"+issue.ID+"/comments?share_token="+withComments, nil); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("sub-route: status = %d, want 401", resp.StatusCode)
+	}
+}
@@ -0,0 +1,188 @@
+package serve
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/capacity"
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/retro"
+)
+
+// ============================================================================
+// GET /v1/sprints/{id}/capacity
+// ============================================================================
+
+// handleSprintCapacity compares the points committed to a sprint with each
+// session's recent throughput. {id} is a sprint name or "current"; ?window=
+// sets the throughput window in days.
+func (s *Server) handleSprintCapacity(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	window := capacity.DefaultWindowDays
+	if v := r.URL.Query().Get("window"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > capacity.MaxWindowDays {
+			WriteValidation(w, []FieldError{{
+				Field:   "window",
+				Rule:    "range",
+				Value:   v,
+				Message: fmt.Sprintf("window must be a number of days from 1 to %d", capacity.MaxWindowDays),
+			}})
+			return
+		}
+		window = n
+	}
+
+	now := clock.Now()
+	sprint, ok := s.lookupSprint(w, id, now)
+	if !ok {
+		return
+	}
+
+	report, err := capacity.Compute(s.db, sprint, window, now)
+	if err != nil {
+		requestLog(r).Error("sprint capacity", "err", err, "sprint", sprint.Name)
+		WriteError(w, ErrInternal, "failed to compute capacity", http.StatusInternalServerError)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"capacity": report}, http.StatusOK)
+}
+
+// ============================================================================
+// GET /v1/sprints/{id}/retro
+// ============================================================================
+
+// handleSprintRetro returns a sprint's retro items grouped by kind, with
+// the sprint's delivery metrics. {id} is a sprint name or "current".
+func (s *Server) handleSprintRetro(w http.ResponseWriter, r *http.Request) {
+	sprint, ok := s.lookupSprint(w, r.PathValue("id"), clock.Now())
+	if !ok {
+		return
+	}
+
+	report, err := retro.Compute(s.db, sprint)
+	if err != nil {
+		requestLog(r).Error("sprint retro", "err", err, "sprint", sprint.Name)
+		WriteError(w, ErrInternal, "failed to build retro report", http.StatusInternalServerError)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"retro": report}, http.StatusOK)
+}
+
+// ============================================================================
+// POST /v1/sprints/{id}/retro
+// ============================================================================
+
+// RetroItemBody is the JSON body for adding a retro item
+type RetroItemBody struct {
+	Kind string `json:"kind"`
+	Text string `json:"text"`
+}
+
+// handleAddRetroItem records a retro item. An action item also creates a
+// follow-up task, returned as "issue".
+func (s *Server) handleAddRetroItem(w http.ResponseWriter, r *http.Request) {
+	var body RetroItemBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		WriteError(w, ErrValidation, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var errs []FieldError
+	kind := models.NormalizeRetroKind(body.Kind)
+	if !models.IsValidRetroKind(kind) {
+		errs = append(errs, FieldError{
+			Field:   "kind",
+			Rule:    "enum",
+			Value:   body.Kind,
+			Message: "kind must be went_well, needs_improvement or action_item",
+		})
+	}
+	if strings.TrimSpace(body.Text) == "" {
+		errs = append(errs, FieldError{Field: "text", Rule: "required", Message: "text is required"})
+	}
+	if len(errs) > 0 {
+		WriteValidation(w, errs)
+		return
+	}
+
+	sprint, ok := s.lookupSprint(w, r.PathValue("id"), clock.Now())
+	if !ok {
+		return
+	}
+
+	item, err := retro.AddItem(s.db, sprint.Name, kind, body.Text, s.requestSession(r))
+	if err != nil {
+		requestLog(r).Error("add retro item", "err", err, "sprint", sprint.Name)
+		WriteError(w, ErrInternal, "failed to add retro item", http.StatusInternalServerError)
+		return
+	}
+
+	data := map[string]interface{}{"item": item}
+	if item.IssueID != "" {
+		s.NotifyChange(r)
+		if issue, err := s.db.GetIssue(item.IssueID); err == nil {
+			data["issue"] = IssueToDTO(issue)
+		}
+	}
+	WriteSuccess(w, data, http.StatusCreated)
+}
+
+// ============================================================================
+// DELETE /v1/sprints/{id}/retro/{item_id}
+// ============================================================================
+
+// handleDeleteRetroItem removes a retro item. An action item's issue is
+// left in place.
+func (s *Server) handleDeleteRetroItem(w http.ResponseWriter, r *http.Request) {
+	sprint, ok := s.lookupSprint(w, r.PathValue("id"), clock.Now())
+	if !ok {
+		return
+	}
+
+	itemID := r.PathValue("item_id")
+	item, err := s.db.GetRetroItem(itemID)
+	if err != nil || item.Sprint != sprint.Name {
+		WriteError(w, ErrNotFound, "retro item not found: "+itemID, http.StatusNotFound)
+		return
+	}
+	if err := s.db.DeleteRetroItem(item.ID); err != nil {
+		requestLog(r).Error("delete retro item", "err", err, "id", item.ID)
+		WriteError(w, ErrInternal, "failed to delete retro item", http.StatusInternalServerError)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"deleted": true}, http.StatusOK)
+}
+
+// lookupSprint resolves a sprint name or "current", writing a 404 or 500
+// when it can't.
+func (s *Server) lookupSprint(w http.ResponseWriter, id string, now time.Time) (models.Sprint, bool) {
+	sprints, err := config.GetSprints(s.baseDir)
+	if err != nil {
+		slog.Error("load sprints", "err", err)
+		WriteError(w, ErrInternal, "failed to load sprints", http.StatusInternalServerError)
+		return models.Sprint{}, false
+	}
+
+	if id == "current" {
+		if sprint, ok := capacity.CurrentSprint(sprints, now); ok {
+			return sprint, true
+		}
+	} else {
+		for _, sp := range sprints {
+			if sp.Name == id {
+				return sp, true
+			}
+		}
+	}
+	WriteError(w, ErrNotFound, "sprint not found: "+id, http.StatusNotFound)
+	return models.Sprint{}, false
+}
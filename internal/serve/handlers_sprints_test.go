@@ -0,0 +1,133 @@
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/models"
+)
+
+func TestSprintCapacity(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	now := time.Now()
+	sprint := models.Sprint{Name: "s1", Start: now.AddDate(0, 0, -3).Format("2006-01-02"), End: now.AddDate(0, 0, 3).Format("2006-01-02")}
+	if err := config.SetSprint(srv.baseDir, sprint); err != nil {
+		t.Fatal(err)
+	}
+
+	done := &models.Issue{Title: "Shipped last week", Points: 3}
+	planned := &models.Issue{Title: "Big sprint item", Points: 8, Sprint: "s1"}
+	for _, issue := range []*models.Issue{done, planned} {
+		if err := srv.db.CreateIssue(issue); err != nil {
+			t.Fatal(err)
+		}
+		issue.ImplementerSession = "ses_a"
+	}
+	closedAt := now.AddDate(0, 0, -7)
+	done.Status, done.ClosedAt = models.StatusClosed, &closedAt
+	planned.Status = models.StatusInProgress
+	for _, issue := range []*models.Issue{done, planned} {
+		if err := srv.db.UpdateIssue(issue); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, id := range []string{"s1", "current"} {
+		resp, env := doJSON(t, ts, "GET", "/v1/sprints/"+id+"/capacity?window=14", nil)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("%s: status = %d: %+v", id, resp.StatusCode, env.Error)
+		}
+		c := env.Data.(map[string]interface{})["capacity"].(map[string]interface{})
+		sessions := c["sessions"].([]interface{})
+		if c["committed"] != float64(8) || c["remaining_days"] != float64(4) || c["over_committed"] != true || len(sessions) != 1 {
+			t.Errorf("%s: capacity = %v", id, c)
+		}
+		if s := sessions[0].(map[string]interface{}); s["session"] != "ses_a" || s["closed_points"] != float64(3) || s["over_committed"] != true {
+			t.Errorf("%s: session = %v", id, s)
+		}
+	}
+
+	if resp, _ := doJSON(t, ts, "GET", "/v1/sprints/nope/capacity", nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown sprint status = %d, want 404", resp.StatusCode)
+	}
+	if resp, _ := doJSON(t, ts, "GET", "/v1/sprints/s1/capacity?window=0", nil); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("bad window status = %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestSprintRetro(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	if err := config.SetSprint(srv.baseDir, models.Sprint{Name: "s1", Start: "2026-03-02", End: "2026-03-13"}); err != nil {
+		t.Fatal(err)
+	}
+	shipped := &models.Issue{Title: "Shipped", Points: 5, Sprint: "s1"}
+	slipped := &models.Issue{Title: "Slipped", Points: 3, Sprint: "s1"}
+	for _, issue := range []*models.Issue{shipped, slipped} {
+		if err := srv.db.CreateIssue(issue); err != nil {
+			t.Fatal(err)
+		}
+	}
+	shipped.Status = models.StatusClosed
+	for _, issue := range []*models.Issue{shipped, slipped} {
+		if err := srv.db.UpdateIssue(issue); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resp, _ := doJSON(t, ts, "POST", "/v1/sprints/s1/retro", map[string]interface{}{"kind": "meh", "text": ""})
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("invalid item status = %d, want 400", resp.StatusCode)
+	}
+	resp, _ = doJSON(t, ts, "POST", "/v1/sprints/nope/retro", map[string]interface{}{"kind": "went-well", "text": "x"})
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown sprint status = %d, want 404", resp.StatusCode)
+	}
+
+	resp, env := doJSON(t, ts, "POST", "/v1/sprints/s1/retro", map[string]interface{}{"kind": "went-well", "text": "Pairing on the parser"})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("add status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	wellID := env.Data.(map[string]interface{})["item"].(map[string]interface{})["id"].(string)
+
+	resp, env = doJSON(t, ts, "POST", "/v1/sprints/s1/retro", map[string]interface{}{"kind": "action", "text": "Add flaky test quarantine"})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("add action status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	issue, _ := env.Data.(map[string]interface{})["issue"].(map[string]interface{})
+	if issue == nil || issue["title"] != "Add flaky test quarantine" {
+		t.Fatalf("action item issue = %v", env.Data)
+	}
+
+	resp, env = doJSON(t, ts, "GET", "/v1/sprints/s1/retro", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("report status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	report := env.Data.(map[string]interface{})["retro"].(map[string]interface{})
+	metrics := report["metrics"].(map[string]interface{})
+	if metrics["closed"] != float64(1) || metrics["carried_over"] != float64(1) || metrics["done_points"] != float64(5) {
+		t.Errorf("metrics = %v", metrics)
+	}
+	actions := report["action_items"].([]interface{})
+	if len(report["went_well"].([]interface{})) != 1 || len(actions) != 1 {
+		t.Fatalf("report = %v", report)
+	}
+	if a := actions[0].(map[string]interface{}); a["issue_id"] != issue["id"] || a["issue_status"] != "open" {
+		t.Errorf("action item = %v", a)
+	}
+
+	if resp, _ := doJSON(t, ts, "DELETE", "/v1/sprints/s1/retro/"+wellID, nil); resp.StatusCode != http.StatusOK {
+		t.Errorf("delete status = %d", resp.StatusCode)
+	}
+	if resp, _ := doJSON(t, ts, "DELETE", "/v1/sprints/s1/retro/"+wellID, nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("second delete status = %d, want 404", resp.StatusCode)
+	}
+}
@@ -0,0 +1,101 @@
+package serve
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/subscription"
+)
+
+// ============================================================================
+// GET /v1/subscriptions
+// ============================================================================
+
+// handleListSubscriptions lists subscriptions, oldest first.
+func (s *Server) handleListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	subs, err := config.GetSubscriptions(s.baseDir)
+	if err != nil {
+		requestLog(r).Error("list subscriptions", "err", err)
+		WriteError(w, ErrInternal, "failed to list subscriptions", http.StatusInternalServerError)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"subscriptions": SubscriptionsToDTOs(subs)}, http.StatusOK)
+}
+
+// ============================================================================
+// POST /v1/subscriptions
+// ============================================================================
+
+// SubscriptionCreateBody is the JSON body for creating a subscription. At
+// least one of label, epic and query is required; url makes it a webhook.
+type SubscriptionCreateBody struct {
+	Name   string `json:"name"`
+	Label  string `json:"label"`
+	Epic   string `json:"epic"`
+	Query  string `json:"query"`
+	URL    string `json:"url"`
+	Secret string `json:"secret"`
+}
+
+// handleCreateSubscription adds a subscription.
+func (s *Server) handleCreateSubscription(w http.ResponseWriter, r *http.Request) {
+	var body SubscriptionCreateBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		WriteError(w, ErrValidation, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sub := models.Subscription{
+		Name:   body.Name,
+		Label:  body.Label,
+		Epic:   body.Epic,
+		Query:  body.Query,
+		URL:    body.URL,
+		Secret: body.Secret,
+	}
+	if sub.Secret != "" && sub.URL == "" {
+		WriteValidation(w, []FieldError{{Field: "secret", Rule: "requires", Message: "secret needs a url"}})
+		return
+	}
+	if err := subscription.Validate(sub); err != nil {
+		WriteError(w, ErrValidation, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if sub.Epic != "" {
+		if _, err := s.db.GetIssue(sub.Epic); err != nil {
+			WriteError(w, ErrNotFound, fmt.Sprintf("epic not found: %s", sub.Epic), http.StatusNotFound)
+			return
+		}
+	}
+
+	if err := config.AddSubscription(s.baseDir, &sub); err != nil {
+		requestLog(r).Error("create subscription", "err", err)
+		WriteError(w, ErrInternal, "failed to create subscription", http.StatusInternalServerError)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"subscription": SubscriptionToDTO(&sub)}, http.StatusCreated)
+}
+
+// ============================================================================
+// DELETE /v1/subscriptions/{id}
+// ============================================================================
+
+// handleDeleteSubscription removes a subscription. Event streams already
+// using it keep their filter until they reconnect.
+func (s *Server) handleDeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	removed, err := config.RemoveSubscription(s.baseDir, id)
+	if err != nil {
+		requestLog(r).Error("delete subscription", "err", err, "id", id)
+		WriteError(w, ErrInternal, "failed to delete subscription", http.StatusInternalServerError)
+		return
+	}
+	if !removed {
+		WriteError(w, ErrNotFound, fmt.Sprintf("subscription not found: %s", id), http.StatusNotFound)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"deleted": true}, http.StatusOK)
+}
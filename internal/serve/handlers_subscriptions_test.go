@@ -0,0 +1,84 @@
+package serve
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSubscriptions(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	srv.sseHub.Start(ctx)
+	defer srv.sseHub.Stop()
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	for name, body := range map[string]map[string]interface{}{
+		"no criteria":   {"url": "https://example.com/hook"},
+		"bad query":     {"query": "type = = bug"},
+		"bad url":       {"label": "security", "url": "ftp://example.com"},
+		"secret no url": {"label": "security", "secret": "s3cret"},
+	} {
+		if resp, _ := doJSON(t, ts, "POST", "/v1/subscriptions", body); resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", name, resp.StatusCode)
+		}
+	}
+	if resp, _ := doJSON(t, ts, "POST", "/v1/subscriptions", map[string]interface{}{"epic": "td-missing"}); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("missing epic: status = %d, want 404", resp.StatusCode)
+	}
+
+	resp, env := doJSON(t, ts, "POST", "/v1/subscriptions", map[string]interface{}{
+		"label": "security", "url": "https://example.com/hook", "secret": "s3cret",
+	})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	sub := env.Data.(map[string]interface{})["subscription"].(map[string]interface{})
+	if sub["has_secret"] != true || sub["secret"] != nil {
+		t.Errorf("created subscription = %v, want the secret hidden", sub)
+	}
+	id := sub["id"].(string)
+
+	_, env = doJSON(t, ts, "GET", "/v1/subscriptions", nil)
+	if subs := env.Data.(map[string]interface{})["subscriptions"].([]interface{}); len(subs) != 1 {
+		t.Fatalf("subscriptions = %v, want 1", subs)
+	}
+
+	// An event stream subscribed to it only hears about security issues
+	if resp, _ := doJSON(t, ts, "GET", "/v1/events?subscription=sub-missing", nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown subscription stream: status = %d, want 404", resp.StatusCode)
+	}
+	filter, ok := srv.eventFilter(httptest.NewRecorder(), httptest.NewRequest("GET", "/v1/events?subscription="+id, nil))
+	if !ok || filter == nil {
+		t.Fatal("eventFilter: want the subscription's filter")
+	}
+	ch := srv.sseHub.registerFiltered(filter)
+	defer srv.sseHub.unregister(ch)
+
+	doJSON(t, ts, "POST", "/v1/issues", map[string]interface{}{"title": "Dark mode for settings"})
+	select {
+	case event := <-ch:
+		t.Fatalf("got %s event %s for an issue without the label", event.Event, event.Data)
+	case <-time.After(200 * time.Millisecond):
+	}
+	doJSON(t, ts, "POST", "/v1/issues", map[string]interface{}{"title": "Escape user input in search", "labels": []string{"security"}})
+	select {
+	case event := <-ch:
+		if event.Event != "refresh" {
+			t.Errorf("event = %s, want refresh", event.Event)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the security issue's refresh")
+	}
+
+	if resp, _ := doJSON(t, ts, "DELETE", "/v1/subscriptions/"+id, nil); resp.StatusCode != http.StatusOK {
+		t.Errorf("delete status = %d", resp.StatusCode)
+	}
+	if resp, _ := doJSON(t, ts, "DELETE", "/v1/subscriptions/"+id, nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("second delete status = %d, want 404", resp.StatusCode)
+	}
+}
@@ -0,0 +1,153 @@
+package serve
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/dependency"
+	"github.com/marcus/td/internal/owners"
+	"github.com/marcus/td/internal/relate"
+)
+
+// ============================================================================
+// GET /v1/issues/{id}/suggestions
+// ============================================================================
+
+// handleListSuggestions suggests issues probably related to this one,
+// from shared files, mentions in logs and comments, and cited commits.
+// ?limit= caps the list (default 10).
+func (s *Server) handleListSuggestions(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	limit := relate.DefaultLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			WriteValidation(w, []FieldError{{Field: "limit", Rule: "min", Value: v, Message: "limit must be a positive number"}})
+			return
+		}
+		limit = n
+	}
+
+	if _, err := s.db.GetIssue(id); err != nil {
+		WriteError(w, ErrNotFound, "issue not found: "+id, http.StatusNotFound)
+		return
+	}
+	suggestions, err := relate.Suggest(s.db, id, limit)
+	if err != nil {
+		requestLog(r).Error("suggest relations", "err", err, "id", id)
+		WriteError(w, ErrInternal, "failed to compute suggestions", http.StatusInternalServerError)
+		return
+	}
+	if suggestions == nil {
+		suggestions = []relate.Suggestion{}
+	}
+	WriteSuccess(w, map[string]interface{}{"suggestions": suggestions}, http.StatusOK)
+}
+
+// ============================================================================
+// POST /v1/issues/{id}/suggestions/{other_id}/accept
+// ============================================================================
+
+// AcceptSuggestionBody is the optional request body for accepting a
+// suggestion
+type AcceptSuggestionBody struct {
+	Relation string `json:"relation"` // depends_on (default) or blocks
+}
+
+// handleAcceptSuggestion turns a suggestion into a dependency: the issue
+// depends on the other, or with "relation": "blocks" the other depends on
+// the issue
+func (s *Server) handleAcceptSuggestion(w http.ResponseWriter, r *http.Request) {
+	var body AcceptSuggestionBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		WriteError(w, ErrValidation, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if body.Relation != "" && body.Relation != relate.RelationDependsOn && body.Relation != relate.RelationBlocks {
+		WriteValidation(w, []FieldError{{
+			Field:    "relation",
+			Rule:     "enum",
+			Value:    body.Relation,
+			Expected: []string{relate.RelationDependsOn, relate.RelationBlocks},
+			Message:  relate.ErrUnknownRelation.Error(),
+		}})
+		return
+	}
+
+	id, otherID := r.PathValue("id"), r.PathValue("other_id")
+	dep, err := relate.Accept(s.db, id, otherID, body.Relation, s.requestSession(r))
+	if err != nil {
+		switch msg := err.Error(); {
+		case errors.Is(err, dependency.ErrDependencyExists):
+			WriteError(w, ErrConflict, "dependency already exists", http.StatusConflict)
+		case strings.Contains(msg, "not found"):
+			WriteError(w, ErrNotFound, msg, http.StatusNotFound)
+		case strings.Contains(msg, "circular"), strings.Contains(msg, "itself"):
+			WriteError(w, ErrValidation, msg, http.StatusBadRequest)
+		default:
+			requestLog(r).Error("accept suggestion", "err", err, "id", id, "other", otherID)
+			WriteError(w, ErrInternal, "failed to accept suggestion", http.StatusInternalServerError)
+		}
+		return
+	}
+	s.NotifyChange(r)
+	WriteSuccess(w, map[string]interface{}{"dependency": DependencyDTO{
+		DepID:        db.DependencyID(dep.IssueID, dep.DependsOnID, dep.RelationType),
+		IssueID:      dep.IssueID,
+		DependsOnID:  dep.DependsOnID,
+		RelationType: dep.RelationType,
+	}}, http.StatusCreated)
+}
+
+// ============================================================================
+// POST /v1/issues/{id}/suggestions/{other_id}/dismiss
+// ============================================================================
+
+// handleDismissSuggestion marks two issues as unrelated, so neither is
+// suggested for the other again
+func (s *Server) handleDismissSuggestion(w http.ResponseWriter, r *http.Request) {
+	id, otherID := r.PathValue("id"), r.PathValue("other_id")
+	if err := relate.Dismiss(s.db, id, otherID, s.requestSession(r)); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			WriteError(w, ErrNotFound, err.Error(), http.StatusNotFound)
+			return
+		}
+		requestLog(r).Error("dismiss suggestion", "err", err, "id", id, "other", otherID)
+		WriteError(w, ErrInternal, "failed to dismiss suggestion", http.StatusInternalServerError)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"dismissed": true, "issue_id": id, "other_id": otherID}, http.StatusOK)
+}
+
+// ============================================================================
+// GET /v1/issues/{id}/reviewers
+// ============================================================================
+
+// handleSuggestReviewers suggests reviewers for an issue from the owners
+// .todos/owners gives its linked files
+func (s *Server) handleSuggestReviewers(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	issue, err := s.db.GetIssue(id)
+	if err != nil {
+		WriteError(w, ErrNotFound, "issue not found: "+id, http.StatusNotFound)
+		return
+	}
+	m, err := owners.Load(s.baseDir)
+	if err != nil {
+		requestLog(r).Error("load owners", "err", err)
+		WriteError(w, ErrInternal, "invalid owners file: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	reviewers, err := owners.Reviewers(s.db, m, issue)
+	if err != nil {
+		requestLog(r).Error("suggest reviewers", "err", err, "id", id)
+		WriteError(w, ErrInternal, "failed to suggest reviewers", http.StatusInternalServerError)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"reviewers": reviewers}, http.StatusOK)
+}
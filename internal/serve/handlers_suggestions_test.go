@@ -0,0 +1,107 @@
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/owners"
+)
+
+func TestSuggestionsAPI(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	var ids []string
+	for _, title := range []string{"Rework the session cache", "Cache eviction misses stale keys", "Document cache configuration"} {
+		issue := &models.Issue{Title: title}
+		if err := srv.db.CreateIssue(issue); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, issue.ID)
+	}
+	for _, id := range ids[:2] {
+		if err := srv.db.LinkFile(id, "internal/cache/cache.go", models.FileRoleImplementation, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := srv.db.AddLog(&models.Log{IssueID: ids[2], SessionID: "ses_test", Message: "Follows the design in " + ids[0], Type: models.LogTypeProgress}); err != nil {
+		t.Fatal(err)
+	}
+
+	base := "/v1/issues/" + ids[0] + "/suggestions"
+	resp, env := doJSON(t, ts, "GET", base, nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("list status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	list := env.Data.(map[string]interface{})["suggestions"].([]interface{})
+	if len(list) != 2 || list[0].(map[string]interface{})["issue_id"] != ids[2] {
+		t.Fatalf("suggestions = %v", list)
+	}
+
+	resp, env = doJSON(t, ts, "POST", base+"/"+ids[1]+"/accept", map[string]string{"relation": "blocks"})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("accept status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	if dep := env.Data.(map[string]interface{})["dependency"].(map[string]interface{}); dep["issue_id"] != ids[1] || dep["depends_on_id"] != ids[0] {
+		t.Errorf("dependency = %v", dep)
+	}
+	if resp, _ := doJSON(t, ts, "POST", base+"/"+ids[1]+"/accept", nil); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("accept creating a cycle = %d, want 400", resp.StatusCode)
+	}
+	if resp, _ := doJSON(t, ts, "POST", base+"/"+ids[2]+"/accept", map[string]string{"relation": "relates"}); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("unknown relation = %d, want 400", resp.StatusCode)
+	}
+
+	if resp, _ := doJSON(t, ts, "POST", base+"/"+ids[2]+"/dismiss", nil); resp.StatusCode != http.StatusOK {
+		t.Fatalf("dismiss status = %d", resp.StatusCode)
+	}
+	_, env = doJSON(t, ts, "GET", base, nil)
+	if list := env.Data.(map[string]interface{})["suggestions"].([]interface{}); len(list) != 0 {
+		t.Errorf("after accept and dismiss = %v", list)
+	}
+
+	if resp, _ := doJSON(t, ts, "GET", "/v1/issues/td-nope/suggestions", nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown issue = %d, want 404", resp.StatusCode)
+	}
+	if resp, _ := doJSON(t, ts, "POST", base+"/td-nope/dismiss", nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("dismiss unknown = %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestSuggestReviewersAPI(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	if err := os.WriteFile(owners.Path(srv.baseDir), []byte("/internal/cache/ #cache @alice\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.db.UpsertSession(&db.SessionRow{ID: "ses_alice", Name: "alice", StartedAt: time.Now(), LastActivity: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	issue := &models.Issue{Title: "Rework the session cache"}
+	if err := srv.db.CreateIssue(issue); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.db.LinkFile(issue.ID, "internal/cache/cache.go", models.FileRoleImplementation, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, env := doJSON(t, ts, "GET", "/v1/issues/"+issue.ID+"/reviewers", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	list := env.Data.(map[string]interface{})["reviewers"].([]interface{})
+	if len(list) != 1 {
+		t.Fatalf("reviewers = %v", list)
+	}
+	if r := list[0].(map[string]interface{}); r["owner"] != "alice" || len(r["sessions"].([]interface{})) != 1 {
+		t.Errorf("reviewer = %v", r)
+	}
+}
@@ -4,11 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log/slog"
 	"net/http"
 	"strings"
-	"time"
 
+	"github.com/marcus/td/internal/clock"
 	"github.com/marcus/td/internal/models"
 	"github.com/marcus/td/internal/workflow"
 )
@@ -20,6 +19,14 @@ import (
 // transitionReasonBody is the optional request body for transition endpoints.
 type transitionReasonBody struct {
 	Reason string `json:"reason"`
+	// Block only
+	BlockedReason string `json:"blocked_reason"`
+	BlockedRef    string `json:"blocked_ref"`
+	// Reopen only
+	Category string `json:"category"`
+	// Approve only: review checklist items acknowledged, by text, 1-based
+	// number or "all"
+	Checklist []string `json:"checklist"`
 }
 
 // transitionCascadeResult holds the results of cascade operations for the response.
@@ -36,11 +43,18 @@ type transitionSpec struct {
 	toStatus models.Status
 	// actionType is the action_log type for the transition.
 	actionType models.ActionType
+	// applyBody validates and applies transition-specific body fields.
+	// Called before the status changes; any errors reject the request.
+	applyBody func(s *Server, issue *models.Issue, body transitionReasonBody) []FieldError
 	// applySideEffects mutates the issue model for transition-specific side
 	// effects (session fields, closed_at, etc.). Called after status is set.
-	applySideEffects func(s *Server, issue *models.Issue)
+	applySideEffects func(s *Server, r *http.Request, issue *models.Issue)
+	// persist saves the updated issue; defaults to updateIssueLogged.
+	persist func(s *Server, r *http.Request, issue *models.Issue) error
+	// afterPersist records transition-specific data once the update is saved.
+	afterPersist func(s *Server, r *http.Request, issue *models.Issue)
 	// runCascades executes any post-transition cascades and returns results.
-	runCascades func(s *Server, issue *models.Issue) transitionCascadeResult
+	runCascades func(s *Server, r *http.Request, issue *models.Issue) transitionCascadeResult
 	// defaultLogMsg is the default progress log message when no reason is given.
 	defaultLogMsg string
 	// logType overrides the log type (defaults to LogTypeProgress).
@@ -61,7 +75,7 @@ func (s *Server) handleTransition(w http.ResponseWriter, r *http.Request, spec t
 		if strings.Contains(err.Error(), "not found") {
 			WriteError(w, ErrNotFound, fmt.Sprintf("issue not found: %s", issueID), http.StatusNotFound)
 		} else {
-			slog.Error("get issue for transition", "err", err, "id", issueID)
+			requestLog(r).Error("get issue for transition", "err", err, "id", issueID)
 			WriteError(w, ErrInternal, "failed to fetch issue", http.StatusInternalServerError)
 		}
 		return
@@ -87,27 +101,41 @@ func (s *Server) handleTransition(w http.ResponseWriter, r *http.Request, spec t
 	}
 
 	// Parse optional reason body (body may be empty or absent)
-	var reason string
+	var body transitionReasonBody
 	if r.Body != nil {
 		bodyBytes, readErr := io.ReadAll(r.Body)
 		if readErr == nil && len(bodyBytes) > 0 {
-			var body transitionReasonBody
-			if jsonErr := json.Unmarshal(bodyBytes, &body); jsonErr == nil {
-				reason = body.Reason
+			if jsonErr := json.Unmarshal(bodyBytes, &body); jsonErr != nil {
+				body = transitionReasonBody{}
 			}
 		}
 	}
+	reason := body.Reason
+	if spec.applyBody != nil {
+		if errs := spec.applyBody(s, issue, body); len(errs) > 0 {
+			WriteValidation(w, errs)
+			return
+		}
+	}
 
 	// Apply the transition
 	issue.Status = spec.toStatus
 	if spec.applySideEffects != nil {
-		spec.applySideEffects(s, issue)
+		spec.applySideEffects(s, r, issue)
 	}
 
 	// Persist
-	if err := s.db.UpdateIssueLogged(issue, s.sessionID, spec.actionType); err != nil {
-		slog.Error("transition issue", "err", err, "id", issueID, "to", spec.toStatus)
-		WriteError(w, ErrInternal, "failed to update issue", http.StatusInternalServerError)
+	persist := func(s *Server, r *http.Request, issue *models.Issue) error {
+		return s.updateIssueLogged(r, issue, spec.actionType)
+	}
+	if spec.persist != nil {
+		persist = spec.persist
+	}
+	if err := persist(s, r, issue); err != nil {
+		if !writeRejection(w, err) {
+			requestLog(r).Error("transition issue", "err", err, "id", issueID, "to", spec.toStatus)
+			WriteError(w, ErrInternal, "failed to update issue", http.StatusInternalServerError)
+		}
 		return
 	}
 
@@ -122,17 +150,20 @@ func (s *Server) handleTransition(w http.ResponseWriter, r *http.Request, spec t
 	}
 	if logErr := s.db.AddLog(&models.Log{
 		IssueID:   canonicalIssueID,
-		SessionID: s.sessionID,
+		SessionID: s.requestSession(r),
 		Message:   logMsg,
 		Type:      logType,
 	}); logErr != nil {
-		slog.Warn("failed to add transition log", "err", logErr, "id", canonicalIssueID)
+		requestLog(r).Warn("failed to add transition log", "err", logErr, "id", canonicalIssueID)
+	}
+	if spec.afterPersist != nil {
+		spec.afterPersist(s, r, issue)
 	}
 
 	// Run cascades
 	var cascades transitionCascadeResult
 	if spec.runCascades != nil {
-		cascades = spec.runCascades(s, issue)
+		cascades = spec.runCascades(s, r, issue)
 	}
 	if cascades.ParentStatusUpdates == nil {
 		cascades.ParentStatusUpdates = []IssueDTO{}
@@ -149,6 +180,7 @@ func (s *Server) handleTransition(w http.ResponseWriter, r *http.Request, spec t
 	}
 
 	dto := IssueToDTO(updated)
+	dto.ClosedViaOverride = s.closedViaOverride(r, *updated)[updated.ID]
 	WriteSuccess(w, map[string]interface{}{
 		"issue":    dto,
 		"cascades": cascades,
@@ -165,6 +197,19 @@ func statusIn(s models.Status, set []models.Status) bool {
 	return false
 }
 
+// closeCascades runs the parent and dependency cascades of a close
+func (s *Server) closeCascades(r *http.Request, issue *models.Issue) transitionCascadeResult {
+	var cr transitionCascadeResult
+	parents, unblocked := s.db.CascadeClose(issue.ID, s.requestSession(r))
+	if len(parents) > 0 {
+		cr.ParentStatusUpdates = s.cascadeIDsToIssueDTOs(parents)
+	}
+	if len(unblocked) > 0 {
+		cr.AutoUnblocked = s.cascadeIDsToIssueDTOs(unblocked)
+	}
+	return cr
+}
+
 // cascadeIDsToIssueDTOs fetches issues by ID and converts to DTOs.
 func (s *Server) cascadeIDsToIssueDTOs(ids []string) []IssueDTO {
 	var dtos []IssueDTO
@@ -189,8 +234,8 @@ func (s *Server) handleStart(w http.ResponseWriter, r *http.Request) {
 		validFrom:  []models.Status{models.StatusOpen},
 		toStatus:   models.StatusInProgress,
 		actionType: models.ActionStart,
-		applySideEffects: func(srv *Server, issue *models.Issue) {
-			issue.ImplementerSession = srv.sessionID
+		applySideEffects: func(srv *Server, r *http.Request, issue *models.Issue) {
+			issue.ImplementerSession = srv.requestSession(r)
 		},
 		defaultLogMsg: "Started work",
 	})
@@ -205,15 +250,15 @@ func (s *Server) handleReview(w http.ResponseWriter, r *http.Request) {
 		validFrom:  []models.Status{models.StatusOpen, models.StatusInProgress},
 		toStatus:   models.StatusInReview,
 		actionType: models.ActionReview,
-		applySideEffects: func(srv *Server, issue *models.Issue) {
+		applySideEffects: func(srv *Server, r *http.Request, issue *models.Issue) {
 			if issue.ImplementerSession == "" {
-				issue.ImplementerSession = srv.sessionID
+				issue.ImplementerSession = srv.requestSession(r)
 			}
 		},
-		runCascades: func(srv *Server, issue *models.Issue) transitionCascadeResult {
+		runCascades: func(srv *Server, r *http.Request, issue *models.Issue) transitionCascadeResult {
 			var cr transitionCascadeResult
 			// Parent cascade to in_review when all siblings qualify
-			if _, ids := srv.db.CascadeUpParentStatus(issue.ID, models.StatusInReview, srv.sessionID); len(ids) > 0 {
+			if _, ids := srv.db.CascadeUpParentStatus(issue.ID, models.StatusInReview, srv.requestSession(r)); len(ids) > 0 {
 				cr.ParentStatusUpdates = srv.cascadeIDsToIssueDTOs(ids)
 			}
 			return cr
@@ -227,26 +272,31 @@ func (s *Server) handleReview(w http.ResponseWriter, r *http.Request) {
 // ============================================================================
 
 func (s *Server) handleApprove(w http.ResponseWriter, r *http.Request) {
+	var override *models.Override
+	var checklist []string
 	s.handleTransition(w, r, transitionSpec{
 		validFrom:  []models.Status{models.StatusInReview},
 		toStatus:   models.StatusClosed,
 		actionType: models.ActionApprove,
-		applySideEffects: func(srv *Server, issue *models.Issue) {
-			issue.ReviewerSession = srv.sessionID
-			now := time.Now()
+		applyBody: func(s *Server, issue *models.Issue, body transitionReasonBody) []FieldError {
+			override = s.minorSelfOverride(r, issue, models.OverrideMinorSelfApprove, body.Reason)
+			checklist = body.Checklist
+			return nil
+		},
+		persist: func(s *Server, r *http.Request, issue *models.Issue) error {
+			confirmed := r.URL.Query().Get("confirm") == "thrash"
+			return s.db.ApproveIssueLogged(issue, s.requestSession(r), checklist, confirmed)
+		},
+		applySideEffects: func(srv *Server, r *http.Request, issue *models.Issue) {
+			issue.ReviewerSession = srv.requestSession(r)
+			now := clock.Now()
 			issue.ClosedAt = &now
 		},
-		runCascades: func(srv *Server, issue *models.Issue) transitionCascadeResult {
-			var cr transitionCascadeResult
-			// Parent cascade to closed when all siblings closed
-			if _, ids := srv.db.CascadeUpParentStatus(issue.ID, models.StatusClosed, srv.sessionID); len(ids) > 0 {
-				cr.ParentStatusUpdates = srv.cascadeIDsToIssueDTOs(ids)
-			}
-			// Dependency unblocking cascade
-			if _, ids := srv.db.CascadeUnblockDependents(issue.ID, srv.sessionID); len(ids) > 0 {
-				cr.AutoUnblocked = srv.cascadeIDsToIssueDTOs(ids)
-			}
-			return cr
+		afterPersist: func(s *Server, r *http.Request, issue *models.Issue) {
+			s.recordOverride(r, override)
+		},
+		runCascades: func(srv *Server, r *http.Request, issue *models.Issue) transitionCascadeResult {
+			return srv.closeCascades(r, issue)
 		},
 		defaultLogMsg: "Approved",
 	})
@@ -261,7 +311,7 @@ func (s *Server) handleReject(w http.ResponseWriter, r *http.Request) {
 		validFrom:  []models.Status{models.StatusInReview},
 		toStatus:   models.StatusOpen,
 		actionType: models.ActionReject,
-		applySideEffects: func(_ *Server, issue *models.Issue) {
+		applySideEffects: func(_ *Server, _ *http.Request, issue *models.Issue) {
 			issue.ImplementerSession = ""
 			issue.ReviewerSession = ""
 			issue.ClosedAt = nil
@@ -279,11 +329,40 @@ func (s *Server) handleBlock(w http.ResponseWriter, r *http.Request) {
 		validFrom:     []models.Status{models.StatusOpen, models.StatusInProgress},
 		toStatus:      models.StatusBlocked,
 		actionType:    models.ActionBlock,
+		applyBody:     applyBlockedReason,
 		defaultLogMsg: "Blocked",
 		logType:       models.LogTypeBlocker,
 	})
 }
 
+// applyBlockedReason records why an issue is being blocked. Without a
+// blocked_reason it is inferred: external when a blocked_ref is given,
+// dependency when the issue has open dependencies, otherwise left unset.
+func applyBlockedReason(s *Server, issue *models.Issue, body transitionReasonBody) []FieldError {
+	kind := models.NormalizeBlockedReason(body.BlockedReason)
+	switch {
+	case body.BlockedReason != "":
+		if !models.IsValidBlockedReason(kind) {
+			return []FieldError{{
+				Field:    "blocked_reason",
+				Rule:     "enum",
+				Value:    body.BlockedReason,
+				Expected: []string{"dependency", "external", "decision"},
+				Message:  "blocked_reason must be dependency, external or decision",
+			}}
+		}
+	case body.BlockedRef != "":
+		kind = models.BlockedReasonExternal
+	default:
+		if open, _ := s.db.HasOpenDependencies(issue.ID); open {
+			kind = models.BlockedReasonDependency
+		}
+	}
+	issue.BlockedReason = kind
+	issue.BlockedRef = body.BlockedRef
+	return nil
+}
+
 // ============================================================================
 // POST /v1/issues/{id}/unblock
 // ============================================================================
@@ -302,25 +381,24 @@ func (s *Server) handleUnblock(w http.ResponseWriter, r *http.Request) {
 // ============================================================================
 
 func (s *Server) handleClose(w http.ResponseWriter, r *http.Request) {
+	var override *models.Override
 	s.handleTransition(w, r, transitionSpec{
 		validFrom:  []models.Status{models.StatusOpen, models.StatusInProgress, models.StatusBlocked, models.StatusInReview},
 		toStatus:   models.StatusClosed,
 		actionType: models.ActionClose,
-		applySideEffects: func(_ *Server, issue *models.Issue) {
-			now := time.Now()
+		applyBody: func(s *Server, issue *models.Issue, body transitionReasonBody) []FieldError {
+			override = s.minorSelfOverride(r, issue, models.OverrideMinorSelfClose, body.Reason)
+			return nil
+		},
+		applySideEffects: func(_ *Server, _ *http.Request, issue *models.Issue) {
+			now := clock.Now()
 			issue.ClosedAt = &now
 		},
-		runCascades: func(srv *Server, issue *models.Issue) transitionCascadeResult {
-			var cr transitionCascadeResult
-			// Parent cascade to closed when all siblings closed
-			if _, ids := srv.db.CascadeUpParentStatus(issue.ID, models.StatusClosed, srv.sessionID); len(ids) > 0 {
-				cr.ParentStatusUpdates = srv.cascadeIDsToIssueDTOs(ids)
-			}
-			// Dependency unblocking cascade
-			if _, ids := srv.db.CascadeUnblockDependents(issue.ID, srv.sessionID); len(ids) > 0 {
-				cr.AutoUnblocked = srv.cascadeIDsToIssueDTOs(ids)
-			}
-			return cr
+		afterPersist: func(s *Server, r *http.Request, issue *models.Issue) {
+			s.recordOverride(r, override)
+		},
+		runCascades: func(srv *Server, r *http.Request, issue *models.Issue) transitionCascadeResult {
+			return srv.closeCascades(r, issue)
 		},
 		defaultLogMsg: "Closed",
 	})
@@ -330,15 +408,46 @@ func (s *Server) handleClose(w http.ResponseWriter, r *http.Request) {
 // POST /v1/issues/{id}/reopen
 // ============================================================================
 
+// handleReopen reopens a closed issue and records the rework cycle, with
+// an optional category saying why the work came back.
 func (s *Server) handleReopen(w http.ResponseWriter, r *http.Request) {
+	var rework models.Rework
 	s.handleTransition(w, r, transitionSpec{
 		validFrom:  []models.Status{models.StatusClosed},
 		toStatus:   models.StatusOpen,
 		actionType: models.ActionReopen,
-		applySideEffects: func(_ *Server, issue *models.Issue) {
+		applyBody: func(s *Server, issue *models.Issue, body transitionReasonBody) []FieldError {
+			category := models.ReworkOther
+			if body.Category != "" {
+				category = models.ReworkCategory(strings.ToLower(body.Category))
+			}
+			if !models.IsValidReworkCategory(category) {
+				return []FieldError{{
+					Field:    "category",
+					Rule:     "enum",
+					Value:    body.Category,
+					Expected: models.ReworkCategories(),
+					Message:  "category must be one of bug, incomplete, requirements, regression, other",
+				}}
+			}
+			rework = models.Rework{
+				IssueID:   issue.ID,
+				Category:  category,
+				Reason:    body.Reason,
+				Approved:  issue.ReviewerSession != "",
+				SessionID: s.requestSession(r),
+			}
+			return nil
+		},
+		applySideEffects: func(_ *Server, _ *http.Request, issue *models.Issue) {
 			issue.ReviewerSession = ""
 			issue.ClosedAt = nil
 		},
+		afterPersist: func(s *Server, r *http.Request, issue *models.Issue) {
+			if err := s.db.RecordRework(&rework); err != nil {
+				requestLog(r).Warn("failed to record rework", "err", err, "id", issue.ID)
+			}
+		},
 		defaultLogMsg: "Reopened",
 	})
 }
@@ -0,0 +1,78 @@
+package serve
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/usage"
+)
+
+// ============================================================================
+// GET /v1/labels/stats
+// ============================================================================
+
+// handleLabelStats reports per-label usage counts, co-occurrence, bug share,
+// completion rates and weekly trend lines, and lists stale labels. ?weeks=
+// sets the trend window; ?stale_days= how long unused makes a label stale.
+func (s *Server) handleLabelStats(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	var errs []FieldError
+	intParam := func(name string, def, max int) int {
+		v := q.Get(name)
+		if v == "" {
+			return def
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > max {
+			errs = append(errs, FieldError{
+				Field:   name,
+				Rule:    "range",
+				Value:   v,
+				Message: fmt.Sprintf("%s must be a number from 1 to %d", name, max),
+			})
+			return def
+		}
+		return n
+	}
+	weeks := intParam("weeks", usage.DefaultWeeks, usage.MaxWeeks)
+	staleDays := intParam("stale_days", usage.DefaultStaleDays, 3650)
+	if len(errs) > 0 {
+		WriteValidation(w, errs)
+		return
+	}
+
+	report, err := usage.ComputeLabels(s.db, weeks, staleDays, clock.Now())
+	if err != nil {
+		requestLog(r).Error("label stats", "err", err)
+		WriteError(w, ErrInternal, "failed to compute label stats", http.StatusInternalServerError)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"labels": report}, http.StatusOK)
+}
+
+// ============================================================================
+// GET /v1/sprints/stats
+// ============================================================================
+
+// handleSprintStats reports each sprint's delivery metrics and top labels in
+// sprint order, with the change in completion from the sprint before.
+func (s *Server) handleSprintStats(w http.ResponseWriter, r *http.Request) {
+	sprints, err := config.GetSprints(s.baseDir)
+	if err != nil {
+		requestLog(r).Error("load sprints", "err", err)
+		WriteError(w, ErrInternal, "failed to load sprints", http.StatusInternalServerError)
+		return
+	}
+
+	report, err := usage.ComputeSprints(s.db, sprints)
+	if err != nil {
+		requestLog(r).Error("sprint stats", "err", err)
+		WriteError(w, ErrInternal, "failed to compute sprint stats", http.StatusInternalServerError)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"sprints": report}, http.StatusOK)
+}
@@ -0,0 +1,57 @@
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/models"
+)
+
+func TestLabelAndSprintStats(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	if err := config.SetSprint(srv.baseDir, models.Sprint{Name: "s1", Start: "2026-03-02", End: "2026-03-15"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, issue := range []*models.Issue{
+		{Title: "Login times out", Type: models.TypeBug, Labels: []string{"auth", "api"}, Sprint: "s1"},
+		{Title: "Add SSO provider", Labels: []string{"auth"}, Sprint: "s1"},
+		{Title: "Unlabelled chore"},
+	} {
+		if err := srv.db.CreateIssue(issue); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resp, env := doJSON(t, ts, "GET", "/v1/labels/stats?weeks=4", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("label stats status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	report := env.Data.(map[string]interface{})["labels"].(map[string]interface{})
+	labels := report["labels"].([]interface{})
+	auth := labels[0].(map[string]interface{})
+	if report["unlabeled"] != float64(1) || len(report["week_starts"].([]interface{})) != 4 || auth["label"] != "auth" || auth["bugs"] != float64(1) || auth["bug_share"] != 0.5 {
+		t.Errorf("label stats = %v", report)
+	}
+
+	if resp, _ := doJSON(t, ts, "GET", "/v1/labels/stats?weeks=0", nil); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("bad weeks status = %d, want 400", resp.StatusCode)
+	}
+
+	resp, env = doJSON(t, ts, "GET", "/v1/sprints/stats", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("sprint stats status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	sprints := env.Data.(map[string]interface{})["sprints"].(map[string]interface{})
+	list := sprints["sprints"].([]interface{})
+	if len(list) != 1 || sprints["unscheduled"] != float64(1) {
+		t.Fatalf("sprint stats = %v", sprints)
+	}
+	if s1 := list[0].(map[string]interface{}); s1["issues"] != float64(2) || s1["bugs"] != float64(1) || s1["configured"] != true {
+		t.Errorf("s1 = %v", s1)
+	}
+}
@@ -0,0 +1,237 @@
+package serve
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/query"
+	"github.com/marcus/td/internal/view"
+)
+
+// ============================================================================
+// GET /v1/views
+// ============================================================================
+
+// handleListViews lists saved views by name.
+func (s *Server) handleListViews(w http.ResponseWriter, r *http.Request) {
+	views, err := s.db.ListViews()
+	if err != nil {
+		requestLog(r).Error("list views", "err", err)
+		WriteError(w, ErrInternal, "failed to list views", http.StatusInternalServerError)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"views": ViewsToDTOs(views)}, http.StatusOK)
+}
+
+// ============================================================================
+// GET /v1/views/{id}
+// ============================================================================
+
+// handleGetView returns one view, looked up by ID or name.
+func (s *Server) handleGetView(w http.ResponseWriter, r *http.Request) {
+	v, ok := s.lookupView(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"view": ViewToDTO(v)}, http.StatusOK)
+}
+
+// ============================================================================
+// GET /v1/views/{id}/issues
+// ============================================================================
+
+// handleViewIssues runs a view and returns its issues in its groups, each
+// issue trimmed to the view's fields. ?fields= overrides the view's
+// selection; a view without fields returns whole issues.
+func (s *Server) handleViewIssues(w http.ResponseWriter, r *http.Request) {
+	v, ok := s.lookupView(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+
+	q := r.URL.Query()
+	if len(q["fields"]) == 0 && len(v.Fields) > 0 {
+		q = url.Values{"fields": v.Fields}
+	}
+	fields, errs := ParseIssueFields(q)
+	if len(errs) > 0 {
+		WriteValidation(w, errs)
+		return
+	}
+
+	groups, err := view.Run(s.db, v, s.requestSession(r), query.ExecuteOptions{})
+	if err != nil {
+		requestLog(r).Error("run view", "err", err, "id", v.ID)
+		WriteError(w, ErrInternal, "failed to run view: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var all []models.Issue
+	for _, g := range groups {
+		all = append(all, g.Issues...)
+	}
+	overrides := s.closedViaOverride(r, all...)
+
+	out := make([]map[string]interface{}, len(groups))
+	total := 0
+	for i, g := range groups {
+		issues := make([]interface{}, len(g.Issues))
+		for j := range g.Issues {
+			dto := IssueToDTO(&g.Issues[j])
+			dto.ClosedViaOverride = overrides[g.Issues[j].ID]
+			issues[j] = fields.Issue(dto)
+		}
+		out[i] = map[string]interface{}{"key": g.Key, "issues": issues}
+		total += len(g.Issues)
+	}
+
+	WriteSuccess(w, map[string]interface{}{
+		"view":     ViewToDTO(v),
+		"group_by": view.GroupField(v),
+		"groups":   out,
+		"total":    total,
+	}, http.StatusOK)
+}
+
+// ============================================================================
+// POST /v1/views
+// ============================================================================
+
+// ViewBody is the JSON body for creating or updating a view. On update,
+// omitted fields are left unchanged and fields replaces the selection.
+type ViewBody struct {
+	Name    *string   `json:"name"`
+	Query   *string   `json:"query"`
+	Sort    *string   `json:"sort"`
+	Fields  *[]string `json:"fields"`
+	GroupBy *string   `json:"group_by"`
+	Layout  *string   `json:"layout"`
+}
+
+// handleCreateView saves a view. Names are unique, case-insensitively.
+func (s *Server) handleCreateView(w http.ResponseWriter, r *http.Request) {
+	var body ViewBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		WriteError(w, ErrValidation, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	v := &models.View{SessionID: s.requestSession(r)}
+	if errs := applyViewBody(v, body); len(errs) > 0 {
+		WriteValidation(w, errs)
+		return
+	}
+	if err := s.db.CreateView(v); err != nil {
+		writeViewSaveError(w, r, err)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"view": ViewToDTO(v)}, http.StatusCreated)
+}
+
+// ============================================================================
+// PATCH /v1/views/{id}
+// ============================================================================
+
+// handleUpdateView updates the settings present in the body.
+func (s *Server) handleUpdateView(w http.ResponseWriter, r *http.Request) {
+	var body ViewBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		WriteError(w, ErrValidation, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	v, ok := s.lookupView(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+	if errs := applyViewBody(v, body); len(errs) > 0 {
+		WriteValidation(w, errs)
+		return
+	}
+	if err := s.db.UpdateView(v); err != nil {
+		writeViewSaveError(w, r, err)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"view": ViewToDTO(v)}, http.StatusOK)
+}
+
+// ============================================================================
+// DELETE /v1/views/{id}
+// ============================================================================
+
+// handleDeleteView removes a view.
+func (s *Server) handleDeleteView(w http.ResponseWriter, r *http.Request) {
+	v, ok := s.lookupView(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+	if err := s.db.DeleteView(v.ID); err != nil {
+		requestLog(r).Error("delete view", "err", err, "id", v.ID)
+		WriteError(w, ErrInternal, "failed to delete view", http.StatusInternalServerError)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"deleted": true}, http.StatusOK)
+}
+
+// lookupView fetches a view by ID or name, writing a 404 or 500 when it can't.
+func (s *Server) lookupView(w http.ResponseWriter, ref string) (*models.View, bool) {
+	v, err := s.db.GetView(ref)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			WriteError(w, ErrNotFound, "view not found: "+ref, http.StatusNotFound)
+		} else {
+			slog.Error("get view", "err", err, "ref", ref)
+			WriteError(w, ErrInternal, "failed to fetch view", http.StatusInternalServerError)
+		}
+		return nil, false
+	}
+	return v, true
+}
+
+// applyViewBody copies the settings the body sets onto v and validates the
+// result.
+func applyViewBody(v *models.View, body ViewBody) []FieldError {
+	if body.Name != nil {
+		v.Name = *body.Name
+	}
+	if body.Query != nil {
+		v.Query = *body.Query
+	}
+	if body.Sort != nil {
+		v.Sort = *body.Sort
+	}
+	if body.Fields != nil {
+		v.Fields = *body.Fields
+	}
+	if body.GroupBy != nil {
+		v.GroupBy = *body.GroupBy
+	}
+	if body.Layout != nil {
+		v.Layout = *body.Layout
+	}
+
+	if err := view.Normalize(v); err != nil {
+		var verr *view.Error
+		if errors.As(err, &verr) {
+			return []FieldError{{Field: verr.Field, Rule: "invalid", Message: verr.Message}}
+		}
+		return []FieldError{{Field: "view", Rule: "invalid", Message: err.Error()}}
+	}
+	return nil
+}
+
+// writeViewSaveError reports a failed view write: 409 for a taken name,
+// 500 otherwise.
+func writeViewSaveError(w http.ResponseWriter, r *http.Request, err error) {
+	if strings.Contains(err.Error(), "already exists") {
+		WriteError(w, ErrConflict, err.Error(), http.StatusConflict)
+		return
+	}
+	requestLog(r).Error("save view", "err", err)
+	WriteError(w, ErrInternal, "failed to save view", http.StatusInternalServerError)
+}
@@ -0,0 +1,102 @@
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/view"
+)
+
+func TestViews_CRUD(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	for _, issue := range []*models.Issue{
+		{Title: "Login page ignores SSO redirect", Type: models.TypeBug, Priority: models.PriorityP1},
+		{Title: "Crash when the config file is empty", Type: models.TypeBug, Priority: models.PriorityP0},
+		{Title: "Export issues as CSV from the list", Type: models.TypeFeature, Priority: models.PriorityP2},
+	} {
+		if err := srv.db.CreateIssue(issue); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resp, env := doJSON(t, ts, "POST", "/v1/views", map[string]interface{}{"name": "bugs", "group_by": "owner"})
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("bad group status = %d", resp.StatusCode)
+	}
+
+	resp, env = doJSON(t, ts, "POST", "/v1/views", map[string]interface{}{
+		"name":     "bugs",
+		"query":    "type = bug",
+		"sort":     "priority",
+		"fields":   []string{"title", "priority"},
+		"group_by": "type",
+		"layout":   "table",
+	})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	v := env.Data.(map[string]interface{})["view"].(map[string]interface{})
+	id := v["id"].(string)
+	if v["layout"] != "table" || len(v["fields"].([]interface{})) != 2 {
+		t.Errorf("view = %v", v)
+	}
+
+	resp, _ = doJSON(t, ts, "POST", "/v1/views", map[string]interface{}{"name": "BUGS"})
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("duplicate name status = %d", resp.StatusCode)
+	}
+
+	// Views open by name as well as ID
+	resp, env = doJSON(t, ts, "GET", "/v1/views/bugs/issues", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("issues status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	data := env.Data.(map[string]interface{})
+	groups := data["groups"].([]interface{})
+	if data["total"] != float64(2) || len(groups) != 1 {
+		t.Fatalf("view issues = %v", data)
+	}
+	first := groups[0].(map[string]interface{})["issues"].([]interface{})[0].(map[string]interface{})
+	if first["priority"] != "P0" || first["status"] != nil || len(first) != 3 {
+		t.Errorf("first issue = %v, want id, title and priority of the P0", first)
+	}
+
+	resp, env = doJSON(t, ts, "PATCH", "/v1/views/"+id, map[string]interface{}{"query": "", "group_by": "", "layout": "board"})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("update status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	_, env = doJSON(t, ts, "GET", "/v1/views/"+id+"/issues", nil)
+	data = env.Data.(map[string]interface{})
+	if data["group_by"] != "status" || data["total"] != float64(3) {
+		t.Errorf("board view issues = %v, want all issues by status", data)
+	}
+
+	_, env = doJSON(t, ts, "GET", "/v1/views", nil)
+	if list := env.Data.(map[string]interface{})["views"].([]interface{}); len(list) != 1 {
+		t.Errorf("views = %v", list)
+	}
+
+	resp, _ = doJSON(t, ts, "DELETE", "/v1/views/"+id, nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("delete status = %d", resp.StatusCode)
+	}
+	resp, _ = doJSON(t, ts, "GET", "/v1/views/"+id, nil)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("get deleted status = %d", resp.StatusCode)
+	}
+}
+
+// TestViews_FieldsMatchIssueDTO keeps the fields a view can select in step
+// with the API's issue objects
+func TestViews_FieldsMatchIssueDTO(t *testing.T) {
+	for _, f := range view.Fields() {
+		if _, ok := issueFieldIndex[f]; !ok {
+			t.Errorf("view field %q is not an IssueDTO field", f)
+		}
+	}
+}
@@ -2,17 +2,24 @@ package serve
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log/slog"
+	"io"
 	"net/http"
+	"slices"
 	"strings"
 
+	"github.com/marcus/td/internal/confidential"
 	"github.com/marcus/td/internal/config"
 	"github.com/marcus/td/internal/db"
 	"github.com/marcus/td/internal/dependency"
 	"github.com/marcus/td/internal/git"
 	"github.com/marcus/td/internal/models"
 	"github.com/marcus/td/internal/query"
+	"github.com/marcus/td/internal/quickadd"
+	"github.com/marcus/td/internal/session"
+	"github.com/marcus/td/internal/triage"
+	"github.com/marcus/td/internal/xref"
 )
 
 // ============================================================================
@@ -26,12 +33,50 @@ func (s *Server) handleCreateIssue(w http.ResponseWriter, r *http.Request) {
 		WriteError(w, ErrValidation, "invalid JSON: "+err.Error(), http.StatusBadRequest)
 		return
 	}
+	s.createIssue(w, r, &body)
+}
+
+// ============================================================================
+// POST /v1/issues/quick — Quick-Add Issue
+// ============================================================================
+
+// handleQuickAddIssue creates an issue from a one-line quick-add string,
+// the grammar td add takes: "Fix login timeout #bug !p1 @sprint-7 +auth
+// due:friday 3pts".
+func (s *Server) handleQuickAddIssue(w http.ResponseWriter, r *http.Request) {
+	var body QuickAddBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		WriteError(w, ErrValidation, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(body.Text) == "" {
+		WriteValidation(w, []FieldError{{Field: "text", Rule: "required", Message: "text is required"}})
+		return
+	}
+	fields, err := quickadd.Parse(body.Text)
+	if err != nil {
+		WriteValidation(w, []FieldError{{Field: "text", Rule: "format", Value: body.Text, Message: err.Error()}})
+		return
+	}
+	s.createIssue(w, r, &IssueCreateBody{
+		Title:    fields.Title,
+		Type:     string(fields.Type),
+		Priority: string(fields.Priority),
+		Points:   fields.Points,
+		Labels:   fields.Labels,
+		Sprint:   fields.Sprint,
+		DueDate:  fields.DueDate,
+		Inbox:    body.Inbox,
+	})
+}
 
+// createIssue validates body and creates the issue it describes.
+func (s *Server) createIssue(w http.ResponseWriter, r *http.Request, body *IssueCreateBody) {
 	// Load configurable title length limits
 	titleMin, titleMax := s.titleLengthLimits()
 
 	// Validate
-	if errs := ValidateIssueCreate(&body, titleMin, titleMax); len(errs) > 0 {
+	if errs := ValidateIssueCreate(body, titleMin, titleMax); len(errs) > 0 {
 		WriteValidation(w, errs)
 		return
 	}
@@ -55,7 +100,7 @@ func (s *Server) handleCreateIssue(w http.ResponseWriter, r *http.Request) {
 			if strings.Contains(err.Error(), "not found") {
 				WriteError(w, ErrNotFound, fmt.Sprintf("parent issue not found: %s", body.ParentID), http.StatusNotFound)
 			} else {
-				slog.Error("lookup parent issue", "err", err, "parent_id", body.ParentID)
+				requestLog(r).Error("lookup parent issue", "err", err, "parent_id", body.ParentID)
 				WriteError(w, ErrInternal, "failed to verify parent issue", http.StatusInternalServerError)
 			}
 			return
@@ -85,7 +130,9 @@ func (s *Server) handleCreateIssue(w http.ResponseWriter, r *http.Request) {
 		Acceptance:     body.Acceptance,
 		Sprint:         body.Sprint,
 		Minor:          body.Minor,
-		CreatorSession: s.sessionID,
+		Inbox:          body.Inbox || triage.Routes(s.baseDir, triage.SourceAPI),
+		Confidential:   body.Confidential,
+		CreatorSession: s.requestSession(r),
 		DeferUntil:     deferUntil,
 		DueDate:        dueDate,
 	}
@@ -94,22 +141,34 @@ func (s *Server) handleCreateIssue(w http.ResponseWriter, r *http.Request) {
 	gitState, _ := git.GetState()
 	if gitState != nil {
 		issue.CreatedBranch = gitState.Branch
+		issue.CreatedRepo = gitState.Repo
+	}
+
+	if issue.Confidential {
+		if _, _, err := confidential.EnsureKey(s.baseDir); err != nil {
+			requestLog(r).Error("confidential key", "err", err)
+			WriteError(w, ErrInternal, "failed to set up the confidential key", http.StatusInternalServerError)
+			return
+		}
 	}
 
 	// Create atomically with action log
-	if err := s.db.CreateIssueLogged(issue, s.sessionID); err != nil {
-		slog.Error("create issue", "err", err)
-		WriteError(w, ErrInternal, "failed to create issue", http.StatusInternalServerError)
+	if err := s.db.CreateIssueLogged(issue, s.requestSession(r)); err != nil {
+		if !writeRejection(w, err) {
+			requestLog(r).Error("create issue", "err", err)
+			WriteError(w, ErrInternal, "failed to create issue", http.StatusInternalServerError)
+		}
 		return
 	}
 
 	// Record session action for bypass prevention
-	if err := s.db.RecordSessionAction(issue.ID, s.sessionID, models.ActionSessionCreated); err != nil {
-		slog.Warn("failed to record session history", "err", err)
+	if err := s.db.RecordSessionAction(issue.ID, s.requestSession(r), models.ActionSessionCreated); err != nil {
+		requestLog(r).Warn("failed to record session history", "err", err)
 	}
 
-	s.NotifyChange()
+	s.NotifyChange(r)
 
+	s.revealIssue(r, issue, nil)
 	dto := IssueToDTO(issue)
 	WriteSuccess(w, map[string]interface{}{"issue": dto}, http.StatusCreated)
 }
@@ -147,11 +206,21 @@ func (s *Server) handleUpdateIssue(w http.ResponseWriter, r *http.Request) {
 		if strings.Contains(err.Error(), "not found") {
 			WriteError(w, ErrNotFound, fmt.Sprintf("issue not found: %s", issueID), http.StatusNotFound)
 		} else {
-			slog.Error("get issue for update", "err", err, "id", issueID)
+			requestLog(r).Error("get issue for update", "err", err, "id", issueID)
 			WriteError(w, ErrInternal, "failed to fetch issue", http.StatusInternalServerError)
 		}
 		return
 	}
+	if err := s.db.CheckClosedEdit(issue.ID, s.requestSession(r), "update", closedOverride(r)); err != nil {
+		if !writeRejection(w, err) {
+			requestLog(r).Error("check closed issue", "err", err, "id", issue.ID)
+			WriteError(w, ErrInternal, "failed to update issue", http.StatusInternalServerError)
+		}
+		return
+	}
+	if (body.Description != nil || body.Acceptance != nil) && !s.requireConfidentialAccess(w, r, issue) {
+		return
+	}
 
 	// Apply only non-nil fields
 	if body.Title != nil {
@@ -185,7 +254,7 @@ func (s *Server) handleUpdateIssue(w http.ResponseWriter, r *http.Request) {
 				if strings.Contains(err.Error(), "not found") {
 					WriteError(w, ErrNotFound, fmt.Sprintf("parent issue not found: %s", parentID), http.StatusNotFound)
 				} else {
-					slog.Error("lookup parent issue", "err", err, "parent_id", parentID)
+					requestLog(r).Error("lookup parent issue", "err", err, "parent_id", parentID)
 					WriteError(w, ErrInternal, "failed to verify parent issue", http.StatusInternalServerError)
 				}
 				return
@@ -217,14 +286,17 @@ func (s *Server) handleUpdateIssue(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Update atomically with action log
-	if err := s.db.UpdateIssueLogged(issue, s.sessionID, models.ActionUpdate); err != nil {
-		slog.Error("update issue", "err", err, "id", issueID)
-		WriteError(w, ErrInternal, "failed to update issue", http.StatusInternalServerError)
+	if err := s.updateIssueLogged(r, issue, models.ActionUpdate); err != nil {
+		if !writeRejection(w, err) {
+			requestLog(r).Error("update issue", "err", err, "id", issueID)
+			WriteError(w, ErrInternal, "failed to update issue", http.StatusInternalServerError)
+		}
 		return
 	}
 
-	s.NotifyChange()
+	s.NotifyChange(r)
 
+	s.revealIssue(r, issue, nil)
 	dto := IssueToDTO(issue)
 	WriteSuccess(w, map[string]interface{}{"issue": dto}, http.StatusOK)
 }
@@ -247,22 +319,93 @@ func (s *Server) handleDeleteIssue(w http.ResponseWriter, r *http.Request) {
 		if strings.Contains(err.Error(), "not found") {
 			WriteError(w, ErrNotFound, fmt.Sprintf("issue not found: %s", issueID), http.StatusNotFound)
 		} else {
-			slog.Error("get issue for delete", "err", err, "id", issueID)
+			requestLog(r).Error("get issue for delete", "err", err, "id", issueID)
 			WriteError(w, ErrInternal, "failed to fetch issue", http.StatusInternalServerError)
 		}
 		return
 	}
 
-	// Soft delete with action log
-	if err := s.db.DeleteIssueLogged(issue.ID, s.sessionID); err != nil {
-		slog.Error("delete issue", "err", err, "id", issue.ID)
+	// Soft delete with action log. A still-referenced issue is refused
+	// with the references listed, unless cascade=true cleans them up.
+	cascade := r.URL.Query().Get("cascade") == "true"
+	refs, err := s.db.DeleteIssueRefsLogged(issue.ID, s.requestSession(r), cascade)
+	if err != nil {
+		var refErr *db.ReferencesError
+		if errors.As(err, &refErr) {
+			WriteErrorDetails(w, ErrConflict, refErr.Error(), refErr.Refs, http.StatusConflict)
+			return
+		}
+		requestLog(r).Error("delete issue", "err", err, "id", issue.ID)
 		WriteError(w, ErrInternal, "failed to delete issue", http.StatusInternalServerError)
 		return
 	}
 
-	s.NotifyChange()
+	s.NotifyChange(r)
 
-	WriteSuccess(w, map[string]interface{}{"deleted": true}, http.StatusOK)
+	WriteSuccess(w, map[string]interface{}{"deleted": true, "cleaned": refs}, http.StatusOK)
+}
+
+// ============================================================================
+// POST /v1/issues/{id}/move — Reparent Subtree
+// ============================================================================
+
+// IssueMoveBody represents the expected JSON body for moving an issue.
+// An empty parent_id makes the issue a root.
+type IssueMoveBody struct {
+	ParentID *string `json:"parent_id"`
+}
+
+// handleMoveIssue reparents an issue and, with it, its subtree. The
+// hierarchy check and the update happen under one write lock.
+func (s *Server) handleMoveIssue(w http.ResponseWriter, r *http.Request) {
+	issueID := r.PathValue("id")
+	if issueID == "" {
+		WriteError(w, ErrValidation, "issue id is required", http.StatusBadRequest)
+		return
+	}
+
+	var body IssueMoveBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		WriteError(w, ErrValidation, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if body.ParentID == nil {
+		WriteValidation(w, []FieldError{{
+			Field:   "parent_id",
+			Rule:    "required",
+			Message: `parent_id is required; use "" to make the issue a root`,
+		}})
+		return
+	}
+	if err := s.db.CheckClosedEdit(issueID, s.requestSession(r), "move", closedOverride(r)); err != nil {
+		if !writeRejection(w, err) {
+			requestLog(r).Error("check closed issue", "err", err, "id", issueID)
+			WriteError(w, ErrInternal, "failed to move issue", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	issue, subtree, err := s.writeDB(r).MoveIssueLogged(issueID, *body.ParentID, s.requestSession(r))
+	if err != nil {
+		switch {
+		case writeRejection(w, err):
+		case strings.HasPrefix(err.Error(), "parent issue not found"):
+			WriteError(w, ErrNotFound, err.Error(), http.StatusNotFound)
+		case strings.Contains(err.Error(), "not found"):
+			WriteError(w, ErrNotFound, fmt.Sprintf("issue not found: %s", issueID), http.StatusNotFound)
+		default:
+			requestLog(r).Error("move issue", "err", err, "id", issueID)
+			WriteError(w, ErrInternal, "failed to move issue", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	s.NotifyChange(r)
+
+	WriteSuccess(w, map[string]interface{}{
+		"issue":   IssueToDTO(issue),
+		"subtree": subtree,
+	}, http.StatusOK)
 }
 
 // ============================================================================
@@ -306,14 +449,14 @@ func (s *Server) handleCreateBoard(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	board, err := s.db.CreateBoardLogged(body.Name, body.Query, s.sessionID)
+	board, err := s.db.CreateBoardLogged(body.Name, body.Query, s.requestSession(r))
 	if err != nil {
-		slog.Error("create board", "err", err)
+		requestLog(r).Error("create board", "err", err)
 		WriteError(w, ErrInternal, "failed to create board", http.StatusInternalServerError)
 		return
 	}
 
-	s.NotifyChange()
+	s.NotifyChange(r)
 
 	dto := BoardToDTO(board)
 	WriteSuccess(w, map[string]interface{}{"board": dto}, http.StatusCreated)
@@ -350,7 +493,7 @@ func (s *Server) handleUpdateBoard(w http.ResponseWriter, r *http.Request) {
 		if strings.Contains(err.Error(), "not found") {
 			WriteError(w, ErrNotFound, fmt.Sprintf("board not found: %s", boardID), http.StatusNotFound)
 		} else {
-			slog.Error("get board for update", "err", err, "id", boardID)
+			requestLog(r).Error("get board for update", "err", err, "id", boardID)
 			WriteError(w, ErrInternal, "failed to fetch board", http.StatusInternalServerError)
 		}
 		return
@@ -391,13 +534,13 @@ func (s *Server) handleUpdateBoard(w http.ResponseWriter, r *http.Request) {
 		board.Query = *body.Query
 	}
 
-	if err := s.db.UpdateBoardLogged(board, s.sessionID); err != nil {
-		slog.Error("update board", "err", err, "id", boardID)
+	if err := s.db.UpdateBoardLogged(board, s.requestSession(r)); err != nil {
+		requestLog(r).Error("update board", "err", err, "id", boardID)
 		WriteError(w, ErrInternal, "failed to update board", http.StatusInternalServerError)
 		return
 	}
 
-	s.NotifyChange()
+	s.NotifyChange(r)
 
 	dto := BoardToDTO(board)
 	WriteSuccess(w, map[string]interface{}{"board": dto}, http.StatusOK)
@@ -421,7 +564,7 @@ func (s *Server) handleDeleteBoard(w http.ResponseWriter, r *http.Request) {
 		if strings.Contains(err.Error(), "not found") {
 			WriteError(w, ErrNotFound, fmt.Sprintf("board not found: %s", boardID), http.StatusNotFound)
 		} else {
-			slog.Error("get board for delete", "err", err, "id", boardID)
+			requestLog(r).Error("get board for delete", "err", err, "id", boardID)
 			WriteError(w, ErrInternal, "failed to fetch board", http.StatusInternalServerError)
 		}
 		return
@@ -433,13 +576,13 @@ func (s *Server) handleDeleteBoard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := s.db.DeleteBoardLogged(board.ID, s.sessionID); err != nil {
-		slog.Error("delete board", "err", err, "id", boardID)
+	if err := s.db.DeleteBoardLogged(board.ID, s.requestSession(r)); err != nil {
+		requestLog(r).Error("delete board", "err", err, "id", boardID)
 		WriteError(w, ErrInternal, "failed to delete board", http.StatusInternalServerError)
 		return
 	}
 
-	s.NotifyChange()
+	s.NotifyChange(r)
 
 	WriteSuccess(w, map[string]interface{}{"deleted": true}, http.StatusOK)
 }
@@ -484,7 +627,7 @@ func (s *Server) handleSetBoardPosition(w http.ResponseWriter, r *http.Request)
 		if strings.Contains(err.Error(), "not found") {
 			WriteError(w, ErrNotFound, fmt.Sprintf("board not found: %s", boardID), http.StatusNotFound)
 		} else {
-			slog.Error("get board for position", "err", err, "id", boardID)
+			requestLog(r).Error("get board for position", "err", err, "id", boardID)
 			WriteError(w, ErrInternal, "failed to fetch board", http.StatusInternalServerError)
 		}
 		return
@@ -497,7 +640,7 @@ func (s *Server) handleSetBoardPosition(w http.ResponseWriter, r *http.Request)
 		if strings.Contains(err.Error(), "not found") {
 			WriteError(w, ErrNotFound, fmt.Sprintf("issue not found: %s", body.IssueID), http.StatusNotFound)
 		} else {
-			slog.Error("get issue for board position", "err", err, "issue_id", body.IssueID)
+			requestLog(r).Error("get issue for board position", "err", err, "issue_id", body.IssueID)
 			WriteError(w, ErrInternal, "failed to fetch issue", http.StatusInternalServerError)
 		}
 		return
@@ -506,19 +649,19 @@ func (s *Server) handleSetBoardPosition(w http.ResponseWriter, r *http.Request)
 	// Compute sort key from the position slot
 	sortKey, _, err := s.db.ComputeInsertPosition(board.ID, body.Position)
 	if err != nil {
-		slog.Error("compute insert position", "err", err, "board_id", board.ID, "position", body.Position)
+		requestLog(r).Error("compute insert position", "err", err, "board_id", board.ID, "position", body.Position)
 		WriteError(w, ErrInternal, "failed to compute position", http.StatusInternalServerError)
 		return
 	}
 
 	// Set the position
-	if err := s.db.SetIssuePositionLogged(board.ID, normalizedIssueID, sortKey, s.sessionID); err != nil {
-		slog.Error("set board position", "err", err, "board_id", board.ID, "issue_id", normalizedIssueID)
+	if err := s.db.SetIssuePositionLogged(board.ID, normalizedIssueID, sortKey, s.requestSession(r)); err != nil {
+		requestLog(r).Error("set board position", "err", err, "board_id", board.ID, "issue_id", normalizedIssueID)
 		WriteError(w, ErrInternal, "failed to set position", http.StatusInternalServerError)
 		return
 	}
 
-	s.NotifyChange()
+	s.NotifyChange(r)
 
 	WriteSuccess(w, map[string]interface{}{"positioned": true}, http.StatusOK)
 }
@@ -547,7 +690,7 @@ func (s *Server) handleRemoveBoardPosition(w http.ResponseWriter, r *http.Reques
 		if strings.Contains(err.Error(), "not found") {
 			WriteError(w, ErrNotFound, fmt.Sprintf("board not found: %s", boardID), http.StatusNotFound)
 		} else {
-			slog.Error("get board for position removal", "err", err, "id", boardID)
+			requestLog(r).Error("get board for position removal", "err", err, "id", boardID)
 			WriteError(w, ErrInternal, "failed to fetch board", http.StatusInternalServerError)
 		}
 		return
@@ -555,17 +698,17 @@ func (s *Server) handleRemoveBoardPosition(w http.ResponseWriter, r *http.Reques
 
 	normalizedIssueID := db.NormalizeIssueID(issueID)
 
-	if err := s.db.RemoveIssuePositionLogged(board.ID, normalizedIssueID, s.sessionID); err != nil {
+	if err := s.db.RemoveIssuePositionLogged(board.ID, normalizedIssueID, s.requestSession(r)); err != nil {
 		if strings.Contains(err.Error(), "not positioned") {
 			WriteError(w, ErrNotFound, fmt.Sprintf("issue %s not positioned on board %s", issueID, boardID), http.StatusNotFound)
 		} else {
-			slog.Error("remove board position", "err", err, "board_id", board.ID, "issue_id", normalizedIssueID)
+			requestLog(r).Error("remove board position", "err", err, "board_id", board.ID, "issue_id", normalizedIssueID)
 			WriteError(w, ErrInternal, "failed to remove position", http.StatusInternalServerError)
 		}
 		return
 	}
 
-	s.NotifyChange()
+	s.NotifyChange(r)
 
 	WriteSuccess(w, map[string]interface{}{"removed": true}, http.StatusOK)
 }
@@ -609,27 +752,36 @@ func (s *Server) handleAddComment(w http.ResponseWriter, r *http.Request) {
 		if strings.Contains(err.Error(), "not found") {
 			WriteError(w, ErrNotFound, fmt.Sprintf("issue not found: %s", issueID), http.StatusNotFound)
 		} else {
-			slog.Error("get issue for comment", "err", err, "id", issueID)
+			requestLog(r).Error("get issue for comment", "err", err, "id", issueID)
 			WriteError(w, ErrInternal, "failed to fetch issue", http.StatusInternalServerError)
 		}
 		return
 	}
+	if err := s.db.CheckClosedEdit(issue.ID, s.requestSession(r), "comment on", closedOverride(r)); err != nil {
+		if !writeRejection(w, err) {
+			requestLog(r).Error("check closed issue", "err", err, "id", issue.ID)
+			WriteError(w, ErrInternal, "failed to add comment", http.StatusInternalServerError)
+		}
+		return
+	}
 
 	comment := &models.Comment{
 		IssueID:   issue.ID,
-		SessionID: s.sessionID,
+		SessionID: s.requestSession(r),
 		Text:      body.Text,
 	}
 
-	if err := s.db.AddComment(comment); err != nil {
-		slog.Error("add comment", "err", err, "issue_id", issue.ID)
+	if err := s.writeDB(r).AddComment(comment); err != nil {
+		requestLog(r).Error("add comment", "err", err, "issue_id", issue.ID)
 		WriteError(w, ErrInternal, "failed to add comment", http.StatusInternalServerError)
 		return
 	}
 
-	s.NotifyChange()
+	s.NotifyChange(r)
 
-	dto := CommentToDTO(comment)
+	comments := []models.Comment{*comment}
+	s.revealIssue(r, issue, comments)
+	dto := CommentToDTO(&comments[0])
 	WriteSuccess(w, map[string]interface{}{"comment": dto}, http.StatusCreated)
 }
 
@@ -654,7 +806,7 @@ func (s *Server) handleDeleteComment(w http.ResponseWriter, r *http.Request) {
 	// Look up the comment and verify it belongs to this issue
 	comment, err := s.db.GetCommentByID(commentID)
 	if err != nil {
-		slog.Error("get comment for delete", "err", err, "comment_id", commentID)
+		requestLog(r).Error("get comment for delete", "err", err, "comment_id", commentID)
 		WriteError(w, ErrInternal, "failed to fetch comment", http.StatusInternalServerError)
 		return
 	}
@@ -666,19 +818,86 @@ func (s *Server) handleDeleteComment(w http.ResponseWriter, r *http.Request) {
 		WriteError(w, ErrNotFound, fmt.Sprintf("comment %s not found on issue %s", commentID, issueID), http.StatusNotFound)
 		return
 	}
+	if err := s.db.CheckClosedEdit(issueID, s.requestSession(r), "delete comments on", closedOverride(r)); err != nil {
+		if !writeRejection(w, err) {
+			requestLog(r).Error("check closed issue", "err", err, "id", issueID)
+			WriteError(w, ErrInternal, "failed to delete comment", http.StatusInternalServerError)
+		}
+		return
+	}
 
 	// Hard-delete with action log
-	if err := s.db.DeleteCommentLogged(commentID, s.sessionID); err != nil {
-		slog.Error("delete comment", "err", err, "comment_id", commentID)
+	if err := s.writeDB(r).DeleteCommentLogged(commentID, s.requestSession(r)); err != nil {
+		requestLog(r).Error("delete comment", "err", err, "comment_id", commentID)
 		WriteError(w, ErrInternal, "failed to delete comment", http.StatusInternalServerError)
 		return
 	}
 
-	s.NotifyChange()
+	s.NotifyChange(r)
 
 	WriteSuccess(w, map[string]interface{}{"deleted": true}, http.StatusOK)
 }
 
+// ============================================================================
+// PATCH /v1/issues/{id}/comments/{comment_id} — Edit Comment
+// ============================================================================
+
+// handleUpdateComment replaces a comment's text. The previous text is kept
+// as a revision.
+func (s *Server) handleUpdateComment(w http.ResponseWriter, r *http.Request) {
+	issueID := db.NormalizeIssueID(r.PathValue("id"))
+	commentID := r.PathValue("comment_id")
+
+	var body CommentCreateBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		WriteError(w, ErrValidation, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(body.Text) == "" {
+		WriteValidation(w, []FieldError{{
+			Field:   "text",
+			Rule:    "required",
+			Message: "text is required",
+		}})
+		return
+	}
+
+	comment, err := s.db.GetCommentByID(commentID)
+	if err != nil {
+		requestLog(r).Error("get comment for update", "err", err, "comment_id", commentID)
+		WriteError(w, ErrInternal, "failed to fetch comment", http.StatusInternalServerError)
+		return
+	}
+	if comment == nil || comment.IssueID != issueID {
+		WriteError(w, ErrNotFound, fmt.Sprintf("comment %s not found on issue %s", commentID, issueID), http.StatusNotFound)
+		return
+	}
+	if confidential.IsSealed(comment.Text) {
+		issue, ok := s.lookupIssue(w, r)
+		if !ok || !s.requireConfidentialAccess(w, r, issue) {
+			return
+		}
+	}
+	if err := s.db.CheckClosedEdit(issueID, s.requestSession(r), "edit comments on", closedOverride(r)); err != nil {
+		if !writeRejection(w, err) {
+			requestLog(r).Error("check closed issue", "err", err, "id", issueID)
+			WriteError(w, ErrInternal, "failed to update comment", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if err := s.writeDB(r).UpdateCommentLogged(commentID, body.Text, s.requestSession(r)); err != nil {
+		requestLog(r).Error("update comment", "err", err, "comment_id", commentID)
+		WriteError(w, ErrInternal, "failed to update comment", http.StatusInternalServerError)
+		return
+	}
+	comment.Text = body.Text
+
+	s.NotifyChange(r)
+
+	WriteSuccess(w, map[string]interface{}{"comment": CommentToDTO(comment)}, http.StatusOK)
+}
+
 // ============================================================================
 // POST /v1/issues/{id}/dependencies — Add Dependency
 // ============================================================================
@@ -716,7 +935,7 @@ func (s *Server) handleAddDependency(w http.ResponseWriter, r *http.Request) {
 		if strings.Contains(err.Error(), "not found") {
 			WriteError(w, ErrNotFound, fmt.Sprintf("issue not found: %s", requestedIssueID), http.StatusNotFound)
 		} else {
-			slog.Error("get issue for dependency", "err", err, "id", requestedIssueID)
+			requestLog(r).Error("get issue for dependency", "err", err, "id", requestedIssueID)
 			WriteError(w, ErrInternal, "failed to fetch issue", http.StatusInternalServerError)
 		}
 		return
@@ -724,6 +943,11 @@ func (s *Server) handleAddDependency(w http.ResponseWriter, r *http.Request) {
 	issueID := issue.ID
 	dependsOnID := db.NormalizeIssueID(body.DependsOn)
 
+	if xref.IsQualified(dependsOnID) {
+		s.addCrossProjectDependency(w, r, issueID, dependsOnID)
+		return
+	}
+
 	// Validate both issues exist, check for cycles and duplicates
 	if err := dependency.Validate(s.db, issueID, dependsOnID); err != nil {
 		if err == dependency.ErrDependencyExists {
@@ -739,19 +963,19 @@ func (s *Server) handleAddDependency(w http.ResponseWriter, r *http.Request) {
 			WriteError(w, ErrValidation, errMsg, http.StatusBadRequest)
 			return
 		}
-		slog.Error("validate dependency", "err", err, "issue_id", issueID, "depends_on", dependsOnID)
+		requestLog(r).Error("validate dependency", "err", err, "issue_id", issueID, "depends_on", dependsOnID)
 		WriteError(w, ErrInternal, "failed to validate dependency", http.StatusInternalServerError)
 		return
 	}
 
 	// Add the dependency with action log
-	if err := s.db.AddDependencyLogged(issueID, dependsOnID, "depends_on", s.sessionID); err != nil {
-		slog.Error("add dependency", "err", err, "issue_id", issueID, "depends_on", dependsOnID)
+	if err := s.db.AddDependencyLogged(issueID, dependsOnID, "depends_on", s.requestSession(r)); err != nil {
+		requestLog(r).Error("add dependency", "err", err, "issue_id", issueID, "depends_on", dependsOnID)
 		WriteError(w, ErrInternal, "failed to add dependency", http.StatusInternalServerError)
 		return
 	}
 
-	s.NotifyChange()
+	s.NotifyChange(r)
 
 	depID := db.DependencyID(issueID, dependsOnID, "depends_on")
 	dto := DependencyDTO{
@@ -764,6 +988,62 @@ func (s *Server) handleAddDependency(w http.ResponseWriter, r *http.Request) {
 	WriteSuccess(w, map[string]interface{}{"dependency": dto}, http.StatusCreated)
 }
 
+// addCrossProjectDependency handles POST .../dependencies for a depends_on
+// qualified with a linked project. The referenced issue must exist there;
+// its canonical ID is what gets stored.
+func (s *Server) addCrossProjectDependency(w http.ResponseWriter, r *http.Request, issueID, qualifiedID string) {
+	ref, ok := xref.Parse(qualifiedID)
+	if !ok {
+		WriteValidation(w, []FieldError{{
+			Field:   "depends_on",
+			Rule:    "format",
+			Value:   qualifiedID,
+			Message: "cross-project references look like <project>/<issue-id>",
+		}})
+		return
+	}
+
+	resolver := s.newResolver()
+	defer resolver.Close()
+	target, err := resolver.Resolve(ref)
+	if err != nil {
+		if errors.Is(err, xref.ErrUnknownProject) {
+			WriteValidation(w, []FieldError{{
+				Field:   "depends_on",
+				Rule:    "linked_project",
+				Value:   ref.Project,
+				Message: err.Error(),
+			}})
+			return
+		}
+		WriteError(w, ErrNotFound, fmt.Sprintf("issue not found: %s", qualifiedID), http.StatusNotFound)
+		return
+	}
+	ref.IssueID = target.ID
+	dependsOnID := ref.String()
+
+	existing, _ := s.db.GetDependencies(issueID)
+	if slices.Contains(existing, dependsOnID) {
+		WriteError(w, ErrConflict, "dependency already exists", http.StatusConflict)
+		return
+	}
+
+	if err := s.db.AddDependencyLogged(issueID, dependsOnID, "depends_on", s.requestSession(r)); err != nil {
+		requestLog(r).Error("add dependency", "err", err, "issue_id", issueID, "depends_on", dependsOnID)
+		WriteError(w, ErrInternal, "failed to add dependency", http.StatusInternalServerError)
+		return
+	}
+
+	s.NotifyChange(r)
+
+	dto := DependencyToDTO(&models.IssueDependency{
+		IssueID:      issueID,
+		DependsOnID:  dependsOnID,
+		RelationType: "depends_on",
+	})
+	WriteSuccess(w, map[string]interface{}{"dependency": dto}, http.StatusCreated)
+}
+
 // ============================================================================
 // DELETE /v1/issues/{id}/dependencies/{dep_id} — Remove Dependency
 // ============================================================================
@@ -785,7 +1065,7 @@ func (s *Server) handleDeleteDependency(w http.ResponseWriter, r *http.Request)
 	// Look up the dependency by its deterministic dep_id
 	dep, err := s.db.GetDependencyByDepID(depID)
 	if err != nil {
-		slog.Error("get dependency for delete", "err", err, "dep_id", depID)
+		requestLog(r).Error("get dependency for delete", "err", err, "dep_id", depID)
 		WriteError(w, ErrInternal, "failed to fetch dependency", http.StatusInternalServerError)
 		return
 	}
@@ -801,13 +1081,13 @@ func (s *Server) handleDeleteDependency(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Remove with action log
-	if err := s.db.RemoveDependencyLogged(dep.IssueID, dep.DependsOnID, s.sessionID); err != nil {
-		slog.Error("remove dependency", "err", err, "dep_id", depID)
+	if err := s.db.RemoveRelationLogged(dep.IssueID, dep.DependsOnID, dep.RelationType, s.requestSession(r)); err != nil {
+		requestLog(r).Error("remove dependency", "err", err, "dep_id", depID)
 		WriteError(w, ErrInternal, "failed to remove dependency", http.StatusInternalServerError)
 		return
 	}
 
-	s.NotifyChange()
+	s.NotifyChange(r)
 
 	WriteSuccess(w, map[string]interface{}{"removed": true}, http.StatusOK)
 }
@@ -833,7 +1113,7 @@ func (s *Server) handleSetFocus(w http.ResponseWriter, r *http.Request) {
 	if body.IssueID == nil || *body.IssueID == "" {
 		// Clear focus
 		if err := config.ClearFocus(s.baseDir); err != nil {
-			slog.Error("clear focus", "err", err)
+			requestLog(r).Error("clear focus", "err", err)
 			WriteError(w, ErrInternal, "failed to clear focus", http.StatusInternalServerError)
 			return
 		}
@@ -848,14 +1128,14 @@ func (s *Server) handleSetFocus(w http.ResponseWriter, r *http.Request) {
 		if strings.Contains(err.Error(), "not found") {
 			WriteError(w, ErrNotFound, fmt.Sprintf("issue not found: %s", issueID), http.StatusNotFound)
 		} else {
-			slog.Error("get issue for focus", "err", err, "id", issueID)
+			requestLog(r).Error("get issue for focus", "err", err, "id", issueID)
 			WriteError(w, ErrInternal, "failed to fetch issue", http.StatusInternalServerError)
 		}
 		return
 	}
 
 	if err := config.SetFocus(s.baseDir, issue.ID); err != nil {
-		slog.Error("set focus", "err", err, "issue_id", issue.ID)
+		requestLog(r).Error("set focus", "err", err, "issue_id", issue.ID)
 		WriteError(w, ErrInternal, "failed to set focus", http.StatusInternalServerError)
 		return
 	}
@@ -864,6 +1144,63 @@ func (s *Server) handleSetFocus(w http.ResponseWriter, r *http.Request) {
 	WriteSuccess(w, map[string]interface{}{"focused_issue_id": issue.ID}, http.StatusOK)
 }
 
+// ============================================================================
+// POST /v1/sessions/heartbeat — Session Heartbeat
+// ============================================================================
+
+// HeartbeatBody represents the optional JSON body for a session heartbeat.
+// When SessionID is empty the session the request acts as is bumped.
+type HeartbeatBody struct {
+	SessionID string `json:"session_id"`
+}
+
+// handleSessionHeartbeat bumps a session's last_activity so it is reported as
+// active. Callers bump the session they act as; naming another session
+// needs the admin scope, so one agent can't keep another looking alive.
+func (s *Server) handleSessionHeartbeat(w http.ResponseWriter, r *http.Request) {
+	var body HeartbeatBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		WriteError(w, ErrValidation, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sessionID := strings.TrimSpace(body.SessionID)
+	if sessionID == "" {
+		sessionID = s.requestSession(r)
+	}
+	if tok := requestToken(r); tok != nil && sessionID != tok.SessionID && !tok.HasScope(models.TokenScopeAdmin) {
+		WriteError(w, ErrForbidden, "token "+tok.ID+" may only heartbeat its own session", http.StatusForbidden)
+		return
+	}
+
+	row, err := s.db.GetSessionByID(sessionID)
+	if err != nil {
+		requestLog(r).Error("lookup session for heartbeat", "err", err, "session_id", sessionID)
+		WriteError(w, ErrInternal, "failed to look up session", http.StatusInternalServerError)
+		return
+	}
+	if row == nil {
+		WriteError(w, ErrNotFound, fmt.Sprintf("session not found: %s", sessionID), http.StatusNotFound)
+		return
+	}
+
+	if err := session.Heartbeat(s.db, row.ID); err != nil {
+		requestLog(r).Error("session heartbeat", "err", err, "session_id", row.ID)
+		WriteError(w, ErrInternal, "failed to record heartbeat", http.StatusInternalServerError)
+		return
+	}
+
+	sess, err := session.GetByID(s.db, row.ID)
+	if err != nil || sess == nil {
+		requestLog(r).Error("reload session after heartbeat", "err", err, "session_id", row.ID)
+		WriteError(w, ErrInternal, "failed to reload session", http.StatusInternalServerError)
+		return
+	}
+
+	// Heartbeats only touch session metadata; no sync or change notification.
+	WriteSuccess(w, map[string]interface{}{"session": SessionToDTO(sess)}, http.StatusOK)
+}
+
 // ============================================================================
 // Helpers
 // ============================================================================
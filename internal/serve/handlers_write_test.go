@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/marcus/td/internal/db"
 	"github.com/marcus/td/internal/models"
@@ -339,6 +340,43 @@ func TestCreateIssue_ParentNotFound(t *testing.T) {
 	}
 }
 
+func TestQuickAddIssue(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	resp, env := doJSON(t, ts, "POST", "/v1/issues/quick", QuickAddBody{Text: "Fix login timeout #bug !p1 @sprint-7 +auth +backend due:2026-05-01 3pts"})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %+v", resp.StatusCode, http.StatusCreated, env.Error)
+	}
+	issue := env.Data.(map[string]interface{})["issue"].(map[string]interface{})
+	for field, want := range map[string]interface{}{
+		"title":    "Fix login timeout",
+		"type":     "bug",
+		"priority": "P1",
+		"sprint":   "sprint-7",
+		"due_date": "2026-05-01",
+		"points":   float64(3),
+	} {
+		if issue[field] != want {
+			t.Errorf("%s = %v, want %v", field, issue[field], want)
+		}
+	}
+	if labels := issue["labels"].([]interface{}); len(labels) != 2 || labels[0] != "auth" || labels[1] != "backend" {
+		t.Errorf("labels = %v, want [auth backend]", labels)
+	}
+	if stored, err := srv.db.GetIssue(issue["id"].(string)); err != nil || stored.Sprint != "sprint-7" {
+		t.Errorf("stored sprint = %+v (%v), want sprint-7", stored, err)
+	}
+
+	for _, text := range []string{"", "Fix login timeout due:someday", "#bug !p1", "Fix"} {
+		resp, env := doJSON(t, ts, "POST", "/v1/issues/quick", QuickAddBody{Text: text})
+		if resp.StatusCode != http.StatusBadRequest || env.Error.Code != ErrValidation {
+			t.Errorf("%q: status = %d, error = %+v, want 400 validation", text, resp.StatusCode, env.Error)
+		}
+	}
+}
+
 func TestCreateIssue_WithValidParent(t *testing.T) {
 	srv := newTestServerWithDB(t)
 	ts := httptest.NewServer(srv.Handler())
@@ -639,7 +677,7 @@ func TestDeleteIssue_Success(t *testing.T) {
 	}
 
 	// Verify the issue is soft-deleted in the database
-	issue, err := srv.db.GetIssue(id)
+	issue, err := srv.db.GetIssueWithDeleted(id)
 	if err != nil {
 		t.Fatalf("get issue: %v", err)
 	}
@@ -648,6 +686,38 @@ func TestDeleteIssue_Success(t *testing.T) {
 	}
 }
 
+func TestDeleteIssue_Referenced(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	parent := createTestIssue(t, ts, "Parent that still has a child")
+	_, env := doJSON(t, ts, "POST", "/v1/issues", IssueCreateBody{Title: "Child of the parent", ParentID: parent})
+	if !env.OK {
+		t.Fatalf("create child: %+v", env.Error)
+	}
+	child := env.Data.(map[string]interface{})["issue"].(map[string]interface{})["id"].(string)
+
+	// Refused with the references listed
+	resp, env := doJSON(t, ts, "DELETE", "/v1/issues/"+parent, nil)
+	if resp.StatusCode != http.StatusConflict || env.Error == nil || env.Error.Code != ErrConflict {
+		t.Fatalf("status = %d, error = %+v; want 409 conflict", resp.StatusCode, env.Error)
+	}
+	details, _ := env.Error.Details.(map[string]interface{})
+	if children, _ := details["children"].([]interface{}); len(children) != 1 || children[0] != child {
+		t.Errorf("details = %v, want child %s", details, child)
+	}
+
+	// cascade=true detaches the child and deletes
+	resp, env = doJSON(t, ts, "DELETE", "/v1/issues/"+parent+"?cascade=true", nil)
+	if resp.StatusCode != http.StatusOK || !env.OK {
+		t.Fatalf("cascade status = %d, error = %+v", resp.StatusCode, env.Error)
+	}
+	if got, err := srv.db.GetIssue(child); err != nil || got.ParentID != "" {
+		t.Errorf("child = %+v, %v; want detached", got, err)
+	}
+}
+
 func TestDeleteIssue_NotFound(t *testing.T) {
 	srv := newTestServerWithDB(t)
 	ts := httptest.NewServer(srv.Handler())
@@ -663,6 +733,60 @@ func TestDeleteIssue_NotFound(t *testing.T) {
 	}
 }
 
+func TestMoveIssue(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	epic := createTestIssue(t, ts, "Epic that will take a subtree")
+	story := createTestIssue(t, ts, "Story that moves with its task")
+	_, env := doJSON(t, ts, "POST", "/v1/issues", IssueCreateBody{Title: "Task under the story", ParentID: story})
+	if !env.OK {
+		t.Fatalf("create task: %+v", env.Error)
+	}
+	task := env.Data.(map[string]interface{})["issue"].(map[string]interface{})["id"].(string)
+
+	resp, env := doJSON(t, ts, "POST", "/v1/issues/"+story+"/move", map[string]string{"parent_id": epic})
+	if resp.StatusCode != http.StatusOK || !env.OK {
+		t.Fatalf("move status = %d, error = %+v", resp.StatusCode, env.Error)
+	}
+	data := env.Data.(map[string]interface{})
+	if data["issue"].(map[string]interface{})["parent_id"] != epic {
+		t.Errorf("issue = %v, want parent %s", data["issue"], epic)
+	}
+	if subtree, _ := data["subtree"].([]interface{}); len(subtree) != 2 {
+		t.Errorf("subtree = %v, want story and task", data["subtree"])
+	}
+
+	// Moving the epic under its own grandchild is a cycle
+	resp, env = doJSON(t, ts, "POST", "/v1/issues/"+epic+"/move", map[string]string{"parent_id": task})
+	if resp.StatusCode != http.StatusBadRequest || env.Error == nil || env.Error.Code != ErrValidation {
+		t.Fatalf("cycle status = %d, error = %+v; want 400 validation", resp.StatusCode, env.Error)
+	}
+	fields := env.Error.Details.(map[string]interface{})["fields"].([]interface{})
+	if f := fields[0].(map[string]interface{}); f["field"] != "parent_id" || f["rule"] != "cycle" {
+		t.Errorf("field error = %v, want parent_id cycle", f)
+	}
+
+	// PATCH runs the same check
+	self := epic
+	resp, env = doJSON(t, ts, "PATCH", "/v1/issues/"+epic, IssueUpdateBody{ParentID: &self})
+	if resp.StatusCode != http.StatusBadRequest || env.Error == nil || env.Error.Code != ErrValidation {
+		t.Errorf("self-parent status = %d, error = %+v; want 400 validation", resp.StatusCode, env.Error)
+	}
+
+	// An empty parent_id makes the issue a root; a missing one is an error
+	if resp, _ := doJSON(t, ts, "POST", "/v1/issues/"+story+"/move", map[string]string{"parent_id": ""}); resp.StatusCode != http.StatusOK {
+		t.Errorf("move to root status = %d", resp.StatusCode)
+	}
+	if resp, _ := doJSON(t, ts, "POST", "/v1/issues/"+story+"/move", map[string]string{}); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("missing parent_id status = %d, want 400", resp.StatusCode)
+	}
+	if resp, _ := doJSON(t, ts, "POST", "/v1/issues/"+story+"/move", map[string]string{"parent_id": "td-missing"}); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("missing parent status = %d, want 404", resp.StatusCode)
+	}
+}
+
 // ============================================================================
 // Action Log Verification
 // ============================================================================
@@ -700,3 +824,91 @@ func TestCreateIssue_LogsAction(t *testing.T) {
 		t.Error("expected a 'create' action for entity type 'issue' in the action log")
 	}
 }
+
+// ============================================================================
+// POST /v1/sessions/heartbeat
+// ============================================================================
+
+func TestSessionHeartbeat_DefaultsToServerSession(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	stale := time.Now().Add(-time.Hour)
+	if err := srv.db.UpsertSession(&db.SessionRow{
+		ID: "ses_test123", Branch: "default", AgentType: "web",
+		StartedAt: stale, LastActivity: stale,
+	}); err != nil {
+		t.Fatalf("upsert session: %v", err)
+	}
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	resp, env := doJSON(t, ts, "POST", "/v1/sessions/heartbeat", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (error = %+v)", resp.StatusCode, env.Error)
+	}
+
+	data := env.Data.(map[string]interface{})
+	sess := data["session"].(map[string]interface{})
+	if sess["id"] != "ses_test123" {
+		t.Errorf("id = %v, want ses_test123", sess["id"])
+	}
+	if sess["liveness"] != "active" {
+		t.Errorf("liveness = %v, want active", sess["liveness"])
+	}
+}
+
+func TestSessionHeartbeat_TokenSession(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	stale := time.Now().Add(-time.Hour)
+	for _, id := range []string{"ses_test123", "ses_agent", "ses_other"} {
+		if err := srv.db.UpsertSession(&db.SessionRow{
+			ID: id, Branch: "default", AgentType: "web",
+			StartedAt: stale, LastActivity: stale,
+		}); err != nil {
+			t.Fatalf("upsert session: %v", err)
+		}
+	}
+	agent, err := srv.db.CreateAPIToken(&models.APIToken{SessionID: "ses_agent", Scopes: []string{"write"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin, err := srv.db.CreateAPIToken(&models.APIToken{SessionID: "ses_agent", Scopes: []string{"write", "admin"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	// Without session_id the token's own session is bumped
+	resp, env := doWithToken(t, ts, agent, "POST", "/v1/sessions/heartbeat", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	if id := env.Data.(map[string]interface{})["session"].(map[string]interface{})["id"]; id != "ses_agent" {
+		t.Errorf("bumped %v, want ses_agent", id)
+	}
+
+	other := HeartbeatBody{SessionID: "ses_other"}
+	if resp, env := doWithToken(t, ts, agent, "POST", "/v1/sessions/heartbeat", other); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("other session status = %d, want 403 (%+v)", resp.StatusCode, env.Error)
+	}
+	if row, _ := srv.db.GetSessionByID("ses_other"); row.LastActivity.After(stale.Add(time.Minute)) {
+		t.Error("forbidden heartbeat bumped ses_other")
+	}
+	if resp, env := doWithToken(t, ts, admin, "POST", "/v1/sessions/heartbeat", other); resp.StatusCode != http.StatusOK {
+		t.Errorf("admin status = %d: %+v", resp.StatusCode, env.Error)
+	}
+}
+
+func TestSessionHeartbeat_UnknownSession(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	resp, env := doJSON(t, ts, "POST", "/v1/sessions/heartbeat", HeartbeatBody{SessionID: "ses_missing"})
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", resp.StatusCode)
+	}
+	if env.Error == nil || env.Error.Code != ErrNotFound {
+		t.Errorf("error = %+v, want not_found", env.Error)
+	}
+}
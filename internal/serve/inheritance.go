@@ -0,0 +1,33 @@
+package serve
+
+import (
+	"net/http"
+
+	"github.com/marcus/td/internal/models"
+)
+
+// PriorityInheritanceDTO explains a raised effective priority
+type PriorityInheritanceDTO struct {
+	From string   `json:"from"` // the issue the priority comes from
+	Via  []string `json:"via"`  // the dependency chain from From to this issue, both ends included
+}
+
+// withInheritance sets the DTO's effective priority from the project's
+// inherited priorities
+func (dto *IssueDTO) withInheritance(inherited map[string]models.InheritedPriority) {
+	if ip, ok := inherited[dto.ID]; ok {
+		dto.EffectivePriority = string(ip.Priority)
+		dto.PriorityInheritance = &PriorityInheritanceDTO{From: ip.From, Via: ip.Via}
+	}
+}
+
+// inheritedPriorities looks up the project's raised priorities for the
+// effective_priority DTO field. Lookup errors leave priorities as they are.
+func (s *Server) inheritedPriorities(r *http.Request) map[string]models.InheritedPriority {
+	inherited, err := s.db.InheritedPriorities()
+	if err != nil {
+		requestLog(r).Warn("failed to look up inherited priorities", "err", err)
+		return nil
+	}
+	return inherited
+}
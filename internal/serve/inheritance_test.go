@@ -0,0 +1,51 @@
+package serve
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/models"
+)
+
+func TestIssueDTOEffectivePriority(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	urgent := &models.Issue{Title: "Urgent release fix", Priority: models.PriorityP0}
+	blocker := &models.Issue{Title: "Low priority blocker", Priority: models.PriorityP3}
+	for _, issue := range []*models.Issue{urgent, blocker} {
+		if err := srv.db.CreateIssueLogged(issue, "ses_test123"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := srv.db.AddDependency(urgent.ID, blocker.ID, "depends_on"); err != nil {
+		t.Fatal(err)
+	}
+
+	get := func() map[string]interface{} {
+		_, env := doJSON(t, ts, "GET", "/v1/issues/"+blocker.ID, nil)
+		return env.Data.(map[string]interface{})["issue"].(map[string]interface{})
+	}
+	if got := get(); got["effective_priority"] != "P3" || got["priority_inheritance"] != nil {
+		t.Errorf("policy off = %v, %v", got["effective_priority"], got["priority_inheritance"])
+	}
+
+	if err := config.SetPriorityInheritance(srv.baseDir, true); err != nil {
+		t.Fatal(err)
+	}
+	got := get()
+	why, _ := got["priority_inheritance"].(map[string]interface{})
+	if got["priority"] != "P3" || got["effective_priority"] != "P0" || why["from"] != urgent.ID {
+		t.Errorf("policy on = %v, %v, %v", got["priority"], got["effective_priority"], why)
+	}
+
+	_, env := doJSON(t, ts, "GET", "/v1/issues?sort=priority", nil)
+	for _, v := range env.Data.(map[string]interface{})["issues"].([]interface{}) {
+		issue := v.(map[string]interface{})
+		if issue["id"] == blocker.ID && issue["effective_priority"] != "P0" {
+			t.Errorf("listed blocker = %v", issue["effective_priority"])
+		}
+	}
+}
@@ -8,11 +8,14 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/query"
 )
 
 // ============================================================================
@@ -572,7 +575,7 @@ func TestIntegration_GetIssue_Found(t *testing.T) {
 
 	id := iCreateIssue(t, baseURL, "Detail test issue for integration")
 
-	resp := iDoJSON(t, "GET", baseURL+"/v1/issues/"+id, nil)
+	resp := iDoJSON(t, "GET", baseURL+"/v1/issues/"+id+"?include=all", nil)
 	ok, data, _ := iParseEnvelope(t, resp)
 	if !ok {
 		t.Fatal("get issue failed")
@@ -606,6 +609,77 @@ func TestIntegration_GetIssue_Found(t *testing.T) {
 	if blockedBy == nil {
 		t.Error("data.blocked_by should be an array, not null")
 	}
+
+	for _, key := range []string{"handoffs", "children", "stack", "contributors"} {
+		if arr, _ := data[key].([]interface{}); arr == nil {
+			t.Errorf("data.%s should be an array, not null", key)
+		}
+	}
+	if _, ok := data["counts"]; ok {
+		t.Error("data.counts should be omitted when everything is included")
+	}
+}
+
+func TestIntegration_GetIssue_IncludeExpansion(t *testing.T) {
+	baseURL, _, cleanup := setupIntegrationServer(t)
+	defer cleanup()
+
+	parentID := iCreateIssue(t, baseURL, "Parent for include expansion")
+	resp := iDoJSON(t, "POST", baseURL+"/v1/issues", map[string]interface{}{
+		"title": "Child for include expansion", "parent_id": parentID,
+	})
+	if ok, _, _ := iParseEnvelope(t, resp); !ok {
+		t.Fatal("create child failed")
+	}
+	resp = iDoJSON(t, "POST", baseURL+"/v1/issues/"+parentID+"/comments", map[string]interface{}{"text": "one"})
+	if ok, _, _ := iParseEnvelope(t, resp); !ok {
+		t.Fatal("add comment failed")
+	}
+
+	// Default is the issue plus counts
+	resp = iDoJSON(t, "GET", baseURL+"/v1/issues/"+parentID, nil)
+	ok, data, _ := iParseEnvelope(t, resp)
+	if !ok {
+		t.Fatal("get issue failed")
+	}
+	for _, key := range []string{"logs", "comments", "dependencies", "blocked_by", "handoffs", "latest_handoff", "children"} {
+		if _, present := data[key]; present {
+			t.Errorf("default response should not include %s", key)
+		}
+	}
+	counts, _ := data["counts"].(map[string]interface{})
+	if counts["comments"] != float64(1) || counts["children"] != float64(1) {
+		t.Errorf("counts = %v", counts)
+	}
+
+	// Included collections leave counts
+	resp = iDoJSON(t, "GET", baseURL+"/v1/issues/"+parentID+"?include=children&fields=title", nil)
+	ok, data, _ = iParseEnvelope(t, resp)
+	if !ok {
+		t.Fatal("get issue with children failed")
+	}
+	children, _ := data["children"].([]interface{})
+	if len(children) != 1 {
+		t.Fatalf("children = %v", data["children"])
+	}
+	child, _ := children[0].(map[string]interface{})
+	if child["title"] != "Child for include expansion" || child["status"] != nil {
+		t.Errorf("child not reduced to requested fields: %v", child)
+	}
+	counts, _ = data["counts"].(map[string]interface{})
+	if _, present := counts["children"]; present {
+		t.Errorf("counts should omit included children: %v", counts)
+	}
+	if counts["comments"] != float64(1) {
+		t.Errorf("counts.comments = %v, want 1", counts["comments"])
+	}
+
+	// Unknown include names are rejected
+	resp = iDoJSON(t, "GET", baseURL+"/v1/issues/"+parentID+"?include=attachments", nil)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("unknown include status = %d, want 400", resp.StatusCode)
+	}
 }
 
 func TestIntegration_GetIssue_NotFound(t *testing.T) {
@@ -889,14 +963,43 @@ func TestIntegration_DeleteIssue_Success(t *testing.T) {
 		t.Errorf("deleted = %v, want true", data["deleted"])
 	}
 
-	// Issue should no longer be found via the API (soft-deleted issues
-	// are filtered by GetIssue which checks deleted_at)
+	// Soft-deleted issues are not found by default
 	resp = iDoJSON(t, "GET", baseURL+"/v1/issues/"+id, nil)
-	ok2, _, _ := iParseEnvelope(t, resp)
-	if ok2 {
-		// Soft-deleted issues may still be returned by GetIssue depending
-		// on implementation. Just verify the delete itself succeeded.
-		t.Log("note: soft-deleted issue still returned by GET (expected for some implementations)")
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GET deleted issue status = %d, want 404", resp.StatusCode)
+	}
+	resp.Body.Close()
+
+	// with_deleted=true returns them for admin tooling
+	resp = iDoJSON(t, "GET", baseURL+"/v1/issues/"+id+"?with_deleted=true", nil)
+	ok, data, _ = iParseEnvelope(t, resp)
+	issue, _ := data["issue"].(map[string]interface{})
+	if !ok || issue["deleted_at"] == nil {
+		t.Errorf("GET with_deleted issue = %v, want deleted_at set", data)
+	}
+}
+
+func TestIntegration_ListIssues_WithDeleted(t *testing.T) {
+	baseURL, _, cleanup := setupIntegrationServer(t)
+	defer cleanup()
+
+	iCreateIssue(t, baseURL, "Live issue that stays listed")
+	id := iCreateIssue(t, baseURL, "Issue deleted before listing")
+	iDoJSON(t, "DELETE", baseURL+"/v1/issues/"+id, nil).Body.Close()
+
+	for _, tt := range []struct {
+		query string
+		want  float64
+	}{
+		{"", 1},
+		{"?with_deleted=true", 2},
+		{"?search=is(deleted)&search_mode=tdq", 1},
+	} {
+		resp := iDoJSON(t, "GET", baseURL+"/v1/issues"+tt.query, nil)
+		ok, data, _ := iParseEnvelope(t, resp)
+		if total, _ := data["total"].(float64); !ok || total != tt.want {
+			t.Errorf("GET /v1/issues%s total = %v, want %v", tt.query, data["total"], tt.want)
+		}
 	}
 }
 
@@ -1164,7 +1267,7 @@ func TestIntegration_Transition_WithReason(t *testing.T) {
 	}
 
 	// Verify the log entry was created by checking the issue detail
-	resp = iDoJSON(t, "GET", baseURL+"/v1/issues/"+id, nil)
+	resp = iDoJSON(t, "GET", baseURL+"/v1/issues/"+id+"?include=logs", nil)
 	ok, data, _ := iParseEnvelope(t, resp)
 	if !ok {
 		t.Fatal("get issue failed")
@@ -1377,7 +1480,7 @@ func TestIntegration_AddComment(t *testing.T) {
 	}
 
 	// Verify comment appears in issue detail
-	resp = iDoJSON(t, "GET", baseURL+"/v1/issues/"+id, nil)
+	resp = iDoJSON(t, "GET", baseURL+"/v1/issues/"+id+"?include=comments", nil)
 	ok, data, _ = iParseEnvelope(t, resp)
 	if !ok {
 		t.Fatal("get issue failed")
@@ -1456,7 +1559,7 @@ func TestIntegration_DeleteComment(t *testing.T) {
 	}
 
 	// Verify comment is gone from issue detail
-	resp = iDoJSON(t, "GET", baseURL+"/v1/issues/"+id, nil)
+	resp = iDoJSON(t, "GET", baseURL+"/v1/issues/"+id+"?include=comments", nil)
 	ok, data, _ = iParseEnvelope(t, resp)
 	if !ok {
 		t.Fatal("get issue failed")
@@ -1559,7 +1662,7 @@ func TestIntegration_AddDependency(t *testing.T) {
 	}
 
 	// Verify dependency appears in issue detail
-	resp = iDoJSON(t, "GET", baseURL+"/v1/issues/"+id1, nil)
+	resp = iDoJSON(t, "GET", baseURL+"/v1/issues/"+id1+"?include=dependencies", nil)
 	ok, data, _ = iParseEnvelope(t, resp)
 	if !ok {
 		t.Fatal("get issue failed")
@@ -1652,7 +1755,7 @@ func TestIntegration_DeleteDependency(t *testing.T) {
 	}
 
 	// Verify dependency is gone from issue detail
-	resp = iDoJSON(t, "GET", baseURL+"/v1/issues/"+id1, nil)
+	resp = iDoJSON(t, "GET", baseURL+"/v1/issues/"+id1+"?include=dependencies", nil)
 	ok, data, _ = iParseEnvelope(t, resp)
 	if !ok {
 		t.Fatal("get issue failed")
@@ -1954,6 +2057,58 @@ func TestIntegration_GetBoard(t *testing.T) {
 	}
 }
 
+func TestIntegration_IssueCards(t *testing.T) {
+	baseURL, database, cleanup := setupIntegrationServer(t)
+	defer cleanup()
+
+	blocker := iCreateIssue(t, baseURL, "Card blocker issue")
+	blocked := iCreateIssue(t, baseURL, "Card blocked issue")
+	if err := database.AddDependencyLogged(blocked, blocker, "depends_on", "ses_test"); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.AddComment(&models.Comment{IssueID: blocked, SessionID: "ses_test", Text: "waiting"}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp := iDoJSON(t, "GET", baseURL+"/v1/monitor", nil)
+	ok, data, _ := iParseEnvelope(t, resp)
+	if !ok {
+		t.Fatal("monitor failed")
+	}
+	mon, _ := data["monitor"].(map[string]interface{})
+	cards, _ := mon["cards"].(map[string]interface{})
+	card, _ := cards[blocked].(map[string]interface{})
+	if card == nil || card["blocked_by_count"] != float64(1) || card["comment_count"] != float64(1) || card["last_activity"] == nil {
+		t.Errorf("monitor card = %v", card)
+	}
+	taskList, _ := mon["task_list"].(map[string]interface{})
+	if list, _ := taskList["blocked"].([]interface{}); len(list) != 1 || list[0].(map[string]interface{})["id"] != blocked {
+		t.Errorf("task_list.blocked = %v", list)
+	}
+
+	boardID := iCreateBoard(t, baseURL, "Card Board", "")
+	resp = iDoJSON(t, "GET", baseURL+"/v1/boards/"+boardID, nil)
+	ok, data, _ = iParseEnvelope(t, resp)
+	if !ok {
+		t.Fatal("get board failed")
+	}
+	issues, _ := data["issues"].([]interface{})
+	found := false
+	for _, item := range issues {
+		entry := item.(map[string]interface{})
+		if entry["issue"].(map[string]interface{})["id"] != blocked {
+			continue
+		}
+		found = true
+		if c, _ := entry["card"].(map[string]interface{}); c == nil || c["comment_count"] != float64(1) || c["blocked_by_count"] != float64(1) {
+			t.Errorf("board entry = %v", entry)
+		}
+	}
+	if !found {
+		t.Errorf("blocked issue missing from board: %v", issues)
+	}
+}
+
 func TestIntegration_UpdateBoard(t *testing.T) {
 	baseURL, _, cleanup := setupIntegrationServer(t)
 	defer cleanup()
@@ -2244,6 +2399,67 @@ func TestIntegration_SSE_ReceivesRefreshOnWrite(t *testing.T) {
 	}
 }
 
+func TestIntegration_SSE_QueryFilter(t *testing.T) {
+	tmpDir := t.TempDir()
+	database, err := db.Initialize(tmpDir)
+	if err != nil {
+		t.Fatalf("db.Initialize: %v", err)
+	}
+	defer database.Close()
+
+	sess, err := GetOrCreateWebSession(database)
+	if err != nil {
+		t.Fatalf("GetOrCreateWebSession: %v", err)
+	}
+	srv := NewServer(database, tmpDir, sess.ID, ServeConfig{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	srv.sseHub.Start(ctx)
+	defer srv.sseHub.Stop()
+
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	resp := iDoJSON(t, "GET", ts.URL+"/v1/events?query="+url.QueryEscape("type = bug AND"), nil)
+	if _, _, errP := iParseEnvelope(t, resp); resp.StatusCode != http.StatusBadRequest || errP["code"] != ErrValidation {
+		t.Errorf("invalid query: status = %d, error = %v, want 400 validation_error", resp.StatusCode, errP)
+	}
+
+	bugID := iCreateIssueWithFields(t, ts.URL, map[string]interface{}{"title": "Crash on empty config", "type": "bug"})
+	filter, err := query.Parse("type = bug AND is(open)")
+	if err != nil {
+		t.Fatalf("parse filter: %v", err)
+	}
+	ch := srv.sseHub.registerFiltered(filter)
+	defer srv.sseHub.unregister(ch)
+
+	// A change to a task is filtered out
+	iCreateIssue(t, ts.URL, "Unrelated task for the filter")
+	select {
+	case event := <-ch:
+		t.Fatalf("got %s event %s for a non-matching issue", event.Event, event.Data)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// Closing the bug takes it out of the filter but still notifies
+	resp = iDoJSON(t, "POST", ts.URL+"/v1/issues/"+bugID+"/close", nil)
+	if ok, _, errP := iParseEnvelope(t, resp); !ok {
+		t.Fatalf("close bug: %v", errP)
+	}
+	select {
+	case event := <-ch:
+		var data refreshData
+		if err := json.Unmarshal([]byte(event.Data), &data); err != nil {
+			t.Fatalf("decode refresh: %v", err)
+		}
+		if event.Event != "refresh" || len(data.IssueIDs) != 1 || data.IssueIDs[0] != bugID {
+			t.Errorf("event = %s %s, want refresh naming %s", event.Event, event.Data, bugID)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for refresh for a matching issue")
+	}
+}
+
 func TestIntegration_SSE_Ping(t *testing.T) {
 	// Test that the hub sends ping events via the poll ticker.
 	// We use a very short poll interval and wait for a ping.
@@ -2342,3 +2558,36 @@ func TestIntegration_SearchMode_TDQ_Invalid(t *testing.T) {
 		t.Errorf("error.code = %v, want %s", errP["code"], ErrValidation)
 	}
 }
+
+func TestIntegration_Health_ChangeTokensPerCollection(t *testing.T) {
+	baseURL, _, cleanup := setupIntegrationServer(t)
+	defer cleanup()
+
+	tokens := func() map[string]interface{} {
+		resp, err := http.Get(baseURL + "/health")
+		if err != nil {
+			t.Fatalf("GET /health: %v", err)
+		}
+		_, data, _ := iParseEnvelope(t, resp)
+		tokens, ok := data["change_tokens"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("change_tokens = %T, want object", data["change_tokens"])
+		}
+		return tokens
+	}
+
+	issueID := iCreateIssue(t, baseURL, "Commented issue")
+	before := tokens()
+	resp := iDoJSON(t, "POST", baseURL+"/v1/issues/"+issueID+"/comments", map[string]string{"text": "first"})
+	resp.Body.Close()
+	after := tokens()
+
+	if after["comments"] == before["comments"] {
+		t.Error("comments token should change after a comment")
+	}
+	for _, c := range []string{"issues", "boards"} {
+		if after[c] != before[c] {
+			t.Errorf("%s token changed after a comment: %v -> %v", c, before[c], after[c])
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package serve
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/marcus/td/internal/query"
+)
+
+// ============================================================================
+// Issue List Filters (params → TDQ)
+// ============================================================================
+
+// issueFilterParam maps an explicit /v1/issues query param onto a TDQ
+// field. Params are translated into the same AST a search=... query
+// parses to, so both forms share one evaluator.
+type issueFilterParam struct {
+	Param string
+	Field string
+	Kind  string // "eq", "label", "bool", "min", "max", "after", "before"
+}
+
+var issueFilterParams = []issueFilterParam{
+	{"id", "id", "eq"},
+	{"status", "status", "eq"},
+	{"type", "type", "eq"},
+	{"priority", "priority", "eq"},
+	{"label", "labels", "label"},
+	{"sprint", "sprint", "eq"},
+	{"implementer", "implementer", "eq"},
+	{"reviewer", "reviewer", "eq"},
+	{"parent", "parent", "eq"},
+	{"epic", "epic", "eq"},
+	{"branch", "branch", "eq"},
+	{"repo", "repo", "eq"},
+	{"blocked_reason", "blocked_reason", "eq"},
+	{"minor", "minor", "bool"},
+	{"inbox", "inbox", "bool"},
+	{"confidential", "confidential", "bool"},
+	{"points_min", "points", "min"},
+	{"points_max", "points", "max"},
+	{"created_after", "created", "after"},
+	{"created_before", "created", "before"},
+	{"updated_after", "updated", "after"},
+	{"updated_before", "updated", "before"},
+	{"closed_after", "closed", "after"},
+	{"closed_before", "closed", "before"},
+}
+
+// buildIssueListQuery translates /v1/issues filter, search and sort params
+// into a single TDQ query. Multiple values for one param (repeated or
+// comma-separated) are ORed, except label, where every label must match.
+// Closed issues are excluded unless include_closed=true or the filters or
+// search constrain status themselves.
+func buildIssueListQuery(q url.Values) (*query.Query, []FieldError) {
+	var errs []FieldError
+	var nodes []query.Node
+
+	for _, fp := range issueFilterParams {
+		values := splitParam(q[fp.Param])
+		if len(values) == 0 {
+			continue
+		}
+		node, err := issueFilterNode(fp, values)
+		if err == nil {
+			if verrs := (&query.Query{Root: node}).Validate(); len(verrs) > 0 {
+				err = verrs[0]
+			}
+		}
+		if err != nil {
+			errs = append(errs, FieldError{
+				Field:   fp.Param,
+				Rule:    "invalid",
+				Value:   strings.Join(values, ","),
+				Message: err.Error(),
+			})
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+
+	search, searchErr := parseIssueSearch(q.Get("search"), q.Get("search_mode"))
+	if searchErr != nil {
+		errs = append(errs, *searchErr)
+	}
+
+	sort, sortErrs := parseIssueSort(q.Get("sort"), q.Get("order"), search.Sort)
+	errs = append(errs, sortErrs...)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	tdq := &query.Query{Root: query.And(append(nodes, search.Root)...), Sort: sort}
+	if q.Get("include_closed") != "true" && !tdq.ReferencesField("status") {
+		tdq.Root = query.And(tdq.Root, &query.FieldExpr{Field: "status", Operator: query.OpNeq, Value: "closed"})
+	}
+	tdq.Raw = tdq.String()
+	return tdq, nil
+}
+
+// issueFilterNode builds the TDQ node for one param's values
+func issueFilterNode(fp issueFilterParam, values []string) (query.Node, error) {
+	switch fp.Kind {
+	case "label":
+		nodes := make([]query.Node, len(values))
+		for i, v := range values {
+			nodes[i] = &query.FunctionCall{Name: "label", Args: []interface{}{v}}
+		}
+		return query.And(nodes...), nil
+	case "eq":
+		nodes := make([]query.Node, len(values))
+		for i, v := range values {
+			var value interface{} = v
+			if v == "@me" {
+				value = &query.SpecialValue{Type: "me"}
+			}
+			nodes[i] = &query.FieldExpr{Field: fp.Field, Operator: query.OpEq, Value: value}
+		}
+		return query.Or(nodes...), nil
+	}
+
+	if len(values) > 1 {
+		return nil, fmt.Errorf("%s takes a single value", fp.Param)
+	}
+	v := values[0]
+	switch fp.Kind {
+	case "bool":
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be true or false", fp.Param)
+		}
+		return &query.FieldExpr{Field: fp.Field, Operator: query.OpEq, Value: strconv.FormatBool(b)}, nil
+	case "min", "max":
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be an integer", fp.Param)
+		}
+		op := query.OpGte
+		if fp.Kind == "max" {
+			op = query.OpLte
+		}
+		return &query.FieldExpr{Field: fp.Field, Operator: op, Value: n}, nil
+	case "after", "before":
+		date, err := query.NewDateValue(v)
+		if err != nil {
+			return nil, err
+		}
+		op := query.OpGte
+		if fp.Kind == "before" {
+			op = query.OpLte
+		}
+		return &query.FieldExpr{Field: fp.Field, Operator: op, Value: date}, nil
+	}
+	return nil, fmt.Errorf("unsupported filter kind %q", fp.Kind)
+}
+
+// parseIssueSearch turns ?search= into a query. In auto mode (the default)
+// text that is not valid TDQ falls back to a plain text search.
+func parseIssueSearch(search, mode string) (*query.Query, *FieldError) {
+	if search == "" {
+		return &query.Query{}, nil
+	}
+	text := &query.Query{Root: &query.TextSearch{Text: search}, Raw: search}
+
+	switch mode {
+	case "text":
+		return text, nil
+	case "", "auto", "tdq":
+		parsed, err := query.Parse(search)
+		if err == nil {
+			if verrs := parsed.Validate(); len(verrs) > 0 {
+				err = verrs[0]
+			}
+		}
+		if err == nil {
+			return parsed, nil
+		}
+		if mode != "tdq" {
+			return text, nil
+		}
+		return &query.Query{}, &FieldError{
+			Field:   "search",
+			Rule:    "tdq",
+			Value:   search,
+			Message: "invalid TDQ query: " + err.Error(),
+		}
+	}
+	return &query.Query{}, &FieldError{
+		Field:    "search_mode",
+		Rule:     "enum",
+		Value:    mode,
+		Expected: []string{"auto", "text", "tdq"},
+		Message:  fmt.Sprintf("unknown search_mode %q", mode),
+	}
+}
+
+// parseIssueSort resolves ?sort= and ?order=. sort takes any TDQ sort
+// field, optionally prefixed with "-" for descending; an explicit sort
+// param overrides a sort: clause in the search. created and updated sort
+// newest first, and score highest first, unless order says otherwise.
+func parseIssueSort(sortBy, order string, clause *query.SortClause) (*query.SortClause, []FieldError) {
+	var errs []FieldError
+	if order != "" && order != "asc" && order != "desc" {
+		errs = append(errs, FieldError{
+			Field:    "order",
+			Rule:     "enum",
+			Value:    order,
+			Expected: []string{"asc", "desc"},
+			Message:  fmt.Sprintf("unknown order %q", order),
+		})
+	}
+
+	sort := clause
+	if sortBy != "" {
+		name, desc := strings.CutPrefix(sortBy, "-")
+		col, ok := query.SortFieldToColumn[name]
+		if !ok {
+			errs = append(errs, FieldError{
+				Field:   "sort",
+				Rule:    "enum",
+				Value:   sortBy,
+				Message: fmt.Sprintf("unknown sort field %q", name),
+			})
+		}
+		sort = &query.SortClause{Field: col, Descending: desc || col == "created_at" || col == "updated_at" || col == "score"}
+	}
+	if sort == nil {
+		sort = &query.SortClause{Field: "priority"}
+	}
+	switch order {
+	case "asc":
+		sort.Descending = false
+	case "desc":
+		sort.Descending = true
+	}
+	return sort, errs
+}
+
+// splitParam flattens repeated, comma-separated param values
+func splitParam(values []string) []string {
+	var out []string
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,129 @@
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestBuildIssueListQuery(t *testing.T) {
+	tests := []struct {
+		params url.Values
+		want   string
+	}{
+		{url.Values{}, "status != closed sort:priority"},
+		{url.Values{"include_closed": {"true"}}, "sort:priority"},
+		{url.Values{"status": {"Open,in-progress"}}, "(status = open OR status = in_progress) sort:priority"},
+		{url.Values{"label": {"api", "ui"}, "include_closed": {"true"}}, "(label(api) AND label(ui)) sort:priority"},
+		{url.Values{"search": {"is(closed)"}}, "is(closed) sort:priority"},
+		{url.Values{"search": {"label(api) sort:-updated"}, "include_closed": {"true"}}, "label(api) sort:-updated_at"},
+		{url.Values{"search": {"sort:title"}, "sort": {"created"}, "include_closed": {"true"}}, "sort:-created_at"},
+		{url.Values{"sort": {"score"}, "include_closed": {"true"}}, "sort:-score"},
+		{url.Values{"points_min": {"3"}, "created_after": {"-7d"}, "include_closed": {"true"}}, "(points >= 3 AND created >= -7d) sort:priority"},
+	}
+	for _, tt := range tests {
+		tdq, errs := buildIssueListQuery(tt.params)
+		if len(errs) > 0 {
+			t.Errorf("%v: errs = %+v", tt.params, errs)
+			continue
+		}
+		if got := tdq.String(); got != tt.want {
+			t.Errorf("%v: query = %q, want %q", tt.params, got, tt.want)
+		}
+	}
+}
+
+func TestBuildIssueListQuery_Invalid(t *testing.T) {
+	for _, params := range []url.Values{
+		{"status": {"bogus"}},
+		{"minor": {"maybe"}},
+		{"points_min": {"lots"}},
+		{"created_after": {"last tuesday"}},
+		{"sort": {"colour"}},
+		{"order": {"sideways"}},
+		{"search_mode": {"regex"}, "search": {"x"}},
+		{"search_mode": {"tdq"}, "search": {"status = = open"}},
+	} {
+		if _, errs := buildIssueListQuery(params); len(errs) == 0 {
+			t.Errorf("%v: expected validation error", params)
+		}
+	}
+}
+
+func TestListIssues_ParamsMatchTDQ(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	for _, issue := range []*models.Issue{
+		{Title: "Labelled api", Labels: []string{"api"}, Sprint: "s1", Priority: models.PriorityP1},
+		{Title: "Labelled apiary", Labels: []string{"apiary"}, Sprint: "s10", Priority: models.PriorityP2},
+		{Title: "Unlabelled", Sprint: "s1", Priority: models.PriorityP0},
+	} {
+		if err := srv.db.CreateIssue(issue); err != nil {
+			t.Fatal(err)
+		}
+		// CreateIssue does not store sprint
+		if err := srv.db.UpdateIssue(issue); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	titles := func(path string) []string {
+		t.Helper()
+		resp, env := doJSON(t, ts, "GET", path, nil)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("%s: status = %d", path, resp.StatusCode)
+		}
+		var out []string
+		for _, item := range env.Data.(map[string]interface{})["issues"].([]interface{}) {
+			out = append(out, item.(map[string]interface{})["title"].(string))
+		}
+		return out
+	}
+	same := func(a, b []string) bool {
+		if len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if a[i] != b[i] {
+				return false
+			}
+		}
+		return true
+	}
+
+	pairs := [][2]string{
+		{"/v1/issues?label=api", "/v1/issues?search=" + url.QueryEscape("label(api)")},
+		{"/v1/issues?sprint=s1", "/v1/issues?search=" + url.QueryEscape("sprint = s1")},
+		{"/v1/issues?created_after=-7d", "/v1/issues?search=" + url.QueryEscape("created >= -7d")},
+		{"/v1/issues?sort=title&order=desc", "/v1/issues?search=" + url.QueryEscape("sort:-title")},
+	}
+	for _, p := range pairs {
+		byParam, byTDQ := titles(p[0]), titles(p[1])
+		if !same(byParam, byTDQ) {
+			t.Errorf("%s = %v, %s = %v", p[0], byParam, p[1], byTDQ)
+		}
+	}
+
+	if got := titles("/v1/issues?label=api"); len(got) != 1 || got[0] != "Labelled api" {
+		t.Errorf("label=api = %v", got)
+	}
+	if got := titles("/v1/issues?created_after=today"); len(got) != 3 {
+		t.Errorf("created_after=today = %v", got)
+	}
+	if got := titles("/v1/issues?created_before=yesterday"); len(got) != 0 {
+		t.Errorf("created_before=yesterday = %v", got)
+	}
+	// score is computed per issue and sorts highest first
+	if got := titles("/v1/issues?sort=score"); len(got) != 3 || got[0] != "Unlabelled" {
+		t.Errorf("sort=score = %v", got)
+	}
+	// sort applies to TDQ searches too
+	if got := titles("/v1/issues?search=" + url.QueryEscape("sprint = s1") + "&sort=title"); len(got) != 2 || got[0] != "Labelled api" {
+		t.Errorf("sorted TDQ search = %v", got)
+	}
+}
@@ -0,0 +1,70 @@
+package serve
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/delivery"
+	"github.com/marcus/td/internal/jobs"
+	"github.com/marcus/td/internal/notify"
+)
+
+// newScheduler builds the scheduler with the server's background jobs.
+// Background features add their job here rather than starting their own
+// goroutine, so every one shows up at /v1/jobs and can be run by hand.
+func (s *Server) newScheduler() *jobs.Scheduler {
+	sched := jobs.New()
+	sched.OnFailure = s.jobFailed
+	for _, job := range []jobs.Job{
+		s.dedupeJob(),
+		s.retentionJob(),
+		s.logCompactionJob(),
+		s.deliveryJob(),
+		s.externalDepsJob(),
+	} {
+		if err := sched.Add(job); err != nil {
+			panic(err) // job names are fixed, so this is a programming error
+		}
+	}
+	return sched
+}
+
+// jobFailed logs a failed run, tells SSE clients, and when the job has just
+// started failing sends a job_failed notification through the project's
+// notify routes. Repeat failures are only logged so a broken job doesn't
+// page someone every interval.
+func (s *Server) jobFailed(st jobs.Status, err error) {
+	slog.Error("job failed", "job", st.Name, "err", err, "consecutive_failures", st.ConsecutiveFailures)
+	if s.sseHub != nil {
+		s.sseHub.jobFailed(st)
+	}
+	if st.ConsecutiveFailures != 1 || s.db == nil {
+		return
+	}
+
+	now := clock.Now()
+	if !notify.Load(s.baseDir).Allows(notify.EventJobFailed, now) {
+		return
+	}
+	routes, rerr := s.db.ListNotifyRoutes()
+	if rerr != nil {
+		slog.Error("job failure alert: load notify routes", "err", rerr)
+		return
+	}
+	dispatcher, derr := notify.NewDispatcher(routes)
+	if derr != nil {
+		slog.Error("job failure alert: notify routes", "err", derr)
+		return
+	}
+	dispatcher.Queue = delivery.NotifyQueue(s.db)
+	defer s.triggerDeliveries()
+	if serr := dispatcher.Send(notify.Message{
+		Event: notify.EventJobFailed,
+		Title: "td job failed: " + st.Name,
+		Body:  fmt.Sprintf("%s: %v", st.Description, err),
+		Time:  now,
+	}); serr != nil {
+		slog.Error("job failure alert", "job", st.Name, "err", serr)
+	}
+}
@@ -0,0 +1,36 @@
+package serve
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/jobs"
+	"github.com/marcus/td/internal/logcompact"
+)
+
+// logCompactionJob compacts long runs of progress logs on every issue each
+// CompactInterval. It only runs once td logs config has set up compaction,
+// and rereads the settings each time.
+func (s *Server) logCompactionJob() jobs.Job {
+	return jobs.Job{
+		Name:        "log-compaction",
+		Description: "Replace long runs of progress logs with digests",
+		Interval:    s.config.CompactInterval,
+		Run: func(context.Context) error {
+			lc, err := config.GetLogCompactionConfig(s.baseDir)
+			if err != nil || lc == nil {
+				return err
+			}
+			results, err := logcompact.CompactAll(s.db, logcompact.OptionsFor(lc, s.baseDir))
+			compacted := 0
+			for _, r := range results {
+				compacted += r.Compacted
+			}
+			if compacted > 0 {
+				slog.Info("log compaction", "issues", len(results), "logs_compacted", compacted)
+			}
+			return err
+		},
+	}
+}
@@ -0,0 +1,50 @@
+package serve
+
+import (
+	"net/http"
+
+	"github.com/marcus/td/internal/models"
+)
+
+// minorSelfOverride returns the override audited when the request's session
+// approves or closes a minor issue it worked on, or nil when the transition
+// bypasses nothing. Call it before the transition changes the issue.
+func (s *Server) minorSelfOverride(r *http.Request, issue *models.Issue, kind models.OverrideKind, reason string) *models.Override {
+	if !issue.Minor {
+		return nil
+	}
+	sessionID := s.requestSession(r)
+	involved := issue.CreatorSession == sessionID || issue.ImplementerSession == sessionID
+	if !involved {
+		var err error
+		if involved, err = s.db.WasSessionInvolved(issue.ID, sessionID); err != nil {
+			involved = true // Conservative, as in the CLI
+		}
+	}
+	if !involved {
+		return nil
+	}
+	return &models.Override{IssueID: issue.ID, Kind: kind, Justification: reason, SessionID: sessionID}
+}
+
+// recordOverride stores an override once its transition is saved. A nil
+// override is ignored.
+func (s *Server) recordOverride(r *http.Request, o *models.Override) {
+	if o == nil {
+		return
+	}
+	if err := s.db.RecordOverride(o); err != nil {
+		requestLog(r).Warn("failed to record override", "err", err, "id", o.IssueID, "kind", o.Kind)
+	}
+}
+
+// closedViaOverride reports which of the issues were closed via an override,
+// for the closed_via_override DTO flag. Lookup errors leave issues unflagged.
+func (s *Server) closedViaOverride(r *http.Request, issues ...models.Issue) map[string]bool {
+	flagged, err := s.db.ClosedViaOverride(issues)
+	if err != nil {
+		requestLog(r).Warn("failed to look up overrides", "err", err)
+		return map[string]bool{}
+	}
+	return flagged
+}
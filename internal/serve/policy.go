@@ -0,0 +1,90 @@
+package serve
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/marcus/td/internal/db"
+)
+
+// closedOverride reports whether a request overrides closed-issue
+// immutability with ?override=closed
+func closedOverride(r *http.Request) bool {
+	return r.URL.Query().Get("override") == "closed"
+}
+
+// writeDB is the database a request writes through: one that may edit
+// closed issues when the request overrides immutability, which
+// CheckClosedEdit has audited
+func (s *Server) writeDB(r *http.Request) *db.DB {
+	if closedOverride(r) {
+		return s.db.WithClosedOverride()
+	}
+	return s.db
+}
+
+// writeRejection writes the response for an update refused for missing
+// required fields, unacknowledged review checklist items or a parent that
+// breaks the hierarchy (400, one field error each), vetoed by a policy hook or made to a closed immutable issue
+// (409), or rejected by the anti-thrash guard. Returns false, writing
+// nothing, for any other error.
+func writeRejection(w http.ResponseWriter, err error) bool {
+	var ce *db.ClosedIssueError
+	if errors.As(err, &ce) {
+		WriteError(w, ErrConflict, ce.Error()+" or retry with ?override=closed", http.StatusConflict)
+		return true
+	}
+	var he *db.HierarchyError
+	if errors.As(err, &he) {
+		WriteValidation(w, []FieldError{{
+			Field:   "parent_id",
+			Rule:    he.Rule,
+			Value:   he.ParentID,
+			Message: he.Error(),
+		}})
+		return true
+	}
+	var rfe *db.RequiredFieldsError
+	if errors.As(err, &rfe) {
+		fields := make([]FieldError, len(rfe.Missing))
+		for i, f := range rfe.Missing {
+			fields[i] = FieldError{
+				Field:   f,
+				Rule:    "required",
+				Message: fmt.Sprintf("%s is required to move a %s to %s", f, rfe.Type, rfe.To),
+			}
+		}
+		WriteValidation(w, fields)
+		return true
+	}
+	var cle *db.ChecklistError
+	if errors.As(err, &cle) {
+		if cle.Err != nil {
+			WriteValidation(w, []FieldError{{
+				Field:    "checklist",
+				Rule:     "checklist",
+				Expected: cle.Items,
+				Message:  cle.Err.Error(),
+			}})
+			return true
+		}
+		fields := make([]FieldError, len(cle.Missing))
+		for i, item := range cle.Missing {
+			fields[i] = FieldError{
+				Field:   "checklist",
+				Rule:    "required",
+				Value:   item,
+				Message: fmt.Sprintf("review checklist item %q must be acknowledged to approve a %s", item, cle.Type),
+			}
+		}
+		WriteValidation(w, fields)
+		return true
+	}
+	var pe *db.PolicyError
+	if errors.As(err, &pe) {
+		WriteError(w, ErrConflict, pe.Error(), http.StatusConflict)
+		return true
+	}
+	return writeThrashError(w, err)
+}
@@ -0,0 +1,183 @@
+package serve
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/workflow"
+)
+
+func TestPolicyHookVetoesTransition(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	if err := config.SetPolicyHook(srv.baseDir, models.PolicyHookConfig{
+		Name: "handoff-before-review", Builtin: workflow.BuiltinRequireHandoff, To: []string{"in_review"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	issue := &models.Issue{Title: "Needs a handoff"}
+	if err := srv.db.CreateIssue(issue); err != nil {
+		t.Fatal(err)
+	}
+
+	if resp, env := doJSON(t, ts, "POST", "/v1/issues/"+issue.ID+"/start", nil); resp.StatusCode != http.StatusOK {
+		t.Fatalf("start status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	resp, env := doJSON(t, ts, "POST", "/v1/issues/"+issue.ID+"/review", nil)
+	if resp.StatusCode != http.StatusConflict {
+		t.Fatalf("review status = %d, want 409", resp.StatusCode)
+	}
+	if env.Error == nil || !strings.Contains(env.Error.Message, "handoff-before-review") {
+		t.Errorf("error = %+v, want the vetoing hook named", env.Error)
+	}
+
+	if err := srv.db.AddHandoff(&models.Handoff{IssueID: issue.ID, SessionID: srv.sessionID, Done: []string{"wired up"}}); err != nil {
+		t.Fatal(err)
+	}
+	if resp, env := doJSON(t, ts, "POST", "/v1/issues/"+issue.ID+"/review", nil); resp.StatusCode != http.StatusOK {
+		t.Fatalf("review after handoff status = %d: %+v", resp.StatusCode, env.Error)
+	}
+}
+
+func TestRequiredFieldsRejectTransition(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	if err := config.SetRequiredFields(srv.baseDir, models.RequiredFieldsRule{
+		Type: "bug", To: "in_progress", Fields: []string{workflow.FieldDescription, workflow.FieldLabels},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	issue := &models.Issue{Title: "Crash on save", Type: models.TypeBug, Labels: []string{"crash"}}
+	if err := srv.db.CreateIssue(issue); err != nil {
+		t.Fatal(err)
+	}
+
+	path := "/v1/issues/" + issue.ID
+	resp, env := doJSON(t, ts, "POST", path+"/start", nil)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("start status = %d, want 400", resp.StatusCode)
+	}
+	if env.Error == nil || env.Error.Code != ErrValidation {
+		t.Fatalf("error = %+v, want validation error", env.Error)
+	}
+	detailsJSON, _ := json.Marshal(env.Error.Details)
+	var details ValidationDetails
+	if err := json.Unmarshal(detailsJSON, &details); err != nil {
+		t.Fatal(err)
+	}
+	if len(details.Fields) != 1 || details.Fields[0].Field != "description" || details.Fields[0].Rule != "required" {
+		t.Errorf("fields = %+v, want one missing description", details.Fields)
+	}
+
+	if resp, env := doJSON(t, ts, "PATCH", path, map[string]interface{}{"description": "1. open\n2. save"}); resp.StatusCode != http.StatusOK {
+		t.Fatalf("set description status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	if resp, env := doJSON(t, ts, "POST", path+"/start", nil); resp.StatusCode != http.StatusOK {
+		t.Fatalf("start after description status = %d: %+v", resp.StatusCode, env.Error)
+	}
+}
+
+func TestClosedImmutableRejectsEdits(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	if err := config.SetClosedImmutable(srv.baseDir, true); err != nil {
+		t.Fatal(err)
+	}
+	issue := &models.Issue{Title: "Shipped last quarter", Status: models.StatusClosed}
+	if err := srv.db.CreateIssue(issue); err != nil {
+		t.Fatal(err)
+	}
+	points := 8
+
+	resp, env := doJSON(t, ts, "PATCH", "/v1/issues/"+issue.ID, IssueUpdateBody{Points: &points})
+	if resp.StatusCode != http.StatusConflict || env.Error == nil || env.Error.Code != ErrConflict {
+		t.Fatalf("patch status = %d, error = %+v; want 409", resp.StatusCode, env.Error)
+	}
+	resp, _ = doJSON(t, ts, "POST", "/v1/issues/"+issue.ID+"/comments", CommentCreateBody{Text: "late note"})
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("comment status = %d, want 409", resp.StatusCode)
+	}
+
+	// The override goes through and lands in the security log
+	resp, env = doJSON(t, ts, "PATCH", "/v1/issues/"+issue.ID+"?override=closed", IssueUpdateBody{Points: &points})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("override status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	if got, _ := srv.db.GetIssue(issue.ID); got.Points != points {
+		t.Errorf("points = %d, want %d", got.Points, points)
+	}
+	events, _ := db.ReadSecurityEvents(srv.baseDir)
+	if len(events) != 1 || events[0].IssueID != issue.ID {
+		t.Errorf("security events = %+v, want the override", events)
+	}
+
+	// Reopening stays allowed, after which edits need no override
+	if resp, env := doJSON(t, ts, "POST", "/v1/issues/"+issue.ID+"/reopen", nil); resp.StatusCode != http.StatusOK {
+		t.Fatalf("reopen status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	if resp, _ := doJSON(t, ts, "POST", "/v1/issues/"+issue.ID+"/comments", CommentCreateBody{Text: "reopened"}); resp.StatusCode != http.StatusCreated {
+		t.Errorf("comment after reopen status = %d, want 201", resp.StatusCode)
+	}
+}
+
+func TestReviewChecklistGatesApproval(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	if err := config.SetReviewChecklist(srv.baseDir, models.ReviewChecklist{
+		Type: "bug", Items: []string{"tests added", "security considered"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	issue := &models.Issue{Title: "Token leaks in logs", Type: models.TypeBug, Status: models.StatusInReview, ImplementerSession: "ses_other"}
+	if err := srv.db.CreateIssue(issue); err != nil {
+		t.Fatal(err)
+	}
+	path := "/v1/issues/" + issue.ID + "/approve"
+
+	tests := []struct {
+		name      string
+		checklist []string
+		wantRule  string
+		wantN     int
+	}{
+		{"no acknowledgment", nil, "required", 2},
+		{"partial", []string{"Tests added"}, "required", 1},
+		{"not on the list", []string{"docs updated"}, "checklist", 1},
+	}
+	for _, tt := range tests {
+		resp, env := doJSON(t, ts, "POST", path, map[string]interface{}{"checklist": tt.checklist})
+		if resp.StatusCode != http.StatusBadRequest || env.Error == nil {
+			t.Fatalf("%s: status = %d, want 400", tt.name, resp.StatusCode)
+		}
+		detailsJSON, _ := json.Marshal(env.Error.Details)
+		var details ValidationDetails
+		if err := json.Unmarshal(detailsJSON, &details); err != nil {
+			t.Fatal(err)
+		}
+		if len(details.Fields) != tt.wantN || details.Fields[0].Field != "checklist" || details.Fields[0].Rule != tt.wantRule {
+			t.Errorf("%s: fields = %+v", tt.name, details.Fields)
+		}
+	}
+
+	if resp, env := doJSON(t, ts, "POST", path, map[string]interface{}{"checklist": []string{"1", "security considered"}}); resp.StatusCode != http.StatusOK {
+		t.Fatalf("approve status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	acks, err := srv.db.ListReviewAcks(issue.ID)
+	if err != nil || len(acks) != 1 || acks[0].SessionID != srv.sessionID || len(acks[0].Items) != 2 {
+		t.Errorf("review acks = %+v, %v; want one with both items", acks, err)
+	}
+}
@@ -0,0 +1,105 @@
+package serve
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ProblemContentType is the RFC 9457 media type. A request that accepts it
+// gets errors as problem details instead of the {"ok": false} envelope;
+// successful responses are unchanged.
+const ProblemContentType = "application/problem+json"
+
+// problemTypePrefix forms each problem's type URI from the error code, so
+// "not_found" becomes "urn:td:problem:not_found"
+const problemTypePrefix = "urn:td:problem:"
+
+// Problem is an error as RFC 9457 problem details. Code, RequestID and
+// Details are extension members carrying what the envelope would have.
+type Problem struct {
+	Type      string      `json:"type"`
+	Title     string      `json:"title"`
+	Status    int         `json:"status"`
+	Detail    string      `json:"detail,omitempty"`
+	Instance  string      `json:"instance,omitempty"`
+	Code      string      `json:"code"`
+	RequestID string      `json:"request_id,omitempty"`
+	Details   interface{} `json:"details,omitempty"`
+}
+
+// problemWriter marks a response whose errors are written as problem
+// details. Error writers find it by unwrapping the response writer chain.
+type problemWriter struct {
+	http.ResponseWriter
+	instance string // the request path
+}
+
+// Unwrap exposes the underlying writer to ResponseController.
+func (pw *problemWriter) Unwrap() http.ResponseWriter {
+	return pw.ResponseWriter
+}
+
+// Flush forwards streaming flushes (required for SSE).
+func (pw *problemWriter) Flush() {
+	if f, ok := pw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// problem converts an error payload to problem details
+func (pw *problemWriter) problem(status int, payload ErrorPayload, requestID string) Problem {
+	return Problem{
+		Type:      problemTypePrefix + payload.Code,
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    payload.Message,
+		Instance:  pw.instance,
+		Code:      payload.Code,
+		RequestID: requestID,
+		Details:   payload.Details,
+	}
+}
+
+// problemWriterOf finds the problemWriter in a chain of wrapped writers
+func problemWriterOf(w http.ResponseWriter) *problemWriter {
+	for w != nil {
+		if pw, ok := w.(*problemWriter); ok {
+			return pw
+		}
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return nil
+		}
+		w = u.Unwrap()
+	}
+	return nil
+}
+
+// acceptsProblem reports whether an Accept header lists the problem+json
+// media type with a non-zero quality
+func acceptsProblem(accept string) bool {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || mediaType != ProblemContentType {
+			continue
+		}
+		if q, err := strconv.ParseFloat(params["q"], 64); err == nil && q == 0 {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// problemMiddleware switches error responses to problem details for
+// requests that accept application/problem+json.
+func (s *Server) problemMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if acceptsProblem(r.Header.Get("Accept")) {
+			w = &problemWriter{ResponseWriter: w, instance: r.URL.Path}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,100 @@
+package serve
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/marcus/td/internal/db"
+)
+
+func TestAcceptsProblem(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   bool
+	}{
+		{"", false},
+		{"application/json", false},
+		{"application/problem+json", true},
+		{"application/json, application/problem+json;q=0.9", true},
+		{"application/problem+json;q=0", false},
+		{"*/*", false},
+	}
+	for _, tt := range tests {
+		if got := acceptsProblem(tt.accept); got != tt.want {
+			t.Errorf("acceptsProblem(%q) = %v, want %v", tt.accept, got, tt.want)
+		}
+	}
+}
+
+// getProblem sends a request accepting problem+json and decodes the body
+func getProblem(t *testing.T, ts *httptest.Server, method, path, body string) (*http.Response, Problem) {
+	t.Helper()
+	req, _ := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+	req.Header.Set("Accept", "application/json, "+ProblemContentType)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+	var p Problem
+	if err := json.NewDecoder(resp.Body).Decode(&p); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	return resp, p
+}
+
+func TestProblemDetails(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	resp, p := getProblem(t, ts, "GET", "/v1/issues/td-missing", "")
+	if ct := resp.Header.Get("Content-Type"); ct != ProblemContentType {
+		t.Errorf("Content-Type = %q, want %q", ct, ProblemContentType)
+	}
+	want := Problem{
+		Type:      "urn:td:problem:not_found",
+		Title:     "Not Found",
+		Status:    http.StatusNotFound,
+		Instance:  "/v1/issues/td-missing",
+		Code:      ErrNotFound,
+		RequestID: resp.Header.Get(RequestIDHeader),
+	}
+	if p.Detail == "" || p.Type != want.Type || p.Title != want.Title || p.Status != want.Status ||
+		p.Instance != want.Instance || p.Code != want.Code || p.RequestID != want.RequestID {
+		t.Errorf("problem = %+v, want %+v with a detail", p, want)
+	}
+
+	// Validation details carry over as an extension member
+	resp, p = getProblem(t, ts, "POST", "/v1/issues", `{}`)
+	details, _ := p.Details.(map[string]interface{})
+	if resp.StatusCode != http.StatusBadRequest || p.Code != ErrValidation || details["fields"] == nil {
+		t.Errorf("validation problem = %d %+v", resp.StatusCode, p)
+	}
+
+	// Successful responses keep the envelope
+	resp, env := doJSON(t, ts, "GET", "/health", nil)
+	if !env.OK || resp.Header.Get("Content-Type") == ProblemContentType {
+		t.Errorf("health = %+v, %s", env, resp.Header.Get("Content-Type"))
+	}
+}
+
+func TestProblemDetails_Unauthorized(t *testing.T) {
+	dir := t.TempDir()
+	database, err := db.Initialize(dir)
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+	srv := NewServer(database, dir, "ses_test123", ServeConfig{Token: "secret"})
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	resp, p := getProblem(t, ts, "GET", "/v1/issues", "")
+	if resp.StatusCode != http.StatusUnauthorized || p.Code != ErrUnauthorized || p.Status != http.StatusUnauthorized {
+		t.Errorf("problem = %d %+v, want 401 unauthorized", resp.StatusCode, p)
+	}
+}
@@ -0,0 +1,261 @@
+package serve
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/db"
+)
+
+// maxRecordedBody caps each request and response body kept in a recording.
+// A request cut short can't be replayed and is skipped by td replay.
+const maxRecordedBody = 1 << 20
+
+// redactedQueryParams are query parameters that carry credentials and are
+// left out of recorded paths
+var redactedQueryParams = []string{"token", "share_token"}
+
+// RecordedExchange is one API request and its response in a recording made
+// with td serve --record. Recordings are JSON lines, one exchange per line,
+// in the order the handlers finished.
+type RecordedExchange struct {
+	Seq          int             `json:"seq"`
+	Time         time.Time       `json:"time"`    // clock time the request arrived
+	Session      string          `json:"session"` // session the request acted as
+	Method       string          `json:"method"`
+	Path         string          `json:"path"` // with the query, less credentials
+	ContentType  string          `json:"content_type,omitempty"`
+	Body         string          `json:"body,omitempty"`
+	Truncated    bool            `json:"truncated,omitempty"` // the request body was longer than the cap
+	Status       int             `json:"status"`
+	Response     json.RawMessage `json:"response,omitempty"`     // JSON responses only
+	Stream       bool            `json:"stream,omitempty"`       // an event stream, which replay skips
+	Confidential bool            `json:"confidential,omitempty"` // bodies left out; replay skips it
+	Panic        string          `json:"panic,omitempty"`
+}
+
+// Recorder appends exchanges to a recording. It is safe for concurrent use.
+type Recorder struct {
+	mu  sync.Mutex
+	w   io.Writer
+	seq int
+}
+
+// NewRecorder returns a recorder writing JSON lines to w.
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{w: w}
+}
+
+func (rec *Recorder) write(x *RecordedExchange) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.seq++
+	x.Seq = rec.seq
+	line, err := json.Marshal(x)
+	if err != nil {
+		slog.Error("record request", "err", err)
+		return
+	}
+	if _, err := rec.w.Write(append(line, '\n')); err != nil {
+		slog.Error("record request", "err", err)
+	}
+}
+
+// ReadRecording parses a recording, skipping blank lines.
+func ReadRecording(r io.Reader) ([]RecordedExchange, error) {
+	dec := json.NewDecoder(r)
+	var out []RecordedExchange
+	for {
+		var x RecordedExchange
+		err := dec.Decode(&x)
+		if err == io.EOF {
+			return out, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("exchange %d: %w", len(out)+1, err)
+		}
+		out = append(out, x)
+	}
+}
+
+// captureWriter keeps a copy of the status and the start of the body
+type captureWriter struct {
+	http.ResponseWriter
+	code   int
+	body   bytes.Buffer
+	stream bool
+}
+
+func (cw *captureWriter) WriteHeader(code int) {
+	cw.code = code
+	cw.stream = strings.HasPrefix(cw.Header().Get("Content-Type"), "text/event-stream")
+	cw.ResponseWriter.WriteHeader(code)
+}
+
+func (cw *captureWriter) Write(p []byte) (int, error) {
+	if cw.code == 0 {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if !cw.stream && cw.body.Len() < maxRecordedBody {
+		cw.body.Write(p[:min(len(p), maxRecordedBody-cw.body.Len())])
+	}
+	return cw.ResponseWriter.Write(p)
+}
+
+// Unwrap exposes the underlying writer for http.ResponseController.
+func (cw *captureWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// Flush forwards streaming flushes (required for SSE).
+func (cw *captureWriter) Flush() {
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// recordedURI is the request URI without credentials in the query
+func recordedURI(u *url.URL) string {
+	q := u.Query()
+	redacted := false
+	for _, k := range redactedQueryParams {
+		if q.Has(k) {
+			q.Del(k)
+			redacted = true
+		}
+	}
+	if !redacted {
+		return u.RequestURI()
+	}
+	uri := u.EscapedPath()
+	if enc := q.Encode(); enc != "" {
+		uri += "?" + enc
+	}
+	return uri
+}
+
+// pathIssueConfidential reports whether the request path names a
+// confidential issue (/v1/issues/{id}/...)
+func (s *Server) pathIssueConfidential(r *http.Request) bool {
+	rest, ok := strings.CutPrefix(r.URL.Path, "/v1/issues/")
+	if !ok {
+		return false
+	}
+	id, _, _ := strings.Cut(rest, "/")
+	if id == "" {
+		return false
+	}
+	issue, err := s.db.GetIssue(db.NormalizeIssueID(id))
+	return err == nil && issue.Confidential
+}
+
+// mentionsConfidential reports whether a JSON value holds an object marked
+// "confidential": true, such as a confidential issue
+func mentionsConfidential(v any) bool {
+	switch v := v.(type) {
+	case map[string]any:
+		if c, _ := v["confidential"].(bool); c {
+			return true
+		}
+		for _, e := range v {
+			if mentionsConfidential(e) {
+				return true
+			}
+		}
+	case []any:
+		for _, e := range v {
+			if mentionsConfidential(e) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// recordMiddleware writes every request that gets past auth, with its
+// response, to the configured recording. It sits inside auth so the session
+// a request acts as is known; requests auth rejects aren't recorded.
+// Credentials in the query are left out, and so are both bodies of any
+// exchange that touches a confidential issue, since they may hold its
+// decrypted text.
+func (s *Server) recordMiddleware(next http.Handler) http.Handler {
+	if s.recorder == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		x := &RecordedExchange{
+			Time:        clock.Now().UTC(),
+			Session:     s.requestSession(r),
+			Method:      r.Method,
+			Path:        recordedURI(r.URL),
+			ContentType: r.Header.Get("Content-Type"),
+		}
+		confidentialBefore := s.pathIssueConfidential(r)
+		if r.Body != nil {
+			body, err := io.ReadAll(io.LimitReader(r.Body, maxRecordedBody+1))
+			if err != nil {
+				WriteError(w, ErrValidation, "failed to read request body: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			x.Truncated = len(body) > maxRecordedBody
+			x.Body = string(body[:min(len(body), maxRecordedBody)])
+			r.Body = readCloser{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+		}
+
+		cw := &captureWriter{ResponseWriter: w}
+		defer func() {
+			if p := recover(); p != nil {
+				x.Status = http.StatusInternalServerError
+				x.Panic = fmt.Sprint(p)
+				s.writeRecord(r, x, confidentialBefore)
+				panic(p)
+			}
+			x.Status = cw.code
+			if x.Status == 0 {
+				x.Status = http.StatusOK
+			}
+			x.Stream = cw.stream
+			if body := bytes.TrimSpace(cw.body.Bytes()); json.Valid(body) {
+				x.Response = json.RawMessage(body)
+			}
+			s.writeRecord(r, x, confidentialBefore)
+		}()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// writeRecord writes x to the recording, without its bodies when the
+// request touched a confidential issue
+func (s *Server) writeRecord(r *http.Request, x *RecordedExchange, confidentialBefore bool) {
+	if confidentialBefore || s.pathIssueConfidential(r) || x.mentionsConfidential() {
+		x.Body, x.Response, x.Confidential = "", nil, true
+	}
+	s.recorder.write(x)
+}
+
+// mentionsConfidential reports whether the request or response body refers
+// to a confidential issue
+func (x *RecordedExchange) mentionsConfidential() bool {
+	for _, data := range [][]byte{[]byte(x.Body), x.Response} {
+		var v any
+		if json.Unmarshal(data, &v) == nil && mentionsConfidential(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// readCloser reads from one reader and closes another
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
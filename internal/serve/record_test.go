@@ -0,0 +1,175 @@
+package serve
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/marcus/td/internal/confidential"
+	"github.com/marcus/td/internal/db"
+)
+
+// record runs a short agent session against a recording server
+func record(t *testing.T) []RecordedExchange {
+	t.Helper()
+	var buf bytes.Buffer
+	database, err := db.Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+	srv := NewServer(database, t.TempDir(), "ses_rec", ServeConfig{Token: "secret", Record: &buf})
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	env := doJSONAuth(t, ts, http.MethodPost, "/v1/issues", `{"title":"Recorded by an agent"}`)
+	id := env["data"].(map[string]any)["issue"].(map[string]any)["id"].(string)
+	doJSONAuth(t, ts, http.MethodPatch, "/v1/issues/"+id, `{"priority":"P1"}`)
+	doJSONAuth(t, ts, http.MethodPost, "/v1/issues/"+id+"/comments", `{"text":"looks good"}`)
+	doJSONAuth(t, ts, http.MethodGet, "/v1/issues/td-missing", "")
+
+	// Rejected by auth, so not recorded
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/v1/issues", nil)
+	if resp, err := http.DefaultClient.Do(req); err == nil {
+		resp.Body.Close()
+	}
+
+	if strings.Contains(buf.String(), "secret") {
+		t.Error("recording contains the bearer token")
+	}
+	records, err := ReadRecording(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return records
+}
+
+// doJSONAuth sends a raw JSON body with the test token and decodes the reply
+func doJSONAuth(t *testing.T, ts *httptest.Server, method, path, body string) map[string]any {
+	t.Helper()
+	req, _ := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var env map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil {
+		t.Fatal(err)
+	}
+	return env
+}
+
+func TestRecordMiddleware(t *testing.T) {
+	records := record(t)
+	if len(records) != 4 {
+		t.Fatalf("recorded %d exchanges, want 4", len(records))
+	}
+	first := records[0]
+	if first.Seq != 1 || first.Session != "ses_rec" || first.Method != http.MethodPost || first.Status != http.StatusCreated {
+		t.Errorf("first = %+v", first)
+	}
+	if first.Body != `{"title":"Recorded by an agent"}` || len(first.Response) == 0 || first.Time.IsZero() {
+		t.Errorf("first body %q, response %s", first.Body, first.Response)
+	}
+	if last := records[3]; last.Path != "/v1/issues/td-missing" || last.Status != http.StatusNotFound {
+		t.Errorf("last = %s %d", last.Path, last.Status)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	records := record(t)
+
+	replay := func() (*db.DB, *ReplayResult) {
+		dir := t.TempDir()
+		database, err := db.Initialize(dir)
+		if err != nil {
+			t.Fatalf("init db: %v", err)
+		}
+		t.Cleanup(func() { database.Close() })
+		res, err := Replay(database, dir, records, ReplayOptions{Seed: 3})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return database, res
+	}
+
+	database, res := replay()
+	if res.Replayed != 4 || res.Matched != 4 || len(res.Mismatches) != 0 {
+		t.Fatalf("result = %+v", res)
+	}
+	recordedID := strings.TrimPrefix(records[1].Path, "/v1/issues/")
+	id := res.IDs[recordedID]
+	if id == "" {
+		t.Fatalf("recorded issue %s not mapped: %v", recordedID, res.IDs)
+	}
+	issue, err := database.GetIssue(id)
+	if err != nil || issue.Priority != "P1" || issue.CreatorSession != "ses_rec" {
+		t.Fatalf("replayed issue = %+v, %v", issue, err)
+	}
+	if comments, _ := database.GetComments(id); len(comments) != 1 {
+		t.Errorf("comments = %d, want 1", len(comments))
+	}
+
+	// The same seed creates the same records
+	if _, again := replay(); again.IDs[recordedID] != id {
+		t.Errorf("second replay created %s, first %s", again.IDs[recordedID], id)
+	}
+
+	// A status that no longer matches is reported
+	records[3].Status = http.StatusOK
+	if _, res := replay(); len(res.Mismatches) != 1 || res.Mismatches[0].Got != http.StatusNotFound {
+		t.Errorf("mismatches = %+v", res.Mismatches)
+	}
+}
+
+func TestRecordLeavesOutSecrets(t *testing.T) {
+	t.Setenv(confidential.KeyEnv, "")
+	var buf bytes.Buffer
+	dir := t.TempDir()
+	database, err := db.Initialize(dir)
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+	srv := NewServer(database, dir, "ses_rec", ServeConfig{Token: "secret", Record: &buf})
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	if resp, err := http.Get(ts.URL + calendarPath + "?events=due&token=secret"); err == nil {
+		resp.Body.Close()
+	}
+	env := doJSONAuth(t, ts, http.MethodPost, "/v1/issues", `{"title":"Set up the payroll export","description":"salary sheet in s3"}`)
+	id := env["data"].(map[string]any)["issue"].(map[string]any)["id"].(string)
+	doJSONAuth(t, ts, http.MethodPost, "/v1/issues/"+id+"/confidential", `{"confidential":true}`)
+	doJSONAuth(t, ts, http.MethodGet, "/v1/issues/"+id, "")
+	doJSONAuth(t, ts, http.MethodPost, "/v1/issues/"+id+"/comments", `{"text":"bucket is payroll-2026"}`)
+
+	if strings.Contains(buf.String(), "secret") {
+		t.Errorf("recording contains the calendar token")
+	}
+	records, err := ReadRecording(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 5 || records[0].Path != calendarPath+"?events=due" {
+		t.Fatalf("records = %+v", records)
+	}
+	// Created before it was confidential, so the description was public
+	if records[1].Confidential {
+		t.Error("public create recorded as confidential")
+	}
+	for _, x := range records[2:] {
+		if !x.Confidential || x.Body != "" || len(x.Response) != 0 {
+			t.Errorf("%s %s kept its bodies: %q %s", x.Method, x.Path, x.Body, x.Response)
+		}
+	}
+	if strings.Contains(buf.String(), "payroll-2026") {
+		t.Error("recording contains confidential comment text")
+	}
+}
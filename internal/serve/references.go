@@ -0,0 +1,81 @@
+package serve
+
+import (
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/xref"
+)
+
+// ReferenceDTO is an issue in a linked project that an issue depends on or
+// mentions in its description or acceptance criteria.
+type ReferenceDTO struct {
+	ID       string `json:"id"` // project-qualified, e.g. api/td-a1b2c3
+	Project  string `json:"project"`
+	IssueID  string `json:"issue_id"`
+	Source   string `json:"source"` // "dependency" or "description"
+	Resolved bool   `json:"resolved"`
+	Title    string `json:"title,omitempty"`
+	Status   string `json:"status,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// newResolver returns a resolver for the projects linked in the server's
+// config. A config that can't be read links nothing.
+func (s *Server) newResolver() *xref.Resolver {
+	resolver, err := xref.NewResolver(s.baseDir)
+	if err != nil {
+		return xref.NewResolverFor(nil)
+	}
+	return resolver
+}
+
+// issueReferences resolves the cross-project references of issue: its
+// dependencies on other projects first, then description mentions of
+// linked projects that aren't already dependencies.
+func (s *Server) issueReferences(issue *models.Issue) []ReferenceDTO {
+	resolver := s.newResolver()
+	defer resolver.Close()
+
+	var refs []xref.Ref
+	sources := map[xref.Ref]string{}
+	deps, _ := s.db.GetDependencies(issue.ID)
+	for _, d := range deps {
+		if ref, ok := xref.Parse(d); ok {
+			refs = append(refs, ref)
+			sources[ref] = "dependency"
+		}
+	}
+mentions:
+	for _, ref := range xref.FindAll(issue.Description + "\n" + issue.Acceptance) {
+		if !resolver.Has(ref.Project) {
+			continue
+		}
+		for _, known := range refs {
+			if known.Matches(ref) {
+				continue mentions
+			}
+		}
+		refs = append(refs, ref)
+		sources[ref] = "description"
+	}
+
+	dtos := make([]ReferenceDTO, 0, len(refs))
+	for _, ref := range refs {
+		dto := ReferenceDTO{
+			ID:      ref.String(),
+			Project: ref.Project,
+			IssueID: ref.IssueID,
+			Source:  sources[ref],
+		}
+		if target, err := resolver.Resolve(ref); err != nil {
+			dto.Error = err.Error()
+		} else {
+			dto.Resolved = true
+			dto.IssueID = target.ID
+			dto.ID = xref.Ref{Project: ref.Project, IssueID: target.ID}.String()
+			dto.Title = target.Title
+			dto.Status = string(target.Status)
+		}
+		dtos = append(dtos, dto)
+	}
+	return dtos
+}
@@ -0,0 +1,78 @@
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+func TestCrossProjectReferences(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	otherDir := t.TempDir()
+	other, err := db.Initialize(otherDir)
+	if err != nil {
+		t.Fatalf("init other db: %v", err)
+	}
+	endpoint := &models.Issue{Title: "Expose export endpoint", Status: models.StatusInProgress}
+	docs := &models.Issue{Title: "Document export format", Status: models.StatusOpen}
+	other.CreateIssue(endpoint)
+	other.CreateIssue(docs)
+	other.Close()
+	if err := config.SetLinkedProject(srv.baseDir, "api", otherDir); err != nil {
+		t.Fatalf("SetLinkedProject: %v", err)
+	}
+
+	local := &models.Issue{
+		Title:       "Export button in settings",
+		Description: "Waits on api/" + endpoint.ID + "; format in api/" + docs.ID + ", see web/td-abcdef.",
+	}
+	if err := srv.db.CreateIssueLogged(local, "ses_test123"); err != nil {
+		t.Fatalf("create local issue: %v", err)
+	}
+	id := local.ID
+
+	short := strings.TrimPrefix(endpoint.ID, "td-")
+	resp, env := doJSON(t, ts, "POST", "/v1/issues/"+id+"/dependencies", map[string]string{"depends_on": "api/" + short})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("add dependency status = %d, %+v", resp.StatusCode, env.Error)
+	}
+	dep := env.Data.(map[string]interface{})["dependency"].(map[string]interface{})
+	if dep["depends_on_id"] != "api/"+endpoint.ID || dep["project"] != "api" {
+		t.Errorf("dependency = %v", dep)
+	}
+
+	resp, _ = doJSON(t, ts, "POST", "/v1/issues/"+id+"/dependencies", map[string]string{"depends_on": "api/" + endpoint.ID})
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("duplicate status = %d, want 409", resp.StatusCode)
+	}
+	resp, _ = doJSON(t, ts, "POST", "/v1/issues/"+id+"/dependencies", map[string]string{"depends_on": "web/td-abcdef"})
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("unknown project status = %d, want 400", resp.StatusCode)
+	}
+	resp, _ = doJSON(t, ts, "POST", "/v1/issues/"+id+"/dependencies", map[string]string{"depends_on": "api/td-000000"})
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("missing issue status = %d, want 404", resp.StatusCode)
+	}
+
+	_, env = doJSON(t, ts, "GET", "/v1/issues/"+id+"?include=references", nil)
+	refs := env.Data.(map[string]interface{})["references"].([]interface{})
+	if len(refs) != 2 {
+		t.Fatalf("references = %v, want 2", refs)
+	}
+	first := refs[0].(map[string]interface{})
+	if first["id"] != "api/"+endpoint.ID || first["source"] != "dependency" || first["status"] != "in_progress" || first["resolved"] != true {
+		t.Errorf("first reference = %v", first)
+	}
+	second := refs[1].(map[string]interface{})
+	if second["id"] != "api/"+docs.ID || second["source"] != "description" || second["title"] != docs.Title {
+		t.Errorf("second reference = %v", second)
+	}
+}
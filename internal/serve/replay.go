@@ -0,0 +1,177 @@
+package serve
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/db"
+)
+
+// ReplayOptions tune a replay
+type ReplayOptions struct {
+	// Seed seeds the IDs the replayed requests generate, so the same
+	// recording and seed always produce the same database
+	Seed uint64
+}
+
+// ReplayMismatch is a replayed request whose status differs from the
+// recorded one
+type ReplayMismatch struct {
+	Seq      int    `json:"seq"`
+	Method   string `json:"method"`
+	Path     string `json:"path"` // as replayed, with recorded IDs mapped
+	Want     int    `json:"want_status"`
+	Got      int    `json:"got_status"`
+	Response string `json:"response,omitempty"` // the replayed response body
+}
+
+// ReplayResult summarises a replay
+type ReplayResult struct {
+	Requests   int               `json:"requests"`
+	Replayed   int               `json:"replayed"`
+	Matched    int               `json:"matched"`
+	Skipped    int               `json:"skipped"` // event streams, truncated and confidential bodies
+	Mismatches []ReplayMismatch  `json:"mismatches"`
+	IDs        map[string]string `json:"ids"` // recorded ID to replayed ID
+}
+
+// replayIDPattern matches the generated IDs (td-a1b2c3, dep_...) that
+// replay maps from the recording onto the fresh database
+var replayIDPattern = regexp.MustCompile(`^[a-z]{2,4}[-_][0-9a-f]{4,}$`)
+
+// Replay re-executes a recording against database, which should be freshly
+// initialized, in recorded order. The clock is pinned to each request's
+// recorded time and IDs come from a seeded source. IDs the original server
+// returned are mapped to the ones the replay generates, so later requests
+// that refer to them hit the same records. Background jobs a request
+// triggers finish before the next request starts.
+func Replay(database *db.DB, baseDir string, records []RecordedExchange, opts ReplayOptions) (*ReplayResult, error) {
+	res := &ReplayResult{Requests: len(records), Mismatches: []ReplayMismatch{}, IDs: map[string]string{}}
+	if len(records) == 0 {
+		return res, nil
+	}
+
+	fake := clock.NewFake(records[0].Time)
+	defer clock.Set(fake.Now)()
+	var seed [32]byte
+	binary.LittleEndian.PutUint64(seed[:], opts.Seed)
+	defer db.SetIDSource(rand.NewChaCha8(seed))()
+
+	// One server per session, each acting as that session like the
+	// original server's web session or a token bound to it
+	servers := map[string]*Server{}
+	handlers := map[string]http.Handler{}
+	defer func() {
+		for _, s := range servers {
+			s.jobs.Wait()
+		}
+	}()
+
+	for _, x := range records {
+		if x.Stream || x.Truncated || x.Confidential {
+			res.Skipped++
+			continue
+		}
+		if !x.Time.IsZero() {
+			fake.SetTime(x.Time)
+		}
+
+		s, ok := servers[x.Session]
+		if !ok {
+			if err := database.UpsertSession(&db.SessionRow{
+				ID:           x.Session,
+				Name:         "td-replay",
+				AgentType:    "replay",
+				StartedAt:    fake.Now(),
+				LastActivity: fake.Now(),
+			}); err != nil {
+				return res, fmt.Errorf("create session %s: %w", x.Session, err)
+			}
+			s = NewServer(database, baseDir, x.Session, ServeConfig{})
+			servers[x.Session] = s
+			handlers[x.Session] = s.Handler()
+		}
+
+		mapper := res.replacer()
+		path, body := mapper.Replace(x.Path), mapper.Replace(x.Body)
+		req := httptest.NewRequest(x.Method, path, strings.NewReader(body))
+		if x.ContentType != "" {
+			req.Header.Set("Content-Type", x.ContentType)
+		}
+		rec := httptest.NewRecorder()
+		handlers[x.Session].ServeHTTP(rec, req)
+		s.jobs.Wait()
+		res.Replayed++
+
+		if rec.Code != x.Status {
+			res.Mismatches = append(res.Mismatches, ReplayMismatch{
+				Seq: x.Seq, Method: x.Method, Path: path,
+				Want: x.Status, Got: rec.Code, Response: strings.TrimSpace(rec.Body.String()),
+			})
+			continue
+		}
+		res.Matched++
+
+		var want, got any
+		if json.Unmarshal(x.Response, &want) == nil && json.Unmarshal(rec.Body.Bytes(), &got) == nil {
+			matchIDs(res.IDs, want, got)
+		}
+	}
+	return res, nil
+}
+
+// replacer rewrites recorded IDs to replayed ones, longest first so an ID
+// that prefixes another can't clobber it
+func (res *ReplayResult) replacer() *strings.Replacer {
+	olds := make([]string, 0, len(res.IDs))
+	for old := range res.IDs {
+		olds = append(olds, old)
+	}
+	sort.Slice(olds, func(i, j int) bool { return len(olds[i]) > len(olds[j]) })
+	pairs := make([]string, 0, 2*len(olds))
+	for _, old := range olds {
+		pairs = append(pairs, old, res.IDs[old])
+	}
+	return strings.NewReplacer(pairs...)
+}
+
+// matchIDs walks the recorded and replayed responses side by side and notes
+// each recorded ID that came back as a different one
+func matchIDs(ids map[string]string, want, got any) {
+	switch w := want.(type) {
+	case string:
+		g, ok := got.(string)
+		if ok && g != w && replayIDPattern.MatchString(w) && replayIDPattern.MatchString(g) {
+			if _, seen := ids[w]; !seen {
+				ids[w] = g
+			}
+		}
+	case map[string]any:
+		g, ok := got.(map[string]any)
+		if !ok {
+			return
+		}
+		for k, v := range w {
+			if k == "request_id" { // per request, not a record
+				continue
+			}
+			matchIDs(ids, v, g[k])
+		}
+	case []any:
+		g, ok := got.([]any)
+		if !ok {
+			return
+		}
+		for i := range min(len(w), len(g)) {
+			matchIDs(ids, w[i], g[i])
+		}
+	}
+}
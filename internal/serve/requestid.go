@@ -0,0 +1,59 @@
+package serve
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"regexp"
+)
+
+// RequestIDHeader carries a request's correlation ID. A valid ID sent by the
+// client is kept, so a caller can tie its own logs to the server's;
+// otherwise the server assigns one. Either way it is echoed in the response.
+const RequestIDHeader = "X-Request-ID"
+
+const requestIDPrefix = "req_"
+
+// validRequestID limits client-supplied IDs to something safe to log
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
+type requestIDKey struct{}
+
+// RequestID returns the correlation ID of the request ctx belongs to, or ""
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// newRequestID creates a random ID with the req_ prefix (e.g. "req_3fa9c2e71b04")
+func newRequestID() string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return requestIDPrefix + "unknown"
+	}
+	return requestIDPrefix + hex.EncodeToString(b)
+}
+
+// requestIDMiddleware assigns each request its correlation ID, storing it
+// in the request context and the response header before any handler runs.
+func (s *Server) requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id)))
+	})
+}
+
+// requestLog returns the default logger with the request's correlation ID
+// attached, for log records written while handling r
+func requestLog(r *http.Request) *slog.Logger {
+	if id := RequestID(r.Context()); id != "" {
+		return slog.With("request_id", id)
+	}
+	return slog.Default()
+}
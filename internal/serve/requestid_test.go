@@ -0,0 +1,104 @@
+package serve
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRequestID_Header(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	resp, _ := doJSON(t, ts, "GET", "/health", nil)
+	if id := resp.Header.Get(RequestIDHeader); !strings.HasPrefix(id, requestIDPrefix) {
+		t.Errorf("assigned request ID = %q, want %s prefix", id, requestIDPrefix)
+	}
+
+	for _, tt := range []struct{ sent, want string }{
+		{"client-42", "client-42"},
+		{"has spaces and is 'quoted'", ""},
+	} {
+		req, _ := http.NewRequest("GET", ts.URL+"/health", nil)
+		req.Header.Set(RequestIDHeader, tt.sent)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("GET /health: %v", err)
+		}
+		resp.Body.Close()
+		got := resp.Header.Get(RequestIDHeader)
+		if tt.want != "" && got != tt.want {
+			t.Errorf("sent %q: request ID = %q, want it kept", tt.sent, got)
+		}
+		if tt.want == "" && !strings.HasPrefix(got, requestIDPrefix) {
+			t.Errorf("sent %q: request ID = %q, want a generated one", tt.sent, got)
+		}
+	}
+}
+
+func TestRequestID_ErrorDetails(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	resp, env := doJSON(t, ts, "GET", "/v1/issues/td-missing", nil)
+	if resp.StatusCode != http.StatusNotFound || env.Error == nil {
+		t.Fatalf("status = %d, error = %+v; want 404", resp.StatusCode, env.Error)
+	}
+	details, _ := env.Error.Details.(map[string]interface{})
+	if details["request_id"] != resp.Header.Get(RequestIDHeader) {
+		t.Errorf("details = %v, want request_id %q", env.Error.Details, resp.Header.Get(RequestIDHeader))
+	}
+
+	// Validation errors keep their fields alongside the ID
+	resp, env = doJSON(t, ts, "POST", "/v1/issues", IssueCreateBody{})
+	details, _ = env.Error.Details.(map[string]interface{})
+	if details["request_id"] != resp.Header.Get(RequestIDHeader) || details["fields"] == nil {
+		t.Errorf("validation details = %v", env.Error.Details)
+	}
+}
+
+func TestRequestID_LogsAndSSE(t *testing.T) {
+	var logs bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	defer slog.SetDefault(prev)
+
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+	events, unsubscribe := srv.sseHub.Subscribe()
+	defer unsubscribe()
+
+	body, _ := json.Marshal(IssueCreateBody{Title: "Trace this write through SSE"})
+	req, _ := http.NewRequest("POST", ts.URL+"/v1/issues", bytes.NewReader(body))
+	req.Header.Set(RequestIDHeader, "trace-7")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST /v1/issues: %v", err)
+	}
+	resp.Body.Close()
+
+	select {
+	case ev := <-events:
+		var data refreshData
+		if err := json.Unmarshal([]byte(ev.Data), &data); err != nil {
+			t.Fatalf("decode refresh: %v", err)
+		}
+		if ev.Event != "refresh" || data.RequestID != "trace-7" {
+			t.Errorf("event %s request_id = %q, want refresh from trace-7", ev.Event, data.RequestID)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no refresh event after the write")
+	}
+
+	if !strings.Contains(logs.String(), "request_id=trace-7") {
+		t.Errorf("request log missing request_id:\n%s", logs.String())
+	}
+}
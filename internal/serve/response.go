@@ -4,6 +4,7 @@
 package serve
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
 	"log/slog"
@@ -12,9 +13,15 @@ import (
 	"time"
 	"unicode/utf8"
 
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/confidential"
+	"github.com/marcus/td/internal/dateparse"
 	"github.com/marcus/td/internal/db"
 	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/score"
 	"github.com/marcus/td/internal/session"
+	"github.com/marcus/td/internal/textdiff"
+	"github.com/marcus/td/internal/xref"
 	"github.com/marcus/td/pkg/monitor"
 )
 
@@ -60,6 +67,7 @@ const (
 	ErrUnauthorized = "unauthorized"     // 401
 	ErrForbidden    = "forbidden"        // 403
 	ErrInternal     = "internal"         // 500
+	ErrRateLimited  = "rate_limited"     // 429
 )
 
 // WriteSuccess writes a JSON success envelope with the given data and status.
@@ -71,37 +79,112 @@ func WriteSuccess(w http.ResponseWriter, data interface{}, status int) {
 	}
 }
 
-// WriteError writes a JSON error envelope.
-func WriteError(w http.ResponseWriter, code, message string, status int) {
+// WriteIssueList writes a success envelope whose data holds issues under
+// "issues" alongside the meta fields. Issues are converted and encoded one
+// at a time, so large pages never build a full DTO slice in memory. fields
+// limits each issue to a sparse fieldset (nil for all fields);
+// closedViaOverride holds the IDs to flag as closed via an override and
+// inherited the raised priorities.
+func WriteIssueList(w http.ResponseWriter, issues []models.Issue, fields IssueFields, closedViaOverride map[string]bool, inherited map[string]models.InheritedPriority, meta map[string]interface{}, status int) {
+	metaJSON, err := json.Marshal(meta)
+	if err != nil {
+		WriteError(w, ErrInternal, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
-	if err := json.NewEncoder(w).Encode(Envelope{
-		OK: false,
-		Error: &ErrorPayload{
-			Code:    code,
-			Message: message,
-		},
-	}); err != nil {
-		slog.Error("write error response", "err", err)
+
+	bw := bufio.NewWriterSize(w, 32*1024)
+	enc := json.NewEncoder(bw)
+	bw.WriteString(`{"ok":true,"data":{"issues":[`)
+	for i := range issues {
+		if i > 0 {
+			bw.WriteByte(',')
+		}
+		dto := IssueToDTO(&issues[i])
+		dto.ClosedViaOverride = closedViaOverride[issues[i].ID]
+		dto.withInheritance(inherited)
+		if err := enc.Encode(fields.Issue(dto)); err != nil {
+			slog.Error("write issue list", "err", err)
+			return
+		}
+	}
+	bw.WriteByte(']')
+	if len(metaJSON) > 2 { // not "{}"
+		bw.WriteByte(',')
+		bw.Write(metaJSON[1 : len(metaJSON)-1])
+	}
+	bw.WriteString("}}\n")
+	if err := bw.Flush(); err != nil {
+		slog.Error("write issue list", "err", err)
 	}
 }
 
+// WriteError writes a JSON error envelope.
+func WriteError(w http.ResponseWriter, code, message string, status int) {
+	writeErrorPayload(w, status, ErrorPayload{Code: code, Message: message})
+}
+
+// WriteErrorDetails writes a JSON error envelope with structured details.
+func WriteErrorDetails(w http.ResponseWriter, code, message string, details interface{}, status int) {
+	writeErrorPayload(w, status, ErrorPayload{Code: code, Message: message, Details: details})
+}
+
 // WriteValidation writes a 400 validation_error response with field-level details.
 func WriteValidation(w http.ResponseWriter, fields []FieldError) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusBadRequest)
-	if err := json.NewEncoder(w).Encode(Envelope{
-		OK: false,
-		Error: &ErrorPayload{
-			Code:    ErrValidation,
-			Message: "Validation failed",
-			Details: ValidationDetails{Fields: fields},
-		},
-	}); err != nil {
-		slog.Error("write validation response", "err", err)
+	writeErrorPayload(w, http.StatusBadRequest, ErrorPayload{
+		Code:    ErrValidation,
+		Message: "Validation failed",
+		Details: ValidationDetails{Fields: fields},
+	})
+}
+
+// writeErrorPayload writes an error as the JSON envelope, or as RFC 9457
+// problem details when the client asked for application/problem+json.
+func writeErrorPayload(w http.ResponseWriter, status int, payload ErrorPayload) {
+	var body interface{}
+	contentType := "application/json"
+	if pw := problemWriterOf(w); pw != nil {
+		body = pw.problem(status, payload, w.Header().Get(RequestIDHeader))
+		contentType = ProblemContentType
+	} else {
+		payload.Details = withRequestID(w, payload.Details)
+		body = Envelope{OK: false, Error: &payload}
+	}
+
+	w.Header().Del("ETag") // errors are never cacheable
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		slog.Error("write error response", "err", err)
 	}
 }
 
+// withRequestID adds the request's correlation ID, which the request ID
+// middleware put in the response header, to an error's details as
+// request_id. Details that do not encode as a JSON object are kept under
+// "info".
+func withRequestID(w http.ResponseWriter, details interface{}) interface{} {
+	id := w.Header().Get(RequestIDHeader)
+	if id == "" {
+		return details
+	}
+	if details == nil {
+		return map[string]interface{}{"request_id": id}
+	}
+	data, err := json.Marshal(details)
+	if err != nil {
+		return details
+	}
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(data, &fields) != nil || fields == nil {
+		return map[string]interface{}{"request_id": id, "info": details}
+	}
+	fields["request_id"], _ = json.Marshal(id)
+	return fields
+}
+
 // ============================================================================
 // Issue DTO
 // ============================================================================
@@ -130,30 +213,52 @@ type IssueDTO struct {
 	ClosedAt           *string  `json:"closed_at"`
 	DeletedAt          *string  `json:"deleted_at"`
 	Minor              bool     `json:"minor"`
+	Inbox              bool     `json:"inbox"`
+	Confidential       bool     `json:"confidential"`
+	Rank               int      `json:"rank"` // place in the project-wide queue; 0 = unranked
 	CreatedBranch      *string  `json:"created_branch"`
+	CreatedRepo        *string  `json:"created_repo"`
 	DeferUntil         *string  `json:"defer_until"`
 	DueDate            *string  `json:"due_date"`
 	DeferCount         int      `json:"defer_count"`
+	BlockedReason      *string  `json:"blocked_reason"`
+	BlockedRef         *string  `json:"blocked_ref"`
+	Score              float64  `json:"score"` // computed by the configured score formula
+	// Priority after inheritance from the issues waiting on this one (see
+	// td policy inherit), and why it is raised; null when it isn't. Set by
+	// handlers that look inheritance up.
+	EffectivePriority   string                  `json:"effective_priority"`
+	PriorityInheritance *PriorityInheritanceDTO `json:"priority_inheritance"`
+	// Closed by an override (e.g. a minor self-approval) rather than an
+	// independent review. Set by handlers that look overrides up.
+	ClosedViaOverride bool `json:"closed_via_override"`
 }
 
 // IssueToDTO converts a models.Issue to an IssueDTO with proper null/empty
-// handling for the API layer.
+// handling for the API layer. Sealed text of a confidential issue is
+// redacted; handlers reveal it beforehand for viewers with access.
 func IssueToDTO(issue *models.Issue) IssueDTO {
 	dto := IssueDTO{
-		ID:          issue.ID,
-		Title:       issue.Title,
-		Description: issue.Description,
-		Status:      string(issue.Status),
-		Type:        string(issue.Type),
-		Priority:    string(issue.Priority),
-		Points:      issue.Points,
-		Labels:      issue.Labels,
-		Acceptance:  issue.Acceptance,
-		Sprint:      issue.Sprint,
-		Minor:       issue.Minor,
-		DeferCount:  issue.DeferCount,
-		CreatedAt:   issue.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:   issue.UpdatedAt.Format(time.RFC3339),
+		ID:           issue.ID,
+		Title:        issue.Title,
+		Description:  confidential.Redact(issue.Description),
+		Status:       string(issue.Status),
+		Type:         string(issue.Type),
+		Priority:     string(issue.Priority),
+		Points:       issue.Points,
+		Labels:       issue.Labels,
+		Acceptance:   confidential.Redact(issue.Acceptance),
+		Sprint:       issue.Sprint,
+		Minor:        issue.Minor,
+		Inbox:        issue.Inbox,
+		Confidential: issue.Confidential,
+		Rank:         issue.Rank,
+		DeferCount:   issue.DeferCount,
+		Score:        score.Current().Eval(issue, dateparse.Now()),
+		CreatedAt:    formatTimestamp(issue.CreatedAt),
+		UpdatedAt:    formatTimestamp(issue.UpdatedAt),
+
+		EffectivePriority: string(issue.Priority),
 	}
 
 	// Ensure labels is always an array, never null
@@ -167,6 +272,9 @@ func IssueToDTO(issue *models.Issue) IssueDTO {
 	dto.CreatorSession = nullableString(issue.CreatorSession)
 	dto.ReviewerSession = nullableString(issue.ReviewerSession)
 	dto.CreatedBranch = nullableString(issue.CreatedBranch)
+	dto.CreatedRepo = nullableString(issue.CreatedRepo)
+	dto.BlockedReason = nullableString(string(issue.BlockedReason))
+	dto.BlockedRef = nullableString(issue.BlockedRef)
 
 	// Nullable *string fields (already pointers in model)
 	dto.DeferUntil = issue.DeferUntil
@@ -188,6 +296,41 @@ func IssuesToDTOs(issues []models.Issue) []IssueDTO {
 	return dtos
 }
 
+// IssueCardDTO is the API representation of an issue card: the summary
+// and counts list views show next to an issue.
+type IssueCardDTO struct {
+	ID             string   `json:"id"`
+	Title          string   `json:"title"`
+	Status         string   `json:"status"`
+	Priority       string   `json:"priority"`
+	Points         int      `json:"points"`
+	Labels         []string `json:"labels"`
+	BlockedByCount int      `json:"blocked_by_count"`
+	CommentCount   int      `json:"comment_count"`
+	LastActivity   *string  `json:"last_activity"`
+}
+
+// IssueCardToDTO converts a models.IssueCard to an IssueCardDTO.
+func IssueCardToDTO(card models.IssueCard) IssueCardDTO {
+	dto := IssueCardDTO{
+		ID:             card.ID,
+		Title:          card.Title,
+		Status:         string(card.Status),
+		Priority:       string(card.Priority),
+		Points:         card.Points,
+		Labels:         card.Labels,
+		BlockedByCount: card.BlockedBy,
+		CommentCount:   card.Comments,
+	}
+	if dto.Labels == nil {
+		dto.Labels = []string{}
+	}
+	if !card.LastActivity.IsZero() {
+		dto.LastActivity = nullableTime(&card.LastActivity)
+	}
+	return dto
+}
+
 // ============================================================================
 // Log DTO
 // ============================================================================
@@ -212,7 +355,7 @@ func LogToDTO(log *models.Log) LogDTO {
 		WorkSessionID: log.WorkSessionID,
 		Message:       log.Message,
 		Type:          string(log.Type),
-		Timestamp:     log.Timestamp.Format(time.RFC3339),
+		Timestamp:     formatTimestamp(log.Timestamp),
 	}
 }
 
@@ -244,8 +387,8 @@ func CommentToDTO(comment *models.Comment) CommentDTO {
 		ID:        comment.ID,
 		IssueID:   comment.IssueID,
 		SessionID: comment.SessionID,
-		Text:      comment.Text,
-		CreatedAt: comment.CreatedAt.Format(time.RFC3339),
+		Text:      confidential.Redact(comment.Text),
+		CreatedAt: formatTimestamp(comment.CreatedAt),
 	}
 }
 
@@ -284,7 +427,7 @@ func HandoffToDTO(handoff *models.Handoff) HandoffDTO {
 		Remaining: handoff.Remaining,
 		Decisions: handoff.Decisions,
 		Uncertain: handoff.Uncertain,
-		Timestamp: handoff.Timestamp.Format(time.RFC3339),
+		Timestamp: formatTimestamp(handoff.Timestamp),
 	}
 	// Ensure collections are never null
 	if dto.Done == nil {
@@ -307,21 +450,27 @@ func HandoffToDTO(handoff *models.Handoff) HandoffDTO {
 // ============================================================================
 
 // DependencyDTO is the API representation of an issue dependency.
+// Project is set when DependsOnID is qualified with a linked project.
 type DependencyDTO struct {
 	DepID        string `json:"dep_id"`
 	IssueID      string `json:"issue_id"`
 	DependsOnID  string `json:"depends_on_id"`
 	RelationType string `json:"relation_type"`
+	Project      string `json:"project,omitempty"`
 }
 
 // DependencyToDTO converts a models.IssueDependency to a DependencyDTO.
 func DependencyToDTO(dep *models.IssueDependency) DependencyDTO {
-	return DependencyDTO{
+	dto := DependencyDTO{
 		DepID:        db.DependencyID(dep.IssueID, dep.DependsOnID, dep.RelationType),
 		IssueID:      dep.IssueID,
 		DependsOnID:  dep.DependsOnID,
 		RelationType: dep.RelationType,
 	}
+	if ref, ok := xref.Parse(dep.DependsOnID); ok {
+		dto.Project = ref.Project
+	}
+	return dto
 }
 
 // DependenciesToDTOs converts a slice of dependencies to DTOs.
@@ -358,8 +507,8 @@ func BoardToDTO(board *models.Board) BoardDTO {
 		IsBuiltin:    board.IsBuiltin,
 		ViewMode:     board.ViewMode,
 		LastViewedAt: nullableTime(board.LastViewedAt),
-		CreatedAt:    board.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:    board.UpdatedAt.Format(time.RFC3339),
+		CreatedAt:    formatTimestamp(board.CreatedAt),
+		UpdatedAt:    formatTimestamp(board.UpdatedAt),
 	}
 }
 
@@ -372,6 +521,509 @@ func BoardsToDTOs(boards []models.Board) []BoardDTO {
 	return dtos
 }
 
+// ============================================================================
+// Plan DTO
+// ============================================================================
+
+// PlanChangeDTO is the API representation of one planned field change.
+type PlanChangeDTO struct {
+	IssueID string `json:"issue_id"`
+	Title   string `json:"title"`
+	Field   string `json:"field"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+}
+
+// PlanDTO is the API representation of a plan. Status reports pending
+// plans past their TTL as "expired".
+type PlanDTO struct {
+	ID        string          `json:"id"`
+	Kind      string          `json:"kind"`
+	Summary   string          `json:"summary"`
+	Status    string          `json:"status"`
+	SessionID string          `json:"session_id"`
+	Changes   []PlanChangeDTO `json:"changes"`
+	CreatedAt string          `json:"created_at"`
+	ExpiresAt string          `json:"expires_at"`
+	AppliedAt *string         `json:"applied_at"`
+	AppliedBy string          `json:"applied_by,omitempty"`
+}
+
+// PlanToDTO converts a models.Plan to a PlanDTO.
+func PlanToDTO(plan *models.Plan) PlanDTO {
+	status := string(plan.Status)
+	if plan.Expired(clock.Now()) {
+		status = "expired"
+	}
+	changes := make([]PlanChangeDTO, len(plan.Changes))
+	for i, c := range plan.Changes {
+		changes[i] = PlanChangeDTO(c)
+	}
+	return PlanDTO{
+		ID:        plan.ID,
+		Kind:      string(plan.Kind),
+		Summary:   plan.Summary,
+		Status:    status,
+		SessionID: plan.SessionID,
+		Changes:   changes,
+		CreatedAt: formatTimestamp(plan.CreatedAt),
+		ExpiresAt: formatTimestamp(plan.ExpiresAt),
+		AppliedAt: nullableTime(plan.AppliedAt),
+		AppliedBy: plan.AppliedBy,
+	}
+}
+
+// PlansToDTOs converts a slice of plans to DTOs.
+func PlansToDTOs(plans []models.Plan) []PlanDTO {
+	dtos := make([]PlanDTO, len(plans))
+	for i := range plans {
+		dtos[i] = PlanToDTO(&plans[i])
+	}
+	return dtos
+}
+
+// ============================================================================
+// API Token DTO
+// ============================================================================
+
+// APITokenDTO is the API representation of an API token. The token itself
+// is never returned.
+type APITokenDTO struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	SessionID string   `json:"session_id"`
+	Scopes    []string `json:"scopes"`
+	Status    string   `json:"status"`
+	CreatedAt string   `json:"created_at"`
+	ExpiresAt *string  `json:"expires_at"`
+	RevokedAt *string  `json:"revoked_at"`
+}
+
+// APITokenToDTO converts a models.APIToken to an APITokenDTO. Status is
+// active, expired or revoked.
+func APITokenToDTO(t *models.APIToken) APITokenDTO {
+	status := "active"
+	switch {
+	case t.RevokedAt != nil:
+		status = "revoked"
+	case !t.Active(clock.Now()):
+		status = "expired"
+	}
+	return APITokenDTO{
+		ID:        t.ID,
+		Name:      t.Name,
+		SessionID: t.SessionID,
+		Scopes:    t.Scopes,
+		Status:    status,
+		CreatedAt: formatTimestamp(t.CreatedAt),
+		ExpiresAt: nullableTime(t.ExpiresAt),
+		RevokedAt: nullableTime(t.RevokedAt),
+	}
+}
+
+// APITokensToDTOs converts a slice of API tokens to DTOs, never nil.
+func APITokensToDTOs(tokens []models.APIToken) []APITokenDTO {
+	dtos := make([]APITokenDTO, len(tokens))
+	for i := range tokens {
+		dtos[i] = APITokenToDTO(&tokens[i])
+	}
+	return dtos
+}
+
+// ============================================================================
+// Subscription DTO
+// ============================================================================
+
+// SubscriptionDTO is the API representation of a subscription. The
+// secret is never returned, only whether one is set.
+type SubscriptionDTO struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Label     string `json:"label"`
+	Epic      string `json:"epic"`
+	Query     string `json:"query"`
+	URL       string `json:"url"`
+	HasSecret bool   `json:"has_secret"`
+	CreatedAt string `json:"created_at"`
+}
+
+// SubscriptionToDTO converts a models.Subscription to a SubscriptionDTO.
+func SubscriptionToDTO(sub *models.Subscription) SubscriptionDTO {
+	return SubscriptionDTO{
+		ID:        sub.ID,
+		Name:      sub.Name,
+		Label:     sub.Label,
+		Epic:      sub.Epic,
+		Query:     sub.Query,
+		URL:       sub.URL,
+		HasSecret: sub.Secret != "",
+		CreatedAt: formatTimestamp(sub.CreatedAt),
+	}
+}
+
+// SubscriptionsToDTOs converts a slice of subscriptions to DTOs, never nil.
+func SubscriptionsToDTOs(subs []models.Subscription) []SubscriptionDTO {
+	dtos := make([]SubscriptionDTO, len(subs))
+	for i := range subs {
+		dtos[i] = SubscriptionToDTO(&subs[i])
+	}
+	return dtos
+}
+
+// ============================================================================
+// Reminder DTO
+// ============================================================================
+
+// ReminderDTO is the API representation of a reminder.
+type ReminderDTO struct {
+	ID        string  `json:"id"`
+	IssueID   string  `json:"issue_id"`
+	SessionID string  `json:"session_id"`
+	Message   string  `json:"message"`
+	Status    string  `json:"status"`
+	RemindAt  string  `json:"remind_at"`
+	CreatedAt string  `json:"created_at"`
+	FiredAt   *string `json:"fired_at"`
+}
+
+// ReminderToDTO converts a models.Reminder to a ReminderDTO.
+func ReminderToDTO(r *models.Reminder) ReminderDTO {
+	return ReminderDTO{
+		ID:        r.ID,
+		IssueID:   r.IssueID,
+		SessionID: r.SessionID,
+		Message:   r.Message,
+		Status:    string(r.Status),
+		RemindAt:  formatTimestamp(r.RemindAt),
+		CreatedAt: formatTimestamp(r.CreatedAt),
+		FiredAt:   nullableTime(r.FiredAt),
+	}
+}
+
+// RemindersToDTOs converts a slice of reminders to DTOs, never nil.
+func RemindersToDTOs(reminders []models.Reminder) []ReminderDTO {
+	dtos := make([]ReminderDTO, len(reminders))
+	for i := range reminders {
+		dtos[i] = ReminderToDTO(&reminders[i])
+	}
+	return dtos
+}
+
+// ============================================================================
+// Decision DTO
+// ============================================================================
+
+// DecisionDTO is the API representation of a decision.
+type DecisionDTO struct {
+	ID        string   `json:"id"`
+	Title     string   `json:"title"`
+	Context   string   `json:"context"`
+	Options   []string `json:"options"`
+	Decision  string   `json:"decision"`
+	IssueIDs  []string `json:"issue_ids"`
+	SessionID string   `json:"session_id"`
+	CreatedAt string   `json:"created_at"`
+	UpdatedAt string   `json:"updated_at"`
+}
+
+// DecisionToDTO converts a models.Decision to a DecisionDTO.
+func DecisionToDTO(d *models.Decision) DecisionDTO {
+	dto := DecisionDTO{
+		ID:        d.ID,
+		Title:     d.Title,
+		Context:   d.Context,
+		Options:   d.Options,
+		Decision:  d.Decision,
+		IssueIDs:  d.IssueIDs,
+		SessionID: d.SessionID,
+		CreatedAt: formatTimestamp(d.CreatedAt),
+		UpdatedAt: formatTimestamp(d.UpdatedAt),
+	}
+	// Ensure collections are never null
+	if dto.Options == nil {
+		dto.Options = []string{}
+	}
+	if dto.IssueIDs == nil {
+		dto.IssueIDs = []string{}
+	}
+	return dto
+}
+
+// DecisionsToDTOs converts a slice of decisions to DTOs, never nil.
+func DecisionsToDTOs(decisions []models.Decision) []DecisionDTO {
+	dtos := make([]DecisionDTO, len(decisions))
+	for i := range decisions {
+		dtos[i] = DecisionToDTO(&decisions[i])
+	}
+	return dtos
+}
+
+// ============================================================================
+// View DTO
+// ============================================================================
+
+// ViewDTO is the API representation of a saved view.
+type ViewDTO struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	Query     string   `json:"query"`
+	Sort      string   `json:"sort"`
+	Fields    []string `json:"fields"`
+	GroupBy   string   `json:"group_by"`
+	Layout    string   `json:"layout"`
+	SessionID string   `json:"session_id"`
+	CreatedAt string   `json:"created_at"`
+	UpdatedAt string   `json:"updated_at"`
+}
+
+// ViewToDTO converts a models.View to a ViewDTO.
+func ViewToDTO(v *models.View) ViewDTO {
+	dto := ViewDTO{
+		ID:        v.ID,
+		Name:      v.Name,
+		Query:     v.Query,
+		Sort:      v.Sort,
+		Fields:    v.Fields,
+		GroupBy:   v.GroupBy,
+		Layout:    v.Layout,
+		SessionID: v.SessionID,
+		CreatedAt: formatTimestamp(v.CreatedAt),
+		UpdatedAt: formatTimestamp(v.UpdatedAt),
+	}
+	if dto.Fields == nil {
+		dto.Fields = []string{}
+	}
+	return dto
+}
+
+// ViewsToDTOs converts a slice of views to DTOs, never nil.
+func ViewsToDTOs(views []models.View) []ViewDTO {
+	dtos := make([]ViewDTO, len(views))
+	for i := range views {
+		dtos[i] = ViewToDTO(&views[i])
+	}
+	return dtos
+}
+
+// RevisionDTO is the API representation of a revision. Diff is a unified
+// line diff from Before to After.
+type RevisionDTO struct {
+	ID        string `json:"id"`
+	IssueID   string `json:"issue_id"`
+	Field     string `json:"field"`
+	EntityID  string `json:"entity_id"`
+	Before    string `json:"before"`
+	After     string `json:"after"`
+	Diff      string `json:"diff"`
+	SessionID string `json:"session_id"`
+	CreatedAt string `json:"created_at"`
+}
+
+// RevisionToDTO converts a models.Revision to a RevisionDTO.
+func RevisionToDTO(r *models.Revision) RevisionDTO {
+	return RevisionDTO{
+		ID:        r.ID,
+		IssueID:   r.IssueID,
+		Field:     r.Field,
+		EntityID:  r.EntityID,
+		Before:    r.Before,
+		After:     r.After,
+		Diff:      textdiff.Unified(textdiff.Lines(r.Before, r.After)),
+		SessionID: r.SessionID,
+		CreatedAt: formatTimestamp(r.CreatedAt),
+	}
+}
+
+// RevisionsToDTOs converts a slice of revisions to DTOs, never nil.
+func RevisionsToDTOs(revisions []models.Revision) []RevisionDTO {
+	dtos := make([]RevisionDTO, len(revisions))
+	for i := range revisions {
+		dtos[i] = RevisionToDTO(&revisions[i])
+	}
+	return dtos
+}
+
+// ReworkDTO is the API representation of one reopen of a closed issue.
+type ReworkDTO struct {
+	ID        string `json:"id"`
+	IssueID   string `json:"issue_id"`
+	Category  string `json:"category"`
+	Reason    string `json:"reason"`
+	Approved  bool   `json:"approved"`
+	SessionID string `json:"session_id"`
+	CreatedAt string `json:"created_at"`
+}
+
+// ReworkToDTO converts a models.Rework to a ReworkDTO.
+func ReworkToDTO(rw *models.Rework) ReworkDTO {
+	return ReworkDTO{
+		ID:        rw.ID,
+		IssueID:   rw.IssueID,
+		Category:  string(rw.Category),
+		Reason:    rw.Reason,
+		Approved:  rw.Approved,
+		SessionID: rw.SessionID,
+		CreatedAt: formatTimestamp(rw.CreatedAt),
+	}
+}
+
+// ReworksToDTOs converts a slice of reworks to DTOs, never nil.
+func ReworksToDTOs(reworks []models.Rework) []ReworkDTO {
+	dtos := make([]ReworkDTO, len(reworks))
+	for i := range reworks {
+		dtos[i] = ReworkToDTO(&reworks[i])
+	}
+	return dtos
+}
+
+// ReworkedIssueDTO is one row of the rework report.
+type ReworkedIssueDTO struct {
+	IssueID      string         `json:"issue_id"`
+	Title        string         `json:"title"`
+	Status       string         `json:"status"`
+	Count        int            `json:"count"`
+	ByCategory   map[string]int `json:"by_category"`
+	LastReopened string         `json:"last_reopened"`
+}
+
+// ReworkedIssuesToDTOs converts rework report rows to DTOs, never nil.
+func ReworkedIssuesToDTOs(issues []models.ReworkedIssue) []ReworkedIssueDTO {
+	dtos := make([]ReworkedIssueDTO, len(issues))
+	for i, ri := range issues {
+		byCategory := make(map[string]int, len(ri.ByCategory))
+		for c, n := range ri.ByCategory {
+			byCategory[string(c)] = n
+		}
+		dtos[i] = ReworkedIssueDTO{
+			IssueID:      ri.IssueID,
+			Title:        ri.Title,
+			Status:       string(ri.Status),
+			Count:        ri.Count,
+			ByCategory:   byCategory,
+			LastReopened: formatTimestamp(ri.LastReopened),
+		}
+	}
+	return dtos
+}
+
+// OverrideDTO is the API representation of one bypass-prevention override.
+type OverrideDTO struct {
+	ID            string `json:"id"`
+	IssueID       string `json:"issue_id"`
+	Kind          string `json:"kind"`
+	Justification string `json:"justification"`
+	SessionID     string `json:"session_id"`
+	CreatedAt     string `json:"created_at"`
+}
+
+// OverridesToDTOs converts overrides to DTOs, never nil.
+func OverridesToDTOs(overrides []models.Override) []OverrideDTO {
+	dtos := make([]OverrideDTO, len(overrides))
+	for i, o := range overrides {
+		dtos[i] = OverrideDTO{
+			ID:            o.ID,
+			IssueID:       o.IssueID,
+			Kind:          string(o.Kind),
+			Justification: o.Justification,
+			SessionID:     o.SessionID,
+			CreatedAt:     o.CreatedAt.Format(time.RFC3339),
+		}
+	}
+	return dtos
+}
+
+// ImpersonationDTO is the API representation of an admin repair made on
+// behalf of another session.
+type ImpersonationDTO struct {
+	ID           string `json:"id"`
+	IssueID      string `json:"issue_id"`
+	Action       string `json:"action"`
+	ActorSession string `json:"actor_session"`
+	AsSession    string `json:"as_session"`
+	Before       string `json:"before"`
+	After        string `json:"after"`
+	Reason       string `json:"reason"`
+	CreatedAt    string `json:"created_at"`
+}
+
+// ImpersonationToDTO converts an impersonation record to its DTO.
+func ImpersonationToDTO(im models.Impersonation) ImpersonationDTO {
+	return ImpersonationDTO{
+		ID:           im.ID,
+		IssueID:      im.IssueID,
+		Action:       string(im.Action),
+		ActorSession: im.ActorSession,
+		AsSession:    im.AsSession,
+		Before:       im.Before,
+		After:        im.After,
+		Reason:       im.Reason,
+		CreatedAt:    im.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// ImpersonationsToDTOs converts impersonation records to DTOs, never nil.
+func ImpersonationsToDTOs(records []models.Impersonation) []ImpersonationDTO {
+	dtos := make([]ImpersonationDTO, len(records))
+	for i, im := range records {
+		dtos[i] = ImpersonationToDTO(im)
+	}
+	return dtos
+}
+
+// ContributionDTO is one session's share of the work logged on an issue.
+type ContributionDTO struct {
+	SessionID    string `json:"session_id"`
+	SessionName  string `json:"session_name"`
+	AgentType    string `json:"agent_type"`
+	Logs         int    `json:"logs"`
+	WorkSessions int    `json:"work_sessions"`
+	FirstAt      string `json:"first_at"`
+	LastAt       string `json:"last_at"`
+}
+
+// IssueEffortDTO breaks the work logged on an issue down by session.
+type IssueEffortDTO struct {
+	IssueID      string            `json:"issue_id"`
+	Title        string            `json:"title"`
+	Status       string            `json:"status"`
+	Logs         int               `json:"logs"`
+	WorkSessions int               `json:"work_sessions"`
+	Contributors []ContributionDTO `json:"contributors"`
+}
+
+// IssueEffortToDTO converts an effort breakdown to its DTO.
+func IssueEffortToDTO(e *models.IssueEffort) IssueEffortDTO {
+	contributors := make([]ContributionDTO, len(e.Contributors))
+	for i, c := range e.Contributors {
+		contributors[i] = ContributionDTO{
+			SessionID:    c.SessionID,
+			SessionName:  c.SessionName,
+			AgentType:    c.AgentType,
+			Logs:         c.Logs,
+			WorkSessions: c.WorkSessions,
+			FirstAt:      formatTimestamp(c.FirstAt),
+			LastAt:       formatTimestamp(c.LastAt),
+		}
+	}
+	return IssueEffortDTO{
+		IssueID:      e.IssueID,
+		Title:        e.Title,
+		Status:       string(e.Status),
+		Logs:         e.Logs,
+		WorkSessions: e.WorkSessions,
+		Contributors: contributors,
+	}
+}
+
+// IssueEffortsToDTOs converts effort breakdowns to DTOs, never nil.
+func IssueEffortsToDTOs(efforts []models.IssueEffort) []IssueEffortDTO {
+	dtos := make([]IssueEffortDTO, len(efforts))
+	for i := range efforts {
+		dtos[i] = IssueEffortToDTO(&efforts[i])
+	}
+	return dtos
+}
+
 // ============================================================================
 // Session DTO
 // ============================================================================
@@ -387,6 +1039,7 @@ type SessionDTO struct {
 	PreviousSessionID *string `json:"previous_session_id"`
 	StartedAt         string  `json:"started_at"`
 	LastActivity      string  `json:"last_activity"`
+	Liveness          string  `json:"liveness"`
 }
 
 // SessionToDTO converts a session.Session to a SessionDTO.
@@ -399,8 +1052,9 @@ func SessionToDTO(sess *session.Session) SessionDTO {
 		AgentPID:          sess.AgentPID,
 		ContextID:         sess.ContextID,
 		PreviousSessionID: nullableString(sess.PreviousSessionID),
-		StartedAt:         sess.StartedAt.Format(time.RFC3339),
-		LastActivity:      sess.LastActivity.Format(time.RFC3339),
+		StartedAt:         formatTimestamp(sess.StartedAt),
+		LastActivity:      formatTimestamp(sess.LastActivity),
+		Liveness:          string(sess.Liveness(clock.Now())),
 	}
 }
 
@@ -436,7 +1090,7 @@ type ActivityItemDTO struct {
 // ActivityItemToDTO converts a monitor.ActivityItem to an ActivityItemDTO.
 func ActivityItemToDTO(item *monitor.ActivityItem) ActivityItemDTO {
 	return ActivityItemDTO{
-		Timestamp:    item.Timestamp.Format(time.RFC3339),
+		Timestamp:    formatTimestamp(item.Timestamp),
 		SessionID:    item.SessionID,
 		Type:         item.Type,
 		IssueID:      item.IssueID,
@@ -466,13 +1120,15 @@ func ActivityItemsToDTOs(items []monitor.ActivityItem) []ActivityItemDTO {
 
 // MonitorDTO is the API representation of the full monitor state.
 type MonitorDTO struct {
-	FocusedIssue   *IssueDTO          `json:"focused_issue"`
-	InProgress     []IssueDTO         `json:"in_progress"`
-	Activity       []ActivityItemDTO  `json:"activity"`
-	TaskList       TaskListDTO        `json:"task_list"`
-	RecentHandoffs []RecentHandoffDTO `json:"recent_handoffs"`
-	ActiveSessions []string           `json:"active_sessions"`
-	Timestamp      string             `json:"timestamp"`
+	FocusedIssue    *IssueDTO               `json:"focused_issue"`
+	InProgress      []IssueDTO              `json:"in_progress"`
+	Activity        []ActivityItemDTO       `json:"activity"`
+	TaskList        TaskListDTO             `json:"task_list"`
+	RecentHandoffs  []RecentHandoffDTO      `json:"recent_handoffs"`
+	ActiveSessions  []string                `json:"active_sessions"`
+	SessionLiveness map[string]string       `json:"session_liveness"` // "active" or "idle"; gone sessions omitted
+	Cards           map[string]IssueCardDTO `json:"cards"`            // keyed by issue ID, for every issue listed
+	Timestamp       string                  `json:"timestamp"`
 }
 
 // TaskListDTO is the API representation of categorized task lists.
@@ -481,6 +1137,7 @@ type TaskListDTO struct {
 	NeedsRework   []IssueDTO `json:"needs_rework"`
 	InProgress    []IssueDTO `json:"in_progress"`
 	Ready         []IssueDTO `json:"ready"`
+	NeedsTriage   []IssueDTO `json:"needs_triage"`
 	PendingReview []IssueDTO `json:"pending_review"`
 	Blocked       []IssueDTO `json:"blocked"`
 	Closed        []IssueDTO `json:"closed"`
@@ -496,7 +1153,7 @@ type RecentHandoffDTO struct {
 // MonitorDataToDTO converts a RefreshDataMsg to a MonitorDTO.
 func MonitorDataToDTO(msg *monitor.RefreshDataMsg) MonitorDTO {
 	dto := MonitorDTO{
-		Timestamp:      msg.Timestamp.Format(time.RFC3339),
+		Timestamp:      formatTimestamp(msg.Timestamp),
 		ActiveSessions: msg.ActiveSessions,
 	}
 
@@ -505,6 +1162,11 @@ func MonitorDataToDTO(msg *monitor.RefreshDataMsg) MonitorDTO {
 		dto.ActiveSessions = []string{}
 	}
 
+	dto.SessionLiveness = make(map[string]string, len(msg.SessionLiveness))
+	for id, l := range msg.SessionLiveness {
+		dto.SessionLiveness[id] = string(l)
+	}
+
 	// Focused issue
 	if msg.FocusedIssue != nil {
 		focused := IssueToDTO(msg.FocusedIssue)
@@ -520,13 +1182,19 @@ func MonitorDataToDTO(msg *monitor.RefreshDataMsg) MonitorDTO {
 	// Task list
 	dto.TaskList = taskListDataToDTO(&msg.TaskList)
 
+	// Issue cards
+	dto.Cards = make(map[string]IssueCardDTO, len(msg.Cards))
+	for id, card := range msg.Cards {
+		dto.Cards[id] = IssueCardToDTO(card)
+	}
+
 	// Recent handoffs
 	dto.RecentHandoffs = make([]RecentHandoffDTO, len(msg.RecentHandoffs))
 	for i, h := range msg.RecentHandoffs {
 		dto.RecentHandoffs[i] = RecentHandoffDTO{
 			IssueID:   h.IssueID,
 			SessionID: h.SessionID,
-			Timestamp: h.Timestamp.Format(time.RFC3339),
+			Timestamp: formatTimestamp(h.Timestamp),
 		}
 	}
 	if dto.RecentHandoffs == nil {
@@ -543,6 +1211,7 @@ func taskListDataToDTO(data *monitor.TaskListData) TaskListDTO {
 		NeedsRework:   issuesToDTOsNonNil(data.NeedsRework),
 		InProgress:    issuesToDTOsNonNil(data.InProgress),
 		Ready:         issuesToDTOsNonNil(data.Ready),
+		NeedsTriage:   issuesToDTOsNonNil(data.NeedsTriage),
 		PendingReview: issuesToDTOsNonNil(data.PendingReview),
 		Blocked:       issuesToDTOsNonNil(data.Blocked),
 		Closed:        issuesToDTOsNonNil(data.Closed),
@@ -560,6 +1229,10 @@ type StatsDTO struct {
 	ByType     map[string]int `json:"by_type"`
 	ByPriority map[string]int `json:"by_priority"`
 
+	// ByBlockedReason counts blocked issues by why they are blocked;
+	// "unspecified" covers issues blocked without a reason.
+	ByBlockedReason map[string]int `json:"by_blocked_reason"`
+
 	OldestOpen      *IssueDTO `json:"oldest_open"`
 	NewestTask      *IssueDTO `json:"newest_task"`
 	LastClosed      *IssueDTO `json:"last_closed"`
@@ -573,6 +1246,13 @@ type StatsDTO struct {
 	TotalLogs         int    `json:"total_logs"`
 	TotalHandoffs     int    `json:"total_handoffs"`
 	MostActiveSession string `json:"most_active_session"`
+
+	// Reopens of closed issues. ReworkRate is the share of issues ever
+	// closed that were reopened at least once.
+	TotalReworks     int            `json:"total_reworks"`
+	ReworkedIssues   int            `json:"reworked_issues"`
+	ReworkRate       float64        `json:"rework_rate"`
+	ByReworkCategory map[string]int `json:"by_rework_category"`
 }
 
 // StatsToDTO converts a models.ExtendedStats to a StatsDTO.
@@ -582,6 +1262,7 @@ func StatsToDTO(stats *models.ExtendedStats) StatsDTO {
 		ByStatus:          make(map[string]int),
 		ByType:            make(map[string]int),
 		ByPriority:        make(map[string]int),
+		ByBlockedReason:   make(map[string]int),
 		CreatedToday:      stats.CreatedToday,
 		CreatedThisWeek:   stats.CreatedThisWeek,
 		TotalPoints:       stats.TotalPoints,
@@ -590,6 +1271,10 @@ func StatsToDTO(stats *models.ExtendedStats) StatsDTO {
 		TotalLogs:         stats.TotalLogs,
 		TotalHandoffs:     stats.TotalHandoffs,
 		MostActiveSession: stats.MostActiveSession,
+		TotalReworks:      stats.TotalReworks,
+		ReworkedIssues:    stats.ReworkedIssues,
+		ReworkRate:        stats.ReworkRate,
+		ByReworkCategory:  make(map[string]int),
 	}
 
 	for status, count := range stats.ByStatus {
@@ -601,6 +1286,15 @@ func StatsToDTO(stats *models.ExtendedStats) StatsDTO {
 	for prio, count := range stats.ByPriority {
 		dto.ByPriority[string(prio)] = count
 	}
+	for category, count := range stats.ByReworkCategory {
+		dto.ByReworkCategory[string(category)] = count
+	}
+	for reason, count := range stats.ByBlockedReason {
+		if reason == "" {
+			reason = "unspecified"
+		}
+		dto.ByBlockedReason[string(reason)] = count
+	}
 
 	if stats.OldestOpen != nil {
 		issue := IssueToDTO(stats.OldestOpen)
@@ -618,6 +1312,33 @@ func StatsToDTO(stats *models.ExtendedStats) StatsDTO {
 	return dto
 }
 
+// SummaryDTO is the compact project overview served by GET /v1/summary.
+type SummaryDTO struct {
+	ByStatus     map[string]int `json:"by_status"`
+	Open         int            `json:"open"`
+	OpenP0       int            `json:"open_p0"`
+	ReviewQueue  int            `json:"review_queue"`
+	LastActivity *string        `json:"last_activity"`
+}
+
+// SummaryToDTO converts a models.Summary to a SummaryDTO.
+func SummaryToDTO(sum *models.Summary) SummaryDTO {
+	dto := SummaryDTO{
+		ByStatus:    make(map[string]int, len(sum.ByStatus)),
+		Open:        sum.Open,
+		OpenP0:      sum.OpenP0,
+		ReviewQueue: sum.ReviewQueue,
+	}
+	for status, count := range sum.ByStatus {
+		dto.ByStatus[string(status)] = count
+	}
+	if sum.LastActivity != nil {
+		at := sum.LastActivity.UTC().Format(time.RFC3339)
+		dto.LastActivity = &at
+	}
+	return dto
+}
+
 // ============================================================================
 // Pagination DTO
 // ============================================================================
@@ -651,8 +1372,17 @@ type IssueCreateBody struct {
 	Acceptance  string   `json:"acceptance"`
 	Sprint      string   `json:"sprint"`
 	Minor       bool     `json:"minor"`
+	Inbox       bool     `json:"inbox"` // hold for triage; see /v1/inbox
 	DeferUntil  string   `json:"defer_until"`
 	DueDate     string   `json:"due_date"`
+	// Seal the description, acceptance and comments with the project key
+	Confidential bool `json:"confidential"`
+}
+
+// QuickAddBody is the JSON body for POST /v1/issues/quick.
+type QuickAddBody struct {
+	Text  string `json:"text"`  // e.g. "Fix login timeout #bug !p1 +auth"
+	Inbox bool   `json:"inbox"` // hold for triage; see /v1/inbox
 }
 
 // IssueUpdateBody represents the expected JSON body for updating an issue.
@@ -860,12 +1590,19 @@ func nullableString(s string) *string {
 	return &s
 }
 
+// formatTimestamp renders a DTO timestamp as RFC3339 in the project's
+// timezone, so every timestamp carries the same offset whichever machine
+// wrote it
+func formatTimestamp(t time.Time) string {
+	return t.In(dateparse.Location()).Format(time.RFC3339)
+}
+
 // nullableTime converts a *time.Time to *string (RFC3339), returning nil when input is nil.
 func nullableTime(t *time.Time) *string {
 	if t == nil {
 		return nil
 	}
-	s := t.Format(time.RFC3339)
+	s := formatTimestamp(*t)
 	return &s
 }
 
@@ -0,0 +1,39 @@
+package serve
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/jobs"
+)
+
+// retentionJob applies the project's retention policy every
+// RetentionInterval. The policy is reread on each run, so td retention set
+// takes effect without a restart; with no policy the run does nothing.
+func (s *Server) retentionJob() jobs.Job {
+	return jobs.Job{
+		Name:        "retention",
+		Description: "Prune logs and activity history past the retention policy",
+		Interval:    s.config.RetentionInterval,
+		Run: func(context.Context) error {
+			rc, err := config.GetRetentionConfig(s.baseDir)
+			if err != nil {
+				return err
+			}
+			opts := db.RetentionOptionsFor(rc, clock.Now())
+			if opts.LogsBefore.IsZero() && opts.ActivityBefore.IsZero() {
+				return nil
+			}
+			report, err := s.db.ApplyRetention(opts)
+			if err != nil {
+				return err
+			}
+			slog.Info("retention", "logs_pruned", report.LogsPruned, "logs_exempt", report.LogsExempt,
+				"actions_pruned", report.ActionsPruned, "actions_kept", report.ActionsKept)
+			return nil
+		},
+	}
+}
@@ -4,14 +4,15 @@ import (
 	"bufio"
 	"context"
 	"fmt"
-	"log/slog"
+	"io"
 	"net"
 	"net/http"
-	"runtime/debug"
 	"strings"
 	"time"
 
+	"github.com/marcus/td/internal/crash"
 	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/jobs"
 )
 
 // ServeConfig holds the configuration for the HTTP server.
@@ -21,6 +22,30 @@ type ServeConfig struct {
 	Token        string
 	CORSOrigin   string
 	PollInterval time.Duration
+
+	// DedupeInterval is how often the duplicate report is rebuilt in the
+	// background; zero disables the scan
+	DedupeInterval time.Duration
+
+	// RetentionInterval is how often the retention policy is applied in
+	// the background; zero leaves it to td retention run
+	RetentionInterval time.Duration
+
+	// CompactInterval is how often log compaction runs in the background
+	// once configured; zero leaves it to td logs compact
+	CompactInterval time.Duration
+
+	// DeliveryInterval is how often queued webhooks, channel posts and
+	// notifications are sent and retried; zero sends them only after writes
+	DeliveryInterval time.Duration
+
+	// ExternalPollInterval is how often external dependencies (pull
+	// requests, CI runs, URLs) are checked; zero leaves it to td dep poll
+	ExternalPollInterval time.Duration
+
+	// Record, when set, receives every API request and its response as
+	// JSON lines for td replay; see record.go
+	Record io.Writer
 }
 
 // Server is the td serve HTTP server.
@@ -32,6 +57,17 @@ type Server struct {
 	mux       *http.ServeMux
 	sseHub    *SSEHub
 	http      *http.Server
+
+	// deprecations maps route patterns to their deprecation; see versioning.go
+	deprecations map[string]Deprecation
+
+	duplicates duplicateCache
+
+	// jobs runs background work; see jobs.go
+	jobs *jobs.Scheduler
+
+	// recorder writes requests to ServeConfig.Record; nil when not recording
+	recorder *Recorder
 }
 
 // NewServer creates a new Server, registers all routes, and sets up the
@@ -49,11 +85,18 @@ func NewServer(database *db.DB, baseDir, sessionID string, config ServeConfig) *
 		baseDir:   baseDir,
 		config:    config,
 		mux:       http.NewServeMux(),
+
+		deprecations: v1Deprecations,
 	}
 
 	// Initialize SSE hub (requires database for change_token polling)
 	if database != nil {
 		s.sseHub = NewSSEHub(database, pollInterval)
+		s.sseHub.baseDir = baseDir
+	}
+	s.jobs = s.newScheduler()
+	if config.Record != nil {
+		s.recorder = NewRecorder(config.Record)
 	}
 
 	s.registerRoutes()
@@ -66,11 +109,16 @@ func (s *Server) Handler() http.Handler {
 
 	// Wrap order: outermost first when applied, so we apply innermost first.
 	// Final order (outermost to innermost):
-	//   recovery -> logging -> CORS -> auth -> handler
+	//   request ID -> problem -> recovery -> logging -> compress -> CORS -> version -> auth -> record -> handler
+	h = s.recordMiddleware(h)
 	h = s.authMiddleware(h)
+	h = s.versionMiddleware(h)
 	h = s.corsMiddleware(h)
+	h = s.compressMiddleware(h)
 	h = s.loggingMiddleware(h)
 	h = s.recoveryMiddleware(h)
+	h = s.problemMiddleware(h)
+	h = s.requestIDMiddleware(h)
 
 	return h
 }
@@ -88,6 +136,7 @@ func (s *Server) ListenAndServe(ctx context.Context) error {
 	if s.sseHub != nil {
 		s.sseHub.Start(ctx)
 	}
+	s.jobs.Start(ctx)
 
 	s.http = &http.Server{
 		Handler:      s.Handler(),
@@ -110,6 +159,7 @@ func (s *Server) ListenAndServe(ctx context.Context) error {
 		if s.sseHub != nil {
 			s.sseHub.Stop()
 		}
+		s.jobs.Stop()
 		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 		return s.http.Shutdown(shutdownCtx)
@@ -127,11 +177,13 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	return s.http.Shutdown(ctx)
 }
 
-// StartBackground starts long-lived background processes (SSE polling loop).
+// StartBackground starts long-lived background processes (SSE polling loop
+// and scheduled jobs).
 func (s *Server) StartBackground(ctx context.Context) {
 	if s.sseHub != nil {
 		s.sseHub.Start(ctx)
 	}
+	s.jobs.Start(ctx)
 }
 
 // StopBackground stops long-lived background processes.
@@ -139,6 +191,7 @@ func (s *Server) StopBackground() {
 	if s.sseHub != nil {
 		s.sseHub.Stop()
 	}
+	s.jobs.Stop()
 }
 
 // ============================================================================
@@ -148,8 +201,9 @@ func (s *Server) StopBackground() {
 // registerRoutes registers all API routes. Read endpoints use real handlers;
 // write/mutation endpoints use real handlers where implemented, or placeholders.
 func (s *Server) registerRoutes() {
-	// Health (read)
+	// Health and API versions (read)
 	s.mux.HandleFunc("GET /health", s.handleHealth)
+	s.mux.HandleFunc("GET /versions", s.handleVersions)
 
 	// Monitor (read)
 	s.mux.HandleFunc("GET /v1/monitor", s.handleMonitor)
@@ -157,9 +211,23 @@ func (s *Server) registerRoutes() {
 	// Issues CRUD
 	s.mux.HandleFunc("GET /v1/issues", s.handleListIssues)
 	s.mux.HandleFunc("GET /v1/issues/{id}", s.handleGetIssue)
+	s.mux.HandleFunc("GET /v1/issues/export", s.handleExportIssues)
 	s.mux.HandleFunc("POST /v1/issues", s.handleCreateIssue)
+	s.mux.HandleFunc("POST /v1/issues/quick", s.handleQuickAddIssue)
 	s.mux.HandleFunc("PATCH /v1/issues/{id}", s.handleUpdateIssue)
 	s.mux.HandleFunc("DELETE /v1/issues/{id}", s.handleDeleteIssue)
+	s.mux.HandleFunc("POST /v1/issues/{id}/move", s.handleMoveIssue)
+	s.mux.HandleFunc("POST /v1/issues/{id}/clone", s.handleCloneIssue)
+	s.mux.HandleFunc("POST /v1/issues/clone", s.handleBulkClone)
+
+	// Project-wide rank
+	s.mux.HandleFunc("GET /v1/rank", s.handleListRank)
+	s.mux.HandleFunc("POST /v1/rank", s.handleMoveRank)
+	s.mux.HandleFunc("DELETE /v1/issues/{id}/rank", s.handleClearRank)
+
+	// Bulk import and export
+	s.mux.HandleFunc("POST /v1/import/csv", s.handleImportCSV)
+	s.mux.HandleFunc("GET /v1/export/sqlite", s.handleExportSQLite)
 
 	// Issue workflow transitions
 	s.mux.HandleFunc("POST /v1/issues/{id}/start", s.handleStart)
@@ -173,11 +241,31 @@ func (s *Server) registerRoutes() {
 
 	// Comments
 	s.mux.HandleFunc("POST /v1/issues/{id}/comments", s.handleAddComment)
+	s.mux.HandleFunc("PATCH /v1/issues/{id}/comments/{comment_id}", s.handleUpdateComment)
 	s.mux.HandleFunc("DELETE /v1/issues/{id}/comments/{comment_id}", s.handleDeleteComment)
 
+	// Confidential issues and their access grants
+	s.mux.HandleFunc("POST /v1/issues/{id}/confidential", s.handleSetConfidential)
+	s.mux.HandleFunc("GET /v1/issues/{id}/grants", s.handleListGrants)
+	s.mux.HandleFunc("POST /v1/issues/{id}/grants", s.handleAddGrant)
+	s.mux.HandleFunc("DELETE /v1/issues/{id}/grants/{grantee}", s.handleDeleteGrant)
+
+	// Revisions
+	s.mux.HandleFunc("GET /v1/issues/{id}/revisions", s.handleListRevisions)
+	s.mux.HandleFunc("POST /v1/issues/{id}/revisions/{revision_id}/revert", s.handleRevertRevision)
+
 	// Dependencies
 	s.mux.HandleFunc("POST /v1/issues/{id}/dependencies", s.handleAddDependency)
 	s.mux.HandleFunc("DELETE /v1/issues/{id}/dependencies/{dep_id}", s.handleDeleteDependency)
+	s.mux.HandleFunc("GET /v1/issues/{id}/external-dependencies", s.handleListExternalDependencies)
+	s.mux.HandleFunc("POST /v1/issues/{id}/external-dependencies", s.handleAddExternalDependency)
+	s.mux.HandleFunc("DELETE /v1/issues/{id}/external-dependencies/{ext_id}", s.handleDeleteExternalDependency)
+
+	// Relation and reviewer suggestions (read + accept/dismiss)
+	s.mux.HandleFunc("GET /v1/issues/{id}/suggestions", s.handleListSuggestions)
+	s.mux.HandleFunc("POST /v1/issues/{id}/suggestions/{other_id}/accept", s.handleAcceptSuggestion)
+	s.mux.HandleFunc("POST /v1/issues/{id}/suggestions/{other_id}/dismiss", s.handleDismissSuggestion)
+	s.mux.HandleFunc("GET /v1/issues/{id}/reviewers", s.handleSuggestReviewers)
 
 	// Focus
 	s.mux.HandleFunc("PUT /v1/focus", s.handleSetFocus)
@@ -191,14 +279,116 @@ func (s *Server) registerRoutes() {
 	s.mux.HandleFunc("POST /v1/boards/{id}/issues", s.handleSetBoardPosition)
 	s.mux.HandleFunc("DELETE /v1/boards/{id}/issues/{issue_id}", s.handleRemoveBoardPosition)
 
-	// Sessions (read)
+	// Plans (previewed bulk changes)
+	s.mux.HandleFunc("GET /v1/plans", s.handleListPlans)
+	s.mux.HandleFunc("GET /v1/plans/{id}", s.handleGetPlan)
+	s.mux.HandleFunc("POST /v1/plans", s.handleCreatePlan)
+	s.mux.HandleFunc("POST /v1/plans/{id}/apply", s.handleApplyPlan)
+	s.mux.HandleFunc("DELETE /v1/plans/{id}", s.handleDiscardPlan)
+
+	// Reminders
+	s.mux.HandleFunc("GET /v1/reminders", s.handleListReminders)
+	s.mux.HandleFunc("POST /v1/reminders", s.handleCreateReminder)
+	s.mux.HandleFunc("DELETE /v1/reminders/{id}", s.handleCancelReminder)
+
+	// Subscriptions (filtered webhooks and event streams)
+	s.mux.HandleFunc("GET /v1/subscriptions", s.handleListSubscriptions)
+	s.mux.HandleFunc("POST /v1/subscriptions", s.handleCreateSubscription)
+	s.mux.HandleFunc("DELETE /v1/subscriptions/{id}", s.handleDeleteSubscription)
+
+	// Triage inbox
+	s.mux.HandleFunc("GET /v1/inbox", s.handleInbox)
+	s.mux.HandleFunc("POST /v1/inbox", s.handleTriage)
+
+	// Decisions
+	s.mux.HandleFunc("GET /v1/decisions", s.handleListDecisions)
+	s.mux.HandleFunc("GET /v1/decisions/{id}", s.handleGetDecision)
+	s.mux.HandleFunc("POST /v1/decisions", s.handleCreateDecision)
+	s.mux.HandleFunc("PATCH /v1/decisions/{id}", s.handleUpdateDecision)
+	s.mux.HandleFunc("DELETE /v1/decisions/{id}", s.handleDeleteDecision)
+
+	// Estimation poker (blind votes, reveal, recorded estimates)
+	s.mux.HandleFunc("GET /v1/poker", s.handleListPokerRounds)
+	s.mux.HandleFunc("POST /v1/poker", s.handleCreatePokerRound)
+	s.mux.HandleFunc("GET /v1/poker/{id}", s.handleGetPokerRound)
+	s.mux.HandleFunc("POST /v1/poker/{id}/votes", s.handleCastPokerVote)
+	s.mux.HandleFunc("POST /v1/poker/{id}/reveal", s.handleRevealPokerRound)
+	s.mux.HandleFunc("POST /v1/poker/{id}/estimates", s.handleRecordPokerEstimate)
+	s.mux.HandleFunc("POST /v1/poker/{id}/close", s.handleClosePokerRound)
+
+	// Saved views
+	s.mux.HandleFunc("GET /v1/views", s.handleListViews)
+	s.mux.HandleFunc("GET /v1/views/{id}", s.handleGetView)
+	s.mux.HandleFunc("GET /v1/views/{id}/issues", s.handleViewIssues)
+	s.mux.HandleFunc("POST /v1/views", s.handleCreateView)
+	s.mux.HandleFunc("PATCH /v1/views/{id}", s.handleUpdateView)
+	s.mux.HandleFunc("DELETE /v1/views/{id}", s.handleDeleteView)
+
+	// Sessions
 	s.mux.HandleFunc("GET /v1/sessions", s.handleListSessions)
+	s.mux.HandleFunc("POST /v1/sessions/heartbeat", s.handleSessionHeartbeat)
+
+	// API tokens (issued with td token create)
+	s.mux.HandleFunc("GET /v1/tokens", s.handleListTokens)
+	s.mux.HandleFunc("DELETE /v1/tokens/{id}", s.handleRevokeToken)
+
+	// Reports (read)
+	s.mux.HandleFunc("GET /v1/reports/aging", s.handleAging)
+	s.mux.HandleFunc("GET /v1/reports/forecast", s.handleForecast)
+	s.mux.HandleFunc("GET /v1/reports/duplicates", s.handleDuplicates)
+	s.mux.HandleFunc("GET /v1/reports/rework", s.handleRework)
+	s.mux.HandleFunc("GET /v1/reports/overrides", s.handleOverrides)
+	s.mux.HandleFunc("GET /v1/reports/contributors", s.handleContributors)
+	s.mux.HandleFunc("GET /v1/reports/heatmap", s.handleHeatmap)
+
+	// Reports (write)
+	s.mux.HandleFunc("POST /v1/reports/duplicates/merge", s.handleMergeDuplicates)
+
+	// Admin repairs on behalf of other sessions (admin scope)
+	s.mux.HandleFunc("POST /v1/admin/issues/{id}/reassign", s.handleAdminReassign)
+	s.mux.HandleFunc("POST /v1/admin/issues/{id}/clear-reviewer", s.handleAdminClearReviewer)
+	s.mux.HandleFunc("GET /v1/admin/impersonations", s.handleImpersonations)
+
+	// Sprints (capacity read, retro read + write)
+	s.mux.HandleFunc("GET /v1/sprints/{id}/capacity", s.handleSprintCapacity)
+	s.mux.HandleFunc("GET /v1/sprints/{id}/retro", s.handleSprintRetro)
+	s.mux.HandleFunc("POST /v1/sprints/{id}/retro", s.handleAddRetroItem)
+	s.mux.HandleFunc("DELETE /v1/sprints/{id}/retro/{item_id}", s.handleDeleteRetroItem)
 
 	// Stats (read)
 	s.mux.HandleFunc("GET /v1/stats", s.handleStats)
+	s.mux.HandleFunc("GET /v1/summary", s.handleSummary)
+	s.mux.HandleFunc("GET /v1/labels/stats", s.handleLabelStats)
+	s.mux.HandleFunc("GET /v1/sprints/stats", s.handleSprintStats)
+
+	// TDQ validation and autocomplete metadata (read)
+	s.mux.HandleFunc("GET /v1/query/validate", s.handleValidateQuery)
+
+	// Calendar feed (read)
+	s.mux.HandleFunc("GET "+calendarPath, s.handleCalendar)
+
+	// Chat integrations (signature-authenticated)
+	s.mux.HandleFunc("POST "+integrationsPathPrefix+"{name}", s.handleIntegration)
+
+	// Monitor key remapping (read + write)
+	s.mux.HandleFunc("GET /v1/config/keymap", s.handleGetKeymap)
+	s.mux.HandleFunc("PUT /v1/config/keymap", s.handleSetKeymap)
+
+	// Background jobs (status read, manual trigger)
+	s.mux.HandleFunc("GET /v1/jobs", s.handleListJobs)
+	s.mux.HandleFunc("GET /v1/jobs/{name}", s.handleGetJob)
+	s.mux.HandleFunc("POST /v1/jobs/{name}/run", s.handleRunJob)
+
+	// Delivery outbox (status read, manual retry)
+	s.mux.HandleFunc("GET /v1/deliveries", s.handleListDeliveries)
+	s.mux.HandleFunc("GET /v1/deliveries/{id}", s.handleGetDelivery)
+	s.mux.HandleFunc("POST /v1/deliveries/{id}/retry", s.handleRetryDelivery)
 
 	// SSE events
 	s.mux.HandleFunc("GET /v1/events", s.handleEvents)
+	s.mux.HandleFunc("GET /v1/events/metrics", s.handleEventMetrics)
+
+	s.registerV2Routes()
 }
 
 // placeholder returns 501 Not Implemented for all unimplemented routes.
@@ -243,21 +433,35 @@ func (sr *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
 	return hj.Hijack()
 }
 
-// recoveryMiddleware catches panics, logs the stack trace, and returns a 500
-// error envelope.
+// recoveryMiddleware catches panics, logs the stack trace, saves a crash
+// dump, and returns a 500 error envelope whose details carry the crash ID.
+// http.ErrAbortHandler is passed on: it is how a handler aborts a response
+// on purpose.
 func (s *Server) recoveryMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
-			if rec := recover(); rec != nil {
-				stack := debug.Stack()
-				slog.Error("panic recovered",
-					"panic", rec,
-					"method", r.Method,
-					"path", r.URL.Path,
-					"stack", string(stack),
-				)
-				WriteError(w, ErrInternal, "internal server error", http.StatusInternalServerError)
+			rec := recover()
+			if rec == nil {
+				return
 			}
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+			_, route := s.mux.Handler(r)
+			report, _ := crash.Capture(s.baseDir, "serve", rec, map[string]string{
+				"method":     r.Method,
+				"route":      route,
+				"path":       r.URL.Path,
+				"request_id": RequestID(r.Context()),
+			})
+			requestLog(r).Error("panic recovered",
+				"crash_id", report.ID,
+				"panic", rec,
+				"method", r.Method,
+				"path", r.URL.Path,
+				"stack", report.Stack,
+			)
+			WriteErrorDetails(w, ErrInternal, "internal server error", map[string]interface{}{"crash_id": report.ID}, http.StatusInternalServerError)
 		}()
 		next.ServeHTTP(w, r)
 	})
@@ -270,7 +474,7 @@ func (s *Server) loggingMiddleware(next http.Handler) http.Handler {
 		start := time.Now()
 		sr := &statusRecorder{ResponseWriter: w, code: http.StatusOK}
 		next.ServeHTTP(sr, r)
-		slog.Info("req",
+		requestLog(r).Info("req",
 			"method", r.Method,
 			"path", r.URL.Path,
 			"status", sr.code,
@@ -303,7 +507,8 @@ func (s *Server) corsMiddleware(next http.Handler) http.Handler {
 
 		w.Header().Set("Access-Control-Allow-Origin", origin)
 		w.Header().Set("Access-Control-Allow-Methods", "GET,POST,PATCH,PUT,DELETE,OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type,Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type,Authorization,If-None-Match,"+RequestIDHeader)
+		w.Header().Set("Access-Control-Expose-Headers", "ETag,Deprecation,Sunset,Link,"+APIVersionHeader+","+RequestIDHeader)
 		w.Header().Set("Access-Control-Max-Age", "3600")
 
 		if r.Method == http.MethodOptions {
@@ -316,9 +521,16 @@ func (s *Server) corsMiddleware(next http.Handler) http.Handler {
 }
 
 // authMiddleware validates the Bearer token when the server is configured with
-// a token. GET /health is always exempt from authentication.
+// a token. GET /health is always exempt from authentication. API tokens from
+// td token create are accepted with or without a server token, and make the
+// request act as the token's session.
 func (s *Server) authMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token, ok := sessionBearer(r); ok {
+			s.serveWithAPIToken(w, r, next, token)
+			return
+		}
+
 		// No token configured - pass through
 		if s.config.Token == "" {
 			next.ServeHTTP(w, r)
@@ -331,6 +543,28 @@ func (s *Server) authMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
+		// Chat platforms authenticate with per-integration signatures
+		if s.signedIntegration(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Calendar subscriptions can only carry the token in the URL
+		if r.Method == http.MethodGet && r.URL.Path == calendarPath && r.URL.Query().Has("token") {
+			if r.URL.Query().Get("token") != s.config.Token {
+				WriteError(w, ErrUnauthorized, "invalid token", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Share links carry their own signed token, checked by the handler
+		if isShareRequest(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
 			WriteError(w, ErrUnauthorized, "missing authorization header", http.StatusUnauthorized)
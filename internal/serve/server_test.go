@@ -4,8 +4,12 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/marcus/td/internal/crash"
 )
 
 // newTestServer creates a Server with the given config for testing.
@@ -336,6 +340,72 @@ func TestRecoveryMiddleware_CatchesPanic(t *testing.T) {
 	}
 }
 
+func TestRecoveryMiddleware_SavesCrashDump(t *testing.T) {
+	t.Setenv("TD_CRASH_DUMPS", "1")
+	baseDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(baseDir, ".todos"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	srv := NewServer(nil, baseDir, "ses_test123", ServeConfig{})
+	panicMux := http.NewServeMux()
+	panicMux.HandleFunc("GET /panic/{id}", func(w http.ResponseWriter, r *http.Request) {
+		panic("dump me")
+	})
+	srv.mux = panicMux
+
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/panic/td-abc")
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	defer resp.Body.Close()
+	var env Envelope
+	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	details, _ := env.Error.Details.(map[string]interface{})
+	id, _ := details["crash_id"].(string)
+	if id == "" {
+		t.Fatalf("error.details = %v, want a crash_id", env.Error.Details)
+	}
+
+	report, err := crash.Load(baseDir, id)
+	if err != nil {
+		t.Fatalf("load crash dump: %v", err)
+	}
+	if report.Panic != "dump me" || report.Context["route"] != "GET /panic/{id}" || report.Context["path"] != "/panic/td-abc" {
+		t.Errorf("report = %+v", report)
+	}
+	if report.Context["request_id"] != resp.Header.Get(RequestIDHeader) {
+		t.Errorf("request_id = %q, want %q", report.Context["request_id"], resp.Header.Get(RequestIDHeader))
+	}
+
+	// The server keeps serving after the panic
+	again, err := http.Get(ts.URL + "/panic/td-def")
+	if err != nil {
+		t.Fatalf("second GET: %v", err)
+	}
+	again.Body.Close()
+	if again.StatusCode != http.StatusInternalServerError {
+		t.Errorf("second status = %d, want 500", again.StatusCode)
+	}
+}
+
+func TestRecoveryMiddleware_PassesAbortHandler(t *testing.T) {
+	srv := newTestServer(ServeConfig{})
+	h := srv.recoveryMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+	defer func() {
+		if rec := recover(); rec != http.ErrAbortHandler {
+			t.Errorf("recovered %v, want http.ErrAbortHandler passed on", rec)
+		}
+	}()
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+}
+
 // ============================================================================
 // Server Struct Tests
 // ============================================================================
@@ -385,6 +455,7 @@ func TestAllRoutesRegistered(t *testing.T) {
 		{"GET", "/v1/boards"},
 		{"GET", "/v1/boards/b1"},
 		{"GET", "/v1/sessions"},
+		{"GET", "/v1/tokens"},
 		{"GET", "/v1/stats"},
 		// Issue write endpoints
 		{"POST", "/v1/issues"},
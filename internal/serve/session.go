@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/crash"
 	"github.com/marcus/td/internal/db"
 )
 
@@ -32,7 +34,7 @@ func GetOrCreateWebSession(database *db.DB) (*db.SessionRow, error) {
 
 	if row != nil {
 		// Found existing session - bump activity
-		now := time.Now()
+		now := clock.Now()
 		if err := database.UpdateSessionActivity(row.ID, now); err != nil {
 			return nil, fmt.Errorf("bump web session activity: %w", err)
 		}
@@ -48,7 +50,7 @@ func GetOrCreateWebSession(database *db.DB) (*db.SessionRow, error) {
 	// Replace srv_ prefix with ses_ for session IDs
 	id = "ses_" + id[len(instancePrefix):]
 
-	now := time.Now()
+	now := clock.Now()
 	row = &db.SessionRow{
 		ID:           id,
 		Name:         webSessionName,
@@ -68,7 +70,7 @@ func GetOrCreateWebSession(database *db.DB) (*db.SessionRow, error) {
 
 // BumpSessionActivity updates the last_activity timestamp for a session.
 func BumpSessionActivity(database *db.DB, sessionID string) error {
-	return database.UpdateSessionActivity(sessionID, time.Now())
+	return database.UpdateSessionActivity(sessionID, clock.Now())
 }
 
 // StartSessionHeartbeat launches a goroutine that periodically bumps the
@@ -77,6 +79,7 @@ func BumpSessionActivity(database *db.DB, sessionID string) error {
 // heartbeats are best-effort.
 func StartSessionHeartbeat(ctx context.Context, database *db.DB, sessionID string) {
 	go func() {
+		defer crash.Recover("", "serve", map[string]string{"task": "heartbeat"})
 		ticker := time.NewTicker(heartbeatInterval)
 		defer ticker.Stop()
 
@@ -86,7 +89,7 @@ func StartSessionHeartbeat(ctx context.Context, database *db.DB, sessionID strin
 				return
 			case <-ticker.C:
 				// Best-effort: ignore errors from heartbeat bumps
-				_ = database.UpdateSessionActivity(sessionID, time.Now())
+				_ = database.UpdateSessionActivity(sessionID, clock.Now())
 			}
 		}
 	}()
@@ -6,12 +6,20 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/crash"
 	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/jobs"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/query"
 	"github.com/marcus/td/internal/session"
+	"github.com/marcus/td/internal/subscription"
 	tdsync "github.com/marcus/td/internal/sync"
 	"github.com/marcus/td/internal/syncclient"
 	"github.com/marcus/td/internal/syncconfig"
@@ -24,32 +32,60 @@ import (
 // SSEEvent represents a single Server-Sent Event.
 type SSEEvent struct {
 	ID    string // change_token used as event ID
-	Event string // "refresh" or "ping"
+	Event string // "refresh", "ping", "reminder" or "job_failed"
 	Data  string // JSON payload
 }
 
-// refreshData is the JSON payload for a refresh event.
+// refreshData is the JSON payload for a refresh event. Collections lists
+// the collections whose change token moved; it is empty when only data
+// outside the tracked collections changed.
 type refreshData struct {
-	ChangeToken string `json:"change_token"`
-	Timestamp   string `json:"timestamp"`
+	ChangeToken  string            `json:"change_token"`
+	ChangeTokens map[string]string `json:"change_tokens,omitempty"`
+	Collections  []string          `json:"collections"`
+	RequestID    string            `json:"request_id,omitempty"` // the API request whose write caused the refresh
+	IssueIDs     []string          `json:"issue_ids,omitempty"`  // changed issues matching the client's ?query=
+	Timestamp    string            `json:"timestamp"`
 }
 
 // pingData is the JSON payload for a ping event.
 type pingData struct {
-	ChangeToken string `json:"change_token"`
+	ChangeToken  string            `json:"change_token"`
+	ChangeTokens map[string]string `json:"change_tokens,omitempty"`
+}
+
+// reminderData is the JSON payload for a reminder event
+type reminderData struct {
+	Reminder ReminderDTO `json:"reminder"`
+}
+
+// jobFailedData is the JSON payload for a job_failed event
+type jobFailedData struct {
+	Job jobs.Status `json:"job"`
 }
 
 // ============================================================================
 // SSE Hub
 // ============================================================================
 
-// SSEHub manages connected SSE clients and broadcasts events.
+// SSEHub manages connected SSE clients and broadcasts events. Each client
+// has its own bounded queue (see sse_client.go), so broadcasting never
+// waits on a client and a stalled one is disconnected rather than holding
+// up the rest.
 type SSEHub struct {
 	db           *db.DB
 	pollInterval time.Duration
+	baseDir      string // where crash dumps go; empty saves none
 
 	mu      sync.Mutex
-	clients map[chan SSEEvent]struct{}
+	clients map[chan SSEEvent]*sseClient // keyed by the client's out channel
+	nextID  uint64
+	stats   sseStats
+
+	// tokenMu guards the tokens from the last broadcast
+	tokenMu    sync.Mutex
+	lastToken  string
+	lastTokens map[string]string
 
 	cancel context.CancelFunc
 	done   chan struct{}
@@ -60,7 +96,7 @@ func NewSSEHub(database *db.DB, pollInterval time.Duration) *SSEHub {
 	return &SSEHub{
 		db:           database,
 		pollInterval: pollInterval,
-		clients:      make(map[chan SSEEvent]struct{}),
+		clients:      make(map[chan SSEEvent]*sseClient),
 		done:         make(chan struct{}),
 	}
 }
@@ -84,56 +120,175 @@ func (h *SSEHub) Stop() {
 
 // register adds a client channel and returns it.
 func (h *SSEHub) register() chan SSEEvent {
-	ch := make(chan SSEEvent, 16) // buffered to avoid blocking broadcasts
+	return h.registerFiltered(nil)
+}
+
+// registerFiltered adds a client that only receives refreshes for changes
+// to issues filter matches; a nil filter receives every refresh. Events
+// arrive on the returned channel, which is closed when the client is
+// unregistered or dropped as too slow.
+func (h *SSEHub) registerFiltered(filter *query.Query) chan SSEEvent {
 	h.mu.Lock()
-	h.clients[ch] = struct{}{}
+	h.nextID++
+	c := newSSEClient(h.nextID, filter, &h.stats, clock.Now())
+	h.clients[c.out] = c
+	n := len(h.clients)
 	h.mu.Unlock()
-	slog.Debug("sse: client registered", "clients", h.clientCount())
-	return ch
+	slog.Debug("sse: client registered", "client", c.id, "clients", n)
+	return c.out
 }
 
-// unregister removes a client channel and closes it.
+// unregister removes a client; its channel is closed once its queue pump
+// has stopped.
 func (h *SSEHub) unregister(ch chan SSEEvent) {
 	h.mu.Lock()
-	if _, ok := h.clients[ch]; ok {
+	c, ok := h.clients[ch]
+	if ok {
 		delete(h.clients, ch)
-		close(ch)
+		c.close()
 	}
+	n := len(h.clients)
 	h.mu.Unlock()
-	slog.Debug("sse: client unregistered", "clients", h.clientCount())
+	if ok {
+		slog.Debug("sse: client unregistered", "client", c.id, "clients", n)
+	}
 }
 
-// clientCount returns the number of connected clients (for logging).
-func (h *SSEHub) clientCount() int {
-	// Caller must NOT hold the lock if calling from outside locked section.
-	// This is only called from within locked sections or for logging after unlock.
-	return len(h.clients)
+// Subscribe registers a client outside an HTTP request. It receives events
+// until the returned function is called.
+func (h *SSEHub) Subscribe() (<-chan SSEEvent, func()) {
+	ch := h.register()
+	return ch, func() { h.unregister(ch) }
 }
 
-// Broadcast sends a refresh event to all connected clients with the given
-// change token.
-func (h *SSEHub) Broadcast(changeToken string) {
-	data, _ := json.Marshal(refreshData{
-		ChangeToken: changeToken,
-		Timestamp:   time.Now().UTC().Format(time.RFC3339),
-	})
+// deliver queues ev for c and disconnects c when it has stopped reading.
+// Caller must hold h.mu.
+func (h *SSEHub) deliver(c *sseClient, ev SSEEvent, now time.Time) {
+	dropped, stalled := c.push(ev, now)
+	if dropped {
+		slog.Debug("sse: dropped event for slow client", "client", c.id, "event", ev.Event)
+	}
+	if stalled {
+		h.dropSlow(c)
+	}
+}
+
+// dropSlow disconnects a client whose queue has stayed full. Caller must
+// hold h.mu.
+func (h *SSEHub) dropSlow(c *sseClient) {
+	delete(h.clients, c.out)
+	c.close()
+	h.stats.slowDisconnects.Add(1)
+	slog.Warn("sse: disconnecting slow client", "client", c.id, "connected_at", c.connectedAt)
+}
 
+// Metrics reports delivery totals and each connected client, oldest
+// connection first.
+func (h *SSEHub) Metrics() SSEMetrics {
+	h.mu.Lock()
+	clients := make([]*sseClient, 0, len(h.clients))
+	for _, c := range h.clients {
+		clients = append(clients, c)
+	}
+	h.mu.Unlock()
+	sort.Slice(clients, func(i, j int) bool { return clients[i].id < clients[j].id })
+
+	m := SSEMetrics{
+		QueueSize:       sseQueueSize,
+		Delivered:       h.stats.delivered.Load(),
+		Dropped:         h.stats.dropped.Load(),
+		SlowDisconnects: h.stats.slowDisconnects.Load(),
+		Clients:         make([]SSEClientMetrics, 0, len(clients)),
+	}
+	for _, c := range clients {
+		m.Clients = append(m.Clients, c.metrics())
+	}
+	return m
+}
+
+// Broadcast sends a refresh event to all connected clients with the given
+// change token and the collections that changed since the last broadcast.
+// requestID names the API request that caused the change; it is empty for
+// changes found by polling. Clients with a query filter only get the
+// refresh when a changed issue matches it.
+func (h *SSEHub) Broadcast(changeToken, requestID string) {
+	tokens, collections, since := h.advanceTokens(changeToken)
+	refresh := refreshData{
+		ChangeToken:  changeToken,
+		ChangeTokens: tokens,
+		Collections:  collections,
+		RequestID:    requestID,
+		Timestamp:    clock.Now().UTC().Format(time.RFC3339),
+	}
 	event := SSEEvent{
 		ID:    changeToken,
 		Event: "refresh",
-		Data:  string(data),
+		Data:  marshalJSON(refresh),
 	}
 
+	// Filters are evaluated outside the client lock, which only guards
+	// delivery
 	h.mu.Lock()
-	for ch := range h.clients {
-		select {
-		case ch <- event:
-		default:
-			// Client too slow, skip this event (next poll or broadcast will catch up)
-			slog.Debug("sse: dropped event for slow client")
+	filters := make(map[chan SSEEvent]*query.Query)
+	for ch, c := range h.clients {
+		if c.filter != nil {
+			filters[ch] = c.filter
 		}
 	}
 	h.mu.Unlock()
+
+	filtered := make(map[chan SSEEvent]*SSEEvent, len(filters))
+	if len(filters) > 0 {
+		changed, err := h.db.ChangedIssues(since)
+		if err != nil {
+			slog.Debug("sse: changed issues error", "err", err)
+		}
+		for ch, filter := range filters {
+			filtered[ch] = h.filterRefresh(filter, refresh, changed, err)
+		}
+	}
+
+	now := clock.Now()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch, c := range h.clients {
+		ev := &event
+		if c.filter != nil {
+			if fev, ok := filtered[ch]; ok {
+				ev = fev
+			}
+			if ev == nil {
+				continue
+			}
+		}
+		// A slow client that misses a refresh catches up on the next one
+		h.deliver(c, *ev, now)
+	}
+}
+
+// filterRefresh returns the refresh event for a client filtered by query,
+// naming the changed issues it matches, or nil when none do. When the
+// changes cannot be worked out the client gets the refresh unfiltered
+// rather than miss it.
+func (h *SSEHub) filterRefresh(filter *query.Query, refresh refreshData, changed []models.Issue, changedErr error) *SSEEvent {
+	if changedErr == nil {
+		matches, err := query.Match(h.db, filter, "", changed)
+		if err != nil {
+			slog.Debug("sse: match query filter error", "err", err)
+		} else {
+			if len(matches) == 0 {
+				return nil
+			}
+			seen := make(map[string]bool)
+			for _, issue := range matches {
+				if !seen[issue.ID] {
+					seen[issue.ID] = true
+					refresh.IssueIDs = append(refresh.IssueIDs, issue.ID)
+				}
+			}
+		}
+	}
+	return &SSEEvent{ID: refresh.ChangeToken, Event: "refresh", Data: marshalJSON(refresh)}
 }
 
 // run is the background goroutine that polls the change_token and sends pings.
@@ -146,7 +301,8 @@ func (h *SSEHub) run(ctx context.Context) {
 	pingTicker := time.NewTicker(30 * time.Second)
 	defer pingTicker.Stop()
 
-	lastToken, _ := h.db.GetChangeToken()
+	token, _ := h.db.GetChangeToken()
+	h.advanceTokens(token)
 
 	for {
 		select {
@@ -155,22 +311,15 @@ func (h *SSEHub) run(ctx context.Context) {
 			return
 
 		case <-pollTicker.C:
-			token, err := h.db.GetChangeToken()
-			if err != nil {
-				slog.Debug("sse: poll change_token error", "err", err)
-				continue
-			}
-			if token != lastToken {
-				lastToken = token
-				h.Broadcast(token)
-			}
+			h.poll()
 
 		case <-pingTicker.C:
 			token, _ := h.db.GetChangeToken()
-			lastToken = token
+			tokens, _ := h.db.GetChangeTokens()
 
 			data, _ := json.Marshal(pingData{
-				ChangeToken: token,
+				ChangeToken:  token,
+				ChangeTokens: tokens,
 			})
 
 			event := SSEEvent{
@@ -179,24 +328,124 @@ func (h *SSEHub) run(ctx context.Context) {
 				Data:  string(data),
 			}
 
-			h.mu.Lock()
-			for ch := range h.clients {
-				select {
-				case ch <- event:
-				default:
-				}
-			}
-			h.mu.Unlock()
+			h.ping(event)
 		}
 	}
 }
 
-// closeAllClients closes all registered client channels.
+// poll broadcasts a change when the change_token moved and fires due
+// reminders. A panic here is recovered so the next tick polls again.
+func (h *SSEHub) poll() {
+	defer crash.Recover(h.baseDir, "serve", map[string]string{"task": "sse-poll"})
+
+	token, err := h.db.GetChangeToken()
+	if err != nil {
+		slog.Debug("sse: poll change_token error", "err", err)
+		return
+	}
+	h.tokenMu.Lock()
+	changed := token != h.lastToken
+	h.tokenMu.Unlock()
+	if changed {
+		h.Broadcast(token, "")
+	}
+	h.fireReminders(token)
+}
+
+// ping sends a keepalive to clients with nothing queued; a client with
+// events waiting will hear from the server anyway. It is also where a
+// client that stalled during a quiet spell is noticed.
+func (h *SSEHub) ping(event SSEEvent) {
+	now := clock.Now()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, c := range h.clients {
+		if c.idle() {
+			h.deliver(c, event, now)
+		} else if c.stalled(now) {
+			h.dropSlow(c)
+		}
+	}
+}
+
+// fireReminders delivers reminders that have come due as "reminder" events.
+// Reminders only fire while a client is listening, so one set while nobody
+// is connected waits for the next client (or a running monitor).
+func (h *SSEHub) fireReminders(token string) {
+	h.mu.Lock()
+	listening := len(h.clients) > 0
+	h.mu.Unlock()
+	if !listening {
+		return
+	}
+
+	fired, err := h.db.FireDueReminders(clock.Now())
+	if err != nil {
+		slog.Debug("sse: fire reminders error", "err", err)
+		return
+	}
+	for i := range fired {
+		h.send(SSEEvent{
+			ID:    token,
+			Event: "reminder",
+			Data:  marshalJSON(reminderData{Reminder: ReminderToDTO(&fired[i])}),
+		})
+	}
+}
+
+// jobFailed tells connected clients a background job failed
+func (h *SSEHub) jobFailed(st jobs.Status) {
+	h.tokenMu.Lock()
+	token := h.lastToken
+	h.tokenMu.Unlock()
+	h.send(SSEEvent{
+		ID:    token,
+		Event: "job_failed",
+		Data:  marshalJSON(jobFailedData{Job: st}),
+	})
+}
+
+// send queues event for every client
+func (h *SSEHub) send(event SSEEvent) {
+	now := clock.Now()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, c := range h.clients {
+		h.deliver(c, event, now)
+	}
+}
+
+// advanceTokens records token and the current per-collection tokens as the
+// latest broadcast state. It returns those tokens, the collections whose
+// token differs from the previous state, and the previous change token.
+func (h *SSEHub) advanceTokens(token string) (map[string]string, []string, string) {
+	tokens, err := h.db.GetChangeTokens()
+	if err != nil {
+		slog.Debug("sse: get change_tokens error", "err", err)
+	}
+
+	h.tokenMu.Lock()
+	defer h.tokenMu.Unlock()
+	collections := []string{}
+	for _, c := range db.ChangeCollections() {
+		if tokens != nil && h.lastTokens != nil && tokens[c] != h.lastTokens[c] {
+			collections = append(collections, c)
+		}
+	}
+	prev := h.lastToken
+	h.lastToken = token
+	if tokens != nil {
+		h.lastTokens = tokens
+	}
+	return tokens, collections, prev
+}
+
+// closeAllClients closes all registered clients.
 func (h *SSEHub) closeAllClients() {
 	h.mu.Lock()
 	defer h.mu.Unlock()
-	for ch := range h.clients {
-		close(ch)
+	for ch, c := range h.clients {
+		c.close()
 		delete(h.clients, ch)
 	}
 }
@@ -206,26 +455,35 @@ func (h *SSEHub) closeAllClients() {
 // ============================================================================
 
 // handleEvents is the HTTP handler for GET /v1/events (SSE endpoint).
+// Optional ?query= (TDQ), ?label=, ?epic= and ?subscription= filters limit
+// refreshes to changes touching issues that match all of them, before or
+// after the change.
 func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
 	// Verify streaming support
-	flusher, ok := w.(http.Flusher)
-	if !ok {
+	if _, ok := w.(http.Flusher); !ok {
 		WriteError(w, ErrInternal, "streaming not supported", http.StatusInternalServerError)
 		return
 	}
 
+	filter, ok := s.eventFilter(w, r)
+	if !ok {
+		return
+	}
+
 	// Set SSE headers
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
 
-	// Disable response buffering at the http.Server level by wrapping
-	// with an http.ResponseController to override write deadline for this
-	// long-lived connection.
+	// The server's write timeout would end this long-lived connection, so
+	// each event gets its own deadline instead; a reader that stops
+	// reading then fails the write rather than parking the handler.
 	rc := http.NewResponseController(w)
-	// Remove write deadline for SSE connections
-	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
-		slog.Debug("sse: failed to clear write deadline", "err", err)
+	write := func(event SSEEvent) error {
+		if err := rc.SetWriteDeadline(clock.Now().Add(sseWriteTimeout)); err != nil {
+			slog.Debug("sse: failed to set write deadline", "err", err)
+		}
+		return writeSSEEvent(w, rc, event)
 	}
 
 	// Register this client with the hub
@@ -234,30 +492,36 @@ func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
 		WriteError(w, ErrInternal, "event stream unavailable", http.StatusInternalServerError)
 		return
 	}
-	ch := hub.register()
+	ch := hub.registerFiltered(filter)
 	defer hub.unregister(ch)
 
 	// Check Last-Event-ID for reconnect support
 	lastEventID := r.Header.Get("Last-Event-ID")
 	currentToken, _ := s.db.GetChangeToken()
+	currentTokens, _ := s.db.GetChangeTokens()
 
 	if lastEventID != "" && lastEventID != currentToken {
-		// Client reconnecting with a stale token — send immediate refresh
-		writeSSEEvent(w, flusher, SSEEvent{
+		// Client reconnecting with a stale token — send immediate refresh.
+		// The hub cannot know what this client missed, so every collection
+		// is reported as changed.
+		_ = write(SSEEvent{
 			ID:    currentToken,
 			Event: "refresh",
 			Data: marshalJSON(refreshData{
-				ChangeToken: currentToken,
-				Timestamp:   time.Now().UTC().Format(time.RFC3339),
+				ChangeToken:  currentToken,
+				ChangeTokens: currentTokens,
+				Collections:  db.ChangeCollections(),
+				Timestamp:    clock.Now().UTC().Format(time.RFC3339),
 			}),
 		})
 	} else {
 		// New connection — send initial ping so client knows it's connected
-		writeSSEEvent(w, flusher, SSEEvent{
+		_ = write(SSEEvent{
 			ID:    currentToken,
 			Event: "ping",
 			Data: marshalJSON(pingData{
-				ChangeToken: currentToken,
+				ChangeToken:  currentToken,
+				ChangeTokens: currentTokens,
 			}),
 		})
 	}
@@ -270,18 +534,75 @@ func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
 			return
 		case event, ok := <-ch:
 			if !ok {
-				// Channel closed (hub shutting down)
+				// Channel closed (hub shutting down, or this client fell
+				// too far behind and was dropped)
+				return
+			}
+			if err := write(event); err != nil {
+				slog.Debug("sse: write failed, closing stream", "err", err)
 				return
 			}
-			writeSSEEvent(w, flusher, event)
 		}
 	}
 }
 
+// eventFilter builds the filter for an event stream from its query params,
+// writing the error response and returning false when they are invalid.
+// The filter is nil when there are none.
+func (s *Server) eventFilter(w http.ResponseWriter, r *http.Request) (*query.Query, bool) {
+	q := r.URL.Query()
+	subs := []models.Subscription{{Label: q.Get("label"), Epic: q.Get("epic")}}
+	if raw := q.Get("query"); raw != "" {
+		parsed, err := query.Parse(raw)
+		if err == nil {
+			if verrs := parsed.Validate(); len(verrs) > 0 {
+				err = verrs[0]
+			}
+		}
+		if err != nil {
+			WriteValidation(w, []FieldError{{Field: "query", Rule: "tdq", Value: raw, Message: "invalid TDQ query: " + err.Error()}})
+			return nil, false
+		}
+		subs[0].Query = raw
+	}
+	if id := q.Get("subscription"); id != "" {
+		sub, err := config.GetSubscription(s.baseDir, id)
+		if err != nil || sub == nil {
+			WriteError(w, ErrNotFound, fmt.Sprintf("subscription not found: %s", id), http.StatusNotFound)
+			return nil, false
+		}
+		subs = append(subs, *sub)
+	}
+
+	filter, err := subscription.Query(subs...)
+	if err != nil {
+		WriteError(w, ErrValidation, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	return filter, true
+}
+
 // writeSSEEvent writes a single SSE event to the response writer and flushes.
-func writeSSEEvent(w http.ResponseWriter, flusher http.Flusher, event SSEEvent) {
-	fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Event, event.Data)
-	flusher.Flush()
+func writeSSEEvent(w http.ResponseWriter, rc *http.ResponseController, event SSEEvent) error {
+	if _, err := fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Event, event.Data); err != nil {
+		return err
+	}
+	return rc.Flush()
+}
+
+// ============================================================================
+// GET /v1/events/metrics
+// ============================================================================
+
+// handleEventMetrics reports event stream delivery: totals of events
+// delivered and dropped and of clients disconnected as too slow, and each
+// connected client's queue.
+func (s *Server) handleEventMetrics(w http.ResponseWriter, r *http.Request) {
+	if s.sseHub == nil {
+		WriteError(w, ErrInternal, "event stream unavailable", http.StatusInternalServerError)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"events": s.sseHub.Metrics()}, http.StatusOK)
 }
 
 // marshalJSON is a helper that marshals to JSON, returning "{}" on error.
@@ -299,22 +620,29 @@ func marshalJSON(v interface{}) string {
 
 // NotifyChange is called after successful write operations. It:
 // 1. Gets the current change_token
-// 2. Broadcasts a refresh event to all SSE clients
+// 2. Broadcasts a refresh event, naming the changed collections and the
+// request that made the write, to all SSE clients
 // 3. Triggers a debounced autosync
-func (s *Server) NotifyChange() {
+func (s *Server) NotifyChange(r *http.Request) {
 	token, err := s.db.GetChangeToken()
 	if err != nil {
-		slog.Debug("serve: NotifyChange get token", "err", err)
+		requestLog(r).Debug("serve: NotifyChange get token", "err", err)
 		return
 	}
 
 	// Broadcast to SSE clients
 	if s.sseHub != nil {
-		s.sseHub.Broadcast(token)
+		s.sseHub.Broadcast(token, RequestID(r.Context()))
 	}
 
+	// Send webhooks and channel posts for the change
+	s.triggerDeliveries()
+
 	// Trigger debounced autosync
-	go s.autoSyncDebounced()
+	go func() {
+		defer crash.Recover(s.baseDir, "serve", map[string]string{"task": "autosync"})
+		s.autoSyncDebounced()
+	}()
 }
 
 // ============================================================================
@@ -0,0 +1,202 @@
+package serve
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/marcus/td/internal/query"
+)
+
+// sseQueueSize is how many events a client can have waiting before the
+// oldest are dropped
+const sseQueueSize = 32
+
+// sseSlowClientTimeout is how long a client's queue may stay full without
+// the client taking an event before it is disconnected. It reconnects with
+// Last-Event-ID and gets a fresh refresh, so nothing is lost for good.
+const sseSlowClientTimeout = 30 * time.Second
+
+// sseWriteTimeout bounds writing one event to a connection, so a stalled
+// reader errors out instead of holding its handler forever
+const sseWriteTimeout = 10 * time.Second
+
+// eventPriority ranks events for eviction from a full queue. Pings are
+// only keepalives and refreshes are superseded by the next one, but a
+// reminder or job alert is delivered once, so it is kept longest.
+func eventPriority(event string) int {
+	switch event {
+	case "ping":
+		return 0
+	case "refresh":
+		return 1
+	default:
+		return 2
+	}
+}
+
+// sseStats counts deliveries across all clients, including ones that
+// have gone
+type sseStats struct {
+	delivered       atomic.Uint64
+	dropped         atomic.Uint64
+	slowDisconnects atomic.Uint64
+}
+
+// sseClient is one subscriber. Broadcasts push onto its queue without
+// blocking; its pump goroutine feeds the queue to out at the pace the
+// subscriber reads, so a stalled reader only ever holds up itself.
+type sseClient struct {
+	id          uint64
+	filter      *query.Query // ?query= filter, nil for none
+	connectedAt time.Time
+	out         chan SSEEvent
+	done        chan struct{}
+	wake        chan struct{}
+	stats       *sseStats
+
+	mu        sync.Mutex
+	queue     []SSEEvent
+	fullSince time.Time // zero unless the queue is full and unread
+	delivered uint64
+	dropped   uint64
+	closeOnce sync.Once
+}
+
+func newSSEClient(id uint64, filter *query.Query, stats *sseStats, now time.Time) *sseClient {
+	c := &sseClient{
+		id:          id,
+		filter:      filter,
+		connectedAt: now,
+		out:         make(chan SSEEvent),
+		done:        make(chan struct{}),
+		wake:        make(chan struct{}, 1),
+		stats:       stats,
+	}
+	go c.pump()
+	return c
+}
+
+// push queues ev. On a full queue the oldest event of the lowest priority
+// present is dropped to make room, or ev itself when everything queued
+// outranks it. stalled reports that the queue has been full for longer
+// than sseSlowClientTimeout.
+func (c *sseClient) push(ev SSEEvent, now time.Time) (dropped, stalled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.queue) >= sseQueueSize {
+		dropped = true
+		c.dropped++
+		c.stats.dropped.Add(1)
+		if c.fullSince.IsZero() {
+			c.fullSince = now
+		}
+		victim := -1
+		for i, q := range c.queue {
+			if victim < 0 || eventPriority(q.Event) < eventPriority(c.queue[victim].Event) {
+				victim = i
+			}
+		}
+		if eventPriority(c.queue[victim].Event) > eventPriority(ev.Event) {
+			return dropped, c.stalledLocked(now)
+		}
+		c.queue = append(c.queue[:victim], c.queue[victim+1:]...)
+	}
+	c.queue = append(c.queue, ev)
+
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+	return dropped, c.stalledLocked(now)
+}
+
+// idle reports whether nothing is waiting to be sent
+func (c *sseClient) idle() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.queue) == 0
+}
+
+// stalled reports whether the queue has been full and unread for longer
+// than sseSlowClientTimeout
+func (c *sseClient) stalled(now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stalledLocked(now)
+}
+
+func (c *sseClient) stalledLocked(now time.Time) bool {
+	return !c.fullSince.IsZero() && now.Sub(c.fullSince) > sseSlowClientTimeout
+}
+
+// pump moves queued events to out, oldest first, until the client is
+// closed, then closes out
+func (c *sseClient) pump() {
+	defer close(c.out)
+	for {
+		c.mu.Lock()
+		if len(c.queue) == 0 {
+			c.mu.Unlock()
+			select {
+			case <-c.done:
+				return
+			case <-c.wake:
+				continue
+			}
+		}
+		ev := c.queue[0]
+		c.queue = c.queue[1:]
+		c.mu.Unlock()
+
+		select {
+		case <-c.done:
+			return
+		case c.out <- ev:
+		}
+		c.mu.Lock()
+		c.delivered++
+		c.fullSince = time.Time{}
+		c.mu.Unlock()
+		c.stats.delivered.Add(1)
+	}
+}
+
+// close stops the pump; out is closed once it has exited
+func (c *sseClient) close() {
+	c.closeOnce.Do(func() { close(c.done) })
+}
+
+// SSEClientMetrics describes one connected client
+type SSEClientMetrics struct {
+	ID          uint64    `json:"id"`
+	ConnectedAt time.Time `json:"connected_at"`
+	Filtered    bool      `json:"filtered"`
+	Queued      int       `json:"queued"`
+	Delivered   uint64    `json:"delivered"`
+	Dropped     uint64    `json:"dropped"`
+}
+
+func (c *sseClient) metrics() SSEClientMetrics {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return SSEClientMetrics{
+		ID:          c.id,
+		ConnectedAt: c.connectedAt,
+		Filtered:    c.filter != nil,
+		Queued:      len(c.queue),
+		Delivered:   c.delivered,
+		Dropped:     c.dropped,
+	}
+}
+
+// SSEMetrics describes the event hub: totals since the server started and
+// each connected client
+type SSEMetrics struct {
+	QueueSize       int                `json:"queue_size"`
+	Delivered       uint64             `json:"delivered"`
+	Dropped         uint64             `json:"dropped"`
+	SlowDisconnects uint64             `json:"slow_disconnects"`
+	Clients         []SSEClientMetrics `json:"clients"`
+}
@@ -0,0 +1,106 @@
+package serve
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+)
+
+func TestSSEClientQueueEviction(t *testing.T) {
+	// No pump, so the queue only changes through push
+	c := &sseClient{stats: &sseStats{}, wake: make(chan struct{}, 1)}
+	t0 := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	c.push(SSEEvent{ID: "0", Event: "ping"}, t0)
+	for i := 1; i < sseQueueSize; i++ {
+		if dropped, _ := c.push(SSEEvent{ID: fmt.Sprint(i), Event: "refresh"}, t0); dropped {
+			t.Fatalf("dropped at %d before the queue was full", i)
+		}
+	}
+
+	// A reminder evicts the ping, a ping is refused, a refresh evicts the
+	// oldest refresh
+	c.push(SSEEvent{ID: "r", Event: "reminder"}, t0)
+	if c.queue[0].ID != "1" || c.queue[len(c.queue)-1].ID != "r" {
+		t.Errorf("after reminder: head %s, tail %s", c.queue[0].ID, c.queue[len(c.queue)-1].ID)
+	}
+	c.push(SSEEvent{ID: "p", Event: "ping"}, t0)
+	if c.queue[len(c.queue)-1].ID != "r" {
+		t.Error("ping was queued into a full queue of higher-priority events")
+	}
+	c.push(SSEEvent{ID: "new", Event: "refresh"}, t0)
+	if c.queue[0].ID != "2" || c.queue[len(c.queue)-1].ID != "new" || len(c.queue) != sseQueueSize {
+		t.Errorf("after refresh: head %s, tail %s, len %d", c.queue[0].ID, c.queue[len(c.queue)-1].ID, len(c.queue))
+	}
+	if c.dropped != 3 || c.stats.dropped.Load() != 3 {
+		t.Errorf("dropped = %d / %d, want 3", c.dropped, c.stats.dropped.Load())
+	}
+
+	if _, stalled := c.push(SSEEvent{Event: "refresh"}, t0.Add(sseSlowClientTimeout)); stalled {
+		t.Error("stalled at the timeout, want only after it")
+	}
+	if _, stalled := c.push(SSEEvent{Event: "refresh"}, t0.Add(sseSlowClientTimeout+time.Second)); !stalled {
+		t.Error("not stalled after the queue stayed full past the timeout")
+	}
+}
+
+func TestSSEHubDropsSlowClient(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC))
+	defer clock.Set(fake.Now)()
+
+	hub := NewSSEHub(nil, time.Second)
+	stalled := hub.register()
+	reader := hub.register()
+
+	// The stalled client never reads; its queue fills and later events
+	// are dropped while the reader keeps getting each one
+	send := func(id string) {
+		t.Helper()
+		hub.send(SSEEvent{ID: id, Event: "refresh"})
+		select {
+		case ev := <-reader:
+			if ev.ID != id {
+				t.Fatalf("reader got %s, want %s", ev.ID, id)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("reader blocked on %s", id)
+		}
+	}
+	for i := 0; i < sseQueueSize+2; i++ {
+		send(fmt.Sprint(i))
+	}
+	fake.Advance(sseSlowClientTimeout + time.Second)
+	send("last")
+
+	// The stalled client's channel closes, after the one event its pump
+	// had in flight
+	for range stalled {
+	}
+	m := hub.Metrics()
+	if m.SlowDisconnects != 1 || len(m.Clients) != 1 || m.Dropped < 2 {
+		t.Errorf("metrics = %+v", m)
+	}
+	hub.unregister(reader)
+}
+
+func TestEventMetricsEndpoint(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	_, unsubscribe := srv.sseHub.Subscribe()
+	defer unsubscribe()
+
+	resp, env := doJSON(t, ts, "GET", "/v1/events/metrics", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d", resp.StatusCode)
+	}
+	m := env.Data.(map[string]interface{})["events"].(map[string]interface{})
+	if m["queue_size"] != float64(sseQueueSize) || len(m["clients"].([]interface{})) != 1 {
+		t.Errorf("metrics = %v", m)
+	}
+}
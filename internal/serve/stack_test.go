@@ -0,0 +1,54 @@
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestIssueIncludeStack(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	var ids []string
+	for _, title := range []string{"Extract payment client", "Retry failed payments", "Show payment retries"} {
+		issue := &models.Issue{Title: title}
+		if err := srv.db.CreateIssueLogged(issue, "ses_test123"); err != nil {
+			t.Fatal(err)
+		}
+		if n := len(ids); n > 0 {
+			if err := srv.db.AddDependencyLogged(issue.ID, ids[n-1], models.RelationStackedOn, "ses_test123"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		ids = append(ids, issue.ID)
+	}
+
+	_, env := doJSON(t, ts, "GET", "/v1/issues/"+ids[2]+"?include=stack&fields=id", nil)
+	stack, _ := env.Data.(map[string]interface{})["stack"].([]interface{})
+	if len(stack) != 3 {
+		t.Fatalf("stack = %v, want 3 issues", env.Data)
+	}
+	for i, member := range stack {
+		if id := member.(map[string]interface{})["id"]; id != ids[i] {
+			t.Errorf("stack[%d] = %v, want %s", i, id, ids[i])
+		}
+	}
+
+	// Stacks are kept out of the dependency lists, and removable by dep_id
+	_, env = doJSON(t, ts, "GET", "/v1/issues/"+ids[1]+"?include=dependencies", nil)
+	if deps := env.Data.(map[string]interface{})["dependencies"].([]interface{}); len(deps) != 0 {
+		t.Errorf("dependencies = %v, want none", deps)
+	}
+	depID := DependencyToDTO(&models.IssueDependency{IssueID: ids[1], DependsOnID: ids[0], RelationType: models.RelationStackedOn}).DepID
+	if resp, _ := doJSON(t, ts, "DELETE", "/v1/issues/"+ids[1]+"/dependencies/"+depID, nil); resp.StatusCode != http.StatusOK {
+		t.Fatalf("delete stacked_on status = %d", resp.StatusCode)
+	}
+	_, env = doJSON(t, ts, "GET", "/v1/issues/"+ids[0]+"?include=stack", nil)
+	if stack := env.Data.(map[string]interface{})["stack"].([]interface{}); len(stack) != 0 {
+		t.Errorf("stack after unstacking = %v, want none", stack)
+	}
+}
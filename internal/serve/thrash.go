@@ -0,0 +1,42 @@
+package serve
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/thrash"
+)
+
+// updateIssueLogged persists an issue update. ?confirm=thrash confirms a
+// change the anti-thrash guard holds in confirm mode.
+func (s *Server) updateIssueLogged(r *http.Request, issue *models.Issue, actionType models.ActionType) error {
+	if r.URL.Query().Get("confirm") == "thrash" {
+		return s.writeDB(r).UpdateIssueLoggedConfirmed(issue, s.requestSession(r), actionType)
+	}
+	return s.writeDB(r).UpdateIssueLogged(issue, s.requestSession(r), actionType)
+}
+
+// writeThrashError writes the response for an anti-thrash rejection: 429
+// with Retry-After when throttled, 409 when confirmation is required.
+// Returns false, writing nothing, for any other error.
+func writeThrashError(w http.ResponseWriter, err error) bool {
+	var te *db.ThrashError
+	if !errors.As(err, &te) {
+		return false
+	}
+	if te.Mode == thrash.ModeThrottle {
+		secs := int(math.Ceil(te.Verdict.RetryAfter.Seconds()))
+		w.Header().Set("Retry-After", strconv.Itoa(max(secs, 1)))
+		WriteError(w, ErrRateLimited, te.Error(), http.StatusTooManyRequests)
+		return true
+	}
+	WriteError(w, ErrConflict,
+		fmt.Sprintf("%s: %s; retry with ?confirm=thrash to confirm this change", te.IssueID, te.Verdict.String()),
+		http.StatusConflict)
+	return true
+}
@@ -0,0 +1,51 @@
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/thrash"
+)
+
+func TestThrashGuard_PriorityFlapping(t *testing.T) {
+	srv := newTestServerWithDB(t)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	if err := config.SetThrashConfig(srv.baseDir, models.ThrashConfig{Mode: thrash.ModeConfirm, MaxReversals: 1}); err != nil {
+		t.Fatal(err)
+	}
+	issue := &models.Issue{Title: "Flapping priority", Priority: models.PriorityP2}
+	if err := srv.db.CreateIssue(issue); err != nil {
+		t.Fatal(err)
+	}
+
+	path := "/v1/issues/" + issue.ID
+	for _, p := range []string{"P1", "P2"} {
+		if resp, env := doJSON(t, ts, "PATCH", path, map[string]interface{}{"priority": p}); resp.StatusCode != http.StatusOK {
+			t.Fatalf("set %s status = %d: %+v", p, resp.StatusCode, env.Error)
+		}
+	}
+
+	resp, env := doJSON(t, ts, "PATCH", path, map[string]interface{}{"priority": "P1"})
+	if resp.StatusCode != http.StatusConflict {
+		t.Fatalf("second reversal status = %d, want 409", resp.StatusCode)
+	}
+	if env.Error == nil || env.Error.Code != ErrConflict {
+		t.Errorf("error = %+v", env.Error)
+	}
+	if resp, env := doJSON(t, ts, "PATCH", path+"?confirm=thrash", map[string]interface{}{"priority": "P1"}); resp.StatusCode != http.StatusOK {
+		t.Fatalf("confirmed status = %d: %+v", resp.StatusCode, env.Error)
+	}
+
+	if err := config.SetThrashConfig(srv.baseDir, models.ThrashConfig{Mode: thrash.ModeThrottle, MaxReversals: 1}); err != nil {
+		t.Fatal(err)
+	}
+	resp, _ = doJSON(t, ts, "PATCH", path, map[string]interface{}{"priority": "P2"})
+	if resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") == "" {
+		t.Errorf("throttled status = %d, Retry-After = %q", resp.StatusCode, resp.Header.Get("Retry-After"))
+	}
+}
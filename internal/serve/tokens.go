@@ -0,0 +1,126 @@
+package serve
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+// apiTokenKey is the context key of the API token a request was made with
+type apiTokenKey struct{}
+
+// sessionBearer returns the API token in r's Authorization header, if it
+// carries one rather than the server's own token.
+func sessionBearer(r *http.Request) (string, bool) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return token, ok && strings.HasPrefix(token, models.APITokenPrefix)
+}
+
+// serveWithAPIToken checks an API token and its scope for the request, then
+// serves it as the token's session. GET, HEAD and OPTIONS need the read
+// scope and everything else needs write, except under /v1/admin/, which
+// needs the admin scope whatever the method.
+func (s *Server) serveWithAPIToken(w http.ResponseWriter, r *http.Request, next http.Handler, token string) {
+	tok, err := s.db.LookupAPIToken(token)
+	if err != nil || !tok.Active(clock.Now()) {
+		WriteError(w, ErrUnauthorized, "invalid, expired or revoked token", http.StatusUnauthorized)
+		return
+	}
+
+	scope := models.TokenScopeWrite
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/v1/admin/"):
+		scope = models.TokenScopeAdmin
+	case r.Method == http.MethodGet, r.Method == http.MethodHead, r.Method == http.MethodOptions:
+		scope = models.TokenScopeRead
+	}
+	if !tok.HasScope(scope) {
+		WriteError(w, ErrForbidden, "token "+tok.ID+" lacks the "+scope+" scope", http.StatusForbidden)
+		return
+	}
+
+	next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), apiTokenKey{}, tok)))
+}
+
+// requestToken returns the API token a request was made with, nil when it
+// used the server token or none.
+func requestToken(r *http.Request) *models.APIToken {
+	tok, _ := r.Context().Value(apiTokenKey{}).(*models.APIToken)
+	return tok
+}
+
+// requestSession returns the session a request acts as: the session its API
+// token is bound to, else the server's web session.
+func (s *Server) requestSession(r *http.Request) string {
+	if tok := requestToken(r); tok != nil {
+		return tok.SessionID
+	}
+	return s.sessionID
+}
+
+// ============================================================================
+// GET /v1/tokens
+// ============================================================================
+
+// handleListTokens lists active API tokens, newest first; ?all=true adds
+// revoked and expired ones. A request made with an API token only sees
+// the tokens of its own session.
+func (s *Server) handleListTokens(w http.ResponseWriter, r *http.Request) {
+	all, _ := strconv.ParseBool(r.URL.Query().Get("all"))
+	sessionID := ""
+	if tok := requestToken(r); tok != nil {
+		sessionID = tok.SessionID
+	}
+
+	tokens, err := s.db.ListAPITokens(sessionID, all)
+	if err != nil {
+		requestLog(r).Error("list tokens", "err", err)
+		WriteError(w, ErrInternal, "failed to list tokens", http.StatusInternalServerError)
+		return
+	}
+	WriteSuccess(w, map[string]interface{}{"tokens": APITokensToDTOs(tokens)}, http.StatusOK)
+}
+
+// ============================================================================
+// DELETE /v1/tokens/{id}
+// ============================================================================
+
+// handleRevokeToken revokes an API token. A request made with an API token
+// can only revoke tokens of its own session; others are reported as not
+// found. Tokens already revoked return 409.
+func (s *Server) handleRevokeToken(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	tok, err := s.db.GetAPIToken(id)
+	if err == nil {
+		if caller := requestToken(r); caller != nil && caller.SessionID != tok.SessionID {
+			err = errors.New("not found")
+		}
+	}
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			WriteError(w, ErrNotFound, "token not found: "+id, http.StatusNotFound)
+		} else {
+			requestLog(r).Error("get token", "err", err, "id", id)
+			WriteError(w, ErrInternal, "failed to fetch token", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if err := s.db.RevokeAPIToken(id); err != nil {
+		if errors.Is(err, db.ErrAPITokenRevoked) {
+			WriteError(w, ErrConflict, err.Error(), http.StatusConflict)
+			return
+		}
+		requestLog(r).Error("revoke token", "err", err, "id", id)
+		WriteError(w, ErrInternal, "failed to revoke token", http.StatusInternalServerError)
+		return
+	}
+	tok, _ = s.db.GetAPIToken(id)
+	WriteSuccess(w, map[string]interface{}{"token": APITokenToDTO(tok)}, http.StatusOK)
+}
@@ -0,0 +1,219 @@
+package serve
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+// doWithToken sends a JSON request with a bearer token
+func doWithToken(t *testing.T, ts *httptest.Server, token, method, path string, body interface{}) (*http.Response, Envelope) {
+	t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	req, _ := http.NewRequest(method, ts.URL+path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+	var env Envelope
+	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return resp, env
+}
+
+func TestAPITokenAuth(t *testing.T) {
+	tmpDir := t.TempDir()
+	database, err := db.Initialize(tmpDir)
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+
+	srv := NewServer(database, tmpDir, "ses_web", ServeConfig{Token: "server-secret"})
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	newToken := func(tok *models.APIToken) string {
+		t.Helper()
+		secret, err := database.CreateAPIToken(tok)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return secret
+	}
+	past := time.Now().Add(-time.Hour)
+	writer := newToken(&models.APIToken{Name: "agent", SessionID: "ses_agent", Scopes: []string{"read", "write"}})
+	reader := newToken(&models.APIToken{SessionID: "ses_agent", Scopes: []string{"read"}})
+	expired := newToken(&models.APIToken{SessionID: "ses_agent", Scopes: []string{"read"}, ExpiresAt: &past})
+
+	// Writes made with a token act as its session
+	resp, env := doWithToken(t, ts, writer, "POST", "/v1/issues", map[string]string{"title": "Created by an agent over HTTP"})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create with write token = %d: %+v", resp.StatusCode, env.Error)
+	}
+	id := env.Data.(map[string]interface{})["issue"].(map[string]interface{})["id"].(string)
+	issue, err := database.GetIssue(id)
+	if err != nil || issue.CreatorSession != "ses_agent" {
+		t.Errorf("creator session = %q, want ses_agent", issue.CreatorSession)
+	}
+
+	// So do transitions
+	if resp, env := doWithToken(t, ts, writer, "POST", "/v1/issues/"+id+"/start", nil); resp.StatusCode != http.StatusOK {
+		t.Fatalf("start with write token = %d: %+v", resp.StatusCode, env.Error)
+	}
+	if issue, _ := database.GetIssue(id); issue.ImplementerSession != "ses_agent" {
+		t.Errorf("implementer session = %q, want ses_agent", issue.ImplementerSession)
+	}
+
+	// The server token still works and acts as the web session
+	resp, env = doWithToken(t, ts, "server-secret", "POST", "/v1/issues", map[string]string{"title": "Created by the web session"})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create with server token = %d: %+v", resp.StatusCode, env.Error)
+	}
+	id = env.Data.(map[string]interface{})["issue"].(map[string]interface{})["id"].(string)
+	if issue, _ := database.GetIssue(id); issue.CreatorSession != "ses_web" {
+		t.Errorf("creator session = %q, want ses_web", issue.CreatorSession)
+	}
+
+	tests := []struct {
+		name   string
+		token  string
+		method string
+		path   string
+		want   int
+	}{
+		{"read token reads", reader, "GET", "/v1/issues", http.StatusOK},
+		{"read token cannot write", reader, "POST", "/v1/issues", http.StatusForbidden},
+		{"expired token", expired, "GET", "/v1/issues", http.StatusUnauthorized},
+		{"unknown token", models.APITokenPrefix + "0000", "GET", "/v1/issues", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		resp, env := doWithToken(t, ts, tt.token, tt.method, tt.path, map[string]string{"title": "Should not be created"})
+		if resp.StatusCode != tt.want {
+			t.Errorf("%s: status = %d, want %d (%+v)", tt.name, resp.StatusCode, tt.want, env.Error)
+		}
+	}
+}
+
+func TestAPITokenAuthWithoutServerToken(t *testing.T) {
+	tmpDir := t.TempDir()
+	database, err := db.Initialize(tmpDir)
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+
+	srv := NewServer(database, tmpDir, "ses_web", ServeConfig{})
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	token, err := database.CreateAPIToken(&models.APIToken{SessionID: "ses_agent", Scopes: []string{"read"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Identity applies in tokenless mode too
+	resp, env := doWithToken(t, ts, token, "GET", "/v1/sessions", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d: %+v", resp.StatusCode, env.Error)
+	}
+	if got := env.Data.(map[string]interface{})["current_session_id"]; got != "ses_agent" {
+		t.Errorf("current_session_id = %v, want ses_agent", got)
+	}
+	if resp, _ := doWithToken(t, ts, models.APITokenPrefix+"bad", "GET", "/v1/sessions", nil); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("bad token in tokenless mode = %d, want 401", resp.StatusCode)
+	}
+}
+
+func TestTokenEndpoints(t *testing.T) {
+	tmpDir := t.TempDir()
+	database, err := db.Initialize(tmpDir)
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+
+	srv := NewServer(database, tmpDir, "ses_web", ServeConfig{Token: "server-secret"})
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	mine := &models.APIToken{Name: "mine", SessionID: "ses_agent", Scopes: []string{"read", "write"}}
+	spare := &models.APIToken{SessionID: "ses_agent", Scopes: []string{"read"}}
+	theirs := &models.APIToken{SessionID: "ses_other", Scopes: []string{"read"}}
+	var token string
+	for _, tok := range []*models.APIToken{mine, spare, theirs} {
+		secret, err := database.CreateAPIToken(tok)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tok == mine {
+			token = secret
+		}
+	}
+
+	listLen := func(bearer, query string) int {
+		t.Helper()
+		resp, env := doWithToken(t, ts, bearer, "GET", "/v1/tokens"+query, nil)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("list tokens = %d: %+v", resp.StatusCode, env.Error)
+		}
+		list := env.Data.(map[string]interface{})["tokens"].([]interface{})
+		for _, item := range list {
+			if _, leaked := item.(map[string]interface{})["token_hash"]; leaked {
+				t.Error("token hash in the listing")
+			}
+		}
+		return len(list)
+	}
+	if n := listLen("server-secret", ""); n != 3 {
+		t.Errorf("server token lists %d tokens, want 3", n)
+	}
+	if n := listLen(token, ""); n != 2 {
+		t.Errorf("agent token lists %d tokens, want its session's 2", n)
+	}
+
+	// Agents cannot touch other sessions' tokens
+	if resp, _ := doWithToken(t, ts, token, "DELETE", "/v1/tokens/"+theirs.ID, nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("revoke other session's token = %d, want 404", resp.StatusCode)
+	}
+
+	resp, env := doWithToken(t, ts, token, "DELETE", "/v1/tokens/"+spare.ID, nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("revoke = %d: %+v", resp.StatusCode, env.Error)
+	}
+	if status := env.Data.(map[string]interface{})["token"].(map[string]interface{})["status"]; status != "revoked" {
+		t.Errorf("revoked token status = %v", status)
+	}
+	if resp, _ := doWithToken(t, ts, token, "DELETE", "/v1/tokens/"+spare.ID, nil); resp.StatusCode != http.StatusConflict {
+		t.Errorf("second revoke = %d, want 409", resp.StatusCode)
+	}
+	if n := listLen(token, ""); n != 1 {
+		t.Errorf("after revoke agent lists %d tokens, want 1", n)
+	}
+	if n := listLen(token, "?all=true"); n != 2 {
+		t.Errorf("?all=true lists %d tokens, want 2", n)
+	}
+
+	// A token can revoke itself, after which it stops working
+	doWithToken(t, ts, token, "DELETE", "/v1/tokens/"+mine.ID, nil)
+	if resp, _ := doWithToken(t, ts, token, "GET", "/v1/tokens", nil); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("revoked token = %d, want 401", resp.StatusCode)
+	}
+}
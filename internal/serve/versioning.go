@@ -0,0 +1,102 @@
+package serve
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// API Versioning
+// ============================================================================
+//
+// Each major version lives under its own path prefix. /v1 is frozen: no
+// route is removed and no DTO field is renamed, removed or retyped. The
+// golden files under testdata/contract enforce that, so changing them is
+// an API change reviewers can see. Fields may still be added to v1 DTOs.
+// Breaking changes go to /v2, and the v1 route they replace gets an entry
+// in v1Deprecations so clients are told where to move.
+
+// APIVersionHeader names the version that served a response.
+const APIVersionHeader = "TD-API-Version"
+
+// Version statuses reported by GET /versions
+const (
+	VersionFrozen     = "frozen"     // stable; only additive changes
+	VersionPreview    = "preview"    // may change without notice
+	VersionDeprecated = "deprecated" // still served, but has a sunset date
+)
+
+// APIVersionDTO describes one major version of the API.
+type APIVersionDTO struct {
+	Version string `json:"version"`
+	Status  string `json:"status"`
+	Prefix  string `json:"prefix"`
+}
+
+// apiVersions lists the versions the server speaks, oldest first
+var apiVersions = []APIVersionDTO{
+	{Version: "v1", Status: VersionFrozen, Prefix: "/v1/"},
+	{Version: "v2", Status: VersionPreview, Prefix: "/v2/"},
+}
+
+// Deprecation marks a route as replaced. Responses from it carry the
+// Deprecation, Sunset and Link headers (RFC 9745 and RFC 8594).
+type Deprecation struct {
+	Since     time.Time // when the route was deprecated
+	Sunset    time.Time // when it may be removed; zero if not scheduled
+	Successor string    // path of the replacement route, if any
+}
+
+// v1Deprecations maps deprecated v1 route patterns, exactly as registered
+// (e.g. "GET /v1/issues/{id}"), to their deprecation.
+var v1Deprecations = map[string]Deprecation{}
+
+// versionMiddleware stamps responses with the API version they came from
+// and, for deprecated routes, with deprecation headers. It runs outside
+// the mux, so it looks the route up itself.
+func (s *Server) versionMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, v := range apiVersions {
+			if strings.HasPrefix(r.URL.Path, v.Prefix) {
+				w.Header().Set(APIVersionHeader, v.Version)
+				break
+			}
+		}
+
+		if len(s.deprecations) > 0 {
+			if _, pattern := s.mux.Handler(r); pattern != "" {
+				if dep, ok := s.deprecations[pattern]; ok {
+					setDeprecationHeaders(w.Header(), dep)
+				}
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// setDeprecationHeaders writes the headers announcing a deprecation
+func setDeprecationHeaders(h http.Header, dep Deprecation) {
+	h.Set("Deprecation", "@"+strconv.FormatInt(dep.Since.Unix(), 10))
+	if !dep.Sunset.IsZero() {
+		h.Set("Sunset", dep.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if dep.Successor != "" {
+		h.Add("Link", "<"+dep.Successor+`>; rel="successor-version"`)
+	}
+}
+
+// handleVersions handles GET /versions.
+func (s *Server) handleVersions(w http.ResponseWriter, r *http.Request) {
+	WriteSuccess(w, map[string]interface{}{"versions": apiVersions}, http.StatusOK)
+}
+
+// registerV2Routes registers the /v2 API. v2 is a preview: endpoints land
+// here when they need a shape v1 can't take. Until then every /v2 path is
+// a 404 that points clients at GET /versions.
+func (s *Server) registerV2Routes() {
+	s.mux.HandleFunc("/v2/", func(w http.ResponseWriter, r *http.Request) {
+		WriteError(w, ErrNotFound, "no v2 endpoint "+r.Method+" "+r.URL.Path+" yet; see GET /versions", http.StatusNotFound)
+	})
+}
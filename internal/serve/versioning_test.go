@@ -0,0 +1,54 @@
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestVersionHeaders(t *testing.T) {
+	srv := newTestServer(ServeConfig{})
+	srv.deprecations = map[string]Deprecation{
+		"GET /v1/stats": {
+			Since:     time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+			Sunset:    time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC),
+			Successor: "/v2/stats",
+		},
+	}
+	h := srv.Handler()
+
+	tests := []struct {
+		path        string
+		version     string
+		deprecation string
+		status      int
+	}{
+		{"/v1/stats", "v1", "@1740787200", 0},
+		{"/v1/boards", "v1", "", 0},
+		{"/health", "", "", 0},
+		{"/v2/issues", "v2", "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+		if got := w.Header().Get(APIVersionHeader); got != tt.version {
+			t.Errorf("%s: %s = %q, want %q", tt.path, APIVersionHeader, got, tt.version)
+		}
+		if got := w.Header().Get("Deprecation"); got != tt.deprecation {
+			t.Errorf("%s: Deprecation = %q, want %q", tt.path, got, tt.deprecation)
+		}
+		if tt.status != 0 && w.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d", tt.path, w.Code, tt.status)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/v1/stats", nil))
+	if got := w.Header().Get("Sunset"); got != "Mon, 01 Sep 2025 00:00:00 GMT" {
+		t.Errorf("Sunset = %q", got)
+	}
+	if got := w.Header().Get("Link"); got != `</v2/stats>; rel="successor-version"` {
+		t.Errorf("Link = %q", got)
+	}
+}
@@ -0,0 +1,238 @@
+// Package serveclient is a Go client for the td serve HTTP API. Request and
+// response bodies use the serve package's DTOs, so the client cannot drift
+// from the server.
+package serveclient
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/serve"
+)
+
+// Client is an HTTP client for td serve.
+type Client struct {
+	BaseURL string
+	Token   string // bearer token; empty when the server runs without one
+	HTTP    *http.Client
+}
+
+// New creates a client for the server at baseURL (e.g. http://127.0.0.1:8080).
+func New(baseURL, token string) *Client {
+	return &Client{
+		BaseURL: baseURL,
+		Token:   token,
+		HTTP:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// APIError is an error envelope returned by the server.
+type APIError struct {
+	Status  int
+	Code    string
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("HTTP %d %s: %s", e.Status, e.Code, e.Message)
+}
+
+// Health checks GET /health.
+func (c *Client) Health(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/health", nil, nil)
+}
+
+// Monitor fetches GET /v1/monitor.
+func (c *Client) Monitor(ctx context.Context) (*serve.MonitorDTO, error) {
+	var out serve.MonitorDTO
+	if err := c.do(ctx, http.MethodGet, "/v1/monitor", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListIssues fetches GET /v1/issues with the given query parameters
+// (status, type, q, limit, ...).
+func (c *Client) ListIssues(ctx context.Context, params url.Values) ([]serve.IssueDTO, error) {
+	path := "/v1/issues"
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+	var out struct {
+		Issues []serve.IssueDTO `json:"issues"`
+	}
+	if err := c.do(ctx, http.MethodGet, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Issues, nil
+}
+
+// GetIssue fetches GET /v1/issues/{id}.
+func (c *Client) GetIssue(ctx context.Context, id string) (*serve.IssueDTO, error) {
+	return c.issue(ctx, http.MethodGet, "/v1/issues/"+url.PathEscape(id), nil)
+}
+
+// CreateIssue creates an issue with POST /v1/issues.
+func (c *Client) CreateIssue(ctx context.Context, body serve.IssueCreateBody) (*serve.IssueDTO, error) {
+	return c.issue(ctx, http.MethodPost, "/v1/issues", body)
+}
+
+// UpdateIssue applies the set fields of body with PATCH /v1/issues/{id}.
+func (c *Client) UpdateIssue(ctx context.Context, id string, body serve.IssueUpdateBody) (*serve.IssueDTO, error) {
+	return c.issue(ctx, http.MethodPatch, "/v1/issues/"+url.PathEscape(id), body)
+}
+
+// DeleteIssue soft-deletes an issue with DELETE /v1/issues/{id}.
+func (c *Client) DeleteIssue(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/v1/issues/"+url.PathEscape(id), nil, nil)
+}
+
+// AddComment comments on an issue with POST /v1/issues/{id}/comments.
+func (c *Client) AddComment(ctx context.Context, id, text string) error {
+	return c.do(ctx, http.MethodPost, "/v1/issues/"+url.PathEscape(id)+"/comments", serve.CommentCreateBody{Text: text}, nil)
+}
+
+// Events streams GET /v1/events, calling fn with each event until ctx is
+// cancelled or the server closes the stream. params may carry a query
+// filter (?query=). The returned error is nil only when ctx ended it.
+func (c *Client) Events(ctx context.Context, params url.Values, fn func(serve.SSEEvent)) error {
+	path := "/v1/events"
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	// The stream outlives any request timeout
+	stream := *c.HTTP
+	stream.Timeout = 0
+	resp, err := stream.Do(req)
+	if err != nil {
+		return fmt.Errorf("http request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+
+	var event serve.SSEEvent
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if event.Event != "" || event.Data != "" {
+				fn(event)
+			}
+			event = serve.SSEEvent{}
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "id":
+			event.ID = value
+		case "event":
+			event.Event = value
+		case "data":
+			if event.Data != "" {
+				event.Data += "\n"
+			}
+			event.Data += value
+		}
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read event stream: %w", err)
+	}
+	return fmt.Errorf("event stream closed")
+}
+
+// issue runs a request whose response data is {"issue": ...}
+func (c *Client) issue(ctx context.Context, method, path string, body any) (*serve.IssueDTO, error) {
+	var out struct {
+		Issue serve.IssueDTO `json:"issue"`
+	}
+	if err := c.do(ctx, method, path, body, &out); err != nil {
+		return nil, err
+	}
+	return &out.Issue, nil
+}
+
+// do sends a request and decodes the envelope's data into result, which
+// may be nil. Error envelopes are returned as *APIError.
+func (c *Client) do(ctx context.Context, method, path string, body, result any) error {
+	var bodyReader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("marshal request: %w", err)
+		}
+		bodyReader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, bodyReader)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return fmt.Errorf("http request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+
+	var env struct {
+		OK    bool                `json:"ok"`
+		Data  json.RawMessage     `json:"data"`
+		Error *serve.ErrorPayload `json:"error"`
+	}
+	if err := json.Unmarshal(respBody, &env); err != nil {
+		if resp.StatusCode >= 400 {
+			return fmt.Errorf("HTTP %d: %s", resp.StatusCode, bytes.TrimSpace(respBody))
+		}
+		return fmt.Errorf("decode response: %w", err)
+	}
+	if resp.StatusCode >= 400 || !env.OK {
+		apiErr := &APIError{Status: resp.StatusCode}
+		if env.Error != nil {
+			apiErr.Code, apiErr.Message = env.Error.Code, env.Error.Message
+		}
+		return apiErr
+	}
+
+	if result != nil && len(env.Data) > 0 {
+		if err := json.Unmarshal(env.Data, result); err != nil {
+			return fmt.Errorf("decode response: %w", err)
+		}
+	}
+	return nil
+}
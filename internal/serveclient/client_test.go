@@ -0,0 +1,64 @@
+package serveclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/marcus/td/internal/serve"
+)
+
+func TestEvents(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/events" || r.URL.Query().Get("query") != "is(open)" {
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q", got)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "id: 5\nevent: refresh\ndata: {\"change_token\":\"5\"}\n\n")
+		fmt.Fprint(w, ": comment\n\nevent: ping\ndata: {}\n\n")
+	}))
+	defer srv.Close()
+
+	var events []serve.SSEEvent
+	client := New(srv.URL, "secret")
+	err := client.Events(context.Background(), url.Values{"query": {"is(open)"}}, func(e serve.SSEEvent) {
+		events = append(events, e)
+	})
+	if err == nil {
+		t.Error("Events() error = nil when the server closed the stream")
+	}
+
+	want := []serve.SSEEvent{
+		{ID: "5", Event: "refresh", Data: `{"change_token":"5"}`},
+		{Event: "ping", Data: "{}"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(events), len(want), events)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Errorf("event %d = %+v, want %+v", i, events[i], want[i])
+		}
+	}
+}
+
+func TestEventsHTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	err := New(srv.URL, "").Events(context.Background(), nil, func(serve.SSEEvent) {
+		t.Error("unexpected event")
+	})
+	if err == nil {
+		t.Fatal("Events() error = nil for a 401")
+	}
+}
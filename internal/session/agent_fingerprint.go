@@ -44,6 +44,22 @@ func (af AgentFingerprint) String() string {
 	return string(af.Type)
 }
 
+// Source describes how the fingerprint was detected, for td whoami
+func (af AgentFingerprint) Source() string {
+	switch {
+	case af.ExplicitID != "":
+		return "TD_SESSION_ID"
+	case af.Type == AgentCursor && os.Getenv("CURSOR_AGENT") != "":
+		return "CURSOR_AGENT"
+	case af.PID > 0:
+		return "process ancestry"
+	case af.Type == AgentTerminal:
+		return "terminal session"
+	default:
+		return "none (shared fallback session)"
+	}
+}
+
 // sanitizeForFilename makes a string safe for use in filenames
 func sanitizeForFilename(s string) string {
 	// Replace problematic characters
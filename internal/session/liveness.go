@@ -0,0 +1,103 @@
+package session
+
+import (
+	"context"
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/db"
+)
+
+// Liveness describes how recently a session has shown signs of life.
+type Liveness string
+
+const (
+	LivenessActive Liveness = "active" // heartbeat or activity within ActiveWindow
+	LivenessIdle   Liveness = "idle"   // quiet for longer than ActiveWindow but within IdleWindow
+	LivenessGone   Liveness = "gone"   // no activity within IdleWindow
+)
+
+const (
+	// HeartbeatInterval is how often long-running commands bump last_activity.
+	HeartbeatInterval = 60 * time.Second
+
+	// ActiveWindow is the maximum quiet period for a session to count as active.
+	// It spans two missed heartbeats so a single slow tick does not flap state.
+	ActiveWindow = 2*HeartbeatInterval + 30*time.Second
+
+	// IdleWindow is the maximum quiet period before a session is considered gone.
+	IdleWindow = 15 * time.Minute
+)
+
+// ComputeLiveness classifies a session by the age of its last activity.
+// A zero lastActivity is treated as gone.
+func ComputeLiveness(lastActivity, now time.Time) Liveness {
+	if lastActivity.IsZero() {
+		return LivenessGone
+	}
+	age := now.Sub(lastActivity)
+	switch {
+	case age <= ActiveWindow:
+		return LivenessActive
+	case age <= IdleWindow:
+		return LivenessIdle
+	default:
+		return LivenessGone
+	}
+}
+
+// Liveness returns the session's liveness relative to now, falling back to
+// StartedAt when no activity has been recorded.
+func (s *Session) Liveness(now time.Time) Liveness {
+	last := s.LastActivity
+	if last.IsZero() {
+		last = s.StartedAt
+	}
+	return ComputeLiveness(last, now)
+}
+
+// Heartbeat records a liveness ping for the session.
+func Heartbeat(database *db.DB, sessionID string) error {
+	return database.UpdateSessionActivity(sessionID, clock.Now())
+}
+
+// StartHeartbeat bumps the session's last_activity every HeartbeatInterval
+// until ctx is cancelled. Used by long-running commands (monitor, serve) so
+// their sessions stay active while no issues are being touched. Errors are
+// ignored since heartbeats are best-effort.
+func StartHeartbeat(ctx context.Context, database *db.DB, sessionID string) {
+	go func() {
+		_ = Heartbeat(database, sessionID)
+
+		ticker := time.NewTicker(HeartbeatInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_ = Heartbeat(database, sessionID)
+			}
+		}
+	}()
+}
+
+// LivenessBySession returns the liveness of every session that is not gone.
+func LivenessBySession(database *db.DB, now time.Time) (map[string]Liveness, error) {
+	rows, err := database.ListAllSessions()
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]Liveness)
+	for i := range rows {
+		last := rows[i].LastActivity
+		if last.IsZero() {
+			last = rows[i].StartedAt
+		}
+		if l := ComputeLiveness(last, now); l != LivenessGone {
+			result[rows[i].ID] = l
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,85 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/db"
+)
+
+func TestComputeLiveness(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		last time.Time
+		want Liveness
+	}{
+		{"zero time", time.Time{}, LivenessGone},
+		{"just now", now, LivenessActive},
+		{"within active window", now.Add(-ActiveWindow), LivenessActive},
+		{"just past active window", now.Add(-ActiveWindow - time.Second), LivenessIdle},
+		{"within idle window", now.Add(-IdleWindow), LivenessIdle},
+		{"past idle window", now.Add(-IdleWindow - time.Second), LivenessGone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ComputeLiveness(tt.last, now); got != tt.want {
+				t.Errorf("ComputeLiveness() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLivenessBySessionOmitsGone(t *testing.T) {
+	database := setupTestDB(t)
+	now := time.Now()
+
+	rows := []db.SessionRow{
+		{ID: "ses_active", Branch: "main", AgentType: "terminal", StartedAt: now.Add(-time.Hour), LastActivity: now},
+		{ID: "ses_idle", Branch: "main", AgentType: "terminal", AgentPID: 1, StartedAt: now.Add(-time.Hour), LastActivity: now.Add(-10 * time.Minute)},
+		{ID: "ses_gone", Branch: "main", AgentType: "terminal", AgentPID: 2, StartedAt: now.Add(-time.Hour), LastActivity: now.Add(-time.Hour)},
+	}
+	for i := range rows {
+		if err := database.UpsertSession(&rows[i]); err != nil {
+			t.Fatalf("UpsertSession: %v", err)
+		}
+	}
+
+	got, err := LivenessBySession(database, now)
+	if err != nil {
+		t.Fatalf("LivenessBySession: %v", err)
+	}
+	if got["ses_active"] != LivenessActive {
+		t.Errorf("ses_active = %q, want active", got["ses_active"])
+	}
+	if got["ses_idle"] != LivenessIdle {
+		t.Errorf("ses_idle = %q, want idle", got["ses_idle"])
+	}
+	if _, ok := got["ses_gone"]; ok {
+		t.Errorf("ses_gone should be omitted, got %q", got["ses_gone"])
+	}
+}
+
+func TestHeartbeatRevivesSession(t *testing.T) {
+	database := setupTestDB(t)
+	now := time.Now()
+
+	row := db.SessionRow{ID: "ses_hb", Branch: "main", AgentType: "terminal", StartedAt: now.Add(-time.Hour), LastActivity: now.Add(-time.Hour)}
+	if err := database.UpsertSession(&row); err != nil {
+		t.Fatalf("UpsertSession: %v", err)
+	}
+
+	if err := Heartbeat(database, row.ID); err != nil {
+		t.Fatalf("Heartbeat: %v", err)
+	}
+
+	sess, err := GetByID(database, row.ID)
+	if err != nil || sess == nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if l := sess.Liveness(time.Now()); l != LivenessActive {
+		t.Errorf("Liveness after heartbeat = %q, want active", l)
+	}
+}
@@ -3,7 +3,6 @@
 package session
 
 import (
-	"crypto/rand"
 	"encoding/hex"
 	"fmt"
 	"os"
@@ -12,6 +11,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/marcus/td/internal/clock"
 	"github.com/marcus/td/internal/db"
 	"github.com/marcus/td/internal/git"
 )
@@ -32,6 +32,7 @@ type Session struct {
 	AgentPID          int       `json:"agent_pid,omitempty"`         // stable parent agent process ID
 	ContextID         string    `json:"context_id,omitempty"`        // audit only, not used for matching
 	PreviousSessionID string    `json:"previous_session_id,omitempty"`
+	Repo              string    `json:"repo,omitempty"` // repository identity for multi-repo projects
 	StartedAt         time.Time `json:"started_at"`
 	LastActivity      time.Time `json:"last_activity,omitempty"` // heartbeat for session liveness
 	IsNew             bool      `json:"-"`                       // True if session was just created (not persisted)
@@ -69,7 +70,7 @@ func FormatSessionID(database *db.DB, sessionID string) string {
 // generateID creates a new random session ID
 func generateID() (string, error) {
 	bytes := make([]byte, 3) // 6 hex characters
-	if _, err := rand.Read(bytes); err != nil {
+	if _, err := db.RandomBytes(bytes); err != nil {
 		return "", fmt.Errorf("generate session id: %w", err)
 	}
 	return sessionPrefix + hex.EncodeToString(bytes), nil
@@ -77,19 +78,28 @@ func generateID() (string, error) {
 
 // getCurrentBranch returns the current git branch, or "default" if not in a repo
 func getCurrentBranch() string {
+	_, branch, _ := getCurrentScope()
+	return branch
+}
+
+// getCurrentScope returns the repository identity, branch and checkout
+// root sessions are scoped by. Outside a repo the branch is "default" and
+// repo and root are empty.
+func getCurrentScope() (repo, branch, root string) {
 	state, err := git.GetState()
 	if err != nil {
-		return defaultBranch
+		return "", defaultBranch, ""
 	}
-	branch := state.Branch
+	branch = state.Branch
 	if branch == "" || branch == "HEAD" {
 		// Detached HEAD - use short commit SHA
 		if len(state.CommitSHA) >= 8 {
-			return "detached-" + state.CommitSHA[:8]
+			branch = "detached-" + state.CommitSHA[:8]
+		} else {
+			branch = defaultBranch
 		}
-		return defaultBranch
 	}
-	return branch
+	return state.Repo, branch, state.Root
 }
 
 // getContextID generates a unique identifier for the current execution context.
@@ -149,6 +159,7 @@ func sessionFromRow(row *db.SessionRow) *Session {
 		AgentPID:          row.AgentPID,
 		ContextID:         row.ContextID,
 		PreviousSessionID: row.PreviousSessionID,
+		Repo:              row.Repo,
 		StartedAt:         row.StartedAt,
 		LastActivity:      row.LastActivity,
 	}
@@ -161,7 +172,7 @@ func GetOrCreate(database *db.DB) (*Session, error) {
 	getOrCreateMu.Lock()
 	defer getOrCreateMu.Unlock()
 
-	branch := getCurrentBranch()
+	repo, branch, root := getCurrentScope()
 	fp := GetAgentFingerprint()
 
 	// One-time migration from filesystem (no-op after first run)
@@ -170,17 +181,23 @@ func GetOrCreate(database *db.DB) (*Session, error) {
 	if cwd, err := os.Getwd(); err == nil && cwd != database.BaseDir() {
 		database.MigrateFileSystemSessions(cwd)
 	}
+	database.RecordRepo(repo, root)
 
-	// Look up existing session for this branch + agent fingerprint
-	row, err := database.GetSessionByBranchAgent(branch, fp.String(), fp.PID)
+	// Look up existing session for this repo + branch + agent fingerprint
+	row, err := database.GetSessionByRepoBranchAgent(repo, branch, fp.String(), fp.PID)
 	if err != nil {
 		return nil, fmt.Errorf("lookup session: %w", err)
 	}
 
 	if row != nil {
 		// Found existing session - update heartbeat
-		now := time.Now()
+		now := clock.Now()
 		database.UpdateSessionActivity(row.ID, now)
+		if row.Repo == "" && repo != "" {
+			// Session predates repo tracking; it belongs to this repo now
+			database.UpdateSessionRepo(row.ID, repo)
+			row.Repo = repo
+		}
 		sess := sessionFromRow(row)
 		sess.LastActivity = now
 		sess.IsNew = false
@@ -188,15 +205,15 @@ func GetOrCreate(database *db.DB) (*Session, error) {
 	}
 
 	// No session found - create new one
-	return createSession(database, branch, fp, "")
+	return createSession(database, repo, branch, fp, "")
 }
 
 // Get returns the current session without creating one
 func Get(database *db.DB) (*Session, error) {
-	branch := getCurrentBranch()
+	repo, branch, _ := getCurrentScope()
 	fp := GetAgentFingerprint()
 
-	row, err := database.GetSessionByBranchAgent(branch, fp.String(), fp.PID)
+	row, err := database.GetSessionByRepoBranchAgent(repo, branch, fp.String(), fp.PID)
 	if err != nil {
 		return nil, fmt.Errorf("lookup session: %w", err)
 	}
@@ -208,17 +225,17 @@ func Get(database *db.DB) (*Session, error) {
 
 // ForceNewSession creates a new session on the current branch/agent, regardless of existing session
 func ForceNewSession(database *db.DB) (*Session, error) {
-	branch := getCurrentBranch()
+	repo, branch, _ := getCurrentScope()
 	fp := GetAgentFingerprint()
 
 	// Get previous session ID if exists
 	var previousID string
-	row, err := database.GetSessionByBranchAgent(branch, fp.String(), fp.PID)
+	row, err := database.GetSessionByRepoBranchAgent(repo, branch, fp.String(), fp.PID)
 	if err == nil && row != nil {
 		previousID = row.ID
 	}
 
-	return createSession(database, branch, fp, previousID)
+	return createSession(database, repo, branch, fp, previousID)
 }
 
 // SetName sets the session name
@@ -235,6 +252,15 @@ func SetName(database *db.DB, name string) (*Session, error) {
 	return sess, nil
 }
 
+// GetByID returns the session with the given ID, or nil if it does not exist.
+func GetByID(database *db.DB, id string) (*Session, error) {
+	row, err := database.GetSessionByID(id)
+	if err != nil || row == nil {
+		return nil, err
+	}
+	return sessionFromRow(row), nil
+}
+
 // ListSessions returns all sessions
 func ListSessions(database *db.DB) ([]Session, error) {
 	rows, err := database.ListAllSessions()
@@ -258,13 +284,13 @@ func CleanupStaleSessions(database *db.DB, maxAge time.Duration) (int, error) {
 }
 
 // createSession creates a new session in the DB
-func createSession(database *db.DB, branch string, fp AgentFingerprint, previousID string) (*Session, error) {
+func createSession(database *db.DB, repo, branch string, fp AgentFingerprint, previousID string) (*Session, error) {
 	id, err := generateID()
 	if err != nil {
 		return nil, err
 	}
 
-	now := time.Now()
+	now := clock.Now()
 	row := &db.SessionRow{
 		ID:                id,
 		Name:              "",
@@ -275,6 +301,7 @@ func createSession(database *db.DB, branch string, fp AgentFingerprint, previous
 		PreviousSessionID: previousID,
 		StartedAt:         now,
 		LastActivity:      now,
+		Repo:              repo,
 	}
 
 	if err := database.UpsertSession(row); err != nil {
@@ -0,0 +1,154 @@
+package session
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+// BypassError reports in-review issues whose review a session switch
+// would open to the context that implemented them. Either the context
+// leaves the implementing session for one that could approve its work,
+// or it takes over an implementing session bound elsewhere, leaving that
+// context a fresh session that could.
+type BypassError struct {
+	From    string   // session the context is leaving, "" if none
+	To      string   // session it asked to switch to
+	Issues  []string // in-review issues at stake
+	BoundTo string   // set when To implemented Issues and belongs to this other context
+}
+
+func (e *BypassError) Error() string {
+	verb := "are"
+	if len(e.Issues) == 1 {
+		verb = "is"
+	}
+	issues := strings.Join(e.Issues, ", ")
+	if e.BoundTo != "" {
+		return fmt.Sprintf("cannot switch to %s: it implemented %s, which %s in review, and %s would get a new session that could approve it; switch once the review is done",
+			e.To, issues, verb, e.BoundTo)
+	}
+	return fmt.Sprintf("cannot switch from %s to %s: %s implemented %s, which %s in review, and %s could then approve it; have another agent or terminal review first",
+		e.From, e.To, e.From, issues, verb, e.To)
+}
+
+// SwitchResult describes a session switch
+type SwitchResult struct {
+	Session  *Session // the session the context now uses
+	Previous *Session // the session it used before, nil if it had none
+	Was      *Session // Session as it was bound before the switch
+}
+
+// Resolve finds a session by ID or, failing that, by name. A name shared
+// by several sessions is an error listing their IDs.
+func Resolve(database *db.DB, ref string) (*Session, error) {
+	if sess, err := GetByID(database, ref); err != nil || sess != nil {
+		return sess, err
+	}
+	sessions, err := ListSessions(database)
+	if err != nil {
+		return nil, err
+	}
+	var matches []Session
+	for _, s := range sessions {
+		if s.Name == ref {
+			matches = append(matches, s)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no session with ID or name %q (see td session list)", ref)
+	case 1:
+		return &matches[0], nil
+	}
+	ids := make([]string, len(matches))
+	for i, s := range matches {
+		ids[i] = s.ID
+	}
+	return nil, fmt.Errorf("session name %q is ambiguous: %s", ref, strings.Join(ids, ", "))
+}
+
+// Switch makes the session identified by ref the one the current repo,
+// branch and agent resolve to, by rebinding it to them. It refuses with a
+// *BypassError when the switch would let a context approve issues in
+// review that it implemented.
+func Switch(database *db.DB, ref string) (*SwitchResult, error) {
+	target, err := Resolve(database, ref)
+	if err != nil {
+		return nil, err
+	}
+	repo, branch, _ := getCurrentScope()
+	fp := GetAgentFingerprint()
+
+	res := &SwitchResult{Was: target}
+	row, err := database.GetSessionByRepoBranchAgent(repo, branch, fp.String(), fp.PID)
+	if err != nil {
+		return nil, fmt.Errorf("lookup session: %w", err)
+	}
+	if row != nil {
+		res.Previous = sessionFromRow(row)
+		if row.ID == target.ID {
+			res.Session = res.Previous
+			return res, nil
+		}
+		if blocked, err := inReviewImplementedBy(database, row.ID, target.ID); err != nil {
+			return nil, err
+		} else if len(blocked) > 0 {
+			return nil, &BypassError{From: row.ID, To: target.ID, Issues: blocked}
+		}
+	}
+	if target.Repo != repo || target.Branch != branch || target.AgentType != fp.String() || target.AgentPID != fp.PID {
+		if blocked, err := inReviewImplementedBy(database, target.ID, ""); err != nil {
+			return nil, err
+		} else if len(blocked) > 0 {
+			return nil, &BypassError{To: target.ID, Issues: blocked, BoundTo: fmt.Sprintf("%s on branch %s", target.AgentType, target.Branch)}
+		}
+	}
+
+	now := clock.Now()
+	if err := database.RebindSession(target.ID, repo, branch, fp.String(), fp.PID, now); err != nil {
+		return nil, fmt.Errorf("switch session: %w", err)
+	}
+	sess := *target
+	sess.Repo, sess.Branch, sess.AgentType, sess.AgentPID, sess.LastActivity = repo, branch, fp.String(), fp.PID, now
+	res.Session = &sess
+	return res, nil
+}
+
+// inReviewImplementedBy lists the in-review issues that session
+// implemented and other, if set, did not
+func inReviewImplementedBy(database *db.DB, session, other string) ([]string, error) {
+	issues, err := database.ListIssues(db.ListIssuesOptions{Status: []models.Status{models.StatusInReview}})
+	if err != nil {
+		return nil, err
+	}
+	implemented := func(issue *models.Issue, sessionID string) (bool, error) {
+		if issue.ImplementerSession == sessionID {
+			return true, nil
+		}
+		return database.WasSessionImplementationInvolved(issue.ID, sessionID)
+	}
+	var ids []string
+	for i := range issues {
+		impl, err := implemented(&issues[i], session)
+		if err != nil {
+			return nil, err
+		}
+		if !impl {
+			continue
+		}
+		if other != "" {
+			if impl, err = implemented(&issues[i], other); err != nil {
+				return nil, err
+			}
+			if impl {
+				continue
+			}
+		}
+		ids = append(ids, issues[i].ID)
+	}
+	return ids, nil
+}
@@ -0,0 +1,78 @@
+package session
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestSwitch(t *testing.T) {
+	database := setupTestDB(t)
+
+	named := func(ctx, name string) *Session {
+		t.Helper()
+		t.Setenv("TD_SESSION_ID", ctx)
+		sess, err := SetName(database, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sess
+	}
+	impl := named("ctx-impl", "implementer")
+	rev := named("ctx-rev", "reviewer")
+	named("ctx-dup1", "twin")
+	named("ctx-dup2", "twin")
+
+	if _, err := Resolve(database, "twin"); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("Resolve(twin) err = %v, want ambiguous", err)
+	}
+	if _, err := Resolve(database, "nobody"); err == nil {
+		t.Error("Resolve(nobody) succeeded")
+	}
+
+	issue := &models.Issue{Title: "Retry sync pushes"}
+	if err := database.CreateIssue(issue); err != nil {
+		t.Fatal(err)
+	}
+	issue.Status = models.StatusInReview
+	issue.ImplementerSession = impl.ID
+	if err := database.UpdateIssue(issue); err != nil {
+		t.Fatal(err)
+	}
+
+	// Leaving the implementer for a session that could approve its work
+	t.Setenv("TD_SESSION_ID", "ctx-impl")
+	var bypass *BypassError
+	if _, err := Switch(database, "reviewer"); !errors.As(err, &bypass) || bypass.BoundTo != "" || len(bypass.Issues) != 1 {
+		t.Fatalf("switch away from implementer err = %v", err)
+	}
+	// Taking over the implementer, leaving its context a fresh session
+	t.Setenv("TD_SESSION_ID", "ctx-new")
+	if _, err := Switch(database, impl.ID); !errors.As(err, &bypass) || bypass.BoundTo == "" {
+		t.Fatalf("take over implementer err = %v", err)
+	}
+
+	// Nothing implemented by the reviewer is at stake
+	res, err := Switch(database, "reviewer")
+	if err != nil {
+		t.Fatalf("Switch: %v", err)
+	}
+	if res.Session.ID != rev.ID || res.Previous != nil {
+		t.Errorf("result = %+v", res)
+	}
+	cur, err := GetOrCreate(database)
+	if err != nil || cur.ID != rev.ID {
+		t.Errorf("after switch current = %v, %v; want %s", cur, err, rev.ID)
+	}
+	if res, err := Switch(database, rev.ID); err != nil || res.Previous == nil || res.Previous.ID != rev.ID {
+		t.Errorf("switch to current session = %+v, %v", res, err)
+	}
+
+	// The reviewer's old context no longer resolves to it
+	t.Setenv("TD_SESSION_ID", "ctx-rev")
+	if cur, err := GetOrCreate(database); err != nil || cur.ID == rev.ID {
+		t.Errorf("displaced context still on %s (%v)", rev.ID, err)
+	}
+}
@@ -0,0 +1,526 @@
+// Package settings resolves td's configurable values through their layers,
+// later layers winning:
+//
+//	default  built into td
+//	user     ~/.config/td/config.json
+//	profile  the profile in the user config named by TD_PROFILE
+//	project  .todos/config.json
+//	env      TD_* environment variables
+//	flag     command flags, applied by the command itself
+//
+// Not every setting can be set in every layer; each Setting says which it
+// reads. Resolve reports, for every setting, the value td will use, where it
+// came from and which lower layers it overrode, so conflicts are visible.
+package settings
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/hookscripts"
+	"github.com/marcus/td/internal/i18n"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/score"
+	"github.com/marcus/td/internal/syncconfig"
+	"github.com/marcus/td/internal/thrash"
+)
+
+// Source is the layer a value came from
+type Source string
+
+// Layers, lowest first
+const (
+	SourceDefault Source = "default"
+	SourceUser    Source = "user"
+	SourceProfile Source = "profile"
+	SourceProject Source = "project"
+	SourceEnv     Source = "env"
+	SourceFlag    Source = "flag"
+)
+
+// Areas group settings by what they configure
+const (
+	AreaServe    = "serve"
+	AreaSync     = "sync"
+	AreaWebhook  = "webhook"
+	AreaWorkflow = "workflow"
+	AreaTUI      = "tui"
+)
+
+// Setting is one configurable value and the layers that can set it
+type Setting struct {
+	Key         string `json:"key"`
+	Area        string `json:"area"`
+	Description string `json:"description"`
+	Default     string `json:"default"`
+	UserPath    string `json:"user_path,omitempty"`    // key in the user config and profiles; empty if they can't set it
+	ProjectPath string `json:"project_path,omitempty"` // key in the project config; empty if it can't set it
+	Env         string `json:"env,omitempty"`
+	Flag        string `json:"flag,omitempty"` // e.g. "td serve --port"
+
+	check   func(string) error
+	user    func(*syncconfig.Config) string
+	project func(*models.Config) string
+}
+
+// Layer is a value one layer sets
+type Layer struct {
+	Source Source `json:"source"`
+	Value  string `json:"value"`
+}
+
+// Value is a setting's effective value
+type Value struct {
+	Setting
+	Value      string  `json:"value"`
+	Source     Source  `json:"source"`
+	Overridden []Layer `json:"overridden,omitempty"` // lower layers that also set it, lowest first
+}
+
+// All returns every setting, grouped by area
+func All() []Setting {
+	return registry
+}
+
+// Lookup returns the setting with the given key
+func Lookup(key string) (Setting, bool) {
+	for _, s := range registry {
+		if s.Key == key {
+			return s, true
+		}
+	}
+	return Setting{}, false
+}
+
+// layers holds the config files a resolution reads
+type layers struct {
+	user    *syncconfig.Config
+	profile *syncconfig.Config
+	project *models.Config
+	env     func(string) string
+}
+
+// Resolve works out the effective value of every setting for the project
+// in baseDir, which may be empty outside a project. A value td would reject
+// is skipped, as td skips it, and reported in problems along with config
+// files that could not be read.
+func Resolve(baseDir string, getenv func(string) string) (values []Value, problems []string) {
+	l := layers{env: getenv}
+	if cfg, err := syncconfig.LoadConfig(); err != nil {
+		problems = append(problems, fmt.Sprintf("user config: %v", err))
+	} else {
+		l.user = cfg
+		if name := strings.TrimSpace(getenv("TD_PROFILE")); name != "" {
+			if l.profile, err = syncconfig.LoadProfile(cfg, name); err != nil {
+				problems = append(problems, fmt.Sprintf("TD_PROFILE: %v", err))
+			}
+		}
+	}
+	if baseDir != "" {
+		if cfg, err := config.Load(baseDir); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", filepath.Join(".todos", "config.json"), err))
+		} else {
+			l.project = cfg
+		}
+	}
+
+	for _, s := range registry {
+		v, p := l.resolve(s)
+		values = append(values, v)
+		problems = append(problems, p...)
+	}
+	return values, problems
+}
+
+// Get resolves a single setting
+func Get(baseDir, key string, getenv func(string) string) (Value, bool) {
+	values, _ := Resolve(baseDir, getenv)
+	for _, v := range values {
+		if v.Key == key {
+			return v, true
+		}
+	}
+	return Value{}, false
+}
+
+func (l layers) resolve(s Setting) (Value, []string) {
+	var set []Layer
+	if s.user != nil && l.user != nil {
+		set = append(set, Layer{SourceUser, s.user(l.user)})
+	}
+	if s.user != nil && l.profile != nil {
+		set = append(set, Layer{SourceProfile, s.user(l.profile)})
+	}
+	if s.project != nil && l.project != nil {
+		set = append(set, Layer{SourceProject, s.project(l.project)})
+	}
+	if s.Env != "" {
+		set = append(set, Layer{SourceEnv, l.env(s.Env)})
+	}
+
+	v := Value{Setting: s, Value: s.Default, Source: SourceDefault}
+	var problems []string
+	for _, layer := range set {
+		if layer.Value == "" {
+			continue
+		}
+		if s.check != nil {
+			if err := s.check(layer.Value); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: ignoring %s value %q: %v", s.Key, layer.Source, layer.Value, err))
+				continue
+			}
+		}
+		if v.Source != SourceDefault {
+			v.Overridden = append(v.Overridden, Layer{v.Source, v.Value})
+		}
+		v.Value, v.Source = layer.Value, layer.Source
+	}
+	return v, problems
+}
+
+// Value checks
+
+func isBool(v string) error {
+	switch strings.ToLower(v) {
+	case "1", "true", "0", "false":
+		return nil
+	}
+	return fmt.Errorf("want true or false")
+}
+
+func isDuration(v string) error {
+	if _, err := time.ParseDuration(v); err != nil {
+		return fmt.Errorf("want a duration like 30s or 5m")
+	}
+	return nil
+}
+
+func isInt(min, max int) func(string) error {
+	return func(v string) error {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < min || n > max {
+			return fmt.Errorf("want a whole number from %d to %d", min, max)
+		}
+		return nil
+	}
+}
+
+func oneOf(options ...string) func(string) error {
+	return func(v string) error {
+		for _, o := range options {
+			if v == o {
+				return nil
+			}
+		}
+		return fmt.Errorf("want one of %s", strings.Join(options, ", "))
+	}
+}
+
+func isTimezone(v string) error {
+	if _, err := time.LoadLocation(v); err != nil {
+		return fmt.Errorf("want an IANA timezone name like Europe/Berlin")
+	}
+	return nil
+}
+
+// Formatting stored values; zero values read as unset
+
+func itoa(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+func btoa(b bool) string {
+	if !b {
+		return ""
+	}
+	return "true"
+}
+
+func bptr(b *bool) string {
+	if b == nil {
+		return ""
+	}
+	return strconv.FormatBool(*b)
+}
+
+func iptr(n *int) string {
+	if n == nil {
+		return ""
+	}
+	return strconv.Itoa(*n)
+}
+
+func ftoa(f float64) string {
+	if f == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func userServe(get func(*models.ServeConfig) string) func(*syncconfig.Config) string {
+	return func(c *syncconfig.Config) string {
+		if c.Serve == nil {
+			return ""
+		}
+		return get(c.Serve)
+	}
+}
+
+func projectServe(get func(*models.ServeConfig) string) func(*models.Config) string {
+	return func(c *models.Config) string {
+		if c.Serve == nil {
+			return ""
+		}
+		return get(c.Serve)
+	}
+}
+
+func projectThrash(get func(*models.ThrashConfig) string) func(*models.Config) string {
+	return func(c *models.Config) string {
+		if c.Thrash == nil {
+			return ""
+		}
+		return get(c.Thrash)
+	}
+}
+
+func projectScripts(get func(*models.ScriptHooksConfig) string) func(*models.Config) string {
+	return func(c *models.Config) string {
+		if c.ScriptHooks == nil {
+			return ""
+		}
+		return get(c.ScriptHooks)
+	}
+}
+
+var registry = []Setting{
+	// td serve
+	{
+		Key: "serve.port", Area: AreaServe, Description: "Port to listen on (0 = auto-assign)",
+		Default: "0", UserPath: "serve.port", ProjectPath: "serve.port", Env: "TD_SERVE_PORT", Flag: "td serve --port",
+		check:   isInt(0, 65535),
+		user:    userServe(func(c *models.ServeConfig) string { return itoa(c.Port) }),
+		project: projectServe(func(c *models.ServeConfig) string { return itoa(c.Port) }),
+	},
+	{
+		Key: "serve.addr", Area: AreaServe, Description: "Address to bind to",
+		Default: "localhost", UserPath: "serve.addr", ProjectPath: "serve.addr", Env: "TD_SERVE_ADDR", Flag: "td serve --addr",
+		user:    userServe(func(c *models.ServeConfig) string { return c.Addr }),
+		project: projectServe(func(c *models.ServeConfig) string { return c.Addr }),
+	},
+	{
+		Key: "serve.cors", Area: AreaServe, Description: "Allowed CORS origin",
+		Default: "", UserPath: "serve.cors", ProjectPath: "serve.cors", Env: "TD_SERVE_CORS", Flag: "td serve --cors",
+		user:    userServe(func(c *models.ServeConfig) string { return c.CORS }),
+		project: projectServe(func(c *models.ServeConfig) string { return c.CORS }),
+	},
+	{
+		Key: "serve.interval", Area: AreaServe, Description: "Poll interval for SSE events",
+		Default: "2s", UserPath: "serve.interval", ProjectPath: "serve.interval", Env: "TD_SERVE_INTERVAL", Flag: "td serve --interval",
+		check:   isDuration,
+		user:    userServe(func(c *models.ServeConfig) string { return c.Interval }),
+		project: projectServe(func(c *models.ServeConfig) string { return c.Interval }),
+	},
+	{
+		Key: "serve.crash_dumps", Area: AreaServe, Description: "Save recovered panics under .todos/crashes",
+		Default: "true", UserPath: "crash_dumps", Env: "TD_CRASH_DUMPS",
+		check: isBool,
+		user:  func(c *syncconfig.Config) string { return bptr(c.CrashDumps) },
+	},
+
+	// Sync
+	{
+		Key: "sync.url", Area: AreaSync, Description: "Sync server URL",
+		Default: "http://localhost:8080", UserPath: "sync.url", Env: "TD_SYNC_URL",
+		user: func(c *syncconfig.Config) string { return c.Sync.URL },
+	},
+	{
+		Key: "sync.enabled", Area: AreaSync, Description: "Sync is set up for this machine",
+		Default: "false", UserPath: "sync.enabled",
+		user: func(c *syncconfig.Config) string { return btoa(c.Sync.Enabled) },
+	},
+	{
+		Key: "sync.auto.enabled", Area: AreaSync, Description: "Sync automatically after changes",
+		Default: "true", UserPath: "sync.auto.enabled", Env: "TD_SYNC_AUTO",
+		check: isBool,
+		user:  func(c *syncconfig.Config) string { return bptr(c.Sync.Auto.Enabled) },
+	},
+	{
+		Key: "sync.auto.on_start", Area: AreaSync, Description: "Sync when a command starts",
+		Default: "true", UserPath: "sync.auto.on_start", Env: "TD_SYNC_AUTO_START",
+		check: isBool,
+		user:  func(c *syncconfig.Config) string { return bptr(c.Sync.Auto.OnStart) },
+	},
+	{
+		Key: "sync.auto.debounce", Area: AreaSync, Description: "Wait after a change before syncing",
+		Default: "3s", UserPath: "sync.auto.debounce", Env: "TD_SYNC_AUTO_DEBOUNCE",
+		check: isDuration,
+		user:  func(c *syncconfig.Config) string { return c.Sync.Auto.Debounce },
+	},
+	{
+		Key: "sync.auto.interval", Area: AreaSync, Description: "Periodic sync interval",
+		Default: "5m", UserPath: "sync.auto.interval", Env: "TD_SYNC_AUTO_INTERVAL",
+		check: isDuration,
+		user:  func(c *syncconfig.Config) string { return c.Sync.Auto.Interval },
+	},
+	{
+		Key: "sync.auto.pull", Area: AreaSync, Description: "Pull as well as push when syncing automatically",
+		Default: "true", UserPath: "sync.auto.pull", Env: "TD_SYNC_AUTO_PULL",
+		check: isBool,
+		user:  func(c *syncconfig.Config) string { return bptr(c.Sync.Auto.Pull) },
+	},
+	{
+		Key: "sync.snapshot_threshold", Area: AreaSync, Description: "Server events before bootstrapping from a snapshot",
+		Default: "100", UserPath: "sync.snapshot_threshold", Env: "TD_SYNC_SNAPSHOT_THRESHOLD",
+		check: isInt(0, 1<<31-1),
+		user:  func(c *syncconfig.Config) string { return iptr(c.Sync.SnapshotThreshold) },
+	},
+
+	// Webhooks
+	{
+		Key: "webhook.url", Area: AreaWebhook, Description: "URL that receives change events",
+		Default: "", UserPath: "webhook.url", ProjectPath: "webhook.url", Env: "TD_WEBHOOK_URL",
+		user: func(c *syncconfig.Config) string {
+			if c.Webhook == nil {
+				return ""
+			}
+			return c.Webhook.URL
+		},
+		project: func(c *models.Config) string {
+			if c.Webhook == nil {
+				return ""
+			}
+			return c.Webhook.URL
+		},
+	},
+
+	// Workflow
+	{
+		Key: "workflow.title_min_length", Area: AreaWorkflow, Description: "Shortest allowed issue title",
+		Default: strconv.Itoa(config.DefaultTitleMinLength), ProjectPath: "title_min_length",
+		check:   isInt(1, 1000),
+		project: func(c *models.Config) string { return itoa(c.TitleMinLength) },
+	},
+	{
+		Key: "workflow.title_max_length", Area: AreaWorkflow, Description: "Longest allowed issue title",
+		Default: strconv.Itoa(config.DefaultTitleMaxLength), ProjectPath: "title_max_length",
+		check:   isInt(1, 1000),
+		project: func(c *models.Config) string { return itoa(c.TitleMaxLength) },
+	},
+	{
+		Key: "workflow.max_hierarchy_depth", Area: AreaWorkflow, Description: "Deepest allowed parent chain",
+		Default: strconv.Itoa(config.DefaultMaxHierarchyDepth), ProjectPath: "max_hierarchy_depth",
+		check:   isInt(1, 1000),
+		project: func(c *models.Config) string { return itoa(c.MaxHierarchyDepth) },
+	},
+	{
+		Key: "workflow.score_formula", Area: AreaWorkflow, Description: "Issue scoring expression",
+		Default: score.DefaultFormula, ProjectPath: "score_formula",
+		project: func(c *models.Config) string { return c.ScoreFormula },
+	},
+	{
+		Key: "workflow.closed_immutable", Area: AreaWorkflow, Description: "Refuse edits to closed issues",
+		Default: "false", ProjectPath: "closed_immutable",
+		project: func(c *models.Config) string { return btoa(c.ClosedImmutable) },
+	},
+	{
+		Key: "workflow.timezone", Area: AreaWorkflow, Description: "Timezone for due dates and TDQ days",
+		Default: "", ProjectPath: "timezone", Env: "TD_TIMEZONE",
+		check:   isTimezone,
+		project: func(c *models.Config) string { return c.Timezone },
+	},
+	{
+		Key: "workflow.thrash.mode", Area: AreaWorkflow, Description: "Anti-thrash guard mode",
+		Default: thrash.DefaultMode, ProjectPath: "thrash.mode",
+		check:   oneOf(thrash.ModeOff, thrash.ModeWarn, thrash.ModeThrottle, thrash.ModeConfirm),
+		project: projectThrash(func(c *models.ThrashConfig) string { return c.Mode }),
+	},
+	{
+		Key: "workflow.thrash.window_minutes", Area: AreaWorkflow, Description: "Anti-thrash window",
+		Default: strconv.Itoa(thrash.DefaultWindowMinutes), ProjectPath: "thrash.window_minutes",
+		check:   isInt(1, 24*60),
+		project: projectThrash(func(c *models.ThrashConfig) string { return itoa(c.WindowMinutes) }),
+	},
+	{
+		Key: "workflow.thrash.max_reversals", Area: AreaWorkflow, Description: "Reversals allowed in the window",
+		Default: strconv.Itoa(thrash.DefaultMaxReversals), ProjectPath: "thrash.max_reversals",
+		check:   isInt(1, 1000),
+		project: projectThrash(func(c *models.ThrashConfig) string { return itoa(c.MaxReversals) }),
+	},
+	{
+		Key: "workflow.hook_scripts.timeout_seconds", Area: AreaWorkflow, Description: "Hook script timeout",
+		Default: strconv.Itoa(int(hookscripts.DefaultTimeout.Seconds())), ProjectPath: "script_hooks.timeout_seconds",
+		check:   isInt(1, 3600),
+		project: projectScripts(func(c *models.ScriptHooksConfig) string { return itoa(c.TimeoutSeconds) }),
+	},
+	{
+		Key: "workflow.hook_scripts.on_failure", Area: AreaWorkflow, Description: "What a failing hook script does",
+		Default: "fail for pre-, warn for post-", ProjectPath: "script_hooks.on_failure",
+		check:   oneOf(hookscripts.OnFailureFail, hookscripts.OnFailureWarn, hookscripts.OnFailureIgnore),
+		project: projectScripts(func(c *models.ScriptHooksConfig) string { return c.OnFailure }),
+	},
+
+	// td monitor
+	{
+		Key: "tui.locale", Area: AreaTUI, Description: "Language of td's messages",
+		Default: "system (LANG)", UserPath: "locale", Env: "TD_LANG",
+		check: func(v string) error {
+			if i18n.Normalize(v) == "" {
+				return fmt.Errorf("want one of %s", strings.Join(i18n.Locales(), ", "))
+			}
+			return nil
+		},
+		user: func(c *syncconfig.Config) string { return c.Locale },
+	},
+	{
+		Key: "tui.accessible", Area: AreaTUI, Description: "Monitor starts in accessible mode",
+		Default: "false", UserPath: "accessible", Env: "TD_ACCESSIBLE", Flag: "td monitor --accessible",
+		check: isBool,
+		user:  func(c *syncconfig.Config) string { return btoa(c.Accessible) },
+	},
+	{
+		Key: "tui.mouse", Area: AreaTUI, Description: "Monitor turns on mouse reporting",
+		Default: "true", UserPath: "mouse", Env: "TD_MOUSE", Flag: "td monitor --no-mouse",
+		check: isBool,
+		user:  func(c *syncconfig.Config) string { return bptr(c.Mouse) },
+	},
+	{
+		Key: "tui.sort_mode", Area: AreaTUI, Description: "Monitor sort order",
+		Default: "priority", ProjectPath: "sort_mode",
+		project: func(c *models.Config) string { return c.SortMode },
+	},
+	{
+		Key: "tui.type_filter", Area: AreaTUI, Description: "Monitor type filter",
+		Default: "", ProjectPath: "type_filter",
+		project: func(c *models.Config) string { return c.TypeFilter },
+	},
+	{
+		Key: "tui.include_closed", Area: AreaTUI, Description: "Monitor shows closed issues",
+		Default: "false", ProjectPath: "include_closed",
+		project: func(c *models.Config) string { return btoa(c.IncludeClosed) },
+	},
+	{
+		Key: "tui.preview_open", Area: AreaTUI, Description: "Monitor preview pane is open",
+		Default: "false", ProjectPath: "preview_open",
+		project: func(c *models.Config) string { return btoa(c.PreviewOpen) },
+	},
+	{
+		Key: "tui.preview_ratio", Area: AreaTUI, Description: "Monitor preview pane width",
+		Default: ftoa(config.DefaultPreviewRatio), ProjectPath: "preview_ratio",
+		check: func(v string) error {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil || f < config.MinPreviewRatio || f > config.MaxPreviewRatio {
+				return fmt.Errorf("want a ratio from %g to %g", config.MinPreviewRatio, config.MaxPreviewRatio)
+			}
+			return nil
+		},
+		project: func(c *models.Config) string { return ftoa(c.PreviewRatio) },
+	},
+}
@@ -0,0 +1,84 @@
+package settings
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/syncconfig"
+)
+
+func TestResolveLayers(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if err := syncconfig.SaveConfig(&syncconfig.Config{
+		Serve: &models.ServeConfig{Port: 7000, Addr: "0.0.0.0", Interval: "5s"},
+		Profiles: map[string]json.RawMessage{
+			"demo": json.RawMessage(`{"serve": {"addr": "127.0.0.1"}}`),
+		},
+	}); err != nil {
+		t.Fatalf("save user config: %v", err)
+	}
+
+	baseDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(baseDir, ".todos"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Save(baseDir, &models.Config{Serve: &models.ServeConfig{Port: 8000}, TitleMinLength: 20}); err != nil {
+		t.Fatalf("save project config: %v", err)
+	}
+
+	env := map[string]string{"TD_PROFILE": "demo", "TD_SERVE_PORT": "9000", "TD_SERVE_INTERVAL": "soon"}
+	values, problems := Resolve(baseDir, func(k string) string { return env[k] })
+	got := map[string]Value{}
+	for _, v := range values {
+		got[v.Key] = v
+	}
+
+	port := got["serve.port"]
+	if port.Value != "9000" || port.Source != SourceEnv {
+		t.Errorf("serve.port = %s from %s, want 9000 from env", port.Value, port.Source)
+	}
+	if len(port.Overridden) != 2 || port.Overridden[0] != (Layer{SourceUser, "7000"}) || port.Overridden[1] != (Layer{SourceProject, "8000"}) {
+		t.Errorf("serve.port overridden = %v, want user 7000 then project 8000", port.Overridden)
+	}
+	if addr := got["serve.addr"]; addr.Value != "127.0.0.1" || addr.Source != SourceProfile {
+		t.Errorf("serve.addr = %s from %s, want 127.0.0.1 from profile", addr.Value, addr.Source)
+	}
+	// An invalid value is skipped, as td skips it, and reported
+	if interval := got["serve.interval"]; interval.Value != "5s" || interval.Source != SourceUser {
+		t.Errorf("serve.interval = %s from %s, want 5s from user", interval.Value, interval.Source)
+	}
+	if len(problems) != 1 || !strings.Contains(problems[0], `"soon"`) {
+		t.Errorf("problems = %v, want the invalid interval", problems)
+	}
+	if title := got["workflow.title_min_length"]; title.Value != "20" || title.Source != SourceProject {
+		t.Errorf("title_min_length = %s from %s", title.Value, title.Source)
+	}
+	if sort := got["tui.sort_mode"]; sort.Value != "priority" || sort.Source != SourceDefault {
+		t.Errorf("sort_mode = %s from %s, want the default", sort.Value, sort.Source)
+	}
+
+	env["TD_PROFILE"] = "nope"
+	if _, problems := Resolve(baseDir, func(k string) string { return env[k] }); len(problems) != 2 {
+		t.Errorf("problems = %v, want the unknown profile reported", problems)
+	}
+}
+
+func TestRegistryKeysUnique(t *testing.T) {
+	seen := map[string]bool{}
+	for _, s := range All() {
+		if seen[s.Key] {
+			t.Errorf("duplicate setting %s", s.Key)
+		}
+		seen[s.Key] = true
+		if s.check != nil && s.Default != "" && !strings.Contains(s.Default, " ") {
+			if err := s.check(s.Default); err != nil {
+				t.Errorf("%s default %q fails its own check: %v", s.Key, s.Default, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package share
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSignVerify(t *testing.T) {
+	secret, err := NewSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := Sign(secret, "sh-1a2b3c4d", "td-abc")
+
+	if id, err := Verify(secret, token, "td-abc"); err != nil || id != "sh-1a2b3c4d" {
+		t.Fatalf("Verify = %q, %v", id, err)
+	}
+
+	other, _ := NewSecret()
+	for name, tt := range map[string]struct{ secret, token, issue string }{
+		"other issue":  {secret, token, "td-xyz"},
+		"other secret": {other, token, "td-abc"},
+		"no secret":    {"", token, "td-abc"},
+		"tampered id":  {secret, "sh-ffffffff" + token[len("sh-1a2b3c4d"):], "td-abc"},
+		"no signature": {secret, "sh-1a2b3c4d", "td-abc"},
+		"empty":        {secret, "", "td-abc"},
+	} {
+		if _, err := Verify(tt.secret, tt.token, tt.issue); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: err = %v, want ErrInvalidToken", name, err)
+		}
+	}
+}
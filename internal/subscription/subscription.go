@@ -0,0 +1,159 @@
+// Package subscription filters change events down to the issues an
+// integration cares about. A subscription names a label, an epic or a TDQ
+// query; filtered webhooks deliver only the actions touching matching
+// issues, and td serve's event stream only the refreshes they appear in.
+package subscription
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/query"
+)
+
+// Validate checks a subscription before it is stored: it needs at least
+// one criterion, a valid query, and an http(s) URL if it has one.
+func Validate(sub models.Subscription) error {
+	if sub.Label == "" && sub.Epic == "" && sub.Query == "" {
+		return fmt.Errorf("a subscription needs a label, epic or query")
+	}
+	if _, err := Query(sub); err != nil {
+		return err
+	}
+	if sub.URL != "" {
+		u, err := url.Parse(sub.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid URL %q: use http or https", sub.URL)
+		}
+	}
+	return nil
+}
+
+// Query builds the TDQ filter the subscriptions describe, ANDing every
+// criterion of every subscription. It returns nil when there are none.
+func Query(subs ...models.Subscription) (*query.Query, error) {
+	var nodes []query.Node
+	for _, sub := range subs {
+		if sub.Label != "" {
+			nodes = append(nodes, &query.FunctionCall{Name: "label", Args: []interface{}{sub.Label}})
+		}
+		if sub.Epic != "" {
+			// The epic itself as well as everything under it
+			epic := db.NormalizeIssueID(sub.Epic)
+			nodes = append(nodes, query.Or(
+				&query.FieldExpr{Field: "id", Operator: query.OpEq, Value: epic},
+				&query.FieldExpr{Field: "epic", Operator: query.OpEq, Value: epic},
+			))
+		}
+		if sub.Query != "" {
+			parsed, err := query.Parse(sub.Query)
+			if err != nil {
+				return nil, fmt.Errorf("invalid query: %w", err)
+			}
+			nodes = append(nodes, parsed.Root)
+		}
+	}
+	if len(nodes) == 0 {
+		return nil, nil
+	}
+	q := &query.Query{Root: query.And(nodes...)}
+	if verrs := q.Validate(); len(verrs) > 0 {
+		return nil, fmt.Errorf("invalid query: %w", verrs[0])
+	}
+	q.Raw = q.String()
+	return q, nil
+}
+
+// Webhooks returns the subscriptions delivered to a URL. Config errors
+// return none.
+func Webhooks(baseDir string) []models.Subscription {
+	subs, err := config.GetSubscriptions(baseDir)
+	if err != nil {
+		return nil
+	}
+	var hooks []models.Subscription
+	for _, sub := range subs {
+		if sub.URL != "" {
+			hooks = append(hooks, sub)
+		}
+	}
+	return hooks
+}
+
+// Describe summarizes a subscription's criteria, e.g.
+// "label security, epic td-a1b2"
+func Describe(sub models.Subscription) string {
+	var parts []string
+	if sub.Label != "" {
+		parts = append(parts, "label "+sub.Label)
+	}
+	if sub.Epic != "" {
+		parts = append(parts, "epic "+sub.Epic)
+	}
+	if sub.Query != "" {
+		parts = append(parts, "query "+sub.Query)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FilterActions returns the actions that touch an issue the filter
+// matches, as it is now or as an action found it, so receivers hear about
+// issues leaving their view as well as entering it. Matching is per issue:
+// every action on a matching issue is kept. Actions that belong to no
+// issue are dropped.
+func FilterActions(database *db.DB, filter *query.Query, actions []models.ActionLog) ([]models.ActionLog, error) {
+	if filter == nil {
+		return actions, nil
+	}
+
+	issueIDs := make([]string, len(actions))
+	var ids []string
+	var before []models.Issue
+	seen := make(map[string]bool)
+	for i, a := range actions {
+		id := db.ActionIssueID(a.EntityType, a.EntityID, a.PreviousData, a.NewData)
+		issueIDs[i] = id
+		if id == "" {
+			continue
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+		if id == a.EntityID {
+			var prev models.Issue
+			if json.Unmarshal([]byte(a.PreviousData), &prev) == nil && prev.ID != "" {
+				before = append(before, prev)
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	issues, err := database.GetIssuesByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := query.Match(database, filter, "", append(issues, before...))
+	if err != nil {
+		return nil, err
+	}
+	matched := make(map[string]bool, len(matches))
+	for _, issue := range matches {
+		matched[issue.ID] = true
+	}
+
+	var out []models.ActionLog
+	for i, a := range actions {
+		if matched[issueIDs[i]] {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
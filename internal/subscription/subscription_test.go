@@ -0,0 +1,102 @@
+package subscription
+
+import (
+	"testing"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+func TestFilterActions(t *testing.T) {
+	database, err := db.Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	defer database.Close()
+
+	before, err := database.MaxActionRowid()
+	if err != nil {
+		t.Fatal(err)
+	}
+	create := func(issue *models.Issue) *models.Issue {
+		t.Helper()
+		if err := database.CreateIssueLogged(issue, "ses_a"); err != nil {
+			t.Fatalf("CreateIssueLogged: %v", err)
+		}
+		return issue
+	}
+	epic := create(&models.Issue{Title: "Checkout redesign", Type: models.TypeEpic})
+	child := create(&models.Issue{Title: "New payment form", ParentID: epic.ID})
+	secure := create(&models.Issue{Title: "Escape search input", Labels: []string{"security"}})
+	other := create(&models.Issue{Title: "Dark mode toggle"})
+
+	// A label removed in the change still reaches the subscriber
+	other.Labels = []string{"security"}
+	if err := database.UpdateIssueLogged(other, "ses_a", models.ActionUpdate); err != nil {
+		t.Fatal(err)
+	}
+	other.Labels = nil
+	if err := database.UpdateIssueLogged(other, "ses_a", models.ActionUpdate); err != nil {
+		t.Fatal(err)
+	}
+
+	actions, err := database.GetActionsAfterRowid(before)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		sub  models.Subscription
+		want map[string]int // issue ID -> matching actions
+	}{
+		{"label", models.Subscription{Label: "security"}, map[string]int{secure.ID: 1, other.ID: 3}},
+		{"epic", models.Subscription{Epic: epic.ID}, map[string]int{epic.ID: 1, child.ID: 1}},
+		{"label and query", models.Subscription{Label: "security", Query: "title ~ escape"}, map[string]int{secure.ID: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := Query(tt.sub)
+			if err != nil {
+				t.Fatalf("Query: %v", err)
+			}
+			got, err := FilterActions(database, filter, actions)
+			if err != nil {
+				t.Fatalf("FilterActions: %v", err)
+			}
+			counts := make(map[string]int)
+			for _, a := range got {
+				counts[a.EntityID]++
+			}
+			if len(counts) != len(tt.want) {
+				t.Fatalf("matched %v, want %v", counts, tt.want)
+			}
+			for id, n := range tt.want {
+				if counts[id] != n {
+					t.Errorf("%s: %d actions, want %d", id, counts[id], n)
+				}
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		sub  models.Subscription
+		ok   bool
+	}{
+		{"label", models.Subscription{Label: "security"}, true},
+		{"query with url", models.Subscription{Query: "priority <= P1", URL: "https://example.com/hook"}, true},
+		{"no criteria", models.Subscription{URL: "https://example.com/hook"}, false},
+		{"bad query", models.Subscription{Query: "priority <="}, false},
+		{"bad url", models.Subscription{Label: "security", URL: "example.com/hook"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Validate(tt.sub); (err == nil) != tt.ok {
+				t.Errorf("Validate() = %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+}
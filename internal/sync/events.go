@@ -79,6 +79,9 @@ func checkAndResolveCyclicDependency(tx *sql.Tx, event Event) bool {
 	if issueID == "" || dependsOnID == "" {
 		return false // missing fields, let upsert handle validation
 	}
+	if rt, ok := fields["relation_type"].(string); ok && rt != "depends_on" {
+		return false // only depends_on edges form the dependency graph
+	}
 
 	if !wouldCreateCycleTx(tx, issueID, dependsOnID) {
 		return false // no cycle, proceed with create
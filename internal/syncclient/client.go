@@ -149,6 +149,29 @@ func (c *Client) HealthCheck() (*HealthResponse, error) {
 	return &resp, nil
 }
 
+// ClockSkew estimates how far the local clock is ahead of the server's
+// (negative when behind) from the Date header of /healthz, taken as the
+// server's time halfway through the request. Date has one-second
+// resolution, so skews of a second or two are noise.
+func (c *Client) ClockSkew() (time.Duration, error) {
+	start := time.Now()
+	resp, err := c.HTTP.Get(c.BaseURL + "/healthz")
+	if err != nil {
+		return 0, fmt.Errorf("http request: %w", err)
+	}
+	resp.Body.Close()
+	end := time.Now()
+
+	server, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return 0, fmt.Errorf("server sent no usable Date header")
+	}
+	// Date is truncated to the second; its midpoint is the best guess
+	server = server.Add(500 * time.Millisecond)
+	local := start.Add(end.Sub(start) / 2)
+	return local.Sub(server), nil
+}
+
 // --- Auth methods ---
 
 // LoginStart initiates device auth flow. No API key required.
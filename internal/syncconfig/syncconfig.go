@@ -35,6 +35,19 @@ type SyncConfig struct {
 type Config struct {
 	Sync    SyncConfig            `json:"sync"`
 	Webhook *models.WebhookConfig `json:"webhook,omitempty"`
+	Notify  *models.NotifyConfig  `json:"notify,omitempty"`
+	Serve   *models.ServeConfig   `json:"serve,omitempty"`
+	// Language for td's messages, e.g. "es"; empty follows LANG
+	Locale string `json:"locale,omitempty"`
+	// Start td monitor in accessible mode
+	Accessible bool `json:"accessible,omitempty"`
+	// Mouse reporting in td monitor; nil means on
+	Mouse *bool `json:"mouse,omitempty"`
+	// Save crash dumps under .todos/crashes when td recovers from a panic;
+	// nil means on
+	CrashDumps *bool `json:"crash_dumps,omitempty"`
+	// Named partial configs laid over this one when TD_PROFILE names them
+	Profiles map[string]json.RawMessage `json:"profiles,omitempty"`
 }
 
 // AuthCredentials stores authentication state at ~/.config/td/auth.json.
@@ -82,6 +95,47 @@ func LoadConfig() (*Config, error) {
 	return &cfg, nil
 }
 
+// ActiveProfile returns the profile named by TD_PROFILE, or "" for none.
+func ActiveProfile() string {
+	return strings.TrimSpace(os.Getenv("TD_PROFILE"))
+}
+
+// LoadProfile returns the named profile's settings on their own, without
+// the rest of the global config.
+func LoadProfile(cfg *Config, name string) (*Config, error) {
+	raw, ok := cfg.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown profile %q", name)
+	}
+	var profile Config
+	if err := json.Unmarshal(raw, &profile); err != nil {
+		return nil, fmt.Errorf("profile %q: %w", name, err)
+	}
+	return &profile, nil
+}
+
+// LoadActiveConfig reads the global config with the TD_PROFILE profile laid
+// over it: settings the profile sets win, the rest come from the config. A
+// profile that is missing or unreadable is left out; td config doctor
+// reports it. Use LoadConfig to edit the config itself.
+func LoadActiveConfig() (*Config, error) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	name := ActiveProfile()
+	if name == "" {
+		return cfg, nil
+	}
+	if _, err := LoadProfile(cfg, name); err != nil {
+		return cfg, nil
+	}
+	if err := json.Unmarshal(cfg.Profiles[name], cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
 // SaveConfig writes the global config to ~/.config/td/config.json.
 func SaveConfig(cfg *Config) error {
 	dir, err := ConfigDir()
@@ -142,12 +196,12 @@ func ClearAuth() error {
 }
 
 // GetServerURL returns the sync server URL.
-// Priority: TD_SYNC_URL env > config.json > default.
+// Priority: TD_SYNC_URL env > config.json (and TD_PROFILE profile) > default.
 func GetServerURL() string {
 	if v := os.Getenv("TD_SYNC_URL"); v != "" {
 		return v
 	}
-	cfg, err := LoadConfig()
+	cfg, err := LoadActiveConfig()
 	if err == nil && cfg.Sync.URL != "" {
 		return cfg.Sync.URL
 	}
@@ -162,13 +216,27 @@ func GetSnapshotThreshold() int {
 			return n
 		}
 	}
-	cfg, err := LoadConfig()
+	cfg, err := LoadActiveConfig()
 	if err == nil && cfg.Sync.SnapshotThreshold != nil && *cfg.Sync.SnapshotThreshold >= 0 {
 		return *cfg.Sync.SnapshotThreshold
 	}
 	return 100
 }
 
+// GetLocale returns the configured language for td's messages, or "" to
+// follow the system's.
+// Priority: TD_LANG env > config.json (and TD_PROFILE profile).
+func GetLocale() string {
+	if v := os.Getenv("TD_LANG"); v != "" {
+		return v
+	}
+	cfg, err := LoadActiveConfig()
+	if err == nil {
+		return cfg.Locale
+	}
+	return ""
+}
+
 // GetAPIKey returns the API key.
 // Priority: TD_AUTH_KEY env > auth.json.
 func GetAPIKey() string {
@@ -226,13 +294,49 @@ func parseBoolEnv(envKey string) *bool {
 	return nil
 }
 
+// GetAccessible returns whether td monitor starts in accessible mode.
+// Priority: TD_ACCESSIBLE env > config.json accessible > false
+func GetAccessible() bool {
+	if v := parseBoolEnv("TD_ACCESSIBLE"); v != nil {
+		return *v
+	}
+	cfg, err := LoadActiveConfig()
+	return err == nil && cfg.Accessible
+}
+
+// GetMouse returns whether td monitor turns on mouse reporting.
+// Priority: TD_MOUSE env > config.json mouse > true
+func GetMouse() bool {
+	if v := parseBoolEnv("TD_MOUSE"); v != nil {
+		return *v
+	}
+	cfg, err := LoadActiveConfig()
+	if err == nil && cfg.Mouse != nil {
+		return *cfg.Mouse
+	}
+	return true
+}
+
+// GetCrashDumps returns whether recovered panics are saved as crash dumps.
+// Priority: TD_CRASH_DUMPS env > config.json crash_dumps > true
+func GetCrashDumps() bool {
+	if v := parseBoolEnv("TD_CRASH_DUMPS"); v != nil {
+		return *v
+	}
+	cfg, err := LoadActiveConfig()
+	if err == nil && cfg.CrashDumps != nil {
+		return *cfg.CrashDumps
+	}
+	return true
+}
+
 // GetAutoSyncEnabled returns whether auto-sync is enabled.
 // Priority: TD_SYNC_AUTO env > config.json sync.auto.enabled > true
 func GetAutoSyncEnabled() bool {
 	if v := parseBoolEnv("TD_SYNC_AUTO"); v != nil {
 		return *v
 	}
-	cfg, err := LoadConfig()
+	cfg, err := LoadActiveConfig()
 	if err == nil && cfg.Sync.Auto.Enabled != nil {
 		return *cfg.Sync.Auto.Enabled
 	}
@@ -245,7 +349,7 @@ func GetAutoSyncOnStart() bool {
 	if v := parseBoolEnv("TD_SYNC_AUTO_START"); v != nil {
 		return *v
 	}
-	cfg, err := LoadConfig()
+	cfg, err := LoadActiveConfig()
 	if err == nil && cfg.Sync.Auto.OnStart != nil {
 		return *cfg.Sync.Auto.OnStart
 	}
@@ -260,7 +364,7 @@ func GetAutoSyncDebounce() time.Duration {
 			return d
 		}
 	}
-	cfg, err := LoadConfig()
+	cfg, err := LoadActiveConfig()
 	if err == nil && cfg.Sync.Auto.Debounce != "" {
 		if d, err := time.ParseDuration(cfg.Sync.Auto.Debounce); err == nil {
 			return d
@@ -277,7 +381,7 @@ func GetAutoSyncInterval() time.Duration {
 			return d
 		}
 	}
-	cfg, err := LoadConfig()
+	cfg, err := LoadActiveConfig()
 	if err == nil && cfg.Sync.Auto.Interval != "" {
 		if d, err := time.ParseDuration(cfg.Sync.Auto.Interval); err == nil {
 			return d
@@ -292,7 +396,7 @@ func GetAutoSyncPull() bool {
 	if v := parseBoolEnv("TD_SYNC_AUTO_PULL"); v != nil {
 		return *v
 	}
-	cfg, err := LoadConfig()
+	cfg, err := LoadActiveConfig()
 	if err == nil && cfg.Sync.Auto.Pull != nil {
 		return *cfg.Sync.Auto.Pull
 	}
@@ -162,3 +162,33 @@ func TestAutoSyncEnvOverridesConfig(t *testing.T) {
 		t.Error("env should override config for pull")
 	}
 }
+
+func TestProfileOverlaysConfig(t *testing.T) {
+	writeTestConfig(t, &Config{
+		Sync: SyncConfig{URL: "https://sync.example.com", Auto: AutoSyncConfig{Interval: "15m"}},
+		Profiles: map[string]json.RawMessage{
+			"ci": json.RawMessage(`{"sync": {"auto": {"interval": "1m"}}}`),
+		},
+	})
+	t.Setenv("TD_SYNC_URL", "")
+	t.Setenv("TD_SYNC_AUTO_INTERVAL", "")
+
+	t.Setenv("TD_PROFILE", "")
+	if d := GetAutoSyncInterval(); d != 15*time.Minute {
+		t.Errorf("without profile: interval = %v, want 15m", d)
+	}
+
+	t.Setenv("TD_PROFILE", "ci")
+	if d := GetAutoSyncInterval(); d != time.Minute {
+		t.Errorf("ci profile: interval = %v, want 1m", d)
+	}
+	if url := GetServerURL(); url != "https://sync.example.com" {
+		t.Errorf("ci profile: url = %q, settings it leaves unset should come from the config", url)
+	}
+
+	// An unknown profile leaves the config as it is
+	t.Setenv("TD_PROFILE", "missing")
+	if d := GetAutoSyncInterval(); d != 15*time.Minute {
+		t.Errorf("unknown profile: interval = %v, want 15m", d)
+	}
+}
@@ -0,0 +1,25 @@
+package textdiff
+
+import "testing"
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name          string
+		before, after string
+		want          string
+	}{
+		{"unchanged", "a\nb", "a\nb", " a\n b\n"},
+		{"changed line", "a\nb\nc", "a\nB\nc", " a\n-b\n+B\n c\n"},
+		{"appended", "a", "a\nb\n", " a\n+b\n"},
+		{"from empty", "", "a", "+a\n"},
+		{"to empty", "a\nb", "", "-a\n-b\n"},
+		{"both empty", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Unified(Lines(tt.before, tt.after)); got != tt.want {
+				t.Errorf("diff = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
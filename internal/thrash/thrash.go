@@ -0,0 +1,144 @@
+// Package thrash detects a session flipping an issue back and forth: a
+// close/reopen loop, or a priority that keeps bouncing between two values.
+//
+// A reversal is a change that sets a field back to a value it already had
+// earlier in the window. Each session's changes to each issue are counted
+// separately; when one more reversal would exceed the limit the change is
+// logged and, depending on the mode, allowed, throttled until the window
+// cools down, or held for explicit confirmation.
+package thrash
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/marcus/td/internal/models"
+)
+
+// Modes
+const (
+	ModeOff      = "off"      // no checks
+	ModeWarn     = "warn"     // log the anomaly, allow the change
+	ModeThrottle = "throttle" // reject until enough reversals leave the window
+	ModeConfirm  = "confirm"  // reject unless the caller confirms
+)
+
+// Defaults for an unset ThrashConfig
+const (
+	DefaultMode          = ModeConfirm
+	DefaultWindowMinutes = 10
+	DefaultMaxReversals  = 3
+)
+
+// Fields the guard watches
+const (
+	FieldStatus   = "status"
+	FieldPriority = "priority"
+)
+
+// IsValidMode reports whether mode is a known mode
+func IsValidMode(mode string) bool {
+	switch mode {
+	case ModeOff, ModeWarn, ModeThrottle, ModeConfirm:
+		return true
+	}
+	return false
+}
+
+// WithDefaults fills in unset config values
+func WithDefaults(cfg *models.ThrashConfig) models.ThrashConfig {
+	var c models.ThrashConfig
+	if cfg != nil {
+		c = *cfg
+	}
+	if c.Mode == "" {
+		c.Mode = DefaultMode
+	}
+	if c.WindowMinutes <= 0 {
+		c.WindowMinutes = DefaultWindowMinutes
+	}
+	if c.MaxReversals <= 0 {
+		c.MaxReversals = DefaultMaxReversals
+	}
+	return c
+}
+
+// Change is one field change made by a session
+type Change struct {
+	Field string
+	From  string
+	To    string
+	At    time.Time
+}
+
+// Changes lists the watched fields that differ between two versions of an
+// issue
+func Changes(prev, next *models.Issue, at time.Time) []Change {
+	var out []Change
+	if prev.Status != next.Status {
+		out = append(out, Change{FieldStatus, string(prev.Status), string(next.Status), at})
+	}
+	if prev.Priority != next.Priority {
+		out = append(out, Change{FieldPriority, string(prev.Priority), string(next.Priority), at})
+	}
+	return out
+}
+
+// Verdict describes a change that would exceed the reversal limit
+type Verdict struct {
+	Field      string
+	Reversals  int           // reversals in the window, including the proposed change
+	Window     time.Duration // the configured window
+	RetryAfter time.Duration // until the count drops back under the limit
+}
+
+func (v *Verdict) String() string {
+	return fmt.Sprintf("%s reversed %d times in %s", v.Field, v.Reversals, v.Window)
+}
+
+// Check counts the reversals in history (the session's earlier changes to
+// the issue, any order) plus proposed, per field, within the window ending
+// at now. It returns a verdict for the first field over the limit, or nil.
+func Check(history, proposed []Change, cfg models.ThrashConfig, now time.Time) *Verdict {
+	cfg = WithDefaults(&cfg)
+	if cfg.Mode == ModeOff {
+		return nil
+	}
+	window := time.Duration(cfg.WindowMinutes) * time.Minute
+	since := now.Add(-window)
+
+	for _, p := range proposed {
+		var seq []Change
+		for _, c := range history {
+			if c.Field == p.Field && !c.At.Before(since) {
+				seq = append(seq, c)
+			}
+		}
+		sort.SliceStable(seq, func(i, j int) bool { return seq[i].At.Before(seq[j].At) })
+		seq = append(seq, p)
+
+		seen := map[string]bool{seq[0].From: true}
+		var reversals []time.Time
+		for _, c := range seq {
+			if seen[c.To] {
+				reversals = append(reversals, c.At)
+			}
+			seen[c.From], seen[c.To] = true, true
+		}
+		if len(reversals) <= cfg.MaxReversals {
+			continue
+		}
+
+		// The count drops to the limit once enough of the earliest
+		// reversals age out of the window
+		expire := reversals[len(reversals)-1-cfg.MaxReversals].Add(window)
+		return &Verdict{
+			Field:      p.Field,
+			Reversals:  len(reversals),
+			Window:     window,
+			RetryAfter: expire.Sub(now),
+		}
+	}
+	return nil
+}
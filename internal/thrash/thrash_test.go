@@ -0,0 +1,64 @@
+package thrash
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestCheck(t *testing.T) {
+	now := time.Date(2026, 3, 9, 12, 0, 0, 0, time.UTC)
+	cfg := models.ThrashConfig{Mode: ModeConfirm, WindowMinutes: 10, MaxReversals: 1}
+	change := func(from, to string, minsAgo int) Change {
+		return Change{FieldStatus, from, to, now.Add(-time.Duration(minsAgo) * time.Minute)}
+	}
+
+	// close, reopen, close: the second close returns to a value already
+	// held, the first reopen does not (the issue was in review before)
+	history := []Change{
+		change("in_review", "closed", 8),
+		change("closed", "open", 6),
+		change("open", "closed", 4),
+	}
+	reopen := []Change{change("closed", "open", 0)}
+
+	v := Check(history, reopen, cfg, now)
+	if v == nil || v.Field != FieldStatus || v.Reversals != 2 {
+		t.Fatalf("Check = %+v, want 2 status reversals", v)
+	}
+	// The earlier reversal (4 min ago) leaves the window in 6 min
+	if v.RetryAfter != 6*time.Minute {
+		t.Errorf("RetryAfter = %s, want 6m", v.RetryAfter)
+	}
+
+	// A change to a new value is not a reversal
+	if v := Check(history, []Change{change("closed", "in_progress", 0)}, cfg, now); v != nil {
+		t.Errorf("forward change flagged: %+v", v)
+	}
+	// Changes outside the window are ignored
+	cfg.WindowMinutes = 3
+	if v := Check(history, reopen, cfg, now); v != nil {
+		t.Errorf("old changes counted: %+v", v)
+	}
+	// Off disables the guard
+	if v := Check(history, reopen, models.ThrashConfig{Mode: ModeOff, MaxReversals: 1}, now); v != nil {
+		t.Errorf("off mode flagged: %+v", v)
+	}
+	// Other fields are counted separately
+	priority := []Change{{FieldPriority, "P2", "P1", now}}
+	if v := Check(history, priority, models.ThrashConfig{MaxReversals: 1}, now); v != nil {
+		t.Errorf("priority flagged for status reversals: %+v", v)
+	}
+}
+
+func TestWithDefaults(t *testing.T) {
+	got := WithDefaults(nil)
+	want := models.ThrashConfig{Mode: DefaultMode, WindowMinutes: DefaultWindowMinutes, MaxReversals: DefaultMaxReversals}
+	if got != want {
+		t.Errorf("WithDefaults(nil) = %+v, want %+v", got, want)
+	}
+	if got := WithDefaults(&models.ThrashConfig{Mode: ModeWarn}); got.Mode != ModeWarn || got.MaxReversals != DefaultMaxReversals {
+		t.Errorf("WithDefaults = %+v", got)
+	}
+}
@@ -0,0 +1,227 @@
+// Package triage implements the triage inbox. Issues created from the
+// sources a project routes to the inbox (td policy inbox), or explicitly
+// with --inbox, wait there, out of the ready lists, until someone accepts,
+// rejects, merges or defers them.
+//
+// Actions work on many issues at once and skip the ones they cannot apply
+// to, so one stale ID does not stop a bulk triage.
+package triage
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/dateparse"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/dedupe"
+	"github.com/marcus/td/internal/models"
+)
+
+// Sources a project can route to the inbox
+const (
+	SourceCLI         = "cli"         // td create
+	SourceAPI         = "api"         // POST /v1/issues
+	SourceImport      = "import"      // td import and CSV imports
+	SourceIntegration = "integration" // chat integrations
+)
+
+// Sources lists every source, in the order td policy shows them
+var Sources = []string{SourceCLI, SourceAPI, SourceImport, SourceIntegration}
+
+// ValidateSources checks the sources of td policy inbox
+func ValidateSources(sources []string) error {
+	for _, s := range sources {
+		if !slices.Contains(Sources, s) {
+			return fmt.Errorf("unknown source %q: use %s", s, strings.Join(Sources, ", "))
+		}
+	}
+	return nil
+}
+
+// Routes reports whether issues created from source land in the inbox.
+// Config errors route nothing.
+func Routes(baseDir, source string) bool {
+	sources, err := config.GetInboxSources(baseDir)
+	return err == nil && slices.Contains(sources, source)
+}
+
+// List returns the unclosed issues in the inbox, oldest first. Issues
+// deferred in triage are left out until their date.
+func List(database *db.DB) ([]models.Issue, error) {
+	return database.ListIssues(db.ListIssuesOptions{
+		Status:          []models.Status{models.StatusOpen, models.StatusInProgress, models.StatusBlocked, models.StatusInReview},
+		InboxOnly:       true,
+		ExcludeDeferred: true,
+		SortBy:          "created_at",
+	})
+}
+
+// Action is a triage decision
+type Action string
+
+const (
+	ActionAccept Action = "accept" // leave the inbox as ordinary work
+	ActionReject Action = "reject" // close with a reason
+	ActionMerge  Action = "merge"  // close as duplicates of another issue
+	ActionDefer  Action = "defer"  // hide until a date, then triage again
+)
+
+// Actions lists every action
+var Actions = []Action{ActionAccept, ActionReject, ActionMerge, ActionDefer}
+
+// Request is a bulk triage decision
+type Request struct {
+	Action Action   `json:"action"`
+	IDs    []string `json:"ids"`
+	Reason string   `json:"reason,omitempty"` // reject
+	Into   string   `json:"into,omitempty"`   // merge
+	Until  string   `json:"until,omitempty"`  // defer; any date td defer accepts
+}
+
+// Validate checks a request and resolves Until to a date
+func (r *Request) Validate() error {
+	if !slices.Contains(Actions, r.Action) {
+		return fmt.Errorf("unknown action %q: use accept, reject, merge or defer", r.Action)
+	}
+	if len(r.IDs) == 0 {
+		return fmt.Errorf("no issues given")
+	}
+	switch r.Action {
+	case ActionReject:
+		if strings.TrimSpace(r.Reason) == "" {
+			return fmt.Errorf("reject needs a reason")
+		}
+	case ActionMerge:
+		if r.Into == "" {
+			return fmt.Errorf("merge needs an issue to merge into")
+		}
+	case ActionDefer:
+		if r.Until == "" {
+			return fmt.Errorf("defer needs a date")
+		}
+		until, err := dateparse.ParseDate(r.Until)
+		if err != nil {
+			return fmt.Errorf("invalid date: %w", err)
+		}
+		r.Until = until
+	}
+	return nil
+}
+
+// Skip is an issue a triage action passed over
+type Skip struct {
+	ID     string `json:"id"`
+	Reason string `json:"reason"`
+}
+
+// Result reports a bulk triage decision
+type Result struct {
+	Action  Action              `json:"action"`
+	Done    []string            `json:"done"`
+	Skipped []Skip              `json:"skipped,omitempty"`
+	Until   string              `json:"until,omitempty"` // defer, as a date
+	Merge   *dedupe.MergeResult `json:"merge,omitempty"`
+}
+
+// Apply carries out a triage decision. Issues that are not in the inbox,
+// or that the action fails on, are skipped; the error is for requests
+// that are invalid as a whole, or a merge that fails.
+func Apply(database *db.DB, req Request, sessionID string) (*Result, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	result := &Result{Action: req.Action, Done: []string{}, Until: req.Until}
+
+	var issues []*models.Issue
+	for _, id := range req.IDs {
+		issue, err := database.GetIssue(id)
+		switch {
+		case err != nil:
+			result.Skipped = append(result.Skipped, Skip{ID: id, Reason: err.Error()})
+		case !issue.Inbox:
+			result.Skipped = append(result.Skipped, Skip{ID: issue.ID, Reason: "not in the inbox"})
+		case issue.Status == models.StatusClosed:
+			result.Skipped = append(result.Skipped, Skip{ID: issue.ID, Reason: "already closed"})
+		default:
+			issues = append(issues, issue)
+		}
+	}
+
+	if req.Action == ActionMerge {
+		return result, merge(database, req, issues, sessionID, result)
+	}
+	for _, issue := range issues {
+		if err := apply(database, req, issue, sessionID); err != nil {
+			result.Skipped = append(result.Skipped, Skip{ID: issue.ID, Reason: err.Error()})
+			continue
+		}
+		result.Done = append(result.Done, issue.ID)
+	}
+	return result, nil
+}
+
+// apply carries out an accept, reject or defer on one issue
+func apply(database *db.DB, req Request, issue *models.Issue, sessionID string) error {
+	action := models.ActionUpdate
+	var msg string
+	switch req.Action {
+	case ActionAccept:
+		issue.Inbox = false
+		msg = "Accepted in triage"
+	case ActionReject:
+		issue.Inbox = false
+		now := time.Now()
+		issue.Status = models.StatusClosed
+		issue.ClosedAt = &now
+		action = models.ActionClose
+		msg = "Rejected in triage: " + req.Reason
+	case ActionDefer:
+		// Stays in the inbox, to be triaged again once the date comes
+		if issue.DeferUntil != nil && req.Until > *issue.DeferUntil {
+			issue.DeferCount++
+		}
+		issue.DeferUntil = &req.Until
+		msg = "Triage deferred until " + req.Until
+	}
+	if err := database.UpdateIssueLogged(issue, sessionID, action); err != nil {
+		return err
+	}
+	return database.AddLog(&models.Log{
+		IssueID:   issue.ID,
+		SessionID: sessionID,
+		Message:   msg,
+		Type:      models.LogTypeProgress,
+	})
+}
+
+// merge closes the issues as duplicates of req.Into and takes them out of
+// the inbox
+func merge(database *db.DB, req Request, issues []*models.Issue, sessionID string, result *Result) error {
+	if len(issues) == 0 {
+		return nil
+	}
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	merged, err := dedupe.Merge(database, req.Into, ids, sessionID)
+	if err != nil {
+		return err
+	}
+	result.Merge = merged
+	for _, id := range merged.Closed {
+		issue, err := database.GetIssue(id)
+		if err != nil {
+			return err
+		}
+		issue.Inbox = false
+		if err := database.UpdateIssueLogged(issue, sessionID, models.ActionUpdate); err != nil {
+			return err
+		}
+		result.Done = append(result.Done, id)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package triage
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+func TestApply(t *testing.T) {
+	database, err := db.Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	defer database.Close()
+
+	create := func(title string, inbox bool) *models.Issue {
+		t.Helper()
+		issue := &models.Issue{Title: title, Inbox: inbox}
+		if err := database.CreateIssueLogged(issue, "ses_bot"); err != nil {
+			t.Fatalf("CreateIssueLogged: %v", err)
+		}
+		return issue
+	}
+	accepted := create("Add export command", true)
+	rejected := create("Make it faster", true)
+	deferred := create("Try the new parser", true)
+	dup := create("Export command", true)
+	triaged := create("Fix login timeout", false)
+
+	inbox, err := List(database)
+	if err != nil || len(inbox) != 4 {
+		t.Fatalf("List = %d issues, err %v; want 4", len(inbox), err)
+	}
+
+	res, err := Apply(database, Request{Action: ActionAccept, IDs: []string{accepted.ID, triaged.ID, "td-missing"}}, "ses_lead")
+	if err != nil {
+		t.Fatalf("accept: %v", err)
+	}
+	if !slices.Equal(res.Done, []string{accepted.ID}) || len(res.Skipped) != 2 {
+		t.Errorf("accept = %+v, want only %s done", res, accepted.ID)
+	}
+	if got, _ := database.GetIssue(accepted.ID); got.Inbox || got.Status != models.StatusOpen {
+		t.Errorf("accepted issue inbox = %v, status = %s", got.Inbox, got.Status)
+	}
+
+	if _, err := Apply(database, Request{Action: ActionReject, IDs: []string{rejected.ID}}, "ses_lead"); err == nil {
+		t.Error("reject without a reason succeeded")
+	}
+	if _, err := Apply(database, Request{Action: ActionReject, IDs: []string{rejected.ID}, Reason: "too vague"}, "ses_lead"); err != nil {
+		t.Fatalf("reject: %v", err)
+	}
+	if got, _ := database.GetIssue(rejected.ID); got.Inbox || got.Status != models.StatusClosed {
+		t.Errorf("rejected issue inbox = %v, status = %s", got.Inbox, got.Status)
+	}
+
+	if _, err := Apply(database, Request{Action: ActionDefer, IDs: []string{deferred.ID}, Until: "+1w"}, "ses_lead"); err != nil {
+		t.Fatalf("defer: %v", err)
+	}
+	if got, _ := database.GetIssue(deferred.ID); !got.Inbox || got.DeferUntil == nil {
+		t.Errorf("deferred issue inbox = %v, defer_until = %v; want still in the inbox, deferred", got.Inbox, got.DeferUntil)
+	}
+
+	res, err = Apply(database, Request{Action: ActionMerge, IDs: []string{dup.ID}, Into: accepted.ID}, "ses_lead")
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	if res.Merge == nil || res.Merge.Keep != accepted.ID || !slices.Equal(res.Done, []string{dup.ID}) {
+		t.Errorf("merge = %+v", res)
+	}
+	if got, _ := database.GetIssue(dup.ID); got.Inbox || got.Status != models.StatusClosed {
+		t.Errorf("merged issue inbox = %v, status = %s", got.Inbox, got.Status)
+	}
+
+	// Deferred issues wait out of sight
+	if inbox, _ := List(database); len(inbox) != 0 {
+		t.Errorf("List after triage = %d issues, want 0", len(inbox))
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		req  Request
+		ok   bool
+	}{
+		{"accept", Request{Action: ActionAccept, IDs: []string{"td-a"}}, true},
+		{"no ids", Request{Action: ActionAccept}, false},
+		{"unknown action", Request{Action: "close", IDs: []string{"td-a"}}, false},
+		{"merge without target", Request{Action: ActionMerge, IDs: []string{"td-a"}}, false},
+		{"defer bad date", Request{Action: ActionDefer, IDs: []string{"td-a"}, Until: "someday"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.Validate(); (err == nil) != tt.ok {
+				t.Errorf("Validate() = %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+	if err := ValidateSources([]string{SourceImport, "email"}); err == nil {
+		t.Error("unknown source: want error")
+	}
+}
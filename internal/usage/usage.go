@@ -0,0 +1,303 @@
+// Package usage reports how labels and sprints are used: how many issues
+// carry each label, which labels travel together, how often labelled work
+// gets done and how that changes week to week, so dead labels can be pruned
+// and bug-heavy areas spotted. Sprint stats line sprints up in order with
+// their delivery metrics to show the trend across sprints.
+package usage
+
+import (
+	"sort"
+	"time"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/retro"
+)
+
+// DefaultWeeks is how many weeks label trend lines cover
+const DefaultWeeks = 12
+
+// MaxWeeks bounds the trend window
+const MaxWeeks = 104
+
+// DefaultStaleDays is how long a label can go without an issue carrying it
+// being created or updated before it is reported stale
+const DefaultStaleDays = 90
+
+// MaxCoOccurring caps the co-occurring labels listed per label
+const MaxCoOccurring = 5
+
+const dateLayout = "2006-01-02"
+
+// LabelCount is a label and how many issues it shares with another
+type LabelCount struct {
+	Label string `json:"label"`
+	Count int    `json:"count"`
+}
+
+// Trend counts issues created and closed per week, oldest week first
+type Trend struct {
+	Created []int `json:"created"`
+	Closed  []int `json:"closed"`
+}
+
+// LabelStats is how one label is used
+type LabelStats struct {
+	Label      string       `json:"label"`
+	Issues     int          `json:"issues"`
+	Open       int          `json:"open"`
+	Closed     int          `json:"closed"`
+	Bugs       int          `json:"bugs"`
+	BugShare   float64      `json:"bug_share"`  // Bugs / Issues
+	Completion float64      `json:"completion"` // Closed / Issues
+	LastUsed   time.Time    `json:"last_used"`  // latest create or update of an issue with the label
+	Stale      bool         `json:"stale"`
+	CoOccurs   []LabelCount `json:"co_occurs"` // most shared first
+	Trend      Trend        `json:"trend"`
+}
+
+// LabelReport is label usage, most used label first
+type LabelReport struct {
+	Weeks      int          `json:"weeks"`
+	WeekStarts []string     `json:"week_starts"` // YYYY-MM-DD, one per trend point
+	StaleDays  int          `json:"stale_days"`
+	Issues     int          `json:"issues"`
+	Unlabeled  int          `json:"unlabeled"`
+	Stale      []string     `json:"stale"`
+	Labels     []LabelStats `json:"labels"`
+}
+
+// SprintStats is one sprint's delivery metrics
+type SprintStats struct {
+	Sprint     models.Sprint `json:"sprint"`
+	Configured bool          `json:"configured"` // false for sprint names only found on issues
+	retro.Metrics
+	Change float64      `json:"change"` // completion minus the previous sprint's
+	Labels []LabelCount `json:"labels"` // most used first
+}
+
+// SprintReport is sprint usage in sprint order, the trend across sprints
+type SprintReport struct {
+	Sprints       []SprintStats `json:"sprints"`
+	Unscheduled   int           `json:"unscheduled"` // issues without a sprint
+	AvgCompletion float64       `json:"avg_completion"`
+	AvgDonePoints float64       `json:"avg_done_points"`
+}
+
+// Labels computes label usage from issues. The trend covers the weeks
+// ending with the week of now.
+func Labels(issues []models.Issue, weeks, staleDays int, now time.Time) *LabelReport {
+	if weeks <= 0 {
+		weeks = DefaultWeeks
+	}
+	if staleDays <= 0 {
+		staleDays = DefaultStaleDays
+	}
+	r := &LabelReport{
+		Weeks:      weeks,
+		WeekStarts: make([]string, weeks),
+		StaleDays:  staleDays,
+		Issues:     len(issues),
+		Stale:      []string{},
+		Labels:     []LabelStats{},
+	}
+	first := weekStart(now).AddDate(0, 0, -7*(weeks-1))
+	for i := range r.WeekStarts {
+		r.WeekStarts[i] = first.AddDate(0, 0, 7*i).Format(dateLayout)
+	}
+	week := func(t time.Time) int {
+		if t.Before(first) {
+			return -1
+		}
+		i := int(weekStart(t).Sub(first).Hours()/(24*7) + 0.5)
+		if i >= weeks {
+			return -1
+		}
+		return i
+	}
+
+	byLabel := make(map[string]*LabelStats)
+	shared := make(map[string]map[string]int)
+	for _, issue := range issues {
+		labels := distinct(issue.Labels)
+		if len(labels) == 0 {
+			r.Unlabeled++
+			continue
+		}
+		for _, label := range labels {
+			s := byLabel[label]
+			if s == nil {
+				s = &LabelStats{Label: label, Trend: Trend{Created: make([]int, weeks), Closed: make([]int, weeks)}}
+				byLabel[label] = s
+				shared[label] = make(map[string]int)
+			}
+			s.Issues++
+			if issue.Status == models.StatusClosed {
+				s.Closed++
+			} else {
+				s.Open++
+			}
+			if issue.Type == models.TypeBug {
+				s.Bugs++
+			}
+			if issue.UpdatedAt.After(s.LastUsed) {
+				s.LastUsed = issue.UpdatedAt
+			}
+			if issue.CreatedAt.After(s.LastUsed) {
+				s.LastUsed = issue.CreatedAt
+			}
+			if i := week(issue.CreatedAt); i >= 0 {
+				s.Trend.Created[i]++
+			}
+			if issue.ClosedAt != nil {
+				if i := week(*issue.ClosedAt); i >= 0 {
+					s.Trend.Closed[i]++
+				}
+			}
+			for _, other := range labels {
+				if other != label {
+					shared[label][other]++
+				}
+			}
+		}
+	}
+
+	staleBefore := now.AddDate(0, 0, -staleDays)
+	for label, s := range byLabel {
+		s.BugShare = float64(s.Bugs) / float64(s.Issues)
+		s.Completion = float64(s.Closed) / float64(s.Issues)
+		s.Stale = s.LastUsed.Before(staleBefore)
+		s.CoOccurs = ranked(shared[label], MaxCoOccurring)
+		r.Labels = append(r.Labels, *s)
+	}
+	sort.Slice(r.Labels, func(i, j int) bool {
+		if r.Labels[i].Issues != r.Labels[j].Issues {
+			return r.Labels[i].Issues > r.Labels[j].Issues
+		}
+		return r.Labels[i].Label < r.Labels[j].Label
+	})
+	for _, s := range r.Labels {
+		if s.Stale {
+			r.Stale = append(r.Stale, s.Label)
+		}
+	}
+	return r
+}
+
+// Sprints computes per-sprint metrics. Configured sprints come first in
+// start order, then sprint names only found on issues, by name.
+func Sprints(sprints []models.Sprint, issues []models.Issue) *SprintReport {
+	bySprint := make(map[string][]models.Issue)
+	r := &SprintReport{Sprints: []SprintStats{}}
+	for _, issue := range issues {
+		if issue.Sprint == "" {
+			r.Unscheduled++
+			continue
+		}
+		bySprint[issue.Sprint] = append(bySprint[issue.Sprint], issue)
+	}
+
+	ordered := append([]models.Sprint(nil), sprints...)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Start < ordered[j].Start })
+	configured := make(map[string]bool, len(ordered))
+	for _, sp := range ordered {
+		configured[sp.Name] = true
+	}
+	var extra []string
+	for name := range bySprint {
+		if !configured[name] {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	for _, name := range extra {
+		ordered = append(ordered, models.Sprint{Name: name})
+	}
+
+	var totalCompletion float64
+	var totalDone int
+	for i, sp := range ordered {
+		sprintIssues := bySprint[sp.Name]
+		labels := make(map[string]int)
+		for _, issue := range sprintIssues {
+			for _, label := range distinct(issue.Labels) {
+				labels[label]++
+			}
+		}
+		s := SprintStats{
+			Sprint:     sp,
+			Configured: configured[sp.Name],
+			Metrics:    retro.Measure(sprintIssues),
+			Labels:     ranked(labels, 0),
+		}
+		if i > 0 {
+			s.Change = s.Completion - r.Sprints[i-1].Completion
+		}
+		totalCompletion += s.Completion
+		totalDone += s.DonePoints
+		r.Sprints = append(r.Sprints, s)
+	}
+	if n := len(r.Sprints); n > 0 {
+		r.AvgCompletion = totalCompletion / float64(n)
+		r.AvgDonePoints = float64(totalDone) / float64(n)
+	}
+	return r
+}
+
+// ComputeLabels loads all issues and computes label usage
+func ComputeLabels(database *db.DB, weeks, staleDays int, now time.Time) (*LabelReport, error) {
+	issues, err := database.ListIssues(db.ListIssuesOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return Labels(issues, weeks, staleDays, now), nil
+}
+
+// ComputeSprints loads all issues and computes sprint usage
+func ComputeSprints(database *db.DB, sprints []models.Sprint) (*SprintReport, error) {
+	issues, err := database.ListIssues(db.ListIssuesOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return Sprints(sprints, issues), nil
+}
+
+// ranked sorts counts most first, then by label, keeping at most limit
+// (all when limit is 0)
+func ranked(counts map[string]int, limit int) []LabelCount {
+	out := make([]LabelCount, 0, len(counts))
+	for label, n := range counts {
+		out = append(out, LabelCount{Label: label, Count: n})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Label < out[j].Label
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+// distinct drops empty and repeated labels
+func distinct(labels []string) []string {
+	var out []string
+	seen := make(map[string]bool, len(labels))
+	for _, l := range labels {
+		if l != "" && !seen[l] {
+			seen[l] = true
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+// weekStart is midnight on the Monday of t's week
+func weekStart(t time.Time) time.Time {
+	y, m, d := t.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
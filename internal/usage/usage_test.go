@@ -0,0 +1,83 @@
+package usage
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestLabels(t *testing.T) {
+	now := time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC) // a Wednesday
+	day := func(offset int) time.Time { return now.AddDate(0, 0, offset) }
+	closedAt := day(-1)
+
+	issues := []models.Issue{
+		{Labels: []string{"auth", "api"}, Type: models.TypeBug, CreatedAt: day(-2), UpdatedAt: day(-1), Status: models.StatusClosed, ClosedAt: &closedAt},
+		{Labels: []string{"auth"}, Type: models.TypeBug, CreatedAt: day(-9), UpdatedAt: day(-9)},
+		{Labels: []string{"auth", "ui"}, CreatedAt: day(-20), UpdatedAt: day(-20)},
+		{Labels: []string{"legacy", "legacy"}, CreatedAt: day(-400), UpdatedAt: day(-200)},
+		{CreatedAt: day(-3), UpdatedAt: day(-3)},
+	}
+
+	r := Labels(issues, 4, 90, now)
+	if r.Issues != 5 || r.Unlabeled != 1 || !reflect.DeepEqual(r.Stale, []string{"legacy"}) {
+		t.Fatalf("report = %+v", r)
+	}
+	if !reflect.DeepEqual(r.WeekStarts, []string{"2026-02-16", "2026-02-23", "2026-03-02", "2026-03-09"}) {
+		t.Errorf("week starts = %v", r.WeekStarts)
+	}
+
+	auth := r.Labels[0]
+	if auth.Label != "auth" || auth.Issues != 3 || auth.Closed != 1 || auth.Bugs != 2 {
+		t.Fatalf("auth = %+v", auth)
+	}
+	if want := []LabelCount{{"api", 1}, {"ui", 1}}; !reflect.DeepEqual(auth.CoOccurs, want) {
+		t.Errorf("auth co-occurs = %v, want %v", auth.CoOccurs, want)
+	}
+	if want := []int{1, 0, 1, 1}; !reflect.DeepEqual(auth.Trend.Created, want) {
+		t.Errorf("auth created trend = %v, want %v", auth.Trend.Created, want)
+	}
+	if want := []int{0, 0, 0, 1}; !reflect.DeepEqual(auth.Trend.Closed, want) {
+		t.Errorf("auth closed trend = %v, want %v", auth.Trend.Closed, want)
+	}
+	for _, s := range r.Labels {
+		if s.Label == "legacy" && s.Issues != 1 {
+			t.Errorf("repeated label counted %d times", s.Issues)
+		}
+	}
+}
+
+func TestSprints(t *testing.T) {
+	sprints := []models.Sprint{
+		{Name: "s2", Start: "2026-03-16", End: "2026-03-29"},
+		{Name: "s1", Start: "2026-03-02", End: "2026-03-15"},
+	}
+	issues := []models.Issue{
+		{Sprint: "s1", Status: models.StatusClosed, Labels: []string{"auth"}},
+		{Sprint: "s1", Status: models.StatusOpen},
+		{Sprint: "s2", Status: models.StatusClosed, Labels: []string{"auth"}},
+		{Sprint: "adhoc", Status: models.StatusOpen},
+		{Status: models.StatusOpen},
+	}
+
+	r := Sprints(sprints, issues)
+	var names []string
+	for _, s := range r.Sprints {
+		names = append(names, s.Sprint.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"s1", "s2", "adhoc"}) {
+		t.Fatalf("sprint order = %v", names)
+	}
+	s1, s2, adhoc := r.Sprints[0], r.Sprints[1], r.Sprints[2]
+	if s1.Completion != 0.5 || s2.Change != 0.5 || adhoc.Configured || !s1.Configured {
+		t.Errorf("sprints = %+v", r.Sprints)
+	}
+	if r.Unscheduled != 1 || r.AvgCompletion != 0.5 {
+		t.Errorf("report = %+v", r)
+	}
+	if want := []LabelCount{{"auth", 1}}; !reflect.DeepEqual(s1.Labels, want) {
+		t.Errorf("s1 labels = %v", s1.Labels)
+	}
+}
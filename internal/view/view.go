@@ -0,0 +1,339 @@
+// Package view validates and runs saved views: a TDQ query plus the sort,
+// columns, grouping and layout to present its issues with. The CLI, the
+// monitor and the HTTP API all run views through here, so a view shows
+// the same issues, in the same order and groups, wherever it is opened.
+package view
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/query"
+)
+
+// Layouts a view can ask its client to render with
+const (
+	LayoutList    = "list"
+	LayoutTable   = "table"
+	LayoutBoard   = "board"
+	LayoutBacklog = "backlog"
+)
+
+// Layouts lists the valid layouts, default first
+var Layouts = []string{LayoutList, LayoutTable, LayoutBoard, LayoutBacklog}
+
+// Groupings lists the fields issues can be grouped by
+var Groupings = []string{"status", "priority", "type", "sprint", "parent", "label", "implementer"}
+
+// DefaultFields are the columns shown when a view does not pick any
+var DefaultFields = []string{"id", "priority", "status", "title"}
+
+// fieldValues renders each selectable field as text. Names match the JSON
+// names of the API's issue objects, so a view's fields also work as the
+// API's ?fields= selection.
+var fieldValues = map[string]func(*models.Issue) string{
+	"id":                  func(i *models.Issue) string { return i.ID },
+	"title":               func(i *models.Issue) string { return i.Title },
+	"description":         func(i *models.Issue) string { return i.Description },
+	"status":              func(i *models.Issue) string { return string(i.Status) },
+	"type":                func(i *models.Issue) string { return string(i.Type) },
+	"priority":            func(i *models.Issue) string { return string(i.Priority) },
+	"points":              func(i *models.Issue) string { return pointsText(i.Points) },
+	"labels":              func(i *models.Issue) string { return strings.Join(i.Labels, ",") },
+	"parent_id":           func(i *models.Issue) string { return i.ParentID },
+	"acceptance":          func(i *models.Issue) string { return i.Acceptance },
+	"sprint":              func(i *models.Issue) string { return i.Sprint },
+	"implementer_session": func(i *models.Issue) string { return i.ImplementerSession },
+	"creator_session":     func(i *models.Issue) string { return i.CreatorSession },
+	"reviewer_session":    func(i *models.Issue) string { return i.ReviewerSession },
+	"created_at":          func(i *models.Issue) string { return dateText(&i.CreatedAt) },
+	"updated_at":          func(i *models.Issue) string { return dateText(&i.UpdatedAt) },
+	"closed_at":           func(i *models.Issue) string { return dateText(i.ClosedAt) },
+	"defer_until":         func(i *models.Issue) string { return stringText(i.DeferUntil) },
+	"due_date":            func(i *models.Issue) string { return stringText(i.DueDate) },
+	"defer_count":         func(i *models.Issue) string { return strconv.Itoa(i.DeferCount) },
+}
+
+// Fields lists the field names a view can select, sorted
+func Fields() []string {
+	names := make([]string, 0, len(fieldValues))
+	for name := range fieldValues {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Error reports an invalid view setting. Field is the setting's JSON name.
+type Error struct {
+	Field   string
+	Message string
+}
+
+func (e *Error) Error() string { return e.Message }
+
+func invalid(field, format string, args ...interface{}) error {
+	return &Error{Field: field, Message: fmt.Sprintf(format, args...)}
+}
+
+// namePattern keeps view names usable as CLI arguments and URL path segments
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9 _.-]{0,63}$`)
+
+// Normalize trims and lower-cases a view's settings, defaults its layout
+// to list, and reports the first setting that is not valid as an *Error.
+// The query's syntax is checked too, so a view that normalizes will run.
+func Normalize(v *models.View) error {
+	v.Name = strings.TrimSpace(v.Name)
+	if !namePattern.MatchString(v.Name) {
+		return invalid("name", "invalid view name %q: use letters, digits, spaces, '.', '_' or '-' (max 64)", v.Name)
+	}
+	if strings.HasPrefix(v.Name, "vw-") {
+		return invalid("name", "invalid view name %q: the vw- prefix is reserved for view IDs", v.Name)
+	}
+
+	v.Query = strings.TrimSpace(v.Query)
+	if _, err := parse(v.Query); err != nil {
+		return invalid("query", "%v", err)
+	}
+
+	v.Sort = strings.ToLower(strings.TrimSpace(v.Sort))
+	if _, _, err := sortColumn(v.Sort); err != nil {
+		return invalid("sort", "%v", err)
+	}
+
+	var fields []string
+	seen := make(map[string]bool)
+	for _, f := range v.Fields {
+		f = strings.ToLower(strings.TrimSpace(f))
+		if f == "" || seen[f] {
+			continue
+		}
+		if _, ok := fieldValues[f]; !ok {
+			return invalid("fields", "unknown field %q (valid: %s)", f, strings.Join(Fields(), ", "))
+		}
+		seen[f] = true
+		fields = append(fields, f)
+	}
+	v.Fields = fields
+
+	v.GroupBy = strings.ToLower(strings.TrimSpace(v.GroupBy))
+	if v.GroupBy != "" && !contains(Groupings, v.GroupBy) {
+		return invalid("group_by", "invalid group %q (valid: %s)", v.GroupBy, strings.Join(Groupings, ", "))
+	}
+
+	v.Layout = strings.ToLower(strings.TrimSpace(v.Layout))
+	if v.Layout == "" {
+		v.Layout = LayoutList
+	}
+	if !contains(Layouts, v.Layout) {
+		return invalid("layout", "invalid layout %q (valid: %s)", v.Layout, strings.Join(Layouts, ", "))
+	}
+	return nil
+}
+
+// Columns returns the fields v shows: its own selection or DefaultFields
+func Columns(v *models.View) []string {
+	if len(v.Fields) > 0 {
+		return v.Fields
+	}
+	return DefaultFields
+}
+
+// Value renders one field of an issue as text; unknown fields are empty
+func Value(issue *models.Issue, field string) string {
+	if f, ok := fieldValues[field]; ok {
+		return f(issue)
+	}
+	return ""
+}
+
+// Group is one bucket of a view's results. Key is the grouped field's
+// value, empty for issues without one (and for views that do not group).
+type Group struct {
+	Key    string         `json:"key"`
+	Issues []models.Issue `json:"issues"`
+}
+
+// GroupField returns the field v groups by. Board layouts without a
+// grouping fall back to status, so their columns are the workflow.
+func GroupField(v *models.View) string {
+	if v.GroupBy == "" && v.Layout == LayoutBoard {
+		return "status"
+	}
+	return v.GroupBy
+}
+
+// Run executes v's query with its sort and splits the results into groups.
+// The view's sort replaces any sort: clause in its query.
+func Run(src query.QuerySource, v *models.View, sessionID string, opts query.ExecuteOptions) ([]Group, error) {
+	q, err := parse(v.Query)
+	if err != nil {
+		return nil, err
+	}
+	if v.Sort != "" {
+		col, desc, err := sortColumn(v.Sort)
+		if err != nil {
+			return nil, err
+		}
+		q.Sort = &query.SortClause{Field: col, Descending: desc}
+	}
+	issues, err := query.ExecuteQuery(src, q, sessionID, opts)
+	if err != nil {
+		return nil, err
+	}
+	return GroupIssues(issues, GroupField(v)), nil
+}
+
+// GroupIssues splits issues by field, keeping their order within each
+// group. Status and priority groups follow the workflow and priority
+// order; the rest are alphabetical, with the empty group last. An issue
+// with several labels appears under each of them.
+func GroupIssues(issues []models.Issue, field string) []Group {
+	if field == "" {
+		return []Group{{Key: "", Issues: nonNil(issues)}}
+	}
+
+	byKey := make(map[string][]models.Issue)
+	var keys []string
+	add := func(key string, issue models.Issue) {
+		if _, ok := byKey[key]; !ok {
+			keys = append(keys, key)
+		}
+		byKey[key] = append(byKey[key], issue)
+	}
+	for _, issue := range issues {
+		if field == "label" {
+			if len(issue.Labels) == 0 {
+				add("", issue)
+			}
+			for _, l := range issue.Labels {
+				add(l, issue)
+			}
+			continue
+		}
+		add(groupKey(&issue, field), issue)
+	}
+
+	sort.SliceStable(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if (a == "") != (b == "") {
+			return b == ""
+		}
+		if field == "status" {
+			return statusRank(a) < statusRank(b)
+		}
+		return a < b
+	})
+
+	groups := make([]Group, len(keys))
+	for i, k := range keys {
+		groups[i] = Group{Key: k, Issues: byKey[k]}
+	}
+	return groups
+}
+
+// groupKey returns the value issues are grouped on for a single-valued field
+func groupKey(issue *models.Issue, field string) string {
+	switch field {
+	case "status":
+		return string(issue.Status)
+	case "priority":
+		return string(issue.Priority)
+	case "type":
+		return string(issue.Type)
+	case "sprint":
+		return issue.Sprint
+	case "parent":
+		return issue.ParentID
+	case "implementer":
+		return issue.ImplementerSession
+	}
+	return ""
+}
+
+// statusOrder is the order status groups appear in
+var statusOrder = []models.Status{
+	models.StatusOpen, models.StatusInProgress, models.StatusBlocked,
+	models.StatusInReview, models.StatusClosed,
+}
+
+func statusRank(s string) int {
+	for i, st := range statusOrder {
+		if string(st) == s {
+			return i
+		}
+	}
+	return len(statusOrder)
+}
+
+// parse parses and validates a TDQ query
+func parse(queryStr string) (*query.Query, error) {
+	q, err := query.Parse(queryStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query: %w", err)
+	}
+	if errs := q.Validate(); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid query: %v", errs[0])
+	}
+	return q, nil
+}
+
+// sortColumn maps a view sort ("created", "-priority") to a column
+func sortColumn(s string) (col string, desc bool, err error) {
+	if s == "" {
+		return "", false, nil
+	}
+	field, desc := strings.CutPrefix(s, "-")
+	col, ok := query.SortFieldToColumn[field]
+	if !ok {
+		valid := make([]string, 0, len(query.SortFieldToColumn))
+		for k := range query.SortFieldToColumn {
+			valid = append(valid, k)
+		}
+		sort.Strings(valid)
+		return "", false, fmt.Errorf("invalid sort %q (valid: %s; prefix - for descending)", s, strings.Join(valid, ", "))
+	}
+	return col, desc, nil
+}
+
+func pointsText(p int) string {
+	if p == 0 {
+		return ""
+	}
+	return strconv.Itoa(p)
+}
+
+func dateText(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.Local().Format("2006-01-02")
+}
+
+func stringText(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func nonNil(issues []models.Issue) []models.Issue {
+	if issues == nil {
+		return []models.Issue{}
+	}
+	return issues
+}
@@ -0,0 +1,97 @@
+package view
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/query"
+)
+
+func TestNormalize(t *testing.T) {
+	v := &models.View{Name: " triage ", Sort: "-Created", Fields: []string{"ID", "title", "id"}, GroupBy: "Priority"}
+	if err := Normalize(v); err != nil {
+		t.Fatalf("Normalize: %v", err)
+	}
+	want := &models.View{Name: "triage", Sort: "-created", Fields: []string{"id", "title"}, GroupBy: "priority", Layout: LayoutList}
+	if !reflect.DeepEqual(v, want) {
+		t.Errorf("Normalize = %+v, want %+v", v, want)
+	}
+
+	bad := []models.View{
+		{Name: ""},
+		{Name: "vw-1234"},
+		{Name: "a/b"},
+		{Name: "x", Query: "status ="},
+		{Name: "x", Sort: "owner"},
+		{Name: "x", Fields: []string{"owner"}},
+		{Name: "x", GroupBy: "owner"},
+		{Name: "x", Layout: "grid"},
+	}
+	for _, v := range bad {
+		if err := Normalize(&v); err == nil {
+			t.Errorf("Normalize(%+v) succeeded, want error", v)
+		}
+	}
+}
+
+func TestGroupIssues(t *testing.T) {
+	issues := []models.Issue{
+		{ID: "td-1", Status: models.StatusClosed, Labels: []string{"ui"}},
+		{ID: "td-2", Status: models.StatusOpen},
+		{ID: "td-3", Status: models.StatusInProgress, Labels: []string{"api", "ui"}},
+	}
+
+	keys := func(groups []Group) []string {
+		var out []string
+		for _, g := range groups {
+			out = append(out, g.Key)
+		}
+		return out
+	}
+	if got := keys(GroupIssues(issues, "status")); !reflect.DeepEqual(got, []string{"open", "in_progress", "closed"}) {
+		t.Errorf("status groups = %v", got)
+	}
+	labels := GroupIssues(issues, "label")
+	if got := keys(labels); !reflect.DeepEqual(got, []string{"api", "ui", ""}) {
+		t.Errorf("label groups = %v", got)
+	}
+	if len(labels[1].Issues) != 2 {
+		t.Errorf("ui group = %+v, want td-1 and td-3", labels[1].Issues)
+	}
+	if got := GroupIssues(nil, ""); len(got) != 1 || got[0].Issues == nil {
+		t.Errorf("ungrouped = %+v, want one empty group", got)
+	}
+}
+
+func TestRun(t *testing.T) {
+	database, err := db.Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+
+	for _, issue := range []*models.Issue{
+		{Title: "Login page ignores SSO redirect", Type: models.TypeBug, Priority: models.PriorityP1},
+		{Title: "Export issues as CSV from the list", Type: models.TypeFeature, Priority: models.PriorityP2},
+		{Title: "Crash when the config file is empty", Type: models.TypeBug, Priority: models.PriorityP0},
+	} {
+		if err := database.CreateIssue(issue); err != nil {
+			t.Fatalf("CreateIssue: %v", err)
+		}
+	}
+
+	v := &models.View{Name: "bugs", Query: "type = bug sort:title", Sort: "priority", Layout: LayoutBoard}
+	groups, err := Run(database, v, "ses_test", query.ExecuteOptions{})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(groups) != 1 || groups[0].Key != "open" || len(groups[0].Issues) != 2 {
+		t.Fatalf("Run = %+v, want one open group with 2 bugs", groups)
+	}
+	// The view's sort wins over the query's sort: clause
+	if groups[0].Issues[0].Priority != models.PriorityP0 {
+		t.Errorf("first issue = %s, want the P0", groups[0].Issues[0].Priority)
+	}
+}
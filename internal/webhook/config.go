@@ -18,7 +18,7 @@ func GetURL(baseDir string) string {
 	if err == nil && cfg.Webhook != nil && cfg.Webhook.URL != "" {
 		return cfg.Webhook.URL
 	}
-	gcfg, err := syncconfig.LoadConfig()
+	gcfg, err := syncconfig.LoadActiveConfig()
 	if err == nil && gcfg.Webhook != nil {
 		return gcfg.Webhook.URL
 	}
@@ -35,7 +35,7 @@ func GetSecret(baseDir string) string {
 	if err == nil && cfg.Webhook != nil && cfg.Webhook.Secret != "" {
 		return cfg.Webhook.Secret
 	}
-	gcfg, err := syncconfig.LoadConfig()
+	gcfg, err := syncconfig.LoadActiveConfig()
 	if err == nil && gcfg.Webhook != nil {
 		return gcfg.Webhook.Secret
 	}
@@ -101,6 +101,13 @@ func ReadTempFile(path string) (*TempFile, error) {
 // Dispatch performs a synchronous HTTP POST to the webhook URL.
 // Returns nil on success (2xx status).
 func Dispatch(url, secret string, payload Payload) error {
+	return DispatchDelivery(url, secret, "", payload)
+}
+
+// DispatchDelivery is Dispatch for a delivery from the outbox. The ID is
+// sent as X-TD-Delivery and stays the same across retries, so a receiver
+// can drop a payload it already handled.
+func DispatchDelivery(url, secret, deliveryID string, payload Payload) error {
 	body, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("marshal payload: %w", err)
@@ -112,6 +119,9 @@ func Dispatch(url, secret string, payload Payload) error {
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", "td-webhook/1")
+	if deliveryID != "" {
+		req.Header.Set("X-TD-Delivery", deliveryID)
+	}
 
 	unixTS := fmt.Sprintf("%d", time.Now().Unix())
 	req.Header.Set("X-TD-Timestamp", unixTS)
@@ -0,0 +1,123 @@
+package workflow
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/marcus/td/internal/models"
+)
+
+// AckAll acknowledges every item of a review checklist at once
+const AckAll = "all"
+
+// ValidateReviewChecklist checks a review checklist
+func ValidateReviewChecklist(c models.ReviewChecklist) error {
+	if c.Type != "" && !models.IsValidType(models.Type(c.Type)) {
+		return fmt.Errorf("invalid type %q", c.Type)
+	}
+	for i, item := range c.Items {
+		if strings.TrimSpace(item) == "" {
+			return fmt.Errorf("item %d is empty", i+1)
+		}
+		if _, err := strconv.Atoi(item); err == nil || strings.EqualFold(item, AckAll) {
+			return fmt.Errorf("item %q would be ambiguous with an acknowledgment by number or %q", item, AckAll)
+		}
+	}
+	return nil
+}
+
+// ChecklistFor returns the checklist items a reviewer must acknowledge to
+// approve an issue of a type: the items for every type, then the type's
+// own, without duplicates
+func ChecklistFor(t models.Type, lists []models.ReviewChecklist) []string {
+	var items []string
+	add := func(c models.ReviewChecklist) {
+		for _, item := range c.Items {
+			if !slices.ContainsFunc(items, func(s string) bool { return strings.EqualFold(s, item) }) {
+				items = append(items, item)
+			}
+		}
+	}
+	for _, c := range lists {
+		if c.Type == "" {
+			add(c)
+		}
+	}
+	for _, c := range lists {
+		if c.Type == string(t) {
+			add(c)
+		}
+	}
+	return items
+}
+
+// ResolveAcks maps acknowledgments to checklist items. Each ack is an
+// item's text (case-insensitive), its 1-based number, or "all".
+func ResolveAcks(items, acks []string) ([]string, error) {
+	var acked []string
+	add := func(item string) {
+		if !slices.Contains(acked, item) {
+			acked = append(acked, item)
+		}
+	}
+	for _, ack := range acks {
+		ack = strings.TrimSpace(ack)
+		if strings.EqualFold(ack, AckAll) {
+			for _, item := range items {
+				add(item)
+			}
+			continue
+		}
+		if n, err := strconv.Atoi(ack); err == nil {
+			if n < 1 || n > len(items) {
+				return nil, fmt.Errorf("no checklist item %d: the checklist has %d", n, len(items))
+			}
+			add(items[n-1])
+			continue
+		}
+		i := slices.IndexFunc(items, func(s string) bool { return strings.EqualFold(s, ack) })
+		if i < 0 {
+			return nil, fmt.Errorf("%q is not on the review checklist", ack)
+		}
+		add(items[i])
+	}
+	return acked, nil
+}
+
+// UnacknowledgedItems returns the checklist items not among acked, in
+// checklist order
+func UnacknowledgedItems(items, acked []string) []string {
+	var missing []string
+	for _, item := range items {
+		if !slices.Contains(acked, item) {
+			missing = append(missing, item)
+		}
+	}
+	return missing
+}
+
+// ReviewChecklistGuard requires the reviewer to acknowledge every item of
+// the issue's review checklist before approving it. It runs on approvals
+// in all modes, like policy hooks.
+type ReviewChecklistGuard struct {
+	Items []string
+}
+
+func (g *ReviewChecklistGuard) Name() string {
+	return "ReviewChecklistGuard"
+}
+
+func (g *ReviewChecklistGuard) Check(ctx *TransitionContext) GuardResult {
+	if ctx.ToStatus != models.StatusClosed {
+		return GuardResult{Passed: true}
+	}
+	if missing := UnacknowledgedItems(g.Items, ctx.Acknowledged); len(missing) > 0 {
+		return GuardResult{
+			Passed:  false,
+			Message: "review checklist not acknowledged: " + strings.Join(missing, ", "),
+		}
+	}
+	return GuardResult{Passed: true}
+}
@@ -0,0 +1,76 @@
+package workflow
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestChecklistFor(t *testing.T) {
+	lists := []models.ReviewChecklist{
+		{Type: "bug", Items: []string{"regression test added", "Docs updated"}},
+		{Items: []string{"docs updated", "security considered"}},
+	}
+	if got, want := ChecklistFor(models.TypeBug, lists), []string{"docs updated", "security considered", "regression test added"}; !slices.Equal(got, want) {
+		t.Errorf("ChecklistFor(bug) = %v, want %v", got, want)
+	}
+	if got := ChecklistFor(models.TypeTask, lists[:1]); got != nil {
+		t.Errorf("ChecklistFor(task) = %v, want none", got)
+	}
+}
+
+func TestResolveAcks(t *testing.T) {
+	items := []string{"tests added", "docs updated", "security considered"}
+
+	tests := []struct {
+		name    string
+		acks    []string
+		want    []string
+		wantErr bool
+	}{
+		{"by text", []string{"Docs Updated"}, []string{"docs updated"}, false},
+		{"by number", []string{"3", "1"}, []string{"security considered", "tests added"}, false},
+		{"all", []string{"all", "2"}, items, false},
+		{"out of range", []string{"4"}, nil, true},
+		{"unknown", []string{"changelog"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveAcks(items, tt.acks)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ResolveAcks = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	guard := &ReviewChecklistGuard{Items: items}
+	ctx := &TransitionContext{Issue: &models.Issue{}, ToStatus: models.StatusClosed, Acknowledged: items[:2]}
+	if res := guard.Check(ctx); res.Passed {
+		t.Error("guard passed with an item unacknowledged")
+	}
+	ctx.Acknowledged = items
+	if res := guard.Check(ctx); !res.Passed {
+		t.Errorf("guard failed with every item acknowledged: %s", res.Message)
+	}
+}
+
+func TestValidateReviewChecklist(t *testing.T) {
+	bad := []models.ReviewChecklist{
+		{Type: "story", Items: []string{"tests added"}},
+		{Items: []string{"  "}},
+		{Items: []string{"2"}},
+		{Items: []string{"All"}},
+	}
+	for _, c := range bad {
+		if ValidateReviewChecklist(c) == nil {
+			t.Errorf("ValidateReviewChecklist(%+v) = nil, want error", c)
+		}
+	}
+	if err := ValidateReviewChecklist(models.ReviewChecklist{Type: "bug", Items: []string{"tests added"}}); err != nil {
+		t.Errorf("valid checklist: %v", err)
+	}
+}
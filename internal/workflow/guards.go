@@ -15,6 +15,10 @@
 // These future guards require caller modifications to pass necessary context
 // (e.g., open child count, self-close exception reason) and will be wired
 // up when Advisory/Strict modes are enabled by default.
+//
+// Policy hooks (see hooks.go) are guards that run on every status change
+// regardless of mode, and ReviewChecklistGuard (see checklist.go) runs on
+// every approval when a review checklist is configured.
 package workflow
 
 import (
@@ -0,0 +1,232 @@
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/marcus/td/internal/models"
+)
+
+// Policy hooks are checks that run before every status transition, in all
+// modes, and veto it with a message when they fail. Any Guard can be a
+// hook: Go code embedding td registers its own with RegisterHook, and
+// projects configure built-in and command hooks in .todos/config.json.
+
+// Built-in hook kinds
+const (
+	BuiltinRequireHandoff    = "require_handoff"
+	BuiltinRequireAcceptance = "require_acceptance"
+)
+
+// DefaultHookTimeout bounds a command hook with no timeout configured
+const DefaultHookTimeout = 10 * time.Second
+
+var (
+	hooksMu         sync.RWMutex
+	registeredHooks []Guard
+)
+
+// RegisterHook adds a policy hook that runs for every project
+func RegisterHook(h Guard) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	registeredHooks = append(registeredHooks, h)
+}
+
+// RegisteredHooks returns the hooks added with RegisterHook
+func RegisteredHooks() []Guard {
+	hooksMu.RLock()
+	defer hooksMu.RUnlock()
+	return slices.Clone(registeredHooks)
+}
+
+// RunHooks runs hooks against a transition. It returns a *ValidationError
+// holding a *GuardError for each veto, or nil when all pass.
+func RunHooks(ctx *TransitionContext, hooks []Guard) error {
+	verr := &ValidationError{}
+	for _, h := range hooks {
+		result := h.Check(ctx)
+		if result.Passed {
+			continue
+		}
+		verr.Add(&GuardError{GuardName: h.Name(), Reason: result.Message, IssueID: ctx.Issue.ID})
+	}
+	if verr.HasErrors() {
+		return verr
+	}
+	return nil
+}
+
+// RequireHandoffHook vetoes transitions of issues that have no handoff
+type RequireHandoffHook struct{}
+
+func (h *RequireHandoffHook) Name() string {
+	return BuiltinRequireHandoff
+}
+
+func (h *RequireHandoffHook) Check(ctx *TransitionContext) GuardResult {
+	if ctx.HasHandoff {
+		return GuardResult{Passed: true}
+	}
+	return GuardResult{
+		Passed:  false,
+		Message: fmt.Sprintf("cannot move to %s unless a handoff exists", ctx.ToStatus),
+	}
+}
+
+// RequireAcceptanceHook vetoes transitions of issues without acceptance
+// criteria
+type RequireAcceptanceHook struct{}
+
+func (h *RequireAcceptanceHook) Name() string {
+	return BuiltinRequireAcceptance
+}
+
+func (h *RequireAcceptanceHook) Check(ctx *TransitionContext) GuardResult {
+	if strings.TrimSpace(ctx.Issue.Acceptance) != "" {
+		return GuardResult{Passed: true}
+	}
+	return GuardResult{
+		Passed:  false,
+		Message: fmt.Sprintf("%s requires acceptance criteria", ctx.Issue.Priority),
+	}
+}
+
+// CommandHook runs an external command with sh -c before a transition. The
+// issue is passed as JSON on stdin and the transition in TD_* environment
+// variables. A non-zero exit vetoes the transition, with the first line of
+// the command's output as the message; so does running past the timeout.
+type CommandHook struct {
+	HookName string
+	Command  string
+	Dir      string // working directory, normally the project root
+	Timeout  time.Duration
+}
+
+func (h *CommandHook) Name() string {
+	return h.HookName
+}
+
+func (h *CommandHook) Check(ctx *TransitionContext) GuardResult {
+	timeout := h.Timeout
+	if timeout <= 0 {
+		timeout = DefaultHookTimeout
+	}
+	c, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	issueJSON, _ := json.Marshal(ctx.Issue)
+	cmd := exec.CommandContext(c, "sh", "-c", h.Command)
+	cmd.Dir = h.Dir
+	cmd.Stdin = bytes.NewReader(issueJSON)
+	cmd.Env = append(os.Environ(),
+		"TD_HOOK="+h.HookName,
+		"TD_ISSUE_ID="+ctx.Issue.ID,
+		"TD_FROM_STATUS="+string(ctx.FromStatus),
+		"TD_TO_STATUS="+string(ctx.ToStatus),
+		"TD_SESSION_ID="+ctx.SessionID,
+		"TD_PRIORITY="+string(ctx.Issue.Priority),
+		"TD_TYPE="+string(ctx.Issue.Type),
+	)
+	// Don't let a background child holding the output pipe outlive the timeout
+	cmd.WaitDelay = time.Second
+
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		return GuardResult{Passed: true}
+	}
+	if c.Err() == context.DeadlineExceeded {
+		return GuardResult{Passed: false, Message: fmt.Sprintf("timed out after %s", timeout)}
+	}
+	msg, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	if msg == "" {
+		msg = err.Error()
+	}
+	return GuardResult{Passed: false, Message: msg}
+}
+
+// scopedHook runs a configured hook only on the transitions it applies to,
+// under the name it was configured with
+type scopedHook struct {
+	name       string
+	hook       Guard
+	to         []string
+	priorities []string
+}
+
+func (h *scopedHook) Name() string {
+	return h.name
+}
+
+func (h *scopedHook) Check(ctx *TransitionContext) GuardResult {
+	if len(h.to) > 0 && !slices.Contains(h.to, string(ctx.ToStatus)) {
+		return GuardResult{Passed: true}
+	}
+	if len(h.priorities) > 0 && !slices.Contains(h.priorities, string(ctx.Issue.Priority)) {
+		return GuardResult{Passed: true}
+	}
+	return h.hook.Check(ctx)
+}
+
+// ValidateHookConfig checks a policy hook configuration
+func ValidateHookConfig(c models.PolicyHookConfig) error {
+	if c.Name == "" {
+		return fmt.Errorf("hook name is required")
+	}
+	switch {
+	case c.Builtin != "" && c.Command != "":
+		return fmt.Errorf("hook %s: set either a builtin or a command, not both", c.Name)
+	case c.Command != "":
+	case c.Builtin == BuiltinRequireHandoff, c.Builtin == BuiltinRequireAcceptance:
+	case c.Builtin != "":
+		return fmt.Errorf("hook %s: unknown builtin %q: use %s or %s", c.Name, c.Builtin, BuiltinRequireHandoff, BuiltinRequireAcceptance)
+	default:
+		return fmt.Errorf("hook %s: a builtin or a command is required", c.Name)
+	}
+	for _, s := range c.To {
+		if !models.IsValidStatus(models.Status(s)) {
+			return fmt.Errorf("hook %s: invalid status %q", c.Name, s)
+		}
+	}
+	for _, p := range c.Priorities {
+		if !models.IsValidPriority(models.Priority(p)) {
+			return fmt.Errorf("hook %s: invalid priority %q", c.Name, p)
+		}
+	}
+	return nil
+}
+
+// HooksFromConfig builds a project's configured hooks. Command hooks run
+// in dir.
+func HooksFromConfig(cfgs []models.PolicyHookConfig, dir string) ([]Guard, error) {
+	hooks := make([]Guard, 0, len(cfgs))
+	for _, c := range cfgs {
+		if err := ValidateHookConfig(c); err != nil {
+			return nil, err
+		}
+		var hook Guard
+		switch c.Builtin {
+		case BuiltinRequireHandoff:
+			hook = &RequireHandoffHook{}
+		case BuiltinRequireAcceptance:
+			hook = &RequireAcceptanceHook{}
+		default:
+			hook = &CommandHook{
+				HookName: c.Name,
+				Command:  c.Command,
+				Dir:      dir,
+				Timeout:  time.Duration(c.TimeoutSeconds) * time.Second,
+			}
+		}
+		hooks = append(hooks, &scopedHook{name: c.Name, hook: hook, to: c.To, priorities: c.Priorities})
+	}
+	return hooks, nil
+}
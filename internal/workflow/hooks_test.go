@@ -0,0 +1,100 @@
+package workflow
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestHooksFromConfig(t *testing.T) {
+	hooks, err := HooksFromConfig([]models.PolicyHookConfig{
+		{Name: "handoff-before-review", Builtin: BuiltinRequireHandoff, To: []string{"in_review"}},
+		{Name: "p0-acceptance", Builtin: BuiltinRequireAcceptance, Priorities: []string{"P0"}},
+	}, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		issue    models.Issue
+		to       models.Status
+		handoff  bool
+		wantVeto []string
+	}{
+		{"start is out of scope", models.Issue{Priority: models.PriorityP2}, models.StatusInProgress, false, nil},
+		{"review without handoff", models.Issue{Priority: models.PriorityP2}, models.StatusInReview, false, []string{"handoff-before-review"}},
+		{"review with handoff", models.Issue{Priority: models.PriorityP2}, models.StatusInReview, true, nil},
+		{"P0 without acceptance", models.Issue{Priority: models.PriorityP0}, models.StatusInProgress, false, []string{"p0-acceptance"}},
+		{"P0 with acceptance", models.Issue{Priority: models.PriorityP0, Acceptance: "works"}, models.StatusInProgress, false, nil},
+		{"both", models.Issue{Priority: models.PriorityP0}, models.StatusInReview, false, []string{"handoff-before-review", "p0-acceptance"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.issue.ID = "td-1"
+			err := RunHooks(&TransitionContext{
+				Issue:      &tt.issue,
+				FromStatus: models.StatusOpen,
+				ToStatus:   tt.to,
+				HasHandoff: tt.handoff,
+			}, hooks)
+			var got []string
+			var verr *ValidationError
+			if errors.As(err, &verr) {
+				for _, e := range verr.Errors {
+					got = append(got, e.(*GuardError).GuardName)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.wantVeto, ",") {
+				t.Errorf("vetoes = %v, want %v", got, tt.wantVeto)
+			}
+		})
+	}
+}
+
+func TestCommandHook(t *testing.T) {
+	ctx := &TransitionContext{
+		Issue:      &models.Issue{ID: "td-1", Priority: models.PriorityP1},
+		FromStatus: models.StatusInProgress,
+		ToStatus:   models.StatusInReview,
+	}
+
+	pass := &CommandHook{HookName: "ok", Command: `test "$TD_TO_STATUS" = in_review && grep -q '"id":"td-1"'`}
+	if r := pass.Check(ctx); !r.Passed {
+		t.Errorf("passing hook vetoed: %s", r.Message)
+	}
+
+	veto := &CommandHook{HookName: "no", Command: `echo "tests are failing"; echo detail; exit 1`}
+	if r := veto.Check(ctx); r.Passed || r.Message != "tests are failing" {
+		t.Errorf("veto = %+v, want message from first output line", r)
+	}
+
+	slow := &CommandHook{HookName: "slow", Command: "sleep 5", Timeout: 100 * time.Millisecond}
+	if r := slow.Check(ctx); r.Passed || !strings.Contains(r.Message, "timed out") {
+		t.Errorf("slow hook = %+v, want timeout veto", r)
+	}
+}
+
+func TestValidateHookConfig(t *testing.T) {
+	bad := []models.PolicyHookConfig{
+		{Builtin: BuiltinRequireHandoff},
+		{Name: "x"},
+		{Name: "x", Builtin: "nope"},
+		{Name: "x", Builtin: BuiltinRequireHandoff, Command: "true"},
+		{Name: "x", Command: "true", To: []string{"done"}},
+		{Name: "x", Command: "true", Priorities: []string{"P9"}},
+	}
+	for _, c := range bad {
+		if ValidateHookConfig(c) == nil {
+			t.Errorf("ValidateHookConfig(%+v) = nil, want error", c)
+		}
+	}
+	if err := ValidateHookConfig(models.PolicyHookConfig{Name: "x", Command: "true", To: []string{"closed"}}); err != nil {
+		t.Errorf("valid config: %v", err)
+	}
+}
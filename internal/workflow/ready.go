@@ -0,0 +1,47 @@
+package workflow
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/marcus/td/internal/models"
+)
+
+// ReadyResolvedParent is the ready-gate criterion that an issue's parent,
+// if it has one, resolves to an issue that still exists
+const ReadyResolvedParent = "resolved_parent"
+
+// TriageGap is the readiness gap of an issue still in the triage inbox. It
+// applies with or without a ready gate.
+const TriageGap = "triage"
+
+// ReadyCriteria lists what a definition of ready can require: any
+// requirable field, plus a resolved parent
+var ReadyCriteria = append(slices.Clone(RequirableFields), ReadyResolvedParent)
+
+// ValidateReadyGate checks the criteria of a definition of ready
+func ValidateReadyGate(criteria []string) error {
+	for _, c := range criteria {
+		if !slices.Contains(ReadyCriteria, c) {
+			return fmt.Errorf("unknown criterion %q: use %s", c, strings.Join(ReadyCriteria, ", "))
+		}
+	}
+	return nil
+}
+
+// ReadyGaps returns the field criteria an issue fails, in gate order,
+// after TriageGap for an issue in the inbox. ReadyResolvedParent needs a
+// lookup and is left to the caller.
+func ReadyGaps(issue *models.Issue, criteria []string) []string {
+	var gaps []string
+	if issue.Inbox {
+		gaps = append(gaps, TriageGap)
+	}
+	for _, c := range criteria {
+		if c != ReadyResolvedParent && !hasField(issue, c) && !slices.Contains(gaps, c) {
+			gaps = append(gaps, c)
+		}
+	}
+	return gaps
+}
@@ -0,0 +1,30 @@
+package workflow
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestValidateReadyGate(t *testing.T) {
+	if err := ValidateReadyGate([]string{FieldPoints, FieldAcceptance, ReadyResolvedParent}); err != nil {
+		t.Errorf("valid gate: %v", err)
+	}
+	if err := ValidateReadyGate([]string{"estimate"}); err == nil {
+		t.Error("unknown criterion: want error")
+	}
+}
+
+func TestReadyGaps(t *testing.T) {
+	criteria := []string{FieldPoints, FieldAcceptance, ReadyResolvedParent}
+
+	bare := &models.Issue{ParentID: "td-gone"}
+	if got, want := ReadyGaps(bare, criteria), []string{FieldPoints, FieldAcceptance}; !slices.Equal(got, want) {
+		t.Errorf("ReadyGaps(bare) = %v, want %v", got, want)
+	}
+	specified := &models.Issue{Points: 3, Acceptance: "It works"}
+	if got := ReadyGaps(specified, criteria); got != nil {
+		t.Errorf("ReadyGaps(specified) = %v, want none", got)
+	}
+}
@@ -0,0 +1,80 @@
+package workflow
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/marcus/td/internal/models"
+)
+
+// Fields a required-fields rule can name
+const (
+	FieldDescription = "description"
+	FieldAcceptance  = "acceptance"
+	FieldLabels      = "labels"
+	FieldPoints      = "points"
+	FieldParent      = "parent"
+	FieldSprint      = "sprint"
+	FieldDue         = "due"
+)
+
+// RequirableFields lists the fields a rule can require
+var RequirableFields = []string{
+	FieldDescription, FieldAcceptance, FieldLabels, FieldPoints,
+	FieldParent, FieldSprint, FieldDue,
+}
+
+// ValidateRequiredFieldsRule checks a required-fields rule
+func ValidateRequiredFieldsRule(r models.RequiredFieldsRule) error {
+	if r.Type != "" && !models.IsValidType(models.Type(r.Type)) {
+		return fmt.Errorf("invalid type %q", r.Type)
+	}
+	if !models.IsValidStatus(models.Status(r.To)) {
+		return fmt.Errorf("invalid status %q", r.To)
+	}
+	for _, f := range r.Fields {
+		if !slices.Contains(RequirableFields, f) {
+			return fmt.Errorf("unknown field %q: use %s", f, strings.Join(RequirableFields, ", "))
+		}
+	}
+	return nil
+}
+
+// MissingFields returns the fields the rules require for moving issue to
+// a status that the issue has not filled in, in rule order
+func MissingFields(issue *models.Issue, to models.Status, rules []models.RequiredFieldsRule) []string {
+	var missing []string
+	for _, r := range rules {
+		if r.To != string(to) || (r.Type != "" && r.Type != string(issue.Type)) {
+			continue
+		}
+		for _, f := range r.Fields {
+			if !hasField(issue, f) && !slices.Contains(missing, f) {
+				missing = append(missing, f)
+			}
+		}
+	}
+	return missing
+}
+
+// hasField reports whether an issue has a value for a requirable field
+func hasField(issue *models.Issue, field string) bool {
+	switch field {
+	case FieldDescription:
+		return strings.TrimSpace(issue.Description) != ""
+	case FieldAcceptance:
+		return strings.TrimSpace(issue.Acceptance) != ""
+	case FieldLabels:
+		return len(issue.Labels) > 0
+	case FieldPoints:
+		return issue.Points > 0
+	case FieldParent:
+		return issue.ParentID != ""
+	case FieldSprint:
+		return issue.Sprint != ""
+	case FieldDue:
+		return issue.DueDate != nil && *issue.DueDate != ""
+	}
+	return true
+}
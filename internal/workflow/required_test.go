@@ -0,0 +1,56 @@
+package workflow
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestMissingFields(t *testing.T) {
+	rules := []models.RequiredFieldsRule{
+		{Type: "bug", To: "in_progress", Fields: []string{FieldDescription}},
+		{Type: "feature", To: "in_review", Fields: []string{FieldAcceptance}},
+		{To: "closed", Fields: []string{FieldPoints, FieldLabels}},
+		{Type: "bug", To: "closed", Fields: []string{FieldLabels, FieldDescription}},
+	}
+	due := "2026-01-02"
+
+	tests := []struct {
+		name  string
+		issue models.Issue
+		to    models.Status
+		want  []string
+	}{
+		{"bug start without description", models.Issue{Type: models.TypeBug}, models.StatusInProgress, []string{FieldDescription}},
+		{"bug start blank description", models.Issue{Type: models.TypeBug, Description: "  "}, models.StatusInProgress, []string{FieldDescription}},
+		{"bug start with description", models.Issue{Type: models.TypeBug, Description: "steps"}, models.StatusInProgress, nil},
+		{"task start has no rule", models.Issue{Type: models.TypeTask}, models.StatusInProgress, nil},
+		{"feature review", models.Issue{Type: models.TypeFeature}, models.StatusInReview, []string{FieldAcceptance}},
+		{"bug close merges rules", models.Issue{Type: models.TypeBug, Points: 2}, models.StatusClosed, []string{FieldLabels, FieldDescription}},
+		{"task close satisfied", models.Issue{Type: models.TypeTask, Points: 1, Labels: []string{"x"}, DueDate: &due}, models.StatusClosed, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MissingFields(&tt.issue, tt.to, rules); !slices.Equal(got, tt.want) {
+				t.Errorf("MissingFields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateRequiredFieldsRule(t *testing.T) {
+	bad := []models.RequiredFieldsRule{
+		{Type: "story", To: "in_progress", Fields: []string{FieldDescription}},
+		{Type: "bug", To: "started", Fields: []string{FieldDescription}},
+		{Type: "bug", To: "in_progress", Fields: []string{"title"}},
+	}
+	for _, r := range bad {
+		if ValidateRequiredFieldsRule(r) == nil {
+			t.Errorf("ValidateRequiredFieldsRule(%+v) = nil, want error", r)
+		}
+	}
+	if err := ValidateRequiredFieldsRule(models.RequiredFieldsRule{To: "closed", Fields: []string{FieldDue}}); err != nil {
+		t.Errorf("valid rule: %v", err)
+	}
+}
@@ -55,6 +55,9 @@ type TransitionContext struct {
 	Minor       bool
 	Context     ActionContext
 	WasInvolved bool // Whether current session was involved with issue
+	HasHandoff  bool // Whether the issue has a handoff; set for policy hooks
+	// Review checklist items the reviewer acknowledged; set for approvals
+	Acknowledged []string
 }
 
 // Transition defines a valid status transition with optional guards
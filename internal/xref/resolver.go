@@ -0,0 +1,87 @@
+package xref
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+// ErrUnknownProject is returned for references to projects that aren't linked
+var ErrUnknownProject = errors.New("unknown project")
+
+// Resolver looks up referenced issues in the projects linked from one
+// project. Each linked database is opened on first use and kept open
+// until Close. Resolvers only read from linked projects.
+type Resolver struct {
+	projects map[string]string
+
+	mu  sync.Mutex
+	dbs map[string]*db.DB
+}
+
+// NewResolver returns a resolver for the projects linked in baseDir's config
+func NewResolver(baseDir string) (*Resolver, error) {
+	projects, err := config.GetLinkedProjects(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	return NewResolverFor(projects), nil
+}
+
+// NewResolverFor returns a resolver for the given name → path map
+func NewResolverFor(projects map[string]string) *Resolver {
+	return &Resolver{projects: projects, dbs: make(map[string]*db.DB)}
+}
+
+// Has reports whether project is linked
+func (r *Resolver) Has(project string) bool {
+	_, ok := r.projects[project]
+	return ok
+}
+
+// Resolve returns the referenced issue. The issue ID in ref may omit the
+// prefix; the returned issue carries the other project's canonical ID.
+func (r *Resolver) Resolve(ref Ref) (*models.Issue, error) {
+	database, err := r.open(ref.Project)
+	if err != nil {
+		return nil, err
+	}
+	issue, err := database.GetIssue(ref.IssueID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ref.Project, err)
+	}
+	return issue, nil
+}
+
+func (r *Resolver) open(project string) (*db.DB, error) {
+	path, ok := r.projects[project]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s (link it with td project link)", ErrUnknownProject, project)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if database, ok := r.dbs[project]; ok {
+		return database, nil
+	}
+	database, err := db.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open project %s: %w", project, err)
+	}
+	r.dbs[project] = database
+	return database, nil
+}
+
+// Close closes the linked databases opened so far
+func (r *Resolver) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for name, database := range r.dbs {
+		database.Close()
+		delete(r.dbs, name)
+	}
+}
@@ -0,0 +1,85 @@
+// Package xref parses and resolves references to issues in other td
+// projects.
+//
+// A reference is "<project>/<issue-id>", for example api/td-a1b2c3, where
+// project is a name registered with td project link. Qualified IDs can be
+// used as dependencies and are recognised in descriptions; they are never
+// looked up in the local database, so a local td-a1b2c3 and api/td-a1b2c3
+// stay distinct.
+package xref
+
+import (
+	"regexp"
+	"strings"
+)
+
+// namePattern matches linked project names
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,39}$`)
+
+// issuePattern matches the issue part of a reference. The prefix is
+// optional; it is filled in from the other project when the reference
+// is resolved.
+var issuePattern = regexp.MustCompile(`^(?:[a-z][a-z0-9]{0,9}-)?[0-9a-z]{4,}$`)
+
+// textPattern finds fully qualified references in free text. The leading
+// group keeps paths and URLs such as github.com/acme/td-abc123 from
+// matching on their last two segments.
+var textPattern = regexp.MustCompile(`(?:^|[^\w./-])([a-z0-9][a-z0-9_-]{0,39})/([a-z][a-z0-9]{0,9}-[0-9a-z]{4,})\b`)
+
+// Ref is a reference to an issue in another project
+type Ref struct {
+	Project string `json:"project"`
+	IssueID string `json:"issue_id"`
+}
+
+// String returns the project-qualified ID
+func (r Ref) String() string {
+	return r.Project + "/" + r.IssueID
+}
+
+// Matches reports whether r and o name the same issue, allowing either
+// side to omit the issue prefix (api/a1b2c3 matches api/td-a1b2c3)
+func (r Ref) Matches(o Ref) bool {
+	if r.Project != o.Project {
+		return false
+	}
+	return r.IssueID == o.IssueID ||
+		strings.HasSuffix(r.IssueID, "-"+o.IssueID) ||
+		strings.HasSuffix(o.IssueID, "-"+r.IssueID)
+}
+
+// ValidName reports whether name can be used for a linked project
+func ValidName(name string) bool {
+	return namePattern.MatchString(name)
+}
+
+// IsQualified reports whether id is project-qualified rather than local
+func IsQualified(id string) bool {
+	return strings.Contains(id, "/")
+}
+
+// Parse splits a project-qualified ID. ok is false for local IDs and
+// anything that isn't a well-formed reference.
+func Parse(s string) (ref Ref, ok bool) {
+	project, issueID, found := strings.Cut(strings.TrimSpace(s), "/")
+	if !found || !namePattern.MatchString(project) || !issuePattern.MatchString(issueID) {
+		return Ref{}, false
+	}
+	return Ref{Project: project, IssueID: issueID}, true
+}
+
+// FindAll returns the distinct references in text, in order of first
+// appearance. Only fully qualified IDs (with an issue prefix) count, and
+// whether the project exists is left to the resolver.
+func FindAll(text string) []Ref {
+	var refs []Ref
+	seen := make(map[Ref]bool)
+	for _, m := range textPattern.FindAllStringSubmatch(text, -1) {
+		ref := Ref{Project: m[1], IssueID: m[2]}
+		if !seen[ref] {
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
@@ -0,0 +1,90 @@
+package xref
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Ref
+		ok   bool
+	}{
+		{"api/td-a1b2c3", Ref{"api", "td-a1b2c3"}, true},
+		{"web_app/a1b2c3", Ref{"web_app", "a1b2c3"}, true},
+		{" ops/ops-9f3e ", Ref{"ops", "ops-9f3e"}, true},
+		{"td-a1b2c3", Ref{}, false},
+		{"API/td-a1b2c3", Ref{}, false},
+		{"api/", Ref{}, false},
+		{"api/td-a1b2c3/x", Ref{}, false},
+		{"src/main.go", Ref{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := Parse(tt.in)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("Parse(%q) = %v, %v; want %v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	ref := Ref{"api", "td-a1b2c3"}
+	for _, o := range []Ref{{"api", "td-a1b2c3"}, {"api", "a1b2c3"}} {
+		if !ref.Matches(o) || !o.Matches(ref) {
+			t.Errorf("%v should match %v", ref, o)
+		}
+	}
+	for _, o := range []Ref{{"web", "td-a1b2c3"}, {"api", "td-a1b2c4"}, {"api", "b2c3"}} {
+		if ref.Matches(o) {
+			t.Errorf("%v should not match %v", ref, o)
+		}
+	}
+}
+
+func TestFindAll(t *testing.T) {
+	text := `Needs api/td-a1b2c3 first (and web/td-ffee01, api/td-a1b2c3 again).
+See https://github.com/acme/td-abc123 and docs/td-readme.md, not local td-123456.`
+	want := []Ref{{"api", "td-a1b2c3"}, {"web", "td-ffee01"}, {"docs", "td-readme"}}
+	if got := FindAll(text); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindAll = %v, want %v", got, want)
+	}
+	if got := FindAll("no references here"); got != nil {
+		t.Errorf("FindAll = %v, want nil", got)
+	}
+}
+
+func TestResolver(t *testing.T) {
+	dir := t.TempDir()
+	other, err := db.Initialize(dir)
+	if err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	issue := &models.Issue{Title: "Rate limit the export endpoint", Status: models.StatusOpen}
+	if err := other.CreateIssue(issue); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+	other.Close()
+
+	r := NewResolverFor(map[string]string{"api": dir})
+	defer r.Close()
+
+	got, err := r.Resolve(Ref{Project: "api", IssueID: issue.ID[len("td-"):]})
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if got.ID != issue.ID || got.Title != issue.Title {
+		t.Errorf("Resolve = %s %q", got.ID, got.Title)
+	}
+
+	if _, err := r.Resolve(Ref{Project: "api", IssueID: "td-000000"}); err == nil {
+		t.Error("expected error for missing issue")
+	}
+	if _, err := r.Resolve(Ref{Project: "web", IssueID: issue.ID}); !errors.Is(err, ErrUnknownProject) {
+		t.Errorf("unknown project err = %v", err)
+	}
+}
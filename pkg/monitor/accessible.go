@@ -0,0 +1,179 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/pkg/monitor/modal"
+)
+
+// Accessible mode makes the monitor usable with screen readers and for
+// people who can't rely on color: high-contrast styles that mark state with
+// bold, underline and reverse video instead of shades, ASCII in place of
+// box-drawing and symbol glyphs, letters for issue types, and a footer that
+// announces where focus is in reading order.
+
+// asciiGlyphs swaps the glyphs the monitor draws for ASCII of the same
+// width, so layout is unchanged
+var asciiGlyphs = strings.NewReplacer(
+	"╭", "+", "╮", "+", "╰", "+", "╯", "+",
+	"┌", "+", "┐", "+", "└", "+", "┘", "+",
+	"├", "+", "┤", "+", "┬", "+", "┴", "+", "┼", "+",
+	"─", "-", "━", "-", "═", "=", "│", "|", "┃", "|", "║", "|",
+	"▲", "^", "↑", "^", "▼", "v", "↓", "v",
+	"→", ">", "▸", ">", "▶", ">", "←", "<", "◀", "<",
+	"…", ".", "·", ".", "—", "-",
+	"•", "*", "●", "*", "★", "*", "◆", "*", "■", "#", "○", "o",
+	"✓", "+", "✔", "+", "✗", "x", "✘", "x", "⚠", "!", "⧩", "Y",
+	"█", "#", "▓", "#", "▒", "#", "░", ":",
+)
+
+// accessibleTypeIcons name issue types by letter rather than shape
+var accessibleTypeIcons = map[models.Type]string{
+	models.TypeEpic:    "E",
+	models.TypeFeature: "F",
+	models.TypeBug:     "B",
+	models.TypeTask:    "T",
+	models.TypeChore:   "C",
+}
+
+// EnableAccessible turns on accessible mode. Styles are shared by every
+// monitor in the process, so it is meant to be called once, before the
+// program starts.
+func (m *Model) EnableAccessible() {
+	m.Accessible = true
+	useAccessibleStyles()
+}
+
+// useAccessibleStyles replaces the palette with the terminal's own
+// foreground and a few basic ANSI colors, and marks selection and
+// emphasis with attributes that survive any color scheme
+func useAccessibleStyles() {
+	plain := lipgloss.NewStyle()
+	bold := plain.Bold(true)
+	reverse := plain.Reverse(true)
+
+	mutedColor = lipgloss.Color("")
+	subtleStyle = plain
+	helpStyle = plain
+	timestampStyle = plain
+
+	statusStyles = map[models.Status]lipgloss.Style{
+		models.StatusOpen:       plain,
+		models.StatusInProgress: bold,
+		models.StatusBlocked:    bold.Underline(true),
+		models.StatusInReview:   bold,
+		models.StatusClosed:     plain,
+	}
+	priorityStyles = map[models.Priority]lipgloss.Style{
+		models.PriorityP0: bold.Underline(true),
+		models.PriorityP1: bold,
+		models.PriorityP2: plain,
+		models.PriorityP3: plain,
+		models.PriorityP4: plain,
+	}
+	typeIcons = accessibleTypeIcons
+	typeIconStyles = map[models.Type]lipgloss.Style{}
+
+	selectedRowStyle = reverse
+	highlightRowStyle = reverse
+	activityTableSelectedStyle = reverse
+	epicTaskSelectedStyle = reverse
+	parentEpicFocusedStyle = reverse
+	blockedBySelectedStyle = reverse
+	blocksSelectedStyle = reverse
+	rowHighlightCode = "\x1b[7m" // reverse video
+	rowHoverCode = "\x1b[4m"     // underline
+
+	toastStyle = reverse.Bold(true)
+	toastErrorStyle = reverse.Bold(true)
+	modal.InfoToast = reverse.Bold(true)
+	modal.SuccessToast = reverse.Bold(true)
+	modal.ErrorToast = reverse.Bold(true)
+	buttonStyle = plain.Padding(0, 2)
+	buttonHoverStyle = plain.Underline(true).Padding(0, 2)
+	buttonFocusedStyle = reverse.Bold(true).Padding(0, 2)
+	buttonDangerStyle = buttonStyle
+	buttonDangerHoverStyle = buttonHoverStyle
+	buttonDangerFocusedStyle = buttonFocusedStyle
+}
+
+// panelNames are the panels in focus order, as announced
+var panelNames = []struct {
+	panel Panel
+	name  string
+}{
+	{PanelCurrentWork, "Current work"},
+	{PanelTaskList, "Task list"},
+	{PanelActivity, "Activity log"},
+}
+
+// focusAnnouncement describes the focused panel and row in words, for the
+// accessible footer: "Task list, panel 2 of 3, row 4 of 12: td-a1b2c3
+// [open] P1 Fix login timeout"
+func (m Model) focusAnnouncement() string {
+	name, index := "", 0
+	for i, p := range panelNames {
+		if p.panel == m.ActivePanel {
+			name, index = p.name, i+1
+		}
+	}
+	if name == "" {
+		return ""
+	}
+	announcement := fmt.Sprintf("%s, panel %d of %d", name, index, len(panelNames))
+
+	count := m.rowCount(m.ActivePanel)
+	if count == 0 {
+		return announcement + ", empty"
+	}
+	row := m.Cursor[m.ActivePanel]
+	if m.ActivePanel == PanelTaskList && m.TaskListMode == TaskListModeBoard {
+		row = m.BoardMode.Cursor
+		if m.BoardMode.ViewMode == BoardViewSwimlanes {
+			row = m.BoardMode.SwimlaneCursor
+		}
+	}
+	announcement += fmt.Sprintf(", row %d of %d", row+1, count)
+
+	if m.ActivePanel == PanelActivity && row < len(m.Activity) {
+		item := m.Activity[row]
+		return fmt.Sprintf("%s: %s %s %s", announcement, item.Type, item.IssueID, item.Message)
+	}
+	id := m.SelectedIssueID(m.ActivePanel)
+	if issue := m.announcedIssue(id); issue != nil {
+		return fmt.Sprintf("%s: %s [%s] %s %s", announcement, issue.ID, issue.Status, issue.Priority, issue.Title)
+	}
+	if id != "" {
+		return announcement + ": " + id
+	}
+	return announcement
+}
+
+// announcedIssue finds a listed issue by ID among the monitor's data
+func (m Model) announcedIssue(id string) *models.Issue {
+	if id == "" {
+		return nil
+	}
+	if m.FocusedIssue != nil && m.FocusedIssue.ID == id {
+		return m.FocusedIssue
+	}
+	for i := range m.InProgress {
+		if m.InProgress[i].ID == id {
+			return &m.InProgress[i]
+		}
+	}
+	for i := range m.TaskListRows {
+		if m.TaskListRows[i].Issue.ID == id {
+			return &m.TaskListRows[i].Issue
+		}
+	}
+	for i := range m.BoardMode.Issues {
+		if m.BoardMode.Issues[i].Issue.ID == id {
+			return &m.BoardMode.Issues[i].Issue
+		}
+	}
+	return nil
+}
@@ -0,0 +1,489 @@
+package monitor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/td/internal/i18n"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/workflow"
+	"github.com/marcus/td/pkg/monitor/modal"
+	"github.com/marcus/td/pkg/monitor/mouse"
+)
+
+// Action menu item IDs (also used as modal action IDs)
+const (
+	issueActionStart   = "start"
+	issueActionReview  = "review"
+	issueActionApprove = "approve"
+	issueActionBlock   = "block"
+	issueActionComment = "comment"
+	issueActionVote    = "vote"
+)
+
+// actionMenuStep identifies which screen of the action menu is showing
+type actionMenuStep int
+
+const (
+	actionMenuStepPick    actionMenuStep = iota // choosing an action
+	actionMenuStepBlock                         // entering a block reason
+	actionMenuStepComment                       // entering comment text
+	actionMenuStepVote                          // entering poker points
+)
+
+// actionMenuState holds action menu state. Stored as a pointer on Model so the
+// modal's list and input sections (which capture pointers at creation time)
+// keep working after Bubble Tea copies the Model.
+type actionMenuState struct {
+	IssueID string
+	Title   string
+	Status  models.Status
+	Items   []modal.ListItem
+	Cursor  int
+	Step    actionMenuStep
+	Input   textinput.Model
+	Error   string
+
+	PokerRoundID string // open poker round the issue is in, if any
+}
+
+// availableIssueActions returns the menu items valid for an issue in its
+// current state, as seen by the given session.
+func availableIssueActions(issue *models.Issue, sessionID string) []modal.ListItem {
+	sm := workflow.DefaultMachine()
+	var items []modal.ListItem
+
+	if issue.Status != models.StatusInProgress && sm.IsValidTransition(issue.Status, models.StatusInProgress) {
+		items = append(items, modal.ListItem{ID: issueActionStart, Label: "s  Start work"})
+	}
+	if issue.Status != models.StatusInReview && sm.IsValidTransition(issue.Status, models.StatusInReview) {
+		items = append(items, modal.ListItem{ID: issueActionReview, Label: "r  Submit for review"})
+	}
+	if issue.Status == models.StatusInReview && issue.ImplementerSession != sessionID {
+		items = append(items, modal.ListItem{ID: issueActionApprove, Label: "a  Approve"})
+	}
+	if issue.Status != models.StatusBlocked && sm.IsValidTransition(issue.Status, models.StatusBlocked) {
+		items = append(items, modal.ListItem{ID: issueActionBlock, Label: "b  Block (with reason)"})
+	}
+	items = append(items, modal.ListItem{ID: issueActionComment, Label: "c  Add comment"})
+
+	return items
+}
+
+// actionTargetIssue resolves the issue the action menu should act on:
+// highlighted epic task > open modal issue > active panel selection
+func (m Model) actionTargetIssue() *models.Issue {
+	var issueID string
+	if md := m.CurrentModal(); md != nil && md.Issue != nil {
+		if md.TaskSectionFocused && len(md.EpicTasks) > 0 && md.EpicTasksCursor < len(md.EpicTasks) {
+			issueID = md.EpicTasks[md.EpicTasksCursor].ID
+		} else {
+			issueID = md.IssueID
+		}
+	} else {
+		issueID = m.SelectedIssueID(m.ActivePanel)
+	}
+	if issueID == "" {
+		return nil
+	}
+	issue, err := m.DB.GetIssue(issueID)
+	if err != nil {
+		return nil
+	}
+	return issue
+}
+
+// openActionMenu opens the contextual action menu for the selected issue
+func (m Model) openActionMenu() (tea.Model, tea.Cmd) {
+	issue := m.actionTargetIssue()
+	if issue == nil {
+		return m, nil
+	}
+
+	input := textinput.New()
+	input.Width = 40
+	input.CharLimit = 500
+
+	m.ActionMenu = &actionMenuState{
+		IssueID: issue.ID,
+		Title:   issue.Title,
+		Status:  issue.Status,
+		Items:   availableIssueActions(issue, m.SessionID),
+		Input:   input,
+	}
+	if rounds, err := m.DB.OpenPokerRoundsForIssue(issue.ID); err == nil && len(rounds) > 0 {
+		m.ActionMenu.PokerRoundID = rounds[0].ID
+		m.ActionMenu.Items = append(m.ActionMenu.Items, modal.ListItem{ID: issueActionVote, Label: "p  Vote points (" + rounds[0].ID + ")"})
+	}
+	m.ActionMenuOpen = true
+	m.ActionMenuModal = m.createActionMenuModal()
+	m.ActionMenuModal.Reset()
+	m.ActionMenuMouseHandler = mouse.NewHandler()
+	return m, nil
+}
+
+// closeActionMenu closes the action menu and clears state
+func (m *Model) closeActionMenu() {
+	m.ActionMenuOpen = false
+	m.ActionMenu = nil
+	m.ActionMenuModal = nil
+	m.ActionMenuMouseHandler = nil
+}
+
+// setActionMenuStep switches the menu to a different step and rebuilds the modal
+func (m *Model) setActionMenuStep(step actionMenuStep) tea.Cmd {
+	st := m.ActionMenu
+	st.Step = step
+	st.Error = ""
+	st.Input.SetValue("")
+	switch step {
+	case actionMenuStepBlock:
+		st.Input.Placeholder = "Why is this blocked?"
+	case actionMenuStepComment:
+		st.Input.Placeholder = "Comment text"
+	case actionMenuStepVote:
+		st.Input.Placeholder = "1, 2, 3, 5, 8, 13 or 21"
+	}
+	m.ActionMenuModal = m.createActionMenuModal()
+	m.ActionMenuModal.Reset()
+	m.ActionMenuMouseHandler = mouse.NewHandler()
+	if step == actionMenuStepPick {
+		st.Input.Blur()
+		return nil
+	}
+	return st.Input.Focus()
+}
+
+// createActionMenuModal builds the declarative modal for the current step
+func (m *Model) createActionMenuModal() *modal.Modal {
+	st := m.ActionMenu
+
+	displayTitle := st.Title
+	if len(displayTitle) > 40 {
+		displayTitle = displayTitle[:37] + "..."
+	}
+
+	switch st.Step {
+	case actionMenuStepBlock, actionMenuStepComment, actionMenuStepVote:
+		title, label, button := fmt.Sprintf("Block %s", st.IssueID), "Reason:", " Block "
+		variant := modal.VariantWarning
+		switch st.Step {
+		case actionMenuStepComment:
+			title, label, button = fmt.Sprintf("Comment on %s", st.IssueID), "Comment:", " Comment "
+			variant = modal.VariantDefault
+		case actionMenuStepVote:
+			title, label, button = fmt.Sprintf("Vote on %s (blind)", st.IssueID), "Points:", " Vote "
+			variant = modal.VariantDefault
+		}
+
+		md := modal.New(title,
+			modal.WithWidth(56),
+			modal.WithVariant(variant),
+			modal.WithHints(false),
+			modal.WithPrimaryAction("submit"),
+		)
+		md.AddSection(modal.Text("\"" + displayTitle + "\""))
+		md.AddSection(modal.Spacer())
+		md.AddSection(modal.InputWithLabel("text", label, &st.Input,
+			modal.WithSubmitOnEnter(true),
+			modal.WithSubmitAction("submit"),
+		))
+		md.AddSection(modal.When(func() bool { return st.Error != "" },
+			modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+				return modal.RenderedSection{Content: errorStyle.Render(st.Error)}
+			}, nil)))
+		md.AddSection(modal.Spacer())
+		md.AddSection(modal.Buttons(
+			modal.Btn(button, "submit", modal.BtnPrimary()),
+			modal.Btn(" Back ", "back"),
+		))
+		md.AddSection(modal.Spacer())
+		md.AddSection(modal.Text("Tab:switch  Enter:submit  Esc:back"))
+		return md
+
+	default:
+		md := modal.New(fmt.Sprintf("%s [%s]", st.IssueID, st.Status),
+			modal.WithWidth(50),
+			modal.WithHints(false),
+		)
+		md.AddSection(modal.Text("\"" + displayTitle + "\""))
+		md.AddSection(modal.Spacer())
+		md.AddSection(modal.List("actions", st.Items, &st.Cursor, modal.WithMaxVisible(len(st.Items))))
+		md.AddSection(modal.Spacer())
+		md.AddSection(modal.Text("j/k:select  Enter:run  Esc:close"))
+		return md
+	}
+}
+
+// handleActionMenuKey routes key presses while the action menu is open.
+// All keys are consumed so nothing leaks to the panels underneath.
+func (m Model) handleActionMenuKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	st := m.ActionMenu
+	if st == nil || m.ActionMenuModal == nil {
+		m.closeActionMenu()
+		return m, nil
+	}
+
+	// Letter shortcuts on the pick screen
+	if st.Step == actionMenuStepPick && msg.Type == tea.KeyRunes && len(msg.Runes) == 1 {
+		shortcuts := map[rune]string{
+			's': issueActionStart,
+			'r': issueActionReview,
+			'a': issueActionApprove,
+			'b': issueActionBlock,
+			'c': issueActionComment,
+			'p': issueActionVote,
+		}
+		if id, ok := shortcuts[msg.Runes[0]]; ok {
+			for _, item := range st.Items {
+				if item.ID == id {
+					return m.handleActionMenuAction(id)
+				}
+			}
+			return m, nil
+		}
+	}
+
+	action, cmd := m.ActionMenuModal.HandleKey(msg)
+	if action != "" {
+		return m.handleActionMenuAction(action)
+	}
+	return m, cmd
+}
+
+// handleActionMenuAction handles actions from the action menu modal
+func (m Model) handleActionMenuAction(action string) (tea.Model, tea.Cmd) {
+	st := m.ActionMenu
+	if st == nil {
+		return m, nil
+	}
+
+	switch action {
+	case "cancel", "back":
+		if st.Step != actionMenuStepPick {
+			return m, m.setActionMenuStep(actionMenuStepPick)
+		}
+		m.closeActionMenu()
+		return m, nil
+
+	case "actions":
+		// Click on the list itself: run the highlighted item
+		if st.Cursor >= 0 && st.Cursor < len(st.Items) {
+			return m.handleActionMenuAction(st.Items[st.Cursor].ID)
+		}
+		return m, nil
+
+	case issueActionBlock:
+		return m, m.setActionMenuStep(actionMenuStepBlock)
+
+	case issueActionComment:
+		return m, m.setActionMenuStep(actionMenuStepComment)
+
+	case issueActionVote:
+		return m, m.setActionMenuStep(actionMenuStepVote)
+
+	case "submit", "text":
+		text := strings.TrimSpace(st.Input.Value())
+		if st.Step == actionMenuStepVote {
+			return m.castPokerVote(text)
+		}
+		if text == "" {
+			if st.Step == actionMenuStepBlock {
+				st.Error = i18n.T("A reason is required to block")
+			} else {
+				st.Error = i18n.T("Comment cannot be empty")
+			}
+			return m, nil
+		}
+		if st.Step == actionMenuStepBlock {
+			return m.runIssueAction(issueActionBlock, st.IssueID, text)
+		}
+		return m.runIssueAction(issueActionComment, st.IssueID, text)
+
+	case issueActionStart, issueActionReview, issueActionApprove:
+		return m.runIssueAction(action, st.IssueID, "")
+	}
+
+	return m, nil
+}
+
+// runIssueAction applies an action menu action to an issue, optimistically
+// updates the view, then triggers a refresh to reconcile with the database.
+func (m Model) runIssueAction(action, issueID, text string) (tea.Model, tea.Cmd) {
+	m.closeActionMenu()
+
+	issue, err := m.DB.GetIssue(issueID)
+	if err != nil {
+		return m.actionMenuStatus("Failed: "+err.Error(), true)
+	}
+
+	var newStatus models.Status
+	var verb string
+
+	switch action {
+	case issueActionStart:
+		err = m.startIssue(issue)
+		newStatus, verb = models.StatusInProgress, "STARTED"
+	case issueActionReview:
+		err = m.submitIssueForReview(issue)
+		newStatus, verb = models.StatusInReview, "REVIEW"
+	case issueActionApprove:
+		if items := m.reviewChecklist(issue); len(items) > 0 {
+			if err := m.canApprove(issue); err != nil {
+				return m.actionMenuStatus("Failed: "+err.Error(), true)
+			}
+			return m.openApproveChecklist(issue, items), nil
+		}
+		err = m.approveReviewedIssue(issue, nil)
+		newStatus, verb = models.StatusClosed, "APPROVED"
+	case issueActionBlock:
+		err = m.blockIssue(issue, text)
+		newStatus, verb = models.StatusBlocked, "BLOCKED"
+	case issueActionComment:
+		err = m.DB.AddComment(&models.Comment{
+			IssueID:   issue.ID,
+			SessionID: m.SessionID,
+			Text:      text,
+		})
+		verb = "COMMENTED"
+	default:
+		return m, nil
+	}
+
+	if err != nil {
+		return m.actionMenuStatus("Failed: "+err.Error(), true)
+	}
+	return m.issueActionDone(issue.ID, newStatus, verb)
+}
+
+// castPokerVote casts this session's blind vote from the vote step
+func (m Model) castPokerVote(text string) (tea.Model, tea.Cmd) {
+	st := m.ActionMenu
+	points, err := strconv.Atoi(text)
+	if err != nil || !models.IsValidPoints(points) {
+		st.Error = i18n.T("Points must be 1, 2, 3, 5, 8, 13 or 21")
+		return m, nil
+	}
+	if err := m.DB.CastPokerVote(st.PokerRoundID, st.IssueID, m.SessionID, points); err != nil {
+		st.Error = err.Error()
+		return m, nil
+	}
+	issueID := st.IssueID
+	m.closeActionMenu()
+	return m, m.Toasts.Success(fmt.Sprintf("VOTED %d on %s", points, issueID))
+}
+
+// issueActionDone reports an applied issue action in a toast, optimistically
+// shows the new status (if any) and refreshes the affected views.
+func (m Model) issueActionDone(issueID string, newStatus models.Status, verb string) (tea.Model, tea.Cmd) {
+	if newStatus != "" {
+		m.applyOptimisticStatus(issueID, newStatus)
+	}
+
+	cmds := []tea.Cmd{
+		m.Toasts.Success(verb + " " + issueID),
+		m.fetchData(),
+	}
+	if md := m.CurrentModal(); md != nil {
+		cmds = append(cmds, m.fetchIssueDetails(md.IssueID))
+	}
+	if m.TaskListMode == TaskListModeBoard && m.BoardMode.Board != nil {
+		cmds = append(cmds, m.fetchBoardIssues(m.BoardMode.Board.ID))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// actionMenuStatus reports the outcome of an action in a toast
+func (m Model) actionMenuStatus(msg string, isError bool) (tea.Model, tea.Cmd) {
+	if isError {
+		return m, m.Toasts.Error(msg)
+	}
+	return m, m.Toasts.Success(msg)
+}
+
+// startIssue moves an issue to in_progress and records this session as implementer
+func (m Model) startIssue(issue *models.Issue) error {
+	sm := workflow.DefaultMachine()
+	if !sm.IsValidTransition(issue.Status, models.StatusInProgress) {
+		return fmt.Errorf("cannot start from %s", issue.Status)
+	}
+
+	issue.Status = models.StatusInProgress
+	issue.ImplementerSession = m.SessionID
+	if err := m.DB.UpdateIssueLogged(issue, m.SessionID, models.ActionStart); err != nil {
+		return err
+	}
+	m.DB.RecordSessionAction(issue.ID, m.SessionID, models.ActionSessionStarted)
+	m.DB.AddLog(&models.Log{
+		IssueID:   issue.ID,
+		SessionID: m.SessionID,
+		Message:   "Started work",
+		Type:      models.LogTypeProgress,
+	})
+	return nil
+}
+
+// blockIssue moves an issue to blocked and logs the reason as a blocker
+func (m Model) blockIssue(issue *models.Issue, reason string) error {
+	sm := workflow.DefaultMachine()
+	if !sm.IsValidTransition(issue.Status, models.StatusBlocked) {
+		return fmt.Errorf("cannot block from %s", issue.Status)
+	}
+
+	issue.Status = models.StatusBlocked
+	if err := m.DB.UpdateIssueLogged(issue, m.SessionID, models.ActionBlock); err != nil {
+		return err
+	}
+	m.DB.AddLog(&models.Log{
+		IssueID:   issue.ID,
+		SessionID: m.SessionID,
+		Message:   "Blocked: " + reason,
+		Type:      models.LogTypeBlocker,
+	})
+	return nil
+}
+
+// applyOptimisticStatus updates every in-memory copy of an issue with its new
+// status so the view reflects the change before the next refresh lands.
+func (m *Model) applyOptimisticStatus(issueID string, status models.Status) {
+	update := func(issues []models.Issue) {
+		for i := range issues {
+			if issues[i].ID == issueID {
+				issues[i].Status = status
+			}
+		}
+	}
+
+	if m.FocusedIssue != nil && m.FocusedIssue.ID == issueID {
+		m.FocusedIssue.Status = status
+	}
+	update(m.InProgress)
+	update(m.TaskList.Reviewable)
+	update(m.TaskList.NeedsRework)
+	update(m.TaskList.InProgress)
+	update(m.TaskList.Ready)
+	update(m.TaskList.NeedsTriage)
+	update(m.TaskList.PendingReview)
+	update(m.TaskList.Blocked)
+	update(m.TaskList.Closed)
+	for i := range m.TaskListRows {
+		if m.TaskListRows[i].Issue.ID == issueID {
+			m.TaskListRows[i].Issue.Status = status
+		}
+	}
+	for i := range m.BoardMode.Issues {
+		if m.BoardMode.Issues[i].Issue.ID == issueID {
+			m.BoardMode.Issues[i].Issue.Status = status
+		}
+	}
+	for i := range m.ModalStack {
+		entry := &m.ModalStack[i]
+		if entry.Issue != nil && entry.Issue.ID == issueID {
+			entry.Issue.Status = status
+		}
+		update(entry.EpicTasks)
+	}
+}
@@ -0,0 +1,250 @@
+package monitor
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/pkg/monitor/keymap"
+)
+
+func newActionMenuTestModel(t *testing.T) (Model, *db.DB) {
+	t.Helper()
+	database, err := db.Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	m := newTestModel()
+	m.DB = database
+	return m, database
+}
+
+func actionIDs(m Model) []string {
+	var ids []string
+	for _, item := range m.ActionMenu.Items {
+		ids = append(ids, item.ID)
+	}
+	return ids
+}
+
+func TestActionMenuKeybinding(t *testing.T) {
+	km := newTestKeymap()
+	for _, ctx := range []keymap.Context{keymap.ContextMain, keymap.ContextBoard, keymap.ContextModal} {
+		cmd, found := km.Lookup(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'.'}}, ctx)
+		if !found || cmd != keymap.CmdOpenActionMenu {
+			t.Errorf("'.' in %s = %v (found=%v), want %v", ctx, cmd, found, keymap.CmdOpenActionMenu)
+		}
+	}
+}
+
+func TestAvailableIssueActions(t *testing.T) {
+	tests := []struct {
+		name  string
+		issue models.Issue
+		want  []string
+	}{
+		{
+			name:  "open issue",
+			issue: models.Issue{Status: models.StatusOpen},
+			want:  []string{issueActionStart, issueActionReview, issueActionBlock, issueActionComment},
+		},
+		{
+			name:  "in review by someone else",
+			issue: models.Issue{Status: models.StatusInReview, ImplementerSession: "other"},
+			want:  []string{issueActionStart, issueActionApprove, issueActionComment},
+		},
+		{
+			name:  "in review by self cannot approve",
+			issue: models.Issue{Status: models.StatusInReview, ImplementerSession: "me"},
+			want:  []string{issueActionStart, issueActionComment},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := availableIssueActions(&tt.issue, "me")
+			var got []string
+			for _, item := range items {
+				got = append(got, item.ID)
+			}
+			for _, id := range tt.want {
+				found := false
+				for _, g := range got {
+					if g == id {
+						found = true
+					}
+				}
+				if !found {
+					t.Errorf("missing action %q in %v", id, got)
+				}
+			}
+			if tt.issue.ImplementerSession == "me" {
+				for _, g := range got {
+					if g == issueActionApprove {
+						t.Errorf("approve offered for own implementation")
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestActionMenuStartUpdatesOptimistically(t *testing.T) {
+	m, database := newActionMenuTestModel(t)
+	issue := createTestIssue(t, database, "Ready task", models.StatusOpen)
+	m.TaskList.Ready = []models.Issue{*issue}
+	m.buildTaskListRows()
+	m.SelectedID[PanelTaskList] = issue.ID
+
+	result, _ := m.executeCommand(keymap.CmdOpenActionMenu)
+	m = result.(Model)
+	if !m.ActionMenuOpen || m.ActionMenu == nil {
+		t.Fatal("expected action menu to open")
+	}
+	if ids := actionIDs(m); len(ids) == 0 || ids[0] != issueActionStart {
+		t.Fatalf("first action = %v, want start", ids)
+	}
+
+	result, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	m = result.(Model)
+
+	if m.ActionMenuOpen {
+		t.Error("menu should close after running an action")
+	}
+	if got := m.TaskList.Ready[0].Status; got != models.StatusInProgress {
+		t.Errorf("optimistic status = %s, want in_progress", got)
+	}
+	stored, err := database.GetIssue(issue.ID)
+	if err != nil {
+		t.Fatalf("GetIssue: %v", err)
+	}
+	if stored.Status != models.StatusInProgress || stored.ImplementerSession != m.SessionID {
+		t.Errorf("stored status=%s implementer=%q, want in_progress/%s", stored.Status, stored.ImplementerSession, m.SessionID)
+	}
+}
+
+func TestActionMenuBlockRequiresReason(t *testing.T) {
+	m, database := newActionMenuTestModel(t)
+	issue := createTestIssue(t, database, "Task to block", models.StatusOpen)
+	m.TaskList.Ready = []models.Issue{*issue}
+	m.buildTaskListRows()
+	m.SelectedID[PanelTaskList] = issue.ID
+
+	result, _ := m.openActionMenu()
+	m = result.(Model)
+	result, _ = m.handleActionMenuAction(issueActionBlock)
+	m = result.(Model)
+	if m.ActionMenu.Step != actionMenuStepBlock {
+		t.Fatalf("step = %v, want block", m.ActionMenu.Step)
+	}
+
+	// Empty reason keeps the menu open with an error
+	result, _ = m.handleActionMenuAction("submit")
+	m = result.(Model)
+	if !m.ActionMenuOpen || m.ActionMenu.Error == "" {
+		t.Fatal("expected validation error for empty reason")
+	}
+
+	m.ActionMenu.Input.SetValue("waiting on API keys")
+	result, _ = m.handleActionMenuAction("submit")
+	m = result.(Model)
+	if m.ActionMenuOpen {
+		t.Error("menu should close after blocking")
+	}
+
+	stored, _ := database.GetIssue(issue.ID)
+	if stored.Status != models.StatusBlocked {
+		t.Errorf("status = %s, want blocked", stored.Status)
+	}
+	logs, err := database.GetLogs(issue.ID, 10)
+	if err != nil {
+		t.Fatalf("GetLogs: %v", err)
+	}
+	found := false
+	for _, l := range logs {
+		if l.Type == models.LogTypeBlocker && l.Message == "Blocked: waiting on API keys" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected blocker log with reason, got %+v", logs)
+	}
+}
+
+func TestActionMenuEscFromStepReturnsToPick(t *testing.T) {
+	m, database := newActionMenuTestModel(t)
+	issue := createTestIssue(t, database, "Comment target", models.StatusOpen)
+	m.TaskList.Ready = []models.Issue{*issue}
+	m.buildTaskListRows()
+	m.SelectedID[PanelTaskList] = issue.ID
+
+	result, _ := m.openActionMenu()
+	m = result.(Model)
+	result, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	m = result.(Model)
+	if m.ActionMenu.Step != actionMenuStepComment {
+		t.Fatalf("step = %v, want comment", m.ActionMenu.Step)
+	}
+
+	result, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyEsc})
+	m = result.(Model)
+	if !m.ActionMenuOpen || m.ActionMenu.Step != actionMenuStepPick {
+		t.Fatal("esc from comment step should return to the action list")
+	}
+
+	result, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyEsc})
+	m = result.(Model)
+	if m.ActionMenuOpen {
+		t.Error("esc from action list should close the menu")
+	}
+}
+
+func TestActionMenuPokerVote(t *testing.T) {
+	m, database := newActionMenuTestModel(t)
+	issue := createTestIssue(t, database, "Task to estimate", models.StatusOpen)
+	m.TaskList.Ready = []models.Issue{*issue}
+	m.buildTaskListRows()
+	m.SelectedID[PanelTaskList] = issue.ID
+
+	result, _ := m.openActionMenu()
+	m = result.(Model)
+	for _, id := range actionIDs(m) {
+		if id == issueActionVote {
+			t.Fatal("vote should only be offered for issues in an open poker round")
+		}
+	}
+	m.closeActionMenu()
+
+	round, err := database.CreatePokerRound("", []string{issue.ID}, "ses_other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, _ = m.openActionMenu()
+	m = result.(Model)
+	result, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	m = result.(Model)
+	if m.ActionMenu == nil || m.ActionMenu.Step != actionMenuStepVote {
+		t.Fatal("p should open the vote step")
+	}
+
+	m.ActionMenu.Input.SetValue("4")
+	result, _ = m.handleActionMenuAction("submit")
+	m = result.(Model)
+	if !m.ActionMenuOpen || m.ActionMenu.Error == "" {
+		t.Fatal("expected an error for 4 points")
+	}
+
+	m.ActionMenu.Input.SetValue("8")
+	result, _ = m.handleActionMenuAction("submit")
+	m = result.(Model)
+	if m.ActionMenuOpen {
+		t.Error("menu should close after voting")
+	}
+	got, _ := database.GetPokerRound(round.ID)
+	if votes := got.Item(issue.ID).Votes; len(votes) != 1 || votes[0].SessionID != m.SessionID || votes[0].Points != 8 {
+		t.Errorf("votes = %+v, want 8 from %s", votes, m.SessionID)
+	}
+}
@@ -1,6 +1,7 @@
 package monitor
 
 import (
+	"fmt"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -44,10 +45,32 @@ func (m Model) markForReview() (tea.Model, tea.Cmd) {
 		}
 	}
 
+	if err := m.submitIssueForReview(issue); err != nil {
+		return m, nil
+	}
+
+	// If we're in a modal, refresh instead of closing to keep context
+	if modal := m.CurrentModal(); modal != nil {
+		if m.TaskListMode == TaskListModeBoard && m.BoardMode.Board != nil {
+			return m, tea.Batch(m.fetchData(), m.fetchBoardIssues(m.BoardMode.Board.ID), m.fetchIssueDetails(modal.IssueID))
+		}
+		// Refresh the modal issue data and epic tasks list
+		return m, tea.Batch(m.fetchData(), m.fetchIssueDetails(modal.IssueID))
+	}
+
+	if m.TaskListMode == TaskListModeBoard && m.BoardMode.Board != nil {
+		return m, tea.Batch(m.fetchData(), m.fetchBoardIssues(m.BoardMode.Board.ID))
+	}
+	return m, m.fetchData()
+}
+
+// submitIssueForReview moves an issue to in_review, cascading down to open
+// descendants and up to the parent epic. Shared by the R key and the action menu.
+func (m Model) submitIssueForReview(issue *models.Issue) error {
 	// Validate transition with state machine
 	sm := workflow.DefaultMachine()
 	if !sm.IsValidTransition(issue.Status, models.StatusInReview) {
-		return m, nil
+		return fmt.Errorf("cannot submit for review from %s", issue.Status)
 	}
 
 	// Update status
@@ -56,12 +79,12 @@ func (m Model) markForReview() (tea.Model, tea.Cmd) {
 		issue.ImplementerSession = m.SessionID
 	}
 	if err := m.DB.UpdateIssueLogged(issue, m.SessionID, models.ActionReview); err != nil {
-		return m, nil
+		return err
 	}
 
 	// Cascade DOWN to descendants if this is a parent issue (epic)
-	if hasChildren, _ := m.DB.HasChildren(issueID); hasChildren {
-		descendants, err := m.DB.GetDescendantIssues(issueID, []models.Status{
+	if hasChildren, _ := m.DB.HasChildren(issue.ID); hasChildren {
+		descendants, err := m.DB.GetDescendantIssues(issue.ID, []models.Status{
 			models.StatusOpen,
 			models.StatusInProgress,
 		})
@@ -75,7 +98,7 @@ func (m Model) markForReview() (tea.Model, tea.Cmd) {
 				m.DB.AddLog(&models.Log{
 					IssueID:   child.ID,
 					SessionID: m.SessionID,
-					Message:   "Cascaded review from " + issueID,
+					Message:   "Cascaded review from " + issue.ID,
 					Type:      models.LogTypeProgress,
 				})
 			}
@@ -83,21 +106,9 @@ func (m Model) markForReview() (tea.Model, tea.Cmd) {
 	}
 
 	// Cascade up to parent epic if all siblings are ready
-	m.DB.CascadeUpParentStatus(issueID, models.StatusInReview, m.SessionID)
-
-	// If we're in a modal, refresh instead of closing to keep context
-	if modal := m.CurrentModal(); modal != nil {
-		if m.TaskListMode == TaskListModeBoard && m.BoardMode.Board != nil {
-			return m, tea.Batch(m.fetchData(), m.fetchBoardIssues(m.BoardMode.Board.ID), m.fetchIssueDetails(modal.IssueID))
-		}
-		// Refresh the modal issue data and epic tasks list
-		return m, tea.Batch(m.fetchData(), m.fetchIssueDetails(modal.IssueID))
-	}
+	m.DB.CascadeUpParentStatus(issue.ID, models.StatusInReview, m.SessionID)
 
-	if m.TaskListMode == TaskListModeBoard && m.BoardMode.Board != nil {
-		return m, tea.Batch(m.fetchData(), m.fetchBoardIssues(m.BoardMode.Board.ID))
-	}
-	return m, m.fetchData()
+	return nil
 }
 
 // confirmDelete opens confirmation dialog for deleting selected issue
@@ -317,15 +328,48 @@ func (m Model) approveIssue() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	if items := m.reviewChecklist(issue); len(items) > 0 {
+		if err := m.canApprove(issue); err != nil {
+			return m, nil
+		}
+		return m.openApproveChecklist(issue, items), nil
+	}
+
+	if err := m.approveReviewedIssue(issue, nil); err != nil {
+		return m, nil
+	}
+
+	// Clear the saved ID so cursor stays at the same position after refresh
+	// The item will move to Closed, and we want cursor at same index for next item
+	m.SelectedID[PanelTaskList] = ""
+
+	if m.TaskListMode == TaskListModeBoard && m.BoardMode.Board != nil {
+		return m, tea.Batch(m.fetchData(), m.fetchBoardIssues(m.BoardMode.Board.ID))
+	}
+	return m, m.fetchData()
+}
+
+// canApprove reports why this session cannot approve an issue, if it can't
+func (m Model) canApprove(issue *models.Issue) error {
 	// Validate transition with state machine
 	sm := workflow.DefaultMachine()
 	if !sm.IsValidTransition(issue.Status, models.StatusClosed) {
-		return m, nil
+		return fmt.Errorf("cannot approve from %s", issue.Status)
 	}
 
 	// Can't approve your own issues
 	if issue.ImplementerSession == m.SessionID {
-		return m, nil
+		return fmt.Errorf("cannot approve your own implementation")
+	}
+	return nil
+}
+
+// approveReviewedIssue closes a reviewable issue as approved by this session,
+// cascading to descendants, the parent epic and dependents. acks acknowledge
+// the issue's review checklist, if the project has one.
+func (m Model) approveReviewedIssue(issue *models.Issue, acks []string) error {
+	if err := m.canApprove(issue); err != nil {
+		return err
 	}
 
 	// Update status
@@ -333,8 +377,8 @@ func (m Model) approveIssue() (tea.Model, tea.Cmd) {
 	issue.Status = models.StatusClosed
 	issue.ReviewerSession = m.SessionID
 	issue.ClosedAt = &now
-	if err := m.DB.UpdateIssueLogged(issue, m.SessionID, models.ActionApprove); err != nil {
-		return m, nil
+	if err := m.DB.ApproveIssueLogged(issue, m.SessionID, acks, false); err != nil {
+		return err
 	}
 
 	// Record session action for bypass prevention
@@ -374,14 +418,7 @@ func (m Model) approveIssue() (tea.Model, tea.Cmd) {
 	// Auto-unblock dependents whose dependencies are now all closed
 	m.DB.CascadeUnblockDependents(issue.ID, m.SessionID)
 
-	// Clear the saved ID so cursor stays at the same position after refresh
-	// The item will move to Closed, and we want cursor at same index for next item
-	m.SelectedID[PanelTaskList] = ""
-
-	if m.TaskListMode == TaskListModeBoard && m.BoardMode.Board != nil {
-		return m, tea.Batch(m.fetchData(), m.fetchBoardIssues(m.BoardMode.Board.ID))
-	}
-	return m, m.fetchData()
+	return nil
 }
 
 // reopenIssue reopens a closed issue
@@ -420,27 +457,25 @@ func (m Model) reopenIssue() (tea.Model, tea.Cmd) {
 	// Validate transition with state machine
 	sm := workflow.DefaultMachine()
 	if !sm.IsValidTransition(issue.Status, models.StatusOpen) {
-		m.StatusMessage = "Cannot reopen from " + string(issue.Status)
-		m.StatusIsError = true
-		return m, tea.Tick(2*time.Second, func(t time.Time) tea.Msg {
-			return ClearStatusMsg{}
-		})
+		return m, m.Toasts.Error("Cannot reopen from " + string(issue.Status))
 	}
 
 	// Update status
+	rework := &models.Rework{
+		IssueID: issueID, Category: models.ReworkOther, Approved: issue.ReviewerSession != "", SessionID: m.SessionID,
+	}
+	wasClosed := issue.Status == models.StatusClosed
 	issue.Status = models.StatusOpen
 	issue.ReviewerSession = ""
 	issue.ClosedAt = nil
 	if err := m.DB.UpdateIssueLogged(issue, m.SessionID, models.ActionReopen); err != nil {
-		m.StatusMessage = "Failed to reopen: " + err.Error()
-		m.StatusIsError = true
-		return m, tea.Tick(2*time.Second, func(t time.Time) tea.Msg {
-			return ClearStatusMsg{}
-		})
+		return m, m.Toasts.Error("Failed to reopen: " + err.Error())
+	}
+	if wasClosed {
+		_ = m.DB.RecordRework(rework)
 	}
 
-	m.StatusMessage = "REOPENED " + issueID
-	m.StatusIsError = false
+	toast := m.Toasts.Success("REOPENED " + issueID)
 
 	// If in modal, refresh modal data
 	if modal := m.CurrentModal(); modal != nil {
@@ -450,7 +485,7 @@ func (m Model) reopenIssue() (tea.Model, tea.Cmd) {
 			modal.Issue.ClosedAt = nil
 		}
 		cmds := []tea.Cmd{
-			tea.Tick(2*time.Second, func(t time.Time) tea.Msg { return ClearStatusMsg{} }),
+			toast,
 			m.fetchData(),
 			m.fetchIssueDetails(modal.IssueID),
 		}
@@ -461,7 +496,7 @@ func (m Model) reopenIssue() (tea.Model, tea.Cmd) {
 	}
 
 	cmds := []tea.Cmd{
-		tea.Tick(2*time.Second, func(t time.Time) tea.Msg { return ClearStatusMsg{} }),
+		toast,
 		m.fetchData(),
 	}
 	if m.TaskListMode == TaskListModeBoard && m.BoardMode.Board != nil {
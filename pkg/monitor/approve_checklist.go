@@ -0,0 +1,180 @@
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/workflow"
+	"github.com/marcus/td/pkg/monitor/modal"
+	"github.com/marcus/td/pkg/monitor/mouse"
+)
+
+// checklistItemPrefix prefixes the approve modal's checkbox IDs
+const checklistItemPrefix = "item-"
+
+// approveChecklistState holds state for the approve modal shown when the
+// project has a review checklist. Stored as a pointer on Model so the
+// modal's checkboxes keep pointing at live state after Bubble Tea copies
+// the Model.
+type approveChecklistState struct {
+	IssueID string
+	Title   string
+	Items   []string
+	Checked []bool
+	Error   string
+}
+
+// acknowledged returns the checked items
+func (st *approveChecklistState) acknowledged() []string {
+	var acked []string
+	for i, item := range st.Items {
+		if st.Checked[i] {
+			acked = append(acked, item)
+		}
+	}
+	return acked
+}
+
+// reviewChecklist returns the items a reviewer must acknowledge to approve
+// an issue, or nil when the project has no checklist for its type
+func (m Model) reviewChecklist(issue *models.Issue) []string {
+	lists, err := config.GetReviewChecklists(m.BaseDir)
+	if err != nil {
+		return nil
+	}
+	return workflow.ChecklistFor(issue.Type, lists)
+}
+
+// openApproveChecklist opens the approve modal with one checkbox per
+// review checklist item
+func (m Model) openApproveChecklist(issue *models.Issue, items []string) Model {
+	m.ApproveChecklist = &approveChecklistState{
+		IssueID: issue.ID,
+		Title:   issue.Title,
+		Items:   items,
+		Checked: make([]bool, len(items)),
+	}
+	m.ApproveChecklistOpen = true
+	m.ApproveChecklistModal = m.createApproveChecklistModal()
+	m.ApproveChecklistModal.Reset()
+	m.ApproveChecklistMouseHandler = mouse.NewHandler()
+	return m
+}
+
+// closeApproveChecklist closes the approve modal and clears state
+func (m *Model) closeApproveChecklist() {
+	m.ApproveChecklistOpen = false
+	m.ApproveChecklist = nil
+	m.ApproveChecklistModal = nil
+	m.ApproveChecklistMouseHandler = nil
+}
+
+// createApproveChecklistModal builds the declarative approve modal
+func (m *Model) createApproveChecklistModal() *modal.Modal {
+	st := m.ApproveChecklist
+
+	displayTitle := st.Title
+	if len(displayTitle) > 44 {
+		displayTitle = displayTitle[:41] + "..."
+	}
+
+	md := modal.New(fmt.Sprintf("Approve %s?", st.IssueID),
+		modal.WithWidth(56),
+		modal.WithHints(false),
+	)
+	md.AddSection(modal.Text("\"" + displayTitle + "\""))
+	md.AddSection(modal.Spacer())
+	md.AddSection(modal.Text("Review checklist:"))
+	for i, item := range st.Items {
+		md.AddSection(modal.Checkbox(fmt.Sprintf("%s%d", checklistItemPrefix, i), item, &st.Checked[i]))
+	}
+	md.AddSection(modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		if st.Error == "" {
+			return modal.RenderedSection{}
+		}
+		return modal.RenderedSection{Content: errorStyle.Render(st.Error)}
+	}, nil))
+	md.AddSection(modal.Spacer())
+	md.AddSection(modal.Buttons(
+		modal.Btn(" Approve ", "approve", modal.BtnPrimary()),
+		modal.Btn(" Cancel ", "cancel"),
+	))
+	md.AddSection(modal.Spacer())
+	md.AddSection(modal.Text("Tab:switch  Space:check  Esc:cancel"))
+	return md
+}
+
+// handleApproveChecklistKey routes key presses while the approve modal is
+// open. All keys are consumed so nothing leaks to the panels underneath.
+func (m Model) handleApproveChecklistKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.ApproveChecklist == nil || m.ApproveChecklistModal == nil {
+		m.closeApproveChecklist()
+		return m, nil
+	}
+
+	action, cmd := m.ApproveChecklistModal.HandleKey(msg)
+	if strings.HasPrefix(action, checklistItemPrefix) {
+		// Enter on a checkbox: the checkbox already toggled itself
+		action = ""
+	}
+	if action != "" {
+		return m.handleApproveChecklistAction(action)
+	}
+	m.ApproveChecklist.Error = ""
+	return m, cmd
+}
+
+// handleApproveChecklistAction handles actions from the approve modal
+func (m Model) handleApproveChecklistAction(action string) (tea.Model, tea.Cmd) {
+	st := m.ApproveChecklist
+	if st == nil {
+		return m, nil
+	}
+
+	if i, ok := strings.CutPrefix(action, checklistItemPrefix); ok {
+		// Click on a checkbox
+		if n, err := strconv.Atoi(i); err == nil && n >= 0 && n < len(st.Checked) {
+			st.Checked[n] = !st.Checked[n]
+			st.Error = ""
+		}
+		return m, nil
+	}
+
+	switch action {
+	case "cancel":
+		m.closeApproveChecklist()
+		return m, nil
+
+	case "approve":
+		acked := st.acknowledged()
+		if len(acked) < len(st.Items) {
+			st.Error = "Check every item to approve"
+			return m, nil
+		}
+		issue, err := m.DB.GetIssue(st.IssueID)
+		if err != nil {
+			st.Error = err.Error()
+			return m, nil
+		}
+		if err := m.approveReviewedIssue(issue, acked); err != nil {
+			var ce *db.ChecklistError
+			if errors.As(err, &ce) {
+				// The checklist changed while the modal was open
+				return m.openApproveChecklist(issue, ce.Items), m.Toasts.Error("Review checklist changed")
+			}
+			st.Error = err.Error()
+			return m, nil
+		}
+		m.closeApproveChecklist()
+		m.SelectedID[PanelTaskList] = ""
+		return m.issueActionDone(issue.ID, models.StatusClosed, "APPROVED")
+	}
+
+	return m, nil
+}
@@ -0,0 +1,71 @@
+package monitor
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/pkg/monitor/keymap"
+)
+
+func TestApproveChecklistModal(t *testing.T) {
+	baseDir := t.TempDir()
+	database, err := db.Initialize(baseDir)
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+	if err := config.SetReviewChecklist(baseDir, models.ReviewChecklist{Items: []string{"tests added", "docs updated"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	m := newTestModel()
+	m.DB = database
+	m.BaseDir = baseDir
+	issue := createTestIssue(t, database, "Task awaiting review", models.StatusInReview)
+	m.TaskList.Reviewable = []models.Issue{*issue}
+	m.buildTaskListRows()
+	m.SelectedID[PanelTaskList] = issue.ID
+
+	result, _ := m.approveIssue()
+	m = result.(Model)
+	if !m.ApproveChecklistOpen || m.currentContext() != keymap.ContextApproveChecklist {
+		t.Fatal("expected the approve modal to open")
+	}
+	if got, _ := database.GetIssue(issue.ID); got.Status != models.StatusInReview {
+		t.Fatalf("status = %s before acknowledging, want in_review", got.Status)
+	}
+
+	// Approving with an item unchecked is refused in the modal
+	result, _ = m.handleApproveChecklistAction(checklistItemPrefix + "0")
+	m = result.(Model)
+	result, _ = m.handleApproveChecklistAction("approve")
+	m = result.(Model)
+	if !m.ApproveChecklistOpen || m.ApproveChecklist.Error == "" {
+		t.Fatal("expected an error with an item unchecked")
+	}
+
+	// Space toggles the focused checkbox
+	m.ApproveChecklistModal.Render(m.Width, m.Height, m.ApproveChecklistMouseHandler)
+	m.ApproveChecklistModal.SetFocus(checklistItemPrefix + "1")
+	result, _ = m.handleApproveChecklistKey(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	m = result.(Model)
+	if !m.ApproveChecklist.Checked[1] {
+		t.Fatal("space did not check the focused item")
+	}
+
+	result, _ = m.handleApproveChecklistAction("approve")
+	m = result.(Model)
+	if m.ApproveChecklistOpen {
+		t.Errorf("modal still open: %s", m.ApproveChecklist.Error)
+	}
+	if got, _ := database.GetIssue(issue.ID); got.Status != models.StatusClosed {
+		t.Errorf("status = %s, want closed", got.Status)
+	}
+	acks, _ := database.ListReviewAcks(issue.ID)
+	if len(acks) != 1 || len(acks[0].Items) != 2 || acks[0].SessionID != m.SessionID {
+		t.Errorf("review acks = %+v, want both items from this session", acks)
+	}
+}
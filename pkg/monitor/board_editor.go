@@ -308,7 +308,8 @@ func (m *Model) createBoardEditorDeleteConfirmModal() *modal.Modal {
 	}
 
 	md := modal.New("DELETE BOARD?",
-		modal.WithWidth(50),
+		modal.WithAutoSize(),
+		modal.WithMaxWidth(60),
 		modal.WithVariant(modal.VariantDanger),
 		modal.WithHints(false),
 	)
@@ -13,7 +13,6 @@ import (
 	"github.com/marcus/td/internal/db"
 	"github.com/marcus/td/internal/features"
 	"github.com/marcus/td/internal/models"
-	"github.com/marcus/td/internal/query"
 	"github.com/marcus/td/internal/syncclient"
 	"github.com/marcus/td/internal/syncconfig"
 	"github.com/marcus/td/pkg/monitor/keymap"
@@ -22,6 +21,18 @@ import (
 
 // currentContext returns the keymap context based on current UI state
 func (m Model) currentContext() keymap.Context {
+	if m.ActionMenuOpen {
+		return keymap.ContextActionMenu
+	}
+	if m.ApproveChecklistOpen {
+		return keymap.ContextApproveChecklist
+	}
+	if m.TriageInboxOpen {
+		return keymap.ContextTriageInbox
+	}
+	if m.SectionFilterOpen {
+		return keymap.ContextSectionFilter
+	}
 	if m.SyncPromptOpen {
 		return keymap.ContextSyncPrompt
 	}
@@ -49,6 +60,12 @@ func (m Model) currentContext() keymap.Context {
 	if m.HandoffsOpen {
 		return keymap.ContextHandoffs
 	}
+	if m.RemindersOpen {
+		return keymap.ContextReminders
+	}
+	if m.CapacityOpen {
+		return keymap.ContextCapacity
+	}
 	if m.StatsOpen {
 		return keymap.ContextStats
 	}
@@ -303,6 +320,26 @@ func (m Model) handleFormUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	ctx := m.currentContext()
 
+	// Issue action menu: sits above every other overlay and consumes all keys
+	if m.ActionMenuOpen {
+		return m.handleActionMenuKey(msg)
+	}
+
+	// Approve modal with the review checklist
+	if m.ApproveChecklistOpen {
+		return m.handleApproveChecklistKey(msg)
+	}
+
+	// Triage inbox modal: checkboxes, a note input and action buttons
+	if m.TriageInboxOpen {
+		return m.handleTriageInboxKey(msg)
+	}
+
+	// Section filter prompt: text input with live TDQ validation
+	if m.SectionFilterOpen {
+		return m.handleSectionFilterKey(msg)
+	}
+
 	// Sync Prompt modal: let declarative modal handle keys first
 	if m.SyncPromptOpen && m.SyncPromptModal != nil {
 		action, cmd := m.SyncPromptModal.HandleKey(msg)
@@ -421,6 +458,29 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Fall through to keymap for navigation, ctrl+d, G, g g, r (refresh), etc.
 	}
 
+	// Reminders modal: same approach as handoffs, navigation falls through to the keymap
+	if m.RemindersOpen && m.RemindersModal != nil {
+		action, cmd := m.RemindersModal.HandleKey(msg)
+		if action != "" {
+			return m.handleRemindersAction(action)
+		}
+		if cmd != nil {
+			return m, cmd
+		}
+	}
+
+	// Capacity modal: only buttons are actions, navigation falls through to the keymap
+	if m.CapacityOpen && m.CapacityModal != nil {
+		action, cmd := m.CapacityModal.HandleKey(msg)
+		if action == "close" || action == "cancel" {
+			m.closeCapacityModal()
+			return m, nil
+		}
+		if cmd != nil {
+			return m, cmd
+		}
+	}
+
 	// Board editor modal: let declarative modal handle keys first
 	if m.BoardEditorOpen && m.BoardEditorModal != nil {
 		// Delete confirmation sub-modal gets special handling
@@ -631,6 +691,12 @@ func (m Model) executeCommand(cmd keymap.Command) (tea.Model, tea.Cmd) {
 		if m.HandoffsOpen {
 			return m, m.fetchHandoffs()
 		}
+		if m.RemindersOpen {
+			return m, m.fetchReminders()
+		}
+		if m.CapacityOpen {
+			return m, m.fetchCapacity()
+		}
 		if m.StatsOpen {
 			return m, m.fetchStats()
 		}
@@ -724,6 +790,14 @@ func (m Model) executeCommand(cmd keymap.Command) (tea.Model, tea.Cmd) {
 			if m.HandoffsCursor < len(m.HandoffsData)-1 {
 				m.HandoffsCursor++
 			}
+		} else if m.RemindersOpen {
+			if m.RemindersCursor < len(m.RemindersData)-1 {
+				m.RemindersCursor++
+			}
+		} else if m.CapacityOpen {
+			if m.CapacityData != nil && m.CapacityCursor < len(m.CapacityData.Sessions)-1 {
+				m.CapacityCursor++
+			}
 		} else if m.StatsOpen {
 			// Use declarative modal scroll when available
 			if m.StatsModal != nil && !m.StatsLoading && m.StatsError == nil {
@@ -795,6 +869,14 @@ func (m Model) executeCommand(cmd keymap.Command) (tea.Model, tea.Cmd) {
 			if m.HandoffsCursor > 0 {
 				m.HandoffsCursor--
 			}
+		} else if m.RemindersOpen {
+			if m.RemindersCursor > 0 {
+				m.RemindersCursor--
+			}
+		} else if m.CapacityOpen {
+			if m.CapacityCursor > 0 {
+				m.CapacityCursor--
+			}
 		} else if m.StatsOpen {
 			// Use declarative modal scroll when available
 			if m.StatsModal != nil && !m.StatsLoading && m.StatsError == nil {
@@ -829,6 +911,10 @@ func (m Model) executeCommand(cmd keymap.Command) (tea.Model, tea.Cmd) {
 		} else if m.HandoffsOpen {
 			m.HandoffsCursor = 0
 			m.HandoffsScroll = 0
+		} else if m.RemindersOpen {
+			m.RemindersCursor = 0
+		} else if m.CapacityOpen {
+			m.CapacityCursor = 0
 		} else if m.StatsOpen {
 			// Use declarative modal scroll when available
 			if m.StatsModal != nil && !m.StatsLoading && m.StatsError == nil {
@@ -872,6 +958,14 @@ func (m Model) executeCommand(cmd keymap.Command) (tea.Model, tea.Cmd) {
 			if len(m.HandoffsData) > 0 {
 				m.HandoffsCursor = len(m.HandoffsData) - 1
 			}
+		} else if m.RemindersOpen {
+			if len(m.RemindersData) > 0 {
+				m.RemindersCursor = len(m.RemindersData) - 1
+			}
+		} else if m.CapacityOpen {
+			if m.CapacityData != nil && len(m.CapacityData.Sessions) > 0 {
+				m.CapacityCursor = len(m.CapacityData.Sessions) - 1
+			}
 		} else if m.StatsOpen {
 			// Use declarative modal scroll when available
 			if m.StatsModal != nil && !m.StatsLoading && m.StatsError == nil {
@@ -1114,6 +1208,10 @@ func (m Model) executeCommand(cmd keymap.Command) (tea.Model, tea.Cmd) {
 			m.closeActivityDetailModal()
 		} else if m.HandoffsOpen {
 			m.closeHandoffsModal()
+		} else if m.RemindersOpen {
+			m.closeRemindersModal()
+		} else if m.CapacityOpen {
+			m.closeCapacityModal()
 		} else if m.StatsOpen {
 			m.closeStatsModal()
 		} else if m.ShowTDQHelp {
@@ -1129,6 +1227,9 @@ func (m Model) executeCommand(cmd keymap.Command) (tea.Model, tea.Cmd) {
 		if m.HandoffsOpen {
 			return m.openIssueFromHandoffs()
 		}
+		if m.RemindersOpen {
+			return m.openIssueFromReminders()
+		}
 		if m.TaskListMode == TaskListModeBoard && m.ActivePanel == PanelTaskList {
 			return m.openIssueFromBoard()
 		}
@@ -1144,6 +1245,21 @@ func (m Model) executeCommand(cmd keymap.Command) (tea.Model, tea.Cmd) {
 	case keymap.CmdOpenHandoffs:
 		return m.openHandoffsModal()
 
+	case keymap.CmdOpenReminders:
+		return m.openRemindersModal()
+
+	case keymap.CmdCancelReminder:
+		if m.RemindersOpen {
+			return m.cancelSelectedReminder()
+		}
+		return m, nil
+
+	case keymap.CmdOpenCapacity:
+		return m.openCapacityModal()
+
+	case keymap.CmdOpenTriageInbox:
+		return m.openTriageInbox()
+
 	case keymap.CmdSearch:
 		m.SearchMode = true
 		m.SearchQuery = ""
@@ -1413,6 +1529,27 @@ func (m Model) executeCommand(cmd keymap.Command) (tea.Model, tea.Cmd) {
 	case keymap.CmdSendToWorktree:
 		return m.sendToWorktree()
 
+	case keymap.CmdOpenActionMenu:
+		return m.openActionMenu()
+
+	case keymap.CmdFilterSection:
+		return m.openSectionFilter()
+
+	case keymap.CmdTogglePreview:
+		return m.togglePreview()
+
+	case keymap.CmdPreviewGrow:
+		return m.resizePreview(previewRatioStep)
+
+	case keymap.CmdPreviewShrink:
+		return m.resizePreview(-previewRatioStep)
+
+	case keymap.CmdPaneGrow:
+		return m.resizePane(paneRatioStep)
+
+	case keymap.CmdPaneShrink:
+		return m.resizePane(-paneRatioStep)
+
 	// Form commands
 	case keymap.CmdNewIssue:
 		return m.openNewIssueForm()
@@ -2100,31 +2237,9 @@ func (m Model) fetchBoardIssues(boardID string) tea.Cmd {
 			return BoardIssuesMsg{BoardID: boardID, Error: err}
 		}
 
-		var issues []models.BoardIssueView
-		if board.Query != "" {
-			// Execute TDQ query, then apply positions
-			queryResults, err := query.Execute(m.DB, board.Query, m.SessionID, query.ExecuteOptions{})
-			if err != nil {
-				return BoardIssuesMsg{BoardID: boardID, Error: err}
-			}
-			// Filter by status (query.Execute doesn't filter by status)
-			var filtered []models.Issue
-			for _, issue := range queryResults {
-				if statusFilter[issue.Status] {
-					filtered = append(filtered, issue)
-				}
-			}
-			issues, err = m.DB.ApplyBoardPositions(boardID, filtered)
-			if err != nil {
-				return BoardIssuesMsg{BoardID: boardID, Error: err}
-			}
-		} else {
-			// Empty query - use GetBoardIssues with status filter
-			statusSlice := StatusFilterMapToSlice(statusFilter)
-			issues, err = m.DB.GetBoardIssues(boardID, m.SessionID, statusSlice)
-			if err != nil {
-				return BoardIssuesMsg{BoardID: boardID, Error: err}
-			}
+		issues, err := LoadBoardIssues(m.DB, board, m.SessionID, statusFilter)
+		if err != nil {
+			return BoardIssuesMsg{BoardID: boardID, Error: err}
 		}
 
 		// Pre-compute rejected IDs to avoid synchronous DB query in Update handler
@@ -2245,6 +2360,24 @@ func (m Model) handleHandoffsAction(action string) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleRemindersAction handles actions from the reminders modal
+func (m Model) handleRemindersAction(action string) (tea.Model, tea.Cmd) {
+	switch action {
+	case "open":
+		return m.openIssueFromReminders()
+	case "cancel-reminder":
+		return m.cancelSelectedReminder()
+	case "close", "cancel":
+		m.closeRemindersModal()
+		return m, nil
+	default:
+		if strings.HasPrefix(action, "reminder-") {
+			return m.openIssueFromReminders()
+		}
+	}
+	return m, nil
+}
+
 // handleBoardPickerAction handles actions from the board picker modal
 func (m Model) handleBoardPickerAction(action string) (Model, tea.Cmd) {
 	switch action {
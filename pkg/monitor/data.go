@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/marcus/td/internal/aging"
 	"github.com/marcus/td/internal/config"
 	"github.com/marcus/td/internal/db"
 	"github.com/marcus/td/internal/models"
@@ -16,6 +17,7 @@ import (
 // StatsData holds statistics for the stats modal
 type StatsData struct {
 	ExtendedStats *models.ExtendedStats
+	Aging         *aging.Report // nil if it couldn't be computed
 	Error         error
 }
 
@@ -25,6 +27,9 @@ type StatsDataMsg struct {
 	Error error
 }
 
+// activityLimit is how many items the activity panel holds
+const activityLimit = 50
+
 // FetchData retrieves all data needed for the monitor display.
 // This maintains the legacy behavior (search_mode=auto).
 func FetchData(database *db.DB, sessionID string, startedAt time.Time, searchQuery string, includeClosed bool, sortMode SortMode) RefreshDataMsg {
@@ -61,7 +66,7 @@ func FetchDataWithSearchMode(database *db.DB, sessionID string, startedAt time.T
 	msg.InProgress = inProgress
 
 	// Get activity feed
-	msg.Activity = fetchActivity(database, 50)
+	msg.Activity = fetchActivity(database, activityLimit)
 
 	// Get task list (uses current session for reviewable calculation)
 	msg.TaskList = fetchTaskList(database, currentSessionID, searchQuery, searchMode, includeClosed, sortMode)
@@ -72,9 +77,34 @@ func FetchDataWithSearchMode(database *db.DB, sessionID string, startedAt time.T
 	// Get active sessions (activity in last 5 minutes)
 	msg.ActiveSessions = fetchActiveSessions(database)
 
+	// Get heartbeat-based liveness for sessions that are not gone
+	msg.SessionLiveness = fetchSessionLiveness(database, msg.ActiveSessions, msg.Timestamp)
+
+	msg.Cards = fetchCards(database, &msg)
+
 	return msg
 }
 
+// fetchCards loads the issue cards for every issue the refresh lists, in
+// one query
+func fetchCards(database *db.DB, msg *RefreshDataMsg) map[string]models.IssueCard {
+	var ids []string
+	if msg.FocusedIssue != nil {
+		ids = append(ids, msg.FocusedIssue.ID)
+	}
+	t := &msg.TaskList
+	for _, list := range [][]models.Issue{msg.InProgress, t.Reviewable, t.NeedsRework, t.InProgress, t.Ready, t.NeedsTriage, t.PendingReview, t.Blocked, t.Closed} {
+		for _, issue := range list {
+			ids = append(ids, issue.ID)
+		}
+	}
+	cards, err := database.GetIssueCards(ids)
+	if err != nil {
+		return map[string]models.IssueCard{}
+	}
+	return cards
+}
+
 // fetchActivity combines logs, actions, and comments into a unified activity feed
 func fetchActivity(database *db.DB, limit int) []ActivityItem {
 	// Pre-allocate for logs + actions + comments (3x limit max)
@@ -169,6 +199,11 @@ func isTDQQuery(q string) bool {
 		"@me", "EMPTY",
 		"sort:", // Sort prefix is considered TDQ
 	}
+	for _, fn := range query.Functions() {
+		if !fn.Builtin {
+			tdqPatterns = append(tdqPatterns, fn.Name+"(")
+		}
+	}
 	upper := strings.ToUpper(q)
 	for _, pattern := range tdqPatterns {
 		if strings.Contains(upper, strings.ToUpper(pattern)) {
@@ -201,13 +236,11 @@ func fetchTaskList(database *db.DB, sessionID string, searchQuery, searchMode st
 		return issues
 	}
 
-	// Batch load all dependencies and their statuses upfront
-	allDeps, _ := database.GetAllDependencies()
-	var allDepIDs []string
-	for _, deps := range allDeps {
-		allDepIDs = append(allDepIDs, deps...)
+	// Issues blocked by unclosed dependencies, from the issue cards
+	blockedIDs, err := database.GetDependencyBlockedIDs()
+	if err != nil {
+		blockedIDs = make(map[string]bool)
 	}
-	depStatuses, _ := database.GetIssueStatuses(allDepIDs)
 
 	// Get rejected open/in_progress issue IDs for "needs rework" detection
 	rejectedIDs, err := database.GetRejectedInProgressIssueIDs()
@@ -215,17 +248,6 @@ func fetchTaskList(database *db.DB, sessionID string, searchQuery, searchMode st
 		rejectedIDs = make(map[string]bool) // Safe fallback on error
 	}
 
-	// Helper to check if issue is blocked by unclosed dependencies
-	isBlockedByDeps := func(issueID string) bool {
-		deps := allDeps[issueID]
-		for _, depID := range deps {
-			if status, ok := depStatuses[depID]; ok && status != models.StatusClosed {
-				return true
-			}
-		}
-		return false
-	}
-
 	// Resolve search mode semantics:
 	// - tdq: always attempt TDQ execution (when query is non-empty)
 	// - text: never attempt TDQ execution
@@ -254,7 +276,7 @@ func fetchTaskList(database *db.DB, sessionID string, searchQuery, searchMode st
 			for _, issue := range allIssues {
 				switch issue.Status {
 				case models.StatusOpen:
-					if isBlockedByDeps(issue.ID) {
+					if blockedIDs[issue.ID] {
 						data.Blocked = append(data.Blocked, issue)
 					} else {
 						data.Ready = append(data.Ready, issue)
@@ -279,12 +301,13 @@ func fetchTaskList(database *db.DB, sessionID string, searchQuery, searchMode st
 					}
 				}
 			}
-			return data
+			data.Ready, data.NeedsTriage = database.SplitReady(data.Ready)
+			return groupStacks(database, data)
 		}
 	}
 
 	// Standard search (simple text or when TDQ fails)
-	// Ready issues: open status, not blocked, sorted by priority
+	// Ready issues: open status, not blocked, sorted by rank then priority
 	var openIssues []models.Issue
 	if searchQuery != "" && !useTDQ {
 		results, _ := database.SearchIssuesRanked(searchQuery, db.ListIssuesOptions{
@@ -292,22 +315,32 @@ func fetchTaskList(database *db.DB, sessionID string, searchQuery, searchMode st
 		})
 		openIssues = extractIssues(results)
 	} else if searchQuery == "" {
+		// By priority, the project-wide rank (td rank) goes first
+		readySort := sortBy
+		if sortMode == SortByPriority {
+			readySort = "rank"
+		}
 		openIssues, _ = database.ListIssues(db.ListIssuesOptions{
 			Status:   []models.Status{models.StatusOpen},
-			SortBy:   sortBy,
+			SortBy:   readySort,
 			SortDesc: sortDesc,
 		})
+		if readySort == "rank" {
+			inherited, _ := database.InheritedPriorities()
+			db.SortByEffectivePriority(openIssues, inherited)
+		}
 	}
 
 	// Separate open issues into ready vs blocked-by-dependency
 	var blockedByDep []models.Issue
 	for _, issue := range openIssues {
-		if isBlockedByDeps(issue.ID) {
+		if blockedIDs[issue.ID] {
 			blockedByDep = append(blockedByDep, issue)
 		} else {
 			data.Ready = append(data.Ready, issue)
 		}
 	}
+	data.Ready, data.NeedsTriage = database.SplitReady(data.Ready)
 
 	// In-progress issues: categorize as InProgress or NeedsRework
 	var inProgressIssues []models.Issue
@@ -398,7 +431,7 @@ func fetchTaskList(database *db.DB, sessionID string, searchQuery, searchMode st
 		}
 	}
 
-	return data
+	return groupStacks(database, data)
 }
 
 // fetchActiveSessions retrieves sessions with activity in the last 5 minutes
@@ -411,6 +444,20 @@ func fetchActiveSessions(database *db.DB) []string {
 	return sessions
 }
 
+// fetchSessionLiveness classifies sessions as active or idle from their
+// heartbeats. Sessions that logged recently are always reported as active,
+// even if they have no heartbeat row.
+func fetchSessionLiveness(database *db.DB, activeSessions []string, now time.Time) map[string]session.Liveness {
+	liveness, err := session.LivenessBySession(database, now)
+	if err != nil {
+		liveness = make(map[string]session.Liveness)
+	}
+	for _, id := range activeSessions {
+		liveness[id] = session.LivenessActive
+	}
+	return liveness
+}
+
 // fetchRecentHandoffs retrieves handoffs since the given time
 func fetchRecentHandoffs(database *db.DB, since time.Time) []RecentHandoff {
 	var result []RecentHandoff
@@ -480,8 +527,10 @@ func FetchStats(database *db.DB) StatsDataMsg {
 			Error: err,
 		}
 	}
+	// Aging is secondary: the stats still show without it
+	agingReport, _ := aging.Compute(database, aging.Options{}, time.Now())
 	return StatsDataMsg{
-		Data: &StatsData{ExtendedStats: stats},
+		Data: &StatsData{ExtendedStats: stats, Aging: agingReport},
 	}
 }
 
@@ -504,23 +553,22 @@ func ComputeBoardIssueCategories(database *db.DB, issues []models.BoardIssueView
 		}
 	}
 
-	// Batch load all dependencies and their statuses
-	allDeps, _ := database.GetAllDependencies()
-	var allDepIDs []string
-	for _, deps := range allDeps {
-		allDepIDs = append(allDepIDs, deps...)
+	// Issues blocked by unclosed dependencies, from the issue cards
+	blockedIDs, err := database.GetDependencyBlockedIDs()
+	if err != nil {
+		blockedIDs = make(map[string]bool)
 	}
-	depStatuses, _ := database.GetIssueStatuses(allDepIDs)
 
-	// Helper to check if issue is blocked by unclosed dependencies
-	isBlockedByDeps := func(issueID string) bool {
-		deps := allDeps[issueID]
-		for _, depID := range deps {
-			if status, ok := depStatuses[depID]; ok && status != models.StatusClosed {
-				return true
-			}
+	// Open, unblocked issues short of the definition of ready need triage
+	var candidates []models.Issue
+	for _, biv := range issues {
+		if biv.Issue.Status == models.StatusOpen && !blockedIDs[biv.Issue.ID] {
+			candidates = append(candidates, biv.Issue)
 		}
-		return false
+	}
+	triageGaps, err := database.ReadinessGaps(candidates)
+	if err != nil {
+		triageGaps = make(map[string][]string)
 	}
 
 	// Set category on each issue
@@ -530,8 +578,10 @@ func ComputeBoardIssueCategories(database *db.DB, issues []models.BoardIssueView
 
 		switch issue.Status {
 		case models.StatusOpen:
-			if isBlockedByDeps(issue.ID) {
+			if blockedIDs[issue.ID] {
 				category = CategoryBlocked
+			} else if _, ok := triageGaps[issue.ID]; ok {
+				category = CategoryNeedsTriage
 			} else {
 				category = CategoryReady
 			}
@@ -559,6 +609,29 @@ func ComputeBoardIssueCategories(database *db.DB, issues []models.BoardIssueView
 	}
 }
 
+// LoadBoardIssues returns the issues on a board that pass statusFilter, in
+// board order. Query boards run their TDQ query; empty-query boards list all
+// issues with explicit positions first.
+func LoadBoardIssues(database *db.DB, board *models.Board, sessionID string, statusFilter map[models.Status]bool) ([]models.BoardIssueView, error) {
+	if board.Query == "" {
+		return database.GetBoardIssues(board.ID, sessionID, StatusFilterMapToSlice(statusFilter))
+	}
+
+	// Execute TDQ query, then apply positions
+	queryResults, err := query.Execute(database, board.Query, sessionID, query.ExecuteOptions{})
+	if err != nil {
+		return nil, err
+	}
+	// Filter by status (query.Execute doesn't filter by status)
+	var filtered []models.Issue
+	for _, issue := range queryResults {
+		if statusFilter[issue.Status] {
+			filtered = append(filtered, issue)
+		}
+	}
+	return database.ApplyBoardPositions(board.ID, filtered)
+}
+
 // CategorizeBoardIssues takes board issues and groups them by status category
 // for the swimlanes view. Issues are sorted within each category respecting
 // backlog positions: positioned issues first (by position), then unpositioned
@@ -580,6 +653,7 @@ func CategorizeBoardIssues(database *db.DB, issues []models.BoardIssueView, sess
 		CategoryNeedsRework:   {},
 		CategoryInProgress:    {},
 		CategoryReady:         {},
+		CategoryNeedsTriage:   {},
 		CategoryPendingReview: {},
 		CategoryBlocked:       {},
 		CategoryClosed:        {},
@@ -608,6 +682,9 @@ func CategorizeBoardIssues(database *db.DB, issues []models.BoardIssueView, sess
 	for _, biv := range categories[CategoryReady] {
 		data.Ready = append(data.Ready, biv.Issue)
 	}
+	for _, biv := range categories[CategoryNeedsTriage] {
+		data.NeedsTriage = append(data.NeedsTriage, biv.Issue)
+	}
 	for _, biv := range categories[CategoryPendingReview] {
 		data.PendingReview = append(data.PendingReview, biv.Issue)
 	}
@@ -729,6 +806,11 @@ func BuildSwimlaneRows(data TaskListData) []TaskListRow {
 		rows = append(rows, TaskListRow{Issue: issue, Category: CategoryReady})
 	}
 
+	// Add needs triage issues
+	for _, issue := range data.NeedsTriage {
+		rows = append(rows, TaskListRow{Issue: issue, Category: CategoryNeedsTriage})
+	}
+
 	// Add pending review issues
 	for _, issue := range data.PendingReview {
 		rows = append(rows, TaskListRow{Issue: issue, Category: CategoryPendingReview})
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/marcus/td/internal/config"
 	"github.com/marcus/td/internal/db"
 	"github.com/marcus/td/internal/models"
 )
@@ -327,3 +328,35 @@ func TestComputeBoardIssueCategoriesClosedDepUnblocks(t *testing.T) {
 		t.Errorf("dependent with closed blocker: got %q, want %q", issues[0].Category, CategoryReady)
 	}
 }
+
+func TestReadyGateSplitsNeedsTriage(t *testing.T) {
+	baseDir := t.TempDir()
+	database, err := db.Initialize(baseDir)
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer database.Close()
+
+	if err := config.SetReadyGate(baseDir, []string{"points"}); err != nil {
+		t.Fatalf("SetReadyGate: %v", err)
+	}
+	bare := createTestIssue(t, database, "Unestimated issue", models.StatusOpen)
+	estimated := &models.Issue{Title: "Estimated issue", Type: models.TypeTask, Points: 3}
+	if err := database.CreateIssue(estimated); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+
+	data := fetchTaskList(database, "test-session", "", "", false, SortByPriority)
+	if len(data.Ready) != 1 || data.Ready[0].ID != estimated.ID {
+		t.Errorf("Ready = %v, want only %s", data.Ready, estimated.ID)
+	}
+	if len(data.NeedsTriage) != 1 || data.NeedsTriage[0].ID != bare.ID {
+		t.Errorf("NeedsTriage = %v, want only %s", data.NeedsTriage, bare.ID)
+	}
+
+	issues := []models.BoardIssueView{{Issue: *bare}, {Issue: *estimated}}
+	ComputeBoardIssueCategories(database, issues, "test-session", nil)
+	if issues[0].Category != string(CategoryNeedsTriage) || issues[1].Category != string(CategoryReady) {
+		t.Errorf("board categories = %q, %q; want %q, %q", issues[0].Category, issues[1].Category, CategoryNeedsTriage, CategoryReady)
+	}
+}
@@ -0,0 +1,319 @@
+package monitor
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/session"
+)
+
+// The periodic poll doesn't hand the model a whole new RefreshDataMsg. A
+// dataFeed keeps the snapshot the model last saw and turns each poll into
+// typed messages for what changed, so panels whose data is unchanged keep
+// their rows, cursors and scroll positions, and an idle board isn't redrawn
+// at all. A change in which issues are listed, or in what order, still
+// arrives as a full RefreshDataMsg.
+
+// IssueChangedMsg carries a new version of an issue shown in the current
+// work or task list panels. It only replaces issues already listed; it
+// never adds, drops or moves rows.
+type IssueChangedMsg struct {
+	Issue models.Issue
+}
+
+// FocusChangedMsg carries the focused issue after it changed, nil when
+// focus was cleared
+type FocusChangedMsg struct {
+	Issue *models.Issue
+}
+
+// ActivityAppendedMsg carries activity newer than anything in the activity
+// panel, newest first
+type ActivityAppendedMsg struct {
+	Items []ActivityItem
+}
+
+// SessionsChangedMsg carries the session details that changed since the
+// last poll
+type SessionsChangedMsg struct {
+	ActiveSessions  []string
+	SessionLiveness map[string]session.Liveness
+	RecentHandoffs  []RecentHandoff
+}
+
+// PollResultMsg is the outcome of a periodic poll: the fresh snapshot, for
+// notifications and reminders, and the messages that bring the model up to
+// date with it. Changes is empty when nothing changed.
+type PollResultMsg struct {
+	Data    RefreshDataMsg
+	Changes []tea.Msg
+}
+
+// dataFeed remembers the last snapshot delivered to the model. It is shared
+// by pointer across Model copies and used from command goroutines.
+type dataFeed struct {
+	mu   sync.Mutex
+	last *RefreshDataMsg
+}
+
+func newDataFeed() *dataFeed {
+	return &dataFeed{}
+}
+
+// reset records a snapshot delivered whole, as a RefreshDataMsg
+func (f *dataFeed) reset(data RefreshDataMsg) {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.last = &data
+}
+
+// publish records next and returns the messages that turn the previous
+// snapshot into it
+func (f *dataFeed) publish(next RefreshDataMsg) []tea.Msg {
+	if f == nil {
+		return []tea.Msg{next}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	prev := f.last
+	f.last = &next
+	if prev == nil {
+		return []tea.Msg{next}
+	}
+	return diffRefresh(*prev, next)
+}
+
+// diffRefresh returns the typed messages for what changed between two
+// snapshots, or the whole of next when the listed issues were added,
+// removed or reordered
+func diffRefresh(prev, next RefreshDataMsg) []tea.Msg {
+	if !sameRows(prev, next) || !reflect.DeepEqual(prev.TaskList.StackedOn, next.TaskList.StackedOn) {
+		return []tea.Msg{next}
+	}
+	appended, ok := appendedActivity(prev.Activity, next.Activity)
+	if !ok {
+		return []tea.Msg{next}
+	}
+
+	var changes []tea.Msg
+	if !reflect.DeepEqual(prev.FocusedIssue, next.FocusedIssue) {
+		changes = append(changes, FocusChangedMsg{Issue: next.FocusedIssue})
+	}
+	before := make(map[string]models.Issue)
+	for _, issue := range allRefreshIssues(prev) {
+		before[issue.ID] = issue
+	}
+	sent := make(map[string]bool)
+	for _, issue := range allRefreshIssues(next) {
+		if sent[issue.ID] || reflect.DeepEqual(before[issue.ID], issue) {
+			continue
+		}
+		sent[issue.ID] = true
+		changes = append(changes, IssueChangedMsg{Issue: issue})
+	}
+	if len(appended) > 0 {
+		changes = append(changes, ActivityAppendedMsg{Items: appended})
+	}
+	if !reflect.DeepEqual(prev.ActiveSessions, next.ActiveSessions) ||
+		!reflect.DeepEqual(prev.SessionLiveness, next.SessionLiveness) ||
+		!reflect.DeepEqual(prev.RecentHandoffs, next.RecentHandoffs) {
+		changes = append(changes, SessionsChangedMsg{
+			ActiveSessions:  next.ActiveSessions,
+			SessionLiveness: next.SessionLiveness,
+			RecentHandoffs:  next.RecentHandoffs,
+		})
+	}
+	return changes
+}
+
+// sameRows reports whether both snapshots list the same issues in the same
+// places
+func sameRows(prev, next RefreshDataMsg) bool {
+	a, b := prev.TaskList, next.TaskList
+	pairs := [][2][]models.Issue{
+		{prev.InProgress, next.InProgress},
+		{a.Reviewable, b.Reviewable}, {a.NeedsRework, b.NeedsRework}, {a.InProgress, b.InProgress},
+		{a.Ready, b.Ready}, {a.NeedsTriage, b.NeedsTriage}, {a.PendingReview, b.PendingReview},
+		{a.Blocked, b.Blocked}, {a.Closed, b.Closed},
+	}
+	for _, p := range pairs {
+		if len(p[0]) != len(p[1]) {
+			return false
+		}
+		for i := range p[0] {
+			if p[0][i].ID != p[1][i].ID {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// appendedActivity returns the items at the head of next that are newer
+// than prev. ok is false when next is not prev with items added in front,
+// as when an undo removed something.
+func appendedActivity(prev, next []ActivityItem) (added []ActivityItem, ok bool) {
+	if len(prev) == 0 {
+		return next, true
+	}
+	n := 0
+	for n < len(next) && !reflect.DeepEqual(next[n], prev[0]) {
+		n++
+	}
+	if n == len(next) {
+		return nil, false
+	}
+	// Older items may only fall off the end to make room for newer ones
+	if len(next)-n < len(prev) && len(next) < activityLimit {
+		return nil, false
+	}
+	for i := n; i < len(next); i++ {
+		if i-n >= len(prev) || !reflect.DeepEqual(next[i], prev[i-n]) {
+			return nil, false
+		}
+	}
+	return next[:n], true
+}
+
+// applyRefreshData replaces the panel data with a whole snapshot and
+// rebuilds the rows, keeping each panel's selection by issue ID
+func (m *Model) applyRefreshData(msg RefreshDataMsg) {
+	m.FocusedIssue = msg.FocusedIssue
+	m.InProgress = msg.InProgress
+	m.Activity = msg.Activity
+	m.TaskList = m.applySectionFilters(config.SectionFilterScopeAll, msg.TaskList)
+	m.RecentHandoffs = msg.RecentHandoffs
+	m.ActiveSessions = msg.ActiveSessions
+	m.SessionLiveness = msg.SessionLiveness
+	m.LastRefresh = msg.Timestamp
+
+	// Build flattened rows for selection
+	m.buildCurrentWorkRows()
+	m.buildTaskListRows()
+
+	// Restore cursor positions from saved issue IDs
+	m.restoreCursors()
+}
+
+// refreshSideEffects returns the notifications for a fresh snapshot and
+// shows any reminders it fired
+func (m *Model) refreshSideEffects(msg RefreshDataMsg) []tea.Cmd {
+	cmds := []tea.Cmd{m.checkNotifications(msg)}
+	if n := len(msg.FiredReminders); n > 0 {
+		r := msg.FiredReminders[0]
+		m.StatusMessage = fmt.Sprintf("REMINDER %s: %s", r.IssueID, r.Message)
+		if n > 1 {
+			m.StatusMessage += fmt.Sprintf(" (+%d more, m to view)", n-1)
+		}
+		m.StatusIsError = false
+		cmds = append(cmds, tea.Tick(10*time.Second, func(t time.Time) tea.Msg { return ClearStatusMsg{} }))
+		if m.RemindersOpen {
+			cmds = append(cmds, m.fetchReminders())
+		}
+	}
+	return cmds
+}
+
+// applyChanges brings the model up to date with a poll. With section
+// filters set, a changed issue can enter or leave a filtered section, so
+// the whole snapshot is applied instead.
+func (m *Model) applyChanges(msg PollResultMsg) {
+	if len(m.sectionFiltersFor(config.SectionFilterScopeAll)) > 0 {
+		for _, c := range msg.Changes {
+			if _, ok := c.(IssueChangedMsg); ok {
+				m.applyRefreshData(msg.Data)
+				return
+			}
+		}
+	}
+	for _, c := range msg.Changes {
+		switch c := c.(type) {
+		case RefreshDataMsg:
+			m.applyRefreshData(c)
+		case IssueChangedMsg:
+			m.applyIssueChanged(c.Issue)
+		case FocusChangedMsg:
+			m.applyFocusChanged(c.Issue)
+		case ActivityAppendedMsg:
+			m.applyActivityAppended(c.Items)
+		case SessionsChangedMsg:
+			m.applySessionsChanged(c)
+		}
+	}
+}
+
+// applyIssueChanged swaps in a new version of an issue wherever it is
+// listed, leaving rows and cursors alone
+func (m *Model) applyIssueChanged(issue models.Issue) {
+	if m.FocusedIssue != nil && m.FocusedIssue.ID == issue.ID {
+		focused := issue
+		m.FocusedIssue = &focused
+	}
+	m.InProgress = replaceIssue(m.InProgress, issue)
+	t := &m.TaskList
+	for _, list := range []*[]models.Issue{&t.Reviewable, &t.NeedsRework, &t.InProgress, &t.Ready, &t.NeedsTriage, &t.PendingReview, &t.Blocked, &t.Closed} {
+		*list = replaceIssue(*list, issue)
+	}
+	for i := range m.TaskListRows {
+		if m.TaskListRows[i].Issue.ID == issue.ID {
+			m.TaskListRows[i].Issue = issue
+		}
+	}
+}
+
+// replaceIssue returns list with issue in place of the entry with its ID.
+// The list is copied first, since its array may be shared with the feed's
+// snapshot.
+func replaceIssue(list []models.Issue, issue models.Issue) []models.Issue {
+	for i := range list {
+		if list[i].ID == issue.ID {
+			list = append([]models.Issue(nil), list...)
+			list[i] = issue
+			return list
+		}
+	}
+	return list
+}
+
+// applyFocusChanged shows a new focused issue at the top of the current
+// work panel
+func (m *Model) applyFocusChanged(issue *models.Issue) {
+	m.FocusedIssue = issue
+	m.buildCurrentWorkRows()
+	m.restoreCursors()
+}
+
+// applyActivityAppended adds newer items to the top of the activity
+// panel. When the user has moved down the panel, the cursor and scroll
+// move with their item rather than jumping as items arrive.
+func (m *Model) applyActivityAppended(items []ActivityItem) {
+	activity := append(append([]ActivityItem(nil), items...), m.Activity...)
+	if len(activity) > activityLimit {
+		activity = activity[:activityLimit]
+	}
+	m.Activity = activity
+	if m.Cursor[PanelActivity] > 0 || m.ScrollOffset[PanelActivity] > 0 {
+		m.Cursor[PanelActivity] += len(items)
+		m.ScrollOffset[PanelActivity] += len(items)
+	}
+	m.clampCursor(PanelActivity)
+	if !m.ScrollIndependent[PanelActivity] {
+		m.ensureCursorVisible(PanelActivity)
+	}
+}
+
+// applySessionsChanged updates the session details shown in the header
+// and current work panel
+func (m *Model) applySessionsChanged(msg SessionsChangedMsg) {
+	m.ActiveSessions = msg.ActiveSessions
+	m.SessionLiveness = msg.SessionLiveness
+	m.RecentHandoffs = msg.RecentHandoffs
+}
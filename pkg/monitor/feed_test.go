@@ -0,0 +1,120 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestDiffRefresh(t *testing.T) {
+	a := plainTestIssue("td-a", "Fix login timeout")
+	b := plainTestIssue("td-b", "Add export command")
+	t0 := time.Date(2026, 1, 2, 9, 30, 0, 0, time.UTC)
+	old := ActivityItem{Timestamp: t0, Type: "log", EntityID: "l1", IssueID: "td-a", Message: "started"}
+	prev := RefreshDataMsg{
+		Timestamp:  t0,
+		InProgress: []models.Issue{a},
+		TaskList:   TaskListData{InProgress: []models.Issue{a}, Ready: []models.Issue{b}},
+		Activity:   []ActivityItem{old},
+	}
+
+	same := prev
+	same.Timestamp = t0.Add(time.Second)
+	if changes := diffRefresh(prev, same); len(changes) != 0 {
+		t.Errorf("unchanged poll = %v", changes)
+	}
+
+	edited := prev
+	a2 := a
+	a2.Title = "Fix login timeout on slow links"
+	edited.InProgress = []models.Issue{a2}
+	edited.TaskList = TaskListData{InProgress: []models.Issue{a2}, Ready: []models.Issue{b}}
+	edited.FocusedIssue = &a2
+	added := ActivityItem{Timestamp: t0.Add(time.Minute), Type: "action", EntityID: "a1", IssueID: "td-a", Message: "edited"}
+	edited.Activity = []ActivityItem{added, old}
+	changes := diffRefresh(prev, edited)
+	if len(changes) != 3 {
+		t.Fatalf("changes = %#v", changes)
+	}
+	if f, ok := changes[0].(FocusChangedMsg); !ok || f.Issue.ID != "td-a" {
+		t.Errorf("changes[0] = %#v, want FocusChangedMsg", changes[0])
+	}
+	if c, ok := changes[1].(IssueChangedMsg); !ok || c.Issue.Title != a2.Title {
+		t.Errorf("changes[1] = %#v, want one IssueChangedMsg for td-a", changes[1])
+	}
+	if c, ok := changes[2].(ActivityAppendedMsg); !ok || len(c.Items) != 1 || c.Items[0].EntityID != "a1" {
+		t.Errorf("changes[2] = %#v, want ActivityAppendedMsg", changes[2])
+	}
+
+	// Rows added, moved or removed need the whole snapshot
+	moved := prev
+	moved.TaskList = TaskListData{InProgress: []models.Issue{a}, Blocked: []models.Issue{b}}
+	undone := prev
+	undone.Activity = nil
+	for name, next := range map[string]RefreshDataMsg{"moved": moved, "activity removed": undone} {
+		changes := diffRefresh(prev, next)
+		if len(changes) != 1 {
+			t.Errorf("%s: changes = %#v", name, changes)
+			continue
+		}
+		if _, ok := changes[0].(RefreshDataMsg); !ok {
+			t.Errorf("%s: got %T, want RefreshDataMsg", name, changes[0])
+		}
+	}
+}
+
+func TestPollResultUpdatesInPlace(t *testing.T) {
+	a := plainTestIssue("td-a", "Fix login timeout")
+	b := plainTestIssue("td-b", "Add export command")
+	t0 := time.Date(2026, 1, 2, 9, 30, 0, 0, time.UTC)
+	first := RefreshDataMsg{
+		Timestamp: t0,
+		TaskList:  TaskListData{Ready: []models.Issue{a, b}},
+		Activity: []ActivityItem{
+			{Timestamp: t0, Type: "log", EntityID: "l2", Message: "second"},
+			{Timestamp: t0.Add(-time.Minute), Type: "log", EntityID: "l1", Message: "first"},
+		},
+	}
+
+	m := newTestModel()
+	m.feed = newDataFeed()
+	m.feed.reset(first)
+	updated, _ := m.Update(first)
+	m = updated.(Model)
+	m.Cursor[PanelTaskList] = 1
+	m.SelectedID[PanelTaskList] = "td-b"
+	m.Cursor[PanelActivity] = 1
+
+	next := first
+	b2 := b
+	b2.Priority = models.PriorityP0
+	next.TaskList = TaskListData{Ready: []models.Issue{a, b2}}
+	next.Activity = append([]ActivityItem{{Timestamp: t0.Add(time.Minute), Type: "log", EntityID: "l3", Message: "third"}}, first.Activity...)
+	next.Timestamp = t0.Add(2 * time.Second)
+	poll := PollResultMsg{Data: next, Changes: m.feed.publish(next)}
+
+	rows := m.TaskListRows
+	updated, _ = m.Update(poll)
+	m = updated.(Model)
+	if &m.TaskListRows[0] != &rows[0] {
+		t.Error("task list rows were rebuilt for a field change")
+	}
+	if m.TaskListRows[1].Issue.Priority != models.PriorityP0 || m.TaskList.Ready[1].Priority != models.PriorityP0 {
+		t.Errorf("td-b not updated: %+v", m.TaskListRows[1].Issue)
+	}
+	if first.TaskList.Ready[1].Priority != models.PriorityP1 {
+		t.Error("update wrote through to the earlier snapshot")
+	}
+	if m.Cursor[PanelTaskList] != 1 || len(m.Activity) != 3 || m.Cursor[PanelActivity] != 2 {
+		t.Errorf("cursor = %d, activity = %d items with cursor %d", m.Cursor[PanelTaskList], len(m.Activity), m.Cursor[PanelActivity])
+	}
+	if !m.LastRefresh.Equal(next.Timestamp) {
+		t.Errorf("LastRefresh = %v", m.LastRefresh)
+	}
+
+	// Nothing changed: nothing to apply
+	if changes := m.feed.publish(next); len(changes) != 0 {
+		t.Errorf("repeat poll changes = %v", changes)
+	}
+}
@@ -5,11 +5,10 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/huh"
+	"github.com/marcus/td/internal/i18n"
 	"github.com/marcus/td/internal/models"
 )
 
-var errTitleRequired = errors.New("title is required")
-
 // FormMode represents the mode of the form
 type FormMode string
 
@@ -167,7 +166,7 @@ func (fs *FormState) buildForm() {
 			Placeholder("Issue title...").
 			Validate(func(s string) error {
 				if strings.TrimSpace(s) == "" {
-					return errTitleRequired
+					return errors.New(i18n.T("title is required"))
 				}
 				return nil
 			}),
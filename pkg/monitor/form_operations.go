@@ -60,6 +60,16 @@ func (m Model) openEditIssueForm() (tea.Model, tea.Cmd) {
 		}
 	}
 
+	// A confidential issue is edited decrypted; without access its text
+	// would be saved as the redaction marker, so don't open the form
+	copied := *issue
+	issue = &copied
+	if ok, _ := m.DB.RevealIssue(issue, nil, m.DB.ConfidentialViewer(m.SessionID)); !ok {
+		m.StatusMessage = "No access to confidential issue " + issue.ID
+		m.StatusIsError = true
+		return m, nil
+	}
+
 	// Create form state with issue data
 	m.FormState = NewFormStateForEdit(issue)
 	m.FormOpen = true
@@ -162,6 +172,11 @@ func (m Model) submitForm() (tea.Model, tea.Cmd) {
 		existingIssue.Acceptance = issue.Acceptance
 		existingIssue.Minor = issue.Minor
 
+		// A closed issue moving back is a rework cycle; note whether it
+		// had been approved before its review metadata is cleared
+		reworked := statusChanged && oldStatus == models.StatusClosed
+		approved := existingIssue.ReviewerSession != ""
+
 		// Apply status change with associated field updates
 		if statusChanged {
 			existingIssue.Status = newStatus
@@ -185,6 +200,11 @@ func (m Model) submitForm() (tea.Model, tea.Cmd) {
 			m.Err = err
 			return m, nil
 		}
+		if reworked {
+			_ = m.DB.RecordRework(&models.Rework{
+				IssueID: existingIssue.ID, Category: models.ReworkOther, Approved: approved, SessionID: m.SessionID,
+			})
+		}
 
 		// Sync dependencies: diff old vs new, add/remove as needed
 		newDeps := m.FormState.GetDependencies()
@@ -448,7 +448,7 @@ func (m *Model) buildCurrentWorkRows() {
 // buildTaskListRows builds the flattened list of task list rows with category metadata
 func (m *Model) buildTaskListRows() {
 	m.TaskListRows = nil
-	// Order: Reviewable, NeedsRework, InProgress, Ready, PendingReview, Blocked, Closed
+	// Order: Reviewable, NeedsRework, InProgress, Ready, NeedsTriage, PendingReview, Blocked, Closed
 	for _, issue := range m.TaskList.Reviewable {
 		m.TaskListRows = append(m.TaskListRows, TaskListRow{Issue: issue, Category: CategoryReviewable})
 	}
@@ -461,6 +461,9 @@ func (m *Model) buildTaskListRows() {
 	for _, issue := range m.TaskList.Ready {
 		m.TaskListRows = append(m.TaskListRows, TaskListRow{Issue: issue, Category: CategoryReady})
 	}
+	for _, issue := range m.TaskList.NeedsTriage {
+		m.TaskListRows = append(m.TaskListRows, TaskListRow{Issue: issue, Category: CategoryNeedsTriage})
+	}
 	for _, issue := range m.TaskList.PendingReview {
 		m.TaskListRows = append(m.TaskListRows, TaskListRow{Issue: issue, Category: CategoryPendingReview})
 	}
@@ -813,7 +816,8 @@ func (m *Model) updatePanelBounds() {
 	// 3px hit region centered on the border
 	m.DividerBounds[0] = Rect{X: 0, Y: y - 1, W: m.Width, H: 3}
 
-	m.PanelBounds[PanelTaskList] = Rect{X: 0, Y: y, W: m.Width, H: panelHeights[1]}
+	listWidth, _, _ := m.previewWidths()
+	m.PanelBounds[PanelTaskList] = Rect{X: 0, Y: y, W: listWidth, H: panelHeights[1]}
 	y += panelHeights[1]
 
 	// Second divider (between Task List and Activity)
@@ -1001,6 +1005,35 @@ func (m Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	// Handle Reminders modal mouse events (declarative modal)
+	if m.RemindersOpen && m.RemindersModal != nil && m.RemindersMouseHandler != nil {
+		if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
+			action := m.RemindersModal.HandleMouse(msg, m.RemindersMouseHandler)
+			if action != "" {
+				return m.handleRemindersAction(action)
+			}
+			return m, nil
+		}
+		if msg.Action == tea.MouseActionMotion {
+			_ = m.RemindersModal.HandleMouse(msg, m.RemindersMouseHandler)
+			return m, nil
+		}
+	}
+
+	// Handle Capacity modal mouse events (declarative modal)
+	if m.CapacityOpen && m.CapacityModal != nil && m.CapacityMouseHandler != nil {
+		if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
+			if action := m.CapacityModal.HandleMouse(msg, m.CapacityMouseHandler); action == "close" {
+				m.closeCapacityModal()
+			}
+			return m, nil
+		}
+		if msg.Action == tea.MouseActionMotion {
+			_ = m.CapacityModal.HandleMouse(msg, m.CapacityMouseHandler)
+			return m, nil
+		}
+	}
+
 	// Handle left-click in modal for section selection
 	if m.ModalOpen() && msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
 		return m.handleModalClick(msg.X, msg.Y)
@@ -1022,6 +1055,58 @@ func (m Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	// Handle issue action menu mouse events (declarative modal)
+	if m.ActionMenuOpen && m.ActionMenuModal != nil && m.ActionMenuMouseHandler != nil {
+		if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
+			action := m.ActionMenuModal.HandleMouse(msg, m.ActionMenuMouseHandler)
+			if action != "" {
+				return m.handleActionMenuAction(action)
+			}
+			return m, nil
+		}
+		_ = m.ActionMenuModal.HandleMouse(msg, m.ActionMenuMouseHandler)
+		return m, nil
+	}
+
+	// Handle approve modal mouse events (declarative modal)
+	if m.ApproveChecklistOpen && m.ApproveChecklistModal != nil && m.ApproveChecklistMouseHandler != nil {
+		if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
+			action := m.ApproveChecklistModal.HandleMouse(msg, m.ApproveChecklistMouseHandler)
+			if action != "" {
+				return m.handleApproveChecklistAction(action)
+			}
+			return m, nil
+		}
+		_ = m.ApproveChecklistModal.HandleMouse(msg, m.ApproveChecklistMouseHandler)
+		return m, nil
+	}
+
+	// Handle triage inbox modal mouse events (declarative modal)
+	if m.TriageInboxOpen && m.TriageInboxModal != nil && m.TriageInboxMouseHandler != nil {
+		if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
+			action := m.TriageInboxModal.HandleMouse(msg, m.TriageInboxMouseHandler)
+			if action != "" {
+				return m.handleTriageInboxAction(action)
+			}
+			return m, nil
+		}
+		_ = m.TriageInboxModal.HandleMouse(msg, m.TriageInboxMouseHandler)
+		return m, nil
+	}
+
+	// Handle section filter prompt mouse events (declarative modal)
+	if m.SectionFilterOpen && m.SectionFilterModal != nil && m.SectionFilterMouseHandler != nil {
+		if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
+			action := m.SectionFilterModal.HandleMouse(msg, m.SectionFilterMouseHandler)
+			if action != "" {
+				return m.handleSectionFilterAction(action)
+			}
+			return m, nil
+		}
+		_ = m.SectionFilterModal.HandleMouse(msg, m.SectionFilterMouseHandler)
+		return m, nil
+	}
+
 	// Handle Close confirmation modal mouse events (declarative modal)
 	if m.CloseConfirmOpen && m.CloseConfirmModal != nil && m.CloseConfirmMouseHandler != nil {
 		if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
@@ -1080,7 +1165,7 @@ func (m Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 	}
 
 	// Ignore other mouse events when modals/overlays are open
-	if m.ModalOpen() || m.ActivityDetailOpen || m.StatsOpen || m.HandoffsOpen || m.ConfirmOpen || m.CloseConfirmOpen || m.FormOpen || m.BoardPickerOpen || m.BoardEditorOpen || m.HelpOpen || m.ShowTDQHelp || m.GettingStartedOpen || m.SyncPromptOpen {
+	if m.ModalOpen() || m.ActivityDetailOpen || m.StatsOpen || m.HandoffsOpen || m.RemindersOpen || m.CapacityOpen || m.ConfirmOpen || m.CloseConfirmOpen || m.ActionMenuOpen || m.ApproveChecklistOpen || m.TriageInboxOpen || m.SectionFilterOpen || m.FormOpen || m.BoardPickerOpen || m.BoardEditorOpen || m.HelpOpen || m.ShowTDQHelp || m.GettingStartedOpen || m.SyncPromptOpen {
 		return m, nil
 	}
 
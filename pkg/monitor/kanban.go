@@ -18,6 +18,7 @@ var kanbanColumnOrder = []TaskListCategory{
 	CategoryNeedsRework,
 	CategoryInProgress,
 	CategoryReady,
+	CategoryNeedsTriage,
 	CategoryPendingReview,
 	CategoryBlocked,
 	CategoryClosed,
@@ -34,6 +35,8 @@ func kanbanColumnLabel(cat TaskListCategory) string {
 		return "WIP"
 	case CategoryReady:
 		return "READY"
+	case CategoryNeedsTriage:
+		return "TRIAGE"
 	case CategoryPendingReview:
 		return "P.REVIEW"
 	case CategoryBlocked:
@@ -57,6 +60,8 @@ func kanbanColumnColor(cat TaskListCategory) lipgloss.Color {
 		return cyanColor // cyan (in_progress)
 	case CategoryReady:
 		return successColor // green (open/ready)
+	case CategoryNeedsTriage:
+		return warningColor // orange (needs specifying)
 	case CategoryPendingReview:
 		return lipgloss.Color("183") // light purple (pending review)
 	case CategoryBlocked:
@@ -79,6 +84,8 @@ func kanbanColumnIssues(data TaskListData, cat TaskListCategory) []models.Issue
 		return data.InProgress
 	case CategoryReady:
 		return data.Ready
+	case CategoryNeedsTriage:
+		return data.NeedsTriage
 	case CategoryPendingReview:
 		return data.PendingReview
 	case CategoryBlocked:
@@ -105,38 +105,40 @@ func TestKanbanNavigation(t *testing.T) {
 		t.Errorf("after second moveRight: col = %d, want 2", m.KanbanCol)
 	}
 
-	// Move all the way to Closed (col 6)
+	// Move all the way to Closed (col 7)
 	m.kanbanMoveRight() // col 3 (Ready)
-	m.kanbanMoveRight() // col 4 (PendingReview)
-	m.kanbanMoveRight() // col 5 (Blocked)
-	m.kanbanMoveRight() // col 6 (Closed)
-	if m.KanbanCol != 6 {
-		t.Errorf("col should be 6, got %d", m.KanbanCol)
+	m.kanbanMoveRight() // col 4 (NeedsTriage)
+	m.kanbanMoveRight() // col 5 (PendingReview)
+	m.kanbanMoveRight() // col 6 (Blocked)
+	m.kanbanMoveRight() // col 7 (Closed)
+	if m.KanbanCol != 7 {
+		t.Errorf("col should be 7, got %d", m.KanbanCol)
 	}
 
 	// Move right at rightmost column (should not move)
 	m.kanbanMoveRight()
-	if m.KanbanCol != 6 {
-		t.Errorf("after moveRight at rightmost: col = %d, want 6", m.KanbanCol)
+	if m.KanbanCol != 7 {
+		t.Errorf("after moveRight at rightmost: col = %d, want 7", m.KanbanCol)
 	}
 
-	// Col 6 (Closed) has 3 items - move down to row 2
+	// Col 7 (Closed) has 3 items - move down to row 2
 	m.kanbanMoveDown()
 	m.kanbanMoveDown()
 	if m.KanbanRow != 2 {
 		t.Errorf("after moving down in Closed: row = %d, want 2", m.KanbanRow)
 	}
 
-	// Move left to Blocked (col 5, empty) - row should clamp to 0
+	// Move left to Blocked (col 6, empty) - row should clamp to 0
 	m.kanbanMoveLeft()
-	if m.KanbanCol != 5 {
-		t.Errorf("after moveLeft: col = %d, want 5", m.KanbanCol)
+	if m.KanbanCol != 6 {
+		t.Errorf("after moveLeft: col = %d, want 6", m.KanbanCol)
 	}
 	if m.KanbanRow != 0 {
 		t.Errorf("after moveLeft to empty col: row = %d, want 0", m.KanbanRow)
 	}
 
 	// Move left to col 0
+	m.kanbanMoveLeft() // col 5
 	m.kanbanMoveLeft() // col 4
 	m.kanbanMoveLeft() // col 3
 	m.kanbanMoveLeft() // col 2
@@ -234,8 +236,8 @@ func TestKanbanPerColumnScroll(t *testing.T) {
 	// Record column 0 scroll
 	col0Scroll := m.KanbanColScrolls[0]
 
-	// Move to Closed column (col 6)
-	m.KanbanCol = 6
+	// Move to Closed column (col 7)
+	m.KanbanCol = 7
 	m.clampKanbanRow()
 	m.ensureKanbanCursorVisible()
 
@@ -249,16 +251,16 @@ func TestKanbanPerColumnScroll(t *testing.T) {
 		m.kanbanMoveDown()
 	}
 
-	// Column 6 scroll should be > 0 (if there are enough items)
+	// Column 7 scroll should be > 0 (if there are enough items)
 	if len(m.BoardMode.SwimlaneData.Closed) > maxVisible {
-		if m.KanbanColScrolls[6] <= 0 {
-			t.Errorf("column 6 scroll should be > 0, got %d", m.KanbanColScrolls[6])
+		if m.KanbanColScrolls[7] <= 0 {
+			t.Errorf("column 7 scroll should be > 0, got %d", m.KanbanColScrolls[7])
 		}
 	}
 
 	// Column 0 scroll should still be preserved
 	if m.KanbanColScrolls[0] != col0Scroll {
-		t.Errorf("column 0 scroll changed after scrolling col 6: got %d, want %d", m.KanbanColScrolls[0], col0Scroll)
+		t.Errorf("column 0 scroll changed after scrolling col 7: got %d, want %d", m.KanbanColScrolls[0], col0Scroll)
 	}
 }
 
@@ -0,0 +1,79 @@
+package monitor
+
+import (
+	"math"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Some terminal multiplexer setups break when an application turns on
+// mouse reporting, so the monitor can run keyboard-only. It then ignores
+// mouse events, never shows hover styles, and leaves mouse actions out of
+// the help. Everything the mouse does has a key: panels and rows are
+// picked with tab and the cursor keys, and panels are resized with + and -
+// instead of dragging their dividers.
+
+// DisableMouse makes the monitor keyboard-only. The program should also be
+// started without mouse reporting; an embedding host that still forwards
+// mouse events has them ignored.
+func (m *Model) DisableMouse() {
+	m.NoMouse = true
+	m.HoverPanel = -1
+	m.DividerHover = -1
+	m.DraggingDivider = -1
+	if m.Keymap != nil {
+		m.Keymap.SetKeyboardOnly(true)
+	}
+}
+
+// paneRatioStep is how much of the height + and - move to or from the
+// active panel
+const paneRatioStep = 0.05
+
+// minPaneRatio is the smallest share of the height a panel keeps, as when
+// dragging a divider
+const minPaneRatio = 0.1
+
+// resizePane grows (delta > 0) or shrinks the active panel, taking the
+// height from or giving it to the other two panels in proportion to their
+// size, and saves the new heights
+func (m Model) resizePane(delta float64) (tea.Model, tea.Cmd) {
+	active := int(m.ActivePanel)
+	if active < 0 || active >= len(m.PaneHeights) {
+		return m, nil
+	}
+	heights := m.PaneHeights
+	target := heights[active] + delta
+	if target < minPaneRatio {
+		target = minPaneRatio
+	}
+	if maxRatio := 1 - 2*minPaneRatio; target > maxRatio {
+		target = maxRatio
+	}
+
+	others := 1 - heights[active]
+	rest := 1 - target
+	for i := range heights {
+		if i == active {
+			continue
+		}
+		share := 0.5
+		if others > 0 {
+			share = heights[i] / others
+		}
+		heights[i] = math.Max(minPaneRatio, rest*share)
+	}
+	heights[active] = target
+
+	// Normalize to ensure sum = 1.0
+	sum := heights[0] + heights[1] + heights[2]
+	for i := range heights {
+		heights[i] = math.Round(heights[i]/sum*1000) / 1000
+	}
+	if heights == m.PaneHeights {
+		return m, nil
+	}
+	m.PaneHeights = heights
+	m.updatePanelBounds()
+	return m, m.savePaneHeightsAsync()
+}
@@ -0,0 +1,84 @@
+package monitor
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/td/pkg/monitor/keymap"
+)
+
+func TestDisableMouse_IgnoresMouseEvents(t *testing.T) {
+	m := Model{
+		ActivePanel: PanelCurrentWork,
+		PanelBounds: map[Panel]Rect{
+			PanelCurrentWork: {X: 0, Y: 0, W: 100, H: 10},
+			PanelTaskList:    {X: 0, Y: 10, W: 100, H: 10},
+			PanelActivity:    {X: 0, Y: 20, W: 100, H: 10},
+		},
+		Cursor:       make(map[Panel]int),
+		SelectedID:   make(map[Panel]string),
+		ScrollOffset: make(map[Panel]int),
+		Keymap:       newTestKeymap(),
+	}
+	m.DisableMouse()
+
+	for _, msg := range []tea.MouseMsg{
+		{X: 50, Y: 15, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft},
+		{X: 50, Y: 25, Action: tea.MouseActionMotion},
+	} {
+		result, cmd := m.Update(msg)
+		m2 := result.(Model)
+		if m2.ActivePanel != PanelCurrentWork || m2.HoverPanel != -1 || cmd != nil {
+			t.Errorf("%+v: active = %d, hover = %d, cmd = %v; want the event ignored", msg, m2.ActivePanel, m2.HoverPanel, cmd)
+		}
+	}
+}
+
+func TestDisableMouse_HelpLeavesOutMouse(t *testing.T) {
+	m := Model{Keymap: newTestKeymap()}
+	if !strings.Contains(m.Keymap.GenerateHelp(), "MOUSE:") {
+		t.Fatal("help should list mouse actions by default")
+	}
+	m.DisableMouse()
+	help := m.Keymap.GenerateHelp()
+	if strings.Contains(help, "MOUSE:") || strings.Contains(help, "Click") {
+		t.Error("keyboard-only help should leave out mouse actions")
+	}
+	if !strings.Contains(help, "Taller/shorter panel") {
+		t.Error("help should show the keys that resize panels")
+	}
+}
+
+func TestResizePane(t *testing.T) {
+	m := Model{
+		ActivePanel: PanelTaskList,
+		PaneHeights: [3]float64{0.3, 0.4, 0.3},
+		BaseDir:     t.TempDir(),
+		Keymap:      newTestKeymap(),
+	}
+
+	result, cmd := m.executeCommand(keymap.CmdPaneGrow)
+	m = result.(Model)
+	if cmd == nil {
+		t.Error("resizing should save the heights")
+	}
+	h := m.PaneHeights
+	if math.Abs(h[1]-0.45) > 0.001 || math.Abs(h[0]-0.275) > 0.001 || math.Abs(h[2]-0.275) > 0.001 {
+		t.Errorf("heights after grow = %v, want [0.275 0.45 0.275]", h)
+	}
+
+	// Shrinking stops at the minimum share
+	for i := 0; i < 20; i++ {
+		result, _ = m.executeCommand(keymap.CmdPaneShrink)
+		m = result.(Model)
+	}
+	h = m.PaneHeights
+	if math.Abs(h[1]-minPaneRatio) > 0.001 || math.Abs(h[0]+h[1]+h[2]-1) > 0.001 {
+		t.Errorf("heights after shrinking = %v, want the task list at %v", h, minPaneRatio)
+	}
+	if _, cmd = m.executeCommand(keymap.CmdPaneShrink); cmd != nil {
+		t.Error("shrinking past the minimum should not save")
+	}
+}
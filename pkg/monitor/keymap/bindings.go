@@ -42,6 +42,9 @@ func DefaultBindings() []Binding {
 		{Key: "enter", Command: CmdOpenDetails, Context: ContextMain, Description: "Open details"},
 		{Key: "s", Command: CmdOpenStats, Context: ContextMain, Description: "Open statistics"},
 		{Key: "h", Command: CmdOpenHandoffs, Context: ContextMain, Description: "Open handoffs"},
+		{Key: "m", Command: CmdOpenReminders, Context: ContextMain, Description: "Open reminders"},
+		{Key: "v", Command: CmdOpenCapacity, Context: ContextMain, Description: "Open sprint capacity"},
+		{Key: "I", Command: CmdOpenTriageInbox, Context: ContextMain, Description: "Open triage inbox"},
 		{Key: "/", Command: CmdSearch, Context: ContextMain, Description: "Search"},
 		{Key: "c", Command: CmdToggleClosed, Context: ContextMain, Description: "Toggle closed tasks"},
 		{Key: "S", Command: CmdCycleSortMode, Context: ContextMain, Description: "Cycle sort mode"},
@@ -57,6 +60,13 @@ func DefaultBindings() []Binding {
 		{Key: "y", Command: CmdCopyToClipboard, Context: ContextMain, Description: "Copy issue as markdown"},
 		{Key: "Y", Command: CmdCopyIDToClipboard, Context: ContextMain, Description: "Copy issue ID"},
 		{Key: "W", Command: CmdSendToWorktree, Context: ContextMain, Description: "Send to worktree"},
+		{Key: ".", Command: CmdOpenActionMenu, Context: ContextMain, Description: "Issue actions menu"},
+		{Key: "f", Command: CmdFilterSection, Context: ContextMain, Description: "Filter section (TDQ)"},
+		{Key: "p", Command: CmdTogglePreview, Context: ContextMain, Description: "Toggle preview pane"},
+		{Key: ">", Command: CmdPreviewGrow, Context: ContextMain, Description: "Widen preview pane"},
+		{Key: "<", Command: CmdPreviewShrink, Context: ContextMain, Description: "Narrow preview pane"},
+		{Key: "+", Command: CmdPaneGrow, Context: ContextMain, Description: "Taller panel"},
+		{Key: "-", Command: CmdPaneShrink, Context: ContextMain, Description: "Shorter panel"},
 
 		// ============================================================
 		// MODAL BINDINGS (Issue Details)
@@ -106,6 +116,7 @@ func DefaultBindings() []Binding {
 		{Key: "C", Command: CmdCloseIssue, Context: ContextModal, Description: "Close issue"},
 		{Key: "O", Command: CmdReopenIssue, Context: ContextModal, Description: "Reopen issue"},
 		{Key: "W", Command: CmdSendToWorktree, Context: ContextModal, Description: "Send to worktree"},
+		{Key: ".", Command: CmdOpenActionMenu, Context: ContextModal, Description: "Issue actions menu"},
 
 		// ============================================================
 		// STATS MODAL BINDINGS
@@ -204,6 +215,7 @@ func DefaultBindings() []Binding {
 		{Key: "R", Command: CmdMarkForReview, Context: ContextEpicTasks, Description: "Submit task for review"},
 		{Key: "C", Command: CmdCloseIssue, Context: ContextEpicTasks, Description: "Close task"},
 		{Key: "W", Command: CmdSendToWorktree, Context: ContextEpicTasks, Description: "Send to worktree"},
+		{Key: ".", Command: CmdOpenActionMenu, Context: ContextEpicTasks, Description: "Issue actions menu"},
 
 		// Modal context: add tab to toggle task section focus
 		{Key: "tab", Command: CmdFocusTaskSection, Context: ContextModal, Description: "Focus task list"},
@@ -268,6 +280,36 @@ func DefaultBindings() []Binding {
 		{Key: "end", Command: CmdCursorBottom, Context: ContextHandoffs, Description: "Go to bottom"},
 		{Key: "r", Command: CmdRefresh, Context: ContextHandoffs, Description: "Refresh"},
 
+		// ============================================================
+		// REMINDERS MODAL BINDINGS
+		// Active when the reminders modal is open
+		// ============================================================
+		{Key: "esc", Command: CmdClose, Context: ContextReminders, Description: "Close modal"},
+		{Key: "enter", Command: CmdOpenDetails, Context: ContextReminders, Description: "Open issue"},
+		{Key: "x", Command: CmdCancelReminder, Context: ContextReminders, Description: "Cancel reminder"},
+		{Key: "j", Command: CmdCursorDown, Context: ContextReminders, Description: "Move down"},
+		{Key: "down", Command: CmdCursorDown, Context: ContextReminders, Description: "Move down"},
+		{Key: "k", Command: CmdCursorUp, Context: ContextReminders, Description: "Move up"},
+		{Key: "up", Command: CmdCursorUp, Context: ContextReminders, Description: "Move up"},
+		{Key: "G", Command: CmdCursorBottom, Context: ContextReminders, Description: "Go to bottom"},
+		{Key: "g g", Command: CmdCursorTop, Context: ContextReminders, Description: "Go to top"},
+		{Key: "home", Command: CmdCursorTop, Context: ContextReminders, Description: "Go to top"},
+		{Key: "end", Command: CmdCursorBottom, Context: ContextReminders, Description: "Go to bottom"},
+		{Key: "r", Command: CmdRefresh, Context: ContextReminders, Description: "Refresh"},
+
+		// ============================================================
+		// CAPACITY MODAL BINDINGS
+		// Active when the sprint capacity modal is open
+		// ============================================================
+		{Key: "esc", Command: CmdClose, Context: ContextCapacity, Description: "Close modal"},
+		{Key: "j", Command: CmdCursorDown, Context: ContextCapacity, Description: "Move down"},
+		{Key: "down", Command: CmdCursorDown, Context: ContextCapacity, Description: "Move down"},
+		{Key: "k", Command: CmdCursorUp, Context: ContextCapacity, Description: "Move up"},
+		{Key: "up", Command: CmdCursorUp, Context: ContextCapacity, Description: "Move up"},
+		{Key: "G", Command: CmdCursorBottom, Context: ContextCapacity, Description: "Go to bottom"},
+		{Key: "g g", Command: CmdCursorTop, Context: ContextCapacity, Description: "Go to top"},
+		{Key: "r", Command: CmdRefresh, Context: ContextCapacity, Description: "Refresh"},
+
 		// ============================================================
 		// FORM MODAL BINDINGS
 		// Active when form modal is open
@@ -374,6 +416,13 @@ func DefaultBindings() []Binding {
 		{Key: "S", Command: CmdCycleSortMode, Context: ContextBoard, Description: "Cycle sort mode"},
 		{Key: "T", Command: CmdCycleTypeFilter, Context: ContextBoard, Description: "Cycle type filter"},
 		{Key: "W", Command: CmdSendToWorktree, Context: ContextBoard, Description: "Send to worktree"},
+		{Key: ".", Command: CmdOpenActionMenu, Context: ContextBoard, Description: "Issue actions menu"},
+		{Key: "f", Command: CmdFilterSection, Context: ContextBoard, Description: "Filter section (TDQ)"},
+		{Key: "p", Command: CmdTogglePreview, Context: ContextBoard, Description: "Toggle preview pane"},
+		{Key: ">", Command: CmdPreviewGrow, Context: ContextBoard, Description: "Widen preview pane"},
+		{Key: "<", Command: CmdPreviewShrink, Context: ContextBoard, Description: "Narrow preview pane"},
+		{Key: "+", Command: CmdPaneGrow, Context: ContextBoard, Description: "Taller panel"},
+		{Key: "-", Command: CmdPaneShrink, Context: ContextBoard, Description: "Shorter panel"},
 
 		// Additional navigation (same as ContextMain)
 		{Key: "ctrl+f", Command: CmdFullPageDown, Context: ContextBoard, Description: "Full page down"},
@@ -0,0 +1,218 @@
+package keymap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Unbind is the command that takes a key away from its default command
+// without giving it a new one: {"main:x": "none"}.
+const Unbind = "none"
+
+// Problem severities
+const (
+	SeverityError   = "error"   // the binding is not applied
+	SeverityWarning = "warning" // applied, but probably not what was meant
+)
+
+// Problem is a keymap.json entry that can't be applied or that conflicts
+// with another binding
+type Problem struct {
+	Binding  string `json:"binding"` // "context:key" as written in the config
+	Command  string `json:"command"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// Contexts returns every context a binding can be set for
+func Contexts() []Context {
+	return []Context{
+		ContextGlobal, ContextMain, ContextModal, ContextStats, ContextSearch, ContextConfirm,
+		ContextEpicTasks, ContextParentEpicFocused, ContextBlockedByFocused, ContextBlocksFocused,
+		ContextHandoffs, ContextForm, ContextHelp, ContextBoardPicker, ContextBoard,
+		ContextGettingStarted, ContextTDQHelp, ContextBoardEditor, ContextCloseConfirm,
+		ContextSyncPrompt, ContextKanban, ContextActionMenu, ContextSectionFilter,
+		ContextApproveChecklist, ContextTriageInbox, ContextReminders, ContextCapacity,
+	}
+}
+
+// Commands returns every command a key can be bound to, sorted
+func Commands() []Command {
+	seen := make(map[Command]bool)
+	for _, c := range AllCommands() {
+		seen[c] = true
+	}
+	for _, b := range DefaultBindings() {
+		seen[b.Command] = true
+	}
+	cmds := make([]Command, 0, len(seen))
+	for c := range seen {
+		cmds = append(cmds, c)
+	}
+	sort.Slice(cmds, func(i, j int) bool { return cmds[i] < cmds[j] })
+	return cmds
+}
+
+// NormalizeKey writes a key the way KeyToString does, so "Ctrl+S" and
+// "Esc" match the keys the monitor sees. Single characters keep their case:
+// "S" and "s" are different keys, and any character a keyboard layout
+// produces, such as "ö", can be bound.
+func NormalizeKey(key string) string {
+	parts := strings.Fields(key)
+	for i, p := range parts {
+		if len([]rune(p)) > 1 {
+			parts[i] = strings.ToLower(p)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// Check reports the problems with cfg against the default bindings.
+// Entries naming an unknown context or command, or the same key as another
+// entry, are errors and ApplyConfig skips them. Warnings are for keys that
+// can never fire because they start a key sequence, and for commands left
+// without any key in a context because their keys were all taken.
+func Check(cfg *Config) []Problem {
+	var problems []Problem
+	if cfg == nil {
+		return problems
+	}
+
+	contexts := make(map[Context]bool)
+	for _, c := range Contexts() {
+		contexts[c] = true
+	}
+	commands := map[Command]bool{Unbind: true}
+	for _, c := range Commands() {
+		commands[c] = true
+	}
+
+	names := make([]string, 0, len(cfg.Bindings))
+	for name := range cfg.Bindings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	r := NewRegistry()
+	RegisterDefaults(r)
+	type entry struct {
+		name, cmd string
+		ctx       Context
+		key       string
+	}
+	var applied []entry
+	taken := make(map[string]string) // "context:key" -> entry that set it
+	for _, name := range names {
+		cmd := cfg.Bindings[name]
+		ctx, key := parseBinding(name)
+		key = NormalizeKey(key)
+		fail := func(format string, args ...interface{}) {
+			problems = append(problems, Problem{Binding: name, Command: cmd, Severity: SeverityError, Message: fmt.Sprintf(format, args...)})
+		}
+		switch {
+		case !contexts[ctx]:
+			fail("unknown context %q", ctx)
+		case key == "":
+			fail("no key given")
+		case !commands[Command(cmd)]:
+			fail("unknown command %q", cmd)
+		case taken[string(ctx)+":"+key] != "":
+			fail("same key as %s", taken[string(ctx)+":"+key])
+		default:
+			taken[string(ctx)+":"+key] = name
+			r.userOverrides[string(ctx)+":"+key] = Command(cmd)
+			applied = append(applied, entry{name: name, cmd: cmd, ctx: ctx, key: key})
+		}
+	}
+
+	for _, e := range applied {
+		warn := func(format string, args ...interface{}) {
+			problems = append(problems, Problem{Binding: e.name, Command: e.cmd, Severity: SeverityWarning, Message: fmt.Sprintf(format, args...)})
+		}
+		if !strings.Contains(e.key, " ") && r.isSequenceStart(e.key, e.ctx) {
+			warn("%q starts a key sequence, so it never fires on its own", e.key)
+		}
+		lost, ok := r.findInContext(e.key, e.ctx)
+		if !ok && e.ctx != ContextGlobal {
+			lost, ok = r.findInContext(e.key, ContextGlobal)
+		}
+		if ok && lost != Command(e.cmd) && len(r.keysFor(e.ctx, lost, true)) == 0 {
+			warn("%s has no other key in %s", lost, e.ctx)
+		}
+	}
+	return problems
+}
+
+// HasErrors reports whether any of problems keeps a binding from applying
+func HasErrors(problems []Problem) bool {
+	for _, p := range problems {
+		if p.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// KeysFor returns the keys that run cmd in a context, user overrides
+// included, in the order help text shows them
+func (r *Registry) KeysFor(context Context, cmd Command) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.keysFor(context, cmd, true)
+}
+
+// keysFor returns the keys that run cmd in context, with or without the
+// user overrides. The caller holds r.mu.
+func (r *Registry) keysFor(context Context, cmd Command, withOverrides bool) []string {
+	var candidates []string
+	if withOverrides {
+		var overridden []string
+		for k, c := range r.userOverrides {
+			ctx, key := parseBinding(k)
+			if c == cmd && (ctx == context || ctx == ContextGlobal) {
+				overridden = append(overridden, key)
+			}
+		}
+		sort.Strings(overridden)
+		candidates = append(candidates, overridden...)
+	}
+	for _, ctx := range []Context{context, ContextGlobal} {
+		for _, b := range r.bindings[ctx] {
+			if b.Command == cmd {
+				candidates = append(candidates, b.Key)
+			}
+		}
+		if context == ContextGlobal {
+			break
+		}
+	}
+
+	var keys []string
+	seen := make(map[string]bool)
+	for _, key := range candidates {
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		got, ok := r.resolve(key, context, withOverrides)
+		if ok && got == cmd {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// resolve finds the command a key runs in context, as findCommand does,
+// optionally ignoring the user overrides
+func (r *Registry) resolve(key string, context Context, withOverrides bool) (Command, bool) {
+	if withOverrides {
+		return r.findCommand(key, context)
+	}
+	if context != ContextGlobal {
+		if cmd, ok := r.findInContext(key, context); ok {
+			return cmd, true
+		}
+	}
+	return r.findInContext(key, ContextGlobal)
+}
@@ -0,0 +1,140 @@
+package keymap
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestNormalizeKey(t *testing.T) {
+	tests := map[string]string{
+		"Ctrl+S": "ctrl+s",
+		"Esc":    "esc",
+		"S":      "S",
+		"ö":      "ö",
+		"g  g":   "g g",
+	}
+	for in, want := range tests {
+		if got := NormalizeKey(in); got != want {
+			t.Errorf("NormalizeKey(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestCheckErrors(t *testing.T) {
+	problems := Check(&Config{Bindings: map[string]string{
+		"nowhere:x":   "quit",
+		"main:z":      "fly",
+		"main:ctrl+s": "open-stats",
+		"main:Ctrl+S": "refresh",
+		"main:ö":      "cursor-down",
+	}})
+
+	errs := make(map[string]string)
+	for _, p := range problems {
+		if p.Severity == SeverityError {
+			errs[p.Binding] = p.Message
+		}
+	}
+	if len(errs) != 3 {
+		t.Fatalf("errors = %v, want 3", errs)
+	}
+	if !strings.Contains(errs["nowhere:x"], "unknown context") {
+		t.Errorf("nowhere:x = %q", errs["nowhere:x"])
+	}
+	if !strings.Contains(errs["main:z"], "unknown command") {
+		t.Errorf("main:z = %q", errs["main:z"])
+	}
+	// Sorted names put "main:Ctrl+S" first, so the second spelling is the duplicate
+	if !strings.Contains(errs["main:ctrl+s"], "same key as main:Ctrl+S") {
+		t.Errorf("main:ctrl+s = %q", errs["main:ctrl+s"])
+	}
+}
+
+func TestCheckWarnings(t *testing.T) {
+	problems := Check(&Config{Bindings: map[string]string{
+		"main:g":      "open-stats", // starts "g g"
+		"main:a":      "quit",       // approve has no other key
+		"main:j":      "none",       // cursor-down keeps "down"
+		"main:ctrl+n": "new-issue",  // free key
+	}})
+
+	warned := make(map[string]string)
+	for _, p := range problems {
+		if p.Severity != SeverityWarning {
+			t.Errorf("unexpected error %+v", p)
+		}
+		warned[p.Binding] = p.Message
+	}
+	if !strings.Contains(warned["main:g"], "key sequence") {
+		t.Errorf("main:g = %q", warned["main:g"])
+	}
+	if !strings.Contains(warned["main:a"], "approve has no other key") {
+		t.Errorf("main:a = %q", warned["main:a"])
+	}
+	if len(warned) != 2 {
+		t.Errorf("warnings = %v, want main:g and main:a", warned)
+	}
+}
+
+func TestApplyConfigSkipsErrors(t *testing.T) {
+	r := NewRegistry()
+	RegisterDefaults(r)
+	ApplyConfig(r, &Config{Bindings: map[string]string{
+		"main:Ctrl+E": "cursor-down",
+		"main:z":      "fly",
+	}})
+
+	if cmd, ok := r.Lookup(tea.KeyMsg{Type: tea.KeyCtrlE}, ContextMain); !ok || cmd != CmdCursorDown {
+		t.Errorf("ctrl+e = %q, %v", cmd, ok)
+	}
+	if cmd, ok := r.Lookup(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'z'}}, ContextMain); ok {
+		t.Errorf("invalid binding applied: z = %q", cmd)
+	}
+}
+
+func TestKeysFor(t *testing.T) {
+	r := NewRegistry()
+	RegisterDefaults(r)
+	ApplyConfig(r, &Config{Bindings: map[string]string{
+		"main:ö": "cursor-down",
+		"main:j": "none",
+	}})
+
+	got := strings.Join(r.KeysFor(ContextMain, CmdCursorDown), ",")
+	if got != "ö,down" {
+		t.Errorf("KeysFor(main, cursor-down) = %s, want ö,down", got)
+	}
+	if keys := r.KeysFor(ContextMain, CmdQuit); len(keys) == 0 {
+		t.Error("quit should keep its global keys in main")
+	}
+}
+
+func TestHelpShowsRemappedKeys(t *testing.T) {
+	r := NewRegistry()
+	RegisterDefaults(r)
+	ApplyConfig(r, &Config{Bindings: map[string]string{"main:ctrl+a": "approve"}})
+
+	help := r.GenerateHelp()
+	if !strings.Contains(help, "YOUR KEY BINDINGS") {
+		t.Error("help should list the user's bindings")
+	}
+	found := false
+	for _, line := range strings.Split(help, "\n") {
+		if strings.Contains(line, "Approve issue (Task List reviewable)") {
+			found = true
+			if !strings.Contains(line, "Ctrl+a / a") {
+				t.Errorf("approve help line = %q", line)
+			}
+		}
+	}
+	if !found {
+		t.Error("help is missing the approve entry")
+	}
+
+	// Untouched entries keep their hand-written keys
+	if !strings.Contains(help, "↑ / ↓ / j / k") {
+		t.Error("unchanged entries should be shown as written")
+	}
+}
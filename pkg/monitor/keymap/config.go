@@ -60,15 +60,22 @@ func SaveConfig(path string, cfg *Config) error {
 	return os.WriteFile(path, data, 0644)
 }
 
-// ApplyConfig applies user configuration overrides to the registry.
+// ApplyConfig applies user configuration overrides to the registry,
+// skipping the entries Check reports as errors.
 func ApplyConfig(r *Registry, cfg *Config) {
+	skip := make(map[string]bool)
+	for _, p := range Check(cfg) {
+		if p.Severity == SeverityError {
+			skip[p.Binding] = true
+		}
+	}
 	for binding, cmdStr := range cfg.Bindings {
-		// Parse "context:key" format
-		ctx, key := parseBinding(binding)
-		if ctx == "" || key == "" {
+		if skip[binding] {
 			continue
 		}
-		r.SetUserOverride(ctx, key, Command(cmdStr))
+		// Parse "context:key" format
+		ctx, key := parseBinding(binding)
+		r.SetUserOverride(ctx, NormalizeKey(key), Command(cmdStr))
 	}
 }
 
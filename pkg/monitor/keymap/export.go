@@ -36,6 +36,12 @@ var contextToSidecar = map[Context]string{
 	ContextBoardEditor:       "td-board-editor",
 	ContextCloseConfirm:      "td-close-confirm",
 	ContextKanban:            "td-kanban",
+	ContextActionMenu:        "td-action-menu",
+	ContextSectionFilter:     "td-section-filter",
+	ContextApproveChecklist:  "td-approve-checklist",
+	ContextTriageInbox:       "td-triage-inbox",
+	ContextReminders:         "td-reminders",
+	ContextCapacity:          "td-capacity",
 }
 
 // commandMetadata defines display info and priority for each command.
@@ -54,12 +60,23 @@ var commandMetadata = map[Command]struct {
 
 	// Medium priority - footer when space allows (P2)
 	CmdOpenHandoffs:    {"Handoffs", "Open handoffs", 2},
+	CmdOpenReminders:   {"Reminders", "Open reminders", 3},
+	CmdCancelReminder:  {"Cancel", "Cancel reminder", 2},
+	CmdOpenCapacity:    {"Capacity", "Open sprint capacity", 3},
+	CmdOpenTriageInbox: {"Inbox", "Open triage inbox", 3},
 	CmdToggleClosed:    {"Closed", "Toggle closed tasks", 2},
 	CmdDelete:          {"Delete", "Delete issue", 2},
 	CmdCloseIssue:      {"Close", "Close issue", 2},
 	CmdReopenIssue:     {"Reopen", "Reopen closed issue", 2},
+	CmdOpenActionMenu:  {"Actions", "Open issue actions menu", 2},
 	CmdCycleSortMode:   {"Sort", "Cycle sort mode", 2},
 	CmdCycleTypeFilter: {"Type", "Cycle type filter", 2},
+	CmdFilterSection:   {"Filter", "Filter section with TDQ", 3},
+	CmdTogglePreview:   {"Preview", "Toggle issue preview pane", 3},
+	CmdPreviewGrow:     {"Wider", "Widen preview pane", 4},
+	CmdPreviewShrink:   {"Narrower", "Narrow preview pane", 4},
+	CmdPaneGrow:        {"Taller", "Make the active panel taller", 4},
+	CmdPaneShrink:      {"Shorter", "Make the active panel shorter", 4},
 
 	// Board mode controls (P2)
 	CmdOpenBoardPicker:        {"Boards", "Open board picker", 2},
@@ -6,6 +6,8 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/marcus/td/internal/i18n"
 )
 
 // Header style for TDQ help sections
@@ -23,6 +25,12 @@ type HelpSection struct {
 type HelpBinding struct {
 	Keys        string // Combined keys like "j / k" or "↑ / ↓"
 	Description string
+	// Context and Commands tie the entry to the keymap, so remapped keys
+	// replace Keys. Entries without commands, such as mouse actions, are
+	// shown as written.
+	Context  Context
+	Commands []Command
+	Mouse    bool // left out when the monitor is keyboard-only
 }
 
 // GenerateHelp generates help text from the registry bindings
@@ -31,164 +39,227 @@ func (r *Registry) GenerateHelp() string {
 	defer r.mu.RUnlock()
 
 	var sb strings.Builder
-	sb.WriteString("\nMONITOR TUI - Key Bindings\n")
+	sb.WriteString("\n" + i18n.T("MONITOR TUI - Key Bindings") + "\n")
+
+	// Keys changed in .todos/keymap.json come first
+	if len(r.userOverrides) > 0 {
+		sb.WriteString("\n" + i18n.T("YOUR KEY BINDINGS:") + "\n")
+		overrides := make([]string, 0, len(r.userOverrides))
+		for k := range r.userOverrides {
+			overrides = append(overrides, k)
+		}
+		sort.Strings(overrides)
+		for _, k := range overrides {
+			ctx, key := parseBinding(k)
+			desc := i18n.T("Unbound")
+			if cmd := r.userOverrides[k]; cmd != Unbind {
+				desc = i18n.Text(CommandHelp(cmd))
+			}
+			sb.WriteString(fmt.Sprintf("  %-20s %s\n", string(ctx)+" "+formatKey(key), desc))
+		}
+	}
 
 	// Build navigation section manually for better grouping
-	sb.WriteString("\nNAVIGATION:\n")
+	sb.WriteString("\n" + i18n.T("NAVIGATION:") + "\n")
 	navBindings := []HelpBinding{
-		{Keys: "Tab / Shift+Tab", Description: "Switch between panels"},
-		{Keys: "↑ / ↓ / j / k", Description: "Move cursor"},
-		{Keys: "Ctrl+d / Ctrl+u", Description: "Half page down/up"},
-		{Keys: "Ctrl+f / Ctrl+b", Description: "Full page down/up"},
-		{Keys: "G / g g", Description: "Jump to bottom/top"},
-		{Keys: "Enter", Description: "Open issue details"},
+		{Keys: "Tab / Shift+Tab", Description: "Switch between panels", Context: ContextMain, Commands: []Command{CmdNextPanel, CmdPrevPanel}},
+		{Keys: "↑ / ↓ / j / k", Description: "Move cursor", Context: ContextMain, Commands: []Command{CmdCursorUp, CmdCursorDown}},
+		{Keys: "Ctrl+d / Ctrl+u", Description: "Half page down/up", Context: ContextMain, Commands: []Command{CmdHalfPageDown, CmdHalfPageUp}},
+		{Keys: "Ctrl+f / Ctrl+b", Description: "Full page down/up", Context: ContextMain, Commands: []Command{CmdFullPageDown, CmdFullPageUp}},
+		{Keys: "G / g g", Description: "Jump to bottom/top", Context: ContextMain, Commands: []Command{CmdCursorBottom, CmdCursorTop}},
+		{Keys: "Enter", Description: "Open issue details", Context: ContextMain, Commands: []Command{CmdOpenDetails}},
 	}
 	for _, b := range navBindings {
-		sb.WriteString(fmt.Sprintf("  %-20s %s\n", b.Keys, b.Description))
+		sb.WriteString(fmt.Sprintf("  %-20s %s\n", r.helpKeys(b), i18n.Text(b.Description)))
 	}
 
-	sb.WriteString("\nMODALS:\n")
+	sb.WriteString("\n" + i18n.T("MODALS:") + "\n")
 	modalBindings := []HelpBinding{
-		{Keys: "↑ / ↓ / j / k", Description: "Scroll (k at top focuses parent epic)"},
-		{Keys: "Ctrl+d / Ctrl+u", Description: "Half page down/up"},
-		{Keys: "← / → / h / l", Description: "Navigate prev/next issue"},
+		{Keys: "↑ / ↓ / j / k", Description: "Scroll (k at top focuses parent epic)", Context: ContextModal, Commands: []Command{CmdScrollUp, CmdScrollDown}},
+		{Keys: "Ctrl+d / Ctrl+u", Description: "Half page down/up", Context: ContextModal, Commands: []Command{CmdHalfPageDown, CmdHalfPageUp}},
+		{Keys: "← / → / h / l", Description: "Navigate prev/next issue", Context: ContextModal, Commands: []Command{CmdNavigatePrev, CmdNavigateNext}},
 		{Keys: "Enter", Description: "Open focused epic / close modal"},
-		{Keys: "Esc", Description: "Close modal (return to previous)"},
-		{Keys: "r", Description: "Refresh modal content"},
-		{Keys: "y", Description: "Copy to clipboard (markdown)"},
-		{Keys: "Tab", Description: "Focus epic task list (if epic)"},
+		{Keys: "Esc", Description: "Close modal (return to previous)", Context: ContextModal, Commands: []Command{CmdClose}},
+		{Keys: "r", Description: "Refresh modal content", Context: ContextModal, Commands: []Command{CmdRefresh}},
+		{Keys: "y", Description: "Copy to clipboard (markdown)", Context: ContextModal, Commands: []Command{CmdCopyToClipboard}},
+		{Keys: "Tab", Description: "Focus epic task list (if epic)", Context: ContextModal, Commands: []Command{CmdFocusTaskSection}},
 	}
 	for _, b := range modalBindings {
-		sb.WriteString(fmt.Sprintf("  %-20s %s\n", b.Keys, b.Description))
+		sb.WriteString(fmt.Sprintf("  %-20s %s\n", r.helpKeys(b), i18n.Text(b.Description)))
 	}
 
-	sb.WriteString("\nEPIC TASKS (when focused):\n")
+	sb.WriteString("\n" + i18n.T("EPIC TASKS (when focused):") + "\n")
 	epicBindings := []HelpBinding{
-		{Keys: "↑ / ↓ / j / k", Description: "Select task in list"},
-		{Keys: "Enter", Description: "Open selected task"},
-		{Keys: "Tab", Description: "Exit task list"},
-		{Keys: "y", Description: "Copy epic to clipboard (markdown)"},
-		{Keys: "Esc", Description: "Close modal"},
+		{Keys: "↑ / ↓ / j / k", Description: "Select task in list", Context: ContextEpicTasks, Commands: []Command{CmdCursorUp, CmdCursorDown}},
+		{Keys: "Enter", Description: "Open selected task", Context: ContextEpicTasks, Commands: []Command{CmdOpenEpicTask}},
+		{Keys: "Tab", Description: "Exit task list", Context: ContextEpicTasks, Commands: []Command{CmdFocusTaskSection}},
+		{Keys: "y", Description: "Copy epic to clipboard (markdown)", Context: ContextEpicTasks, Commands: []Command{CmdCopyToClipboard}},
+		{Keys: "Esc", Description: "Close modal", Context: ContextEpicTasks, Commands: []Command{CmdClose}},
 	}
 	for _, b := range epicBindings {
-		sb.WriteString(fmt.Sprintf("  %-20s %s\n", b.Keys, b.Description))
+		sb.WriteString(fmt.Sprintf("  %-20s %s\n", r.helpKeys(b), i18n.Text(b.Description)))
 	}
 
-	sb.WriteString("\nCRUD:\n")
+	sb.WriteString("\n" + i18n.T("CRUD:") + "\n")
 	crudBindings := []HelpBinding{
-		{Keys: "n", Description: "New issue"},
-		{Keys: "e", Description: "Edit selected/open issue"},
-		{Keys: "x", Description: "Delete issue (confirmation required)"},
-		{Keys: "C", Description: "Close issue"},
-		{Keys: "O", Description: "Reopen closed issue"},
+		{Keys: "n", Description: "New issue", Context: ContextMain, Commands: []Command{CmdNewIssue}},
+		{Keys: "e", Description: "Edit selected/open issue", Context: ContextMain, Commands: []Command{CmdEditIssue}},
+		{Keys: "x", Description: "Delete issue (confirmation required)", Context: ContextMain, Commands: []Command{CmdDelete}},
+		{Keys: "C", Description: "Close issue", Context: ContextMain, Commands: []Command{CmdCloseIssue}},
+		{Keys: "O", Description: "Reopen closed issue", Context: ContextMain, Commands: []Command{CmdReopenIssue}},
+		{Keys: ".", Description: "Actions menu (start/review/approve/block/comment)", Context: ContextMain, Commands: []Command{CmdOpenActionMenu}},
 	}
 	for _, b := range crudBindings {
-		sb.WriteString(fmt.Sprintf("  %-20s %s\n", b.Keys, b.Description))
+		sb.WriteString(fmt.Sprintf("  %-20s %s\n", r.helpKeys(b), i18n.Text(b.Description)))
 	}
 
-	sb.WriteString("\nCONFIRMATION DIALOGS:\n")
+	sb.WriteString("\n" + i18n.T("CONFIRMATION DIALOGS:") + "\n")
 	confirmBindings := []HelpBinding{
-		{Keys: "Tab / Shift+Tab", Description: "Switch between buttons"},
-		{Keys: "Enter", Description: "Execute focused button"},
-		{Keys: "Y / y", Description: "Confirm (delete dialog)"},
-		{Keys: "N / n", Description: "Cancel (delete dialog)"},
+		{Keys: "Tab / Shift+Tab", Description: "Switch between buttons", Context: ContextConfirm, Commands: []Command{CmdNextButton, CmdPrevButton}},
+		{Keys: "Enter", Description: "Execute focused button", Context: ContextConfirm, Commands: []Command{CmdSelect}},
+		{Keys: "Y / y", Description: "Confirm (delete dialog)", Context: ContextConfirm, Commands: []Command{CmdConfirm}},
+		{Keys: "N / n", Description: "Cancel (delete dialog)", Context: ContextConfirm, Commands: []Command{CmdCancel}},
 		{Keys: "Esc", Description: "Cancel and close"},
-		{Keys: "Click", Description: "Click buttons directly"},
+		{Keys: "Click", Description: "Click buttons directly", Mouse: true},
 	}
 	for _, b := range confirmBindings {
-		sb.WriteString(fmt.Sprintf("  %-20s %s\n", b.Keys, b.Description))
+		if b.Mouse && r.keyboardOnly {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("  %-20s %s\n", r.helpKeys(b), i18n.Text(b.Description)))
 	}
 
-	sb.WriteString("\nFORM (when editing):\n")
+	sb.WriteString("\n" + i18n.T("FORM (when editing):") + "\n")
 	formBindings := []HelpBinding{
-		{Keys: "Ctrl+S", Description: "Save form"},
-		{Keys: "Esc", Description: "Cancel form"},
-		{Keys: "Ctrl+X", Description: "Toggle extended fields"},
-		{Keys: "Ctrl+O", Description: "Edit description in $EDITOR"},
+		{Keys: "Ctrl+S", Description: "Save form", Context: ContextForm, Commands: []Command{CmdFormSubmit}},
+		{Keys: "Esc", Description: "Cancel form", Context: ContextForm, Commands: []Command{CmdFormCancel}},
+		{Keys: "Ctrl+X", Description: "Toggle extended fields", Context: ContextForm, Commands: []Command{CmdFormToggleExtend}},
+		{Keys: "Ctrl+O", Description: "Edit description in $EDITOR", Context: ContextForm, Commands: []Command{CmdFormOpenEditor}},
 	}
 	for _, b := range formBindings {
-		sb.WriteString(fmt.Sprintf("  %-20s %s\n", b.Keys, b.Description))
+		sb.WriteString(fmt.Sprintf("  %-20s %s\n", r.helpKeys(b), i18n.Text(b.Description)))
 	}
 
-	sb.WriteString("\nACTIONS:\n")
+	sb.WriteString("\n" + i18n.T("ACTIONS:") + "\n")
 	actionBindings := []HelpBinding{
-		{Keys: "r", Description: "Mark for review (Current Work) / Refresh"},
-		{Keys: "a", Description: "Approve issue (Task List reviewable)"},
-		{Keys: "s", Description: "Show statistics dashboard"},
-		{Keys: "h", Description: "Show handoffs modal"},
-		{Keys: "S", Description: "Cycle sort (priority/created/updated)"},
-		{Keys: "T", Description: "Cycle type filter (epic/task/bug/...)"},
-		{Keys: "/", Description: "Search tasks"},
-		{Keys: "Esc", Description: "Clear search filter"},
-		{Keys: "c", Description: "Toggle closed tasks"},
-		{Keys: "q / Ctrl+C", Description: "Quit"},
+		{Keys: "r", Description: "Mark for review (Current Work) / Refresh", Context: ContextMain, Commands: []Command{CmdMarkForReview}},
+		{Keys: "a", Description: "Approve issue (Task List reviewable)", Context: ContextMain, Commands: []Command{CmdApprove}},
+		{Keys: "s", Description: "Show statistics dashboard", Context: ContextMain, Commands: []Command{CmdOpenStats}},
+		{Keys: "h", Description: "Show handoffs modal", Context: ContextMain, Commands: []Command{CmdOpenHandoffs}},
+		{Keys: "S", Description: "Cycle sort (priority/created/updated)", Context: ContextMain, Commands: []Command{CmdCycleSortMode}},
+		{Keys: "T", Description: "Cycle type filter (epic/task/bug/...)", Context: ContextMain, Commands: []Command{CmdCycleTypeFilter}},
+		{Keys: "/", Description: "Search tasks", Context: ContextMain, Commands: []Command{CmdSearch}},
+		{Keys: "f", Description: "Filter section under cursor (TDQ, saved per board)", Context: ContextMain, Commands: []Command{CmdFilterSection}},
+		{Keys: "p", Description: "Toggle issue preview pane", Context: ContextMain, Commands: []Command{CmdTogglePreview}},
+		{Keys: "< / >", Description: "Narrow/widen preview pane", Context: ContextMain, Commands: []Command{CmdPreviewShrink, CmdPreviewGrow}},
+		{Keys: "+ / -", Description: "Taller/shorter panel", Context: ContextMain, Commands: []Command{CmdPaneGrow, CmdPaneShrink}},
+		{Keys: "Esc", Description: "Clear search filter", Context: ContextMain, Commands: []Command{CmdSearchClear}},
+		{Keys: "c", Description: "Toggle closed tasks", Context: ContextMain, Commands: []Command{CmdToggleClosed}},
+		{Keys: "q / Ctrl+C", Description: "Quit", Context: ContextMain, Commands: []Command{CmdQuit}},
 	}
 	for _, b := range actionBindings {
-		sb.WriteString(fmt.Sprintf("  %-20s %s\n", b.Keys, b.Description))
+		sb.WriteString(fmt.Sprintf("  %-20s %s\n", r.helpKeys(b), i18n.Text(b.Description)))
 	}
 
-	sb.WriteString("\nGETTING STARTED:\n")
+	sb.WriteString("\n" + i18n.T("GETTING STARTED:") + "\n")
 	gettingStartedBindings := []HelpBinding{
-		{Keys: "H", Description: "Open getting started guide"},
-		{Keys: "I", Description: "Install td instructions to agent file"},
+		{Keys: "H", Description: "Open getting started guide", Context: ContextMain, Commands: []Command{CmdOpenGettingStarted}},
+		{Keys: "I", Description: "Install td instructions to agent file", Context: ContextGettingStarted, Commands: []Command{CmdInstallInstructions}},
 	}
 	for _, b := range gettingStartedBindings {
-		sb.WriteString(fmt.Sprintf("  %-20s %s\n", b.Keys, b.Description))
+		sb.WriteString(fmt.Sprintf("  %-20s %s\n", r.helpKeys(b), i18n.Text(b.Description)))
 	}
 
-	sb.WriteString("\nHANDOFFS MODAL:\n")
+	sb.WriteString("\n" + i18n.T("HANDOFFS MODAL:") + "\n")
 	handoffBindings := []HelpBinding{
-		{Keys: "↑ / ↓ / j / k", Description: "Select handoff"},
-		{Keys: "Enter", Description: "Open issue for selected handoff"},
-		{Keys: "Esc", Description: "Close handoffs modal"},
-		{Keys: "r", Description: "Refresh handoffs"},
+		{Keys: "↑ / ↓ / j / k", Description: "Select handoff", Context: ContextHandoffs, Commands: []Command{CmdCursorUp, CmdCursorDown}},
+		{Keys: "Enter", Description: "Open issue for selected handoff", Context: ContextHandoffs, Commands: []Command{CmdOpenDetails}},
+		{Keys: "Esc", Description: "Close handoffs modal", Context: ContextHandoffs, Commands: []Command{CmdClose}},
+		{Keys: "r", Description: "Refresh handoffs", Context: ContextHandoffs, Commands: []Command{CmdRefresh}},
 	}
 	for _, b := range handoffBindings {
-		sb.WriteString(fmt.Sprintf("  %-20s %s\n", b.Keys, b.Description))
+		sb.WriteString(fmt.Sprintf("  %-20s %s\n", r.helpKeys(b), i18n.Text(b.Description)))
 	}
 
-	sb.WriteString("\nBOARDS:\n")
+	sb.WriteString("\n" + i18n.T("BOARDS:") + "\n")
 	boardBindings := []HelpBinding{
-		{Keys: "b", Description: "Open board picker"},
-		{Keys: "Enter", Description: "Select board"},
+		{Keys: "b", Description: "Open board picker", Context: ContextMain, Commands: []Command{CmdOpenBoardPicker}},
+		{Keys: "Enter", Description: "Select board", Context: ContextBoardPicker, Commands: []Command{CmdSelectBoard}},
 		{Keys: "Esc / q", Description: "Close picker / exit board"},
 		{Keys: "← / →", Description: "Switch columns (swimlanes)"},
-		{Keys: "J / K", Description: "Move issue down/up in column"},
-		{Keys: "Ctrl+J / Ctrl+K", Description: "Move issue to bottom/top"},
-		{Keys: "v", Description: "Toggle swimlanes/backlog view"},
-		{Keys: "c", Description: "Toggle closed issues"},
-		{Keys: "F", Description: "Cycle status filter"},
+		{Keys: "J / K", Description: "Move issue down/up in column", Context: ContextBoard, Commands: []Command{CmdMoveIssueDown, CmdMoveIssueUp}},
+		{Keys: "Ctrl+J / Ctrl+K", Description: "Move issue to bottom/top", Context: ContextBoard, Commands: []Command{CmdMoveIssueToBottom, CmdMoveIssueToTop}},
+		{Keys: "v", Description: "Toggle swimlanes/backlog view", Context: ContextBoard, Commands: []Command{CmdToggleBoardView}},
+		{Keys: "c", Description: "Toggle closed issues", Context: ContextBoard, Commands: []Command{CmdToggleBoardClosed}},
+		{Keys: "F", Description: "Cycle status filter", Context: ContextBoard, Commands: []Command{CmdCycleBoardStatusFilter}},
 	}
 	for _, b := range boardBindings {
-		sb.WriteString(fmt.Sprintf("  %-20s %s\n", b.Keys, b.Description))
+		sb.WriteString(fmt.Sprintf("  %-20s %s\n", r.helpKeys(b), i18n.Text(b.Description)))
 	}
 
-	sb.WriteString("\nSEARCH (TDQ Query Language):\n")
+	sb.WriteString("\n" + i18n.T("SEARCH (TDQ Query Language):") + "\n")
 	searchBindings := []HelpBinding{
-		{Keys: "Enter", Description: "Confirm search"},
-		{Keys: "Esc", Description: "Cancel search"},
+		{Keys: "Enter", Description: "Confirm search", Context: ContextSearch, Commands: []Command{CmdSearchConfirm}},
+		{Keys: "Esc", Description: "Cancel search", Context: ContextSearch, Commands: []Command{CmdSearchCancel}},
 		{Keys: "Backspace", Description: "Delete character"},
 		{Keys: "?", Description: "Show TDQ syntax help"},
 	}
 	for _, b := range searchBindings {
-		sb.WriteString(fmt.Sprintf("  %-20s %s\n", b.Keys, b.Description))
+		sb.WriteString(fmt.Sprintf("  %-20s %s\n", r.helpKeys(b), i18n.Text(b.Description)))
 	}
 
-	sb.WriteString("\nMOUSE:\n")
-	mouseBindings := []HelpBinding{
-		{Keys: "Click", Description: "Select panel/row"},
-		{Keys: "Double-click", Description: "Open issue details"},
-		{Keys: "Scroll wheel", Description: "Scroll hovered panel"},
-	}
-	for _, b := range mouseBindings {
-		sb.WriteString(fmt.Sprintf("  %-20s %s\n", b.Keys, b.Description))
+	if !r.keyboardOnly {
+		sb.WriteString("\n" + i18n.T("MOUSE:") + "\n")
+		mouseBindings := []HelpBinding{
+			{Keys: "Click", Description: "Select panel/row", Mouse: true},
+			{Keys: "Double-click", Description: "Open issue details", Mouse: true},
+			{Keys: "Scroll wheel", Description: "Scroll hovered panel", Mouse: true},
+			{Keys: "Drag divider", Description: "Resize panels", Mouse: true},
+		}
+		for _, b := range mouseBindings {
+			sb.WriteString(fmt.Sprintf("  %-20s %s\n", r.helpKeys(b), i18n.Text(b.Description)))
+		}
 	}
 
-	sb.WriteString("\nPress ? to close help\n")
+	sb.WriteString("\n" + i18n.T("Press ? to close help") + "\n")
 
 	return sb.String()
 }
 
+// SetKeyboardOnly leaves mouse actions out of the help, for a monitor
+// running without mouse reporting
+func (r *Registry) SetKeyboardOnly(on bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.keyboardOnly = on
+}
+
+// helpKeys returns the keys shown for a help entry: as written, unless
+// user overrides changed the keys of its commands. The caller holds r.mu.
+func (r *Registry) helpKeys(b HelpBinding) string {
+	if len(b.Commands) == 0 || len(r.userOverrides) == 0 {
+		return b.Keys
+	}
+	changed := false
+	var keys []string
+	for _, cmd := range b.Commands {
+		effective := r.keysFor(b.Context, cmd, true)
+		if strings.Join(effective, "\x00") != strings.Join(r.keysFor(b.Context, cmd, false), "\x00") {
+			changed = true
+		}
+		for _, k := range effective {
+			keys = append(keys, formatKey(k))
+		}
+	}
+	if !changed {
+		return b.Keys
+	}
+	if len(keys) == 0 {
+		return i18n.T("(no key)")
+	}
+	return strings.Join(keys, " / ")
+}
+
 // GenerateTDQHelp generates help text for TDQ query language
 func (r *Registry) GenerateTDQHelp() string {
 	var sb strings.Builder
@@ -299,23 +370,23 @@ func (r *Registry) GenerateTDQHelp() string {
 // FooterHelp generates a compact help string for the footer
 func (r *Registry) FooterHelp() string {
 	// Grouped: actions | view controls | search/nav
-	return "n:new e:edit x:del a:approve r:review  S:sort T:type c:closed b:boards  /:search s:stats tab:panel ?:help"
+	return i18n.T("n:new e:edit x:del a:approve r:review  S:sort T:type c:closed b:boards  /:search s:stats tab:panel ?:help")
 }
 
 // BoardFooterHelp generates help text for board mode footer
 func (r *Registry) BoardFooterHelp() string {
 	// Board-specific: v:view toggles swimlanes/backlog, F:filter cycles status
-	return "n:new e:edit x:del a:approve  v:view S:sort T:type F:filter c:closed b:boards  /:search s:stats tab:panel ?:help"
+	return i18n.T("n:new e:edit x:del a:approve  v:view S:sort T:type F:filter c:closed b:boards  /:search s:stats tab:panel ?:help")
 }
 
 // ModalFooterHelp generates help text for the modal footer
 func (r *Registry) ModalFooterHelp() string {
-	return "↑↓:scroll  ←→:prev/next  y:copy  esc:close  r:refresh"
+	return i18n.T("↑↓:scroll  ←→:prev/next  y:copy  esc:close  r:refresh")
 }
 
 // StatsFooterHelp generates help text for the stats modal footer
 func (r *Registry) StatsFooterHelp() string {
-	return "↑↓:scroll  Ctrl+d/u:½page  esc:close  r:refresh"
+	return i18n.T("↑↓:scroll  Ctrl+d/u:½page  esc:close  r:refresh")
 }
 
 // CommandHelp returns help info for a specific command
@@ -353,6 +424,14 @@ func CommandHelp(cmd Command) string {
 		return "Open statistics dashboard"
 	case CmdOpenHandoffs:
 		return "Open handoffs modal"
+	case CmdOpenReminders:
+		return "Open reminders modal"
+	case CmdCancelReminder:
+		return "Cancel the selected reminder"
+	case CmdOpenCapacity:
+		return "Open sprint capacity modal"
+	case CmdOpenTriageInbox:
+		return "Open triage inbox modal"
 	case CmdSearch:
 		return "Enter search mode"
 	case CmdToggleClosed:
@@ -387,6 +466,20 @@ func CommandHelp(cmd Command) string {
 		return "Close the selected issue"
 	case CmdReopenIssue:
 		return "Reopen a closed issue"
+	case CmdTogglePreview:
+		return "Show the selected issue in a preview pane beside the task list"
+	case CmdPreviewGrow:
+		return "Widen the preview pane"
+	case CmdPreviewShrink:
+		return "Narrow the preview pane"
+	case CmdPaneGrow:
+		return "Make the active panel taller"
+	case CmdPaneShrink:
+		return "Make the active panel shorter"
+	case CmdFilterSection:
+		return "Filter the section under the cursor with a TDQ expression"
+	case CmdOpenActionMenu:
+		return "Open actions menu: start, review, approve, block, comment"
 	case CmdOpenBoardPicker:
 		return "Open board picker to select a board"
 	case CmdSelectBoard:
@@ -477,7 +570,7 @@ func AllCommands() []Command {
 		CmdHalfPageDown, CmdHalfPageUp, CmdFullPageDown, CmdFullPageUp,
 		CmdScrollDown, CmdScrollUp, CmdSelect, CmdBack, CmdClose,
 		CmdNavigatePrev, CmdNavigateNext,
-		CmdOpenDetails, CmdOpenStats, CmdOpenHandoffs, CmdSearch, CmdToggleClosed, CmdCycleSortMode, CmdCycleTypeFilter,
+		CmdOpenDetails, CmdOpenStats, CmdOpenHandoffs, CmdOpenReminders, CmdCancelReminder, CmdOpenCapacity, CmdOpenTriageInbox, CmdSearch, CmdToggleClosed, CmdCycleSortMode, CmdCycleTypeFilter,
 		CmdMarkForReview, CmdApprove, CmdDelete, CmdConfirm, CmdCancel,
 		CmdSearchConfirm, CmdSearchCancel, CmdSearchClear, CmdSearchBackspace, CmdSearchInput,
 		CmdFocusTaskSection, CmdOpenEpicTask, CmdOpenParentEpic, CmdCopyToClipboard, CmdCopyIDToClipboard,
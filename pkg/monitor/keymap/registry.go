@@ -35,6 +35,12 @@ const (
 	ContextCloseConfirm      Context = "close-confirm"      // When close confirmation modal is open (has text input)
 	ContextSyncPrompt        Context = "td-sync-prompt"    // When sync prompt modal is open
 	ContextKanban            Context = "kanban"            // When kanban view modal is open
+	ContextActionMenu        Context = "action-menu"       // When the issue action menu is open
+	ContextSectionFilter     Context = "section-filter"    // When the section filter prompt is open
+	ContextApproveChecklist  Context = "approve-checklist" // When the approve modal with the review checklist is open
+	ContextTriageInbox       Context = "triage-inbox"      // When the triage inbox modal is open
+	ContextReminders         Context = "reminders"         // When reminders modal is open
+	ContextCapacity          Context = "capacity"          // When sprint capacity modal is open
 )
 
 // Command represents a named command that can be triggered by key bindings
@@ -99,6 +105,16 @@ const (
 	// Handoffs modal
 	CmdOpenHandoffs Command = "open-handoffs"
 
+	// Reminders modal
+	CmdOpenReminders  Command = "open-reminders"
+	CmdCancelReminder Command = "cancel-reminder"
+
+	// Sprint capacity modal
+	CmdOpenCapacity Command = "open-capacity"
+
+	// Triage inbox modal
+	CmdOpenTriageInbox Command = "open-triage-inbox"
+
 	// Clipboard
 	CmdCopyToClipboard   Command = "copy-to-clipboard"
 	CmdCopyIDToClipboard Command = "copy-id-to-clipboard"
@@ -112,11 +128,20 @@ const (
 	CmdFormOpenEditor   Command = "form-open-editor"
 
 	// Issue actions
-	CmdCloseIssue  Command = "close-issue"
-	CmdReopenIssue Command = "reopen-issue"
+	CmdCloseIssue     Command = "close-issue"
+	CmdReopenIssue    Command = "reopen-issue"
+	CmdOpenActionMenu Command = "open-action-menu"
 
 	// Filters
 	CmdCycleTypeFilter Command = "cycle-type-filter"
+	CmdFilterSection   Command = "filter-section"
+
+	// Preview pane
+	CmdTogglePreview Command = "toggle-preview"
+	CmdPreviewGrow   Command = "preview-grow"
+	CmdPreviewShrink Command = "preview-shrink"
+	CmdPaneGrow      Command = "pane-grow"
+	CmdPaneShrink    Command = "pane-shrink"
 
 	// Button navigation (for confirmation dialogs and forms)
 	CmdNextButton Command = "next-button"
@@ -157,16 +182,17 @@ const (
 
 // Binding maps a key or key sequence to a command in a specific context
 type Binding struct {
-	Key         string  // e.g., "tab", "ctrl+d", "g g"
-	Command     Command // Command ID
-	Context     Context // "global", "main", "modal", etc.
-	Description string  // Human-readable description for help text
+	Key         string  `json:"key"`         // e.g., "tab", "ctrl+d", "g g"
+	Command     Command `json:"command"`     // Command ID
+	Context     Context `json:"context"`     // "global", "main", "modal", etc.
+	Description string  `json:"description"` // Human-readable description for help text
 }
 
 // Registry manages key bindings and command dispatch
 type Registry struct {
 	bindings      map[Context][]Binding // context -> bindings
 	userOverrides map[string]Command    // "context:key" -> command
+	keyboardOnly  bool // help leaves out mouse actions
 	pendingKey    string
 	pendingTime   time.Time
 	mu            sync.RWMutex
@@ -3,6 +3,7 @@ package monitor
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"encoding/json"
 
@@ -579,6 +580,240 @@ func (m Model) openIssueFromHandoffs() (tea.Model, tea.Cmd) {
 	return m.pushModal(issueID, PanelCurrentWork)
 }
 
+// openRemindersModal opens the reminders modal and fetches data
+func (m Model) openRemindersModal() (tea.Model, tea.Cmd) {
+	m.RemindersOpen = true
+	m.RemindersCursor = 0
+	m.RemindersLoading = true
+	m.RemindersError = nil
+	m.RemindersData = nil
+	m.RemindersMouseHandler = mouse.NewHandler()
+	m.RemindersModal = m.createRemindersModal()
+	m.RemindersModal.Reset()
+
+	return m, m.fetchReminders()
+}
+
+// closeRemindersModal closes the reminders modal and clears state
+func (m *Model) closeRemindersModal() {
+	m.RemindersOpen = false
+	m.RemindersCursor = 0
+	m.RemindersLoading = false
+	m.RemindersError = nil
+	m.RemindersData = nil
+	m.RemindersModal = nil
+	m.RemindersMouseHandler = nil
+}
+
+// createRemindersModal builds the declarative modal for reminders. Unlike
+// handoffs it is rebuilt for loading, error and empty states too, so the
+// reminders modal never needs a legacy renderer.
+func (m *Model) createRemindersModal() *modal.Modal {
+	modalWidth := m.Width * 80 / 100
+	if modalWidth > 100 {
+		modalWidth = 100
+	}
+	if modalWidth < 50 {
+		modalWidth = 50
+	}
+
+	md := modal.New("Reminders",
+		modal.WithWidth(modalWidth),
+		modal.WithVariant(modal.VariantInfo),
+		modal.WithHints(false),
+	)
+
+	switch {
+	case m.RemindersLoading:
+		md.AddSection(modal.Text(subtleStyle.Render("Loading reminders...")))
+	case m.RemindersError != nil:
+		md.AddSection(modal.Text(errorStyle.Render(fmt.Sprintf("Error: %v", m.RemindersError))))
+	case len(m.RemindersData) == 0:
+		md.AddSection(modal.Text(subtleStyle.Render("No reminders. Set one with: td remind <id> in 3d \"message\"")))
+	default:
+		now := time.Now()
+		items := make([]modal.ListItem, 0, len(m.RemindersData))
+		for i, r := range m.RemindersData {
+			status := string(r.Status)
+			if r.Status == models.ReminderPending && !r.RemindAt.After(now) {
+				status = "due"
+			}
+			label := fmt.Sprintf("%s %-7s %s %s", r.RemindAt.Local().Format("01-02 15:04"), status, r.IssueID, r.Message)
+			items = append(items, modal.ListItem{
+				ID:    fmt.Sprintf("reminder-%d", i),
+				Label: label,
+				Data:  i,
+			})
+		}
+
+		modalHeight := m.Height * 80 / 100
+		if modalHeight > 40 {
+			modalHeight = 40
+		}
+		if modalHeight < 15 {
+			modalHeight = 15
+		}
+		maxVisible := modalHeight - 8
+		if maxVisible < 3 {
+			maxVisible = 3
+		}
+		if maxVisible > len(items) {
+			maxVisible = len(items)
+		}
+		md.AddSection(modal.List("reminders-list", items, &m.RemindersCursor, modal.WithMaxVisible(maxVisible)))
+	}
+
+	md.AddSection(modal.Spacer())
+	if len(m.RemindersData) > 0 {
+		md.AddSection(modal.Buttons(
+			modal.Btn(" Open Issue ", "open"),
+			modal.Btn(" Cancel Reminder ", "cancel-reminder"),
+			modal.Btn(" Close ", "close"),
+		))
+	} else {
+		md.AddSection(modal.Buttons(modal.Btn(" Close ", "close")))
+	}
+
+	return md
+}
+
+// openIssueFromReminders opens the issue detail modal for the selected reminder
+func (m Model) openIssueFromReminders() (tea.Model, tea.Cmd) {
+	if m.RemindersCursor >= len(m.RemindersData) {
+		return m, nil
+	}
+	issueID := m.RemindersData[m.RemindersCursor].IssueID
+	m.closeRemindersModal()
+	return m.pushModal(issueID, PanelCurrentWork)
+}
+
+// cancelSelectedReminder cancels the highlighted reminder if it is still pending
+func (m Model) cancelSelectedReminder() (tea.Model, tea.Cmd) {
+	if m.RemindersCursor >= len(m.RemindersData) {
+		return m, nil
+	}
+	r := m.RemindersData[m.RemindersCursor]
+	if err := m.DB.CancelReminder(r.ID); err != nil {
+		m.StatusMessage = fmt.Sprintf("Reminder %s already %s", r.ID, r.Status)
+		m.StatusIsError = true
+	} else {
+		m.StatusMessage = "CANCELLED reminder " + r.ID
+		m.StatusIsError = false
+	}
+	return m, tea.Batch(m.fetchReminders(), tea.Tick(3*time.Second, func(t time.Time) tea.Msg { return ClearStatusMsg{} }))
+}
+
+// openCapacityModal opens the sprint capacity modal and fetches data
+func (m Model) openCapacityModal() (tea.Model, tea.Cmd) {
+	m.CapacityOpen = true
+	m.CapacityCursor = 0
+	m.CapacityLoading = true
+	m.CapacityError = nil
+	m.CapacityData = nil
+	m.CapacityMouseHandler = mouse.NewHandler()
+	m.CapacityModal = m.createCapacityModal()
+	m.CapacityModal.Reset()
+
+	return m, m.fetchCapacity()
+}
+
+// closeCapacityModal closes the sprint capacity modal and clears state
+func (m *Model) closeCapacityModal() {
+	m.CapacityOpen = false
+	m.CapacityCursor = 0
+	m.CapacityLoading = false
+	m.CapacityError = nil
+	m.CapacityData = nil
+	m.CapacityModal = nil
+	m.CapacityMouseHandler = nil
+}
+
+// createCapacityModal builds the declarative modal for the current
+// sprint's capacity: one row per session, most loaded first
+func (m *Model) createCapacityModal() *modal.Modal {
+	modalWidth := m.Width * 80 / 100
+	if modalWidth > 100 {
+		modalWidth = 100
+	}
+	if modalWidth < 50 {
+		modalWidth = 50
+	}
+
+	title := "Sprint Capacity"
+	variant := modal.VariantInfo
+	if r := m.CapacityData; r != nil {
+		title = "Sprint Capacity: " + r.Sprint.Name
+		if r.OverCommitted {
+			variant = modal.VariantWarning
+		}
+	}
+	md := modal.New(title,
+		modal.WithWidth(modalWidth),
+		modal.WithVariant(variant),
+		modal.WithHints(false),
+	)
+
+	r := m.CapacityData
+	switch {
+	case m.CapacityLoading:
+		md.AddSection(modal.Text(subtleStyle.Render("Loading capacity...")))
+	case m.CapacityError != nil:
+		md.AddSection(modal.Text(errorStyle.Render(fmt.Sprintf("Error: %v", m.CapacityError))))
+	case r == nil:
+		md.AddSection(modal.Text(subtleStyle.Render("No sprints. Define one with: td sprint set <name> --start <date> --end <date>")))
+	default:
+		md.AddSection(modal.Text(fmt.Sprintf("%s to %s, %d of %d days left", r.Sprint.Start, r.Sprint.End, r.RemainingDays, r.Days)))
+		summary := fmt.Sprintf("Committed %d pts (%d unassigned) against %.1f pts of capacity, %d done", r.Committed, r.Unassigned, r.Capacity, r.Done)
+		if r.OverCommitted {
+			summary = errorStyle.Render("Over-committed: " + summary)
+		}
+		md.AddSection(modal.Text(summary))
+		if r.Unestimated > 0 {
+			md.AddSection(modal.Text(subtleStyle.Render(fmt.Sprintf("%d open issues have no points", r.Unestimated))))
+		}
+		md.AddSection(modal.Spacer())
+
+		if len(r.Sessions) == 0 {
+			md.AddSection(modal.Text(subtleStyle.Render("No session has sprint work or recent throughput")))
+			break
+		}
+		items := make([]modal.ListItem, 0, len(r.Sessions))
+		for i, l := range r.Sessions {
+			name := l.Session
+			if l.Name != "" {
+				name = l.Name
+			}
+			load := "    -"
+			if !l.NoHistory {
+				load = fmt.Sprintf("%4.0f%%", l.Load*100)
+			}
+			label := fmt.Sprintf("%-20s %3d pts / %5.1f cap  %s  %3d done", truncateString(name, 20), l.Committed, l.Capacity, load, l.Done)
+			if l.OverCommitted {
+				label = errorStyle.Render(label + "  over")
+			}
+			items = append(items, modal.ListItem{
+				ID:    fmt.Sprintf("capacity-%d", i),
+				Label: label,
+				Data:  i,
+			})
+		}
+
+		maxVisible := min(max(m.Height*80/100, 15), 40) - 12
+		if maxVisible < 3 {
+			maxVisible = 3
+		}
+		if maxVisible > len(items) {
+			maxVisible = len(items)
+		}
+		md.AddSection(modal.List("capacity-list", items, &m.CapacityCursor, modal.WithMaxVisible(maxVisible)))
+		md.AddSection(modal.Text(subtleStyle.Render(fmt.Sprintf("Throughput from issues closed in the last %d days", r.WindowDays))))
+	}
+
+	md.AddSection(modal.Spacer())
+	md.AddSection(modal.Buttons(modal.Btn(" Close ", "close")))
+	return md
+}
+
 // openBoardPickerModal opens the board picker modal and fetches data
 func (m Model) openBoardPickerModal() (Model, tea.Cmd) {
 	m.BoardPickerOpen = true
@@ -0,0 +1,173 @@
+package modal
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// choiceSection picks one of a few options: a vertical radio group or a
+// one-line segmented control. The group is a single Tab stop; arrow keys
+// cycle the selection and each option is clickable.
+type choiceSection struct {
+	id        string
+	options   []string
+	selected  *int
+	segmented bool
+	variant   Variant
+}
+
+// RadioGroup creates a radio group section, one option per line.
+// selected points to the selected option's index.
+func RadioGroup(id string, options []string, selected *int) Section {
+	return &choiceSection{id: id, options: options, selected: selected}
+}
+
+// Segmented creates a compact segmented control section: the options side
+// by side on one line, wrapping when they don't fit, the selected one
+// highlighted.
+// selected points to the selected option's index.
+func Segmented(id string, options []string, selected *int) Section {
+	return &choiceSection{id: id, options: options, selected: selected, segmented: true}
+}
+
+// OptionID returns the hit region ID of a radio group or segmented
+// control's option, for hover styling and tests.
+func OptionID(id string, index int) string {
+	return fmt.Sprintf("%s:%d", id, index)
+}
+
+func (c *choiceSection) setVariant(v Variant) {
+	c.variant = v
+}
+
+func (c *choiceSection) selectedIndex() int {
+	if c.selected == nil {
+		return -1
+	}
+	return *c.selected
+}
+
+func (c *choiceSection) Render(contentWidth int, focusID, hoverID string) RenderedSection {
+	if len(c.options) == 0 {
+		return RenderedSection{Content: MutedText.Render("(no options)")}
+	}
+	if c.segmented {
+		return c.renderSegmented(contentWidth, focusID, hoverID)
+	}
+	return c.renderRadio(focusID, hoverID)
+}
+
+func (c *choiceSection) renderRadio(focusID, hoverID string) RenderedSection {
+	accent := variantColor(c.variant)
+	isFocused := focusID == c.id
+
+	lines := make([]string, len(c.options))
+	clickables := make([]ClickableInfo, len(c.options))
+	width := 0
+	for i, label := range c.options {
+		optID := OptionID(c.id, i)
+		isSelected := i == c.selectedIndex()
+
+		marker := "( ) "
+		style := ListItemNormal
+		switch {
+		case isSelected && isFocused:
+			marker = "(•) "
+			style = ListItemFocused.Foreground(accent)
+		case isSelected:
+			marker = "(•) "
+			style = ListItemNormal.Foreground(accent)
+		case optID == hoverID:
+			style = ListItemSelected
+		}
+		if isSelected {
+			marker = lipgloss.NewStyle().Foreground(accent).Bold(true).Render(marker)
+		}
+
+		lines[i] = marker + style.Render(label)
+		w := ansi.StringWidth(lines[i])
+		width = max(width, w)
+		clickables[i] = ClickableInfo{ID: optID, FocusID: c.id, OffsetY: i, Width: w, Height: 1}
+	}
+
+	return RenderedSection{
+		Content:    strings.Join(lines, "\n"),
+		Focusables: []FocusableInfo{{ID: c.id, Width: width, Height: len(lines)}},
+		Clickables: clickables,
+	}
+}
+
+func (c *choiceSection) renderSegmented(contentWidth int, focusID, hoverID string) RenderedSection {
+	accent := variantColor(c.variant)
+	isFocused := focusID == c.id
+
+	var rows []string
+	var sb strings.Builder
+	clickables := make([]ClickableInfo, len(c.options))
+	x, width := 0, 0
+	for i, label := range c.options {
+		optID := OptionID(c.id, i)
+
+		var rendered string
+		switch {
+		case i == c.selectedIndex():
+			rendered = SegmentSelected.Background(accent).Bold(isFocused).Render("[" + label + "]")
+		case optID == hoverID:
+			rendered = SegmentHover.Render(label)
+		default:
+			rendered = Segment.Render(label)
+		}
+
+		w := ansi.StringWidth(rendered)
+		if x > 0 && x+w > contentWidth {
+			rows = append(rows, sb.String())
+			sb.Reset()
+			x = 0
+		}
+		sb.WriteString(rendered)
+		clickables[i] = ClickableInfo{ID: optID, FocusID: c.id, OffsetX: x, OffsetY: len(rows), Width: w, Height: 1}
+		x += w
+		width = max(width, x)
+	}
+	rows = append(rows, sb.String())
+
+	return RenderedSection{
+		Content:    strings.Join(rows, "\n"),
+		Focusables: []FocusableInfo{{ID: c.id, Width: width, Height: len(rows)}},
+		Clickables: clickables,
+	}
+}
+
+func (c *choiceSection) Update(msg tea.Msg, focusID string) (string, tea.Cmd) {
+	if focusID != c.id || c.selected == nil || len(c.options) == 0 {
+		return "", nil
+	}
+
+	switch msg := msg.(type) {
+	case ClickMsg:
+		for i := range c.options {
+			if OptionID(c.id, i) == msg.ID {
+				*c.selected = i
+				return c.id, nil
+			}
+		}
+
+	case tea.KeyMsg:
+		n := len(c.options)
+		switch msg.String() {
+		case "left", "up", "h", "k":
+			*c.selected = (*c.selected - 1 + n) % n
+		case "right", "down", "l", "j", " ":
+			*c.selected = (*c.selected + 1) % n
+		case "home":
+			*c.selected = 0
+		case "end":
+			*c.selected = n - 1
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,113 @@
+package modal_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/marcus/td/pkg/monitor/modal"
+	"github.com/marcus/td/pkg/monitor/modal/modaltest"
+)
+
+var issueTypes = []string{"task", "bug", "feature", "epic"}
+
+func TestRadioGroupKeys(t *testing.T) {
+	selected := 0
+	m := modal.New("Type").
+		AddSection(modal.RadioGroup("type", issueTypes, &selected)).
+		AddSection(modal.Buttons(modal.Btn(" Save ", "save")))
+	h := modaltest.New(t, m, 80, 24)
+
+	if !strings.Contains(h.Screen(), "(•) task") || !strings.Contains(h.Screen(), "( ) bug") {
+		t.Fatalf("screen:\n%s", h.Screen())
+	}
+
+	h.Press("down", "down")
+	if selected != 2 {
+		t.Errorf("after down, down: selected = %d, want 2", selected)
+	}
+	h.Press("up", "up", "up")
+	if selected != 3 {
+		t.Errorf("up from the first option should wrap to the last: selected = %d", selected)
+	}
+	if !strings.Contains(h.Screen(), "(•) epic") {
+		t.Errorf("screen not updated:\n%s", h.Screen())
+	}
+
+	// The group is one Tab stop
+	h.Press("tab")
+	if got := h.Modal.FocusedID(); got != "save" {
+		t.Errorf("after tab: focus = %q, want save", got)
+	}
+}
+
+func TestRadioGroupMouse(t *testing.T) {
+	selected := 0
+	m := modal.New("Type").
+		AddSection(modal.Buttons(modal.Btn(" Save ", "save"))).
+		AddSection(modal.RadioGroup("type", issueTypes, &selected))
+	h := modaltest.New(t, m, 80, 24)
+
+	for i, label := range issueTypes {
+		h.AssertRegionText(modal.OptionID("type", i), label)
+	}
+
+	if got := h.ClickRegion(modal.OptionID("type", 1)); got != "type" {
+		t.Errorf("click bug = %q, want type", got)
+	}
+	if selected != 1 || h.Modal.FocusedID() != "type" {
+		t.Errorf("after click: selected = %d, focus %q", selected, h.Modal.FocusedID())
+	}
+
+	h.HoverRegion(modal.OptionID("type", 3))
+	if got := h.Modal.HoveredID(); got != modal.OptionID("type", 3) {
+		t.Errorf("hovered = %q", got)
+	}
+}
+
+func TestSegmented(t *testing.T) {
+	priorities := []string{"P0", "P1", "P2", "P3", "P4"}
+	selected := 2
+	m := modal.New("Priority", modal.WithVariant(modal.VariantWarning)).
+		AddSection(modal.Segmented("priority", priorities, &selected))
+	h := modaltest.New(t, m, 80, 24)
+
+	if !strings.Contains(h.Screen(), " P0  P1 [P2] P3  P4 ") {
+		t.Fatalf("screen:\n%s", h.Screen())
+	}
+	for i, label := range priorities {
+		h.AssertRegionText(modal.OptionID("priority", i), label)
+	}
+
+	h.Press("right", "right", "right")
+	if selected != 0 {
+		t.Errorf("right past the last segment should wrap: selected = %d", selected)
+	}
+	h.Press("left")
+	if selected != 4 {
+		t.Errorf("left from the first segment should wrap: selected = %d", selected)
+	}
+
+	if got := h.ClickRegion(modal.OptionID("priority", 1)); got != "priority" || selected != 1 {
+		t.Errorf("click P1 = %q, selected %d", got, selected)
+	}
+	if !strings.Contains(h.Screen(), "[P1]") {
+		t.Errorf("screen:\n%s", h.Screen())
+	}
+}
+
+func TestChoiceInsideWhen(t *testing.T) {
+	selected := 0
+	show := true
+	m := modal.New("Type").
+		AddSection(modal.When(func() bool { return show }, modal.Segmented("type", issueTypes, &selected)))
+	h := modaltest.New(t, m, 80, 24)
+
+	h.ClickRegion(modal.OptionID("type", 3))
+	if selected != 3 {
+		t.Errorf("selected = %d, want 3", selected)
+	}
+
+	show = false
+	h.Render()
+	h.AssertNoRegion(modal.OptionID("type", 0))
+}
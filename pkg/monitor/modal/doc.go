@@ -37,16 +37,34 @@
 //   - Input(id string, model *textinput.Model, opts...) - text input
 //   - Textarea(id string, model *textarea.Model, height int, opts...) - multiline
 //   - List(id string, items []ListItem, selectedIdx *int, opts...) - scrollable list
+//   - RadioGroup(id string, options []string, selected *int) - one option per line
+//   - Segmented(id string, options []string, selected *int) - options on one line
 //   - When(condition func() bool, section) - conditional rendering
 //   - Custom(renderFn, updateFn) - escape hatch for complex content
 //
 // # Options
 //
 //   - WithWidth(w int) - set modal width (default: 50)
+//   - WithAutoSize() - size to content, within the min and max widths
+//   - WithMinWidth(w int), WithMaxWidth(w int) - width constraints
+//   - WithMaxHeight(h int) - height limit; longer content scrolls
 //   - WithVariant(v Variant) - set visual style (Default, Danger, Warning, Info)
 //   - WithHints(show bool) - show/hide keyboard hints at bottom
 //   - WithPrimaryAction(actionID string) - action for implicit Enter submit
 //   - WithCloseOnBackdropClick(close bool) - close on backdrop click
 //
+// # Toasts
+//
+// Toasts is a stack of transient success, info and error notifications for
+// feedback that doesn't need a modal. Push from Update, return the command
+// it gives back, and pass ToastExpiredMsg to Toasts.Update; toasts dismiss
+// themselves and extras wait in a queue until there is room.
+//
+// # Testing
+//
+// Package modaltest renders modals at a fixed screen size, drives them
+// with key and mouse events, and checks hit regions against the text drawn
+// under them.
+//
 // See the package-level documentation for detailed integration guides.
 package modal
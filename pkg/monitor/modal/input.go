@@ -138,6 +138,8 @@ func (s *inputSection) Render(contentWidth int, focusID, hoverID string) Rendere
 	}
 }
 
+func (s *inputSection) fillsWidth() bool { return true }
+
 func (s *inputSection) Update(msg tea.Msg, focusID string) (string, tea.Cmd) {
 	if s.id != focusID || s.model == nil {
 		return "", nil
@@ -281,6 +283,8 @@ func (s *textareaSection) Render(contentWidth int, focusID, hoverID string) Rend
 	}
 }
 
+func (s *textareaSection) fillsWidth() bool { return true }
+
 func (s *textareaSection) Update(msg tea.Msg, focusID string) (string, tea.Cmd) {
 	if s.id != focusID || s.model == nil {
 		return "", nil
@@ -13,20 +13,12 @@ type renderedSection struct {
 	content    string
 	height     int
 	focusables []FocusableInfo
+	clickables []ClickableInfo
 }
 
 // buildLayout renders all sections, measures heights, and registers hit regions.
 func (m *Modal) buildLayout(screenW, screenH int, handler *mouse.Handler) string {
-	// Clamp modal width
-	maxWidth := screenW - 4
-	if maxWidth < 1 {
-		maxWidth = 1
-	}
-	minWidth := MinModalWidth
-	if maxWidth < minWidth {
-		minWidth = maxWidth
-	}
-	modalWidth := clamp(m.width, minWidth, maxWidth)
+	modalWidth := m.resolveWidth(screenW)
 	contentWidth := modalWidth - ModalPadding // border(2) + padding(4)
 	if contentWidth < 1 {
 		contentWidth = 1
@@ -36,6 +28,7 @@ func (m *Modal) buildLayout(screenW, screenH int, handler *mouse.Handler) string
 	focusID := m.currentFocusID()
 	rendered := make([]renderedSection, 0, len(m.sections))
 	m.focusIDs = m.focusIDs[:0] // Reset focusable IDs
+	m.clickTargets = make(map[string]string)
 
 	for _, s := range m.sections {
 		res := s.Render(contentWidth, focusID, m.hoverID)
@@ -45,12 +38,16 @@ func (m *Modal) buildLayout(screenW, screenH int, handler *mouse.Handler) string
 			content:    res.Content,
 			height:     height,
 			focusables: res.Focusables,
+			clickables: res.Clickables,
 		})
 
 		// Collect focusable IDs in order
 		for _, f := range res.Focusables {
 			m.focusIDs = append(m.focusIDs, f.ID)
 		}
+		for _, c := range res.Clickables {
+			m.clickTargets[c.ID] = c.FocusID
+		}
 	}
 
 	// Ensure focusIdx is valid
@@ -79,6 +76,9 @@ func (m *Modal) buildLayout(screenW, screenH int, handler *mouse.Handler) string
 	actualContentHeight := totalContentHeight
 
 	modalInnerHeight := desiredModalInnerHeight(screenH)
+	if m.maxHeight > 0 {
+		modalInnerHeight = max(1, min(modalInnerHeight, m.maxHeight-ModalChrome))
+	}
 	headerLines := 0
 	if m.title != "" {
 		headerLines = 2 // title + blank line
@@ -149,6 +149,13 @@ func (m *Modal) buildLayout(screenW, screenH int, handler *mouse.Handler) string
 					handler.HitMap.AddRect(f.ID, absX, absY, f.Width, f.Height, f.ID)
 				}
 			}
+			// Clickables go on top of the focusable they belong to
+			for _, c := range r.clickables {
+				absY := contentY + sectionStartY + c.OffsetY - m.scrollOffset
+				if intersectsViewport(absY, c.Height, contentY, viewportHeight) {
+					handler.HitMap.AddRect(c.ID, contentX+c.OffsetX, absY, c.Width, c.Height, c.ID)
+				}
+			}
 			sectionStartY += r.height
 		}
 	}
@@ -156,26 +163,75 @@ func (m *Modal) buildLayout(screenW, screenH int, handler *mouse.Handler) string
 	return styled
 }
 
-// modalStyle returns the lipgloss style for the modal box based on variant.
-func (m *Modal) modalStyle(width int) lipgloss.Style {
-	borderColor := Primary
-	switch m.variant {
-	case VariantDanger:
-		borderColor = Error
-	case VariantWarning:
-		borderColor = Warning
-	case VariantInfo:
-		borderColor = Info
+// resolveWidth picks the modal width for a screen: the fixed width, or the
+// content's natural width when auto-sized, kept within the min and max
+// widths and a margin of the screen.
+func (m *Modal) resolveWidth(screenW int) int {
+	maxWidth := max(1, screenW-4)
+	switch {
+	case m.maxWidth > 0:
+		maxWidth = min(maxWidth, m.maxWidth)
+	case m.autoSize:
+		maxWidth = min(maxWidth, MaxModalWidth)
+	}
+	minWidth := MinModalWidth
+	if m.minWidth > 0 {
+		minWidth = m.minWidth
 	}
+	minWidth = min(minWidth, maxWidth)
 
+	width := m.width
+	if m.autoSize {
+		width = m.naturalWidth(max(1, maxWidth-ModalPadding)) + ModalPadding
+	}
+	return clamp(width, minWidth, maxWidth)
+}
+
+// naturalWidth measures the widest line of content rendered at most
+// maxContentWidth wide. Sections that fill the width they are given don't
+// count.
+func (m *Modal) naturalWidth(maxContentWidth int) int {
+	width := 0
+	if m.title != "" {
+		width = lipgloss.Width(renderTitleLine(m.title, m.variant))
+	}
+	if m.showHints {
+		width = max(width, lipgloss.Width(renderHintLine()))
+	}
+	focusID := m.currentFocusID()
+	for _, s := range m.sections {
+		if f, ok := s.(widthFiller); ok && f.fillsWidth() {
+			continue
+		}
+		width = max(width, lipgloss.Width(s.Render(maxContentWidth, focusID, m.hoverID).Content))
+	}
+	return min(width, maxContentWidth)
+}
+
+// modalStyle returns the lipgloss style for the modal box based on variant.
+func (m *Modal) modalStyle(width int) lipgloss.Style {
 	return lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(borderColor).
+		BorderForeground(variantColor(m.variant)).
 		Background(BgSecondary).
 		Padding(1, 2).
 		Width(width)
 }
 
+// variantColor returns the accent color of a variant: the border color,
+// and the color of selected choices.
+func variantColor(v Variant) lipgloss.Color {
+	switch v {
+	case VariantDanger:
+		return Error
+	case VariantWarning:
+		return Warning
+	case VariantInfo:
+		return Info
+	}
+	return Primary
+}
+
 // renderTitleLine renders the modal title.
 func renderTitleLine(title string, variant Variant) string {
 	titleStyle := ModalTitle
@@ -0,0 +1,141 @@
+package modal_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textinput"
+
+	"github.com/marcus/td/pkg/monitor/modal"
+	"github.com/marcus/td/pkg/monitor/modal/modaltest"
+)
+
+func TestAutoSize(t *testing.T) {
+	long := strings.Repeat("word ", 60)
+	name := textinput.New()
+
+	tests := []struct {
+		name  string
+		modal *modal.Modal
+		want  int
+	}{
+		{
+			name: "short content gets the min width",
+			modal: modal.New("Delete?", modal.WithAutoSize(), modal.WithHints(false)).
+				AddSection(modal.Text("Sure?")).
+				AddSection(modal.Buttons(modal.Btn(" Yes ", "yes"), modal.Btn(" No ", "no"))),
+			want: modal.MinModalWidth,
+		},
+		{
+			name: "content wider than the min width",
+			modal: modal.New("Delete?", modal.WithAutoSize(), modal.WithHints(false)).
+				AddSection(modal.Text(strings.Repeat("x", 40))),
+			want: 40 + modal.ModalPadding,
+		},
+		{
+			name: "long text stops at the max width",
+			modal: modal.New("Notes", modal.WithAutoSize(), modal.WithHints(false)).
+				AddSection(modal.Text(long)),
+			want: modal.MaxModalWidth,
+		},
+		{
+			name: "explicit max width",
+			modal: modal.New("Notes", modal.WithAutoSize(), modal.WithMaxWidth(60), modal.WithHints(false)).
+				AddSection(modal.Text(long)),
+			want: 60,
+		},
+		{
+			name: "explicit min width",
+			modal: modal.New("Delete?", modal.WithAutoSize(), modal.WithMinWidth(44), modal.WithHints(false)).
+				AddSection(modal.Text("Sure?")),
+			want: 44,
+		},
+		{
+			name: "inputs don't stretch the modal",
+			modal: modal.New("Rename", modal.WithAutoSize(), modal.WithHints(false)).
+				AddSection(modal.InputWithLabel("name", "Name:", &name)),
+			want: modal.MinModalWidth,
+		},
+		{
+			name: "fixed width ignores content",
+			modal: modal.New("Notes", modal.WithWidth(50), modal.WithHints(false)).
+				AddSection(modal.Text(long)),
+			want: 50,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := modaltest.New(t, tt.modal, 200, 40)
+			if got := h.AssertRegion("modal-body").W; got != tt.want {
+				t.Errorf("width = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNarrowScreenWrapsButtons(t *testing.T) {
+	m := modal.New("Delete board?", modal.WithWidth(50), modal.WithHints(false)).
+		AddSection(modal.Text("This cannot be undone.")).
+		AddSection(modal.Buttons(
+			modal.Btn(" Delete ", "delete", modal.BtnDanger()),
+			modal.Btn(" Archive ", "archive"),
+			modal.Btn(" Cancel ", "cancel"),
+		))
+	h := modaltest.New(t, m, 30, 24)
+
+	for i, line := range strings.Split(h.Screen(), "\n") {
+		if len([]rune(line)) > 30 {
+			t.Errorf("row %d is wider than the screen: %q", i, line)
+		}
+	}
+	del := h.AssertRegion("delete")
+	cancel := h.AssertRegion("cancel")
+	if cancel.Y <= del.Y {
+		t.Errorf("buttons did not wrap: delete at row %d, cancel at row %d", del.Y, cancel.Y)
+	}
+	h.AssertRegionText("delete", "Delete")
+	h.AssertRegionText("archive", "Archive")
+	h.AssertRegionText("cancel", "Cancel")
+	if got := h.ClickRegion("cancel"); got != "cancel" {
+		t.Errorf("click cancel = %q", got)
+	}
+}
+
+func TestNarrowScreenWrapsSegments(t *testing.T) {
+	options := []string{"backlog", "open", "in progress", "in review", "closed"}
+	selected := 4
+	m := modal.New("Status", modal.WithHints(false)).
+		AddSection(modal.Segmented("status", options, &selected))
+	h := modaltest.New(t, m, 34, 24)
+
+	first := h.AssertRegion(modal.OptionID("status", 0))
+	last := h.AssertRegion(modal.OptionID("status", 4))
+	if last.Y <= first.Y {
+		t.Fatalf("segments did not wrap:\n%s", h.Screen())
+	}
+	for i, label := range options {
+		h.AssertRegionText(modal.OptionID("status", i), label)
+	}
+	if h.ClickRegion(modal.OptionID("status", 3)); selected != 3 {
+		t.Errorf("selected = %d after clicking a wrapped segment, want 3", selected)
+	}
+}
+
+func TestMaxHeight(t *testing.T) {
+	m := modal.New("Long", modal.WithMaxHeight(12), modal.WithHints(false))
+	for i := 0; i < 20; i++ {
+		m.AddSection(modal.Text(fmt.Sprintf("Line %d", i)))
+	}
+	m.AddSection(modal.Buttons(modal.Btn(" OK ", "ok")))
+	h := modaltest.New(t, m, 80, 40)
+
+	// The max height includes the border
+	if got := h.AssertRegion("modal-body").H; got != 12 {
+		t.Errorf("height = %d, want 12", got)
+	}
+	h.AssertNoRegion("ok")
+	if strings.Contains(h.Screen(), "Line 19") {
+		t.Errorf("content past the max height is drawn:\n%s", h.Screen())
+	}
+}
@@ -49,6 +49,8 @@ func WithMaxVisible(n int) ListOption {
 	}
 }
 
+func (s *listSection) fillsWidth() bool { return true }
+
 func (s *listSection) Render(contentWidth int, focusID, hoverID string) RenderedSection {
 	if len(s.items) == 0 {
 		return RenderedSection{Content: MutedText.Render("(no items)")}
@@ -11,16 +11,21 @@ type Modal struct {
 	title           string
 	variant         Variant
 	width           int
+	autoSize        bool
+	minWidth        int // 0 = MinModalWidth
+	maxWidth        int // 0 = no limit (MaxModalWidth when auto-sized)
+	maxHeight       int // 0 = no limit
 	sections        []Section
 	showHints       bool
 	primaryAction   string
 	closeOnBackdrop bool
 
 	// State (managed internally)
-	focusIdx     int      // Current focused element index in focusIDs
-	hoverID      string   // Currently hovered element ID
-	focusIDs     []string // Ordered list of focusable IDs (built during Render)
-	scrollOffset int      // Content scroll position in lines
+	focusIdx     int               // Current focused element index in focusIDs
+	hoverID      string            // Currently hovered element ID
+	focusIDs     []string          // Ordered list of focusable IDs (built during Render)
+	clickTargets map[string]string // Clickable ID -> focusable ID it belongs to (built during Render)
+	scrollOffset int               // Content scroll position in lines
 }
 
 // New creates a new Modal with the given title and options.
@@ -40,6 +45,9 @@ func New(title string, opts ...Option) *Modal {
 
 // AddSection adds a section to the modal. Returns the modal for chaining.
 func (m *Modal) AddSection(s Section) *Modal {
+	if vs, ok := s.(variantSetter); ok {
+		vs.setVariant(m.variant)
+	}
 	m.sections = append(m.sections, s)
 	return m
 }
@@ -124,6 +132,18 @@ func (m *Modal) HandleMouse(msg tea.MouseMsg, handler *mouse.Handler) string {
 				return id
 			}
 		}
+
+		// Click on a target inside one - focus its element and let the
+		// section handle the click, defaulting to the element's ID
+		if focusID, ok := m.clickTargets[id]; ok {
+			m.SetFocus(focusID)
+			for _, section := range m.sections {
+				if action, _ := section.Update(ClickMsg{ID: id}, focusID); action != "" {
+					return action
+				}
+			}
+			return focusID
+		}
 		return ""
 
 	case mouse.ActionHover:
@@ -0,0 +1,262 @@
+// Package modaltest drives modal dialogs in tests the way a terminal would.
+//
+// A Harness renders a modal on a screen of fixed size, centered as the
+// monitor's overlay draws it, sends key and mouse events through the
+// modal's own handlers, and checks the hit regions the modal registers
+// against the text drawn under them:
+//
+//	h := modaltest.New(t, m, 80, 24)
+//	h.AssertRegionText("delete", "Delete")
+//	if action := h.ClickRegion("delete"); action != "delete" {
+//	    t.Errorf("click = %q", action)
+//	}
+//	if action := h.Press("tab", "enter"); action != "cancel" {
+//	    t.Errorf("tab, enter = %q", action)
+//	}
+//
+// Screens are plain text: colors and other escape sequences are stripped,
+// so snapshots don't depend on the terminal's color profile.
+package modaltest
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/marcus/td/pkg/monitor/modal"
+	"github.com/marcus/td/pkg/monitor/mouse"
+)
+
+// RenderToString renders m on a width by height screen and returns the
+// screen as plain text, one line per row with trailing spaces trimmed.
+func RenderToString(m *modal.Modal, width, height int) string {
+	return joinScreen(compose(m.Render(width, height, mouse.NewHandler()), width, height))
+}
+
+// Harness renders a modal at a fixed size and feeds it events. Every event
+// is followed by a render, as a Bubble Tea program calls View after Update,
+// so the screen and hit regions always reflect the modal's current state.
+type Harness struct {
+	Modal  *modal.Modal
+	Width  int
+	Height int
+	Mouse  *mouse.Handler
+
+	t      testing.TB
+	screen []string
+}
+
+// New renders m on a width by height screen and returns a harness for it.
+func New(t testing.TB, m *modal.Modal, width, height int) *Harness {
+	t.Helper()
+	h := &Harness{Modal: m, Width: width, Height: height, Mouse: mouse.NewHandler(), t: t}
+	h.Render()
+	return h
+}
+
+// Render renders the modal again and returns the screen as plain text.
+func (h *Harness) Render() string {
+	h.screen = compose(h.Modal.Render(h.Width, h.Height, h.Mouse), h.Width, h.Height)
+	return joinScreen(h.screen)
+}
+
+// Screen returns the last rendered screen as plain text.
+func (h *Harness) Screen() string {
+	return joinScreen(h.screen)
+}
+
+// Key builds the key message a terminal sends for name, written as
+// tea.KeyMsg.String() prints it: "enter", "esc", "tab", "shift+tab",
+// "up", "ctrl+s", "alt+x", "space", or the typed text itself.
+func Key(name string) tea.KeyMsg {
+	alt := false
+	if rest, ok := strings.CutPrefix(name, "alt+"); ok && rest != "" {
+		alt, name = true, rest
+	}
+	if name == "space" {
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}, Alt: alt}
+	}
+	if t, ok := keyTypes[name]; ok {
+		return tea.KeyMsg{Type: t, Alt: alt}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(name), Alt: alt}
+}
+
+// keyTypes maps key names to their types
+var keyTypes = func() map[string]tea.KeyType {
+	types := map[string]tea.KeyType{}
+	for t := tea.KeyType(-512); t < 128; t++ {
+		if name := t.String(); name != "" && name != " " {
+			if _, seen := types[name]; !seen {
+				types[name] = t
+			}
+		}
+	}
+	return types
+}()
+
+// Press sends each key to the modal in turn and returns the action the
+// last one produced, "" if none.
+func (h *Harness) Press(keys ...string) string {
+	action := ""
+	for _, k := range keys {
+		action, _ = h.Modal.HandleKey(Key(k))
+		h.Render()
+	}
+	return action
+}
+
+// Type sends text one character at a time, as typing it would.
+func (h *Harness) Type(text string) {
+	for _, r := range text {
+		h.Modal.HandleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	h.Render()
+}
+
+// Click presses the left button at x, y and returns the modal's action.
+// Each click is a single click: clicks in quick succession are not turned
+// into double clicks.
+func (h *Harness) Click(x, y int) string {
+	h.Mouse = &mouse.Handler{HitMap: h.Mouse.HitMap}
+	return h.mouse(tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+}
+
+// ClickRegion clicks the middle of the region with id, failing the test
+// if the modal registered no such region.
+func (h *Harness) ClickRegion(id string) string {
+	h.t.Helper()
+	x, y := center(h.AssertRegion(id))
+	return h.Click(x, y)
+}
+
+// Hover moves the pointer to x, y.
+func (h *Harness) Hover(x, y int) {
+	h.mouse(tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionMotion, Button: tea.MouseButtonNone})
+}
+
+// HoverRegion moves the pointer to the middle of the region with id.
+func (h *Harness) HoverRegion(id string) {
+	h.t.Helper()
+	x, y := center(h.AssertRegion(id))
+	h.Hover(x, y)
+}
+
+// WheelUp turns the mouse wheel up once at x, y.
+func (h *Harness) WheelUp(x, y int) {
+	h.mouse(tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionPress, Button: tea.MouseButtonWheelUp})
+}
+
+// WheelDown turns the mouse wheel down once at x, y.
+func (h *Harness) WheelDown(x, y int) {
+	h.mouse(tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionPress, Button: tea.MouseButtonWheelDown})
+}
+
+func (h *Harness) mouse(msg tea.MouseMsg) string {
+	action := h.Modal.HandleMouse(msg, h.Mouse)
+	h.Render()
+	return action
+}
+
+// Regions returns the hit regions registered by the last render, lowest
+// priority first.
+func (h *Harness) Regions() []mouse.Region {
+	return h.Mouse.HitMap.Regions()
+}
+
+// Region returns the rectangle of the region with id.
+func (h *Harness) Region(id string) (mouse.Rect, bool) {
+	for _, r := range h.Regions() {
+		if r.ID == id {
+			return r.Rect, true
+		}
+	}
+	return mouse.Rect{}, false
+}
+
+// AssertRegion fails the test unless the last render registered a region
+// with id, and returns its rectangle.
+func (h *Harness) AssertRegion(id string) mouse.Rect {
+	h.t.Helper()
+	rect, ok := h.Region(id)
+	if !ok {
+		var ids []string
+		for _, r := range h.Regions() {
+			ids = append(ids, r.ID)
+		}
+		h.t.Fatalf("no hit region %q; have %s", id, strings.Join(ids, ", "))
+	}
+	return rect
+}
+
+// AssertNoRegion fails the test if the last render registered a region
+// with id, as it shouldn't for elements scrolled out of view.
+func (h *Harness) AssertNoRegion(id string) {
+	h.t.Helper()
+	if rect, ok := h.Region(id); ok {
+		h.t.Errorf("unexpected hit region %q at %+v", id, rect)
+	}
+}
+
+// RegionText returns the text drawn under the region with id, one line
+// per row of the region.
+func (h *Harness) RegionText(id string) string {
+	h.t.Helper()
+	rect := h.AssertRegion(id)
+	rows := make([]string, 0, rect.H)
+	for y := rect.Y; y < rect.Y+rect.H; y++ {
+		line := ""
+		if y >= 0 && y < len(h.screen) {
+			line = ansi.Cut(h.screen[y], rect.X, rect.X+rect.W)
+		}
+		rows = append(rows, line)
+	}
+	return strings.Join(rows, "\n")
+}
+
+// AssertRegionText fails the test unless the text drawn under the region
+// with id contains want, which catches hit regions that are off by a row
+// or column from what they are meant to cover.
+func (h *Harness) AssertRegionText(id, want string) {
+	h.t.Helper()
+	if got := h.RegionText(id); !strings.Contains(got, want) {
+		h.t.Errorf("region %q covers %q, want it to cover %q\nscreen:\n%s", id, got, want, h.Screen())
+	}
+}
+
+// compose draws a rendered modal centered on a blank screen, as the
+// monitor's overlay does, and returns the screen's plain-text rows
+func compose(rendered string, width, height int) []string {
+	lines := strings.Split(ansi.Strip(rendered), "\n")
+	modalWidth := 0
+	for _, l := range lines {
+		modalWidth = max(modalWidth, ansi.StringWidth(l))
+	}
+	x := max(0, (width-modalWidth)/2)
+	y := max(0, (height-len(lines))/2)
+
+	screen := make([]string, height)
+	for row := range screen {
+		line := ""
+		if i := row - y; i >= 0 && i < len(lines) {
+			line = strings.Repeat(" ", x) + lines[i]
+		}
+		line = ansi.Truncate(line, width, "")
+		screen[row] = line + strings.Repeat(" ", width-ansi.StringWidth(line))
+	}
+	return screen
+}
+
+func joinScreen(screen []string) string {
+	rows := make([]string, len(screen))
+	for i, line := range screen {
+		rows[i] = strings.TrimRight(line, " ")
+	}
+	return strings.Join(rows, "\n")
+}
+
+func center(r mouse.Rect) (x, y int) {
+	return r.X + r.W/2, r.Y + r.H/2
+}
@@ -0,0 +1,145 @@
+package modaltest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textinput"
+
+	"github.com/marcus/td/pkg/monitor/modal"
+)
+
+func confirmModal(agree *bool) *modal.Modal {
+	return modal.New("Delete issue?", modal.WithWidth(40), modal.WithVariant(modal.VariantDanger)).
+		AddSection(modal.Text("This cannot be undone.")).
+		AddSection(modal.Checkbox("agree", "I understand", agree)).
+		AddSection(modal.Spacer()).
+		AddSection(modal.Buttons(
+			modal.Btn(" Delete ", "delete", modal.BtnDanger()),
+			modal.Btn(" Cancel ", "cancel"),
+		))
+}
+
+func TestRenderToString(t *testing.T) {
+	agree := false
+	got := RenderToString(confirmModal(&agree), 60, 20)
+
+	if strings.Contains(got, "\x1b[") {
+		t.Errorf("screen has escape sequences: %q", got)
+	}
+	lines := strings.Split(got, "\n")
+	if len(lines) != 20 {
+		t.Fatalf("got %d rows, want 20", len(lines))
+	}
+	for i, line := range lines {
+		if len([]rune(line)) > 60 {
+			t.Errorf("row %d is wider than the screen: %q", i, line)
+		}
+	}
+	for _, want := range []string{"Delete issue?", "This cannot be undone.", "[ ] I understand", "Delete", "Cancel"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("screen missing %q:\n%s", want, got)
+		}
+	}
+	if again := RenderToString(confirmModal(&agree), 60, 20); again != got {
+		t.Errorf("render is not deterministic:\n%s\nthen\n%s", got, again)
+	}
+}
+
+func TestHitRegionsCoverWhatIsDrawn(t *testing.T) {
+	agree := false
+	h := New(t, confirmModal(&agree), 60, 20)
+
+	h.AssertRegionText("agree", "I understand")
+	h.AssertRegionText("delete", "Delete")
+	h.AssertRegionText("cancel", "Cancel")
+	h.AssertNoRegion("missing")
+}
+
+func TestKeys(t *testing.T) {
+	agree := false
+	h := New(t, confirmModal(&agree), 60, 20)
+
+	if got := h.Modal.FocusedID(); got != "agree" {
+		t.Fatalf("initial focus = %q, want agree", got)
+	}
+	h.Press("space")
+	if !agree {
+		t.Error("space did not check the checkbox")
+	}
+	if !strings.Contains(h.Screen(), "[x] I understand") {
+		t.Errorf("screen not re-rendered after a key:\n%s", h.Screen())
+	}
+	if got := h.Press("tab", "tab", "enter"); got != "cancel" {
+		t.Errorf("tab, tab, enter = %q, want cancel", got)
+	}
+	if got := h.Press("shift+tab", "enter"); got != "delete" {
+		t.Errorf("shift+tab, enter = %q, want delete", got)
+	}
+	if got := h.Press("esc"); got != "cancel" {
+		t.Errorf("esc = %q, want cancel", got)
+	}
+}
+
+func TestKey(t *testing.T) {
+	for _, name := range []string{"enter", "esc", "tab", "shift+tab", "up", "ctrl+s", "alt+x", "x", " "} {
+		if got := Key(name).String(); got != name {
+			t.Errorf("Key(%q).String() = %q", name, got)
+		}
+	}
+	if got := Key("space").String(); got != " " {
+		t.Errorf(`Key("space").String() = %q, want " "`, got)
+	}
+}
+
+func TestMouse(t *testing.T) {
+	agree := false
+	h := New(t, confirmModal(&agree), 60, 20)
+
+	h.HoverRegion("cancel")
+	if got := h.Modal.HoveredID(); got != "cancel" {
+		t.Errorf("hovered = %q, want cancel", got)
+	}
+	if got := h.ClickRegion("cancel"); got != "cancel" {
+		t.Errorf("click cancel = %q", got)
+	}
+	// A second quick click on the same button is still a click
+	if got := h.ClickRegion("cancel"); got != "cancel" {
+		t.Errorf("second click cancel = %q", got)
+	}
+	if got := h.ClickRegion("agree"); got != "agree" || h.Modal.FocusedID() != "agree" {
+		t.Errorf("click agree = %q, focus %q", got, h.Modal.FocusedID())
+	}
+	if got := h.Click(0, 0); got != "cancel" {
+		t.Errorf("backdrop click = %q, want cancel", got)
+	}
+}
+
+func TestWheelScrollsHitRegions(t *testing.T) {
+	m := modal.New("Long", modal.WithWidth(40), modal.WithHints(false))
+	for i := 0; i < 20; i++ {
+		m.AddSection(modal.Text(fmt.Sprintf("Line %d", i)))
+	}
+	name := textinput.New()
+	m.AddSection(modal.InputWithLabel("name", "Name:", &name))
+	m.AddSection(modal.Buttons(modal.Btn(" OK ", "ok")))
+
+	h := New(t, m, 60, 16)
+	h.AssertNoRegion("ok")
+
+	body := h.AssertRegion("modal-body")
+	for i := 0; i < 10; i++ {
+		h.WheelDown(body.X+1, body.Y+1)
+	}
+	if h.Modal.ScrollOffset() == 0 {
+		t.Fatal("wheel did not scroll the modal")
+	}
+	h.AssertRegionText("ok", "OK")
+
+	h.Modal.SetFocus("name")
+	h.Type("td-a1b2")
+	if got := name.Value(); got != "td-a1b2" {
+		t.Errorf("typed value = %q", got)
+	}
+}
@@ -20,6 +20,40 @@ func WithWidth(w int) Option {
 	}
 }
 
+// WithAutoSize sizes the modal to its content instead of a fixed width:
+// as wide as its widest line, within the min and max widths and the screen.
+// Inputs, textareas and lists fill whatever width the rest of the content
+// gives them.
+func WithAutoSize() Option {
+	return func(m *Modal) {
+		m.autoSize = true
+	}
+}
+
+// WithMinWidth sets the narrowest the modal gets (default: MinModalWidth).
+// Screens narrower than that still shrink it.
+func WithMinWidth(w int) Option {
+	return func(m *Modal) {
+		m.minWidth = w
+	}
+}
+
+// WithMaxWidth sets the widest the modal gets. Auto-sized modals default
+// to MaxModalWidth.
+func WithMaxWidth(w int) Option {
+	return func(m *Modal) {
+		m.maxWidth = w
+	}
+}
+
+// WithMaxHeight sets the tallest the modal gets, border included. Content
+// that doesn't fit scrolls, as it does on short screens.
+func WithMaxHeight(h int) Option {
+	return func(m *Modal) {
+		m.maxHeight = h
+	}
+}
+
 // WithVariant sets the modal visual variant.
 func WithVariant(v Variant) Option {
 	return func(m *Modal) {
@@ -55,4 +89,5 @@ const (
 	MinModalWidth = 30
 	MaxModalWidth = 120
 	ModalPadding  = 6 // border(2) + horizontal padding(4)
+	ModalChrome   = 4 // border(2) + vertical padding(2)
 )
@@ -25,6 +25,7 @@ type Section interface {
 type RenderedSection struct {
 	Content    string          // Rendered string content
 	Focusables []FocusableInfo // Focusable elements with hit region info
+	Clickables []ClickableInfo // Mouse targets inside focusables (e.g. radio options)
 }
 
 // FocusableInfo describes a focusable element within a section.
@@ -36,6 +37,36 @@ type FocusableInfo struct {
 	Height  int    // Height in lines
 }
 
+// ClickableInfo describes a mouse target inside a focusable element, like
+// one option of a radio group. Clicking it focuses FocusID and sends the
+// section a ClickMsg; it is not a Tab stop of its own.
+type ClickableInfo struct {
+	ID      string // Hit region ID, unique within the modal
+	FocusID string // Focusable element the target belongs to
+	OffsetX int    // X offset relative to section top-left (within content area)
+	OffsetY int    // Y offset relative to section top-left (within content area)
+	Width   int    // Width in characters
+	Height  int    // Height in lines
+}
+
+// ClickMsg is sent to the focused section's Update when one of its
+// clickables is clicked.
+type ClickMsg struct {
+	ID string // The clickable's ID
+}
+
+// variantSetter is implemented by sections styled after the modal's
+// variant; AddSection passes it on.
+type variantSetter interface {
+	setVariant(v Variant)
+}
+
+// widthFiller is implemented by sections that stretch to the content width,
+// like inputs; auto-sized modals size to the other sections.
+type widthFiller interface {
+	fillsWidth() bool
+}
+
 // --- Text Section ---
 
 // textSection is a static text section.
@@ -104,6 +135,17 @@ func (w *whenSection) Update(msg tea.Msg, focusID string) (string, tea.Cmd) {
 	return w.inner.Update(msg, focusID)
 }
 
+func (w *whenSection) fillsWidth() bool {
+	f, ok := w.inner.(widthFiller)
+	return ok && f.fillsWidth()
+}
+
+func (w *whenSection) setVariant(v Variant) {
+	if vs, ok := w.inner.(variantSetter); ok {
+		vs.setVariant(v)
+	}
+}
+
 // --- Custom Section ---
 
 // customSection allows escape-hatch for complex custom content.
@@ -175,7 +217,8 @@ func BtnPrimary() BtnOption {
 	return func(b *ButtonDef) {}
 }
 
-// buttonsSection renders a row of buttons.
+// buttonsSection renders a row of buttons, wrapping onto more rows when
+// they don't fit the width.
 type buttonsSection struct {
 	buttons []ButtonDef
 }
@@ -190,37 +233,46 @@ func (b *buttonsSection) Render(contentWidth int, focusID, hoverID string) Rende
 		return RenderedSection{}
 	}
 
-	var sb strings.Builder
+	var rows []string
+	var row strings.Builder
 	focusables := make([]FocusableInfo, 0, len(b.buttons))
 	currentX := 0
 
 	for i, btn := range b.buttons {
-		if i > 0 {
-			sb.WriteString("  ") // Button spacing
-			currentX += 2
-		}
-
 		// Determine button style
 		style := b.resolveStyle(btn, focusID, hoverID)
 		rendered := style.Render(btn.Label)
-		sb.WriteString(rendered)
 
 		// Calculate visual width (ANSI-stripped)
 		visualWidth := ansi.StringWidth(rendered)
 
+		if i > 0 {
+			if currentX+2+visualWidth > contentWidth {
+				// Wrap to the next row
+				rows = append(rows, row.String())
+				row.Reset()
+				currentX = 0
+			} else {
+				row.WriteString("  ") // Button spacing
+				currentX += 2
+			}
+		}
+		row.WriteString(rendered)
+
 		focusables = append(focusables, FocusableInfo{
 			ID:      btn.ID,
 			OffsetX: currentX,
-			OffsetY: 0,
+			OffsetY: len(rows),
 			Width:   visualWidth,
 			Height:  1,
 		})
 
 		currentX += visualWidth
 	}
+	rows = append(rows, row.String())
 
 	return RenderedSection{
-		Content:    sb.String(),
+		Content:    strings.Join(rows, "\n"),
 		Focusables: focusables,
 	}
 }
@@ -78,3 +78,40 @@ var (
 			Foreground(Primary).
 			Bold(true)
 )
+
+// Segment styles for segmented controls; the selected segment's background
+// is the modal variant's color, and brackets take the place of its padding
+// so it stands out without color too
+var (
+	Segment = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252")).
+		Background(lipgloss.Color("238")).
+		Padding(0, 1)
+
+	SegmentSelected = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("255")).
+			Background(Primary)
+
+	SegmentHover = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("255")).
+			Background(lipgloss.Color("245")).
+			Padding(0, 1)
+)
+
+// Toast styles, matching the monitor's status toasts
+var (
+	InfoToast = lipgloss.NewStyle().
+			Background(Info).
+			Foreground(lipgloss.Color("0")).
+			Bold(true)
+
+	SuccessToast = lipgloss.NewStyle().
+			Background(lipgloss.Color("42")).
+			Foreground(lipgloss.Color("0")).
+			Bold(true)
+
+	ErrorToast = lipgloss.NewStyle().
+			Background(Error).
+			Foreground(lipgloss.Color("255")).
+			Bold(true)
+)
@@ -0,0 +1,194 @@
+package modal
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ToastKind is the kind of a toast, which sets its icon and color.
+type ToastKind int
+
+const (
+	ToastInfo ToastKind = iota
+	ToastSuccess
+	ToastError
+)
+
+// Toast defaults
+const (
+	DefaultToastDuration = 3 * time.Second
+	DefaultMaxToasts     = 3
+)
+
+// Toast is a transient notification.
+type Toast struct {
+	ID       int // Assigned by Push
+	Kind     ToastKind
+	Message  string
+	Duration time.Duration // How long it stays once shown (0 = the stack's default)
+}
+
+// ToastExpiredMsg dismisses the toast with ID once its time is up. Pass it
+// to Toasts.Update.
+type ToastExpiredMsg struct {
+	ID int
+}
+
+// Toasts is a stack of transient notifications that needs no modal: push a
+// toast from Update, return the command Push gives back, and pass messages
+// on to Update so toasts dismiss themselves. Toasts beyond MaxVisible wait
+// in a queue and are shown, with their own full duration, as earlier ones
+// expire. The zero value is ready to use.
+//
+//	case actionDoneMsg:
+//	    return m, m.Toasts.Success("Saved " + msg.ID)
+//	case modal.ToastExpiredMsg:
+//	    return m, m.Toasts.Update(msg)
+type Toasts struct {
+	MaxVisible int           // Toasts shown at once (0 = DefaultMaxToasts)
+	Duration   time.Duration // Default time on screen (0 = DefaultToastDuration); errors stay twice as long
+
+	visible []Toast
+	queued  []Toast
+	nextID  int
+}
+
+// Info shows an informational toast.
+func (t *Toasts) Info(msg string) tea.Cmd {
+	return t.Push(Toast{Kind: ToastInfo, Message: msg})
+}
+
+// Success shows a toast confirming an action.
+func (t *Toasts) Success(msg string) tea.Cmd {
+	return t.Push(Toast{Kind: ToastSuccess, Message: msg})
+}
+
+// Error shows a toast reporting a failure.
+func (t *Toasts) Error(msg string) tea.Cmd {
+	return t.Push(Toast{Kind: ToastError, Message: msg})
+}
+
+// Push shows a toast, or queues it when the stack is full, and returns the
+// command that dismisses it (nil while it is queued).
+func (t *Toasts) Push(toast Toast) tea.Cmd {
+	t.nextID++
+	toast.ID = t.nextID
+	if toast.Duration <= 0 {
+		toast.Duration = t.duration(toast.Kind)
+	}
+	if len(t.visible) >= t.max() {
+		t.queued = append(t.queued, toast)
+		return nil
+	}
+	t.visible = append(t.visible, toast)
+	return expireToast(toast)
+}
+
+// Update dismisses toasts whose time is up. Other messages are ignored.
+func (t *Toasts) Update(msg tea.Msg) tea.Cmd {
+	if msg, ok := msg.(ToastExpiredMsg); ok {
+		return t.Dismiss(msg.ID)
+	}
+	return nil
+}
+
+// Dismiss removes a toast now, shown or queued, and shows the next queued
+// one in its place.
+func (t *Toasts) Dismiss(id int) tea.Cmd {
+	for i, toast := range t.visible {
+		if toast.ID == id {
+			// Copy rather than shift in place: models holding the stack are
+			// copied by value, and the old copy must not change under them
+			t.visible = append(t.visible[:i:i], t.visible[i+1:]...)
+			return t.promote()
+		}
+	}
+	for i, toast := range t.queued {
+		if toast.ID == id {
+			t.queued = append(t.queued[:i:i], t.queued[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// Clear removes every toast, shown and queued.
+func (t *Toasts) Clear() {
+	t.visible, t.queued = nil, nil
+}
+
+// Visible returns the toasts on screen, oldest first.
+func (t *Toasts) Visible() []Toast {
+	return append([]Toast(nil), t.visible...)
+}
+
+// Queued returns the number of toasts waiting for room on screen.
+func (t *Toasts) Queued() int {
+	return len(t.queued)
+}
+
+// View renders the visible toasts one per line, oldest on top, right
+// aligned and no wider than width. It returns "" when there are none.
+func (t *Toasts) View(width int) string {
+	if len(t.visible) == 0 {
+		return ""
+	}
+	lines := make([]string, len(t.visible))
+	for i, toast := range t.visible {
+		icon, style := "•", InfoToast
+		switch toast.Kind {
+		case ToastSuccess:
+			icon, style = "✓", SuccessToast
+		case ToastError:
+			icon, style = "✗", ErrorToast
+		}
+		// Messages are one line; long ones are cut to fit
+		msg := strings.Join(strings.Fields(toast.Message), " ")
+		if width > 0 {
+			msg = ansi.Truncate(msg, max(1, width-4), "…")
+		}
+		lines[i] = style.Render(" " + icon + " " + msg + " ")
+	}
+	return lipgloss.JoinVertical(lipgloss.Right, lines...)
+}
+
+// promote shows queued toasts while there is room
+func (t *Toasts) promote() tea.Cmd {
+	var cmds []tea.Cmd
+	for len(t.visible) < t.max() && len(t.queued) > 0 {
+		toast := t.queued[0]
+		t.queued = t.queued[1:]
+		t.visible = append(t.visible, toast)
+		cmds = append(cmds, expireToast(toast))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (t *Toasts) max() int {
+	if t.MaxVisible > 0 {
+		return t.MaxVisible
+	}
+	return DefaultMaxToasts
+}
+
+func (t *Toasts) duration(kind ToastKind) time.Duration {
+	d := t.Duration
+	if d <= 0 {
+		d = DefaultToastDuration
+	}
+	if kind == ToastError {
+		d *= 2
+	}
+	return d
+}
+
+func expireToast(toast Toast) tea.Cmd {
+	id := toast.ID
+	return tea.Tick(toast.Duration, func(time.Time) tea.Msg {
+		return ToastExpiredMsg{ID: id}
+	})
+}
@@ -0,0 +1,125 @@
+package modal
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func toastMessages(ts []Toast) []string {
+	msgs := make([]string, len(ts))
+	for i, t := range ts {
+		msgs[i] = t.Message
+	}
+	return msgs
+}
+
+func TestToastsQueue(t *testing.T) {
+	var toasts Toasts
+	toasts.MaxVisible = 2
+
+	if cmd := toasts.Success("one"); cmd == nil {
+		t.Error("shown toast returned no expiry command")
+	}
+	toasts.Info("two")
+	if cmd := toasts.Error("three"); cmd != nil {
+		t.Error("queued toast returned an expiry command")
+	}
+
+	if got := toastMessages(toasts.Visible()); strings.Join(got, ",") != "one,two" {
+		t.Errorf("visible = %v, want one,two", got)
+	}
+	if toasts.Queued() != 1 {
+		t.Errorf("queued = %d, want 1", toasts.Queued())
+	}
+
+	first := toasts.Visible()[0]
+	if cmd := toasts.Update(ToastExpiredMsg{ID: first.ID}); cmd == nil {
+		t.Error("promoting a queued toast returned no expiry command")
+	}
+	if got := toastMessages(toasts.Visible()); strings.Join(got, ",") != "two,three" {
+		t.Errorf("after expiry visible = %v, want two,three", got)
+	}
+	if toasts.Queued() != 0 {
+		t.Errorf("queued = %d, want 0", toasts.Queued())
+	}
+
+	// Expiring a toast twice is harmless
+	toasts.Update(ToastExpiredMsg{ID: first.ID})
+	if len(toasts.Visible()) != 2 {
+		t.Errorf("visible = %d after a stale expiry, want 2", len(toasts.Visible()))
+	}
+
+	toasts.Clear()
+	if len(toasts.Visible()) != 0 || toasts.View(40) != "" {
+		t.Error("Clear left toasts behind")
+	}
+}
+
+func TestToastsDismissQueued(t *testing.T) {
+	toasts := Toasts{MaxVisible: 1}
+	toasts.Info("shown")
+	toasts.Info("waiting")
+	queued := toasts.nextID
+
+	toasts.Dismiss(queued)
+	if toasts.Queued() != 0 {
+		t.Errorf("queued = %d after dismissing it, want 0", toasts.Queued())
+	}
+}
+
+func TestToastsCopySafe(t *testing.T) {
+	var toasts Toasts
+	toasts.Info("a")
+	toasts.Info("b")
+	toasts.Info("c")
+	before := toasts
+
+	toasts.Dismiss(before.Visible()[0].ID)
+	if got := toastMessages(before.Visible()); strings.Join(got, ",") != "a,b,c" {
+		t.Errorf("dismissing changed an earlier copy: %v", got)
+	}
+}
+
+func TestToastDurations(t *testing.T) {
+	var toasts Toasts
+	toasts.Success("ok")
+	toasts.Error("bad")
+	toasts.Push(Toast{Message: "custom", Duration: time.Second})
+
+	got := toasts.Visible()
+	if got[0].Duration != DefaultToastDuration {
+		t.Errorf("success duration = %v", got[0].Duration)
+	}
+	if got[1].Duration != 2*DefaultToastDuration {
+		t.Errorf("error duration = %v, want twice the default", got[1].Duration)
+	}
+	if got[2].Duration != time.Second {
+		t.Errorf("custom duration = %v", got[2].Duration)
+	}
+}
+
+func TestToastsView(t *testing.T) {
+	var toasts Toasts
+	toasts.Success("Closed td-a1b2")
+	toasts.Error("Failed: database is locked\nretry later")
+
+	view := ansi.Strip(toasts.View(24))
+	lines := strings.Split(view, "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), view)
+	}
+	if !strings.Contains(lines[0], "✓ Closed td-a1b2") {
+		t.Errorf("success line = %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "✗ Failed") || !strings.Contains(lines[1], "…") {
+		t.Errorf("error line should be one truncated line: %q", lines[1])
+	}
+	for _, l := range lines {
+		if w := ansi.StringWidth(l); w > 24 {
+			t.Errorf("line %q is %d wide, want at most 24", l, w)
+		}
+	}
+}
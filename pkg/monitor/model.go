@@ -8,6 +8,7 @@ import (
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/td/internal/capacity"
 	"github.com/marcus/td/internal/config"
 	"github.com/marcus/td/internal/db"
 	"github.com/marcus/td/internal/models"
@@ -30,12 +31,13 @@ type Model struct {
 	Height int
 
 	// Panel data
-	FocusedIssue   *models.Issue
-	InProgress     []models.Issue
-	Activity       []ActivityItem
-	TaskList       TaskListData
-	RecentHandoffs []RecentHandoff // Handoffs since monitor started
-	ActiveSessions []string        // Sessions with recent activity
+	FocusedIssue    *models.Issue
+	InProgress      []models.Issue
+	Activity        []ActivityItem
+	TaskList        TaskListData
+	RecentHandoffs  []RecentHandoff             // Handoffs since monitor started
+	ActiveSessions  []string                    // Sessions with recent activity
+	SessionLiveness map[string]session.Liveness // Heartbeat liveness for non-gone sessions
 
 	// UI state
 	ActivePanel         Panel
@@ -55,6 +57,8 @@ type Model struct {
 	StartedAt           time.Time // When monitor started, to track new handoffs
 	Err                 error     // Last error, if any
 	Embedded            bool      // When true, skip footer (embedded in sidecar)
+	Accessible          bool      // Screen-reader friendly: ASCII only, focus announced in the footer (see EnableAccessible)
+	NoMouse             bool      // Keyboard only: mouse events ignored, no hover styles (see DisableMouse)
 
 	// Flattened rows for selection
 	TaskListRows    []TaskListRow // Flattened task list for selection
@@ -70,6 +74,7 @@ type Model struct {
 	IncludeClosed  bool            // Whether to include closed tasks
 	SortMode       SortMode        // Task list sort order
 	TypeFilterMode TypeFilterMode  // Type filter (epic, task, bug, etc.)
+	PinnedView     string          // Saved view opened with td monitor --view (see OpenView)
 
 	// Confirmation dialog state (delete confirmation)
 	ConfirmOpen        bool
@@ -95,6 +100,42 @@ type Model struct {
 	CloseConfirmModal        *modal.Modal   // Declarative modal instance
 	CloseConfirmMouseHandler *mouse.Handler // Mouse handler for close confirmation modal
 
+	// Issue action menu state (start/review/approve/block/comment)
+	ActionMenuOpen         bool
+	ActionMenu             *actionMenuState // Shared pointer: survives stale closure captures
+	ActionMenuModal        *modal.Modal     // Declarative modal instance
+	ActionMenuMouseHandler *mouse.Handler   // Mouse handler for action menu modal
+
+	// Approve modal state, shown when the project has a review checklist
+	ApproveChecklistOpen         bool
+	ApproveChecklist             *approveChecklistState // Shared pointer: survives stale closure captures
+	ApproveChecklistModal        *modal.Modal           // Declarative modal instance
+	ApproveChecklistMouseHandler *mouse.Handler         // Mouse handler for approve modal
+
+	// Triage inbox modal state (accept/reject/merge/defer inbox issues)
+	TriageInboxOpen         bool
+	TriageInbox             *triageInboxState // Shared pointer: survives stale closure captures
+	TriageInboxModal        *modal.Modal      // Declarative modal instance
+	TriageInboxMouseHandler *mouse.Handler    // Mouse handler for triage inbox modal
+
+	// Issue preview pane (split beside the task list)
+	PreviewOpen  bool
+	PreviewRatio float64      // Width ratio of the preview pane
+	Preview      *PreviewData // Data for the selected issue (nil while loading)
+
+	// Desktop notifications (nil when disabled in config)
+	Notifier *notifyTracker
+
+	// Last snapshot delivered, so polls send only what changed (see feed.go)
+	feed *dataFeed
+
+	// Section filter prompt state (per-category TDQ filters)
+	SectionFilters            map[string]map[TaskListCategory]string // scope (board ID or "all") -> category -> TDQ
+	SectionFilterOpen         bool
+	SectionFilter             *sectionFilterState // Shared pointer: survives stale closure captures
+	SectionFilterModal        *modal.Modal        // Declarative modal instance
+	SectionFilterMouseHandler *mouse.Handler      // Mouse handler for section filter modal
+
 	// Stats modal state
 	StatsOpen         bool
 	StatsLoading      bool
@@ -114,6 +155,24 @@ type Model struct {
 	HandoffsModal        *modal.Modal   // Declarative modal instance
 	HandoffsMouseHandler *mouse.Handler // Mouse handler for handoffs modal
 
+	// Reminders modal state
+	RemindersOpen         bool
+	RemindersLoading      bool
+	RemindersData         []models.Reminder
+	RemindersCursor       int
+	RemindersError        error
+	RemindersModal        *modal.Modal   // Declarative modal instance
+	RemindersMouseHandler *mouse.Handler // Mouse handler for reminders modal
+
+	// Sprint capacity modal state
+	CapacityOpen         bool
+	CapacityLoading      bool
+	CapacityData         *capacity.Report
+	CapacityCursor       int
+	CapacityError        error
+	CapacityModal        *modal.Modal   // Declarative modal instance
+	CapacityMouseHandler *mouse.Handler // Mouse handler for capacity modal
+
 	// Activity detail modal state
 	ActivityDetailOpen         bool
 	ActivityDetailItem         *ActivityItem  // The selected activity item
@@ -189,6 +248,9 @@ type Model struct {
 	StatusMessage string
 	StatusIsError bool // true for error messages, false for success
 
+	// Toasts report the outcome of actions in the bottom right corner
+	Toasts modal.Toasts
+
 	// Version checking
 	Version     string // Current version
 	UpdateAvail *version.UpdateAvailableMsg
@@ -226,9 +288,23 @@ func NewModel(database *db.DB, sessionID string, interval time.Duration, ver str
 	km := keymap.NewRegistry()
 	keymap.RegisterDefaults(km)
 
+	// Apply the user's remapped keys from .todos/keymap.json
+	var keymapStatus string
+	if kc, err := keymap.LoadConfig(keymap.ConfigPath(baseDir)); err != nil {
+		keymapStatus = fmt.Sprintf("keymap.json: %v", err)
+	} else {
+		keymap.ApplyConfig(km, kc)
+		if keymap.HasErrors(keymap.Check(kc)) {
+			keymapStatus = "keymap.json has bindings that were skipped (td config keymap check)"
+		}
+	}
+
 	// Load pane heights from config (or use defaults)
 	paneHeights, _ := config.GetPaneHeights(baseDir)
 
+	// Load preview pane layout from config
+	previewOpen, previewRatio, _ := config.GetPreviewLayout(baseDir)
+
 	// Initialize search input
 	searchInput := textinput.New()
 	searchInput.Placeholder = "search"
@@ -260,6 +336,13 @@ func NewModel(database *db.DB, sessionID string, interval time.Duration, ver str
 		DraggingDivider:   -1,
 		DividerHover:      -1,
 		BaseDir:           baseDir,
+		PreviewOpen:       previewOpen,
+		PreviewRatio:      previewRatio,
+		Notifier:          newNotifyTracker(database, baseDir, sessionID),
+		feed:              newDataFeed(),
+		SectionFilters:    make(map[string]map[TaskListCategory]string),
+		StatusMessage:     keymapStatus,
+		StatusIsError:     keymapStatus != "",
 	}
 }
 
@@ -294,6 +377,7 @@ type EmbeddedOptions struct {
 	Version       string        // Version string for display
 	PanelRenderer PanelRenderer // Custom panel border renderer (nil = default lipgloss)
 	ModalRenderer ModalRenderer // Custom modal border renderer (nil = default lipgloss)
+	NoMouse       bool          // Keyboard only: ignore mouse events the host forwards
 
 	// MarkdownTheme configures markdown rendering to share themes with embedder.
 	// Pass colors from your theme to get consistent syntax highlighting.
@@ -325,6 +409,9 @@ func NewEmbeddedWithOptions(opts EmbeddedOptions) (*Model, error) {
 	m.PanelRenderer = opts.PanelRenderer
 	m.ModalRenderer = opts.ModalRenderer
 	m.MarkdownTheme = opts.MarkdownTheme
+	if opts.NoMouse {
+		m.DisableMouse()
+	}
 	return &m, nil
 }
 
@@ -399,10 +486,12 @@ func (m Model) Init() tea.Cmd {
 	cmds := []tea.Cmd{
 		m.fetchData(),
 		m.scheduleTick(),
-		m.restoreLastViewedBoard(),
-		m.restoreFilterState(),
 		m.checkFirstRun(),
 	}
+	// A view opened from the command line wins over the last session's state
+	if m.PinnedView == "" {
+		cmds = append(cmds, m.restoreLastViewedBoard(), m.restoreFilterState())
+	}
 
 	// Start async version check (non-blocking)
 	if m.Version != "" && !version.IsDevelopmentVersion(m.Version) {
@@ -456,14 +545,14 @@ type RestoreFilterMsg struct {
 	IncludeClosed  bool
 }
 
-// Update implements tea.Model
-func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+// update handles msg; Update wraps it with panic recovery
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// Handle TickMsg before any UI-mode interceptions to keep the poll chain
 	// alive. Without this, opening a form (or other overlay that intercepts all
 	// messages) would swallow the TickMsg, preventing scheduleTick() from being
 	// called, permanently breaking the periodic refresh cycle.
 	if _, ok := msg.(TickMsg); ok {
-		cmds := []tea.Cmd{m.fetchData(), m.scheduleTick()}
+		cmds := []tea.Cmd{m.pollData(), m.scheduleTick()}
 		if m.TaskListMode == TaskListModeBoard && m.BoardMode.Board != nil {
 			cmds = append(cmds, m.fetchBoardIssues(m.BoardMode.Board.ID))
 		}
@@ -483,6 +572,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, tea.Batch(cmds...)
 	}
 
+	// Keyboard-only monitors ignore mouse events from embedding hosts
+	if _, ok := msg.(tea.MouseMsg); ok && m.NoMouse {
+		return m, nil
+	}
+
 	// Form mode: forward all messages to huh form first
 	if m.FormOpen && m.FormState != nil && m.FormState.Form != nil {
 		return m.handleFormUpdate(msg)
@@ -538,7 +632,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		return m.handleKey(msg)
+		return syncPreview(m.handleKey(msg))
 
 	case tea.WindowSizeMsg:
 		m.Width = msg.Width
@@ -554,26 +648,47 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.MouseMsg:
-		return m.handleMouse(msg)
+		return syncPreview(m.handleMouse(msg))
 
 	// NOTE: TickMsg is handled above the form/overlay interception block
 	// to prevent the poll chain from breaking. Do not add a TickMsg case here.
 
 	case RefreshDataMsg:
-		m.FocusedIssue = msg.FocusedIssue
-		m.InProgress = msg.InProgress
-		m.Activity = msg.Activity
-		m.TaskList = msg.TaskList
-		m.RecentHandoffs = msg.RecentHandoffs
-		m.ActiveSessions = msg.ActiveSessions
-		m.LastRefresh = msg.Timestamp
-
-		// Build flattened rows for selection
-		m.buildCurrentWorkRows()
-		m.buildTaskListRows()
-
-		// Restore cursor positions from saved issue IDs
-		m.restoreCursors()
+		m.applyRefreshData(msg)
+		cmds := append([]tea.Cmd{m.refreshPreview()}, m.refreshSideEffects(msg)...)
+		return m, tea.Batch(cmds...)
+
+	case PollResultMsg:
+		m.LastRefresh = msg.Data.Timestamp
+		cmds := m.refreshSideEffects(msg.Data)
+		if len(msg.Changes) > 0 {
+			m.applyChanges(msg)
+			cmds = append(cmds, m.refreshPreview())
+		}
+		return m, tea.Batch(cmds...)
+
+	case IssueChangedMsg:
+		m.applyIssueChanged(msg.Issue)
+		return m, m.refreshPreview()
+
+	case FocusChangedMsg:
+		m.applyFocusChanged(msg.Issue)
+		return m, m.refreshPreview()
+
+	case ActivityAppendedMsg:
+		m.applyActivityAppended(msg.Items)
+		return m, nil
+
+	case SessionsChangedMsg:
+		m.applySessionsChanged(msg)
+		return m, nil
+
+	case PreviewDataMsg:
+		// Drop stale results if the selection moved while fetching
+		if m.PreviewOpen && msg.Data.IssueID == m.previewTargetID() {
+			data := msg.Data
+			m.Preview = &data
+		}
 		return m, nil
 
 	case IssueDetailsMsg:
@@ -665,11 +780,40 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case RemindersDataMsg:
+		if m.RemindersOpen {
+			m.RemindersLoading = false
+			m.RemindersError = msg.Error
+			m.RemindersData = msg.Data
+			if m.RemindersCursor >= len(m.RemindersData) {
+				m.RemindersCursor = max(len(m.RemindersData)-1, 0)
+			}
+			m.RemindersModal = m.createRemindersModal()
+			m.RemindersModal.Reset()
+		}
+		return m, nil
+
+	case CapacityDataMsg:
+		if m.CapacityOpen {
+			m.CapacityLoading = false
+			m.CapacityError = msg.Error
+			m.CapacityData = msg.Data
+			if msg.Data != nil && m.CapacityCursor >= len(msg.Data.Sessions) {
+				m.CapacityCursor = max(len(msg.Data.Sessions)-1, 0)
+			}
+			m.CapacityModal = m.createCapacityModal()
+			m.CapacityModal.Reset()
+		}
+		return m, nil
+
 	case ClearStatusMsg:
 		m.StatusMessage = ""
 		m.StatusIsError = false
 		return m, nil
 
+	case modal.ToastExpiredMsg:
+		return m, m.Toasts.Update(msg)
+
 	case FirstRunCheckMsg:
 		m.AgentFilePath = msg.AgentFilePath
 		m.AgentFileHasTD = msg.HasInstructions
@@ -788,6 +932,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.BoardMode.Issues = filteredIssues
 			// Build swimlane data using filtered issues
 			m.BoardMode.SwimlaneData = CategorizeBoardIssues(m.DB, filteredIssues, m.SessionID, m.SortMode, msg.RejectedIDs)
+			m.BoardMode.SwimlaneData = m.applySectionFilters(msg.BoardID, m.BoardMode.SwimlaneData)
 			m.BoardMode.SwimlaneRows = BuildSwimlaneRows(m.BoardMode.SwimlaneData)
 
 			// Clamp kanban cursor if the kanban view is open (data may have changed)
@@ -817,7 +962,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.BoardMode.PendingSelectionID = "" // Clear after use
 			}
 		}
-		return m, nil
+		return m, m.refreshPreview()
 
 	case RestoreLastBoardMsg:
 		if msg.Board != nil {
@@ -892,9 +1037,16 @@ func (m Model) CurrentContextString() string {
 	return keymap.ContextToSidecar(m.currentContext())
 }
 
-// View implements tea.Model
-func (m Model) View() string {
-	return m.renderView()
+// view renders the screen; View wraps it with panic recovery
+func (m Model) view() string {
+	view := m.renderView()
+	if m.Width >= MinWidth && m.Height >= MinHeight {
+		view = OverlayToasts(view, m.Toasts.View(m.Width/2), m.Width, m.Height)
+	}
+	if m.Accessible {
+		return asciiGlyphs.Replace(view)
+	}
+	return view
 }
 
 // scheduleTick returns a command that sends a TickMsg after the refresh interval
@@ -908,10 +1060,28 @@ func (m Model) scheduleTick() tea.Cmd {
 func (m Model) fetchData() tea.Cmd {
 	return func() tea.Msg {
 		data := FetchData(m.DB, m.SessionID, m.StartedAt, m.SearchQuery, m.IncludeClosed, m.SortMode)
+		// Claim due reminders here rather than in FetchData, which also
+		// backs the read-only /v1/monitor endpoint.
+		if m.DB != nil {
+			data.FiredReminders, _ = m.DB.FireDueReminders(time.Now())
+		}
+		m.feed.reset(data)
 		return data
 	}
 }
 
+// pollData returns a command that fetches all data and sends a
+// PollResultMsg with only what changed since the model's last snapshot
+func (m Model) pollData() tea.Cmd {
+	return func() tea.Msg {
+		data := FetchData(m.DB, m.SessionID, m.StartedAt, m.SearchQuery, m.IncludeClosed, m.SortMode)
+		if m.DB != nil {
+			data.FiredReminders, _ = m.DB.FireDueReminders(time.Now())
+		}
+		return PollResultMsg{Data: data, Changes: m.feed.publish(data)}
+	}
+}
+
 // fetchModalDataIfOpen returns a command to refresh the current modal's data
 // if a modal is open, otherwise returns nil
 func (m Model) fetchModalDataIfOpen() tea.Cmd {
@@ -947,6 +1117,9 @@ func (m Model) fetchIssueDetails(issueID string) tea.Cmd {
 		comments, _ := m.DB.GetComments(issueID)
 		msg.Comments = comments
 
+		// Decrypt a confidential issue for this session, or redact it
+		m.DB.RevealIssue(issue, comments, m.DB.ConfidentialViewer(m.SessionID))
+
 		// Fetch parent epic if this issue has a parent
 		if issue.ParentID != "" {
 			if parent, err := m.DB.GetIssue(issue.ParentID); err == nil && parent.Type == models.TypeEpic {
@@ -1006,6 +1179,33 @@ func (m Model) fetchHandoffs() tea.Cmd {
 	}
 }
 
+// fetchReminders returns a command that fetches pending and fired reminders
+func (m Model) fetchReminders() tea.Cmd {
+	return func() tea.Msg {
+		reminders, err := m.DB.ListReminders(db.ReminderFilter{
+			Status: []models.ReminderStatus{models.ReminderPending, models.ReminderFired},
+		})
+		return RemindersDataMsg{Data: reminders, Error: err}
+	}
+}
+
+// fetchCapacity returns a command that computes capacity for the current sprint
+func (m Model) fetchCapacity() tea.Cmd {
+	return func() tea.Msg {
+		sprints, err := config.GetSprints(m.BaseDir)
+		if err != nil {
+			return CapacityDataMsg{Error: err}
+		}
+		now := time.Now()
+		sprint, ok := capacity.CurrentSprint(sprints, now)
+		if !ok {
+			return CapacityDataMsg{}
+		}
+		report, err := capacity.Compute(m.DB, sprint, capacity.DefaultWindowDays, now)
+		return CapacityDataMsg{Data: report, Error: err}
+	}
+}
+
 // ensureBoardCursorVisible adjusts the board scroll offset to keep the cursor visible.
 // Uses content height matching the rendering (panelHeight - 3) and dynamically
 // accounts for scroll indicator lines based on current scroll position.
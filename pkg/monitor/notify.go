@@ -0,0 +1,235 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/delivery"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/notify"
+	"github.com/marcus/td/internal/session"
+)
+
+// notification is a single notification raised by the monitor
+type notification struct {
+	Event   notify.Event
+	Title   string
+	Body    string
+	IssueID string
+}
+
+// notifyTracker remembers what the monitor has already seen so only new
+// events raise notifications. Stored as a pointer on Model so state survives
+// Bubble Tea copying the Model.
+type notifyTracker struct {
+	Settings notify.Settings
+	Send     notify.Sender
+	Handles  []string // lowercase mention handles, e.g. "@ses_abc", "@alice"
+
+	seeded     bool
+	lastCheck  time.Time
+	reviewable map[string]bool
+	seenIssues map[string]bool
+}
+
+// newNotifyTracker loads notification settings for the project. Returns nil
+// when notifications are disabled so the refresh path can skip detection.
+func newNotifyTracker(database *db.DB, baseDir, sessionID string) *notifyTracker {
+	settings := notify.Load(baseDir)
+	if !settings.Enabled {
+		return nil
+	}
+
+	handles := []string{"@" + strings.ToLower(sessionID)}
+	if database != nil {
+		if sess, err := session.GetByID(database, sessionID); err == nil && sess.Name != "" {
+			handles = append(handles, "@"+strings.ToLower(sess.Name))
+		}
+	}
+
+	// Routes decide where each event goes; a broken route set falls back
+	// to the desktop rather than silently dropping notifications
+	var routes []models.NotifyRoute
+	if database != nil {
+		routes, _ = database.ListNotifyRoutes()
+	}
+	dispatcher, err := notify.NewDispatcher(routes)
+	if err != nil {
+		dispatcher, _ = notify.NewDispatcher(nil)
+	}
+	send := dispatcher.Send
+	if database != nil {
+		// Network routes go through the delivery outbox, so a message to
+		// an unreachable endpoint is retried instead of dropped
+		dispatcher.Queue = delivery.NotifyQueue(database)
+		send = func(msg notify.Message) error {
+			err := dispatcher.Send(msg)
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			_, _ = delivery.Deliver(ctx, database, baseDir)
+			return err
+		}
+	}
+
+	return &notifyTracker{
+		Settings: settings,
+		Send:     send,
+		Handles:  handles,
+	}
+}
+
+// detect compares a refresh against what was seen before and returns the
+// notifications for anything new. The first refresh only seeds state so
+// launching the monitor doesn't replay existing work; reminders that came
+// due are delivered regardless, since each is claimed exactly once.
+func (t *notifyTracker) detect(msg RefreshDataMsg, sessionID string) []notification {
+	var out []notification
+	for _, r := range msg.FiredReminders {
+		body := r.Message
+		if body == "" {
+			body = "reminder due"
+		}
+		out = append(out, notification{
+			Event:   notify.EventReminder,
+			Title:   fmt.Sprintf("td: reminder on %s", r.IssueID),
+			Body:    truncateString(body, 120),
+			IssueID: r.IssueID,
+		})
+	}
+
+	reviewable := make(map[string]bool, len(msg.TaskList.Reviewable))
+	for _, issue := range msg.TaskList.Reviewable {
+		reviewable[issue.ID] = true
+	}
+
+	all := allRefreshIssues(msg)
+	seen := make(map[string]bool, len(all))
+	for _, issue := range all {
+		seen[issue.ID] = true
+	}
+
+	if !t.seeded {
+		t.seeded = true
+		t.lastCheck = msg.Timestamp
+		t.reviewable = reviewable
+		t.seenIssues = seen
+		return out
+	}
+
+	for _, issue := range msg.TaskList.Reviewable {
+		if !t.reviewable[issue.ID] {
+			out = append(out, notification{
+				Event:   notify.EventReview,
+				Title:   "td: ready for your review",
+				Body:    fmt.Sprintf("%s %s", issue.ID, issue.Title),
+				IssueID: issue.ID,
+			})
+		}
+	}
+
+	for _, issue := range all {
+		if !t.seenIssues[issue.ID] && issue.Priority == models.PriorityP0 && issue.CreatedAt.After(t.lastCheck) {
+			out = append(out, notification{
+				Event:   notify.EventP0,
+				Title:   "td: new P0",
+				Body:    fmt.Sprintf("%s %s", issue.ID, issue.Title),
+				IssueID: issue.ID,
+			})
+		}
+	}
+
+	for _, item := range msg.Activity {
+		if item.Type != "log" && item.Type != "comment" {
+			continue
+		}
+		if item.SessionID == sessionID || !item.Timestamp.After(t.lastCheck) {
+			continue
+		}
+		if t.mentions(item.Message) {
+			out = append(out, notification{
+				Event:   notify.EventMention,
+				Title:   fmt.Sprintf("td: mentioned on %s", item.IssueID),
+				Body:    truncateString(item.Message, 120),
+				IssueID: item.IssueID,
+			})
+		}
+	}
+
+	t.reviewable = reviewable
+	for id := range seen {
+		t.seenIssues[id] = true
+	}
+	if msg.Timestamp.After(t.lastCheck) {
+		t.lastCheck = msg.Timestamp
+	}
+	return out
+}
+
+// mentions reports whether text contains one of the tracker's handles as a
+// whole word
+func (t *notifyTracker) mentions(text string) bool {
+	lower := strings.ToLower(text)
+	for _, h := range t.Handles {
+		for idx := strings.Index(lower, h); idx >= 0; {
+			end := idx + len(h)
+			if end == len(lower) || !isHandleChar(lower[end]) {
+				return true
+			}
+			next := strings.Index(lower[end:], h)
+			if next < 0 {
+				break
+			}
+			idx = end + next
+		}
+	}
+	return false
+}
+
+func isHandleChar(c byte) bool {
+	return c == '_' || c == '-' || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9')
+}
+
+// allRefreshIssues returns every issue carried by a refresh message
+func allRefreshIssues(msg RefreshDataMsg) []models.Issue {
+	tl := msg.TaskList
+	var all []models.Issue
+	all = append(all, msg.InProgress...)
+	for _, group := range [][]models.Issue{tl.Reviewable, tl.NeedsRework, tl.InProgress, tl.Ready, tl.NeedsTriage, tl.PendingReview, tl.Blocked, tl.Closed} {
+		all = append(all, group...)
+	}
+	return all
+}
+
+// checkNotifications runs detection for a refresh and returns a command that
+// delivers the allowed notifications. Delivery errors are ignored since
+// notifications are best-effort.
+func (m Model) checkNotifications(msg RefreshDataMsg) tea.Cmd {
+	t := m.Notifier
+	if t == nil {
+		return nil
+	}
+	pending := t.detect(msg, m.SessionID)
+	now := time.Now()
+
+	var allowed []notification
+	for _, n := range pending {
+		if t.Settings.Allows(n.Event, now) {
+			allowed = append(allowed, n)
+		}
+	}
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	send := t.Send
+	return func() tea.Msg {
+		for _, n := range allowed {
+			_ = send(notify.Message{Event: n.Event, Title: n.Title, Body: n.Body, IssueID: n.IssueID, Time: now})
+		}
+		return nil
+	}
+}
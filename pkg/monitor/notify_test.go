@@ -0,0 +1,129 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/notify"
+)
+
+func newTestNotifyTracker() *notifyTracker {
+	return &notifyTracker{
+		Settings: notify.FromConfig(&models.NotifyConfig{Enabled: true}),
+		Handles:  []string{"@ses_me", "@alice"},
+	}
+}
+
+func TestNotifyTrackerSeedsOnFirstRefresh(t *testing.T) {
+	tr := newTestNotifyTracker()
+	start := time.Now()
+
+	first := RefreshDataMsg{
+		Timestamp: start,
+		TaskList: TaskListData{
+			Reviewable: []models.Issue{{ID: "td-1", Title: "Existing review"}},
+		},
+	}
+	if got := tr.detect(first, "ses_me"); len(got) != 0 {
+		t.Fatalf("first refresh should only seed, got %v", got)
+	}
+
+	// Same state again: nothing new
+	first.Timestamp = start.Add(time.Second)
+	if got := tr.detect(first, "ses_me"); len(got) != 0 {
+		t.Fatalf("unchanged refresh should not notify, got %v", got)
+	}
+}
+
+func TestNotifyTrackerDetectsNewEvents(t *testing.T) {
+	tr := newTestNotifyTracker()
+	start := time.Now()
+	tr.detect(RefreshDataMsg{Timestamp: start}, "ses_me")
+
+	later := start.Add(5 * time.Second)
+	msg := RefreshDataMsg{
+		Timestamp: later,
+		TaskList: TaskListData{
+			Reviewable: []models.Issue{{ID: "td-rev", Title: "Needs eyes"}},
+			Ready: []models.Issue{
+				{ID: "td-p0", Title: "Prod down", Priority: models.PriorityP0, CreatedAt: start.Add(time.Second)},
+				{ID: "td-p2", Title: "Minor", Priority: models.PriorityP2, CreatedAt: start.Add(time.Second)},
+			},
+		},
+		Activity: []ActivityItem{
+			{Type: "comment", SessionID: "ses_other", IssueID: "td-rev", Message: "@alice can you look?", Timestamp: start.Add(2 * time.Second)},
+			{Type: "comment", SessionID: "ses_other", IssueID: "td-rev", Message: "cc @alicex", Timestamp: start.Add(2 * time.Second)},
+			{Type: "log", SessionID: "ses_me", IssueID: "td-rev", Message: "note to self @ses_me", Timestamp: start.Add(2 * time.Second)},
+			{Type: "comment", SessionID: "ses_other", IssueID: "td-old", Message: "@alice old", Timestamp: start.Add(-time.Minute)},
+		},
+	}
+
+	got := tr.detect(msg, "ses_me")
+	counts := map[notify.Event]int{}
+	for _, n := range got {
+		counts[n.Event]++
+	}
+	if counts[notify.EventReview] != 1 || counts[notify.EventP0] != 1 || counts[notify.EventMention] != 1 {
+		t.Errorf("event counts = %v, want one of each", counts)
+	}
+
+	// Repeating the same refresh produces nothing new
+	msg.Timestamp = later.Add(time.Second)
+	if again := tr.detect(msg, "ses_me"); len(again) != 0 {
+		t.Errorf("repeat refresh should not notify, got %v", again)
+	}
+}
+
+func TestCheckNotificationsRespectsSettings(t *testing.T) {
+	tr := newTestNotifyTracker()
+	tr.Settings = notify.FromConfig(&models.NotifyConfig{Enabled: true, Events: []string{"p0"}})
+	var sent []string
+	tr.Send = func(msg notify.Message) error {
+		sent = append(sent, msg.Body)
+		return nil
+	}
+
+	m := newTestModel()
+	m.SessionID = "ses_me"
+	m.Notifier = tr
+
+	start := time.Now()
+	m.checkNotifications(RefreshDataMsg{Timestamp: start})
+
+	cmd := m.checkNotifications(RefreshDataMsg{
+		Timestamp: start.Add(time.Second),
+		TaskList: TaskListData{
+			Reviewable: []models.Issue{{ID: "td-rev"}},
+		},
+	})
+	if cmd != nil {
+		t.Fatal("review events are disabled; expected no command")
+	}
+
+	cmd = m.checkNotifications(RefreshDataMsg{
+		Timestamp: start.Add(2 * time.Second),
+		TaskList: TaskListData{
+			Ready: []models.Issue{{ID: "td-p0", Title: "Outage", Priority: models.PriorityP0, CreatedAt: start.Add(1500 * time.Millisecond)}},
+		},
+	})
+	if cmd == nil {
+		t.Fatal("expected a command for the P0 notification")
+	}
+	cmd()
+	if len(sent) != 1 || sent[0] != "td-p0 Outage" {
+		t.Errorf("sent = %v, want [td-p0 Outage]", sent)
+	}
+}
+
+func TestNotifyTrackerDeliversRemindersOnFirstRefresh(t *testing.T) {
+	tr := newTestNotifyTracker()
+	msg := RefreshDataMsg{
+		Timestamp:      time.Now(),
+		FiredReminders: []models.Reminder{{ID: "rm-1", IssueID: "td-ci", Message: "check CI flake"}},
+	}
+	got := tr.detect(msg, "ses_me")
+	if len(got) != 1 || got[0].Event != notify.EventReminder || got[0].Body != "check CI flake" {
+		t.Fatalf("detect = %+v, want one reminder notification", got)
+	}
+}
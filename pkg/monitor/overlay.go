@@ -108,3 +108,34 @@ func OverlayModal(background, modal string, width, height int) string {
 
 	return strings.Join(result, "\n")
 }
+
+// OverlayToasts draws a stack of toasts over the bottom right corner of a
+// view, just above the footer, leaving the rest of the view as it is.
+func OverlayToasts(view, toasts string, width, height int) string {
+	if toasts == "" {
+		return view
+	}
+	lines := strings.Split(view, "\n")
+	toastLines := strings.Split(toasts, "\n")
+	startY := max(0, len(lines)-1-len(toastLines)) // keep the footer line clear
+	for i, toast := range toastLines {
+		y := startY + i
+		if y >= len(lines) {
+			break
+		}
+		w := ansi.StringWidth(toast)
+		x := max(0, width-w-1)
+		line := lines[y]
+		lineWidth := ansi.StringWidth(line)
+		left := ansi.Truncate(line, x, "")
+		if pad := x - ansi.StringWidth(left); pad > 0 {
+			left += strings.Repeat(" ", pad)
+		}
+		right := ""
+		if lineWidth > x+w {
+			right = ansi.Cut(line, x+w, lineWidth)
+		}
+		lines[y] = left + "\x1b[0m" + toast + right
+	}
+	return strings.Join(lines, "\n")
+}
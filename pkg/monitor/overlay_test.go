@@ -3,6 +3,8 @@ package monitor
 import (
 	"strings"
 	"testing"
+
+	"github.com/charmbracelet/x/ansi"
 )
 
 func TestMaxLineWidth(t *testing.T) {
@@ -251,3 +253,25 @@ func TestOverlayModalWithANSI(t *testing.T) {
 		t.Error("Modal content should be present in output")
 	}
 }
+
+func TestOverlayToasts(t *testing.T) {
+	background := strings.TrimSuffix(strings.Repeat("AAAAAAAAAA\n", 5), "\n")
+
+	got := OverlayToasts(background, "T1\nT2", 10, 5)
+	lines := strings.Split(got, "\n")
+	if len(lines) != 5 {
+		t.Fatalf("got %d lines, want 5", len(lines))
+	}
+
+	// Bottom right, one column in, with the footer line left alone
+	want := []string{"AAAAAAAAAA", "AAAAAAAAAA", "AAAAAAAT1A", "AAAAAAAT2A", "AAAAAAAAAA"}
+	for i, line := range lines {
+		if plain := ansi.Strip(line); plain != want[i] {
+			t.Errorf("line %d = %q, want %q", i, plain, want[i])
+		}
+	}
+
+	if OverlayToasts(background, "", 10, 5) != background {
+		t.Error("no toasts should leave the view unchanged")
+	}
+}
@@ -0,0 +1,176 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+)
+
+// plainSection is a group of issues the plain monitor lists
+type plainSection struct {
+	name   string
+	issues []models.Issue
+}
+
+// plainSections groups a refresh's issues as the TUI does, each issue in
+// the first section that lists it
+func plainSections(data RefreshDataMsg) []plainSection {
+	t := data.TaskList
+	return []plainSection{
+		{"To review", t.Reviewable},
+		{"Needs rework", t.NeedsRework},
+		{"In progress", data.InProgress},
+		{"Ready", t.Ready},
+		{"Needs triage", t.NeedsTriage},
+		{"Pending review", t.PendingReview},
+		{"Blocked", t.Blocked},
+	}
+}
+
+// plainEntry is where an issue is listed
+type plainEntry struct {
+	section string
+	issue   models.Issue
+}
+
+func plainIndex(data RefreshDataMsg) map[string]plainEntry {
+	index := map[string]plainEntry{}
+	for _, s := range plainSections(data) {
+		for _, issue := range s.issues {
+			if _, seen := index[issue.ID]; !seen {
+				index[issue.ID] = plainEntry{section: s.name, issue: issue}
+			}
+		}
+	}
+	return index
+}
+
+func plainIssue(issue models.Issue) string {
+	return fmt.Sprintf("%s %s %s: %s", issue.ID, issue.Priority, issue.Type, issue.Title)
+}
+
+// plainSnapshot describes a refresh in full: the focused issue, then each
+// section with its issues, one per line
+func plainSnapshot(data RefreshDataMsg) []string {
+	lines := []string{fmt.Sprintf("td monitor at %s", data.Timestamp.Format("15:04:05"))}
+	if data.FocusedIssue != nil {
+		lines = append(lines, "Focused: "+plainIssue(*data.FocusedIssue))
+	}
+	index := plainIndex(data)
+	for _, s := range plainSections(data) {
+		var listed []models.Issue
+		for _, issue := range s.issues {
+			if index[issue.ID].section == s.name {
+				listed = append(listed, issue)
+			}
+		}
+		lines = append(lines, fmt.Sprintf("%s: %d", s.name, len(listed)))
+		for _, issue := range listed {
+			lines = append(lines, "  "+plainIssue(issue))
+		}
+	}
+	return lines
+}
+
+// plainChanges describes what changed between two refreshes, one line per
+// change: focus, issues entering, leaving or moving between sections, and
+// new activity, oldest first
+func plainChanges(prev, next RefreshDataMsg) []string {
+	stamp := next.Timestamp.Format("15:04:05") + " "
+	var lines []string
+
+	prevFocus, nextFocus := "", ""
+	if prev.FocusedIssue != nil {
+		prevFocus = prev.FocusedIssue.ID
+	}
+	if next.FocusedIssue != nil {
+		nextFocus = next.FocusedIssue.ID
+	}
+	if nextFocus != prevFocus {
+		if next.FocusedIssue == nil {
+			lines = append(lines, stamp+"Focus cleared")
+		} else {
+			lines = append(lines, stamp+"Focused: "+plainIssue(*next.FocusedIssue))
+		}
+	}
+
+	before, after := plainIndex(prev), plainIndex(next)
+	for _, s := range plainSections(next) {
+		for _, issue := range s.issues {
+			now := after[issue.ID]
+			if now.section != s.name {
+				continue
+			}
+			was, ok := before[issue.ID]
+			switch {
+			case !ok:
+				lines = append(lines, fmt.Sprintf("%sNew in %s: %s", stamp, now.section, plainIssue(issue)))
+			case was.section != now.section:
+				lines = append(lines, fmt.Sprintf("%sMoved from %s to %s: %s", stamp, was.section, now.section, plainIssue(issue)))
+			}
+		}
+	}
+	for _, s := range plainSections(prev) {
+		for _, issue := range s.issues {
+			was := before[issue.ID]
+			if was.section != s.name {
+				continue
+			}
+			if _, ok := after[issue.ID]; !ok {
+				lines = append(lines, fmt.Sprintf("%sLeft %s: %s", stamp, was.section, plainIssue(issue)))
+			}
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, item := range prev.Activity {
+		seen[item.Type+item.EntityID] = true
+	}
+	// Activity is newest first; announce it in the order it happened
+	for i := len(next.Activity) - 1; i >= 0; i-- {
+		item := next.Activity[i]
+		if seen[item.Type+item.EntityID] {
+			continue
+		}
+		if len(prev.Activity) > 0 && item.Timestamp.Before(prev.Activity[len(prev.Activity)-1].Timestamp) {
+			continue // older than anything shown before, pushed in by the feed limit
+		}
+		lines = append(lines, fmt.Sprintf("%s%s %s by %s: %s", stamp, item.Type, item.IssueID, item.SessionID, item.Message))
+	}
+	return lines
+}
+
+// RunPlain prints the monitor as plain text for screen readers, braille
+// displays and logs: a snapshot, then a line for each change every
+// interval, with no colors, cursor movement or redraws. It stops when ctx
+// is cancelled.
+func RunPlain(ctx context.Context, database *db.DB, sessionID string, interval time.Duration, w io.Writer) error {
+	startedAt := time.Now()
+	prev := FetchData(database, sessionID, startedAt, "", false, SortByPriority)
+	for _, line := range plainSnapshot(prev) {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		next := FetchData(database, sessionID, startedAt, "", false, SortByPriority)
+		for _, line := range plainChanges(prev, next) {
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+		prev = next
+	}
+}
@@ -0,0 +1,108 @@
+package monitor
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func plainTestIssue(id, title string) models.Issue {
+	return models.Issue{ID: id, Title: title, Priority: models.PriorityP1, Type: models.TypeTask, Status: models.StatusOpen}
+}
+
+func TestPlainSnapshot(t *testing.T) {
+	a := plainTestIssue("td-a", "Fix login timeout")
+	b := plainTestIssue("td-b", "Add export command")
+	data := RefreshDataMsg{
+		Timestamp:    time.Date(2026, 1, 2, 9, 30, 0, 0, time.UTC),
+		FocusedIssue: &a,
+		InProgress:   []models.Issue{a},
+		TaskList:     TaskListData{Ready: []models.Issue{a, b}},
+	}
+
+	got := plainSnapshot(data)
+	want := []string{
+		"td monitor at 09:30:00",
+		"Focused: td-a P1 task: Fix login timeout",
+		"To review: 0",
+		"Needs rework: 0",
+		"In progress: 1",
+		"  td-a P1 task: Fix login timeout",
+		"Ready: 1",
+		"  td-b P1 task: Add export command",
+		"Needs triage: 0",
+		"Pending review: 0",
+		"Blocked: 0",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("plainSnapshot() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestPlainChanges(t *testing.T) {
+	a := plainTestIssue("td-a", "Fix login timeout")
+	b := plainTestIssue("td-b", "Add export command")
+	c := plainTestIssue("td-c", "Remove dead flags")
+	t0 := time.Date(2026, 1, 2, 9, 30, 0, 0, time.UTC)
+
+	prev := RefreshDataMsg{
+		Timestamp: t0,
+		TaskList:  TaskListData{Ready: []models.Issue{a, b}},
+		Activity: []ActivityItem{
+			{Timestamp: t0, Type: "log", EntityID: "l1", IssueID: "td-b", SessionID: "ses_1", Message: "looked"},
+		},
+	}
+	next := RefreshDataMsg{
+		Timestamp:    t0.Add(2 * time.Second),
+		FocusedIssue: &a,
+		InProgress:   []models.Issue{a},
+		TaskList:     TaskListData{Ready: []models.Issue{c}},
+		Activity: []ActivityItem{
+			{Timestamp: t0.Add(2 * time.Second), Type: "action", EntityID: "a2", IssueID: "td-a", SessionID: "ses_1", Message: "started"},
+			{Timestamp: t0.Add(time.Second), Type: "log", EntityID: "l2", IssueID: "td-a", SessionID: "ses_1", Message: "picked up"},
+			{Timestamp: t0, Type: "log", EntityID: "l1", IssueID: "td-b", SessionID: "ses_1", Message: "looked"},
+		},
+	}
+
+	got := plainChanges(prev, next)
+	want := []string{
+		"09:30:02 Focused: td-a P1 task: Fix login timeout",
+		"09:30:02 Moved from Ready to In progress: td-a P1 task: Fix login timeout",
+		"09:30:02 New in Ready: td-c P1 task: Remove dead flags",
+		"09:30:02 Left Ready: td-b P1 task: Add export command",
+		"09:30:02 log td-a by ses_1: picked up",
+		"09:30:02 action td-a by ses_1: started",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("plainChanges() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if got := plainChanges(next, next); len(got) != 0 {
+		t.Errorf("plainChanges() with no changes = %q, want none", got)
+	}
+}
+
+func TestFocusAnnouncement(t *testing.T) {
+	a := plainTestIssue("td-a", "Fix login timeout")
+	m := Model{
+		ActivePanel: PanelTaskList,
+		Cursor:      map[Panel]int{PanelTaskList: 0},
+		TaskListRows: []TaskListRow{
+			{Issue: a, Category: CategoryReady},
+		},
+	}
+
+	got := m.focusAnnouncement()
+	want := "Task list, panel 2 of 3, row 1 of 1: td-a [open] P1 Fix login timeout"
+	if got != want {
+		t.Errorf("focusAnnouncement() = %q, want %q", got, want)
+	}
+
+	m.ActivePanel = PanelActivity
+	if got := m.focusAnnouncement(); got != "Activity log, panel 3 of 3, empty" {
+		t.Errorf("focusAnnouncement() on empty panel = %q", got)
+	}
+}
@@ -0,0 +1,254 @@
+package monitor
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/models"
+)
+
+// panelPreview identifies the preview pane for styling only. It is not a
+// selectable panel and never becomes ActivePanel.
+const panelPreview Panel = -2
+
+// previewMinListWidth is the narrowest the task list may get before the
+// preview pane is hidden to keep the list usable.
+const previewMinListWidth = 50
+
+// previewLogLimit caps the logs shown in the preview pane
+const previewLogLimit = 5
+
+// previewRatioStep is how much one resize keypress changes the preview width
+const previewRatioStep = 0.05
+
+// PreviewData holds the issue shown in the preview pane
+type PreviewData struct {
+	IssueID   string
+	Issue     *models.Issue
+	Handoff   *models.Handoff
+	Logs      []models.Log
+	BlockedBy []models.Issue
+	Blocks    []models.Issue
+	Error     error
+}
+
+// PreviewDataMsg carries fetched preview data
+type PreviewDataMsg struct {
+	Data PreviewData
+}
+
+// previewWidths returns the task list and preview widths for the current
+// terminal width. ok is false when the preview is closed or doesn't fit.
+func (m Model) previewWidths() (listWidth, previewWidth int, ok bool) {
+	if !m.PreviewOpen {
+		return m.Width, 0, false
+	}
+	ratio := m.PreviewRatio
+	if ratio < config.MinPreviewRatio || ratio > config.MaxPreviewRatio {
+		ratio = config.DefaultPreviewRatio
+	}
+	previewWidth = int(float64(m.Width) * ratio)
+	listWidth = m.Width - previewWidth
+	if listWidth < previewMinListWidth || previewWidth < 20 {
+		return m.Width, 0, false
+	}
+	return listWidth, previewWidth, true
+}
+
+// previewTargetID returns the ID of the issue the preview should show
+func (m Model) previewTargetID() string {
+	return m.SelectedIssueID(m.ActivePanel)
+}
+
+// fetchPreview returns a command that loads preview data for an issue
+func (m Model) fetchPreview(issueID string) tea.Cmd {
+	return func() tea.Msg {
+		data := PreviewData{IssueID: issueID}
+
+		issue, err := m.DB.GetIssue(issueID)
+		if err != nil {
+			data.Error = err
+			return PreviewDataMsg{Data: data}
+		}
+		m.DB.RevealIssue(issue, nil, m.DB.ConfidentialViewer(m.SessionID))
+		data.Issue = issue
+
+		data.Handoff, _ = m.DB.GetLatestHandoff(issueID)
+		data.Logs, _ = m.DB.GetLogs(issueID, previewLogLimit)
+
+		depIDs, _ := m.DB.GetDependencies(issueID)
+		blockedIDs, _ := m.DB.GetBlockedBy(issueID)
+		allRelatedIDs := append(depIDs, blockedIDs...)
+		if len(allRelatedIDs) > 0 {
+			relatedIssues, _ := m.DB.GetIssuesByIDs(allRelatedIDs)
+			issueMap := make(map[string]models.Issue)
+			for _, i := range relatedIssues {
+				issueMap[i.ID] = i
+			}
+			for _, depID := range depIDs {
+				if i, ok := issueMap[depID]; ok {
+					data.BlockedBy = append(data.BlockedBy, i)
+				}
+			}
+			for _, blockedID := range blockedIDs {
+				if i, ok := issueMap[blockedID]; ok {
+					data.Blocks = append(data.Blocks, i)
+				}
+			}
+		}
+
+		return PreviewDataMsg{Data: data}
+	}
+}
+
+// refreshPreview returns a fetch command for the selected issue, or nil if
+// the preview is closed or nothing is selected
+func (m Model) refreshPreview() tea.Cmd {
+	if !m.PreviewOpen || m.DB == nil {
+		return nil
+	}
+	id := m.previewTargetID()
+	if id == "" {
+		return nil
+	}
+	return m.fetchPreview(id)
+}
+
+// syncPreview wraps an Update result, fetching new preview data when the
+// selection moved to a different issue
+func syncPreview(model tea.Model, cmd tea.Cmd) (tea.Model, tea.Cmd) {
+	m, ok := model.(Model)
+	if !ok || !m.PreviewOpen {
+		return model, cmd
+	}
+	id := m.previewTargetID()
+	if id == "" || (m.Preview != nil && m.Preview.IssueID == id) {
+		return m, cmd
+	}
+	return m, tea.Batch(cmd, m.fetchPreview(id))
+}
+
+// togglePreview shows or hides the preview pane and persists the choice
+func (m Model) togglePreview() (tea.Model, tea.Cmd) {
+	m.PreviewOpen = !m.PreviewOpen
+	m.updatePanelBounds()
+	if !m.PreviewOpen {
+		m.Preview = nil
+		return m, m.savePreviewLayout()
+	}
+	return m, tea.Batch(m.savePreviewLayout(), m.refreshPreview())
+}
+
+// resizePreview grows (delta > 0) or shrinks the preview pane width
+func (m Model) resizePreview(delta float64) (tea.Model, tea.Cmd) {
+	if !m.PreviewOpen {
+		return m, nil
+	}
+	ratio := m.PreviewRatio + delta
+	ratio = math.Round(ratio*100) / 100
+	if ratio < config.MinPreviewRatio {
+		ratio = config.MinPreviewRatio
+	}
+	if ratio > config.MaxPreviewRatio {
+		ratio = config.MaxPreviewRatio
+	}
+	m.PreviewRatio = ratio
+	m.updatePanelBounds()
+	return m, m.savePreviewLayout()
+}
+
+// savePreviewLayout returns a command that persists preview layout to config
+func (m Model) savePreviewLayout() tea.Cmd {
+	open, ratio, baseDir := m.PreviewOpen, m.PreviewRatio, m.BaseDir
+	return func() tea.Msg {
+		// Fire and forget - errors are not critical
+		_ = config.SetPreviewLayout(baseDir, open, ratio)
+		return nil
+	}
+}
+
+// renderPreviewPanel renders the preview pane at the given size
+func (m Model) renderPreviewPanel(width, height int) string {
+	pm := m
+	pm.Width = width
+	contentWidth := width - 4
+
+	p := m.Preview
+	switch {
+	case m.previewTargetID() == "":
+		return pm.wrapPanel("PREVIEW", subtleStyle.Render("No issue selected"), height, panelPreview)
+	case p == nil:
+		return pm.wrapPanel("PREVIEW", subtleStyle.Render("Loading..."), height, panelPreview)
+	case p.Error != nil:
+		return pm.wrapPanel("PREVIEW", errorStyle.Render("Error: "+p.Error.Error()), height, panelPreview)
+	}
+
+	issue := p.Issue
+	var lines []string
+	lines = append(lines, fmt.Sprintf("%s %s %s %s",
+		formatTypeIcon(issue.Type),
+		titleStyle.Render(issue.ID),
+		formatPriority(issue.Priority),
+		formatStatus(issue.Status)))
+	lines = append(lines, wrapText(issue.Title, contentWidth)...)
+	lines = append(lines, "")
+
+	if issue.Description != "" {
+		lines = append(lines, sectionHeader.Render("DESCRIPTION"))
+		for _, para := range strings.Split(issue.Description, "\n") {
+			if strings.TrimSpace(para) == "" {
+				lines = append(lines, "")
+				continue
+			}
+			lines = append(lines, wrapText(para, contentWidth)...)
+		}
+		lines = append(lines, "")
+	}
+
+	if len(p.BlockedBy) > 0 || len(p.Blocks) > 0 {
+		lines = append(lines, sectionHeader.Render("DEPENDENCIES"))
+		for _, dep := range p.BlockedBy {
+			lines = append(lines, fmt.Sprintf("  %s %s %s %s",
+				blockedColor.Render("←"),
+				titleStyle.Render(dep.ID),
+				formatStatus(dep.Status),
+				truncateString(dep.Title, contentWidth-24)))
+		}
+		for _, dep := range p.Blocks {
+			lines = append(lines, fmt.Sprintf("  %s %s %s %s",
+				subtleStyle.Render("→"),
+				titleStyle.Render(dep.ID),
+				formatStatus(dep.Status),
+				truncateString(dep.Title, contentWidth-24)))
+		}
+		lines = append(lines, "")
+	}
+
+	if p.Handoff != nil {
+		lines = append(lines, sectionHeader.Render("LATEST HANDOFF")+" "+
+			timestampStyle.Render(p.Handoff.Timestamp.Format("01-02 15:04"))+" "+
+			subtleStyle.Render(truncateSession(p.Handoff.SessionID)))
+		for _, item := range p.Handoff.Done {
+			lines = append(lines, readyColor.Render("  ✓ ")+item)
+		}
+		for _, item := range p.Handoff.Remaining {
+			lines = append(lines, reviewColor.Render("  • ")+item)
+		}
+		for _, item := range p.Handoff.Uncertain {
+			lines = append(lines, blockedColor.Render("  ? ")+item)
+		}
+		lines = append(lines, "")
+	}
+
+	if len(p.Logs) > 0 {
+		lines = append(lines, sectionHeader.Render(fmt.Sprintf("RECENT LOGS (%d)", len(p.Logs))))
+		for _, log := range p.Logs {
+			lines = append(lines, renderLogLines(log, contentWidth)...)
+		}
+	}
+
+	return pm.wrapPanel("PREVIEW", strings.Join(lines, "\n"), height, panelPreview)
+}
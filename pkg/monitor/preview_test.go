@@ -0,0 +1,163 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/pkg/monitor/keymap"
+)
+
+func newPreviewTestModel(t *testing.T) (Model, *db.DB) {
+	t.Helper()
+	database, err := db.Initialize(t.TempDir())
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	m := newTestModel()
+	m.DB = database
+	m.BaseDir = t.TempDir()
+	m.Width = 160
+	m.Height = 40
+	m.PanelBounds = make(map[Panel]Rect)
+	m.PreviewRatio = config.DefaultPreviewRatio
+	return m, database
+}
+
+func TestPreviewKeybindings(t *testing.T) {
+	km := newTestKeymap()
+	for _, ctx := range []keymap.Context{keymap.ContextMain, keymap.ContextBoard} {
+		cmd, found := km.Lookup(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}}, ctx)
+		if !found || cmd != keymap.CmdTogglePreview {
+			t.Errorf("'p' in %s = %v (found=%v), want %v", ctx, cmd, found, keymap.CmdTogglePreview)
+		}
+	}
+}
+
+func TestPreviewWidths(t *testing.T) {
+	m := newTestModel()
+	m.Width = 160
+	m.PreviewRatio = 0.5
+
+	if _, _, ok := m.previewWidths(); ok {
+		t.Error("closed preview should not split the task list")
+	}
+
+	m.PreviewOpen = true
+	list, preview, ok := m.previewWidths()
+	if !ok || list != 80 || preview != 80 {
+		t.Errorf("previewWidths = (%d, %d, %v), want (80, 80, true)", list, preview, ok)
+	}
+
+	// Too narrow: the list would drop below its minimum width
+	m.Width = 90
+	if list, _, ok := m.previewWidths(); ok || list != 90 {
+		t.Errorf("narrow terminal: got list=%d ok=%v, want full width and no split", list, ok)
+	}
+}
+
+func TestResizePreviewClampsAndPersists(t *testing.T) {
+	m, _ := newPreviewTestModel(t)
+	m.PreviewOpen = true
+	m.PreviewRatio = config.MaxPreviewRatio
+
+	updated, cmd := m.resizePreview(previewRatioStep)
+	m = updated.(Model)
+	if m.PreviewRatio != config.MaxPreviewRatio {
+		t.Errorf("ratio = %v, want clamped to %v", m.PreviewRatio, config.MaxPreviewRatio)
+	}
+
+	updated, cmd = m.resizePreview(-previewRatioStep)
+	m = updated.(Model)
+	if m.PreviewRatio != 0.75 {
+		t.Errorf("ratio = %v, want 0.75", m.PreviewRatio)
+	}
+	cmd()
+
+	open, ratio, err := config.GetPreviewLayout(m.BaseDir)
+	if err != nil {
+		t.Fatalf("GetPreviewLayout: %v", err)
+	}
+	if !open || ratio != 0.75 {
+		t.Errorf("persisted open=%v ratio=%v, want true 0.75", open, ratio)
+	}
+}
+
+func TestFetchPreviewAndRender(t *testing.T) {
+	m, database := newPreviewTestModel(t)
+
+	blocker := createTestIssue(t, database, "Blocker issue for preview", models.StatusOpen)
+	issue := createTestIssue(t, database, "Previewed issue title", models.StatusInProgress)
+	issue.Description = "Preview description body"
+	if err := database.UpdateIssue(issue); err != nil {
+		t.Fatalf("update issue: %v", err)
+	}
+	if err := database.AddDependency(issue.ID, blocker.ID, "depends_on"); err != nil {
+		t.Fatalf("add dependency: %v", err)
+	}
+	if err := database.AddHandoff(&models.Handoff{IssueID: issue.ID, SessionID: "ses-1", Remaining: []string{"wire up preview"}}); err != nil {
+		t.Fatalf("add handoff: %v", err)
+	}
+	for i := 0; i < previewLogLimit+2; i++ {
+		if err := database.AddLog(&models.Log{IssueID: issue.ID, SessionID: "ses-1", Message: "progress note", Type: models.LogTypeProgress}); err != nil {
+			t.Fatalf("add log: %v", err)
+		}
+	}
+
+	m.PreviewOpen = true
+	m.TaskListRows = []TaskListRow{{Issue: *issue, Category: CategoryInProgress}}
+
+	msg := m.fetchPreview(issue.ID)().(PreviewDataMsg)
+	if len(msg.Data.Logs) != previewLogLimit {
+		t.Errorf("logs = %d, want %d", len(msg.Data.Logs), previewLogLimit)
+	}
+	if len(msg.Data.BlockedBy) != 1 || msg.Data.BlockedBy[0].ID != blocker.ID {
+		t.Errorf("BlockedBy = %v, want [%s]", msg.Data.BlockedBy, blocker.ID)
+	}
+
+	updated, _ := m.Update(msg)
+	m = updated.(Model)
+	if m.Preview == nil || m.Preview.IssueID != issue.ID {
+		t.Fatal("expected preview data to be stored for the selected issue")
+	}
+
+	out := m.renderPreviewPanel(70, 30)
+	for _, want := range []string{"PREVIEW", "Preview description body", "DEPENDENCIES", blocker.ID, "LATEST HANDOFF", "wire up preview", "RECENT LOGS"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("preview missing %q", want)
+		}
+	}
+}
+
+func TestPreviewDataMsgDropsStaleSelection(t *testing.T) {
+	m, _ := newPreviewTestModel(t)
+	m.PreviewOpen = true
+	m.TaskListRows = []TaskListRow{{Issue: models.Issue{ID: "td-current"}, Category: CategoryReady}}
+
+	updated, _ := m.Update(PreviewDataMsg{Data: PreviewData{IssueID: "td-old"}})
+	m = updated.(Model)
+	if m.Preview != nil {
+		t.Errorf("stale preview data should be ignored, got %s", m.Preview.IssueID)
+	}
+}
+
+func TestSyncPreviewFetchesOnSelectionChange(t *testing.T) {
+	m, _ := newPreviewTestModel(t)
+	m.PreviewOpen = true
+	m.TaskListRows = []TaskListRow{{Issue: models.Issue{ID: "td-a"}, Category: CategoryReady}}
+	m.Preview = &PreviewData{IssueID: "td-a"}
+
+	if _, cmd := syncPreview(m, nil); cmd != nil {
+		t.Error("expected no fetch when selection is unchanged")
+	}
+
+	m.TaskListRows = []TaskListRow{{Issue: models.Issue{ID: "td-b"}, Category: CategoryReady}}
+	if _, cmd := syncPreview(m, nil); cmd == nil {
+		t.Error("expected a fetch after the selection moved")
+	}
+}
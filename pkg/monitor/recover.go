@@ -0,0 +1,58 @@
+package monitor
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/td/internal/crash"
+)
+
+// Bubble Tea ends the program on a panic in Update or View, leaving the
+// terminal to the shell with only a stack trace. The monitor recovers
+// instead: a message that panics is dropped, the model stays as it was
+// before it, and the status bar names the crash dump to look at. No log is
+// written, since the screen is the monitor's stderr.
+
+// Update implements tea.Model
+func (m Model) Update(msg tea.Msg) (result tea.Model, cmd tea.Cmd) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			result, cmd = m.recoverUpdate(msg, rec)
+		}
+	}()
+	return m.update(msg)
+}
+
+// recoverUpdate records a panic raised handling msg and returns the model
+// as it was before msg, with the error in the status bar
+func (m Model) recoverUpdate(msg tea.Msg, rec interface{}) (tea.Model, tea.Cmd) {
+	report, saved := crash.Capture(m.BaseDir, "monitor", rec, map[string]string{"msg": fmt.Sprintf("%T", msg)})
+	m.StatusMessage = crashStatus(report, saved)
+	m.StatusIsError = true
+
+	// A lost tick would stop the periodic refresh for good
+	if _, ok := msg.(TickMsg); ok {
+		return m, m.scheduleTick()
+	}
+	return m, nil
+}
+
+// View implements tea.Model
+func (m Model) View() (view string) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			report, saved := crash.Capture(m.BaseDir, "monitor", rec, map[string]string{"msg": "view"})
+			view = "td monitor could not draw the screen.\n\n" + crashStatus(report, saved) +
+				"\n\nPress esc to close what is open, or ctrl+c to quit."
+		}
+	}()
+	return m.view()
+}
+
+// crashStatus describes a recovered panic in one line
+func crashStatus(report *crash.Report, saved bool) string {
+	if saved {
+		return fmt.Sprintf("Internal error: %s (td crash show %s)", report.Panic, report.ID)
+	}
+	return "Internal error: " + report.Panic
+}
@@ -0,0 +1,50 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/td/internal/crash"
+)
+
+func TestUpdate_RecoversPanic(t *testing.T) {
+	t.Setenv("TD_CRASH_DUMPS", "1")
+	baseDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(baseDir, ".todos"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// No keymap: handling a key dereferences nil
+	m := Model{BaseDir: baseDir, ActivePanel: PanelTaskList}
+
+	result, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	got := result.(Model)
+	if cmd != nil {
+		t.Errorf("cmd = %v, want nil", cmd)
+	}
+	if !got.StatusIsError || !strings.Contains(got.StatusMessage, "td crash show cr-") {
+		t.Errorf("status = %q, want the crash report named", got.StatusMessage)
+	}
+	if got.ActivePanel != PanelTaskList {
+		t.Errorf("active panel = %d, want the model from before the panic", got.ActivePanel)
+	}
+
+	reports, err := crash.List(baseDir)
+	if err != nil || len(reports) != 1 {
+		t.Fatalf("reports = %v, %v; want one", reports, err)
+	}
+	if reports[0].Component != "monitor" || reports[0].Context["msg"] != "tea.KeyMsg" {
+		t.Errorf("report = %+v", reports[0])
+	}
+}
+
+func TestView_RecoversPanic(t *testing.T) {
+	t.Setenv("TD_CRASH_DUMPS", "0")
+	m := Model{Width: 120, Height: 40}
+	view := m.View()
+	if !strings.Contains(view, "Internal error:") {
+		t.Errorf("view = %q, want the error shown", view)
+	}
+}
@@ -0,0 +1,35 @@
+package monitor
+
+import (
+	"strings"
+
+	"github.com/marcus/td/internal/models"
+)
+
+// OpenView starts the monitor on a saved view: the task list is filtered
+// by the view's query and sorted by its sort, in place of the last board
+// and filters. The monitor keeps its own panels, so the view's fields,
+// grouping and layout only apply in td views show and the API.
+func (m *Model) OpenView(v *models.View) {
+	m.PinnedView = v.Name
+	m.SearchQuery = viewSearchQuery(v)
+	m.SearchInput.SetValue(m.SearchQuery)
+	m.SortMode = SortModeFromString(strings.TrimPrefix(v.Sort, "-"))
+	// The view's query decides which issues show, closed ones included
+	m.IncludeClosed = true
+}
+
+// viewSearchQuery returns a view's query with its sort as a sort: clause,
+// replacing any sort clause the query has
+func viewSearchQuery(v *models.View) string {
+	if v.Sort == "" {
+		return v.Query
+	}
+	var words []string
+	for _, word := range strings.Fields(v.Query) {
+		if !strings.HasPrefix(strings.ToLower(word), "sort:") {
+			words = append(words, word)
+		}
+	}
+	return strings.TrimSpace(strings.Join(words, " ") + " sort:" + v.Sort)
+}
@@ -0,0 +1,23 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestViewSearchQuery(t *testing.T) {
+	tests := []struct {
+		view models.View
+		want string
+	}{
+		{models.View{Query: "type = bug"}, "type = bug"},
+		{models.View{Query: "type = bug sort:title", Sort: "-created"}, "type = bug sort:-created"},
+		{models.View{Sort: "priority"}, "sort:priority"},
+	}
+	for _, tt := range tests {
+		if got := viewSearchQuery(&tt.view); got != tt.want {
+			t.Errorf("viewSearchQuery(%+v) = %q, want %q", tt.view, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,321 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/query"
+	"github.com/marcus/td/pkg/monitor/modal"
+	"github.com/marcus/td/pkg/monitor/mouse"
+)
+
+// sectionFilterState holds state for the section filter prompt. Stored as a
+// pointer on Model so the modal's input and validation sections keep working
+// after Bubble Tea copies the Model.
+type sectionFilterState struct {
+	Scope    string           // config scope: board ID or config.SectionFilterScopeAll
+	Category TaskListCategory // section being filtered
+	Input    textinput.Model
+	Error    string // live validation error ("" when the query is valid)
+}
+
+// compileSectionFilter parses a TDQ fragment into an in-memory matcher.
+// Cross-entity conditions (log.*, comment.*, ...) need database access and are
+// rejected, since section filters run against already-loaded issues.
+func compileSectionFilter(q, sessionID string) (func(models.Issue) bool, error) {
+	parsed, err := query.Parse(q)
+	if err != nil {
+		return nil, err
+	}
+	eval := query.NewEvaluator(query.NewEvalContext(sessionID), parsed)
+	if eval.HasCrossEntityConditions() {
+		return nil, fmt.Errorf("cross-entity fields are not supported in section filters")
+	}
+	return eval.ToMatcher()
+}
+
+// sectionFilterScope returns the config scope for the task list as currently
+// displayed: the active board ID, or the "all issues" scope.
+func (m Model) sectionFilterScope() string {
+	if m.TaskListMode == TaskListModeBoard && m.BoardMode.Board != nil {
+		return m.BoardMode.Board.ID
+	}
+	return config.SectionFilterScopeAll
+}
+
+// sectionFiltersFor returns the section filters for a scope, loading them from
+// config on first use.
+func (m Model) sectionFiltersFor(scope string) map[TaskListCategory]string {
+	if filters, ok := m.SectionFilters[scope]; ok {
+		return filters
+	}
+	filters := make(map[TaskListCategory]string)
+	saved, _ := config.GetSectionFilters(m.BaseDir, scope)
+	for cat, q := range saved {
+		filters[TaskListCategory(cat)] = q
+	}
+	if m.SectionFilters != nil {
+		m.SectionFilters[scope] = filters
+	}
+	return filters
+}
+
+// sectionFilter returns the active filter for a category in a scope
+func (m Model) sectionFilter(scope string, cat TaskListCategory) string {
+	return m.sectionFiltersFor(scope)[cat]
+}
+
+// applySectionFilters narrows each category of data by its saved filter.
+// Filters that no longer parse are ignored rather than hiding the section.
+func (m Model) applySectionFilters(scope string, data TaskListData) TaskListData {
+	filters := m.sectionFiltersFor(scope)
+	if len(filters) == 0 {
+		return data
+	}
+
+	apply := func(cat TaskListCategory, issues []models.Issue) []models.Issue {
+		q := filters[cat]
+		if q == "" {
+			return issues
+		}
+		match, err := compileSectionFilter(q, m.SessionID)
+		if err != nil {
+			return issues
+		}
+		var out []models.Issue
+		for _, issue := range issues {
+			if match(issue) {
+				out = append(out, issue)
+			}
+		}
+		return out
+	}
+
+	data.Reviewable = apply(CategoryReviewable, data.Reviewable)
+	data.NeedsRework = apply(CategoryNeedsRework, data.NeedsRework)
+	data.InProgress = apply(CategoryInProgress, data.InProgress)
+	data.Ready = apply(CategoryReady, data.Ready)
+	data.NeedsTriage = apply(CategoryNeedsTriage, data.NeedsTriage)
+	data.PendingReview = apply(CategoryPendingReview, data.PendingReview)
+	data.Blocked = apply(CategoryBlocked, data.Blocked)
+	data.Closed = apply(CategoryClosed, data.Closed)
+	return data
+}
+
+// formatSectionFilterSuffix returns the header annotation for a filtered section
+func (m Model) formatSectionFilterSuffix(scope string, cat TaskListCategory) string {
+	q := m.sectionFilter(scope, cat)
+	if q == "" {
+		return ""
+	}
+	if len(q) > 40 {
+		q = q[:37] + "..."
+	}
+	return subtleStyle.Render(" ⧩ " + q)
+}
+
+// sectionFilterTarget returns the category of the task list row under the
+// cursor, or "" when the task list is not showing categorized sections.
+func (m Model) sectionFilterTarget() TaskListCategory {
+	if m.ActivePanel != PanelTaskList {
+		return ""
+	}
+	if m.TaskListMode == TaskListModeBoard {
+		if m.BoardMode.ViewMode != BoardViewSwimlanes {
+			return ""
+		}
+		cursor := m.BoardMode.SwimlaneCursor
+		if cursor >= 0 && cursor < len(m.BoardMode.SwimlaneRows) {
+			return m.BoardMode.SwimlaneRows[cursor].Category
+		}
+		return CategoryReady
+	}
+	cursor := m.Cursor[PanelTaskList]
+	if cursor >= 0 && cursor < len(m.TaskListRows) {
+		return m.TaskListRows[cursor].Category
+	}
+	return CategoryReady
+}
+
+// openSectionFilter opens the TDQ filter prompt for the section under the cursor
+func (m Model) openSectionFilter() (tea.Model, tea.Cmd) {
+	cat := m.sectionFilterTarget()
+	if cat == "" {
+		m.StatusMessage = "Section filters apply to the Task List sections"
+		m.StatusIsError = true
+		return m, tea.Tick(2*time.Second, func(t time.Time) tea.Msg { return ClearStatusMsg{} })
+	}
+
+	scope := m.sectionFilterScope()
+	input := textinput.New()
+	input.Placeholder = "e.g. priority <= P1 AND labels ~ backend"
+	input.Width = 50
+	input.CharLimit = 200
+	input.SetValue(m.sectionFilter(scope, cat))
+
+	m.SectionFilter = &sectionFilterState{
+		Scope:    scope,
+		Category: cat,
+		Input:    input,
+	}
+	m.SectionFilterOpen = true
+	m.SectionFilterModal = m.createSectionFilterModal()
+	m.SectionFilterModal.Reset()
+	m.SectionFilterMouseHandler = mouse.NewHandler()
+	return m, m.SectionFilter.Input.Focus()
+}
+
+// closeSectionFilter closes the filter prompt and clears state
+func (m *Model) closeSectionFilter() {
+	m.SectionFilterOpen = false
+	m.SectionFilter = nil
+	m.SectionFilterModal = nil
+	m.SectionFilterMouseHandler = nil
+}
+
+// createSectionFilterModal builds the declarative modal for the filter prompt
+func (m *Model) createSectionFilterModal() *modal.Modal {
+	st := m.SectionFilter
+
+	md := modal.New(fmt.Sprintf("Filter %s", sectionDisplayName(st.Category)),
+		modal.WithWidth(64),
+		modal.WithHints(false),
+		modal.WithPrimaryAction("apply"),
+	)
+	md.AddSection(modal.InputWithLabel("query", "TDQ:", &st.Input,
+		modal.WithSubmitOnEnter(true),
+		modal.WithSubmitAction("apply"),
+	))
+	md.AddSection(modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		if st.Error != "" {
+			return modal.RenderedSection{Content: errorStyle.Render(st.Error)}
+		}
+		if strings.TrimSpace(st.Input.Value()) == "" {
+			return modal.RenderedSection{Content: subtleStyle.Render("Empty filter shows all issues")}
+		}
+		return modal.RenderedSection{Content: readyColor.Render("✓ valid")}
+	}, nil))
+	md.AddSection(modal.Spacer())
+	md.AddSection(modal.Buttons(
+		modal.Btn(" Apply ", "apply", modal.BtnPrimary()),
+		modal.Btn(" Clear ", "clear"),
+		modal.Btn(" Cancel ", "cancel"),
+	))
+	md.AddSection(modal.Spacer())
+	md.AddSection(modal.Text("Tab:switch  Enter:apply  Esc:cancel"))
+	return md
+}
+
+// sectionDisplayName returns a human-readable section name for a category
+func sectionDisplayName(cat TaskListCategory) string {
+	switch cat {
+	case CategoryReviewable:
+		return "Reviewable"
+	case CategoryNeedsRework:
+		return "Needs Rework"
+	case CategoryInProgress:
+		return "In Progress"
+	case CategoryReady:
+		return "Ready"
+	case CategoryNeedsTriage:
+		return "Needs Triage"
+	case CategoryPendingReview:
+		return "Pending Review"
+	case CategoryBlocked:
+		return "Blocked"
+	case CategoryClosed:
+		return "Closed"
+	}
+	return string(cat)
+}
+
+// validateSectionFilter re-parses the prompt input and records any error
+func (m Model) validateSectionFilter() {
+	st := m.SectionFilter
+	q := strings.TrimSpace(st.Input.Value())
+	if q == "" {
+		st.Error = ""
+		return
+	}
+	if _, err := compileSectionFilter(q, m.SessionID); err != nil {
+		st.Error = err.Error()
+		return
+	}
+	st.Error = ""
+}
+
+// handleSectionFilterKey routes key presses while the filter prompt is open.
+// The input is validated after every keystroke.
+func (m Model) handleSectionFilterKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.SectionFilter == nil || m.SectionFilterModal == nil {
+		m.closeSectionFilter()
+		return m, nil
+	}
+
+	action, cmd := m.SectionFilterModal.HandleKey(msg)
+	if action != "" {
+		return m.handleSectionFilterAction(action)
+	}
+	m.validateSectionFilter()
+	return m, cmd
+}
+
+// handleSectionFilterAction handles actions from the filter prompt modal
+func (m Model) handleSectionFilterAction(action string) (tea.Model, tea.Cmd) {
+	st := m.SectionFilter
+	if st == nil {
+		return m, nil
+	}
+
+	switch action {
+	case "cancel":
+		m.closeSectionFilter()
+		return m, nil
+
+	case "clear":
+		return m.setSectionFilter(st.Scope, st.Category, "")
+
+	case "apply", "query":
+		m.validateSectionFilter()
+		if st.Error != "" {
+			return m, nil
+		}
+		return m.setSectionFilter(st.Scope, st.Category, strings.TrimSpace(st.Input.Value()))
+	}
+
+	return m, nil
+}
+
+// setSectionFilter stores a section filter, persists it, and refreshes the
+// affected list. An empty query removes the filter.
+func (m Model) setSectionFilter(scope string, cat TaskListCategory, q string) (tea.Model, tea.Cmd) {
+	m.closeSectionFilter()
+
+	filters := m.sectionFiltersFor(scope)
+	if q == "" {
+		delete(filters, cat)
+		m.StatusMessage = fmt.Sprintf("%s filter cleared", sectionDisplayName(cat))
+	} else {
+		filters[cat] = q
+		m.StatusMessage = fmt.Sprintf("%s filtered: %s", sectionDisplayName(cat), q)
+	}
+	m.StatusIsError = false
+
+	baseDir := m.BaseDir
+	save := func() tea.Msg {
+		// Fire and forget - errors are not critical
+		_ = config.SetSectionFilter(baseDir, scope, string(cat), q)
+		return nil
+	}
+
+	refresh := m.fetchData()
+	if scope != config.SectionFilterScopeAll && m.BoardMode.Board != nil {
+		refresh = m.fetchBoardIssues(m.BoardMode.Board.ID)
+	}
+	return m, tea.Batch(save, refresh, tea.Tick(2*time.Second, func(t time.Time) tea.Msg { return ClearStatusMsg{} }))
+}
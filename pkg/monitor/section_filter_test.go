@@ -0,0 +1,142 @@
+package monitor
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/td/internal/config"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/pkg/monitor/keymap"
+)
+
+func newSectionFilterTestModel(t *testing.T) Model {
+	t.Helper()
+	m := newTestModel()
+	m.BaseDir = t.TempDir()
+	m.SectionFilters = make(map[string]map[TaskListCategory]string)
+	return m
+}
+
+func typeRunes(m Model, s string) Model {
+	for _, r := range s {
+		updated, _ := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updated.(Model)
+	}
+	return m
+}
+
+func TestSectionFilterKeybinding(t *testing.T) {
+	km := newTestKeymap()
+	for _, ctx := range []keymap.Context{keymap.ContextMain, keymap.ContextBoard} {
+		cmd, found := km.Lookup(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}}, ctx)
+		if !found || cmd != keymap.CmdFilterSection {
+			t.Errorf("'f' in %s = %v (found=%v), want %v", ctx, cmd, found, keymap.CmdFilterSection)
+		}
+	}
+}
+
+func TestCompileSectionFilter(t *testing.T) {
+	tests := []struct {
+		query   string
+		wantErr bool
+	}{
+		{query: "priority <= P1", wantErr: false},
+		{query: "type = bug AND labels ~ backend", wantErr: false},
+		{query: "priority <=", wantErr: true},
+		{query: `log.message ~ "fix"`, wantErr: true},
+	}
+	for _, tt := range tests {
+		_, err := compileSectionFilter(tt.query, "ses-1")
+		if (err != nil) != tt.wantErr {
+			t.Errorf("compileSectionFilter(%q) err = %v, wantErr %v", tt.query, err, tt.wantErr)
+		}
+	}
+}
+
+func TestApplySectionFiltersOnlyTargetsCategory(t *testing.T) {
+	m := newSectionFilterTestModel(t)
+	m.SectionFilters[config.SectionFilterScopeAll] = map[TaskListCategory]string{
+		CategoryReady: "type = bug",
+	}
+
+	data := TaskListData{
+		Ready: []models.Issue{
+			{ID: "td-1", Type: models.TypeBug},
+			{ID: "td-2", Type: models.TypeTask},
+		},
+		Blocked: []models.Issue{
+			{ID: "td-3", Type: models.TypeTask},
+		},
+	}
+
+	got := m.applySectionFilters(config.SectionFilterScopeAll, data)
+	if len(got.Ready) != 1 || got.Ready[0].ID != "td-1" {
+		t.Errorf("Ready = %v, want only td-1", got.Ready)
+	}
+	if len(got.Blocked) != 1 {
+		t.Errorf("Blocked should be unfiltered, got %v", got.Blocked)
+	}
+
+	// Other scopes are unaffected
+	other := m.applySectionFilters("bd-other", data)
+	if len(other.Ready) != 2 {
+		t.Errorf("board scope Ready = %v, want unfiltered", other.Ready)
+	}
+}
+
+func TestSectionFilterPromptValidatesAndPersists(t *testing.T) {
+	m := newSectionFilterTestModel(t)
+	m.TaskListRows = []TaskListRow{
+		{Issue: models.Issue{ID: "td-1"}, Category: CategoryBlocked},
+	}
+
+	updated, _ := m.openSectionFilter()
+	m = updated.(Model)
+	if !m.SectionFilterOpen || m.SectionFilter.Category != CategoryBlocked {
+		t.Fatalf("expected prompt open for BLOCKED, got open=%v", m.SectionFilterOpen)
+	}
+	// Render twice: the first pass populates focusIDs, the second focuses the input
+	_ = m.SectionFilterModal.Render(m.Width, m.Height, m.SectionFilterMouseHandler)
+	_ = m.SectionFilterModal.Render(m.Width, m.Height, m.SectionFilterMouseHandler)
+	if m.currentContext() != keymap.ContextSectionFilter {
+		t.Errorf("context = %v, want %v", m.currentContext(), keymap.ContextSectionFilter)
+	}
+
+	// Incomplete query shows a live error and Enter keeps the prompt open
+	m = typeRunes(m, "priority <=")
+	if m.SectionFilter.Error == "" {
+		t.Fatal("expected live validation error for incomplete query")
+	}
+	updated, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if !m.SectionFilterOpen {
+		t.Fatal("prompt should stay open while the query is invalid")
+	}
+
+	m = typeRunes(m, " P1")
+	if m.SectionFilter.Error != "" {
+		t.Fatalf("unexpected error for valid query: %s", m.SectionFilter.Error)
+	}
+	updated, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.SectionFilterOpen {
+		t.Fatal("prompt should close after applying a valid query")
+	}
+	if got := m.sectionFilter(config.SectionFilterScopeAll, CategoryBlocked); got != "priority <= P1" {
+		t.Errorf("filter = %q, want %q", got, "priority <= P1")
+	}
+
+	// Run the batched commands so the save lands on disk
+	if cmd != nil {
+		if batch, ok := cmd().(tea.BatchMsg); ok {
+			batch[0]()
+		}
+	}
+	saved, err := config.GetSectionFilters(m.BaseDir, config.SectionFilterScopeAll)
+	if err != nil {
+		t.Fatalf("GetSectionFilters: %v", err)
+	}
+	if saved[string(CategoryBlocked)] != "priority <= P1" {
+		t.Errorf("persisted filter = %q", saved[string(CategoryBlocked)])
+	}
+}
@@ -0,0 +1,53 @@
+package monitor
+
+import (
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/dependency"
+	"github.com/marcus/td/internal/models"
+)
+
+// groupStacks keeps each stack's issues together within every section, in
+// landing order, where the first of them sorted. It also records what each
+// issue is stacked on so rows can be marked.
+func groupStacks(database *db.DB, data TaskListData) TaskListData {
+	bases, err := database.GetStackBases()
+	if err != nil || len(bases) == 0 {
+		return data
+	}
+	data.StackedOn = bases
+	for _, list := range []*[]models.Issue{
+		&data.Reviewable, &data.NeedsRework, &data.InProgress, &data.Ready,
+		&data.NeedsTriage, &data.PendingReview, &data.Blocked, &data.Closed,
+	} {
+		*list = groupStackIssues(*list, bases)
+	}
+	return data
+}
+
+// groupStackIssues reorders issues so the members of a stack that are in
+// the list follow each other in landing order
+func groupStackIssues(issues []models.Issue, bases map[string]string) []models.Issue {
+	index := make(map[string]int, len(issues))
+	for i, issue := range issues {
+		index[issue.ID] = i
+	}
+
+	out := make([]models.Issue, 0, len(issues))
+	placed := make(map[string]bool, len(issues))
+	for _, issue := range issues {
+		if placed[issue.ID] {
+			continue
+		}
+		chain := dependency.Chain(bases, issue.ID)
+		if chain == nil {
+			chain = []string{issue.ID}
+		}
+		for _, id := range chain {
+			if i, ok := index[id]; ok && !placed[id] {
+				placed[id] = true
+				out = append(out, issues[i])
+			}
+		}
+	}
+	return out
+}
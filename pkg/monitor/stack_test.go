@@ -0,0 +1,23 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/marcus/td/internal/models"
+)
+
+func TestGroupStackIssues(t *testing.T) {
+	bases := map[string]string{"td-b": "td-a", "td-c": "td-b"}
+	issues := []models.Issue{{ID: "td-c"}, {ID: "td-x"}, {ID: "td-a"}, {ID: "td-y"}, {ID: "td-b"}}
+
+	got := groupStackIssues(issues, bases)
+	want := []string{"td-a", "td-b", "td-c", "td-x", "td-y"}
+	if len(got) != len(want) {
+		t.Fatalf("got %d issues, want %d", len(got), len(want))
+	}
+	for i, id := range want {
+		if got[i].ID != id {
+			t.Errorf("row %d = %s, want %s", i, got[i].ID, id)
+		}
+	}
+}
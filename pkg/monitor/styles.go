@@ -111,6 +111,8 @@ var (
 	// Stats modal styles
 	statsBarFilled  = "█"
 	statsBarEmpty   = "░"
+	statsDot        = "●"
+	statsOutlierDot = "◆"
 	statsTableLabel = lipgloss.NewStyle().Foreground(mutedColor)
 	statsTableValue = lipgloss.NewStyle().Foreground(lipgloss.Color("255")).Bold(true)
 	statsSection    = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("255")).MarginTop(1)
@@ -332,9 +334,16 @@ func renderButtonPair(leftLabel, rightLabel string, leftFocused, rightFocused, l
 	return left + "  " + right
 }
 
+// Escape codes that mark the selected and hovered rows; accessible mode
+// swaps them for reverse video and underline
+var (
+	rowHighlightCode = "\x1b[48;5;237m" // Background color 237
+	rowHoverCode     = "\x1b[48;5;236m" // Background color 236 (slightly darker than highlight)
+)
+
 // highlightRow applies selection highlight to entire row width, preserving text colors
 func highlightRow(line string, width int) string {
-	bgCode := "\x1b[48;5;237m" // Background color 237
+	bgCode := rowHighlightCode
 	reset := "\x1b[0m"
 
 	// First, truncate if line is too wide (ANSI-aware truncation)
@@ -361,7 +370,7 @@ func highlightRow(line string, width int) string {
 
 // hoverRow applies a subtle hover highlight to a row (for mouse hover)
 func hoverRow(line string, width int) string {
-	bgCode := "\x1b[48;5;236m" // Background color 236 (slightly darker than highlight)
+	bgCode := rowHoverCode
 	reset := "\x1b[0m"
 
 	// First, truncate if line is too wide (ANSI-aware truncation)
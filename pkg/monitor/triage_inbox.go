@@ -0,0 +1,206 @@
+package monitor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/td/internal/triage"
+	"github.com/marcus/td/pkg/monitor/modal"
+	"github.com/marcus/td/pkg/monitor/mouse"
+)
+
+// triageItemPrefix prefixes the triage inbox modal's checkbox IDs
+const triageItemPrefix = "inbox-"
+
+// triageInboxState holds state for the triage inbox modal. Stored as a
+// pointer on Model so the modal's checkboxes and input keep pointing at
+// live state after Bubble Tea copies the Model.
+type triageInboxState struct {
+	IDs     []string
+	Titles  []string
+	Checked []bool
+	Input   textinput.Model // reason, issue to merge into, or date
+	Error   string
+}
+
+// selected returns the checked issue IDs
+func (st *triageInboxState) selected() []string {
+	var ids []string
+	for i, id := range st.IDs {
+		if st.Checked[i] {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// openTriageInbox opens the triage inbox modal with one checkbox per issue
+// awaiting triage
+func (m Model) openTriageInbox() (tea.Model, tea.Cmd) {
+	issues, err := triage.List(m.DB)
+	if err != nil {
+		return m, m.Toasts.Error("Failed to load inbox: " + err.Error())
+	}
+
+	input := textinput.New()
+	input.Placeholder = "reason, issue to merge into, or date"
+	input.Width = 44
+	input.CharLimit = 200
+
+	st := &triageInboxState{Input: input, Checked: make([]bool, len(issues))}
+	for _, issue := range issues {
+		st.IDs = append(st.IDs, issue.ID)
+		st.Titles = append(st.Titles, issue.Title)
+	}
+	m.TriageInbox = st
+	m.TriageInboxOpen = true
+	m.TriageInboxModal = m.createTriageInboxModal()
+	m.TriageInboxModal.Reset()
+	m.TriageInboxMouseHandler = mouse.NewHandler()
+	return m, nil
+}
+
+// closeTriageInbox closes the triage inbox modal and clears state
+func (m *Model) closeTriageInbox() {
+	m.TriageInboxOpen = false
+	m.TriageInbox = nil
+	m.TriageInboxModal = nil
+	m.TriageInboxMouseHandler = nil
+}
+
+// createTriageInboxModal builds the declarative triage inbox modal
+func (m *Model) createTriageInboxModal() *modal.Modal {
+	st := m.TriageInbox
+
+	md := modal.New(fmt.Sprintf("Triage inbox (%d)", len(st.IDs)),
+		modal.WithWidth(64),
+		modal.WithHints(false),
+	)
+	if len(st.IDs) == 0 {
+		md.AddSection(modal.Text("Inbox empty"))
+		md.AddSection(modal.Spacer())
+		md.AddSection(modal.Buttons(modal.Btn(" Close ", "cancel", modal.BtnPrimary())))
+		return md
+	}
+
+	for i, id := range st.IDs {
+		title := st.Titles[i]
+		if len(title) > 44 {
+			title = title[:41] + "..."
+		}
+		md.AddSection(modal.Checkbox(fmt.Sprintf("%s%d", triageItemPrefix, i), id+"  "+title, &st.Checked[i]))
+	}
+	md.AddSection(modal.Spacer())
+	md.AddSection(modal.InputWithLabel("note", "Note:", &st.Input))
+	md.AddSection(modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		if st.Error == "" {
+			return modal.RenderedSection{}
+		}
+		return modal.RenderedSection{Content: errorStyle.Render(st.Error)}
+	}, nil))
+	md.AddSection(modal.Spacer())
+	md.AddSection(modal.Buttons(
+		modal.Btn(" Accept ", string(triage.ActionAccept), modal.BtnPrimary()),
+		modal.Btn(" Reject ", string(triage.ActionReject)),
+		modal.Btn(" Merge ", string(triage.ActionMerge)),
+		modal.Btn(" Defer ", string(triage.ActionDefer)),
+		modal.Btn(" Close ", "cancel"),
+	))
+	md.AddSection(modal.Spacer())
+	md.AddSection(modal.Text("Tab:switch  Space:check  Esc:close"))
+	md.AddSection(modal.Text(subtleStyle.Render("Reject takes a reason, merge an issue ID, defer a date")))
+	return md
+}
+
+// handleTriageInboxKey routes key presses while the triage inbox is open.
+// All keys are consumed so nothing leaks to the panels underneath.
+func (m Model) handleTriageInboxKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.TriageInbox == nil || m.TriageInboxModal == nil {
+		m.closeTriageInbox()
+		return m, nil
+	}
+
+	action, cmd := m.TriageInboxModal.HandleKey(msg)
+	if strings.HasPrefix(action, triageItemPrefix) || action == "note" {
+		// Enter on a checkbox toggled it; Enter in the input does nothing
+		action = ""
+	}
+	if action != "" {
+		return m.handleTriageInboxAction(action)
+	}
+	m.TriageInbox.Error = ""
+	return m, cmd
+}
+
+// handleTriageInboxAction handles actions from the triage inbox modal.
+// After a triage decision the modal reopens on the remaining inbox.
+func (m Model) handleTriageInboxAction(action string) (tea.Model, tea.Cmd) {
+	st := m.TriageInbox
+	if st == nil {
+		return m, nil
+	}
+
+	if i, ok := strings.CutPrefix(action, triageItemPrefix); ok {
+		// Click on a checkbox
+		if n, err := strconv.Atoi(i); err == nil && n >= 0 && n < len(st.Checked) {
+			st.Checked[n] = !st.Checked[n]
+			st.Error = ""
+		}
+		return m, nil
+	}
+
+	if action == "cancel" {
+		m.closeTriageInbox()
+		return m, nil
+	}
+
+	req := triage.Request{Action: triage.Action(action), IDs: st.selected()}
+	if len(req.IDs) == 0 {
+		st.Error = "Check the issues to triage"
+		return m, nil
+	}
+	note := strings.TrimSpace(st.Input.Value())
+	switch req.Action {
+	case triage.ActionReject:
+		req.Reason = note
+	case triage.ActionMerge:
+		req.Into = note
+	case triage.ActionDefer:
+		req.Until = note
+	}
+	result, err := triage.Apply(m.DB, req, m.SessionID)
+	if err != nil {
+		st.Error = err.Error()
+		return m, nil
+	}
+
+	msg := fmt.Sprintf("%s %d", triageVerb(req.Action), len(result.Done))
+	if len(result.Skipped) > 0 {
+		msg += fmt.Sprintf(", skipped %d", len(result.Skipped))
+	}
+	m.closeTriageInbox()
+	next, cmd := m.openTriageInbox()
+	cmds := []tea.Cmd{cmd, m.Toasts.Success(msg), m.fetchData()}
+	if m.TaskListMode == TaskListModeBoard && m.BoardMode.Board != nil {
+		cmds = append(cmds, m.fetchBoardIssues(m.BoardMode.Board.ID))
+	}
+	return next, tea.Batch(cmds...)
+}
+
+// triageVerb returns the past tense shown after a triage action
+func triageVerb(action triage.Action) string {
+	switch action {
+	case triage.ActionAccept:
+		return "Accepted"
+	case triage.ActionReject:
+		return "Rejected"
+	case triage.ActionMerge:
+		return "Merged"
+	case triage.ActionDefer:
+		return "Deferred"
+	}
+	return string(action)
+}
@@ -0,0 +1,84 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/pkg/monitor/keymap"
+)
+
+func TestTriageInboxModal(t *testing.T) {
+	baseDir := t.TempDir()
+	database, err := db.Initialize(baseDir)
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	defer database.Close()
+
+	var ids []string
+	for _, title := range []string{"Imported: flaky upload test", "Imported: add CSV export"} {
+		issue := &models.Issue{Title: title, Inbox: true}
+		if err := database.CreateIssue(issue); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, issue.ID)
+	}
+	createTestIssue(t, database, "Already triaged work", models.StatusOpen)
+
+	m := newTestModel()
+	m.DB = database
+	m.BaseDir = baseDir
+
+	result, _ := m.executeCommand(keymap.CmdOpenTriageInbox)
+	m = result.(Model)
+	if !m.TriageInboxOpen || m.currentContext() != keymap.ContextTriageInbox {
+		t.Fatal("expected the triage inbox to open")
+	}
+	if len(m.TriageInbox.IDs) != 2 {
+		t.Fatalf("inbox = %v, want the two inbox issues", m.TriageInbox.IDs)
+	}
+
+	// Acting with nothing checked is refused in the modal
+	result, _ = m.handleTriageInboxAction("accept")
+	m = result.(Model)
+	if m.TriageInbox.Error == "" {
+		t.Fatal("expected an error with no issues checked")
+	}
+
+	// Reject needs a reason in the note input
+	result, _ = m.handleTriageInboxAction(triageItemPrefix + "1")
+	m = result.(Model)
+	result, _ = m.handleTriageInboxAction("reject")
+	m = result.(Model)
+	if m.TriageInbox.Error == "" {
+		t.Fatal("expected an error rejecting without a reason")
+	}
+	m.TriageInbox.Input.SetValue("out of scope")
+	result, _ = m.handleTriageInboxAction("reject")
+	m = result.(Model)
+	if got, _ := database.GetIssue(ids[1]); got.Status != models.StatusClosed || got.Inbox {
+		t.Errorf("rejected issue status = %s, inbox = %v", got.Status, got.Inbox)
+	}
+
+	// The modal reopens on what is left
+	if !m.TriageInboxOpen || len(m.TriageInbox.IDs) != 1 || m.TriageInbox.IDs[0] != ids[0] {
+		t.Fatalf("inbox after reject = %v, want [%s]", m.TriageInbox.IDs, ids[0])
+	}
+	result, _ = m.handleTriageInboxAction(triageItemPrefix + "0")
+	m = result.(Model)
+	result, _ = m.handleTriageInboxAction("accept")
+	m = result.(Model)
+	if got, _ := database.GetIssue(ids[0]); got.Inbox || got.Status != models.StatusOpen {
+		t.Errorf("accepted issue status = %s, inbox = %v", got.Status, got.Inbox)
+	}
+	if len(m.TriageInbox.IDs) != 0 {
+		t.Errorf("inbox after accept = %v, want empty", m.TriageInbox.IDs)
+	}
+
+	result, _ = m.handleTriageInboxAction("cancel")
+	m = result.(Model)
+	if m.TriageInboxOpen {
+		t.Error("cancel did not close the inbox")
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/marcus/td/internal/capacity"
 	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/session"
 	"github.com/marcus/td/internal/syncclient"
 )
 
@@ -242,6 +244,7 @@ const (
 	CategoryNeedsRework   TaskListCategory = "REWORK"
 	CategoryInProgress    TaskListCategory = "IN_PROGRESS"
 	CategoryReady         TaskListCategory = "READY"
+	CategoryNeedsTriage   TaskListCategory = "NEEDS_TRIAGE"
 	CategoryPendingReview TaskListCategory = "PENDING_REVIEW"
 	CategoryBlocked       TaskListCategory = "BLOCKED"
 	CategoryClosed        TaskListCategory = "CLOSED"
@@ -269,9 +272,12 @@ type TaskListData struct {
 	NeedsRework   []models.Issue
 	InProgress    []models.Issue // in_progress, not rejected
 	Ready         []models.Issue // open, not blocked
+	NeedsTriage   []models.Issue // open, not blocked, short of the ready gate
 	PendingReview []models.Issue // in_review, own implementation
 	Blocked       []models.Issue
 	Closed        []models.Issue
+
+	StackedOn map[string]string // issue -> the issue it is stacked on
 }
 
 // TaskListRow represents a single selectable row in the task list panel
@@ -348,13 +354,16 @@ type TickMsg time.Time
 
 // RefreshDataMsg carries refreshed data
 type RefreshDataMsg struct {
-	FocusedIssue   *models.Issue
-	InProgress     []models.Issue
-	Activity       []ActivityItem
-	TaskList       TaskListData
-	RecentHandoffs []RecentHandoff
-	ActiveSessions []string
-	Timestamp      time.Time
+	FocusedIssue    *models.Issue
+	InProgress      []models.Issue
+	Activity        []ActivityItem
+	TaskList        TaskListData
+	RecentHandoffs  []RecentHandoff
+	ActiveSessions  []string
+	SessionLiveness map[string]session.Liveness // liveness of every session that is not gone
+	FiredReminders  []models.Reminder           // reminders that came due and were claimed by this refresh
+	Cards           map[string]models.IssueCard // card summaries for the focused, in-progress and task list issues
+	Timestamp       time.Time
 }
 
 // IssueDetailsMsg carries fetched issue details for the modal
@@ -384,6 +393,19 @@ type HandoffsDataMsg struct {
 	Error error
 }
 
+// RemindersDataMsg carries fetched reminders for the modal
+type RemindersDataMsg struct {
+	Data  []models.Reminder
+	Error error
+}
+
+// CapacityDataMsg carries the sprint capacity report for the modal. Data
+// is nil when no sprint is defined.
+type CapacityDataMsg struct {
+	Data  *capacity.Report
+	Error error
+}
+
 // ClearStatusMsg clears the status message
 type ClearStatusMsg struct{}
 
@@ -615,6 +637,8 @@ const (
 	ModalTypeConfirmation
 	ModalTypeStats
 	ModalTypeKanban
+	ModalTypeReminders
+	ModalTypeCapacity
 )
 
 // PanelRenderer renders content in a bordered panel
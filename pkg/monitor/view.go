@@ -9,7 +9,11 @@ import (
 	"github.com/charmbracelet/lipgloss/table"
 	"github.com/charmbracelet/x/ansi"
 	"github.com/charmbracelet/x/cellbuf"
+	"github.com/marcus/td/internal/aging"
+	"github.com/marcus/td/internal/dateparse"
+	"github.com/marcus/td/internal/i18n"
 	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/session"
 )
 
 // renderView renders the complete TUI view
@@ -58,6 +62,30 @@ func (m Model) renderView() string {
 	// Render base view (panels + footer)
 	base := m.renderBaseView()
 
+	// Overlay issue action menu if open (declarative modal, above everything else)
+	if m.ActionMenuOpen && m.ActionMenuModal != nil && m.ActionMenuMouseHandler != nil {
+		menu := m.ActionMenuModal.Render(m.Width, m.Height, m.ActionMenuMouseHandler)
+		return OverlayModal(base, menu, m.Width, m.Height)
+	}
+
+	// Overlay approve modal with the review checklist if open
+	if m.ApproveChecklistOpen && m.ApproveChecklistModal != nil && m.ApproveChecklistMouseHandler != nil {
+		approve := m.ApproveChecklistModal.Render(m.Width, m.Height, m.ApproveChecklistMouseHandler)
+		return OverlayModal(base, approve, m.Width, m.Height)
+	}
+
+	// Overlay triage inbox modal if open
+	if m.TriageInboxOpen && m.TriageInboxModal != nil && m.TriageInboxMouseHandler != nil {
+		inbox := m.TriageInboxModal.Render(m.Width, m.Height, m.TriageInboxMouseHandler)
+		return OverlayModal(base, inbox, m.Width, m.Height)
+	}
+
+	// Overlay section filter prompt if open
+	if m.SectionFilterOpen && m.SectionFilterModal != nil && m.SectionFilterMouseHandler != nil {
+		prompt := m.SectionFilterModal.Render(m.Width, m.Height, m.SectionFilterMouseHandler)
+		return OverlayModal(base, prompt, m.Width, m.Height)
+	}
+
 	// Overlay form modal if open
 	if m.FormOpen && m.FormState != nil {
 		form := m.renderFormModal()
@@ -94,6 +122,18 @@ func (m Model) renderView() string {
 		return OverlayModal(base, handoffs, m.Width, m.Height)
 	}
 
+	// Overlay reminders modal if open
+	if m.RemindersOpen && m.RemindersModal != nil && m.RemindersMouseHandler != nil {
+		reminders := m.RemindersModal.Render(m.Width, m.Height, m.RemindersMouseHandler)
+		return OverlayModal(base, reminders, m.Width, m.Height)
+	}
+
+	// Overlay capacity modal if open
+	if m.CapacityOpen && m.CapacityModal != nil && m.CapacityMouseHandler != nil {
+		capacity := m.CapacityModal.Render(m.Width, m.Height, m.CapacityMouseHandler)
+		return OverlayModal(base, capacity, m.Width, m.Height)
+	}
+
 	// Overlay board editor if open (on top of board picker)
 	if m.BoardEditorOpen && m.BoardEditorModal != nil && m.BoardEditorMouseHandler != nil {
 		boardEditor := m.BoardEditorModal.Render(m.Width, m.Height, m.BoardEditorMouseHandler)
@@ -155,6 +195,16 @@ func (m Model) renderBaseView() string {
 	activity := m.renderActivityPanel(panelHeights[2])
 	taskList := m.renderTaskListPanel(panelHeights[1])
 
+	// Split the task list row with the preview pane when open
+	if listWidth, previewWidth, ok := m.previewWidths(); ok {
+		lm := m
+		lm.Width = listWidth
+		taskList = lipgloss.JoinHorizontal(lipgloss.Top,
+			lm.renderTaskListPanel(panelHeights[1]),
+			m.renderPreviewPanel(previewWidth, panelHeights[1]),
+		)
+	}
+
 	// Stack panels vertically (Current Work → Task List → Activity)
 	panels := lipgloss.JoinVertical(lipgloss.Left,
 		currentWork,
@@ -190,8 +240,9 @@ func (m Model) renderCompact() string {
 	}
 
 	s.WriteString(fmt.Sprintf("In Progress: %d\n", len(m.InProgress)))
-	s.WriteString(fmt.Sprintf("Ready: %d | WIP: %d | Review: %d | Rework: %d | PRev: %d | Blocked: %d\n",
+	s.WriteString(fmt.Sprintf("Ready: %d | Triage: %d | WIP: %d | Review: %d | Rework: %d | PRev: %d | Blocked: %d\n",
 		len(m.TaskList.Ready),
+		len(m.TaskList.NeedsTriage),
 		len(m.TaskList.InProgress),
 		len(m.TaskList.Reviewable),
 		len(m.TaskList.NeedsRework),
@@ -609,7 +660,7 @@ func (m Model) renderTaskListPanel(height int) string {
 					break
 				}
 			}
-			header := m.formatCategoryHeader(row.Category)
+			header := m.formatCategoryHeader(row.Category) + m.formatSectionFilterSuffix(m.sectionFilterScope(), row.Category)
 			content.WriteString(header)
 			content.WriteString("\n")
 			linesWritten++
@@ -902,7 +953,7 @@ func (m Model) renderBoardSwimlanesView(height int) string {
 					break
 				}
 			}
-			header := m.formatSwimlaneCategoryHeader(row.Category)
+			header := m.formatSwimlaneCategoryHeader(row.Category) + m.formatSectionFilterSuffix(m.sectionFilterScope(), row.Category)
 			content.WriteString(header)
 			content.WriteString("\n")
 			linesWritten++
@@ -951,6 +1002,9 @@ func (m Model) formatSwimlaneCategoryHeader(cat TaskListCategory) string {
 	case CategoryReady:
 		count = len(m.BoardMode.SwimlaneData.Ready)
 		return readyHeaderStyle.Render("READY") + fmt.Sprintf(" (%d):", count)
+	case CategoryNeedsTriage:
+		count = len(m.BoardMode.SwimlaneData.NeedsTriage)
+		return triageColor.Render("◌ NEEDS TRIAGE") + fmt.Sprintf(" (%d):", count)
 	case CategoryPendingReview:
 		count = len(m.BoardMode.SwimlaneData.PendingReview)
 		return pendingReviewHeaderStyle.Render("PENDING REVIEW") + fmt.Sprintf(" (%d):", count)
@@ -980,12 +1034,19 @@ func (m Model) formatCategoryHeader(cat TaskListCategory) string {
 	case CategoryReady:
 		count = len(m.TaskList.Ready)
 		return readyHeaderStyle.Render("READY") + fmt.Sprintf(" (%d):", count)
+	case CategoryNeedsTriage:
+		count = len(m.TaskList.NeedsTriage)
+		return triageColor.Render("◌ NEEDS TRIAGE") + fmt.Sprintf(" (%d):", count)
 	case CategoryPendingReview:
 		count = len(m.TaskList.PendingReview)
 		return pendingReviewHeaderStyle.Render("PENDING REVIEW") + fmt.Sprintf(" (%d):", count)
 	case CategoryBlocked:
 		count = len(m.TaskList.Blocked)
-		return blockedHeaderStyle.Render("BLOCKED") + fmt.Sprintf(" (%d):", count)
+		header := blockedHeaderStyle.Render("BLOCKED") + fmt.Sprintf(" (%d):", count)
+		if summary := blockedReasonSummary(m.TaskList.Blocked); summary != "" {
+			header += " " + subtleStyle.Render(summary)
+		}
+		return header
 	case CategoryClosed:
 		count = len(m.TaskList.Closed)
 		return subtleStyle.Render("CLOSED") + fmt.Sprintf(" (%d):", count)
@@ -1004,6 +1065,8 @@ func (m Model) formatCategoryTag(cat TaskListCategory) string {
 		return inProgressColor.Render("[WIP]")
 	case CategoryReady:
 		return readyColor.Render("[RDY]")
+	case CategoryNeedsTriage:
+		return triageColor.Render("[TRI]")
 	case CategoryPendingReview:
 		return pendingReviewColor.Render("[PRV]")
 	case CategoryBlocked:
@@ -1461,6 +1524,21 @@ func (m Model) renderStatsContent(contentWidth int) string {
 		lines = append(lines, "")
 	}
 
+	// Blocked reason breakdown (compact)
+	blockedBreakdown := m.formatBlockedReasonBreakdown(stats)
+	if blockedBreakdown != "" {
+		lines = append(lines, sectionHeader.Render("BLOCKED BY"))
+		lines = append(lines, blockedBreakdown)
+		lines = append(lines, "")
+	}
+
+	// Aging work in progress
+	if r := m.StatsData.Aging; r != nil && len(r.Items) > 0 {
+		lines = append(lines, sectionHeader.Render("AGING WIP"))
+		lines = append(lines, m.renderAgingScatter(r, contentWidth))
+		lines = append(lines, "")
+	}
+
 	// Summary stats
 	lines = append(lines, sectionHeader.Render("SUMMARY"))
 	lines = append(lines, fmt.Sprintf("%s Total: %d", statsTableLabel.Render("  "), stats.Total))
@@ -1470,6 +1548,10 @@ func (m Model) renderStatsContent(contentWidth int) string {
 	}
 	completionPct := int(stats.CompletionRate * 100)
 	lines = append(lines, fmt.Sprintf("%s Completion: %d%%", statsTableLabel.Render("  "), completionPct))
+	if stats.TotalReworks > 0 {
+		lines = append(lines, fmt.Sprintf("%s Rework: %d%% (%d reopens)", statsTableLabel.Render("  "),
+			int(stats.ReworkRate*100), stats.TotalReworks))
+	}
 	lines = append(lines, "")
 
 	// Timeline
@@ -1868,6 +1950,94 @@ func (m Model) renderStatusBarChart(stats *models.ExtendedStats, width int) stri
 	return strings.Join(lines, "\n")
 }
 
+// agingBuckets are the age rows of the aging scatter, oldest first
+var agingBuckets = []struct {
+	label string
+	min   float64 // days
+}{
+	{"16d+", 16}, {"8-16d", 8}, {"4-8d", 4}, {"2-4d", 2}, {"1-2d", 1}, {"<1d", 0},
+}
+
+// renderAgingScatter renders in-flight issues as a column scatter: one
+// column per status, one row per age bucket, one dot per issue. Outliers
+// against the status's history are drawn as diamonds and listed below.
+func (m Model) renderAgingScatter(r *aging.Report, width int) string {
+	const labelWidth = 8
+	colWidth := (width - labelWidth - 2) / len(aging.InFlight)
+	if colWidth < 8 {
+		colWidth = 8
+	}
+
+	type cell struct{ dots, outliers int }
+	grid := make([]map[models.Status]*cell, len(agingBuckets))
+	for i := range grid {
+		grid[i] = map[models.Status]*cell{}
+		for _, s := range aging.InFlight {
+			grid[i][s] = &cell{}
+		}
+	}
+	for _, it := range r.Items {
+		for i, b := range agingBuckets {
+			if it.AgeDays >= b.min {
+				c := grid[i][it.Status]
+				if it.Outlier {
+					c.outliers++
+				} else {
+					c.dots++
+				}
+				break
+			}
+		}
+	}
+
+	var lines []string
+	header := strings.Repeat(" ", labelWidth+2)
+	norms := strings.Repeat(" ", labelWidth+2)
+	for _, n := range r.Norms {
+		header += fmt.Sprintf("%-*s", colWidth, truncateString(string(n.Status), colWidth-1))
+		norm := "no history"
+		if n.Threshold > 0 {
+			norm = fmt.Sprintf("p%d %.1fd", r.OutlierPercentile, n.Threshold)
+		}
+		norms += subtleStyle.Render(fmt.Sprintf("%-*s", colWidth, truncateString(norm, colWidth-1)))
+	}
+	lines = append(lines, header, norms)
+
+	for i, b := range agingBuckets {
+		line := statsTableLabel.Render(fmt.Sprintf("  %-*s", labelWidth, b.label))
+		for _, s := range aging.InFlight {
+			c := grid[i][s]
+			n := c.outliers + c.dots
+			var cellText string
+			width := n
+			if n > colWidth-1 {
+				// Too many to draw: show the count
+				style := statusChartStyles[s]
+				if c.outliers > 0 {
+					style = errorStyle
+				}
+				text := fmt.Sprintf("%d", n)
+				cellText, width = style.Render(text), len(text)
+			} else {
+				cellText = errorStyle.Render(strings.Repeat(statsOutlierDot, c.outliers)) +
+					statusChartStyles[s].Render(strings.Repeat(statsDot, c.dots))
+			}
+			line += cellText + strings.Repeat(" ", colWidth-width)
+		}
+		lines = append(lines, line)
+	}
+
+	for _, it := range r.Items {
+		if !it.Outlier {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("  %s %s %s %.1fd in %s (p%d)",
+			errorStyle.Render(statsOutlierDot), it.ID, truncateString(it.Title, 30), it.AgeDays, it.Status, *it.Percentile))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
 // formatTypeBreakdown formats a compact type breakdown
 func (m Model) formatTypeBreakdown(stats *models.ExtendedStats) string {
 	types := []models.Type{
@@ -1918,6 +2088,35 @@ func (m Model) formatPriorityBreakdown(stats *models.ExtendedStats) string {
 	return statsTableLabel.Render("  ") + strings.Join(parts, "  ")
 }
 
+// formatBlockedReasonBreakdown formats a compact breakdown of why issues are blocked
+func (m Model) formatBlockedReasonBreakdown(stats *models.ExtendedStats) string {
+	reasons := []models.BlockedReason{
+		models.BlockedReasonDependency,
+		models.BlockedReasonExternal,
+		models.BlockedReasonDecision,
+		"",
+	}
+
+	var parts []string
+	for _, r := range reasons {
+		count := stats.ByBlockedReason[r]
+		if count == 0 {
+			continue
+		}
+		label := string(r)
+		if r == "" {
+			label = "unspecified"
+		}
+		parts = append(parts, fmt.Sprintf("%s:%d", label, count))
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+
+	return statsTableLabel.Render("  ") + strings.Join(parts, "  ")
+}
+
 // wrapModal wraps content in a modal box with border (deprecated, use wrapModalWithDepth)
 func (m Model) wrapModal(content string, width, height int) string {
 	return m.wrapModalWithDepth(content, width, height)
@@ -2140,11 +2339,11 @@ var warningStyle = lipgloss.NewStyle().Foreground(warningColor)
 
 // formatDeferUntil formats a defer_until date string for display.
 func formatDeferUntil(dateStr string) string {
-	t, err := time.Parse("2006-01-02", dateStr)
+	t, err := time.ParseInLocation("2006-01-02", dateStr, dateparse.Location())
 	if err != nil {
 		return dateStr
 	}
-	now := time.Now()
+	now := dateparse.Now()
 	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	days := int(t.Sub(today).Hours() / 24)
 	switch {
@@ -2161,27 +2360,25 @@ func formatDeferUntil(dateStr string) string {
 
 // formatDueDate formats a due_date string for display with urgency styling.
 func formatDueDate(dateStr string) string {
-	t, err := time.Parse("2006-01-02", dateStr)
+	t, err := time.ParseInLocation("2006-01-02", dateStr, dateparse.Location())
 	if err != nil {
 		return dateStr
 	}
-	now := time.Now()
+	now := dateparse.Now()
 	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	days := int(t.Sub(today).Hours() / 24)
 	switch {
 	case days < 0:
-		n := -days
-		s := "s"
-		if n == 1 {
-			s = ""
+		if days == -1 {
+			return errorStyle.Render(i18n.T("OVERDUE by 1 day"))
 		}
-		return errorStyle.Render(fmt.Sprintf("OVERDUE by %d day%s", n, s))
+		return errorStyle.Render(i18n.T("OVERDUE by %d days", -days))
 	case days == 0:
-		return warningStyle.Render("due TODAY")
+		return warningStyle.Render(i18n.T("due TODAY"))
 	case days <= 7:
-		return warningStyle.Render(fmt.Sprintf("%s (%d days)", t.Format("Jan 2"), days))
+		return warningStyle.Render(t.Format("Jan 2") + " " + i18n.T("(%d days)", days))
 	default:
-		return fmt.Sprintf("%s (%d days)", t.Format("Jan 2"), days)
+		return t.Format("Jan 2") + " " + i18n.T("(%d days)", days)
 	}
 }
 
@@ -2234,11 +2431,30 @@ func (m Model) renderSearchBar() string {
 		Render(sb.String())
 }
 
+// sessionLivenessCounts returns the number of active and idle sessions.
+// Falls back to the log-based active session list when no liveness data exists.
+func (m Model) sessionLivenessCounts() (active, idle int) {
+	if len(m.SessionLiveness) == 0 {
+		return len(m.ActiveSessions), 0
+	}
+	for _, l := range m.SessionLiveness {
+		switch l {
+		case session.LivenessActive:
+			active++
+		case session.LivenessIdle:
+			idle++
+		}
+	}
+	return active, idle
+}
+
 // renderFooter renders the footer with key bindings and refresh time
 func (m Model) renderFooter() string {
 	// Use board-specific footer when in board mode
 	var keysStr string
-	if m.TaskListMode == TaskListModeBoard {
+	if m.Accessible {
+		keysStr = m.focusAnnouncement() + " (? for keys)"
+	} else if m.TaskListMode == TaskListModeBoard {
 		keysStr = m.Keymap.BoardFooterHelp()
 	} else {
 		keysStr = m.Keymap.FooterHelp()
@@ -2247,26 +2463,30 @@ func (m Model) renderFooter() string {
 
 	// Show active sessions indicator
 	sessionsIndicator := ""
-	if len(m.ActiveSessions) > 0 {
-		sessionsIndicator = activeSessionStyle.Render(fmt.Sprintf(" %d active ", len(m.ActiveSessions)))
+	if active, idle := m.sessionLivenessCounts(); active > 0 || idle > 0 {
+		label := " " + i18n.T("%d active", active) + " "
+		if idle > 0 {
+			label = " " + i18n.T("%d active · %d idle", active, idle) + " "
+		}
+		sessionsIndicator = activeSessionStyle.Render(label)
 	}
 
 	// Show prominent handoff alert if new handoffs occurred
 	handoffAlert := ""
 	if len(m.RecentHandoffs) > 0 {
-		handoffAlert = handoffAlertStyle.Render(fmt.Sprintf(" [%d HANDOFF] ", len(m.RecentHandoffs)))
+		handoffAlert = handoffAlertStyle.Render(" [" + i18n.T("%d HANDOFF", len(m.RecentHandoffs)) + "] ")
 	}
 
 	// Show prominent review alert if items need review
 	reviewAlert := ""
 	if len(m.TaskList.Reviewable) > 0 {
-		reviewAlert = reviewAlertStyle.Render(fmt.Sprintf(" [%d TO REVIEW] ", len(m.TaskList.Reviewable)))
+		reviewAlert = reviewAlertStyle.Render(" [" + i18n.T("%d TO REVIEW", len(m.TaskList.Reviewable)) + "] ")
 	}
 
 	// Show update available notification
 	updateNotif := ""
 	if m.UpdateAvail != nil {
-		updateNotif = updateAvailStyle.Render(fmt.Sprintf(" [UPDATE: %s] ", m.UpdateAvail.LatestVersion))
+		updateNotif = updateAvailStyle.Render(" [" + i18n.T("UPDATE: %s", m.UpdateAvail.LatestVersion) + "] ")
 	}
 
 	// Show status message toast (yank confirmation, errors, etc.)
@@ -2279,7 +2499,7 @@ func (m Model) renderFooter() string {
 		statusToast = style.Render(fmt.Sprintf(" %s ", m.StatusMessage))
 	}
 
-	refresh := timestampStyle.Render(fmt.Sprintf("Last: %s", m.LastRefresh.Format("15:04:05")))
+	refresh := timestampStyle.Render(i18n.T("Last: %s", m.LastRefresh.Format("15:04:05")))
 
 	// Calculate spacing
 	padding := m.Width - lipgloss.Width(keys) - lipgloss.Width(sessionsIndicator) - lipgloss.Width(handoffAlert) - lipgloss.Width(reviewAlert) - lipgloss.Width(updateNotif) - lipgloss.Width(statusToast) - lipgloss.Width(refresh) - 2
@@ -2572,7 +2792,50 @@ func (m Model) formatIssueShort(issue *models.Issue) string {
 		titleWidth = 20 // minimum reasonable width
 	}
 
-	return fmt.Sprintf("%s %s %s %s", typeIcon, idStr, priorityStr, truncateString(issue.Title, titleWidth))
+	title := issue.Title
+	if m.TaskList.StackedOn[issue.ID] != "" {
+		// Stacked issues follow their base within a section
+		title = "↳ " + title
+	}
+	if issue.Status == models.StatusBlocked && issue.BlockedReason != "" {
+		badge := blockedColor.Render(blockedReasonAbbrev(issue.BlockedReason) + ":")
+		titleWidth -= lipgloss.Width(badge) + 1
+		return fmt.Sprintf("%s %s %s %s %s", typeIcon, idStr, priorityStr, badge, truncateString(title, titleWidth))
+	}
+
+	return fmt.Sprintf("%s %s %s %s", typeIcon, idStr, priorityStr, truncateString(title, titleWidth))
+}
+
+// blockedReasonAbbrev returns a three-letter label for a blocked reason
+func blockedReasonAbbrev(r models.BlockedReason) string {
+	switch r {
+	case models.BlockedReasonDependency:
+		return "dep"
+	case models.BlockedReasonExternal:
+		return "ext"
+	case models.BlockedReasonDecision:
+		return "dec"
+	}
+	return string(r)
+}
+
+// blockedReasonSummary counts blocked issues by reason, e.g. "dep:2 ext:1".
+// Returns "" when no issue has a reason recorded.
+func blockedReasonSummary(issues []models.Issue) string {
+	counts := make(map[models.BlockedReason]int)
+	for _, issue := range issues {
+		if issue.Status == models.StatusBlocked && issue.BlockedReason != "" {
+			counts[issue.BlockedReason]++
+		}
+	}
+
+	var parts []string
+	for _, r := range []models.BlockedReason{models.BlockedReasonDependency, models.BlockedReasonExternal, models.BlockedReasonDecision} {
+		if counts[r] > 0 {
+			parts = append(parts, fmt.Sprintf("%s:%d", blockedReasonAbbrev(r), counts[r]))
+		}
+	}
+	return strings.Join(parts, " ")
 }
 
 // truncateString truncates a string to maxLen with ellipsis (ANSI-aware)
@@ -2603,6 +2866,7 @@ var (
 	reworkColor        = lipgloss.NewStyle().Foreground(lipgloss.Color("214")) // Orange/warning
 	inProgressColor    = lipgloss.NewStyle().Foreground(lipgloss.Color("45"))  // Cyan
 	pendingReviewColor = lipgloss.NewStyle().Foreground(lipgloss.Color("183")) // Light purple
+	triageColor        = lipgloss.NewStyle().Foreground(lipgloss.Color("180")) // Tan
 
 	// Prominent style for review alert in footer
 	reviewAlertStyle = lipgloss.NewStyle().
@@ -0,0 +1,45 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/marcus/td/internal/aging"
+	"github.com/marcus/td/internal/models"
+)
+
+func TestRenderAgingScatter(t *testing.T) {
+	pct := 98
+	r := &aging.Report{
+		OutlierPercentile: 85,
+		Norms: []aging.Norm{
+			{Status: models.StatusInProgress},
+			{Status: models.StatusBlocked},
+			{Status: models.StatusInReview, Samples: 5, Threshold: 3},
+		},
+		Items: []aging.Item{
+			{ID: "td-stuck1", Title: "Stuck in review", Status: models.StatusInReview, AgeDays: 9, Percentile: &pct, Outlier: true},
+			{ID: "td-wip001", Status: models.StatusInProgress, AgeDays: 0.5},
+			{ID: "td-wip002", Status: models.StatusInProgress, AgeDays: 0.2},
+		},
+	}
+
+	out := ansi.Strip(Model{}.renderAgingScatter(r, 60))
+	lines := strings.Split(out, "\n")
+	if len(lines) != 2+len(agingBuckets)+1 {
+		t.Fatalf("got %d lines:\n%s", len(lines), out)
+	}
+	if !strings.Contains(lines[1], "no history") || !strings.Contains(lines[1], "p85 3.0d") {
+		t.Errorf("norms line = %q", lines[1])
+	}
+	if !strings.HasPrefix(strings.TrimSpace(lines[3]), "8-16d") || strings.Count(lines[3], statsOutlierDot) != 1 {
+		t.Errorf("8-16d row = %q", lines[3])
+	}
+	if strings.Count(lines[7], statsDot) != 2 {
+		t.Errorf("<1d row = %q", lines[7])
+	}
+	if !strings.Contains(lines[8], "td-stuck1") || !strings.Contains(lines[8], "(p98)") {
+		t.Errorf("outlier line = %q", lines[8])
+	}
+}
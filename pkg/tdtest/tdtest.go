@@ -0,0 +1,303 @@
+// Package tdtest runs a real td API server for hermetic tests of code that
+// talks to td serve. Each Server has its own temporary database, a fake
+// clock that only moves when the test says so, and IDs drawn from a seeded
+// source, so the same Options produce the same issue IDs, timestamps and
+// responses on every run.
+//
+//	srv := tdtest.New(t, tdtest.Options{
+//		Issues: []tdtest.Issue{
+//			{Key: "login", Title: "Fix login timeout for SSO users", Priority: "P1"},
+//			{Key: "docs", Title: "Document the SSO setup steps", DependsOn: []string{"login"}},
+//		},
+//	})
+//	resp, err := srv.Do("GET", "/v1/issues/"+srv.ID("login"), nil)
+//
+// The clock and ID source are process-wide, so only one Server runs at a
+// time: New waits until any Server from another test has been cleaned up.
+// Tests that use tdtest can still call t.Parallel; their servers take
+// turns.
+package tdtest
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/marcus/td/internal/clock"
+	"github.com/marcus/td/internal/db"
+	"github.com/marcus/td/internal/demo"
+	"github.com/marcus/td/internal/models"
+	"github.com/marcus/td/internal/serve"
+)
+
+// DefaultStart is where the clock starts when Options.Start is zero: a
+// Monday morning, so week and sprint boundaries are easy to reason about.
+var DefaultStart = time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+
+// SessionID is the session the server attributes API writes to.
+const SessionID = "ses_tdtest"
+
+// Options configures a test server. The zero value is an empty project.
+type Options struct {
+	Start time.Time // clock start; DefaultStart when zero
+	Seed  uint64    // seeds generated IDs; change it to get a different, still repeatable, set
+	Token string    // bearer token the API requires; empty disables auth
+
+	// Demo seeds the td init --demo sample project before Issues
+	Demo bool
+
+	// Issues are created in order, after the demo data if any
+	Issues []Issue
+}
+
+// Issue is a fixture issue. Empty fields take td's defaults: open, task,
+// P2. Parent and DependsOn refer to earlier fixtures by Key.
+type Issue struct {
+	Key         string
+	Title       string
+	Description string
+	Type        string
+	Priority    string
+	Status      string
+	Points      int
+	Labels      []string
+	Sprint      string
+	Parent      string
+	DependsOn   []string
+}
+
+// Server is a running test server. Close is registered with t.Cleanup.
+type Server struct {
+	URL   string // base URL, e.g. http://127.0.0.1:51234
+	Token string
+
+	t     testing.TB
+	clock *clock.Fake
+	ids   map[string]string
+	http  *httptest.Server
+}
+
+// active is held from New until cleanup, since the clock and ID source
+// are shared by the whole process
+var active sync.Mutex
+
+// New starts a server for the duration of the test. It fails the test if
+// the database or fixtures cannot be set up.
+func New(t testing.TB, opts Options) *Server {
+	t.Helper()
+	active.Lock()
+	t.Cleanup(active.Unlock)
+
+	start := opts.Start
+	if start.IsZero() {
+		start = DefaultStart
+	}
+	fake := clock.NewFake(start)
+	t.Cleanup(clock.Set(fake.Now))
+
+	var seed [32]byte
+	binary.LittleEndian.PutUint64(seed[:], opts.Seed)
+	t.Cleanup(db.SetIDSource(rand.NewChaCha8(seed)))
+
+	dir := t.TempDir()
+	database, err := db.Initialize(dir)
+	if err != nil {
+		t.Fatalf("tdtest: initialize database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	if err := database.UpsertSession(&db.SessionRow{
+		ID:           SessionID,
+		Name:         "tdtest",
+		Branch:       "default",
+		AgentType:    "web",
+		StartedAt:    start,
+		LastActivity: start,
+	}); err != nil {
+		t.Fatalf("tdtest: create session: %v", err)
+	}
+
+	if opts.Demo {
+		if _, err := demo.Seed(database); err != nil {
+			t.Fatalf("tdtest: seed demo data: %v", err)
+		}
+	}
+	ids, err := createIssues(database, opts.Issues)
+	if err != nil {
+		t.Fatalf("tdtest: %v", err)
+	}
+
+	srv := serve.NewServer(database, dir, SessionID, serve.ServeConfig{Token: opts.Token})
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+
+	return &Server{
+		URL:   ts.URL,
+		Token: opts.Token,
+		t:     t,
+		clock: fake,
+		ids:   ids,
+		http:  ts,
+	}
+}
+
+// createIssues creates fixture issues, returning their IDs by key
+func createIssues(database *db.DB, fixtures []Issue) (map[string]string, error) {
+	ids := make(map[string]string, len(fixtures))
+	for _, f := range fixtures {
+		issue := &models.Issue{
+			Title:          f.Title,
+			Description:    f.Description,
+			Points:         f.Points,
+			Labels:         f.Labels,
+			Sprint:         f.Sprint,
+			CreatorSession: SessionID,
+		}
+		if f.Type != "" {
+			if issue.Type = models.NormalizeType(strings.ToLower(f.Type)); !models.IsValidType(issue.Type) {
+				return nil, fmt.Errorf("fixture %q: invalid type %q", f.Key, f.Type)
+			}
+		}
+		if f.Priority != "" {
+			if issue.Priority = models.NormalizePriority(f.Priority); !models.IsValidPriority(issue.Priority) {
+				return nil, fmt.Errorf("fixture %q: invalid priority %q", f.Key, f.Priority)
+			}
+		}
+		if f.Status != "" {
+			if issue.Status = models.NormalizeStatus(f.Status); !models.IsValidStatus(issue.Status) {
+				return nil, fmt.Errorf("fixture %q: invalid status %q", f.Key, f.Status)
+			}
+		}
+		if f.Parent != "" {
+			parent, ok := ids[f.Parent]
+			if !ok {
+				return nil, fmt.Errorf("fixture %q: parent %q is not an earlier fixture", f.Key, f.Parent)
+			}
+			issue.ParentID = parent
+		}
+		if err := database.CreateIssueLogged(issue, SessionID); err != nil {
+			return nil, fmt.Errorf("create fixture %q: %w", f.Key, err)
+		}
+		if f.Key != "" {
+			ids[f.Key] = issue.ID
+		}
+		for _, dep := range f.DependsOn {
+			target, ok := ids[dep]
+			if !ok {
+				return nil, fmt.Errorf("fixture %q: dependency %q is not an earlier fixture", f.Key, dep)
+			}
+			if err := database.AddDependencyLogged(issue.ID, target, "depends_on", SessionID); err != nil {
+				return nil, fmt.Errorf("fixture %q: add dependency on %q: %w", f.Key, dep, err)
+			}
+		}
+	}
+	return ids, nil
+}
+
+// ID returns the issue ID of the fixture with the given key, failing the
+// test for an unknown key.
+func (s *Server) ID(key string) string {
+	s.t.Helper()
+	id, ok := s.ids[key]
+	if !ok {
+		s.t.Fatalf("tdtest: no fixture with key %q", key)
+	}
+	return id
+}
+
+// Now returns the server's current time.
+func (s *Server) Now() time.Time {
+	return s.clock.Now()
+}
+
+// Advance moves the server's clock forward by d.
+func (s *Server) Advance(d time.Duration) {
+	s.clock.Advance(d)
+}
+
+// SetTime moves the server's clock to t, which may be in the past.
+func (s *Server) SetTime(t time.Time) {
+	s.clock.SetTime(t)
+}
+
+// Client returns an HTTP client for the server.
+func (s *Server) Client() *http.Client {
+	return s.http.Client()
+}
+
+// Do sends a request to path, relative to the server's URL. A non-nil body
+// is sent as JSON, and the server's token, if any, as a bearer token.
+func (s *Server) Do(method, path string, body any) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, s.URL+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if s.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.Token)
+	}
+	return s.Client().Do(req)
+}
+
+// APIError is an error response from the server.
+type APIError struct {
+	Status  int
+	Code    string
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("HTTP %d %s: %s", e.Status, e.Code, e.Message)
+}
+
+// Call sends a request like Do and decodes the response envelope's data
+// into out, which may be nil. Error responses are returned as *APIError.
+func (s *Server) Call(method, path string, body, out any) error {
+	resp, err := s.Do(method, path, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var env struct {
+		OK    bool            `json:"ok"`
+		Data  json.RawMessage `json:"data"`
+		Error *struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil {
+		return fmt.Errorf("%s %s: decode response (HTTP %d): %w", method, path, resp.StatusCode, err)
+	}
+	if resp.StatusCode >= 400 || !env.OK {
+		apiErr := &APIError{Status: resp.StatusCode}
+		if env.Error != nil {
+			apiErr.Code, apiErr.Message = env.Error.Code, env.Error.Message
+		}
+		return apiErr
+	}
+	if out == nil || len(env.Data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(env.Data, out)
+}
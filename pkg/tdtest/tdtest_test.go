@@ -0,0 +1,80 @@
+package tdtest
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type issueDTO struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Priority  string    `json:"priority"`
+	ParentID  string    `json:"parent_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+var fixtures = []Issue{
+	{Key: "epic", Title: "Single sign-on for enterprise accounts", Type: "epic"},
+	{Key: "login", Title: "Fix login timeout for SSO users", Priority: "P1", Parent: "epic"},
+	{Key: "docs", Title: "Document the SSO setup steps", DependsOn: []string{"login"}},
+}
+
+// run starts a server, creates an issue an hour in and returns everything
+// the API reports
+func run(t *testing.T) []issueDTO {
+	srv := New(t, Options{Issues: fixtures})
+	srv.Advance(time.Hour)
+
+	var created struct{ Issue issueDTO }
+	if err := srv.Call("POST", "/v1/issues", map[string]any{"title": "Rotate the SAML signing certificate"}, &created); err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+	if !created.Issue.CreatedAt.Equal(DefaultStart.Add(time.Hour)) {
+		t.Errorf("created_at = %v, want the fake clock's %v", created.Issue.CreatedAt, DefaultStart.Add(time.Hour))
+	}
+
+	var got struct{ Issue issueDTO }
+	if err := srv.Call("GET", "/v1/issues/"+srv.ID("login"), nil, &got); err != nil {
+		t.Fatalf("get fixture: %v", err)
+	}
+	login := got.Issue
+	if login.Priority != "P1" || login.ParentID != srv.ID("epic") || !login.CreatedAt.Equal(DefaultStart) {
+		t.Errorf("login fixture = %+v", login)
+	}
+
+	var page struct {
+		Issues []issueDTO `json:"issues"`
+	}
+	if err := srv.Call("GET", "/v1/issues?sort=id", nil, &page); err != nil {
+		t.Fatalf("list issues: %v", err)
+	}
+	if len(page.Issues) != len(fixtures)+1 {
+		t.Errorf("listed %d issues, want %d", len(page.Issues), len(fixtures)+1)
+	}
+	return page.Issues
+}
+
+func TestServerIsDeterministic(t *testing.T) {
+	var first, second []issueDTO
+	t.Run("first", func(t *testing.T) { first = run(t) })
+	t.Run("second", func(t *testing.T) { second = run(t) })
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("runs differ:\n%+v\n%+v", first, second)
+	}
+}
+
+func TestServerToken(t *testing.T) {
+	srv := New(t, Options{Token: "secret"})
+	if err := srv.Call("GET", "/v1/issues", nil, nil); err != nil {
+		t.Fatalf("with token: %v", err)
+	}
+
+	srv.Token = ""
+	var apiErr *APIError
+	if err := srv.Call("GET", "/v1/issues", nil, nil); !errors.As(err, &apiErr) || apiErr.Status != http.StatusUnauthorized {
+		t.Errorf("without token err = %v, want 401", err)
+	}
+}